@@ -2,50 +2,111 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"maps"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/backfill"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dedup"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dryrun"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/httpreceiver"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/kafkabroker"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/maestroclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/natsbroker"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/notification"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/replay"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/schedule"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sharding"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sli"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/smoke"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/subscription"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/admin"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/health"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/telemetry"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/traffic"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/version"
 	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
 	"gopkg.in/yaml.v3"
 )
 
 // Command-line flags
 var (
-	configPath     string // Path to deployment config (adapter-config.yaml)
-	taskConfigPath string // Path to task config (adapter-task-config.yaml)
+	configPath     string        // Path to deployment config (adapter-config.yaml)
+	taskConfigPath string        // Path to task config (adapter-task-config.yaml) or oci:// reference
+	taskCacheDir   string        // Cache directory for task config artifacts pulled from OCI registries
+	taskVerifyKey  string        // Cosign public key for verifying task config artifacts
+	policyBundle   string        // Policy file or directory evaluated against the loaded config
+	taskWatch      time.Duration // Poll interval for task config hot reload (0 = disabled)
 	logLevel       string
 	logFormat      string
 	logOutput      string
 
 	// Dry-run flags
-	dryRunEvent        string // Path to CloudEvent JSON file
+	dryRunEvent        string // Path to CloudEvent JSON file, directory or glob
 	dryRunAPIResponses string // Path to mock API responses JSON file
 	dryRunDiscovery    string // Path to mock discovery responses JSON file
 	dryRunVerbose      bool   // Show verbose dry-run output
 	dryRunOutput       string // Output format: text or json
+	dryRunServeUI      bool   // Serve the trace UI after the dry-run
+	dryRunUIAddress    string // Listen address of the trace UI
+
+	// Config-effects flags
+	effectsOutput string // Output format: text, json or yaml
+
+	// Validate flags
+	validateOutput string // Output format: text or json
+
+	// Verify-event flags
+	verifyEventPath   string // Path to CloudEvent JSON file
+	verifyEventOutput string // Output format: text or json
+
+	// Schema flags
+	schemaKind string // Document the schema describes: adapter, task or trace
+
+	// Backfill flags
+	backfillCollection string  // HyperFleet API collection to page through, e.g. clusters
+	backfillKind       string  // Kind of the listed resources
+	backfillSearch     string  // HyperFleet API search expression filtering the collection
+	backfillEventType  string  // CloudEvent type of the synthesized events
+	backfillCheckpoint string  // Checkpoint file to resume from
+	backfillRate       float64 // Events executed per second
+	backfillPageSize   int     // Resources requested per page
+
+	// Smoke flags
+	smokeGVK       string        // Kind of the canary object as <apiVersion>/<Kind>
+	smokeNamespace string        // Namespace of the canary object
+	smokeName      string        // Name of the canary object
+	smokeTransport string        // Transport client to test
+	smokeConsumer  string        // Maestro consumer the canary ManifestWork targets
+	smokeTimeout   time.Duration // Bound on the whole smoke test
+	smokeOutput    string        // Output format: text or json
 )
 
+// schemaKindTrace selects the schema of the JSON dry-run traces in the schema command
+const schemaKindTrace = "trace"
+
 // Timeout constants
 const (
 	// OTelShutdownTimeout is the timeout for gracefully shutting down the OpenTelemetry TracerProvider
@@ -54,6 +115,10 @@ const (
 	HealthServerShutdownTimeout = 5 * time.Second
 )
 
+// brokerParallelismEnv overrides subscriber.parallelism of broker.yaml in the
+// hyperfleet-broker library: the number of messages the subscriber hands over at once
+const brokerParallelismEnv = "SUBSCRIBER_PARALLELISM"
+
 // Server port constants
 const (
 	// HealthServerPort is the port for /healthz and /readyz endpoints
@@ -62,6 +127,18 @@ const (
 	MetricsServerPort = "9090"
 )
 
+// Output formats of the dry-run, replay, config-effects, validate and verify-event commands
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// logOutputStderr sends logs to stderr, for commands that print their result to stdout
+const logOutputStderr = "stderr"
+
+// logLevelWarn is the default log level of the commands that report progress on stderr
+const logLevelWarn = "warn"
+
 func main() {
 	// Root command
 	rootCmd := &cobra.Command{
@@ -92,9 +169,14 @@ and HyperFleet API calls.`,
 Dry-run mode:
   Pass --dry-run-event to process a single CloudEvent from a JSON file
   using mock transport clients. No broker, cluster, or API is required.
-  Optionally pass --dry-run-api-responses to configure mock API responses.`,
+  Pass a directory or glob instead to run every event file and print a
+  batch report; the command exits non-zero if any event fails.
+  Optionally pass --dry-run-api-responses to configure mock API responses.
+  Pass --dry-run-serve-ui to inspect the traces in a local web page.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if isDryRun() {
+				// Failed events are reported in the output, not a usage error
+				cmd.SilenceUsage = true
 				return runDryRun(cmd.Flags())
 			}
 			return runServe(cmd.Flags())
@@ -104,6 +186,9 @@ Dry-run mode:
 	addOverrideFlags(serveCmd)
 	serveCmd.Flags().Bool("debug-config", false,
 		"Log the full merged configuration after load. Env: HYPERFLEET_DEBUG_CONFIG")
+	serveCmd.Flags().DurationVar(&taskWatch, "task-config-watch-interval", 0,
+		fmt.Sprintf("Reload the task config when its directory changes, polling at this interval (0 = disabled). Env: %s",
+			configloader.EnvTaskConfigWatchInterval))
 	serveCmd.Flags().StringVar(&logLevel, "log-level", "",
 		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
 	serveCmd.Flags().StringVar(&logFormat, "log-format", "",
@@ -111,15 +196,19 @@ Dry-run mode:
 	serveCmd.Flags().StringVar(&logOutput, "log-output", "",
 		"Log output (stdout, stderr). Env: LOG_OUTPUT")
 	serveCmd.Flags().StringVar(&dryRunEvent, "dry-run-event", "",
-		"Path to CloudEvent JSON file for dry-run mode")
+		"Path to a CloudEvent JSON file, or a directory or glob of event files, for dry-run mode")
 	serveCmd.Flags().StringVar(&dryRunAPIResponses, "dry-run-api-responses", "",
 		"Path to mock API responses JSON file for dry-run mode (defaults to 200 OK)")
 	serveCmd.Flags().StringVar(&dryRunDiscovery, "dry-run-discovery", "",
 		"Path to mock discovery responses JSON file for dry-run mode (overrides applied resources)")
 	serveCmd.Flags().BoolVar(&dryRunVerbose, "dry-run-verbose", false,
 		"Show rendered manifests, API request/response bodies in dry-run output")
-	serveCmd.Flags().StringVar(&dryRunOutput, "dry-run-output", "text",
+	serveCmd.Flags().StringVar(&dryRunOutput, "dry-run-output", outputFormatText,
 		"Dry-run output format: text or json")
+	serveCmd.Flags().BoolVar(&dryRunServeUI, "dry-run-serve-ui", false,
+		"After the dry-run, serve a local web page rendering the traces until interrupted")
+	serveCmd.Flags().StringVar(&dryRunUIAddress, "dry-run-ui-address", dryrun.DefaultUIAddress,
+		"Listen address of the dry-run trace UI")
 
 	// Config-dump command: loads config and prints the merged result as YAML, then exits.
 	// Useful for debugging and verifying that config files, env vars, and CLI flags load correctly.
@@ -147,6 +236,237 @@ Priority order (lowest to highest): config file < env vars < CLI flags`,
 	configDumpCmd.Flags().StringVar(&logOutput, "log-output", "",
 		"Log output (stdout, stderr). Env: LOG_OUTPUT")
 
+	// Config-effects command: statically lists what a task config can mutate
+	configEffectsCmd := &cobra.Command{
+		Use:   "config-effects",
+		Short: "List the mutating effects of the adapter configuration",
+		Long: `Load the adapter configuration and print every mutating effect it can have:
+HyperFleet API calls (non-GET methods and URLs, including teardown steps),
+Kubernetes objects written (kind, namespace, name, lifecycle finalizer),
+Maestro ManifestWorks (target consumer and workload), Git writes of gitops
+resources (repository, branch, path) and the objects prune steps can delete
+(kind, namespace, label selector). Effects of for_each steps are flagged as
+fan-out, with the for_each expression and limits.max_for_each_items.
+
+The analysis is static: templates are printed as written, not rendered.
+Attach the output to change tickets so reviewers can see the blast radius.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigEffects(cmd.Flags())
+		},
+	}
+	addConfigPathFlags(configEffectsCmd)
+	addOverrideFlags(configEffectsCmd)
+	configEffectsCmd.Flags().StringVarP(&effectsOutput, "output", "o", outputFormatText,
+		"Output format: text, json or yaml")
+	configEffectsCmd.Flags().StringVar(&logLevel, "log-level", "",
+		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
+
+	// Validate command: loads and validates the config like serve does, without connecting anywhere
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the adapter configuration",
+		Long: `Load the adapter configuration and run the same structural and semantic
+validation as serve (CEL expressions, template variables, manifests, limits
+and policies), then print every error with its config path.
+
+Exit codes:
+  0  configuration is valid
+  1  configuration is invalid
+  2  validation could not run (for example an unsupported --output)
+
+Use in CI to catch config errors before deploying.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runValidate(cmd.Flags())
+		},
+	}
+	addConfigPathFlags(validateCmd)
+	addOverrideFlags(validateCmd)
+	validateCmd.Flags().StringVarP(&validateOutput, "output", "o", outputFormatText,
+		"Output format: text or json")
+	validateCmd.Flags().StringVar(&logLevel, "log-level", "",
+		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
+
+	// Verify-event command: checks a producer's event against the params of the config
+	verifyEventCmd := &cobra.Command{
+		Use:   "verify-event",
+		Short: "Check that a CloudEvent provides the fields the adapter configuration reads",
+		Long: `Load the adapter configuration and a CloudEvent JSON file, then check that every
+event.* param source resolves in the event data. Reports referenced fields that
+are missing and event fields that no param reads. Nothing is executed.
+
+Exit codes:
+  0  all required fields are present
+  1  a required field is missing
+  2  the check could not run (for example an unreadable event or invalid config)
+
+Producer teams can run this in CI as a contract check against the adapter.`,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runVerifyEvent(cmd.Flags())
+		},
+	}
+	addConfigPathFlags(verifyEventCmd)
+	addOverrideFlags(verifyEventCmd)
+	verifyEventCmd.Flags().StringVarP(&verifyEventPath, "event", "e", "",
+		"Path to CloudEvent JSON file (required)")
+	verifyEventCmd.Flags().StringVarP(&verifyEventOutput, "output", "o", outputFormatText,
+		"Output format: text or json")
+	verifyEventCmd.Flags().StringVar(&logLevel, "log-level", "",
+		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
+
+	// Replay command: runs a recorded failed execution again through the dry-run clients
+	replayCmd := &cobra.Command{
+		Use:   "replay <recording>",
+		Short: "Replay a recorded failed execution with the dry-run clients",
+		Long: `Run a failed execution recorded by a serving adapter (execution_recording)
+again, offline. The recorded event is executed with the dry-run clients, which
+return the recorded HyperFleet API responses and the recorded objects, and the
+execution trace is printed like a dry-run.
+
+The recording is executed with the configuration given by the flags, so a fix
+to the task config can be checked against the recorded failure.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runReplay(cmd.Flags(), args[0])
+		},
+	}
+	addConfigPathFlags(replayCmd)
+	addOverrideFlags(replayCmd)
+	replayCmd.Flags().BoolVar(&dryRunVerbose, "verbose", false,
+		"Show rendered manifests, API request/response bodies in the trace")
+	replayCmd.Flags().StringVarP(&dryRunOutput, "output", "o", outputFormatText,
+		"Output format: text or json")
+	replayCmd.Flags().StringVar(&logLevel, "log-level", "",
+		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
+
+	// Backfill command: reconciles every existing resource once with the real clients
+	backfillCmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Reconcile every existing cluster once",
+		Long: `Page through a HyperFleet API collection (clusters by default), synthesize the
+event a change to each resource would publish, and execute it with the real
+clients at a controlled rate. Use it when rolling out a new adapter to an
+existing fleet, so that clusters which will not change soon are reconciled too.
+
+Progress is saved to the checkpoint file after every event: an interrupted
+backfill run again with the same flags resumes where it stopped. Failed events
+do not stop the backfill; their resource IDs are printed at the end and the
+command exits non-zero.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runBackfill(cmd.Flags())
+		},
+	}
+	addConfigPathFlags(backfillCmd)
+	addOverrideFlags(backfillCmd)
+	backfillCmd.Flags().StringVar(&backfillCollection, "collection", "clusters",
+		"HyperFleet API collection to page through, e.g. clusters or nodepools")
+	backfillCmd.Flags().StringVar(&backfillKind, "kind", "Cluster",
+		"Kind of the listed resources, set in the event data when an item has none")
+	backfillCmd.Flags().StringVar(&backfillSearch, "search", "",
+		"HyperFleet API search expression filtering the collection, e.g. \"region='us-east-1'\"")
+	backfillCmd.Flags().StringVar(&backfillEventType, "event-type", "",
+		"CloudEvent type of the synthesized events (default io.hyperfleet.<kind>.updated)")
+	backfillCmd.Flags().StringVar(&backfillCheckpoint, "checkpoint", "backfill-checkpoint.json",
+		"Checkpoint file progress is saved to and resumed from; empty to not resume")
+	backfillCmd.Flags().Float64Var(&backfillRate, "rate", backfill.DefaultRate,
+		"Events executed per second")
+	backfillCmd.Flags().IntVar(&backfillPageSize, "page-size", backfill.DefaultPageSize,
+		"Resources requested per page")
+	backfillCmd.Flags().StringVar(&logLevel, "log-level", "",
+		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
+
+	// Smoke command: checks the transport against the target cluster with a canary object
+	smokeCmd := &cobra.Command{
+		Use:   "smoke",
+		Short: "Create, read, update and delete a canary object through the transport",
+		Long: `Create, read, update and delete a canary object on the target cluster through
+the configured transport (kubernetes, or maestro with --consumer), then print the
+latency of each operation and whether it was denied by RBAC. Use it to validate
+the credentials and permissions of a new environment without crafting events.
+
+The canary only sets metadata, so --gvk must name a kind that accepts an object
+without spec, such as v1/ConfigMap, v1/Secret or v1/ServiceAccount. It is labeled
+hyperfleet.io/smoke-test=true and deleted at the end, even when a read or update
+failed. With maestro the canary is wrapped in a ManifestWork of the same name.
+
+The command exits non-zero when an operation failed.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSmoke(cmd.Flags())
+		},
+	}
+	addConfigPathFlags(smokeCmd)
+	addOverrideFlags(smokeCmd)
+	smokeCmd.Flags().StringVar(&smokeGVK, "gvk", "v1/ConfigMap",
+		"Kind of the canary object as <apiVersion>/<Kind>, e.g. v1/ConfigMap")
+	smokeCmd.Flags().StringVar(&smokeNamespace, "namespace", "default",
+		"Namespace of the canary object; empty for a cluster-scoped kind")
+	smokeCmd.Flags().StringVar(&smokeName, "name", "",
+		"Name of the canary object (default hyperfleet-adapter-smoke-<unix time>)")
+	smokeCmd.Flags().StringVar(&smokeTransport, "transport", "",
+		"Transport to test: kubernetes or maestro (default maestro when clients.maestro is configured)")
+	smokeCmd.Flags().StringVar(&smokeConsumer, "consumer", "",
+		"Maestro consumer (target cluster) of the canary ManifestWork, required with maestro")
+	smokeCmd.Flags().DurationVar(&smokeTimeout, "timeout", 30*time.Second,
+		"Bound on the whole smoke test")
+	smokeCmd.Flags().StringVarP(&smokeOutput, "output", "o", outputFormatText,
+		"Output format: text or json")
+	smokeCmd.Flags().StringVar(&logLevel, "log-level", "",
+		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
+
+	// Login command: interactive OIDC login for running the adapter from a developer machine
+	loginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to the HyperFleet API with OIDC",
+		Long: `Log in to the OIDC issuer of clients.hyperfleet_api.auth.oidc with the device
+flow (enter a code on any browser) or the PKCE flow (a browser on this machine),
+and cache the short-lived token and its refresh token under
+~/.config/hyperfleet-adapter. Commands then use the cached token and refresh it,
+so no long-lived token has to be kept in an environment variable.
+
+backfill logs in by itself when there is no usable login; serve only uses a
+cached one.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runLogin(cmd.Flags())
+		},
+	}
+	addConfigPathFlags(loginCmd)
+	addOverrideFlags(loginCmd)
+	loginCmd.Flags().StringVar(&logLevel, "log-level", "",
+		"Log level (debug, info, warn, error). Env: LOG_LEVEL")
+
+	// Schema command: prints the JSON Schema of the config files for editors and CI
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the adapter or task configuration",
+		Long: `Print a JSON Schema (draft 2020-12) of the task config (params, preconditions,
+resources, post, prune and wait) or of the adapter deployment config. The schema
+is generated from the config types, so it matches the running version.
+
+--kind trace prints the versioned schema of the JSON dry-run traces and batch
+reports (--dry-run-output json) instead.
+
+Point an editor at it for autocompletion, for example with the yaml-language-server
+modeline:
+  # yaml-language-server: $schema=./adapter-task-config.schema.json
+
+or validate configs in a pipeline with any JSON Schema validator.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSchema()
+		},
+	}
+	schemaCmd.Flags().StringVar(&schemaKind, "kind", configloader.SchemaKindTask,
+		"Document to describe: task or adapter config, or trace for the JSON dry-run traces")
+
 	// Version command
 	versionCmd := &cobra.Command{
 		Use:   "version",
@@ -162,14 +482,41 @@ Priority order (lowest to highest): config file < env vars < CLI flags`,
 	// Add subcommands
 	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(configDumpCmd)
+	rootCmd.AddCommand(configEffectsCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(verifyEventCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(backfillCmd)
+	rootCmd.AddCommand(smokeCmd)
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(schemaCmd)
 	rootCmd.AddCommand(versionCmd)
 
 	// Execute
 	if err := rootCmd.Execute(); err != nil {
+		var exitErr *exitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.code)
+		}
 		os.Exit(1)
 	}
 }
 
+// exitCodeError makes the process exit with code once the command returns.
+// The command is expected to have reported the failure itself.
+type exitCodeError struct {
+	err  error
+	code int
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
 // isDryRun returns true when dry-run flags are present.
 func isDryRun() bool {
 	return dryRunEvent != "" || dryRunAPIResponses != ""
@@ -196,6 +543,7 @@ func buildLoggerConfig(component string, logCfg *configloader.LogConfig) logger.
 		if logCfg.Output != "" {
 			cfg.Output = logCfg.Output
 		}
+		cfg.Sampling = logCfg.Sampling
 	}
 
 	// Apply environment variables (override config file)
@@ -228,10 +576,46 @@ func buildLoggerConfig(component string, logCfg *configloader.LogConfig) logger.
 
 // loadConfig loads the unified adapter configuration from both config files.
 func loadConfig(ctx context.Context, log logger.Logger, flags *pflag.FlagSet) (*configloader.Config, error) {
+	return loadConfigWithTask(ctx, log, flags, taskConfigPath)
+}
+
+// loadSubscriptionConfigs loads the task configs of the broker subscriptions that set
+// their own, by path. Each is merged with the adapter config like the main task config.
+func loadSubscriptionConfigs(
+	ctx context.Context,
+	log logger.Logger,
+	flags *pflag.FlagSet,
+	config *configloader.Config,
+) (map[string]*configloader.Config, error) {
+	configs := make(map[string]*configloader.Config)
+	for _, sub := range config.Clients.Broker.EffectiveSubscriptions() {
+		if sub.TaskConfig == "" || configs[sub.TaskConfig] != nil {
+			continue
+		}
+		log.Infof(ctx, "Loading task config %s of subscription %s...", sub.TaskConfig, sub.DisplayName())
+		subConfig, err := loadConfigWithTask(ctx, log, flags, sub.TaskConfig)
+		if err != nil {
+			return nil, fmt.Errorf("subscription %s: %w", sub.DisplayName(), err)
+		}
+		configs[sub.TaskConfig] = subConfig
+	}
+	return configs, nil
+}
+
+// loadConfigWithTask loads the adapter configuration with the task config at taskPath
+func loadConfigWithTask(
+	ctx context.Context,
+	log logger.Logger,
+	flags *pflag.FlagSet,
+	taskPath string,
+) (*configloader.Config, error) {
 	log.Info(ctx, "Loading adapter configuration...")
 	config, err := configloader.LoadConfig(
 		configloader.WithAdapterConfigPath(configPath),
-		configloader.WithTaskConfigPath(taskConfigPath),
+		configloader.WithTaskConfigPath(taskPath),
+		configloader.WithOCICacheDir(taskCacheDir),
+		configloader.WithOCIVerifyKey(taskVerifyKey),
+		configloader.WithPolicyBundle(policyBundle),
 		configloader.WithAdapterVersion(version.Version),
 		configloader.WithFlags(flags),
 		configloader.WithContext(ctx),
@@ -245,12 +629,131 @@ func loadConfig(ctx context.Context, log logger.Logger, flags *pflag.FlagSet) (*
 	return config, nil
 }
 
+// startTaskConfigWatcher reloads the task config when its directory changes, if a watch
+// interval is set. Reloaded configs go through the same loading and validation as at
+// startup and only their task part (params, preconditions, resources, post) is swapped
+// into the executor; deployment settings, and transports the running config does not use,
+// need a restart. Events already being processed finish with the config they started with.
+func startTaskConfigWatcher(
+	ctx context.Context,
+	log logger.Logger,
+	flags *pflag.FlagSet,
+	exec *executor.Executor,
+	transports map[string]transportclient.TransportClient,
+	healthServer *health.Server,
+	adminServer *admin.Server,
+	recorder *metrics.Recorder,
+) error {
+	interval := taskWatch
+	if env := os.Getenv(configloader.EnvTaskConfigWatchInterval); interval == 0 && env != "" {
+		parsed, err := configloader.ParseDuration(env)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", configloader.EnvTaskConfigWatchInterval, err)
+		}
+		interval = parsed.Std()
+	}
+	if interval <= 0 {
+		return nil
+	}
+
+	reload := func() (*configloader.Config, error) {
+		reloaded, err := loadConfig(ctx, log, flags)
+		if err != nil {
+			recorder.RecordConfigReload(metrics.ReloadResultFailed)
+		}
+		return reloaded, err
+	}
+	apply := func(reloaded *configloader.Config) error {
+		updated := exec.Config().WithTaskFrom(reloaded)
+		for _, name := range updated.TransportClients() {
+			if _, ok := transports[name]; !ok {
+				recorder.RecordConfigReload(metrics.ReloadResultFailed)
+				return fmt.Errorf("the %s transport has no client: restart the adapter to use it", name)
+			}
+		}
+		if err := exec.SwapConfig(updated); err != nil {
+			recorder.RecordConfigReload(metrics.ReloadResultFailed)
+			return err
+		}
+		recorder.RecordConfigReload(metrics.ReloadResultSuccess)
+		log.Infof(ctx, "Task config hash: %s, config fingerprint: %s", exec.ConfigHash(), exec.ConfigFingerprint())
+		healthServer.SetConfigVersion(exec.ConfigHash(), exec.ConfigFingerprint())
+		if updated.DebugConfig || adminServer != nil {
+			if data, err := yaml.Marshal(updated.Redacted()); err == nil {
+				if updated.DebugConfig {
+					healthServer.SetConfig(data)
+				}
+				adminServer.SetConfig(data)
+			}
+		}
+		return nil
+	}
+
+	watcher, err := configloader.NewTaskConfigWatcher(taskConfigPath, interval, reload, apply, log)
+	if err != nil {
+		return fmt.Errorf("failed to watch task config: %w", err)
+	}
+	go watcher.Run(ctx)
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 // Client creation (shared between serve and dry-run)
 // -----------------------------------------------------------------------------
 
+// toggleTrafficRecordingOnSignal flips traffic recording on every SIGUSR1 until ctx is canceled
+func toggleTrafficRecordingOnSignal(ctx context.Context, recorder *traffic.Recorder, log logger.Logger) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR1)
+	defer signal.Stop(sigCh)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			log.Infof(ctx, "Received SIGUSR1, traffic recording enabled=%t", recorder.Toggle())
+		}
+	}
+}
+
+// toggleDebugLoggingOnSignal switches the log level to debug on SIGUSR2, and back to the
+// level it replaced on the next SIGUSR2, until ctx is canceled
+func toggleDebugLoggingOnSignal(ctx context.Context, log logger.Logger) {
+	controller, ok := log.(logger.LevelController)
+	if !ok {
+		return
+	}
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGUSR2)
+	defer signal.Stop(sigCh)
+	restore := ""
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigCh:
+			previous := controller.Level()
+			next := "debug"
+			if previous == "debug" && restore != "" {
+				next = restore
+			}
+			restore = previous
+			if err := controller.SetLevel(next); err != nil {
+				errCtx := logger.WithErrorField(ctx, err)
+				log.Errorf(errCtx, "Received SIGUSR2, failed to change the log level")
+				continue
+			}
+			log.Warnf(ctx, "Received SIGUSR2, log level changed from %s to %s", previous, next)
+		}
+	}
+}
+
 // createAPIClient creates a HyperFleet API client from the config
-func createAPIClient(apiConfig configloader.HyperfleetAPIConfig, log logger.Logger) (hyperfleetapi.Client, error) {
+func createAPIClient(
+	apiConfig configloader.HyperfleetAPIConfig,
+	log logger.Logger,
+	recorder *traffic.Recorder,
+) (hyperfleetapi.Client, error) {
 	var opts []hyperfleetapi.ClientOption
 
 	// Set base URL if configured (env fallback handled in NewClient)
@@ -260,7 +763,7 @@ func createAPIClient(apiConfig configloader.HyperfleetAPIConfig, log logger.Logg
 
 	// Set timeout if configured (0 means use default)
 	if apiConfig.Timeout > 0 {
-		opts = append(opts, hyperfleetapi.WithTimeout(apiConfig.Timeout))
+		opts = append(opts, hyperfleetapi.WithTimeout(apiConfig.Timeout.Std()))
 	}
 
 	// Set retry attempts
@@ -283,12 +786,12 @@ func createAPIClient(apiConfig configloader.HyperfleetAPIConfig, log logger.Logg
 
 	// Set retry base delay
 	if apiConfig.BaseDelay > 0 {
-		opts = append(opts, hyperfleetapi.WithBaseDelay(apiConfig.BaseDelay))
+		opts = append(opts, hyperfleetapi.WithBaseDelay(apiConfig.BaseDelay.Std()))
 	}
 
 	// Set retry max delay
 	if apiConfig.MaxDelay > 0 {
-		opts = append(opts, hyperfleetapi.WithMaxDelay(apiConfig.MaxDelay))
+		opts = append(opts, hyperfleetapi.WithMaxDelay(apiConfig.MaxDelay.Std()))
 	}
 
 	// Set default headers
@@ -296,37 +799,134 @@ func createAPIClient(apiConfig configloader.HyperfleetAPIConfig, log logger.Logg
 		opts = append(opts, hyperfleetapi.WithDefaultHeader(key, value))
 	}
 
+	// Configure request/response compression
+	opts = append(opts, hyperfleetapi.WithCompression(apiConfig.Compression))
+
 	// Configure bearer token auth if set
 	if apiConfig.Auth != nil {
 		opts = append(opts, hyperfleetapi.WithAuth(apiConfig.Auth))
 	}
 
+	if recorder != nil {
+		opts = append(opts, hyperfleetapi.WithTransportWrapper(recorder.WrapTransport))
+	}
+
 	return hyperfleetapi.NewClient(log, opts...)
 }
 
-// createTransportClient creates the appropriate transport client based on config.
-func createTransportClient(
+// createTransportClients creates the client of every transport the task config uses, and
+// of the default transport, from the transport registry. The transports of taskConfigs,
+// such as the task configs of broker subscriptions, are created too. It returns them by
+// name, and a router that passes the calls for each resource to the client of its transport.
+func createTransportClients(
 	ctx context.Context,
 	config *configloader.Config,
 	log logger.Logger,
-) (transportclient.TransportClient, error) {
+	recorder *traffic.Recorder,
+	taskConfigs ...*configloader.Config,
+) (transportclient.TransportClient, map[string]transportclient.TransportClient, error) {
+	defaultName := defaultTransportClient(config)
+	names := config.TransportClients()
+	for _, taskConfig := range taskConfigs {
+		names = append(names, taskConfig.TransportClients()...)
+	}
+	if !slices.Contains(names, defaultName) {
+		names = append(names, defaultName)
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	clients := make(map[string]transportclient.TransportClient, len(names))
+	opts := transportclient.FactoryOptions{Logger: log, Recorder: recorder}
+	for _, name := range names {
+		log.Infof(ctx, "Creating %s transport client...", name)
+		client, err := transportclient.New(ctx, name, config, opts)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create %s transport client: %w", name, err)
+		}
+		log.Infof(ctx, "%s transport client created successfully", name)
+		clients[name] = client
+	}
+	return transportclient.NewRouter(clients, defaultName), clients, nil
+}
+
+// defaultTransportClient returns the transport of calls made outside of a resource step:
+// maestro when clients.maestro is configured, kubernetes otherwise
+func defaultTransportClient(config *configloader.Config) string {
+	if config.Clients.Maestro != nil {
+		return configloader.TransportClientMaestro
+	}
+	return configloader.TransportClientKubernetes
+}
+
+// maxConcurrentWrites returns the cap on concurrent writes of the default transport, shared
+// by every transport, zero when unlimited
+func maxConcurrentWrites(config *configloader.Config) int {
 	if config.Clients.Maestro != nil {
-		log.Info(ctx, "Creating Maestro transport client...")
-		client, err := createMaestroClient(ctx, config.Clients.Maestro, log)
+		return config.Clients.Maestro.MaxConcurrentApplies
+	}
+	return config.Clients.Kubernetes.MaxConcurrentWrites
+}
+
+// createSharder creates the event sharder when sharding is enabled and keeps its shard
+// count in sync with the StatefulSet. Unless the count is fixed in config, it is read from
+// the adapter's own StatefulSet with an in-cluster client, since clients.kubernetes may
+// point at another cluster.
+func createSharder(
+	ctx context.Context,
+	shardingConfig configloader.ShardingConfig,
+	log logger.Logger,
+) (*sharding.Sharder, error) {
+	if !shardingConfig.Enabled {
+		return nil, nil
+	}
+	var counter sharding.ReplicaCounter
+	if shardingConfig.Count == 0 {
+		client, err := createK8sClient(ctx, configloader.KubernetesConfig{}, log, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create Maestro client: %w", err)
+			return nil, fmt.Errorf("failed to create Kubernetes client for sharding: %w", err)
 		}
-		log.Info(ctx, "Maestro transport client created successfully")
-		return client, nil
+		counter = sharding.StatefulSetReplicas(client)
+	}
+	sharder, err := sharding.New(ctx, shardingConfig, counter, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure sharding: %w", err)
 	}
+	log.Infof(ctx, "Sharding enabled: this replica is shard %d of %d", sharder.Index(), sharder.Count())
+	go sharder.Run(ctx)
+	return sharder, nil
+}
 
-	log.Info(ctx, "Creating Kubernetes transport client...")
-	client, err := createK8sClient(ctx, config.Clients.Kubernetes, log)
+// createDeduplicator creates the store of completed events when deduplication is enabled.
+// The configmap store uses an in-cluster client and defaults to the pod namespace, like
+// sharding, since clients.kubernetes may point at another cluster.
+func createDeduplicator(
+	ctx context.Context,
+	dedupConfig configloader.DeduplicationConfig,
+	adapterName string,
+	log logger.Logger,
+) (dedup.Store, error) {
+	if !dedupConfig.Enabled {
+		return nil, nil
+	}
+	var client dedup.ConfigMapClient
+	if dedupConfig.Store == configloader.DeduplicationStoreConfigMap {
+		k8sClient, err := createK8sClient(ctx, configloader.KubernetesConfig{}, log, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Kubernetes client for deduplication: %w", err)
+		}
+		client = k8sClient
+	}
+	store, err := dedup.New(dedupConfig, adapterName, sharding.PodNamespace(), client)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+		return nil, fmt.Errorf("failed to configure event deduplication: %w", err)
 	}
-	log.Info(ctx, "Kubernetes transport client created successfully")
-	return client, nil
+	storeName := dedupConfig.Store
+	if storeName == "" {
+		storeName = configloader.DeduplicationStoreMemory
+	}
+	log.Infof(ctx, "Event deduplication enabled with the %s store", storeName)
+	return store, nil
 }
 
 // createK8sClient creates a Kubernetes client from the config
@@ -334,12 +934,16 @@ func createK8sClient(
 	ctx context.Context,
 	k8sConfig configloader.KubernetesConfig,
 	log logger.Logger,
+	recorder *traffic.Recorder,
 ) (*k8sclient.Client, error) {
 	clientConfig := k8sclient.ClientConfig{
 		KubeConfigPath: k8sConfig.KubeConfigPath,
 		QPS:            k8sConfig.QPS,
 		Burst:          k8sConfig.Burst,
 	}
+	if recorder != nil {
+		clientConfig.WrapTransport = recorder.WrapTransport
+	}
 	return k8sclient.NewClient(ctx, clientConfig, log)
 }
 
@@ -348,6 +952,7 @@ func createMaestroClient(
 	ctx context.Context,
 	maestroConfig *configloader.MaestroClientConfig,
 	log logger.Logger,
+	recorder *traffic.Recorder,
 ) (*maestroclient.Client, error) {
 	config := &maestroclient.Config{
 		MaestroServerAddr: maestroConfig.HTTPServerAddress,
@@ -355,26 +960,16 @@ func createMaestroClient(
 		SourceID:          maestroConfig.SourceID,
 		Insecure:          maestroConfig.Insecure,
 	}
-
-	if maestroConfig.Timeout != "" {
-		d, err := time.ParseDuration(maestroConfig.Timeout)
-		if err != nil {
-			return nil, fmt.Errorf("invalid maestro timeout %q: %w", maestroConfig.Timeout, err)
+	if recorder != nil {
+		config.WrapTransport = recorder.WrapTransport
+		config.GRPCDialOptions = []grpc.DialOption{
+			grpc.WithChainUnaryInterceptor(recorder.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(recorder.StreamClientInterceptor()),
 		}
-		config.HTTPTimeout = d
 	}
 
-	if maestroConfig.ServerHealthinessTimeout != "" {
-		d, err := time.ParseDuration(maestroConfig.ServerHealthinessTimeout)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"invalid maestro serverHealthinessTimeout %q: %w",
-				maestroConfig.ServerHealthinessTimeout,
-				err,
-			)
-		}
-		config.ServerHealthinessTimeout = d
-	}
+	config.HTTPTimeout = maestroConfig.Timeout.Std()
+	config.ServerHealthinessTimeout = maestroConfig.ServerHealthinessTimeout.Std()
 
 	if maestroConfig.Auth.TLSConfig != nil {
 		config.CAFile = maestroConfig.Auth.TLSConfig.CAFile
@@ -386,21 +981,88 @@ func createMaestroClient(
 	return maestroclient.NewMaestroClient(ctx, config, log)
 }
 
-// buildExecutor creates the executor with the given clients.
+// manifestProcessors post-process the rendered manifests of every executor the adapter
+// builds. Builds with org-specific processing append to it from an init function in their
+// own file, like the transports.
+var manifestProcessors []executor.ManifestProcessor
+
+// buildExecutor creates the executor with the given clients. taskName labels the config
+// of a subscription executor in config_info; it is empty for the main task config.
 func buildExecutor(
 	config *configloader.Config,
 	apiClient hyperfleetapi.Client,
 	tc transportclient.TransportClient,
 	log logger.Logger,
 	metricsRecorder *metrics.Recorder,
+	publisher executor.ResultPublisher,
+	secrets executor.SecretProvider,
+	taskName string,
 ) (*executor.Executor, error) {
-	return executor.NewBuilder().
+	builder := executor.NewBuilder().
 		WithConfig(config).
+		WithTaskName(taskName).
 		WithAPIClient(apiClient).
 		WithTransportClient(tc).
 		WithLogger(log).
 		WithMetricsRecorder(metricsRecorder).
-		Build()
+		WithManifestProcessor(manifestProcessors...)
+	if secrets != nil {
+		builder = builder.WithSecretProvider(secrets)
+	}
+	if publisher != nil {
+		builder = builder.WithResultPublisher(publisher, config.Clients.Broker.PublishTopic)
+	}
+	return builder.Build()
+}
+
+// clientHealthChecks returns the background health checks of the configured clients:
+// the HyperFleet API /healthz endpoint, each transport that can be pinged (Kubernetes
+// /version or the Maestro API) and the broker subscription. The API and transport results
+// are reported to the api and transport readiness checks; with no transport to ping, the
+// transport check is ok.
+func clientHealthChecks(
+	apiClient hyperfleetapi.Client,
+	transports map[string]transportclient.TransportClient,
+	healthServer *health.Server,
+) []health.ClientCheck {
+	checks := []health.ClientCheck{
+		{
+			Name:      "hyperfleet_api",
+			Readiness: health.CheckNameAPI,
+			Probe: func(ctx context.Context) error {
+				healthURL := strings.TrimSuffix(apiClient.BaseURL(), "/") + "/healthz"
+				resp, err := apiClient.Get(ctx, healthURL, hyperfleetapi.WithRequestRetryAttempts(1))
+				if err != nil {
+					return err
+				}
+				if !resp.IsSuccess() {
+					return fmt.Errorf("GET %s returned %d", healthURL, resp.StatusCode)
+				}
+				return nil
+			},
+		},
+		{
+			Name: "broker",
+			Probe: func(ctx context.Context) error {
+				if healthServer.Check("broker") != health.CheckOK {
+					return errors.New("broker subscription is not active")
+				}
+				return nil
+			},
+		},
+	}
+
+	pinged := false
+	for _, name := range slices.Sorted(maps.Keys(transports)) {
+		if pinger, ok := transports[name].(health.Pinger); ok {
+			checks = append(checks, health.ClientCheck{Name: name, Readiness: health.CheckNameTransport, Probe: pinger.Ping})
+			pinged = true
+		}
+	}
+	if !pinged {
+		healthServer.SetCheckResult(health.CheckNameTransport, health.CheckOK, "no transport client to probe")
+	}
+	return checks
 }
 
 // -----------------------------------------------------------------------------
@@ -490,8 +1152,11 @@ func runServe(flags *pflag.FlagSet) error {
 		}
 	}()
 
-	// Start health server
+	// Start health server; /readyz includes the configured dependency checks
 	healthServer := health.NewServer(log, HealthServerPort, config.Adapter.Name)
+	for _, name := range []string{health.CheckNameBroker, health.CheckNameTransport, health.CheckNameAPI} {
+		healthServer.SetAggregated(name, slices.Contains(config.Readiness.EffectiveChecks(), name))
+	}
 	err = healthServer.Start(ctx)
 	if err != nil {
 		errCtx := logger.WithErrorField(ctx, err)
@@ -511,6 +1176,33 @@ func runServe(flags *pflag.FlagSet) error {
 		}
 	}()
 
+	// Start the admin server for authenticated debug and operational endpoints (nil when disabled)
+	adminServer, err := admin.NewServer(config.Admin, log)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to create admin server")
+		return fmt.Errorf("failed to create admin server: %w", err)
+	}
+	err = adminServer.Start(ctx)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to start admin server")
+		return fmt.Errorf("failed to start admin server: %w", err)
+	}
+	if adminServer != nil {
+		if data, marshalErr := yaml.Marshal(config.Redacted()); marshalErr == nil {
+			adminServer.SetConfig(data)
+		}
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), HealthServerShutdownTimeout)
+		defer shutdownCancel()
+		if shutdownErr := adminServer.Shutdown(shutdownCtx); shutdownErr != nil {
+			errCtx := logger.WithErrorField(shutdownCtx, shutdownErr)
+			log.Warnf(errCtx, "Failed to shutdown admin server")
+		}
+	}()
+
 	// Start metrics server
 	metricsServer := health.NewMetricsServer(log, MetricsServerPort, health.MetricsConfig{
 		Component: config.Adapter.Name,
@@ -536,33 +1228,205 @@ func runServe(flags *pflag.FlagSet) error {
 	adapterName := metrics.ExtractAdapterName(config.Adapter.Name)
 	metricsRecorder := metrics.NewRecorder(config.Adapter.Name, version.Version, adapterName, nil)
 
+	// Status report SLIs of processed generations, served at /sli (nil when disabled)
+	sliTracker, err := sli.New(config.SLI, config.Adapter.Name, version.Version, adapterName, nil, log)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to configure SLIs")
+		return err
+	}
+	if sliTracker != nil {
+		metricsServer.SetSLIHandler(sliTracker.Handler())
+		sliTracker.Start(ctx)
+		defer sliTracker.Close()
+	}
+
+	// Record outgoing call metadata for debugging; toggled at runtime with SIGUSR1 or the admin server
+	trafficRecorder := traffic.NewRecorder(config.TrafficRecording.Capacity, config.TrafficRecording.SpanEvents)
+	trafficRecorder.SetEnabled(config.TrafficRecording.Enabled)
+	adminServer.SetTrafficHandler(trafficRecorder.Handler())
+	adminServer.RegisterToggle("traffic_recording", admin.Toggle{
+		Get: trafficRecorder.Enabled,
+		Set: trafficRecorder.SetEnabled,
+	})
+	go toggleTrafficRecordingOnSignal(ctx, trafficRecorder, log)
+	go toggleDebugLoggingOnSignal(ctx, log)
+
 	// Create real clients
 	log.Info(ctx, "Creating HyperFleet API client...")
-	apiClient, err := createAPIClient(config.Clients.HyperfleetAPI, log)
+	apiClient, err := createAPIClient(config.Clients.HyperfleetAPI, log, trafficRecorder)
 	if err != nil {
 		errCtx := logger.WithErrorField(ctx, err)
 		log.Errorf(errCtx, "Failed to create HyperFleet API client")
 		return fmt.Errorf("failed to create HyperFleet API client: %w", err)
 	}
 
-	tc, err := createTransportClient(ctx, config, log)
+	// Broker subscriptions may execute their events with their own task config
+	subscriptionConfigs, err := loadSubscriptionConfigs(ctx, log, flags, config)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to load subscription task configs")
+		return err
+	}
+
+	tc, transports, err := createTransportClients(ctx, config, log, trafficRecorder,
+		slices.Collect(maps.Values(subscriptionConfigs))...)
 	if err != nil {
 		errCtx := logger.WithErrorField(ctx, err)
 		log.Errorf(errCtx, "Failed to create transport client")
 		return err
 	}
 
+	// Create broker metrics recorder (shared by the subscriber and the optional result publisher)
+	brokerMetrics := broker.NewMetricsRecorder(config.Adapter.Name, version.Version, nil)
+
+	// Create the broker publisher when a result or dead-letter topic is configured
+	var publisher broker.Publisher
+	brokerConfig := config.Clients.Broker
+	if brokerConfig.PublishTopic != "" || brokerConfig.DeadLetterTopic != "" {
+		log.Infof(ctx, "Creating broker publisher: result_topic=%q dead_letter_topic=%q",
+			brokerConfig.PublishTopic, brokerConfig.DeadLetterTopic)
+		var pubErr error
+		publisher, pubErr = broker.NewPublisher(log, brokerMetrics)
+		if pubErr != nil {
+			errCtx := logger.WithErrorField(ctx, pubErr)
+			log.Errorf(errCtx, "Failed to create broker publisher")
+			return fmt.Errorf("failed to create broker publisher: %w", pubErr)
+		}
+		defer func() {
+			if closeErr := publisher.Close(); closeErr != nil {
+				errCtx := logger.WithErrorField(ctx, closeErr)
+				log.Warnf(errCtx, "Failed to close broker publisher")
+			}
+		}()
+	}
+	var resultPublisher executor.ResultPublisher
+	if brokerConfig.PublishTopic != "" {
+		resultPublisher = publisher
+	}
+
+	// Create the Vault secret provider when vault.* param sources can be resolved
+	var secrets executor.SecretProvider
+	if config.Clients.Vault != nil {
+		log.Infof(ctx, "Creating Vault secret provider: address=%s", config.Clients.Vault.Address)
+		vault, vaultErr := executor.NewVaultSecretProvider(config.Clients.Vault)
+		if vaultErr != nil {
+			errCtx := logger.WithErrorField(ctx, vaultErr)
+			log.Errorf(errCtx, "Failed to create Vault secret provider")
+			return fmt.Errorf("failed to create Vault secret provider: %w", vaultErr)
+		}
+		secrets = vault
+	}
+
+	// Record failed executions for replay (nil when disabled). The executor gets clients
+	// that capture what each execution reads; health checks keep the plain clients.
+	executionRecorder, err := replay.NewRecorder(config.ExecutionRecording, log)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to configure execution recording")
+		return err
+	}
+	if limit := maxConcurrentWrites(config); limit > 0 {
+		log.Infof(ctx, "Limiting transport writes to %d at a time, queued fairly per cluster", limit)
+	}
+	execAPIClient, execTC := apiClient, transportclient.NewLimitedClient(tc, maxConcurrentWrites(config))
+	if executionRecorder != nil {
+		log.Infof(ctx, "Recording failed executions to %s", config.ExecutionRecording.Dir)
+		execAPIClient = replay.WrapAPIClient(apiClient)
+		execTC = replay.WrapTransportClient(execTC)
+	}
+
 	// Build executor
 	log.Info(ctx, "Creating event executor...")
-	exec, err := buildExecutor(config, apiClient, tc, log, metricsRecorder)
+	exec, err := buildExecutor(config, execAPIClient, execTC, log, metricsRecorder, resultPublisher, secrets, "")
 	if err != nil {
 		errCtx := logger.WithErrorField(ctx, err)
 		log.Errorf(errCtx, "Failed to create executor")
 		return fmt.Errorf("failed to create executor: %w", err)
 	}
+	log.Infof(ctx, "Task config hash: %s, config fingerprint: %s", exec.ConfigHash(), exec.ConfigFingerprint())
+	healthServer.SetConfigVersion(exec.ConfigHash(), exec.ConfigFingerprint())
+
+	// Subscriptions with their own task config execute on their own executor, sharing the
+	// clients. Their task configs are not hot reloaded.
+	subscriptionExecs := make(map[string]*executor.Executor, len(subscriptionConfigs))
+	for path, subConfig := range subscriptionConfigs {
+		var subExec *executor.Executor
+		subExec, err = buildExecutor(subConfig, execAPIClient, execTC, log, metricsRecorder, resultPublisher, secrets, path)
+		if err != nil {
+			errCtx := logger.WithErrorField(ctx, err)
+			log.Errorf(errCtx, "Failed to create executor for task config %s", path)
+			return fmt.Errorf("failed to create executor for task config %s: %w", path, err)
+		}
+		subscriptionExecs[path] = subExec
+	}
+
+	// Task config hot reload (optional)
+	err = startTaskConfigWatcher(ctx, log, flags, exec, transports, healthServer, adminServer, metricsRecorder)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to start task config watcher")
+		return err
+	}
+
+	// Failure notifications (nil when no targets are configured)
+	notifier := notification.New(config.Notifications, config.Adapter.Name, log)
+	defer notifier.Close()
+
+	// Event sharding between StatefulSet replicas (nil when disabled)
+	sharder, err := createSharder(ctx, config.Sharding, log)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to configure sharding")
+		return err
+	}
+
+	// Event deduplication (nil when disabled)
+	deduplicator, err := createDeduplicator(ctx, config.Deduplication, config.Adapter.Name, log)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to configure event deduplication")
+		return err
+	}
+
+	// Create the event handlers and subscribe to broker
+	var deadLetter executor.DeadLetterQueue
+	if brokerConfig.DeadLetterTopic != "" {
+		deadLetter = executor.DeadLetterQueue{
+			Publisher:   publisher,
+			Topic:       brokerConfig.DeadLetterTopic,
+			Adapter:     config.Adapter.Name,
+			MaxAttempts: brokerConfig.MaxDeliveryAttempts,
+		}
+	}
 
-	// Create the event handler and subscribe to broker
-	handler := executor.AlwaysAck(executor.WithMetrics(exec.CreateHandler(), metricsRecorder, log), log)
+	// Ack messages according to the ack mode. Events execute on a worker pool when concurrency
+	// is configured or messages are acked before execution; events for the same cluster run
+	// one at a time and the pool is drained after the subscriber closes. Broker events
+	// are checked against the event filter and the filter of their subscription first, so
+	// subscriptions have their own pool when there is a filter or a task config of their own.
+	var ackers []*executor.Acknowledger
+	newAcker := func(eventExec *executor.Executor, eventFilter, subscriptionFilter string) *executor.Acknowledger {
+		handler := executor.WithSharding(executor.WithMetrics(
+			executor.WithDeduplication(executor.WithSLI(executor.WithNotifications(executor.WithDeadLetter(
+				replay.WithRecorder(eventExec.CreateHandler(), executionRecorder), deadLetter, log), notifier), sliTracker),
+				deduplicator, log), metricsRecorder, log), sharder, metricsRecorder, log)
+		handler = executor.WithEventFilter(handler, "subscription filter", subscriptionFilter, metricsRecorder, log)
+		handler = executor.WithEventFilter(handler, "event filter", eventFilter, metricsRecorder, log)
+		acker := executor.NewAcknowledger(executor.AlwaysAck(handler, log), brokerConfig.EffectiveAckMode(),
+			brokerConfig.Concurrency, metricsRecorder, log)
+		ackers = append(ackers, acker)
+		return acker
+	}
+	// The main handler executes scheduled events, and the subscriptions without a filter or
+	// a task config of their own
+	mainAcker := newAcker(exec, "", "")
+	handler := mainAcker.Handle
+	if mainAcker.Mode() == configloader.AckModeBeforeExecute {
+		log.Infof(ctx, "Acking messages before execution on %d workers (at-most-once)", mainAcker.Workers())
+	} else {
+		log.Infof(ctx, "Acking messages after execution on %d workers (at-least-once)", max(mainAcker.Workers(), 1))
+	}
 
 	// Handle signals for graceful shutdown
 	sigCh := make(chan os.Signal, 1)
@@ -580,61 +1444,128 @@ func runServe(flags *pflag.FlagSet) error {
 		os.Exit(1)
 	}()
 
-	// Get broker config
-	subscriptionID := config.Clients.Broker.SubscriptionID
-	if subscriptionID == "" {
-		err = fmt.Errorf("clients.broker.subscription_id is required")
-		errCtx := logger.WithErrorField(ctx, err)
-		log.Errorf(errCtx, "Missing required broker configuration")
-		return err
+	// Create a broker subscriber per subscription and subscribe. Each subscription manager
+	// owns its subscriber and recreates it when the broker reports that it has stopped. The
+	// adapter is ready while every subscription is active.
+	subscriptions := brokerConfig.EffectiveSubscriptions()
+	readiness := subscription.NewReadiness(len(subscriptions), func(ready bool) {
+		healthServer.SetBrokerReady(ready)
+		notifier.SetDegraded(ctx, !ready, "broker subscription is not active")
+	})
+	// With the http broker type, events are posted to an endpoint of the adapter
+	var receiver *httpreceiver.Receiver
+	if brokerConfig.EffectiveType() == configloader.BrokerTypeHTTP {
+		receiver, err = httpreceiver.NewReceiver(*brokerConfig.HTTP, log)
+		if err == nil {
+			err = receiver.Start(ctx)
+		}
+		if err != nil {
+			errCtx := logger.WithErrorField(ctx, err)
+			log.Errorf(errCtx, "Failed to start HTTP receiver")
+			return fmt.Errorf("failed to start HTTP receiver: %w", err)
+		}
+		defer func() {
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), HealthServerShutdownTimeout)
+			defer shutdownCancel()
+			if shutdownErr := receiver.Shutdown(shutdownCtx); shutdownErr != nil {
+				errCtx := logger.WithErrorField(shutdownCtx, shutdownErr)
+				log.Warnf(errCtx, "Failed to shutdown HTTP receiver")
+			}
+		}()
 	}
 
-	topic := config.Clients.Broker.Topic
-	if topic == "" {
-		err = fmt.Errorf("clients.broker.topic is required")
-		errCtx := logger.WithErrorField(ctx, err)
-		log.Errorf(errCtx, "Missing required broker configuration")
-		return err
+	if brokerConfig.EffectiveType() == configloader.BrokerTypeHyperfleet {
+		setBrokerParallelism(ctx, brokerConfig.Concurrency, log)
 	}
 
-	// Create broker metrics recorder
-	brokerMetrics := broker.NewMetricsRecorder(config.Adapter.Name, version.Version, nil)
+	subManagers := make([]*subscription.Manager, 0, len(subscriptions))
+	for i, sub := range subscriptions {
+		if sub.SubscriptionID == "" {
+			err = fmt.Errorf("clients.broker.subscription_id is required")
+			errCtx := logger.WithErrorField(ctx, err)
+			log.Errorf(errCtx, "Missing required broker configuration")
+			return err
+		}
+		if sub.Topic == "" {
+			err = fmt.Errorf("clients.broker.topic is required")
+			errCtx := logger.WithErrorField(ctx, err)
+			log.Errorf(errCtx, "Missing required broker configuration")
+			return err
+		}
+		subscriptionID := sharder.SubscriptionID(sub.SubscriptionID)
 
-	// Create broker subscriber and subscribe
-	log.Info(ctx, "Creating broker subscriber...")
-	subscriber, err := broker.NewSubscriber(log, subscriptionID, brokerMetrics)
-	if err != nil {
-		errCtx := logger.WithErrorField(ctx, err)
-		log.Errorf(errCtx, "Failed to create subscriber")
-		return fmt.Errorf("failed to create subscriber: %w", err)
+		subHandler := handler
+		if sub.TaskConfig != "" || sub.Filter != "" || config.EventFilter != "" {
+			subExec := exec
+			if sub.TaskConfig != "" {
+				subExec = subscriptionExecs[sub.TaskConfig]
+			}
+			subHandler = newAcker(subExec, config.EventFilter, sub.Filter).Handle
+		}
+		// Events of other types and subjects are acked before they are decoded or queued
+		subHandler = executor.WithEventRouting(
+			executor.WithEventRouting(subHandler, sub.Routing, metricsRecorder, log),
+			config.EventRouting, metricsRecorder, log)
+
+		log.Infof(ctx, "Subscribing to broker topic %s (subscription %s)...", sub.Topic, sub.DisplayName())
+		subManager := subscription.NewManager(
+			func() (broker.Subscriber, error) {
+				switch brokerConfig.EffectiveType() {
+				case configloader.BrokerTypeKafka:
+					return kafkabroker.NewSubscriber(*brokerConfig.Kafka, subscriptionID, log, brokerMetrics)
+				case configloader.BrokerTypeNATS:
+					return natsbroker.NewSubscriber(*brokerConfig.NATS, subscriptionID, log, brokerMetrics)
+				case configloader.BrokerTypeHTTP:
+					return receiver.NewSubscriber(subscriptionID, brokerMetrics), nil
+				default:
+					return broker.NewSubscriber(log, subscriptionID, brokerMetrics)
+				}
+			},
+			sub.Topic, subHandler, log,
+			subscription.WithMetrics(metricsRecorder),
+			subscription.WithReadiness(readiness.Member(i)),
+		)
+		if err = subManager.Start(ctx); err != nil {
+			errCtx := logger.WithErrorField(ctx, err)
+			log.Errorf(errCtx, "Failed to subscribe to topic %s", sub.Topic)
+			for _, started := range subManagers {
+				_ = started.Close() //nolint:errcheck // best-effort cleanup, the start error is returned
+			}
+			return err
+		}
+		subManagers = append(subManagers, subManager)
 	}
-	log.Info(ctx, "Broker subscriber created successfully")
+	log.Infof(ctx, "Successfully subscribed to %d broker topic(s)", len(subManagers))
+	log.Info(ctx, "Adapter is ready to process events")
 
-	log.Info(ctx, "Subscribing to broker topic...")
-	err = subscriber.Subscribe(ctx, topic, handler)
+	// Probe client dependencies in the background, independent of event flow
+	clientChecker := health.NewClientChecker(log, metricsRecorder, 0, 0,
+		clientHealthChecks(apiClient, transports, healthServer)...)
+	clientChecker.ReportTo(healthServer)
+	go clientChecker.Run(ctx)
+
+	// Inject scheduled synthetic events (nil when no schedules are configured)
+	scheduler, err := schedule.New(config.Schedules, handler, log)
 	if err != nil {
 		errCtx := logger.WithErrorField(ctx, err)
-		log.Errorf(errCtx, "Failed to subscribe to topic")
-		return fmt.Errorf("failed to subscribe to topic: %w", err)
+		log.Errorf(errCtx, "Failed to create the event scheduler")
+		return err
 	}
-	log.Info(ctx, "Successfully subscribed to broker topic")
+	schedulerDone := make(chan struct{})
+	go func() {
+		defer close(schedulerDone)
+		scheduler.Run(ctx)
+	}()
 
-	// Mark as ready
-	healthServer.SetBrokerReady(true)
-	log.Info(ctx, "Adapter is ready to process events")
-
-	// Monitor subscription errors
-	fatalErrCh := make(chan error, 1)
-	go func() {
-		for subErr := range subscriber.Errors() {
-			errCtx := logger.WithErrorField(ctx, subErr)
-			log.Errorf(errCtx, "Subscription error")
-			select {
-			case fatalErrCh <- subErr:
-			default:
+	// Supervise the subscriptions; only unrecoverable errors are reported here
+	fatalErrCh := make(chan error, len(subManagers))
+	for _, subManager := range subManagers {
+		go func() {
+			if err := subManager.Run(ctx); err != nil {
+				fatalErrCh <- err
 			}
-		}
-	}()
+		}()
+	}
 
 	log.Info(ctx, "Adapter started, waiting for events...")
 
@@ -649,8 +1580,8 @@ func runServe(flags *pflag.FlagSet) error {
 		cancel()
 	}
 
-	// Close subscriber gracefully
-	log.Info(ctx, "Closing broker subscriber...")
+	// Close subscribers gracefully
+	log.Info(ctx, "Closing broker subscribers...")
 	shutdownCtx, shutdownCancel := context.WithTimeout(
 		context.Background(), 30*time.Second,
 	)
@@ -658,7 +1589,11 @@ func runServe(flags *pflag.FlagSet) error {
 
 	closeDone := make(chan error, 1)
 	go func() {
-		closeDone <- subscriber.Close()
+		var closeErrs []error
+		for _, subManager := range subManagers {
+			closeErrs = append(closeErrs, subManager.Close())
+		}
+		closeDone <- errors.Join(closeErrs...)
 	}()
 
 	select {
@@ -675,6 +1610,23 @@ func runServe(flags *pflag.FlagSet) error {
 		log.Error(errCtx, "Subscriber close timed out")
 	}
 
+	select {
+	case <-schedulerDone:
+	case <-shutdownCtx.Done():
+		log.Error(ctx, "Timed out waiting for the scheduled event in progress")
+	}
+
+	for _, acker := range ackers {
+		if acker.Workers() == 0 {
+			continue
+		}
+		log.Info(ctx, "Waiting for queued events to finish...")
+		if err := acker.Close(shutdownCtx); err != nil {
+			errCtx := logger.WithErrorField(ctx, err)
+			log.Error(errCtx, "Timed out waiting for queued events")
+		}
+	}
+
 	log.Info(ctx, "Adapter shutdown complete")
 
 	return nil
@@ -684,7 +1636,9 @@ func runServe(flags *pflag.FlagSet) error {
 // Dry-run mode
 // -----------------------------------------------------------------------------
 
-// runDryRun processes a single CloudEvent from file using mock clients.
+// runDryRun processes CloudEvents from file using mock clients. --dry-run-event names a
+// single event file, or a directory or glob pattern to run every matching event against
+// the same mocks and print an aggregate report.
 func runDryRun(flags *pflag.FlagSet) error {
 	ctx := context.Background()
 
@@ -692,7 +1646,7 @@ func runDryRun(flags *pflag.FlagSet) error {
 	log, err := logger.NewLogger(logger.Config{
 		Level:     "warn",
 		Format:    "text",
-		Output:    "stderr",
+		Output:    logOutputStderr,
 		Component: "dry-run",
 	})
 	if err != nil {
@@ -705,16 +1659,15 @@ func runDryRun(flags *pflag.FlagSet) error {
 		return err
 	}
 
-	// Load CloudEvent from file
 	if dryRunEvent == "" {
 		return fmt.Errorf("--dry-run-event is required for dry-run mode")
 	}
-	evt, err := dryrun.LoadCloudEvent(dryRunEvent)
+	eventFiles, batch, err := dryrun.ResolveEventFiles(dryRunEvent)
 	if err != nil {
-		return fmt.Errorf("failed to load event: %w", err)
+		return fmt.Errorf("failed to resolve event files: %w", err)
 	}
 
-	// Create dryrun API client
+	// Load the mock inputs once; every event gets fresh clients built from them
 	var dryrunResponsesFile *dryrun.DryrunResponsesFile
 	if dryRunAPIResponses != "" {
 		dryrunResponsesFile, err = dryrun.LoadDryrunResponses(dryRunAPIResponses)
@@ -722,63 +1675,452 @@ func runDryRun(flags *pflag.FlagSet) error {
 			return fmt.Errorf("failed to load dryrun responses: %w", err)
 		}
 	}
-	dryrunAPI, err := dryrun.NewDryrunAPIClient(dryrunResponsesFile)
-	if err != nil {
-		return fmt.Errorf("failed to create dryrun API client: %w", err)
-	}
-
-	// Create recording transport client
-	var dryrunClient *dryrun.DryrunTransportClient
+	var overrides dryrun.DiscoveryOverrides
 	if dryRunDiscovery != "" {
-		var overrides dryrun.DiscoveryOverrides
 		overrides, err = dryrun.LoadDiscoveryOverrides(dryRunDiscovery)
 		if err != nil {
 			return fmt.Errorf("failed to load discovery overrides: %w", err)
 		}
+	}
+
+	if !batch {
+		trace, err := dryRunEventFile(ctx, config, eventFiles[0], dryrunResponsesFile, overrides, log)
+		if err != nil {
+			return err
+		}
+		switch dryRunOutput {
+		case outputFormatJSON:
+			data, err := trace.FormatJSON()
+			if err != nil {
+				return fmt.Errorf("failed to format trace as JSON: %w", err)
+			}
+			fmt.Println(string(data))
+		default:
+			fmt.Print(trace.FormatText())
+		}
+
+		if trace.Result.Status == executor.StatusFailed {
+			for _, err := range trace.Result.Errors {
+				fmt.Fprintf(os.Stderr, "Error in %s: %v\n", err.Phase, err)
+			}
+		}
+		if dryRunServeUI {
+			return serveDryRunUI(&dryrun.BatchReport{Entries: []dryrun.BatchEntry{{File: eventFiles[0], Trace: trace}}})
+		}
+		return nil
+	}
+
+	report := &dryrun.BatchReport{}
+	for _, file := range eventFiles {
+		trace, err := dryRunEventFile(ctx, config, file, dryrunResponsesFile, overrides, log)
+		report.Entries = append(report.Entries, dryrun.BatchEntry{File: file, Trace: trace, Err: err})
+	}
+
+	switch dryRunOutput {
+	case outputFormatJSON:
+		data, err := report.FormatJSON()
+		if err != nil {
+			return fmt.Errorf("failed to format batch report as JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	default:
+		fmt.Print(report.FormatText(dryRunVerbose))
+	}
+
+	if dryRunServeUI {
+		if err := serveDryRunUI(report); err != nil {
+			return err
+		}
+	}
+
+	if failed := report.Failed(); failed > 0 {
+		return &exitCodeError{
+			err:  fmt.Errorf("%d of %d dry-run events failed", failed, len(report.Entries)),
+			code: 1,
+		}
+	}
+	return nil
+}
+
+// serveDryRunUI serves the trace UI of report until the process is interrupted. The UI
+// shows the verbose traces, with rendered manifests and API bodies.
+func serveDryRunUI(report *dryrun.BatchReport) error {
+	for _, e := range report.Entries {
+		if e.Trace != nil {
+			e.Trace.Verbose = true
+		}
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return dryrun.ServeUI(ctx, dryRunUIAddress, report, func(url string) {
+		fmt.Fprintf(os.Stderr, "Serving the dry-run trace UI at %s (Ctrl+C to stop)\n", url)
+	})
+}
+
+// dryRunEventFile executes the CloudEvent in file against fresh mock clients and returns
+// its execution trace.
+func dryRunEventFile(
+	ctx context.Context,
+	config *configloader.Config,
+	file string,
+	responses *dryrun.DryrunResponsesFile,
+	overrides dryrun.DiscoveryOverrides,
+	log logger.Logger,
+) (*dryrun.ExecutionTrace, error) {
+	evt, err := dryrun.LoadCloudEvent(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load event: %w", err)
+	}
+	return dryRunExecute(ctx, config, evt, responses, overrides, log)
+}
+
+// dryRunExecute executes evt against fresh mock clients and returns its execution trace.
+func dryRunExecute(
+	ctx context.Context,
+	config *configloader.Config,
+	evt *cloudevents.Event,
+	responses *dryrun.DryrunResponsesFile,
+	overrides dryrun.DiscoveryOverrides,
+	log logger.Logger,
+) (*dryrun.ExecutionTrace, error) {
+	dryrunAPI, err := dryrun.NewDryrunAPIClient(responses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dryrun API client: %w", err)
+	}
+
+	// Create recording transport client
+	var dryrunClient *dryrun.DryrunTransportClient
+	if overrides != nil {
 		dryrunClient = dryrun.NewDryrunTransportClientWithOverrides(overrides)
 	} else {
 		dryrunClient = dryrun.NewDryrunTransportClient()
 	}
 
-	// Build executor with mock clients (same builder as serve, no metrics in dry-run)
-	exec, err := buildExecutor(config, dryrunAPI, dryrunClient, log, nil)
+	// Build executor with mock clients (same builder as serve, no metrics in dry-run).
+	// Secrets resolve to placeholders so dry-run never contacts Vault.
+	exec, err := buildExecutor(config, dryrunAPI, dryrunClient, log, nil, nil, dryrun.NewDryrunSecretProvider(), "")
 	if err != nil {
-		return fmt.Errorf("failed to create executor: %w", err)
+		return nil, fmt.Errorf("failed to create executor: %w", err)
 	}
 
 	// Execute with event data
-	result := exec.Execute(ctx, evt.Data())
+	result := exec.Execute(ctx, evt)
 
-	// Build and output execution trace
-	trace := &dryrun.ExecutionTrace{
+	return &dryrun.ExecutionTrace{
 		EventID:   evt.ID(),
 		EventType: evt.Type(),
 		Result:    result,
 		APIClient: dryrunAPI,
 		Transport: dryrunClient,
 		Verbose:   dryRunVerbose,
+	}, nil
+}
+
+// -----------------------------------------------------------------------------
+// Replay mode
+// -----------------------------------------------------------------------------
+
+// runReplay executes a recorded failed execution with the dry-run clients, serving the
+// recorded API responses and objects, and prints the trace.
+func runReplay(flags *pflag.FlagSet, recordingDir string) error {
+	ctx := context.Background()
+
+	// Create logger on stderr so stdout is reserved for trace output
+	log, err := logger.NewLogger(logger.Config{
+		Level:     "warn",
+		Format:    "text",
+		Output:    "stderr",
+		Component: "replay",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	config, err := loadConfig(ctx, log, flags)
+	if err != nil {
+		return err
+	}
+
+	rec, err := replay.Load(filepath.Clean(recordingDir))
+	if err != nil {
+		return fmt.Errorf("failed to load recording: %w", err)
+	}
+
+	trace, err := dryRunExecute(ctx, config, rec.Event, rec.Responses, rec.Discovery, log)
+	if err != nil {
+		return err
 	}
 
 	switch dryRunOutput {
-	case "json":
+	case outputFormatJSON:
 		data, err := trace.FormatJSON()
 		if err != nil {
 			return fmt.Errorf("failed to format trace as JSON: %w", err)
 		}
 		fmt.Println(string(data))
 	default:
+		fmt.Printf("Replaying event %s recorded at %s\n", rec.Result.EventID, rec.Result.RecordedAt.Format(time.RFC3339))
+		for _, recorded := range rec.Result.Errors {
+			fmt.Printf("  Recorded error: %s\n", recorded)
+		}
+		fmt.Println()
 		fmt.Print(trace.FormatText())
 	}
 
-	if result.Status == executor.StatusFailed {
-		for phase, err := range result.Errors {
-			fmt.Fprintf(os.Stderr, "Error in %s: %v\n", phase, err)
+	if trace.Result.Status == executor.StatusFailed {
+		for _, err := range trace.Result.Errors {
+			fmt.Fprintf(os.Stderr, "Error in %s: %v\n", err.Phase, err)
+		}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Backfill mode
+// -----------------------------------------------------------------------------
+
+// runBackfill executes an event for every resource of a HyperFleet API collection with
+// the real clients, printing a progress bar on stderr and the failed resources at the end.
+func runBackfill(flags *pflag.FlagSet) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Log warnings only by default, on stderr below the progress bar
+	level := logLevelWarn
+	if logLevel != "" {
+		level = logLevel
+	}
+	log, err := logger.NewLogger(logger.Config{
+		Level:     level,
+		Format:    "text",
+		Output:    "stderr",
+		Component: "backfill",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	config, err := loadConfig(ctx, log, flags)
+	if err != nil {
+		return err
+	}
+
+	if oidc := apiOIDCConfig(config); oidc != nil {
+		if err = hyperfleetapi.EnsureOIDCLogin(ctx, oidc, os.Stderr); err != nil {
+			return err
+		}
+	}
+	apiClient, err := createAPIClient(config.Clients.HyperfleetAPI, log, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create HyperFleet API client: %w", err)
+	}
+	tc, _, err := createTransportClients(ctx, config, log, nil)
+	if err != nil {
+		return err
+	}
+	var secrets executor.SecretProvider
+	if config.Clients.Vault != nil {
+		vault, vaultErr := executor.NewVaultSecretProvider(config.Clients.Vault)
+		if vaultErr != nil {
+			return fmt.Errorf("failed to create Vault secret provider: %w", vaultErr)
+		}
+		secrets = vault
+	}
+	exec, err := buildExecutor(config, apiClient, transportclient.NewLimitedClient(tc, maxConcurrentWrites(config)),
+		log, nil, nil, secrets, "")
+	if err != nil {
+		return fmt.Errorf("failed to create executor: %w", err)
+	}
+
+	apiVersion := config.Clients.HyperfleetAPI.Version
+	if apiVersion == "" {
+		apiVersion = "v1"
+	}
+	opts := backfill.Options{
+		Path:       "/api/hyperfleet/" + apiVersion + "/" + strings.Trim(backfillCollection, "/"),
+		Search:     backfillSearch,
+		Kind:       backfillKind,
+		EventType:  backfillEventType,
+		Checkpoint: backfillCheckpoint,
+		Rate:       backfillRate,
+		PageSize:   backfillPageSize,
+	}
+	if info, statErr := os.Stderr.Stat(); statErr == nil && info.Mode()&os.ModeCharDevice != 0 {
+		opts.Progress = os.Stderr
+	}
+	handler := func(ctx context.Context, evt *cloudevents.Event) error {
+		result := exec.Execute(ctx, evt)
+		if result.Status == executor.StatusFailed {
+			return errors.New(result.Errors.String())
 		}
+		return nil
 	}
 
+	summary, err := backfill.Run(ctx, apiClient, handler, opts, log)
+	if summary != nil {
+		fmt.Printf("Backfill of %s: %d processed, %d failed\n", opts.Path, summary.Processed, len(summary.Failed))
+		for _, id := range summary.Failed {
+			fmt.Printf("  Failed: %s\n", id)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) && opts.Checkpoint != "" {
+			return fmt.Errorf("backfill interrupted: run it again with the same flags to resume from %s", opts.Checkpoint)
+		}
+		return err
+	}
+	if len(summary.Failed) > 0 {
+		return fmt.Errorf("%d events failed", len(summary.Failed))
+	}
 	return nil
 }
 
+// -----------------------------------------------------------------------------
+// Smoke mode
+// -----------------------------------------------------------------------------
+
+// runSmoke creates, reads, updates and deletes a canary object through one transport
+// client and prints the outcome of each operation
+func runSmoke(flags *pflag.FlagSet) error {
+	if smokeOutput != outputFormatText && smokeOutput != outputFormatJSON {
+		return fmt.Errorf("unsupported output format %q (expected text or json)", smokeOutput)
+	}
+	gvk, err := smoke.ParseGVK(smokeGVK)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	level := logLevelWarn
+	if logLevel != "" {
+		level = logLevel
+	}
+	log, err := logger.NewLogger(logger.Config{
+		Level:     level,
+		Format:    "text",
+		Output:    "stderr",
+		Component: "smoke",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	config, err := loadConfig(ctx, log, flags)
+	if err != nil {
+		return err
+	}
+
+	transport := smokeTransport
+	if transport == "" {
+		transport = defaultTransportClient(config)
+	}
+	if transport != configloader.TransportClientKubernetes && transport != configloader.TransportClientMaestro {
+		return fmt.Errorf("unsupported transport %q (expected %s or %s)",
+			transport, configloader.TransportClientKubernetes, configloader.TransportClientMaestro)
+	}
+	client, err := transportclient.New(ctx, transport, config, transportclient.FactoryOptions{Logger: log})
+	if err != nil {
+		return fmt.Errorf("failed to create %s transport client: %w", transport, err)
+	}
+
+	name := smokeName
+	if name == "" {
+		name = fmt.Sprintf("hyperfleet-adapter-smoke-%d", time.Now().Unix())
+	}
+	ctx, cancel := context.WithTimeout(ctx, smokeTimeout)
+	defer cancel()
+	report, err := smoke.Run(ctx, client, smoke.Options{
+		GVK:       gvk,
+		Transport: transport,
+		Namespace: smokeNamespace,
+		Name:      name,
+		Consumer:  smokeConsumer,
+	})
+	if err != nil {
+		return err
+	}
+
+	if smokeOutput == outputFormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if !report.Passed {
+		return fmt.Errorf("smoke test of the %s transport failed", transport)
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Schema mode
+// -----------------------------------------------------------------------------
+
+// runSchema prints the JSON Schema of the config file selected by --kind.
+// apiOIDCConfig returns the interactive OIDC login of the HyperFleet API client, nil when
+// it authenticates otherwise
+func apiOIDCConfig(config *configloader.Config) *hyperfleetapi.OIDCConfig {
+	if auth := config.Clients.HyperfleetAPI.Auth; auth != nil {
+		return auth.OIDC
+	}
+	return nil
+}
+
+// runLogin logs in to the OIDC issuer of the HyperFleet API client and caches the token
+func runLogin(flags *pflag.FlagSet) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	level := "warn"
+	if logLevel != "" {
+		level = logLevel
+	}
+	log, err := logger.NewLogger(logger.Config{
+		Level:     level,
+		Format:    "text",
+		Output:    "stderr",
+		Component: "login",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	config, err := loadConfig(ctx, log, flags)
+	if err != nil {
+		return err
+	}
+	oidc := apiOIDCConfig(config)
+	if oidc == nil {
+		return fmt.Errorf("clients.hyperfleet_api.auth.oidc is not configured")
+	}
+	path, err := hyperfleetapi.LoginOIDC(ctx, oidc, os.Stderr)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Logged in to %s, token cached in %s\n", oidc.Issuer, path)
+	return nil
+}
+
+func runSchema() error {
+	if schemaKind == schemaKindTrace {
+		_, err := os.Stdout.Write(dryrun.TraceSchema())
+		return err
+	}
+	schema, err := configloader.GenerateSchema(schemaKind)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
+
 // -----------------------------------------------------------------------------
 // Config-dump mode
 // -----------------------------------------------------------------------------
@@ -805,23 +2147,242 @@ func runConfigDump(flags *pflag.FlagSet) error {
 	return nil
 }
 
+// -----------------------------------------------------------------------------
+// Config-effects mode
+// -----------------------------------------------------------------------------
+
+// runConfigEffects loads the adapter configuration and prints its mutating effects.
+func runConfigEffects(flags *pflag.FlagSet) error {
+	ctx := context.Background()
+	// Log to stderr so stdout only carries the report
+	logCfg := buildLoggerConfig("config-effects", nil)
+	logCfg.Output = logOutputStderr
+	log, err := logger.NewLogger(logCfg)
+	if err != nil {
+		return fmt.Errorf("failed to create logger: %w", err)
+	}
+
+	config, err := loadConfig(ctx, log, flags)
+	if err != nil {
+		return err
+	}
+
+	effects := configloader.AnalyzeEffects(config)
+	switch effectsOutput {
+	case outputFormatText:
+		return effects.WriteText(os.Stdout)
+	case outputFormatJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(effects)
+	case "yaml":
+		data, err := yaml.Marshal(effects)
+		if err != nil {
+			return fmt.Errorf("failed to marshal effects: %w", err)
+		}
+		fmt.Print(string(data))
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q (expected text, json or yaml)", effectsOutput)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Validate mode
+// -----------------------------------------------------------------------------
+
+// Exit codes of the validate command
+const (
+	validateExitInvalid = 1
+	validateExitError   = 2
+)
+
+// validationReport is the validate command output
+type validationReport struct {
+	Errors []validationReportError `json:"errors"`
+	Valid  bool                    `json:"valid"`
+}
+
+// validationReportError is a single validation error. Path is empty for errors that
+// are not tied to a config field, such as a missing file or invalid YAML.
+type validationReportError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// runValidate loads the adapter configuration with full validation and prints the result.
+// Errors go to stdout as the report; logs go to stderr.
+func runValidate(flags *pflag.FlagSet) error {
+	if validateOutput != outputFormatText && validateOutput != outputFormatJSON {
+		err := fmt.Errorf("unsupported output format %q (expected text or json)", validateOutput)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return &exitCodeError{err: err, code: validateExitError}
+	}
+
+	ctx := context.Background()
+	logCfg := buildLoggerConfig("validate", nil)
+	logCfg.Output = logOutputStderr
+	log, err := logger.NewLogger(logCfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return &exitCodeError{err: fmt.Errorf("failed to create logger: %w", err), code: validateExitError}
+	}
+
+	report := validationReport{Valid: true, Errors: []validationReportError{}}
+	_, loadErr := loadConfig(ctx, log, flags)
+	for _, e := range configloader.ValidationErrorsOf(loadErr) {
+		report.Valid = false
+		report.Errors = append(report.Errors, validationReportError{Path: e.Path, Message: e.Message})
+	}
+
+	if validateOutput == outputFormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return &exitCodeError{err: err, code: validateExitError}
+		}
+	} else {
+		for _, e := range report.Errors {
+			if e.Path == "" {
+				fmt.Println(e.Message)
+				continue
+			}
+			fmt.Printf("%s: %s\n", e.Path, e.Message)
+		}
+		if report.Valid {
+			fmt.Println("Configuration is valid")
+		}
+	}
+
+	if !report.Valid {
+		return &exitCodeError{err: loadErr, code: validateExitInvalid}
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Verify-event mode
+// -----------------------------------------------------------------------------
+
+// Exit codes of the verify-event command
+const (
+	verifyEventExitMissing = 1
+	verifyEventExitError   = 2
+)
+
+// runVerifyEvent checks a CloudEvent against the event.* param sources of the config.
+// The report goes to stdout; logs and errors go to stderr.
+func runVerifyEvent(flags *pflag.FlagSet) error {
+	fail := func(err error) error {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		return &exitCodeError{err: err, code: verifyEventExitError}
+	}
+
+	if verifyEventOutput != outputFormatText && verifyEventOutput != outputFormatJSON {
+		return fail(fmt.Errorf("unsupported output format %q (expected text or json)", verifyEventOutput))
+	}
+	if verifyEventPath == "" {
+		return fail(fmt.Errorf("--event is required"))
+	}
+
+	ctx := context.Background()
+	logCfg := buildLoggerConfig("verify-event", nil)
+	logCfg.Output = logOutputStderr
+	log, err := logger.NewLogger(logCfg)
+	if err != nil {
+		return fail(fmt.Errorf("failed to create logger: %w", err))
+	}
+
+	config, err := loadConfig(ctx, log, flags)
+	if err != nil {
+		return fail(err)
+	}
+
+	evt, err := dryrun.LoadCloudEvent(verifyEventPath)
+	if err != nil {
+		return fail(fmt.Errorf("failed to load event: %w", err))
+	}
+
+	report, err := dryrun.VerifyEventContract(config, evt)
+	if err != nil {
+		return fail(err)
+	}
+
+	if verifyEventOutput == outputFormatJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(os.Stdout)
+	}
+	if err != nil {
+		return &exitCodeError{err: err, code: verifyEventExitError}
+	}
+
+	if !report.Valid {
+		return &exitCodeError{
+			err:  fmt.Errorf("event %s is missing fields required by the adapter", report.EventID),
+			code: verifyEventExitMissing,
+		}
+	}
+	return nil
+}
+
+// setBrokerParallelism makes the hyperfleet-broker subscriber hand over as many messages
+// at once as there are workers. Workers wait for execution before a message is acked
+// (after_execute), so with the library's default of one message at a time the pool would
+// never execute two events concurrently. A parallelism set by the operator is kept.
+// Messages handed over at once race to the pool, so events for the same cluster still run
+// one at a time but not necessarily in delivery order.
+func setBrokerParallelism(ctx context.Context, concurrency int, log logger.Logger) {
+	if concurrency <= 1 {
+		return
+	}
+	if value := os.Getenv(brokerParallelismEnv); value != "" {
+		log.Infof(ctx, "Keeping broker subscriber parallelism %s=%s (concurrency %d)",
+			brokerParallelismEnv, value, concurrency)
+		return
+	}
+	if err := os.Setenv(brokerParallelismEnv, strconv.Itoa(concurrency)); err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Warnf(errCtx, "Failed to set broker subscriber parallelism to %d", concurrency)
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Flag registration helpers (shared between serve and config-dump)
 // -----------------------------------------------------------------------------
 
-// addConfigPathFlags registers the --config and --task-config path flags.
+// addConfigPathFlags registers the --config and --task-config path flags, plus the
+// options used when --task-config is an oci:// artifact reference.
 func addConfigPathFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&configPath, "config", "c", "",
 		fmt.Sprintf("Path to adapter deployment config file (can also use %s env var)",
 			configloader.EnvAdapterConfig))
 	cmd.Flags().StringVarP(&taskConfigPath, "task-config", "t", "",
-		fmt.Sprintf("Path to adapter task config file (can also use %s env var)",
+		fmt.Sprintf("Path to adapter task config file or oci://registry/repo:tag reference (can also use %s env var)",
 			configloader.EnvTaskConfigPath))
+	cmd.Flags().StringVar(&taskCacheDir, "task-config-cache-dir", "",
+		fmt.Sprintf("Cache directory for task config artifacts pulled from OCI registries. Env: %s",
+			configloader.EnvTaskConfigCacheDir))
+	cmd.Flags().StringVar(&taskVerifyKey, "task-config-verify-key", "",
+		fmt.Sprintf("Cosign public key (PEM) required to verify OCI task config artifacts. Env: %s",
+			configloader.EnvTaskConfigVerifyKey))
+	cmd.Flags().StringVar(&policyBundle, "policy-bundle", "",
+		fmt.Sprintf("Policy file or directory checked against the loaded config; violations block startup. Env: %s",
+			configloader.EnvPolicyBundle))
 }
 
 // addOverrideFlags registers all configuration override flags (Maestro, API, broker, Kubernetes).
 // These flags are available on both the serve and config-dump commands.
 func addOverrideFlags(cmd *cobra.Command) {
+	cmd.Flags().String("missing-env-vars", "",
+		"When an optional env.* param has no value at load: warn or fail (default warn). "+
+			"Env: HYPERFLEET_MISSING_ENV_VARS")
+	cmd.Flags().String("json-numbers", "",
+		"How numbers of event data and API responses are decoded: float or strict (default float). "+
+			"Env: HYPERFLEET_JSON_NUMBERS")
+
 	// Maestro override flags
 	cmd.Flags().String("maestro-grpc-server-address", "",
 		"Maestro gRPC server address. Env: HYPERFLEET_MAESTRO_GRPC_SERVER_ADDRESS")
@@ -861,10 +2422,28 @@ func addOverrideFlags(cmd *cobra.Command) {
 		"HyperFleet API retry base delay (e.g. 1s). Env: HYPERFLEET_API_BASE_DELAY")
 	cmd.Flags().String("hyperfleet-api-max-delay", "",
 		"HyperFleet API retry max delay (e.g. 30s). Env: HYPERFLEET_API_MAX_DELAY")
+	cmd.Flags().String("hyperfleet-api-profile", "",
+		"HyperFleet API profile to apply from clients.hyperfleet_api.profiles. Env: HYPERFLEET_API_PROFILE")
+	cmd.Flags().Bool("hyperfleet-api-compression-disabled", false,
+		"Disable gzip compression of API requests and responses. Env: HYPERFLEET_API_COMPRESSION_DISABLED")
+	cmd.Flags().Int("hyperfleet-api-compression-min-bytes", 0,
+		"Gzip API request bodies of at least this size (0 = never). Env: HYPERFLEET_API_COMPRESSION_MIN_BYTES")
 
 	// Broker override flags
 	cmd.Flags().String("broker-subscription-id", "", "Broker subscription ID. Env: HYPERFLEET_BROKER_SUBSCRIPTION_ID")
 	cmd.Flags().String("broker-topic", "", "Broker topic. Env: HYPERFLEET_BROKER_TOPIC")
+	cmd.Flags().String("broker-publish-topic", "",
+		"Broker topic for execution result events (empty = disabled). Env: HYPERFLEET_BROKER_PUBLISH_TOPIC")
+	cmd.Flags().String("broker-dead-letter-topic", "",
+		"Broker topic for events that keep failing (empty = disabled). Env: HYPERFLEET_BROKER_DEAD_LETTER_TOPIC")
+	cmd.Flags().Int("broker-max-delivery-attempts", 0,
+		"Executions of a failing event before it is dead-lettered (0 = 3). Env: HYPERFLEET_BROKER_MAX_DELIVERY_ATTEMPTS")
+	cmd.Flags().Int("broker-concurrency", 0,
+		"Workers executing events, one at a time per cluster (0 = serial). Env: HYPERFLEET_BROKER_CONCURRENCY")
+	cmd.Flags().String("broker-ack-mode", "",
+		"When messages are acked: after_execute or before_execute. Env: HYPERFLEET_BROKER_ACK_MODE")
+	cmd.Flags().String("broker-type", "",
+		"Broker backend: hyperfleet-broker, kafka, nats or http. Env: HYPERFLEET_BROKER_TYPE")
 
 	// Kubernetes override flags
 	cmd.Flags().String("kubernetes-kube-config-path", "",
@@ -872,4 +2451,22 @@ func addOverrideFlags(cmd *cobra.Command) {
 	cmd.Flags().String("kubernetes-api-version", "", "Kubernetes API version. Env: HYPERFLEET_KUBERNETES_API_VERSION")
 	cmd.Flags().Float64("kubernetes-qps", 0, "Kubernetes client QPS rate limit. Env: HYPERFLEET_KUBERNETES_QPS")
 	cmd.Flags().Int("kubernetes-burst", 0, "Kubernetes client burst rate limit. Env: HYPERFLEET_KUBERNETES_BURST")
+
+	// Task config limit override flags
+	cmd.Flags().Int("limits-max-steps", 0,
+		"Maximum params, preconditions, resources and post steps (0 = default). Env: HYPERFLEET_LIMITS_MAX_STEPS")
+	cmd.Flags().Int("limits-max-templates-per-manifest", 0,
+		"Maximum template actions in one manifest (0 = default). Env: HYPERFLEET_LIMITS_MAX_TEMPLATES_PER_MANIFEST")
+	cmd.Flags().Int("limits-max-captures", 0,
+		"Maximum precondition captures (0 = default). Env: HYPERFLEET_LIMITS_MAX_CAPTURES")
+	cmd.Flags().Int("limits-max-variables", 0,
+		"Maximum params, captures and payloads (0 = default). Env: HYPERFLEET_LIMITS_MAX_VARIABLES")
+	cmd.Flags().Int("limits-max-for-each-items", 0,
+		"Maximum items of one for_each step (0 = default). Env: HYPERFLEET_LIMITS_MAX_FOR_EACH_ITEMS")
+
+	// Sharding override flags
+	cmd.Flags().Bool("sharding-enabled", false,
+		"Split events between StatefulSet replicas by resource. Env: HYPERFLEET_SHARDING_ENABLED")
+	cmd.Flags().Int("sharding-count", 0,
+		"Number of shards (0 = StatefulSet replica count). Env: HYPERFLEET_SHARDING_COUNT")
 }
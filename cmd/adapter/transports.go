@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/gitopsclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+)
+
+// The built-in transports. Other backends register their factory the same way, from an
+// init function in their own file, and resources select them with transport.client.
+func init() {
+	transportclient.Register(configloader.TransportClientKubernetes, newKubernetesTransport)
+	transportclient.Register(configloader.TransportClientMaestro, newMaestroTransport)
+	transportclient.Register(configloader.TransportClientGitOps, newGitOpsTransport)
+}
+
+// newKubernetesTransport creates the kubernetes transport from clients.kubernetes
+func newKubernetesTransport(
+	ctx context.Context,
+	config *configloader.Config,
+	opts transportclient.FactoryOptions,
+) (transportclient.TransportClient, error) {
+	return createK8sClient(ctx, config.Clients.Kubernetes, opts.Logger, opts.Recorder)
+}
+
+// newMaestroTransport creates the maestro transport from clients.maestro
+func newMaestroTransport(
+	ctx context.Context,
+	config *configloader.Config,
+	opts transportclient.FactoryOptions,
+) (transportclient.TransportClient, error) {
+	if config.Clients.Maestro == nil {
+		return nil, errors.New("clients.maestro is not configured")
+	}
+	return createMaestroClient(ctx, config.Clients.Maestro, opts.Logger, opts.Recorder)
+}
+
+// newGitOpsTransport creates the gitops transport from clients.gitops
+func newGitOpsTransport(
+	ctx context.Context,
+	config *configloader.Config,
+	opts transportclient.FactoryOptions,
+) (transportclient.TransportClient, error) {
+	cfg := config.Clients.GitOps
+	if cfg == nil {
+		return nil, errors.New("clients.gitops is not configured")
+	}
+	gitopsConfig := gitopsclient.Config{
+		Repository:  cfg.Repository,
+		Branch:      cfg.Branch,
+		TokenPath:   cfg.TokenPath,
+		AuthorName:  cfg.AuthorName,
+		AuthorEmail: cfg.AuthorEmail,
+		WorkDir:     cfg.WorkDir,
+	}
+	if cfg.PullRequest != nil {
+		gitopsConfig.PullRequest = &gitopsclient.PullRequestConfig{
+			Repository:   cfg.PullRequest.Repository,
+			APIURL:       cfg.PullRequest.APIURL,
+			BranchPrefix: cfg.PullRequest.BranchPrefix,
+		}
+	}
+	return gitopsclient.NewClient(ctx, gitopsConfig, opts.Logger)
+}
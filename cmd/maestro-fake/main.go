@@ -0,0 +1,53 @@
+// Command maestro-fake runs the in-memory fake Maestro server for local development, so the
+// adapter's Maestro transport can be exercised without a Maestro deployment.
+//
+//	go run ./cmd/maestro-fake --http-addr 127.0.0.1:8000 --grpc-addr 127.0.0.1:8090
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/maestrofake"
+)
+
+func main() {
+	opts := maestrofake.Options{}
+	flag.StringVar(&opts.HTTPAddr, "http-addr", "127.0.0.1:8000", "listen address of the HTTP API")
+	flag.StringVar(&opts.GRPCAddr, "grpc-addr", "127.0.0.1:8090", "listen address of the CloudEvents gRPC service")
+	flag.DurationVar(&opts.HeartbeatInterval, "heartbeat-interval", maestrofake.DefaultHeartbeatInterval,
+		"how often subscribers receive a heartbeat")
+	var consumers stringList
+	flag.Var(&consumers, "consumer", "consumer to register at startup (repeatable)")
+	flag.Parse()
+
+	srv := maestrofake.New(opts)
+	for _, consumer := range consumers {
+		srv.AddConsumer(consumer)
+	}
+	if err := srv.Start(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start fake Maestro server: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("fake Maestro server listening: http=%s grpc=%s\n", srv.HTTPURL(), srv.GRPCAddr())
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	<-sig
+	srv.Close()
+}
+
+// stringList collects the values of a repeated flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return fmt.Sprint(*l)
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
@@ -0,0 +1,348 @@
+// Package backfill reconciles an existing fleet once. It pages through the resources the
+// HyperFleet API lists, such as clusters, synthesizes the event a change to each would
+// publish, and executes it at a controlled rate. Progress is saved to a checkpoint file
+// after every event, so an interrupted backfill resumes where it stopped.
+package backfill
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+const (
+	// DefaultPageSize is the number of resources requested per page
+	DefaultPageSize = 100
+	// DefaultRate is the number of events executed per second
+	DefaultRate = 2.0
+	// EventSource is the CloudEvent source of backfill events
+	EventSource = "hyperfleet-adapter/backfill"
+)
+
+// Handler executes a synthesized event, and returns an error when the execution failed
+type Handler func(ctx context.Context, evt *event.Event) error
+
+// Options configures a backfill
+type Options struct {
+	// Progress receives a progress bar, nil for none
+	Progress io.Writer
+	// Path is the URL of the collection to page through, e.g. /api/hyperfleet/v1/clusters
+	Path string
+	// Search is the HyperFleet API search expression filtering the collection, empty for all
+	Search string
+	// Kind is the kind of the listed resources, used when an item does not set one
+	Kind string
+	// EventType is the CloudEvent type of the events (default io.hyperfleet.<kind>.updated)
+	EventType string
+	// Checkpoint is the file progress is saved to and resumed from, empty to not resume
+	Checkpoint string
+	// Rate is the number of events executed per second (default DefaultRate)
+	Rate float64
+	// PageSize is the number of resources requested per page (default DefaultPageSize)
+	PageSize int
+}
+
+// Summary is the outcome of a backfill
+type Summary struct {
+	// Failed are the IDs of the resources whose event failed
+	Failed []string
+	// Processed is the number of events executed, including those of a resumed run
+	Processed int
+	// Total is the number of resources the API reported, zero when it did not
+	Total int
+	// Resumed is true when the backfill continued from a checkpoint
+	Resumed bool
+}
+
+// checkpoint is the progress of a backfill, saved after every event. LastID is the last
+// resource handled on Page: a resumed backfill reads Page again and continues after it.
+type checkpoint struct {
+	Path      string   `json:"path"`
+	Search    string   `json:"search,omitempty"`
+	LastID    string   `json:"last_id,omitempty"`
+	Failed    []string `json:"failed,omitempty"`
+	Page      int      `json:"page"`
+	Processed int      `json:"processed"`
+	Total     int      `json:"total,omitempty"`
+}
+
+// listPage is a page of a HyperFleet API collection
+type listPage struct {
+	Items []map[string]interface{} `json:"items"`
+	Total int                      `json:"total"`
+}
+
+// Run executes an event for every resource of the collection, page by page. A failed
+// event is recorded in the summary and the backfill goes on; listing errors and
+// cancellation stop it, with the checkpoint kept for a later run. The checkpoint is
+// removed once every page is done.
+func Run(
+	ctx context.Context,
+	client hyperfleetapi.Client,
+	handler Handler,
+	opts Options,
+	log logger.Logger,
+) (*Summary, error) {
+	if opts.Path == "" {
+		return nil, errors.New("backfill needs the path of the collection to list")
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	if opts.Rate <= 0 {
+		opts.Rate = DefaultRate
+	}
+	if opts.Kind == "" {
+		opts.Kind = "Cluster"
+	}
+	if opts.EventType == "" {
+		opts.EventType = "io.hyperfleet." + strings.ToLower(opts.Kind) + ".updated"
+	}
+
+	cp, resumed, err := loadCheckpoint(opts)
+	if err != nil {
+		return nil, err
+	}
+	summary := &Summary{Resumed: resumed}
+	defer func() {
+		summary.Processed, summary.Failed, summary.Total = cp.Processed, cp.Failed, cp.Total
+	}()
+	if resumed {
+		log.Infof(ctx, "Resuming backfill from %s: page %d, %d events already processed",
+			opts.Checkpoint, cp.Page, cp.Processed)
+	}
+
+	interval := time.Duration(float64(time.Second) / opts.Rate)
+	var lastStart time.Time
+	for {
+		page, err := fetchPage(ctx, client, opts, cp.Page)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list page %d of %s: %w", cp.Page, opts.Path, err)
+		}
+		if page.Total > 0 {
+			cp.Total = page.Total
+		}
+
+		items := page.Items
+		if cp.LastID != "" {
+			for i, item := range items {
+				if itemID(item) == cp.LastID {
+					items = items[i+1:]
+					break
+				}
+			}
+		}
+
+		for _, item := range items {
+			id := itemID(item)
+			if id == "" {
+				log.Warnf(ctx, "Backfill: skipping a %s without an id on page %d", opts.Kind, cp.Page)
+				continue
+			}
+			if wait := interval - time.Since(lastStart); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return summary, ctx.Err()
+				case <-timer.C:
+				}
+			}
+			lastStart = time.Now()
+
+			evt, err := newEvent(item, id, opts)
+			if err != nil {
+				return summary, fmt.Errorf("failed to build the event of %s %s: %w", opts.Kind, id, err)
+			}
+			evtCtx := logger.WithLogFields(ctx, logger.LogFields{"event_id": evt.ID(), "resource_id": id})
+			handleErr := handler(evtCtx, evt)
+			if ctx.Err() != nil {
+				// Interrupted: the event is executed again on resume
+				return summary, ctx.Err()
+			}
+			if handleErr != nil {
+				errCtx := logger.WithErrorField(evtCtx, handleErr)
+				log.Warnf(errCtx, "Backfill: event of %s %s failed", opts.Kind, id)
+				cp.Failed = append(cp.Failed, id)
+			}
+			cp.Processed++
+			cp.LastID = id
+			if err := saveCheckpoint(opts.Checkpoint, cp); err != nil {
+				return summary, err
+			}
+			printProgress(opts.Progress, cp)
+		}
+
+		if len(page.Items) < opts.PageSize || (cp.Total > 0 && cp.Page*opts.PageSize >= cp.Total) {
+			break
+		}
+		cp.Page++
+		cp.LastID = ""
+		if err := saveCheckpoint(opts.Checkpoint, cp); err != nil {
+			return summary, err
+		}
+	}
+
+	if opts.Progress != nil {
+		_, _ = fmt.Fprintln(opts.Progress) //nolint:errcheck // best-effort progress output
+	}
+	if opts.Checkpoint != "" {
+		if err := os.Remove(opts.Checkpoint); err != nil && !os.IsNotExist(err) {
+			return summary, fmt.Errorf("failed to remove checkpoint: %w", err)
+		}
+	}
+	return summary, nil
+}
+
+// fetchPage lists one page of the collection
+func fetchPage(ctx context.Context, client hyperfleetapi.Client, opts Options, page int) (*listPage, error) {
+	query := url.Values{}
+	query.Set("page", strconv.Itoa(page))
+	query.Set("size", strconv.Itoa(opts.PageSize))
+	if opts.Search != "" {
+		query.Set("search", opts.Search)
+	}
+	listURL := opts.Path + "?" + query.Encode()
+	if strings.Contains(opts.Path, "?") {
+		listURL = opts.Path + "&" + query.Encode()
+	}
+
+	resp, err := client.Get(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.IsSuccess() {
+		return nil, fmt.Errorf("GET %s returned %d", listURL, resp.StatusCode)
+	}
+	var result listPage
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse the list response: %w", err)
+	}
+	return &result, nil
+}
+
+// newEvent builds the anemic event of a listed resource: its id, kind, href and generation
+func newEvent(item map[string]interface{}, id string, opts Options) (*event.Event, error) {
+	data := map[string]interface{}{"id": id, "kind": opts.Kind}
+	if kind, ok := item["kind"].(string); ok && kind != "" {
+		data["kind"] = kind
+	}
+	for _, field := range []string{"href", "generation"} {
+		if value, ok := item[field]; ok && value != nil {
+			data[field] = value
+		}
+	}
+
+	evt := event.New()
+	evt.SetID(uuid.NewString())
+	evt.SetTime(time.Now())
+	evt.SetType(opts.EventType)
+	evt.SetSource(EventSource)
+	if err := evt.SetData(event.ApplicationJSON, data); err != nil {
+		return nil, err
+	}
+	return &evt, nil
+}
+
+// itemID returns the id of a listed resource, empty when it has none
+func itemID(item map[string]interface{}) string {
+	switch id := item["id"].(type) {
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// loadCheckpoint returns the checkpoint to resume from, or a new one. A checkpoint of
+// another collection or search is an error rather than silently discarded.
+func loadCheckpoint(opts Options) (*checkpoint, bool, error) {
+	fresh := &checkpoint{Path: opts.Path, Search: opts.Search, Page: 1}
+	if opts.Checkpoint == "" {
+		return fresh, false, nil
+	}
+	data, err := os.ReadFile(opts.Checkpoint)
+	if os.IsNotExist(err) {
+		return fresh, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, false, fmt.Errorf("failed to parse checkpoint %s: %w", opts.Checkpoint, err)
+	}
+	if cp.Path != opts.Path || cp.Search != opts.Search {
+		return nil, false, fmt.Errorf("checkpoint %s is for %s with search %q: remove it to start another backfill",
+			opts.Checkpoint, cp.Path, cp.Search)
+	}
+	if cp.Page < 1 {
+		cp.Page = 1
+	}
+	return &cp, true, nil
+}
+
+// saveCheckpoint writes the checkpoint atomically, so an interruption never leaves a
+// partial file
+func saveCheckpoint(path string, cp *checkpoint) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".backfill-checkpoint-*")
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", err)
+	}
+	if _, err = tmp.Write(data); err != nil {
+		err = errors.Join(err, tmp.Close())
+	} else {
+		err = tmp.Close()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save checkpoint: %w", errors.Join(err, os.Remove(tmp.Name())))
+	}
+	return nil
+}
+
+// progressWidth is the number of cells of the progress bar
+const progressWidth = 30
+
+// printProgress redraws the progress bar on w
+func printProgress(w io.Writer, cp *checkpoint) {
+	if w == nil {
+		return
+	}
+	failed := ""
+	if len(cp.Failed) > 0 {
+		failed = fmt.Sprintf(" (%d failed)", len(cp.Failed))
+	}
+	if cp.Total <= 0 {
+		_, _ = fmt.Fprintf(w, "\r%d processed%s", cp.Processed, failed) //nolint:errcheck // best-effort progress output
+		return
+	}
+	done := min(cp.Processed*progressWidth/cp.Total, progressWidth)
+	_, _ = fmt.Fprintf(w, "\r[%s%s] %d/%d%s", //nolint:errcheck // best-effort progress output
+		strings.Repeat("#", done), strings.Repeat("-", progressWidth-done), cp.Processed, cp.Total, failed)
+}
@@ -0,0 +1,149 @@
+package backfill
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// newClusterAPI serves n clusters named c1..cn, paged like the HyperFleet API, and
+// records the search expressions it receives
+func newClusterAPI(t *testing.T, n int, searches *[]string) hyperfleetapi.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/hyperfleet/v1/clusters", r.URL.Path)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		if searches != nil {
+			*searches = append(*searches, r.URL.Query().Get("search"))
+		}
+		items := []map[string]interface{}{}
+		for i := (page-1)*size + 1; i <= min(page*size, n); i++ {
+			items = append(items, map[string]interface{}{
+				"id":         fmt.Sprintf("c%d", i),
+				"kind":       "Cluster",
+				"href":       fmt.Sprintf("/api/hyperfleet/v1/clusters/c%d", i),
+				"generation": i,
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"kind": "ClusterList", "page": page, "size": len(items), "total": n, "items": items,
+		})
+	}))
+	t.Cleanup(server.Close)
+	client, err := hyperfleetapi.NewClient(logger.NewTestLogger(), hyperfleetapi.WithBaseURL(server.URL))
+	require.NoError(t, err)
+	return client
+}
+
+func testOptions(checkpoint string) Options {
+	return Options{
+		Path:       "/api/hyperfleet/v1/clusters",
+		Checkpoint: checkpoint,
+		PageSize:   2,
+		Rate:       1000,
+	}
+}
+
+func TestRun(t *testing.T) {
+	var searches []string
+	client := newClusterAPI(t, 5, &searches)
+	var events []*event.Event
+	handler := func(_ context.Context, evt *event.Event) error {
+		events = append(events, evt)
+		var data map[string]interface{}
+		require.NoError(t, evt.DataAs(&data))
+		if data["id"] == "c3" {
+			return errors.New("execution failed")
+		}
+		return nil
+	}
+
+	opts := testOptions(filepath.Join(t.TempDir(), "checkpoint.json"))
+	opts.Search = "region='us-east-1'"
+	var progress bytes.Buffer
+	opts.Progress = &progress
+	summary, err := Run(context.Background(), client, handler, opts, logger.NewTestLogger())
+	require.NoError(t, err)
+
+	assert.Equal(t, 5, summary.Processed)
+	assert.Equal(t, 5, summary.Total)
+	assert.Equal(t, []string{"c3"}, summary.Failed, "a failed event should not stop the backfill")
+	assert.Equal(t, []string{"region='us-east-1'", "region='us-east-1'", "region='us-east-1'"}, searches)
+	require.Len(t, events, 5)
+
+	evt := events[0]
+	assert.Equal(t, "io.hyperfleet.cluster.updated", evt.Type())
+	assert.Equal(t, EventSource, evt.Source())
+	var data map[string]interface{}
+	require.NoError(t, evt.DataAs(&data))
+	assert.Equal(t, map[string]interface{}{
+		"id": "c1", "kind": "Cluster", "href": "/api/hyperfleet/v1/clusters/c1", "generation": float64(1),
+	}, data)
+
+	assert.Contains(t, progress.String(), "5/5 (1 failed)")
+	assert.NoFileExists(t, opts.Checkpoint, "a finished backfill should remove its checkpoint")
+}
+
+func TestRun_ResumesFromCheckpoint(t *testing.T) {
+	client := newClusterAPI(t, 5, nil)
+	opts := testOptions(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	// The first run is interrupted while executing c4
+	ctx, cancel := context.WithCancel(context.Background())
+	var first []string
+	_, err := Run(ctx, client, func(_ context.Context, evt *event.Event) error {
+		var data map[string]interface{}
+		require.NoError(t, evt.DataAs(&data))
+		if data["id"] == "c4" {
+			cancel()
+			return ctx.Err()
+		}
+		first = append(first, data["id"].(string))
+		return nil
+	}, opts, logger.NewTestLogger())
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"c1", "c2", "c3"}, first)
+	assert.FileExists(t, opts.Checkpoint)
+
+	var second []string
+	summary, err := Run(context.Background(), client, func(_ context.Context, evt *event.Event) error {
+		var data map[string]interface{}
+		require.NoError(t, evt.DataAs(&data))
+		second = append(second, data["id"].(string))
+		return nil
+	}, opts, logger.NewTestLogger())
+	require.NoError(t, err)
+	assert.True(t, summary.Resumed)
+	assert.Equal(t, []string{"c4", "c5"}, second, "the resumed run should continue after the last handled cluster")
+	assert.Equal(t, 5, summary.Processed)
+}
+
+func TestRun_CheckpointOfAnotherBackfill(t *testing.T) {
+	opts := testOptions(filepath.Join(t.TempDir(), "checkpoint.json"))
+	data, err := json.Marshal(checkpoint{Path: opts.Path, Search: "region='eu-west-1'", Page: 2})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(opts.Checkpoint, data, 0o600))
+
+	_, err = Run(context.Background(), newClusterAPI(t, 1, nil), func(context.Context, *event.Event) error {
+		return nil
+	}, opts, logger.NewTestLogger())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "remove it to start another backfill")
+}
@@ -0,0 +1,19 @@
+package brokerutil
+
+import (
+	"context"
+
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// SendError logs err as an error of the named subscriber and reports it on errs without
+// blocking; errors are dropped once the channel is full
+func SendError(log logger.Logger, name string, errs chan<- *broker.SubscriberError, err *broker.SubscriberError) {
+	log.Warnf(context.Background(), "%s subscriber: %v", name, err)
+	select {
+	case errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,80 @@
+// Package brokerutil holds what the broker subscribers share: decoding CloudEvents from
+// message headers and reporting subscriber errors.
+package brokerutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+const (
+	// HeaderContentType is the header with the content type of a message
+	HeaderContentType = "content-type"
+	// StructuredContentType is the content type of structured-mode messages
+	StructuredContentType = "application/cloudevents"
+)
+
+// DecodeEvent converts the headers and data of a message to a CloudEvent, following the
+// CloudEvents protocol bindings of Kafka and NATS, which differ in the prefix of the
+// attribute headers. Header names must be lower case. A binary-mode message carries the
+// attributes in prefixed headers and the event data in data; a structured-mode message
+// is a JSON CloudEvent. A message with neither a specversion header nor a content type is
+// read as structured.
+func DecodeEvent(headers map[string]string, prefix string, data []byte) (*event.Event, error) {
+	contentType := headers[HeaderContentType]
+
+	if _, binary := headers[prefix+"specversion"]; !binary || strings.HasPrefix(contentType, StructuredContentType) {
+		evt := event.New()
+		if err := json.Unmarshal(data, &evt); err != nil {
+			return nil, fmt.Errorf("invalid structured CloudEvent: %w", err)
+		}
+		if err := evt.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid structured CloudEvent: %w", err)
+		}
+		return &evt, nil
+	}
+
+	evt := event.New(headers[prefix+"specversion"])
+	for key, value := range headers {
+		name, ok := strings.CutPrefix(key, prefix)
+		if !ok {
+			continue
+		}
+		switch name {
+		case "specversion":
+		case "id":
+			evt.SetID(value)
+		case "source":
+			evt.SetSource(value)
+		case "type":
+			evt.SetType(value)
+		case "subject":
+			evt.SetSubject(value)
+		case "dataschema":
+			evt.SetDataSchema(value)
+		case "time":
+			t, err := time.Parse(time.RFC3339Nano, value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %stime header %q: %w", prefix, value, err)
+			}
+			evt.SetTime(t)
+		default:
+			evt.SetExtension(name, value)
+		}
+	}
+	if len(data) > 0 {
+		if err := evt.SetData(contentType, data); err != nil {
+			return nil, fmt.Errorf("invalid event data: %w", err)
+		}
+	} else if contentType != "" {
+		evt.SetDataContentType(contentType)
+	}
+	if err := evt.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid binary CloudEvent: %w", err)
+	}
+	return &evt, nil
+}
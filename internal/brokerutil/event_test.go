@@ -0,0 +1,56 @@
+package brokerutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	binary := map[string]string{
+		"ce_specversion": "1.0",
+		"ce_id":          "evt-1",
+		"ce_source":      "/hyperfleet",
+		"ce_type":        "io.hyperfleet.cluster.updated",
+		"ce_time":        "2026-01-02T03:04:05Z",
+		"ce_region":      "us-east-1",
+		"content-type":   "application/json",
+	}
+	evt, err := DecodeEvent(binary, "ce_", []byte(`{"id":"cluster-1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "evt-1", evt.ID())
+	assert.Equal(t, "io.hyperfleet.cluster.updated", evt.Type())
+	assert.Equal(t, "application/json", evt.DataContentType())
+	assert.Equal(t, "us-east-1", evt.Extensions()["region"])
+	assert.Equal(t, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), evt.Time().UTC())
+	assert.JSONEq(t, `{"id":"cluster-1"}`, string(evt.Data()))
+
+	_, err = DecodeEvent(binary, "ce-", []byte(`{"id":"cluster-1"}`))
+	assert.Error(t, err, "headers with another prefix make a structured message")
+
+	structured := []byte(`{"specversion":"1.0","id":"evt-2","source":"/hyperfleet",` +
+		`"type":"io.hyperfleet.cluster.created","datacontenttype":"application/json","data":{"id":"cluster-2"}}`)
+	evt, err = DecodeEvent(map[string]string{"content-type": "application/cloudevents+json"}, "ce-", structured)
+	require.NoError(t, err)
+	assert.Equal(t, "evt-2", evt.ID())
+	assert.JSONEq(t, `{"id":"cluster-2"}`, string(evt.Data()))
+
+	_, err = DecodeEvent(nil, "ce-", structured)
+	assert.NoError(t, err, "a message without headers should be read as structured")
+
+	for name, data := range map[string][]byte{
+		"not JSON":   []byte("hello"),
+		"missing id": []byte(`{"specversion":"1.0","source":"/s","type":"t"}`),
+	} {
+		_, err = DecodeEvent(nil, "ce-", data)
+		assert.Error(t, err, name)
+	}
+	_, err = DecodeEvent(map[string]string{"ce-specversion": "1.0"}, "ce-", nil)
+	assert.Error(t, err, "a binary message without an id should be rejected")
+
+	binary["ce_time"] = "yesterday"
+	_, err = DecodeEvent(binary, "ce_", nil)
+	assert.ErrorContains(t, err, `invalid ce_time header "yesterday"`)
+}
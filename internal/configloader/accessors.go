@@ -28,6 +28,7 @@ func BuiltinVariables() []string {
 // - Parameters from params
 // - Captured variables from preconditions
 // - Post payloads
+// - The platform param, when platform is configured
 // - Resource aliases (resources.<name>)
 func (c *Config) GetDefinedVariables() map[string]bool {
 	vars := make(map[string]bool)
@@ -66,6 +67,11 @@ func (c *Config) GetDefinedVariables() map[string]bool {
 		}
 	}
 
+	// Resolved spoke platform
+	if c.Platform != nil {
+		vars[FieldPlatform] = true
+	}
+
 	// Resource aliases
 	for _, r := range c.Resources {
 		if r.Name != "" {
@@ -179,13 +185,62 @@ func (r *Resource) GetTransportClient() string {
 	return r.Transport.Client
 }
 
+// IsKubernetesTransport returns true if this resource uses the kubernetes transport client
+func (r *Resource) IsKubernetesTransport() bool {
+	return r.GetTransportClient() == TransportClientKubernetes
+}
+
 // IsMaestroTransport returns true if this resource uses the maestro transport client
 func (r *Resource) IsMaestroTransport() bool {
 	return r.GetTransportClient() == TransportClientMaestro
 }
 
-// HasManifestRef returns true if the manifest uses a ref (single file reference)
+// UsesServerSideApply returns true if the resource is written with server-side apply
+func (r *Resource) UsesServerSideApply() bool {
+	return r != nil && r.ApplyStrategy == ApplyStrategyServerSideApply
+}
+
+// GetDriftPolicy returns the drift policy of the resource, "ignore" when unset
+func (r *Resource) GetDriftPolicy() string {
+	if r == nil || r.DriftPolicy == "" {
+		return DriftPolicyIgnore
+	}
+	return r.DriftPolicy
+}
+
+// EnsuresNamespace returns true if missing namespaces of the resource's manifests are
+// created before it is applied: with ensure_namespace, or with manageNamespaces from
+// clients.kubernetes.manage_namespaces. Only kubernetes transport resources do.
+func (r *Resource) EnsuresNamespace(manageNamespaces bool) bool {
+	return r != nil && r.IsKubernetesTransport() && (r.EnsureNamespace || manageNamespaces)
+}
+
+// ManifestOrdering returns the ManifestWork ordering of a maestro resource, or nil
+func (r *Resource) ManifestOrdering() *ManifestOrderingConfig {
+	if !r.IsMaestroTransport() || r.Transport.Maestro == nil {
+		return nil
+	}
+	return r.Transport.Maestro.Ordering
+}
+
+// SplitsManifestWork returns true if the ManifestWork is delivered as one work per tier
+func (r *Resource) SplitsManifestWork() bool {
+	ordering := r.ManifestOrdering()
+	return ordering != nil && ordering.Split
+}
+
+// HasManifestSource returns true if the resource has its manifest inline, in a file or
+// from a Helm chart
+func (r *Resource) HasManifestSource() bool {
+	return r != nil && (r.Manifest != nil || r.ManifestRef != "" || r.Helm != nil)
+}
+
+// HasManifestRef returns true if the manifest is loaded from a file, with manifest_ref
+// or manifest.ref
 func (r *Resource) HasManifestRef() bool {
+	if r != nil && r.ManifestRef != "" {
+		return true
+	}
 	if r == nil || r.Manifest == nil {
 		return false
 	}
@@ -197,8 +252,11 @@ func (r *Resource) HasManifestRef() bool {
 	return hasRef
 }
 
-// GetManifestRef returns the ref path if set, empty string otherwise
+// GetManifestRef returns the manifest_ref or manifest.ref path if set, empty string otherwise
 func (r *Resource) GetManifestRef() string {
+	if r != nil && r.ManifestRef != "" {
+		return r.ManifestRef
+	}
 	if r == nil || r.Manifest == nil {
 		return ""
 	}
@@ -258,3 +316,49 @@ func normalizeToStringKeyMap(v interface{}) map[string]interface{} {
 		return nil
 	}
 }
+
+// HasByName reports whether discovery by name is configured
+func (d *DiscoveryConfig) HasByName() bool {
+	return d != nil && d.ByName != ""
+}
+
+// HasBySelectors reports whether discovery by label selector is configured. An empty
+// by_selectors block selects nothing and does not count.
+func (d *DiscoveryConfig) HasBySelectors() bool {
+	return d != nil && d.BySelectors != nil && len(d.BySelectors.LabelSelector) > 0
+}
+
+// Methods returns the configured discovery methods (DiscoveryByName, DiscoveryBySelectors)
+// in lookup order. Methods listed in Order come first; configured methods missing from
+// Order follow in the default order.
+func (d *DiscoveryConfig) Methods() []string {
+	if d == nil {
+		return nil
+	}
+	configured := map[string]bool{
+		DiscoveryByName:      d.HasByName(),
+		DiscoveryBySelectors: d.HasBySelectors(),
+	}
+	methods := make([]string, 0, 2)
+	for _, m := range append(append([]string{}, d.Order...), DiscoveryByName, DiscoveryBySelectors) {
+		if configured[m] {
+			methods = append(methods, m)
+			configured[m] = false
+		}
+	}
+	return methods
+}
+
+// UsesFailOnMulti reports whether selector lookups matching several resources must fail
+func (d *DiscoveryConfig) UsesFailOnMulti() bool {
+	return d != nil && d.MatchPolicy == MatchPolicyFailOnMulti
+}
+
+// OnErrorPolicy returns the failure policy of a step with the given on_error: onError if
+// set, else the task's failure_policy. "" means the default of the step kind.
+func (c *Config) OnErrorPolicy(onError string) string {
+	if onError != "" || c == nil {
+		return onError
+	}
+	return c.FailurePolicy
+}
@@ -6,15 +6,29 @@ package configloader
 
 // Field names
 const (
-	FieldAdapter       = "adapter"
-	FieldHyperfleetAPI = "hyperfleet_api"
-	FieldKubernetes    = "kubernetes"
-	FieldParams        = "params"
-	FieldPreconditions = "preconditions"
-	FieldResources     = "resources"
-	FieldPost          = "post"
-	FieldEnv           = "env"
-	FieldEvent         = "event"
+	FieldAdapter          = "adapter"
+	FieldHyperfleetAPI    = "hyperfleet_api"
+	FieldKubernetes       = "kubernetes"
+	FieldParams           = "params"
+	FieldPreconditions    = "preconditions"
+	FieldResources        = "resources"
+	FieldPost             = "post"
+	FieldPrune            = "prune"
+	FieldWait             = "wait"
+	FieldPlatform         = "platform"
+	FieldEnv              = "env"
+	FieldEvent            = "event"
+	FieldEventOverlays    = "event_overlays"
+	FieldExecutionTimeout = "execution_timeout"
+	FieldFailurePolicy    = "failure_policy"
+)
+
+// Step file include field names: a step list entry with include pulls in the steps of a
+// step file, with the values of the inputs of the file in with
+const (
+	FieldInclude = "include"
+	FieldWith    = "with"
+	FieldInputs  = "inputs"
 )
 
 // Adapter field names
@@ -45,6 +59,16 @@ const (
 	FieldCapture    = "capture"
 	FieldConditions = "conditions"
 	FieldExpression = "expression"
+
+	// FieldForEach is also a resource field
+	FieldForEach = "for_each"
+)
+
+// Variables of the templates and expressions of a for_each step: the list item of the
+// current iteration and its position in the list
+const (
+	ForEachItemVariable  = "item"
+	ForEachIndexVariable = "index"
 )
 
 // API call field names
@@ -54,6 +78,50 @@ const (
 	FieldTimeout = "timeout"
 	FieldHeaders = "headers"
 	FieldBody    = "body"
+	FieldPatch   = "patch"
+
+	FieldBodyEncoding = "body_encoding"
+	FieldFiles        = "files"
+
+	FieldResponseFields  = "response_fields"
+	FieldKeepRawResponse = "keep_raw_response"
+
+	// FieldTransform is also a resource and capture field
+	FieldTransform = "transform"
+)
+
+// api_call body encodings
+const (
+	BodyEncodingJSON      = "json"
+	BodyEncodingForm      = "form"
+	BodyEncodingMultipart = "multipart"
+)
+
+// JSON Patch operation field names
+const (
+	FieldPatchPath       = "path"
+	FieldPatchFrom       = "from"
+	FieldValueExpression = "value_expression"
+)
+
+// Wait step field names
+const (
+	FieldResource     = "resource"
+	FieldCondition    = "condition"
+	FieldInterval     = "interval"
+	FieldManifestWork = "manifest_work"
+)
+
+// Platform field names
+const (
+	FieldOS           = "os"
+	FieldArchitecture = "architecture"
+	FieldMaps         = "maps"
+)
+
+// Event overlay field names
+const (
+	FieldAttribute = "attribute"
 )
 
 // Header field names
@@ -75,21 +143,85 @@ const (
 	FieldClient        = "client"
 	FieldMaestro       = "maestro"
 	FieldTargetCluster = "target_cluster"
+	FieldPlacement     = "placement"
+	FieldLabels        = "labels"
+	FieldAnnotations   = "annotations"
+	FieldOrdering      = "ordering"
+	FieldTiers         = "tiers"
+	FieldKinds         = "kinds"
+	FieldFeedbackRules = "feedback_rules"
+	FieldJSONPaths     = "json_paths"
+	FieldPath          = "path"
+	FieldGitOps        = "gitops"
+	FieldCommitMessage = "commit_message"
 )
 
 // Transport client types
 const (
 	TransportClientKubernetes = "kubernetes"
 	TransportClientMaestro    = "maestro"
+	TransportClientGitOps     = "gitops"
+)
+
+// Post-action phases
+const (
+	PostActionPhasePostActions = "post_actions"
+	PostActionPhaseReporting   = "reporting"
+)
+
+// Resource apply strategies
+const (
+	ApplyStrategyUpdate          = "update"
+	ApplyStrategyServerSideApply = "server_side_apply"
+)
+
+// Capture transforms, applied to a captured value before it is stored. A transform starting
+// with CaptureTransformTemplatePrefix is a Go template rendered with the value as .value.
+const (
+	CaptureTransformLower          = "lower"
+	CaptureTransformTrim           = "trim"
+	CaptureTransformToInt          = "toInt"
+	CaptureTransformToBool         = "toBool"
+	CaptureTransformTemplatePrefix = "template:"
+)
+
+// Step failure policies, set per step with on_error or for the task with failure_policy
+const (
+	OnErrorAbort    = "abort"
+	OnErrorContinue = "continue"
+)
+
+// Resource drift policies
+const (
+	DriftPolicyIgnore    = "ignore"
+	DriftPolicyReport    = "report"
+	DriftPolicyReconcile = "reconcile"
 )
 
 // Resource field names
 const (
 	FieldManifest          = "manifest"
+	FieldManifestRef       = "manifest_ref"
+	FieldManifestDocuments = "manifest_documents"
 	FieldRecreateOnChange  = "recreate_on_change"
+	FieldApplyStrategy     = "apply_strategy"
+	FieldEnsureNamespace   = "ensure_namespace"
+	FieldDriftPolicy       = "drift_policy"
+	FieldFieldManager      = "field_manager"
 	FieldDiscovery         = "discovery"
 	FieldNestedDiscoveries = "nested_discoveries"
 	FieldLifecycle         = "lifecycle"
+	FieldParallel          = "parallel"
+	FieldDependsOn         = "depends_on"
+	FieldRetry             = "retry"
+	FieldHelm              = "helm"
+)
+
+// Helm field names
+const (
+	FieldChart            = "chart"
+	FieldReleaseName      = "release_name"
+	FieldManifestWorkName = "manifest_work_name"
 )
 
 // Lifecycle field names
@@ -98,6 +230,8 @@ const (
 	FieldLifecycleDelete            = "delete"
 	FieldLifecyclePropagationPolicy = "propagationPolicy"
 	FieldLifecycleWhen              = "when"
+	FieldLifecycleFinalizer         = "finalizer"
+	FieldLifecycleTeardown          = "teardown"
 )
 
 // Manifest reference field names
@@ -110,6 +244,21 @@ const (
 	FieldNamespace   = "namespace"
 	FieldByName      = "by_name"
 	FieldBySelectors = "by_selectors"
+	FieldOrder       = "order"
+	FieldMatchPolicy = "match_policy"
+	FieldRequireAll  = "require_all"
+)
+
+// Discovery methods, as listed in discovery.order
+const (
+	DiscoveryByName      = FieldByName
+	DiscoveryBySelectors = FieldBySelectors
+)
+
+// Discovery match policies for selector lookups that match more than one resource
+const (
+	MatchPolicyNewestGeneration = "newest_generation" // pick the highest generation (default)
+	MatchPolicyFailOnMulti      = "fail_on_multi"     // fail discovery
 )
 
 // Selector field names
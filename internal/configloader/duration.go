@@ -0,0 +1,15 @@
+package configloader
+
+import (
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// Duration is a config duration written as a Go duration string such as "30s". It is
+// utils.Duration, which the client packages below configloader use for their own
+// config fields.
+type Duration = utils.Duration
+
+// ParseDuration parses a Go duration string, rejecting a number without a unit
+func ParseDuration(s string) (Duration, error) {
+	return utils.ParseDuration(s)
+}
@@ -0,0 +1,173 @@
+package configloader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+		want    Duration
+	}{
+		{name: "seconds", input: "30s", want: Duration(30 * time.Second)},
+		{name: "compound", input: "1h30m", want: Duration(90 * time.Minute)},
+		{name: "zero without unit", input: "0", want: 0},
+		{name: "surrounding whitespace", input: " 5m ", want: Duration(5 * time.Minute)},
+		{name: "bare number", input: "30", wantErr: `duration "30" has no unit, write it with one such as "30s" or "30ms"`},
+		{name: "bare float", input: "1.5", wantErr: "has no unit"},
+		{name: "unknown unit", input: "5 minutes", wantErr: `invalid duration "5 minutes"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDuration(tt.input)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDurationOrDefault(t *testing.T) {
+	assert.Equal(t, 10*time.Second, Duration(0).OrDefault(10*time.Second))
+	assert.Equal(t, time.Second, Duration(time.Second).OrDefault(10*time.Second))
+}
+
+func TestDurationYAML(t *testing.T) {
+	var step WaitStep
+	require.NoError(t, yaml.Unmarshal([]byte("timeout: 2m\ninterval: 10s\n"), &step))
+	assert.Equal(t, Duration(2*time.Minute), step.Timeout)
+	assert.Equal(t, Duration(10*time.Second), step.Interval)
+
+	out, err := yaml.Marshal(step)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "timeout: 2m0s")
+
+	err = yaml.Unmarshal([]byte("name: ready\ntimeout: 30\n"), &step)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `line 2: duration "30" has no unit`)
+}
+
+func TestDurationJSON(t *testing.T) {
+	var d Duration
+	require.NoError(t, json.Unmarshal([]byte(`"500ms"`), &d))
+	assert.Equal(t, Duration(500*time.Millisecond), d)
+
+	out, err := json.Marshal(Duration(time.Minute))
+	require.NoError(t, err)
+	assert.JSONEq(t, `"1m0s"`, string(out))
+
+	err = json.Unmarshal([]byte(`500`), &d)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "has no unit")
+}
+
+func TestDurationText(t *testing.T) {
+	var d Duration
+	require.NoError(t, d.UnmarshalText([]byte("15s")))
+	assert.Equal(t, Duration(15*time.Second), d)
+	require.Error(t, d.UnmarshalText([]byte("15")))
+}
+
+func TestLoadAdapterConfigDurations(t *testing.T) {
+	const adapterYAML = `
+adapter:
+  name: test-adapter
+  version: "0.1.0"
+clients:
+  hyperfleet_api:
+    base_url: "https://test.example.com"
+    timeout: 2s
+  kubernetes:
+    api_version: "v1"
+  maestro:
+    grpc_server_address: "maestro-grpc:8090"
+    http_server_address: "https://maestro:8000"
+    source_id: "test"
+    timeout: 15s
+`
+
+	t.Run("durations with units", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "adapter-config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(adapterYAML), 0644))
+
+		_, config, err := loadAdapterConfigWithViper(path, nil)
+		require.NoError(t, err)
+		require.NotNil(t, config.Clients.Maestro)
+		assert.Equal(t, Duration(15*time.Second), config.Clients.Maestro.Timeout)
+	})
+
+	t.Run("API client duration without unit", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "adapter-config.yaml")
+		withBareDelay := strings.Replace(adapterYAML, "timeout: 2s", "timeout: 2s\n    base_delay: 5", 1)
+		require.NoError(t, os.WriteFile(path, []byte(withBareDelay), 0644))
+
+		_, _, err := loadAdapterConfigWithViper(path, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `line 9: duration "5" has no unit`)
+	})
+
+	t.Run("API client durations with units", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "adapter-config.yaml")
+		withDelays := strings.Replace(adapterYAML, "timeout: 2s", "timeout: 2s\n    base_delay: 500ms\n    max_delay: 0", 1)
+		require.NoError(t, os.WriteFile(path, []byte(withDelays), 0644))
+
+		_, config, err := loadAdapterConfigWithViper(path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, Duration(2*time.Second), config.Clients.HyperfleetAPI.Timeout)
+		assert.Equal(t, Duration(500*time.Millisecond), config.Clients.HyperfleetAPI.BaseDelay)
+		assert.Zero(t, config.Clients.HyperfleetAPI.MaxDelay)
+	})
+
+	t.Run("adapter durations without unit", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			section string
+			client  string
+		}{
+			{name: "deduplication ttl", section: "deduplication:\n  ttl: 3600\n"},
+			{name: "sli target", section: "sli:\n  target: 300\n"},
+			{name: "sli window", section: "sli:\n  window: 3600\n"},
+			{name: "log sampling window", section: "log:\n  sampling:\n    window: 60\n"},
+			{name: "sharding refresh_interval", section: "sharding:\n  refresh_interval: 30\n"},
+			{name: "notifications min_interval", section: "notifications:\n  min_interval: 900\n"},
+			{name: "notifications timeout", section: "notifications:\n  timeout: 10\n"},
+			{name: "nats ack_wait", client: "  broker:\n    nats:\n      ack_wait: 30\n"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "adapter-config.yaml")
+				config := strings.Replace(adapterYAML, "clients:\n", "clients:\n"+tt.client, 1) + tt.section
+				require.NoError(t, os.WriteFile(path, []byte(config), 0644))
+
+				_, _, err := loadAdapterConfigWithViper(path, nil)
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "has no unit")
+			})
+		}
+	})
+
+	t.Run("environment override without unit", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "adapter-config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(adapterYAML), 0644))
+		t.Setenv("HYPERFLEET_MAESTRO_TIMEOUT", "15")
+
+		_, _, err := loadAdapterConfigWithViper(path, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "has no unit")
+	})
+}
@@ -0,0 +1,508 @@
+package configloader
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// Effect operations reported by AnalyzeEffects
+const (
+	EffectApply    = "apply"
+	EffectRecreate = "recreate"
+	EffectDelete   = "delete"
+	// EffectFinalizer adds the lifecycle finalizer on apply and removes it after teardown
+	EffectFinalizer = "finalizer"
+)
+
+// Effects is the set of mutating operations a task config can perform.
+// Values are reported as written in the config; templates are not rendered.
+type Effects struct {
+	APICalls   []APICallEffect  `json:"api_calls" yaml:"api_calls"`
+	Kubernetes []ResourceEffect `json:"kubernetes" yaml:"kubernetes"`
+	Maestro    []MaestroEffect  `json:"maestro" yaml:"maestro"`
+	Git        []GitEffect      `json:"git" yaml:"git"`
+	Prune      []PruneEffect    `json:"prune" yaml:"prune"`
+}
+
+// APICallEffect is a non-GET HyperFleet API call made by a precondition, a teardown step
+// or a post-action
+type APICallEffect struct {
+	Source string `json:"source" yaml:"source"` // e.g. post_actions[reportStatus]
+	Method string `json:"method" yaml:"method"`
+	// FanOut is set when a for_each precondition makes the call once per item
+	FanOut *FanOutEffect `json:"fan_out,omitempty" yaml:"fan_out,omitempty"`
+	URL    string        `json:"url" yaml:"url"`
+}
+
+// FanOutEffect flags an effect repeated once per item of a for_each list. The number of
+// items is only known at runtime; MaxItems is limits.max_for_each_items, or 0 when the
+// limit is disabled and the fan-out is unbounded.
+type FanOutEffect struct {
+	ForEach  string `json:"for_each" yaml:"for_each"`
+	MaxItems int    `json:"max_items,omitempty" yaml:"max_items,omitempty"`
+}
+
+// ResourceEffect is a Kubernetes object written by a resource
+type ResourceEffect struct {
+	Resource   string `json:"resource,omitempty" yaml:"resource,omitempty"`
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"` // empty for cluster-scoped objects
+	Name       string `json:"name" yaml:"name"`
+	// Finalizer is the finalizer the adapter adds and removes, with the finalizer operation
+	Finalizer string `json:"finalizer,omitempty" yaml:"finalizer,omitempty"`
+	// FanOut is set when a for_each resource writes the object once per item
+	FanOut     *FanOutEffect `json:"fan_out,omitempty" yaml:"fan_out,omitempty"`
+	Operations []string      `json:"operations,omitempty" yaml:"operations,omitempty"`
+}
+
+// GitEffect is the manifests of a gitops resource, committed to a Git repository for Argo CD
+// or Flux to apply. With pull requests, the commits go to a branch per path and a pull
+// request is opened against Branch.
+type GitEffect struct {
+	Resource    string           `json:"resource" yaml:"resource"`
+	Repository  string           `json:"repository" yaml:"repository"`
+	Branch      string           `json:"branch" yaml:"branch"`
+	PullRequest string           `json:"pull_request,omitempty" yaml:"pull_request,omitempty"` // owner/name
+	Path        string           `json:"path" yaml:"path"`
+	Objects     []ResourceEffect `json:"objects,omitempty" yaml:"objects,omitempty"`
+	FanOut      *FanOutEffect    `json:"fan_out,omitempty" yaml:"fan_out,omitempty"`
+	Operations  []string         `json:"operations" yaml:"operations"`
+}
+
+// gitOpsDefaultBranch is the branch the gitops client commits to when none is configured
+const gitOpsDefaultBranch = "main"
+
+// PruneEffect is the set of Kubernetes objects a prune step can delete: those of its
+// kind matching its label selector that the resources phase did not apply
+type PruneEffect struct {
+	Step       string `json:"step" yaml:"step"`
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+	Namespace  string `json:"namespace,omitempty" yaml:"namespace,omitempty"` // empty for all namespaces
+	Selector   string `json:"selector" yaml:"selector"`
+}
+
+// MaestroEffect is a ManifestWork delivered to a Maestro consumer
+type MaestroEffect struct {
+	Resource      string           `json:"resource" yaml:"resource"`
+	TargetCluster string           `json:"target_cluster" yaml:"target_cluster"`
+	ManifestWork  string           `json:"manifest_work" yaml:"manifest_work"`
+	Workload      []ResourceEffect `json:"workload,omitempty" yaml:"workload,omitempty"`
+	FanOut        *FanOutEffect    `json:"fan_out,omitempty" yaml:"fan_out,omitempty"`
+	Operations    []string         `json:"operations" yaml:"operations"`
+}
+
+// AnalyzeEffects statically lists the API endpoints, Kubernetes objects, Maestro
+// consumers, Git repositories and prune selectors a config can mutate. Manifests loaded
+// from manifest_ref and manifest.ref files are parsed best-effort, one object per YAML
+// document; objects whose manifest cannot be parsed are reported with empty kind.
+// Effects of for_each steps carry a FanOut.
+func AnalyzeEffects(config *Config) *Effects {
+	effects := &Effects{
+		APICalls:   []APICallEffect{},
+		Kubernetes: []ResourceEffect{},
+		Maestro:    []MaestroEffect{},
+		Git:        []GitEffect{},
+		Prune:      []PruneEffect{},
+	}
+	if config == nil {
+		return effects
+	}
+
+	for _, p := range config.Preconditions {
+		effects.addAPICall(fmt.Sprintf("%s[%s]", FieldPreconditions, p.Name), p.APICall, forEachFanOut(config, p.ForEach))
+	}
+	for i := range config.Resources {
+		r := &config.Resources[i]
+		if r.Lifecycle == nil || r.Lifecycle.Delete == nil {
+			continue
+		}
+		for _, step := range r.Lifecycle.Delete.Teardown {
+			effects.addAPICall(fmt.Sprintf("resources[%s].teardown[%s]", r.Name, step.Name), step.APICall,
+				forEachFanOut(config, r.ForEach))
+		}
+	}
+	if config.Post != nil {
+		for _, pa := range config.Post.PostActions {
+			effects.addAPICall(fmt.Sprintf("post_actions[%s]", pa.Name), pa.APICall, nil)
+		}
+	}
+
+	for i := range config.Resources {
+		r := &config.Resources[i]
+		k, m, g := len(effects.Kubernetes), len(effects.Maestro), len(effects.Git)
+		effects.addResource(config, r)
+		if fanOut := forEachFanOut(config, r.ForEach); fanOut != nil {
+			for j := range effects.Kubernetes[k:] {
+				effects.Kubernetes[k+j].FanOut = fanOut
+			}
+			for j := range effects.Maestro[m:] {
+				effects.Maestro[m+j].FanOut = fanOut
+			}
+			for j := range effects.Git[g:] {
+				effects.Git[g+j].FanOut = fanOut
+			}
+		}
+	}
+	effects.addNamespaces(config)
+
+	for _, step := range config.Prune {
+		effects.Prune = append(effects.Prune, PruneEffect{
+			Step:       step.Name,
+			APIVersion: step.APIVersion,
+			Kind:       step.Kind,
+			Namespace:  step.Namespace,
+			Selector:   manifest.BuildLabelSelector(step.LabelSelector),
+		})
+	}
+	return effects
+}
+
+// addResource reports the objects written by resource r
+func (e *Effects) addResource(config *Config, r *Resource) {
+	ops := resourceOperations(r)
+	if r.GetTransportClient() == TransportClientGitOps {
+		e.addGitOps(config.Clients.GitOps, r, ops)
+		return
+	}
+	if r.Helm != nil {
+		e.addHelm(r, ops)
+		return
+	}
+	docs := parseEffectManifests(r.Manifest)
+	manifest := docs[0]
+
+	if !r.IsMaestroTransport() {
+		obj := objectEffect(manifest)
+		obj.Resource = r.Name
+		obj.Operations = ops
+		obj.Finalizer = resourceFinalizer(r)
+		if obj.Namespace == "" && r.Discovery != nil {
+			obj.Namespace = r.Discovery.Namespace
+		}
+		e.Kubernetes = append(e.Kubernetes, obj)
+
+		// The other documents of a multi-document manifest are applied, never deleted
+		for _, doc := range docs[1:] {
+			extra := objectEffect(doc)
+			extra.Resource = r.Name
+			extra.Operations = withoutOperation(withoutOperation(ops, EffectDelete), EffectFinalizer)
+			e.Kubernetes = append(e.Kubernetes, extra)
+		}
+		return
+	}
+
+	mw := MaestroEffect{Resource: r.Name, Operations: ops}
+	if r.Transport.Maestro != nil {
+		mw.TargetCluster = r.Transport.Maestro.TargetCluster
+	}
+	mw.ManifestWork = objectEffect(manifest).Name
+	for _, m := range workloadManifests(manifest) {
+		mw.Workload = append(mw.Workload, objectEffect(m))
+	}
+	// The other documents of a multi-document manifest join the workload
+	for _, doc := range docs[1:] {
+		mw.Workload = append(mw.Workload, objectEffect(doc))
+	}
+	e.Maestro = append(e.Maestro, mw)
+}
+
+// addNamespaces reports the namespaces created for the kubernetes objects of the
+// resources that ensure their namespace, once per namespace
+func (e *Effects) addNamespaces(config *Config) {
+	ensured := make(map[string]bool)
+	for i := range config.Resources {
+		r := &config.Resources[i]
+		if r.EnsuresNamespace(config.Clients.Kubernetes.ManageNamespaces) {
+			ensured[r.Name] = true
+		}
+	}
+	seen := make(map[string]bool)
+	var namespaces []ResourceEffect
+	for _, obj := range e.Kubernetes {
+		if !ensured[obj.Resource] || obj.Namespace == "" || seen[obj.Namespace] {
+			continue
+		}
+		seen[obj.Namespace] = true
+		namespaces = append(namespaces, ResourceEffect{
+			Resource:   obj.Resource,
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       obj.Namespace,
+			Operations: []string{EffectApply},
+			FanOut:     obj.FanOut,
+		})
+	}
+	e.Kubernetes = append(e.Kubernetes, namespaces...)
+}
+
+// addHelm reports the objects of a helm resource: with the kubernetes transport the
+// first object in install order is the resource's own and the others are never deleted;
+// with the maestro transport they form the workload of one ManifestWork.
+func (e *Effects) addHelm(r *Resource, ops []string) {
+	docs := helmEffectManifests(r.Helm)
+	if r.IsMaestroTransport() {
+		mw := MaestroEffect{Resource: r.Name, Operations: ops, ManifestWork: r.Helm.GetManifestWorkName()}
+		if r.Transport.Maestro != nil {
+			mw.TargetCluster = r.Transport.Maestro.TargetCluster
+		}
+		for _, doc := range docs {
+			mw.Workload = append(mw.Workload, objectEffect(doc))
+		}
+		e.Maestro = append(e.Maestro, mw)
+		return
+	}
+	for i, doc := range docs {
+		obj := objectEffect(doc)
+		obj.Resource = r.Name
+		obj.Operations = ops
+		obj.Finalizer = resourceFinalizer(r)
+		if i > 0 {
+			obj.Operations = withoutOperation(withoutOperation(ops, EffectDelete), EffectFinalizer)
+			obj.Finalizer = ""
+		}
+		e.Kubernetes = append(e.Kubernetes, obj)
+	}
+}
+
+// addGitOps reports the manifests of a gitops resource as files committed to the
+// repository of client, which may be nil when the config does not configure it
+func (e *Effects) addGitOps(client *GitOpsClientConfig, r *Resource, ops []string) {
+	git := GitEffect{Resource: r.Name, Branch: gitOpsDefaultBranch, Operations: ops}
+	if client != nil {
+		git.Repository = client.Repository
+		if client.Branch != "" {
+			git.Branch = client.Branch
+		}
+		if client.PullRequest != nil {
+			git.PullRequest = client.PullRequest.Repository
+		}
+	}
+	if r.Transport.GitOps != nil {
+		git.Path = r.Transport.GitOps.Path
+	}
+	var docs []map[string]interface{}
+	if r.Helm != nil {
+		docs = helmEffectManifests(r.Helm)
+	} else {
+		docs = parseEffectManifests(r.Manifest)
+	}
+	for _, doc := range docs {
+		if doc != nil {
+			git.Objects = append(git.Objects, objectEffect(doc))
+		}
+	}
+	e.Git = append(e.Git, git)
+}
+
+func (e *Effects) addAPICall(source string, call *APICall, fanOut *FanOutEffect) {
+	if call == nil || strings.EqualFold(call.Method, http.MethodGet) {
+		return
+	}
+	e.APICalls = append(e.APICalls, APICallEffect{
+		Source: source,
+		Method: strings.ToUpper(call.Method),
+		URL:    call.URL,
+		FanOut: fanOut,
+	})
+}
+
+// forEachFanOut returns the fan-out of a step with the for_each expression, or nil when
+// the step has none
+func forEachFanOut(config *Config, expression string) *FanOutEffect {
+	if expression == "" {
+		return nil
+	}
+	fanOut := &FanOutEffect{ForEach: strings.TrimSpace(expression)}
+	if limit := config.Limits.WithDefaults().MaxForEachItems; limit > 0 {
+		fanOut.MaxItems = limit
+	}
+	return fanOut
+}
+
+// WriteText writes a human-readable summary suitable for change tickets
+func (e *Effects) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	out := &utils.ErrWriter{W: tw}
+
+	out.Printf("HyperFleet API calls (%d)\n", len(e.APICalls))
+	for _, c := range e.APICalls {
+		out.Printf("  %s\t%s\t%s%s\n", c.Method, c.URL, c.Source, fanOutString(c.FanOut))
+	}
+
+	out.Printf("\nKubernetes objects (%d)\n", len(e.Kubernetes))
+	for _, r := range e.Kubernetes {
+		ops := strings.Join(r.Operations, ", ")
+		if r.Finalizer != "" {
+			ops += " (" + r.Finalizer + ")"
+		}
+		out.Printf("  %s\t%s\t%s\t%s\t%s%s\n",
+			r.Resource, kindString(r), namespaceString(r.Namespace), r.Name, ops, fanOutString(r.FanOut))
+	}
+
+	out.Printf("\nMaestro ManifestWorks (%d)\n", len(e.Maestro))
+	for _, m := range e.Maestro {
+		out.Printf("  %s\tconsumer=%s\tmanifestwork=%s\t%s%s\n",
+			m.Resource, m.TargetCluster, m.ManifestWork, strings.Join(m.Operations, ", "), fanOutString(m.FanOut))
+		for _, r := range m.Workload {
+			out.Printf("    \t%s\t%s\t%s\n", kindString(r), namespaceString(r.Namespace), r.Name)
+		}
+	}
+
+	out.Printf("\nGit repositories (%d)\n", len(e.Git))
+	for _, g := range e.Git {
+		target := "branch=" + g.Branch
+		if g.PullRequest != "" {
+			target = "pull request on " + g.PullRequest + " against " + g.Branch
+		}
+		out.Printf("  %s\trepository=%s\t%s\tpath=%s\t%s%s\n",
+			g.Resource, g.Repository, target, g.Path, strings.Join(g.Operations, ", "), fanOutString(g.FanOut))
+		for _, r := range g.Objects {
+			out.Printf("    \t%s\t%s\t%s\n", kindString(r), namespaceString(r.Namespace), r.Name)
+		}
+	}
+
+	out.Printf("\nPruned Kubernetes objects (%d)\n", len(e.Prune))
+	for _, p := range e.Prune {
+		kind := kindString(ResourceEffect{APIVersion: p.APIVersion, Kind: p.Kind})
+		namespace := "<all namespaces>"
+		if p.Namespace != "" {
+			namespace = namespaceString(p.Namespace)
+		}
+		out.Printf("  %s\t%s\t%s\tselector=%s\t%s\n", p.Step, kind, namespace, p.Selector, EffectDelete)
+	}
+	if out.Err != nil {
+		return out.Err
+	}
+	return tw.Flush()
+}
+
+// resourceOperations lists the mutating operations configured for r
+func resourceOperations(r *Resource) []string {
+	ops := []string{EffectApply}
+	if r.RecreateOnChange {
+		ops = append(ops, EffectRecreate)
+	}
+	if r.Lifecycle != nil && r.Lifecycle.Delete != nil {
+		ops = append(ops, EffectDelete)
+	}
+	if resourceFinalizer(r) != "" {
+		ops = append(ops, EffectFinalizer)
+	}
+	return ops
+}
+
+// resourceFinalizer returns the lifecycle finalizer of r, if any
+func resourceFinalizer(r *Resource) string {
+	if r.Lifecycle == nil || r.Lifecycle.Delete == nil {
+		return ""
+	}
+	return r.Lifecycle.Delete.Finalizer
+}
+
+// parseEffectManifests returns the documents of the manifest as maps, parsing raw
+// manifest_ref and manifest.ref content. It always returns at least one, possibly nil, map.
+func parseEffectManifests(manifest interface{}) []map[string]interface{} {
+	raw, ok := manifest.(string)
+	if !ok {
+		return []map[string]interface{}{normalizeToStringKeyMap(manifest)}
+	}
+
+	var docs []map[string]interface{}
+	decoder := yaml.NewDecoder(strings.NewReader(raw))
+	for {
+		var parsed map[string]interface{}
+		if decoder.Decode(&parsed) != nil {
+			break
+		}
+		if len(parsed) > 0 {
+			docs = append(docs, parsed)
+		}
+	}
+	if len(docs) == 0 {
+		return []map[string]interface{}{nil}
+	}
+	return docs
+}
+
+// withoutOperation returns ops without op
+func withoutOperation(ops []string, op string) []string {
+	out := make([]string, 0, len(ops))
+	for _, o := range ops {
+		if o != op {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// workloadManifests returns spec.workload.manifests of a ManifestWork
+func workloadManifests(manifest map[string]interface{}) []map[string]interface{} {
+	spec := normalizeToStringKeyMap(manifest["spec"])
+	workload := normalizeToStringKeyMap(spec["workload"])
+	items, ok := workload["manifests"].([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if m := normalizeToStringKeyMap(item); m != nil {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// objectEffect reads apiVersion, kind, metadata.namespace and metadata.name from manifest
+func objectEffect(manifest map[string]interface{}) ResourceEffect {
+	metadata := normalizeToStringKeyMap(manifest["metadata"])
+	str := func(m map[string]interface{}, key string) string {
+		if s, ok := m[key].(string); ok {
+			return s
+		}
+		return ""
+	}
+	return ResourceEffect{
+		APIVersion: str(manifest, "apiVersion"),
+		Kind:       str(manifest, "kind"),
+		Namespace:  str(metadata, "namespace"),
+		Name:       str(metadata, "name"),
+	}
+}
+
+// fanOutString describes a for_each fan-out for the text summary, or "" without one
+func fanOutString(f *FanOutEffect) string {
+	if f == nil {
+		return ""
+	}
+	if f.MaxItems == 0 {
+		return fmt.Sprintf("\t[fan-out: for_each %s, unbounded]", f.ForEach)
+	}
+	return fmt.Sprintf("\t[fan-out: for_each %s, up to %d items]", f.ForEach, f.MaxItems)
+}
+
+func kindString(r ResourceEffect) string {
+	if r.Kind == "" {
+		return "<unknown kind>"
+	}
+	if r.APIVersion == "" {
+		return r.Kind
+	}
+	return r.Kind + " (" + r.APIVersion + ")"
+}
+
+func namespaceString(ns string) string {
+	if ns == "" {
+		return "<cluster-scoped>"
+	}
+	return "ns=" + ns
+}
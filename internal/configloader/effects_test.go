@@ -0,0 +1,306 @@
+package configloader
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeEffects(t *testing.T) {
+	config := &Config{
+		Preconditions: []Precondition{
+			{ActionBase: ActionBase{Name: "getCluster", APICall: &APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"}}},
+			{ActionBase: ActionBase{Name: "claim", APICall: &APICall{Method: "post", URL: "/clusters/{{ .clusterId }}/claims"}}},
+		},
+		Resources: []Resource{
+			{
+				Name: "ns",
+				Manifest: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "Namespace",
+					"metadata":   map[string]interface{}{"name": "{{ .clusterId }}"},
+				},
+				Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{}},
+			},
+			{
+				Name:             "cm",
+				Manifest:         "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cfg\n",
+				Discovery:        &DiscoveryConfig{Namespace: "{{ .clusterId }}", ByName: "cfg"},
+				RecreateOnChange: true,
+			},
+			{
+				Name: "work",
+				Transport: &TransportConfig{
+					Client:  TransportClientMaestro,
+					Maestro: &MaestroTransportConfig{TargetCluster: "{{ .consumer }}"},
+				},
+				Manifest: map[string]interface{}{
+					"apiVersion": "work.open-cluster-management.io/v1",
+					"kind":       "ManifestWork",
+					"metadata":   map[string]interface{}{"name": "work-{{ .clusterId }}"},
+					"spec": map[string]interface{}{
+						"workload": map[string]interface{}{
+							"manifests": []interface{}{
+								map[string]interface{}{
+									"apiVersion": "apps/v1",
+									"kind":       "Deployment",
+									"metadata":   map[string]interface{}{"name": "agent", "namespace": "agents"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Post: &PostConfig{PostActions: []PostAction{
+			{ActionBase: ActionBase{
+				Name:    "reportStatus",
+				APICall: &APICall{Method: "PUT", URL: "/clusters/{{ .clusterId }}/statuses"},
+			}},
+			{ActionBase: ActionBase{Name: "logOnly", Log: &LogAction{Message: "done"}}},
+		}},
+	}
+
+	effects := AnalyzeEffects(config)
+
+	assert.Equal(t, []APICallEffect{
+		{Source: "preconditions[claim]", Method: "POST", URL: "/clusters/{{ .clusterId }}/claims"},
+		{Source: "post_actions[reportStatus]", Method: "PUT", URL: "/clusters/{{ .clusterId }}/statuses"},
+	}, effects.APICalls)
+
+	require.Len(t, effects.Kubernetes, 2)
+	assert.Equal(t, ResourceEffect{
+		Resource: "ns", APIVersion: "v1", Kind: "Namespace", Name: "{{ .clusterId }}",
+		Operations: []string{EffectApply, EffectDelete},
+	}, effects.Kubernetes[0])
+	assert.Equal(t, "ConfigMap", effects.Kubernetes[1].Kind)
+	assert.Equal(t, "{{ .clusterId }}", effects.Kubernetes[1].Namespace, "namespace falls back to discovery")
+	assert.Equal(t, []string{EffectApply, EffectRecreate}, effects.Kubernetes[1].Operations)
+
+	require.Len(t, effects.Maestro, 1)
+	mw := effects.Maestro[0]
+	assert.Equal(t, "{{ .consumer }}", mw.TargetCluster)
+	assert.Equal(t, "work-{{ .clusterId }}", mw.ManifestWork)
+	require.Len(t, mw.Workload, 1)
+	assert.Equal(t, "Deployment", mw.Workload[0].Kind)
+	assert.Equal(t, "agents", mw.Workload[0].Namespace)
+
+	var buf bytes.Buffer
+	require.NoError(t, effects.WriteText(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "HyperFleet API calls (2)")
+	assert.Contains(t, out, "Namespace (v1)")
+	assert.Contains(t, out, "<cluster-scoped>")
+	assert.Contains(t, out, "consumer={{ .consumer }}")
+}
+
+func TestAnalyzeEffects_MultiDocumentManifest(t *testing.T) {
+	config := &Config{Resources: []Resource{
+		{
+			Name:      "app",
+			Manifest:  "kind: ConfigMap\nmetadata:\n  name: cfg\n---\nkind: Secret\nmetadata:\n  name: creds\n",
+			Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{}},
+		},
+		{
+			Name:      "work",
+			Transport: &TransportConfig{Client: TransportClientMaestro},
+			Manifest:  "kind: ManifestWork\nmetadata:\n  name: work\n---\nkind: Deployment\nmetadata:\n  name: agent\n",
+		},
+	}}
+
+	effects := AnalyzeEffects(config)
+
+	require.Len(t, effects.Kubernetes, 2)
+	assert.Equal(t, []string{EffectApply, EffectDelete}, effects.Kubernetes[0].Operations)
+	assert.Equal(t, "Secret", effects.Kubernetes[1].Kind)
+	assert.Equal(t, "app", effects.Kubernetes[1].Resource)
+	assert.Equal(t, []string{EffectApply}, effects.Kubernetes[1].Operations,
+		"later documents are not deleted with the resource")
+
+	require.Len(t, effects.Maestro, 1)
+	assert.Equal(t, "work", effects.Maestro[0].ManifestWork)
+	require.Len(t, effects.Maestro[0].Workload, 1)
+	assert.Equal(t, "Deployment", effects.Maestro[0].Workload[0].Kind)
+}
+
+func TestAnalyzeEffects_EnsureNamespace(t *testing.T) {
+	config := &Config{Resources: []Resource{
+		{
+			Name:            "app",
+			Manifest:        "kind: ConfigMap\nmetadata:\n  name: cfg\n  namespace: fleet\n",
+			EnsureNamespace: true,
+		},
+		{
+			Name:            "creds",
+			Manifest:        "kind: Secret\nmetadata:\n  name: creds\n  namespace: fleet\n",
+			EnsureNamespace: true,
+		},
+		{
+			Name:     "other",
+			Manifest: "kind: ConfigMap\nmetadata:\n  name: cfg\n  namespace: other\n",
+		},
+	}}
+
+	effects := AnalyzeEffects(config)
+	require.Len(t, effects.Kubernetes, 4)
+	assert.Equal(t, ResourceEffect{
+		Resource: "app", APIVersion: "v1", Kind: "Namespace", Name: "fleet", Operations: []string{EffectApply},
+	}, effects.Kubernetes[3], "a namespace is reported once, by the first resource ensuring it")
+
+	config.Clients.Kubernetes.ManageNamespaces = true
+	effects = AnalyzeEffects(config)
+	require.Len(t, effects.Kubernetes, 5)
+	assert.Equal(t, "other", effects.Kubernetes[4].Name)
+}
+
+func TestAnalyzeEffects_NilConfig(t *testing.T) {
+	effects := AnalyzeEffects(nil)
+	assert.Empty(t, effects.APICalls)
+	assert.Empty(t, effects.Kubernetes)
+	assert.Empty(t, effects.Maestro)
+	assert.Empty(t, effects.Git)
+	assert.Empty(t, effects.Prune)
+}
+
+func TestAnalyzeEffects_Prune(t *testing.T) {
+	config := &Config{Prune: []PruneStep{
+		{
+			Name: "staleConfigMaps", APIVersion: "v1", Kind: "ConfigMap", Namespace: "{{ .clusterId }}",
+			LabelSelector: map[string]string{"hyperfleet.io/cluster-id": "{{ .clusterId }}", "app": "agent"},
+		},
+		{
+			Name: "staleRoles", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole",
+			LabelSelector: map[string]string{"app": "agent"},
+		},
+	}}
+
+	effects := AnalyzeEffects(config)
+	assert.Equal(t, []PruneEffect{
+		{
+			Step: "staleConfigMaps", APIVersion: "v1", Kind: "ConfigMap", Namespace: "{{ .clusterId }}",
+			Selector: "app=agent,hyperfleet.io/cluster-id={{ .clusterId }}",
+		},
+		{Step: "staleRoles", APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole", Selector: "app=agent"},
+	}, effects.Prune)
+
+	var buf bytes.Buffer
+	require.NoError(t, effects.WriteText(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "Pruned Kubernetes objects (2)")
+	assert.Contains(t, out, "selector=app=agent,hyperfleet.io/cluster-id={{ .clusterId }}")
+	assert.Contains(t, out, "<all namespaces>")
+}
+
+func TestAnalyzeEffects_Teardown(t *testing.T) {
+	config := &Config{Resources: []Resource{{
+		Name:     "bucket",
+		Manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: bucket\n---\nkind: Secret\nmetadata:\n  name: creds\n",
+		Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{
+			Finalizer: "hyperfleet.io/bucket-cleanup",
+			Teardown: []ActionBase{
+				{Name: "getBucket", APICall: &APICall{Method: "GET", URL: "/buckets/{{ .clusterId }}"}},
+				{Name: "deleteBucket", APICall: &APICall{Method: "DELETE", URL: "/buckets/{{ .clusterId }}"}},
+				{Name: "logDone", Log: &LogAction{Message: "bucket deleted"}},
+			},
+		}},
+	}}}
+
+	effects := AnalyzeEffects(config)
+	assert.Equal(t, []APICallEffect{
+		{Source: "resources[bucket].teardown[deleteBucket]", Method: "DELETE", URL: "/buckets/{{ .clusterId }}"},
+	}, effects.APICalls)
+
+	require.Len(t, effects.Kubernetes, 2)
+	assert.Equal(t, []string{EffectApply, EffectDelete, EffectFinalizer}, effects.Kubernetes[0].Operations)
+	assert.Equal(t, "hyperfleet.io/bucket-cleanup", effects.Kubernetes[0].Finalizer)
+	assert.Equal(t, []string{EffectApply}, effects.Kubernetes[1].Operations,
+		"only the resource's own object carries the finalizer")
+	assert.Empty(t, effects.Kubernetes[1].Finalizer)
+
+	var buf bytes.Buffer
+	require.NoError(t, effects.WriteText(&buf))
+	assert.Contains(t, buf.String(), "apply, delete, finalizer (hyperfleet.io/bucket-cleanup)")
+}
+
+func TestAnalyzeEffects_ForEach(t *testing.T) {
+	config := &Config{
+		Preconditions: []Precondition{{
+			ActionBase: ActionBase{
+				Name:    "registerRegions",
+				APICall: &APICall{Method: "POST", URL: "/regions/{{ .item }}"},
+			},
+			ForEach: "params.regions",
+		}},
+		Resources: []Resource{
+			{
+				Name:     "regionConfig",
+				ForEach:  "params.regions",
+				Manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm-{{ .item }}\n  namespace: fleet\n",
+			},
+			{
+				Name:     "agent",
+				Manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: agent\n  namespace: fleet\n",
+			},
+		},
+	}
+
+	effects := AnalyzeEffects(config)
+	fanOut := &FanOutEffect{ForEach: "params.regions", MaxItems: DefaultMaxForEachItems}
+	require.Len(t, effects.APICalls, 1)
+	assert.Equal(t, fanOut, effects.APICalls[0].FanOut)
+	require.Len(t, effects.Kubernetes, 2)
+	assert.Equal(t, fanOut, effects.Kubernetes[0].FanOut)
+	assert.Nil(t, effects.Kubernetes[1].FanOut, "a resource without for_each does not fan out")
+
+	var buf bytes.Buffer
+	require.NoError(t, effects.WriteText(&buf))
+	assert.Contains(t, buf.String(), "[fan-out: for_each params.regions, up to 100 items]")
+
+	config.Limits.MaxForEachItems = -1
+	effects = AnalyzeEffects(config)
+	assert.Equal(t, &FanOutEffect{ForEach: "params.regions"}, effects.Kubernetes[0].FanOut)
+	buf.Reset()
+	require.NoError(t, effects.WriteText(&buf))
+	assert.Contains(t, buf.String(), "[fan-out: for_each params.regions, unbounded]")
+}
+
+func TestAnalyzeEffects_GitOps(t *testing.T) {
+	config := &Config{
+		Clients: ClientsConfig{GitOps: &GitOpsClientConfig{
+			Repository:  "https://github.com/org/fleet.git",
+			PullRequest: &GitOpsPullRequestConfig{Repository: "org/fleet"},
+		}},
+		Resources: []Resource{{
+			Name: "agent",
+			Transport: &TransportConfig{
+				Client: TransportClientGitOps,
+				GitOps: &GitOpsTransportConfig{Path: "clusters/{{ .clusterId }}"},
+			},
+			Manifest:  "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: agent\n  namespace: fleet\n",
+			Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{}},
+		}},
+	}
+
+	effects := AnalyzeEffects(config)
+	assert.Empty(t, effects.Kubernetes, "gitops resources are not written to a cluster")
+	require.Len(t, effects.Git, 1)
+	assert.Equal(t, GitEffect{
+		Resource:    "agent",
+		Repository:  "https://github.com/org/fleet.git",
+		Branch:      "main",
+		PullRequest: "org/fleet",
+		Path:        "clusters/{{ .clusterId }}",
+		Operations:  []string{EffectApply, EffectDelete},
+		Objects:     []ResourceEffect{{APIVersion: "v1", Kind: "ConfigMap", Namespace: "fleet", Name: "agent"}},
+	}, effects.Git[0])
+
+	var buf bytes.Buffer
+	require.NoError(t, effects.WriteText(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "Git repositories (1)")
+	assert.Contains(t, out, "repository=https://github.com/org/fleet.git")
+	assert.Contains(t, out, "pull request on org/fleet against main")
+	assert.Contains(t, out, "path=clusters/{{ .clusterId }}")
+}
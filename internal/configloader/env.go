@@ -0,0 +1,66 @@
+package configloader
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// MissingEnvVars modes: what loading does when an optional env.* param has no value
+const (
+	MissingEnvVarsWarn = "warn"
+	MissingEnvVarsFail = "fail"
+)
+
+// MissingEnvVar is an environment variable read by an env.* param that is not set and
+// that the param has no default for
+type MissingEnvVar struct {
+	Name     string
+	Param    string
+	Required bool
+}
+
+func (m MissingEnvVar) String() string {
+	if m.Required {
+		return fmt.Sprintf("%s (param %s, required)", m.Name, m.Param)
+	}
+	return fmt.Sprintf("%s (param %s)", m.Name, m.Param)
+}
+
+// FindMissingEnvVars lists, in param order, the environment variables of env.* params
+// without a default that are unset, or empty for required params
+func FindMissingEnvVars(params []Parameter) []MissingEnvVar {
+	var missing []MissingEnvVar
+	for _, p := range params {
+		if !p.Source.IsString() || !strings.HasPrefix(p.Source.StringVal, "env.") || p.Default != nil {
+			continue
+		}
+		name := strings.TrimPrefix(p.Source.StringVal, "env.")
+		if value, ok := os.LookupEnv(name); !ok || (p.Required && value == "") {
+			missing = append(missing, MissingEnvVar{Name: name, Param: p.Name, Required: p.Required})
+		}
+	}
+	return missing
+}
+
+// CheckEnvVars reports the environment variables missing for env.* params. Missing
+// variables of required params are errors; those of optional params are errors with
+// config.MissingEnvVars set to "fail" and are returned as warnings otherwise.
+func CheckEnvVars(config *Config) (warnings []string, err error) {
+	if config == nil {
+		return nil, nil
+	}
+	errs := &ValidationErrors{}
+	for _, m := range FindMissingEnvVars(config.Params) {
+		if m.Required || config.MissingEnvVars == MissingEnvVarsFail {
+			errs.Add(FieldParams, fmt.Sprintf("environment variable %s is not set", m))
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"environment variable %s is not set: the param is left unset on every event", m))
+	}
+	if errs.HasErrors() {
+		return warnings, errs
+	}
+	return warnings, nil
+}
@@ -0,0 +1,103 @@
+package configloader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindMissingEnvVars(t *testing.T) {
+	t.Setenv("HF_TEST_SET", "value")
+	t.Setenv("HF_TEST_EMPTY", "")
+
+	params := []Parameter{
+		{Name: "set", Source: StringSource("env.HF_TEST_SET"), Required: true},
+		{Name: "emptyOptional", Source: StringSource("env.HF_TEST_EMPTY")},
+		{Name: "emptyRequired", Source: StringSource("env.HF_TEST_EMPTY"), Required: true},
+		{Name: "unset", Source: StringSource("env.HF_TEST_UNSET")},
+		{Name: "defaulted", Source: StringSource("env.HF_TEST_UNSET"), Default: "fallback", Required: true},
+		{Name: "event", Source: StringSource("event.id"), Required: true},
+	}
+
+	assert.Equal(t, []MissingEnvVar{
+		{Name: "HF_TEST_EMPTY", Param: "emptyRequired", Required: true},
+		{Name: "HF_TEST_UNSET", Param: "unset"},
+	}, FindMissingEnvVars(params))
+}
+
+func TestCheckEnvVars(t *testing.T) {
+	config := &Config{Params: []Parameter{
+		{Name: "region", Source: StringSource("env.HF_TEST_REGION")},
+		{Name: "token", Source: StringSource("env.HF_TEST_TOKEN"), Required: true},
+		{Name: "zone", Source: StringSource("env.HF_TEST_ZONE")},
+	}}
+
+	t.Run("optional params warn, required params fail", func(t *testing.T) {
+		warnings, err := CheckEnvVars(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "HF_TEST_TOKEN (param token, required)")
+		assert.NotContains(t, err.Error(), "HF_TEST_REGION")
+		require.Len(t, warnings, 2)
+		assert.Contains(t, warnings[0], "HF_TEST_REGION (param region)")
+		assert.Contains(t, warnings[1], "HF_TEST_ZONE (param zone)")
+	})
+
+	t.Run("fail reports every missing variable", func(t *testing.T) {
+		failing := *config
+		failing.MissingEnvVars = MissingEnvVarsFail
+		warnings, err := CheckEnvVars(&failing)
+		require.Error(t, err)
+		assert.Empty(t, warnings)
+		for _, name := range []string{"HF_TEST_REGION", "HF_TEST_TOKEN", "HF_TEST_ZONE"} {
+			assert.Contains(t, err.Error(), name)
+		}
+	})
+
+	t.Run("all set", func(t *testing.T) {
+		t.Setenv("HF_TEST_REGION", "us-east-1")
+		t.Setenv("HF_TEST_TOKEN", "secret")
+		t.Setenv("HF_TEST_ZONE", "a")
+		warnings, err := CheckEnvVars(config)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+}
+
+func TestLoadConfig_MissingEnvVars(t *testing.T) {
+	tmpDir := t.TempDir()
+	adapterPath, taskPath := createTestConfigFiles(t, tmpDir, testAdapterConfigYAML, `
+params:
+  - name: "region"
+    source: "env.HF_TEST_LOAD_REGION"
+  - name: "token"
+    source: "env.HF_TEST_LOAD_TOKEN"
+    required: true
+  - name: "project"
+    source: "env.HF_TEST_LOAD_PROJECT"
+    required: true
+`)
+	load := func() error {
+		_, err := LoadConfig(
+			WithAdapterConfigPath(adapterPath),
+			WithTaskConfigPath(taskPath),
+			WithSkipSemanticValidation(),
+		)
+		return err
+	}
+
+	err := load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing environment variables")
+	assert.Contains(t, err.Error(), "HF_TEST_LOAD_TOKEN", "every missing variable should be listed")
+	assert.Contains(t, err.Error(), "HF_TEST_LOAD_PROJECT", "every missing variable should be listed")
+
+	t.Setenv("HF_TEST_LOAD_TOKEN", "secret")
+	t.Setenv("HF_TEST_LOAD_PROJECT", "hyperfleet")
+	require.NoError(t, load(), "a missing optional variable only warns by default")
+
+	t.Setenv("HYPERFLEET_MISSING_ENV_VARS", MissingEnvVarsFail)
+	err = load()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HF_TEST_LOAD_REGION (param region)")
+}
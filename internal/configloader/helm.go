@@ -0,0 +1,89 @@
+package configloader
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/helm"
+)
+
+// loadHelmCharts loads the charts of the resources with a helm step into
+// HelmConfig.LoadedChart. Local charts resolve relative to baseDir; oci:// charts are
+// pulled into the task config cache with the task config registry credentials.
+func loadHelmCharts(o *loadOptions, config *AdapterTaskConfig, baseDir string) error {
+	var puller *ociPuller
+	for i := range config.Resources {
+		h := config.Resources[i].Helm
+		if h == nil {
+			continue
+		}
+		path := fmt.Sprintf("%s[%d].%s.%s", FieldResources, i, FieldHelm, FieldChart)
+
+		var chart *helm.Chart
+		var err error
+		if IsOCIReference(h.Chart) {
+			if puller == nil {
+				base, pullerErr := newOCIPuller(o.ociCacheDir, o.ociVerifyKeyPath, o.logger)
+				if pullerErr != nil {
+					return fmt.Errorf("%s: failed to configure chart pull: %w", path, pullerErr)
+				}
+				puller = base.withArtifact(helmChartArtifact)
+			}
+			chart, err = pullHelmChart(o.ctx, puller, h)
+		} else {
+			var chartPath string
+			chartPath, err = resolvePath(baseDir, h.Chart)
+			if err == nil {
+				chart, err = helm.Load(chartPath)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		if h.Version != "" && chart.Metadata.Version != h.Version {
+			return fmt.Errorf("%s: chart %s has version %q, expected %q",
+				path, chart.Metadata.Name, chart.Metadata.Version, h.Version)
+		}
+		h.LoadedChart = chart
+	}
+	return nil
+}
+
+// pullHelmChart pulls an oci:// chart. A reference without a tag or digest is pulled
+// at the configured version, tagged as `helm push` does.
+func pullHelmChart(ctx context.Context, puller *ociPuller, h *HelmConfig) (*helm.Chart, error) {
+	ref := h.Chart
+	lastSegment := ref[strings.LastIndex(ref, "/")+1:]
+	if h.Version != "" && !strings.ContainsAny(lastSegment, ":@") {
+		ref += ":" + strings.ReplaceAll(h.Version, "+", "_")
+	}
+	archivePath, err := puller.Pull(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull chart: %w", err)
+	}
+	return helm.Load(archivePath)
+}
+
+// helmEffectManifests renders the chart of a helm resource with its values as written,
+// for AnalyzeEffects. A chart that does not render without the execution params is
+// reported as one object with empty kind.
+func helmEffectManifests(h *HelmConfig) []map[string]interface{} {
+	if h.LoadedChart == nil {
+		return []map[string]interface{}{nil}
+	}
+	docs, err := helm.Render(h.LoadedChart, helm.Release{Name: h.ReleaseName, Namespace: h.Namespace}, h.Values)
+	if err != nil || len(docs) == 0 {
+		return []map[string]interface{}{nil}
+	}
+	out := make([]map[string]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		var obj map[string]interface{}
+		if json.Unmarshal(doc, &obj) == nil {
+			out = append(out, obj)
+		}
+	}
+	return out
+}
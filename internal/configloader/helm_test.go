@@ -0,0 +1,189 @@
+package configloader
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/helm"
+)
+
+var testHelmChartFiles = map[string]string{
+	"Chart.yaml":  "apiVersion: v2\nname: agent\nversion: 0.1.0\n",
+	"values.yaml": "region: unknown\n",
+	"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  region: {{ .Values.region | quote }}
+`,
+	"templates/serviceaccount.yaml": `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Release.Name }}
+`,
+}
+
+// packageTestChart returns testHelmChartFiles as a chart archive, as `helm package` makes it
+func packageTestChart(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range testHelmChartFiles {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name: "agent/" + name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestLoadConfig_HelmChart(t *testing.T) {
+	tmpDir := t.TempDir()
+	for name, content := range testHelmChartFiles {
+		path := filepath.Join(tmpDir, "charts", "agent", name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	load := func(helmYAML string) (*Config, error) {
+		adapterPath, taskPath := createTestConfigFiles(t, tmpDir, testAdapterConfigYAML, `
+params:
+  - name: "clusterId"
+    source: "event.id"
+resources:
+  - name: "agent"
+`+helmYAML+`
+    discovery:
+      namespace: "fleet"
+      by_name: "agent-{{ .clusterId }}"
+`)
+		return LoadConfig(
+			WithAdapterConfigPath(adapterPath),
+			WithTaskConfigPath(taskPath),
+		)
+	}
+
+	config, err := load(`    helm:
+      chart: "charts/agent"
+      version: "0.1.0"
+      release_name: "agent-{{ .clusterId }}"
+      namespace: "fleet"
+      values:
+        region: "{{ .clusterId }}"`)
+	require.NoError(t, err)
+	chart := config.Resources[0].Helm.LoadedChart
+	require.NotNil(t, chart, "the chart should be loaded with the config")
+	assert.Equal(t, "agent", chart.Metadata.Name)
+
+	_, err = load(`    helm:
+      chart: "charts/agent"
+      version: "0.2.0"
+      release_name: "agent"
+      namespace: "fleet"`)
+	assert.ErrorContains(t, err, `resources[0].helm.chart: chart agent has version "0.1.0", expected "0.2.0"`)
+
+	_, err = load(`    helm:
+      chart: "charts/agent"
+      release_name: "agent"
+      namespace: "fleet"
+      values:
+        region: "{{ .undefinedParam }}"`)
+	assert.ErrorContains(t, err, "resources[0].helm.values.region")
+
+	_, err = load(`    helm:
+      chart: "charts/agent"
+      release_name: "agent"
+      namespace: "fleet"
+    manifest:
+      apiVersion: v1
+      kind: ConfigMap`)
+	assert.ErrorContains(t, err, "helm cannot be combined with manifest or manifest_ref")
+}
+
+func TestPullHelmChart(t *testing.T) {
+	reg := newFakeRegistry()
+	archive := packageTestChart(t)
+	chartConfig := []byte(`{"name":"agent","version":"0.1.0"}`)
+	reg.addManifest("0.1.0", []ociDescriptor{
+		{MediaType: "application/vnd.cncf.helm.config.v1+json", Digest: reg.addBlob(chartConfig)},
+		{MediaType: OCIHelmChartMediaType, Digest: reg.addBlob(archive), Size: int64(len(archive))},
+	})
+	server := httptest.NewTLSServer(reg)
+	defer server.Close()
+	puller := newTestOCIPuller(t, server).withArtifact(helmChartArtifact)
+
+	chart, err := pullHelmChart(context.Background(), puller, &HelmConfig{
+		Chart:   ociRef(server, ""),
+		Version: "0.1.0",
+	})
+	require.NoError(t, err, "a reference without a tag should be pulled at the version")
+	assert.Equal(t, "agent", chart.Metadata.Name)
+	assert.Len(t, chart.Templates, 2)
+
+	entries, err := os.ReadDir(puller.cacheDir)
+	require.NoError(t, err)
+	var cached []string
+	for _, e := range entries {
+		if e.IsDir() && e.Name() != "refs" {
+			files, _ := os.ReadDir(filepath.Join(puller.cacheDir, e.Name()))
+			for _, f := range files {
+				if strings.HasPrefix(f.Name(), ".") {
+					continue // verification records, not layers
+				}
+				cached = append(cached, f.Name())
+			}
+		}
+	}
+	assert.Equal(t, []string{ociHelmChartFileName}, cached, "only the chart layer should be extracted")
+}
+
+func TestAnalyzeEffects_HelmChart(t *testing.T) {
+	chart, err := helm.LoadArchive(packageTestChart(t))
+	require.NoError(t, err)
+
+	config := &Config{Resources: []Resource{
+		{
+			Name:      "agent",
+			Helm:      &HelmConfig{Chart: "charts/agent", ReleaseName: "agent", Namespace: "fleet", LoadedChart: chart},
+			Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{}},
+		},
+		{
+			Name: "agentWork",
+			Transport: &TransportConfig{
+				Client:  TransportClientMaestro,
+				Maestro: &MaestroTransportConfig{TargetCluster: "{{ .clusterName }}"},
+			},
+			Helm: &HelmConfig{
+				Chart: "charts/agent", ReleaseName: "agent", Namespace: "fleet",
+				ManifestWorkName: "agent-work", LoadedChart: chart,
+			},
+		},
+	}}
+
+	effects := AnalyzeEffects(config)
+	require.Len(t, effects.Kubernetes, 2)
+	assert.Equal(t, "ServiceAccount", effects.Kubernetes[0].Kind)
+	assert.Contains(t, effects.Kubernetes[0].Operations, EffectDelete)
+	assert.Equal(t, "ConfigMap", effects.Kubernetes[1].Kind)
+	assert.Equal(t, "fleet", effects.Kubernetes[1].Namespace)
+	assert.NotContains(t, effects.Kubernetes[1].Operations, EffectDelete)
+
+	require.Len(t, effects.Maestro, 1)
+	assert.Equal(t, "agent-work", effects.Maestro[0].ManifestWork)
+	assert.Len(t, effects.Maestro[0].Workload, 2)
+}
@@ -0,0 +1,291 @@
+package configloader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// -----------------------------------------------------------------------------
+// Step file includes
+// -----------------------------------------------------------------------------
+
+// Delimiters of the step file inputs. They differ from the Go template delimiters of
+// the steps, which are rendered at execution time and are left as written.
+const (
+	includeLeftDelim  = "[["
+	includeRightDelim = "]]"
+)
+
+// includeSection is a step list an include entry can appear in
+type includeSection struct {
+	// key is the key of the list in a step file
+	key string
+	// path is the path of the list in the task config
+	path []string
+}
+
+// includeSections are the step lists that can include a step file
+var includeSections = []includeSection{
+	{path: []string{FieldPreconditions}, key: FieldPreconditions},
+	{path: []string{FieldResources}, key: FieldResources},
+	{path: []string{FieldPost, FieldPostActions}, key: FieldPostActions},
+}
+
+// stepFile is a file of steps shared by task configs. Inputs are the parameters of the
+// file with their default values; an input without a default must be set by each include
+// entry.
+type stepFile struct {
+	Inputs        map[string]interface{} `yaml:"inputs"`
+	Preconditions yaml.Node              `yaml:"preconditions"`
+	Resources     yaml.Node              `yaml:"resources"`
+	PostActions   yaml.Node              `yaml:"post_actions"`
+}
+
+// section returns the step list of the file for key
+func (f *stepFile) section(key string) *yaml.Node {
+	switch key {
+	case FieldPreconditions:
+		return &f.Preconditions
+	case FieldResources:
+		return &f.Resources
+	default:
+		return &f.PostActions
+	}
+}
+
+// includeEntry is a step list entry pulling in the steps of a step file
+type includeEntry struct {
+	With    map[string]interface{} `yaml:"with"`
+	Include string                 `yaml:"include"`
+}
+
+// expandIncludes replaces the include entries of the step lists of a task config with the
+// steps of the referenced step files, resolved against baseDir. The config is returned
+// unchanged when it has no include entry.
+func expandIncludes(data []byte, baseDir string) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse task config YAML: %w", err)
+	}
+	if len(root.Content) == 0 {
+		return data, nil
+	}
+
+	expanded := false
+	for _, section := range includeSections {
+		list := mappingValue(root.Content[0], section.path...)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+		items, changed, err := expandStepList(list.Content, section.key, strings.Join(section.path, "."), baseDir, nil)
+		if err != nil {
+			return nil, err
+		}
+		list.Content = items
+		expanded = expanded || changed
+	}
+	if !expanded {
+		return data, nil
+	}
+	return yaml.Marshal(&root)
+}
+
+// expandStepList replaces the include entries of items, the steps of the list at path,
+// with the section key of their step files. chain holds the files being included, to
+// reject cycles.
+func expandStepList(items []*yaml.Node, key, path, baseDir string, chain []string) ([]*yaml.Node, bool, error) {
+	var expanded []*yaml.Node
+	changed := false
+	for i, item := range items {
+		if mappingValue(item, FieldInclude) == nil {
+			expanded = append(expanded, item)
+			continue
+		}
+		changed = true
+		entryPath := fmt.Sprintf("%s[%d]", path, i)
+		steps, err := includeSteps(item, key, entryPath, baseDir, chain)
+		if err != nil {
+			return nil, false, err
+		}
+		expanded = append(expanded, steps...)
+	}
+	return expanded, changed, nil
+}
+
+// includeSteps returns the steps an include entry pulls in: the section key of its step
+// file, with the inputs substituted and nested includes expanded
+func includeSteps(item *yaml.Node, key, path, baseDir string, chain []string) ([]*yaml.Node, error) {
+	for j := 0; j+1 < len(item.Content); j += 2 {
+		if k := item.Content[j].Value; k != FieldInclude && k != FieldWith {
+			return nil, fmt.Errorf("%s: unknown field %q in include entry (expected %s and %s)",
+				path, k, FieldInclude, FieldWith)
+		}
+	}
+	var entry includeEntry
+	if err := item.Decode(&entry); err != nil {
+		return nil, fmt.Errorf("%s: invalid include entry: %w", path, err)
+	}
+	if entry.Include == "" {
+		return nil, fmt.Errorf("%s.%s: a step file path is required", path, FieldInclude)
+	}
+	path = path + "." + FieldInclude
+
+	fullPath, err := resolvePath(baseDir, entry.Include)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, included := range chain {
+		if included == fullPath {
+			return nil, fmt.Errorf("%s: include cycle: %s -> %s", path, strings.Join(chain, " -> "), fullPath)
+		}
+	}
+
+	file, err := loadStepFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	values, err := includeInputs(file.Inputs, entry.With)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", path, entry.Include, err)
+	}
+
+	section := file.section(key)
+	if section.Kind == 0 {
+		return nil, fmt.Errorf("%s: %s has no %s", path, entry.Include, key)
+	}
+	if section.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("%s: %s: %s must be a list", path, entry.Include, key)
+	}
+	if err = renderIncludeInputs(section, values); err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", path, entry.Include, err)
+	}
+
+	steps, _, err := expandStepList(section.Content, key, entry.Include+"."+key, baseDir,
+		append(chain[:len(chain):len(chain)], fullPath))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return steps, nil
+}
+
+// loadStepFile reads and parses a step file, rejecting unknown fields
+func loadStepFile(fullPath string) (*stepFile, error) {
+	data, err := os.ReadFile(filepath.Clean(fullPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read step file %q: %w", fullPath, err)
+	}
+	var file stepFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("failed to parse step file %q: %w", fullPath, err)
+	}
+	return &file, nil
+}
+
+// includeInputs returns the input values of an include entry: the input defaults of
+// the step file overridden by with. Inputs the file does not declare are rejected, and
+// inputs without a default are required.
+func includeInputs(inputs, with map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(inputs))
+	for name, value := range inputs {
+		values[name] = value
+	}
+	var unknown, missing []string
+	for name, value := range with {
+		if _, ok := inputs[name]; !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		values[name] = value
+	}
+	for name, value := range values {
+		if value == nil {
+			missing = append(missing, name)
+		}
+	}
+	sort.Strings(unknown)
+	sort.Strings(missing)
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%s sets values not declared in %s: %s", FieldWith, FieldInputs, strings.Join(unknown, ", "))
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%s without a default are not set in %s: %s",
+			FieldInputs, FieldWith, strings.Join(missing, ", "))
+	}
+	return values, nil
+}
+
+var (
+	// includeInputRef matches a scalar that is a single input reference
+	includeInputRef = regexp.MustCompile(`^\[\[\s*\.(\w+)\s*\]\]$`)
+	// includeInputStart matches a scalar with an input reference; other uses of [[, such
+	// as CEL nested list literals, are left as written
+	includeInputStart = regexp.MustCompile(`\[\[\s*\.`)
+)
+
+// renderIncludeInputs substitutes the [[ .name ]] inputs of the scalars under node. A
+// scalar that is a single reference to an input that is not a string takes the type of
+// the value, so "[[ .replicas ]]" can set a number.
+func renderIncludeInputs(node *yaml.Node, values map[string]interface{}) error {
+	if node.Kind == yaml.ScalarNode {
+		if !includeInputStart.MatchString(node.Value) {
+			return nil
+		}
+		if m := includeInputRef.FindStringSubmatch(node.Value); m != nil {
+			if value, ok := values[m[1]]; ok {
+				if _, isString := value.(string); !isString {
+					if err := node.Encode(value); err != nil {
+						return fmt.Errorf("line %d: %w", node.Line, err)
+					}
+					return nil
+				}
+			}
+		}
+		tmpl, err := template.New("include").
+			Delims(includeLeftDelim, includeRightDelim).
+			Option("missingkey=error").
+			Parse(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: invalid input reference: %w", node.Line, err)
+		}
+		var out strings.Builder
+		if err := tmpl.Execute(&out, values); err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		node.Value = out.String()
+		return nil
+	}
+	for _, child := range node.Content {
+		if err := renderIncludeInputs(child, values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mappingValue returns the node at path in the mapping node, nil when absent
+func mappingValue(node *yaml.Node, path ...string) *yaml.Node {
+	for _, key := range path {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				next = node.Content[j+1]
+				break
+			}
+		}
+		node = next
+	}
+	return node
+}
@@ -0,0 +1,271 @@
+package configloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClusterReadySteps is a step file checking that a cluster is ready
+const testClusterReadySteps = `
+inputs:
+  name:
+  phase: "Ready"
+  maxWorkers: 10
+preconditions:
+  - name: "[[ .name ]]"
+    api_call:
+      method: "GET"
+      url: "/clusters/{{ .clusterId }}"
+    capture:
+      - name: "[[ .name ]]Phase"
+        field: "status.phase"
+      - name: "[[ .name ]]Pairs"
+        expression: "[[1, 2], [3, 4]]"
+    expression: '[[ .name ]]Phase == "[[ .phase ]]" && [[ .name ]].workers <= [[ .maxWorkers ]]'
+resources:
+  - name: "[[ .name ]]Config"
+    manifest:
+      apiVersion: v1
+      kind: ConfigMap
+      metadata:
+        name: "{{ .clusterId }}-[[ .name ]]"
+        namespace: default
+      data:
+        workers: "[[ .maxWorkers ]]"
+    discovery:
+      namespace: default
+      by_name: "{{ .clusterId }}-[[ .name ]]"
+    retry:
+      attempts: "[[ .maxWorkers ]]"
+`
+
+func writeIncludeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestLoadTaskConfig_Include(t *testing.T) {
+	dir := writeIncludeFiles(t, map[string]string{
+		"steps/cluster-ready.yaml": testClusterReadySteps,
+		"task-config.yaml": `
+params:
+  - name: "clusterId"
+    source: "event.id"
+preconditions:
+  - name: "first"
+    expression: "true"
+  - include: steps/cluster-ready.yaml
+    with:
+      name: cluster
+      maxWorkers: 3
+  - name: "last"
+    expression: "true"
+resources:
+  - include: steps/cluster-ready.yaml
+    with:
+      name: cluster
+`,
+	})
+
+	config, err := loadTaskConfig(filepath.Join(dir, "task-config.yaml"))
+
+	require.NoError(t, err)
+	require.Len(t, config.Preconditions, 3)
+	assert.Equal(t, "first", config.Preconditions[0].Name)
+	assert.Equal(t, "last", config.Preconditions[2].Name)
+	included := config.Preconditions[1]
+	assert.Equal(t, "cluster", included.Name)
+	assert.Equal(t, "/clusters/{{ .clusterId }}", included.APICall.URL, "Go templates should be kept for execution time")
+	assert.Equal(t, "clusterPhase", included.Capture[0].Name)
+	assert.Equal(t, "[[1, 2], [3, 4]]", included.Capture[1].Expression, "CEL nested lists are not input references")
+	assert.Equal(t, `clusterPhase == "Ready" && cluster.workers <= 3`, included.Expression)
+
+	require.Len(t, config.Resources, 1)
+	resource := config.Resources[0]
+	assert.Equal(t, "clusterConfig", resource.Name)
+	assert.Equal(t, "{{ .clusterId }}-cluster", resource.Discovery.ByName)
+	require.NotNil(t, resource.Retry)
+	assert.Equal(t, 10, resource.Retry.Attempts, "a whole-value reference should keep the input type")
+	data := resource.Manifest.(map[string]interface{})["data"].(map[string]interface{})
+	assert.Equal(t, 10, data["workers"])
+}
+
+func TestLoadTaskConfig_NestedInclude(t *testing.T) {
+	dir := writeIncludeFiles(t, map[string]string{
+		"steps/cluster-ready.yaml": testClusterReadySteps,
+		"steps/readiness.yaml": `
+inputs:
+  prefix:
+preconditions:
+  - include: cluster-ready.yaml
+    with:
+      name: "[[ .prefix ]]Cluster"
+  - include: cluster-ready.yaml
+    with:
+      name: "[[ .prefix ]]Parent"
+`,
+		"task-config.yaml": `
+preconditions:
+  - include: steps/readiness.yaml
+    with:
+      prefix: np
+`,
+	})
+
+	_, err := loadTaskConfig(filepath.Join(dir, "task-config.yaml"))
+	require.Error(t, err, "nested paths resolve against the task config directory")
+	assert.Contains(t, err.Error(), "cluster-ready.yaml")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "steps/readiness.yaml"), []byte(`
+inputs:
+  prefix:
+preconditions:
+  - include: steps/cluster-ready.yaml
+    with:
+      name: "[[ .prefix ]]Cluster"
+  - include: steps/cluster-ready.yaml
+    with:
+      name: "[[ .prefix ]]Parent"
+`), 0644))
+	config, err := loadTaskConfig(filepath.Join(dir, "task-config.yaml"))
+	require.NoError(t, err)
+	require.Len(t, config.Preconditions, 2)
+	assert.Equal(t, "npCluster", config.Preconditions[0].Name)
+	assert.Equal(t, "npParent", config.Preconditions[1].Name)
+}
+
+func TestLoadTaskConfig_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		files   map[string]string
+		name    string
+		wantErr string
+	}{
+		{
+			name: "missing required input",
+			files: map[string]string{"task-config.yaml": `
+preconditions:
+  - include: steps/cluster-ready.yaml
+`},
+			wantErr: "inputs without a default are not set in with: name",
+		},
+		{
+			name: "undeclared input",
+			files: map[string]string{"task-config.yaml": `
+preconditions:
+  - include: steps/cluster-ready.yaml
+    with:
+      name: cluster
+      phse: Ready
+`},
+			wantErr: "with sets values not declared in inputs: phse",
+		},
+		{
+			name: "unknown field in the include entry",
+			files: map[string]string{"task-config.yaml": `
+preconditions:
+  - include: steps/cluster-ready.yaml
+    name: cluster
+`},
+			wantErr: `preconditions[0]: unknown field "name" in include entry`,
+		},
+		{
+			name: "section missing from the step file",
+			files: map[string]string{"task-config.yaml": `
+post:
+  post_actions:
+    - include: steps/cluster-ready.yaml
+      with:
+        name: cluster
+`},
+			wantErr: "post.post_actions[0].include: steps/cluster-ready.yaml has no post_actions",
+		},
+		{
+			name: "undefined input reference",
+			files: map[string]string{
+				"steps/bad.yaml": `
+preconditions:
+  - name: "[[ .missing ]]"
+    expression: "true"
+`,
+				"task-config.yaml": `
+preconditions:
+  - include: steps/bad.yaml
+`},
+			wantErr: "line 3",
+		},
+		{
+			name: "path escaping the config directory",
+			files: map[string]string{"task-config.yaml": `
+preconditions:
+  - include: ../outside.yaml
+`},
+			wantErr: "escapes base directory",
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"steps/a.yaml": `
+preconditions:
+  - include: steps/b.yaml
+`,
+				"steps/b.yaml": `
+preconditions:
+  - include: steps/a.yaml
+`,
+				"task-config.yaml": `
+preconditions:
+  - include: steps/a.yaml
+`},
+			wantErr: "include cycle",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := map[string]string{"steps/cluster-ready.yaml": testClusterReadySteps}
+			for name, content := range tt.files {
+				files[name] = content
+			}
+			dir := writeIncludeFiles(t, files)
+
+			_, err := loadTaskConfig(filepath.Join(dir, "task-config.yaml"))
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadConfig_Include(t *testing.T) {
+	dir := writeIncludeFiles(t, map[string]string{
+		"steps/cluster-ready.yaml": testClusterReadySteps,
+		"adapter-config.yaml":      testAdapterConfigYAML,
+		"task-config.yaml": `
+params:
+  - name: "clusterId"
+    source: "event.id"
+preconditions:
+  - include: steps/cluster-ready.yaml
+    with:
+      name: cluster
+`,
+	})
+
+	config, err := LoadConfig(
+		WithAdapterConfigPath(filepath.Join(dir, "adapter-config.yaml")),
+		WithTaskConfigPath(filepath.Join(dir, "task-config.yaml")),
+	)
+
+	require.NoError(t, err)
+	require.Len(t, config.Preconditions, 1)
+	assert.Equal(t, "cluster", config.Preconditions[0].Name)
+}
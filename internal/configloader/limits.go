@@ -0,0 +1,143 @@
+package configloader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+)
+
+// Default task config limits, applied when a limit is not set (zero)
+const (
+	DefaultMaxSteps                = 200
+	DefaultMaxTemplatesPerManifest = 1000
+	DefaultMaxCaptures             = 200
+	DefaultMaxVariables            = 500
+	DefaultMaxForEachItems         = 100
+)
+
+// LimitsConfig caps the size of the task config. The limits protect the adapter from
+// pathological, usually machine-generated, configs. Zero uses the default; a negative
+// value disables the limit.
+type LimitsConfig struct {
+	// MaxSteps caps params + preconditions + resources and their teardown + prune + wait +
+	// post payloads + post actions, and at runtime also the items of for_each steps
+	// of one event
+	MaxSteps int `yaml:"max_steps,omitempty" mapstructure:"max_steps"`
+	// MaxTemplatesPerManifest caps the {{ }} actions in a single resource manifest
+	MaxTemplatesPerManifest int `yaml:"max_templates_per_manifest,omitempty" mapstructure:"max_templates_per_manifest"`
+	// MaxCaptures caps the precondition captures across all preconditions
+	MaxCaptures int `yaml:"max_captures,omitempty" mapstructure:"max_captures"`
+	// MaxVariables caps the variables defined by params, captures and post payloads, and at
+	// runtime the distinct variables (including precondition responses and ManifestWork wait
+	// statuses) set by one event
+	MaxVariables int `yaml:"max_variables,omitempty" mapstructure:"max_variables"`
+	// MaxForEachItems caps the items a single for_each step expands to at runtime
+	MaxForEachItems int `yaml:"max_for_each_items,omitempty" mapstructure:"max_for_each_items"`
+}
+
+// WithDefaults returns a copy with unset limits replaced by their defaults
+func (l LimitsConfig) WithDefaults() LimitsConfig {
+	if l.MaxSteps == 0 {
+		l.MaxSteps = DefaultMaxSteps
+	}
+	if l.MaxTemplatesPerManifest == 0 {
+		l.MaxTemplatesPerManifest = DefaultMaxTemplatesPerManifest
+	}
+	if l.MaxCaptures == 0 {
+		l.MaxCaptures = DefaultMaxCaptures
+	}
+	if l.MaxVariables == 0 {
+		l.MaxVariables = DefaultMaxVariables
+	}
+	if l.MaxForEachItems == 0 {
+		l.MaxForEachItems = DefaultMaxForEachItems
+	}
+	return l
+}
+
+// ExceedsLimit reports whether count is over limit. A zero or negative limit is unlimited,
+// so it takes the limits after WithDefaults.
+func ExceedsLimit(count, limit int) bool {
+	return limit > 0 && count > limit
+}
+
+// StepCount returns the steps of the task config counted against limits.max_steps:
+// params, preconditions, resources and their teardown api_calls, prune and wait steps,
+// post payloads and post actions. for_each steps count once; their items are counted
+// when they are expanded at runtime.
+func StepCount(config *Config) int {
+	if config == nil {
+		return 0
+	}
+	steps := len(config.Params) + len(config.Preconditions) + len(config.Resources) + teardownCount(config) +
+		len(config.Prune) + len(config.Wait)
+	if config.Post != nil {
+		steps += len(config.Post.Payloads) + len(config.Post.PostActions)
+	}
+	return steps
+}
+
+func teardownCount(config *Config) int {
+	count := 0
+	for _, r := range config.Resources {
+		if r.Lifecycle != nil && r.Lifecycle.Delete != nil {
+			count += len(r.Lifecycle.Delete.Teardown)
+		}
+	}
+	return count
+}
+
+// CheckLimits reports every limit in config.Limits that the task config exceeds
+func CheckLimits(config *Config) error {
+	if config == nil {
+		return nil
+	}
+	limits := config.Limits.WithDefaults()
+	errs := &ValidationErrors{}
+	exceeds := ExceedsLimit
+
+	captures := 0
+	for _, p := range config.Preconditions {
+		captures += len(p.Capture)
+	}
+	payloads, postActions := 0, 0
+	if config.Post != nil {
+		payloads = len(config.Post.Payloads)
+		postActions = len(config.Post.PostActions)
+	}
+
+	if steps := StepCount(config); exceeds(steps, limits.MaxSteps) {
+		errs.Add("limits.max_steps", fmt.Sprintf(
+			"task config has %d steps (%d params, %d preconditions, %d resources, %d teardown, %d prune, "+
+				"%d wait, %d payloads, %d post actions), limit is %d",
+			steps, len(config.Params), len(config.Preconditions), len(config.Resources), teardownCount(config),
+			len(config.Prune), len(config.Wait), payloads, postActions, limits.MaxSteps))
+	}
+	if exceeds(captures, limits.MaxCaptures) {
+		errs.Add("limits.max_captures", fmt.Sprintf(
+			"task config has %d precondition captures, limit is %d", captures, limits.MaxCaptures))
+	}
+	if variables := len(config.Params) + captures + payloads; exceeds(variables, limits.MaxVariables) {
+		errs.Add("limits.max_variables", fmt.Sprintf(
+			"task config defines %d variables (params, captures and payloads), limit is %d",
+			variables, limits.MaxVariables))
+	}
+
+	for i, r := range config.Resources {
+		manifestStr, err := manifest.ToYAMLString(r.Manifest)
+		if err != nil || r.Manifest == nil {
+			continue
+		}
+		if count := strings.Count(manifestStr, "{{"); exceeds(count, limits.MaxTemplatesPerManifest) {
+			errs.Add(fmt.Sprintf("%s[%d].%s", FieldResources, i, FieldManifest), fmt.Sprintf(
+				"manifest has %d template actions, limit (limits.max_templates_per_manifest) is %d",
+				count, limits.MaxTemplatesPerManifest))
+		}
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
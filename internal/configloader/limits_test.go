@@ -0,0 +1,85 @@
+package configloader
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckLimits(t *testing.T) {
+	params := func(n int) []Parameter {
+		out := make([]Parameter, n)
+		for i := range out {
+			out[i] = Parameter{Name: fmt.Sprintf("p%d", i), Source: StringSource("event.id")}
+		}
+		return out
+	}
+
+	t.Run("within defaults", func(t *testing.T) {
+		config := &Config{
+			Params: params(10),
+			Resources: []Resource{{
+				Name:     "cm",
+				Manifest: map[string]interface{}{"metadata": map[string]interface{}{"name": "{{ .p0 }}"}},
+			}},
+		}
+		assert.NoError(t, CheckLimits(config))
+	})
+
+	t.Run("reports every exceeded limit", func(t *testing.T) {
+		manifest := "data:\n" + strings.Repeat("  k: '{{ .p0 }}'\n", 4)
+		config := &Config{
+			Limits: LimitsConfig{MaxSteps: 3, MaxTemplatesPerManifest: 3, MaxCaptures: 1, MaxVariables: 4},
+			Params: params(2),
+			Preconditions: []Precondition{{
+				ActionBase: ActionBase{Name: "check"},
+				Capture:    []CaptureField{{Name: "a"}, {Name: "b"}},
+			}},
+			Resources: []Resource{{Name: "cm", Manifest: manifest}},
+			Post:      &PostConfig{Payloads: []Payload{{Name: "status"}}},
+		}
+
+		err := CheckLimits(config)
+		require.Error(t, err)
+		var errs *ValidationErrors
+		require.ErrorAs(t, err, &errs)
+		require.Len(t, errs.Errors, 4)
+		assert.Contains(t, err.Error(), "limits.max_steps")
+		assert.Contains(t, err.Error(), "5 steps")
+		assert.Contains(t, err.Error(), "limits.max_captures")
+		assert.Contains(t, err.Error(), "limits.max_variables")
+		assert.Contains(t, err.Error(), "resources[0].manifest: manifest has 4 template actions")
+	})
+
+	t.Run("teardown api_calls count as steps", func(t *testing.T) {
+		config := &Config{
+			Limits: LimitsConfig{MaxSteps: 2},
+			Resources: []Resource{{
+				Name: "cm",
+				Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{
+					Finalizer: "example.com/cleanup",
+					Teardown:  []ActionBase{{Name: "deregister"}, {Name: "notify"}},
+				}},
+			}},
+		}
+		err := CheckLimits(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "3 steps (0 params, 0 preconditions, 1 resources, 2 teardown")
+		assert.Equal(t, 3, StepCount(config))
+	})
+
+	t.Run("negative disables a limit", func(t *testing.T) {
+		config := &Config{
+			Limits: LimitsConfig{MaxSteps: -1},
+			Params: params(DefaultMaxSteps + 1),
+		}
+		assert.NoError(t, CheckLimits(config))
+	})
+
+	t.Run("nil config", func(t *testing.T) {
+		assert.NoError(t, CheckLimits(nil))
+	})
+}
@@ -38,6 +38,9 @@ type loadOptions struct {
 	adapterConfigPath      string
 	taskConfigPath         string
 	adapterVersion         string
+	ociCacheDir            string
+	ociVerifyKeyPath       string
+	policyBundlePath       string
 	skipSemanticValidation bool
 }
 
@@ -55,6 +58,27 @@ func WithTaskConfigPath(path string) LoadOption {
 	}
 }
 
+// WithOCICacheDir sets the directory used to cache task config artifacts pulled from oci:// references
+func WithOCICacheDir(dir string) LoadOption {
+	return func(o *loadOptions) {
+		o.ociCacheDir = dir
+	}
+}
+
+// WithOCIVerifyKey sets the cosign public key used to verify task config artifacts
+func WithOCIVerifyKey(path string) LoadOption {
+	return func(o *loadOptions) {
+		o.ociVerifyKeyPath = path
+	}
+}
+
+// WithPolicyBundle sets the policy file or directory evaluated against the merged config
+func WithPolicyBundle(path string) LoadOption {
+	return func(o *loadOptions) {
+		o.policyBundlePath = path
+	}
+}
+
 // WithFlags sets the CLI flags for Viper binding
 func WithFlags(flags interface{}) LoadOption {
 	return func(o *loadOptions) {
@@ -140,16 +164,31 @@ func LoadConfig(opts ...LoadOption) (*Config, error) {
 	}
 
 	// 2. Load AdapterTaskConfig from YAML (no env binding)
-	taskCfg, err := loadTaskConfig(o.taskConfigPath)
+	taskConfigPath := o.taskConfigPath
+	if taskConfigPath == "" {
+		taskConfigPath = os.Getenv(EnvTaskConfigPath)
+	}
+
+	// Pull oci:// references into the local cache; file references then resolve
+	// relative to the extracted artifact
+	if IsOCIReference(taskConfigPath) {
+		var puller *ociPuller
+		puller, err = newOCIPuller(o.ociCacheDir, o.ociVerifyKeyPath, o.logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure task config pull: %w", err)
+		}
+		taskConfigPath, err = puller.Pull(o.ctx, taskConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pull task config: %w", err)
+		}
+	}
+
+	taskCfg, err := loadTaskConfig(taskConfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load task config: %w", err)
 	}
 
 	// Get base directory from task config path
-	taskConfigPath := o.taskConfigPath
-	if taskConfigPath == "" {
-		taskConfigPath = os.Getenv(EnvTaskConfigPath)
-	}
 	taskBaseDir := ""
 	if taskConfigPath != "" {
 		var errBaseDir error
@@ -176,6 +215,11 @@ func LoadConfig(opts ...LoadOption) (*Config, error) {
 		}
 	}
 
+	// Load the charts of helm resources, pulling oci:// charts into the cache
+	if err := loadHelmCharts(o, taskCfg, taskBaseDir); err != nil {
+		return nil, fmt.Errorf("failed to load helm charts: %w", err)
+	}
+
 	// Semantic validation for task config (optional)
 	if !o.skipSemanticValidation {
 		if err := taskValidator.ValidateSemantic(); err != nil {
@@ -191,6 +235,34 @@ func LoadConfig(opts ...LoadOption) (*Config, error) {
 	if config == nil {
 		return nil, fmt.Errorf("failed to merge configurations")
 	}
+	if err := CheckLimits(config); err != nil {
+		return nil, fmt.Errorf("task config exceeds limits: %w", err)
+	}
+	envWarnings, envErr := CheckEnvVars(config)
+	if envErr != nil {
+		return nil, fmt.Errorf("missing environment variables: %w", envErr)
+	}
+	for _, w := range envWarnings {
+		o.logger.Warn(o.ctx, w)
+	}
+	transportWarnings, transportErr := CheckTransportClients(config)
+	if transportErr != nil {
+		return nil, fmt.Errorf("task config transports do not match the configured clients: %w", transportErr)
+	}
+	for _, w := range transportWarnings {
+		o.logger.Warn(o.ctx, w)
+	}
+
+	// 4. Enforce organization policies (optional); violations block startup
+	policyBundlePath := o.policyBundlePath
+	if policyBundlePath == "" {
+		policyBundlePath = os.Getenv(EnvPolicyBundle)
+	}
+	if policyBundlePath != "" {
+		if err := enforcePolicies(o.ctx, o.logger, policyBundlePath, config); err != nil {
+			return nil, fmt.Errorf("config policy check failed: %w", err)
+		}
+	}
 
 	return config, nil
 }
@@ -201,7 +273,7 @@ func LoadConfig(opts ...LoadOption) (*Config, error) {
 
 // loadTaskConfigFileReferences loads content from file references into the task config
 func loadTaskConfigFileReferences(config *AdapterTaskConfig, baseDir string) error {
-	// Load manifest.ref in resources as raw strings to support Go template syntax.
+	// Load manifest_ref and manifest.ref in resources as raw strings to support Go template syntax.
 	// Files are stored as raw strings so that structural Go templates ({{ if }}, {{ range }}, etc.)
 	// are preserved and rendered at execution time before YAML parsing.
 	for i := range config.Resources {
@@ -213,7 +285,7 @@ func loadTaskConfigFileReferences(config *AdapterTaskConfig, baseDir string) err
 
 		content, err := loadRawFile(baseDir, ref)
 		if err != nil {
-			return fmt.Errorf("%s[%d].%s.%s: %w", FieldResources, i, FieldManifest, FieldRef, err)
+			return fmt.Errorf("%s[%d].%s: %w", FieldResources, i, manifestRefPath(resource), err)
 		}
 
 		// Replace manifest with raw string content for template rendering at execution time
@@ -237,6 +309,14 @@ func loadTaskConfigFileReferences(config *AdapterTaskConfig, baseDir string) err
 	return nil
 }
 
+// manifestRefPath returns the config path of the manifest file reference of a resource
+func manifestRefPath(resource *Resource) string {
+	if resource.ManifestRef != "" {
+		return FieldManifestRef
+	}
+	return FieldManifest + "." + FieldRef
+}
+
 // loadRawFile reads a file and returns its content as a raw string.
 // Used for manifest ref files to preserve Go template syntax for later rendering.
 func loadRawFile(baseDir, refPath string) (string, error) {
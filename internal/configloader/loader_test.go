@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -27,6 +28,13 @@ clients:
     api_version: "v1"
 `
 
+// testMaestroAdapterConfigYAML is testAdapterConfigYAML with a Maestro client
+const testMaestroAdapterConfigYAML = testAdapterConfigYAML + `  maestro:
+    grpc_server_address: "maestro-grpc.example.com:8090"
+    http_server_address: "https://maestro.example.com"
+    source_id: "test-adapter"
+`
+
 // createTestConfigFiles creates temporary adapter and task config files for testing
 func createTestConfigFiles(t *testing.T, tmpDir string, adapterYAML, taskYAML string) (adapterPath, taskPath string) {
 	t.Helper()
@@ -100,7 +108,8 @@ resources:
 	assert.Equal(t, "0.1.0", config.Adapter.Version)
 	// Clients config comes from adapter config
 	assert.Equal(t, "https://test.example.com", config.Clients.HyperfleetAPI.BaseURL)
-	assert.Equal(t, 2*time.Second, config.Clients.HyperfleetAPI.Timeout)
+	assert.Equal(t, Duration(2*time.Second), config.Clients.HyperfleetAPI.Timeout)
+	assert.Nil(t, config.Clients.Vault, "optional clients stay unset when not configured")
 	// Task fields come from task config
 	require.Len(t, config.Params, 1)
 	assert.Equal(t, "clusterId", config.Params[0].Name)
@@ -440,7 +449,7 @@ func TestMergeConfigs(t *testing.T) {
 		Clients: ClientsConfig{
 			HyperfleetAPI: HyperfleetAPIConfig{
 				BaseURL:       "https://api.example.com",
-				Timeout:       5 * time.Second,
+				Timeout:       Duration(5 * time.Second),
 				RetryAttempts: 3,
 			},
 			Kubernetes: KubernetesConfig{
@@ -469,7 +478,7 @@ func TestMergeConfigs(t *testing.T) {
 	assert.Equal(t, "1.0.0", merged.Adapter.Version)
 	// Clients from adapter config
 	assert.Equal(t, "https://api.example.com", merged.Clients.HyperfleetAPI.BaseURL)
-	assert.Equal(t, 5*time.Second, merged.Clients.HyperfleetAPI.Timeout)
+	assert.Equal(t, Duration(5*time.Second), merged.Clients.HyperfleetAPI.Timeout)
 	// Task fields from task config
 	require.Len(t, merged.Params, 1)
 	assert.Equal(t, "clusterId", merged.Params[0].Name)
@@ -1271,8 +1280,8 @@ resources:
 
 func TestGetTransportClient(t *testing.T) {
 	tests := []struct {
-		name     string
 		want     string
+		name     string
 		resource Resource
 	}{
 		{
@@ -1349,7 +1358,7 @@ spec:
     manifests: []
 `), 0644))
 
-	adapterYAML := testAdapterConfigYAML
+	adapterYAML := testMaestroAdapterConfigYAML
 
 	taskYAML := `
 params:
@@ -1424,7 +1433,7 @@ resources:
 func TestLoadConfigWithInlineManifestWork(t *testing.T) {
 	tmpDir := t.TempDir()
 
-	adapterYAML := testAdapterConfigYAML
+	adapterYAML := testMaestroAdapterConfigYAML
 
 	taskYAML := `
 params:
@@ -1688,6 +1697,73 @@ resources:
 	assert.Contains(t, manifestStr, "name: \"my-config\"")
 }
 
+func TestLoadConfigManifestRefField(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(tmpDir, "manifests"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "manifests", "app.yaml"), []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "app-{{ .clusterId }}"
+  namespace: "default"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: "app-{{ .clusterId }}"
+  namespace: "default"
+`), 0644))
+
+	adapterPath := filepath.Join(tmpDir, "adapter-config.yaml")
+	require.NoError(t, os.WriteFile(adapterPath, []byte(testAdapterConfigYAML), 0644))
+
+	writeTask := func(resourceYAML string) string {
+		taskPath := filepath.Join(tmpDir, "task-config.yaml")
+		require.NoError(t, os.WriteFile(taskPath, []byte(`
+params:
+  - name: "clusterId"
+    source: "event.id"
+resources:
+  - name: "app"
+`+resourceYAML+`
+    discovery:
+      namespace: "default"
+      by_name: "app"
+`), 0644))
+		return taskPath
+	}
+
+	config, err := LoadConfig(
+		WithAdapterConfigPath(adapterPath),
+		WithTaskConfigPath(writeTask(`    manifest_ref: "manifests/app.yaml"`)),
+		WithSkipSemanticValidation(),
+	)
+	require.NoError(t, err)
+	require.Len(t, config.Resources, 1)
+	manifestStr, ok := config.Resources[0].Manifest.(string)
+	require.True(t, ok, "manifest_ref should be loaded as a raw string")
+	assert.Contains(t, manifestStr, "kind: Secret", "every document should be kept")
+	assert.Equal(t, "manifests/app.yaml", config.Resources[0].GetManifestRef())
+
+	_, err = LoadConfig(
+		WithAdapterConfigPath(adapterPath),
+		WithTaskConfigPath(writeTask(`    manifest_ref: "manifests/missing.yaml"`)),
+		WithSkipSemanticValidation(),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resources[0].manifest_ref")
+
+	_, err = LoadConfig(
+		WithAdapterConfigPath(adapterPath),
+		WithTaskConfigPath(writeTask(`    manifest_ref: "manifests/app.yaml"
+    manifest:
+      apiVersion: v1
+      kind: ConfigMap`)),
+		WithSkipSemanticValidation(),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "manifest and manifest_ref are mutually exclusive")
+}
+
 func TestParameterSourceUnmarshal(t *testing.T) {
 	t.Run("string scalar source", func(t *testing.T) {
 		var cfg AdapterTaskConfig
@@ -1797,3 +1873,160 @@ params:
 		assert.Contains(t, err.Error(), "only one of")
 	})
 }
+
+func TestLoadConfig_HyperfleetAPIProfiles(t *testing.T) {
+	adapterYAML := `
+adapter:
+  name: test-adapter
+clients:
+  hyperfleet_api:
+    base_url: "https://api.dev.example.com"
+    timeout: 2s
+    default_headers:
+      X-Team: platform
+    profile: dev
+    profiles:
+      dev:
+        base_url: "https://api.dev.example.com"
+      prod:
+        base_url: "https://api.example.com"
+        default_headers:
+          X-Env: prod
+        auth:
+          token_path: /var/run/secrets/prod/token
+  kubernetes:
+    api_version: "v1"
+`
+	adapterPath, taskPath := createTestConfigFiles(t, t.TempDir(), adapterYAML, "params: []\n")
+	load := func(flags *pflag.FlagSet) (*Config, error) {
+		return LoadConfig(
+			WithAdapterConfigPath(adapterPath),
+			WithTaskConfigPath(taskPath),
+			WithSkipSemanticValidation(),
+			WithFlags(flags),
+		)
+	}
+
+	t.Run("profile from config file", func(t *testing.T) {
+		config, err := load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "dev", config.Clients.HyperfleetAPI.Profile)
+		assert.Equal(t, "https://api.dev.example.com", config.Clients.HyperfleetAPI.BaseURL)
+		assert.Nil(t, config.Clients.HyperfleetAPI.Auth)
+	})
+
+	t.Run("env selects profile", func(t *testing.T) {
+		t.Setenv("HYPERFLEET_API_PROFILE", "prod")
+		config, err := load(nil)
+		require.NoError(t, err)
+		api := config.Clients.HyperfleetAPI
+		assert.Equal(t, "prod", api.Profile)
+		assert.Equal(t, "https://api.example.com", api.BaseURL)
+		assert.Equal(t, Duration(2*time.Second), api.Timeout)
+		assert.Equal(t, map[string]string{"x-team": "platform", "x-env": "prod"}, api.DefaultHeaders)
+		require.NotNil(t, api.Auth)
+		assert.Equal(t, "/var/run/secrets/prod/token", api.Auth.TokenPath)
+	})
+
+	t.Run("explicit env overrides win over profile", func(t *testing.T) {
+		t.Setenv("HYPERFLEET_API_PROFILE", "prod")
+		t.Setenv("HYPERFLEET_API_BASE_URL", "https://override.example.com")
+		t.Setenv("HYPERFLEET_API_AUTH_TOKEN_PATH", "/tmp/override-token")
+		config, err := load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, "https://override.example.com", config.Clients.HyperfleetAPI.BaseURL)
+		assert.Equal(t, "/tmp/override-token", config.Clients.HyperfleetAPI.Auth.TokenPath)
+	})
+
+	t.Run("flag wins over env", func(t *testing.T) {
+		t.Setenv("HYPERFLEET_API_PROFILE", "prod")
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("hyperfleet-api-profile", "", "")
+		require.NoError(t, flags.Set("hyperfleet-api-profile", "dev"))
+		config, err := load(flags)
+		require.NoError(t, err)
+		assert.Equal(t, "dev", config.Clients.HyperfleetAPI.Profile)
+		assert.Equal(t, "https://api.dev.example.com", config.Clients.HyperfleetAPI.BaseURL)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		t.Setenv("HYPERFLEET_API_PROFILE", "qa")
+		_, err := load(nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `unknown HyperFleet API profile "qa" (defined: dev, prod)`)
+	})
+}
+
+func TestLoadConfig_HyperfleetAPICompression(t *testing.T) {
+	adapterYAML := `
+adapter:
+  name: test-adapter
+clients:
+  hyperfleet_api:
+    base_url: "https://api.example.com"
+    compression:
+      min_bytes: 1024
+  kubernetes:
+    api_version: "v1"
+`
+	adapterPath, taskPath := createTestConfigFiles(t, t.TempDir(), adapterYAML, "params: []\n")
+	load := func() (*Config, error) {
+		return LoadConfig(
+			WithAdapterConfigPath(adapterPath),
+			WithTaskConfigPath(taskPath),
+			WithSkipSemanticValidation(),
+		)
+	}
+
+	t.Run("from config file", func(t *testing.T) {
+		config, err := load()
+		require.NoError(t, err)
+		assert.Equal(t, HyperfleetAPICompressionConfig{MinBytes: 1024}, config.Clients.HyperfleetAPI.Compression)
+	})
+
+	t.Run("env overrides", func(t *testing.T) {
+		t.Setenv("HYPERFLEET_API_COMPRESSION_DISABLED", "true")
+		t.Setenv("HYPERFLEET_API_COMPRESSION_MIN_BYTES", "2048")
+		config, err := load()
+		require.NoError(t, err)
+		assert.Equal(t, HyperfleetAPICompressionConfig{Disabled: true, MinBytes: 2048},
+			config.Clients.HyperfleetAPI.Compression)
+	})
+}
+
+func TestLoadConfig_ShardingOverrides(t *testing.T) {
+	adapterYAML := `
+adapter:
+  name: test-adapter
+clients:
+  kubernetes:
+    api_version: "v1"
+sharding:
+  enabled: true
+  refresh_interval: 1m
+`
+	adapterPath, taskPath := createTestConfigFiles(t, t.TempDir(), adapterYAML, "params: []\n")
+	t.Setenv("HYPERFLEET_SHARDING_COUNT", "3")
+	t.Setenv("HYPERFLEET_SHARDING_STATEFULSET", "adapter")
+
+	config, err := LoadConfig(
+		WithAdapterConfigPath(adapterPath),
+		WithTaskConfigPath(taskPath),
+		WithSkipSemanticValidation(),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ShardingConfig{
+		Enabled:         true,
+		Count:           3,
+		StatefulSet:     "adapter",
+		RefreshInterval: Duration(time.Minute),
+	}, config.Sharding)
+
+	t.Setenv("HYPERFLEET_SHARDING_REFRESH_INTERVAL", "-1s")
+	_, err = LoadConfig(
+		WithAdapterConfigPath(adapterPath),
+		WithTaskConfigPath(taskPath),
+		WithSkipSemanticValidation(),
+	)
+	assert.ErrorContains(t, err, "sharding.refresh_interval must not be negative")
+}
@@ -0,0 +1,775 @@
+package configloader
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// OCI task config constants
+const (
+	// OCIReferencePrefix marks a task config path as an OCI artifact reference
+	OCIReferencePrefix = "oci://"
+
+	// OCITaskConfigMediaType is the preferred layer media type for the task config YAML.
+	// Artifacts with a single layer may use any media type.
+	OCITaskConfigMediaType = "application/vnd.hyperfleet.adapter.task-config.v1+yaml"
+
+	// OCIHelmChartMediaType is the layer media type of a Helm chart pushed with `helm push`
+	OCIHelmChartMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+	// Environment variables for OCI task config pulls
+	EnvTaskConfigCacheDir         = "HYPERFLEET_TASK_CONFIG_CACHE_DIR"
+	EnvTaskConfigVerifyKey        = "HYPERFLEET_TASK_CONFIG_VERIFY_KEY"
+	EnvTaskConfigRegistryUsername = "HYPERFLEET_TASK_CONFIG_REGISTRY_USERNAME"
+	EnvTaskConfigRegistryPassword = "HYPERFLEET_TASK_CONFIG_REGISTRY_PASSWORD" //nolint:gosec // env var name
+
+	defaultOCITaskConfigFileName = "task-config.yaml"
+	ociHelmChartFileName         = "chart.tgz"
+	ociTitleAnnotation           = "org.opencontainers.image.title"
+	cosignSignatureAnnotation    = "dev.cosignproject.cosign/signature"
+	ociPullTimeout               = 60 * time.Second
+	ociMaxBlobSize               = 16 << 20
+
+	// Verification records kept next to the extracted layers. Layer files never start
+	// with a dot, so these names cannot collide with them.
+	ociCachedManifestFile  = ".manifest.json"
+	ociCachedSignatureFile = ".signature.json"
+)
+
+// ociArtifact describes a kind of artifact the puller extracts
+type ociArtifact struct {
+	// name is used in messages
+	name string
+	// mediaType selects the main layer, which is stored as fileName
+	mediaType string
+	fileName  string
+	// titledLayers extracts the other layers with a title annotation too
+	titledLayers bool
+}
+
+var (
+	taskConfigArtifact = ociArtifact{
+		name:         "task config",
+		mediaType:    OCITaskConfigMediaType,
+		fileName:     defaultOCITaskConfigFileName,
+		titledLayers: true,
+	}
+	helmChartArtifact = ociArtifact{
+		name:      "chart",
+		mediaType: OCIHelmChartMediaType,
+		fileName:  ociHelmChartFileName,
+	}
+)
+
+// errOCIRegistryUnavailable marks the pull errors that leave the artifact unknown: the
+// registry could not be reached or failed to answer. Only these fall back to the cached
+// copy of a tag; an artifact that fails verification is never replaced by a stale copy.
+var errOCIRegistryUnavailable = errors.New("registry unavailable")
+
+// errOCINotCached marks a digest with no cached copy, as opposed to a cached copy that fails verification
+var errOCINotCached = errors.New("not cached")
+
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// IsOCIReference reports whether the task config path points at an OCI artifact.
+func IsOCIReference(path string) bool {
+	return strings.HasPrefix(path, OCIReferencePrefix)
+}
+
+// ociReference is a parsed oci://registry/repository[:tag|@digest] reference.
+type ociReference struct {
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseOCIReference parses an oci:// task config reference. The tag defaults to "latest".
+func parseOCIReference(ref string) (*ociReference, error) {
+	rest := strings.TrimPrefix(ref, OCIReferencePrefix)
+	slash := strings.Index(rest, "/")
+	if slash <= 0 || slash == len(rest)-1 {
+		return nil, fmt.Errorf("invalid OCI reference %q: expected oci://registry/repository[:tag|@digest]", ref)
+	}
+
+	parsed := &ociReference{Registry: rest[:slash]}
+	repo := rest[slash+1:]
+
+	if at := strings.Index(repo, "@"); at >= 0 {
+		parsed.Digest = repo[at+1:]
+		repo = repo[:at]
+		if !strings.HasPrefix(parsed.Digest, "sha256:") || len(parsed.Digest) != len("sha256:")+64 {
+			return nil, fmt.Errorf("invalid OCI reference %q: only sha256 digests are supported", ref)
+		}
+	} else if colon := strings.LastIndex(repo, ":"); colon > strings.LastIndex(repo, "/") {
+		parsed.Tag = repo[colon+1:]
+		repo = repo[:colon]
+	}
+
+	if repo == "" {
+		return nil, fmt.Errorf("invalid OCI reference %q: repository is empty", ref)
+	}
+	if parsed.Tag == "" && parsed.Digest == "" {
+		parsed.Tag = "latest"
+	}
+	parsed.Repository = repo
+	return parsed, nil
+}
+
+// reference returns the tag or digest used to fetch the manifest.
+func (r *ociReference) reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// String returns the reference without the oci:// prefix.
+func (r *ociReference) String() string {
+	if r.Digest != "" {
+		return fmt.Sprintf("%s/%s@%s", r.Registry, r.Repository, r.Digest)
+	}
+	return fmt.Sprintf("%s/%s:%s", r.Registry, r.Repository, r.Tag)
+}
+
+type ociDescriptor struct {
+	Annotations map[string]string `json:"annotations,omitempty"`
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+}
+
+type ociManifest struct {
+	MediaType string          `json:"mediaType"`
+	Layers    []ociDescriptor `json:"layers"`
+}
+
+// ociSignature is a cosign signature that was verified against the configured key.
+// It is stored next to the cached layers so a cache hit can be verified again offline.
+type ociSignature struct {
+	Signature string `json:"signature"`
+	Payload   []byte `json:"payload"`
+}
+
+// ociToken holds the registry bearer token, shared by the copies made by withArtifact
+type ociToken struct {
+	value string
+	mu    sync.Mutex
+}
+
+func (t *ociToken) get() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.value
+}
+
+func (t *ociToken) set(value string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.value = value
+}
+
+// ociPuller pulls task config artifacts from an OCI registry using the
+// distribution HTTP API, verifying blob digests and (optionally) a cosign signature.
+// Pulled artifacts are cached on disk by manifest digest so restarts do not
+// depend on registry availability. Cached copies are verified again before use.
+type ociPuller struct {
+	client    *http.Client
+	log       logger.Logger
+	publicKey crypto.PublicKey
+	username  string
+	password  string
+	scheme    string
+	token     *ociToken
+	cacheDir  string
+	artifact  ociArtifact
+}
+
+// newOCIPuller creates a puller. verifyKeyPath is optional; when set, the
+// artifact must carry a valid cosign signature made with that key, and the cache
+// dir must be set explicitly to a directory other users cannot write to.
+func newOCIPuller(cacheDir, verifyKeyPath string, log logger.Logger) (*ociPuller, error) {
+	if cacheDir == "" {
+		cacheDir = os.Getenv(EnvTaskConfigCacheDir)
+	}
+	if verifyKeyPath == "" {
+		verifyKeyPath = os.Getenv(EnvTaskConfigVerifyKey)
+	}
+	if cacheDir == "" {
+		if verifyKeyPath != "" {
+			return nil, fmt.Errorf("a task config cache dir (%s) is required when a verify key is set",
+				EnvTaskConfigCacheDir)
+		}
+		cacheDir = filepath.Join(os.TempDir(), "hyperfleet-task-config")
+	} else if verifyKeyPath != "" {
+		if err := checkPrivateDir(cacheDir); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &ociPuller{
+		client:   &http.Client{Timeout: ociPullTimeout},
+		log:      log,
+		cacheDir: cacheDir,
+		username: os.Getenv(EnvTaskConfigRegistryUsername),
+		password: os.Getenv(EnvTaskConfigRegistryPassword),
+		scheme:   "https",
+		artifact: taskConfigArtifact,
+		token:    &ociToken{},
+	}
+
+	if verifyKeyPath != "" {
+		key, err := loadCosignPublicKey(verifyKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		p.publicKey = key
+	}
+
+	return p, nil
+}
+
+// withArtifact returns a copy of the puller that extracts another kind of artifact
+func (p *ociPuller) withArtifact(artifact ociArtifact) *ociPuller {
+	copied := *p
+	copied.artifact = artifact
+	return &copied
+}
+
+// Pull resolves the reference, downloads and verifies the artifact, and returns
+// the local path of the task config file, or of the main layer of other artifacts. If the registry is unreachable and a
+// previously pulled copy of a tag reference exists in the cache, the cached copy is used. Signature and digest
+// verification failures are returned without falling back.
+func (p *ociPuller) Pull(ctx context.Context, rawRef string) (string, error) {
+	ref, err := parseOCIReference(rawRef)
+	if err != nil {
+		return "", err
+	}
+
+	// Digest references are immutable, so a verified cache hit never needs the registry
+	if ref.Digest != "" {
+		path, cacheErr := p.verifiedCachedPath(ref.Digest, true)
+		if cacheErr == nil {
+			p.log.Debugf(ctx, "Using cached %s artifact %s", p.artifact.name, ref)
+			return path, nil
+		}
+		if !errors.Is(cacheErr, errOCINotCached) {
+			errCtx := logger.WithErrorField(ctx, cacheErr)
+			p.log.Warnf(errCtx, "Cached %s artifact %s failed verification, pulling it again", p.artifact.name, ref)
+		}
+	}
+
+	path, err := p.pull(ctx, ref)
+	if err == nil {
+		return path, nil
+	}
+	if !errors.Is(err, errOCIRegistryUnavailable) {
+		return "", err
+	}
+
+	if digest, ok := p.readRefIndex(ref); ok {
+		cached, cacheErr := p.verifiedCachedPath(digest, true)
+		if cacheErr == nil {
+			errCtx := logger.WithErrorField(ctx, err)
+			p.log.Warnf(errCtx, "Failed to pull %s artifact %s, using cached copy %s", p.artifact.name, ref, digest)
+			return cached, nil
+		}
+		if !errors.Is(cacheErr, errOCINotCached) {
+			return "", fmt.Errorf("%w; cached copy %s cannot be used: %w", err, digest, cacheErr)
+		}
+	}
+	return "", err
+}
+
+func (p *ociPuller) pull(ctx context.Context, ref *ociReference) (string, error) {
+	manifestBytes, err := p.fetch(ctx, ref, "manifests/"+ref.reference(), strings.Join(ociManifestMediaTypes, ", "))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch manifest for %s: %w", ref, err)
+	}
+	manifestDigest := sha256Digest(manifestBytes)
+	if ref.Digest != "" && ref.Digest != manifestDigest {
+		return "", fmt.Errorf("manifest digest mismatch for %s: got %s", ref, manifestDigest)
+	}
+
+	var signature *ociSignature
+	if p.publicKey != nil {
+		signature, err = p.verifySignature(ctx, ref, manifestDigest)
+		if err != nil {
+			return "", fmt.Errorf("signature verification failed for %s: %w", ref, err)
+		}
+	}
+
+	// The signature was just checked against the registry, so the cached copy only
+	// needs its layers re-hashed; its stored signature is refreshed
+	if path, cacheErr := p.verifiedCachedPath(manifestDigest, false); cacheErr == nil {
+		if signature != nil {
+			sigPath := filepath.Join(p.digestDir(manifestDigest), ociCachedSignatureFile)
+			if err = writeJSONFile(sigPath, signature); err != nil {
+				return "", fmt.Errorf("failed to cache signature for %s: %w", ref, err)
+			}
+		}
+		p.writeRefIndex(ctx, ref, manifestDigest)
+		return path, nil
+	} else if !errors.Is(cacheErr, errOCINotCached) {
+		errCtx := logger.WithErrorField(ctx, cacheErr)
+		p.log.Warnf(errCtx, "Cached %s artifact %s failed verification, replacing it", p.artifact.name, ref)
+		if err = os.RemoveAll(p.digestDir(manifestDigest)); err != nil {
+			return "", fmt.Errorf("failed to remove corrupt %s cache for %s: %w", p.artifact.name, ref, err)
+		}
+	}
+
+	var manifest ociManifest
+	if err = json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse manifest for %s: %w", ref, err)
+	}
+	mainLayer, err := selectLayer(manifest.Layers, p.artifact.mediaType)
+	if err != nil {
+		return "", fmt.Errorf("artifact %s: %w", ref, err)
+	}
+
+	// Extract into a staging directory and rename atomically so a partial pull
+	// never looks like a cache hit
+	if err = os.MkdirAll(p.cacheDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s cache dir %q: %w", p.artifact.name, p.cacheDir, err)
+	}
+	stagingDir, err := os.MkdirTemp(p.cacheDir, ".pull-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging dir in %q: %w", p.cacheDir, err)
+	}
+	defer os.RemoveAll(stagingDir) //nolint:errcheck // best-effort cleanup
+
+	for _, layer := range manifest.Layers {
+		name := p.layerFileName(layer, mainLayer)
+		if name == "" {
+			continue
+		}
+		blob, err := p.fetchBlob(ctx, ref, layer)
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(stagingDir, name), blob, 0o600); err != nil {
+			return "", fmt.Errorf("failed to write %q to cache: %w", name, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(stagingDir, ociCachedManifestFile), manifestBytes, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write manifest to cache: %w", err)
+	}
+	if signature != nil {
+		if err := writeJSONFile(filepath.Join(stagingDir, ociCachedSignatureFile), signature); err != nil {
+			return "", fmt.Errorf("failed to write signature to cache: %w", err)
+		}
+	}
+
+	finalDir := p.digestDir(manifestDigest)
+	if err := os.Rename(stagingDir, finalDir); err != nil && !dirExists(finalDir) {
+		return "", fmt.Errorf("failed to populate %s cache %q: %w", p.artifact.name, finalDir, err)
+	}
+	p.writeRefIndex(ctx, ref, manifestDigest)
+
+	p.log.Infof(ctx, "Pulled %s artifact %s (%s)", p.artifact.name, ref, manifestDigest)
+	path, _ := p.cachedPath(manifestDigest)
+	return path, nil
+}
+
+// selectLayer picks the main layer of an artifact: the layer with mediaType, or the
+// only layer when the artifact has just one.
+func selectLayer(layers []ociDescriptor, mediaType string) (*ociDescriptor, error) {
+	for i := range layers {
+		if layers[i].MediaType == mediaType {
+			return &layers[i], nil
+		}
+	}
+	if len(layers) == 1 {
+		return &layers[0], nil
+	}
+	return nil, fmt.Errorf("no layer with media type %s found among %d layers", mediaType, len(layers))
+}
+
+// layerFileName returns the cache file name for a layer. The main layer is always
+// stored under the artifact's file name (task-config.yaml for task configs); other
+// layers of a task config are extracted only when they carry a title annotation, so
+// they can be used as manifest file references.
+func (p *ociPuller) layerFileName(layer ociDescriptor, mainLayer *ociDescriptor) string {
+	if layer.Digest == mainLayer.Digest {
+		return p.artifact.fileName
+	}
+	if !p.artifact.titledLayers {
+		return ""
+	}
+	title := filepath.Base(layer.Annotations[ociTitleAnnotation])
+	if title == "." || title == "/" || title == p.artifact.fileName || strings.HasPrefix(title, ".") {
+		return ""
+	}
+	return title
+}
+
+func (p *ociPuller) fetchBlob(ctx context.Context, ref *ociReference, layer ociDescriptor) ([]byte, error) {
+	if layer.Size > ociMaxBlobSize {
+		return nil, fmt.Errorf("layer %s exceeds maximum size of %d bytes", layer.Digest, ociMaxBlobSize)
+	}
+	blob, err := p.fetch(ctx, ref, "blobs/"+layer.Digest, "*/*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch layer %s: %w", layer.Digest, err)
+	}
+	if got := sha256Digest(blob); got != layer.Digest {
+		return nil, fmt.Errorf("layer digest mismatch: expected %s, got %s", layer.Digest, got)
+	}
+	return blob, nil
+}
+
+// verifySignature checks the cosign signature stored at the sha256-<hex>.sig tag.
+// At least one signature layer must be signed by the configured key and reference
+// the artifact's manifest digest; that signature is returned so it can be cached.
+func (p *ociPuller) verifySignature(
+	ctx context.Context, ref *ociReference, manifestDigest string,
+) (*ociSignature, error) {
+	sigTag := strings.Replace(manifestDigest, ":", "-", 1) + ".sig"
+	sigManifestBytes, err := p.fetch(ctx, ref, "manifests/"+sigTag, strings.Join(ociManifestMediaTypes, ", "))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signature %s: %w", sigTag, err)
+	}
+
+	var sigManifest ociManifest
+	if err := json.Unmarshal(sigManifestBytes, &sigManifest); err != nil {
+		return nil, fmt.Errorf("failed to parse signature manifest: %w", err)
+	}
+
+	for _, layer := range sigManifest.Layers {
+		sigB64, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		payload, err := p.fetchBlob(ctx, ref, layer)
+		if err != nil {
+			return nil, err
+		}
+		signature := &ociSignature{Payload: payload, Signature: sigB64}
+		if p.signatureMatches(signature, manifestDigest) {
+			return signature, nil
+		}
+	}
+	return nil, fmt.Errorf("no valid signature for %s", manifestDigest)
+}
+
+// signatureMatches reports whether the signature was made with the configured key over
+// a simple signing payload that references manifestDigest.
+func (p *ociPuller) signatureMatches(signature *ociSignature, manifestDigest string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature.Signature)
+	if err != nil {
+		return false
+	}
+	if !verifyPayload(p.publicKey, signature.Payload, sig) {
+		return false
+	}
+
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	if err := json.Unmarshal(signature.Payload, &simpleSigning); err != nil {
+		return false
+	}
+	return simpleSigning.Critical.Image.DockerManifestDigest == manifestDigest
+}
+
+// fetch performs a GET against the registry, handling the anonymous or basic-auth
+// bearer token challenge on the first 401.
+func (p *ociPuller) fetch(ctx context.Context, ref *ociReference, path, accept string) ([]byte, error) {
+	u := fmt.Sprintf("%s://%s/v2/%s/%s", p.scheme, ref.Registry, ref.Repository, path)
+
+	for attempt := 0; attempt < 2; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", accept)
+		token := p.token.get()
+		switch {
+		case token != "":
+			req.Header.Set("Authorization", "Bearer "+token)
+		case p.username != "":
+			req.SetBasicAuth(p.username, p.password)
+		}
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errOCIRegistryUnavailable, err)
+		}
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, ociMaxBlobSize+1))
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			p.log.Debugf(ctx, "Failed to close registry response body: %v", closeErr)
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("%w: %w", errOCIRegistryUnavailable, readErr)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && attempt == 0:
+			if err := p.authenticate(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, err
+			}
+			continue
+		case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+			return nil, fmt.Errorf("%w: GET %s returned status %d", errOCIRegistryUnavailable, u, resp.StatusCode)
+		case resp.StatusCode != http.StatusOK:
+			return nil, fmt.Errorf("GET %s returned status %d", u, resp.StatusCode)
+		case len(body) > ociMaxBlobSize:
+			return nil, fmt.Errorf("GET %s exceeds maximum size of %d bytes", u, ociMaxBlobSize)
+		}
+		return body, nil
+	}
+	return nil, fmt.Errorf("GET %s: unauthorized", u)
+}
+
+// authenticate obtains a registry token from a Bearer WWW-Authenticate challenge.
+func (p *ociPuller) authenticate(ctx context.Context, challenge string) error {
+	if !strings.HasPrefix(challenge, "Bearer ") {
+		return fmt.Errorf("registry requires unsupported authentication %q", challenge)
+	}
+	params := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(challenge, "Bearer "), ",") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			params[k] = strings.Trim(v, `"`)
+		}
+	}
+	realm := params["realm"]
+	if realm == "" {
+		return fmt.Errorf("registry auth challenge has no realm")
+	}
+
+	q := url.Values{}
+	for _, k := range []string{"service", "scope"} {
+		if params[k] != "" {
+			q.Set(k, params[k])
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request registry token: %w: %w", errOCIRegistryUnavailable, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // read-only body
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token request returned status %d", resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return fmt.Errorf("failed to decode registry token: %w", err)
+	}
+	token := tokenResp.Token
+	if token == "" {
+		token = tokenResp.AccessToken
+	}
+	if token == "" {
+		return fmt.Errorf("registry token response contained no token")
+	}
+	p.token.set(token)
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// Cache layout: <cacheDir>/<sha256 hex>/task-config.yaml (chart.tgz for charts), the
+// manifest (.manifest.json) and, when a verify key is set, the verified signature
+// (.signature.json), plus <cacheDir>/refs/<escaped reference> holding the last resolved digest.
+// -----------------------------------------------------------------------------
+
+func (p *ociPuller) digestDir(digest string) string {
+	return filepath.Join(p.cacheDir, strings.TrimPrefix(digest, "sha256:"))
+}
+
+func (p *ociPuller) cachedPath(digest string) (string, bool) {
+	path := filepath.Join(p.digestDir(digest), p.artifact.fileName)
+	if _, err := os.Stat(path); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// verifiedCachedPath returns the cached main layer of the artifact with the manifest
+// digest after checking the cached manifest against the digest and every extracted layer
+// against the manifest. With checkSignature and a verify key, the cached signature must
+// also verify. A copy that is not cached returns an error wrapping errOCINotCached.
+func (p *ociPuller) verifiedCachedPath(digest string, checkSignature bool) (string, error) {
+	path, ok := p.cachedPath(digest)
+	if !ok {
+		return "", fmt.Errorf("%s %s: %w", p.artifact.name, digest, errOCINotCached)
+	}
+	dir := p.digestDir(digest)
+
+	manifestBytes, err := os.ReadFile(filepath.Join(dir, ociCachedManifestFile)) //nolint:gosec // cache dir path
+	if err != nil {
+		return "", fmt.Errorf("cached %s %s has no manifest: %w", p.artifact.name, digest, err)
+	}
+	if got := sha256Digest(manifestBytes); got != digest {
+		return "", fmt.Errorf("cached manifest digest mismatch: expected %s, got %s", digest, got)
+	}
+	var manifest ociManifest
+	if err = json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return "", fmt.Errorf("failed to parse cached manifest %s: %w", digest, err)
+	}
+	mainLayer, err := selectLayer(manifest.Layers, p.artifact.mediaType)
+	if err != nil {
+		return "", fmt.Errorf("cached artifact %s: %w", digest, err)
+	}
+	for _, layer := range manifest.Layers {
+		name := p.layerFileName(layer, mainLayer)
+		if name == "" {
+			continue
+		}
+		data, readErr := os.ReadFile(filepath.Join(dir, name)) //nolint:gosec // cache dir path
+		if readErr != nil {
+			return "", fmt.Errorf("failed to read cached layer %q: %w", name, readErr)
+		}
+		if got := sha256Digest(data); got != layer.Digest {
+			return "", fmt.Errorf("cached layer %q digest mismatch: expected %s, got %s", name, layer.Digest, got)
+		}
+	}
+
+	if checkSignature && p.publicKey != nil {
+		var signature ociSignature
+		data, readErr := os.ReadFile(filepath.Join(dir, ociCachedSignatureFile)) //nolint:gosec // cache dir path
+		if readErr != nil {
+			return "", fmt.Errorf("cached task config %s has no verified signature: %w", digest, readErr)
+		}
+		if err = json.Unmarshal(data, &signature); err != nil {
+			return "", fmt.Errorf("failed to parse cached signature for %s: %w", digest, err)
+		}
+		if !p.signatureMatches(&signature, digest) {
+			return "", fmt.Errorf("cached signature for %s does not verify", digest)
+		}
+	}
+	return path, nil
+}
+
+func (p *ociPuller) refIndexPath(ref *ociReference) string {
+	return filepath.Join(p.cacheDir, "refs", url.PathEscape(ref.String()))
+}
+
+func (p *ociPuller) readRefIndex(ref *ociReference) (string, bool) {
+	data, err := os.ReadFile(p.refIndexPath(ref))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// writeRefIndex records the digest ref resolved to, for the offline fallback of Pull.
+// A failure is only logged: the pulled artifact is usable without it.
+func (p *ociPuller) writeRefIndex(ctx context.Context, ref *ociReference, digest string) {
+	path := p.refIndexPath(ref)
+	err := os.MkdirAll(filepath.Dir(path), 0o750)
+	if err == nil {
+		err = os.WriteFile(path, []byte(digest), 0o600)
+	}
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		p.log.Warnf(errCtx, "Failed to index task config artifact %s in the cache", ref)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Helpers
+// -----------------------------------------------------------------------------
+
+func sha256Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func writeJSONFile(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// checkPrivateDir creates dir if needed and rejects a directory other users can write to,
+// so a verified cache cannot be swapped out underneath the adapter.
+func checkPrivateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create task config cache dir %q: %w", dir, err)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("failed to stat task config cache dir %q: %w", dir, err)
+	}
+	if info.Mode().Perm()&0o002 != 0 {
+		return fmt.Errorf("task config cache dir %q is writable by other users; use a private directory "+
+			"when a verify key is set", dir)
+	}
+	return nil
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// loadCosignPublicKey reads a PEM-encoded PKIX public key (ECDSA, RSA, or Ed25519).
+func loadCosignPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read task config verify key %q: %w", path, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("task config verify key %q is not PEM encoded", path)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse task config verify key %q: %w", path, err)
+	}
+	return key, nil
+}
+
+func verifyPayload(key crypto.PublicKey, payload, sig []byte) bool {
+	digest := sha256.Sum256(payload)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, payload, sig)
+	default:
+		return false
+	}
+}
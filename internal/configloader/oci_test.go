@@ -0,0 +1,351 @@
+package configloader
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// fakeRegistry serves manifests and blobs for a single repository.
+type fakeRegistry struct {
+	manifests map[string][]byte // tag or digest -> manifest
+	blobs     map[string][]byte // digest -> blob
+	requests  int
+	status    int // status of every response when set, to fail like an unavailable registry
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+}
+
+func (r *fakeRegistry) addBlob(data []byte) string {
+	digest := sha256Digest(data)
+	r.blobs[digest] = data
+	return digest
+}
+
+func (r *fakeRegistry) addManifest(tag string, layers []ociDescriptor) string {
+	data, _ := json.Marshal(ociManifest{MediaType: ociManifestMediaTypes[0], Layers: layers})
+	digest := sha256Digest(data)
+	r.manifests[tag] = data
+	r.manifests[digest] = data
+	return digest
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.requests++
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+		return
+	}
+	const prefix = "/v2/example/task/"
+	path := strings.TrimPrefix(req.URL.Path, prefix)
+	var body []byte
+	var ok bool
+	switch {
+	case strings.HasPrefix(path, "manifests/"):
+		body, ok = r.manifests[strings.TrimPrefix(path, "manifests/")]
+	case strings.HasPrefix(path, "blobs/"):
+		body, ok = r.blobs[strings.TrimPrefix(path, "blobs/")]
+	}
+	if !ok {
+		http.NotFound(w, req)
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+func newTestOCIPuller(t *testing.T, server *httptest.Server) *ociPuller {
+	t.Helper()
+	p, err := newOCIPuller(t.TempDir(), "", logger.NewTestLogger())
+	require.NoError(t, err)
+	p.client = server.Client()
+	return p
+}
+
+func ociRef(server *httptest.Server, suffix string) string {
+	return OCIReferencePrefix + strings.TrimPrefix(server.URL, "https://") + "/example/task" + suffix
+}
+
+func TestParseOCIReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("a", 64)
+	tests := []struct {
+		name    string
+		ref     string
+		want    ociReference
+		wantErr bool
+	}{
+		{
+			name: "tag",
+			ref:  "oci://quay.io/org/task:v1",
+			want: ociReference{Registry: "quay.io", Repository: "org/task", Tag: "v1"},
+		},
+		{
+			name: "default tag",
+			ref:  "oci://quay.io/org/task",
+			want: ociReference{Registry: "quay.io", Repository: "org/task", Tag: "latest"},
+		},
+		{
+			name: "registry with port",
+			ref:  "oci://localhost:5000/task:dev",
+			want: ociReference{Registry: "localhost:5000", Repository: "task", Tag: "dev"},
+		},
+		{
+			name: "digest",
+			ref:  "oci://quay.io/org/task@" + digest,
+			want: ociReference{Registry: "quay.io", Repository: "org/task", Digest: digest},
+		},
+		{name: "missing repository", ref: "oci://quay.io", wantErr: true},
+		{name: "unsupported digest", ref: "oci://quay.io/org/task@md5:abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOCIReference(tt.ref)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
+
+func TestOCIPullerPull(t *testing.T) {
+	reg := newFakeRegistry()
+	taskYAML := []byte("params: []\n")
+	manifestYAML := []byte("apiVersion: v1\nkind: Namespace\n")
+	configDigest := reg.addBlob(taskYAML)
+	refDigest := reg.addBlob(manifestYAML)
+	manifestDigest := reg.addManifest("v1", []ociDescriptor{
+		{MediaType: OCITaskConfigMediaType, Digest: configDigest, Size: int64(len(taskYAML))},
+		{
+			MediaType:   "application/yaml",
+			Digest:      refDigest,
+			Size:        int64(len(manifestYAML)),
+			Annotations: map[string]string{ociTitleAnnotation: "namespace.yaml"},
+		},
+	})
+
+	server := httptest.NewTLSServer(reg)
+	defer server.Close()
+	puller := newTestOCIPuller(t, server)
+
+	path, err := puller.Pull(context.Background(), ociRef(server, ":v1"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(puller.digestDir(manifestDigest), "task-config.yaml"), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, taskYAML, data)
+
+	data, err = os.ReadFile(filepath.Join(filepath.Dir(path), "namespace.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, manifestYAML, data)
+
+	t.Run("digest reference is served from cache", func(t *testing.T) {
+		before := reg.requests
+		cached, err := puller.Pull(context.Background(), ociRef(server, "@"+manifestDigest))
+		require.NoError(t, err)
+		assert.Equal(t, path, cached)
+		assert.Equal(t, before, reg.requests)
+	})
+
+	t.Run("tampered cached layer is pulled again", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("params: [tampered]\n"), 0o600))
+		before := reg.requests
+		cached, err := puller.Pull(context.Background(), ociRef(server, "@"+manifestDigest))
+		require.NoError(t, err)
+		assert.Equal(t, path, cached)
+		assert.Greater(t, reg.requests, before)
+		data, err := os.ReadFile(cached)
+		require.NoError(t, err)
+		assert.Equal(t, taskYAML, data)
+	})
+
+	t.Run("tampered cached layer is not used as a fallback", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("params: [tampered]\n"), 0o600))
+		defer func() { require.NoError(t, os.WriteFile(path, taskYAML, 0o600)) }()
+		reg.status = http.StatusServiceUnavailable
+		defer func() { reg.status = 0 }()
+		_, err := puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "digest mismatch")
+	})
+
+	t.Run("tag falls back to cache when registry fails", func(t *testing.T) {
+		reg.status = http.StatusServiceUnavailable
+		defer func() { reg.status = 0 }()
+		cached, err := puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.NoError(t, err)
+		assert.Equal(t, path, cached)
+	})
+
+	t.Run("tag does not fall back to cache when the artifact fails verification", func(t *testing.T) {
+		published := reg.manifests["v1"]
+		defer func() { reg.manifests["v1"] = published }()
+		tamperedDigest := reg.addBlob([]byte("params: [tampered]\n"))
+		reg.addManifest("v1", []ociDescriptor{{MediaType: OCITaskConfigMediaType, Digest: tamperedDigest}})
+		reg.blobs[tamperedDigest] = []byte("tampered")
+
+		_, err := puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "layer digest mismatch")
+	})
+
+	t.Run("tag falls back to cache when registry is unavailable", func(t *testing.T) {
+		ref := ociRef(server, ":v1")
+		server.Close()
+		cached, err := puller.Pull(context.Background(), ref)
+		require.NoError(t, err)
+		assert.Equal(t, path, cached)
+	})
+}
+
+func TestOCIPullerDigestMismatch(t *testing.T) {
+	reg := newFakeRegistry()
+	digest := reg.addBlob([]byte("params: []\n"))
+	reg.addManifest("v1", []ociDescriptor{{MediaType: OCITaskConfigMediaType, Digest: digest}})
+	reg.blobs[digest] = []byte("tampered")
+
+	server := httptest.NewTLSServer(reg)
+	defer server.Close()
+
+	_, err := newTestOCIPuller(t, server).Pull(context.Background(), ociRef(server, ":v1"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "layer digest mismatch")
+}
+
+func TestOCIPullerSignatureVerification(t *testing.T) {
+	key, keyErr := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, keyErr)
+	pubDER, keyErr := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, keyErr)
+	keyPath := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600))
+
+	newRegistry := func(signer *ecdsa.PrivateKey) *fakeRegistry {
+		reg := newFakeRegistry()
+		digest := reg.addBlob([]byte("params: []\n"))
+		manifestDigest := reg.addManifest("v1", []ociDescriptor{{MediaType: OCITaskConfigMediaType, Digest: digest}})
+
+		payload := []byte(fmt.Sprintf(
+			`{"critical":{"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"}}`,
+			manifestDigest))
+		sum := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, signer, sum[:])
+		require.NoError(t, err)
+		reg.addManifest(strings.Replace(manifestDigest, ":", "-", 1)+".sig", []ociDescriptor{{
+			MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+			Digest:      reg.addBlob(payload),
+			Annotations: map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig)},
+		}})
+		return reg
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		server := httptest.NewTLSServer(newRegistry(key))
+		defer server.Close()
+		puller := newTestOCIPuller(t, server)
+		publicKey, err := loadCosignPublicKey(keyPath)
+		require.NoError(t, err)
+		puller.publicKey = publicKey
+
+		_, err = puller.Pull(context.Background(), ociRef(server, ":v1"))
+		assert.NoError(t, err)
+	})
+
+	t.Run("signed with another key", func(t *testing.T) {
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		server := httptest.NewTLSServer(newRegistry(other))
+		defer server.Close()
+		puller := newTestOCIPuller(t, server)
+		publicKey, err := loadCosignPublicKey(keyPath)
+		require.NoError(t, err)
+		puller.publicKey = publicKey
+
+		_, err = puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "signature verification failed")
+	})
+
+	t.Run("cached copy is verified against the stored signature", func(t *testing.T) {
+		reg := newRegistry(key)
+		server := httptest.NewTLSServer(reg)
+		defer server.Close()
+		puller := newTestOCIPuller(t, server)
+		publicKey, err := loadCosignPublicKey(keyPath)
+		require.NoError(t, err)
+		puller.publicKey = publicKey
+		path, err := puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.NoError(t, err)
+
+		reg.status = http.StatusServiceUnavailable
+		cached, err := puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.NoError(t, err)
+		assert.Equal(t, path, cached)
+
+		sigPath := filepath.Join(filepath.Dir(path), ociCachedSignatureFile)
+		require.NoError(t, os.Remove(sigPath))
+		_, err = puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.Error(t, err, "an unsigned cached copy must not be used when a verify key is set")
+		assert.Contains(t, err.Error(), "no verified signature")
+	})
+
+	t.Run("verify key requires a private cache dir", func(t *testing.T) {
+		t.Setenv(EnvTaskConfigCacheDir, "")
+		_, err := newOCIPuller("", keyPath, logger.NewTestLogger())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), EnvTaskConfigCacheDir)
+
+		shared := t.TempDir()
+		require.NoError(t, os.Chmod(shared, 0o777)) //nolint:gosec // the test needs a shared dir
+		_, err = newOCIPuller(shared, keyPath, logger.NewTestLogger())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "writable by other users")
+
+		_, err = newOCIPuller(t.TempDir(), keyPath, logger.NewTestLogger())
+		assert.NoError(t, err)
+	})
+
+	t.Run("invalid signature does not fall back to the cached copy", func(t *testing.T) {
+		reg := newRegistry(key)
+		server := httptest.NewTLSServer(reg)
+		defer server.Close()
+		puller := newTestOCIPuller(t, server)
+		publicKey, err := loadCosignPublicKey(keyPath)
+		require.NoError(t, err)
+		puller.publicKey = publicKey
+		_, err = puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.NoError(t, err)
+
+		other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		resigned := newRegistry(other)
+		reg.manifests, reg.blobs = resigned.manifests, resigned.blobs
+
+		_, err = puller.Pull(context.Background(), ociRef(server, ":v1"))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "signature verification failed")
+	})
+}
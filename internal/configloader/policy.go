@@ -0,0 +1,273 @@
+package configloader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// Policy constants
+const (
+	// EnvPolicyBundle is the path to a policy file or a directory of policy files
+	EnvPolicyBundle = "HYPERFLEET_POLICY_BUNDLE"
+
+	PolicyLanguageCEL  = "cel"
+	PolicyLanguageRego = "rego"
+
+	PolicyEnforcementDeny = "deny"
+	PolicyEnforcementWarn = "warn"
+)
+
+// PolicyBundle is a set of organization-wide policies evaluated against the
+// merged config at load time.
+type PolicyBundle struct {
+	Policies []Policy `yaml:"policies"`
+}
+
+// Policy is a single governance rule. Expression must evaluate to true for a
+// compliant config.
+//
+// Expressions can reference: config (the whole merged config, with client
+// secrets redacted), adapter, clients, params, preconditions, resources and post.
+// Manifests loaded from manifest.ref files are parsed best-effort, so
+// resources[i].manifest is a map unless the file uses structural templates.
+type Policy struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Language    string `yaml:"language,omitempty"`    // cel (default)
+	Expression  string `yaml:"expression"`            // must evaluate to bool
+	Message     string `yaml:"message,omitempty"`     // reported on violation
+	Enforcement string `yaml:"enforcement,omitempty"` // deny (default) or warn
+}
+
+// policyVariables are the CEL variables exposed to policy expressions
+var policyVariables = []string{"config", "adapter", "clients", "params", "preconditions", "resources", "post"}
+
+// LoadPolicyBundle loads policies from a YAML file, or from every *.yaml/*.yml
+// file in a directory (in lexical order).
+func LoadPolicyBundle(path string) (*PolicyBundle, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy bundle %q: %w", path, err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy bundle directory %q: %w", path, err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+		sort.Strings(files)
+	}
+
+	bundle := &PolicyBundle{}
+	seen := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file) //nolint:gosec // path is operator-provided config
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy file %q: %w", file, err)
+		}
+		var part PolicyBundle
+		if err := yaml.Unmarshal(data, &part); err != nil {
+			return nil, fmt.Errorf("failed to parse policy file %q: %w", file, err)
+		}
+		for _, p := range part.Policies {
+			if prev, ok := seen[p.Name]; ok {
+				return nil, fmt.Errorf("policy %q in %q is already defined in %q", p.Name, file, prev)
+			}
+			seen[p.Name] = file
+			bundle.Policies = append(bundle.Policies, p)
+		}
+	}
+	return bundle, nil
+}
+
+// PolicyEvaluator evaluates a policy bundle against a merged config
+type PolicyEvaluator struct {
+	env      *cel.Env
+	programs map[string]cel.Program
+	bundle   *PolicyBundle
+}
+
+// NewPolicyEvaluator validates and compiles every policy in the bundle.
+// Rego policies are rejected: this build only embeds the CEL engine.
+func NewPolicyEvaluator(bundle *PolicyBundle) (*PolicyEvaluator, error) {
+	options := []cel.EnvOption{cel.OptionalTypes()}
+	for _, name := range policyVariables {
+		options = append(options, cel.Variable(name, cel.DynType))
+	}
+	env, err := cel.NewEnv(options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy CEL environment: %w", err)
+	}
+
+	pe := &PolicyEvaluator{env: env, programs: make(map[string]cel.Program), bundle: bundle}
+	errs := &ValidationErrors{}
+	for i, p := range bundle.Policies {
+		path := fmt.Sprintf("policies[%d]", i)
+		if p.Name == "" {
+			errs.Add(path, "name is required")
+			continue
+		}
+		path = fmt.Sprintf("policies[%s]", p.Name)
+		switch p.Enforcement {
+		case "", PolicyEnforcementDeny, PolicyEnforcementWarn:
+		default:
+			errs.Add(path, fmt.Sprintf("unsupported enforcement %q (expected %s or %s)",
+				p.Enforcement, PolicyEnforcementDeny, PolicyEnforcementWarn))
+		}
+		switch strings.ToLower(p.Language) {
+		case "", PolicyLanguageCEL:
+		case PolicyLanguageRego:
+			errs.Add(path, "rego policies are not supported; rewrite the policy as a CEL expression")
+			continue
+		default:
+			errs.Add(path, fmt.Sprintf("unsupported language %q", p.Language))
+			continue
+		}
+		if strings.TrimSpace(p.Expression) == "" {
+			errs.Add(path, "expression is required")
+			continue
+		}
+		ast, issues := env.Compile(p.Expression)
+		if issues != nil && issues.Err() != nil {
+			errs.Add(path, fmt.Sprintf("invalid CEL expression: %v", issues.Err()))
+			continue
+		}
+		prg, err := env.Program(ast)
+		if err != nil {
+			errs.Add(path, fmt.Sprintf("failed to build CEL program: %v", err))
+			continue
+		}
+		pe.programs[p.Name] = prg
+	}
+	if errs.HasErrors() {
+		return nil, errs
+	}
+	return pe, nil
+}
+
+// Evaluate checks the config against every policy. Violations of deny policies
+// are returned as ValidationErrors; violations of warn policies are returned
+// as warning messages. A policy that fails to evaluate counts as a violation.
+func (pe *PolicyEvaluator) Evaluate(config *Config) (warnings []string, err error) {
+	vars, err := policyInput(config)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := &ValidationErrors{}
+	for _, p := range pe.bundle.Policies {
+		msg := ""
+		out, _, evalErr := pe.programs[p.Name].Eval(vars)
+		switch {
+		case evalErr != nil:
+			msg = fmt.Sprintf("evaluation failed: %v", evalErr)
+		case out.Value() != true:
+			if _, isBool := out.Value().(bool); !isBool {
+				msg = fmt.Sprintf("expression must evaluate to bool, got %T", out.Value())
+				break
+			}
+			msg = p.Message
+			if msg == "" {
+				msg = fmt.Sprintf("config violates policy: %s", p.Expression)
+			}
+		default:
+			continue
+		}
+
+		if p.Enforcement == PolicyEnforcementWarn {
+			warnings = append(warnings, fmt.Sprintf("policy %s: %s", p.Name, msg))
+			continue
+		}
+		errs.Add(fmt.Sprintf("policy[%s]", p.Name), msg)
+	}
+	if errs.HasErrors() {
+		return warnings, errs
+	}
+	return warnings, nil
+}
+
+// policyInput converts the redacted config to plain maps keyed by YAML field names
+func policyInput(config *Config) (map[string]interface{}, error) {
+	data, err := yaml.Marshal(config.Redacted())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config for policy evaluation: %w", err)
+	}
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to decode config for policy evaluation: %w", err)
+	}
+
+	if resources, ok := root[FieldResources].([]interface{}); ok {
+		for _, r := range resources {
+			res, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if raw, ok := res[FieldManifest].(string); ok {
+				docs := parseEffectManifests(raw)
+				if docs[0] != nil {
+					res[FieldManifest] = docs[0]
+				}
+				if len(docs) > 1 {
+					all := make([]interface{}, len(docs))
+					for i, doc := range docs {
+						all[i] = doc
+					}
+					res[FieldManifestDocuments] = all
+				}
+			}
+		}
+	}
+
+	vars := map[string]interface{}{"config": root}
+	for _, name := range policyVariables[1:] {
+		switch v := root[name]; {
+		case v != nil:
+			vars[name] = v
+		case name == FieldParams || name == FieldPreconditions || name == FieldResources:
+			vars[name] = []interface{}{}
+		default:
+			vars[name] = map[string]interface{}{}
+		}
+	}
+	return vars, nil
+}
+
+// enforcePolicies loads the bundle at path and evaluates it against config,
+// logging warn-level violations and returning deny-level ones as an error.
+func enforcePolicies(ctx context.Context, log logger.Logger, path string, config *Config) error {
+	bundle, err := LoadPolicyBundle(path)
+	if err != nil {
+		return err
+	}
+	evaluator, err := NewPolicyEvaluator(bundle)
+	if err != nil {
+		return fmt.Errorf("invalid policy bundle: %w", err)
+	}
+	warnings, err := evaluator.Evaluate(config)
+	for _, w := range warnings {
+		log.Warn(ctx, w)
+	}
+	if err != nil {
+		return err
+	}
+	log.Infof(ctx, "Config satisfies policy bundle %s (%d policies)", path, len(bundle.Policies))
+	return nil
+}
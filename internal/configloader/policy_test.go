@@ -0,0 +1,177 @@
+package configloader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicyTaskYAML = `
+params:
+  - name: "clusterId"
+    source: "event.id"
+resources:
+  - name: "clusterRole"
+    manifest:
+      apiVersion: rbac.authorization.k8s.io/v1
+      kind: ClusterRole
+      metadata:
+        name: "test-role"
+    discovery:
+      by_name: "test-role"
+`
+
+func writePolicyFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestLoadConfig_PolicyBundle(t *testing.T) {
+	tmpDir := t.TempDir()
+	adapterPath, taskPath := createTestConfigFiles(t, tmpDir, testAdapterConfigYAML, testPolicyTaskYAML)
+
+	load := func(policyPath string) (*Config, error) {
+		return LoadConfig(
+			WithAdapterConfigPath(adapterPath),
+			WithTaskConfigPath(taskPath),
+			WithSkipSemanticValidation(),
+			WithPolicyBundle(policyPath),
+		)
+	}
+
+	t.Run("compliant config loads", func(t *testing.T) {
+		path := writePolicyFile(t, t.TempDir(), "policy.yaml", `
+policies:
+  - name: api-timeout
+    expression: duration(clients.hyperfleet_api.timeout) <= duration("30s")
+`)
+		config, err := load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "test-adapter", config.Adapter.Name)
+	})
+
+	t.Run("deny violation blocks load", func(t *testing.T) {
+		path := writePolicyFile(t, t.TempDir(), "policy.yaml", `
+policies:
+  - name: no-cluster-scoped-kinds
+    expression: '!resources.exists(r, r.manifest.kind in ["ClusterRole", "ClusterRoleBinding"])'
+    message: cluster-scoped kinds are not allowed
+`)
+		_, err := load(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "policy[no-cluster-scoped-kinds]: cluster-scoped kinds are not allowed")
+	})
+
+	t.Run("warn violation does not block load", func(t *testing.T) {
+		path := writePolicyFile(t, t.TempDir(), "policy.yaml", `
+policies:
+  - name: no-cluster-scoped-kinds
+    enforcement: warn
+    expression: '!resources.exists(r, r.manifest.kind == "ClusterRole")'
+`)
+		_, err := load(path)
+		require.NoError(t, err)
+	})
+
+	t.Run("env var fallback", func(t *testing.T) {
+		path := writePolicyFile(t, t.TempDir(), "policy.yaml", `
+policies:
+  - name: deny-all
+    expression: "false"
+`)
+		t.Setenv(EnvPolicyBundle, path)
+		_, err := load("")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "policy[deny-all]")
+	})
+}
+
+func TestLoadConfig_PolicyBundleMultiDocumentManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	writePolicyFile(t, tmpDir, "app.yaml", `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: default
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app
+`)
+	adapterPath, taskPath := createTestConfigFiles(t, tmpDir, testAdapterConfigYAML, `
+params:
+  - name: "clusterId"
+    source: "event.id"
+resources:
+  - name: "app"
+    manifest_ref: "app.yaml"
+    discovery:
+      namespace: "default"
+      by_name: "app"
+`)
+	policyPath := writePolicyFile(t, t.TempDir(), "policy.yaml", `
+policies:
+  - name: no-cluster-roles
+    expression: >-
+      !resources.exists(r, r.manifest.kind == "ClusterRole" ||
+        (has(r.manifest_documents) && r.manifest_documents.exists(d, d.kind == "ClusterRole")))
+`)
+
+	_, err := LoadConfig(
+		WithAdapterConfigPath(adapterPath),
+		WithTaskConfigPath(taskPath),
+		WithSkipSemanticValidation(),
+		WithPolicyBundle(policyPath),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "policy[no-cluster-roles]")
+}
+
+func TestLoadPolicyBundle_Directory(t *testing.T) {
+	dir := t.TempDir()
+	writePolicyFile(t, dir, "b.yaml", "policies:\n  - name: second\n    expression: \"true\"\n")
+	writePolicyFile(t, dir, "a.yml", "policies:\n  - name: first\n    expression: \"true\"\n")
+	writePolicyFile(t, dir, "README.md", "not a policy")
+
+	bundle, err := LoadPolicyBundle(dir)
+	require.NoError(t, err)
+	require.Len(t, bundle.Policies, 2)
+	assert.Equal(t, "first", bundle.Policies[0].Name)
+	assert.Equal(t, "second", bundle.Policies[1].Name)
+
+	writePolicyFile(t, dir, "c.yaml", "policies:\n  - name: first\n    expression: \"true\"\n")
+	_, err = LoadPolicyBundle(dir)
+	assert.ErrorContains(t, err, "already defined")
+}
+
+func TestNewPolicyEvaluator_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{"missing name", Policy{Expression: "true"}, "name is required"},
+		{"rego", Policy{Name: "p", Language: "rego", Expression: "deny[msg] { true }"}, "rego policies are not supported"},
+		{"bad CEL", Policy{Name: "p", Expression: "resources.exists("}, "invalid CEL expression"},
+		{"bad enforcement", Policy{Name: "p", Expression: "true", Enforcement: "audit"}, "unsupported enforcement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPolicyEvaluator(&PolicyBundle{Policies: []Policy{tt.policy}})
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPolicyEvaluator_NonBoolResult(t *testing.T) {
+	pe, err := NewPolicyEvaluator(&PolicyBundle{Policies: []Policy{{Name: "p", Expression: "adapter.name"}}})
+	require.NoError(t, err)
+	_, err = pe.Evaluate(&Config{Adapter: AdapterInfo{Name: "a"}})
+	assert.ErrorContains(t, err, "must evaluate to bool")
+}
@@ -0,0 +1,314 @@
+package configloader
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+)
+
+// -----------------------------------------------------------------------------
+// JSON Schema generation
+// -----------------------------------------------------------------------------
+
+// Config files a JSON Schema can be generated for
+const (
+	SchemaKindAdapter = "adapter"
+	SchemaKindTask    = "task"
+)
+
+// JSONSchemaDialect is the JSON Schema draft of the generated schemas
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSON Schema instance types
+const (
+	schemaTypeString  = "string"
+	schemaTypeBoolean = "boolean"
+	schemaTypeInteger = "integer"
+	schemaTypeNumber  = "number"
+	schemaTypeArray   = "array"
+	schemaTypeObject  = "object"
+)
+
+// JSONSchema is a JSON Schema document or subschema
+type JSONSchema struct {
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Minimum              *int                   `json:"minimum,omitempty"`
+	Maximum              *int                   `json:"maximum,omitempty"`
+	MinItems             *int                   `json:"minItems,omitempty"`
+	MinProperties        *int                   `json:"minProperties,omitempty"`
+	MaxProperties        *int                   `json:"maxProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	OneOf                []*JSONSchema          `json:"oneOf,omitempty"`
+}
+
+// GenerateSchema returns the JSON Schema of the adapter config (SchemaKindAdapter) or the
+// task config (SchemaKindTask). The schema is generated from the config types: property
+// names come from the yaml tags, and required fields, enums and bounds from the validate
+// tags. Unknown keys are rejected, as they are by the loader.
+func GenerateSchema(kind string) (*JSONSchema, error) {
+	var (
+		t     reflect.Type
+		title string
+	)
+	switch kind {
+	case SchemaKindAdapter:
+		t, title = reflect.TypeOf(AdapterConfig{}), "HyperFleet adapter deployment config"
+	case SchemaKindTask:
+		t, title = reflect.TypeOf(AdapterTaskConfig{}), "HyperFleet adapter task config"
+	default:
+		return nil, fmt.Errorf("unknown schema kind %q, expected %q or %q", kind, SchemaKindAdapter, SchemaKindTask)
+	}
+
+	g := &schemaGenerator{defs: map[string]*JSONSchema{}}
+	root := g.structSchema(t)
+	if kind == SchemaKindTask {
+		g.addIncludeEntries(root)
+	}
+	root.Schema = JSONSchemaDialect
+	root.Title = title
+	root.Defs = g.defs
+	return root, nil
+}
+
+type schemaGenerator struct {
+	defs map[string]*JSONSchema
+}
+
+var (
+	durationType        = reflect.TypeOf(Duration(0))
+	stdDurationType     = reflect.TypeOf(time.Duration(0))
+	parameterSourceType = reflect.TypeOf(ParameterSource{})
+	conditionType       = reflect.TypeOf(Condition{})
+)
+
+// typeSchema returns the schema of a Go type. Named structs are added to $defs and
+// referenced, so that shared types appear once.
+func (g *schemaGenerator) typeSchema(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case durationType, stdDurationType:
+		return &JSONSchema{Type: schemaTypeString, Description: `Go duration such as "30s", "5m" or "1h30m"`}
+	case parameterSourceType, conditionType:
+		return g.ref(t, g.customSchema)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &JSONSchema{Type: schemaTypeString}
+	case reflect.Bool:
+		return &JSONSchema{Type: schemaTypeBoolean}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: schemaTypeInteger}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: schemaTypeNumber}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: schemaTypeArray, Items: g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return &JSONSchema{Type: schemaTypeObject, AdditionalProperties: g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.ref(t, g.structSchema)
+	default:
+		// interface{} fields accept any YAML value
+		return &JSONSchema{}
+	}
+}
+
+// ref adds the schema of a named type to $defs on first use and returns a reference to it
+func (g *schemaGenerator) ref(t reflect.Type, build func(reflect.Type) *JSONSchema) *JSONSchema {
+	name := t.Name()
+	if pkg := path.Base(t.PkgPath()); pkg != "configloader" {
+		name = pkg + "." + name
+	}
+	if _, ok := g.defs[name]; !ok {
+		// Reserve the name first so that recursive types terminate
+		g.defs[name] = nil
+		g.defs[name] = build(t)
+	}
+	return &JSONSchema{Ref: "#/$defs/" + name}
+}
+
+// includeEntryDef is the $defs name of the include entry schema
+const includeEntryDef = "IncludeEntry"
+
+// addIncludeEntries lets the step lists that can include a step file hold include
+// entries, which the loader expands before decoding the steps
+func (g *schemaGenerator) addIncludeEntries(root *JSONSchema) {
+	g.defs[includeEntryDef] = &JSONSchema{
+		Type: schemaTypeObject,
+		Properties: map[string]*JSONSchema{
+			FieldInclude: {Type: schemaTypeString, Description: "Path of the step file, relative to the task config"},
+			FieldWith: {
+				Type:                 schemaTypeObject,
+				Description:          "Values of the inputs of the step file",
+				AdditionalProperties: &JSONSchema{},
+			},
+		},
+		Required:             []string{FieldInclude},
+		AdditionalProperties: false,
+	}
+	for _, section := range includeSections {
+		list := root
+		for _, key := range section.path {
+			if list.Ref != "" {
+				list = g.defs[strings.TrimPrefix(list.Ref, "#/$defs/")]
+			}
+			list = list.Properties[key]
+		}
+		list.Items = &JSONSchema{OneOf: []*JSONSchema{list.Items, {Ref: "#/$defs/" + includeEntryDef}}}
+	}
+}
+
+// customSchema describes the types with a custom UnmarshalYAML
+func (g *schemaGenerator) customSchema(t reflect.Type) *JSONSchema {
+	if t == parameterSourceType {
+		one := 1
+		return &JSONSchema{OneOf: []*JSONSchema{
+			{Type: schemaTypeString},
+			{
+				Type: schemaTypeObject,
+				Properties: map[string]*JSONSchema{
+					"api_call":   g.typeSchema(reflect.TypeOf(APICall{})),
+					"expression": {Type: schemaTypeString},
+					"file":       g.typeSchema(reflect.TypeOf(FileSourceConfig{})),
+				},
+				AdditionalProperties: false,
+				MinProperties:        &one,
+				MaxProperties:        &one,
+			},
+		}}
+	}
+
+	s := g.structSchema(reflect.TypeOf(conditionRaw{}))
+	s.Properties["operator"].Enum = stringEnum(criteria.OperatorStrings())
+	s.Required = []string{"operator"}
+	return s
+}
+
+// structSchema returns the object schema of a struct, with inline fields flattened
+func (g *schemaGenerator) structSchema(t reflect.Type) *JSONSchema {
+	s := &JSONSchema{Type: schemaTypeObject, Properties: map[string]*JSONSchema{}, AdditionalProperties: false}
+	g.addFields(s, t)
+	sort.Strings(s.Required)
+	return s
+}
+
+func (g *schemaGenerator) addFields(s *JSONSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if strings.Contains(opts, "inline") {
+			g.addFields(s, field.Type)
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+
+		prop := g.typeSchema(field.Type)
+		if applyValidateTag(prop, field.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// applyValidateTag maps the validate rules with a JSON Schema equivalent onto s and
+// reports whether the field is required. Rules after "dive" apply to the items.
+func applyValidateTag(s *JSONSchema, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	required := false
+	target := s
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			if target.Items == nil {
+				return required
+			}
+			target = target.Items
+		case "required":
+			if target == s {
+				required = true
+			}
+		case "oneof":
+			values := strings.Fields(param)
+			if target.Type == schemaTypeInteger {
+				target.Enum = intEnum(values)
+			} else {
+				target.Enum = stringEnum(values)
+			}
+		case "validoperator":
+			target.Enum = stringEnum(criteria.OperatorStrings())
+		case "resourcename":
+			target.Pattern = resourceNamePattern.String()
+		case "min", "gte":
+			n, err := strconv.Atoi(param)
+			if err != nil {
+				continue
+			}
+			switch target.Type {
+			case schemaTypeArray:
+				target.MinItems = &n
+			case schemaTypeObject:
+				target.MinProperties = &n
+			case schemaTypeInteger, schemaTypeNumber:
+				target.Minimum = &n
+			}
+		case "max", "lte":
+			n, err := strconv.Atoi(param)
+			if err == nil && (target.Type == schemaTypeInteger || target.Type == schemaTypeNumber) {
+				target.Maximum = &n
+			}
+		}
+	}
+	return required
+}
+
+func stringEnum(values []string) []interface{} {
+	enum := make([]interface{}, len(values))
+	for i, v := range values {
+		enum[i] = v
+	}
+	return enum
+}
+
+func intEnum(values []string) []interface{} {
+	enum := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		if n, err := strconv.Atoi(v); err == nil {
+			enum = append(enum, n)
+		}
+	}
+	return enum
+}
@@ -0,0 +1,210 @@
+package configloader
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestGenerateSchema_Task(t *testing.T) {
+	s, err := GenerateSchema(SchemaKindTask)
+	require.NoError(t, err)
+	assert.Equal(t, JSONSchemaDialect, s.Schema)
+	assert.Equal(t, false, s.AdditionalProperties)
+	for _, key := range []string{"params", "preconditions", "resources", "post", "prune", "wait"} {
+		assert.Contains(t, s.Properties, key)
+	}
+
+	// Inline fields are flattened into the embedding struct
+	precondition := s.Defs["Precondition"]
+	require.NotNil(t, precondition)
+	for _, key := range []string{"name", "api_call", "log", "expression", "capture", "conditions"} {
+		assert.Contains(t, precondition.Properties, key)
+	}
+	assert.Equal(t, []string{"name"}, precondition.Required)
+	assert.Equal(t, resourceNamePattern.String(), precondition.Properties["name"].Pattern)
+
+	apiCall := s.Defs["APICall"]
+	require.NotNil(t, apiCall)
+	assert.Equal(t, []string{"method", "url"}, apiCall.Required)
+	assert.Equal(t, []interface{}{"GET", "POST", "PUT", "PATCH", "DELETE"}, apiCall.Properties["method"].Enum)
+	assert.Equal(t, "string", apiCall.Properties["timeout"].Type)
+
+	// Fields not read from YAML are left out
+	assert.NotContains(t, s.Defs["Payload"].Properties, "BuildRefContent")
+	assert.NotContains(t, s.Defs["Payload"].Properties, "-")
+
+	source := s.Defs["ParameterSource"]
+	require.NotNil(t, source)
+	require.Len(t, source.OneOf, 2)
+	assert.Equal(t, "string", source.OneOf[0].Type)
+	assert.Contains(t, source.OneOf[1].Properties, "expression")
+
+	condition := s.Defs["Condition"]
+	require.NotNil(t, condition)
+	assert.Contains(t, condition.Properties, "value")
+	assert.Contains(t, condition.Properties, "values")
+	assert.Contains(t, condition.Properties["operator"].Enum, "equals")
+
+	retry := s.Defs["RetryPolicy"]
+	require.NotNil(t, retry)
+	require.NotNil(t, retry.Properties["attempts"].Minimum)
+	assert.Equal(t, 1, *retry.Properties["attempts"].Minimum)
+	assert.Equal(t, 10, *retry.Properties["attempts"].Maximum)
+	assert.Equal(t, []interface{}{"retryable", "api", "transport", "timeout"}, retry.Properties["retry_on"].Items.Enum)
+}
+
+func TestGenerateSchema_IncludeEntries(t *testing.T) {
+	s, err := GenerateSchema(SchemaKindTask)
+	require.NoError(t, err)
+
+	entry := s.Defs["IncludeEntry"]
+	require.NotNil(t, entry)
+	assert.Equal(t, []string{"include"}, entry.Required)
+	assert.Equal(t, false, entry.AdditionalProperties)
+
+	for name, list := range map[string]*JSONSchema{
+		"preconditions":     s.Properties["preconditions"],
+		"resources":         s.Properties["resources"],
+		"post.post_actions": s.Defs["PostConfig"].Properties["post_actions"],
+	} {
+		require.Len(t, list.Items.OneOf, 2, name)
+		assert.Equal(t, "#/$defs/IncludeEntry", list.Items.OneOf[1].Ref, name)
+	}
+
+	var doc interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+preconditions:
+  - include: steps/cluster-ready.yaml
+    with:
+      name: cluster
+  - name: "ready"
+    expression: "true"
+resources:
+  - include: steps/config.yaml
+post:
+  post_actions:
+    - include: steps/report.yaml
+`), &doc))
+	assert.Empty(t, unknownSchemaKeys(s, s, doc, ""))
+
+	require.NoError(t, yaml.Unmarshal([]byte(`
+resources:
+  - include: steps/config.yaml
+    inputs:
+      name: cluster
+`), &doc))
+	assert.Equal(t, []string{".resources[].inputs"}, unknownSchemaKeys(s, s, doc, ""))
+}
+
+func TestGenerateSchema_Adapter(t *testing.T) {
+	s, err := GenerateSchema(SchemaKindAdapter)
+	require.NoError(t, err)
+	for _, key := range []string{"adapter", "clients", "log", "schedules", "admin"} {
+		assert.Contains(t, s.Properties, key)
+	}
+	// Types from other packages are qualified, so that admin.Config and schedule.Config do not collide
+	assert.Contains(t, s.Defs, "admin.Config")
+	assert.Contains(t, s.Defs, "schedule.Config")
+	assert.Equal(t, []interface{}{1, 2}, s.Defs["VaultClientConfig"].Properties["kv_version"].Enum)
+
+	_, err = json.Marshal(s)
+	require.NoError(t, err)
+}
+
+func TestGenerateSchema_UnknownKind(t *testing.T) {
+	_, err := GenerateSchema("deployment")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown schema kind")
+}
+
+// TestGenerateSchema_CoversExamples checks that every key of the shipped example configs
+// is described by the schema
+func TestGenerateSchema_CoversExamples(t *testing.T) {
+	tests := []struct {
+		kind  string
+		files []string
+	}{
+		{SchemaKindTask, []string{
+			"../../configs/adapter-task-config-template.yaml",
+			"../../charts/examples/kubernetes/adapter-task-config.yaml",
+			"../../charts/examples/maestro/adapter-task-config.yaml",
+		}},
+		{SchemaKindAdapter, []string{
+			"../../configs/adapter-config-template.yaml",
+			"../../charts/examples/kubernetes/adapter-config.yaml",
+			"../../charts/examples/maestro/adapter-config.yaml",
+		}},
+	}
+	for _, tt := range tests {
+		s, err := GenerateSchema(tt.kind)
+		require.NoError(t, err)
+		for _, file := range tt.files {
+			t.Run(filepath.Base(filepath.Dir(file))+"/"+filepath.Base(file), func(t *testing.T) {
+				data, err := os.ReadFile(filepath.Clean(file))
+				require.NoError(t, err)
+				var doc interface{}
+				require.NoError(t, yaml.Unmarshal(data, &doc))
+				assert.Empty(t, unknownSchemaKeys(s, s, doc, ""))
+			})
+		}
+	}
+}
+
+// unknownSchemaKeys returns the paths of the mapping keys in doc that s does not allow
+func unknownSchemaKeys(root, s *JSONSchema, doc interface{}, path string) []string {
+	if s.Ref != "" {
+		return unknownSchemaKeys(root, root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")], doc, path)
+	}
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		if s.Type == "" && len(s.OneOf) == 0 {
+			// The empty schema accepts any value
+			return nil
+		}
+		if len(s.OneOf) > 0 {
+			// The mapping is allowed when one of the alternatives allows all its keys
+			var unknown []string
+			for i, alt := range s.OneOf {
+				altUnknown := unknownSchemaKeys(root, alt, doc, path)
+				if len(altUnknown) == 0 {
+					return nil
+				}
+				if i == 0 || len(altUnknown) < len(unknown) {
+					unknown = altUnknown
+				}
+			}
+			return unknown
+		}
+		var unknown []string
+		for key, value := range v {
+			prop, ok := s.Properties[key]
+			if !ok {
+				additional, isSchema := s.AdditionalProperties.(*JSONSchema)
+				if !isSchema {
+					unknown = append(unknown, path+"."+key)
+					continue
+				}
+				prop = additional
+			}
+			unknown = append(unknown, unknownSchemaKeys(root, prop, value, path+"."+key)...)
+		}
+		return unknown
+	case []interface{}:
+		if s.Items == nil {
+			return nil
+		}
+		var unknown []string
+		for _, item := range v {
+			unknown = append(unknown, unknownSchemaKeys(root, s.Items, item, path+"[]")...)
+		}
+		return unknown
+	}
+	return nil
+}
@@ -2,7 +2,6 @@ package configloader
 
 import (
 	"fmt"
-	"os"
 	"reflect"
 	"regexp"
 	"strings"
@@ -76,9 +75,14 @@ func getStructValidator() *validator.Validate {
 			panic(fmt.Sprintf(
 				"failed to register validoperator validation: %v", err))
 		}
+		if err := structValidator.RegisterValidation(
+			"transportclient", validateTransportClient); err != nil {
+			panic(fmt.Sprintf(
+				"failed to register transportclient validation: %v", err))
+		}
 
 		// Register custom struct-level validations
-		structValidator.RegisterStructValidation(validateParameterEnvRequired, Parameter{})
+		structValidator.RegisterStructValidation(validateParameterSource, Parameter{})
 
 		// Use yaml tag names for field names in errors
 		structValidator.RegisterTagNameFunc(extractYamlTagName)
@@ -102,29 +106,20 @@ func validateOperator(fl validator.FieldLevel) bool {
 	return criteria.IsValidOperator(fl.Field().String())
 }
 
-// validateParameterEnvRequired is a struct-level validator for Parameter.
-// Checks that source is set and, for required env params, that the env var exists.
-func validateParameterEnvRequired(sl validator.StructLevel) {
+// validateTransportClient is a custom validator for registered transport client names
+func validateTransportClient(fl validator.FieldLevel) bool {
+	return IsSupportedTransportClient(fl.Field().String())
+}
+
+// validateParameterSource is a struct-level validator for Parameter that checks that
+// source is set. Missing environment variables of env.* params are reported by CheckEnvVars.
+func validateParameterSource(sl validator.StructLevel) {
 	// type is guaranteed by RegisterStructValidation
 	//nolint:errcheck
 	param := sl.Current().Interface().(Parameter)
 
 	if param.Source.IsZero() || (param.Source.IsString() && strings.TrimSpace(param.Source.StringVal) == "") {
 		sl.ReportError(param.Source, "source", "Source", "required", "")
-		return
-	}
-
-	if !param.Required || !param.Source.IsString() ||
-		!strings.HasPrefix(param.Source.StringVal, "env.") {
-		return
-	}
-
-	envName := strings.TrimPrefix(param.Source.StringVal, "env.")
-	envValue := os.Getenv(envName)
-
-	// Error if env var is not set and no default provided
-	if envValue == "" && param.Default == nil {
-		sl.ReportError(param.Source, "source", "Source", "envrequired", envName)
 	}
 }
 
@@ -179,6 +174,9 @@ func formatFullErrorMessage(e validator.FieldError) string {
 			"%s %q: must start with lowercase letter and contain only letters, numbers, underscores (no hyphens)",
 			path, e.Value(),
 		)
+	case "transportclient":
+		return fmt.Sprintf("%s %q is invalid (allowed: %s)",
+			path, e.Value(), strings.Join(SupportedTransportClients(), ", "))
 	case "validoperator":
 		return fmt.Sprintf("%s: invalid operator %q, must be one of: %s",
 			path, e.Value(), strings.Join(criteria.OperatorStrings(), ", "))
@@ -201,9 +199,6 @@ func formatFullErrorMessage(e validator.FieldError) string {
 	case "unique":
 		// e.g., "spec.resources: contains duplicate name values"
 		return fmt.Sprintf("%s: contains duplicate %s values", path, yamlFieldName(e.Param()))
-	case "envrequired":
-		// e.g., "spec.params[0]: required environment variable MY_VAR is not set"
-		return fmt.Sprintf("%s: required environment variable %s is not set", parentPath(path), e.Param())
 	default:
 		return fmt.Sprintf("%s: failed validation %s", path, e.Tag())
 	}
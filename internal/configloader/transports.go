@@ -0,0 +1,97 @@
+package configloader
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	transportClientsMu sync.RWMutex
+	// transportClients are the transport client names resources may select
+	transportClients = map[string]bool{
+		TransportClientKubernetes: true,
+		TransportClientMaestro:    true,
+		TransportClientGitOps:     true,
+	}
+)
+
+// RegisterTransportClient adds name to the transport clients that resources may select
+// with transport.client. Backends register with transportclient.Register, which calls it.
+func RegisterTransportClient(name string) {
+	transportClientsMu.Lock()
+	defer transportClientsMu.Unlock()
+	transportClients[name] = true
+}
+
+// SupportedTransportClients returns the names of the transport clients, sorted
+func SupportedTransportClients() []string {
+	transportClientsMu.RLock()
+	defer transportClientsMu.RUnlock()
+	names := make([]string, 0, len(transportClients))
+	for name := range transportClients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsSupportedTransportClient returns true if resources may select the transport client name
+func IsSupportedTransportClient(name string) bool {
+	transportClientsMu.RLock()
+	defer transportClientsMu.RUnlock()
+	return transportClients[name]
+}
+
+// TransportClients returns the names of the transport clients the task config uses, sorted:
+// those of its resources, and kubernetes for prune steps
+func (c *Config) TransportClients() []string {
+	used := make(map[string]bool)
+	for i := range c.Resources {
+		used[c.Resources[i].GetTransportClient()] = true
+	}
+	if len(c.Prune) > 0 {
+		used[TransportClientKubernetes] = true
+	}
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckTransportClients cross-checks the transports of the task config against the clients
+// of the deployment config. Every resource selecting maestro or gitops needs the matching
+// clients.maestro or clients.gitops; the mismatches are returned as one error. Configured
+// maestro and gitops clients that no resource uses are returned as warnings.
+func CheckTransportClients(config *Config) (warnings []string, err error) {
+	if config == nil {
+		return nil, nil
+	}
+	configured := map[string]bool{
+		TransportClientMaestro: config.Clients.Maestro != nil,
+		TransportClientGitOps:  config.Clients.GitOps != nil,
+	}
+	used := make(map[string]bool)
+	errs := &ValidationErrors{}
+	for i := range config.Resources {
+		name := config.Resources[i].GetTransportClient()
+		used[name] = true
+		if isConfigured, checked := configured[name]; checked && !isConfigured {
+			errs.Add(fmt.Sprintf("%s[%d].%s.client", FieldResources, i, FieldTransport), fmt.Sprintf(
+				"resource %q uses the %s transport but clients.%s is not configured",
+				config.Resources[i].Name, name, name))
+		}
+	}
+	for _, name := range []string{TransportClientGitOps, TransportClientMaestro} {
+		if configured[name] && !used[name] {
+			warnings = append(warnings, fmt.Sprintf(
+				"clients.%s is configured but no resource uses the %s transport", name, name))
+		}
+	}
+	if errs.HasErrors() {
+		return warnings, errs
+	}
+	return warnings, nil
+}
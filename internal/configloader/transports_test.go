@@ -0,0 +1,92 @@
+package configloader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTransportClients(t *testing.T) {
+	resources := []Resource{
+		{Name: "namespace"},
+		{Name: "work", Transport: &TransportConfig{Client: TransportClientMaestro}},
+		{Name: "repo", Transport: &TransportConfig{Client: TransportClientGitOps}},
+	}
+
+	t.Run("every mismatch is reported", func(t *testing.T) {
+		warnings, err := CheckTransportClients(&Config{Resources: resources})
+		require.Error(t, err)
+		assert.Empty(t, warnings)
+		errs := ValidationErrorsOf(err)
+		require.Len(t, errs, 2)
+		assert.Equal(t, "resources[1].transport.client", errs[0].Path)
+		assert.Contains(t, errs[0].Message, `resource "work" uses the maestro transport but clients.maestro`)
+		assert.Equal(t, "resources[2].transport.client", errs[1].Path)
+		assert.Contains(t, errs[1].Message, `resource "repo" uses the gitops transport but clients.gitops`)
+	})
+
+	t.Run("configured clients", func(t *testing.T) {
+		config := &Config{Resources: resources}
+		config.Clients.Maestro = &MaestroClientConfig{}
+		config.Clients.GitOps = &GitOpsClientConfig{}
+		warnings, err := CheckTransportClients(config)
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+
+	t.Run("unused clients warn", func(t *testing.T) {
+		config := &Config{Resources: resources[:1]}
+		config.Clients.Maestro = &MaestroClientConfig{}
+		config.Clients.GitOps = &GitOpsClientConfig{}
+		warnings, err := CheckTransportClients(config)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"clients.gitops is configured but no resource uses the gitops transport",
+			"clients.maestro is configured but no resource uses the maestro transport",
+		}, warnings)
+	})
+
+	t.Run("registered transport clients are not checked", func(t *testing.T) {
+		RegisterTransportClient("test-argocd")
+		warnings, err := CheckTransportClients(&Config{Resources: []Resource{
+			{Name: "app", Transport: &TransportConfig{Client: "test-argocd"}},
+		}})
+		require.NoError(t, err)
+		assert.Empty(t, warnings)
+	})
+}
+
+func TestLoadConfig_TransportClients(t *testing.T) {
+	taskYAML := `
+resources:
+  - name: "work"
+    transport:
+      client: "maestro"
+      maestro:
+        target_cluster: "cluster1"
+    manifest:
+      apiVersion: work.open-cluster-management.io/v1
+      kind: ManifestWork
+      metadata:
+        name: "work"
+    discovery:
+      by_name: "work"
+`
+	load := func(adapterYAML string) error {
+		adapterPath, taskPath := createTestConfigFiles(t, t.TempDir(), adapterYAML, taskYAML)
+		_, err := LoadConfig(
+			WithAdapterConfigPath(adapterPath),
+			WithTaskConfigPath(taskPath),
+			WithSkipSemanticValidation(),
+		)
+		return err
+	}
+
+	err := load(testAdapterConfigYAML)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task config transports do not match the configured clients")
+	assert.Contains(t, err.Error(), "clients.maestro is not configured")
+
+	assert.NoError(t, load(testMaestroAdapterConfigYAML))
+}
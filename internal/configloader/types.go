@@ -1,24 +1,78 @@
 package configloader
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/helm"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/httpreceiver"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/kafkabroker"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/natsbroker"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/schedule"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/admin"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 	"gopkg.in/yaml.v3"
 )
 
 // Config is the unified configuration passed throughout the application.
 // Created by merging AdapterConfig (deployment) and AdapterTaskConfig (task).
 type Config struct {
-	Post          *PostConfig    `yaml:"post,omitempty"`
-	Log           LogConfig      `yaml:"log,omitempty"`
-	Adapter       AdapterInfo    `yaml:"adapter"`
-	Params        []Parameter    `yaml:"params,omitempty"`
-	Preconditions []Precondition `yaml:"preconditions,omitempty"`
-	Resources     []Resource     `yaml:"resources,omitempty"`
-	Clients       ClientsConfig  `yaml:"clients"`
-	DebugConfig   bool           `yaml:"debug_config,omitempty"`
+	Post          *PostConfig         `yaml:"post,omitempty"`
+	Log           LogConfig           `yaml:"log,omitempty"`
+	Adapter       AdapterInfo         `yaml:"adapter"`
+	Params        []Parameter         `yaml:"params,omitempty"`
+	Preconditions []Precondition      `yaml:"preconditions,omitempty"`
+	Resources     []Resource          `yaml:"resources,omitempty"`
+	Prune         []PruneStep         `yaml:"prune,omitempty"`
+	Wait          []WaitStep          `yaml:"wait,omitempty"`
+	Platform      *PlatformConfig     `yaml:"platform,omitempty"`
+	EventOverlays []EventOverlay      `yaml:"event_overlays,omitempty"`
+	Sharding      ShardingConfig      `yaml:"sharding,omitempty"`
+	Notifications NotificationsConfig `yaml:"notifications,omitempty"`
+	Deduplication DeduplicationConfig `yaml:"deduplication,omitempty"`
+	// SLI configures the status report SLIs of processed generations
+	SLI SLIConfig `yaml:"sli,omitempty"`
+	// Schedules inject synthetic events into the executor on cron schedules
+	Schedules []ScheduleConfig `yaml:"schedules,omitempty"`
+	// FailurePolicy is the on_error of the steps that do not set one
+	FailurePolicy string `yaml:"failure_policy,omitempty"`
+	// Admin configures the authenticated admin server
+	Admin AdminConfig `yaml:"admin,omitempty"`
+	// ExecutionRecording configures the recording of failed executions for replay
+	ExecutionRecording ExecutionRecordingConfig `yaml:"execution_recording,omitempty"`
+	// Readiness selects the dependency checks that /readyz includes
+	Readiness ReadinessConfig `yaml:"readiness,omitempty"`
+	// EventFilter skips the broker events for which this CEL expression is not true
+	EventFilter string `yaml:"event_filter,omitempty"`
+	// EventRouting acks the broker events of other types and subjects without decoding them
+	EventRouting EventRoutingConfig `yaml:"event_routing,omitempty"`
+	// MissingEnvVars is what loading does when an optional env.* param has no value
+	MissingEnvVars string `yaml:"missing_env_vars,omitempty"`
+	// JSONNumbers is how numbers of event data and API responses are decoded
+	JSONNumbers string        `yaml:"json_numbers,omitempty"`
+	Clients     ClientsConfig `yaml:"clients"`
+	// ExecutionTimeout bounds the phases before the post actions of one execution; 0 is unbounded
+	ExecutionTimeout Duration     `yaml:"execution_timeout,omitempty"`
+	Limits           LimitsConfig `yaml:"limits,omitempty"`
+	// TrafficRecording configures the recording of outgoing calls for debugging
+	TrafficRecording TrafficRecordingConfig `yaml:"traffic_recording,omitempty"`
+	DebugConfig      bool                   `yaml:"debug_config,omitempty"`
+}
+
+// StrictJSONNumbers reports whether JSON numbers are decoded as int64 or float64 by their
+// form, rather than always as float64
+func (c *Config) StrictJSONNumbers() bool {
+	return c != nil && c.JSONNumbers == JSONNumbersStrict
 }
 
 // Merge combines AdapterConfig (deployment) and AdapterTaskConfig (task) into a unified Config.
@@ -30,15 +84,106 @@ func Merge(adapterCfg *AdapterConfig, taskCfg *AdapterTaskConfig) *Config {
 	}
 
 	return &Config{
-		Adapter:       adapterCfg.Adapter,
-		Clients:       adapterCfg.Clients,
-		DebugConfig:   adapterCfg.DebugConfig,
-		Log:           adapterCfg.Log,
-		Params:        taskCfg.Params,
-		Preconditions: taskCfg.Preconditions,
-		Resources:     taskCfg.Resources,
-		Post:          taskCfg.Post,
+		Adapter:            adapterCfg.Adapter,
+		Clients:            adapterCfg.Clients,
+		DebugConfig:        adapterCfg.DebugConfig,
+		Limits:             adapterCfg.Limits,
+		Notifications:      adapterCfg.Notifications,
+		Sharding:           adapterCfg.Sharding,
+		Deduplication:      adapterCfg.Deduplication,
+		TrafficRecording:   adapterCfg.TrafficRecording,
+		ExecutionRecording: adapterCfg.ExecutionRecording,
+		SLI:                adapterCfg.SLI,
+		Schedules:          adapterCfg.Schedules,
+		Admin:              adapterCfg.Admin,
+		Readiness:          adapterCfg.Readiness,
+		EventFilter:        adapterCfg.EventFilter,
+		EventRouting:       adapterCfg.EventRouting,
+		MissingEnvVars:     adapterCfg.MissingEnvVars,
+		JSONNumbers:        adapterCfg.JSONNumbers,
+		Log:                adapterCfg.Log,
+		Params:             taskCfg.Params,
+		Preconditions:      taskCfg.Preconditions,
+		Resources:          taskCfg.Resources,
+		Prune:              taskCfg.Prune,
+		Wait:               taskCfg.Wait,
+		Platform:           taskCfg.Platform,
+		EventOverlays:      taskCfg.EventOverlays,
+		ExecutionTimeout:   taskCfg.ExecutionTimeout,
+		FailurePolicy:      taskCfg.FailurePolicy,
+		Post:               taskCfg.Post,
+	}
+}
+
+// WithTaskFrom returns a copy of c whose task config (params, preconditions, resources,
+// prune and wait steps, platform, event overlays and post-processing) is taken from
+// reloaded. Deployment settings such as clients are kept, since they are only applied
+// when the adapter starts.
+func (c *Config) WithTaskFrom(reloaded *Config) *Config {
+	updated := *c
+	updated.Params = reloaded.Params
+	updated.Preconditions = reloaded.Preconditions
+	updated.Resources = reloaded.Resources
+	updated.Prune = reloaded.Prune
+	updated.Wait = reloaded.Wait
+	updated.Platform = reloaded.Platform
+	updated.EventOverlays = reloaded.EventOverlays
+	updated.ExecutionTimeout = reloaded.ExecutionTimeout
+	updated.FailurePolicy = reloaded.FailurePolicy
+	updated.Post = reloaded.Post
+	return &updated
+}
+
+// configHashLength is the number of hex characters kept from the task config hash
+const configHashLength = 12
+
+// TaskConfigHash returns a short content hash of the task config (params, preconditions,
+// resources, prune and wait steps, platform, event overlays, execution timeout, failure
+// policy and post-processing), identifying the config version an execution ran with.
+// Loaded file content (manifest and build refs) is included. It returns "" if the config
+// cannot be serialized.
+func (c *Config) TaskConfigHash() string {
+	if c == nil {
+		return ""
 	}
+	data, err := json.Marshal(struct {
+		Resources        []Resource
+		Prune            []PruneStep
+		Wait             []WaitStep
+		Platform         *PlatformConfig
+		EventOverlays    []EventOverlay `json:",omitempty"`
+		Post             *PostConfig
+		Params           []Parameter
+		FailurePolicy    string `json:",omitempty"`
+		Preconditions    []Precondition
+		ExecutionTimeout Duration `json:",omitempty"`
+	}{
+		Params: c.Params, Preconditions: c.Preconditions, Resources: c.Resources,
+		Prune: c.Prune, Wait: c.Wait, Platform: c.Platform, EventOverlays: c.EventOverlays,
+		ExecutionTimeout: c.ExecutionTimeout, FailurePolicy: c.FailurePolicy, Post: c.Post,
+	})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:configHashLength]
+}
+
+// ConfigFingerprint returns a short content hash of the whole merged config, deployment
+// config included, identifying the config revision a replica runs: replicas started from
+// the same files, environment and flags report the same fingerprint. Sensitive fields are
+// redacted first, so rotating a credential does not change it. It returns "" if the
+// config cannot be serialized.
+func (c *Config) ConfigFingerprint() string {
+	if c == nil {
+		return ""
+	}
+	data, err := json.Marshal(c.Redacted())
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:configHashLength]
 }
 
 const redactedValue = "**REDACTED**"
@@ -50,6 +195,15 @@ func (c *Config) Redacted() *Config {
 	}
 	copy := *c
 	copy.Clients = redactedClients(c.Clients)
+	if len(c.Notifications.Targets) > 0 {
+		copy.Notifications.Targets = make([]NotificationTarget, len(c.Notifications.Targets))
+		for i, t := range c.Notifications.Targets {
+			// Webhook URLs (e.g. Slack incoming webhooks) carry their credential in the path
+			t.URL = redactedValue
+			t.Headers = nil
+			copy.Notifications.Targets[i] = t
+		}
+	}
 	return &copy
 }
 
@@ -75,6 +229,16 @@ func redactedClients(clients ClientsConfig) ClientsConfig {
 		}
 		copy.Maestro = &maestroCopy
 	}
+	if clients.Vault != nil {
+		vaultCopy := *clients.Vault
+		if vaultCopy.TokenPath != "" {
+			vaultCopy.TokenPath = redactedValue
+		}
+		if vaultCopy.CAFile != "" {
+			vaultCopy.CAFile = redactedValue
+		}
+		copy.Vault = &vaultCopy
+	}
 	return copy
 }
 
@@ -149,8 +313,17 @@ type LogConfig struct {
 	Level  string `yaml:"level,omitempty" mapstructure:"level"`
 	Format string `yaml:"format,omitempty" mapstructure:"format"`
 	Output string `yaml:"output,omitempty" mapstructure:"output"`
+	// Sampling limits identical warning and error logs; unset logs every record
+	Sampling *LogSamplingConfig `yaml:"sampling,omitempty" mapstructure:"sampling"`
+	// DebugClusters lists the cluster IDs whose events are logged at debug level,
+	// whatever the configured level
+	DebugClusters []string `yaml:"debug_clusters,omitempty" mapstructure:"debug_clusters"`
 }
 
+// LogSamplingConfig is the log sampling configuration.
+// Alias to logger.SamplingConfig to ensure shared schema.
+type LogSamplingConfig = logger.SamplingConfig
+
 // HyperfleetAPIConfig is the HyperFleet API client configuration.
 // Alias to hyperfleetapi.ClientConfig to ensure shared schema.
 type HyperfleetAPIConfig = hyperfleetapi.ClientConfig
@@ -159,10 +332,178 @@ type HyperfleetAPIConfig = hyperfleetapi.ClientConfig
 // Alias to hyperfleetapi.AuthConfig to ensure shared schema.
 type HyperfleetAPIAuthConfig = hyperfleetapi.AuthConfig
 
-// BrokerConfig contains broker consumer configuration
+// HyperfleetAPIOAuth2Config is the HyperFleet API OAuth2 client credentials configuration.
+// Alias to hyperfleetapi.OAuth2Config to ensure shared schema.
+type HyperfleetAPIOAuth2Config = hyperfleetapi.OAuth2Config
+
+// HyperfleetAPIOIDCConfig is the HyperFleet API interactive OIDC login configuration.
+// Alias to hyperfleetapi.OIDCConfig to ensure shared schema.
+type HyperfleetAPIOIDCConfig = hyperfleetapi.OIDCConfig
+
+// HyperfleetAPICompressionConfig is the HyperFleet API compression configuration.
+// Alias to hyperfleetapi.CompressionConfig to ensure shared schema.
+type HyperfleetAPICompressionConfig = hyperfleetapi.CompressionConfig
+
+// BrokerConfig contains broker consumer and publisher configuration
 type BrokerConfig struct {
 	SubscriptionID string `yaml:"subscription_id,omitempty" mapstructure:"subscription_id"`
 	Topic          string `yaml:"topic,omitempty" mapstructure:"topic"`
+	// PublishTopic enables publishing execution result summaries to this topic. Empty disables publishing.
+	PublishTopic string `yaml:"publish_topic,omitempty" mapstructure:"publish_topic"`
+	// DeadLetterTopic receives events that still fail after MaxDeliveryAttempts. Empty disables the DLQ.
+	DeadLetterTopic string `yaml:"dead_letter_topic,omitempty" mapstructure:"dead_letter_topic"`
+	// AckMode is when the broker message of an event is acknowledged: "after_execute"
+	// (at-least-once) or "before_execute" (at-most-once). Empty uses after_execute.
+	AckMode string `yaml:"ack_mode" mapstructure:"ack_mode" validate:"omitempty,oneof=before_execute after_execute"`
+	// Type selects the broker backend: hyperfleet-broker, kafka, nats or http. Empty uses the
+	// backend whose config is set, and hyperfleet-broker when none is.
+	Type string `yaml:"type" mapstructure:"type" validate:"omitempty,oneof=hyperfleet-broker kafka nats http"`
+	// Kafka consumes the topics from Apache Kafka instead of the hyperfleet-broker configured
+	// by broker.yaml
+	Kafka *BrokerKafkaConfig `yaml:"kafka,omitempty" mapstructure:"kafka"`
+	// NATS consumes the topics, as subjects, from NATS JetStream instead of the
+	// hyperfleet-broker configured by broker.yaml
+	NATS *BrokerNATSConfig `yaml:"nats,omitempty" mapstructure:"nats"`
+	// HTTP receives the events of the topics as CloudEvents posted to an HTTP endpoint of the
+	// adapter, instead of consuming them from a broker
+	HTTP *BrokerHTTPConfig `yaml:"http,omitempty" mapstructure:"http"`
+	// Subscriptions subscribes to several topics, each with its own filter and task config.
+	// Replaces SubscriptionID and Topic, which configure a single subscription.
+	Subscriptions []BrokerSubscription `yaml:"subscriptions,omitempty" mapstructure:"subscriptions"`
+	// MaxDeliveryAttempts is how many times a failing event is executed before it is dead-lettered.
+	// Zero uses the default (3). Only used with DeadLetterTopic.
+	//nolint:lll
+	MaxDeliveryAttempts int `yaml:"max_delivery_attempts,omitempty" mapstructure:"max_delivery_attempts" validate:"gte=0"`
+	// Concurrency is the number of workers executing events. Events for the same cluster are
+	// executed one at a time by one worker, in the order they are handed over; messages a
+	// broker hands over at once are not kept in delivery order. Zero or one executes events
+	// serially as delivered.
+	Concurrency int `yaml:"concurrency,omitempty" mapstructure:"concurrency" validate:"gte=0"`
+}
+
+// BrokerKafkaConfig is the Kafka subscriber configuration.
+// Alias to kafkabroker.Config to ensure shared schema.
+type BrokerKafkaConfig = kafkabroker.Config
+
+// BrokerNATSConfig is the NATS JetStream subscriber configuration.
+// Alias to natsbroker.Config to ensure shared schema.
+type BrokerNATSConfig = natsbroker.Config
+
+// BrokerHTTPConfig is the HTTP CloudEvents receiver configuration.
+// Alias to httpreceiver.Config to ensure shared schema.
+type BrokerHTTPConfig = httpreceiver.Config
+
+// Broker types of clients.broker.type
+const (
+	// BrokerTypeHyperfleet consumes with the hyperfleet-broker library configured by broker.yaml
+	BrokerTypeHyperfleet = "hyperfleet-broker"
+	BrokerTypeKafka      = kafkabroker.BrokerType
+	BrokerTypeNATS       = natsbroker.BrokerType
+	BrokerTypeHTTP       = httpreceiver.BrokerType
+)
+
+// EffectiveType returns the configured broker type, or the type of the backend configured
+func (b BrokerConfig) EffectiveType() string {
+	switch {
+	case b.Type != "":
+		return b.Type
+	case b.Kafka != nil:
+		return BrokerTypeKafka
+	case b.NATS != nil:
+		return BrokerTypeNATS
+	case b.HTTP != nil:
+		return BrokerTypeHTTP
+	default:
+		return BrokerTypeHyperfleet
+	}
+}
+
+// BrokerSubscription is a topic subscription of the adapter
+type BrokerSubscription struct {
+	// Name identifies the subscription in logs. Empty uses the topic.
+	Name           string `yaml:"name,omitempty" mapstructure:"name"`
+	SubscriptionID string `yaml:"subscription_id" mapstructure:"subscription_id"`
+	Topic          string `yaml:"topic" mapstructure:"topic"`
+	// Filter is a CEL expression over the event, as the adapter's event_filter. Events for
+	// which it is not true are skipped. Empty executes every event.
+	Filter string `yaml:"filter,omitempty" mapstructure:"filter"`
+	// TaskConfig is the path or oci:// reference of the task config executing the events of
+	// the subscription. Empty uses the adapter's task config.
+	TaskConfig string `yaml:"task_config,omitempty" mapstructure:"task_config"`
+	// Routing selects the events of the subscription by their type and subject, as the
+	// adapter's event_routing. Events must match both.
+	Routing EventRoutingConfig `yaml:"routing,omitempty" mapstructure:"routing"`
+}
+
+// DisplayName returns the name of the subscription, or its topic when unnamed
+func (s BrokerSubscription) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Topic
+}
+
+// EventRoutingConfig selects broker events by their CloudEvent type and subject. Patterns
+// are globs as in path.Match: * matches any run of characters other than /.
+type EventRoutingConfig struct {
+	// Types are patterns of the event types to handle. Empty handles every type.
+	Types []string `yaml:"types,omitempty" mapstructure:"types"`
+	// Subjects are patterns of the event subjects to handle. Empty handles every subject.
+	Subjects []string `yaml:"subjects,omitempty" mapstructure:"subjects"`
+}
+
+// IsSet reports whether any pattern is configured
+func (r EventRoutingConfig) IsSet() bool {
+	return len(r.Types) > 0 || len(r.Subjects) > 0
+}
+
+// Matches reports whether an event of eventType and subject is routed to the adapter: its
+// type matches one of Types and its subject one of Subjects, when they are set
+func (r EventRoutingConfig) Matches(eventType, subject string) bool {
+	return matchesAnyPattern(r.Types, eventType) && matchesAnyPattern(r.Subjects, subject)
+}
+
+// matchesAnyPattern reports whether value matches one of patterns, true when there are none.
+// Invalid patterns are rejected by the validator, so they match nothing here.
+func matchesAnyPattern(patterns []string, value string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched { //nolint:errcheck // validated patterns
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveSubscriptions returns the configured subscriptions, or the single subscription of
+// SubscriptionID and Topic
+func (b BrokerConfig) EffectiveSubscriptions() []BrokerSubscription {
+	if len(b.Subscriptions) > 0 {
+		return b.Subscriptions
+	}
+	return []BrokerSubscription{{SubscriptionID: b.SubscriptionID, Topic: b.Topic}}
+}
+
+// Broker ack modes
+const (
+	// AckModeAfterExecute acknowledges a message once its event has executed, so an event
+	// interrupted by a crash is redelivered (at-least-once)
+	AckModeAfterExecute = "after_execute"
+	// AckModeBeforeExecute acknowledges a message once its event is queued for execution,
+	// so an event is never redelivered but is lost if the adapter dies first (at-most-once)
+	AckModeBeforeExecute = "before_execute"
+)
+
+// EffectiveAckMode returns the configured ack mode, or after_execute when unset.
+// A worker pool does not change the default: events are only acked at-most-once when
+// before_execute is configured explicitly.
+func (b BrokerConfig) EffectiveAckMode() string {
+	if b.AckMode != "" {
+		return b.AckMode
+	}
+	return AckModeAfterExecute
 }
 
 // KubernetesConfig contains Kubernetes configuration
@@ -170,10 +511,15 @@ type KubernetesConfig struct {
 	APIVersion string `yaml:"api_version" mapstructure:"api_version"`
 	// KubeConfigPath is the path to a kubeconfig file. Empty means in-cluster auth.
 	KubeConfigPath string `yaml:"kube_config_path,omitempty" mapstructure:"kube_config_path"`
-	// QPS is the client-side rate limit. Zero uses defaults.
-	QPS float32 `yaml:"qps,omitempty" mapstructure:"qps"`
 	// Burst is the client-side burst rate. Zero uses defaults.
 	Burst int `yaml:"burst,omitempty" mapstructure:"burst"`
+	// QPS is the client-side rate limit. Zero uses defaults.
+	QPS float32 `yaml:"qps,omitempty" mapstructure:"qps"`
+	// ManageNamespaces sets ensure_namespace on every kubernetes transport resource
+	ManageNamespaces bool `yaml:"manage_namespaces,omitempty" mapstructure:"manage_namespaces"`
+	// MaxConcurrentWrites caps the applies, deletes and updates sent to the API server at
+	// once, across all executing events. Zero is unlimited.
+	MaxConcurrentWrites int `yaml:"max_concurrent_writes" mapstructure:"max_concurrent_writes" validate:"gte=0"`
 }
 
 // ParameterSource is the source field on Parameter
@@ -194,6 +540,9 @@ const (
 	paramSourceKindFile       = "file"
 )
 
+// VaultSourcePrefix is the prefix of string sources resolved from HashiCorp Vault
+const VaultSourcePrefix = "vault."
+
 // StringSource constructs a string-literal ParameterSource.
 func StringSource(s string) ParameterSource {
 	return ParameterSource{Kind: paramSourceKindString, StringVal: s}
@@ -224,6 +573,29 @@ func (ps ParameterSource) IsExpression() bool { return ps.Kind == paramSourceKin
 
 func (ps ParameterSource) IsFile() bool { return ps.Kind == paramSourceKindFile }
 
+// IsVault returns true for vault.<path>#<key> string sources
+func (ps ParameterSource) IsVault() bool {
+	return ps.Kind == paramSourceKindString && strings.HasPrefix(ps.StringVal, VaultSourcePrefix)
+}
+
+// VaultRef returns the secret path and key of a vault.<path>#<key> source
+func (ps ParameterSource) VaultRef() (path, key string, err error) {
+	if !ps.IsVault() {
+		return "", "", fmt.Errorf("source %q is not a vault source", ps.StringVal)
+	}
+	ref := strings.TrimPrefix(ps.StringVal, VaultSourcePrefix)
+	path, key, found := strings.Cut(ref, "#")
+	path = strings.Trim(path, "/")
+	if !found || path == "" || key == "" {
+		return "", "", fmt.Errorf("source %q must have the form %s<path>#<key>", ps.StringVal, VaultSourcePrefix)
+	}
+	if !strings.Contains(path, "/") {
+		return "", "", fmt.Errorf("source %q must name the secrets engine mount and a secret path, "+
+			"e.g. %ssecret/my-app#password", ps.StringVal, VaultSourcePrefix)
+	}
+	return path, key, nil
+}
+
 // Describe returns a human-readable description for use in error messages
 func (ps ParameterSource) Describe() string {
 	switch ps.Kind {
@@ -372,21 +744,88 @@ type ActionBase struct {
 // Must have at least one of: APICall (from ActionBase), Expression, or Conditions.
 type Precondition struct {
 	ActionBase `yaml:",inline"`
-	Expression string         `yaml:"expression,omitempty" validate:"required_without_all=ActionBase.APICall Conditions"`
-	Capture    []CaptureField `yaml:"capture,omitempty" validate:"dive"`
+	Expression string `yaml:"expression,omitempty" validate:"required_without_all=ActionBase.APICall Conditions"`
 	//nolint:lll
 	Conditions []Condition `yaml:"conditions,omitempty" validate:"dive,required_without_all=ActionBase.APICall Expression"`
+	// OnError is what a failure of the precondition does: abort (default) fails the
+	// execution, continue records it as a warning and evaluates the next precondition
+	OnError string `yaml:"on_error,omitempty" validate:"omitempty,oneof=abort continue"`
+	// ForEach is a CEL expression evaluating to a list. The api_call is made once per
+	// item, with the item and its position as item and index, and the list of responses
+	// is stored under the precondition name.
+	ForEach string         `yaml:"for_each,omitempty"`
+	Capture []CaptureField `yaml:"capture,omitempty" validate:"dive"`
 }
 
 // APICall represents an API call configuration
 type APICall struct {
-	Method        string   `yaml:"method" validate:"required,oneof=GET POST PUT PATCH DELETE"`
-	URL           string   `yaml:"url" validate:"required"`
-	Timeout       string   `yaml:"timeout,omitempty"`
-	RetryBackoff  string   `yaml:"retry_backoff,omitempty"`
-	Body          string   `yaml:"body,omitempty"`
-	Headers       []Header `yaml:"headers,omitempty"`
-	RetryAttempts int      `yaml:"retry_attempts,omitempty"`
+	Method       string   `yaml:"method" validate:"required,oneof=GET POST PUT PATCH DELETE"`
+	URL          string   `yaml:"url" validate:"required"`
+	RetryBackoff string   `yaml:"retry_backoff,omitempty"`
+	Body         string   `yaml:"body,omitempty"`
+	Headers      []Header `yaml:"headers,omitempty"`
+	// Patch sends an RFC 6902 JSON Patch instead of Body (PATCH only), so a call can
+	// change single fields without replacing the whole object
+	Patch []JSONPatchOperation `yaml:"patch,omitempty" validate:"omitempty,excluded_with=Body,dive"`
+	// ResponseFields, when set, keeps only these dot-separated paths of the JSON response.
+	// The response is decoded as it streams in and its raw body is not kept unless
+	// KeepRawResponse is set, which bounds memory for large inventory-style responses.
+	ResponseFields []string `yaml:"response_fields,omitempty" validate:"omitempty,dive,required"`
+	// BodyEncoding selects how Body is sent: "json" (default) sends it as rendered;
+	// "form" and "multipart" need it to render to a JSON object, whose fields are sent
+	// as form fields.
+	BodyEncoding string `yaml:"body_encoding,omitempty" validate:"omitempty,oneof=json form multipart"`
+	// Transform is a CEL expression reshaping the response before it is stored under the
+	// step name; the fields of the response are its variables. Params and preconditions only.
+	Transform string `yaml:"transform,omitempty"`
+	// Files are the file parts of a multipart body
+	Files           []FilePart `yaml:"files,omitempty" validate:"omitempty,dive"`
+	Timeout         Duration   `yaml:"timeout,omitempty"`
+	RetryAttempts   int        `yaml:"retry_attempts,omitempty"`
+	KeepRawResponse bool       `yaml:"keep_raw_response,omitempty"`
+}
+
+// FilePart is a file sent in a multipart api_call body. Its content comes from Content,
+// rendered with the params, or from the file at Path, read when the call is made.
+//
+// Example YAML:
+//
+//	files:
+//	  - name: kubeconfig
+//	    filename: "{{ .clusterId }}.kubeconfig"
+//	    content: "{{ .kubeconfig }}"
+//	  - name: report
+//	    path: /tmp/report.tar.gz
+//	    content_type: application/gzip
+type FilePart struct {
+	// Name is the form field name
+	Name string `yaml:"name" validate:"required"`
+	// Filename is sent to the server (templated, default the base name of Path or Name)
+	Filename string `yaml:"filename,omitempty"`
+	// ContentType of the part (default application/octet-stream)
+	ContentType string `yaml:"content_type,omitempty"`
+	// Content is the file content (templated)
+	Content string `yaml:"content,omitempty" validate:"required_without=Path,excluded_with=Path"`
+	// Path is a file to send (templated), such as a file written by an earlier step
+	Path string `yaml:"path,omitempty"`
+}
+
+// JSONPatchOperation is one operation of an api_call JSON Patch.
+//
+// Example YAML:
+//
+//	patch:
+//	  - op: replace
+//	    path: /status/conditions/0/status
+//	    value_expression: "adapter.executionStatus == 'success' ? 'True' : 'False'"
+type JSONPatchOperation struct {
+	Op string `yaml:"op" validate:"required,oneof=add remove replace move copy test"`
+	// Path is a JSON Pointer and may use Go templates like the URL
+	Path string `yaml:"path" validate:"required"`
+	// From is the source JSON Pointer of move and copy
+	From string `yaml:"from,omitempty"`
+	// ValueExpression is a CEL expression giving the value of add, replace and test
+	ValueExpression string `yaml:"value_expression,omitempty"`
 }
 
 // FileSourceConfig defines a file-based parameter source.
@@ -413,12 +852,17 @@ type Header struct {
 // Default applies only to field: captures. When the field is absent from the API response,
 // Default is used and no WARN is logged. Ignored for expression: captures.
 // Note: null/nil defaults are not supported — use a typed value (false, "", 0).
+//
+// Transform normalizes the captured value before it is stored: lower, trim, toInt, toBool,
+// or template:<Go template> rendered with the value as .value and the params.
 type CaptureField struct {
 	// Default value to use when the field is absent from the API response.
 	// Only effective for field: captures; ignored for expression: captures.
 	Default            interface{} `yaml:"default,omitempty"`
 	Name               string      `yaml:"name" validate:"required"`
 	FieldExpressionDef `yaml:",inline"`
+	// Transform is applied to the captured value, including a default
+	Transform string `yaml:"transform,omitempty"`
 }
 
 // Condition represents a structured condition
@@ -467,14 +911,98 @@ func (c *Condition) UnmarshalYAML(unmarshal func(interface{}) error) error {
 type TransportConfig struct {
 	// Maestro contains maestro-specific transport settings (required when Client is "maestro")
 	Maestro *MaestroTransportConfig `yaml:"maestro,omitempty"`
-	// Client is the transport client type: "kubernetes" or "maestro"
-	Client string `yaml:"client" validate:"required,oneof=kubernetes maestro"`
+	// GitOps contains gitops-specific transport settings (required when Client is "gitops")
+	GitOps *GitOpsTransportConfig `yaml:"gitops,omitempty"`
+	// Client is the transport client type: "kubernetes", "maestro", "gitops" or another
+	// registered backend
+	Client string `yaml:"client" validate:"required,transportclient"`
+}
+
+// GitOpsTransportConfig contains gitops-specific transport settings
+type GitOpsTransportConfig struct {
+	// Path is the directory of the repository the manifests are written to (template)
+	Path string `yaml:"path" validate:"required"`
+	// CommitMessage is the message of the commits writing the manifests (template). Empty
+	// describes the operation, e.g. "Update ConfigMap default/app".
+	CommitMessage string `yaml:"commit_message,omitempty"`
 }
 
 // MaestroTransportConfig contains maestro-specific transport settings
 type MaestroTransportConfig struct {
+	// Ordering sorts the manifests of the ManifestWork by kind, and can split them into
+	// ManifestWorks applied one after the other
+	Ordering *ManifestOrderingConfig `yaml:"ordering,omitempty"`
+	// Placement sets labels and annotations on the ManifestWork for downstream placement controllers
+	Placement *MaestroPlacementConfig `yaml:"placement,omitempty"`
 	// TargetCluster is the name of the target cluster (consumer) for ManifestWork delivery
 	TargetCluster string `yaml:"target_cluster" validate:"required"`
+	// FeedbackRules request status feedback for workload manifests, set as the
+	// ManifestWork's spec.manifestConfigs
+	FeedbackRules []ManifestFeedbackRule `yaml:"feedback_rules,omitempty" validate:"dive"`
+}
+
+// ManifestFeedbackRule asks the work agent to report status fields of the workload manifests
+// it selects back in status.resourceStatus.manifests[].statusFeedback
+type ManifestFeedbackRule struct {
+	// Kind selects the workload manifests of this kind
+	Kind string `yaml:"kind" validate:"required"`
+	// Name selects the manifest with this name (template). Empty selects every manifest of Kind.
+	Name string `yaml:"name,omitempty"`
+	// Namespace selects the manifests in this namespace (template). Empty selects any namespace.
+	Namespace string `yaml:"namespace,omitempty"`
+	// Resource is the plural resource name of Kind (e.g. "deployments"), guessed from Kind when empty
+	Resource string `yaml:"resource,omitempty"`
+	// JSONPaths are the status fields to report, each under its name
+	JSONPaths []FeedbackJSONPath `yaml:"json_paths,omitempty" validate:"dive"`
+	// WellKnownStatus reports the status fields the work agent knows for the kind,
+	// such as readyReplicas for a Deployment
+	WellKnownStatus bool `yaml:"well_known_status,omitempty"`
+}
+
+// FeedbackJSONPath is a status field reported by a feedback rule
+type FeedbackJSONPath struct {
+	// Name is the name the value is reported under
+	Name string `yaml:"name" validate:"required"`
+	// Path is a JSONPath into the manifest, e.g. ".status.readyReplicas"
+	Path string `yaml:"path" validate:"required"`
+	// Version is the API version to read the resource with. Empty uses the manifest's.
+	Version string `yaml:"version,omitempty"`
+}
+
+// ManifestOrderingConfig orders the manifests of a ManifestWork by tiers of kinds.
+// Manifests whose kind is in no tier form the last tier, "workloads".
+type ManifestOrderingConfig struct {
+	// Tiers replaces the default tiers: namespaces, crds, rbac
+	Tiers []ManifestTier `yaml:"tiers,omitempty" validate:"dive"`
+	// Split delivers each tier as its own ManifestWork. A tier is only applied once the
+	// ManifestWork of the previous tier is unchanged and reported as applied.
+	Split bool `yaml:"split,omitempty"`
+}
+
+// ManifestTier is a named group of manifest kinds applied together
+type ManifestTier struct {
+	Name  string   `yaml:"name" validate:"required"`
+	Kinds []string `yaml:"kinds" validate:"required,min=1"`
+}
+
+// KindTiers returns the configured tiers, or the default tiers when none are set
+func (o *ManifestOrderingConfig) KindTiers() []manifest.KindTier {
+	if len(o.Tiers) == 0 {
+		return manifest.DefaultKindTiers
+	}
+	tiers := make([]manifest.KindTier, len(o.Tiers))
+	for i, tier := range o.Tiers {
+		tiers[i] = manifest.KindTier{Name: tier.Name, Kinds: tier.Kinds}
+	}
+	return tiers
+}
+
+// MaestroPlacementConfig holds ManifestWork metadata consumed by placement controllers.
+// Keys must be valid Kubernetes qualified names; values are Go templates rendered
+// with the execution params. Entries override the same keys set in the manifest.
+type MaestroPlacementConfig struct {
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
 }
 
 // Resource represents a resource configuration.
@@ -486,14 +1014,257 @@ type Resource struct {
 	Transport *TransportConfig `yaml:"transport,omitempty"`
 	Manifest  interface{}      `yaml:"manifest,omitempty"`
 	Discovery *DiscoveryConfig `yaml:"discovery,omitempty" validate:"required"`
+	// Lifecycle defines the resource lifecycle behavior, including deletion triggers and policy.
+	// If not set, the resource uses the default apply-only behavior.
+	Lifecycle *ResourceLifecycle `yaml:"lifecycle,omitempty"`
+	// ApplyStrategy selects how the kubernetes transport writes the resource:
+	// "update" (default) replaces the whole object, "server_side_apply" uses server-side apply.
+	ApplyStrategy string `yaml:"apply_strategy,omitempty" validate:"omitempty,oneof=update server_side_apply"`
+	// FieldManager is the server-side apply field manager (default "hyperfleet-adapter")
+	FieldManager string `yaml:"field_manager,omitempty"`
+	// Retry re-runs the resource when it fails, before the failure is recorded
+	Retry *RetryPolicy `yaml:"retry,omitempty" validate:"omitempty"`
+	// ManifestRef is a file, relative to the task config, holding the manifest as one or
+	// more YAML documents. It is an alternative to Manifest and is rendered the same way.
+	ManifestRef string `yaml:"manifest_ref,omitempty"`
+	// DriftPolicy decides what happens when the live object diverges from the manifest
+	// although its generation is unchanged: "ignore" (default) skips it, "report" records
+	// the drift and "reconcile" also re-applies the manifest. Kubernetes transport only.
+	DriftPolicy string `yaml:"drift_policy,omitempty" validate:"omitempty,oneof=ignore report reconcile"`
+	// Helm renders a Helm chart as the resource's manifests. It is an alternative to
+	// Manifest and ManifestRef.
+	Helm *HelmConfig `yaml:"helm,omitempty" validate:"omitempty"`
 	// NestedDiscoveries defines how to discover individual sub-resources
 	// within the applied manifest. For example, discovering resources
 	// inside a ManifestWork's workload.
-	// Lifecycle defines the resource lifecycle behavior, including deletion triggers and policy.
-	// If not set, the resource uses the default apply-only behavior.
-	Lifecycle         *ResourceLifecycle `yaml:"lifecycle,omitempty"`
-	NestedDiscoveries []NestedDiscovery  `yaml:"nested_discoveries,omitempty" validate:"dive"`
-	RecreateOnChange  bool               `yaml:"recreate_on_change,omitempty"`
+	NestedDiscoveries []NestedDiscovery `yaml:"nested_discoveries,omitempty" validate:"dive"`
+	// OnError is what a failure of the resource does: abort stops the resources phase,
+	// continue records it as a warning and runs the next resources. By default a failed
+	// apply aborts, while a failed delete lets the other resources run and then fails the phase.
+	OnError string `yaml:"on_error,omitempty" validate:"omitempty,oneof=abort continue"`
+	// Transform is a CEL expression reshaping the discovered object, whose fields are its
+	// variables, into the value of resources.<name> once the resource step has run
+	Transform string `yaml:"transform,omitempty"`
+	// ForEach is a CEL expression evaluating to a list. The resource is applied once per
+	// item, as the resource named ForEachItemName(Name, index), with the item and its
+	// position as item and index; resources.<name> is the list of their objects.
+	ForEach string `yaml:"for_each,omitempty"`
+	// DependsOn names earlier resources that must finish before this one runs.
+	// Setting it implies Parallel.
+	DependsOn        []string `yaml:"depends_on,omitempty"`
+	RecreateOnChange bool     `yaml:"recreate_on_change,omitempty"`
+	// Parallel lets the resource run as soon as the resources in DependsOn are done,
+	// instead of waiting for every resource declared before it.
+	Parallel bool `yaml:"parallel,omitempty"`
+	// EnsureNamespace creates the namespaces of the resource's manifests that do not exist,
+	// labeled as managed by the adapter. Kubernetes transport only.
+	EnsureNamespace bool `yaml:"ensure_namespace,omitempty"`
+}
+
+// HelmConfig renders a chart into the manifests of a resource. With the kubernetes
+// transport the first manifest in install order is the resource's own object and the
+// others are applied with it; with the maestro transport every manifest goes into one
+// ManifestWork.
+type HelmConfig struct {
+	// Values are merged over the chart's values.yaml. Strings are rendered as templates
+	// with the execution params, like manifests.
+	Values map[string]interface{} `yaml:"values,omitempty"`
+	// LoadedChart is the chart, loaded or pulled when the config is loaded
+	LoadedChart *helm.Chart `yaml:"-"`
+	// Chart is a chart directory or .tgz archive relative to the task config, or an
+	// oci:// reference
+	Chart string `yaml:"chart" validate:"required"`
+	// Version is the tag of an oci:// chart without one, or the version a local chart
+	// must declare
+	Version string `yaml:"version,omitempty"`
+	// ReleaseName is the release name templates see as .Release.Name (templated)
+	ReleaseName string `yaml:"release_name" validate:"required"`
+	// Namespace is the release namespace, set on namespaced objects without one (templated)
+	Namespace string `yaml:"namespace" validate:"required"`
+	// ManifestWorkName names the ManifestWork holding the manifests with the maestro
+	// transport (templated, default the release name)
+	ManifestWorkName string `yaml:"manifest_work_name,omitempty"`
+}
+
+// GetManifestWorkName returns the name of the ManifestWork holding the chart's manifests
+func (h *HelmConfig) GetManifestWorkName() string {
+	if h.ManifestWorkName != "" {
+		return h.ManifestWorkName
+	}
+	return h.ReleaseName
+}
+
+// RetryPolicy.RetryOn values. "retryable" matches any error classified as transient;
+// the others match an error category regardless of classification.
+const (
+	RetryOnRetryable = "retryable"
+	RetryOnAPI       = "api"
+	RetryOnTransport = "transport"
+	RetryOnTimeout   = "timeout"
+)
+
+// RetryPolicy re-runs a failed resource or post-action.
+// The HyperFleet API client already retries individual requests (retry_attempts on
+// api_call); this policy retries the whole step, including rendering and discovery.
+type RetryPolicy struct {
+	// Backoff is exponential (default), linear or constant
+	Backoff string `yaml:"backoff,omitempty" validate:"omitempty,oneof=exponential linear constant"`
+	// RetryOn lists the errors that are retried (default [retryable])
+	RetryOn []string `yaml:"retry_on,omitempty" validate:"omitempty,dive,oneof=retryable api transport timeout"`
+	// BaseDelay is the delay before the first retry (default 1s)
+	BaseDelay Duration `yaml:"base_delay,omitempty"`
+	// MaxDelay caps the delay between retries (default 30s)
+	MaxDelay Duration `yaml:"max_delay,omitempty"`
+	// Attempts is the total number of attempts, including the first one
+	Attempts int `yaml:"attempts" validate:"required,min=1,max=10"`
+}
+
+// ForEachItemName returns the name of the item at index of the for_each resource name
+func ForEachItemName(name string, index int) string {
+	return fmt.Sprintf("%s[%d]", name, index)
+}
+
+// isForEachItemOf reports whether itemName is the name of an item of the for_each resource name
+func isForEachItemOf(itemName, name string) bool {
+	index, ok := strings.CutPrefix(itemName, name+"[")
+	if !ok {
+		return false
+	}
+	index, ok = strings.CutSuffix(index, "]")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(index)
+	return err == nil
+}
+
+// Dependencies returns the indexes of the resources that must finish before the
+// resource at index i runs. Sequential resources depend on every earlier resource;
+// parallel resources only on those named in DependsOn, where the name of a for_each
+// resource stands for all of its items. Unknown names are ignored (the validator
+// rejects them).
+func Dependencies(resources []Resource, i int) []int {
+	r := resources[i]
+	if !r.Parallel && len(r.DependsOn) == 0 {
+		deps := make([]int, i)
+		for j := range deps {
+			deps[j] = j
+		}
+		return deps
+	}
+	deps := make([]int, 0, len(r.DependsOn))
+	for _, name := range r.DependsOn {
+		for j := 0; j < i; j++ {
+			if resources[j].Name == name || isForEachItemOf(resources[j].Name, name) {
+				deps = append(deps, j)
+			}
+		}
+	}
+	return deps
+}
+
+// PruneStep deletes the resources of a kind that match a label selector but were not
+// applied by the resources phase of the same execution, such as ConfigMaps left behind
+// for decommissioned clusters. Prune steps run after every resource succeeded, and
+// only with the kubernetes transport.
+type PruneStep struct {
+	// LabelSelector selects the candidates; keys and values are Go templates
+	LabelSelector map[string]string `yaml:"label_selector" validate:"required,min=1"`
+	Name          string            `yaml:"name" validate:"required,resourcename"`
+	APIVersion    string            `yaml:"api_version" validate:"required"`
+	Kind          string            `yaml:"kind" validate:"required"`
+	// Namespace limits the candidates to one namespace (template); empty means all namespaces
+	Namespace string `yaml:"namespace,omitempty"`
+	// PropagationPolicy is the Kubernetes deletion propagation policy (default Background)
+	PropagationPolicy string `yaml:"propagationPolicy,omitempty" validate:"omitempty,oneof=Background Foreground Orphan"`
+}
+
+// WaitStep polls a resource of the resources phase, or a HyperFleet API endpoint,
+// until a CEL condition is true or the timeout elapses. Wait steps run after the
+// resources phase (and prune steps) succeeded, before post-processing. The condition
+// sees the fields of the polled object as variables, e.g. status.phase == "Ready".
+type WaitStep struct {
+	// APICall polls a HyperFleet API endpoint; exactly one of Resource and APICall is set
+	APICall *APICall `yaml:"api_call,omitempty" validate:"required_without=Resource,excluded_with=Resource"`
+	Name    string   `yaml:"name" validate:"required,resourcename"`
+	// ManifestWork waits for the status conditions the work agent reports on the
+	// ManifestWork of a maestro resource
+	ManifestWork *ManifestWorkWait `yaml:"manifest_work,omitempty" validate:"omitempty,excluded_with=APICall"`
+	// Resource names the resource whose discovered object is polled
+	Resource string `yaml:"resource,omitempty"`
+	// Condition is a CEL expression; optional with ManifestWork, in which case both must hold
+	Condition string `yaml:"condition,omitempty" validate:"required_without=ManifestWork"`
+	// OnError is what a failure of the wait step does: abort (default) fails the resources
+	// phase, continue records it as a warning and runs the next wait step
+	OnError string `yaml:"on_error,omitempty" validate:"omitempty,oneof=abort continue"`
+	// Timeout bounds the wait (default 5m); the step fails when it elapses
+	Timeout Duration `yaml:"timeout,omitempty"`
+	// Interval is the delay between polls (default 5s)
+	Interval Duration `yaml:"interval,omitempty"`
+}
+
+// ManifestWorkWait waits until the conditions of a ManifestWork are True. Once they are, the
+// reported status is stored in the param named after the wait step: "conditions" maps the
+// work's condition types to their status, and "manifests" lists the kind, name, namespace,
+// condition statuses and status feedback values of each manifest.
+type ManifestWorkWait struct {
+	// Conditions are the condition types that must be True (default Applied and Available)
+	Conditions []string `yaml:"conditions,omitempty"`
+}
+
+// GetConditions returns the condition types to wait for, Applied and Available by default
+func (w *ManifestWorkWait) GetConditions() []string {
+	if len(w.Conditions) == 0 {
+		return []string{"Applied", "Available"}
+	}
+	return w.Conditions
+}
+
+// PlatformConfig resolves the operating system and CPU architecture of the spoke cluster
+// and selects platform-specific values (image tags, node selectors) from declarative maps,
+// so that one config serves clusters of different platforms. The result is stored in the
+// "platform" param before the resources are applied, and again before the post actions.
+type PlatformConfig struct {
+	// Maps are values selected by platform, keyed by "os/arch", "arch", "os" or "default"
+	Maps map[string]map[string]interface{} `yaml:"maps,omitempty"`
+	// OS is a CEL expression giving the spoke operating system (default "linux")
+	OS string `yaml:"os,omitempty"`
+	// Architecture is a CEL expression giving the spoke CPU architecture (default "amd64")
+	Architecture string `yaml:"architecture,omitempty"`
+}
+
+// Event overlay precedence constants
+const (
+	// OverlayPrecedenceData fills the field only when the event data does not set it
+	OverlayPrecedenceData = "data"
+	// OverlayPrecedenceAttribute sets the field even when the event data sets it
+	OverlayPrecedenceAttribute = "attribute"
+)
+
+// EventOverlay sets an event data field from a CloudEvent attribute, for producers that
+// send some metadata as broker attributes instead of in the payload. Overlays are applied
+// in order before the params are extracted.
+type EventOverlay struct {
+	// Attribute is a CloudEvent context attribute (id, source, type, subject, time) or
+	// extension attribute. Overlays of attributes the event does not have are ignored.
+	Attribute string `yaml:"attribute" validate:"required"`
+	// Field is the dot-separated path of the event data field to set; missing parent
+	// objects are created
+	Field string `yaml:"field" validate:"required"`
+	// Precedence decides which value wins when the event data already sets the field:
+	// "data" (default) or "attribute"
+	Precedence string `yaml:"precedence,omitempty" validate:"omitempty,oneof=data attribute"`
+	// Type converts the attribute value, which is a string, before it is set: string
+	// (default), int, int64, float, float64 or bool
+	Type string `yaml:"type,omitempty" validate:"omitempty,oneof=string int int64 float float64 bool"`
+}
+
+// GetPrecedence returns the precedence of the overlay, defaulting to OverlayPrecedenceData
+func (o *EventOverlay) GetPrecedence() string {
+	if o.Precedence == "" {
+		return OverlayPrecedenceData
+	}
+	return o.Precedence
 }
 
 // ResourceLifecycle defines the lifecycle behavior for a resource.
@@ -509,6 +1280,13 @@ type LifecycleDelete struct {
 	// PropagationPolicy is the Kubernetes deletion propagation policy: Background (default), Foreground, Orphan.
 	// For Maestro transport, this is ignored — ManifestWork handles its own cleanup semantics.
 	PropagationPolicy string `yaml:"propagationPolicy,omitempty"`
+	// Finalizer is added to the resource when it is applied and removed only after the
+	// teardown steps succeeded on deletion, so Kubernetes keeps the object until the
+	// external cleanup is done. Kubernetes transport only.
+	Finalizer string `yaml:"finalizer,omitempty"`
+	// Teardown runs when the resource is deleted while it still carries Finalizer, before
+	// the finalizer is removed. A failed step keeps the finalizer for the next event.
+	Teardown []ActionBase `yaml:"teardown,omitempty" validate:"dive"`
 }
 
 type LifecycleCreate struct {
@@ -530,12 +1308,19 @@ type NestedDiscovery struct {
 	Name      string           `yaml:"name" validate:"required,resourcename"`
 }
 
-// DiscoveryConfig represents resource discovery configuration
+// DiscoveryConfig represents resource discovery configuration.
+// When both by_name and by_selectors are set, they are tried in Order (default: by_name first)
+// and a later method is only used when the earlier ones find nothing.
 type DiscoveryConfig struct {
-	BySelectors *SelectorConfig `yaml:"by_selectors,omitempty" validate:"required_without=ByName,excluded_with=ByName"`
+	BySelectors *SelectorConfig `yaml:"by_selectors,omitempty" validate:"required_without=ByName"`
 	Namespace   string          `yaml:"namespace,omitempty"`
-	//nolint:lll
-	ByName string `yaml:"by_name,omitempty" validate:"required_without=BySelectors,excluded_with=BySelectors"`
+	ByName      string          `yaml:"by_name,omitempty" validate:"required_without=BySelectors"`
+	MatchPolicy string          `yaml:"match_policy,omitempty" validate:"omitempty,oneof=newest_generation fail_on_multi"`
+	Order       []string        `yaml:"order,omitempty" validate:"omitempty,dive,oneof=by_name by_selectors"`
+	// RequireAll makes by_name and by_selectors a single lookup: the object named by_name is
+	// only discovered when it also carries the selector labels. A same-named object without
+	// them fails discovery instead of being taken over.
+	RequireAll bool `yaml:"require_all,omitempty"`
 }
 
 // SelectorConfig represents label selector configuration
@@ -560,6 +1345,23 @@ type PostAction struct {
 	// If the expression evaluates to false, the action is skipped (not failed).
 	// Follows the same nested pattern as lifecycle.delete.when for consistency.
 	When *PostActionWhen `yaml:"when,omitempty"`
+	// Phase is when the action runs: "post_actions" (default) actions run in order and
+	// stop at the first failure; "reporting" actions run after them and always run, even
+	// when an earlier step failed, panicked or timed out.
+	Phase string `yaml:"phase,omitempty" validate:"omitempty,oneof=post_actions reporting"`
+	// Retry re-runs the post-action when it fails, before the failure is recorded
+	Retry *RetryPolicy `yaml:"retry,omitempty" validate:"omitempty"`
+	// OnError is what a failure of the post-action does: abort (default) skips the
+	// remaining post-actions, continue records it as a warning and runs them
+	OnError string `yaml:"on_error,omitempty" validate:"omitempty,oneof=abort continue"`
+}
+
+// EffectivePhase returns the phase the action runs in, post_actions when Phase is unset
+func (a *PostAction) EffectivePhase() string {
+	if a.Phase == "" {
+		return PostActionPhasePostActions
+	}
+	return a.Phase
 }
 
 // PostActionWhen defines the condition for when a post-action should execute.
@@ -640,37 +1442,162 @@ func (ve *ValidationErrors) HasErrors() bool {
 	return len(ve.Errors) > 0
 }
 
+// configPathPattern matches config paths such as "resources[0].manifest.ref"
+var configPathPattern = regexp.MustCompile(`^[A-Za-z_]\w*(\[\d+\])*(\.[A-Za-z_]\w*(\[\d+\])*)*$`)
+
+// ValidationErrorsOf returns the validation errors carried by err. For other errors,
+// each "\n  - " list item becomes an entry and the first segment of its "a: b: c" chain
+// that looks like a config path is used as Path; errors without one (unreadable file,
+// invalid YAML, ...) have an empty Path and the full error text as Message.
+func ValidationErrorsOf(err error) []ValidationError {
+	if err == nil {
+		return nil
+	}
+	var errs *ValidationErrors
+	if errors.As(err, &errs) && errs.HasErrors() {
+		return errs.Errors
+	}
+	var single *ValidationError
+	if errors.As(err, &single) {
+		return []ValidationError{*single}
+	}
+
+	items := strings.Split(err.Error(), "\n  - ")
+	if len(items) > 1 {
+		items = items[1:]
+	}
+	out := make([]ValidationError, 0, len(items))
+	for _, item := range items {
+		out = append(out, splitConfigPath(item))
+	}
+	return out
+}
+
+// splitConfigPath splits msg at the first ": "-separated segment that is a config path
+func splitConfigPath(msg string) ValidationError {
+	segments := strings.Split(msg, ": ")
+	for i, segment := range segments[:len(segments)-1] {
+		if strings.ContainsAny(segment, ".[") && configPathPattern.MatchString(segment) {
+			return ValidationError{Path: segment, Message: strings.Join(segments[i+1:], ": ")}
+		}
+	}
+	return ValidationError{Message: msg}
+}
+
 // AdapterConfig represents the deployment-level configuration.
 // Contains infrastructure settings that can be overridden via environment variables
 // and CLI flags using Viper.
 type AdapterConfig struct {
-	Adapter     AdapterInfo   `yaml:"adapter" mapstructure:"adapter"`
-	Log         LogConfig     `yaml:"log,omitempty" mapstructure:"log"`
-	Clients     ClientsConfig `yaml:"clients" mapstructure:"clients"`
-	DebugConfig bool          `yaml:"debug_config,omitempty" mapstructure:"debug_config"`
+	Adapter            AdapterInfo              `yaml:"adapter" mapstructure:"adapter"`
+	Log                LogConfig                `yaml:"log,omitempty" mapstructure:"log"`
+	Sharding           ShardingConfig           `yaml:"sharding,omitempty" mapstructure:"sharding"`
+	Notifications      NotificationsConfig      `yaml:"notifications,omitempty" mapstructure:"notifications"`
+	Deduplication      DeduplicationConfig      `yaml:"deduplication,omitempty" mapstructure:"deduplication"`
+	SLI                SLIConfig                `yaml:"sli,omitempty" mapstructure:"sli"`
+	Schedules          []ScheduleConfig         `yaml:"schedules,omitempty" mapstructure:"schedules" validate:"dive"`
+	Admin              AdminConfig              `yaml:"admin,omitempty" mapstructure:"admin"`
+	ExecutionRecording ExecutionRecordingConfig `yaml:"execution_recording,omitempty" mapstructure:"execution_recording"`
+	// Readiness selects the dependency checks that /readyz includes
+	Readiness ReadinessConfig `yaml:"readiness,omitempty" mapstructure:"readiness"`
+	// EventFilter is a CEL expression over the broker events: their attributes, extensions
+	// and data. Events for which it is not true are skipped before execution.
+	EventFilter string `yaml:"event_filter,omitempty" mapstructure:"event_filter"`
+	// EventRouting selects the broker events to handle by their type and subject. The others
+	// are acked when they are received, without decoding their data.
+	EventRouting EventRoutingConfig `yaml:"event_routing,omitempty" mapstructure:"event_routing"`
+	// MissingEnvVars is what loading does when an env.* param without default has no
+	// value: "warn" (default) or "fail". Missing values of required params always fail.
+	MissingEnvVars string `yaml:"missing_env_vars" mapstructure:"missing_env_vars" validate:"omitempty,oneof=warn fail"`
+	// JSONNumbers is how numbers of event data and API responses are decoded: "float"
+	// (default) decodes every number as a float64, "strict" decodes integers as int64.
+	JSONNumbers string `yaml:"json_numbers" mapstructure:"json_numbers" validate:"omitempty,oneof=float strict"`
+	// Clients configures the external clients
+	Clients ClientsConfig `yaml:"clients" mapstructure:"clients"`
+	Limits  LimitsConfig  `yaml:"limits,omitempty" mapstructure:"limits"`
+	// TrafficRecording configures the recording of outgoing calls for debugging
+	TrafficRecording TrafficRecordingConfig `yaml:"traffic_recording,omitempty" mapstructure:"traffic_recording"`
+	DebugConfig      bool                   `yaml:"debug_config,omitempty" mapstructure:"debug_config"`
 }
 
+// JSONNumbers modes: how numbers of event data and API responses are decoded
+const (
+	JSONNumbersFloat  = "float"
+	JSONNumbersStrict = "strict"
+)
+
 // ClientsConfig contains configuration for all external clients
 type ClientsConfig struct {
 	Maestro       *MaestroClientConfig `yaml:"maestro,omitempty" mapstructure:"maestro"`
 	Broker        BrokerConfig         `yaml:"broker,omitempty" mapstructure:"broker"`
 	Kubernetes    KubernetesConfig     `yaml:"kubernetes" mapstructure:"kubernetes"`
+	Vault         *VaultClientConfig   `yaml:"vault,omitempty" mapstructure:"vault"`
+	GitOps        *GitOpsClientConfig  `yaml:"gitops,omitempty" mapstructure:"gitops"`
 	HyperfleetAPI HyperfleetAPIConfig  `yaml:"hyperfleet_api" mapstructure:"hyperfleet_api"`
 }
 
+// GitOpsClientConfig configures the gitops transport, which commits rendered manifests to a
+// Git repository for Argo CD or Flux to apply instead of applying them
+type GitOpsClientConfig struct {
+	// PullRequest opens a pull request for the commits instead of pushing them to Branch
+	PullRequest *GitOpsPullRequestConfig `yaml:"pull_request,omitempty" mapstructure:"pull_request"`
+	// Repository is the URL of the Git repository, e.g. https://github.com/org/fleet.git
+	Repository string `yaml:"repository" mapstructure:"repository"`
+	// Branch is the branch the manifests are committed to. Empty uses main.
+	Branch string `yaml:"branch,omitempty" mapstructure:"branch"`
+	// TokenPath is the path to a file holding the token authenticating HTTPS pushes and pull
+	// requests. It is re-read on every write. Empty uses the credentials of the environment.
+	TokenPath string `yaml:"token_path,omitempty" mapstructure:"token_path"`
+	// AuthorName and AuthorEmail sign the commits. Empty uses hyperfleet-adapter.
+	AuthorName  string `yaml:"author_name,omitempty" mapstructure:"author_name"`
+	AuthorEmail string `yaml:"author_email,omitempty" mapstructure:"author_email"`
+	// WorkDir is the directory the repository is cloned into. Empty uses a temporary directory.
+	WorkDir string `yaml:"work_dir,omitempty" mapstructure:"work_dir"`
+}
+
+// GitOpsPullRequestConfig configures the pull requests of the gitops transport. Commits go
+// to a branch per manifest directory, with one pull request open against the base branch.
+type GitOpsPullRequestConfig struct {
+	// Repository is the GitHub repository of the pull requests, as owner/name
+	Repository string `yaml:"repository" mapstructure:"repository"`
+	// APIURL is the GitHub API URL. Empty uses https://api.github.com.
+	APIURL string `yaml:"api_url,omitempty" mapstructure:"api_url"`
+	// BranchPrefix prefixes the branches of the pull requests. Empty uses hyperfleet/.
+	BranchPrefix string `yaml:"branch_prefix,omitempty" mapstructure:"branch_prefix"`
+}
+
+// VaultClientConfig configures the HashiCorp Vault client that resolves vault.* param sources
+type VaultClientConfig struct {
+	// Address is the Vault server URL, e.g. https://vault.example.com:8200
+	Address string `yaml:"address" mapstructure:"address"`
+	// TokenPath is the absolute path to a file containing the Vault token (for example
+	// written by a Vault Agent sink). It is re-read on every request.
+	TokenPath string `yaml:"token_path" mapstructure:"token_path"`
+	// Namespace is the Vault Enterprise namespace. Empty uses the root namespace.
+	Namespace string `yaml:"namespace,omitempty" mapstructure:"namespace"`
+	// CAFile is the CA bundle used to verify the Vault server certificate
+	CAFile string `yaml:"ca_file,omitempty" mapstructure:"ca_file"`
+	// Timeout bounds each Vault request. Empty uses the default (10s).
+	Timeout Duration `yaml:"timeout,omitempty" mapstructure:"timeout"`
+	// KVVersion is the version of the KV secrets engine, 1 or 2. Zero uses 2.
+	KVVersion int `yaml:"kv_version,omitempty" mapstructure:"kv_version" validate:"omitempty,oneof=1 2"`
+}
+
 // MaestroClientConfig contains Maestro client configuration
 type MaestroClientConfig struct {
-	GRPCServerAddress string `yaml:"grpc_server_address" mapstructure:"grpc_server_address"`
-	HTTPServerAddress string `yaml:"http_server_address" mapstructure:"http_server_address"`
-	SourceID          string `yaml:"source_id" mapstructure:"source_id"`
-	ClientID          string `yaml:"client_id" mapstructure:"client_id"`
-	Timeout           string `yaml:"timeout" mapstructure:"timeout"`
-	//nolint:lll
-	ServerHealthinessTimeout string            `yaml:"server_healthiness_timeout,omitempty" mapstructure:"server_healthiness_timeout"`
-	Keepalive                *KeepaliveConfig  `yaml:"keepalive,omitempty" mapstructure:"keepalive"`
-	Auth                     MaestroAuthConfig `yaml:"auth" mapstructure:"auth"`
-	RetryAttempts            int               `yaml:"retry_attempts" mapstructure:"retry_attempts"`
-	Insecure                 bool              `yaml:"insecure,omitempty" mapstructure:"insecure"`
+	GRPCServerAddress string            `yaml:"grpc_server_address" mapstructure:"grpc_server_address"`
+	HTTPServerAddress string            `yaml:"http_server_address" mapstructure:"http_server_address"`
+	SourceID          string            `yaml:"source_id" mapstructure:"source_id"`
+	ClientID          string            `yaml:"client_id" mapstructure:"client_id"`
+	Keepalive         *KeepaliveConfig  `yaml:"keepalive,omitempty" mapstructure:"keepalive"`
+	Auth              MaestroAuthConfig `yaml:"auth" mapstructure:"auth"`
+	Timeout           Duration          `yaml:"timeout" mapstructure:"timeout"`
+	// ServerHealthinessTimeout is the timeout of the gRPC server health check
+	ServerHealthinessTimeout Duration `yaml:"server_healthiness_timeout" mapstructure:"server_healthiness_timeout"`
+	RetryAttempts            int      `yaml:"retry_attempts" mapstructure:"retry_attempts"`
+	Insecure                 bool     `yaml:"insecure,omitempty" mapstructure:"insecure"`
+	// MaxConcurrentApplies caps the ManifestWork applies and deletes sent to Maestro at once,
+	// across all executing events. Zero is unlimited.
+	MaxConcurrentApplies int `yaml:"max_concurrent_applies" mapstructure:"max_concurrent_applies" validate:"gte=0"`
 }
 
 // MaestroAuthConfig contains authentication configuration for Maestro
@@ -690,16 +1617,189 @@ type TLSConfig struct {
 
 // KeepaliveConfig contains gRPC keepalive configuration
 type KeepaliveConfig struct {
-	Time    string `yaml:"time" mapstructure:"time"`
-	Timeout string `yaml:"timeout" mapstructure:"timeout"`
+	Time    Duration `yaml:"time" mapstructure:"time"`
+	Timeout Duration `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// Notification target types
+const (
+	NotificationTargetSlack   = "slack"
+	NotificationTargetWebhook = "webhook"
+)
+
+// NotificationsConfig configures webhook notifications sent when executions keep
+// failing for the same resource or the adapter becomes degraded
+type NotificationsConfig struct {
+	Targets []NotificationTarget `yaml:"targets,omitempty" mapstructure:"targets" validate:"dive"`
+	// FailureThreshold is the number of consecutive failed executions for the same
+	// resource that triggers a notification (default 3)
+	FailureThreshold int `yaml:"failure_threshold,omitempty" mapstructure:"failure_threshold" validate:"gte=0"`
+	// MinInterval is the minimum time between two notifications with the same
+	// dedup key (resource or degraded reason, default 15m)
+	MinInterval Duration `yaml:"min_interval,omitempty" mapstructure:"min_interval"`
+	// Timeout bounds each webhook request (default 10s)
+	Timeout Duration `yaml:"timeout,omitempty" mapstructure:"timeout"`
+}
+
+// ShardingConfig splits event ownership between the replicas of a StatefulSet. The shard
+// index is the pod ordinal and, unless Count is set, the shard count is the StatefulSet's
+// replica count, so scaling the StatefulSet rebalances ownership.
+type ShardingConfig struct {
+	// StatefulSet is the StatefulSet whose replicas are counted (default: the pod name
+	// without its ordinal)
+	StatefulSet string `yaml:"statefulset,omitempty" mapstructure:"statefulset"`
+	// Count fixes the number of shards instead of reading the StatefulSet
+	Count int `yaml:"count,omitempty" mapstructure:"count" validate:"gte=0"`
+	// RefreshInterval is how often the replica count is re-read (default 30s)
+	RefreshInterval Duration `yaml:"refresh_interval,omitempty" mapstructure:"refresh_interval"`
+	Enabled         bool     `yaml:"enabled,omitempty" mapstructure:"enabled"`
+}
+
+// Deduplication stores
+const (
+	DeduplicationStoreMemory    = "memory"
+	DeduplicationStoreRedis     = "redis"
+	DeduplicationStoreConfigMap = "configmap"
+)
+
+// DeduplicationConfig skips redelivered events whose workflow already completed. An event
+// is identified by its CloudEvent ID and the generation in its data, and is remembered for
+// TTL after a successful execution.
+type DeduplicationConfig struct {
+	// Redis configures the redis store
+	Redis *RedisStoreConfig `yaml:"redis,omitempty" mapstructure:"redis"`
+	// ConfigMap configures the configmap store
+	ConfigMap *ConfigMapStoreConfig `yaml:"configmap,omitempty" mapstructure:"configmap"`
+	// Store is "memory" (default, per replica), "redis" or "configmap" (shared by replicas)
+	Store string `yaml:"store,omitempty" mapstructure:"store" validate:"omitempty,oneof=memory redis configmap"`
+	// TTL is how long a completed event is remembered (default 1h)
+	TTL Duration `yaml:"ttl,omitempty" mapstructure:"ttl"`
+	// MaxEntries bounds the events kept by the memory and configmap stores (default 10000)
+	MaxEntries int  `yaml:"max_entries,omitempty" mapstructure:"max_entries" validate:"gte=0"`
+	Enabled    bool `yaml:"enabled,omitempty" mapstructure:"enabled"`
+}
+
+// RedisStoreConfig is the Redis server holding completed events
+type RedisStoreConfig struct {
+	// PasswordPath is the file holding the Redis password (optional)
+	PasswordPath string `yaml:"password_path,omitempty" mapstructure:"password_path"`
+	// TLS encrypts the connections to the Redis server
+	TLS *utils.TLSConfig `yaml:"tls,omitempty" mapstructure:"tls"`
+	// Address is the host:port of the Redis server
+	Address string `yaml:"address" mapstructure:"address"`
+	// DB is the Redis database number
+	DB int `yaml:"db,omitempty" mapstructure:"db" validate:"gte=0"`
+	// PoolSize bounds the connections opened to the Redis server (default 8)
+	PoolSize int `yaml:"pool_size,omitempty" mapstructure:"pool_size" validate:"gte=0"`
+}
+
+// ConfigMapStoreConfig is the ConfigMap holding completed events
+type ConfigMapStoreConfig struct {
+	// Name of the ConfigMap (default "<adapter name>-dedup")
+	Name string `yaml:"name,omitempty" mapstructure:"name"`
+	// Namespace of the ConfigMap (default: the pod namespace)
+	Namespace string `yaml:"namespace,omitempty" mapstructure:"namespace"`
+}
+
+// AdminConfig configures the admin server hosting /configz, /loglevel, /features and the
+// /debug endpoints on its own port, behind token or mTLS authentication
+type AdminConfig = admin.Config
+
+// Readiness checks that /readyz can include, besides the config check it always includes
+const (
+	ReadinessCheckBroker    = "broker"
+	ReadinessCheckTransport = "transport"
+	ReadinessCheckAPI       = "api"
+)
+
+// ReadinessConfig selects the dependency checks that the aggregate /readyz includes. Every
+// check is also served on its own at /readyz/<check>, whether or not it is included.
+type ReadinessConfig struct {
+	// Checks are the dependency checks /readyz includes: broker (the subscriptions are
+	// active), transport (the transport clients answer their probe) and api (the HyperFleet
+	// API answers its probe). Empty includes broker only.
+	Checks []string `yaml:"checks,omitempty" mapstructure:"checks" validate:"dive,oneof=broker transport api"`
+}
+
+// EffectiveChecks returns the dependency checks /readyz includes
+func (c ReadinessConfig) EffectiveChecks() []string {
+	if len(c.Checks) == 0 {
+		return []string{ReadinessCheckBroker}
+	}
+	return c.Checks
+}
+
+// ScheduleConfig is a cron schedule injecting a synthetic CloudEvent into the executor
+type ScheduleConfig = schedule.Config
+
+// TrafficRecordingConfig configures the recording of outgoing HTTP and gRPC call metadata
+// into an in-memory ring served at /debug/traffic on the health port. Recording can also
+// be toggled at runtime with SIGUSR1 or a POST to /debug/traffic, without a restart.
+type TrafficRecordingConfig struct {
+	// Capacity is the number of calls kept (default 500)
+	Capacity int `yaml:"capacity,omitempty" mapstructure:"capacity" validate:"gte=0"`
+	// Enabled starts recording at startup
+	Enabled bool `yaml:"enabled,omitempty" mapstructure:"enabled"`
+	// SpanEvents also adds each recorded call as an event to the active OTel span
+	SpanEvents bool `yaml:"span_events,omitempty" mapstructure:"span_events"`
+}
+
+// ExecutionRecordingConfig configures the recording of failed executions in serve mode.
+// The incoming event, the HyperFleet API responses and the objects read from the transport
+// are written to a directory that `adapter replay` feeds back through the dry-run clients.
+type ExecutionRecordingConfig struct {
+	// Dir is the directory recordings are written to, one subdirectory per failed execution
+	Dir string `yaml:"dir,omitempty" mapstructure:"dir"`
+	// RedactFields are field names whose values are redacted at any depth of the recorded
+	// event data, API response bodies and objects. Secret data is always redacted.
+	RedactFields []string `yaml:"redact_fields,omitempty" mapstructure:"redact_fields"`
+	// MaxRecordings is the number of recordings kept; the oldest are removed (default 100)
+	MaxRecordings int `yaml:"max_recordings,omitempty" mapstructure:"max_recordings" validate:"gte=0"`
+	// Enabled records failed executions
+	Enabled bool `yaml:"enabled,omitempty" mapstructure:"enabled"`
+}
+
+// SLIConfig configures the service level indicators of processed generations: how long it
+// takes from receiving an event to reporting the resource status, by event type and
+// generation freshness.
+type SLIConfig struct {
+	// StatePath is the file the SLI window and the last reported generations are persisted
+	// to, so that /sli and freshness survive restarts (optional)
+	StatePath string `yaml:"state_path,omitempty" mapstructure:"state_path"`
+	// Target is the duration within which a status must be reported (default 5m)
+	Target Duration `yaml:"target,omitempty" mapstructure:"target"`
+	// Window is the period summarized by /sli (default 1h)
+	Window Duration `yaml:"window,omitempty" mapstructure:"window"`
+	// MaxResources bounds the resources whose last reported generation is kept (default 10000)
+	MaxResources int  `yaml:"max_resources,omitempty" mapstructure:"max_resources" validate:"gte=0"`
+	Enabled      bool `yaml:"enabled,omitempty" mapstructure:"enabled"`
+}
+
+// NotificationTarget is a webhook that receives notifications
+type NotificationTarget struct {
+	Headers map[string]string `yaml:"headers,omitempty" mapstructure:"headers"`
+	Name    string            `yaml:"name" mapstructure:"name" validate:"required"`
+	// Type is "slack" (posts {"text": message}) or "webhook" (posts the full notification as JSON)
+	Type string `yaml:"type" mapstructure:"type" validate:"required,oneof=slack webhook"`
+	URL  string `yaml:"url" mapstructure:"url" validate:"required"`
+	// Message is a Go template rendered with the notification fields; empty uses the default
+	Message string `yaml:"message,omitempty" mapstructure:"message"`
 }
 
 // AdapterTaskConfig represents the business logic configuration.
 // Contains params, preconditions, resources, and post-processing actions.
 // This config is loaded from YAML without environment variable overrides.
 type AdapterTaskConfig struct {
-	Post          *PostConfig    `yaml:"post,omitempty" validate:"omitempty"`
-	Params        []Parameter    `yaml:"params,omitempty" validate:"dive"`
+	Post          *PostConfig     `yaml:"post,omitempty" validate:"omitempty"`
+	Resources     []Resource      `yaml:"resources,omitempty" validate:"unique=Name,dive"`
+	Prune         []PruneStep     `yaml:"prune,omitempty" validate:"dive"`
+	Wait          []WaitStep      `yaml:"wait,omitempty" validate:"dive"`
+	Platform      *PlatformConfig `yaml:"platform,omitempty"`
+	Params        []Parameter     `yaml:"params,omitempty" validate:"dive"`
+	EventOverlays []EventOverlay  `yaml:"event_overlays,omitempty" validate:"dive"`
+	// FailurePolicy is the on_error of the steps that do not set one: abort or continue
+	FailurePolicy string         `yaml:"failure_policy,omitempty" validate:"omitempty,oneof=abort continue"`
 	Preconditions []Precondition `yaml:"preconditions,omitempty" validate:"dive"`
-	Resources     []Resource     `yaml:"resources,omitempty" validate:"unique=Name,dive"`
+	// ExecutionTimeout bounds the phases before the post actions of one execution; 0 is unbounded
+	ExecutionTimeout Duration `yaml:"execution_timeout,omitempty"`
 }
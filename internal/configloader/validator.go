@@ -3,18 +3,32 @@ package configloader
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/google/cel-go/cel"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/kafkabroker"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/schedule"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
 // templateVarRegex matches Go template variables like {{ .varName }} or {{ .nested.var }}
@@ -54,62 +68,1146 @@ func (v *AdapterConfigValidator) ValidateStructure() error {
 	if err := v.validateHyperfleetAuth(); err != nil {
 		return err
 	}
+	if err := v.validateVault(); err != nil {
+		return err
+	}
+	if err := v.validateGitOps(); err != nil {
+		return err
+	}
+	if err := v.validateBrokerSubscriptions(); err != nil {
+		return err
+	}
+	if err := v.validateBrokerType(); err != nil {
+		return err
+	}
+	if err := v.validateKafka(); err != nil {
+		return err
+	}
+	if err := v.validateNATS(); err != nil {
+		return err
+	}
+	if err := v.validateHTTPReceiver(); err != nil {
+		return err
+	}
+	if err := v.validateEventFilter(); err != nil {
+		return err
+	}
+	if err := validateEventRouting("event_routing", v.config.EventRouting); err != nil {
+		return err
+	}
+	if err := v.validateNotifications(); err != nil {
+		return err
+	}
+	if err := v.validateSchedules(); err != nil {
+		return err
+	}
+	if err := v.validateAdmin(); err != nil {
+		return err
+	}
+	if v.config.Sharding.RefreshInterval < 0 {
+		return fmt.Errorf("sharding.refresh_interval must not be negative")
+	}
+	if err := v.validateDeduplication(); err != nil {
+		return err
+	}
+	if v.config.ExecutionRecording.Enabled && v.config.ExecutionRecording.Dir == "" {
+		return fmt.Errorf("execution_recording.dir must be set when execution recording is enabled")
+	}
+	if v.config.SLI.Target < 0 || v.config.SLI.Window < 0 {
+		return fmt.Errorf("sli.target and sli.window must not be negative")
+	}
+	if s := v.config.Log.Sampling; s != nil && (s.First < 0 || s.Thereafter < 0 || s.Window < 0) {
+		return fmt.Errorf("log.sampling.first, log.sampling.thereafter and log.sampling.window must not be negative")
+	}
+
+	return nil
+}
+
+// validateDeduplication checks the selected store is configured
+func (v *AdapterConfigValidator) validateDeduplication() error {
+	dedup := v.config.Deduplication
+	if !dedup.Enabled {
+		return nil
+	}
+	if dedup.TTL < 0 {
+		return fmt.Errorf("deduplication.ttl must not be negative")
+	}
+	if dedup.Store == DeduplicationStoreRedis && (dedup.Redis == nil || dedup.Redis.Address == "") {
+		return fmt.Errorf("deduplication.redis.address must be set when the store is redis")
+	}
+	return nil
+}
+
+// validateNotifications checks target names are unique and message templates parse
+func (v *AdapterConfigValidator) validateNotifications() error {
+	notifications := v.config.Notifications
+	if notifications.MinInterval < 0 || notifications.Timeout < 0 {
+		return fmt.Errorf("notifications.min_interval and notifications.timeout must not be negative")
+	}
+	seen := make(map[string]bool, len(notifications.Targets))
+	for i, target := range notifications.Targets {
+		path := fmt.Sprintf("notifications.targets[%d]", i)
+		if seen[target.Name] {
+			return fmt.Errorf("%s.name: %q is already defined", path, target.Name)
+		}
+		seen[target.Name] = true
+		if _, err := template.New(target.Name).Funcs(utils.TemplateFuncs).Parse(target.Message); err != nil {
+			return fmt.Errorf("%s.message: invalid template: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// validateAdmin checks the admin server has principals that can authenticate
+func (v *AdapterConfigValidator) validateAdmin() error {
+	adminCfg := v.config.Admin
+	if !adminCfg.Enabled {
+		return nil
+	}
+	if len(adminCfg.Principals) == 0 {
+		return fmt.Errorf("admin.principals must not be empty when the admin server is enabled")
+	}
+	tlsCfg := adminCfg.TLS
+	if tlsCfg != nil && (tlsCfg.CertFile == "" || tlsCfg.KeyFile == "") {
+		return fmt.Errorf("admin.tls.cert_file and admin.tls.key_file must both be set")
+	}
+	seen := make(map[string]bool, len(adminCfg.Principals))
+	for i, principal := range adminCfg.Principals {
+		path := fmt.Sprintf("admin.principals[%d]", i)
+		if seen[principal.Name] {
+			return fmt.Errorf("%s.name: %q is already defined", path, principal.Name)
+		}
+		seen[principal.Name] = true
+		if (principal.TokenPath == "") == (principal.CommonName == "") {
+			return fmt.Errorf("%s: exactly one of token_path and common_name must be set", path)
+		}
+		if principal.TokenPath != "" && !filepath.IsAbs(principal.TokenPath) {
+			return fmt.Errorf("%s.token_path must be an absolute path, got %q", path, principal.TokenPath)
+		}
+		if principal.CommonName != "" && (tlsCfg == nil || tlsCfg.ClientCAFile == "") {
+			return fmt.Errorf("%s.common_name requires admin.tls.client_ca_file", path)
+		}
+	}
+	return nil
+}
+
+// validateSchedules checks schedule names are unique and their cron expressions, time zones
+// and event templates are valid
+func (v *AdapterConfigValidator) validateSchedules() error {
+	seen := make(map[string]bool, len(v.config.Schedules))
+	for i, config := range v.config.Schedules {
+		path := fmt.Sprintf("schedules[%d]", i)
+		if seen[config.Name] {
+			return fmt.Errorf("%s.name: %q is already defined", path, config.Name)
+		}
+		seen[config.Name] = true
+		if _, err := schedule.ParseCron(config.Cron); err != nil {
+			return fmt.Errorf("%s.cron: %w", path, err)
+		}
+		if _, err := config.Location(); err != nil {
+			return fmt.Errorf("%s.timezone: invalid time zone %q: %w", path, config.Timezone, err)
+		}
+		if _, err := schedule.NewEvent(config, time.Now()); err != nil {
+			return fmt.Errorf("%s.event: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func (v *AdapterConfigValidator) validateBrokerSubscriptions() error {
+	broker := v.config.Clients.Broker
+	if len(broker.Subscriptions) == 0 {
+		return nil
+	}
+	if broker.SubscriptionID != "" || broker.Topic != "" {
+		return fmt.Errorf("clients.broker: subscription_id and topic are mutually exclusive with subscriptions")
+	}
+	seen := make(map[string]bool, len(broker.Subscriptions))
+	for i, sub := range broker.Subscriptions {
+		path := fmt.Sprintf("clients.broker.subscriptions[%d]", i)
+		if sub.Topic == "" {
+			return fmt.Errorf("%s.topic is required", path)
+		}
+		if sub.SubscriptionID == "" {
+			return fmt.Errorf("%s.subscription_id is required", path)
+		}
+		if seen[sub.SubscriptionID] {
+			return fmt.Errorf("%s.subscription_id: %q is already used", path, sub.SubscriptionID)
+		}
+		seen[sub.SubscriptionID] = true
+		if err := validateEventFilterExpression(path+".filter", sub.Filter); err != nil {
+			return err
+		}
+		if err := validateEventRouting(path+".routing", sub.Routing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *AdapterConfigValidator) validateEventFilter() error {
+	return validateEventFilterExpression("event_filter", v.config.EventFilter)
+}
+
+// validateEventFilterExpression checks that an event filter, empty for none, parses as CEL
+func validateEventFilterExpression(path, filter string) error {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil
+	}
+	env, err := cel.NewEnv(cel.OptionalTypes())
+	if err != nil {
+		return err
+	}
+	if _, issues := env.Parse(filter); issues != nil && issues.Err() != nil {
+		return fmt.Errorf("%s: CEL parse error: %v", path, issues.Err())
+	}
+	return nil
+}
+
+// validateEventRouting checks that the type and subject patterns of routing are valid globs
+func validateEventRouting(fieldPath string, routing EventRoutingConfig) error {
+	fields := []struct {
+		name     string
+		patterns []string
+	}{{"types", routing.Types}, {"subjects", routing.Subjects}}
+	for _, field := range fields {
+		for i, pattern := range field.patterns {
+			if pattern == "" {
+				return fmt.Errorf("%s.%s[%d] must not be empty", fieldPath, field.name, i)
+			}
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s.%s[%d]: invalid pattern %q: %w", fieldPath, field.name, i, pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (v *AdapterConfigValidator) validateHyperfleetAuth() error {
+	auth := v.config.Clients.HyperfleetAPI.Auth
+	if auth == nil {
+		return nil
+	}
+	if auth.OIDC != nil {
+		return validateHyperfleetOIDC(auth)
+	}
+	if auth.OAuth2 != nil {
+		return validateHyperfleetOAuth2(auth)
+	}
+	if auth.TokenPath == "" {
+		return fmt.Errorf("clients.hyperfleet_api.auth.token_path must be set when auth is configured without oauth2 or oidc")
+	}
+	if !filepath.IsAbs(auth.TokenPath) {
+		return fmt.Errorf("clients.hyperfleet_api.auth.token_path must be an absolute path, got %q", auth.TokenPath)
+	}
+	if auth.TokenCacheTTL < 0 {
+		return fmt.Errorf("clients.hyperfleet_api.auth.token_cache_ttl must not be negative")
+	}
+	return nil
+}
+
+// validateHyperfleetOAuth2 checks the OAuth2 client credentials of the HyperFleet API auth
+func validateHyperfleetOAuth2(auth *HyperfleetAPIAuthConfig) error {
+	const path = "clients.hyperfleet_api.auth.oauth2"
+	if auth.TokenPath != "" {
+		return fmt.Errorf("clients.hyperfleet_api.auth: token_path and oauth2 are mutually exclusive")
+	}
+	oauth2 := auth.OAuth2
+	tokenURL, err := url.Parse(oauth2.TokenURL)
+	validScheme := err == nil && (tokenURL.Scheme == "http" || tokenURL.Scheme == "https")
+	if oauth2.TokenURL == "" || !validScheme || tokenURL.Host == "" {
+		return fmt.Errorf("%s.token_url must be an http(s) URL, got %q", path, oauth2.TokenURL)
+	}
+	if oauth2.ClientID == "" {
+		return fmt.Errorf("%s.client_id is required", path)
+	}
+	if !filepath.IsAbs(oauth2.ClientSecretPath) {
+		return fmt.Errorf("%s.client_secret_path must be an absolute path, got %q", path, oauth2.ClientSecretPath)
+	}
+	return nil
+}
+
+// validateBrokerType checks that the broker type has its backend configured, and only it
+func (v *AdapterConfigValidator) validateBrokerType() error {
+	brokerConfig := v.config.Clients.Broker
+	configured := map[string]bool{
+		BrokerTypeKafka: brokerConfig.Kafka != nil,
+		BrokerTypeNATS:  brokerConfig.NATS != nil,
+		BrokerTypeHTTP:  brokerConfig.HTTP != nil,
+	}
+	var names []string
+	for _, name := range []string{BrokerTypeKafka, BrokerTypeNATS, BrokerTypeHTTP} {
+		if configured[name] {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 1 {
+		return fmt.Errorf("clients.broker: %s are mutually exclusive", strings.Join(names, " and "))
+	}
+	brokerType := brokerConfig.EffectiveType()
+	if isBackend, ok := configured[brokerType]; ok && !isBackend {
+		return fmt.Errorf("clients.broker.%s must be set when clients.broker.type is %s", brokerType, brokerType)
+	}
+	if len(names) == 1 && names[0] != brokerType {
+		return fmt.Errorf("clients.broker.type %s does not use the configured %s backend", brokerType, names[0])
+	}
+	return nil
+}
+
+// validateKafka checks the Kafka subscriber of the broker config
+func (v *AdapterConfigValidator) validateKafka() error {
+	const path = "clients.broker.kafka"
+	brokerConfig := v.config.Clients.Broker
+	kafka := brokerConfig.Kafka
+	if kafka == nil {
+		return nil
+	}
+	if len(kafka.Brokers) == 0 {
+		return fmt.Errorf("%s.brokers must be set when kafka is configured", path)
+	}
+	if brokerConfig.PublishTopic != "" || brokerConfig.DeadLetterTopic != "" {
+		return fmt.Errorf("clients.broker: publish_topic and dead_letter_topic are not supported with kafka")
+	}
+	switch kafka.StartOffset {
+	case "", kafkabroker.StartOffsetEarliest, kafkabroker.StartOffsetLatest:
+	default:
+		return fmt.Errorf("%s.start_offset must be earliest or latest, got %q", path, kafka.StartOffset)
+	}
+	if tls := kafka.TLS; tls != nil {
+		files := map[string]string{"ca_file": tls.CAFile, "cert_file": tls.CertFile, "key_file": tls.KeyFile}
+		for field, file := range files {
+			if file != "" && !filepath.IsAbs(file) {
+				return fmt.Errorf("%s.tls.%s must be an absolute path, got %q", path, field, file)
+			}
+		}
+		if (tls.CertFile == "") != (tls.KeyFile == "") {
+			return fmt.Errorf("%s.tls: cert_file and key_file must be set together", path)
+		}
+	}
+	if sasl := kafka.SASL; sasl != nil {
+		switch sasl.Mechanism {
+		case kafkabroker.SASLMechanismPlain, kafkabroker.SASLMechanismSCRAMSHA256, kafkabroker.SASLMechanismSCRAMSHA512:
+		default:
+			return fmt.Errorf("%s.sasl.mechanism must be plain, scram-sha-256 or scram-sha-512, got %q",
+				path, sasl.Mechanism)
+		}
+		if sasl.Username == "" {
+			return fmt.Errorf("%s.sasl.username is required", path)
+		}
+		if !filepath.IsAbs(sasl.PasswordPath) {
+			return fmt.Errorf("%s.sasl.password_path must be an absolute path, got %q", path, sasl.PasswordPath)
+		}
+	}
+	return nil
+}
+
+// validateNATS checks the NATS JetStream subscriber of the broker config
+func (v *AdapterConfigValidator) validateNATS() error {
+	const path = "clients.broker.nats"
+	brokerConfig := v.config.Clients.Broker
+	nats := brokerConfig.NATS
+	if nats == nil {
+		return nil
+	}
+	if len(nats.Servers) == 0 {
+		return fmt.Errorf("%s.servers must be set when nats is configured", path)
+	}
+	if nats.Stream == "" {
+		return fmt.Errorf("%s.stream must be set when nats is configured", path)
+	}
+	if brokerConfig.PublishTopic != "" || brokerConfig.DeadLetterTopic != "" {
+		return fmt.Errorf("clients.broker: publish_topic and dead_letter_topic are not supported with nats")
+	}
+	if nats.Durable != "" && len(brokerConfig.Subscriptions) > 1 {
+		return fmt.Errorf("%s.durable cannot be shared by several subscriptions; "+
+			"leave it empty to name each consumer after its subscription_id", path)
+	}
+	if strings.ContainsAny(nats.Durable, ". *>") {
+		return fmt.Errorf("%s.durable must not contain '.', '*', '>' or spaces, got %q", path, nats.Durable)
+	}
+	if nats.AckWait < 0 {
+		return fmt.Errorf("%s.ack_wait must not be negative, got %s", path, nats.AckWait)
+	}
+	if nats.MaxDeliver < 0 {
+		return fmt.Errorf("%s.max_deliver must not be negative, got %d", path, nats.MaxDeliver)
+	}
+	if nats.CredentialsPath != "" && !filepath.IsAbs(nats.CredentialsPath) {
+		return fmt.Errorf("%s.credentials_path must be an absolute path, got %q", path, nats.CredentialsPath)
+	}
+	if tls := nats.TLS; tls != nil {
+		files := map[string]string{"ca_file": tls.CAFile, "cert_file": tls.CertFile, "key_file": tls.KeyFile}
+		for field, file := range files {
+			if file != "" && !filepath.IsAbs(file) {
+				return fmt.Errorf("%s.tls.%s must be an absolute path, got %q", path, field, file)
+			}
+		}
+		if (tls.CertFile == "") != (tls.KeyFile == "") {
+			return fmt.Errorf("%s.tls: cert_file and key_file must be set together", path)
+		}
+	}
+	return nil
+}
+
+// validateHTTPReceiver checks the HTTP CloudEvents receiver of the broker config
+func (v *AdapterConfigValidator) validateHTTPReceiver() error {
+	const path = "clients.broker.http"
+	brokerConfig := v.config.Clients.Broker
+	receiver := brokerConfig.HTTP
+	if receiver == nil {
+		return nil
+	}
+	if brokerConfig.PublishTopic != "" || brokerConfig.DeadLetterTopic != "" {
+		return fmt.Errorf("clients.broker: publish_topic and dead_letter_topic are not supported with http")
+	}
+	if receiver.Port != "" {
+		if port, err := strconv.Atoi(receiver.Port); err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("%s.port must be a port number, got %q", path, receiver.Port)
+		}
+	}
+	if receiver.Path != "" && !strings.HasPrefix(receiver.Path, "/") {
+		return fmt.Errorf("%s.path must start with /, got %q", path, receiver.Path)
+	}
+	if receiver.TokenPath == "" && !receiver.Insecure {
+		return fmt.Errorf("%s.token_path is required; set insecure: true to accept unauthenticated events", path)
+	}
+	if receiver.TokenPath != "" && !filepath.IsAbs(receiver.TokenPath) {
+		return fmt.Errorf("%s.token_path must be an absolute path, got %q", path, receiver.TokenPath)
+	}
+	if tls := receiver.TLS; tls != nil && (!filepath.IsAbs(tls.CertFile) || !filepath.IsAbs(tls.KeyFile)) {
+		return fmt.Errorf("%s.tls: cert_file and key_file must be absolute paths", path)
+	}
+	topics := make(map[string]bool)
+	for _, sub := range brokerConfig.EffectiveSubscriptions() {
+		if sub.Topic == "" {
+			continue
+		}
+		if strings.Contains(sub.Topic, "/") {
+			return fmt.Errorf("clients.broker: topic %q must not contain / with http", sub.Topic)
+		}
+		if topics[sub.Topic] {
+			return fmt.Errorf("clients.broker: topic %s is subscribed more than once, which http does not support",
+				sub.Topic)
+		}
+		topics[sub.Topic] = true
+	}
+	return nil
+}
+
+// validateHyperfleetOIDC checks the interactive OIDC login of the HyperFleet API auth
+func validateHyperfleetOIDC(auth *HyperfleetAPIAuthConfig) error {
+	const path = "clients.hyperfleet_api.auth.oidc"
+	if auth.TokenPath != "" || auth.OAuth2 != nil {
+		return fmt.Errorf("clients.hyperfleet_api.auth: oidc is mutually exclusive with token_path and oauth2")
+	}
+	oidc := auth.OIDC
+	issuer, err := url.Parse(oidc.Issuer)
+	if oidc.Issuer == "" || err != nil || (issuer.Scheme != "http" && issuer.Scheme != "https") || issuer.Host == "" {
+		return fmt.Errorf("%s.issuer must be an http(s) URL, got %q", path, oidc.Issuer)
+	}
+	if oidc.ClientID == "" {
+		return fmt.Errorf("%s.client_id is required", path)
+	}
+	switch oidc.Flow {
+	case "", hyperfleetapi.OIDCFlowDevice, hyperfleetapi.OIDCFlowPKCE:
+	default:
+		return fmt.Errorf("%s.flow must be device or pkce, got %q", path, oidc.Flow)
+	}
+	if oidc.CacheDir != "" && !filepath.IsAbs(oidc.CacheDir) {
+		return fmt.Errorf("%s.cache_dir must be an absolute path, got %q", path, oidc.CacheDir)
+	}
+	return nil
+}
+
+func (v *AdapterConfigValidator) validateVault() error {
+	vault := v.config.Clients.Vault
+	if vault == nil {
+		return nil
+	}
+	if vault.Address == "" {
+		return fmt.Errorf("clients.vault.address must be set when vault is configured")
+	}
+	if u, err := url.Parse(vault.Address); err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("clients.vault.address must be an absolute URL, got %q", vault.Address)
+	}
+	if vault.TokenPath == "" {
+		return fmt.Errorf("clients.vault.token_path must be set when vault is configured")
+	}
+	if !filepath.IsAbs(vault.TokenPath) {
+		return fmt.Errorf("clients.vault.token_path must be an absolute path, got %q", vault.TokenPath)
+	}
+	if vault.Timeout < 0 {
+		return fmt.Errorf("clients.vault.timeout must be a positive duration, got %s", vault.Timeout)
+	}
+	return nil
+}
+
+func (v *AdapterConfigValidator) validateGitOps() error {
+	gitops := v.config.Clients.GitOps
+	if gitops == nil {
+		return nil
+	}
+	if gitops.Repository == "" {
+		return fmt.Errorf("clients.gitops.repository must be set when gitops is configured")
+	}
+	if gitops.TokenPath != "" && !filepath.IsAbs(gitops.TokenPath) {
+		return fmt.Errorf("clients.gitops.token_path must be an absolute path, got %q", gitops.TokenPath)
+	}
+	pr := gitops.PullRequest
+	if pr == nil {
+		return nil
+	}
+	owner, name, ok := strings.Cut(pr.Repository, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("clients.gitops.pull_request.repository must be owner/name, got %q", pr.Repository)
+	}
+	if pr.APIURL != "" {
+		if u, err := url.Parse(pr.APIURL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("clients.gitops.pull_request.api_url must be an absolute URL, got %q", pr.APIURL)
+		}
+	}
+	return nil
+}
+
+// TaskConfigValidator validates AdapterTaskConfig (task configuration)
+type TaskConfigValidator struct {
+	config      *AdapterTaskConfig
+	errors      *ValidationErrors
+	celEnv      *cel.Env
+	definedVars map[string]bool
+	baseDir     string
+	warnings    []string
+	// inForEach is set while the templates of a for_each step are validated, where the
+	// item and index variables are defined
+	inForEach bool
+}
+
+// NewTaskConfigValidator creates a validator for AdapterTaskConfig
+func NewTaskConfigValidator(config *AdapterTaskConfig, baseDir string) *TaskConfigValidator {
+	return &TaskConfigValidator{
+		config:  config,
+		baseDir: baseDir,
+		errors:  &ValidationErrors{},
+	}
+}
+
+// Warnings returns deprecation warnings collected during validation.
+func (v *TaskConfigValidator) Warnings() []string {
+	return v.warnings
+}
+
+// ValidateStructure validates the structural requirements of AdapterTaskConfig
+func (v *TaskConfigValidator) ValidateStructure() error {
+	if v.config == nil {
+		return fmt.Errorf("task config is nil")
+	}
+
+	// Struct tag validation
+	if errs := ValidateStruct(v.config); errs != nil && errs.HasErrors() {
+		return fmt.Errorf("%s", errs.First())
+	}
+
+	if err := v.validateNames(); err != nil {
+		return err
+	}
+	if err := v.validateDependsOn(); err != nil {
+		return err
+	}
+	if err := v.validateRetryPolicies(); err != nil {
+		return err
+	}
+	if err := v.validateManifestRefs(); err != nil {
+		return err
+	}
+	if err := v.validateWaitSteps(); err != nil {
+		return err
+	}
+	if err := v.validateVaultSources(); err != nil {
+		return err
+	}
+	if err := v.validateAPICallPatches(); err != nil {
+		return err
+	}
+	if err := v.validateAPICallResponseFields(); err != nil {
+		return err
+	}
+	if err := v.validateAPICallBodyEncodings(); err != nil {
+		return err
+	}
+	if err := v.validateForEach(); err != nil {
+		return err
+	}
+	if err := v.validatePlatform(); err != nil {
+		return err
+	}
+	if err := v.validateEventOverlays(); err != nil {
+		return err
+	}
+	if v.config.ExecutionTimeout < 0 {
+		return fmt.Errorf("%s must be a positive duration, got %s", FieldExecutionTimeout, v.config.ExecutionTimeout)
+	}
+	return v.validateDiscoveryOrder()
+}
+
+// platformKeyPattern matches the keys of platform maps: "os/arch", "arch", "os" or "default"
+var platformKeyPattern = regexp.MustCompile(`^[a-z0-9_]+(/[a-z0-9_]+)?$`)
+
+// validatePlatform checks that platform map names are CEL identifiers and that their keys
+// are platforms
+func (v *TaskConfigValidator) validatePlatform() error {
+	platform := v.config.Platform
+	if platform == nil {
+		return nil
+	}
+	errs := &ValidationErrors{}
+	path := FieldPlatform + "." + FieldMaps
+	names := make([]string, 0, len(platform.Maps))
+	for name := range platform.Maps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := platform.Maps[name]
+		mapPath := path + "." + name
+		if !celIdentifierPattern.MatchString(name) {
+			errs.Add(mapPath, fmt.Sprintf("%q is not a valid CEL identifier", name))
+		}
+		if len(values) == 0 {
+			errs.Add(mapPath, "must have at least one value")
+		}
+		for key := range values {
+			if !platformKeyPattern.MatchString(key) {
+				errs.Add(mapPath+"."+key,
+					fmt.Sprintf("%q is not a platform key (\"os/arch\", \"arch\", \"os\" or \"default\")", key))
+			}
+		}
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// cloudEventAttributePattern matches CloudEvent attribute names, which the spec limits to
+// lowercase letters and digits
+var cloudEventAttributePattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// eventFieldPattern matches the dot-separated event data field paths of event overlays
+var eventFieldPattern = regexp.MustCompile(`^[A-Za-z_][\w-]*(\.[A-Za-z_][\w-]*)*$`)
+
+// validateEventOverlays checks the attribute names and field paths of event overlays, and
+// that no two overlays set the same field or a field inside another one
+func (v *TaskConfigValidator) validateEventOverlays() error {
+	errs := &ValidationErrors{}
+	for i, overlay := range v.config.EventOverlays {
+		path := fmt.Sprintf("%s[%d]", FieldEventOverlays, i)
+		if !cloudEventAttributePattern.MatchString(overlay.Attribute) {
+			errs.Add(path+"."+FieldAttribute,
+				fmt.Sprintf("%q is not a CloudEvent attribute name (lowercase letters and digits)", overlay.Attribute))
+		}
+		if !eventFieldPattern.MatchString(overlay.Field) {
+			errs.Add(path+"."+FieldField, fmt.Sprintf("%q is not a dot-separated field path", overlay.Field))
+			continue
+		}
+		for j, other := range v.config.EventOverlays[:i] {
+			if other.Field == overlay.Field || strings.HasPrefix(overlay.Field, other.Field+".") ||
+				strings.HasPrefix(other.Field, overlay.Field+".") {
+				errs.Add(path+"."+FieldField,
+					fmt.Sprintf("%q overlaps the field %q of %s[%d]", overlay.Field, other.Field, FieldEventOverlays, j))
+			}
+		}
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// apiCallRef is an api_call of the task config and its field path
+type apiCallRef struct {
+	call *APICall
+	path string
+}
+
+// apiCalls returns the api_calls of params, preconditions, wait steps and post actions
+func (v *TaskConfigValidator) apiCalls() []apiCallRef {
+	var refs []apiCallRef
+	for i, param := range v.config.Params {
+		if param.Source.IsAPICall() && param.Source.APICall != nil {
+			refs = append(refs, apiCallRef{param.Source.APICall,
+				fmt.Sprintf("%s[%d].%s.%s", FieldParams, i, FieldSource, FieldAPICall)})
+		}
+	}
+	for i, precond := range v.config.Preconditions {
+		if precond.APICall != nil {
+			refs = append(refs, apiCallRef{precond.APICall, fmt.Sprintf("%s[%d].%s", FieldPreconditions, i, FieldAPICall)})
+		}
+	}
+	for i, step := range v.config.Wait {
+		if step.APICall != nil {
+			refs = append(refs, apiCallRef{step.APICall, fmt.Sprintf("%s[%d].%s", FieldWait, i, FieldAPICall)})
+		}
+	}
+	if v.config.Post != nil {
+		for i, action := range v.config.Post.PostActions {
+			if action.APICall != nil {
+				refs = append(refs, apiCallRef{action.APICall,
+					fmt.Sprintf("%s.%s[%d].%s", FieldPost, FieldPostActions, i, FieldAPICall)})
+			}
+		}
+	}
+	for i, resource := range v.config.Resources {
+		if resource.Lifecycle == nil || resource.Lifecycle.Delete == nil {
+			continue
+		}
+		for j, step := range resource.Lifecycle.Delete.Teardown {
+			if step.APICall != nil {
+				refs = append(refs, apiCallRef{step.APICall, fmt.Sprintf("%s[%d].%s.%s.%s[%d].%s", FieldResources, i,
+					FieldLifecycle, FieldLifecycleDelete, FieldLifecycleTeardown, j, FieldAPICall)})
+			}
+		}
+	}
+	return refs
+}
 
+// validateAPICallResponseFields checks that response_fields are dot-separated paths, that
+// keep_raw_response is only set alongside them and that transform is only set where the
+// response is stored
+func (v *TaskConfigValidator) validateAPICallResponseFields() error {
+	errs := &ValidationErrors{}
+	for _, ref := range v.apiCalls() {
+		if ref.call.KeepRawResponse && len(ref.call.ResponseFields) == 0 {
+			errs.Add(ref.path+"."+FieldKeepRawResponse, "keep_raw_response requires response_fields")
+		}
+		if ref.call.Transform != "" &&
+			!strings.HasPrefix(ref.path, FieldParams+"[") && !strings.HasPrefix(ref.path, FieldPreconditions+"[") {
+			errs.Add(ref.path+"."+FieldTransform, "transform is only supported on the api_call of params and preconditions")
+		}
+		for j, field := range ref.call.ResponseFields {
+			for _, segment := range strings.Split(field, ".") {
+				if segment == "" {
+					errs.Add(fmt.Sprintf("%s.%s[%d]", ref.path, FieldResponseFields, j),
+						fmt.Sprintf("invalid response field path %q", field))
+					break
+				}
+			}
+		}
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// validateForEach checks that for_each preconditions make an api_call without captures,
+// and that for_each resources have no nested discoveries and are not waited on
+func (v *TaskConfigValidator) validateForEach() error {
+	errs := &ValidationErrors{}
+	for i, precond := range v.config.Preconditions {
+		if precond.ForEach == "" {
+			continue
+		}
+		path := fmt.Sprintf("%s[%d]", FieldPreconditions, i)
+		if precond.APICall == nil {
+			errs.Add(path+"."+FieldForEach, "for_each requires an api_call")
+		}
+		if len(precond.Capture) > 0 {
+			errs.Add(path+"."+FieldCapture, "capture is not supported with for_each")
+		}
+	}
+	forEachResources := make(map[string]bool)
+	for i, resource := range v.config.Resources {
+		if resource.ForEach == "" {
+			continue
+		}
+		forEachResources[resource.Name] = true
+		if len(resource.NestedDiscoveries) > 0 {
+			errs.Add(fmt.Sprintf("%s[%d].%s", FieldResources, i, FieldNestedDiscoveries),
+				"nested_discoveries are not supported with for_each")
+		}
+	}
+	for i, step := range v.config.Wait {
+		if forEachResources[step.Resource] {
+			errs.Add(fmt.Sprintf("%s[%d].%s", FieldWait, i, FieldResource),
+				fmt.Sprintf("resource %q uses for_each and cannot be waited on", step.Resource))
+		}
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// validateAPICallPatches checks that JSON Patch bodies are only used with PATCH and that
+// each operation has the fields its op needs
+func (v *TaskConfigValidator) validateAPICallPatches() error {
+	errs := &ValidationErrors{}
+	for _, ref := range v.apiCalls() {
+		if len(ref.call.Patch) == 0 {
+			continue
+		}
+		if !strings.EqualFold(ref.call.Method, http.MethodPatch) {
+			errs.Add(ref.path+"."+FieldPatch, fmt.Sprintf("patch requires method PATCH, got %q", ref.call.Method))
+		}
+		for j, op := range ref.call.Patch {
+			path := fmt.Sprintf("%s.%s[%d]", ref.path, FieldPatch, j)
+			switch op.Op {
+			case "add", "replace", "test":
+				if strings.TrimSpace(op.ValueExpression) == "" {
+					errs.Add(path+"."+FieldValueExpression, fmt.Sprintf("value_expression is required for op %q", op.Op))
+				}
+			default:
+				if op.ValueExpression != "" {
+					errs.Add(path+"."+FieldValueExpression, fmt.Sprintf("value_expression is not allowed for op %q", op.Op))
+				}
+			}
+			needsFrom := op.Op == "move" || op.Op == "copy"
+			if needsFrom && op.From == "" {
+				errs.Add(path+"."+FieldPatchFrom, fmt.Sprintf("from is required for op %q", op.Op))
+			}
+			if !needsFrom && op.From != "" {
+				errs.Add(path+"."+FieldPatchFrom, fmt.Sprintf("from is not allowed for op %q", op.Op))
+			}
+		}
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// validateAPICallBodyEncodings checks that form and multipart bodies are only set on
+// methods with a body and that files are only sent in multipart bodies
+func (v *TaskConfigValidator) validateAPICallBodyEncodings() error {
+	errs := &ValidationErrors{}
+	for _, ref := range v.apiCalls() {
+		encoding := ref.call.BodyEncoding
+		if len(ref.call.Files) > 0 && encoding != BodyEncodingMultipart {
+			errs.Add(ref.path+"."+FieldFiles, "files require body_encoding \"multipart\"")
+		}
+		if encoding == "" || encoding == BodyEncodingJSON {
+			continue
+		}
+		switch strings.ToUpper(ref.call.Method) {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			errs.Add(ref.path+"."+FieldBodyEncoding,
+				fmt.Sprintf("body_encoding %q requires method POST, PUT or PATCH, got %q", encoding, ref.call.Method))
+		}
+		if len(ref.call.Patch) > 0 {
+			errs.Add(ref.path+"."+FieldBodyEncoding,
+				fmt.Sprintf("body_encoding %q cannot be combined with patch", encoding))
+		}
+	}
+	if errs.HasErrors() {
+		return errs
+	}
 	return nil
 }
 
-func (v *AdapterConfigValidator) validateHyperfleetAuth() error {
-	auth := v.config.Clients.HyperfleetAPI.Auth
-	if auth == nil {
-		return nil
+// validateVaultSources checks that vault param sources name a secret path and key
+func (v *TaskConfigValidator) validateVaultSources() error {
+	errs := &ValidationErrors{}
+	for i, param := range v.config.Params {
+		if !param.Source.IsVault() {
+			continue
+		}
+		if _, _, err := param.Source.VaultRef(); err != nil {
+			errs.Add(fmt.Sprintf("%s[%d].%s", FieldParams, i, FieldSource), err.Error())
+		}
 	}
-	if auth.TokenPath == "" {
-		return fmt.Errorf("clients.hyperfleet_api.auth.token_path must be set when auth is configured")
+	if errs.HasErrors() {
+		return errs
 	}
-	if !filepath.IsAbs(auth.TokenPath) {
-		return fmt.Errorf("clients.hyperfleet_api.auth.token_path must be an absolute path, got %q", auth.TokenPath)
+	return nil
+}
+
+// validateWaitSteps checks that wait steps poll a configured resource and that their
+// timeout and interval are positive durations. ManifestWork waits must poll a maestro
+// resource; validateNames checks their step name, since it names the status variable.
+func (v *TaskConfigValidator) validateWaitSteps() error {
+	errs := &ValidationErrors{}
+	resources := make(map[string]*Resource, len(v.config.Resources))
+	for i := range v.config.Resources {
+		resources[v.config.Resources[i].Name] = &v.config.Resources[i]
+	}
+	for i, step := range v.config.Wait {
+		path := fmt.Sprintf("%s[%d]", FieldWait, i)
+		resource, ok := resources[step.Resource]
+		if step.Resource != "" && !ok {
+			errs.Add(path+"."+FieldResource, fmt.Sprintf("%q is not a configured resource", step.Resource))
+		}
+		if step.ManifestWork != nil {
+			workPath := path + "." + FieldManifestWork
+			if ok && !resource.IsMaestroTransport() {
+				errs.Add(workPath, fmt.Sprintf("resource %q does not use the maestro transport", step.Resource))
+			}
+			for j, condType := range step.ManifestWork.Conditions {
+				if condType == "" {
+					errs.Add(fmt.Sprintf("%s.%s[%d]", workPath, FieldConditions, j), "condition type must not be empty")
+				}
+			}
+		}
+		for _, d := range []struct {
+			field string
+			value Duration
+		}{
+			{FieldTimeout, step.Timeout},
+			{FieldInterval, step.Interval},
+		} {
+			if d.value < 0 {
+				errs.Add(path+"."+d.field, fmt.Sprintf("%s is not a positive duration", d.value))
+			}
+		}
 	}
-	if auth.TokenCacheTTL < 0 {
-		return fmt.Errorf("clients.hyperfleet_api.auth.token_cache_ttl must not be negative")
+	if errs.HasErrors() {
+		return errs
 	}
 	return nil
 }
 
-// TaskConfigValidator validates AdapterTaskConfig (task configuration)
-type TaskConfigValidator struct {
-	config      *AdapterTaskConfig
-	errors      *ValidationErrors
-	celEnv      *cel.Env
-	definedVars map[string]bool
-	baseDir     string
-	warnings    []string
+// validateManifestRefs checks that resources set at most one of manifest, manifest_ref
+// and helm
+func (v *TaskConfigValidator) validateManifestRefs() error {
+	for i, resource := range v.config.Resources {
+		if resource.Manifest != nil && resource.ManifestRef != "" {
+			return fmt.Errorf("%s[%d]: %s and %s are mutually exclusive",
+				FieldResources, i, FieldManifest, FieldManifestRef)
+		}
+		if resource.Helm != nil && (resource.Manifest != nil || resource.ManifestRef != "") {
+			return fmt.Errorf("%s[%d]: %s cannot be combined with %s or %s",
+				FieldResources, i, FieldHelm, FieldManifest, FieldManifestRef)
+		}
+	}
+	return nil
 }
 
-// NewTaskConfigValidator creates a validator for AdapterTaskConfig
-func NewTaskConfigValidator(config *AdapterTaskConfig, baseDir string) *TaskConfigValidator {
-	return &TaskConfigValidator{
-		config:  config,
-		baseDir: baseDir,
-		errors:  &ValidationErrors{},
+// validateRetryPolicies checks that retry delays are valid, ordered durations
+func (v *TaskConfigValidator) validateRetryPolicies() error {
+	check := func(policy *RetryPolicy, path string) error {
+		if policy == nil {
+			return nil
+		}
+		for _, d := range []struct {
+			field string
+			value Duration
+		}{
+			{"base_delay", policy.BaseDelay},
+			{"max_delay", policy.MaxDelay},
+		} {
+			if d.value < 0 {
+				return fmt.Errorf("%s.%s.%s: %s is not a valid duration", path, FieldRetry, d.field, d.value)
+			}
+		}
+		if policy.BaseDelay > 0 && policy.MaxDelay > 0 && policy.BaseDelay > policy.MaxDelay {
+			return fmt.Errorf("%s.%s: base_delay must not exceed max_delay", path, FieldRetry)
+		}
+		return nil
+	}
+
+	for i, resource := range v.config.Resources {
+		if err := check(resource.Retry, fmt.Sprintf("%s[%d]", FieldResources, i)); err != nil {
+			return err
+		}
+	}
+	if v.config.Post != nil {
+		for i, action := range v.config.Post.PostActions {
+			if err := check(action.Retry, fmt.Sprintf("%s.%s[%d]", FieldPost, FieldPostActions, i)); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
 }
 
-// Warnings returns deprecation warnings collected during validation.
-func (v *TaskConfigValidator) Warnings() []string {
-	return v.warnings
+// validateDependsOn checks that resources only depend on resources declared before
+// them. Requiring earlier resources keeps the execution graph acyclic by construction.
+func (v *TaskConfigValidator) validateDependsOn() error {
+	errs := &ValidationErrors{}
+	declared := make(map[string]bool, len(v.config.Resources))
+	for i, resource := range v.config.Resources {
+		seen := make(map[string]bool, len(resource.DependsOn))
+		for j, dep := range resource.DependsOn {
+			path := fmt.Sprintf("%s[%d].%s[%d]", FieldResources, i, FieldDependsOn, j)
+			switch {
+			case dep == resource.Name:
+				errs.Add(path, fmt.Sprintf("resource %q cannot depend on itself", dep))
+			case seen[dep]:
+				errs.Add(path, fmt.Sprintf("%q is listed more than once", dep))
+			case !declared[dep]:
+				errs.Add(path, fmt.Sprintf("%q is not a resource declared before %q", dep, resource.Name))
+			}
+			seen[dep] = true
+		}
+		declared[resource.Name] = true
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
 }
 
-// ValidateStructure validates the structural requirements of AdapterTaskConfig
-func (v *TaskConfigValidator) ValidateStructure() error {
-	if v.config == nil {
-		return fmt.Errorf("task config is nil")
+// celIdentifierPattern matches names usable as CEL identifiers and template fields
+var celIdentifierPattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reservedVariableNames cannot be used by params, captures or payloads: they are
+// CEL roots set by the adapter, CEL extension namespaces or CEL language keywords.
+var reservedVariableNames = map[string]bool{
+	"adapter": true, "config": true, "env": true, "event": true, "now": true, "date": true,
+	"resources": true, "metadata": true, "flags": true, "base64": true, "regex": true,
+	"true": true, "false": true, "null": true, "in": true, "as": true, "break": true,
+	"const": true, "continue": true, "else": true, "for": true, "function": true, "if": true,
+	"import": true, "let": true, "loop": true, "package": true, "namespace": true,
+	"return": true, "var": true, "void": true, "while": true,
+}
+
+// validateNames rejects names that would silently overwrite each other at runtime.
+// Params, preconditions with an api_call, precondition captures, post payloads and
+// ManifestWork wait steps share one variable namespace and must be unique CEL identifiers that are not
+// reserved. Post-action names must be unique in their list, and resource and nested
+// discovery names must be unique across all resources since they share the
+// resources map.
+func (v *TaskConfigValidator) validateNames() error {
+	errs := &ValidationErrors{}
+
+	variables := make(map[string]string)
+	checkVariable := func(name, path string) {
+		if name == "" {
+			return
+		}
+		switch {
+		case !celIdentifierPattern.MatchString(name):
+			errs.Add(path, fmt.Sprintf("%q is not a valid CEL identifier "+
+				"(letters, digits and underscores, not starting with a digit)", name))
+		case reservedVariableNames[name]:
+			errs.Add(path, fmt.Sprintf("%q is a reserved name", name))
+		case name == FieldPlatform && v.config.Platform != nil:
+			errs.Add(path, fmt.Sprintf("%q is reserved for the resolved platform when platform is configured", name))
+		case variables[name] != "":
+			errs.Add(path, fmt.Sprintf("%q is already defined at %s", name, variables[name]))
+		default:
+			variables[name] = path
+		}
 	}
 
-	// Struct tag validation
-	if errs := ValidateStruct(v.config); errs != nil && errs.HasErrors() {
-		return fmt.Errorf("%s", errs.First())
+	for i, p := range v.config.Params {
+		checkVariable(p.Name, fmt.Sprintf("%s[%d].%s", FieldParams, i, FieldName))
+	}
+	for i, precond := range v.config.Preconditions {
+		path := fmt.Sprintf("%s[%d]", FieldPreconditions, i)
+		// API call responses are stored under the precondition name, next to the params;
+		// a condition-only precondition stores nothing and may share a param's name
+		if precond.APICall != nil {
+			checkVariable(precond.Name, path+"."+FieldName)
+		}
+		for j, capture := range precond.Capture {
+			checkVariable(capture.Name, fmt.Sprintf("%s.%s[%d].%s", path, FieldCapture, j, FieldName))
+		}
+	}
+	if v.config.Post != nil {
+		for i, payload := range v.config.Post.Payloads {
+			checkVariable(payload.Name, fmt.Sprintf("%s.%s[%d].%s", FieldPost, FieldPayloads, i, FieldName))
+		}
+		actions := make(map[string]string)
+		for i, action := range v.config.Post.PostActions {
+			path := fmt.Sprintf("%s.%s[%d].%s", FieldPost, FieldPostActions, i, FieldName)
+			if prev, ok := actions[action.Name]; ok && action.Name != "" {
+				errs.Add(path, fmt.Sprintf("%q is already defined at %s", action.Name, prev))
+			} else {
+				actions[action.Name] = path
+			}
+		}
 	}
 
+	waits := make(map[string]string)
+	for i, step := range v.config.Wait {
+		path := fmt.Sprintf("%s[%d].%s", FieldWait, i, FieldName)
+		if prev, ok := waits[step.Name]; ok && step.Name != "" {
+			errs.Add(path, fmt.Sprintf("%q is already defined at %s", step.Name, prev))
+			continue
+		}
+		waits[step.Name] = path
+		// ManifestWork waits store the reported status under the step name, next to the params
+		if step.ManifestWork != nil {
+			checkVariable(step.Name, path)
+		}
+	}
+
+	resources := make(map[string]string)
+	for i, resource := range v.config.Resources {
+		path := fmt.Sprintf("%s[%d]", FieldResources, i)
+		if prev, ok := resources[resource.Name]; ok && resource.Name != "" {
+			errs.Add(path+"."+FieldName, fmt.Sprintf("%q is already defined at %s", resource.Name, prev))
+		} else {
+			resources[resource.Name] = path + "." + FieldName
+		}
+	}
+	// Prune steps report their deletions under their name, next to the resources
+	for i, step := range v.config.Prune {
+		path := fmt.Sprintf("%s[%d].%s", FieldPrune, i, FieldName)
+		if prev, ok := resources[step.Name]; ok && step.Name != "" {
+			errs.Add(path, fmt.Sprintf("%q is already defined at %s", step.Name, prev))
+		} else {
+			resources[step.Name] = path
+		}
+	}
+	for i, resource := range v.config.Resources {
+		for j, nd := range resource.NestedDiscoveries {
+			path := fmt.Sprintf("%s[%d].%s[%d].%s", FieldResources, i, FieldNestedDiscoveries, j, FieldName)
+			if prev, ok := resources[nd.Name]; ok && nd.Name != "" {
+				errs.Add(path, fmt.Sprintf("%q is already defined at %s", nd.Name, prev))
+			} else {
+				resources[nd.Name] = path
+			}
+		}
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// validateDiscoveryOrder checks that discovery.order only lists configured methods, once each,
+// and that require_all combines both methods
+func (v *TaskConfigValidator) validateDiscoveryOrder() error {
+	check := func(d *DiscoveryConfig, path string) error {
+		if d == nil {
+			return nil
+		}
+		if d.RequireAll {
+			if !d.HasByName() || !d.HasBySelectors() {
+				return fmt.Errorf("%s.%s: requires both by_name and by_selectors", path, FieldRequireAll)
+			}
+			if len(d.Order) > 0 {
+				return fmt.Errorf("%s.%s: order has no effect when require_all is set", path, FieldOrder)
+			}
+		}
+		seen := make(map[string]bool, len(d.Order))
+		for _, m := range d.Order {
+			if seen[m] {
+				return fmt.Errorf("%s.%s: %q is listed more than once", path, FieldOrder, m)
+			}
+			seen[m] = true
+			if (m == DiscoveryByName && !d.HasByName()) || (m == DiscoveryBySelectors && !d.HasBySelectors()) {
+				return fmt.Errorf("%s.%s: %q is not configured", path, FieldOrder, m)
+			}
+		}
+		return nil
+	}
+
+	for i, resource := range v.config.Resources {
+		resourcePath := fmt.Sprintf("%s[%d]", FieldResources, i)
+		if err := check(resource.Discovery, resourcePath+"."+FieldDiscovery); err != nil {
+			return err
+		}
+		for j, nd := range resource.NestedDiscoveries {
+			ndPath := fmt.Sprintf("%s.%s[%d].%s", resourcePath, FieldNestedDiscoveries, j, FieldDiscovery)
+			if err := check(nd.Discovery, ndPath); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
@@ -133,11 +1231,11 @@ func (v *TaskConfigValidator) ValidateFileReferences() error {
 		}
 	}
 
-	// Validate manifest.ref in resources
+	// Validate manifest_ref and manifest.ref in resources
 	for i, resource := range v.config.Resources {
 		ref := resource.GetManifestRef()
 		if ref != "" {
-			path := fmt.Sprintf("%s[%d].%s.%s", FieldResources, i, FieldManifest, FieldRef)
+			path := fmt.Sprintf("%s[%d].%s", FieldResources, i, manifestRefPath(&resource))
 			if err := v.validateFileExists(ref, path); err != nil {
 				errors = append(errors, err.Error())
 			}
@@ -262,6 +1360,10 @@ func (v *TaskConfigValidator) validateParamAPICallTemplates() {
 			base := fmt.Sprintf("%s[%d].%s.%s", FieldParams, i, FieldSource, FieldAPICall)
 			v.validateTemplateStringWithVars(ac.URL, base+"."+FieldURL, available)
 			v.validateTemplateStringWithVars(ac.Body, base+"."+FieldBody, available)
+			for j, op := range ac.Patch {
+				v.validateTemplateStringWithVars(op.Path,
+					fmt.Sprintf("%s.%s[%d].%s", base, FieldPatch, j, FieldPatchPath), available)
+			}
 			for j, h := range ac.Headers {
 				v.validateTemplateStringWithVars(h.Value,
 					fmt.Sprintf("%s.%s[%d].%s", base, FieldHeaders, j, FieldHeaderValue), available)
@@ -341,6 +1443,11 @@ func (c *AdapterTaskConfig) GetDefinedVariables() map[string]bool {
 		}
 	}
 
+	// Resolved spoke platform
+	if c.Platform != nil {
+		vars[FieldPlatform] = true
+	}
+
 	// Resource aliases
 	for _, r := range c.Resources {
 		if r.Name != "" {
@@ -396,7 +1503,9 @@ func (v *TaskConfigValidator) initCELEnv() error {
 }
 
 func (v *TaskConfigValidator) validateTransportConfig() {
+	defer func() { v.inForEach = false }()
 	for i, resource := range v.config.Resources {
+		v.inForEach = resource.ForEach != ""
 		basePath := fmt.Sprintf("%s[%d]", FieldResources, i)
 
 		if resource.Transport != nil {
@@ -404,10 +1513,10 @@ func (v *TaskConfigValidator) validateTransportConfig() {
 
 			// Validate client type
 			client := resource.Transport.Client
-			if client != TransportClientKubernetes && client != TransportClientMaestro {
+			if !IsSupportedTransportClient(client) {
 				v.errors.Add(transportPath+"."+FieldClient,
-					fmt.Sprintf("unsupported transport client %q (supported: %s, %s)",
-						client, TransportClientKubernetes, TransportClientMaestro))
+					fmt.Sprintf("unsupported transport client %q (supported: %s)",
+						client, strings.Join(SupportedTransportClients(), ", ")))
 				continue
 			}
 
@@ -431,19 +1540,86 @@ func (v *TaskConfigValidator) validateTransportConfig() {
 						maestroPath+"."+FieldTargetCluster)
 				}
 
+				if placement := resource.Transport.Maestro.Placement; placement != nil {
+					v.validatePlacementMetadata(placement.Labels, maestroPath+"."+FieldPlacement+"."+FieldLabels, true)
+					v.validatePlacementMetadata(placement.Annotations,
+						maestroPath+"."+FieldPlacement+"."+FieldAnnotations, false)
+				}
+
+				if ordering := resource.Transport.Maestro.Ordering; ordering != nil {
+					v.validateManifestOrdering(ordering, maestroPath+"."+FieldOrdering)
+				}
+
+				v.validateFeedbackRules(resource.Transport.Maestro.FeedbackRules, maestroPath+"."+FieldFeedbackRules)
+
 				// Validate manifest is set for maestro transport
-				if resource.Manifest == nil {
+				if !resource.HasManifestSource() {
 					v.errors.Add(basePath+"."+FieldManifest,
 						"manifest is required for maestro transport")
 				}
 			}
 		}
 
-		// Validate manifest is required for kubernetes transport (default)
-		if resource.GetTransportClient() == TransportClientKubernetes && resource.Manifest == nil {
+		if resource.GetTransportClient() == TransportClientGitOps {
+			v.validateGitOpsTransport(&resource, basePath)
+		}
+
+		// Validate manifest is required for the other transports (kubernetes by default)
+		if !resource.IsMaestroTransport() && !resource.HasManifestSource() {
 			v.errors.Add(basePath+"."+FieldManifest,
-				"manifest is required for kubernetes transport")
+				fmt.Sprintf("manifest is required for %s transport", resource.GetTransportClient()))
+		}
+
+		v.validateApplyStrategy(&resource, basePath)
+
+		if resource.EnsureNamespace && !resource.IsKubernetesTransport() {
+			v.errors.Add(basePath+"."+FieldEnsureNamespace,
+				fmt.Sprintf("%s is only supported by the %s transport", FieldEnsureNamespace, TransportClientKubernetes))
+		}
+		if resource.GetDriftPolicy() != DriftPolicyIgnore && !resource.IsKubernetesTransport() {
+			v.errors.Add(basePath+"."+FieldDriftPolicy,
+				fmt.Sprintf("%s is only supported by the %s transport", FieldDriftPolicy, TransportClientKubernetes))
+		}
+	}
+}
+
+// validateGitOpsTransport checks the gitops settings of a resource: the repository path is
+// required, and it and the commit message are templates
+func (v *TaskConfigValidator) validateGitOpsTransport(resource *Resource, basePath string) {
+	gitopsPath := basePath + "." + FieldTransport + "." + FieldGitOps
+	gitops := resource.Transport.GitOps
+	if gitops == nil {
+		v.errors.Add(gitopsPath, "gitops transport config is required when client is \"gitops\"")
+		return
+	}
+	if gitops.Path == "" {
+		v.errors.Add(gitopsPath+"."+FieldPath, "path is required for gitops transport")
+	} else {
+		v.validateTemplateString(gitops.Path, gitopsPath+"."+FieldPath)
+	}
+	if gitops.CommitMessage != "" {
+		v.validateTemplateString(gitops.CommitMessage, gitopsPath+"."+FieldCommitMessage)
+	}
+}
+
+// validateApplyStrategy checks that server-side apply is only used where it is supported
+func (v *TaskConfigValidator) validateApplyStrategy(resource *Resource, basePath string) {
+	if !resource.UsesServerSideApply() {
+		if resource.FieldManager != "" {
+			v.errors.Add(basePath+"."+FieldFieldManager,
+				fmt.Sprintf("field_manager requires %s: %s", FieldApplyStrategy, ApplyStrategyServerSideApply))
 		}
+		return
+	}
+
+	strategyPath := basePath + "." + FieldApplyStrategy
+	if !resource.IsKubernetesTransport() {
+		v.errors.Add(strategyPath,
+			fmt.Sprintf("%s is only supported by the %s transport", ApplyStrategyServerSideApply, TransportClientKubernetes))
+	}
+	if resource.RecreateOnChange {
+		v.errors.Add(strategyPath,
+			fmt.Sprintf("%s cannot be combined with %s", ApplyStrategyServerSideApply, FieldRecreateOnChange))
 	}
 }
 
@@ -485,33 +1661,82 @@ func (v *TaskConfigValidator) validateCaptureFieldExpressions() {
 				path := fmt.Sprintf("%s[%d].%s[%d].%s", FieldPreconditions, i, FieldCapture, j, FieldExpression)
 				v.validateCELExpression(capture.Expression, path)
 			}
+			if capture.Transform != "" {
+				v.validateCaptureTransform(capture.Transform,
+					fmt.Sprintf("%s[%d].%s[%d].%s", FieldPreconditions, i, FieldCapture, j, FieldTransform))
+			}
 		}
 	}
 }
 
+// validateCaptureTransform checks that a capture transform is a known function, or a
+// template that parses and only uses the value and defined variables
+func (v *TaskConfigValidator) validateCaptureTransform(transform, path string) {
+	switch transform {
+	case CaptureTransformLower, CaptureTransformTrim, CaptureTransformToInt, CaptureTransformToBool:
+		return
+	}
+	text, ok := strings.CutPrefix(transform, CaptureTransformTemplatePrefix)
+	if !ok {
+		v.errors.Add(path, fmt.Sprintf("unknown transform %q (supported: %s, %s, %s, %s, %s<template>)", transform,
+			CaptureTransformLower, CaptureTransformTrim, CaptureTransformToInt, CaptureTransformToBool,
+			CaptureTransformTemplatePrefix))
+		return
+	}
+	if _, err := template.New(path).Funcs(utils.TemplateFuncs).Parse(text); err != nil {
+		v.errors.Add(path, fmt.Sprintf("invalid template: %v", err))
+		return
+	}
+	vars := make(map[string]bool, len(v.definedVars)+1)
+	for name := range v.definedVars {
+		vars[name] = true
+	}
+	vars["value"] = true
+	v.validateTemplateStringWithVars(text, path, vars)
+}
+
 func (v *TaskConfigValidator) validateTemplateVariables() {
 	// Validate precondition API call URLs and bodies
 	for i, precond := range v.config.Preconditions {
+		v.inForEach = precond.ForEach != ""
 		if precond.APICall != nil {
 			basePath := fmt.Sprintf("%s[%d].%s", FieldPreconditions, i, FieldAPICall)
 			v.validateTemplateString(precond.APICall.URL, basePath+"."+FieldURL)
 			v.validateTemplateString(precond.APICall.Body, basePath+"."+FieldBody)
+			for j, op := range precond.APICall.Patch {
+				v.validateTemplateString(op.Path, fmt.Sprintf("%s.%s[%d].%s", basePath, FieldPatch, j, FieldPatchPath))
+			}
 			for j, header := range precond.APICall.Headers {
 				v.validateTemplateString(header.Value,
 					fmt.Sprintf("%s.%s[%d].%s", basePath, FieldHeaders, j, FieldHeaderValue))
 			}
+			for j, file := range precond.APICall.Files {
+				filePath := fmt.Sprintf("%s.%s[%d]", basePath, FieldFiles, j)
+				v.validateTemplateString(file.Filename, filePath+".filename")
+				v.validateTemplateString(file.Content, filePath+".content")
+				v.validateTemplateString(file.Path, filePath+".path")
+			}
 		}
 	}
+	v.inForEach = false
 
 	// Validate resource manifests and transport config templates
 	// All manifests are validated as template strings — map manifests are serialized
 	// to YAML first since they are rendered as Go templates at execution time.
 	for i, resource := range v.config.Resources {
+		v.inForEach = resource.ForEach != ""
 		resourcePath := fmt.Sprintf("%s[%d]", FieldResources, i)
 		manifestStr, err := manifest.ToYAMLString(resource.Manifest)
 		if err == nil && manifestStr != "" {
 			v.validateTemplateString(manifestStr, resourcePath+"."+FieldManifest)
 		}
+		if h := resource.Helm; h != nil {
+			helmPath := resourcePath + "." + FieldHelm
+			v.validateTemplateString(h.ReleaseName, helmPath+"."+FieldReleaseName)
+			v.validateTemplateString(h.Namespace, helmPath+"."+FieldNamespace)
+			v.validateTemplateString(h.ManifestWorkName, helmPath+"."+FieldManifestWorkName)
+			v.validateTemplateMap(h.Values, helmPath+"."+FieldValues)
+		}
 		// NOTE: For maestro transport, we skip template variable validation for manifest content.
 		// ManifestWork templates may use variables provided at runtime by the framework
 		// (e.g., adapterName, timestamp) that are not necessarily declared in params or captures.
@@ -541,6 +1766,31 @@ func (v *TaskConfigValidator) validateTemplateVariables() {
 			}
 		}
 	}
+	v.inForEach = false
+
+	for i, step := range v.config.Prune {
+		prunePath := fmt.Sprintf("%s[%d]", FieldPrune, i)
+		v.validateTemplateString(step.Namespace, prunePath+"."+FieldNamespace)
+		for k, val := range step.LabelSelector {
+			v.validateTemplateString(k, fmt.Sprintf("%s.%s[%s]", prunePath, FieldLabelSelector, k))
+			v.validateTemplateString(val, fmt.Sprintf("%s.%s[%s]", prunePath, FieldLabelSelector, k))
+		}
+	}
+
+	for i, step := range v.config.Wait {
+		if step.APICall != nil {
+			basePath := fmt.Sprintf("%s[%d].%s", FieldWait, i, FieldAPICall)
+			v.validateTemplateString(step.APICall.URL, basePath+"."+FieldURL)
+			v.validateTemplateString(step.APICall.Body, basePath+"."+FieldBody)
+			for j, op := range step.APICall.Patch {
+				v.validateTemplateString(op.Path, fmt.Sprintf("%s.%s[%d].%s", basePath, FieldPatch, j, FieldPatchPath))
+			}
+			for j, header := range step.APICall.Headers {
+				v.validateTemplateString(header.Value,
+					fmt.Sprintf("%s.%s[%d].%s", basePath, FieldHeaders, j, FieldHeaderValue))
+			}
+		}
+	}
 
 	// Validate post action API calls
 	if v.config.Post != nil {
@@ -549,6 +1799,9 @@ func (v *TaskConfigValidator) validateTemplateVariables() {
 				basePath := fmt.Sprintf("%s.%s[%d].%s", FieldPost, FieldPostActions, i, FieldAPICall)
 				v.validateTemplateString(action.APICall.URL, basePath+"."+FieldURL)
 				v.validateTemplateString(action.APICall.Body, basePath+"."+FieldBody)
+				for j, op := range action.APICall.Patch {
+					v.validateTemplateString(op.Path, fmt.Sprintf("%s.%s[%d].%s", basePath, FieldPatch, j, FieldPatchPath))
+				}
 				for j, header := range action.APICall.Headers {
 					v.validateTemplateString(header.Value,
 						fmt.Sprintf("%s.%s[%d].%s", basePath, FieldHeaders, j, FieldHeaderValue))
@@ -567,6 +1820,93 @@ func (v *TaskConfigValidator) validateTemplateVariables() {
 	}
 }
 
+// validatePlacementMetadata checks ManifestWork placement labels or annotations.
+// Keys must be qualified names and may not override the generation key managed by
+// the adapter. Label values are checked as label values unless they are templated.
+func (v *TaskConfigValidator) validatePlacementMetadata(entries map[string]string, path string, isLabel bool) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := entries[key]
+		entryPath := fmt.Sprintf("%s[%s]", path, key)
+		if errs := k8svalidation.IsQualifiedName(key); len(errs) > 0 {
+			v.errors.Add(entryPath, fmt.Sprintf("invalid key: %s", strings.Join(errs, "; ")))
+			continue
+		}
+		if key == constants.AnnotationGeneration {
+			v.errors.Add(entryPath, "key is managed by the adapter and cannot be set")
+			continue
+		}
+		if strings.Contains(value, "{{") {
+			v.validateTemplateString(value, entryPath)
+			continue
+		}
+		if isLabel {
+			if errs := k8svalidation.IsValidLabelValue(value); len(errs) > 0 {
+				v.errors.Add(entryPath, fmt.Sprintf("invalid label value: %s", strings.Join(errs, "; ")))
+			}
+		}
+	}
+}
+
+// validateManifestOrdering checks the tiers of a ManifestWork ordering. Tier names end
+// up in the names of split ManifestWorks, so they must be DNS labels, and a kind may
+// only belong to one tier.
+func (v *TaskConfigValidator) validateManifestOrdering(ordering *ManifestOrderingConfig, path string) {
+	tierNames := make(map[string]bool, len(ordering.Tiers))
+	kindTiers := make(map[string]string)
+	for i, tier := range ordering.Tiers {
+		tierPath := fmt.Sprintf("%s.%s[%d]", path, FieldTiers, i)
+		if errs := k8svalidation.IsDNS1123Label(tier.Name); len(errs) > 0 {
+			v.errors.Add(tierPath+"."+FieldName, fmt.Sprintf("invalid tier name: %s", strings.Join(errs, "; ")))
+		} else if tier.Name == manifest.RemainderTier {
+			v.errors.Add(tierPath+"."+FieldName,
+				fmt.Sprintf("%q is reserved for the kinds that are in no tier", manifest.RemainderTier))
+		} else if tierNames[tier.Name] {
+			v.errors.Add(tierPath+"."+FieldName, fmt.Sprintf("duplicate tier name %q", tier.Name))
+		}
+		tierNames[tier.Name] = true
+
+		for j, kind := range tier.Kinds {
+			if other, ok := kindTiers[kind]; ok {
+				v.errors.Add(fmt.Sprintf("%s.%s[%d]", tierPath, FieldKinds, j),
+					fmt.Sprintf("kind %q is already in tier %q", kind, other))
+				continue
+			}
+			kindTiers[kind] = tier.Name
+		}
+	}
+}
+
+// validateFeedbackRules checks that each feedback rule reports something, that its JSON
+// paths are relative to the manifest and uniquely named, and that its templates are defined
+func (v *TaskConfigValidator) validateFeedbackRules(rules []ManifestFeedbackRule, path string) {
+	for i := range rules {
+		rule := &rules[i]
+		rulePath := fmt.Sprintf("%s[%d]", path, i)
+		if !rule.WellKnownStatus && len(rule.JSONPaths) == 0 {
+			v.errors.Add(rulePath, "feedback rule must set well_known_status or json_paths")
+		}
+		v.validateTemplateString(rule.Name, rulePath+"."+FieldName)
+		v.validateTemplateString(rule.Namespace, rulePath+"."+FieldNamespace)
+
+		names := make(map[string]bool, len(rule.JSONPaths))
+		for j, jp := range rule.JSONPaths {
+			jpPath := fmt.Sprintf("%s.%s[%d]", rulePath, FieldJSONPaths, j)
+			if names[jp.Name] {
+				v.errors.Add(jpPath+"."+FieldName, fmt.Sprintf("duplicate json path name %q", jp.Name))
+			}
+			names[jp.Name] = true
+			if jp.Path != "" && !strings.HasPrefix(jp.Path, ".") {
+				v.errors.Add(jpPath+"."+FieldPath, fmt.Sprintf("json path %q must start with \".\", e.g. .status.phase", jp.Path))
+			}
+		}
+	}
+}
+
 func (v *TaskConfigValidator) validateTemplateString(s string, path string) {
 	if s == "" {
 		return
@@ -596,6 +1936,10 @@ func (v *TaskConfigValidator) isVariableDefined(varName string) bool {
 			return true
 		}
 
+		if v.inForEach && (root == ForEachItemVariable || root == ForEachIndexVariable) {
+			return true
+		}
+
 		if root == FieldResources && len(parts) > 1 {
 			alias := root + "." + parts[1]
 			if v.definedVars[alias] {
@@ -647,6 +1991,15 @@ func (v *TaskConfigValidator) validateCELExpressions() {
 		}
 	}
 
+	for i, step := range v.config.Wait {
+		v.validateCELExpression(step.Condition, fmt.Sprintf("%s[%d].%s", FieldWait, i, FieldCondition))
+	}
+
+	if platform := v.config.Platform; platform != nil {
+		v.validateCELExpression(platform.OS, FieldPlatform+"."+FieldOS)
+		v.validateCELExpression(platform.Architecture, FieldPlatform+"."+FieldArchitecture)
+	}
+
 	if v.config.Post != nil {
 		for i, payload := range v.config.Post.Payloads {
 			if payload.When != nil && payload.When.Expression != "" {
@@ -667,6 +2020,22 @@ func (v *TaskConfigValidator) validateCELExpressions() {
 			}
 		}
 	}
+
+	for _, ref := range v.apiCalls() {
+		for j, op := range ref.call.Patch {
+			v.validateCELExpression(op.ValueExpression,
+				fmt.Sprintf("%s.%s[%d].%s", ref.path, FieldPatch, j, FieldValueExpression))
+		}
+		v.validateCELExpression(ref.call.Transform, ref.path+"."+FieldTransform)
+	}
+
+	for i, resource := range v.config.Resources {
+		v.validateCELExpression(resource.Transform, fmt.Sprintf("%s[%d].%s", FieldResources, i, FieldTransform))
+		v.validateCELExpression(resource.ForEach, fmt.Sprintf("%s[%d].%s", FieldResources, i, FieldForEach))
+	}
+	for i, precond := range v.config.Preconditions {
+		v.validateCELExpression(precond.ForEach, fmt.Sprintf("%s[%d].%s", FieldPreconditions, i, FieldForEach))
+	}
 }
 
 func (v *TaskConfigValidator) validateCELExpression(expr string, path string) {
@@ -714,13 +2083,50 @@ func (v *TaskConfigValidator) validateK8sManifests() {
 		// All manifests are rendered as Go templates at execution time.
 		// K8s structural validation is deferred to execution time since
 		// template parameters are not available at config load time.
-		// Only validate manifest ref is not empty.
+		// Only validate manifest ref is not empty and, for inline manifests, the namespace scope.
 		if m, ok := resource.Manifest.(map[string]interface{}); ok {
 			if ref, hasRef := m[FieldRef].(string); hasRef {
 				if ref == "" {
 					v.errors.Add(path+"."+FieldRef, "manifest ref cannot be empty")
 				}
+				continue
 			}
+			v.validateManifestScope(m, resource.IsMaestroTransport(), path)
+		}
+	}
+}
+
+// validateManifestScope checks metadata.namespace against the scope of the kind for
+// inline manifests. kind and apiVersion are static, and a templated namespace still
+// counts as set, so this check holds regardless of the parameters at execution time.
+// For maestro transport the workload manifests inside the ManifestWork are checked.
+func (v *TaskConfigValidator) validateManifestScope(m map[string]interface{}, maestro bool, path string) {
+	if !maestro {
+		if reason := manifest.NamespaceScopeViolation(&unstructured.Unstructured{Object: m}); reason != "" {
+			v.errors.Add(path, reason)
+		}
+		return
+	}
+
+	spec, ok := m["spec"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	workload, ok := spec["workload"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	manifests, ok := workload["manifests"].([]interface{})
+	if !ok {
+		return
+	}
+	for i, item := range manifests {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if reason := manifest.NamespaceScopeViolation(&unstructured.Unstructured{Object: obj}); reason != "" {
+			v.errors.Add(fmt.Sprintf("%s.spec.workload.manifests[%d]", path, i), reason)
 		}
 	}
 }
@@ -731,7 +2137,9 @@ func (v *TaskConfigValidator) validateLifecycleConfig() {
 		"Foreground": true,
 		"Orphan":     true,
 	}
+	defer func() { v.inForEach = false }()
 	for i, resource := range v.config.Resources {
+		v.inForEach = resource.ForEach != ""
 		if resource.Lifecycle == nil {
 			continue
 		}
@@ -794,6 +2202,46 @@ func (v *TaskConfigValidator) validateLifecycleConfig() {
 					path := basePath + "." + FieldLifecycleWhen + "." + FieldExpression
 					v.validateCELExpression(del.When.Expression, path)
 				}
+
+				v.validateLifecycleFinalizer(&resource, basePath)
+			}
+		}
+	}
+}
+
+// validateLifecycleFinalizer checks lifecycle.delete.finalizer and the teardown steps
+// that run before it is removed
+func (v *TaskConfigValidator) validateLifecycleFinalizer(resource *Resource, basePath string) {
+	del := resource.Lifecycle.Delete
+	if del.Finalizer != "" {
+		path := basePath + "." + FieldLifecycleFinalizer
+		if !resource.IsKubernetesTransport() {
+			v.errors.Add(path, "finalizer is only supported with the kubernetes transport")
+		}
+		if msgs := k8svalidation.IsQualifiedName(del.Finalizer); len(msgs) > 0 {
+			v.errors.Add(path, fmt.Sprintf("invalid finalizer %q: %s", del.Finalizer, strings.Join(msgs, "; ")))
+		}
+	}
+	if len(del.Teardown) > 0 && del.Finalizer == "" {
+		v.errors.Add(basePath+"."+FieldLifecycleTeardown,
+			"teardown requires a finalizer: without it the resource can be gone before teardown succeeds")
+	}
+	for j, step := range del.Teardown {
+		stepPath := fmt.Sprintf("%s.%s[%d]", basePath, FieldLifecycleTeardown, j)
+		if step.APICall == nil && step.Log == nil {
+			v.errors.Add(stepPath, "teardown step must have an api_call or a log action")
+			continue
+		}
+		if step.APICall != nil {
+			callPath := stepPath + "." + FieldAPICall
+			v.validateTemplateString(step.APICall.URL, callPath+"."+FieldURL)
+			v.validateTemplateString(step.APICall.Body, callPath+"."+FieldBody)
+			for k, op := range step.APICall.Patch {
+				v.validateTemplateString(op.Path, fmt.Sprintf("%s.%s[%d].%s", callPath, FieldPatch, k, FieldPatchPath))
+			}
+			for k, header := range step.APICall.Headers {
+				v.validateTemplateString(header.Value,
+					fmt.Sprintf("%s.%s[%d].%s", callPath, FieldHeaders, k, FieldHeaderValue))
 			}
 		}
 	}
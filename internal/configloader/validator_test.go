@@ -1,11 +1,16 @@
 package configloader
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/kafkabroker"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/admin"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -365,6 +370,31 @@ func TestValidatePostActionWhenCELExpression(t *testing.T) {
 	})
 }
 
+func TestValidatePostActionPhase(t *testing.T) {
+	withPhase := func(phase string) *AdapterTaskConfig {
+		cfg := baseTaskConfig()
+		cfg.Post = &PostConfig{
+			PostActions: []PostAction{{
+				ActionBase: ActionBase{Name: "reportStatus", Log: &LogAction{Message: "done"}},
+				Phase:      phase,
+			}},
+		}
+		return cfg
+	}
+
+	for _, phase := range []string{"", PostActionPhasePostActions, PostActionPhaseReporting} {
+		t.Run("valid phase "+phase, func(t *testing.T) {
+			require.NoError(t, newTaskValidator(withPhase(phase)).ValidateStructure())
+		})
+	}
+
+	t.Run("unknown phase", func(t *testing.T) {
+		err := newTaskValidator(withPhase("resources")).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `phase "resources" is invalid (allowed: post_actions, reporting)`)
+	})
+}
+
 func TestValidateK8sManifests(t *testing.T) {
 	// Helper to create config with a resource manifest
 	withResource := func(manifest map[string]interface{}) *AdapterTaskConfig {
@@ -462,6 +492,78 @@ metadata:
 		require.NoError(t, v.ValidateSemantic())
 	})
 
+	t.Run("cluster-scoped manifest with namespace", func(t *testing.T) {
+		cfg := withResource(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]interface{}{"name": "test", "namespace": "default"},
+		})
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `Namespace "test" is cluster-scoped but sets namespace "default"`)
+	})
+
+	t.Run("namespaced manifest without namespace", func(t *testing.T) {
+		cfg := withResource(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "test"},
+		})
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `ConfigMap "test" is namespaced but does not set metadata.namespace`)
+	})
+
+	t.Run("namespaced manifest with templated namespace", func(t *testing.T) {
+		cfg := withResource(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "test", "namespace": "{{ .clusterId }}"},
+		})
+		cfg.Params = []Parameter{{Name: "clusterId", Source: StringSource("event.id"), Type: "string"}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("maestro workload manifest scope is checked", func(t *testing.T) {
+		cfg := withResource(map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "work"},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []interface{}{
+						map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       "Namespace",
+							"metadata":   map[string]interface{}{"name": "ns"},
+						},
+						map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       "ConfigMap",
+							"metadata":   map[string]interface{}{"name": "cm"},
+						},
+					},
+				},
+			},
+		})
+		cfg.Resources[0].Transport = &TransportConfig{
+			Client:  TransportClientMaestro,
+			Maestro: &MaestroTransportConfig{TargetCluster: "cluster1"},
+		}
+		v := newTaskValidator(cfg)
+		_ = v.ValidateStructure()
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "resources[0].manifest.spec.workload.manifests[1]")
+		assert.Contains(t, err.Error(), `ConfigMap "cm" is namespaced`)
+	})
+
 	t.Run("map manifest defers K8s validation to execution", func(t *testing.T) {
 		// All manifests are rendered as Go templates — K8s structural
 		// validation is deferred to execution time
@@ -660,6 +762,42 @@ func TestValidateCaptureFields(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "name is required")
 	})
+
+	t.Run("valid transforms", func(t *testing.T) {
+		cfg := withCapture([]CaptureField{
+			{Name: "isReady", FieldExpressionDef: FieldExpressionDef{Field: "ready"}, Transform: CaptureTransformToBool},
+			{
+				Name:               "label",
+				FieldExpressionDef: FieldExpressionDef{Field: "phase"},
+				Transform:          "template:{{ .clusterPhase }}-{{ .value | lower }}",
+			},
+		})
+		cfg.Params = []Parameter{{Name: "clusterPhase", Source: StringSource("event.phase")}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	for name, tt := range map[string]struct {
+		transform string
+		wantErr   string
+	}{
+		"unknown transform":      {transform: "upper", wantErr: `unknown transform "upper"`},
+		"unparsable template":    {transform: "template:{{ .value", wantErr: "invalid template"},
+		"undefined template var": {transform: "template:{{ .missing }}", wantErr: `undefined template variable "missing"`},
+	} {
+		t.Run("invalid - "+name, func(t *testing.T) {
+			cfg := withCapture([]CaptureField{{
+				Name: "phase", FieldExpressionDef: FieldExpressionDef{Field: "phase"}, Transform: tt.transform,
+			}})
+			v := newTaskValidator(cfg)
+			require.NoError(t, v.ValidateStructure())
+			err := v.ValidateSemantic()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "preconditions[0].capture[0].transform")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
 }
 
 func TestYamlFieldName(t *testing.T) {
@@ -777,6 +915,28 @@ func TestValidateTransportConfig(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid")
 	})
 
+	t.Run("registered transport client", func(t *testing.T) {
+		RegisterTransportClient("test-argocd")
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:      "app",
+			Transport: &TransportConfig{Client: "test-argocd"},
+			Manifest: map[string]interface{}{
+				"apiVersion": "argoproj.io/v1alpha1",
+				"kind":       "Application",
+				"metadata":   map[string]interface{}{"name": "app"},
+			},
+			Discovery: &DiscoveryConfig{ByName: "app"},
+		}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+		config := &Config{Resources: cfg.Resources}
+		assert.Equal(t, []string{"test-argocd"}, config.TransportClients())
+		config.Prune = []PruneStep{{Name: "stale"}}
+		assert.Equal(t, []string{"kubernetes", "test-argocd"}, config.TransportClients())
+	})
+
 	t.Run("maestro transport missing maestro config", func(t *testing.T) {
 		cfg := baseTaskConfig()
 		cfg.Resources = []Resource{{
@@ -964,564 +1124,2819 @@ func TestValidateTransportConfig(t *testing.T) {
 	})
 }
 
-func TestValidateFileReferencesManifestRef(t *testing.T) {
-	tmpDir := t.TempDir()
-
-	// Create a test manifest file (ManifestWork content)
-	manifestDir := filepath.Join(tmpDir, "templates")
-	require.NoError(t, os.MkdirAll(manifestDir, 0o755))
-	manifestFile := filepath.Join(manifestDir, "manifestwork.yaml")
-	manifestContent := []byte("apiVersion: work.open-cluster-management.io/v1\nkind: ManifestWork")
-	require.NoError(t, os.WriteFile(manifestFile, manifestContent, 0o644))
-
-	tests := []struct {
-		name    string
-		config  *AdapterTaskConfig
-		errMsg  string
-		wantErr bool
-	}{
-		{
-			name: "valid manifest ref for maestro transport",
-			config: &AdapterTaskConfig{
-				Resources: []Resource{{
-					Name: "test",
-					Transport: &TransportConfig{
-						Client: TransportClientMaestro,
-						Maestro: &MaestroTransportConfig{
-							TargetCluster: "cluster1",
-						},
-					},
-					Manifest: map[string]interface{}{
-						"ref": "templates/manifestwork.yaml",
-					},
-					Discovery: &DiscoveryConfig{
-						BySelectors: &SelectorConfig{
-							LabelSelector: map[string]string{"app": "test"},
-						},
-					},
-				}},
-			},
-			wantErr: false,
-		},
-		{
-			name: "invalid manifest ref - file not found",
-			config: &AdapterTaskConfig{
-				Resources: []Resource{{
-					Name: "test",
-					Transport: &TransportConfig{
-						Client: TransportClientMaestro,
-						Maestro: &MaestroTransportConfig{
-							TargetCluster: "cluster1",
-						},
-					},
-					Manifest: map[string]interface{}{
-						"ref": "templates/nonexistent.yaml",
-					},
-					Discovery: &DiscoveryConfig{
-						BySelectors: &SelectorConfig{
-							LabelSelector: map[string]string{"app": "test"},
-						},
-					},
-				}},
-			},
-			wantErr: true,
-			errMsg:  "does not exist",
-		},
-		{
-			name: "inline manifest - no file reference validation needed",
-			config: &AdapterTaskConfig{
-				Resources: []Resource{{
-					Name: "test",
-					Transport: &TransportConfig{
-						Client: TransportClientMaestro,
-						Maestro: &MaestroTransportConfig{
-							TargetCluster: "cluster1",
-						},
-					},
-					Manifest: map[string]interface{}{
-						"apiVersion": "work.open-cluster-management.io/v1",
-						"kind":       "ManifestWork",
-					},
-					Discovery: &DiscoveryConfig{
-						BySelectors: &SelectorConfig{
-							LabelSelector: map[string]string{"app": "test"},
-						},
-					},
-				}},
+func TestValidateApplyStrategy(t *testing.T) {
+	newResource := func() Resource {
+		return Resource{
+			Name: "testNs",
+			Manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Namespace",
+				"metadata":   map[string]interface{}{"name": "test"},
 			},
-			wantErr: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			validator := NewTaskConfigValidator(tt.config, tmpDir)
-			err := validator.ValidateFileReferences()
-			if tt.wantErr {
-				require.Error(t, err)
-				assert.Contains(t, err.Error(), tt.errMsg)
-			} else {
-				assert.NoError(t, err)
-			}
-		})
+			Discovery: &DiscoveryConfig{ByName: "test"},
+		}
 	}
-}
-
-func TestValidateLifecycleConfig(t *testing.T) {
-	// minResource satisfies required fields (manifest, discovery) so we can focus on lifecycle validation.
-	minDiscovery := &DiscoveryConfig{ByName: "my-resource"}
-	minManifest := map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}
 
-	withLifecycle := func(del *LifecycleDelete) *AdapterTaskConfig {
+	t.Run("server-side apply with field manager", func(t *testing.T) {
 		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:      "myResource",
-			Discovery: minDiscovery,
-			Manifest:  minManifest,
-			Lifecycle: &ResourceLifecycle{Delete: del},
-		}}
-		return cfg
-	}
-
-	t.Run("valid lifecycle delete with when expression", func(t *testing.T) {
-		cfg := withLifecycle(&LifecycleDelete{
-			When: &LifecycleWhen{Expression: "deletedTime != null"},
-		})
+		r := newResource()
+		r.ApplyStrategy = ApplyStrategyServerSideApply
+		r.FieldManager = "landing-zone"
+		cfg.Resources = []Resource{r}
 		v := newTaskValidator(cfg)
 		require.NoError(t, v.ValidateStructure())
 		require.NoError(t, v.ValidateSemantic())
 	})
 
-	t.Run("lifecycle delete missing when block", func(t *testing.T) {
-		cfg := withLifecycle(&LifecycleDelete{
-			PropagationPolicy: "Background",
-		})
-		err := newTaskValidator(cfg).ValidateSemantic()
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "lifecycle.delete.when.expression is required")
+	t.Run("unknown strategy", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		r := newResource()
+		r.ApplyStrategy = "patch"
+		cfg.Resources = []Resource{r}
+		v := newTaskValidator(cfg)
+		require.Error(t, v.ValidateStructure())
 	})
 
-	t.Run("lifecycle delete with empty when expression", func(t *testing.T) {
-		cfg := withLifecycle(&LifecycleDelete{
-			When: &LifecycleWhen{Expression: ""},
-		})
-		err := newTaskValidator(cfg).ValidateSemantic()
+	t.Run("field manager without server-side apply", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		r := newResource()
+		r.FieldManager = "landing-zone"
+		cfg.Resources = []Resource{r}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "lifecycle.delete.when.expression is required")
+		assert.Contains(t, err.Error(), "field_manager requires apply_strategy")
 	})
 
-	t.Run("lifecycle delete with invalid propagationPolicy", func(t *testing.T) {
-		cfg := withLifecycle(&LifecycleDelete{
-			PropagationPolicy: "Invalid",
-			When:              &LifecycleWhen{Expression: "deletedTime != null"},
-		})
-		err := newTaskValidator(cfg).ValidateSemantic()
+	t.Run("server-side apply with recreate_on_change", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		r := newResource()
+		r.ApplyStrategy = ApplyStrategyServerSideApply
+		r.RecreateOnChange = true
+		cfg.Resources = []Resource{r}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid propagationPolicy")
+		assert.Contains(t, err.Error(), "cannot be combined with recreate_on_change")
 	})
 
-	t.Run("lifecycle delete with invalid CEL expression", func(t *testing.T) {
-		cfg := withLifecycle(&LifecycleDelete{
-			When: &LifecycleWhen{Expression: "deletedTime != null &&"},
-		})
-		err := newTaskValidator(cfg).ValidateSemantic()
+	t.Run("server-side apply with maestro transport", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		r := newResource()
+		r.Transport = &TransportConfig{
+			Client:  TransportClientMaestro,
+			Maestro: &MaestroTransportConfig{TargetCluster: "cluster1"},
+		}
+		r.Manifest = map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "test-mw"},
+		}
+		r.ApplyStrategy = ApplyStrategyServerSideApply
+		cfg.Resources = []Resource{r}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "CEL parse error")
+		assert.Contains(t, err.Error(), "only supported by the kubernetes transport")
 	})
+}
 
-	t.Run("lifecycle delete missing discovery", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:     "myResource",
-			Manifest: minManifest,
-			// Discovery intentionally absent
-			Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{
-				When: &LifecycleWhen{Expression: "is_deleting"},
-			}},
+func TestValidateEnsureNamespace(t *testing.T) {
+	cfg := baseTaskConfig()
+	cfg.Resources = []Resource{{
+		Name: "testWork",
+		Transport: &TransportConfig{
+			Client:  TransportClientMaestro,
+			Maestro: &MaestroTransportConfig{TargetCluster: "cluster1"},
+		},
+		Manifest: map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "test-mw"},
+		},
+		Discovery:       &DiscoveryConfig{ByName: "test-mw"},
+		EnsureNamespace: true,
+	}}
+	v := newTaskValidator(cfg)
+	require.NoError(t, v.ValidateStructure())
+	err := v.ValidateSemantic()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"resources[0].ensure_namespace: ensure_namespace is only supported by the kubernetes transport")
+}
+
+func TestValidateDriftPolicy(t *testing.T) {
+	cfg := baseTaskConfig()
+	cfg.Resources = []Resource{{
+		Name: "testWork",
+		Transport: &TransportConfig{
+			Client:  TransportClientMaestro,
+			Maestro: &MaestroTransportConfig{TargetCluster: "cluster1"},
+		},
+		Manifest: map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "test-mw"},
+		},
+		Discovery:   &DiscoveryConfig{ByName: "test-mw"},
+		DriftPolicy: DriftPolicyReport,
+	}}
+	v := newTaskValidator(cfg)
+	require.NoError(t, v.ValidateStructure())
+	err := v.ValidateSemantic()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"resources[0].drift_policy: drift_policy is only supported by the kubernetes transport")
+
+	cfg.Resources[0].DriftPolicy = "fix"
+	require.Error(t, newTaskValidator(cfg).ValidateStructure())
+
+	cfg.Resources[0].DriftPolicy = DriftPolicyIgnore
+	v = newTaskValidator(cfg)
+	require.NoError(t, v.ValidateStructure())
+	require.NoError(t, v.ValidateSemantic())
+}
+
+func TestValidateMaestroPlacement(t *testing.T) {
+	build := func(placement *MaestroPlacementConfig) *AdapterTaskConfig {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{{Name: "clusterId", Source: StringSource("event.id")}}
+		cfg.Resources = []Resource{{
+			Name: "testMW",
+			Transport: &TransportConfig{
+				Client: TransportClientMaestro,
+				Maestro: &MaestroTransportConfig{
+					TargetCluster: "cluster1",
+					Placement:     placement,
+				},
+			},
+			Manifest: map[string]interface{}{
+				"apiVersion": "work.open-cluster-management.io/v1",
+				"kind":       "ManifestWork",
+			},
+			Discovery: &DiscoveryConfig{ByName: "work"},
+		}}
+		return cfg
+	}
+
+	t.Run("valid placement", func(t *testing.T) {
+		v := newTaskValidator(build(&MaestroPlacementConfig{
+			Labels:      map[string]string{"placement.example.com/region": "{{ .clusterId }}", "tier": "gold"},
+			Annotations: map[string]string{"placement.example.com/hint": "any value, with spaces"},
+		}))
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	tests := []struct {
+		name      string
+		placement *MaestroPlacementConfig
+		wantErr   string
+	}{
+		{
+			name:      "invalid label key",
+			placement: &MaestroPlacementConfig{Labels: map[string]string{"bad key": "v"}},
+			wantErr:   "placement.labels[bad key]: invalid key",
+		},
+		{
+			name:      "invalid static label value",
+			placement: &MaestroPlacementConfig{Labels: map[string]string{"tier": "not valid!"}},
+			wantErr:   "invalid label value",
+		},
+		{
+			name:      "reserved generation key",
+			placement: &MaestroPlacementConfig{Annotations: map[string]string{"hyperfleet.io/generation": "1"}},
+			wantErr:   "managed by the adapter",
+		},
+		{
+			name:      "undefined template variable",
+			placement: &MaestroPlacementConfig{Labels: map[string]string{"region": "{{ .nope }}"}},
+			wantErr:   "undefined template variable \"nope\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTaskValidator(build(tt.placement))
+			_ = v.ValidateStructure()
+			err := v.ValidateSemantic()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateManifestOrdering(t *testing.T) {
+	build := func(ordering *ManifestOrderingConfig) *AdapterTaskConfig {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name: "testMW",
+			Transport: &TransportConfig{
+				Client:  TransportClientMaestro,
+				Maestro: &MaestroTransportConfig{TargetCluster: "cluster1", Ordering: ordering},
+			},
+			Manifest: map[string]interface{}{
+				"apiVersion": "work.open-cluster-management.io/v1",
+				"kind":       "ManifestWork",
+			},
+			Discovery: &DiscoveryConfig{ByName: "work"},
+		}}
+		return cfg
+	}
+
+	t.Run("default tiers", func(t *testing.T) {
+		v := newTaskValidator(build(&ManifestOrderingConfig{Split: true}))
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("custom tiers", func(t *testing.T) {
+		v := newTaskValidator(build(&ManifestOrderingConfig{Tiers: []ManifestTier{
+			{Name: "namespaces", Kinds: []string{"Namespace"}},
+			{Name: "operators", Kinds: []string{"CustomResourceDefinition", "Subscription"}},
+		}}))
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("tier without kinds", func(t *testing.T) {
+		v := newTaskValidator(build(&ManifestOrderingConfig{Tiers: []ManifestTier{{Name: "empty"}}}))
+		require.Error(t, v.ValidateStructure())
+	})
+
+	tests := []struct {
+		name    string
+		wantErr string
+		tiers   []ManifestTier
+	}{
+		{
+			name:    "invalid tier name",
+			tiers:   []ManifestTier{{Name: "Name_With_Underscores", Kinds: []string{"Namespace"}}},
+			wantErr: "ordering.tiers[0].name: invalid tier name",
+		},
+		{
+			name:    "reserved tier name",
+			tiers:   []ManifestTier{{Name: "workloads", Kinds: []string{"Deployment"}}},
+			wantErr: "is reserved",
+		},
+		{
+			name: "duplicate tier name",
+			tiers: []ManifestTier{
+				{Name: "first", Kinds: []string{"Namespace"}},
+				{Name: "first", Kinds: []string{"Role"}},
+			},
+			wantErr: "duplicate tier name \"first\"",
+		},
+		{
+			name: "kind in two tiers",
+			tiers: []ManifestTier{
+				{Name: "first", Kinds: []string{"Namespace"}},
+				{Name: "second", Kinds: []string{"Role", "Namespace"}},
+			},
+			wantErr: "ordering.tiers[1].kinds[1]: kind \"Namespace\" is already in tier \"first\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTaskValidator(build(&ManifestOrderingConfig{Tiers: tt.tiers}))
+			require.NoError(t, v.ValidateStructure())
+			err := v.ValidateSemantic()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateFeedbackRules(t *testing.T) {
+	build := func(rules []ManifestFeedbackRule) *AdapterTaskConfig {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{{Name: "clusterId", Source: StringSource("event.id")}}
+		cfg.Resources = []Resource{{
+			Name: "testMW",
+			Transport: &TransportConfig{
+				Client:  TransportClientMaestro,
+				Maestro: &MaestroTransportConfig{TargetCluster: "cluster1", FeedbackRules: rules},
+			},
+			Manifest: map[string]interface{}{
+				"apiVersion": "work.open-cluster-management.io/v1",
+				"kind":       "ManifestWork",
+			},
+			Discovery: &DiscoveryConfig{ByName: "work"},
+		}}
+		return cfg
+	}
+
+	t.Run("valid rules", func(t *testing.T) {
+		v := newTaskValidator(build([]ManifestFeedbackRule{
+			{Kind: "Deployment", Name: "app", Namespace: "{{ .clusterId }}", WellKnownStatus: true},
+			{Kind: "Namespace", JSONPaths: []FeedbackJSONPath{{Name: "phase", Path: ".status.phase"}}},
+		}))
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("rule without kind", func(t *testing.T) {
+		v := newTaskValidator(build([]ManifestFeedbackRule{{WellKnownStatus: true}}))
+		require.Error(t, v.ValidateStructure())
+	})
+
+	tests := []struct {
+		name    string
+		wantErr string
+		rules   []ManifestFeedbackRule
+	}{
+		{
+			name:    "rule without feedback",
+			rules:   []ManifestFeedbackRule{{Kind: "Deployment"}},
+			wantErr: "feedback_rules[0]: feedback rule must set well_known_status or json_paths",
+		},
+		{
+			name:    "undefined template variable",
+			rules:   []ManifestFeedbackRule{{Kind: "Deployment", Name: "{{ .unknown }}", WellKnownStatus: true}},
+			wantErr: "feedback_rules[0].name",
+		},
+		{
+			name: "duplicate json path name",
+			rules: []ManifestFeedbackRule{{Kind: "Namespace", JSONPaths: []FeedbackJSONPath{
+				{Name: "phase", Path: ".status.phase"},
+				{Name: "phase", Path: ".status.conditions"},
+			}}},
+			wantErr: "feedback_rules[0].json_paths[1].name: duplicate json path name \"phase\"",
+		},
+		{
+			name: "json path not relative",
+			rules: []ManifestFeedbackRule{{Kind: "Namespace", JSONPaths: []FeedbackJSONPath{
+				{Name: "phase", Path: "status.phase"},
+			}}},
+			wantErr: "feedback_rules[0].json_paths[0].path: json path \"status.phase\" must start with",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newTaskValidator(build(tt.rules))
+			require.NoError(t, v.ValidateStructure())
+			err := v.ValidateSemantic()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateFileReferencesManifestRef(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	// Create a test manifest file (ManifestWork content)
+	manifestDir := filepath.Join(tmpDir, "templates")
+	require.NoError(t, os.MkdirAll(manifestDir, 0o755))
+	manifestFile := filepath.Join(manifestDir, "manifestwork.yaml")
+	manifestContent := []byte("apiVersion: work.open-cluster-management.io/v1\nkind: ManifestWork")
+	require.NoError(t, os.WriteFile(manifestFile, manifestContent, 0o644))
+
+	tests := []struct {
+		name    string
+		config  *AdapterTaskConfig
+		errMsg  string
+		wantErr bool
+	}{
+		{
+			name: "valid manifest ref for maestro transport",
+			config: &AdapterTaskConfig{
+				Resources: []Resource{{
+					Name: "test",
+					Transport: &TransportConfig{
+						Client: TransportClientMaestro,
+						Maestro: &MaestroTransportConfig{
+							TargetCluster: "cluster1",
+						},
+					},
+					Manifest: map[string]interface{}{
+						"ref": "templates/manifestwork.yaml",
+					},
+					Discovery: &DiscoveryConfig{
+						BySelectors: &SelectorConfig{
+							LabelSelector: map[string]string{"app": "test"},
+						},
+					},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid manifest ref - file not found",
+			config: &AdapterTaskConfig{
+				Resources: []Resource{{
+					Name: "test",
+					Transport: &TransportConfig{
+						Client: TransportClientMaestro,
+						Maestro: &MaestroTransportConfig{
+							TargetCluster: "cluster1",
+						},
+					},
+					Manifest: map[string]interface{}{
+						"ref": "templates/nonexistent.yaml",
+					},
+					Discovery: &DiscoveryConfig{
+						BySelectors: &SelectorConfig{
+							LabelSelector: map[string]string{"app": "test"},
+						},
+					},
+				}},
+			},
+			wantErr: true,
+			errMsg:  "does not exist",
+		},
+		{
+			name: "inline manifest - no file reference validation needed",
+			config: &AdapterTaskConfig{
+				Resources: []Resource{{
+					Name: "test",
+					Transport: &TransportConfig{
+						Client: TransportClientMaestro,
+						Maestro: &MaestroTransportConfig{
+							TargetCluster: "cluster1",
+						},
+					},
+					Manifest: map[string]interface{}{
+						"apiVersion": "work.open-cluster-management.io/v1",
+						"kind":       "ManifestWork",
+					},
+					Discovery: &DiscoveryConfig{
+						BySelectors: &SelectorConfig{
+							LabelSelector: map[string]string{"app": "test"},
+						},
+					},
+				}},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validator := NewTaskConfigValidator(tt.config, tmpDir)
+			err := validator.ValidateFileReferences()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateLifecycleConfig(t *testing.T) {
+	// minResource satisfies required fields (manifest, discovery) so we can focus on lifecycle validation.
+	minDiscovery := &DiscoveryConfig{ByName: "my-resource"}
+	minManifest := map[string]interface{}{
+		"apiVersion": "v1", "kind": "ConfigMap",
+		"metadata": map[string]interface{}{"namespace": "default"},
+	}
+
+	withLifecycle := func(del *LifecycleDelete) *AdapterTaskConfig {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:      "myResource",
+			Discovery: minDiscovery,
+			Manifest:  minManifest,
+			Lifecycle: &ResourceLifecycle{Delete: del},
+		}}
+		return cfg
+	}
+
+	t.Run("valid lifecycle delete with when expression", func(t *testing.T) {
+		cfg := withLifecycle(&LifecycleDelete{
+			When: &LifecycleWhen{Expression: "deletedTime != null"},
+		})
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("lifecycle delete missing when block", func(t *testing.T) {
+		cfg := withLifecycle(&LifecycleDelete{
+			PropagationPolicy: "Background",
+		})
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle.delete.when.expression is required")
+	})
+
+	t.Run("lifecycle delete with empty when expression", func(t *testing.T) {
+		cfg := withLifecycle(&LifecycleDelete{
+			When: &LifecycleWhen{Expression: ""},
+		})
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle.delete.when.expression is required")
+	})
+
+	t.Run("lifecycle delete with invalid propagationPolicy", func(t *testing.T) {
+		cfg := withLifecycle(&LifecycleDelete{
+			PropagationPolicy: "Invalid",
+			When:              &LifecycleWhen{Expression: "deletedTime != null"},
+		})
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid propagationPolicy")
+	})
+
+	t.Run("lifecycle delete with invalid CEL expression", func(t *testing.T) {
+		cfg := withLifecycle(&LifecycleDelete{
+			When: &LifecycleWhen{Expression: "deletedTime != null &&"},
+		})
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CEL parse error")
+	})
+
+	t.Run("lifecycle delete with finalizer and teardown", func(t *testing.T) {
+		cfg := withLifecycle(&LifecycleDelete{
+			When:      &LifecycleWhen{Expression: "deletedTime != null"},
+			Finalizer: "hyperfleet.io/dns-cleanup",
+			Teardown: []ActionBase{{
+				Name:    "deregister",
+				APICall: &APICall{Method: "DELETE", URL: "/dns/{{ .clusterId }}"},
+			}},
+		})
+		cfg.Params = []Parameter{{Name: "clusterId", Source: StringSource("event.id")}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	for _, tt := range []struct {
+		del     *LifecycleDelete
+		name    string
+		wantErr string
+	}{
+		{
+			name:    "invalid finalizer",
+			del:     &LifecycleDelete{Finalizer: "not a finalizer"},
+			wantErr: `invalid finalizer "not a finalizer"`,
+		},
+		{
+			name:    "teardown without finalizer",
+			del:     &LifecycleDelete{Teardown: []ActionBase{{Name: "deregister", Log: &LogAction{Message: "bye"}}}},
+			wantErr: "teardown requires a finalizer",
+		},
+		{
+			name:    "empty teardown step",
+			del:     &LifecycleDelete{Finalizer: "hyperfleet.io/cleanup", Teardown: []ActionBase{{Name: "noop"}}},
+			wantErr: "resources[0].lifecycle.delete.teardown[0]",
+		},
+		{
+			name: "undefined template variable in teardown",
+			del: &LifecycleDelete{Finalizer: "hyperfleet.io/cleanup", Teardown: []ActionBase{{
+				Name: "deregister", APICall: &APICall{Method: "DELETE", URL: "/dns/{{ .unknown }}"},
+			}}},
+			wantErr: "resources[0].lifecycle.delete.teardown[0].api_call.url",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.del.When = &LifecycleWhen{Expression: "deletedTime != null"}
+			err := newTaskValidator(withLifecycle(tt.del)).ValidateSemantic()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("finalizer requires the kubernetes transport", func(t *testing.T) {
+		cfg := withLifecycle(&LifecycleDelete{
+			When:      &LifecycleWhen{Expression: "deletedTime != null"},
+			Finalizer: "hyperfleet.io/cleanup",
+		})
+		cfg.Resources[0].Transport = &TransportConfig{Client: TransportClientMaestro}
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "finalizer is only supported with the kubernetes transport")
+	})
+
+	t.Run("lifecycle delete missing discovery", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:     "myResource",
+			Manifest: minManifest,
+			// Discovery intentionally absent
+			Lifecycle: &ResourceLifecycle{Delete: &LifecycleDelete{
+				When: &LifecycleWhen{Expression: "is_deleting"},
+			}},
+		}}
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle.delete requires a discovery config")
+	})
+
+	t.Run("no lifecycle config is valid", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{Name: "myResource", Discovery: minDiscovery, Manifest: minManifest}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("valid lifecycle create with when expression", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:      "myResource",
+			Discovery: minDiscovery,
+			Manifest:  minManifest,
+			Lifecycle: &ResourceLifecycle{
+				Create: &LifecycleCreate{
+					When: &LifecycleWhen{Expression: "clusterPhase == 'Provisioning'"},
+				},
+			},
+		}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("lifecycle create without when is rejected", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:      "myResource",
+			Discovery: minDiscovery,
+			Manifest:  minManifest,
+			Lifecycle: &ResourceLifecycle{
+				Create: &LifecycleCreate{},
+			},
+		}}
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle.create.when.expression is required")
+	})
+
+	t.Run("lifecycle create with when but empty expression", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:      "myResource",
+			Discovery: minDiscovery,
+			Manifest:  minManifest,
+			Lifecycle: &ResourceLifecycle{
+				Create: &LifecycleCreate{
+					When: &LifecycleWhen{Expression: ""},
+				},
+			},
+		}}
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle.create.when.expression is required")
+	})
+
+	t.Run("lifecycle create with invalid CEL expression", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:      "myResource",
+			Discovery: minDiscovery,
+			Manifest:  minManifest,
+			Lifecycle: &ResourceLifecycle{
+				Create: &LifecycleCreate{
+					When: &LifecycleWhen{Expression: "invalid &&& syntax"},
+				},
+			},
+		}}
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CEL parse error")
+	})
+
+	t.Run("lifecycle with both create and delete", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:      "myResource",
+			Discovery: minDiscovery,
+			Manifest:  minManifest,
+			Lifecycle: &ResourceLifecycle{
+				Create: &LifecycleCreate{
+					When: &LifecycleWhen{Expression: "clusterPhase == 'Provisioning'"},
+				},
+				Delete: &LifecycleDelete{
+					When: &LifecycleWhen{Expression: "deletedTime != null"},
+				},
+			},
+		}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("lifecycle create missing discovery", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:     "myResource",
+			Manifest: minManifest,
+			// Discovery intentionally absent
+			Lifecycle: &ResourceLifecycle{Create: &LifecycleCreate{
+				When: &LifecycleWhen{Expression: "shouldCreate"},
+			}},
+		}}
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle.create requires a discovery config")
+	})
+
+	t.Run("lifecycle create and delete both missing discovery report both errors", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name:     "myResource",
+			Manifest: minManifest,
+			// Discovery intentionally absent
+			Lifecycle: &ResourceLifecycle{
+				Create: &LifecycleCreate{
+					When: &LifecycleWhen{Expression: "shouldCreate"},
+				},
+				Delete: &LifecycleDelete{
+					PropagationPolicy: "NotAValidPolicy",
+					When:              &LifecycleWhen{Expression: "shouldDelete"},
+				},
+			},
+		}}
+		err := newTaskValidator(cfg).ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "lifecycle.create requires a discovery config")
+		assert.Contains(t, err.Error(), "lifecycle.delete requires a discovery config")
+	})
+}
+
+func TestValidateParamsAPICallSource(t *testing.T) {
+	t.Run("api_call source passes validation", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "clusterId", Source: StringSource("event.id")},
+			{Name: "clusterData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"})},
+		}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("api_call source URL can reference param defined before it", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "clusterId", Source: StringSource("event.id")},
+			{Name: "clusterData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"})},
+			{Name: "nodePoolData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}/nodepools"})},
+		}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("api_call source URL cannot reference param defined after it", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			// nodePoolName is defined AFTER nodePoolData uses it (order violation)
+			{Name: "nodePoolData", Source: APICallSource(&APICall{Method: "GET", URL: "/nodepools/{{ .nodePoolName }}"})},
+			{Name: "nodePoolName", Source: StringSource("event.nodePool")},
+		}
+		v := newTaskValidator(cfg)
+		_ = v.ValidateStructure()
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "undefined template variable \"nodePoolName\"")
+	})
+
+	t.Run("expression source passes CEL validation", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "clusterId", Source: StringSource("event.id")},
+			{Name: "clusterData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"})},
+			{Name: "isActive", Source: ExpressionSource(`clusterData.status == "Active"`)},
+		}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("expression source with invalid CEL fails validation", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "bad", Source: ExpressionSource(`====invalid`)},
+		}
+		v := newTaskValidator(cfg)
+		_ = v.ValidateStructure()
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "CEL parse error")
+	})
+
+	t.Run("empty string source fails validation", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "bad", Source: StringSource("")},
+		}
+		v := newTaskValidator(cfg)
+		_ = v.ValidateStructure()
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "source is required")
+	})
+}
+
+func TestValidateParamsFileSource(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+
+	t.Run("file source passes validation", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "token", Source: FileSource(&FileSourceConfig{
+				Path: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			})},
+		}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("file source with explicit trim passes validation", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "token", Source: FileSource(&FileSourceConfig{
+				Path: "/var/run/secrets/token",
+				Trim: boolPtr(false),
+			})},
+		}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("file source with empty path rejected", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "token", Source: FileSource(&FileSourceConfig{Path: ""})},
+		}
+		v := newTaskValidator(cfg)
+		_ = v.ValidateStructure()
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "path is required for file source")
+	})
+
+	t.Run("file source mutual exclusivity with api_call", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		// Construct via YAML to hit UnmarshalYAML mutual exclusivity
+		yamlStr := `
+params:
+  - name: "bad"
+    source:
+      api_call:
+        method: "GET"
+        url: "/clusters/x"
+      file:
+        path: "/some/file"
+`
+		var taskCfg AdapterTaskConfig
+		err := yaml.Unmarshal([]byte(yamlStr), &taskCfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only one of")
+		_ = cfg // suppress unused
+	})
+
+	t.Run("file source mutual exclusivity with expression", func(t *testing.T) {
+		yamlStr := `
+params:
+  - name: "bad"
+    source:
+      expression: "1 + 1"
+      file:
+        path: "/some/file"
+`
+		var taskCfg AdapterTaskConfig
+		err := yaml.Unmarshal([]byte(yamlStr), &taskCfg)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "only one of")
+	})
+
+	t.Run("file source with relative path emits warning", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{
+			{Name: "token", Source: FileSource(&FileSourceConfig{
+				Path: "relative/path/token",
+			})},
+		}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+		require.Len(t, v.Warnings(), 1)
+		assert.Contains(t, v.Warnings()[0], "not absolute")
+	})
+}
+
+func TestValidatePreconditionAPICallForbidden(t *testing.T) {
+	t.Run("precondition with api_call produces deprecation warning not error", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Preconditions = []Precondition{{
+			ActionBase: ActionBase{
+				Name:    "fetchCluster",
+				APICall: &APICall{Method: "GET", URL: "/clusters/x"},
+			},
+		}}
+		v := newTaskValidator(cfg)
+		_ = v.ValidateStructure()
+		err := v.ValidateSemantic()
+		require.NoError(t, err)
+		warnings := v.Warnings()
+		require.Len(t, warnings, 1)
+		assert.Contains(t, warnings[0], "DEPRECATED")
+		assert.Contains(t, warnings[0], "fetchCluster")
+		assert.Contains(t, warnings[0], "source.api_call")
+	})
+
+	t.Run("precondition without api_call passes with no warnings", func(t *testing.T) {
+		cfg := baseTaskConfig()
+		cfg.Preconditions = []Precondition{{
+			ActionBase: ActionBase{Name: "check"},
+			Expression: "true",
+		}}
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+		assert.Empty(t, v.Warnings())
+	})
+}
+
+func TestAdapterConfigValidator_HyperfleetAuth(t *testing.T) {
+	baseAdapterConfig := func() *AdapterConfig {
+		return &AdapterConfig{
+			Adapter: AdapterInfo{Name: "test-adapter"},
+		}
+	}
+	newValidator := func(cfg *AdapterConfig) *AdapterConfigValidator {
+		return NewAdapterConfigValidator(cfg, "")
+	}
+
+	t.Run("nil auth is valid", func(t *testing.T) {
+		cfg := baseAdapterConfig()
+		require.NoError(t, newValidator(cfg).ValidateStructure())
+	})
+
+	t.Run("valid absolute token path", func(t *testing.T) {
+		cfg := baseAdapterConfig()
+		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{
+			TokenPath:     "/var/run/secrets/token",
+			TokenCacheTTL: 30,
+		}
+		require.NoError(t, newValidator(cfg).ValidateStructure())
+	})
+
+	t.Run("empty token_path is an error", func(t *testing.T) {
+		cfg := baseAdapterConfig()
+		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{TokenPath: ""}
+		err := newValidator(cfg).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token_path must be set")
+	})
+
+	t.Run("relative token_path is an error", func(t *testing.T) {
+		cfg := baseAdapterConfig()
+		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{TokenPath: "relative/path/token"}
+		err := newValidator(cfg).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be an absolute path")
+	})
+
+	t.Run("negative token_cache_ttl is an error", func(t *testing.T) {
+		cfg := baseAdapterConfig()
+		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{
+			TokenPath:     "/var/run/secrets/token",
+			TokenCacheTTL: -1,
+		}
+		err := newValidator(cfg).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "token_cache_ttl must not be negative")
+	})
+
+	t.Run("valid oauth2 client credentials", func(t *testing.T) {
+		cfg := baseAdapterConfig()
+		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{
+			OAuth2: &HyperfleetAPIOAuth2Config{
+				TokenURL:         "https://sso.example.com/token",
+				ClientID:         "adapter",
+				ClientSecretPath: "/var/run/secrets/oauth2/client-secret",
+			},
+		}
+		require.NoError(t, newValidator(cfg).ValidateStructure())
+	})
+
+	t.Run("valid oidc login", func(t *testing.T) {
+		cfg := baseAdapterConfig()
+		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{
+			OIDC: &HyperfleetAPIOIDCConfig{
+				Issuer:   "https://sso.example.com/realms/hyperfleet",
+				ClientID: "hyperfleet-cli",
+				Flow:     "pkce",
+			},
+		}
+		require.NoError(t, newValidator(cfg).ValidateStructure())
+	})
+
+	t.Run("oauth2 errors", func(t *testing.T) {
+		tests := []struct {
+			auth    *HyperfleetAPIAuthConfig
+			name    string
+			wantErr string
+		}{
+			{
+				name: "token_path and oauth2",
+				auth: &HyperfleetAPIAuthConfig{
+					TokenPath: "/var/run/secrets/token",
+					OAuth2: &HyperfleetAPIOAuth2Config{
+						TokenURL: "https://sso.example.com/token", ClientID: "adapter", ClientSecretPath: "/secret",
+					},
+				},
+				wantErr: "mutually exclusive",
+			},
+			{
+				name: "token_url without scheme",
+				auth: &HyperfleetAPIAuthConfig{OAuth2: &HyperfleetAPIOAuth2Config{
+					TokenURL: "sso.example.com/token", ClientID: "adapter", ClientSecretPath: "/secret",
+				}},
+				wantErr: "oauth2.token_url must be an http(s) URL",
+			},
+			{
+				name: "missing client_id",
+				auth: &HyperfleetAPIAuthConfig{OAuth2: &HyperfleetAPIOAuth2Config{
+					TokenURL: "https://sso.example.com/token", ClientSecretPath: "/secret",
+				}},
+				wantErr: "oauth2.client_id is required",
+			},
+			{
+				name: "relative client_secret_path",
+				auth: &HyperfleetAPIAuthConfig{OAuth2: &HyperfleetAPIOAuth2Config{
+					TokenURL: "https://sso.example.com/token", ClientID: "adapter", ClientSecretPath: "secret",
+				}},
+				wantErr: "oauth2.client_secret_path must be an absolute path",
+			},
+			{
+				name: "oidc and token_path",
+				auth: &HyperfleetAPIAuthConfig{
+					TokenPath: "/var/run/secrets/token",
+					OIDC:      &HyperfleetAPIOIDCConfig{Issuer: "https://sso.example.com/realms/hyperfleet", ClientID: "cli"},
+				},
+				wantErr: "oidc is mutually exclusive",
+			},
+			{
+				name:    "oidc without issuer",
+				auth:    &HyperfleetAPIAuthConfig{OIDC: &HyperfleetAPIOIDCConfig{ClientID: "cli"}},
+				wantErr: "oidc.issuer must be an http(s) URL",
+			},
+			{
+				name: "oidc unknown flow",
+				auth: &HyperfleetAPIAuthConfig{OIDC: &HyperfleetAPIOIDCConfig{
+					Issuer: "https://sso.example.com/realms/hyperfleet", ClientID: "cli", Flow: "password",
+				}},
+				wantErr: "oidc.flow must be device or pkce",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				cfg := baseAdapterConfig()
+				cfg.Clients.HyperfleetAPI.Auth = tt.auth
+				err := newValidator(cfg).ValidateStructure()
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestAdapterConfigValidator_Vault(t *testing.T) {
+	newConfig := func(vault *VaultClientConfig) *AdapterConfig {
+		return &AdapterConfig{
+			Adapter: AdapterInfo{Name: "test-adapter"},
+			Clients: ClientsConfig{Vault: vault},
+		}
+	}
+	validVault := func() *VaultClientConfig {
+		return &VaultClientConfig{Address: "https://vault.example.com:8200", TokenPath: "/vault/token"}
+	}
+
+	t.Run("valid vault config", func(t *testing.T) {
+		require.NoError(t, NewAdapterConfigValidator(newConfig(validVault()), "").ValidateStructure())
+	})
+
+	tests := []struct {
+		name    string
+		mutate  func(*VaultClientConfig)
+		wantErr string
+	}{
+		{"missing address", func(c *VaultClientConfig) { c.Address = "" }, "clients.vault.address must be set"},
+		{"relative address", func(c *VaultClientConfig) { c.Address = "vault:8200" }, "must be an absolute URL"},
+		{"missing token path", func(c *VaultClientConfig) { c.TokenPath = "" }, "clients.vault.token_path must be set"},
+		{"relative token path", func(c *VaultClientConfig) { c.TokenPath = "token" }, "must be an absolute path"},
+		{"invalid timeout", func(c *VaultClientConfig) { c.Timeout = Duration(-time.Second) }, "clients.vault.timeout"},
+		{"unknown kv version", func(c *VaultClientConfig) { c.KVVersion = 3 }, "clients.vault.kv_version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vault := validVault()
+			tt.mutate(vault)
+			err := NewAdapterConfigValidator(newConfig(vault), "").ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAdapterConfigValidator_BrokerSubscriptions(t *testing.T) {
+	newConfig := func(broker BrokerConfig) *AdapterConfig {
+		return &AdapterConfig{
+			Adapter: AdapterInfo{Name: "test-adapter"},
+			Clients: ClientsConfig{Broker: broker},
+		}
+	}
+	validSubscriptions := func() []BrokerSubscription {
+		return []BrokerSubscription{
+			{SubscriptionID: "clusters-sub", Topic: "clusters"},
+			{
+				Name:           "nodepools",
+				SubscriptionID: "nodepools-sub",
+				Topic:          "nodepools",
+				Filter:         `data.kind == "NodePool"`,
+				TaskConfig:     "/etc/adapter/nodepool-task-config.yaml",
+			},
+		}
+	}
+
+	t.Run("valid subscriptions", func(t *testing.T) {
+		broker := BrokerConfig{Subscriptions: validSubscriptions()}
+		require.NoError(t, NewAdapterConfigValidator(newConfig(broker), "").ValidateStructure())
+		assert.Equal(t, []string{"clusters", "nodepools"}, []string{
+			broker.EffectiveSubscriptions()[0].DisplayName(), broker.EffectiveSubscriptions()[1].DisplayName(),
+		})
+	})
+
+	t.Run("single subscription", func(t *testing.T) {
+		broker := BrokerConfig{SubscriptionID: "sub", Topic: "clusters"}
+		assert.Equal(t, []BrokerSubscription{{SubscriptionID: "sub", Topic: "clusters"}},
+			broker.EffectiveSubscriptions())
+	})
+
+	tests := []struct {
+		name    string
+		mutate  func(*BrokerConfig)
+		wantErr string
+	}{
+		{"topic with subscriptions", func(b *BrokerConfig) { b.Topic = "clusters" }, "mutually exclusive"},
+		{"missing topic", func(b *BrokerConfig) { b.Subscriptions[0].Topic = "" },
+			"clients.broker.subscriptions[0].topic is required"},
+		{"missing subscription id", func(b *BrokerConfig) { b.Subscriptions[1].SubscriptionID = "" },
+			"clients.broker.subscriptions[1].subscription_id is required"},
+		{"duplicate subscription id", func(b *BrokerConfig) { b.Subscriptions[1].SubscriptionID = "clusters-sub" },
+			"is already used"},
+		{"invalid filter", func(b *BrokerConfig) { b.Subscriptions[1].Filter = "data.kind ==" },
+			"clients.broker.subscriptions[1].filter: CEL parse error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := BrokerConfig{Subscriptions: validSubscriptions()}
+			tt.mutate(&broker)
+			err := NewAdapterConfigValidator(newConfig(broker), "").ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAdapterConfigValidator_EventFilter(t *testing.T) {
+	config := &AdapterConfig{
+		Adapter:     AdapterInfo{Name: "test-adapter"},
+		EventFilter: `type.startsWith("io.hyperfleet.cluster.") && extensions.?region.orValue("") != "test"`,
+	}
+	require.NoError(t, NewAdapterConfigValidator(config, "").ValidateStructure())
+
+	config.EventFilter = `type ==`
+	err := NewAdapterConfigValidator(config, "").ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "event_filter: CEL parse error")
+}
+
+func TestAdapterConfigValidator_EventRouting(t *testing.T) {
+	config := &AdapterConfig{
+		Adapter: AdapterInfo{Name: "test-adapter"},
+		EventRouting: EventRoutingConfig{
+			Types:    []string{"io.hyperfleet.cluster.*", "io.hyperfleet.nodepool.created"},
+			Subjects: []string{"clusters/*"},
+		},
+	}
+	require.NoError(t, NewAdapterConfigValidator(config, "").ValidateStructure())
+
+	config.EventRouting.Subjects = []string{"clusters/[a-"}
+	err := NewAdapterConfigValidator(config, "").ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `event_routing.subjects[0]: invalid pattern "clusters/[a-"`)
+
+	config.EventRouting.Subjects = nil
+	config.EventRouting.Types = []string{""}
+	err = NewAdapterConfigValidator(config, "").ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "event_routing.types[0] must not be empty")
+}
+
+func TestEventRoutingConfig_Matches(t *testing.T) {
+	routing := EventRoutingConfig{
+		Types:    []string{"io.hyperfleet.cluster.*", "io.hyperfleet.nodepool.created"},
+		Subjects: []string{"clusters/*", "nodepools/*"},
+	}
+	tests := []struct {
+		eventType string
+		subject   string
+		want      bool
+	}{
+		{"io.hyperfleet.cluster.updated", "clusters/c1", true},
+		{"io.hyperfleet.nodepool.created", "nodepools/np1", true},
+		{"io.hyperfleet.nodepool.deleted", "nodepools/np1", false},
+		{"io.hyperfleet.cluster.updated", "clusters/c1/nodepools/np1", false},
+		{"io.hyperfleet.cluster.updated", "", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, routing.Matches(tt.eventType, tt.subject), "%s %s", tt.eventType, tt.subject)
+	}
+	assert.True(t, EventRoutingConfig{Types: routing.Types}.Matches("io.hyperfleet.cluster.updated", ""),
+		"no subject patterns should match every subject")
+	assert.False(t, EventRoutingConfig{}.IsSet())
+}
+
+func TestAdapterConfigValidator_Kafka(t *testing.T) {
+	newConfig := func(mutate func(*BrokerConfig)) *AdapterConfig {
+		broker := BrokerConfig{
+			SubscriptionID: "my-adapter",
+			Topic:          "hyperfleet-clusters",
+			Kafka: &BrokerKafkaConfig{
+				Brokers: []string{"kafka-0.kafka:9093"},
+				TLS:     &utils.TLSConfig{Enabled: true, CAFile: "/etc/kafka/ca.crt"},
+				SASL: &kafkabroker.SASLConfig{
+					Mechanism: "scram-sha-512", Username: "adapter", PasswordPath: "/etc/kafka/password",
+				},
+			},
+		}
+		if mutate != nil {
+			mutate(&broker)
+		}
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, Clients: ClientsConfig{Broker: broker}}
+	}
+	require.NoError(t, NewAdapterConfigValidator(newConfig(nil), "").ValidateStructure())
+
+	tests := []struct {
+		name    string
+		mutate  func(*BrokerConfig)
+		wantErr string
+	}{
+		{"no brokers", func(b *BrokerConfig) { b.Kafka.Brokers = nil }, "clients.broker.kafka.brokers must be set"},
+		{"publish topic", func(b *BrokerConfig) { b.PublishTopic = "results" }, "not supported with kafka"},
+		{"start offset", func(b *BrokerConfig) { b.Kafka.StartOffset = "newest" }, "start_offset must be earliest or latest"},
+		{"relative CA file", func(b *BrokerConfig) { b.Kafka.TLS.CAFile = "ca.crt" }, "tls.ca_file must be an absolute path"},
+		{"cert without key", func(b *BrokerConfig) { b.Kafka.TLS.CertFile = "/etc/kafka/tls.crt" }, "must be set together"},
+		{"SASL mechanism", func(b *BrokerConfig) { b.Kafka.SASL.Mechanism = "gssapi" }, "sasl.mechanism must be plain"},
+		{"SASL username", func(b *BrokerConfig) { b.Kafka.SASL.Username = "" }, "sasl.username is required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewAdapterConfigValidator(newConfig(tt.mutate), "").ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAdapterConfigValidator_NATS(t *testing.T) {
+	newConfig := func(mutate func(*BrokerConfig)) *AdapterConfig {
+		broker := BrokerConfig{
+			SubscriptionID: "my-adapter",
+			Topic:          "hyperfleet.clusters",
+			Type:           BrokerTypeNATS,
+			NATS: &BrokerNATSConfig{
+				Servers:         []string{"nats://nats:4222"},
+				Stream:          "HYPERFLEET",
+				AckWait:         Duration(time.Minute),
+				MaxDeliver:      5,
+				CredentialsPath: "/etc/nats/adapter.creds",
+			},
+		}
+		if mutate != nil {
+			mutate(&broker)
+		}
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, Clients: ClientsConfig{Broker: broker}}
+	}
+	require.NoError(t, NewAdapterConfigValidator(newConfig(nil), "").ValidateStructure())
+
+	config := newConfig(func(b *BrokerConfig) { b.Type = "" })
+	require.NoError(t, NewAdapterConfigValidator(config, "").ValidateStructure())
+	assert.Equal(t, BrokerTypeNATS, config.Clients.Broker.EffectiveType(), "the configured backend should be used")
+	assert.Equal(t, BrokerTypeHyperfleet, BrokerConfig{}.EffectiveType())
+
+	tests := []struct {
+		name    string
+		mutate  func(*BrokerConfig)
+		wantErr string
+	}{
+		{"unknown type", func(b *BrokerConfig) { b.Type = "sqs" }, "type"},
+		{"type without backend", func(b *BrokerConfig) { b.NATS = nil }, "clients.broker.nats must be set"},
+		{"other type", func(b *BrokerConfig) { b.Type = BrokerTypeHyperfleet }, "does not use the configured"},
+		{"kafka and nats", func(b *BrokerConfig) {
+			b.Kafka = &BrokerKafkaConfig{Brokers: []string{"kafka:9092"}}
+		}, "mutually exclusive"},
+		{"no servers", func(b *BrokerConfig) { b.NATS.Servers = nil }, "clients.broker.nats.servers must be set"},
+		{"no stream", func(b *BrokerConfig) { b.NATS.Stream = "" }, "clients.broker.nats.stream must be set"},
+		{"dead letter topic", func(b *BrokerConfig) { b.DeadLetterTopic = "dlq" }, "not supported with nats"},
+		{"durable with a dot", func(b *BrokerConfig) { b.NATS.Durable = "my.adapter" }, "durable must not contain"},
+		{"shared durable", func(b *BrokerConfig) {
+			b.SubscriptionID, b.Topic, b.NATS.Durable = "", "", "adapter"
+			b.Subscriptions = []BrokerSubscription{
+				{SubscriptionID: "a", Topic: "hyperfleet.clusters"},
+				{SubscriptionID: "b", Topic: "hyperfleet.nodepools"},
+			}
+		}, "cannot be shared"},
+		{"negative max deliver", func(b *BrokerConfig) { b.NATS.MaxDeliver = -1 }, "max_deliver must not be negative"},
+		{"relative credentials", func(b *BrokerConfig) {
+			b.NATS.CredentialsPath = "adapter.creds"
+		}, "must be an absolute path"},
+		{"cert without key", func(b *BrokerConfig) {
+			b.NATS.TLS = &utils.TLSConfig{CertFile: "/etc/nats/tls.crt"}
+		}, "must be set together"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewAdapterConfigValidator(newConfig(tt.mutate), "").ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAdapterConfigValidator_HTTPReceiver(t *testing.T) {
+	newConfig := func(mutate func(*BrokerConfig)) *AdapterConfig {
+		broker := BrokerConfig{
+			SubscriptionID: "my-adapter",
+			Topic:          "clusters",
+			HTTP:           &BrokerHTTPConfig{Port: "8082", Path: "/events", TokenPath: "/etc/adapter/receiver-token"},
+		}
+		if mutate != nil {
+			mutate(&broker)
+		}
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, Clients: ClientsConfig{Broker: broker}}
+	}
+	config := newConfig(nil)
+	require.NoError(t, NewAdapterConfigValidator(config, "").ValidateStructure())
+	assert.Equal(t, BrokerTypeHTTP, config.Clients.Broker.EffectiveType())
+	insecure := newConfig(func(b *BrokerConfig) { b.HTTP.TokenPath, b.HTTP.Insecure = "", true })
+	require.NoError(t, NewAdapterConfigValidator(insecure, "").ValidateStructure())
+
+	tests := []struct {
+		name    string
+		mutate  func(*BrokerConfig)
+		wantErr string
+	}{
+		{"type without backend", func(b *BrokerConfig) {
+			b.Type, b.HTTP = BrokerTypeHTTP, nil
+		}, "clients.broker.http must be set"},
+		{"two backends", func(b *BrokerConfig) { b.NATS = &BrokerNATSConfig{} }, "nats and http are mutually exclusive"},
+		{"port", func(b *BrokerConfig) { b.HTTP.Port = "web" }, "port must be a port number"},
+		{"path", func(b *BrokerConfig) { b.HTTP.Path = "events" }, "path must start with /"},
+		{"relative token", func(b *BrokerConfig) { b.HTTP.TokenPath = "auth-token" }, "token_path must be an absolute path"},
+		{"no token", func(b *BrokerConfig) { b.HTTP.TokenPath = "" }, "token_path is required; set insecure: true"},
+		{"publish topic", func(b *BrokerConfig) { b.PublishTopic = "results" }, "not supported with http"},
+		{"topic with a slash", func(b *BrokerConfig) { b.Topic = "hyperfleet/clusters" }, "must not contain /"},
+		{"topic subscribed twice", func(b *BrokerConfig) {
+			b.SubscriptionID, b.Topic = "", ""
+			b.Subscriptions = []BrokerSubscription{
+				{SubscriptionID: "a", Topic: "clusters"},
+				{SubscriptionID: "b", Topic: "clusters", Filter: `type.endsWith(".created")`},
+			}
+		}, "subscribed more than once"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewAdapterConfigValidator(newConfig(tt.mutate), "").ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAdapterConfigValidator_Readiness(t *testing.T) {
+	config := &AdapterConfig{
+		Adapter:   AdapterInfo{Name: "test-adapter"},
+		Readiness: ReadinessConfig{Checks: []string{ReadinessCheckBroker, ReadinessCheckAPI}},
+	}
+	require.NoError(t, NewAdapterConfigValidator(config, "").ValidateStructure())
+	assert.Equal(t, []string{ReadinessCheckBroker}, ReadinessConfig{}.EffectiveChecks())
+
+	config.Readiness.Checks = []string{"database"}
+	err := NewAdapterConfigValidator(config, "").ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "readiness")
+}
+
+func TestAdapterConfigValidator_GitOps(t *testing.T) {
+	newConfig := func(gitops *GitOpsClientConfig) *AdapterConfig {
+		return &AdapterConfig{
+			Adapter: AdapterInfo{Name: "test-adapter"},
+			Clients: ClientsConfig{GitOps: gitops},
+		}
+	}
+	validGitOps := func() *GitOpsClientConfig {
+		return &GitOpsClientConfig{
+			Repository:  "https://github.com/org/fleet.git",
+			TokenPath:   "/etc/gitops/token",
+			PullRequest: &GitOpsPullRequestConfig{Repository: "org/fleet"},
+		}
+	}
+
+	t.Run("valid gitops config", func(t *testing.T) {
+		require.NoError(t, NewAdapterConfigValidator(newConfig(validGitOps()), "").ValidateStructure())
+	})
+
+	tests := []struct {
+		name    string
+		mutate  func(*GitOpsClientConfig)
+		wantErr string
+	}{
+		{"missing repository", func(c *GitOpsClientConfig) { c.Repository = "" }, "clients.gitops.repository must be set"},
+		{"relative token path", func(c *GitOpsClientConfig) { c.TokenPath = "token" }, "must be an absolute path"},
+		{"pull request repository without owner", func(c *GitOpsClientConfig) { c.PullRequest.Repository = "fleet" },
+			"clients.gitops.pull_request.repository must be owner/name"},
+		{"relative api url", func(c *GitOpsClientConfig) { c.PullRequest.APIURL = "api.github.com" },
+			"clients.gitops.pull_request.api_url must be an absolute URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gitops := validGitOps()
+			tt.mutate(gitops)
+			err := NewAdapterConfigValidator(newConfig(gitops), "").ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestAdapterConfigValidator_Notifications(t *testing.T) {
+	withTargets := func(targets ...NotificationTarget) *AdapterConfig {
+		return &AdapterConfig{
+			Adapter:       AdapterInfo{Name: "test-adapter"},
+			Notifications: NotificationsConfig{Targets: targets},
+		}
+	}
+	slack := NotificationTarget{Name: "ops", Type: NotificationTargetSlack, URL: "https://hooks.example.com/x"}
+
+	t.Run("valid targets", func(t *testing.T) {
+		hook := NotificationTarget{Name: "hook", Type: NotificationTargetWebhook, URL: "https://example.com",
+			Message: "{{ .resource_id }} failed"}
+		require.NoError(t, NewAdapterConfigValidator(withTargets(slack, hook), "").ValidateStructure())
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		bad := slack
+		bad.Type = "email"
+		require.Error(t, NewAdapterConfigValidator(withTargets(bad), "").ValidateStructure())
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := NewAdapterConfigValidator(withTargets(slack, slack), "").ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `notifications.targets[1].name: "ops" is already defined`)
+	})
+
+	t.Run("invalid message template", func(t *testing.T) {
+		bad := slack
+		bad.Message = "{{ .resource_id "
+		err := NewAdapterConfigValidator(withTargets(bad), "").ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "notifications.targets[0].message: invalid template")
+	})
+}
+
+func TestAdapterConfigValidator_Deduplication(t *testing.T) {
+	withDedup := func(dedup DeduplicationConfig) *AdapterConfig {
+		dedup.Enabled = true
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, Deduplication: dedup}
+	}
+
+	t.Run("valid stores", func(t *testing.T) {
+		for _, dedup := range []DeduplicationConfig{
+			{},
+			{Store: DeduplicationStoreMemory, TTL: Duration(time.Hour), MaxEntries: 100},
+			{Store: DeduplicationStoreRedis, Redis: &RedisStoreConfig{Address: "redis:6379"}},
+			{Store: DeduplicationStoreConfigMap},
+		} {
+			require.NoError(t, NewAdapterConfigValidator(withDedup(dedup), "").ValidateStructure())
+		}
+	})
+
+	t.Run("unknown store", func(t *testing.T) {
+		err := NewAdapterConfigValidator(withDedup(DeduplicationConfig{Store: "etcd"}), "").ValidateStructure()
+		require.Error(t, err)
+	})
+
+	t.Run("redis without address", func(t *testing.T) {
+		err := NewAdapterConfigValidator(withDedup(DeduplicationConfig{Store: DeduplicationStoreRedis}), "").
+			ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "deduplication.redis.address must be set")
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		err := NewAdapterConfigValidator(withDedup(DeduplicationConfig{TTL: Duration(-time.Second)}), "").ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "deduplication.ttl")
+	})
+}
+
+func TestAdapterConfigValidator_ExecutionRecording(t *testing.T) {
+	withRecording := func(recording ExecutionRecordingConfig) *AdapterConfig {
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, ExecutionRecording: recording}
+	}
+
+	require.NoError(t, NewAdapterConfigValidator(withRecording(ExecutionRecordingConfig{}), "").ValidateStructure())
+	require.NoError(t, NewAdapterConfigValidator(withRecording(ExecutionRecordingConfig{
+		Enabled: true, Dir: "/var/lib/adapter/recordings", MaxRecordings: 10,
+	}), "").ValidateStructure())
+
+	err := NewAdapterConfigValidator(withRecording(ExecutionRecordingConfig{Enabled: true}), "").ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "execution_recording.dir must be set")
+
+	err = NewAdapterConfigValidator(withRecording(ExecutionRecordingConfig{MaxRecordings: -1}), "").ValidateStructure()
+	require.Error(t, err)
+}
+
+func TestAdapterConfigValidator_SLI(t *testing.T) {
+	withSLI := func(sli SLIConfig) *AdapterConfig {
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, SLI: sli}
+	}
+
+	require.NoError(t, NewAdapterConfigValidator(withSLI(SLIConfig{
+		Enabled: true, Target: Duration(5 * time.Minute), Window: Duration(time.Hour),
+	}), "").ValidateStructure())
+
+	err := NewAdapterConfigValidator(withSLI(SLIConfig{Target: Duration(-time.Minute)}), "").ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "sli.target and sli.window must not be negative")
+
+	err = NewAdapterConfigValidator(withSLI(SLIConfig{MaxResources: -1}), "").ValidateStructure()
+	require.Error(t, err)
+}
+
+func TestAdapterConfigValidator_LogSampling(t *testing.T) {
+	withSampling := func(sampling *LogSamplingConfig) *AdapterConfig {
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, Log: LogConfig{Sampling: sampling}}
+	}
+
+	require.NoError(t, NewAdapterConfigValidator(withSampling(&LogSamplingConfig{}), "").ValidateStructure())
+	require.NoError(t, NewAdapterConfigValidator(withSampling(&LogSamplingConfig{
+		First: 5, Thereafter: 1000, Window: Duration(10 * time.Minute),
+	}), "").ValidateStructure())
+
+	err := NewAdapterConfigValidator(withSampling(&LogSamplingConfig{Thereafter: -1}), "").ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"log.sampling.first, log.sampling.thereafter and log.sampling.window must not be negative")
+}
+
+func TestAdapterConfigValidator_AckMode(t *testing.T) {
+	withAckMode := func(mode string) *AdapterConfig {
+		return &AdapterConfig{
+			Adapter: AdapterInfo{Name: "test-adapter"},
+			Clients: ClientsConfig{Broker: BrokerConfig{AckMode: mode}},
+		}
+	}
+
+	require.NoError(t, NewAdapterConfigValidator(withAckMode(""), "").ValidateStructure())
+	require.NoError(t, NewAdapterConfigValidator(withAckMode(AckModeBeforeExecute), "").ValidateStructure())
+	require.Error(t, NewAdapterConfigValidator(withAckMode("never"), "").ValidateStructure())
+}
+
+func TestBrokerConfig_EffectiveAckMode(t *testing.T) {
+	assert.Equal(t, AckModeAfterExecute, BrokerConfig{}.EffectiveAckMode())
+	assert.Equal(t, AckModeAfterExecute, BrokerConfig{Concurrency: 4}.EffectiveAckMode(),
+		"a worker pool keeps at-least-once delivery unless configured otherwise")
+	assert.Equal(t, AckModeBeforeExecute, BrokerConfig{Concurrency: 4, AckMode: AckModeBeforeExecute}.EffectiveAckMode())
+}
+
+func TestAdapterConfigValidator_Admin(t *testing.T) {
+	withAdmin := func(adminCfg AdminConfig) *AdapterConfig {
+		adminCfg.Enabled = true
+		return &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}, Admin: adminCfg}
+	}
+	oncall := admin.Principal{Name: "oncall", Role: admin.RoleViewer, TokenPath: "/etc/admin/oncall-token"}
+	bot := admin.Principal{Name: "sre-bot", Role: admin.RoleOperator, CommonName: "sre-bot"}
+	mtls := &admin.TLSConfig{
+		CertFile: "/etc/admin/tls.crt", KeyFile: "/etc/admin/tls.key", ClientCAFile: "/etc/admin/ca.crt",
+	}
+
+	tests := []struct {
+		name     string
+		errorMsg string
+		admin    AdminConfig
+	}{
+		{
+			name:  "token and mTLS principals",
+			admin: AdminConfig{TLS: mtls, Principals: []admin.Principal{oncall, bot}},
+		},
+		{
+			name:     "no principals",
+			admin:    AdminConfig{},
+			errorMsg: "admin.principals must not be empty",
+		},
+		{
+			name:     "unknown role",
+			admin:    AdminConfig{Principals: []admin.Principal{{Name: "x", Role: "root", TokenPath: "/t"}}},
+			errorMsg: `admin.principals[0].role "root" is invalid`,
+		},
+		{
+			name:     "duplicate name",
+			admin:    AdminConfig{Principals: []admin.Principal{oncall, oncall}},
+			errorMsg: `admin.principals[1].name: "oncall" is already defined`,
+		},
+		{
+			name:     "no credential",
+			admin:    AdminConfig{Principals: []admin.Principal{{Name: "x", Role: admin.RoleViewer}}},
+			errorMsg: "exactly one of token_path and common_name",
+		},
+		{
+			name:     "relative token path",
+			admin:    AdminConfig{Principals: []admin.Principal{{Name: "x", Role: admin.RoleViewer, TokenPath: "token"}}},
+			errorMsg: "token_path must be an absolute path",
+		},
+		{
+			name:     "common name without client CA",
+			admin:    AdminConfig{Principals: []admin.Principal{bot}},
+			errorMsg: "common_name requires admin.tls.client_ca_file",
+		},
+		{
+			name: "TLS without key",
+			admin: AdminConfig{
+				TLS:        &admin.TLSConfig{CertFile: "/etc/admin/tls.crt"},
+				Principals: []admin.Principal{oncall},
+			},
+			errorMsg: "admin.tls.cert_file and admin.tls.key_file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewAdapterConfigValidator(withAdmin(tt.admin), "").ValidateStructure()
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+
+	disabled := &AdapterConfig{Adapter: AdapterInfo{Name: "test-adapter"}}
+	require.NoError(t, NewAdapterConfigValidator(disabled, "").ValidateStructure(), "disabled admin needs no principals")
+}
+
+func TestAdapterConfigValidator_Schedules(t *testing.T) {
+	withSchedules := func(schedules ...ScheduleConfig) *AdapterConfig {
+		return &AdapterConfig{
+			Adapter:   AdapterInfo{Name: "test-adapter"},
+			Schedules: schedules,
+		}
+	}
+	nightly := ScheduleConfig{Name: "nightly", Cron: "0 2 * * *", Timezone: "Europe/Berlin"}
+
+	t.Run("valid schedules", func(t *testing.T) {
+		hourly := ScheduleConfig{Name: "hourly", Cron: "@hourly"}
+		hourly.Event.Data = map[string]interface{}{"id": "{{ .schedule }}"}
+		require.NoError(t, NewAdapterConfigValidator(withSchedules(nightly, hourly), "").ValidateStructure())
+	})
+
+	t.Run("missing cron", func(t *testing.T) {
+		bad := nightly
+		bad.Cron = ""
+		require.Error(t, NewAdapterConfigValidator(withSchedules(bad), "").ValidateStructure())
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		err := NewAdapterConfigValidator(withSchedules(nightly, nightly), "").ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `schedules[1].name: "nightly" is already defined`)
+	})
+
+	t.Run("invalid cron", func(t *testing.T) {
+		bad := nightly
+		bad.Cron = "0 25 * * *"
+		err := NewAdapterConfigValidator(withSchedules(bad), "").ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "schedules[0].cron: hour value 25 out of range")
+	})
+
+	t.Run("invalid timezone", func(t *testing.T) {
+		bad := nightly
+		bad.Timezone = "Mars/Olympus"
+		err := NewAdapterConfigValidator(withSchedules(bad), "").ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "schedules[0].timezone")
+	})
+
+	t.Run("invalid event template", func(t *testing.T) {
+		bad := nightly
+		bad.Event.Data = map[string]interface{}{"id": "{{ .cluster_id }}"}
+		err := NewAdapterConfigValidator(withSchedules(bad), "").ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "schedules[0].event")
+	})
+}
+
+func TestValidateDiscoveryFallback(t *testing.T) {
+	withDiscovery := func(d *DiscoveryConfig) *AdapterTaskConfig {
+		cfg := baseTaskConfig()
+		cfg.Resources = []Resource{{
+			Name: "testResource",
+			Manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "test"},
+			},
+			Discovery: d,
 		}}
-		err := newTaskValidator(cfg).ValidateSemantic()
+		return cfg
+	}
+	selectors := &SelectorConfig{LabelSelector: map[string]string{"app": "test"}}
+
+	tests := []struct {
+		name      string
+		discovery *DiscoveryConfig
+		errorMsg  string
+	}{
+		{"both methods with default order", &DiscoveryConfig{ByName: "test", BySelectors: selectors}, ""},
+		{
+			"explicit order",
+			&DiscoveryConfig{ByName: "test", BySelectors: selectors, Order: []string{"by_selectors", "by_name"}},
+			"",
+		},
+		{"fail_on_multi", &DiscoveryConfig{BySelectors: selectors, MatchPolicy: "fail_on_multi"}, ""},
+		{"unknown match policy", &DiscoveryConfig{ByName: "test", MatchPolicy: "oldest"}, "is invalid"},
+		{"unknown method in order", &DiscoveryConfig{ByName: "test", Order: []string{"by_uid"}}, "is invalid"},
+		{"unconfigured method in order", &DiscoveryConfig{ByName: "test", Order: []string{"by_selectors"}},
+			`resources[0].discovery.order: "by_selectors" is not configured`},
+		{"duplicate method in order", &DiscoveryConfig{ByName: "test", Order: []string{"by_name", "by_name"}},
+			"listed more than once"},
+		{"require_all", &DiscoveryConfig{ByName: "test", BySelectors: selectors, RequireAll: true}, ""},
+		{"require_all without selectors", &DiscoveryConfig{ByName: "test", RequireAll: true},
+			"resources[0].discovery.require_all: requires both by_name and by_selectors"},
+		{
+			"require_all with order",
+			&DiscoveryConfig{ByName: "test", BySelectors: selectors, RequireAll: true, Order: []string{"by_name"}},
+			"order has no effect when require_all is set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTaskValidator(withDiscovery(tt.discovery)).ValidateStructure()
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+
+	t.Run("empty by_selectors is not configured", func(t *testing.T) {
+		// Methods drops an empty by_selectors from the lookup order, so the order check must too
+		empty := &DiscoveryConfig{ByName: "test", BySelectors: &SelectorConfig{}, Order: []string{"by_selectors"}}
+		err := newTaskValidator(withDiscovery(empty)).validateDiscoveryOrder()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `resources[0].discovery.order: "by_selectors" is not configured`)
+	})
+}
+
+func TestDiscoveryConfigMethods(t *testing.T) {
+	selectors := &SelectorConfig{LabelSelector: map[string]string{"app": "test"}}
+	assert.Equal(t, []string{DiscoveryByName, DiscoveryBySelectors},
+		(&DiscoveryConfig{ByName: "x", BySelectors: selectors}).Methods())
+	assert.Equal(t, []string{DiscoveryBySelectors, DiscoveryByName},
+		(&DiscoveryConfig{ByName: "x", BySelectors: selectors, Order: []string{DiscoveryBySelectors}}).Methods())
+	assert.Equal(t, []string{DiscoveryBySelectors}, (&DiscoveryConfig{BySelectors: selectors}).Methods())
+	assert.Equal(t, []string{DiscoveryByName},
+		(&DiscoveryConfig{ByName: "x", BySelectors: &SelectorConfig{}, Order: []string{DiscoveryBySelectors}}).Methods())
+	assert.Nil(t, (*DiscoveryConfig)(nil).Methods())
+}
+
+func TestValidateNames(t *testing.T) {
+	param := func(name string) Parameter {
+		return Parameter{Name: name, Source: StringSource("event.id")}
+	}
+	precondition := func(name string, captures ...string) Precondition {
+		p := Precondition{ActionBase: ActionBase{Name: name}, Expression: "true"}
+		for _, c := range captures {
+			p.Capture = append(p.Capture, CaptureField{Name: c, FieldExpressionDef: FieldExpressionDef{Field: "x"}})
+		}
+		return p
+	}
+	apiCallPrecondition := func(name string, captures ...string) Precondition {
+		p := precondition(name, captures...)
+		p.APICall = &APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"}
+		return p
+	}
+	resource := func(name string, nested ...string) Resource {
+		r := Resource{
+			Name:      name,
+			Manifest:  map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+			Discovery: &DiscoveryConfig{ByName: name},
+		}
+		for _, n := range nested {
+			r.NestedDiscoveries = append(r.NestedDiscoveries,
+				NestedDiscovery{Name: n, Discovery: &DiscoveryConfig{ByName: n}})
+		}
+		return r
+	}
+	wait := func(name string, work *ManifestWorkWait) WaitStep {
+		return WaitStep{Name: name, Resource: "work", Condition: "true", ManifestWork: work}
+	}
+
+	tests := []struct {
+		name     string
+		config   *AdapterTaskConfig
+		errorMsg string
+	}{
+		{
+			name: "unique names",
+			config: &AdapterTaskConfig{
+				Params:        []Parameter{param("clusterId")},
+				Preconditions: []Precondition{precondition("check", "clusterName")},
+				Resources:     []Resource{resource("work", "ns")},
+			},
+		},
+		{
+			name: "param shadowed by capture",
+			config: &AdapterTaskConfig{
+				Params:        []Parameter{param("clusterId")},
+				Preconditions: []Precondition{precondition("check", "clusterId")},
+			},
+			errorMsg: `preconditions[0].capture[0].name: "clusterId" is already defined at params[0].name`,
+		},
+		{
+			name: "payload shadows param",
+			config: &AdapterTaskConfig{
+				Params: []Parameter{param("status")},
+				Post:   &PostConfig{Payloads: []Payload{{Name: "status", Build: map[string]interface{}{"a": "b"}}}},
+			},
+			errorMsg: `post.payloads[0].name: "status" is already defined at params[0].name`,
+		},
+		{
+			name:     "reserved CEL root",
+			config:   &AdapterTaskConfig{Params: []Parameter{param("clusterId"), param("metadata")}},
+			errorMsg: `params[1].name: "metadata" is a reserved name`,
+		},
+		{
+			name:     "reserved CEL extension namespace",
+			config:   &AdapterTaskConfig{Params: []Parameter{param("base64")}},
+			errorMsg: `params[0].name: "base64" is a reserved name`,
+		},
+		{
+			name:     "invalid CEL identifier",
+			config:   &AdapterTaskConfig{Params: []Parameter{param("cluster-id")}},
+			errorMsg: `params[0].name: "cluster-id" is not a valid CEL identifier`,
+		},
+		{
+			name:     "duplicate precondition",
+			config:   &AdapterTaskConfig{Preconditions: []Precondition{apiCallPrecondition("check"), apiCallPrecondition("check")}},
+			errorMsg: `preconditions[1].name: "check" is already defined at preconditions[0].name`,
+		},
+		{
+			name: "precondition shadows param",
+			config: &AdapterTaskConfig{
+				Params:        []Parameter{param("clusterStatus")},
+				Preconditions: []Precondition{apiCallPrecondition("clusterStatus")},
+			},
+			errorMsg: `preconditions[0].name: "clusterStatus" is already defined at params[0].name`,
+		},
+		{
+			name: "condition-only precondition shares param name",
+			config: &AdapterTaskConfig{
+				Params:        []Parameter{param("clusterStatus")},
+				Preconditions: []Precondition{precondition("clusterStatus")},
+			},
+		},
+		{
+			name: "capture shadows precondition",
+			config: &AdapterTaskConfig{Preconditions: []Precondition{
+				apiCallPrecondition("check"), precondition("other", "check"),
+			}},
+			errorMsg: `preconditions[1].capture[0].name: "check" is already defined at preconditions[0].name`,
+		},
+		{
+			name:     "reserved precondition name",
+			config:   &AdapterTaskConfig{Preconditions: []Precondition{apiCallPrecondition("resources")}},
+			errorMsg: `preconditions[0].name: "resources" is a reserved name`,
+		},
+		{
+			name: "manifest work wait shadows capture",
+			config: &AdapterTaskConfig{
+				Preconditions: []Precondition{precondition("check", "workStatus")},
+				Resources:     []Resource{resource("work")},
+				Wait:          []WaitStep{wait("workStatus", &ManifestWorkWait{})},
+			},
+			errorMsg: `wait[0].name: "workStatus" is already defined at preconditions[0].capture[0].name`,
+		},
+		{
+			name: "reserved manifest work wait name",
+			config: &AdapterTaskConfig{
+				Resources: []Resource{resource("work")},
+				Wait:      []WaitStep{wait("adapter", &ManifestWorkWait{})},
+			},
+			errorMsg: `wait[0].name: "adapter" is a reserved name`,
+		},
+		{
+			name: "resource wait shares capture name",
+			config: &AdapterTaskConfig{
+				Preconditions: []Precondition{precondition("check", "workStatus")},
+				Resources:     []Resource{resource("work")},
+				Wait:          []WaitStep{wait("workStatus", nil)},
+			},
+		},
+		{
+			name:     "nested discovery collides with resource",
+			config:   &AdapterTaskConfig{Resources: []Resource{resource("work", "ns"), resource("ns")}},
+			errorMsg: `resources[0].nested_discoveries[0].name: "ns" is already defined at resources[1].name`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTaskValidator(tt.config).ValidateStructure()
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+func TestValidateDependsOn(t *testing.T) {
+	resource := func(name string, dependsOn ...string) Resource {
+		return Resource{
+			Name:      name,
+			Manifest:  map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+			Discovery: &DiscoveryConfig{ByName: name},
+			DependsOn: dependsOn,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		errorMsg  string
+		resources []Resource
+	}{
+		{
+			name:      "depends on earlier resources",
+			resources: []Resource{resource("ns"), resource("sa"), resource("job", "ns", "sa")},
+		},
+		{
+			name:      "depends on later resource",
+			resources: []Resource{resource("job", "ns"), resource("ns")},
+			errorMsg:  `resources[0].depends_on[0]: "ns" is not a resource declared before "job"`,
+		},
+		{
+			name:      "depends on itself",
+			resources: []Resource{resource("job", "job")},
+			errorMsg:  `resources[0].depends_on[0]: resource "job" cannot depend on itself`,
+		},
+		{
+			name:      "duplicate dependency",
+			resources: []Resource{resource("ns"), resource("job", "ns", "ns")},
+			errorMsg:  `resources[1].depends_on[1]: "ns" is listed more than once`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTaskValidator(&AdapterTaskConfig{Resources: tt.resources}).ValidateStructure()
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+func TestDependencies(t *testing.T) {
+	resources := []Resource{
+		{Name: "a"},
+		{Name: "b", Parallel: true},
+		{Name: "c", DependsOn: []string{"a"}},
+		{Name: "d"},
+	}
+	assert.Empty(t, Dependencies(resources, 0))
+	assert.Empty(t, Dependencies(resources, 1))
+	assert.Equal(t, []int{0}, Dependencies(resources, 2))
+	assert.Equal(t, []int{0, 1, 2}, Dependencies(resources, 3))
+}
+
+func TestDependencies_ForEachItems(t *testing.T) {
+	resources := []Resource{
+		{Name: ForEachItemName("region", 0)},
+		{Name: ForEachItemName("region", 1)},
+		{Name: "regional"},
+		{Name: "summary", Parallel: true, DependsOn: []string{"region"}},
+	}
+	assert.Equal(t, []int{0, 1}, Dependencies(resources, 3), "a for_each name should stand for all of its items")
+}
+
+func TestValidateForEach(t *testing.T) {
+	newConfig := func() *AdapterTaskConfig {
+		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{{Name: "regions", Source: StringSource("event.regions")}}
+		cfg.Preconditions = []Precondition{{
+			ActionBase: ActionBase{Name: "quotas", APICall: &APICall{
+				Method: "GET", URL: "/quotas/{{ .item }}?position={{ .index }}",
+			}},
+			ForEach: "regions",
+		}}
+		cfg.Resources = []Resource{{
+			Name: "regionConfig",
+			Manifest: map[string]interface{}{
+				"apiVersion": "v1", "kind": "ConfigMap",
+				"metadata": map[string]interface{}{"name": "cm-{{ .item }}", "namespace": "default"},
+			},
+			Discovery: &DiscoveryConfig{Namespace: "default", ByName: "cm-{{ .item }}"},
+			ForEach:   "regions.filter(r, r != '')",
+		}}
+		return cfg
+	}
+
+	t.Run("valid for_each steps", func(t *testing.T) {
+		v := newTaskValidator(newConfig())
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("item outside of a for_each step", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Resources[0].ForEach = ""
+		v := newTaskValidator(cfg)
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "item")
+	})
+
+	tests := []struct {
+		modify  func(cfg *AdapterTaskConfig)
+		name    string
+		wantErr string
+	}{
+		{
+			name: "precondition without api_call",
+			modify: func(cfg *AdapterTaskConfig) {
+				cfg.Preconditions[0].APICall = nil
+				cfg.Preconditions[0].Expression = "true"
+			},
+			wantErr: "for_each requires an api_call",
+		},
+		{
+			name: "precondition with captures",
+			modify: func(cfg *AdapterTaskConfig) {
+				cfg.Preconditions[0].Capture = []CaptureField{
+					{Name: "quota", FieldExpressionDef: FieldExpressionDef{Field: "quota"}},
+				}
+			},
+			wantErr: "capture is not supported with for_each",
+		},
+		{
+			name: "resource with nested discoveries",
+			modify: func(cfg *AdapterTaskConfig) {
+				cfg.Resources[0].NestedDiscoveries = []NestedDiscovery{
+					{Name: "nested", Discovery: &DiscoveryConfig{ByName: "nested"}},
+				}
+			},
+			wantErr: "nested_discoveries are not supported with for_each",
+		},
+		{
+			name: "waited on",
+			modify: func(cfg *AdapterTaskConfig) {
+				cfg.Wait = []WaitStep{{Name: "ready", Resource: "regionConfig", Condition: "true"}}
+			},
+			wantErr: `resource "regionConfig" uses for_each and cannot be waited on`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := newConfig()
+			tt.modify(cfg)
+			err := newTaskValidator(cfg).ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("for_each syntax error", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Resources[0].ForEach = "regions.filter(r,"
+		v := newTaskValidator(cfg)
+		_ = v.ValidateStructure()
+		err := v.ValidateSemantic()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "lifecycle.delete requires a discovery config")
+		assert.Contains(t, err.Error(), "resources[0].for_each")
 	})
+}
 
-	t.Run("no lifecycle config is valid", func(t *testing.T) {
+func TestValidateRetryPolicies(t *testing.T) {
+	withRetry := func(policy *RetryPolicy) *AdapterTaskConfig {
+		return &AdapterTaskConfig{Resources: []Resource{{
+			Name:      "cm",
+			Manifest:  map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"},
+			Discovery: &DiscoveryConfig{ByName: "cm"},
+			Retry:     policy,
+		}}}
+	}
+
+	tests := []struct {
+		policy   *RetryPolicy
+		name     string
+		errorMsg string
+	}{
+		{name: "valid policy", policy: &RetryPolicy{
+			Attempts: 3, Backoff: "linear", RetryOn: []string{RetryOnTransport},
+			BaseDelay: Duration(500 * time.Millisecond), MaxDelay: Duration(10 * time.Second),
+		}},
+		{name: "missing attempts", policy: &RetryPolicy{BaseDelay: Duration(time.Second)}, errorMsg: "attempts"},
+		{name: "unknown retry_on", policy: &RetryPolicy{Attempts: 2, RetryOn: []string{"everything"}}, errorMsg: "retry_on"},
+		{name: "negative duration", policy: &RetryPolicy{Attempts: 2, BaseDelay: Duration(-time.Second)},
+			errorMsg: `resources[0].retry.base_delay: -1s is not a valid duration`},
+		{name: "base above max", policy: &RetryPolicy{
+			Attempts: 2, BaseDelay: Duration(time.Minute), MaxDelay: Duration(time.Second),
+		}, errorMsg: "base_delay must not exceed max_delay"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTaskValidator(withRetry(tt.policy)).ValidateStructure()
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorMsg)
+		})
+	}
+}
+
+func TestValidationErrorsOf(t *testing.T) {
+	assert.Nil(t, ValidationErrorsOf(nil))
+
+	errs := &ValidationErrors{}
+	errs.Add("resources[0].manifest", "undefined template variable \"x\"")
+	errs.Add("post.payloads[1]", "invalid CEL expression")
+	got := ValidationErrorsOf(fmt.Errorf("task config semantic validation failed: %w", errs))
+	assert.Equal(t, errs.Errors, got)
+
+	t.Run("plain error with path", func(t *testing.T) {
+		got := ValidationErrorsOf(fmt.Errorf(
+			"task config validation failed: resources[1].name: %q is already defined", "cm"))
+		require.Len(t, got, 1)
+		assert.Equal(t, "resources[1].name", got[0].Path)
+		assert.Equal(t, `"cm" is already defined`, got[0].Message)
+	})
+
+	t.Run("error list", func(t *testing.T) {
+		got := ValidationErrorsOf(fmt.Errorf("validation failed: file reference errors:\n  - %s\n  - %s",
+			`post.payloads[0].build_ref: referenced file "a.yaml" does not exist`,
+			`resources[2].manifest.ref: referenced file "b.yaml" does not exist`))
+		require.Len(t, got, 2)
+		assert.Equal(t, "post.payloads[0].build_ref", got[0].Path)
+		assert.Equal(t, "resources[2].manifest.ref", got[1].Path)
+		assert.Equal(t, `referenced file "b.yaml" does not exist`, got[1].Message)
+	})
+
+	t.Run("error without path", func(t *testing.T) {
+		err := fmt.Errorf("failed to read task config file %q: open /x.yaml: no such file or directory", "/x.yaml")
+		got := ValidationErrorsOf(err)
+		require.Len(t, got, 1)
+		assert.Empty(t, got[0].Path)
+		assert.Equal(t, err.Error(), got[0].Message)
+	})
+}
+
+func TestValidatePruneSteps(t *testing.T) {
+	newStep := func() PruneStep {
+		return PruneStep{
+			Name:          "staleConfigMaps",
+			APIVersion:    "v1",
+			Kind:          "ConfigMap",
+			Namespace:     "{{ .clusterNamespace }}",
+			LabelSelector: map[string]string{"hyperfleet.io/cluster-id": "{{ .clusterId }}"},
+		}
+	}
+	newConfig := func(steps ...PruneStep) *AdapterTaskConfig {
 		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{Name: "myResource", Discovery: minDiscovery, Manifest: minManifest}}
-		v := newTaskValidator(cfg)
+		cfg.Params = []Parameter{
+			{Name: "clusterNamespace", Source: StringSource("event.namespace")},
+			{Name: "clusterId", Source: StringSource("event.id")},
+		}
+		cfg.Prune = steps
+		return cfg
+	}
+
+	t.Run("valid prune step", func(t *testing.T) {
+		v := newTaskValidator(newConfig(newStep()))
 		require.NoError(t, v.ValidateStructure())
 		require.NoError(t, v.ValidateSemantic())
 	})
 
-	t.Run("valid lifecycle create with when expression", func(t *testing.T) {
+	t.Run("label selector is required", func(t *testing.T) {
+		step := newStep()
+		step.LabelSelector = nil
+		require.Error(t, newTaskValidator(newConfig(step)).ValidateStructure())
+	})
+
+	t.Run("unknown propagation policy", func(t *testing.T) {
+		step := newStep()
+		step.PropagationPolicy = "Cascade"
+		require.Error(t, newTaskValidator(newConfig(step)).ValidateStructure())
+	})
+
+	t.Run("name collides with a resource", func(t *testing.T) {
+		cfg := newConfig(newStep())
+		cfg.Resources = []Resource{{Name: "staleConfigMaps", Discovery: &DiscoveryConfig{ByName: "x"}}}
+		err := newTaskValidator(cfg).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"staleConfigMaps" is already defined at resources[0].name`)
+	})
+
+	t.Run("undefined template variable", func(t *testing.T) {
+		step := newStep()
+		step.Namespace = "{{ .unknown }}"
+		v := newTaskValidator(newConfig(step))
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "prune[0].namespace")
+	})
+}
+
+func TestValidateWaitSteps(t *testing.T) {
+	newConfig := func(steps ...WaitStep) *AdapterTaskConfig {
 		cfg := baseTaskConfig()
+		cfg.Params = []Parameter{{Name: "clusterId", Source: StringSource("event.id")}}
 		cfg.Resources = []Resource{{
-			Name:      "myResource",
-			Discovery: minDiscovery,
-			Manifest:  minManifest,
-			Lifecycle: &ResourceLifecycle{
-				Create: &LifecycleCreate{
-					When: &LifecycleWhen{Expression: "clusterPhase == 'Provisioning'"},
-				},
+			Name: "clusterConfigMap",
+			Manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "cluster-config", "namespace": "default"},
 			},
+			Discovery: &DiscoveryConfig{Namespace: "default", ByName: "cluster-config"},
 		}}
-		v := newTaskValidator(cfg)
+		cfg.Wait = steps
+		return cfg
+	}
+	newStep := func() WaitStep {
+		return WaitStep{
+			Name:      "configMapReady",
+			Resource:  "clusterConfigMap",
+			Condition: `data.ready == "true"`,
+			Timeout:   Duration(2 * time.Minute),
+			Interval:  Duration(10 * time.Second),
+		}
+	}
+
+	t.Run("valid resource wait", func(t *testing.T) {
+		v := newTaskValidator(newConfig(newStep()))
 		require.NoError(t, v.ValidateStructure())
 		require.NoError(t, v.ValidateSemantic())
 	})
 
-	t.Run("lifecycle create without when is rejected", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:      "myResource",
-			Discovery: minDiscovery,
-			Manifest:  minManifest,
-			Lifecycle: &ResourceLifecycle{
-				Create: &LifecycleCreate{},
-			},
-		}}
-		err := newTaskValidator(cfg).ValidateSemantic()
+	t.Run("valid api call wait", func(t *testing.T) {
+		step := newStep()
+		step.Resource = ""
+		step.APICall = &APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"}
+		v := newTaskValidator(newConfig(step))
+		require.NoError(t, v.ValidateStructure())
+		require.NoError(t, v.ValidateSemantic())
+	})
+
+	t.Run("resource or api call is required", func(t *testing.T) {
+		step := newStep()
+		step.Resource = ""
+		require.Error(t, newTaskValidator(newConfig(step)).ValidateStructure())
+	})
+
+	t.Run("resource and api call are exclusive", func(t *testing.T) {
+		step := newStep()
+		step.APICall = &APICall{Method: "GET", URL: "/clusters"}
+		require.Error(t, newTaskValidator(newConfig(step)).ValidateStructure())
+	})
+
+	t.Run("unknown resource", func(t *testing.T) {
+		step := newStep()
+		step.Resource = "missing"
+		err := newTaskValidator(newConfig(step)).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "lifecycle.create.when.expression is required")
+		assert.Contains(t, err.Error(), `"missing" is not a configured resource`)
 	})
 
-	t.Run("lifecycle create with when but empty expression", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:      "myResource",
-			Discovery: minDiscovery,
-			Manifest:  minManifest,
-			Lifecycle: &ResourceLifecycle{
-				Create: &LifecycleCreate{
-					When: &LifecycleWhen{Expression: ""},
-				},
-			},
-		}}
-		err := newTaskValidator(cfg).ValidateSemantic()
+	t.Run("negative durations", func(t *testing.T) {
+		step := newStep()
+		step.Timeout = Duration(-time.Minute)
+		step.Interval = Duration(-time.Second)
+		err := newTaskValidator(newConfig(step)).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "lifecycle.create.when.expression is required")
+		assert.Contains(t, err.Error(), "wait[0].timeout")
+		assert.Contains(t, err.Error(), "wait[0].interval")
 	})
 
-	t.Run("lifecycle create with invalid CEL expression", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:      "myResource",
-			Discovery: minDiscovery,
-			Manifest:  minManifest,
-			Lifecycle: &ResourceLifecycle{
-				Create: &LifecycleCreate{
-					When: &LifecycleWhen{Expression: "invalid &&& syntax"},
-				},
-			},
-		}}
-		err := newTaskValidator(cfg).ValidateSemantic()
+	t.Run("duplicate names", func(t *testing.T) {
+		err := newTaskValidator(newConfig(newStep(), newStep())).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "CEL parse error")
+		assert.Contains(t, err.Error(), `"configMapReady" is already defined at wait[0].name`)
 	})
 
-	t.Run("lifecycle with both create and delete", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:      "myResource",
-			Discovery: minDiscovery,
-			Manifest:  minManifest,
-			Lifecycle: &ResourceLifecycle{
-				Create: &LifecycleCreate{
-					When: &LifecycleWhen{Expression: "clusterPhase == 'Provisioning'"},
-				},
-				Delete: &LifecycleDelete{
-					When: &LifecycleWhen{Expression: "deletedTime != null"},
-				},
-			},
-		}}
+	t.Run("invalid condition", func(t *testing.T) {
+		step := newStep()
+		step.Condition = "data.ready =="
+		v := newTaskValidator(newConfig(step))
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "wait[0].condition")
+	})
+
+	t.Run("undefined template variable in api call", func(t *testing.T) {
+		step := newStep()
+		step.Resource = ""
+		step.APICall = &APICall{Method: "GET", URL: "/clusters/{{ .unknown }}"}
+		v := newTaskValidator(newConfig(step))
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "wait[0].api_call.url")
+	})
+
+	t.Run("manifest work wait", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.Resources[0].Transport = &TransportConfig{
+			Client:  TransportClientMaestro,
+			Maestro: &MaestroTransportConfig{TargetCluster: "cluster1"},
+		}
+		cfg.Resources[0].Manifest = map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+		}
+		step := newStep()
+		step.Name = "workStatus"
+		step.Condition = ""
+		step.ManifestWork = &ManifestWorkWait{}
+		cfg.Wait = []WaitStep{step}
 		v := newTaskValidator(cfg)
 		require.NoError(t, v.ValidateStructure())
 		require.NoError(t, v.ValidateSemantic())
-	})
 
-	t.Run("lifecycle create missing discovery", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:     "myResource",
-			Manifest: minManifest,
-			// Discovery intentionally absent
-			Lifecycle: &ResourceLifecycle{Create: &LifecycleCreate{
-				When: &LifecycleWhen{Expression: "shouldCreate"},
-			}},
-		}}
-		err := newTaskValidator(cfg).ValidateSemantic()
+		cfg.Wait[0].ManifestWork.Conditions = []string{"Applied", ""}
+		err := newTaskValidator(cfg).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "lifecycle.create requires a discovery config")
+		assert.Contains(t, err.Error(), "wait[0].manifest_work.conditions[1]: condition type must not be empty")
+
+		cfg.Wait[0].Name = "clusterId"
+		err = newTaskValidator(cfg).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `wait[0].name: "clusterId" is already defined at params[0].name`)
 	})
 
-	t.Run("lifecycle create and delete both missing discovery report both errors", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Resources = []Resource{{
-			Name:     "myResource",
-			Manifest: minManifest,
-			// Discovery intentionally absent
-			Lifecycle: &ResourceLifecycle{
-				Create: &LifecycleCreate{
-					When: &LifecycleWhen{Expression: "shouldCreate"},
-				},
-				Delete: &LifecycleDelete{
-					PropagationPolicy: "NotAValidPolicy",
-					When:              &LifecycleWhen{Expression: "shouldDelete"},
-				},
-			},
-		}}
-		err := newTaskValidator(cfg).ValidateSemantic()
+	t.Run("manifest work wait on a kubernetes resource", func(t *testing.T) {
+		step := newStep()
+		step.ManifestWork = &ManifestWorkWait{}
+		err := newTaskValidator(newConfig(step)).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "lifecycle.create requires a discovery config")
-		assert.Contains(t, err.Error(), "lifecycle.delete requires a discovery config")
+		assert.Contains(t, err.Error(),
+			`wait[0].manifest_work: resource "clusterConfigMap" does not use the maestro transport`)
+	})
+
+	t.Run("condition is required without manifest work", func(t *testing.T) {
+		step := newStep()
+		step.Condition = ""
+		require.Error(t, newTaskValidator(newConfig(step)).ValidateStructure())
 	})
 }
 
-func TestValidateParamsAPICallSource(t *testing.T) {
-	t.Run("api_call source passes validation", func(t *testing.T) {
+func TestValidateVaultSources(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{name: "valid source", source: "vault.secret/clusters/creds#password"},
+		{name: "missing key", source: "vault.secret/clusters/creds", wantErr: "must have the form vault.<path>#<key>"},
+		{name: "empty key", source: "vault.secret/clusters/creds#", wantErr: "must have the form vault.<path>#<key>"},
+		{name: "missing mount", source: "vault.creds#password", wantErr: "must name the secrets engine mount"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseTaskConfig()
+			cfg.Params = []Parameter{{Name: "password", Source: StringSource(tt.source)}}
+			err := newTaskValidator(cfg).ValidateStructure()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "params[0].source")
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateAPICallPatches(t *testing.T) {
+	newConfig := func(apiCall *APICall) *AdapterTaskConfig {
 		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "clusterId", Source: StringSource("event.id")},
-			{Name: "clusterData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"})},
+		cfg.Params = []Parameter{{Name: "clusterId", Source: StringSource("event.id")}}
+		cfg.Post = &PostConfig{
+			PostActions: []PostAction{{ActionBase: ActionBase{Name: "reportStatus", APICall: apiCall}}},
 		}
-		v := newTaskValidator(cfg)
+		return cfg
+	}
+	newPatchCall := func(ops ...JSONPatchOperation) *APICall {
+		return &APICall{Method: "PATCH", URL: "/clusters/{{ .clusterId }}/statuses", Patch: ops}
+	}
+
+	t.Run("valid patch", func(t *testing.T) {
+		v := newTaskValidator(newConfig(newPatchCall(
+			JSONPatchOperation{Op: "replace", Path: "/conditions/0/status", ValueExpression: "'True'"},
+			JSONPatchOperation{Op: "remove", Path: "/conditions/1"},
+			JSONPatchOperation{Op: "move", From: "/a", Path: "/b"},
+		)))
 		require.NoError(t, v.ValidateStructure())
 		require.NoError(t, v.ValidateSemantic())
 	})
 
-	t.Run("api_call source URL can reference param defined before it", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "clusterId", Source: StringSource("event.id")},
-			{Name: "clusterData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"})},
-			{Name: "nodePoolData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}/nodepools"})},
-		}
-		v := newTaskValidator(cfg)
+	tests := []struct {
+		apiCall *APICall
+		name    string
+		wantErr string
+	}{
+		{
+			name: "patch requires PATCH",
+			apiCall: &APICall{Method: "POST", URL: "/clusters", Patch: []JSONPatchOperation{
+				{Op: "remove", Path: "/a"},
+			}},
+			wantErr: `patch requires method PATCH, got "POST"`,
+		},
+		{
+			name: "patch and body are exclusive",
+			apiCall: &APICall{Method: "PATCH", URL: "/clusters", Body: "{}", Patch: []JSONPatchOperation{
+				{Op: "remove", Path: "/a"},
+			}},
+			wantErr: "patch",
+		},
+		{
+			name:    "unknown op",
+			apiCall: newPatchCall(JSONPatchOperation{Op: "merge", Path: "/a"}),
+			wantErr: "op",
+		},
+		{
+			name:    "value required",
+			apiCall: newPatchCall(JSONPatchOperation{Op: "add", Path: "/a"}),
+			wantErr: "post.post_actions[0].api_call.patch[0].value_expression",
+		},
+		{
+			name:    "value not allowed",
+			apiCall: newPatchCall(JSONPatchOperation{Op: "remove", Path: "/a", ValueExpression: "1"}),
+			wantErr: `value_expression is not allowed for op "remove"`,
+		},
+		{
+			name:    "from required",
+			apiCall: newPatchCall(JSONPatchOperation{Op: "copy", Path: "/a"}),
+			wantErr: `from is required for op "copy"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTaskValidator(newConfig(tt.apiCall)).ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("invalid value expression", func(t *testing.T) {
+		v := newTaskValidator(newConfig(newPatchCall(
+			JSONPatchOperation{Op: "replace", Path: "/a", ValueExpression: "adapter.("},
+		)))
 		require.NoError(t, v.ValidateStructure())
-		require.NoError(t, v.ValidateSemantic())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "post.post_actions[0].api_call.patch[0].value_expression")
+	})
+
+	t.Run("undefined template variable in path", func(t *testing.T) {
+		v := newTaskValidator(newConfig(newPatchCall(
+			JSONPatchOperation{Op: "remove", Path: "/conditions/{{ .unknown }}"},
+		)))
+		require.NoError(t, v.ValidateStructure())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "post.post_actions[0].api_call.patch[0].path")
 	})
+}
 
-	t.Run("api_call source URL cannot reference param defined after it", func(t *testing.T) {
+func TestValidateAPICallResponseFields(t *testing.T) {
+	newConfig := func(apiCall *APICall) *AdapterTaskConfig {
 		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			// nodePoolName is defined AFTER nodePoolData uses it (order violation)
-			{Name: "nodePoolData", Source: APICallSource(&APICall{Method: "GET", URL: "/nodepools/{{ .nodePoolName }}"})},
-			{Name: "nodePoolName", Source: StringSource("event.nodePool")},
-		}
-		v := newTaskValidator(cfg)
-		_ = v.ValidateStructure()
-		err := v.ValidateSemantic()
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "undefined template variable \"nodePoolName\"")
+		cfg.Preconditions = []Precondition{{ActionBase: ActionBase{Name: "listClusters", APICall: apiCall}}}
+		return cfg
+	}
+
+	t.Run("valid response fields", func(t *testing.T) {
+		v := newTaskValidator(newConfig(&APICall{
+			Method: "GET", URL: "/clusters", ResponseFields: []string{"items.id", "total"}, KeepRawResponse: true,
+		}))
+		require.NoError(t, v.ValidateStructure())
 	})
 
-	t.Run("expression source passes CEL validation", func(t *testing.T) {
+	tests := []struct {
+		apiCall *APICall
+		name    string
+		wantErr string
+	}{
+		{
+			name:    "empty path segment",
+			apiCall: &APICall{Method: "GET", URL: "/clusters", ResponseFields: []string{"items..id"}},
+			wantErr: "preconditions[0].api_call.response_fields[0]",
+		},
+		{
+			name:    "keep_raw_response without response_fields",
+			apiCall: &APICall{Method: "GET", URL: "/clusters", KeepRawResponse: true},
+			wantErr: "keep_raw_response requires response_fields",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTaskValidator(newConfig(tt.apiCall)).ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateTransform(t *testing.T) {
+	t.Run("valid precondition and resource transforms", func(t *testing.T) {
 		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "clusterId", Source: StringSource("event.id")},
-			{Name: "clusterData", Source: APICallSource(&APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"})},
-			{Name: "isActive", Source: ExpressionSource(`clusterData.status == "Active"`)},
-		}
+		cfg.Preconditions = []Precondition{{ActionBase: ActionBase{Name: "listClusters", APICall: &APICall{
+			Method: "GET", URL: "/clusters", Transform: "items.map(i, i.id)",
+		}}}}
 		v := newTaskValidator(cfg)
 		require.NoError(t, v.ValidateStructure())
 		require.NoError(t, v.ValidateSemantic())
 	})
 
-	t.Run("expression source with invalid CEL fails validation", func(t *testing.T) {
+	t.Run("transform on a post-action api_call", func(t *testing.T) {
 		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "bad", Source: ExpressionSource(`====invalid`)},
-		}
-		v := newTaskValidator(cfg)
-		_ = v.ValidateStructure()
-		err := v.ValidateSemantic()
+		cfg.Post = &PostConfig{PostActions: []PostAction{{ActionBase: ActionBase{Name: "report", APICall: &APICall{
+			Method: "POST", URL: "/status", Transform: "id",
+		}}}}}
+		err := newTaskValidator(cfg).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "CEL parse error")
+		assert.Contains(t, err.Error(), "transform is only supported on the api_call of params and preconditions")
 	})
 
-	t.Run("empty string source fails validation", func(t *testing.T) {
+	t.Run("transform syntax error", func(t *testing.T) {
 		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "bad", Source: StringSource("")},
-		}
+		cfg.Preconditions = []Precondition{{ActionBase: ActionBase{Name: "listClusters", APICall: &APICall{
+			Method: "GET", URL: "/clusters", Transform: "items.map(i,",
+		}}}}
 		v := newTaskValidator(cfg)
 		_ = v.ValidateStructure()
 		err := v.ValidateSemantic()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "source is required")
+		assert.Contains(t, err.Error(), "preconditions[0].api_call.transform")
 	})
 }
 
-func TestValidateParamsFileSource(t *testing.T) {
-	boolPtr := func(b bool) *bool { return &b }
-
-	t.Run("file source passes validation", func(t *testing.T) {
+func TestValidateAPICallBodyEncodings(t *testing.T) {
+	newConfig := func(apiCall *APICall) *AdapterTaskConfig {
 		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "token", Source: FileSource(&FileSourceConfig{
-				Path: "/var/run/secrets/kubernetes.io/serviceaccount/token",
-			})},
-		}
-		v := newTaskValidator(cfg)
+		cfg.Preconditions = []Precondition{{ActionBase: ActionBase{Name: "submit", APICall: apiCall}}}
+		return cfg
+	}
+
+	t.Run("valid multipart call", func(t *testing.T) {
+		v := newTaskValidator(newConfig(&APICall{
+			Method: "POST", URL: "/submit", Body: `{"id": "c1"}`, BodyEncoding: BodyEncodingMultipart,
+			Files: []FilePart{{Name: "kubeconfig", Content: "data"}, {Name: "report", Path: "/tmp/report"}},
+		}))
 		require.NoError(t, v.ValidateStructure())
-		require.NoError(t, v.ValidateSemantic())
 	})
 
-	t.Run("file source with explicit trim passes validation", func(t *testing.T) {
+	tests := []struct {
+		apiCall *APICall
+		name    string
+		wantErr string
+	}{
+		{
+			name:    "unknown encoding",
+			apiCall: &APICall{Method: "POST", URL: "/submit", BodyEncoding: "xml"},
+			wantErr: "body_encoding",
+		},
+		{
+			name:    "form on GET",
+			apiCall: &APICall{Method: "GET", URL: "/submit", BodyEncoding: BodyEncodingForm},
+			wantErr: `body_encoding "form" requires method POST, PUT or PATCH`,
+		},
+		{
+			name: "files without multipart",
+			apiCall: &APICall{Method: "POST", URL: "/submit", BodyEncoding: BodyEncodingForm,
+				Files: []FilePart{{Name: "f", Content: "data"}}},
+			wantErr: "preconditions[0].api_call.files",
+		},
+		{
+			name: "file with content and path",
+			apiCall: &APICall{Method: "POST", URL: "/submit", BodyEncoding: BodyEncodingMultipart,
+				Files: []FilePart{{Name: "f", Content: "data", Path: "/tmp/f"}}},
+			wantErr: "content",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newTaskValidator(newConfig(tt.apiCall)).ValidateStructure()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidatePlatform(t *testing.T) {
+	newConfig := func(platform *PlatformConfig) *AdapterTaskConfig {
 		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "token", Source: FileSource(&FileSourceConfig{
-				Path: "/var/run/secrets/token",
-				Trim: boolPtr(false),
-			})},
+		cfg.Params = []Parameter{{Name: "clusterArch", Source: StringSource("event.arch")}}
+		cfg.Resources = []Resource{{
+			Name: "agent",
+			Manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "agent", "namespace": "default"},
+				"data":       map[string]interface{}{"image": "{{ .platform.values.agentImage }}"},
+			},
+			Discovery: &DiscoveryConfig{Namespace: "default", ByName: "agent"},
+		}}
+		cfg.Platform = platform
+		return cfg
+	}
+	newPlatform := func() *PlatformConfig {
+		return &PlatformConfig{
+			Architecture: "clusterArch",
+			Maps: map[string]map[string]interface{}{
+				"agentImage": {"linux/arm64": "agent:1.4-arm64", "default": "agent:1.4"},
+			},
 		}
-		v := newTaskValidator(cfg)
+	}
+
+	t.Run("valid platform", func(t *testing.T) {
+		v := newTaskValidator(newConfig(newPlatform()))
 		require.NoError(t, v.ValidateStructure())
 		require.NoError(t, v.ValidateSemantic())
 	})
 
-	t.Run("file source with empty path rejected", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "token", Source: FileSource(&FileSourceConfig{Path: ""})},
-		}
-		v := newTaskValidator(cfg)
-		_ = v.ValidateStructure()
+	t.Run("platform variable is undefined without platform", func(t *testing.T) {
+		v := newTaskValidator(newConfig(nil))
+		require.NoError(t, v.ValidateStructure())
 		err := v.ValidateSemantic()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "path is required for file source")
+		assert.Contains(t, err.Error(), "platform.values.agentImage")
 	})
 
-	t.Run("file source mutual exclusivity with api_call", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		// Construct via YAML to hit UnmarshalYAML mutual exclusivity
-		yamlStr := `
-params:
-  - name: "bad"
-    source:
-      api_call:
-        method: "GET"
-        url: "/clusters/x"
-      file:
-        path: "/some/file"
-`
-		var taskCfg AdapterTaskConfig
-		err := yaml.Unmarshal([]byte(yamlStr), &taskCfg)
+	t.Run("invalid map name", func(t *testing.T) {
+		platform := newPlatform()
+		platform.Maps["agent-image"] = map[string]interface{}{"default": "agent:1.4"}
+		err := newTaskValidator(newConfig(platform)).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "only one of")
-		_ = cfg // suppress unused
+		assert.Contains(t, err.Error(), `platform.maps.agent-image: "agent-image" is not a valid CEL identifier`)
 	})
 
-	t.Run("file source mutual exclusivity with expression", func(t *testing.T) {
-		yamlStr := `
-params:
-  - name: "bad"
-    source:
-      expression: "1 + 1"
-      file:
-        path: "/some/file"
-`
-		var taskCfg AdapterTaskConfig
-		err := yaml.Unmarshal([]byte(yamlStr), &taskCfg)
+	t.Run("empty map", func(t *testing.T) {
+		platform := newPlatform()
+		platform.Maps["nodeSelector"] = map[string]interface{}{}
+		err := newTaskValidator(newConfig(platform)).ValidateStructure()
 		require.Error(t, err)
-		assert.Contains(t, err.Error(), "only one of")
+		assert.Contains(t, err.Error(), "platform.maps.nodeSelector: must have at least one value")
 	})
 
-	t.Run("file source with relative path emits warning", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Params = []Parameter{
-			{Name: "token", Source: FileSource(&FileSourceConfig{
-				Path: "relative/path/token",
-			})},
-		}
-		v := newTaskValidator(cfg)
-		require.NoError(t, v.ValidateStructure())
-		require.NoError(t, v.ValidateSemantic())
-		require.Len(t, v.Warnings(), 1)
-		assert.Contains(t, v.Warnings()[0], "not absolute")
+	t.Run("invalid key", func(t *testing.T) {
+		platform := newPlatform()
+		platform.Maps["agentImage"]["Linux ARM"] = "agent:1.4-arm64"
+		err := newTaskValidator(newConfig(platform)).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"Linux ARM" is not a platform key`)
 	})
-}
 
-func TestValidatePreconditionAPICallForbidden(t *testing.T) {
-	t.Run("precondition with api_call produces deprecation warning not error", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Preconditions = []Precondition{{
-			ActionBase: ActionBase{
-				Name:    "fetchCluster",
-				APICall: &APICall{Method: "GET", URL: "/clusters/x"},
-			},
-		}}
-		v := newTaskValidator(cfg)
-		_ = v.ValidateStructure()
-		err := v.ValidateSemantic()
-		require.NoError(t, err)
-		warnings := v.Warnings()
-		require.Len(t, warnings, 1)
-		assert.Contains(t, warnings[0], "DEPRECATED")
-		assert.Contains(t, warnings[0], "fetchCluster")
-		assert.Contains(t, warnings[0], "source.api_call")
+	t.Run("platform name is reserved when configured", func(t *testing.T) {
+		cfg := newConfig(newPlatform())
+		cfg.Params = append(cfg.Params, Parameter{Name: "platform", Source: StringSource("event.platform")})
+		err := newTaskValidator(cfg).ValidateStructure()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `params[1].name: "platform" is reserved`)
+
+		cfg.Platform = nil
+		require.NoError(t, newTaskValidator(cfg).ValidateStructure())
 	})
 
-	t.Run("precondition without api_call passes with no warnings", func(t *testing.T) {
-		cfg := baseTaskConfig()
-		cfg.Preconditions = []Precondition{{
-			ActionBase: ActionBase{Name: "check"},
-			Expression: "true",
-		}}
-		v := newTaskValidator(cfg)
+	t.Run("invalid expression", func(t *testing.T) {
+		platform := newPlatform()
+		platform.OS = "clusterOS =="
+		v := newTaskValidator(newConfig(platform))
 		require.NoError(t, v.ValidateStructure())
-		require.NoError(t, v.ValidateSemantic())
-		assert.Empty(t, v.Warnings())
+		err := v.ValidateSemantic()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "platform.os")
 	})
 }
 
-func TestAdapterConfigValidator_HyperfleetAuth(t *testing.T) {
-	baseAdapterConfig := func() *AdapterConfig {
-		return &AdapterConfig{
-			Adapter: AdapterInfo{Name: "test-adapter"},
-		}
+func TestValidateEventOverlays(t *testing.T) {
+	tests := []struct {
+		name        string
+		expectedErr string
+		overlays    []EventOverlay
+	}{
+		{
+			name: "valid overlays",
+			overlays: []EventOverlay{
+				{Attribute: "region", Field: "region"},
+				{Attribute: "subject", Field: "owner_references.id", Precedence: OverlayPrecedenceAttribute},
+				{Attribute: "generation", Field: "generation", Type: "int64"},
+			},
+		},
+		{
+			name:        "attribute is required",
+			overlays:    []EventOverlay{{Field: "region"}},
+			expectedErr: "event_overlays[0].attribute",
+		},
+		{
+			name:        "unknown precedence",
+			overlays:    []EventOverlay{{Attribute: "region", Field: "region", Precedence: "payload"}},
+			expectedErr: "event_overlays[0].precedence",
+		},
+		{
+			name:        "attribute names are lowercase",
+			overlays:    []EventOverlay{{Attribute: "Region", Field: "region"}},
+			expectedErr: `event_overlays[0].attribute: "Region" is not a CloudEvent attribute name`,
+		},
+		{
+			name:        "invalid field path",
+			overlays:    []EventOverlay{{Attribute: "region", Field: "spec..region"}},
+			expectedErr: `event_overlays[0].field: "spec..region" is not a dot-separated field path`,
+		},
+		{
+			name: "overlapping fields",
+			overlays: []EventOverlay{
+				{Attribute: "placement", Field: "spec.placement"},
+				{Attribute: "region", Field: "spec.placement.region"},
+			},
+			expectedErr: `event_overlays[1].field: "spec.placement.region" overlaps the field ` +
+				`"spec.placement" of event_overlays[0]`,
+		},
 	}
-	newValidator := func(cfg *AdapterConfig) *AdapterConfigValidator {
-		return NewAdapterConfigValidator(cfg, "")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := baseTaskConfig()
+			cfg.EventOverlays = tt.overlays
+			err := newTaskValidator(cfg).ValidateStructure()
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
 	}
+}
 
-	t.Run("nil auth is valid", func(t *testing.T) {
-		cfg := baseAdapterConfig()
-		require.NoError(t, newValidator(cfg).ValidateStructure())
-	})
+func TestValidateExecutionTimeout(t *testing.T) {
+	cfg := baseTaskConfig()
+	cfg.ExecutionTimeout = Duration(5 * time.Minute)
+	require.NoError(t, newTaskValidator(cfg).ValidateStructure())
 
-	t.Run("valid absolute token path", func(t *testing.T) {
-		cfg := baseAdapterConfig()
-		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{
-			TokenPath:     "/var/run/secrets/token",
-			TokenCacheTTL: 30,
-		}
-		require.NoError(t, newValidator(cfg).ValidateStructure())
-	})
+	cfg.ExecutionTimeout = Duration(-time.Second)
+	err := newTaskValidator(cfg).ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "execution_timeout must be a positive duration, got -1s")
+}
 
-	t.Run("empty token_path is an error", func(t *testing.T) {
-		cfg := baseAdapterConfig()
-		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{TokenPath: ""}
-		err := newValidator(cfg).ValidateStructure()
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "token_path must be set")
-	})
+func TestValidateFailurePolicy(t *testing.T) {
+	cfg := baseTaskConfig()
+	cfg.FailurePolicy = OnErrorContinue
+	cfg.Preconditions = []Precondition{{
+		ActionBase: ActionBase{Name: "fetchCluster"},
+		Expression: "true",
+		OnError:    OnErrorAbort,
+	}}
+	require.NoError(t, newTaskValidator(cfg).ValidateStructure())
 
-	t.Run("relative token_path is an error", func(t *testing.T) {
-		cfg := baseAdapterConfig()
-		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{TokenPath: "relative/path/token"}
-		err := newValidator(cfg).ValidateStructure()
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "must be an absolute path")
-	})
+	cfg.Preconditions[0].OnError = "retryThenAbort"
+	err := newTaskValidator(cfg).ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "on_error")
 
-	t.Run("negative token_cache_ttl is an error", func(t *testing.T) {
-		cfg := baseAdapterConfig()
-		cfg.Clients.HyperfleetAPI.Auth = &HyperfleetAPIAuthConfig{
-			TokenPath:     "/var/run/secrets/token",
-			TokenCacheTTL: -1,
-		}
-		err := newValidator(cfg).ValidateStructure()
-		require.Error(t, err)
-		assert.Contains(t, err.Error(), "token_cache_ttl must not be negative")
-	})
+	cfg = baseTaskConfig()
+	cfg.FailurePolicy = "ignore"
+	err = newTaskValidator(cfg).ValidateStructure()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failure_policy")
 }
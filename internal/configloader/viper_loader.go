@@ -5,13 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"gopkg.in/yaml.v3"
 )
 
+// apiClientKey is the Viper key of the HyperFleet API client block
+const apiClientKey = "clients::hyperfleet_api"
+
 // EnvPrefix is the prefix for all environment variables that override deployment config
 const EnvPrefix = "HYPERFLEET"
 
@@ -19,74 +24,145 @@ const EnvPrefix = "HYPERFLEET"
 // The full env var name is EnvPrefix + "_" + suffix
 // Note: Uses "::" as key delimiter to avoid conflicts with dots in YAML keys
 var viperKeyMappings = map[string]string{
-	"debug_config":                                     "DEBUG_CONFIG",
-	"clients::maestro::grpc_server_address":            "MAESTRO_GRPC_SERVER_ADDRESS",
-	"clients::maestro::http_server_address":            "MAESTRO_HTTP_SERVER_ADDRESS",
-	"clients::maestro::source_id":                      "MAESTRO_SOURCE_ID",
-	"clients::maestro::client_id":                      "MAESTRO_CLIENT_ID",
-	"clients::maestro::auth::type":                     "MAESTRO_AUTH_TYPE",
-	"clients::maestro::auth::tls_config::ca_file":      "MAESTRO_CA_FILE",
-	"clients::maestro::auth::tls_config::cert_file":    "MAESTRO_CERT_FILE",
-	"clients::maestro::auth::tls_config::key_file":     "MAESTRO_KEY_FILE",
-	"clients::maestro::auth::tls_config::http_ca_file": "MAESTRO_HTTP_CA_FILE",
-	"clients::maestro::timeout":                        "MAESTRO_TIMEOUT",
-	"clients::maestro::server_healthiness_timeout":     "MAESTRO_SERVER_HEALTHINESS_TIMEOUT",
-	"clients::maestro::retry_attempts":                 "MAESTRO_RETRY_ATTEMPTS",
-	"clients::maestro::keepalive::time":                "MAESTRO_KEEPALIVE_TIME",
-	"clients::maestro::keepalive::timeout":             "MAESTRO_KEEPALIVE_TIMEOUT",
-	"clients::maestro::insecure":                       "MAESTRO_INSECURE",
-	"clients::hyperfleet_api::base_url":                "API_BASE_URL",
-	"clients::hyperfleet_api::version":                 "API_VERSION",
-	"clients::hyperfleet_api::timeout":                 "API_TIMEOUT",
-	"clients::hyperfleet_api::retry_attempts":          "API_RETRY_ATTEMPTS",
-	"clients::hyperfleet_api::retry_backoff":           "API_RETRY_BACKOFF",
-	"clients::hyperfleet_api::base_delay":              "API_BASE_DELAY",
-	"clients::hyperfleet_api::max_delay":               "API_MAX_DELAY",
-	"clients::hyperfleet_api::auth::token_path":        "API_AUTH_TOKEN_PATH",
-	"clients::hyperfleet_api::auth::token_cache_ttl":   "API_AUTH_TOKEN_CACHE_TTL",
-	"clients::broker::subscription_id":                 "BROKER_SUBSCRIPTION_ID",
-	"clients::broker::topic":                           "BROKER_TOPIC",
-	"clients::kubernetes::kube_config_path":            "KUBERNETES_KUBE_CONFIG_PATH",
-	"clients::kubernetes::api_version":                 "KUBERNETES_API_VERSION",
-	"clients::kubernetes::qps":                         "KUBERNETES_QPS",
-	"clients::kubernetes::burst":                       "KUBERNETES_BURST",
+	"debug_config":                                              "DEBUG_CONFIG",
+	"missing_env_vars":                                          "MISSING_ENV_VARS",
+	"json_numbers":                                              "JSON_NUMBERS",
+	"clients::maestro::grpc_server_address":                     "MAESTRO_GRPC_SERVER_ADDRESS",
+	"clients::maestro::http_server_address":                     "MAESTRO_HTTP_SERVER_ADDRESS",
+	"clients::maestro::source_id":                               "MAESTRO_SOURCE_ID",
+	"clients::maestro::client_id":                               "MAESTRO_CLIENT_ID",
+	"clients::maestro::auth::type":                              "MAESTRO_AUTH_TYPE",
+	"clients::maestro::auth::tls_config::ca_file":               "MAESTRO_CA_FILE",
+	"clients::maestro::auth::tls_config::cert_file":             "MAESTRO_CERT_FILE",
+	"clients::maestro::auth::tls_config::key_file":              "MAESTRO_KEY_FILE",
+	"clients::maestro::auth::tls_config::http_ca_file":          "MAESTRO_HTTP_CA_FILE",
+	"clients::maestro::timeout":                                 "MAESTRO_TIMEOUT",
+	"clients::maestro::server_healthiness_timeout":              "MAESTRO_SERVER_HEALTHINESS_TIMEOUT",
+	"clients::maestro::retry_attempts":                          "MAESTRO_RETRY_ATTEMPTS",
+	"clients::maestro::keepalive::time":                         "MAESTRO_KEEPALIVE_TIME",
+	"clients::maestro::keepalive::timeout":                      "MAESTRO_KEEPALIVE_TIMEOUT",
+	"clients::maestro::insecure":                                "MAESTRO_INSECURE",
+	"clients::maestro::max_concurrent_applies":                  "MAESTRO_MAX_CONCURRENT_APPLIES",
+	"clients::hyperfleet_api::base_url":                         "API_BASE_URL",
+	"clients::hyperfleet_api::version":                          "API_VERSION",
+	"clients::hyperfleet_api::timeout":                          "API_TIMEOUT",
+	"clients::hyperfleet_api::retry_attempts":                   "API_RETRY_ATTEMPTS",
+	"clients::hyperfleet_api::retry_backoff":                    "API_RETRY_BACKOFF",
+	"clients::hyperfleet_api::base_delay":                       "API_BASE_DELAY",
+	"clients::hyperfleet_api::max_delay":                        "API_MAX_DELAY",
+	"clients::hyperfleet_api::auth::token_path":                 "API_AUTH_TOKEN_PATH",
+	"clients::hyperfleet_api::auth::token_cache_ttl":            "API_AUTH_TOKEN_CACHE_TTL",
+	"clients::hyperfleet_api::auth::oauth2::token_url":          "API_AUTH_OAUTH2_TOKEN_URL",
+	"clients::hyperfleet_api::auth::oauth2::client_id":          "API_AUTH_OAUTH2_CLIENT_ID",
+	"clients::hyperfleet_api::auth::oauth2::client_secret_path": "API_AUTH_OAUTH2_CLIENT_SECRET_PATH",
+	"clients::hyperfleet_api::auth::oidc::issuer":               "API_AUTH_OIDC_ISSUER",
+	"clients::hyperfleet_api::auth::oidc::client_id":            "API_AUTH_OIDC_CLIENT_ID",
+	"clients::hyperfleet_api::auth::oidc::flow":                 "API_AUTH_OIDC_FLOW",
+	"clients::hyperfleet_api::auth::oidc::cache_dir":            "API_AUTH_OIDC_CACHE_DIR",
+	"clients::hyperfleet_api::profile":                          "API_PROFILE",
+	"clients::hyperfleet_api::compression::disabled":            "API_COMPRESSION_DISABLED",
+	"clients::hyperfleet_api::compression::min_bytes":           "API_COMPRESSION_MIN_BYTES",
+	"clients::broker::subscription_id":                          "BROKER_SUBSCRIPTION_ID",
+	"clients::broker::topic":                                    "BROKER_TOPIC",
+	"clients::broker::publish_topic":                            "BROKER_PUBLISH_TOPIC",
+	"clients::broker::dead_letter_topic":                        "BROKER_DEAD_LETTER_TOPIC",
+	"clients::broker::max_delivery_attempts":                    "BROKER_MAX_DELIVERY_ATTEMPTS",
+	"clients::broker::concurrency":                              "BROKER_CONCURRENCY",
+	"clients::broker::ack_mode":                                 "BROKER_ACK_MODE",
+	"clients::broker::type":                                     "BROKER_TYPE",
+	"clients::kubernetes::kube_config_path":                     "KUBERNETES_KUBE_CONFIG_PATH",
+	"clients::kubernetes::api_version":                          "KUBERNETES_API_VERSION",
+	"clients::kubernetes::qps":                                  "KUBERNETES_QPS",
+	"clients::kubernetes::burst":                                "KUBERNETES_BURST",
+	"clients::kubernetes::manage_namespaces":                    "KUBERNETES_MANAGE_NAMESPACES",
+	"clients::kubernetes::max_concurrent_writes":                "KUBERNETES_MAX_CONCURRENT_WRITES",
+	"clients::vault::address":                                   "VAULT_ADDRESS",
+	"clients::vault::token_path":                                "VAULT_TOKEN_PATH",
+	"clients::vault::namespace":                                 "VAULT_NAMESPACE",
+	"clients::gitops::repository":                               "GITOPS_REPOSITORY",
+	"clients::gitops::branch":                                   "GITOPS_BRANCH",
+	"clients::gitops::token_path":                               "GITOPS_TOKEN_PATH",
+	"limits::max_steps":                                         "LIMITS_MAX_STEPS",
+	"limits::max_templates_per_manifest":                        "LIMITS_MAX_TEMPLATES_PER_MANIFEST",
+	"limits::max_captures":                                      "LIMITS_MAX_CAPTURES",
+	"limits::max_variables":                                     "LIMITS_MAX_VARIABLES",
+	"limits::max_for_each_items":                                "LIMITS_MAX_FOR_EACH_ITEMS",
+	"sharding::enabled":                                         "SHARDING_ENABLED",
+	"sharding::count":                                           "SHARDING_COUNT",
+	"sharding::statefulset":                                     "SHARDING_STATEFULSET",
+	"sharding::refresh_interval":                                "SHARDING_REFRESH_INTERVAL",
+	"deduplication::enabled":                                    "DEDUPLICATION_ENABLED",
+	"deduplication::store":                                      "DEDUPLICATION_STORE",
+	"deduplication::ttl":                                        "DEDUPLICATION_TTL",
+	"deduplication::redis::address":                             "DEDUPLICATION_REDIS_ADDRESS",
+	"deduplication::redis::password_path":                       "DEDUPLICATION_REDIS_PASSWORD_PATH",
+	"deduplication::redis::pool_size":                           "DEDUPLICATION_REDIS_POOL_SIZE",
+	"deduplication::redis::tls::enabled":                        "DEDUPLICATION_REDIS_TLS_ENABLED",
+	"deduplication::redis::tls::ca_file":                        "DEDUPLICATION_REDIS_TLS_CA_FILE",
+	"traffic_recording::enabled":                                "TRAFFIC_RECORDING_ENABLED",
+	"traffic_recording::span_events":                            "TRAFFIC_RECORDING_SPAN_EVENTS",
+	"execution_recording::enabled":                              "EXECUTION_RECORDING_ENABLED",
+	"execution_recording::dir":                                  "EXECUTION_RECORDING_DIR",
+	"sli::enabled":                                              "SLI_ENABLED",
+	"sli::target":                                               "SLI_TARGET",
+	"sli::state_path":                                           "SLI_STATE_PATH",
+	"admin::enabled":                                            "ADMIN_ENABLED",
+	"admin::port":                                               "ADMIN_PORT",
 }
 
 // cliFlags defines mappings from CLI flag names to config paths
 // Note: Uses "::" as key delimiter to avoid conflicts with dots in YAML keys
 var cliFlags = map[string]string{
-	"debug-config":                       "debug_config",
-	"maestro-grpc-server-address":        "clients::maestro::grpc_server_address",
-	"maestro-http-server-address":        "clients::maestro::http_server_address",
-	"maestro-source-id":                  "clients::maestro::source_id",
-	"maestro-client-id":                  "clients::maestro::client_id",
-	"maestro-auth-type":                  "clients::maestro::auth::type",
-	"maestro-ca-file":                    "clients::maestro::auth::tls_config::ca_file",
-	"maestro-cert-file":                  "clients::maestro::auth::tls_config::cert_file",
-	"maestro-key-file":                   "clients::maestro::auth::tls_config::key_file",
-	"maestro-http-ca-file":               "clients::maestro::auth::tls_config::http_ca_file",
-	"maestro-timeout":                    "clients::maestro::timeout",
-	"maestro-server-healthiness-timeout": "clients::maestro::server_healthiness_timeout",
-	"maestro-retry-attempts":             "clients::maestro::retry_attempts",
-	"maestro-keepalive-time":             "clients::maestro::keepalive::time",
-	"maestro-keepalive-timeout":          "clients::maestro::keepalive::timeout",
-	"maestro-insecure":                   "clients::maestro::insecure",
-	"hyperfleet-api-base-url":            "clients::hyperfleet_api::base_url",
-	"hyperfleet-api-version":             "clients::hyperfleet_api::version",
-	"hyperfleet-api-timeout":             "clients::hyperfleet_api::timeout",
-	"hyperfleet-api-retry":               "clients::hyperfleet_api::retry_attempts",
-	"hyperfleet-api-retry-backoff":       "clients::hyperfleet_api::retry_backoff",
-	"hyperfleet-api-base-delay":          "clients::hyperfleet_api::base_delay",
-	"hyperfleet-api-max-delay":           "clients::hyperfleet_api::max_delay",
-	"broker-subscription-id":             "clients::broker::subscription_id",
-	"broker-topic":                       "clients::broker::topic",
-	"kubernetes-kube-config-path":        "clients::kubernetes::kube_config_path",
-	"kubernetes-api-version":             "clients::kubernetes::api_version",
-	"kubernetes-qps":                     "clients::kubernetes::qps",
-	"kubernetes-burst":                   "clients::kubernetes::burst",
-	"log-level":                          "log::level",
-	"log-format":                         "log::format",
-	"log-output":                         "log::output",
+	"debug-config":                         "debug_config",
+	"missing-env-vars":                     "missing_env_vars",
+	"json-numbers":                         "json_numbers",
+	"maestro-grpc-server-address":          "clients::maestro::grpc_server_address",
+	"maestro-http-server-address":          "clients::maestro::http_server_address",
+	"maestro-source-id":                    "clients::maestro::source_id",
+	"maestro-client-id":                    "clients::maestro::client_id",
+	"maestro-auth-type":                    "clients::maestro::auth::type",
+	"maestro-ca-file":                      "clients::maestro::auth::tls_config::ca_file",
+	"maestro-cert-file":                    "clients::maestro::auth::tls_config::cert_file",
+	"maestro-key-file":                     "clients::maestro::auth::tls_config::key_file",
+	"maestro-http-ca-file":                 "clients::maestro::auth::tls_config::http_ca_file",
+	"maestro-timeout":                      "clients::maestro::timeout",
+	"maestro-server-healthiness-timeout":   "clients::maestro::server_healthiness_timeout",
+	"maestro-retry-attempts":               "clients::maestro::retry_attempts",
+	"maestro-keepalive-time":               "clients::maestro::keepalive::time",
+	"maestro-keepalive-timeout":            "clients::maestro::keepalive::timeout",
+	"maestro-insecure":                     "clients::maestro::insecure",
+	"hyperfleet-api-base-url":              "clients::hyperfleet_api::base_url",
+	"hyperfleet-api-version":               "clients::hyperfleet_api::version",
+	"hyperfleet-api-timeout":               "clients::hyperfleet_api::timeout",
+	"hyperfleet-api-retry":                 "clients::hyperfleet_api::retry_attempts",
+	"hyperfleet-api-retry-backoff":         "clients::hyperfleet_api::retry_backoff",
+	"hyperfleet-api-base-delay":            "clients::hyperfleet_api::base_delay",
+	"hyperfleet-api-max-delay":             "clients::hyperfleet_api::max_delay",
+	"hyperfleet-api-profile":               "clients::hyperfleet_api::profile",
+	"hyperfleet-api-compression-disabled":  "clients::hyperfleet_api::compression::disabled",
+	"hyperfleet-api-compression-min-bytes": "clients::hyperfleet_api::compression::min_bytes",
+	"broker-subscription-id":               "clients::broker::subscription_id",
+	"broker-topic":                         "clients::broker::topic",
+	"broker-publish-topic":                 "clients::broker::publish_topic",
+	"broker-dead-letter-topic":             "clients::broker::dead_letter_topic",
+	"broker-max-delivery-attempts":         "clients::broker::max_delivery_attempts",
+	"broker-concurrency":                   "clients::broker::concurrency",
+	"broker-ack-mode":                      "clients::broker::ack_mode",
+	"broker-type":                          "clients::broker::type",
+	"kubernetes-kube-config-path":          "clients::kubernetes::kube_config_path",
+	"kubernetes-api-version":               "clients::kubernetes::api_version",
+	"kubernetes-qps":                       "clients::kubernetes::qps",
+	"kubernetes-burst":                     "clients::kubernetes::burst",
+	"log-level":                            "log::level",
+	"log-format":                           "log::format",
+	"log-output":                           "log::output",
+	"limits-max-steps":                     "limits::max_steps",
+	"limits-max-templates-per-manifest":    "limits::max_templates_per_manifest",
+	"limits-max-captures":                  "limits::max_captures",
+	"limits-max-variables":                 "limits::max_variables",
+	"limits-max-for-each-items":            "limits::max_for_each_items",
+	"sharding-enabled":                     "sharding::enabled",
+	"sharding-count":                       "sharding::count",
 }
 
 // standardConfigPaths are tried when no explicit config path is provided
@@ -155,6 +231,12 @@ func loadAdapterConfigWithViper(
 		return "", nil, fmt.Errorf("failed to merge config map: %w", err)
 	}
 
+	// Apply the selected HyperFleet API profile before env/CLI overrides,
+	// so explicit HYPERFLEET_API_* values still take precedence over it
+	if err := applyAPIProfile(v, selectedAPIProfile(v, flags)); err != nil {
+		return "", nil, err
+	}
+
 	// Bind environment variables
 	v.SetEnvPrefix(EnvPrefix)
 	v.AutomaticEnv()
@@ -202,14 +284,76 @@ func loadAdapterConfigWithViper(
 	}
 
 	// Unmarshal into AdapterConfig struct
+	// Duration fields decode through their UnmarshalText, which rejects bare numbers
 	var config AdapterConfig
-	if err := v.Unmarshal(&config); err != nil {
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.TextUnmarshallerHookFunc(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	if err := v.Unmarshal(&config, decodeHook); err != nil {
 		return "", nil, fmt.Errorf("failed to unmarshal adapter config: %w", err)
 	}
 
 	return filePath, &config, nil
 }
 
+// selectedAPIProfile returns the HyperFleet API profile name.
+// Priority: CLI flag > environment variable > config file
+func selectedAPIProfile(v *viper.Viper, flags *pflag.FlagSet) string {
+	if flags != nil {
+		if flag := flags.Lookup("hyperfleet-api-profile"); flag != nil && flag.Changed {
+			return flag.Value.String()
+		}
+	}
+	if val := os.Getenv(EnvPrefix + "_API_PROFILE"); val != "" {
+		return val
+	}
+	return v.GetString(apiClientKey + "::profile")
+}
+
+// applyAPIProfile overlays the named profile onto clients.hyperfleet_api.
+// Profile fields replace the base values, except default_headers which are merged.
+func applyAPIProfile(v *viper.Viper, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	// Viper keys are case-insensitive, so profile names are matched in lower case
+	profiles := v.GetStringMap(apiClientKey + "::profiles")
+	raw, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		defined := make([]string, 0, len(profiles))
+		for p := range profiles {
+			defined = append(defined, p)
+		}
+		sort.Strings(defined)
+		return fmt.Errorf("unknown HyperFleet API profile %q (defined: %s)", name, strings.Join(defined, ", "))
+	}
+
+	profile, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("HyperFleet API profile %q must be a mapping", name)
+	}
+	for key, val := range profile {
+		if key == "default_headers" {
+			headers := v.GetStringMapString(apiClientKey + "::default_headers")
+			profileHeaders, ok := val.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("default_headers of HyperFleet API profile %q must be a mapping", name)
+			}
+			for hk, hv := range profileHeaders {
+				headers[hk] = fmt.Sprint(hv)
+			}
+			v.Set(apiClientKey+"::default_headers", headers)
+			continue
+		}
+		v.Set(apiClientKey+"::"+key, val)
+	}
+	v.Set(apiClientKey+"::profile", strings.ToLower(name))
+	return nil
+}
+
 // loadTaskConfig loads the task configuration from a YAML file without Viper overrides.
 // Task config is purely static YAML configuration.
 func loadTaskConfig(filePath string) (*AdapterTaskConfig, error) {
@@ -229,6 +373,15 @@ func loadTaskConfig(filePath string) (*AdapterTaskConfig, error) {
 		return nil, fmt.Errorf("failed to read task config file %q: %w", filePath, err)
 	}
 
+	baseDir, err := getBaseDir(filePath)
+	if err != nil {
+		return nil, err
+	}
+	data, err = expandIncludes(data, baseDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to include step files: %w", err)
+	}
+
 	var config AdapterTaskConfig
 	decoder := yaml.NewDecoder(bytes.NewReader(data))
 	decoder.KnownFields(true)
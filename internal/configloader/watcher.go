@@ -0,0 +1,179 @@
+package configloader
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// EnvTaskConfigWatchInterval enables task config hot reload, polling at the given interval
+const EnvTaskConfigWatchInterval = "HYPERFLEET_TASK_CONFIG_WATCH_INTERVAL"
+
+// TaskConfigWatcher reloads the config when a file in the task config directory, or in
+// one of its subdirectories, changes.
+//
+// The directory is polled and compared by content rather than watched with inotify:
+// Kubernetes updates ConfigMap volumes by atomically swapping a hidden symlink, which
+// is reliably seen by re-reading the files. Included step files and manifests referenced
+// from the directory tree are covered as well.
+type TaskConfigWatcher struct {
+	reload   func() (*Config, error)
+	onReload func(*Config) error
+	log      logger.Logger
+	dir      string
+	hash     string
+	interval time.Duration
+}
+
+// NewTaskConfigWatcher creates a watcher for the directory of taskConfigPath. On every
+// change reload is called to load and validate the new config; only a config that
+// loads successfully is passed to onReload. Invalid configs are logged and the
+// running config is kept.
+func NewTaskConfigWatcher(
+	taskConfigPath string,
+	interval time.Duration,
+	reload func() (*Config, error),
+	onReload func(*Config) error,
+	log logger.Logger,
+) (*TaskConfigWatcher, error) {
+	if taskConfigPath == "" {
+		taskConfigPath = os.Getenv(EnvTaskConfigPath)
+	}
+	if taskConfigPath == "" {
+		return nil, fmt.Errorf("task config path is required to watch for changes")
+	}
+	if IsOCIReference(taskConfigPath) {
+		return nil, fmt.Errorf("task config %s is an OCI reference and cannot be watched", taskConfigPath)
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("watch interval must be positive, got %s", interval)
+	}
+
+	w := &TaskConfigWatcher{
+		reload:   reload,
+		onReload: onReload,
+		log:      log,
+		dir:      filepath.Dir(taskConfigPath),
+		interval: interval,
+	}
+	hash, err := hashConfigDir(w.dir)
+	if err != nil {
+		return nil, err
+	}
+	w.hash = hash
+	return w, nil
+}
+
+// Run polls for changes until ctx is canceled
+func (w *TaskConfigWatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	w.log.Infof(ctx, "Watching task config directory %s for changes every %s", w.dir, w.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check(ctx)
+		}
+	}
+}
+
+// check reloads the config if the directory content changed since the last check.
+// The new hash is kept even if the reload fails, so an invalid config is reported
+// once rather than on every tick.
+func (w *TaskConfigWatcher) check(ctx context.Context) {
+	hash, err := hashConfigDir(w.dir)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		w.log.Warnf(errCtx, "Failed to read task config directory %s", w.dir)
+		return
+	}
+	if hash == w.hash {
+		return
+	}
+	w.hash = hash
+	w.log.Infof(ctx, "Task config directory %s changed, reloading", w.dir)
+
+	config, err := w.reload()
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		w.log.Errorf(errCtx, "Reloaded task config is invalid, keeping the running config")
+		return
+	}
+	if err := w.onReload(config); err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		w.log.Errorf(errCtx, "Failed to apply reloaded task config, keeping the running config")
+		return
+	}
+	w.log.Info(ctx, "Task config reloaded")
+}
+
+// hashConfigDir hashes the relative paths and contents of the regular files in dir and
+// its subdirectories, so that changes to included step files and manifest_ref files are
+// detected too. Symlinks are followed and hidden entries (such as ConfigMap ..data links)
+// are skipped.
+func hashConfigDir(dir string) (string, error) {
+	h := sha256.New()
+	if err := hashConfigSubdir(h, dir, "", make(map[string]bool)); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashConfigSubdir writes the files under root/rel to h. visited holds the resolved
+// directories already hashed, so a symlink loop is not followed forever.
+func hashConfigSubdir(h hash.Hash, root, rel string, visited map[string]bool) error {
+	dir := filepath.Join(root, rel)
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("failed to resolve directory %s: %w", dir, err)
+	}
+	if visited[resolved] {
+		return nil
+	}
+	visited[resolved] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		name := filepath.Join(rel, entry.Name())
+		path := filepath.Join(root, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		if info.IsDir() {
+			if err = hashConfigSubdir(h, root, name, visited); err != nil {
+				return err
+			}
+			continue
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		content, err := os.ReadFile(path) //nolint:gosec // path is inside the configured task config directory
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		h.Write([]byte(filepath.ToSlash(name)))
+		h.Write([]byte{0})
+		h.Write(content)
+		h.Write([]byte{0})
+	}
+	return nil
+}
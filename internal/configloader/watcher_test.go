@@ -0,0 +1,173 @@
+package configloader
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTaskConfigWatcher_Rejects(t *testing.T) {
+	noop := func() (*Config, error) { return nil, nil }
+	apply := func(*Config) error { return nil }
+	log := logger.NewTestLogger()
+	t.Setenv(EnvTaskConfigPath, "")
+
+	_, err := NewTaskConfigWatcher("", time.Second, noop, apply, log)
+	assert.ErrorContains(t, err, "task config path is required")
+
+	_, err = NewTaskConfigWatcher("oci://registry.example.com/task:v1", time.Second, noop, apply, log)
+	assert.ErrorContains(t, err, "OCI reference")
+
+	path := filepath.Join(t.TempDir(), "task.yaml")
+	_, err = NewTaskConfigWatcher(path, 0, noop, apply, log)
+	assert.ErrorContains(t, err, "watch interval must be positive")
+}
+
+func TestTaskConfigWatcher_Check(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "task.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("v1"), 0o600))
+
+	var reloads int
+	var reloadErr error
+	var applied []*Config
+	reload := func() (*Config, error) {
+		reloads++
+		if reloadErr != nil {
+			return nil, reloadErr
+		}
+		return &Config{Resources: []Resource{{Name: "cm"}}}, nil
+	}
+	apply := func(cfg *Config) error {
+		applied = append(applied, cfg)
+		return nil
+	}
+
+	w, err := NewTaskConfigWatcher(path, time.Second, reload, apply, logger.NewTestLogger())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Unchanged directory does not reload
+	w.check(ctx)
+	assert.Equal(t, 0, reloads)
+
+	// A change reloads and applies the new config
+	require.NoError(t, os.WriteFile(path, []byte("v2"), 0o600))
+	w.check(ctx)
+	assert.Equal(t, 1, reloads)
+	require.Len(t, applied, 1)
+	assert.Equal(t, "cm", applied[0].Resources[0].Name)
+
+	// Hidden entries are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..data"), []byte("x"), 0o600))
+	w.check(ctx)
+	assert.Equal(t, 1, reloads)
+
+	// An invalid config is reported once and not applied
+	reloadErr = errors.New("invalid")
+	require.NoError(t, os.WriteFile(path, []byte("v3"), 0o600))
+	w.check(ctx)
+	w.check(ctx)
+	assert.Equal(t, 2, reloads)
+	assert.Len(t, applied, 1)
+}
+
+func TestHashConfigDir_Subdirectories(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "task.yaml"), []byte("include: steps/"), 0o600))
+	steps := filepath.Join(dir, "steps", "resources")
+	require.NoError(t, os.MkdirAll(steps, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(steps, "cm.yaml"), []byte("v1"), 0o600))
+	// A symlink loop is hashed once rather than followed forever
+	require.NoError(t, os.Symlink(dir, filepath.Join(steps, "loop")))
+
+	before, err := hashConfigDir(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(steps, "cm.yaml"), []byte("v2"), 0o600))
+	after, err := hashConfigDir(dir)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after, "a change in a subdirectory should change the hash")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".git"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("x"), 0o600))
+	hidden, err := hashConfigDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, after, hidden, "hidden directories should be skipped")
+}
+
+func TestConfig_WithTaskFrom(t *testing.T) {
+	running := &Config{
+		Adapter:   AdapterInfo{Version: "1.0.0"},
+		Resources: []Resource{{Name: "old"}},
+	}
+	reloaded := &Config{
+		Adapter:   AdapterInfo{Version: "2.0.0"},
+		Params:    []Parameter{{Name: "id", Source: StringSource("event.id")}},
+		Resources: []Resource{{Name: "new"}},
+		Post:      &PostConfig{},
+	}
+
+	updated := running.WithTaskFrom(reloaded)
+	assert.Equal(t, "1.0.0", updated.Adapter.Version, "deployment settings are kept")
+	assert.Equal(t, "new", updated.Resources[0].Name)
+	assert.Len(t, updated.Params, 1)
+	assert.NotNil(t, updated.Post)
+	assert.Equal(t, "old", running.Resources[0].Name, "running config is not modified")
+}
+
+func TestConfig_TaskConfigHash(t *testing.T) {
+	config := &Config{
+		Adapter:   AdapterInfo{Version: "1.0.0"},
+		Params:    []Parameter{{Name: "id", Source: StringSource("event.id")}},
+		Resources: []Resource{{Name: "ns", Manifest: map[string]interface{}{"kind": "Namespace"}}},
+	}
+
+	hash := config.TaskConfigHash()
+	assert.Len(t, hash, configHashLength)
+	assert.Equal(t, hash, config.TaskConfigHash(), "hash is stable")
+
+	deployment := *config
+	deployment.Adapter.Version = "2.0.0"
+	assert.Equal(t, hash, deployment.TaskConfigHash(), "deployment settings are not part of the hash")
+
+	changed := *config
+	changed.Params = []Parameter{{Name: "id", Source: StringSource("event.owner_references.id")}}
+	assert.NotEqual(t, hash, changed.TaskConfigHash())
+
+	assert.Empty(t, (*Config)(nil).TaskConfigHash())
+}
+
+func TestConfig_ConfigFingerprint(t *testing.T) {
+	config := &Config{
+		Adapter:       AdapterInfo{Version: "1.0.0"},
+		Notifications: NotificationsConfig{Targets: []NotificationTarget{{URL: "https://hooks.example.com/secret-1"}}},
+		Params:        []Parameter{{Name: "id", Source: StringSource("event.id")}},
+	}
+
+	fingerprint := config.ConfigFingerprint()
+	assert.Len(t, fingerprint, configHashLength)
+	assert.Equal(t, fingerprint, config.ConfigFingerprint(), "fingerprint is stable")
+	assert.NotEqual(t, config.TaskConfigHash(), fingerprint)
+
+	deployment := *config
+	deployment.Adapter.Version = "2.0.0"
+	assert.NotEqual(t, fingerprint, deployment.ConfigFingerprint(), "deployment settings are part of the fingerprint")
+
+	task := *config
+	task.Params = []Parameter{{Name: "id", Source: StringSource("event.owner_references.id")}}
+	assert.NotEqual(t, fingerprint, task.ConfigFingerprint(), "task settings are part of the fingerprint")
+
+	rotated := *config
+	rotated.Notifications.Targets = []NotificationTarget{{URL: "https://hooks.example.com/secret-2"}}
+	assert.Equal(t, fingerprint, rotated.ConfigFingerprint(), "redacted credentials are not part of the fingerprint")
+
+	assert.Empty(t, (*Config)(nil).ConfigFingerprint())
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/google/cel-go/common/types/ref"
 	"github.com/google/cel-go/ext"
 	apperrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // CELEvaluator evaluates CEL expressions against a context
@@ -44,11 +46,12 @@ func (r *CELResult) HasError() bool {
 	return r.Error != nil
 }
 
-// newCELEvaluator creates a new CEL evaluator with the given context
+// newCELEvaluator creates a new CEL evaluator with the given context. strictJSONNumbers
+// selects how fromJson() decodes numbers.
 // NOTE: Caller (NewEvaluator) is responsible for parameter validation
-func newCELEvaluator(evalCtx *EvaluationContext) (*CELEvaluator, error) {
+func newCELEvaluator(evalCtx *EvaluationContext, strictJSONNumbers bool) (*CELEvaluator, error) {
 	// Build CEL environment with variables from context
-	options := buildCELOptions(evalCtx)
+	options := buildCELOptions(evalCtx, strictJSONNumbers)
 
 	env, err := cel.NewEnv(options...)
 	if err != nil {
@@ -63,14 +66,17 @@ func newCELEvaluator(evalCtx *EvaluationContext) (*CELEvaluator, error) {
 
 // buildCELOptions creates CEL environment options from the context
 // Variables are dynamically registered based on what's in ctx.Data()
-func buildCELOptions(ctx *EvaluationContext) []cel.EnvOption {
+func buildCELOptions(ctx *EvaluationContext, strictJSONNumbers bool) []cel.EnvOption {
 	options := make([]cel.EnvOption, 0)
 
 	// Enable optional types for optional chaining syntax (e.g., a.?b.?c)
 	options = append(options, cel.OptionalTypes())
+	options = append(options, cel.CustomTypeAdapter(jsonNumberAdapter{}))
 	options = append(options, ext.Strings())
 	options = append(options, ext.Lists())
+	options = append(options, extensionCELLibraries()...)
 	options = append(options, customCELFunctions()...)
+	options = append(options, extensionCELFunctions(strictJSONNumbers)...)
 
 	// Get a snapshot of the data for thread safety
 	data := ctx.Data()
@@ -301,6 +307,44 @@ func findCondition(conditions []interface{}, condType string) (string, map[strin
 	return "Unknown", nil
 }
 
+// NativeValue converts the value of a CEL result to plain Go values: lists built by the
+// expression become []interface{} and maps map[string]interface{}, so the value can be
+// stored in the params and used by templates and later expressions.
+func NativeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case types.Null, structpb.NullValue:
+		return nil
+	case ref.Val:
+		return NativeValue(v.Value())
+	case []ref.Val:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = NativeValue(item)
+		}
+		return list
+	case map[ref.Val]ref.Val:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(NativeValue(key))] = NativeValue(item)
+		}
+		return m
+	case []interface{}:
+		list := make([]interface{}, len(v))
+		for i, item := range v {
+			list[i] = NativeValue(item)
+		}
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = NativeValue(item)
+		}
+		return m
+	default:
+		return v
+	}
+}
+
 // unwrapCELList converts a CEL ref.Val list to a Go []interface{}.
 func unwrapCELList(val ref.Val) ([]interface{}, bool) {
 	raw, ok := unwrapCELValue(val)
@@ -377,6 +421,11 @@ func inferCELType(value interface{}) *cel.Type {
 		return cel.UintType
 	case float32, float64:
 		return cel.DoubleType
+	case json.Number:
+		if _, ok := utils.NormalizeJSONNumbers(value).(int64); ok {
+			return cel.IntType
+		}
+		return cel.DoubleType
 	case []interface{}:
 		return cel.ListType(cel.DynType)
 	case map[string]interface{}:
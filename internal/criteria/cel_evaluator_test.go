@@ -2,6 +2,7 @@ package criteria
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -17,7 +18,7 @@ func TestNewCELEvaluator(t *testing.T) {
 	ctx.Set("status", "Ready")
 	ctx.Set("replicas", 3)
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 	require.NotNil(t, evaluator)
 }
@@ -29,7 +30,7 @@ func TestCELEvaluatorEvaluate(t *testing.T) {
 	ctx.Set("provider", "aws")
 	ctx.Set("enabled", true)
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	tests := []struct {
@@ -135,7 +136,7 @@ func TestCELEvaluatorWithNestedData(t *testing.T) {
 		},
 	})
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	// Test nested field access
@@ -152,6 +153,32 @@ func TestCELEvaluatorWithNestedData(t *testing.T) {
 	assert.True(t, result.Matched)
 }
 
+func TestCELEvaluatorJSONNumbers(t *testing.T) {
+	ctx := NewEvaluationContext()
+	ctx.Set("nodeCount", json.Number("3"))
+	ctx.Set("ratio", json.Number("0.5"))
+	ctx.Set("spec", map[string]interface{}{
+		"nodeCount": json.Number("3"),
+		"sizes":     []interface{}{json.Number("1"), json.Number("2")},
+	})
+
+	evaluator, err := newCELEvaluator(ctx, false)
+	require.NoError(t, err)
+
+	for _, expr := range []string{
+		`nodeCount == 3`,
+		`nodeCount * 2 == 6`,
+		`ratio * 2.0 == 1.0`,
+		`spec.nodeCount + 1 == 4`,
+		`spec.sizes.all(s, s < 3)`,
+	} {
+		result, err := evaluator.EvaluateSafe(expr)
+		require.NoError(t, err, expr)
+		require.NoError(t, result.Error, expr)
+		assert.True(t, result.Matched, expr)
+	}
+}
+
 func TestCELEvaluatorEvaluateSafe(t *testing.T) {
 	ctx := NewEvaluationContext()
 	ctx.Set("cluster", map[string]interface{}{
@@ -163,7 +190,7 @@ func TestCELEvaluatorEvaluateSafe(t *testing.T) {
 	})
 	ctx.Set("nullValue", nil)
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	t.Run("successful evaluation", func(t *testing.T) {
@@ -252,7 +279,7 @@ func TestCELEvaluatorEvaluateBool(t *testing.T) {
 	ctx := NewEvaluationContext()
 	ctx.Set("status", "Ready")
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	// True result
@@ -271,7 +298,7 @@ func TestCELEvaluatorEvaluateString(t *testing.T) {
 	ctx.Set("status", "Ready")
 	ctx.Set("name", "test-cluster")
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	// String result
@@ -318,7 +345,7 @@ func TestCELEvaluatorCustomFunctions(t *testing.T) {
 		},
 	})
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	t.Run("toJson serializes structures", func(t *testing.T) {
@@ -404,7 +431,7 @@ func TestCELEvaluatorDomainFunctions(t *testing.T) {
 	})
 	ctx.Set("emptyFeedback", map[string]interface{}{})
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	t.Run("conditionStatus returns status for existing condition", func(t *testing.T) {
@@ -580,7 +607,7 @@ func TestCELEvaluatorDomainFunctions_MalformedInputs(t *testing.T) {
 		},
 	})
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	t.Run("conditionAge returns -1 for invalid timestamp", func(t *testing.T) {
@@ -638,7 +665,7 @@ func TestCELEvaluatorExtStrings(t *testing.T) {
 	ctx.Set("channelGroup", "candidate")
 	ctx.Set("version", "4.22.0-ec.4")
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	// The version resolution adapter derives the Cincinnati channel name from channelGroup
@@ -665,7 +692,7 @@ func TestCELEvaluatorExtLists(t *testing.T) {
 		[]interface{}{"c", "d"},
 	})
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	t.Run("distinct removes duplicates", func(t *testing.T) {
@@ -720,6 +747,39 @@ func TestCELEvaluatorExtLists(t *testing.T) {
 
 // TestEvaluateSafeErrorHandling tests how EvaluateSafe handles various error scenarios
 // and how callers can use the result to make decisions at a higher level
+func TestNativeValue(t *testing.T) {
+	ctx := NewEvaluationContext()
+	ctx.Set("items", []interface{}{
+		map[string]interface{}{"name": "a", "replicas": 1},
+		map[string]interface{}{"name": "b", "replicas": 2},
+	})
+	evaluator, err := newCELEvaluator(ctx, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		want       interface{}
+		name       string
+		expression string
+	}{
+		{name: "list", expression: "items.map(i, i.name)", want: []interface{}{"a", "b"}},
+		{
+			name:       "map",
+			expression: `{"names": items.map(i, i.name), "count": items.size()}`,
+			want:       map[string]interface{}{"names": []interface{}{"a", "b"}, "count": int64(2)},
+		},
+		{name: "null", expression: "null", want: nil},
+		{name: "scalar", expression: "items[0].name", want: "a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluator.EvaluateSafe(tt.expression)
+			require.NoError(t, err)
+			require.NoError(t, result.Error)
+			assert.Equal(t, tt.want, NativeValue(result.Value))
+		})
+	}
+}
+
 func TestEvaluateSafeErrorHandling(t *testing.T) {
 	ctx := NewEvaluationContext()
 	ctx.Set("data", map[string]interface{}{
@@ -730,7 +790,7 @@ func TestEvaluateSafeErrorHandling(t *testing.T) {
 		},
 	})
 
-	evaluator, err := newCELEvaluator(ctx)
+	evaluator, err := newCELEvaluator(ctx, false)
 	require.NoError(t, err)
 
 	tests := []struct {
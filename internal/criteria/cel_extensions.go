@@ -0,0 +1,193 @@
+package criteria
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// extensionCELLibraries returns the CEL extension libraries beyond ext.Strings and
+// ext.Lists: base64 encoding (base64.encode, base64.decode) and regular expressions
+// (regex.replace, regex.extract, regex.extractAll). Regex matching itself is the
+// standard matches() function, and timestamp arithmetic uses the standard timestamp()
+// and duration() types.
+func extensionCELLibraries() []cel.EnvOption {
+	return []cel.EnvOption{
+		ext.Encoders(),
+		ext.Regex(),
+	}
+}
+
+type strictJSONNumbersKey struct{}
+
+// WithStrictJSONNumbers returns a context whose evaluators decode the numbers parsed by
+// fromJson() as int64 or float64 by their JSON form when strict (json_numbers: strict),
+// like event data and API responses, rather than always as float64
+func WithStrictJSONNumbers(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictJSONNumbersKey{}, strict)
+}
+
+// strictJSONNumbers reports whether ctx was returned by WithStrictJSONNumbers with strict set
+func strictJSONNumbers(ctx context.Context) bool {
+	strict, ok := ctx.Value(strictJSONNumbersKey{}).(bool)
+	return ok && strict
+}
+
+// extensionCELFunctions registers JSON parsing, semver and platform helpers, so that
+// expressions do not need Go template workarounds for them. strictJSONNumbers selects
+// how fromJson() decodes numbers.
+func extensionCELFunctions(strictJSONNumbers bool) []cel.EnvOption {
+	return []cel.EnvOption{
+		cel.Function("fromJson",
+			cel.Overload(
+				"fromJson_string",
+				[]*cel.Type{cel.StringType},
+				cel.DynType,
+				cel.UnaryBinding(func(arg ref.Val) ref.Val {
+					s, ok := arg.Value().(string)
+					if !ok {
+						return types.NewErr("fromJson() argument must be a string")
+					}
+					value, err := utils.UnmarshalJSONValue([]byte(s), strictJSONNumbers)
+					if err != nil {
+						return types.NewErr("fromJson() failed to parse JSON: %v", err)
+					}
+					return types.DefaultTypeAdapter.NativeToValue(value)
+				}),
+			),
+		),
+		cel.Function("isSemver",
+			cel.Overload(
+				"isSemver_string",
+				[]*cel.Type{cel.StringType},
+				cel.BoolType,
+				cel.UnaryBinding(func(arg ref.Val) ref.Val {
+					s, ok := arg.Value().(string)
+					if !ok {
+						return types.Bool(false)
+					}
+					_, err := semver.NewVersion(s)
+					return types.Bool(err == nil)
+				}),
+			),
+		),
+		cel.Function("semverCompare",
+			cel.Overload(
+				"semverCompare_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.IntType,
+				cel.BinaryBinding(func(lhs ref.Val, rhs ref.Val) ref.Val {
+					a, errVal := parseSemverArg("semverCompare", lhs)
+					if errVal != nil {
+						return errVal
+					}
+					b, errVal := parseSemverArg("semverCompare", rhs)
+					if errVal != nil {
+						return errVal
+					}
+					return types.Int(a.Compare(b))
+				}),
+			),
+		),
+		cel.Function("semverMatches",
+			cel.Overload(
+				"semverMatches_string_string",
+				[]*cel.Type{cel.StringType, cel.StringType},
+				cel.BoolType,
+				cel.BinaryBinding(func(versionArg ref.Val, constraintArg ref.Val) ref.Val {
+					v, errVal := parseSemverArg("semverMatches", versionArg)
+					if errVal != nil {
+						return errVal
+					}
+					constraint, ok := constraintArg.Value().(string)
+					if !ok {
+						return types.NewErr("semverMatches() constraint must be a string")
+					}
+					c, err := semver.NewConstraint(constraint)
+					if err != nil {
+						return types.NewErr("semverMatches() invalid constraint %q: %v", constraint, err)
+					}
+					return types.Bool(c.Check(v))
+				}),
+			),
+		),
+		cel.Function("platformSelect",
+			cel.Overload(
+				"platformSelect_dyn_map",
+				[]*cel.Type{cel.DynType, cel.MapType(cel.StringType, cel.DynType)},
+				cel.DynType,
+				cel.BinaryBinding(func(platformArg ref.Val, valuesArg ref.Val) ref.Val {
+					platformMap, err := toStringMap(platformArg)
+					if err != nil {
+						return types.NewErr("platformSelect() platform must be a map: %v", err)
+					}
+					valuesMap, err := toStringMap(valuesArg)
+					if err != nil {
+						return types.NewErr("platformSelect() values must be a map with string keys: %v", err)
+					}
+					os, arch := utils.PlatformOSArch(platformMap)
+					value, found := utils.SelectPlatformValue(valuesMap, os, arch)
+					if !found {
+						return types.NewErr("platformSelect() no value for %s/%s and no %q value", os, arch,
+							utils.PlatformDefaultKey)
+					}
+					return types.DefaultTypeAdapter.NativeToValue(value)
+				}),
+			),
+		),
+	}
+}
+
+// toStringMap converts a CEL map, such as a map literal, to a Go map
+func toStringMap(val ref.Val) (map[string]interface{}, error) {
+	native, err := val.ConvertToNative(reflect.TypeOf(map[string]interface{}{}))
+	if err != nil {
+		return nil, err
+	}
+	m, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected map type %T", native)
+	}
+	return m, nil
+}
+
+// parseSemverArg parses a CEL string argument as a semantic version. A leading "v" and
+// missing minor or patch numbers are accepted.
+func parseSemverArg(fn string, arg ref.Val) (*semver.Version, ref.Val) {
+	s, ok := arg.Value().(string)
+	if !ok {
+		return nil, types.NewErr("%s() version must be a string", fn)
+	}
+	v, err := semver.NewVersion(s)
+	if err != nil {
+		return nil, types.NewErr("%s() invalid version %q: %v", fn, s, err)
+	}
+	return v, nil
+}
+
+// jsonNumberAdapter converts json.Number values, as decoded with json.Decoder.UseNumber, to
+// CEL int or double values by their form, so that an integer compares and adds as an int.
+// Other values are converted by the default adapter; maps and lists are wrapped with this
+// adapter so that nested numbers are converted too.
+type jsonNumberAdapter struct{}
+
+func (a jsonNumberAdapter) NativeToValue(value any) ref.Val {
+	switch v := value.(type) {
+	case json.Number:
+		return types.DefaultTypeAdapter.NativeToValue(utils.NormalizeJSONNumbers(v))
+	case map[string]interface{}:
+		return types.NewStringInterfaceMap(a, v)
+	case []interface{}:
+		return types.NewDynamicList(a, v)
+	default:
+		return types.DefaultTypeAdapter.NativeToValue(value)
+	}
+}
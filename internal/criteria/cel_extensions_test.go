@@ -0,0 +1,125 @@
+package criteria
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCELEvaluatorExtensionFunctions(t *testing.T) {
+	ctx := NewEvaluationContext()
+	ctx.Set("regions", "us-east-1,us-west-2")
+	ctx.Set("annotation", `{"owner":"team-a","replicas":3}`)
+	ctx.Set("token", "aHlwZXJmbGVldA==")
+	ctx.Set("version", "v4.15.2")
+	ctx.Set("createdAt", "2026-01-01T10:00:00Z")
+	ctx.Set("platform", map[string]interface{}{"os": "linux", "architecture": "arm64"})
+
+	evaluator, err := newCELEvaluator(ctx, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		want       interface{}
+		name       string
+		expression string
+	}{
+		{name: "split", expression: `regions.split(",")[1]`, want: "us-west-2"},
+		{name: "join", expression: `["a", "b"].join("-")`, want: "a-b"},
+		{name: "regex match", expression: `version.matches("^v4\\.[0-9]+")`, want: true},
+		{name: "regex replace", expression: `regex.replace(version, "^v", "")`, want: "4.15.2"},
+		{name: "regex extract", expression: `regex.extract(version, "v([0-9]+)").orValue("")`, want: "4"},
+		{name: "base64 decode", expression: `string(base64.decode(token))`, want: "hyperfleet"},
+		{name: "base64 encode", expression: `base64.encode(b"hyperfleet")`, want: "aHlwZXJmbGVldA=="},
+		{name: "fromJson field", expression: `fromJson(annotation).owner`, want: "team-a"},
+		{name: "fromJson number", expression: `fromJson(annotation).replicas == 3`, want: true},
+		{
+			name:       "timestamp arithmetic",
+			expression: `timestamp(createdAt) + duration("2h") == timestamp("2026-01-01T12:00:00Z")`,
+			want:       true,
+		},
+		{name: "timestamp difference", expression: `timestamp(now()) - timestamp(createdAt) > duration("1h")`, want: true},
+		{name: "semverCompare older", expression: `semverCompare(version, "4.16.0")`, want: int64(-1)},
+		{name: "semverCompare equal", expression: `semverCompare("4.15", "v4.15.0")`, want: int64(0)},
+		{name: "semverMatches", expression: `semverMatches(version, ">= 4.14, < 5")`, want: true},
+		{name: "isSemver", expression: `isSemver(version) && !isSemver("latest")`, want: true},
+		{
+			name:       "platformSelect",
+			expression: `platformSelect(platform, {"arm64": "agent-arm64", "default": "agent"})`,
+			want:       "agent-arm64",
+		},
+		{
+			name:       "platformSelect default",
+			expression: `platformSelect(platform, {"linux/amd64": "agent-amd64", "default": "agent"})`,
+			want:       "agent",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluator.EvaluateSafe(tt.expression)
+			require.NoError(t, err)
+			require.False(t, result.HasError(), "unexpected error: %v", result.Error)
+			assert.Equal(t, tt.want, result.Value)
+		})
+	}
+}
+
+func TestCELEvaluatorFromJson_StrictNumbers(t *testing.T) {
+	evalCtx := NewEvaluationContext()
+	evalCtx.Set("annotation", `{"replicas": 3, "ratio": 0.5, "id": 9007199254740993}`)
+
+	evaluate := func(ctx context.Context, expression string) interface{} {
+		t.Helper()
+		evaluator, err := NewEvaluator(ctx, evalCtx, logger.NewTestLogger())
+		require.NoError(t, err)
+		result, err := evaluator.EvaluateCEL(expression)
+		require.NoError(t, err)
+		require.False(t, result.HasError(), "unexpected error: %v", result.Error)
+		return result.Value
+	}
+
+	ctx := context.Background()
+	assert.Equal(t, 3.0, evaluate(ctx, `fromJson(annotation).replicas`), "numbers are doubles by default")
+	assert.Equal(t, false, evaluate(ctx, `type(fromJson(annotation).replicas) == int`))
+
+	strict := WithStrictJSONNumbers(ctx, true)
+	assert.Equal(t, int64(3), evaluate(strict, `fromJson(annotation).replicas`))
+	assert.Equal(t, true, evaluate(strict, `type(fromJson(annotation).replicas) == int`))
+	assert.Equal(t, 0.5, evaluate(strict, `fromJson(annotation).ratio`))
+	assert.Equal(t, int64(9007199254740993), evaluate(strict, `fromJson(annotation).id`))
+	assert.Equal(t, int64(4), evaluate(strict, `fromJson("[4]")[0]`))
+}
+
+func TestCELEvaluatorExtensionFunctions_Errors(t *testing.T) {
+	ctx := NewEvaluationContext()
+	ctx.Set("version", "latest")
+
+	evaluator, err := newCELEvaluator(ctx, false)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		expression string
+		wantErr    string
+	}{
+		{name: "invalid JSON", expression: `fromJson("{")`, wantErr: "fromJson() failed to parse JSON"},
+		{name: "invalid version", expression: `semverCompare(version, "1.0.0")`, wantErr: `invalid version "latest"`},
+		{name: "invalid constraint", expression: `semverMatches("1.0.0", "~>>1")`, wantErr: "invalid constraint"},
+		{
+			name:       "no platform value",
+			expression: `platformSelect({"os": "linux", "architecture": "s390x"}, {"arm64": "agent-arm64"})`,
+			wantErr:    "no value for linux/s390x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evaluator.EvaluateSafe(tt.expression)
+			require.NoError(t, err)
+			require.True(t, result.HasError())
+			assert.Contains(t, result.Error.Error(), tt.wantErr)
+		})
+	}
+}
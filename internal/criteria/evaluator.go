@@ -81,7 +81,7 @@ func (e *Evaluator) getCELEvaluator() (*CELEvaluator, error) {
 
 	// Recreate CEL evaluator if context changed or not yet created
 	if e.celEval == nil || e.celEvalVersion != currentVersion {
-		celEval, err := newCELEvaluator(e.evalCtx)
+		celEval, err := newCELEvaluator(e.evalCtx, strictJSONNumbers(e.ctx))
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,165 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// configMapUpdateAttempts bounds the retries of a Mark that conflicts with another replica
+const configMapUpdateAttempts = 5
+
+var configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+// ConfigMapClient reads and writes ConfigMaps; *k8sclient.Client satisfies it
+type ConfigMapClient interface {
+	GetResource(
+		ctx context.Context,
+		gvk schema.GroupVersionKind,
+		namespace, name string,
+		target transportclient.TransportContext,
+	) (*unstructured.Unstructured, error)
+	CreateResource(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	UpdateResource(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+}
+
+// ConfigMapStore keeps completed events in a ConfigMap, shared by all replicas without
+// extra infrastructure. Each data entry maps a hash of the event key to its expiry as Unix
+// seconds. Expired entries are dropped on every write, and the entries expiring first
+// when the ConfigMap holds more than maxEntries. The adapter's service account needs get,
+// create and update access to the ConfigMap.
+type ConfigMapStore struct {
+	client     ConfigMapClient
+	now        func() time.Time
+	namespace  string
+	name       string
+	ttl        time.Duration
+	maxEntries int
+	mu         sync.Mutex
+}
+
+// NewConfigMapStore creates a ConfigMapStore for the ConfigMap namespace/name. The
+// ConfigMap is created on the first Mark.
+func NewConfigMapStore(
+	client ConfigMapClient,
+	namespace, name string,
+	ttl time.Duration,
+	maxEntries int,
+) *ConfigMapStore {
+	return &ConfigMapStore{
+		client:     client,
+		now:        time.Now,
+		namespace:  namespace,
+		name:       name,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}
+}
+
+// Seen reports whether the ConfigMap has an unexpired entry for key
+func (s *ConfigMapStore) Seen(ctx context.Context, key string) (bool, error) {
+	cm, err := s.client.GetResource(ctx, configMapGVK, s.namespace, s.name, nil)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	value, found, err := unstructured.NestedString(cm.Object, "data", entryName(key))
+	if err != nil || !found {
+		return false, nil
+	}
+	expires, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	return s.now().Unix() < expires, nil
+}
+
+// Mark adds an entry for key, retrying when another replica updated the ConfigMap first
+func (s *ConfigMapStore) Mark(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var err error
+	for attempt := 0; attempt < configMapUpdateAttempts; attempt++ {
+		err = s.mark(ctx, key)
+		if !apierrors.IsConflict(err) && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("failed to update ConfigMap %s/%s after %d attempts: %w",
+		s.namespace, s.name, configMapUpdateAttempts, err)
+}
+
+func (s *ConfigMapStore) mark(ctx context.Context, key string) error {
+	now := s.now()
+	cm, err := s.client.GetResource(ctx, configMapGVK, s.namespace, s.name, nil)
+	create := apierrors.IsNotFound(err)
+	if err != nil && !create {
+		return err
+	}
+	if create {
+		cm = &unstructured.Unstructured{}
+		cm.SetGroupVersionKind(configMapGVK)
+		cm.SetNamespace(s.namespace)
+		cm.SetName(s.name)
+	}
+
+	data, _, err := unstructured.NestedStringMap(cm.Object, "data")
+	if err != nil || data == nil {
+		data = make(map[string]string)
+	}
+	data[entryName(key)] = strconv.FormatInt(now.Add(s.ttl).Unix(), 10)
+	s.prune(data, now)
+	if err = unstructured.SetNestedStringMap(cm.Object, data, "data"); err != nil {
+		return err
+	}
+
+	if create {
+		_, err = s.client.CreateResource(ctx, cm)
+	} else {
+		_, err = s.client.UpdateResource(ctx, cm)
+	}
+	return err
+}
+
+// prune drops expired entries, then the entries expiring first beyond maxEntries
+func (s *ConfigMapStore) prune(data map[string]string, now time.Time) {
+	expiries := make(map[string]int64, len(data))
+	for name, value := range data {
+		expires, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || expires <= now.Unix() {
+			delete(data, name)
+			continue
+		}
+		expiries[name] = expires
+	}
+	if len(data) <= s.maxEntries {
+		return
+	}
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return expiries[names[i]] < expiries[names[j]] })
+	for _, name := range names[:len(names)-s.maxEntries] {
+		delete(data, name)
+	}
+}
+
+// entryName returns a ConfigMap data key for an event key, which may contain characters
+// ConfigMap keys do not allow
+func entryName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,95 @@
+// Package dedup remembers the events whose workflow completed, so that events redelivered
+// by the broker are not executed again.
+//
+// An event is identified by its CloudEvent ID and the generation of the resource it carries:
+// a redelivery has both, while a new event for a changed resource has a new ID and
+// generation. Completed events are kept in a Store for a TTL. The memory store is local to
+// the replica; the redis and configmap stores are shared by all replicas, so a redelivery
+// to another replica is also recognized.
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+)
+
+// Defaults applied when the config leaves them unset
+const (
+	DefaultTTL        = time.Hour
+	DefaultMaxEntries = 10000
+)
+
+// Store records completed events
+type Store interface {
+	// Seen reports whether key was marked and has not expired
+	Seen(ctx context.Context, key string) (bool, error)
+	// Mark records key as completed for the store's TTL
+	Mark(ctx context.Context, key string) error
+}
+
+// Key returns the store key of an event
+func Key(eventID string, generation int64) string {
+	return fmt.Sprintf("%s/%d", eventID, generation)
+}
+
+// New creates the store selected by cfg. It returns nil when deduplication is disabled.
+// client is used by the configmap store and may be nil for the other stores.
+func New(cfg configloader.DeduplicationConfig, adapterName, namespace string, client ConfigMapClient) (Store, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	ttl := cfg.TTL.OrDefault(DefaultTTL)
+	maxEntries := cfg.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultMaxEntries
+	}
+
+	switch cfg.Store {
+	case "", configloader.DeduplicationStoreMemory:
+		return NewMemoryStore(ttl, maxEntries), nil
+	case configloader.DeduplicationStoreRedis:
+		if cfg.Redis == nil || cfg.Redis.Address == "" {
+			return nil, fmt.Errorf("deduplication.redis.address is required for the redis store")
+		}
+		var password string
+		if cfg.Redis.PasswordPath != "" {
+			data, err := os.ReadFile(cfg.Redis.PasswordPath)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read redis password: %w", err)
+			}
+			password = strings.TrimSpace(string(data))
+		}
+		opts := []RedisOption{WithRedisPoolSize(cfg.Redis.PoolSize)}
+		if cfg.Redis.TLS != nil && cfg.Redis.TLS.Enabled {
+			tlsConfig, err := cfg.Redis.TLS.ClientConfig()
+			if err != nil {
+				return nil, fmt.Errorf("redis TLS: %w", err)
+			}
+			opts = append(opts, WithRedisTLS(tlsConfig))
+		}
+		prefix := "hyperfleet-adapter:" + adapterName + ":dedup:"
+		return NewRedisStore(cfg.Redis.Address, password, cfg.Redis.DB, prefix, ttl, opts...), nil
+	case configloader.DeduplicationStoreConfigMap:
+		if client == nil {
+			return nil, fmt.Errorf("the configmap store needs a Kubernetes client")
+		}
+		name := adapterName + "-dedup"
+		if cfg.ConfigMap != nil && cfg.ConfigMap.Name != "" {
+			name = cfg.ConfigMap.Name
+		}
+		if cfg.ConfigMap != nil && cfg.ConfigMap.Namespace != "" {
+			namespace = cfg.ConfigMap.Namespace
+		}
+		if namespace == "" {
+			return nil, fmt.Errorf("deduplication.configmap.namespace is required outside a pod")
+		}
+		return NewConfigMapStore(client, namespace, name, ttl, maxEntries), nil
+	default:
+		return nil, fmt.Errorf("unknown deduplication store %q", cfg.Store)
+	}
+}
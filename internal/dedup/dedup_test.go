@@ -0,0 +1,414 @@
+package dedup
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "event-1/3", Key("event-1", 3))
+}
+
+func TestNew(t *testing.T) {
+	store, err := New(configloader.DeduplicationConfig{}, "adapter", "", nil)
+	require.NoError(t, err)
+	assert.Nil(t, store, "disabled deduplication has no store")
+
+	store, err = New(configloader.DeduplicationConfig{Enabled: true}, "adapter", "", nil)
+	require.NoError(t, err)
+	assert.IsType(t, &MemoryStore{}, store)
+
+	_, err = New(configloader.DeduplicationConfig{Enabled: true, Store: "redis"}, "adapter", "", nil)
+	require.Error(t, err)
+
+	_, err = New(configloader.DeduplicationConfig{Enabled: true, Store: "configmap"}, "adapter", "ns", nil)
+	require.Error(t, err)
+
+	store, err = New(configloader.DeduplicationConfig{Enabled: true, Store: "configmap"}, "adapter", "ns",
+		newFakeConfigMapClient())
+	require.NoError(t, err)
+	assert.Equal(t, "adapter-dedup", store.(*ConfigMapStore).name)
+	assert.Equal(t, "ns", store.(*ConfigMapStore).namespace)
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore(time.Minute, 2)
+	store.now = func() time.Time { return now }
+
+	seen, err := store.Seen(ctx, "a")
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	require.NoError(t, store.Mark(ctx, "a"))
+	seen, _ = store.Seen(ctx, "a")
+	assert.True(t, seen)
+
+	t.Run("least recently used is evicted", func(t *testing.T) {
+		require.NoError(t, store.Mark(ctx, "b"))
+		_, _ = store.Seen(ctx, "a")
+		require.NoError(t, store.Mark(ctx, "c"))
+		assert.Equal(t, 2, store.Len())
+		seen, _ := store.Seen(ctx, "b")
+		assert.False(t, seen)
+		seen, _ = store.Seen(ctx, "a")
+		assert.True(t, seen)
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		seen, _ := store.Seen(ctx, "a")
+		assert.False(t, seen)
+		assert.Equal(t, 1, store.Len(), "expired entry is evicted on lookup")
+	})
+}
+
+func TestRedisStore(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis(t, "secret")
+	passwordPath := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(passwordPath, []byte("secret\n"), 0600))
+
+	store, err := New(configloader.DeduplicationConfig{
+		Enabled: true,
+		Store:   "redis",
+		TTL:     configloader.Duration(time.Minute),
+		Redis:   &configloader.RedisStoreConfig{Address: server.address, PasswordPath: passwordPath, DB: 2},
+	}, "adapter", "", nil)
+	require.NoError(t, err)
+	redis := store.(*RedisStore)
+	defer func() { _ = redis.Close() }()
+
+	seen, err := redis.Seen(ctx, "event-1/1")
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	require.NoError(t, redis.Mark(ctx, "event-1/1"))
+	seen, err = redis.Seen(ctx, "event-1/1")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	server.mu.Lock()
+	assert.Equal(t, "60000", server.ttls["hyperfleet-adapter:adapter:dedup:event-1/1"])
+	assert.Equal(t, "2", server.db)
+	server.mu.Unlock()
+
+	t.Run("wrong password", func(t *testing.T) {
+		store := NewRedisStore(server.address, "wrong", 0, "", time.Minute)
+		_, err := store.Seen(ctx, "event-1/1")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "authentication failed")
+	})
+
+	t.Run("unreachable server", func(t *testing.T) {
+		store := NewRedisStore("127.0.0.1:1", "", 0, "", time.Minute)
+		_, err := store.Seen(ctx, "event-1/1")
+		require.Error(t, err)
+	})
+
+	t.Run("closed store", func(t *testing.T) {
+		store := NewRedisStore(server.address, "secret", 0, "", time.Minute)
+		require.NoError(t, store.Mark(ctx, "event-2/1"))
+		require.NoError(t, store.Close())
+		_, err := store.Seen(ctx, "event-2/1")
+		assert.ErrorContains(t, err, "closed")
+	})
+}
+
+func TestRedisStore_Pool(t *testing.T) {
+	ctx := context.Background()
+	server := newFakeRedis(t, "")
+	store := NewRedisStore(server.address, "", 0, "", time.Minute, WithRedisPoolSize(2))
+	defer func() { _ = store.Close() }()
+
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, store.Mark(ctx, fmt.Sprintf("event-%d/1", i)))
+		}()
+	}
+	wg.Wait()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Len(t, server.values, 20)
+	assert.LessOrEqual(t, server.maxConns, 2, "connections are bounded by the pool size")
+}
+
+func TestRedisStore_TLS(t *testing.T) {
+	ctx := context.Background()
+	// Borrow the self-signed certificate of an httptest TLS server
+	httpServer := httptest.NewTLSServer(nil)
+	serverTLS := httpServer.TLS.Clone()
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: httpServer.Certificate().Raw})
+	httpServer.Close()
+
+	server := newFakeRedisTLS(t, "", serverTLS)
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, caPEM, 0600))
+
+	newStore := func(tlsConfig *utils.TLSConfig) (*RedisStore, error) {
+		store, err := New(configloader.DeduplicationConfig{
+			Enabled: true,
+			Store:   "redis",
+			Redis:   &configloader.RedisStoreConfig{Address: server.address, TLS: tlsConfig},
+		}, "adapter", "", nil)
+		if err != nil {
+			return nil, err
+		}
+		return store.(*RedisStore), nil
+	}
+
+	store, err := newStore(&utils.TLSConfig{Enabled: true, CAFile: caFile})
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+	require.NoError(t, store.Mark(ctx, "event-1/1"))
+	seen, err := store.Seen(ctx, "event-1/1")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	t.Run("server not trusted", func(t *testing.T) {
+		store, err := newStore(&utils.TLSConfig{Enabled: true})
+		require.NoError(t, err)
+		_, err = store.Seen(ctx, "event-1/1")
+		assert.ErrorContains(t, err, "failed to connect to redis")
+	})
+
+	t.Run("missing CA file", func(t *testing.T) {
+		_, err := newStore(&utils.TLSConfig{Enabled: true, CAFile: filepath.Join(t.TempDir(), "missing")})
+		assert.ErrorContains(t, err, "redis TLS: failed to read CA file")
+	})
+}
+
+func TestConfigMapStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	client := newFakeConfigMapClient()
+	store := NewConfigMapStore(client, "ns", "adapter-dedup", time.Minute, 2)
+	store.now = func() time.Time { return now }
+
+	seen, err := store.Seen(ctx, "event-1/1")
+	require.NoError(t, err)
+	assert.False(t, seen, "missing ConfigMap means no completed events")
+
+	require.NoError(t, store.Mark(ctx, "event-1/1"))
+	seen, err = store.Seen(ctx, "event-1/1")
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	t.Run("retries conflicting updates", func(t *testing.T) {
+		now = now.Add(time.Second)
+		client.conflicts = 2
+		require.NoError(t, store.Mark(ctx, "event-2/1"))
+		seen, _ := store.Seen(ctx, "event-2/1")
+		assert.True(t, seen)
+	})
+
+	t.Run("keeps at most max entries", func(t *testing.T) {
+		now = now.Add(time.Second)
+		require.NoError(t, store.Mark(ctx, "event-3/1"))
+		assert.Len(t, client.data(), 2)
+		seen, _ := store.Seen(ctx, "event-1/1")
+		assert.False(t, seen, "the entry expiring first is dropped")
+	})
+
+	t.Run("entries expire", func(t *testing.T) {
+		now = now.Add(time.Minute)
+		seen, _ := store.Seen(ctx, "event-3/1")
+		assert.False(t, seen)
+		require.NoError(t, store.Mark(ctx, "event-4/1"))
+		assert.Len(t, client.data(), 1, "expired entries are pruned on write")
+	})
+}
+
+// redisOKReply is the RESP simple string replied to successful commands
+const redisOKReply = "+OK\r\n"
+
+// fakeRedis serves the RESP commands used by RedisStore
+type fakeRedis struct {
+	values   map[string]bool
+	ttls     map[string]string
+	address  string
+	password string
+	db       string
+	// conns and maxConns count the open connections
+	conns    int
+	maxConns int
+	mu       sync.Mutex
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	return newFakeRedisTLS(t, password, nil)
+}
+
+// newFakeRedisTLS starts a fakeRedis serving TLS with tlsConfig, or plain TCP when nil
+func newFakeRedisTLS(t *testing.T, password string, tlsConfig *tls.Config) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	server := &fakeRedis{
+		values:   make(map[string]bool),
+		ttls:     make(map[string]string),
+		address:  listener.Addr().String(),
+		password: password,
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.serve(conn)
+		}
+	}()
+	return server
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	f.mu.Lock()
+	f.conns++
+	f.maxConns = max(f.maxConns, f.conns)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.conns--
+		f.mu.Unlock()
+		_ = conn.Close()
+	}()
+	reader := bufio.NewReader(conn)
+	authenticated := f.password == ""
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+		f.mu.Lock()
+		var reply string
+		switch {
+		case args[0] == "AUTH":
+			authenticated = args[1] == f.password
+			reply = redisOKReply
+			if !authenticated {
+				reply = "-WRONGPASS invalid password\r\n"
+			}
+		case !authenticated:
+			reply = "-NOAUTH Authentication required\r\n"
+		case args[0] == "SELECT":
+			f.db = args[1]
+			reply = redisOKReply
+		case args[0] == "EXISTS":
+			reply = ":0\r\n"
+			if f.values[args[1]] {
+				reply = ":1\r\n"
+			}
+		case args[0] == "SET":
+			f.values[args[1]] = true
+			f.ttls[args[1]] = args[4]
+			reply = redisOKReply
+		default:
+			reply = "-ERR unknown command\r\n"
+		}
+		f.mu.Unlock()
+		if _, err := conn.Write([]byte(reply)); err != nil {
+			return
+		}
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimSuffix(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+// fakeConfigMapClient keeps ConfigMaps in memory and can fail updates with conflicts
+type fakeConfigMapClient struct {
+	objects   map[string]*unstructured.Unstructured
+	conflicts int
+}
+
+func newFakeConfigMapClient() *fakeConfigMapClient {
+	return &fakeConfigMapClient{objects: make(map[string]*unstructured.Unstructured)}
+}
+
+func (f *fakeConfigMapClient) GetResource(
+	_ context.Context, gvk schema.GroupVersionKind, namespace, name string, _ transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	obj, ok := f.objects[namespace+"/"+name]
+	if !ok {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (f *fakeConfigMapClient) CreateResource(
+	_ context.Context, obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	f.objects[obj.GetNamespace()+"/"+obj.GetName()] = obj.DeepCopy()
+	return obj, nil
+}
+
+func (f *fakeConfigMapClient) UpdateResource(
+	_ context.Context, obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	if f.conflicts > 0 {
+		f.conflicts--
+		return nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, obj.GetName(),
+			fmt.Errorf("the object has been modified"))
+	}
+	f.objects[obj.GetNamespace()+"/"+obj.GetName()] = obj.DeepCopy()
+	return obj, nil
+}
+
+func (f *fakeConfigMapClient) data() map[string]string {
+	for _, obj := range f.objects {
+		data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+		return data
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package dedup
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is an in-memory LRU of completed events, local to the replica
+type MemoryStore struct {
+	now        func() time.Time
+	entries    map[string]*list.Element
+	order      *list.List
+	ttl        time.Duration
+	maxEntries int
+	mu         sync.Mutex
+}
+
+type memoryEntry struct {
+	expires time.Time
+	key     string
+}
+
+// NewMemoryStore creates a MemoryStore keeping at most maxEntries events for ttl. The
+// least recently used event is evicted when the store is full.
+func NewMemoryStore(ttl time.Duration, maxEntries int) *MemoryStore {
+	return &MemoryStore{
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+		ttl:        ttl,
+		maxEntries: maxEntries,
+	}
+}
+
+// Seen reports whether key was marked within the TTL
+func (s *MemoryStore) Seen(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	elem, ok := s.entries[key]
+	if !ok {
+		return false, nil
+	}
+	if !s.now().Before(entryOf(elem).expires) {
+		s.remove(elem)
+		return false, nil
+	}
+	s.order.MoveToFront(elem)
+	return true, nil
+}
+
+// Mark records key as completed
+func (s *MemoryStore) Mark(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expires := s.now().Add(s.ttl)
+	if elem, ok := s.entries[key]; ok {
+		entryOf(elem).expires = expires
+		s.order.MoveToFront(elem)
+		return nil
+	}
+	s.entries[key] = s.order.PushFront(&memoryEntry{key: key, expires: expires})
+	for s.order.Len() > s.maxEntries {
+		s.remove(s.order.Back())
+	}
+	return nil
+}
+
+// Len returns the number of events kept, including expired ones not yet evicted
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+func (s *MemoryStore) remove(elem *list.Element) {
+	s.order.Remove(elem)
+	delete(s.entries, entryOf(elem).key)
+}
+
+// entryOf returns the entry held by an element of the order list
+func entryOf(elem *list.Element) *memoryEntry {
+	entry, _ := elem.Value.(*memoryEntry) //nolint:errcheck // the list only holds *memoryEntry
+	return entry
+}
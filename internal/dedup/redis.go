@@ -0,0 +1,269 @@
+package dedup
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redisDialTimeout bounds connecting to Redis when the context has no earlier deadline
+const redisDialTimeout = 5 * time.Second
+
+// DefaultRedisPoolSize is the number of connections a RedisStore opens at most by default
+const DefaultRedisPoolSize = 8
+
+// RedisStore keeps completed events in Redis, shared by all replicas. Each event is a key
+// with the TTL as expiry, so Redis drops expired events itself. It speaks the few RESP
+// commands it needs over a small pool of connections, so that events executing on several
+// workers do not wait on each other. A connection is dropped after an error.
+type RedisStore struct {
+	tlsConfig *tls.Config
+	// slots holds a token per open or dialing connection, bounding them to the pool size
+	slots    chan struct{}
+	idle     chan *redisConn
+	address  string
+	password string
+	prefix   string
+	db       int
+	ttl      time.Duration
+	mu       sync.Mutex
+	closed   bool
+}
+
+// RedisOption configures a RedisStore
+type RedisOption func(*RedisStore)
+
+// WithRedisTLS connects to Redis over TLS with config
+func WithRedisTLS(config *tls.Config) RedisOption {
+	return func(s *RedisStore) {
+		s.tlsConfig = config
+	}
+}
+
+// WithRedisPoolSize bounds the connections the store opens; values below one are ignored
+func WithRedisPoolSize(size int) RedisOption {
+	return func(s *RedisStore) {
+		if size > 0 {
+			s.slots = make(chan struct{}, size)
+			s.idle = make(chan *redisConn, size)
+		}
+	}
+}
+
+// NewRedisStore creates a RedisStore for the server at address. Keys are prefixed with
+// prefix. Connections are opened on first use.
+func NewRedisStore(
+	address, password string, db int, prefix string, ttl time.Duration, opts ...RedisOption,
+) *RedisStore {
+	s := &RedisStore{address: address, password: password, db: db, prefix: prefix, ttl: ttl}
+	WithRedisPoolSize(DefaultRedisPoolSize)(s)
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Seen reports whether key exists in Redis
+func (s *RedisStore) Seen(ctx context.Context, key string) (bool, error) {
+	reply, err := s.do(ctx, "EXISTS", s.prefix+key)
+	if err != nil {
+		return false, err
+	}
+	n, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("unexpected redis EXISTS reply %v", reply)
+	}
+	return n > 0, nil
+}
+
+// Mark sets key in Redis with the TTL as expiry
+func (s *RedisStore) Mark(ctx context.Context, key string) error {
+	_, err := s.do(ctx, "SET", s.prefix+key, "1", "PX", strconv.FormatInt(s.ttl.Milliseconds(), 10))
+	return err
+}
+
+// Close closes the idle connections; connections in use are closed when released
+func (s *RedisStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	var errs []error
+	for {
+		select {
+		case conn := <-s.idle:
+			errs = append(errs, conn.close())
+			<-s.slots
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
+
+// do sends a command on a pooled connection and reads its reply. The connection is
+// closed on any error other than a Redis error reply, so the next command starts clean.
+func (s *RedisStore) do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := conn.roundTrip(ctx, args...)
+	var replyErr redisError
+	s.release(conn, err != nil && !errors.As(err, &replyErr))
+	return reply, err
+}
+
+// acquire returns an idle connection, or dials one when the pool has room. It waits for
+// a connection to be released when all of them are in use.
+func (s *RedisStore) acquire(ctx context.Context) (*redisConn, error) {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil, fmt.Errorf("redis store is closed")
+	}
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	default:
+	}
+	select {
+	case conn := <-s.idle:
+		return conn, nil
+	case s.slots <- struct{}{}:
+		conn, err := s.connect(ctx)
+		if err != nil {
+			<-s.slots
+			return nil, err
+		}
+		return conn, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// release returns conn to the pool, or closes it when it is broken or the store is
+// closed. The idle channel holds as many connections as there are slots, so it never blocks.
+func (s *RedisStore) release(conn *redisConn, broken bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if broken || s.closed {
+		_ = conn.close() //nolint:errcheck // the connection is dropped either way
+		<-s.slots
+		return
+	}
+	s.idle <- conn
+}
+
+// connect dials the server, then authenticates and selects the database when configured
+func (s *RedisStore) connect(ctx context.Context) (*redisConn, error) {
+	dialer := &net.Dialer{Timeout: redisDialTimeout}
+	var netConn net.Conn
+	var err error
+	if s.tlsConfig != nil {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: s.tlsConfig}
+		netConn, err = tlsDialer.DialContext(ctx, "tcp", s.address)
+	} else {
+		netConn, err = dialer.DialContext(ctx, "tcp", s.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", s.address, err)
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+	if s.password != "" {
+		if _, err := conn.roundTrip(ctx, "AUTH", s.password); err != nil {
+			return nil, errors.Join(fmt.Errorf("redis authentication failed: %w", err), conn.close())
+		}
+	}
+	if s.db != 0 {
+		if _, err := conn.roundTrip(ctx, "SELECT", strconv.Itoa(s.db)); err != nil {
+			return nil, errors.Join(
+				fmt.Errorf("failed to select redis database %d: %w", s.db, err), conn.close())
+		}
+	}
+	return conn, nil
+}
+
+// redisConn is one connection of the pool
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func (c *redisConn) close() error {
+	return c.conn.Close()
+}
+
+// roundTrip writes a command as a RESP array of bulk strings and reads one reply
+func (c *redisConn) roundTrip(ctx context.Context, args ...string) (interface{}, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(redisDialTimeout)
+	}
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return nil, fmt.Errorf("redis %s failed: %w", args[0], err)
+	}
+	reply, err := readRedisReply(c.reader)
+	if err != nil {
+		return nil, fmt.Errorf("redis %s failed: %w", args[0], err)
+	}
+	return reply, nil
+}
+
+// redisError is an error reply sent by the server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// readRedisReply reads a simple string, error, integer or bulk string reply. A nil bulk
+// string is returned as nil.
+func readRedisReply(r *bufio.Reader) (interface{}, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("empty redis reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply %q", line)
+	}
+}
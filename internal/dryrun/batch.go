@@ -0,0 +1,201 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+)
+
+// ResolveEventFiles returns the event files selected by path, sorted by name. A directory
+// selects the *.json files directly in it, a glob pattern selects its matches, and any
+// other path is a single event file. The bool reports whether path selects a batch.
+func ResolveEventFiles(path string) ([]string, bool, error) {
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		files, globErr := filepath.Glob(filepath.Join(path, "*.json"))
+		if globErr != nil {
+			return nil, true, globErr
+		}
+		if len(files) == 0 {
+			return nil, true, fmt.Errorf("no *.json event files in directory %q", path)
+		}
+		sort.Strings(files)
+		return files, true, nil
+	case err == nil:
+		return []string{path}, false, nil
+	case strings.ContainsAny(path, "*?["):
+		files, globErr := filepath.Glob(path)
+		if globErr != nil {
+			return nil, true, fmt.Errorf("invalid event file pattern %q: %w", path, globErr)
+		}
+		if len(files) == 0 {
+			return nil, true, fmt.Errorf("no event files match %q", path)
+		}
+		sort.Strings(files)
+		return files, true, nil
+	default:
+		// Let the loader report the missing file
+		return []string{path}, false, nil
+	}
+}
+
+// BatchEntry is the outcome of one event file of a batch dry-run. Err is set when the
+// file could not be loaded or executed, in which case Trace is nil.
+type BatchEntry struct {
+	Err   error
+	Trace *ExecutionTrace
+	File  string
+}
+
+// Failed reports whether the event failed to load or its execution failed.
+func (e BatchEntry) Failed() bool {
+	return e.Err != nil || e.Trace == nil || e.Trace.Result.Status == executor.StatusFailed
+}
+
+// BatchReport aggregates the traces of a batch dry-run.
+type BatchReport struct {
+	Entries []BatchEntry
+}
+
+// Failed returns the number of events that failed.
+func (r *BatchReport) Failed() int {
+	failed := 0
+	for _, e := range r.Entries {
+		if e.Failed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+// BatchJSON is the JSON-serializable representation of a batch report, versioned like
+// TraceJSON.
+type BatchJSON struct {
+	SchemaVersion string           `json:"schemaVersion"`
+	Events        []BatchEventJSON `json:"events"`
+	Summary       BatchSummaryJSON `json:"summary"`
+}
+
+// BatchSummaryJSON counts the events of a batch report.
+type BatchSummaryJSON struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// BatchEventJSON is the JSON representation of one event of a batch report.
+type BatchEventJSON struct {
+	Trace  *TraceJSON `json:"trace,omitempty"`
+	File   string     `json:"file"`
+	Status string     `json:"status"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// FormatText formats the batch report as a table with one row per event and the status
+// of each phase, followed by the errors of failed events and a summary line. The full
+// trace of each event is included when verbose.
+func (r *BatchReport) FormatText(verbose bool) string {
+	var b strings.Builder
+	b.WriteString("Dry-Run Batch Report\n")
+	b.WriteString("====================\n")
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	row := func(format string, args ...interface{}) {
+		_, _ = fmt.Fprintf(w, format, args...) //nolint:errcheck // writes to a strings.Builder never fail
+	}
+	row("FILE\tEVENT\tPARAMS\tPRECONDITIONS\tRESOURCES\tPOST ACTIONS\tRESULT\n")
+	for _, e := range r.Entries {
+		name := filepath.Base(e.File)
+		if e.Trace == nil {
+			row("%s\t-\t-\t-\t-\t-\t%s\n", name, statusFailed)
+			continue
+		}
+		phases := phaseStatuses(e.Trace.Result)
+		row("%s\t%s\t%s\t%s\t%s\t%s\t%s\n", name, e.Trace.EventID,
+			phases[0], phases[1], phases[2], phases[3], entryStatus(e))
+	}
+	_ = w.Flush() //nolint:errcheck // writes to a strings.Builder never fail
+
+	for _, e := range r.Entries {
+		if !e.Failed() {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", filepath.Base(e.File))
+		if e.Err != nil {
+			fmt.Fprintf(&b, "  Error: %v\n", e.Err)
+			continue
+		}
+		for _, err := range e.Trace.Result.Errors {
+			fmt.Fprintf(&b, "  Error in %s: %v\n", err.Phase, err)
+		}
+	}
+
+	if verbose {
+		for _, e := range r.Entries {
+			if e.Trace != nil {
+				fmt.Fprintf(&b, "\n--- %s ---\n%s", filepath.Base(e.File), e.Trace.FormatText())
+			}
+		}
+	}
+
+	failed := r.Failed()
+	fmt.Fprintf(&b, "\n%d events: %d succeeded, %d failed\n", len(r.Entries), len(r.Entries)-failed, failed)
+	return b.String()
+}
+
+// FormatJSON formats the batch report as JSON with the full trace of each event.
+func (r *BatchReport) FormatJSON() ([]byte, error) {
+	failed := r.Failed()
+	out := BatchJSON{
+		SchemaVersion: TraceSchemaVersion,
+		Events:        make([]BatchEventJSON, 0, len(r.Entries)),
+		Summary:       BatchSummaryJSON{Total: len(r.Entries), Succeeded: len(r.Entries) - failed, Failed: failed},
+	}
+	for _, e := range r.Entries {
+		event := BatchEventJSON{File: e.File, Status: entryStatus(e)}
+		if e.Err != nil {
+			event.Error = e.Err.Error()
+		}
+		if e.Trace != nil {
+			trace := e.Trace.toJSON()
+			event.Trace = &trace
+		}
+		out.Events = append(out.Events, event)
+	}
+	return json.MarshalIndent(out, "", "  ")
+}
+
+func entryStatus(e BatchEntry) string {
+	if e.Failed() {
+		return statusFailed
+	}
+	return statusSuccess
+}
+
+// phaseStatuses returns the status of the parameter extraction, precondition, resource and
+// post-action phases of a result.
+func phaseStatuses(result *executor.ExecutionResult) [4]string {
+	status := func(phase executor.ExecutionPhase) string {
+		if result.Errors.Phase(phase) != nil {
+			return statusFailed
+		}
+		return statusSuccess
+	}
+	phases := [4]string{
+		status(executor.PhaseParamExtraction),
+		status(executor.PhasePreconditions),
+		status(executor.PhaseResources),
+		status(executor.PhasePostActions),
+	}
+	if phases[2] == statusSuccess && result.ResourcesSkipped {
+		phases[2] = statusSkipped
+	}
+	return phases
+}
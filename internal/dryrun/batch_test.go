@@ -0,0 +1,102 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEventFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"b.json", "a.json", "notes.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0o600))
+	}
+
+	t.Run("single file", func(t *testing.T) {
+		files, batch, err := ResolveEventFiles(filepath.Join(dir, "a.json"))
+		require.NoError(t, err)
+		assert.False(t, batch)
+		assert.Equal(t, []string{filepath.Join(dir, "a.json")}, files)
+	})
+
+	t.Run("directory selects json files in order", func(t *testing.T) {
+		files, batch, err := ResolveEventFiles(dir)
+		require.NoError(t, err)
+		assert.True(t, batch)
+		assert.Equal(t, []string{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")}, files)
+	})
+
+	t.Run("glob", func(t *testing.T) {
+		files, batch, err := ResolveEventFiles(filepath.Join(dir, "b*"))
+		require.NoError(t, err)
+		assert.True(t, batch)
+		assert.Equal(t, []string{filepath.Join(dir, "b.json")}, files)
+	})
+
+	t.Run("glob without matches", func(t *testing.T) {
+		_, _, err := ResolveEventFiles(filepath.Join(dir, "*.yaml"))
+		require.Error(t, err)
+	})
+
+	t.Run("empty directory", func(t *testing.T) {
+		_, _, err := ResolveEventFiles(t.TempDir())
+		require.Error(t, err)
+	})
+
+	t.Run("missing file is left to the loader", func(t *testing.T) {
+		files, batch, err := ResolveEventFiles(filepath.Join(dir, "missing.json"))
+		require.NoError(t, err)
+		assert.False(t, batch)
+		assert.Len(t, files, 1)
+	})
+}
+
+func TestBatchReport(t *testing.T) {
+	failed := makeTestTrace(executor.StatusFailed, false)
+	failed.Result.Errors.Add(executor.PhaseResources, "cm", errors.New("apply failed"))
+	skipped := makeTestTrace(executor.StatusSuccess, false)
+	skipped.Result.ResourcesSkipped = true
+	report := &BatchReport{Entries: []BatchEntry{
+		{File: "/events/ok.json", Trace: makeTestTrace(executor.StatusSuccess, false)},
+		{File: "/events/skipped.json", Trace: skipped},
+		{File: "/events/failed.json", Trace: failed},
+		{File: "/events/broken.json", Err: errors.New("failed to load event")},
+	}}
+
+	assert.Equal(t, 2, report.Failed())
+
+	t.Run("text", func(t *testing.T) {
+		out := report.FormatText(false)
+		assert.Contains(t, out, "Dry-Run Batch Report")
+		assert.Regexp(t, `ok\.json\s+test-event-id\s+SUCCESS\s+SUCCESS\s+SUCCESS\s+SUCCESS\s+SUCCESS`, out)
+		assert.Regexp(t, `skipped\.json\s+test-event-id\s+SUCCESS\s+SUCCESS\s+SKIPPED\s+SUCCESS\s+SUCCESS`, out)
+		assert.Regexp(t, `failed\.json\s+test-event-id\s+SUCCESS\s+SUCCESS\s+FAILED\s+SUCCESS\s+FAILED`, out)
+		assert.Contains(t, out, "Error in resources: cm: apply failed")
+		assert.Contains(t, out, "Error: failed to load event")
+		assert.Contains(t, out, "4 events: 2 succeeded, 2 failed")
+		assert.NotContains(t, out, "Dry-Run Execution Trace")
+
+		assert.Contains(t, report.FormatText(true), "--- ok.json ---\nDry-Run Execution Trace")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := report.FormatJSON()
+		require.NoError(t, err)
+		var out BatchJSON
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, BatchSummaryJSON{Total: 4, Succeeded: 2, Failed: 2}, out.Summary)
+		require.Len(t, out.Events, 4)
+		assert.Equal(t, "SUCCESS", out.Events[0].Status)
+		require.NotNil(t, out.Events[0].Trace)
+		assert.Equal(t, "test-event-id", out.Events[0].Trace.Event.ID)
+		assert.Equal(t, "FAILED", out.Events[3].Status)
+		assert.Nil(t, out.Events[3].Trace)
+		assert.Equal(t, "failed to load event", out.Events[3].Error)
+	})
+}
@@ -3,6 +3,7 @@ package dryrun
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -101,6 +102,15 @@ func (c *DryrunAPIClient) Do(ctx context.Context, req *hyperfleetapi.Request) (*
 		respBody = []byte("{}")
 	} else {
 		dryrunResp := c.nextResponse(ep)
+		if dryrunResp.Error != "" {
+			c.Requests = append(c.Requests, RequestRecord{
+				Method:  req.Method,
+				URL:     req.URL,
+				Headers: req.Headers,
+				Body:    req.Body,
+			})
+			return nil, errors.New(dryrunResp.Error)
+		}
 		statusCode = dryrunResp.StatusCode
 		if statusCode == 0 {
 			statusCode = http.StatusOK
@@ -274,6 +274,37 @@ func TestDo_StatusCodeZeroDefaultsOK(t *testing.T) {
 	assert.Equal(t, "200 OK", resp.Status)
 }
 
+func TestDo_ErrorResponse(t *testing.T) {
+	mrf := &DryrunResponsesFile{
+		Responses: []DryrunEndpoint{
+			{
+				Match: DryrunMatch{Method: "GET", URLPattern: "/api/v1/flaky"},
+				Responses: []DryrunResponse{
+					{Error: "connection reset by peer"},
+					{StatusCode: 200},
+				},
+			},
+		},
+	}
+
+	client, err := NewDryrunAPIClient(mrf)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	req := &hyperfleetapi.Request{Method: "GET", URL: "/api/v1/flaky"}
+
+	resp, err := client.Do(ctx, req)
+	require.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, "connection reset by peer", err.Error())
+
+	resp, err = client.Do(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Len(t, client.Requests, 2)
+	assert.Equal(t, 0, client.Requests[0].StatusCode)
+}
+
 func TestConvenienceMethods(t *testing.T) {
 	tests := []struct {
 		name           string
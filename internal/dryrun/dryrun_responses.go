@@ -26,9 +26,12 @@ type DryrunMatch struct {
 
 // DryrunResponse defines a single dryrun HTTP response.
 type DryrunResponse struct {
-	Headers    map[string]string `json:"headers,omitempty"`
-	Body       interface{}       `json:"body,omitempty"`
-	StatusCode int               `json:"statusCode"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+	// Error fails the request with this message instead of returning a response,
+	// simulating a connection error or timeout
+	Error      string `json:"error,omitempty"`
+	StatusCode int    `json:"statusCode"`
 }
 
 // LoadDryrunResponses reads and parses a dryrun API responses JSON file.
@@ -0,0 +1,157 @@
+package dryrun
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// eventSourcePrefix is the param source prefix that reads from the event data
+const eventSourcePrefix = "event."
+
+// EventContractReport is the result of checking an event against the event.* param
+// sources of a config.
+type EventContractReport struct {
+	EventID    string       `json:"event_id"`
+	EventType  string       `json:"event_type"`
+	Referenced []EventField `json:"referenced"`
+	// Missing lists the referenced fields that are not in the event data
+	Missing []string `json:"missing"`
+	// Extra lists the event data fields that no event.* param source reads
+	Extra []string `json:"extra"`
+	// Valid is false when a required param cannot be resolved from the event
+	Valid bool `json:"valid"`
+}
+
+// EventField is an event data field read by a param
+type EventField struct {
+	Path     string `json:"path"` // e.g. event.owner_references.id
+	Param    string `json:"param"`
+	Required bool   `json:"required"`
+	Found    bool   `json:"found"`
+}
+
+// VerifyEventContract checks that every event.* param source of config resolves in the
+// event data, and lists the event fields that no param reads. A missing field only makes
+// the event invalid when its param is required; optional params fall back to their default.
+func VerifyEventContract(config *configloader.Config, evt *cloudevents.Event) (*EventContractReport, error) {
+	if config == nil || evt == nil {
+		return nil, fmt.Errorf("config and event are required")
+	}
+
+	_, data, err := executor.ParseEventData(evt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event data: %w", err)
+	}
+
+	report := &EventContractReport{
+		EventID:    evt.ID(),
+		EventType:  evt.Type(),
+		Referenced: []EventField{},
+		Missing:    []string{},
+		Extra:      []string{},
+		Valid:      true,
+	}
+
+	var referenced []string
+	for _, param := range config.Params {
+		if !param.Source.IsString() || !strings.HasPrefix(param.Source.StringVal, eventSourcePrefix) {
+			continue
+		}
+		path := strings.TrimPrefix(param.Source.StringVal, eventSourcePrefix)
+		referenced = append(referenced, path)
+
+		_, lookupErr := utils.GetNestedValue(data, path)
+		field := EventField{
+			Path:     param.Source.StringVal,
+			Param:    param.Name,
+			Required: param.Required,
+			Found:    lookupErr == nil,
+		}
+		report.Referenced = append(report.Referenced, field)
+		if !field.Found {
+			report.Missing = append(report.Missing, field.Path)
+			if field.Required {
+				report.Valid = false
+			}
+		}
+	}
+
+	for _, leaf := range leafPaths(data, "") {
+		if !isReferenced(leaf, referenced) {
+			report.Extra = append(report.Extra, eventSourcePrefix+leaf)
+		}
+	}
+	sort.Strings(report.Extra)
+
+	return report, nil
+}
+
+// WriteText writes the report in a human readable layout
+func (r *EventContractReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	out := &utils.ErrWriter{W: tw}
+
+	out.Printf("Event: id=%s type=%s\n", r.EventID, r.EventType)
+
+	out.Printf("\nReferenced fields (%d)\n", len(r.Referenced))
+	for _, f := range r.Referenced {
+		status := "ok"
+		if !f.Found {
+			status = "MISSING"
+		}
+		requirement := "optional"
+		if f.Required {
+			requirement = "required"
+		}
+		out.Printf("  %s\tparam=%s\t%s\t%s\n", f.Path, f.Param, requirement, status)
+	}
+
+	out.Printf("\nExtra fields (%d)\n", len(r.Extra))
+	for _, path := range r.Extra {
+		out.Printf("  %s\n", path)
+	}
+
+	if r.Valid {
+		out.Printf("\nEvent satisfies the adapter contract\n")
+	} else {
+		out.Printf("\nEvent is missing fields required by the adapter\n")
+	}
+	if out.Err != nil {
+		return out.Err
+	}
+	return tw.Flush()
+}
+
+// leafPaths returns the dot-separated paths of the non-map values in data. Lists are
+// leaves: param sources cannot index into them.
+func leafPaths(data map[string]interface{}, prefix string) []string {
+	var paths []string
+	for key, value := range data {
+		path := prefix + key
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			paths = append(paths, leafPaths(nested, path+".")...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// isReferenced reports whether path is read by one of the referenced paths, either
+// directly or as part of a referenced parent object.
+func isReferenced(path string, referenced []string) bool {
+	for _, ref := range referenced {
+		if path == ref || strings.HasPrefix(path, ref+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package dryrun
+
+import (
+	"bytes"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newContractEvent(t *testing.T, data map[string]interface{}) *cloudevents.Event {
+	t.Helper()
+	evt := cloudevents.New()
+	evt.SetID("evt-1")
+	evt.SetType("io.hyperfleet.cluster.updated")
+	evt.SetSource("/test")
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, data))
+	return &evt
+}
+
+func newContractConfig() *configloader.Config {
+	return &configloader.Config{
+		Params: []configloader.Parameter{
+			{Name: "clusterId", Source: configloader.StringSource("event.id"), Required: true},
+			{Name: "generation", Source: configloader.StringSource("event.generation"), Required: true},
+			{Name: "ownerId", Source: configloader.StringSource("event.owner_references.id")},
+			{Name: "region", Source: configloader.StringSource("env.REGION")},
+			{Name: "isReady", Source: configloader.ExpressionSource("true")},
+		},
+	}
+}
+
+func TestVerifyEventContract(t *testing.T) {
+	t.Run("reports extra fields for a valid event", func(t *testing.T) {
+		evt := newContractEvent(t, map[string]interface{}{
+			"id":               "abc123",
+			"generation":       5,
+			"href":             "/api/hyperfleet/v1/clusters/abc123",
+			"owner_references": map[string]interface{}{"id": "owner-1", "kind": "Cluster"},
+		})
+
+		report, err := VerifyEventContract(newContractConfig(), evt)
+
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+		assert.Empty(t, report.Missing)
+		assert.Len(t, report.Referenced, 3)
+		assert.Equal(t, []string{"event.href", "event.owner_references.kind"}, report.Extra)
+	})
+
+	t.Run("missing required field makes the event invalid", func(t *testing.T) {
+		evt := newContractEvent(t, map[string]interface{}{"id": "abc123"})
+
+		report, err := VerifyEventContract(newContractConfig(), evt)
+
+		require.NoError(t, err)
+		assert.False(t, report.Valid)
+		assert.Equal(t, []string{"event.generation", "event.owner_references.id"}, report.Missing)
+		assert.Empty(t, report.Extra)
+	})
+
+	t.Run("missing optional field keeps the event valid", func(t *testing.T) {
+		evt := newContractEvent(t, map[string]interface{}{"id": "abc123", "generation": 1})
+
+		report, err := VerifyEventContract(newContractConfig(), evt)
+
+		require.NoError(t, err)
+		assert.True(t, report.Valid)
+		assert.Equal(t, []string{"event.owner_references.id"}, report.Missing)
+	})
+
+	t.Run("referenced parent object covers its children", func(t *testing.T) {
+		config := &configloader.Config{Params: []configloader.Parameter{
+			{Name: "owner", Source: configloader.StringSource("event.owner_references")},
+		}}
+		evt := newContractEvent(t, map[string]interface{}{
+			"owner_references": map[string]interface{}{"id": "owner-1", "kind": "Cluster"},
+		})
+
+		report, err := VerifyEventContract(config, evt)
+
+		require.NoError(t, err)
+		assert.Empty(t, report.Extra)
+	})
+
+	t.Run("unsupported data content type", func(t *testing.T) {
+		evt := cloudevents.New()
+		evt.SetID("evt-1")
+		evt.SetType("t")
+		evt.SetSource("/test")
+		require.NoError(t, evt.SetData("application/octet-stream", []byte{0x01}))
+
+		_, err := VerifyEventContract(newContractConfig(), &evt)
+
+		require.Error(t, err)
+	})
+}
+
+func TestEventContractReport_WriteText(t *testing.T) {
+	report := &EventContractReport{
+		EventID:   "evt-1",
+		EventType: "io.hyperfleet.cluster.updated",
+		Referenced: []EventField{
+			{Path: "event.id", Param: "clusterId", Required: true, Found: true},
+			{Path: "event.generation", Param: "generation", Required: true},
+		},
+		Missing: []string{"event.generation"},
+		Extra:   []string{"event.href"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+
+	out := buf.String()
+	assert.Contains(t, out, "Event: id=evt-1 type=io.hyperfleet.cluster.updated")
+	assert.Regexp(t, `event\.generation\s+param=generation\s+required\s+MISSING`, out)
+	assert.Contains(t, out, "Extra fields (1)\n  event.href")
+	assert.Contains(t, out, "Event is missing fields required by the adapter")
+}
@@ -105,7 +105,8 @@ func (c *DryrunTransportClient) ApplyResource(
 
 	// Determine operation: create or update
 	var operation manifest.Operation
-	if _, exists := c.resources[key]; exists {
+	existing, exists := c.resources[key]
+	if exists {
 		operation = manifest.OperationUpdate
 	} else {
 		operation = manifest.OperationCreate
@@ -131,6 +132,9 @@ func (c *DryrunTransportClient) ApplyResource(
 		Operation: operation,
 		Reason:    fmt.Sprintf("dry-run %s", operation),
 	}
+	if exists {
+		result.Changes = manifest.Diff(existing, obj)
+	}
 
 	c.Records = append(c.Records, TransportRecord{
 		Operation: operationApply,
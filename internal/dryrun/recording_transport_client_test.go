@@ -85,8 +85,16 @@ func TestApplyResource_UpdateExisting(t *testing.T) {
 	result2, err := client.ApplyResource(ctx, manifestBytes, nil, nil)
 	require.NoError(t, err)
 	assert.Equal(t, manifest.OperationUpdate, result2.Operation)
+	assert.Empty(t, result2.Changes, "an unchanged manifest has no changes")
 
-	require.Len(t, client.Records, 2)
+	// Third apply: update with a changed field.
+	changed := makeManifest("v1", "ConfigMap", "default", "my-cm")
+	changed = []byte(strings.Replace(string(changed), `"kind"`, `"data":{"key":"v"},"kind"`, 1))
+	result3, err := client.ApplyResource(ctx, changed, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []manifest.FieldChange{{Path: "data", New: map[string]interface{}{"key": "v"}}}, result3.Changes)
+
+	require.Len(t, client.Records, 3)
 }
 
 func TestApplyResource_RecreateOnChange(t *testing.T) {
@@ -0,0 +1,19 @@
+package dryrun
+
+import _ "embed"
+
+// TraceSchemaVersion is the version of the JSON trace and batch report format, MAJOR.MINOR.
+// The minor version is bumped when properties are added, which consumers must ignore when
+// they do not know them. The major version is bumped when a property is removed or renamed,
+// or its type or meaning changes; the previous major version is then documented as
+// deprecated for at least one release before the change.
+const TraceSchemaVersion = "1.0"
+
+//go:embed schema/trace.schema.json
+var traceSchema []byte
+
+// TraceSchema returns the JSON Schema (draft 2020-12) of the traces and batch reports
+// produced by FormatJSON
+func TraceSchema() []byte {
+	return traceSchema
+}
@@ -0,0 +1,109 @@
+package dryrun
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+)
+
+type schemaObject struct {
+	Properties map[string]json.RawMessage `json:"properties"`
+	Required   []string                   `json:"required"`
+}
+
+// jsonFields returns the JSON property names of a struct type, and the ones that are
+// always present
+func jsonFields(typ reflect.Type) (all, required []string) {
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("json")
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			continue
+		}
+		all = append(all, name)
+		if !strings.Contains(opts, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(all)
+	sort.Strings(required)
+	return all, required
+}
+
+// TestTraceSchema_MatchesTypes guards the compatibility of the trace format: changing the
+// JSON fields of a trace type fails until the schema, and its version, are updated too.
+func TestTraceSchema_MatchesTypes(t *testing.T) {
+	var schema struct {
+		Defs map[string]schemaObject `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(TraceSchema(), &schema))
+
+	inline := func(def, property string) schemaObject {
+		var obj schemaObject
+		require.NoError(t, json.Unmarshal(schema.Defs[def].Properties[property], &obj))
+		return obj
+	}
+	types := map[string]struct {
+		typ reflect.Type
+		obj schemaObject
+	}{
+		"batch":              {reflect.TypeOf(BatchJSON{}), schema.Defs["batch"]},
+		"batch.summary":      {reflect.TypeOf(BatchSummaryJSON{}), inline("batch", "summary")},
+		"batchEvent":         {reflect.TypeOf(BatchEventJSON{}), schema.Defs["batchEvent"]},
+		"trace":              {reflect.TypeOf(TraceJSON{}), schema.Defs["trace"]},
+		"trace.event":        {reflect.TypeOf(TraceEvent{}), inline("trace", "event")},
+		"precondition":       {reflect.TypeOf(TracePrecondition{}), schema.Defs["precondition"]},
+		"resource":           {reflect.TypeOf(TraceResource{}), schema.Defs["resource"]},
+		"fieldChange":        {reflect.TypeOf(TraceFieldChange{}), schema.Defs["fieldChange"]},
+		"postAction":         {reflect.TypeOf(TracePostAction{}), schema.Defs["postAction"]},
+		"error":              {reflect.TypeOf(TraceError{}), schema.Defs["error"]},
+		"warning":            {reflect.TypeOf(TraceWarning{}), schema.Defs["warning"]},
+		"apiRequest":         {reflect.TypeOf(TraceAPIRequest{}), schema.Defs["apiRequest"]},
+		"transportOperation": {reflect.TypeOf(TraceTransportOp{}), schema.Defs["transportOperation"]},
+	}
+	for name, tt := range types {
+		t.Run(name, func(t *testing.T) {
+			all, required := jsonFields(tt.typ)
+			properties := make([]string, 0, len(tt.obj.Properties))
+			for p := range tt.obj.Properties {
+				properties = append(properties, p)
+			}
+			sort.Strings(properties)
+			sort.Strings(tt.obj.Required)
+			assert.Equal(t, all, properties, "properties of %s", tt.typ.Name())
+			assert.Equal(t, required, tt.obj.Required, "required properties of %s", tt.typ.Name())
+		})
+	}
+}
+
+func TestTraceSchema_Version(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Pattern string `json:"pattern"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(TraceSchema(), &schema))
+	pattern := regexp.MustCompile(schema.Defs["schemaVersion"].Pattern)
+	assert.True(t, pattern.MatchString(TraceSchemaVersion), "the schema should accept version %s", TraceSchemaVersion)
+
+	trace := makeTestTrace(executor.StatusSuccess, false)
+	data, err := trace.FormatJSON()
+	require.NoError(t, err)
+	var single map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &single))
+	assert.Equal(t, TraceSchemaVersion, single["schemaVersion"])
+
+	data, err = (&BatchReport{Entries: []BatchEntry{{File: "event.json", Trace: trace}}}).FormatJSON()
+	require.NoError(t, err)
+	var batch map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &batch))
+	assert.Equal(t, TraceSchemaVersion, batch["schemaVersion"])
+}
@@ -0,0 +1,34 @@
+package dryrun
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SecretLookup records a secret requested through the dryrun secret provider
+type SecretLookup struct {
+	Path string
+	Key  string
+}
+
+// DryrunSecretProvider resolves vault.* param sources without contacting Vault. Every
+// lookup returns a placeholder naming the secret, so rendered output never contains
+// real credentials. All lookups are recorded.
+type DryrunSecretProvider struct {
+	Lookups []SecretLookup
+	mu      sync.Mutex
+}
+
+// NewDryrunSecretProvider creates a DryrunSecretProvider
+func NewDryrunSecretProvider() *DryrunSecretProvider {
+	return &DryrunSecretProvider{}
+}
+
+// GetSecret returns a placeholder for the secret at path and key
+func (p *DryrunSecretProvider) GetSecret(_ context.Context, path, key string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Lookups = append(p.Lookups, SecretLookup{Path: path, Key: key})
+	return fmt.Sprintf("<dryrun-secret:%s#%s>", path, key), nil
+}
@@ -0,0 +1,18 @@
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryrunSecretProvider_GetSecret(t *testing.T) {
+	provider := NewDryrunSecretProvider()
+
+	value, err := provider.GetSecret(context.Background(), "secret/clusters/creds", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "<dryrun-secret:secret/clusters/creds#password>", value)
+	assert.Equal(t, []SecretLookup{{Path: "secret/clusters/creds", Key: "password"}}, provider.Lookups)
+}
@@ -7,11 +7,13 @@ import (
 	"strings"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
 )
 
 const (
 	statusSuccess = "SUCCESS"
 	statusFailed  = "FAILED"
+	statusSkipped = "SKIPPED"
 )
 
 // ExecutionTrace contains all data needed to produce the trace output.
@@ -24,16 +26,20 @@ type ExecutionTrace struct {
 	Verbose   bool
 }
 
-// TraceJSON is the JSON-serializable representation of the execution trace.
+// TraceJSON is the JSON-serializable representation of the execution trace. Its format is
+// versioned by SchemaVersion and described by TraceSchema: the trace types are kept apart
+// from the executor types, so that internal changes do not change the format.
 type TraceJSON struct {
 	Event               TraceEvent             `json:"event"`
+	SchemaVersion       string                 `json:"schemaVersion"`
 	Status              string                 `json:"status"`
 	Params              map[string]interface{} `json:"params,omitempty"`
 	Preconditions       []TracePrecondition    `json:"preconditions,omitempty"`
 	Resources           []TraceResource        `json:"resources,omitempty"`
 	DiscoveredResources map[string]interface{} `json:"discoveredResources,omitempty"`
 	PostActions         []TracePostAction      `json:"postActions,omitempty"`
-	Errors              map[string]string      `json:"errors,omitempty"`
+	Errors              []TraceError           `json:"errors,omitempty"`
+	Warnings            []TraceWarning         `json:"warnings,omitempty"`
 	APIRequests         []TraceAPIRequest      `json:"apiRequests,omitempty"`
 	TransportOps        []TraceTransportOp     `json:"transportOperations,omitempty"`
 }
@@ -63,6 +69,15 @@ type TraceResource struct {
 	Operation       string                 `json:"operation"`
 	Reason          string                 `json:"reason,omitempty"`
 	Error           string                 `json:"error,omitempty"`
+	Changes         []TraceFieldChange     `json:"changes,omitempty"`
+	Drift           []TraceFieldChange     `json:"drift,omitempty"`
+}
+
+// TraceFieldChange is the JSON representation of a field an update changes or that drifted.
+type TraceFieldChange struct {
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new"`
+	Path string      `json:"path"`
 }
 
 // TracePostAction is the JSON representation of a post-action result.
@@ -73,6 +88,23 @@ type TracePostAction struct {
 	Skipped bool   `json:"skipped,omitempty"`
 }
 
+// TraceError is the JSON representation of an execution error.
+type TraceError struct {
+	Phase     string   `json:"phase"`
+	Step      string   `json:"step,omitempty"`
+	Category  string   `json:"category"`
+	Message   string   `json:"message"`
+	Causes    []string `json:"causes,omitempty"`
+	Retryable bool     `json:"retryable"`
+}
+
+// TraceWarning is the JSON representation of an execution warning.
+type TraceWarning struct {
+	Phase   string `json:"phase"`
+	Step    string `json:"step,omitempty"`
+	Message string `json:"message"`
+}
+
 // TraceAPIRequest is the JSON representation of a recorded API request.
 type TraceAPIRequest struct {
 	Request    string `json:"requestBody,omitempty"`
@@ -89,6 +121,8 @@ type TraceTransportOp struct {
 	Namespace string `json:"namespace,omitempty"`
 	Name      string `json:"name"`
 	Result    string `json:"result,omitempty"`
+	// Manifest is the rendered manifest of an apply, in verbose traces only
+	Manifest json.RawMessage `json:"manifest,omitempty"`
 }
 
 // FormatText formats the execution trace as human-readable text.
@@ -117,7 +151,7 @@ func (t *ExecutionTrace) FormatText() string {
 
 	// Phase 1: Parameter Extraction
 	paramStatus := statusSuccess
-	if _, ok := result.Errors[executor.PhaseParamExtraction]; ok {
+	if result.Errors.Phase(executor.PhaseParamExtraction) != nil {
 		paramStatus = statusFailed
 	}
 	fmt.Fprintf(&b, "Phase 1: Parameter Extraction .............. %s\n", paramStatus)
@@ -138,14 +172,16 @@ func (t *ExecutionTrace) FormatText() string {
 			}
 		}
 	} else {
-		fmt.Fprintf(&b, "  Error: %v\n", result.Errors[executor.PhaseParamExtraction])
+		for _, err := range result.Errors.ForPhase(executor.PhaseParamExtraction) {
+			fmt.Fprintf(&b, "  Error: %v\n", err)
+		}
 	}
 	b.WriteString("\n")
 
 	// Phase 2: Preconditions
 	precondStatus := statusSuccess
 	precondDetail := ""
-	_, hasPrecondErr := result.Errors[executor.PhasePreconditions]
+	hasPrecondErr := result.Errors.Phase(executor.PhasePreconditions) != nil
 	switch {
 	case hasPrecondErr:
 		precondStatus = statusFailed
@@ -197,10 +233,10 @@ func (t *ExecutionTrace) FormatText() string {
 
 	// Phase 3: Resources
 	resStatus := statusSuccess
-	if _, ok := result.Errors[executor.PhaseResources]; ok {
+	if result.Errors.Phase(executor.PhaseResources) != nil {
 		resStatus = statusFailed
 	} else if result.ResourcesSkipped {
-		resStatus = "SKIPPED"
+		resStatus = statusSkipped
 	}
 	fmt.Fprintf(&b, "Phase 3: Resources ........................ %s\n", resStatus)
 
@@ -219,6 +255,19 @@ func (t *ExecutionTrace) FormatText() string {
 			fmt.Fprintf(&b, "  [%d/%d] %-30s %s\n", i+1, len(result.ResourceResults), rr.Name, status)
 			fmt.Fprintf(&b, "    Kind: %-12s Namespace: %-12s Name: %s\n", rr.Kind, rr.Namespace, rr.ResourceName)
 
+			if len(rr.Changes) > 0 {
+				fmt.Fprintf(&b, "    Changes (%d):\n", len(rr.Changes))
+				for _, c := range rr.Changes {
+					fmt.Fprintf(&b, "      %s\n", c)
+				}
+			}
+			if len(rr.Drift) > 0 {
+				fmt.Fprintf(&b, "    Drift (%d):\n", len(rr.Drift))
+				for _, c := range rr.Drift {
+					fmt.Fprintf(&b, "      %s\n", c)
+				}
+			}
+
 			if rr.DiscoveredState != nil && rr.DiscoveredState.Object != nil {
 				if stateBytes, err := json.Marshal(rr.DiscoveredState.Object); err == nil {
 					fmt.Fprintf(&b, "    Pre-delete state:\n      %s\n", prettyJSON(stateBytes))
@@ -268,7 +317,7 @@ func (t *ExecutionTrace) FormatText() string {
 	// Phase 4: Post Actions
 	postStatus := statusSuccess
 	postDetail := ""
-	if _, ok := result.Errors[executor.PhasePostActions]; ok {
+	if result.Errors.Phase(executor.PhasePostActions) != nil {
 		postStatus = statusFailed
 	} else if result.ResourcesSkipped && result.SkipReason == executor.ResourceNotFoundReason &&
 		len(result.PostActionResults) > 0 {
@@ -279,7 +328,7 @@ func (t *ExecutionTrace) FormatText() string {
 	for i, pa := range result.PostActionResults {
 		status := "EXECUTED"
 		if pa.Skipped {
-			status = "SKIPPED"
+			status = statusSkipped
 		} else if pa.Status == executor.StatusFailed {
 			status = statusFailed
 		}
@@ -309,6 +358,19 @@ func (t *ExecutionTrace) FormatText() string {
 	}
 	b.WriteString("\n")
 
+	// Warnings
+	if len(result.Warnings) > 0 {
+		fmt.Fprintf(&b, "Warnings (%d)\n", len(result.Warnings))
+		for _, w := range result.Warnings {
+			step := ""
+			if w.Step != "" {
+				step = "[" + w.Step + "]"
+			}
+			fmt.Fprintf(&b, "  %s%s: %s\n", w.Phase, step, w.Message)
+		}
+		b.WriteString("\n")
+	}
+
 	// Final result
 	resultStr := statusSuccess
 	if result.Status == executor.StatusFailed {
@@ -321,12 +383,18 @@ func (t *ExecutionTrace) FormatText() string {
 
 // FormatJSON formats the execution trace as JSON.
 func (t *ExecutionTrace) FormatJSON() ([]byte, error) {
+	return json.MarshalIndent(t.toJSON(), "", "  ")
+}
+
+// toJSON builds the JSON-serializable representation of the trace.
+func (t *ExecutionTrace) toJSON() TraceJSON {
 	result := t.Result
 
 	trace := TraceJSON{
-		Event:  TraceEvent{ID: t.EventID, Type: t.EventType},
-		Status: string(result.Status),
-		Params: result.Params,
+		SchemaVersion: TraceSchemaVersion,
+		Event:         TraceEvent{ID: t.EventID, Type: t.EventType},
+		Status:        string(result.Status),
+		Params:        result.Params,
 	}
 
 	// Discovered resources (from discovery phase, used in payload CEL)
@@ -360,6 +428,8 @@ func (t *ExecutionTrace) FormatJSON() ([]byte, error) {
 			Status:    string(rr.Status),
 			Operation: string(rr.Operation),
 			Reason:    rr.OperationReason,
+			Changes:   traceFieldChanges(rr.Changes),
+			Drift:     traceFieldChanges(rr.Drift),
 		}
 		if rr.DiscoveredState != nil && rr.DiscoveredState.Object != nil {
 			tr.DiscoveredState = rr.DiscoveredState.Object
@@ -384,11 +454,18 @@ func (t *ExecutionTrace) FormatJSON() ([]byte, error) {
 	}
 
 	// Errors
-	if len(result.Errors) > 0 {
-		trace.Errors = make(map[string]string)
-		for phase, err := range result.Errors {
-			trace.Errors[string(phase)] = err.Error()
-		}
+	for _, e := range result.Errors {
+		trace.Errors = append(trace.Errors, TraceError{
+			Phase:     string(e.Phase),
+			Step:      e.Step,
+			Category:  string(e.Category),
+			Message:   e.Message,
+			Causes:    e.Causes,
+			Retryable: e.Retryable,
+		})
+	}
+	for _, w := range result.Warnings {
+		trace.Warnings = append(trace.Warnings, TraceWarning{Phase: string(w.Phase), Step: w.Step, Message: w.Message})
 	}
 
 	// API Requests
@@ -420,10 +497,25 @@ func (t *ExecutionTrace) FormatJSON() ([]byte, error) {
 		if rec.Result != nil {
 			op.Result = string(rec.Result.Operation)
 		}
+		if t.Verbose && rec.Operation == operationApply && json.Valid(rec.Manifest) {
+			op.Manifest = rec.Manifest
+		}
 		trace.TransportOps = append(trace.TransportOps, op)
 	}
 
-	return json.MarshalIndent(trace, "", "  ")
+	return trace
+}
+
+// traceFieldChanges converts field changes to their JSON representation
+func traceFieldChanges(changes []manifest.FieldChange) []TraceFieldChange {
+	if len(changes) == 0 {
+		return nil
+	}
+	out := make([]TraceFieldChange, len(changes))
+	for i, c := range changes {
+		out[i] = TraceFieldChange{Old: c.Old, New: c.New, Path: c.Path}
+	}
+	return out
 }
 
 // prettyJSON attempts to indent raw JSON bytes for readable output using a 6-space prefix.
@@ -21,7 +21,6 @@ func makeTestTrace(status executor.ExecutionStatus, verbose bool) *ExecutionTrac
 		Result: &executor.ExecutionResult{
 			Status: status,
 			Params: map[string]interface{}{"key": "value"},
-			Errors: make(map[executor.ExecutionPhase]error),
 		},
 		APIClient: apiClient,
 		Transport: transport,
@@ -64,7 +63,7 @@ func TestFormatText_Success(t *testing.T) {
 func TestFormatText_Failed(t *testing.T) {
 	t.Run("failed execution trace shows FAILED result and error in resource result", func(t *testing.T) {
 		trace := makeTestTrace(executor.StatusFailed, false)
-		trace.Result.Errors[executor.PhaseResources] = fmt.Errorf("resource apply failed: connection refused")
+		trace.Result.Errors.Add(executor.PhaseResources, "", fmt.Errorf("resource apply failed: connection refused"))
 		trace.Result.ResourceResults = []executor.ResourceResult{
 			{
 				Name:         "failing-resource",
@@ -204,6 +203,52 @@ func TestFormatJSON_Structure(t *testing.T) {
 	})
 }
 
+func TestFormatTrace_Warnings(t *testing.T) {
+	trace := makeTestTrace(executor.StatusSuccess, false)
+	trace.Result.Warnings = []executor.ExecutionWarning{
+		{Phase: executor.PhasePreconditions, Step: "check-exists", Message: "failed to capture 'phase': not found"},
+	}
+
+	output := trace.FormatText()
+	assert.Contains(t, output, "Warnings (1)")
+	assert.Contains(t, output, "preconditions[check-exists]: failed to capture 'phase': not found")
+
+	data, err := trace.FormatJSON()
+	require.NoError(t, err)
+	var result TraceJSON
+	require.NoError(t, json.Unmarshal(data, &result))
+	assert.Equal(t, []TraceWarning{
+		{Phase: "preconditions", Step: "check-exists", Message: "failed to capture 'phase': not found"},
+	}, result.Warnings)
+
+	trace.Result.Warnings = nil
+	assert.NotContains(t, trace.FormatText(), "Warnings")
+}
+
+func TestFormatTrace_Changes(t *testing.T) {
+	trace := makeTestTrace(executor.StatusSuccess, false)
+	trace.Result.ResourceResults = []executor.ResourceResult{
+		{
+			Name:         "my-resource",
+			Kind:         "ConfigMap",
+			ResourceName: "my-configmap",
+			Status:       executor.StatusSuccess,
+			Operation:    manifest.OperationUpdate,
+			Changes:      []manifest.FieldChange{{Path: "data.key", Old: "a", New: "b"}},
+		},
+	}
+
+	output := trace.FormatText()
+	assert.Contains(t, output, "Changes (1):\n      data.key: \"a\" -> \"b\"")
+
+	data, err := trace.FormatJSON()
+	require.NoError(t, err)
+	var result TraceJSON
+	require.NoError(t, json.Unmarshal(data, &result))
+	require.Len(t, result.Resources, 1)
+	assert.Equal(t, []TraceFieldChange{{Path: "data.key", Old: "a", New: "b"}}, result.Resources[0].Changes)
+}
+
 func TestFormatJSON_VerboseIncludesBodies(t *testing.T) {
 	t.Run("verbose JSON includes request and response bodies", func(t *testing.T) {
 		trace := makeTestTrace(executor.StatusSuccess, true)
@@ -0,0 +1,76 @@
+package dryrun
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// DefaultUIAddress is where the dry-run trace UI listens by default: local only, since
+// traces hold rendered manifests and API bodies
+const DefaultUIAddress = "127.0.0.1:8765"
+
+//go:embed ui/index.html
+var uiPage []byte
+
+// NewUIHandler returns the handler of the dry-run trace UI: the page at / and the report
+// it renders, in the format of BatchReport.FormatJSON, at /trace.json.
+func NewUIHandler(report *BatchReport) (http.Handler, error) {
+	data, err := report.FormatJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to format traces for the UI: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(uiPage) //nolint:errcheck // best-effort response
+	})
+	mux.HandleFunc("/trace.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data) //nolint:errcheck // best-effort response
+	})
+	return mux, nil
+}
+
+// ServeUI serves the trace UI of report on addr until ctx is done. ready, when not nil, is
+// called with the URL of the UI once it is listening.
+func ServeUI(ctx context.Context, addr string, report *BatchReport, ready func(url string)) error {
+	handler, err := NewUIHandler(report)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+	if ready != nil {
+		ready("http://" + listener.Addr().String())
+	}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.Serve(listener) }()
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
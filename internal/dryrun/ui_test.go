@@ -0,0 +1,73 @@
+package dryrun
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUIHandler(t *testing.T) {
+	trace := makeTestTrace(executor.StatusSuccess, true)
+	_, err := trace.Transport.ApplyResource(context.Background(),
+		makeManifest("v1", "ConfigMap", "default", "my-cm"), nil, nil)
+	require.NoError(t, err)
+	trace.Result.ResourceResults = []executor.ResourceResult{{
+		Name: "my-resource", Kind: "ConfigMap", Namespace: "default", ResourceName: "my-cm",
+		Status: executor.StatusSuccess, Operation: manifest.OperationCreate,
+	}}
+	handler, err := NewUIHandler(&BatchReport{Entries: []BatchEntry{{File: "events/create.json", Trace: trace}}})
+	require.NoError(t, err)
+
+	get := func(path string) *http.Response {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Result()
+	}
+
+	page := get("/")
+	assert.Equal(t, http.StatusOK, page.StatusCode)
+	assert.Contains(t, page.Header.Get("Content-Type"), "text/html")
+	body, err := io.ReadAll(page.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `fetch("trace.json")`)
+
+	data := get("/trace.json")
+	assert.Equal(t, "application/json", data.Header.Get("Content-Type"))
+	var report BatchJSON
+	require.NoError(t, json.NewDecoder(data.Body).Decode(&report))
+	require.Len(t, report.Events, 1)
+	assert.Equal(t, "events/create.json", report.Events[0].File)
+	require.NotNil(t, report.Events[0].Trace)
+	require.Len(t, report.Events[0].Trace.TransportOps, 1)
+	assert.JSONEq(t, string(makeManifest("v1", "ConfigMap", "default", "my-cm")),
+		string(report.Events[0].Trace.TransportOps[0].Manifest), "verbose traces carry the rendered manifests")
+
+	assert.Equal(t, http.StatusNotFound, get("/other").StatusCode)
+}
+
+func TestServeUI(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	urls := make(chan string, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeUI(ctx, "127.0.0.1:0", &BatchReport{}, func(url string) { urls <- url })
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, <-urls+"/trace.json", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck // test
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	cancel()
+	require.NoError(t, <-done)
+}
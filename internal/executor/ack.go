@@ -0,0 +1,112 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dedup"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+)
+
+// Redelivery detection: delivered event IDs are remembered for redeliveryWindow, up to
+// redeliveryMaxEvents
+const (
+	redeliveryWindow    = time.Hour
+	redeliveryMaxEvents = 10000
+)
+
+// Acknowledger hands broker messages to a handler according to the ack mode. The broker
+// acknowledges a message when the handler returns nil, so the mode decides what returning
+// means:
+//
+//   - configloader.AckModeAfterExecute: Handle returns once the event has executed. An
+//     event interrupted by a crash or shutdown is redelivered (at-least-once).
+//   - configloader.AckModeBeforeExecute: Handle returns once the event is queued on the
+//     worker pool. An event is never redelivered, but is lost if the adapter dies before
+//     executing it (at-most-once).
+//
+// Events are executed on a worker pool, one at a time per cluster, when there is more than one
+// worker or the mode is before_execute. Deliveries of an event ID already delivered within
+// the last hour are counted as redeliveries.
+type Acknowledger struct {
+	handler    func(ctx context.Context, evt *event.Event) error
+	pool       *WorkerPool
+	deliveries *dedup.MemoryStore
+	metrics    *metrics.Recorder
+	log        logger.Logger
+	mode       string
+}
+
+// NewAcknowledger creates an Acknowledger running h with the given ack mode and number of
+// workers. Wrap h with AlwaysAck so that failed executions are acknowledged too.
+func NewAcknowledger(
+	h func(ctx context.Context, evt *event.Event) error,
+	mode string,
+	workers int,
+	recorder *metrics.Recorder,
+	log logger.Logger,
+) *Acknowledger {
+	a := &Acknowledger{
+		handler:    h,
+		deliveries: dedup.NewMemoryStore(redeliveryWindow, redeliveryMaxEvents),
+		metrics:    recorder,
+		log:        log,
+		mode:       mode,
+	}
+	if mode == configloader.AckModeBeforeExecute || workers > 1 {
+		a.pool = NewWorkerPool(h, workers, log)
+	}
+	recorder.SetAckMode(mode)
+	return a
+}
+
+// Mode returns the ack mode
+func (a *Acknowledger) Mode() string {
+	return a.mode
+}
+
+// Workers returns the number of workers executing events, 0 when events execute on the
+// broker's goroutine
+func (a *Acknowledger) Workers() int {
+	if a.pool == nil {
+		return 0
+	}
+	return len(a.pool.queues)
+}
+
+// Handle is the broker handler. Its error makes the broker redeliver the message.
+func (a *Acknowledger) Handle(ctx context.Context, evt *event.Event) error {
+	a.recordDelivery(ctx, evt)
+	switch {
+	case a.mode == configloader.AckModeBeforeExecute:
+		return a.pool.Handle(ctx, evt)
+	case a.pool != nil:
+		return a.pool.HandleAndWait(ctx, evt)
+	default:
+		return a.handler(ctx, evt)
+	}
+}
+
+// Close waits until the queued events have been handled or ctx is done
+func (a *Acknowledger) Close(ctx context.Context) error {
+	if a.pool == nil {
+		return nil
+	}
+	return a.pool.Close(ctx)
+}
+
+// recordDelivery counts the delivery of an event ID that was already delivered
+func (a *Acknowledger) recordDelivery(ctx context.Context, evt *event.Event) {
+	if evt.ID() == "" {
+		return
+	}
+	//nolint:errcheck // the memory store never fails
+	if seen, _ := a.deliveries.Seen(ctx, evt.ID()); seen {
+		a.metrics.RecordRedelivery()
+		a.log.Infof(ctx, "Event %s redelivered by the broker (ack mode %s)", evt.ID(), a.mode)
+	}
+	_ = a.deliveries.Mark(ctx, evt.ID()) //nolint:errcheck // the memory store never fails
+}
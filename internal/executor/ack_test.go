@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+)
+
+func TestAcknowledger_AfterExecute(t *testing.T) {
+	for _, workers := range []int{0, 2} {
+		var executed atomic.Bool
+		handler := func(_ context.Context, _ *event.Event) error {
+			time.Sleep(10 * time.Millisecond)
+			executed.Store(true)
+			return nil
+		}
+		a := NewAcknowledger(handler, configloader.AckModeAfterExecute, workers, nil, logger.NewTestLogger())
+		if workers > 1 {
+			assert.Equal(t, workers, a.Workers())
+		} else {
+			assert.Equal(t, 0, a.Workers(), "events execute on the broker's goroutine")
+		}
+
+		require.NoError(t, a.Handle(context.Background(), newPoolEvent(t, "e1", map[string]interface{}{"id": "c"})))
+		assert.True(t, executed.Load(), "Handle must return after execution with %d workers", workers)
+		require.NoError(t, a.Close(context.Background()))
+	}
+}
+
+func TestAcknowledger_AfterExecuteWaitsOnCancel(t *testing.T) {
+	var executions atomic.Int32
+	handler := func(_ context.Context, _ *event.Event) error {
+		executions.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}
+	a := NewAcknowledger(handler, configloader.AckModeAfterExecute, 2, nil, logger.NewTestLogger())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := a.Handle(ctx, newPoolEvent(t, "e1", map[string]interface{}{"id": "c"}))
+	require.NoError(t, err, "a queued event must be acked once it executed, not redelivered")
+	require.NoError(t, a.Close(context.Background()))
+	assert.Equal(t, int32(1), executions.Load())
+}
+
+func TestAcknowledger_BeforeExecute(t *testing.T) {
+	release := make(chan struct{})
+	var executed atomic.Bool
+	handler := func(_ context.Context, _ *event.Event) error {
+		<-release
+		executed.Store(true)
+		return nil
+	}
+	a := NewAcknowledger(handler, configloader.AckModeBeforeExecute, 0, nil, logger.NewTestLogger())
+	assert.Equal(t, 1, a.Workers(), "before_execute always queues on a worker")
+
+	require.NoError(t, a.Handle(context.Background(), newPoolEvent(t, "e1", map[string]interface{}{"id": "c"})))
+	assert.False(t, executed.Load(), "Handle must return before execution")
+
+	close(release)
+	require.NoError(t, a.Close(context.Background()))
+	assert.True(t, executed.Load())
+}
+
+func TestAcknowledger_CountsRedeliveries(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test", "v0.1.0", "test-adapter", registry)
+	a := NewAcknowledger(func(context.Context, *event.Event) error { return nil },
+		configloader.AckModeAfterExecute, 0, recorder, logger.NewTestLogger())
+
+	for _, id := range []string{"e1", "e2", "e1", "e1"} {
+		require.NoError(t, a.Handle(context.Background(), newPoolEvent(t, id, map[string]interface{}{"id": "c"})))
+	}
+
+	assert.Equal(t, 2.0, gatherCounter(t, registry, "hyperfleet_adapter_event_redeliveries_total"))
+}
+
+// gatherCounter returns the value of the unlabeled counter name in registry
+func gatherCounter(t *testing.T, registry *prometheus.Registry, name string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		if f.GetName() == name {
+			return f.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	t.Fatalf("metric %s not found", name)
+	return 0
+}
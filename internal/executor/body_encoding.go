@@ -0,0 +1,212 @@
+package executor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// maxFilePartSize caps a file part read from disk
+const maxFilePartSize = 32 << 20
+
+// formField is a field of a form or multipart body
+type formField struct {
+	name  string
+	value string
+}
+
+// filePart is a rendered file part of a multipart body
+type filePart struct {
+	name        string
+	filename    string
+	contentType string
+	content     []byte
+}
+
+// bodyEncoder encodes the fields and files of an api_call body and returns the body
+// with its Content-Type
+type bodyEncoder func(fields []formField, files []filePart) ([]byte, string, error)
+
+// bodyEncoders are the encoders of the body_encoding values other than json, which
+// sends the rendered body unchanged
+var bodyEncoders = map[string]bodyEncoder{
+	configloader.BodyEncodingForm:      encodeForm,
+	configloader.BodyEncodingMultipart: encodeMultipart,
+}
+
+// renderAPICallBody renders the body of an api_call and encodes it with its
+// body_encoding. contentType is empty for json bodies, which keep the client default.
+func renderAPICallBody(
+	apiCall *configloader.APICall,
+	params map[string]interface{},
+) (body []byte, contentType string, err error) {
+	body = []byte(apiCall.Body)
+	if apiCall.Body != "" {
+		body, err = utils.RenderTemplateBytes(apiCall.Body, params)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to render body template: %w", err)
+		}
+	}
+	encode, ok := bodyEncoders[apiCall.BodyEncoding]
+	if !ok {
+		return body, "", nil
+	}
+
+	fields, err := bodyFields(body)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s body: %w", apiCall.BodyEncoding, err)
+	}
+	files := make([]filePart, 0, len(apiCall.Files))
+	for i := range apiCall.Files {
+		file, err := renderFilePart(&apiCall.Files[i], params)
+		if err != nil {
+			return nil, "", fmt.Errorf("file %q: %w", apiCall.Files[i].Name, err)
+		}
+		files = append(files, file)
+	}
+	return encode(fields, files)
+}
+
+// bodyFields returns the form fields of a rendered body, which must be a JSON object.
+// Fields are sorted by name. Lists become one field per item, objects are sent as JSON
+// and null as an empty value.
+func bodyFields(body []byte) ([]formField, error) {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, fmt.Errorf("body must render to a JSON object: %w", err)
+	}
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var fields []formField
+	for _, name := range names {
+		values, isList := obj[name].([]interface{})
+		if !isList {
+			values = []interface{}{obj[name]}
+		}
+		for _, v := range values {
+			value, err := formValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", name, err)
+			}
+			fields = append(fields, formField{name: name, value: value})
+		}
+	}
+	return fields, nil
+}
+
+func formValue(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(val)
+		return string(data), err
+	default:
+		return fmt.Sprintf("%v", val), nil
+	}
+}
+
+// renderFilePart renders a file part, reading its content from Path when set
+func renderFilePart(file *configloader.FilePart, params map[string]interface{}) (filePart, error) {
+	part := filePart{name: file.Name, contentType: file.ContentType}
+	if part.contentType == "" {
+		part.contentType = "application/octet-stream"
+	}
+
+	if file.Path != "" {
+		path, err := utils.RenderTemplate(file.Path, params)
+		if err != nil {
+			return part, fmt.Errorf("failed to render path: %w", err)
+		}
+		f, err := os.Open(filepath.Clean(path))
+		if err != nil {
+			return part, fmt.Errorf("failed to open %q: %w", path, err)
+		}
+		defer f.Close() //nolint:errcheck // read-only
+		part.content, err = io.ReadAll(io.LimitReader(f, maxFilePartSize+1))
+		if err != nil {
+			return part, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+		if len(part.content) > maxFilePartSize {
+			return part, fmt.Errorf("%q exceeds %d bytes", path, maxFilePartSize)
+		}
+		part.filename = filepath.Base(path)
+	} else {
+		content, err := utils.RenderTemplate(file.Content, params)
+		if err != nil {
+			return part, fmt.Errorf("failed to render content: %w", err)
+		}
+		part.content = []byte(content)
+		part.filename = file.Name
+	}
+
+	if file.Filename != "" {
+		filename, err := utils.RenderTemplate(file.Filename, params)
+		if err != nil {
+			return part, fmt.Errorf("failed to render filename: %w", err)
+		}
+		part.filename = filename
+	}
+	return part, nil
+}
+
+func encodeForm(fields []formField, _ []filePart) ([]byte, string, error) {
+	values := url.Values{}
+	for _, f := range fields {
+		values.Add(f.name, f.value)
+	}
+	return []byte(values.Encode()), hyperfleetapi.ContentTypeForm, nil
+}
+
+func encodeMultipart(fields []formField, files []filePart) ([]byte, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, f := range fields {
+		if err := w.WriteField(f.name, f.value); err != nil {
+			return nil, "", fmt.Errorf("failed to write field %q: %w", f.name, err)
+		}
+	}
+	for _, f := range files {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(f.name), escapeQuotes(f.filename)))
+		header.Set("Content-Type", f.contentType)
+		part, err := w.CreatePart(header)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to write file %q: %w", f.name, err)
+		}
+		if _, err := part.Write(f.content); err != nil {
+			return nil, "", fmt.Errorf("failed to write file %q: %w", f.name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart body: %w", err)
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// escapeQuotes escapes a Content-Disposition parameter as mime/multipart does
+func escapeQuotes(s string) string {
+	return strings.NewReplacer("\\", "\\\\", `"`, "\\\"").Replace(s)
+}
@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// DeadLetterEventType is the CloudEvent type used for events published to the dead-letter topic
+const DeadLetterEventType = "com.redhat.hyperfleet.adapter.event.dead_letter"
+
+// DefaultMaxDeliveryAttempts is the number of attempts made before dead-lettering an event
+// when clients.broker.max_delivery_attempts is not set
+const DefaultMaxDeliveryAttempts = 3
+
+// DeadLetterQueue configures WithDeadLetter
+type DeadLetterQueue struct {
+	// Publisher publishes dead-lettered events; broker.Publisher satisfies it
+	Publisher ResultPublisher
+	// Topic is the broker topic that receives dead-lettered events
+	Topic string
+	// Adapter is the adapter name, used as the CloudEvent source
+	Adapter string
+	// MaxAttempts is how many times an event is executed before it is dead-lettered.
+	// Zero uses DefaultMaxDeliveryAttempts.
+	MaxAttempts int
+	// BaseDelay is the wait before the second attempt, doubled for each further attempt
+	// up to 30s. Zero waits 1s.
+	BaseDelay time.Duration
+}
+
+// DeadLetterRecord is the data payload of a dead-letter event. It carries the failing
+// CloudEvent unchanged so that it can be republished to the subscription topic once the
+// cause is fixed, and the summary of the last execution as the failure trace.
+type DeadLetterRecord struct {
+	Event    json.RawMessage  `json:"event"`
+	Error    string           `json:"error,omitempty"`
+	Summary  ExecutionSummary `json:"summary"`
+	Attempts int              `json:"attempts"`
+}
+
+// WithDeadLetter wraps a HandlerFunc to execute failing events again, up to
+// dlq.MaxAttempts in total, and to publish events that still fail to dlq.Topic.
+// Failures without a retryable error (invalid event data, CEL errors, 4xx API
+// responses, ...) are dead-lettered after the first attempt. Publishing is
+// best-effort and never changes the returned result. If dlq has no publisher or
+// topic, the handler is returned unwrapped.
+func WithDeadLetter(h HandlerFunc, dlq DeadLetterQueue, log logger.Logger) HandlerFunc {
+	if dlq.Publisher == nil || dlq.Topic == "" {
+		return h
+	}
+	maxAttempts := dlq.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxDeliveryAttempts
+	}
+	backoff := &configloader.RetryPolicy{}
+	if dlq.BaseDelay > 0 {
+		backoff.BaseDelay = configloader.Duration(dlq.BaseDelay)
+	}
+	return func(ctx context.Context, evt *event.Event) (*ExecutionResult, error) {
+		ctx, parsed := withParsedEvent(ctx, evt)
+		var result *ExecutionResult
+		var err error
+		attempts := 0
+		for attempts < maxAttempts {
+			if attempts > 0 {
+				delay := retryDelay(backoff, attempts)
+				log.Warnf(ctx, "Event %s failed on attempt %d/%d, executing again in %s",
+					evt.ID(), attempts, maxAttempts, delay)
+				if !sleepCtx(ctx, delay) {
+					break
+				}
+			}
+			result, err = h(ctx, evt)
+			attempts++
+			if !handlerFailed(result, err) || (err == nil && result != nil && !result.Errors.Retryable()) {
+				break
+			}
+		}
+		if handlerFailed(result, err) {
+			dlq.publish(ctx, log, evt, parsed.data, result, err, attempts)
+		}
+		return result, err
+	}
+}
+
+// publish sends evt and its last execution outcome to the dead-letter topic
+func (dlq DeadLetterQueue) publish(
+	ctx context.Context,
+	log logger.Logger,
+	evt *event.Event,
+	eventData *EventData,
+	result *ExecutionResult,
+	handlerErr error,
+	attempts int,
+) {
+	// Publish even when shutdown canceled the remaining attempts
+	ctx = context.WithoutCancel(ctx)
+	out, err := buildDeadLetterEvent(dlq.Adapter, evt, eventData, result, handlerErr, attempts)
+	if err == nil {
+		err = dlq.Publisher.Publish(ctx, dlq.Topic, out)
+	}
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Errorf(errCtx, "Failed to publish event %s to dead-letter topic %s, the event is lost", evt.ID(), dlq.Topic)
+		return
+	}
+	log.Warnf(ctx, "Event %s failed after %d attempt(s), published to dead-letter topic %s", evt.ID(), attempts, dlq.Topic)
+}
+
+// buildDeadLetterEvent builds the CloudEvent published for an event that kept failing
+func buildDeadLetterEvent(
+	adapterName string,
+	evt *event.Event,
+	eventData *EventData,
+	result *ExecutionResult,
+	handlerErr error,
+	attempts int,
+) (*event.Event, error) {
+	original, err := json.Marshal(evt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode dead-lettered event: %w", err)
+	}
+	if result == nil {
+		result = &ExecutionResult{Status: StatusFailed}
+	}
+	record := DeadLetterRecord{
+		Event:    original,
+		Summary:  buildExecutionSummary(adapterName, evt, eventData, result),
+		Attempts: attempts,
+	}
+	if handlerErr != nil {
+		record.Error = handlerErr.Error()
+	}
+
+	out := event.New()
+	out.SetID(uuid.NewString())
+	out.SetType(DeadLetterEventType)
+	out.SetSource(fmt.Sprintf("hyperfleet-adapter/%s", adapterName))
+	out.SetSubject(evt.ID())
+	out.SetTime(time.Now())
+	if err := out.SetData(event.ApplicationJSON, record); err != nil {
+		return nil, fmt.Errorf("failed to encode dead-letter record: %w", err)
+	}
+	return &out, nil
+}
+
+// handlerFailed reports whether a handler outcome is a failure
+func handlerFailed(result *ExecutionResult, err error) bool {
+	return err != nil || result == nil || result.Status == StatusFailed
+}
+
+// sleepCtx waits for d and reports false when ctx is done first
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
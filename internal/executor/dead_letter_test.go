@@ -0,0 +1,144 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func TestWithDeadLetter(t *testing.T) {
+	transient := &ExecutionResult{
+		Status: StatusFailed,
+		Errors: ExecutionErrors{newPhaseError(PhaseResources, context.DeadlineExceeded)},
+	}
+	permanent := &ExecutionResult{
+		Status: StatusFailed,
+		Errors: ExecutionErrors{newPhaseError(PhaseParamExtraction, errors.New("missing id"))},
+	}
+	succeeded := &ExecutionResult{Status: StatusSuccess}
+
+	tests := []struct {
+		name          string
+		results       []*ExecutionResult
+		wantAttempts  int
+		wantPublished bool
+	}{
+		{name: "success is not retried", results: []*ExecutionResult{succeeded}, wantAttempts: 1},
+		{
+			name:         "transient failure recovers",
+			results:      []*ExecutionResult{transient, succeeded},
+			wantAttempts: 2,
+		},
+		{
+			name:          "transient failure exhausts attempts",
+			results:       []*ExecutionResult{transient, transient, transient, succeeded},
+			wantAttempts:  3,
+			wantPublished: true,
+		},
+		{
+			name:          "permanent failure is dead-lettered at once",
+			results:       []*ExecutionResult{permanent, succeeded},
+			wantAttempts:  1,
+			wantPublished: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			inner := func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+				result := tt.results[attempts]
+				attempts++
+				return result, nil
+			}
+			pub := &recordingPublisher{}
+			dlq := DeadLetterQueue{Publisher: pub, Topic: "dlq", Adapter: "test-adapter", BaseDelay: time.Millisecond}
+
+			result, err := WithDeadLetter(inner, dlq, logger.NewTestLogger())(
+				context.Background(), newPublisherTestEvent(t))
+			require.NoError(t, err)
+			assert.Same(t, tt.results[attempts-1], result)
+			assert.Equal(t, tt.wantAttempts, attempts)
+
+			if !tt.wantPublished {
+				assert.Empty(t, pub.events)
+				return
+			}
+			require.Len(t, pub.events, 1)
+			assert.Equal(t, "dlq", pub.topics[0])
+			assert.Equal(t, DeadLetterEventType, pub.events[0].Type())
+			assert.Equal(t, "evt-1", pub.events[0].Subject())
+
+			var record DeadLetterRecord
+			require.NoError(t, json.Unmarshal(pub.events[0].Data(), &record))
+			assert.Equal(t, tt.wantAttempts, record.Attempts)
+			assert.Equal(t, string(StatusFailed), record.Summary.Status)
+			require.NotNil(t, record.Summary.Resource)
+			assert.Equal(t, "cluster-1", record.Summary.Resource.ID)
+
+			var original event.Event
+			require.NoError(t, json.Unmarshal(record.Event, &original))
+			assert.Equal(t, "evt-1", original.ID())
+			assert.JSONEq(t, string(newPublisherTestEvent(t).Data()), string(original.Data()))
+		})
+	}
+}
+
+func TestWithDeadLetter_HandlerError(t *testing.T) {
+	pub := &recordingPublisher{err: errors.New("broker down")}
+	dlq := DeadLetterQueue{Publisher: pub, Topic: "dlq", MaxAttempts: 2, BaseDelay: time.Millisecond}
+	attempts := 0
+	inner := func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		attempts++
+		return nil, errors.New("boom")
+	}
+
+	result, err := WithDeadLetter(inner, dlq, logger.NewTestLogger())(context.Background(), newPublisherTestEvent(t))
+	assert.Nil(t, result)
+	assert.EqualError(t, err, "boom", "a failed publish does not change the outcome")
+	assert.Equal(t, 2, attempts)
+	require.Len(t, pub.events, 1)
+
+	var record DeadLetterRecord
+	require.NoError(t, json.Unmarshal(pub.events[0].Data(), &record))
+	assert.Equal(t, "boom", record.Error)
+}
+
+func TestWithDeadLetter_NilResult(t *testing.T) {
+	pub := &recordingPublisher{}
+	dlq := DeadLetterQueue{Publisher: pub, Topic: "dlq", MaxAttempts: 2, BaseDelay: time.Millisecond}
+	attempts := 0
+	inner := func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		attempts++
+		return nil, nil
+	}
+
+	result, err := WithDeadLetter(inner, dlq, logger.NewTestLogger())(context.Background(), newPublisherTestEvent(t))
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.Equal(t, 2, attempts, "a missing result is retried like a handler error")
+	require.Len(t, pub.events, 1)
+
+	var record DeadLetterRecord
+	require.NoError(t, json.Unmarshal(pub.events[0].Data(), &record))
+	assert.Equal(t, string(StatusFailed), record.Summary.Status)
+}
+
+func TestWithDeadLetter_Disabled(t *testing.T) {
+	calls := 0
+	inner := func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		calls++
+		return &ExecutionResult{Status: StatusFailed}, nil
+	}
+	h := WithDeadLetter(inner, DeadLetterQueue{Topic: "dlq"}, logger.NewTestLogger())
+	_, _ = h(context.Background(), newPublisherTestEvent(t))
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/gitopsclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/maestroclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// discoveryCacheKey identifies a selector-based LIST call within one execution
+type discoveryCacheKey struct {
+	gvk       schema.GroupVersionKind
+	target    string
+	namespace string
+	selector  string
+}
+
+// newDiscoveryCacheKey builds the cache key for a LIST of gvk in namespace sent to transportTarget
+func newDiscoveryCacheKey(
+	gvk schema.GroupVersionKind,
+	namespace, selector string,
+	transportTarget transportclient.TransportContext,
+) discoveryCacheKey {
+	return discoveryCacheKey{
+		gvk:       gvk,
+		target:    transportTargetName(transportTarget),
+		namespace: namespace,
+		selector:  selector,
+	}
+}
+
+// transportTargetName returns the Maestro consumer name of the target, the path of a
+// gitops target, or "" for k8s
+func transportTargetName(transportTarget transportclient.TransportContext) string {
+	if mt, ok := transportTarget.(*maestroclient.TransportContext); ok && mt != nil {
+		return mt.ConsumerName
+	}
+	if gt, ok := transportTarget.(*gitopsclient.TransportContext); ok && gt != nil {
+		return "gitops:" + gt.Path
+	}
+	return ""
+}
+
+// cachedDiscovery returns the list cached for key by an earlier discovery in this execution
+func (ec *ExecutionContext) cachedDiscovery(key discoveryCacheKey) (*unstructured.UnstructuredList, bool) {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	list, ok := ec.discoveryCache[key]
+	return list, ok
+}
+
+// cacheDiscovery stores the result of a LIST call for reuse by later discoveries
+func (ec *ExecutionContext) cacheDiscovery(key discoveryCacheKey, list *unstructured.UnstructuredList) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.discoveryCache == nil {
+		ec.discoveryCache = make(map[discoveryCacheKey]*unstructured.UnstructuredList)
+	}
+	ec.discoveryCache[key] = list
+}
+
+// invalidateDiscovery drops cached lists that may contain objects of gvk in namespace on
+// transportTarget. Lists across all namespaces are dropped as well, and an empty namespace
+// (cluster-scoped or not known from the manifest) drops every list of gvk.
+func (ec *ExecutionContext) invalidateDiscovery(
+	gvk schema.GroupVersionKind,
+	namespace string,
+	transportTarget transportclient.TransportContext,
+) {
+	target := transportTargetName(transportTarget)
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	for key := range ec.discoveryCache {
+		sameNamespace := namespace == "" || key.namespace == "" || key.namespace == namespace
+		if key.gvk == gvk && key.target == target && sameNamespace {
+			delete(ec.discoveryCache, key)
+		}
+	}
+}
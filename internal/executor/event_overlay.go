@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// cloudEventOf returns the CloudEvent executed data comes from, or nil for other data
+func cloudEventOf(data interface{}) *event.Event {
+	switch v := data.(type) {
+	case *event.Event:
+		return v
+	case event.Event:
+		return &v
+	default:
+		return nil
+	}
+}
+
+// eventAttribute returns the value of a context or extension attribute of evt as a string,
+// and false when the event does not have it
+func eventAttribute(evt *event.Event, name string) (string, bool) {
+	var value string
+	switch name {
+	case "id":
+		value = evt.ID()
+	case "source":
+		value = evt.Source()
+	case "type":
+		value = evt.Type()
+	case "subject":
+		value = evt.Subject()
+	case "time":
+		if !evt.Time().IsZero() {
+			value = evt.Time().UTC().Format(time.RFC3339Nano)
+		}
+	default:
+		ext, ok := evt.Extensions()[name]
+		if !ok {
+			return "", false
+		}
+		formatted, err := types.Format(ext)
+		if err != nil {
+			return "", false
+		}
+		value = formatted
+	}
+	return value, value != ""
+}
+
+// applyEventOverlays sets the fields of data from the attributes of evt, in order. A field
+// the data already sets is kept unless the overlay's precedence is "attribute". It returns
+// whether data changed, and a warning for each attribute that could not be converted to
+// the overlay's type or set because a parent in its field path is not an object.
+func applyEventOverlays(
+	evt *event.Event,
+	data map[string]interface{},
+	overlays []configloader.EventOverlay,
+) (changed bool, warnings []string) {
+	for i := range overlays {
+		overlay := &overlays[i]
+		attr, ok := eventAttribute(evt, overlay.Attribute)
+		if !ok {
+			continue
+		}
+		var value interface{} = attr
+		if overlay.Type != "" {
+			converted, err := utils.ConvertToType(attr, overlay.Type)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("attribute %q not applied: %v", overlay.Attribute, err))
+				continue
+			}
+			value = converted
+		}
+
+		segments := strings.Split(overlay.Field, ".")
+		parent := data
+		for _, segment := range segments[:len(segments)-1] {
+			next, exists := parent[segment]
+			if !exists || next == nil {
+				child := make(map[string]interface{})
+				parent[segment] = child
+				parent = child
+				continue
+			}
+			child, isMap := next.(map[string]interface{})
+			if !isMap {
+				parent = nil
+				break
+			}
+			parent = child
+		}
+		if parent == nil {
+			warnings = append(warnings, fmt.Sprintf("attribute %q not applied: a parent of event field %q is not an object",
+				overlay.Attribute, overlay.Field))
+			continue
+		}
+
+		leaf := segments[len(segments)-1]
+		if existing, exists := parent[leaf]; exists && existing != nil &&
+			overlay.GetPrecedence() == configloader.OverlayPrecedenceData {
+			continue
+		}
+		parent[leaf] = value
+		changed = true
+	}
+	return changed, warnings
+}
@@ -0,0 +1,156 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func newOverlayTestEvent(t *testing.T, data map[string]interface{}) *event.Event {
+	t.Helper()
+	evt := event.New()
+	evt.SetID("evt-1")
+	evt.SetType("com.hyperfleet.cluster.updated")
+	evt.SetSource("hyperfleet-api")
+	evt.SetExtension("region", "us-east-1")
+	evt.SetExtension("generation", 7)
+	require.NoError(t, evt.SetData(event.ApplicationJSON, data))
+	return &evt
+}
+
+func TestApplyEventOverlays(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         map[string]interface{}
+		expected     map[string]interface{}
+		overlays     []configloader.EventOverlay
+		expectChange bool
+		expectWarn   bool
+	}{
+		{
+			name:         "fills a missing field",
+			data:         map[string]interface{}{"id": "c1"},
+			overlays:     []configloader.EventOverlay{{Attribute: "region", Field: "region"}},
+			expected:     map[string]interface{}{"id": "c1", "region": "us-east-1"},
+			expectChange: true,
+		},
+		{
+			name:     "data wins by default",
+			data:     map[string]interface{}{"region": "eu-west-1"},
+			overlays: []configloader.EventOverlay{{Attribute: "region", Field: "region"}},
+			expected: map[string]interface{}{"region": "eu-west-1"},
+		},
+		{
+			name: "attribute precedence overrides the data",
+			data: map[string]interface{}{"region": "eu-west-1"},
+			overlays: []configloader.EventOverlay{
+				{Attribute: "region", Field: "region", Precedence: configloader.OverlayPrecedenceAttribute},
+			},
+			expected:     map[string]interface{}{"region": "us-east-1"},
+			expectChange: true,
+		},
+		{
+			name: "merges into nested objects",
+			data: map[string]interface{}{"spec": map[string]interface{}{"name": "c1"}},
+			overlays: []configloader.EventOverlay{
+				{Attribute: "region", Field: "spec.placement.region"},
+				{Attribute: "type", Field: "spec.event_type"},
+			},
+			expected: map[string]interface{}{"spec": map[string]interface{}{
+				"name":       "c1",
+				"placement":  map[string]interface{}{"region": "us-east-1"},
+				"event_type": "com.hyperfleet.cluster.updated",
+			}},
+			expectChange: true,
+		},
+		{
+			name:         "null fields are missing",
+			data:         map[string]interface{}{"region": nil},
+			overlays:     []configloader.EventOverlay{{Attribute: "region", Field: "region"}},
+			expected:     map[string]interface{}{"region": "us-east-1"},
+			expectChange: true,
+		},
+		{
+			name:     "absent attributes are ignored",
+			data:     map[string]interface{}{},
+			overlays: []configloader.EventOverlay{{Attribute: "zone", Field: "zone"}},
+			expected: map[string]interface{}{},
+		},
+		{
+			name:         "converts to the overlay type",
+			data:         map[string]interface{}{},
+			overlays:     []configloader.EventOverlay{{Attribute: "generation", Field: "generation", Type: "int64"}},
+			expected:     map[string]interface{}{"generation": int64(7)},
+			expectChange: true,
+		},
+		{
+			name:       "a value that does not convert is a warning",
+			data:       map[string]interface{}{},
+			overlays:   []configloader.EventOverlay{{Attribute: "region", Field: "region", Type: "bool"}},
+			expected:   map[string]interface{}{},
+			expectWarn: true,
+		},
+		{
+			name:       "a parent that is not an object is a warning",
+			data:       map[string]interface{}{"spec": "flat"},
+			overlays:   []configloader.EventOverlay{{Attribute: "region", Field: "spec.region"}},
+			expected:   map[string]interface{}{"spec": "flat"},
+			expectWarn: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evt := newOverlayTestEvent(t, nil)
+			changed, warnings := applyEventOverlays(evt, tt.data, tt.overlays)
+			assert.Equal(t, tt.expected, tt.data)
+			assert.Equal(t, tt.expectChange, changed)
+			assert.Equal(t, tt.expectWarn, len(warnings) > 0, "warnings: %v", warnings)
+		})
+	}
+}
+
+func TestExecute_EventOverlays(t *testing.T) {
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		EventOverlays: []configloader.EventOverlay{
+			{Attribute: "region", Field: "region"},
+			{Attribute: "generation", Field: "generation", Type: "int64"},
+		},
+		Params: []configloader.Parameter{
+			{Name: "region", Source: configloader.ParameterSource{Kind: "string", StringVal: "event.region"}},
+		},
+	}
+	exec, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(newMockAPIClient()).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+
+	result := exec.Execute(context.Background(), newOverlayTestEvent(t, map[string]interface{}{"id": "c1"}))
+	require.Equal(t, StatusSuccess, result.Status, "errors: %v", result.Errors)
+	assert.Equal(t, "us-east-1", result.Params["region"])
+	assert.Equal(t, int64(7), result.ExecutionContext.EventData["generation"])
+
+	// The parse shared with the other handlers of the event keeps the event's own data
+	evt := newOverlayTestEvent(t, map[string]interface{}{"id": "c1"})
+	ctx, parsed := withParsedEvent(context.Background(), evt)
+	result = exec.Execute(ctx, evt)
+	require.Equal(t, StatusSuccess, result.Status, "errors: %v", result.Errors)
+	assert.Equal(t, "us-east-1", result.Params["region"])
+	assert.NotContains(t, parsed.raw, "region")
+
+	config.EventOverlays[1].Type = ""
+	result = exec.Execute(context.Background(), newOverlayTestEvent(t, map[string]interface{}{"id": "c1"}))
+	assert.Equal(t, StatusFailed, result.Status, "a string generation should fail to parse")
+	assert.Contains(t, result.Errors.String(), "failed to parse event data")
+}
@@ -0,0 +1,190 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	apierrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// ErrorCategory classifies a PhaseError by its source
+type ErrorCategory string
+
+const (
+	// ErrorCategoryInput is invalid event data or parameters
+	ErrorCategoryInput ErrorCategory = "input"
+	// ErrorCategoryAPI is a failed HyperFleet API call
+	ErrorCategoryAPI ErrorCategory = "api"
+	// ErrorCategoryTransport is a failed Kubernetes or Maestro operation
+	ErrorCategoryTransport ErrorCategory = "transport"
+	// ErrorCategoryEvaluation is a failed CEL evaluation
+	ErrorCategoryEvaluation ErrorCategory = "evaluation"
+	// ErrorCategoryTimeout is a canceled or timed-out operation
+	ErrorCategoryTimeout ErrorCategory = "timeout"
+	// ErrorCategoryPanic is a recovered panic
+	ErrorCategoryPanic ErrorCategory = "panic"
+	// ErrorCategoryInternal is any other failure
+	ErrorCategoryInternal ErrorCategory = "internal"
+)
+
+// errPhasePanic marks errors produced by recoverPhase
+var errPhasePanic = errors.New("panic")
+
+// PhaseError is a single failure recorded in ExecutionResult.Errors
+type PhaseError struct {
+	// Err is the original error; it is not serialized, Message and Causes carry its text
+	Err error `json:"-"`
+	// Phase is the execution phase where the error occurred
+	Phase ExecutionPhase `json:"phase"`
+	// Step is the precondition, resource or post-action name, when known
+	Step string `json:"step,omitempty"`
+	// Category classifies the error source
+	Category ErrorCategory `json:"category"`
+	// Message is the full error message
+	Message string `json:"message"`
+	// Causes are the messages of the wrapped errors, outermost first
+	Causes []string `json:"causes,omitempty"`
+	// Retryable reports whether redelivering the event may succeed
+	Retryable bool `json:"retryable"`
+}
+
+func (e *PhaseError) Error() string {
+	return e.Message
+}
+
+func (e *PhaseError) Unwrap() error {
+	return e.Err
+}
+
+// ExecutionErrors is the ordered list of failures recorded during an execution
+type ExecutionErrors []*PhaseError
+
+// Phase returns the first error recorded for phase, or nil
+func (errs ExecutionErrors) Phase(phase ExecutionPhase) error {
+	for _, e := range errs {
+		if e.Phase == phase {
+			return e
+		}
+	}
+	return nil
+}
+
+// ForPhase returns all errors recorded for phase, in order
+func (errs ExecutionErrors) ForPhase(phase ExecutionPhase) ExecutionErrors {
+	var out ExecutionErrors
+	for _, e := range errs {
+		if e.Phase == phase {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Phases returns the distinct phases with errors, in the order they first failed
+func (errs ExecutionErrors) Phases() []ExecutionPhase {
+	var phases []ExecutionPhase
+	seen := make(map[ExecutionPhase]bool)
+	for _, e := range errs {
+		if !seen[e.Phase] {
+			seen[e.Phase] = true
+			phases = append(phases, e.Phase)
+		}
+	}
+	return phases
+}
+
+// Retryable reports whether any error is likely transient, so that running the
+// event again may succeed
+func (errs ExecutionErrors) Retryable() bool {
+	for _, e := range errs {
+		if e.Retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// String joins all messages as "phase: message; ..."
+func (errs ExecutionErrors) String() string {
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", e.Phase, e.Message))
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Add records err for phase. Errors joined with errors.Join are recorded one entry
+// each, so several failures in the same phase do not hide each other; prefix is
+// prepended to every message.
+func (errs *ExecutionErrors) Add(phase ExecutionPhase, prefix string, err error) {
+	if err == nil {
+		return
+	}
+	members := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		members = joined.Unwrap()
+	}
+	for _, m := range members {
+		if prefix != "" {
+			m = fmt.Errorf("%s: %w", prefix, m)
+		}
+		*errs = append(*errs, newPhaseError(phase, m))
+	}
+}
+
+// newPhaseError classifies err and captures its cause chain
+func newPhaseError(phase ExecutionPhase, err error) *PhaseError {
+	pe := &PhaseError{Err: err, Phase: phase}
+
+	var execErr *ExecutorError
+	if errors.As(err, &execErr) {
+		pe.Step = execErr.Step
+	}
+	// Name the step in template errors too, so that the step result shows it
+	if tmplErr, ok := apierrors.IsTemplateError(err); ok && tmplErr.Step == "" {
+		tmplErr.Step = pe.Step
+	}
+	pe.Message = err.Error()
+
+	for cause := errors.Unwrap(err); cause != nil; cause = errors.Unwrap(cause) {
+		if msg := cause.Error(); len(pe.Causes) == 0 || pe.Causes[len(pe.Causes)-1] != msg {
+			pe.Causes = append(pe.Causes, msg)
+		}
+	}
+
+	pe.Category, pe.Retryable = classifyError(phase, err)
+	return pe
+}
+
+// classifyError returns the category of err and whether it is likely transient
+func classifyError(phase ExecutionPhase, err error) (ErrorCategory, bool) {
+	if errors.Is(err, errPhasePanic) {
+		return ErrorCategoryPanic, false
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return ErrorCategoryTimeout, true
+	}
+	if apiErr, ok := apierrors.IsAPIError(err); ok {
+		retryable := apiErr.IsServerError() || apiErr.IsTimeout() || apiErr.IsRateLimited() || apiErr.StatusCode == 0
+		return ErrorCategoryAPI, retryable
+	}
+	var celErr *apierrors.CELError
+	if errors.As(err, &celErr) {
+		return ErrorCategoryEvaluation, false
+	}
+	var k8sOpErr *apierrors.K8sOperationError
+	var status k8serrors.APIStatus
+	if errors.As(err, &k8sOpErr) || errors.As(err, &status) {
+		return ErrorCategoryTransport, apierrors.IsRetryableDiscoveryError(err) || k8serrors.IsConflict(err)
+	}
+	if apierrors.IsNetworkError(err) {
+		return ErrorCategoryTransport, true
+	}
+	if phase == PhaseParamExtraction {
+		return ErrorCategoryInput, false
+	}
+	return ErrorCategoryInternal, false
+}
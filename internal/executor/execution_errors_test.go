@@ -0,0 +1,99 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apierrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+func TestExecutionErrors_AddSplitsJoinedErrors(t *testing.T) {
+	var errs ExecutionErrors
+	errs.Add(PhaseParamExtraction, "", errors.New("missing clusterId"))
+	errs.Add(PhaseResources, "resource execution failed", errors.Join(
+		NewExecutorError(PhaseResources, "ns", "failed to delete resource", errors.New("boom")),
+		NewExecutorError(PhaseResources, "cm", "failed to delete resource", errors.New("bang")),
+	))
+
+	require.Len(t, errs, 3)
+	assert.Equal(t, []ExecutionPhase{PhaseParamExtraction, PhaseResources}, errs.Phases())
+	assert.Len(t, errs.ForPhase(PhaseResources), 2)
+	assert.Equal(t, "ns", errs[1].Step)
+	assert.Equal(t, "cm", errs[2].Step)
+	assert.Equal(t, "resource execution failed: [resources] cm: failed to delete resource: bang", errs[2].Message)
+	assert.Equal(t, []string{"[resources] cm: failed to delete resource: bang", "bang"}, errs[2].Causes)
+	assert.Equal(t, ErrorCategoryInput, errs[0].Category)
+
+	assert.Same(t, errs[0], errs.Phase(PhaseParamExtraction))
+	assert.Nil(t, errs.Phase(PhasePostActions))
+}
+
+func TestExecutionErrors_TemplateErrorStep(t *testing.T) {
+	_, renderErr := utils.RenderTemplate("{{ .clusterName }}", map[string]interface{}{"clusterId": "abc123"})
+	require.Error(t, renderErr)
+	stepErr := NewExecutorError(PhaseResources, "configmap0", "failed to render manifest", renderErr)
+
+	var errs ExecutionErrors
+	errs.Add(PhaseResources, "", stepErr)
+
+	require.Len(t, errs, 1)
+	assert.Equal(t, "configmap0", errs[0].Step)
+	assert.Contains(t, errs[0].Message,
+		`failed to execute template in step "configmap0" at variable .clusterName: `)
+	assert.Contains(t, errs[0].Message, "(available variables: clusterId)")
+	// The step result holding the same error shows the step too
+	assert.Contains(t, stepErr.Error(), `in step "configmap0"`)
+}
+
+func TestClassifyError(t *testing.T) {
+	notFound := k8serrors.NewNotFound(schema.GroupResource{Resource: "configmaps"}, "cm")
+	tests := []struct {
+		name          string
+		phase         ExecutionPhase
+		err           error
+		wantCategory  ErrorCategory
+		wantRetryable bool
+	}{
+		{"panic", PhaseResources, fmt.Errorf("%w: boom", errPhasePanic), ErrorCategoryPanic, false},
+		{"deadline", PhaseResources, fmt.Errorf("apply: %w", context.DeadlineExceeded), ErrorCategoryTimeout, true},
+		{"api 503", PhasePreconditions, &apierrors.APIError{StatusCode: 503}, ErrorCategoryAPI, true},
+		{"api 400", PhasePostActions, &apierrors.APIError{StatusCode: 400}, ErrorCategoryAPI, false},
+		{"k8s not found", PhaseResources, notFound, ErrorCategoryTransport, false},
+		{"k8s unavailable", PhaseResources, k8serrors.NewServiceUnavailable("down"), ErrorCategoryTransport, true},
+		{"plain", PhaseResources, errors.New("render failed"), ErrorCategoryInternal, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, retryable := classifyError(tt.phase, tt.err)
+			assert.Equal(t, tt.wantCategory, category)
+			assert.Equal(t, tt.wantRetryable, retryable)
+		})
+	}
+}
+
+func TestExecutionErrors_JSON(t *testing.T) {
+	var errs ExecutionErrors
+	errs.Add(PhasePostActions, "post action execution failed",
+		NewExecutorError(PhasePostActions, "reportStatus", "API call failed", &apierrors.APIError{StatusCode: 500}))
+
+	data, err := json.Marshal(errs)
+	require.NoError(t, err)
+
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, "post_actions", decoded[0]["phase"])
+	assert.Equal(t, "reportStatus", decoded[0]["step"])
+	assert.Equal(t, "api", decoded[0]["category"])
+	assert.Equal(t, true, decoded[0]["retryable"])
+	assert.NotEmpty(t, decoded[0]["causes"])
+}
@@ -2,19 +2,26 @@ package executor
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
 	apierrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
 	pkgotel "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/telemetry"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -22,19 +29,85 @@ import (
 // ResourceNotFoundReason indicates the API returned 404 for the target resource.
 const ResourceNotFoundReason = "ResourceNotFound"
 
+// ReportingTimeout bounds the post-actions phase, which runs detached from the
+// event context's cancellation so status is reported even after a timeout.
+const ReportingTimeout = 30 * time.Second
+
+// ExecutionTimeoutReason is the error reason of an execution that ran past the
+// execution_timeout of the task config
+const ExecutionTimeoutReason = "Timeout"
+
+// errExecutionTimeout is the cancellation cause of an execution that ran past its
+// execution_timeout
+var errExecutionTimeout = errors.New("execution timeout exceeded")
+
 // NewExecutor creates a new Executor with the given configuration
 func NewExecutor(config *ExecutorConfig) (*Executor, error) {
 	if err := validateExecutorConfig(config); err != nil {
 		return nil, err
 	}
 
-	return &Executor{
+	e := &Executor{
 		config:             config,
 		precondExecutor:    newPreconditionExecutor(config),
 		resourceExecutor:   newResourceExecutor(config),
+		waitExecutor:       newWaitExecutor(config),
 		postActionExecutor: newPostActionExecutor(config),
 		log:                config.Logger,
-	}, nil
+	}
+	e.storeConfig(config.Config)
+	return e, nil
+}
+
+// Config returns the config used for new executions
+func (e *Executor) Config() *configloader.Config {
+	return e.current.Load().config
+}
+
+// ConfigHash returns the task config hash of the config used for new executions
+func (e *Executor) ConfigHash() string {
+	return e.current.Load().hash
+}
+
+// ConfigFingerprint returns the fingerprint of the whole config used for new executions
+func (e *Executor) ConfigFingerprint() string {
+	return e.current.Load().fingerprint
+}
+
+// SwapConfig atomically replaces the config used for new executions. Executions
+// already running finish with the config they started with, so config must not be
+// modified after the swap; build a new one with Config.WithTaskFrom instead.
+func (e *Executor) SwapConfig(config *configloader.Config) error {
+	if config == nil {
+		return fmt.Errorf("config is required")
+	}
+	if err := configloader.CheckLimits(config); err != nil {
+		return fmt.Errorf("config exceeds limits: %w", err)
+	}
+	if err := checkPruneTransport(config); err != nil {
+		return err
+	}
+	if err := checkSecretProvider(config, e.config.SecretProvider); err != nil {
+		return err
+	}
+	e.storeConfig(config)
+	return nil
+}
+
+// storeConfig makes config the config for new executions and publishes its hash and
+// fingerprint
+func (e *Executor) storeConfig(config *configloader.Config) {
+	version := &configVersion{
+		config:      config,
+		hash:        config.TaskConfigHash(),
+		fingerprint: config.ConfigFingerprint(),
+	}
+	e.current.Store(version)
+	task := e.config.TaskName
+	if task == "" {
+		task = metrics.ConfigTaskMain
+	}
+	e.config.MetricsRecorder.SetActiveConfig(task, version.hash, version.fingerprint)
 }
 
 func validateExecutorConfig(config *ExecutorConfig) error {
@@ -57,6 +130,41 @@ func validateExecutorConfig(config *ExecutorConfig) error {
 		}
 	}
 
+	if config.ResultPublisher != nil && config.ResultTopic == "" {
+		return fmt.Errorf("result topic is required when a result publisher is set")
+	}
+
+	// Configs built without LoadConfig still get the size limits enforced
+	if err := configloader.CheckLimits(config.Config); err != nil {
+		return fmt.Errorf("config exceeds limits: %w", err)
+	}
+
+	if err := checkPruneTransport(config.Config); err != nil {
+		return err
+	}
+	return checkSecretProvider(config.Config, config.SecretProvider)
+}
+
+// checkSecretProvider rejects vault param sources when no secret provider is set,
+// which is the case when clients.vault is not configured
+func checkSecretProvider(config *configloader.Config, secrets SecretProvider) error {
+	if secrets != nil {
+		return nil
+	}
+	for _, param := range config.Params {
+		if param.Source.IsVault() {
+			return fmt.Errorf("param %q: vault sources require clients.vault to be configured", param.Name)
+		}
+	}
+	return nil
+}
+
+// checkPruneTransport rejects prune steps when the adapter uses the Maestro transport:
+// prune lists and deletes resources through the Kubernetes API.
+func checkPruneTransport(config *configloader.Config) error {
+	if len(config.Prune) > 0 && config.Clients.Maestro != nil {
+		return fmt.Errorf("prune steps require the kubernetes transport, but clients.maestro is configured")
+	}
 	return nil
 }
 
@@ -67,18 +175,68 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 	// Start OTel span and add trace context to logs
 	ctx, span := e.startTracedExecution(ctx)
 	defer span.End()
+	ctx = withProgressReporter(ctx, e.log, e.config.MetricsRecorder)
 
-	// Parse event data
-	eventData, rawData, err := ParseEventData(data)
+	result := e.execute(ctx, data)
+	endTracedExecution(span, result)
+	return result
+}
+
+// execute runs the phases of one execution within the event span. Each step runs in
+// a child span of its own.
+func (e *Executor) execute(ctx context.Context, data interface{}) *ExecutionResult {
+	// Read the config once so a concurrent SwapConfig never mixes two configs in one execution
+	version := e.current.Load()
+	ctx = criteria.WithStrictJSONNumbers(ctx, version.config.StrictJSONNumbers())
+
+	// The execution timeout cancels the calls in flight; the post actions still run with
+	// the reporting context
+	if timeout := version.config.ExecutionTimeout.Std(); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %s", errExecutionTimeout, timeout))
+		defer cancel()
+	}
+
+	// Parse event data, reusing the parse of the CloudEvent being handled, with the
+	// configured broker attributes overlaid. The shared parse decodes numbers as float64,
+	// so strict JSON numbers parse the event again.
+	strictNumbers := version.config.StrictJSONNumbers()
+	var eventData *EventData
+	var rawData map[string]interface{}
+	var err error
+	if evt, ok := data.(*event.Event); ok && evt != nil && !strictNumbers {
+		var parsed *parsedEvent
+		ctx, parsed = withParsedEvent(ctx, evt)
+		eventData, rawData, err = parsed.data, parsed.raw, parsed.err
+	} else {
+		eventData, rawData, err = parseEventData(data, strictNumbers)
+	}
+	var overlayWarnings []string
+	if evt := cloudEventOf(data); err == nil && evt != nil && len(version.config.EventOverlays) > 0 {
+		// The parse is shared with the other handlers of the event, so overlay a copy
+		var overlaid map[string]interface{}
+		overlaid, err = utils.DeepCopyMap(rawData)
+		if err == nil {
+			var changed bool
+			changed, overlayWarnings = applyEventOverlays(evt, overlaid, version.config.EventOverlays)
+			if changed {
+				rawData = overlaid
+				eventData, _, err = ParseEventData(rawData)
+			}
+		}
+	}
 	if err != nil {
 		parseErr := fmt.Errorf("failed to parse event data: %w", err)
 		errCtx := logger.WithErrorField(ctx, parseErr)
 		e.log.Errorf(errCtx, "Failed to parse event data")
-		return &ExecutionResult{
-			Status:       StatusFailed,
-			CurrentPhase: PhaseParamExtraction,
-			Errors:       map[ExecutionPhase]error{PhaseParamExtraction: parseErr},
+		result := &ExecutionResult{
+			Status:            StatusFailed,
+			CurrentPhase:      PhaseParamExtraction,
+			ConfigHash:        version.hash,
+			ConfigFingerprint: version.fingerprint,
 		}
+		result.Errors.Add(PhaseParamExtraction, "", parseErr)
+		return result
 	}
 
 	// This is intended to set OwnerReferences and ResourceID for the event when it exists
@@ -92,25 +250,39 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 	} else {
 		ctx = logger.WithDynamicResourceID(ctx, eventData.Kind, eventData.ID)
 	}
+	// Queue this event's transport writes with the other events of its cluster
+	ctx = transportclient.WithQueueKey(ctx, resourceKey(eventData))
+	if clusterID, ok := logger.GetLogFields(ctx)["cluster_id"].(string); ok &&
+		slices.Contains(version.config.Log.DebugClusters, clusterID) {
+		ctx = logger.WithDebugOverride(ctx)
+	}
 
-	execCtx := NewExecutionContext(ctx, rawData, e.config.Config)
+	execCtx := NewExecutionContext(ctx, rawData, version.config)
+	execCtx.ConfigFingerprint = version.fingerprint
+	for _, warning := range overlayWarnings {
+		execCtx.AddWarning(PhaseParamExtraction, "", warning)
+	}
 
 	// Initialize execution result
 	result := &ExecutionResult{
-		Status:       StatusSuccess,
-		Params:       make(map[string]interface{}),
-		Errors:       make(map[ExecutionPhase]error),
-		CurrentPhase: PhaseParamExtraction,
+		Status:            StatusSuccess,
+		Params:            make(map[string]interface{}),
+		CurrentPhase:      PhaseParamExtraction,
+		ConfigHash:        version.hash,
+		ConfigFingerprint: version.fingerprint,
 	}
+	defer func() { result.Warnings = execCtx.WarningsSnapshot() }()
 
 	e.log.Info(ctx, "Processing event")
 
 	// Phase 1: Parameter Extraction
 	e.log.Infof(ctx, "Phase %s: RUNNING", result.CurrentPhase)
 	if paramErr := e.executeParamExtraction(execCtx); paramErr != nil {
+		var reason string
+		reason, paramErr = timeoutReason(ctx, "ParameterExtractionFailed", paramErr)
 		result.Status = StatusFailed
-		result.Errors[PhaseParamExtraction] = paramErr
-		execCtx.SetError("ParameterExtractionFailed", paramErr.Error())
+		result.Errors.Add(PhaseParamExtraction, "", paramErr)
+		execCtx.SetError(reason, paramErr.Error())
 		resErr := fmt.Errorf("parameter extraction failed: %w", paramErr)
 		errCtx := logger.WithErrorField(ctx, resErr)
 		e.log.Errorf(errCtx, "Phase %s: FAILED", PhaseParamExtraction)
@@ -123,9 +295,14 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 
 	// Phase 2: Preconditions
 	result.CurrentPhase = PhasePreconditions
-	preconditions := e.config.Config.Preconditions
+	preconditions := execCtx.Config.Preconditions
 	e.log.Infof(ctx, "Phase %s: RUNNING - %d configured", result.CurrentPhase, len(preconditions))
-	precondOutcome := e.precondExecutor.ExecuteAll(ctx, preconditions, execCtx)
+	var precondOutcome *PreconditionsOutcome
+	if panicErr := recoverPhase(func() {
+		precondOutcome = e.precondExecutor.ExecuteAll(ctx, preconditions, execCtx)
+	}); panicErr != nil {
+		precondOutcome = &PreconditionsOutcome{Error: panicErr}
+	}
 	result.PreconditionResults = precondOutcome.Results
 
 	switch {
@@ -137,16 +314,17 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 		result.ResourcesSkipped = true
 		result.SkipReason = ResourceNotFoundReason
 		execCtx.SetSkipped(ResourceNotFoundReason, "")
-		execCtx.Adapter.ExecutionError = nil
+		execCtx.ClearExecutionError()
 		result.ExecutionContext = execCtx
 		result.Params = execCtx.Params
 		return result
 	case precondOutcome.Error != nil:
 		// Process execution error: precondition evaluation failed
+		var reason string
+		reason, precondOutcome.Error = timeoutReason(ctx, "PreconditionFailed", precondOutcome.Error)
 		result.Status = StatusFailed
-		precondErr := fmt.Errorf("precondition evaluation failed: error=%w", precondOutcome.Error)
-		result.Errors[result.CurrentPhase] = precondErr
-		execCtx.SetError("PreconditionFailed", precondOutcome.Error.Error())
+		result.Errors.Add(result.CurrentPhase, "precondition evaluation failed", precondOutcome.Error)
+		execCtx.SetError(reason, precondOutcome.Error.Error())
 		errCtx := logger.WithErrorField(ctx, precondOutcome.Error)
 		e.log.Errorf(errCtx, "Phase %s: FAILED", result.CurrentPhase)
 		result.ResourcesSkipped = true
@@ -154,8 +332,7 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 		// Set skip metadata on adapter context without overwriting the failed execution status
 		// Note: SetSkipped() is NOT called here because it resets ExecutionStatus to "success",
 		// which would mask the precondition failure in CEL expressions (e.g., Health condition)
-		execCtx.Adapter.ResourcesSkipped = true
-		execCtx.Adapter.SkipReason = precondOutcome.Error.Error()
+		execCtx.MarkResourcesSkipped(precondOutcome.Error.Error(), true)
 		// Continue to post actions for error reporting
 	case !precondOutcome.AllMatched:
 		// Business outcome: precondition not satisfied
@@ -170,17 +347,49 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 
 	// Phase 3: Resources (skip if preconditions not met or previous error)
 	result.CurrentPhase = PhaseResources
-	resources := e.config.Config.Resources
+	resources := execCtx.Config.Resources
 	e.log.Infof(ctx, "Phase %s: RUNNING - %d configured", result.CurrentPhase, len(resources))
 	if !result.ResourcesSkipped {
-		resourceResults, resourceErr := e.resourceExecutor.ExecuteAll(ctx, resources, execCtx)
+		var resourceResults []ResourceResult
+		var resourceErr error
+		if panicErr := recoverPhase(func() {
+			if platformErr := resolvePlatform(ctx, execCtx, e.log); platformErr != nil {
+				resourceErr = NewExecutorError(
+					PhaseResources, configloader.FieldPlatform, "failed to resolve platform", platformErr)
+				return
+			}
+			resourceResults, resourceErr = e.resourceExecutor.ExecuteAll(ctx, resources, execCtx)
+			// Prune only once every resource succeeded: after a failure, even one continued
+			// by on_error, the applied set is incomplete and would select resources that
+			// are still wanted
+			if resourceErr == nil && len(execCtx.Config.Prune) > 0 {
+				if failed := failedResource(resourceResults); failed != "" {
+					msg := fmt.Sprintf("prune skipped: resource %s failed", failed)
+					execCtx.AddWarning(PhaseResources, failed, msg)
+					e.log.Warnf(ctx, "Phase %s: %s", result.CurrentPhase, msg)
+				} else {
+					var pruneResults []ResourceResult
+					pruneResults, resourceErr = e.resourceExecutor.PruneAll(
+						ctx, execCtx.Config.Prune, execCtx, resourceResults)
+					resourceResults = append(resourceResults, pruneResults...)
+				}
+			}
+			// Wait steps block until what was applied reports ready, so the post actions
+			// report its final state
+			if resourceErr == nil && len(execCtx.Config.Wait) > 0 {
+				result.WaitResults, resourceErr = e.waitExecutor.ExecuteAll(ctx, execCtx.Config.Wait, execCtx)
+			}
+		}); panicErr != nil {
+			resourceErr = panicErr
+		}
 		result.ResourceResults = resourceResults
 
 		if resourceErr != nil {
+			var reason string
+			reason, resourceErr = timeoutReason(ctx, "ResourceFailed", resourceErr)
 			result.Status = StatusFailed
-			resErr := fmt.Errorf("resource execution failed: %w", resourceErr)
-			result.Errors[result.CurrentPhase] = resErr
-			execCtx.SetError("ResourceFailed", resourceErr.Error())
+			result.Errors.Add(result.CurrentPhase, "resource execution failed", resourceErr)
+			execCtx.SetError(reason, resourceErr.Error())
 			errCtx := logger.WithErrorField(ctx, resourceErr)
 			e.log.Errorf(errCtx, "Phase %s: FAILED", result.CurrentPhase)
 			// Continue to post actions for error reporting
@@ -191,19 +400,32 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 		e.log.Infof(ctx, "Phase %s: SKIPPED - %s", result.CurrentPhase, result.SkipReason)
 	}
 
-	// Phase 4: Post Actions (always execute for error reporting)
+	// Phase 4: Post Actions (always execute for error reporting, even when the
+	// event context was canceled or timed out during earlier phases)
 	result.CurrentPhase = PhasePostActions
-	postConfig := e.config.Config.Post
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		e.log.Warnf(ctx, "Phase %s: event context done (%v), reporting with a %s grace period",
+			result.CurrentPhase, ctxErr, ReportingTimeout)
+	}
+	ctx, cancelReporting := reportingContext(ctx)
+	defer cancelReporting()
+	postConfig := execCtx.Config.Post
 	postActionCount := 0
 	if postConfig != nil {
 		postActionCount = len(postConfig.PostActions)
 	}
 	e.log.Infof(ctx, "Phase %s: RUNNING - %d configured", result.CurrentPhase, postActionCount)
+	// Resolve the platform again so that payloads see platform info from the discovered
+	// resources; on failure the value resolved for the resources phase, if any, is kept
+	if platformErr := resolvePlatform(ctx, execCtx, e.log); platformErr != nil {
+		execCtx.AddWarning(result.CurrentPhase, configloader.FieldPlatform, platformErr.Error())
+		e.log.Warnf(logger.WithErrorField(ctx, platformErr), "Phase %s: failed to resolve platform", result.CurrentPhase)
+	}
 	postResults, err := e.postActionExecutor.ExecuteAll(ctx, postConfig, execCtx)
 	result.PostActionResults = postResults
 
 	if err != nil {
-		if apierrors.IsResourceNotFoundError(err) {
+		if isResourceNotFoundOnly(err) {
 			// Resource no longer exists. Log and continue, don't fail.
 			e.log.Infof(ctx, "Phase %s: resource not found, skipping remaining post-actions",
 				result.CurrentPhase)
@@ -211,24 +433,25 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 			// ResourceNotFound takes precedence: the resource no longer exists,
 			// making the original skip reason moot.
 			result.SkipReason = ResourceNotFoundReason
-			// The PostActionExecutor set the step to StatusFailed before the error
+			// The PostActionExecutor set the steps to StatusFailed before the error
 			// reached us. Now that we've decided this 404 is a graceful stop (not a
-			// real failure), correct the step to match that decision.
-			if len(result.PostActionResults) > 0 {
-				last := &result.PostActionResults[len(result.PostActionResults)-1]
-				last.Status = StatusSkipped
-				last.Skipped = true
-				last.SkipReason = ResourceNotFoundReason
-				last.Error = nil
+			// real failure), correct the steps to match that decision.
+			for i := range result.PostActionResults {
+				r := &result.PostActionResults[i]
+				if r.Error != nil && apierrors.IsResourceNotFoundError(r.Error) {
+					r.Status = StatusSkipped
+					r.Skipped = true
+					r.SkipReason = ResourceNotFoundReason
+					r.Error = nil
+				}
 			}
 			if result.Status == StatusSuccess {
 				execCtx.SetSkipped(ResourceNotFoundReason, "")
-				execCtx.Adapter.ExecutionError = nil
+				execCtx.ClearExecutionError()
 			}
 		} else {
 			result.Status = StatusFailed
-			postErr := fmt.Errorf("post action execution failed: %w", err)
-			result.Errors[result.CurrentPhase] = postErr
+			result.Errors.Add(result.CurrentPhase, "post action execution failed", err)
 			errCtx := logger.WithErrorField(ctx, err)
 			e.log.Errorf(errCtx, "Phase %s: FAILED", result.CurrentPhase)
 		}
@@ -245,36 +468,78 @@ func (e *Executor) Execute(ctx context.Context, data interface{}) *ExecutionResu
 			result.ResourcesSkipped, result.SkipReason)
 	} else {
 		// Combine all errors into a single error for logging
-		var errMsgs []string
-		for phase, err := range result.Errors {
-			errMsgs = append(errMsgs, fmt.Sprintf("%s: %v", phase, err))
-		}
-		combinedErr := fmt.Errorf("execution failed: %s", strings.Join(errMsgs, "; "))
+		combinedErr := fmt.Errorf("execution failed: %s", result.Errors)
 		errCtx := logger.WithErrorField(ctx, combinedErr)
 		e.log.Errorf(errCtx, "Event execution finished: event_execution_status=failed")
 	}
 	return result
 }
 
+// reportingContext returns the context for the post-actions phase. It keeps the parent's
+// values (logger fields, trace span) but not its cancellation, so status reporting is not
+// lost when processing is canceled or times out; ReportingTimeout bounds it instead.
+func reportingContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), ReportingTimeout)
+}
+
+// isResourceNotFoundOnly reports whether err is a resource not found API error, or errors
+// joined by the reporting phase that all are
+func isResourceNotFoundOnly(err error) bool {
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, e := range joined.Unwrap() {
+			if !isResourceNotFoundOnly(e) {
+				return false
+			}
+		}
+		return true
+	}
+	return apierrors.IsResourceNotFoundError(err)
+}
+
+// timeoutReason returns the error reason and error of a failed phase: reason and err,
+// or ExecutionTimeoutReason and err annotated with the timeout when ctx ran past the
+// execution timeout
+func timeoutReason(ctx context.Context, reason string, err error) (string, error) {
+	cause := context.Cause(ctx)
+	if !errors.Is(cause, errExecutionTimeout) {
+		return reason, err
+	}
+	return ExecutionTimeoutReason, fmt.Errorf("%s: %w", cause, err)
+}
+
+// recoverPhase runs fn and converts a panic into an error, so a bug in one phase
+// cannot prevent the post-actions phase from reporting status.
+func recoverPhase(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", errPhasePanic, r)
+		}
+	}()
+	fn()
+	return nil
+}
+
 // executeParamExtraction extracts parameters from the event and environment
 func (e *Executor) executeParamExtraction(execCtx *ExecutionContext) error {
-	configMap, err := configToMap(e.config.Config)
+	configMap, err := configToMap(execCtx.Config)
 	if err != nil {
 		return NewExecutorError(PhaseParamExtraction, "config", "failed to marshal config", err)
 	}
 
 	// Use a redacted config map for template-accessible params to avoid exposing sensitive
 	// values (e.g. TLS cert paths) in rendered manifests or logs.
-	redactedMap, err := configToMap(e.config.Config.Redacted())
+	redactedMap, err := configToMap(execCtx.Config.Redacted())
 	if err != nil {
 		return NewExecutorError(PhaseParamExtraction, "config", "failed to marshal redacted config", err)
 	}
 
-	addAdapterParams(e.config.Config, execCtx, redactedMap)
+	addAdapterParams(execCtx.Config, execCtx, redactedMap)
 
 	// config.* param sources resolve against the real (unredacted) config so that
 	// sensitive fields like cert paths can still be explicitly extracted when needed.
-	return extractConfigParams(execCtx.Ctx, e.config.Config, execCtx, configMap, e.config.APIClient, e.log)
+	return extractConfigParams(execCtx.Ctx, execCtx.Config, execCtx, configMap,
+		e.config.APIClient, e.config.SecretProvider, e.log)
 }
 
 // startTracedExecution creates an OTel span and adds trace context to logs.
@@ -285,7 +550,7 @@ func (e *Executor) executeParamExtraction(execCtx *ExecutionContext) error {
 //   - Adds trace_id and span_id to logger context (for log correlation)
 //   - The trace context is automatically propagated to outgoing HTTP requests
 func (e *Executor) startTracedExecution(ctx context.Context) (context.Context, trace.Span) {
-	componentName := e.config.Config.Adapter.Name
+	componentName := e.Config().Adapter.Name
 	ctx, span := otel.Tracer(componentName).Start(ctx, "Execute")
 
 	// Add trace_id and span_id to logger context for log correlation
@@ -305,11 +570,15 @@ func (e *Executor) CreateHandler() HandlerFunc {
 		// include traceparent/tracestate in the CloudEvent
 		ctx = pkgotel.ExtractTraceContextFromCloudEvent(ctx, evt)
 
+		// Parse the event data once for Execute and publishResult
+		ctx, _ = withParsedEvent(ctx, evt)
+
 		// Log event metadata
 		e.log.Infof(ctx, "Event received: id=%s type=%s source=%s time=%s",
 			evt.ID(), evt.Type(), evt.Source(), evt.Time())
 
-		result := e.Execute(ctx, evt.Data())
+		result := e.Execute(ctx, evt)
+		e.publishResult(ctx, evt, result)
 
 		e.log.Infof(ctx, "Event processed: type=%s source=%s time=%s",
 			evt.Type(), evt.Source(), evt.Time())
@@ -319,9 +588,21 @@ func (e *Executor) CreateHandler() HandlerFunc {
 }
 
 // ParseEventData parses event data from various input types into structured EventData and raw map.
-// Accepts: []byte (JSON), map[string]interface{}, or any JSON-serializable type.
+// Accepts: a CloudEvent (*event.Event or event.Event), []byte (JSON), map[string]interface{},
+// or any JSON-serializable type.
 // Returns: structured EventData, raw map for flexible access, and any error.
+//
+// For CloudEvents the datacontenttype decides how the data is read: JSON types are parsed,
+// text/* payloads are passed through unparsed as rawData[EventRawKey], and any other type
+// fails with an *InvalidCloudEventError. Data sent as data_base64 is decoded by the
+// CloudEvents SDK; a JSON data value that is a base64 string of a JSON object is decoded too.
 func ParseEventData(data interface{}) (*EventData, map[string]interface{}, error) {
+	return parseEventData(data, false)
+}
+
+// parseEventData is ParseEventData, decoding the integers of the raw map as int64 when
+// strictNumbers is set
+func parseEventData(data interface{}, strictNumbers bool) (*EventData, map[string]interface{}, error) {
 	if data == nil {
 		return &EventData{}, make(map[string]interface{}), nil
 	}
@@ -330,6 +611,13 @@ func ParseEventData(data interface{}) (*EventData, map[string]interface{}, error
 	var err error
 
 	switch v := data.(type) {
+	case *event.Event:
+		if v == nil {
+			return &EventData{}, make(map[string]interface{}), nil
+		}
+		return parseCloudEventData(v.DataContentType(), v.Data(), strictNumbers)
+	case event.Event:
+		return parseCloudEventData(v.DataContentType(), v.Data(), strictNumbers)
 	case []byte:
 		if len(v) == 0 {
 			return &EventData{}, make(map[string]interface{}), nil
@@ -349,6 +637,85 @@ func ParseEventData(data interface{}) (*EventData, map[string]interface{}, error
 		}
 	}
 
+	return parseJSONEventData(jsonBytes, strictNumbers)
+}
+
+// parsedEventKey is the context key of the parsedEvent of the CloudEvent being handled
+type parsedEventKey struct{}
+
+// parsedEvent is the ParseEventData result for a CloudEvent. It is kept in the context so
+// the worker pool, the handler middlewares and the executor parse the event data once.
+// The parsed data is shared and must not be modified.
+type parsedEvent struct {
+	err  error
+	evt  *event.Event
+	data *EventData
+	raw  map[string]interface{}
+}
+
+// withParsedEvent returns the parse of evt held by ctx. When ctx holds none for evt, evt
+// is parsed and the returned context holds the result.
+func withParsedEvent(ctx context.Context, evt *event.Event) (context.Context, *parsedEvent) {
+	if parsed, ok := ctx.Value(parsedEventKey{}).(*parsedEvent); ok && parsed.evt == evt {
+		return ctx, parsed
+	}
+	data, raw, err := ParseEventData(evt)
+	parsed := &parsedEvent{evt: evt, data: data, raw: raw, err: err}
+	return context.WithValue(ctx, parsedEventKey{}, parsed), parsed
+}
+
+// parseCloudEventData parses CloudEvent data according to its datacontenttype
+func parseCloudEventData(
+	contentType string,
+	data []byte,
+	strictNumbers bool,
+) (*EventData, map[string]interface{}, error) {
+	if len(data) == 0 {
+		return &EventData{}, make(map[string]interface{}), nil
+	}
+
+	mediaType := ""
+	if contentType != "" {
+		parsed, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			return nil, nil, &InvalidCloudEventError{ContentType: contentType, Err: err}
+		}
+		mediaType = parsed
+	}
+
+	switch {
+	case isJSONMediaType(mediaType):
+		eventData, rawData, err := parseJSONEventData(data, strictNumbers)
+		if err != nil {
+			return nil, nil, &InvalidCloudEventError{ContentType: contentType, Err: err}
+		}
+		return eventData, rawData, nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return &EventData{}, map[string]interface{}{EventRawKey: string(data)}, nil
+	default:
+		return nil, nil, &InvalidCloudEventError{ContentType: contentType, Err: errUnsupportedContentType}
+	}
+}
+
+// isJSONMediaType reports whether mediaType is JSON. An empty type defaults to JSON
+// as in the CloudEvents JSON format.
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "" || mediaType == event.ApplicationJSON || mediaType == event.TextJSON ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+// parseJSONEventData parses a JSON object into EventData and a raw map. A JSON string
+// holding a base64-encoded JSON object is decoded first.
+func parseJSONEventData(jsonBytes []byte, strictNumbers bool) (*EventData, map[string]interface{}, error) {
+	var encoded string
+	if json.Unmarshal(jsonBytes, &encoded) == nil {
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, nil, errors.New("event data is a string, not a JSON object or base64-encoded JSON")
+		}
+		jsonBytes = decoded
+	}
+
 	// Parse into structured EventData
 	var eventData EventData
 	if err := json.Unmarshal(jsonBytes, &eventData); err != nil {
@@ -356,8 +723,8 @@ func ParseEventData(data interface{}) (*EventData, map[string]interface{}, error
 	}
 
 	// Parse into raw map for flexible access
-	var rawData map[string]interface{}
-	if err := json.Unmarshal(jsonBytes, &rawData); err != nil {
+	rawData, err := utils.UnmarshalJSONObject(jsonBytes, strictNumbers)
+	if err != nil {
 		return nil, nil, fmt.Errorf("failed to unmarshal to map: error=%w", err)
 	}
 
@@ -406,6 +773,34 @@ func (b *ExecutorBuilder) WithMetricsRecorder(recorder *metrics.Recorder) *Execu
 	return b
 }
 
+// WithResultPublisher sets the optional publisher used to fan out execution result
+// summaries as CloudEvents to the given topic
+func (b *ExecutorBuilder) WithResultPublisher(publisher ResultPublisher, topic string) *ExecutorBuilder {
+	b.config.ResultPublisher = publisher
+	b.config.ResultTopic = topic
+	return b
+}
+
+// WithSecretProvider sets the optional provider that resolves vault.* param sources
+func (b *ExecutorBuilder) WithSecretProvider(secrets SecretProvider) *ExecutorBuilder {
+	b.config.SecretProvider = secrets
+	return b
+}
+
+// WithManifestProcessor adds processors run on the rendered manifests before they are
+// applied, after the processors already added
+func (b *ExecutorBuilder) WithManifestProcessor(processors ...ManifestProcessor) *ExecutorBuilder {
+	b.config.ManifestProcessors = append(b.config.ManifestProcessors, processors...)
+	return b
+}
+
+// WithTaskName sets the task name the executor reports its config under in config_info,
+// for a subscription executor with its own task config
+func (b *ExecutorBuilder) WithTaskName(name string) *ExecutorBuilder {
+	b.config.TaskName = name
+	return b
+}
+
 // Build creates the Executor
 func (b *ExecutorBuilder) Build() (*Executor, error) {
 	return NewExecutor(b.config)
@@ -1,24 +1,32 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dedup"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sharding"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sli"
 	apierrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
@@ -200,6 +208,28 @@ func TestExecutionContext_SetError(t *testing.T) {
 	assert.Equal(t, "Test message", execCtx.Adapter.ErrorMessage)
 }
 
+func TestExecutionContext_RuntimeLimits(t *testing.T) {
+	config := &configloader.Config{
+		Limits: configloader.LimitsConfig{MaxSteps: 3, MaxVariables: 2},
+		Params: []configloader.Parameter{{Name: "a"}, {Name: "b"}},
+	}
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, config)
+
+	require.NoError(t, execCtx.SetVariable("a", 1))
+	require.NoError(t, execCtx.SetVariable("b", 2))
+	require.NoError(t, execCtx.SetVariable("a", 3), "setting a variable again does not count")
+	err := execCtx.SetVariable("c", 4)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "limits.max_variables")
+	_, ok := execCtx.GetParam("c")
+	assert.False(t, ok)
+
+	require.NoError(t, execCtx.AddExpandedSteps("items", 1))
+	err = execCtx.AddExpandedSteps("items", 1)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `step "items" expands the event to 4 steps, limit (limits.max_steps) is 3`)
+}
+
 func TestExecutionContext_EvaluationTracking(t *testing.T) {
 	ctx := context.Background()
 	execCtx := NewExecutionContext(ctx, map[string]interface{}{}, nil)
@@ -363,6 +393,186 @@ func TestExecute_ParamExtraction(t *testing.T) {
 	}
 }
 
+// TestExecute_DebugClusters verifies that only the events of the listed clusters are
+// logged at debug level
+func TestExecute_DebugClusters(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := logger.NewLogger(logger.Config{Level: "info", Format: logger.FormatText, Writer: &buf})
+	require.NoError(t, err)
+
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		Log:     configloader.LogConfig{DebugClusters: []string{"cluster-debug"}},
+	}
+	exec, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(newMockAPIClient()).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(log).
+		Build()
+	require.NoError(t, err)
+
+	exec.Execute(context.Background(), map[string]interface{}{"kind": "Cluster", "id": "cluster-other"})
+	assert.NotContains(t, buf.String(), "level=DEBUG", "other clusters keep the configured level")
+
+	exec.Execute(context.Background(), map[string]interface{}{"kind": "Cluster", "id": "cluster-debug"})
+	assert.Contains(t, buf.String(), "level=DEBUG")
+	assert.Contains(t, buf.String(), "cluster_id=cluster-debug")
+
+	buf.Reset()
+	exec.Execute(context.Background(), map[string]interface{}{
+		"kind":             "NodePool",
+		"id":               "nodepool-1",
+		"owner_references": map[string]interface{}{"kind": "Cluster", "id": "cluster-debug"},
+	})
+	assert.Contains(t, buf.String(), "level=DEBUG", "events of resources owned by the cluster are elevated too")
+}
+
+func TestExecutor_SwapConfig(t *testing.T) {
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		Params: []configloader.Parameter{
+			{Name: "oldParam", Source: configloader.StringSource("event.id")},
+		},
+	}
+	exec := build404TestExecutor(t, config, newMockAPIClient())
+
+	reloaded := *config
+	reloaded.Params = []configloader.Parameter{
+		{Name: "newParam", Source: configloader.StringSource("event.id")},
+	}
+	oldHash, oldFingerprint := exec.ConfigHash(), exec.ConfigFingerprint()
+	assert.Equal(t, config.TaskConfigHash(), oldHash)
+	assert.Equal(t, config.ConfigFingerprint(), oldFingerprint)
+	require.NoError(t, exec.SwapConfig(&reloaded))
+	assert.Same(t, &reloaded, exec.Config())
+	assert.NotEqual(t, oldHash, exec.ConfigHash())
+	assert.NotEqual(t, oldFingerprint, exec.ConfigFingerprint())
+
+	result := exec.Execute(context.Background(), map[string]interface{}{"id": "cluster-1"})
+	assert.Equal(t, "cluster-1", result.Params["newParam"])
+	assert.NotContains(t, result.Params, "oldParam")
+	assert.Equal(t, exec.ConfigHash(), result.ConfigHash)
+	assert.Equal(t, exec.ConfigFingerprint(), result.ConfigFingerprint)
+
+	// Invalid configs are rejected and the current config is kept
+	assert.Error(t, exec.SwapConfig(nil))
+	tooMany := reloaded
+	tooMany.Limits = configloader.LimitsConfig{MaxSteps: 1}
+	tooMany.Resources = []configloader.Resource{{Name: "a"}, {Name: "b"}}
+	assert.Error(t, exec.SwapConfig(&tooMany))
+	assert.Same(t, &reloaded, exec.Config())
+}
+
+func TestExecutor_ConfigInfoPerTask(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test-adapter", "v0.1.0", "test", registry)
+	newExecutor := func(config *configloader.Config, task string) *Executor {
+		exec, err := NewBuilder().
+			WithConfig(config).
+			WithAPIClient(newMockAPIClient()).
+			WithTransportClient(k8sclient.NewMockK8sClient()).
+			WithLogger(logger.NewTestLogger()).
+			WithMetricsRecorder(recorder).
+			WithTaskName(task).
+			Build()
+		require.NoError(t, err)
+		return exec
+	}
+	configWithParam := func(param string) *configloader.Config {
+		return &configloader.Config{
+			Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+			Params:  []configloader.Parameter{{Name: param, Source: configloader.StringSource("event.id")}},
+		}
+	}
+
+	mainExec := newExecutor(configWithParam("clusterId"), "")
+	subExec := newExecutor(configWithParam("nodePoolId"), "tasks/nodepool.yaml")
+	require.NoError(t, mainExec.SwapConfig(configWithParam("reloadedClusterId")))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	hashes := make(map[string]string)
+	for _, f := range families {
+		if f.GetName() != "hyperfleet_adapter_config_info" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			hashes[labels["task"]] = labels["config_hash"]
+		}
+	}
+	assert.Equal(t, map[string]string{
+		metrics.ConfigTaskMain: mainExec.ConfigHash(),
+		"tasks/nodepool.yaml":  subExec.ConfigHash(),
+	}, hashes, "a reload of the main config should not replace the subscription's config")
+}
+
+// swapOnGetClient calls onGet before every GET, to act while an execution is running
+type swapOnGetClient struct {
+	*hyperfleetapi.MockClient
+	onGet func()
+}
+
+func (c *swapOnGetClient) Get(
+	ctx context.Context,
+	url string,
+	opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	c.onGet()
+	return c.MockClient.Get(ctx, url, opts...)
+}
+
+func TestExecutor_SwapConfigDuringExecution(t *testing.T) {
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		Clients: configloader.ClientsConfig{
+			HyperfleetAPI: configloader.HyperfleetAPIConfig{BaseURL: "http://mock-api:8000", Version: "v1"},
+		},
+		Params: []configloader.Parameter{
+			{Name: "oldParam", Source: configloader.StringSource("event.id")},
+		},
+		Preconditions: []configloader.Precondition{
+			{ActionBase: configloader.ActionBase{
+				Name:    "fetch",
+				APICall: &configloader.APICall{Method: "GET", URL: "/clusters/{{ .oldParam }}"},
+			}},
+		},
+	}
+
+	reloaded := *config
+	reloaded.Params = []configloader.Parameter{
+		{Name: "newParam", Source: configloader.StringSource("event.id")},
+	}
+	reloaded.Preconditions = nil
+
+	// Swap the config while the first execution is in its precondition API call
+	mockClient := newMockAPIClient()
+	mockClient.GetResponse = &hyperfleetapi.Response{StatusCode: 200, Status: "200 OK", Body: []byte(`{}`)}
+	client := &swapOnGetClient{MockClient: mockClient}
+	exec, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(client).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+	client.onGet = func() { require.NoError(t, exec.SwapConfig(&reloaded)) }
+	oldHash := exec.ConfigHash()
+
+	inFlight := exec.Execute(context.Background(), map[string]interface{}{"id": "cluster-1"})
+	require.Equal(t, StatusSuccess, inFlight.Status)
+	assert.Contains(t, inFlight.Params, "oldParam")
+	assert.Equal(t, oldHash, inFlight.ConfigHash, "in-flight execution keeps its config version")
+
+	next := exec.Execute(context.Background(), map[string]interface{}{"id": "cluster-1"})
+	assert.Contains(t, next.Params, "newParam")
+	assert.Equal(t, reloaded.TaskConfigHash(), next.ConfigHash)
+}
+
 // TestExecute_ParamsAPICallSource verifies the full executor pipeline when params use
 // api_call and expression sources
 func TestExecute_ParamsAPICallSource(t *testing.T) {
@@ -566,7 +776,7 @@ func TestParamExtractor(t *testing.T) {
 			// Extract params using pure function
 			configMap, err := configToMap(config)
 			require.NoError(t, err)
-			err = extractConfigParams(context.Background(), config, execCtx, configMap, nil, logger.NewTestLogger())
+			err = extractConfigParams(context.Background(), config, execCtx, configMap, nil, nil, logger.NewTestLogger())
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -584,6 +794,29 @@ func TestParamExtractor(t *testing.T) {
 	}
 }
 
+func TestParamExtractor_Warnings(t *testing.T) {
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test", Version: "1.0.0"},
+		Params: []configloader.Parameter{
+			{Name: "withDefault", Source: configloader.StringSource("event.missing"), Default: "fallback"},
+			{Name: "withoutDefault", Source: configloader.StringSource("event.missing")},
+			{Name: "badInt", Source: configloader.StringSource("event.id"), Type: "int", Default: int64(1)},
+		},
+	}
+
+	execCtx, err := runParamExtraction(t, config, nil, map[string]interface{}{"id": "not-a-number"})
+	require.NoError(t, err)
+
+	warnings := execCtx.WarningsSnapshot()
+	require.Len(t, warnings, 2, "a configured default is not a warning")
+	assert.Equal(t, PhaseParamExtraction, warnings[0].Phase)
+	assert.Equal(t, "withoutDefault", warnings[0].Step)
+	assert.Contains(t, warnings[0].Message, "leaving it unset")
+	assert.Equal(t, "badInt", warnings[1].Step)
+	assert.Contains(t, warnings[1].Message, "using default 1")
+	assert.Equal(t, int64(1), execCtx.Params["badInt"])
+}
+
 // runParamExtraction is a test helper that wires up the full param extraction
 // pipeline
 func runParamExtraction(
@@ -594,7 +827,7 @@ func runParamExtraction(
 	configMap, err := configToMap(config)
 	require.NoError(t, err)
 	addAdapterParams(config, execCtx, configMap)
-	err = extractConfigParams(context.Background(), config, execCtx, configMap, mockClient, logger.NewTestLogger())
+	err = extractConfigParams(context.Background(), config, execCtx, configMap, mockClient, nil, logger.NewTestLogger())
 	return execCtx, err
 }
 
@@ -697,6 +930,25 @@ func TestParamExtractor_APICallSource(t *testing.T) {
 		assert.Equal(t, true, execCtx.Params["isActive"])
 	})
 
+	t.Run("transform replaces the response map with its result", func(t *testing.T) {
+		mockClient := newMockAPIClient()
+		mockClient.GetResponse = &hyperfleetapi.Response{StatusCode: 200, Body: []byte(responseBody)}
+
+		config := &configloader.Config{
+			Params: []configloader.Parameter{
+				{Name: "clusterData", Source: configloader.APICallSource(&configloader.APICall{
+					Method:    "GET",
+					URL:       "/clusters/x",
+					Transform: `{"name": name, "active": status.phase == "Active"}`,
+				})},
+			},
+		}
+		execCtx, err := runParamExtraction(t, config, mockClient, eventData)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]interface{}{"name": "my-cluster", "active": true}, execCtx.Params["clusterData"])
+	})
+
 	t.Run("required api_call failure returns error", func(t *testing.T) {
 		mockClient := newMockAPIClient()
 		mockClient.GetError = fmt.Errorf("connection refused")
@@ -1258,7 +1510,7 @@ func TestSequentialExecution_PostActions(t *testing.T) {
 			// Verify error expectation
 			if tt.expectError {
 				assert.NotEmpty(t, result.Errors, "expected errors, got none")
-				assert.NotNil(t, result.Errors[PhasePostActions], "expected post_actions error, got %#v", result.Errors)
+				assert.NotNil(t, result.Errors.Phase(PhasePostActions), "expected post_actions error, got %#v", result.Errors)
 			} else {
 				assert.Empty(t, result.Errors, "expected no errors, got %#v", result.Errors)
 			}
@@ -1501,7 +1753,7 @@ func TestWithMetrics_RecordsMetrics(t *testing.T) {
 			name: "failed",
 			result: &ExecutionResult{
 				Status: StatusFailed,
-				Errors: map[ExecutionPhase]error{PhaseParamExtraction: fmt.Errorf("error")},
+				Errors: ExecutionErrors{newPhaseError(PhaseParamExtraction, fmt.Errorf("error"))},
 			},
 			expectedStatus: "failed",
 		},
@@ -1553,6 +1805,83 @@ func TestWithMetrics_RecordsMetrics(t *testing.T) {
 	}
 }
 
+// TestWithMetrics_RecordsStepMetrics verifies per-step statuses, durations, warnings and
+// skip reasons
+func TestWithMetrics_RecordsStepMetrics(t *testing.T) {
+	result := &ExecutionResult{
+		Status: StatusSuccess,
+		PreconditionResults: []PreconditionResult{
+			{Name: "clusterStatus", Status: StatusSuccess, Matched: false, Duration: 50 * time.Millisecond},
+		},
+		ResourceResults: []ResourceResult{
+			{Name: "namespace", Status: StatusSuccess, Operation: manifest.OperationSkip},
+			{Name: "oldConfigMap", Status: StatusSuccess, OperationReason: pruneReason, Duration: time.Second},
+			{Name: "agent", Status: StatusSkipped, Operation: manifest.OperationSkip},
+		},
+		PostActionResults: []PostActionResult{
+			{Name: "reportStatus", Status: StatusFailed, Duration: 2 * time.Second},
+			{Name: "notify", Status: StatusSkipped, Skipped: true},
+		},
+		Warnings: []ExecutionWarning{
+			{Phase: PhasePreconditions, Step: "clusterStatus", Message: "capture missed"},
+		},
+	}
+
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test-adapter", "v0.1.0", "test", registry)
+	inner := HandlerFunc(func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		return result, nil
+	})
+	handler := WithMetrics(inner, recorder, logger.NewTestLogger())
+
+	evt := event.New()
+	evt.SetID("test-step-metrics")
+	_, err := handler(context.Background(), &evt)
+	require.NoError(t, err)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	statuses := make(map[string]string)
+	for _, m := range findFamily(families, "hyperfleet_adapter_steps_total").GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		statuses[labels["step"]] = labels["step_type"] + "/" + labels["status"]
+	}
+	assert.Equal(t, map[string]string{
+		"clusterStatus": "precondition/not_met",
+		"namespace":     "resource/skipped",
+		"oldConfigMap":  "prune/success",
+		"reportStatus":  "post_action/failed",
+		"agent":         "resource/skipped",
+		"notify":        "post_action/skipped",
+	}, statuses)
+
+	durationFamily := findFamily(families, "hyperfleet_adapter_step_duration_seconds")
+	require.NotNil(t, durationFamily)
+	assert.Len(t, durationFamily.GetMetric(), 3, "skipped steps should not observe a duration")
+
+	skips := make(map[string]string)
+	for _, m := range findFamily(families, "hyperfleet_adapter_skips_total").GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		skips[labels["step"]] = labels["reason"]
+	}
+	assert.Equal(t, map[string]string{
+		"clusterStatus": "filtered",
+		"namespace":     "generation_unchanged",
+		"agent":         "when_false",
+		"notify":        "when_false",
+	}, skips)
+
+	assert.Equal(t, float64(1),
+		getCounterValue(t, families, "hyperfleet_adapter_step_warnings_total", "step", "clusterStatus"))
+}
+
 // TestWithMetrics_HandlerPanicPropagates verifies a panic in handler is not swallowed by WithMetrics
 func TestWithMetrics_HandlerPanicPropagates(t *testing.T) {
 	inner := HandlerFunc(func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
@@ -1597,6 +1926,265 @@ func TestWithMetrics_MetricsPanicRecovered(t *testing.T) {
 	assert.NotNil(t, got, "result must still be returned after metrics panic")
 }
 
+// TestWithSharding_SkipsEventsOfOtherShards verifies that only events of owned resources
+// reach the inner handler, keyed by the owner's ID when present
+func TestWithSharding_SkipsEventsOfOtherShards(t *testing.T) {
+	t.Setenv(sharding.EnvShardIndex, "0")
+	sharder, err := sharding.New(context.Background(),
+		configloader.ShardingConfig{Enabled: true, Count: 2}, nil, logger.NewTestLogger())
+	require.NoError(t, err)
+
+	// Find resource IDs owned by shard 0 and shard 1
+	owned, other := "", ""
+	for i := 0; owned == "" || other == ""; i++ {
+		id := fmt.Sprintf("cluster-%d", i)
+		if sharding.ShardOf(id, 2) == 0 {
+			owned = id
+		} else {
+			other = id
+		}
+	}
+
+	var calls int
+	inner := HandlerFunc(func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		calls++
+		return &ExecutionResult{Status: StatusSuccess}, nil
+	})
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test-adapter", "v0.1.0", "test", registry)
+	handler := WithSharding(inner, sharder, recorder, logger.NewTestLogger())
+
+	send := func(data map[string]interface{}) *ExecutionResult {
+		evt := event.New()
+		evt.SetID("test-shard")
+		evt.SetType("com.hyperfleet.test")
+		evt.SetSource("test")
+		require.NoError(t, evt.SetData(event.ApplicationJSON, data))
+		result, err := handler(context.Background(), &evt)
+		require.NoError(t, err)
+		return result
+	}
+
+	send(map[string]interface{}{"id": owned, "kind": "Cluster"})
+	assert.Equal(t, 1, calls)
+
+	result := send(map[string]interface{}{"id": other, "kind": "Cluster"})
+	assert.Equal(t, 1, calls, "event of another shard must not be executed")
+	assert.True(t, result.ResourcesSkipped)
+	assert.Equal(t, float64(1), gatherCounter(t, registry, "hyperfleet_adapter_skips_total"),
+		"events of other shards should be counted as filtered")
+
+	// Node pools follow their owning cluster
+	send(map[string]interface{}{
+		"id": other, "kind": "NodePool", "owner_references": map[string]interface{}{"id": owned},
+	})
+	assert.Equal(t, 2, calls)
+}
+
+func TestWithEventFilter(t *testing.T) {
+	var calls int
+	inner := HandlerFunc(func(ctx context.Context, _ *event.Event) (*ExecutionResult, error) {
+		calls++
+		assert.NotNil(t, ctx.Value(parsedEventKey{}), "the filter should pass its parse of the event on")
+		return &ExecutionResult{Status: StatusSuccess}, nil
+	})
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test-adapter", "v0.1.0", "test", registry)
+	handler := WithEventFilter(inner, "subscription filter", `type.endsWith(".created") && data.kind == "NodePool"`,
+		recorder, logger.NewTestLogger())
+
+	send := func(eventType string, data map[string]interface{}) *ExecutionResult {
+		evt := event.New()
+		evt.SetID("test-filter")
+		evt.SetType(eventType)
+		evt.SetSource("test")
+		require.NoError(t, evt.SetData(event.ApplicationJSON, data))
+		result, err := handler(context.Background(), &evt)
+		require.NoError(t, err)
+		return result
+	}
+
+	send("io.hyperfleet.nodepool.created", map[string]interface{}{"id": "np-1", "kind": "NodePool"})
+	assert.Equal(t, 1, calls)
+
+	result := send("io.hyperfleet.cluster.created", map[string]interface{}{"id": "c-1", "kind": "Cluster"})
+	assert.Equal(t, 1, calls, "an event not matching the filter must not be executed")
+	assert.True(t, result.ResourcesSkipped)
+
+	send("io.hyperfleet.nodepool.created", map[string]interface{}{"id": "np-2"})
+	assert.Equal(t, 1, calls, "a filter that cannot be evaluated should skip the event")
+	assert.Equal(t, float64(2), gatherCounter(t, registry, "hyperfleet_adapter_skips_total"))
+}
+
+func TestWithEventFilter_Attributes(t *testing.T) {
+	var calls int
+	inner := HandlerFunc(func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		calls++
+		return &ExecutionResult{Status: StatusSuccess}, nil
+	})
+	handler := WithEventFilter(inner, "event filter",
+		`subject.startsWith("clusters/") && extensions.?region.orValue("") == "us-east-1" &&
+			extensions.?priority.orValue(0) > 1`,
+		nil, logger.NewTestLogger())
+
+	send := func(subject string, extensions map[string]interface{}) *ExecutionResult {
+		evt := event.New()
+		evt.SetID("test-filter")
+		evt.SetType("io.hyperfleet.cluster.updated")
+		evt.SetSource("test")
+		evt.SetSubject(subject)
+		for name, value := range extensions {
+			evt.SetExtension(name, value)
+		}
+		require.NoError(t, evt.SetData("text/plain", []byte("reconcile")))
+		result, err := handler(context.Background(), &evt)
+		require.NoError(t, err)
+		return result
+	}
+
+	send("clusters/c1", map[string]interface{}{"region": "us-east-1", "priority": 2})
+	assert.Equal(t, 1, calls, "a text event should be filtered on its attributes")
+
+	result := send("clusters/c1", map[string]interface{}{"region": "eu-west-1", "priority": 2})
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "event does not match the event filter", result.SkipReason)
+
+	send("nodepools/np1", map[string]interface{}{"region": "us-east-1", "priority": 2})
+	send("clusters/c1", map[string]interface{}{"region": "us-east-1"})
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithEventRouting(t *testing.T) {
+	var calls int
+	inner := func(_ context.Context, _ *event.Event) error {
+		calls++
+		return nil
+	}
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test-adapter", "v0.1.0", "test", registry)
+	handler := WithEventRouting(inner, configloader.EventRoutingConfig{
+		Types:    []string{"io.hyperfleet.cluster.*"},
+		Subjects: []string{"clusters/*"},
+	}, recorder, logger.NewTestLogger())
+
+	send := func(eventType, subject string) {
+		evt := event.New()
+		evt.SetID("test-routing")
+		evt.SetType(eventType)
+		evt.SetSource("test")
+		evt.SetSubject(subject)
+		// Data that does not decode shows that unrouted events are not parsed
+		require.NoError(t, evt.SetData(event.ApplicationJSON, []byte("{not json")))
+		require.NoError(t, handler(context.Background(), &evt))
+	}
+
+	send("io.hyperfleet.cluster.updated", "clusters/c1")
+	assert.Equal(t, 1, calls)
+
+	send("io.hyperfleet.nodepool.updated", "clusters/c1")
+	send("io.hyperfleet.cluster.updated", "clusters/c1/nodepools/np1")
+	assert.Equal(t, 1, calls, "events of other types and subjects should be acked without handling")
+	assert.Equal(t, float64(2), gatherCounter(t, registry, "hyperfleet_adapter_skips_total"))
+
+	unwrapped := WithEventRouting(inner, configloader.EventRoutingConfig{}, recorder, logger.NewTestLogger())
+	require.NoError(t, unwrapped(context.Background(), &event.Event{}))
+	assert.Equal(t, 2, calls, "no routing should handle every event")
+}
+
+func TestWithDeduplication_SkipsCompletedEvents(t *testing.T) {
+	status := StatusFailed
+	var calls int
+	inner := HandlerFunc(func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		calls++
+		return &ExecutionResult{Status: status}, nil
+	})
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test-adapter", "v0.1.0", "test", registry)
+	handler := WithMetrics(WithDeduplication(inner, dedup.NewMemoryStore(time.Hour, 10), logger.NewTestLogger()),
+		recorder, logger.NewTestLogger())
+
+	send := func(id string, generation int64) *ExecutionResult {
+		evt := event.New()
+		evt.SetID(id)
+		evt.SetType("com.hyperfleet.test")
+		evt.SetSource("test")
+		require.NoError(t, evt.SetData(event.ApplicationJSON, map[string]interface{}{
+			"id": "cluster-1", "kind": "Cluster", "generation": generation,
+		}))
+		result, err := handler(context.Background(), &evt)
+		require.NoError(t, err)
+		return result
+	}
+
+	send("event-1", 1)
+	send("event-1", 1)
+	assert.Equal(t, 2, calls, "failed events must be retried on redelivery")
+
+	status = StatusSuccess
+	send("event-1", 1)
+	result := send("event-1", 1)
+	assert.Equal(t, 3, calls, "completed event must not be executed again")
+	assert.True(t, result.ResourcesSkipped)
+	assert.Equal(t, "event already completed", result.SkipReason)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	assert.Equal(t, float64(1),
+		getCounterValue(t, families, "hyperfleet_adapter_skips_total", "reason", "stale_event"))
+
+	send("event-1", 2)
+	send("event-2", 1)
+	assert.Equal(t, 5, calls, "a new generation or event ID is executed")
+}
+
+func TestWithSLI_RecordsReportsByFreshness(t *testing.T) {
+	tracker, err := sli.New(configloader.SLIConfig{Enabled: true}, "test", "v0", "test",
+		prometheus.NewRegistry(), logger.NewTestLogger())
+	require.NoError(t, err)
+
+	reported := true
+	inner := HandlerFunc(func(_ context.Context, _ *event.Event) (*ExecutionResult, error) {
+		result := &ExecutionResult{Status: StatusSuccess}
+		if reported {
+			result.PostActionResults = []PostActionResult{{Name: "report", Status: StatusSuccess, APICallMade: true}}
+		}
+		return result, nil
+	})
+	handler := WithSLI(inner, tracker)
+
+	send := func(generation int64) {
+		evt := event.New()
+		evt.SetID("event")
+		evt.SetType("com.hyperfleet.cluster.updated")
+		evt.SetSource("test")
+		require.NoError(t, evt.SetData(event.ApplicationJSON, map[string]interface{}{
+			"id": "cluster-1", "kind": "Cluster", "generation": generation,
+		}))
+		_, err := handler(context.Background(), &evt)
+		require.NoError(t, err)
+	}
+
+	send(2)
+	send(2)
+	send(1)
+	reported = false
+	send(3)
+
+	summary := tracker.Summary()
+	assert.Equal(t, 4, summary.Total.Events)
+	assert.Equal(t, 3, summary.Total.Reported)
+	assert.InDelta(t, 0.75, summary.Total.SLI, 0.001)
+
+	byFreshness := map[string]sli.GroupSummary{}
+	for _, g := range summary.Groups {
+		assert.Equal(t, "com.hyperfleet.cluster.updated", g.EventType)
+		byFreshness[g.Freshness] = g
+	}
+	assert.Equal(t, 2, byFreshness[sli.FreshnessNew].Events)
+	assert.Equal(t, 1, byFreshness[sli.FreshnessNew].Reported, "unreported events count against the SLI")
+	assert.Equal(t, 1, byFreshness[sli.FreshnessResync].Events)
+	assert.Equal(t, 1, byFreshness[sli.FreshnessStale].Events)
+}
+
 // TestAlwaysAck_AlwaysReturnsNil verifies AlwaysAck always returns nil
 func TestAlwaysAck_AlwaysReturnsNil(t *testing.T) {
 	tests := []struct {
@@ -1668,7 +2256,7 @@ func TestPreconditionAPIFailure_ExecutionStatusRemainsFailed(t *testing.T) {
 					APICall: &configloader.APICall{
 						Method:  "GET",
 						URL:     "/clusters/{{ .clusterId }}",
-						Timeout: "2s",
+						Timeout: configloader.Duration(2 * time.Second),
 					},
 				},
 			},
@@ -1776,7 +2364,7 @@ func TestPreconditionCapture_NamedMapVariable(t *testing.T) {
 							APICall: &configloader.APICall{
 								Method:  "GET",
 								URL:     "/clusters/test",
-								Timeout: "2s",
+								Timeout: configloader.Duration(2 * time.Second),
 							},
 						},
 						Capture: []configloader.CaptureField{
@@ -1824,6 +2412,7 @@ func TestPreconditionCapture_FieldDefault(t *testing.T) {
 		wantValue    interface{}
 		capture      configloader.CaptureField
 		wantCaptured bool
+		wantWarning  bool
 	}{
 		{
 			name:         "field present - default not used",
@@ -1856,6 +2445,7 @@ func TestPreconditionCapture_FieldDefault(t *testing.T) {
 			},
 			wantValue:    nil,
 			wantCaptured: true,
+			wantWarning:  true,
 		},
 		{
 			name:         "bool default false when field absent",
@@ -1894,7 +2484,7 @@ func TestPreconditionCapture_FieldDefault(t *testing.T) {
 							APICall: &configloader.APICall{
 								Method:  "GET",
 								URL:     "/clusters/test",
-								Timeout: "2s",
+								Timeout: configloader.Duration(2 * time.Second),
 							},
 						},
 						Capture: []configloader.CaptureField{tt.capture},
@@ -1918,10 +2508,79 @@ func TestPreconditionCapture_FieldDefault(t *testing.T) {
 			captured := result.PreconditionResults[0].CapturedFields
 			assert.Equal(t, tt.wantValue, captured[tt.capture.Name],
 				"captured %s should be %v", tt.capture.Name, tt.wantValue)
+			if tt.wantWarning {
+				require.Len(t, result.Warnings, 1)
+				assert.Equal(t, PhasePreconditions, result.Warnings[0].Phase)
+				assert.Equal(t, "fetchCluster", result.Warnings[0].Step)
+				assert.Contains(t, result.Warnings[0].Message, tt.capture.Name)
+			} else {
+				assert.Empty(t, result.Warnings)
+			}
 		})
 	}
 }
 
+func TestPreconditionCapture_Transform(t *testing.T) {
+	mockClient := newMockAPIClient()
+	mockClient.GetResponse = &hyperfleetapi.Response{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Body:       []byte(`{"ready":" TRUE ","replicas":"3","phase":"Active","region":" us-east-1 ","flag":"maybe"}`),
+	}
+	capture := func(name, field, transform string) configloader.CaptureField {
+		return configloader.CaptureField{
+			Name:               name,
+			FieldExpressionDef: configloader.FieldExpressionDef{Field: field},
+			Transform:          transform,
+		}
+	}
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		Params:  []configloader.Parameter{{Name: "clusterId", Source: configloader.StringSource("event.id")}},
+		Preconditions: []configloader.Precondition{
+			{
+				ActionBase: configloader.ActionBase{
+					Name:    "fetchCluster",
+					APICall: &configloader.APICall{Method: "GET", URL: "/clusters/test"},
+				},
+				Capture: []configloader.CaptureField{
+					capture("isReady", "ready", configloader.CaptureTransformToBool),
+					capture("replicas", "replicas", configloader.CaptureTransformToInt),
+					capture("phase", "phase", configloader.CaptureTransformLower),
+					capture("region", "region", configloader.CaptureTransformTrim),
+					capture("label", "phase", `template:{{ .clusterId }}-{{ .value | lower }}`),
+					capture("flag", "flag", configloader.CaptureTransformToBool),
+				},
+				Expression: "isReady && replicas > 2",
+			},
+		},
+	}
+
+	exec, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(mockClient).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+
+	result := exec.Execute(context.Background(), map[string]interface{}{"id": "c1"})
+
+	require.Equal(t, StatusSuccess, result.Status, "errors=%v", result.Errors)
+	require.Len(t, result.PreconditionResults, 1)
+	assert.True(t, result.PreconditionResults[0].Matched)
+	assert.Equal(t, map[string]interface{}{
+		"isReady":  true,
+		"replicas": int64(3),
+		"phase":    "active",
+		"region":   "us-east-1",
+		"label":    "c1-active",
+		"flag":     nil,
+	}, result.PreconditionResults[0].CapturedFields)
+	require.Len(t, result.Warnings, 1, "a value the transform cannot convert should be a warning")
+	assert.Contains(t, result.Warnings[0].Message, "failed to transform capture 'flag'")
+}
+
 // helper functions for metrics assertions
 
 func findFamily(families []*dto.MetricFamily, name string) *dto.MetricFamily {
@@ -1958,7 +2617,7 @@ func new404PreconditionConfig() *configloader.Config {
 					APICall: &configloader.APICall{
 						Method:  "GET",
 						URL:     "/clusters/{{ .clusterId }}",
-						Timeout: "2s",
+						Timeout: configloader.Duration(2 * time.Second),
 					},
 				},
 			},
@@ -1977,7 +2636,7 @@ func new404PostActionConfig() *configloader.Config {
 						Method:  "PUT",
 						URL:     "/clusters/{{ .clusterId }}/statuses",
 						Body:    `{"status":"done"}`,
-						Timeout: "2s",
+						Timeout: configloader.Duration(2 * time.Second),
 					},
 				},
 			},
@@ -2033,7 +2692,7 @@ func TestPostAction404_GracefulHandling(t *testing.T) {
 		"404 in post-actions should result in success, not failure")
 
 	// No post-action errors should be recorded
-	assert.Nil(t, result.Errors[PhasePostActions],
+	assert.Nil(t, result.Errors.Phase(PhasePostActions),
 		"no post_actions error should be recorded for a 404")
 
 	// Verify adapter metadata is consistent
@@ -2100,11 +2759,11 @@ func TestPreconditionFail_PostAction404(t *testing.T) {
 	assert.True(t, result.ResourcesSkipped, "resources should be skipped")
 
 	// The precondition error should still be recorded
-	assert.NotNil(t, result.Errors[PhasePreconditions],
+	assert.NotNil(t, result.Errors.Phase(PhasePreconditions),
 		"precondition error should still be recorded")
 
 	// No post-action error recorded (404 is handled gracefully)
-	assert.Nil(t, result.Errors[PhasePostActions],
+	assert.Nil(t, result.Errors.Phase(PhasePostActions),
 		"post-action 404 should not add an error")
 }
 
@@ -2154,7 +2813,7 @@ func TestPostActionBrokenURL404_ReportsError(t *testing.T) {
 	assert.Equal(t, StatusFailed, result.Status,
 		"broken URL 404 in post-actions should mark execution as failed")
 
-	assert.NotNil(t, result.Errors[PhasePostActions],
+	assert.NotNil(t, result.Errors.Phase(PhasePostActions),
 		"post-action error should be recorded for a broken URL 404")
 }
 
@@ -2481,3 +3140,303 @@ func getCounterValue(t *testing.T, families []*dto.MetricFamily, metricName, lab
 	}
 	return 0
 }
+
+// reportingTestClient wraps MockClient to panic on or hang in precondition GETs and to
+// record whether the post-action PUT was issued with a live context.
+type reportingTestClient struct {
+	*hyperfleetapi.MockClient
+	putCtxErr  error
+	hangOnGet  bool
+	panicOnGet bool
+	putCalled  bool
+}
+
+func (c *reportingTestClient) Get(
+	ctx context.Context, url string, opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	if c.panicOnGet {
+		panic("boom")
+	}
+	if c.hangOnGet {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return c.MockClient.Get(ctx, url, opts...)
+}
+
+func (c *reportingTestClient) Put(
+	ctx context.Context, url string, body []byte, opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	c.putCalled = true
+	c.putCtxErr = ctx.Err()
+	return c.MockClient.Put(ctx, url, body, opts...)
+}
+
+// TestPostActions_RunAfterContextCanceled verifies that status reporting still runs
+// with a usable context when the event context was canceled during processing.
+func TestPostActions_RunAfterContextCanceled(t *testing.T) {
+	client := &reportingTestClient{MockClient: newMockAPIClient()}
+	client.GetResponse = &hyperfleetapi.Response{
+		StatusCode: 200,
+		Body:       []byte(`{"id":"cluster-456","status":{"conditions":[{"type":"Reconciled","status":"False"}]}}`),
+	}
+
+	exec, err := NewBuilder().
+		WithConfig(new404PostActionConfig()).
+		WithAPIClient(client).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(logger.WithEventID(context.Background(), "test-canceled"))
+	cancel()
+	result := exec.Execute(ctx, map[string]interface{}{"id": "cluster-456"})
+
+	require.Len(t, result.PostActionResults, 1)
+	assert.True(t, client.putCalled, "post-action should run after cancellation")
+	assert.NoError(t, client.putCtxErr, "post-action should receive a live context")
+}
+
+// TestExecute_ExecutionTimeout verifies that execution_timeout cancels a hung call, fails
+// the execution with reason Timeout and still reports status.
+func TestExecute_ExecutionTimeout(t *testing.T) {
+	client := &reportingTestClient{MockClient: newMockAPIClient(), hangOnGet: true}
+	config := new404PostActionConfig()
+	config.ExecutionTimeout = configloader.Duration(50 * time.Millisecond)
+
+	exec, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(client).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+
+	result := exec.Execute(context.Background(), map[string]interface{}{"id": "cluster-456"})
+
+	assert.Equal(t, StatusFailed, result.Status)
+	require.Error(t, result.Errors.Phase(PhasePreconditions))
+	assert.ErrorContains(t, result.Errors.Phase(PhasePreconditions), "execution timeout exceeded after 50ms")
+	assert.ErrorIs(t, result.Errors.Phase(PhasePreconditions), context.DeadlineExceeded)
+	assert.Equal(t, ExecutionTimeoutReason, result.ExecutionContext.Adapter.ErrorReason)
+	assert.True(t, client.putCalled, "post-action should run after the timeout")
+	assert.NoError(t, client.putCtxErr, "post-action should receive a live context")
+
+	// A canceled event context is not an execution timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result = exec.Execute(ctx, map[string]interface{}{"id": "cluster-456"})
+	assert.Equal(t, "PreconditionFailed", result.ExecutionContext.Adapter.ErrorReason)
+}
+
+// TestPostActions_RunAfterPhasePanic verifies that a panic in the preconditions phase
+// is recorded as a failure and post-actions still report status.
+func TestPostActions_RunAfterPhasePanic(t *testing.T) {
+	client := &reportingTestClient{MockClient: newMockAPIClient(), panicOnGet: true}
+
+	exec, err := NewBuilder().
+		WithConfig(new404PostActionConfig()).
+		WithAPIClient(client).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+
+	result := exec.Execute(context.Background(), map[string]interface{}{"id": "cluster-456"})
+
+	assert.Equal(t, StatusFailed, result.Status)
+	require.Error(t, result.Errors.Phase(PhasePreconditions))
+	assert.Contains(t, result.Errors.Phase(PhasePreconditions).Error(), "panic: boom")
+	assert.True(t, client.putCalled, "post-action should run after a phase panic")
+}
+
+// TestExecutionContext_ConcurrentAccess exercises the synchronized accessors from
+// many goroutines; run with -race to detect unsynchronized access.
+func TestExecutionContext_ConcurrentAccess(t *testing.T) {
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{"id": "c1"}, &configloader.Config{})
+
+	const workers = 16
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("step%d", i)
+			execCtx.SetParam(name, i)
+			execCtx.SetResource(name, &unstructured.Unstructured{Object: map[string]interface{}{"kind": "ConfigMap"}})
+			execCtx.SetResourceIfAbsent("shared", &unstructured.Unstructured{})
+			execCtx.AddCELEvaluation(PhaseResources, name, "true", true)
+			execCtx.RecordResourceError(name, "failed")
+			execCtx.MarkResourcesSkipped(name, false)
+			_ = execCtx.ParamsSnapshot()
+			_ = execCtx.GetCELVariables()
+			_ = execCtx.GetEvaluationsByPhase(PhaseResources)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, execCtx.Params, workers)
+	assert.Len(t, execCtx.Resources, workers+1)
+	assert.Len(t, execCtx.Evaluations, workers)
+	assert.Len(t, execCtx.Adapter.ResourceErrors, workers)
+	assert.NotNil(t, execCtx.Adapter.ExecutionError)
+	assert.True(t, execCtx.Adapter.ResourcesSkipped)
+}
+
+func TestExecutionContext_SetResourceIfAbsent(t *testing.T) {
+	execCtx := NewExecutionContext(context.Background(), nil, &configloader.Config{})
+
+	assert.True(t, execCtx.SetResourceIfAbsent("ns", "first"))
+	assert.False(t, execCtx.SetResourceIfAbsent("ns", "second"))
+
+	got, ok := execCtx.GetResource("ns")
+	assert.True(t, ok)
+	assert.Equal(t, "first", got)
+}
+
+func TestParseEventData_CloudEvent(t *testing.T) {
+	newEvent := func(t *testing.T, payload string) event.Event {
+		t.Helper()
+		evt := event.New()
+		evt.SetID("evt-1")
+		evt.SetType("com.redhat.hyperfleet.cluster.reconcile")
+		evt.SetSource("test")
+		require.NoError(t, json.Unmarshal([]byte(payload), &evt))
+		return evt
+	}
+
+	t.Run("data_base64", func(t *testing.T) {
+		evt := newEvent(t, `{"specversion":"1.0","id":"evt-1","type":"t","source":"s",`+
+			`"datacontenttype":"application/json","data_base64":"eyJpZCI6ImNsdXN0ZXItMSIsImtpbmQiOiJDbHVzdGVyIn0="}`)
+		eventData, rawData, err := ParseEventData(&evt)
+		require.NoError(t, err)
+		assert.Equal(t, "cluster-1", eventData.ID)
+		assert.Equal(t, "Cluster", rawData["kind"])
+	})
+
+	t.Run("base64 string in JSON data", func(t *testing.T) {
+		evt := newEvent(t, `{"specversion":"1.0","id":"evt-1","type":"t","source":"s",`+
+			`"datacontenttype":"application/cloudevents+json","data":"eyJpZCI6ImNsdXN0ZXItMSJ9"}`)
+		eventData, _, err := ParseEventData(evt)
+		require.NoError(t, err)
+		assert.Equal(t, "cluster-1", eventData.ID)
+	})
+
+	t.Run("text is passed through", func(t *testing.T) {
+		evt := newEvent(t, `{"specversion":"1.0","id":"evt-1","type":"t","source":"s",`+
+			`"datacontenttype":"text/plain; charset=utf-8","data":"reconcile all"}`)
+		eventData, rawData, err := ParseEventData(&evt)
+		require.NoError(t, err)
+		assert.Empty(t, eventData.ID)
+		assert.Equal(t, map[string]interface{}{EventRawKey: "reconcile all"}, rawData)
+	})
+
+	for name, payload := range map[string]string{
+		"unsupported type": `{"specversion":"1.0","id":"evt-1","type":"t","source":"s",` +
+			`"datacontenttype":"application/xml","data":"<cluster/>"}`,
+		"invalid JSON": `{"specversion":"1.0","id":"evt-1","type":"t","source":"s",` +
+			`"datacontenttype":"application/json","data":[1,2]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			evt := newEvent(t, payload)
+			_, _, err := ParseEventData(&evt)
+			var invalidErr *InvalidCloudEventError
+			require.ErrorAs(t, err, &invalidErr)
+			assert.Equal(t, evt.DataContentType(), invalidErr.ContentType)
+			assert.Contains(t, err.Error(), evt.DataContentType())
+		})
+	}
+}
+
+func TestWithParsedEvent(t *testing.T) {
+	newEvent := func(id string) *event.Event {
+		evt := event.New()
+		evt.SetID(id)
+		evt.SetType("com.redhat.hyperfleet.cluster.reconcile")
+		evt.SetSource("test")
+		require.NoError(t, evt.SetData(event.ApplicationJSON, map[string]interface{}{"id": "cluster-" + id}))
+		return &evt
+	}
+	evt := newEvent("1")
+
+	ctx, parsed := withParsedEvent(context.Background(), evt)
+	require.NoError(t, parsed.err)
+	assert.Equal(t, "cluster-1", parsed.data.ID)
+
+	reusedCtx, reused := withParsedEvent(ctx, evt)
+	assert.Same(t, parsed, reused, "the parse held by the context is reused")
+	assert.Equal(t, ctx, reusedCtx)
+
+	_, other := withParsedEvent(ctx, newEvent("2"))
+	assert.NotSame(t, parsed, other, "another event is parsed again")
+	assert.Equal(t, "cluster-2", other.data.ID)
+}
+
+func TestExecute_TextEventData(t *testing.T) {
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		Params: []configloader.Parameter{
+			{Name: "payload", Source: configloader.StringSource("event.eventRaw")},
+		},
+	}
+	exec, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(newMockAPIClient()).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+
+	evt := event.New()
+	evt.SetID("evt-1")
+	evt.SetType("com.redhat.hyperfleet.cluster.reconcile")
+	evt.SetSource("test")
+	require.NoError(t, evt.SetData("text/plain", []byte("reconcile all")))
+
+	result := exec.Execute(context.Background(), &evt)
+	require.Equal(t, StatusSuccess, result.Status, result.Errors.String())
+	assert.Equal(t, "reconcile all", result.Params["payload"])
+}
+
+func TestExecute_StrictJSONNumbers(t *testing.T) {
+	for _, mode := range []string{"", configloader.JSONNumbersStrict} {
+		t.Run("json_numbers="+mode, func(t *testing.T) {
+			config := &configloader.Config{
+				Adapter:     configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+				JSONNumbers: mode,
+				Params: []configloader.Parameter{
+					{Name: "nodeCount", Source: configloader.StringSource("event.spec.nodeCount")},
+				},
+				Preconditions: []configloader.Precondition{
+					{ActionBase: configloader.ActionBase{Name: "scaled"}, Expression: "nodeCount * 2 == 6"},
+				},
+			}
+			exec, err := NewBuilder().
+				WithConfig(config).
+				WithAPIClient(newMockAPIClient()).
+				WithTransportClient(k8sclient.NewMockK8sClient()).
+				WithLogger(logger.NewTestLogger()).
+				Build()
+			require.NoError(t, err)
+
+			evt := event.New()
+			evt.SetID("evt-1")
+			evt.SetType("com.redhat.hyperfleet.cluster.reconcile")
+			evt.SetSource("test")
+			require.NoError(t, evt.SetData(event.ApplicationJSON, []byte(`{"id":"c1","spec":{"nodeCount":3}}`)))
+
+			// The handler middlewares have already parsed the event with float64 numbers
+			ctx, _ := withParsedEvent(context.Background(), &evt)
+			result := exec.Execute(ctx, &evt)
+			if mode == "" {
+				assert.Equal(t, float64(3), result.Params["nodeCount"])
+				assert.True(t, result.ResourcesSkipped, "int arithmetic on a float64 should not match")
+				return
+			}
+			require.Equal(t, StatusSuccess, result.Status, result.Errors.String())
+			assert.Equal(t, int64(3), result.Params["nodeCount"])
+			assert.False(t, result.ResourcesSkipped)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// continueOnError reports whether the execution goes on past step, which failed with err,
+// because its on_error, or the task's failure_policy, is continue. The failure is then
+// recorded as a warning instead of an execution error.
+func continueOnError(
+	ctx context.Context,
+	log logger.Logger,
+	execCtx *ExecutionContext,
+	phase ExecutionPhase,
+	step, onError string,
+	err error,
+) bool {
+	if execCtx.Config.OnErrorPolicy(onError) != configloader.OnErrorContinue {
+		return false
+	}
+	execCtx.RecordContinuedFailure(phase, step, err)
+	errCtx := logger.WithErrorField(ctx, err)
+	log.Warnf(errCtx, "%s[%s] failed, continuing: on_error is continue", phase, step)
+	return true
+}
+
+// abortsOnError reports whether a failure of a step with the given on_error stops its
+// phase because on_error, or the task's failure_policy, is abort
+func abortsOnError(execCtx *ExecutionContext, onError string) bool {
+	return execCtx.Config.OnErrorPolicy(onError) == configloader.OnErrorAbort
+}
@@ -0,0 +1,181 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func TestResourceExecutor_OnError(t *testing.T) {
+	newResources := func(onError string, parallel bool) []configloader.Resource {
+		first := newResourceWithLifecycle("", "")
+		first.Name, first.OnError, first.Parallel = "first", onError, parallel
+		second := newResourceWithLifecycle("", "")
+		second.Name, second.Parallel = "second", parallel
+		if parallel {
+			second.DependsOn = []string{"first"}
+		}
+		return []configloader.Resource{first, second}
+	}
+
+	for name, tt := range map[string]struct {
+		onError       string
+		failurePolicy string
+		parallel      bool
+		continued     bool
+	}{
+		"default aborts":           {},
+		"continue":                 {onError: configloader.OnErrorContinue, continued: true},
+		"continue with dependents": {onError: configloader.OnErrorContinue, parallel: true, continued: true},
+		"failure_policy continue":  {failurePolicy: configloader.OnErrorContinue, continued: true},
+		"on_error overrides failure_policy": {
+			onError: configloader.OnErrorAbort, failurePolicy: configloader.OnErrorContinue,
+		},
+		"default aborts with dependents":      {parallel: true},
+		"failure_policy continue, dependents": {failurePolicy: configloader.OnErrorContinue, parallel: true, continued: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mock := k8sclient.NewMockK8sClient()
+			mock.ApplyResourceError = errors.New("admission webhook denied the request")
+			re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+			execCtx := NewExecutionContext(context.Background(), nil,
+				&configloader.Config{FailurePolicy: tt.failurePolicy})
+
+			results, err := re.ExecuteAll(context.Background(), newResources(tt.onError, tt.parallel), execCtx)
+
+			if !tt.continued {
+				require.Error(t, err)
+				require.Len(t, results, 1, "the resources after the failed one should not run")
+				assert.NotNil(t, execCtx.Adapter.ExecutionError)
+				return
+			}
+			if tt.failurePolicy == configloader.OnErrorContinue {
+				// Both resources fail and continue
+				require.NoError(t, err)
+				require.Len(t, results, 2)
+				assert.Len(t, execCtx.WarningsSnapshot(), 2)
+				assert.Nil(t, execCtx.Adapter.ExecutionError)
+				return
+			}
+			// The second resource still runs, and its failure aborts
+			require.Error(t, err)
+			assert.NotContains(t, err.Error(), "first")
+			require.Len(t, results, 2)
+			assert.Equal(t, StatusFailed, results[0].Status)
+			warnings := execCtx.WarningsSnapshot()
+			require.Len(t, warnings, 1)
+			assert.Equal(t, "first", warnings[0].Step)
+			assert.Contains(t, warnings[0].Message, "admission webhook denied the request")
+			assert.Equal(t, "second", execCtx.Adapter.ExecutionError.Step)
+		})
+	}
+}
+
+func TestResourceExecutor_OnErrorAbortStopsDeletes(t *testing.T) {
+	newObj := func(name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		}}
+	}
+	mock := &multiDeleteMock{
+		MockK8sClient: k8sclient.NewMockK8sClient(),
+		objects: map[string]*unstructured.Unstructured{
+			"resource-a": newObj("resource-a"),
+			"resource-b": newObj("resource-b"),
+		},
+		deleteErr: errors.New("RBAC denied"),
+	}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resources := make([]configloader.Resource, 0, 2)
+	for _, name := range []string{"resource-a", "resource-b"} {
+		resource := newResourceWithLifecycle("deleted_time != null", "Background")
+		resource.Name = name
+		resource.Discovery = &configloader.DiscoveryConfig{ByName: name, Namespace: "default"}
+		resources = append(resources, resource)
+	}
+	resources[0].OnError = configloader.OnErrorAbort
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["deleted_time"] = testDeletedTime
+
+	results, err := re.ExecuteAll(context.Background(), resources, execCtx)
+
+	require.Error(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, []string{"resource-a"}, mock.deleteCalls, "a failed delete with on_error abort should stop the phase")
+}
+
+func TestPreconditionExecutor_OnErrorContinue(t *testing.T) {
+	apiClient := hyperfleetapi.NewMockClient()
+	apiClient.GetError = errors.New("connection refused")
+	apiClient.DoError = apiClient.GetError
+	pe := newPreconditionExecutor(&ExecutorConfig{APIClient: apiClient, Logger: logger.NewTestLogger()})
+
+	preconditions := []configloader.Precondition{
+		{
+			ActionBase: configloader.ActionBase{
+				Name:    "fetchQuota",
+				APICall: &configloader.APICall{Method: "GET", URL: "http://api.example.com/quota"},
+			},
+			OnError: configloader.OnErrorContinue,
+		},
+		{ActionBase: configloader.ActionBase{Name: "always"}, Expression: "true"},
+	}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+
+	outcome := pe.ExecuteAll(context.Background(), preconditions, execCtx)
+
+	require.NoError(t, outcome.Error)
+	assert.True(t, outcome.AllMatched)
+	require.Len(t, outcome.Results, 2)
+	assert.Equal(t, StatusFailed, outcome.Results[0].Status)
+	require.Len(t, execCtx.WarningsSnapshot(), 1)
+
+	preconditions[0].OnError = ""
+	outcome = pe.ExecuteAll(context.Background(), preconditions, NewExecutionContext(context.Background(), nil, nil))
+	require.Error(t, outcome.Error)
+	assert.Len(t, outcome.Results, 1)
+}
+
+func TestPostActionExecutor_OnErrorContinue(t *testing.T) {
+	apiClient := hyperfleetapi.NewMockClient()
+	apiClient.DoError = errors.New("connection refused")
+	apiClient.PostError = apiClient.DoError
+	pae := newPostActionExecutor(&ExecutorConfig{APIClient: apiClient, Logger: logger.NewTestLogger()})
+
+	postConfig := &configloader.PostConfig{PostActions: []configloader.PostAction{
+		{
+			ActionBase: configloader.ActionBase{
+				Name:    "notifyAudit",
+				APICall: &configloader.APICall{Method: "POST", URL: "http://audit.example.com/events"},
+			},
+			OnError: configloader.OnErrorContinue,
+		},
+		{ActionBase: configloader.ActionBase{
+			Name: "logDone",
+			Log:  &configloader.LogAction{Message: "Done", Level: "info"},
+		}},
+	}}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+
+	results, err := pae.ExecuteAll(context.Background(), postConfig, execCtx)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2, "the post-action after the failed one should run")
+	assert.Nil(t, execCtx.Adapter.ExecutionError)
+	warnings := execCtx.WarningsSnapshot()
+	require.Len(t, warnings, 1)
+	assert.Equal(t, PhasePostActions, warnings[0].Phase)
+	assert.Equal(t, "notifyAudit", warnings[0].Step)
+}
@@ -0,0 +1,153 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// applyFeedbackRules sets spec.manifestConfigs of the rendered ManifestWork from the feedback
+// rules, with one entry per workload manifest a rule selects. Entries replace the manifest
+// configs the manifest defines for the same resource. A rule that selects no manifest is an
+// error, since no status would ever come back for it.
+func applyFeedbackRules(
+	rendered []byte,
+	rules []configloader.ManifestFeedbackRule,
+	params map[string]interface{},
+) ([]byte, error) {
+	var work map[string]interface{}
+	if err := json.Unmarshal(rendered, &work); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered ManifestWork: %w", err)
+	}
+	manifests, _, sliceErr := unstructured.NestedSlice(work, "spec", "workload", "manifests")
+	if sliceErr != nil {
+		return nil, fmt.Errorf("invalid spec.workload.manifests in rendered ManifestWork: %w", sliceErr)
+	}
+
+	var options []workv1.ManifestConfigOption
+	for i := range rules {
+		rule := &rules[i]
+		name, err := utils.RenderTemplate(rule.Name, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render feedback rule %d name: %w", i, err)
+		}
+		namespace, err := utils.RenderTemplate(rule.Namespace, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render feedback rule %d namespace: %w", i, err)
+		}
+
+		matched := false
+		for _, m := range manifests {
+			obj, ok := m.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			u := &unstructured.Unstructured{Object: obj}
+			if u.GetKind() != rule.Kind || (name != "" && u.GetName() != name) ||
+				(namespace != "" && u.GetNamespace() != namespace) {
+				continue
+			}
+			matched = true
+			options = append(options, workv1.ManifestConfigOption{
+				ResourceIdentifier: feedbackResourceIdentifier(u, rule.Resource),
+				FeedbackRules:      feedbackRules(rule),
+			})
+		}
+		if !matched {
+			return nil, fmt.Errorf("feedback rule %d (kind %s, name %q, namespace %q) selects no workload manifest",
+				i, rule.Kind, name, namespace)
+		}
+	}
+
+	configs, _, err := unstructured.NestedSlice(work, "spec", "manifestConfigs")
+	if err != nil {
+		return nil, fmt.Errorf("invalid spec.manifestConfigs in rendered ManifestWork: %w", err)
+	}
+	for _, option := range options {
+		entry, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&option)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert manifest config: %w", err)
+		}
+		configs = replaceManifestConfig(configs, entry)
+	}
+	if err := unstructured.SetNestedSlice(work, configs, "spec", "manifestConfigs"); err != nil {
+		return nil, fmt.Errorf("failed to set spec.manifestConfigs: %w", err)
+	}
+	return json.Marshal(work)
+}
+
+// feedbackResourceIdentifier returns the identifier the work agent matches a manifest config
+// against. resource overrides the plural resource name guessed from the kind.
+func feedbackResourceIdentifier(obj *unstructured.Unstructured, resource string) workv1.ResourceIdentifier {
+	gvk := obj.GroupVersionKind()
+	if resource == "" {
+		plural, _ := meta.UnsafeGuessKindToResource(gvk)
+		resource = plural.Resource
+	}
+	return workv1.ResourceIdentifier{
+		Group:     gvk.Group,
+		Resource:  resource,
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+	}
+}
+
+// feedbackRules converts a configured rule to the feedback rules of a manifest config
+func feedbackRules(rule *configloader.ManifestFeedbackRule) []workv1.FeedbackRule {
+	var rules []workv1.FeedbackRule
+	if rule.WellKnownStatus {
+		rules = append(rules, workv1.FeedbackRule{Type: workv1.WellKnownStatusType})
+	}
+	if len(rule.JSONPaths) > 0 {
+		paths := make([]workv1.JsonPath, len(rule.JSONPaths))
+		for i, jp := range rule.JSONPaths {
+			paths[i] = workv1.JsonPath{Name: jp.Name, Path: jp.Path, Version: jp.Version}
+		}
+		rules = append(rules, workv1.FeedbackRule{Type: workv1.JSONPathsType, JsonPaths: paths})
+	}
+	return rules
+}
+
+// replaceManifestConfig returns configs with entry in place of the config for the same
+// resource, or appended when there is none
+func replaceManifestConfig(configs []interface{}, entry map[string]interface{}) []interface{} {
+	for i, c := range configs {
+		existing, ok := c.(map[string]interface{})
+		if ok && equalResourceIdentifiers(existing["resourceIdentifier"], entry["resourceIdentifier"]) {
+			configs[i] = entry
+			return configs
+		}
+	}
+	return append(configs, entry)
+}
+
+// equalResourceIdentifiers reports whether two unstructured resource identifiers select the
+// same resource, treating absent fields as empty
+func equalResourceIdentifiers(a, b interface{}) bool {
+	for _, key := range []string{"group", "resource", "name", "namespace"} {
+		if identifierField(a, key) != identifierField(b, key) {
+			return false
+		}
+	}
+	return true
+}
+
+// identifierField returns the string field key of an unstructured resource identifier,
+// empty when id is not a map or the field is not a string
+func identifierField(id interface{}, key string) string {
+	m, ok := id.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	value, isString := m[key].(string)
+	if !isString {
+		return ""
+	}
+	return value
+}
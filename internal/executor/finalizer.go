@@ -0,0 +1,116 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxFinalizerUpdateAttempts bounds the update retries on conflicting writes
+const maxFinalizerUpdateAttempts = 3
+
+// resourceUpdater is implemented by transport clients that can update an object in
+// place, which removing a finalizer requires (the kubernetes client)
+type resourceUpdater interface {
+	UpdateResource(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+}
+
+// managedFinalizer returns the finalizer the adapter manages for resource, if any
+func managedFinalizer(resource configloader.Resource) string {
+	if resource.Lifecycle == nil || resource.Lifecycle.Delete == nil {
+		return ""
+	}
+	return resource.Lifecycle.Delete.Finalizer
+}
+
+// addFinalizer adds finalizer to the rendered manifest unless it is already listed
+func addFinalizer(rendered []byte, finalizer string) ([]byte, error) {
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal(rendered, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered manifest: %w", err)
+	}
+	if hasFinalizer(obj, finalizer) {
+		return rendered, nil
+	}
+	obj.SetFinalizers(append(obj.GetFinalizers(), finalizer))
+	return json.Marshal(obj.Object)
+}
+
+func hasFinalizer(obj *unstructured.Unstructured, finalizer string) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// runTeardown runs the lifecycle.delete.teardown steps of resource in order and stops
+// at the first failure
+func (re *ResourceExecutor) runTeardown(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+) error {
+	for _, step := range resource.Lifecycle.Delete.Teardown {
+		if step.Log != nil {
+			ExecuteLogAction(ctx, step.Log, execCtx, re.log)
+		}
+		if step.APICall == nil {
+			continue
+		}
+		if re.apiClient == nil {
+			return fmt.Errorf("teardown step %q: API client not configured", step.Name)
+		}
+		resp, url, err := ExecuteAPICall(ctx, step.APICall, execCtx, re.apiClient, re.log)
+		if validationErr := ValidateAPIResponse(resp, err, step.APICall.Method, url); validationErr != nil {
+			return fmt.Errorf("teardown step %q: %w", step.Name, validationErr)
+		}
+		re.log.Debugf(ctx, "Resource[%s] teardown step %q done", resource.Name, step.Name)
+	}
+	return nil
+}
+
+// removeFinalizer removes finalizer from the object, re-reading it when the update
+// conflicts with another writer. An object that is already gone needs no removal.
+func (re *ResourceExecutor) removeFinalizer(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name, finalizer string,
+) error {
+	for attempt := 1; ; attempt++ {
+		obj, err := re.client.GetResource(ctx, gvk, namespace, name, nil)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !hasFinalizer(obj, finalizer) {
+			return nil
+		}
+		updater, ok := re.client.(resourceUpdater)
+		if !ok {
+			return fmt.Errorf("transport client cannot remove finalizer %q", finalizer)
+		}
+		kept := make([]string, 0, len(obj.GetFinalizers()))
+		for _, f := range obj.GetFinalizers() {
+			if f != finalizer {
+				kept = append(kept, f)
+			}
+		}
+		obj.SetFinalizers(kept)
+		_, err = updater.UpdateResource(ctx, obj)
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		if err == nil || !apierrors.IsConflict(err) || attempt == maxFinalizerUpdateAttempts {
+			return err
+		}
+	}
+}
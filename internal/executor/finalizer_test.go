@@ -0,0 +1,151 @@
+package executor
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const testFinalizer = "hyperfleet.io/teardown"
+
+// finalizingMockClient deletes objects like Kubernetes does: an object with finalizers is
+// only marked for deletion, and disappears once an update clears its finalizers
+type finalizingMockClient struct {
+	*k8sclient.MockK8sClient
+}
+
+func (m *finalizingMockClient) DeleteResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	opts *transportclient.DeleteOptions,
+	target transportclient.TransportContext,
+) error {
+	obj, ok := m.Resources[namespace+"/"+name]
+	if ok && len(obj.GetFinalizers()) > 0 {
+		now := metav1.NewTime(time.Now())
+		obj.SetDeletionTimestamp(&now)
+		return nil
+	}
+	return m.MockK8sClient.DeleteResource(ctx, gvk, namespace, name, opts, target)
+}
+
+func (m *finalizingMockClient) UpdateResource(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	if obj.GetDeletionTimestamp() != nil && len(obj.GetFinalizers()) == 0 {
+		delete(m.Resources, obj.GetNamespace()+"/"+obj.GetName())
+		return obj, nil
+	}
+	return m.MockK8sClient.UpdateResource(ctx, obj)
+}
+
+func newResourceWithTeardown() configloader.Resource {
+	resource := newResourceWithLifecycle("deleted_time != null", "")
+	resource.Lifecycle.Delete.Finalizer = testFinalizer
+	resource.Lifecycle.Delete.Teardown = []configloader.ActionBase{{
+		Name:    "deregister",
+		APICall: &configloader.APICall{Method: "DELETE", URL: "http://api.example.com/dns/{{ .clusterId }}"},
+	}}
+	return resource
+}
+
+func TestResourceExecutor_Finalizer_AddedOnApply(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+	resource := newResourceWithTeardown()
+	resource.Lifecycle.Delete.When.Expression = "false"
+
+	_, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource},
+		NewExecutionContext(context.Background(), nil, nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{testFinalizer}, mock.Resources["default/test-cm"].GetFinalizers())
+}
+
+func TestResourceExecutor_Finalizer_TeardownBeforeRemoval(t *testing.T) {
+	newExecCtx := func() *ExecutionContext {
+		execCtx := NewExecutionContext(context.Background(), nil, nil)
+		execCtx.Params["deleted_time"] = testDeletedTime
+		execCtx.Params["clusterId"] = "c1"
+		return execCtx
+	}
+	newObject := func() *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "test-cm", "namespace": "default"},
+		}}
+		obj.SetFinalizers([]string{"other.io/keep", testFinalizer})
+		return obj
+	}
+
+	t.Run("teardown runs and the finalizer is removed", func(t *testing.T) {
+		mock := &finalizingMockClient{MockK8sClient: k8sclient.NewMockK8sClient()}
+		mock.Resources["default/test-cm"] = newObject()
+		apiClient := hyperfleetapi.NewMockClient()
+		re := newResourceExecutor(&ExecutorConfig{
+			TransportClient: mock, APIClient: apiClient, Logger: logger.NewTestLogger(),
+		})
+
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{newResourceWithTeardown()},
+			newExecCtx())
+		require.NoError(t, err)
+		assert.Equal(t, manifest.OperationDelete, results[0].Operation)
+
+		req := apiClient.GetLastRequest()
+		require.NotNil(t, req)
+		assert.Equal(t, http.MethodDelete, req.Method)
+		assert.Equal(t, "http://api.example.com/dns/c1", req.URL)
+
+		obj := mock.Resources["default/test-cm"]
+		require.NotNil(t, obj, "the other finalizer still holds the object")
+		assert.Equal(t, []string{"other.io/keep"}, obj.GetFinalizers())
+	})
+
+	t.Run("failed teardown keeps the finalizer", func(t *testing.T) {
+		mock := &finalizingMockClient{MockK8sClient: k8sclient.NewMockK8sClient()}
+		mock.Resources["default/test-cm"] = newObject()
+		apiClient := hyperfleetapi.NewMockClient()
+		apiClient.DeleteResponse = &hyperfleetapi.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+		re := newResourceExecutor(&ExecutorConfig{
+			TransportClient: mock, APIClient: apiClient, Logger: logger.NewTestLogger(),
+		})
+
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{newResourceWithTeardown()},
+			newExecCtx())
+		require.Error(t, err)
+		assert.Equal(t, StatusFailed, results[0].Status)
+		assert.Contains(t, err.Error(), `teardown step "deregister"`)
+		assert.Equal(t, []string{"other.io/keep", testFinalizer}, mock.Resources["default/test-cm"].GetFinalizers())
+	})
+
+	t.Run("no teardown once the finalizer is gone", func(t *testing.T) {
+		mock := &finalizingMockClient{MockK8sClient: k8sclient.NewMockK8sClient()}
+		obj := newObject()
+		obj.SetFinalizers([]string{"other.io/keep"})
+		mock.Resources["default/test-cm"] = obj
+		apiClient := hyperfleetapi.NewMockClient()
+		re := newResourceExecutor(&ExecutorConfig{
+			TransportClient: mock, APIClient: apiClient, Logger: logger.NewTestLogger(),
+		})
+
+		_, err := re.ExecuteAll(context.Background(), []configloader.Resource{newResourceWithTeardown()},
+			newExecCtx())
+		require.NoError(t, err)
+		assert.Empty(t, apiClient.Requests)
+	})
+}
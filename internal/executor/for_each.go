@@ -0,0 +1,160 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// ForEachItem is the list item a for_each step runs for
+type ForEachItem struct {
+	Value interface{}
+	Index int
+}
+
+type forEachItemKey struct{}
+
+// withForEachItem returns a context carrying the item of the current for_each iteration.
+// The item travels with the context rather than the params, so that items of the same
+// step can run concurrently.
+func withForEachItem(ctx context.Context, item ForEachItem) context.Context {
+	return context.WithValue(ctx, forEachItemKey{}, item)
+}
+
+// forEachItemFrom returns the for_each item carried by ctx, if any
+func forEachItemFrom(ctx context.Context) (ForEachItem, bool) {
+	item, ok := ctx.Value(forEachItemKey{}).(ForEachItem)
+	return item, ok
+}
+
+// addForEachVariables adds item and index to vars when ctx carries a for_each item
+func addForEachVariables(ctx context.Context, vars map[string]interface{}) map[string]interface{} {
+	if item, ok := forEachItemFrom(ctx); ok {
+		vars[configloader.ForEachItemVariable] = item.Value
+		vars[configloader.ForEachIndexVariable] = item.Index
+	}
+	return vars
+}
+
+// stepParams returns the params for the templates of a step, with item and index
+// during a for_each iteration
+func stepParams(ctx context.Context, execCtx *ExecutionContext) map[string]interface{} {
+	return addForEachVariables(ctx, execCtx.ParamsSnapshot())
+}
+
+// stepCELVariables returns the CEL variables of a step, with item and index during a
+// for_each iteration
+func stepCELVariables(ctx context.Context, execCtx *ExecutionContext) map[string]interface{} {
+	return addForEachVariables(ctx, execCtx.GetCELVariables())
+}
+
+// evaluateForEach evaluates the for_each expression of a step into its list of items
+func evaluateForEach(
+	ctx context.Context,
+	log logger.Logger,
+	execCtx *ExecutionContext,
+	expression string,
+) ([]interface{}, error) {
+	evalCtx := criteria.NewEvaluationContext()
+	evalCtx.SetVariablesFromMap(execCtx.GetCELVariables())
+	evaluator, err := criteria.NewEvaluator(ctx, evalCtx, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL evaluator: %w", err)
+	}
+	result, err := evaluator.EvaluateCEL(strings.TrimSpace(expression))
+	if err != nil {
+		return nil, err
+	}
+	if result.HasError() {
+		return nil, result.Error
+	}
+	value := criteria.NativeValue(result.Value)
+	if items, ok := value.([]interface{}); ok {
+		return items, nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice {
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = criteria.NativeValue(v.Index(i).Interface())
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("for_each must evaluate to a list, got %T", value)
+}
+
+// addForEachItems checks the items of a for_each step against limits.max_for_each_items
+// and counts the items beyond the first against limits.max_steps
+func addForEachItems(execCtx *ExecutionContext, step string, items int) error {
+	if limit := execCtx.limits().MaxForEachItems; configloader.ExceedsLimit(items, limit) {
+		return fmt.Errorf("for_each of step %q has %d items, limit (limits.max_for_each_items) is %d",
+			step, items, limit)
+	}
+	if items > 1 {
+		return execCtx.AddExpandedSteps(step, items-1)
+	}
+	return nil
+}
+
+// expandForEach replaces each for_each resource with one resource per item, named
+// configloader.ForEachItemName(name, index), and records the items in execCtx.
+// A list that evaluates to no items leaves no resource.
+func (re *ResourceExecutor) expandForEach(
+	ctx context.Context,
+	resources []configloader.Resource,
+	execCtx *ExecutionContext,
+) ([]configloader.Resource, error) {
+	if !hasForEachResources(resources) {
+		return resources, nil
+	}
+	expanded := make([]configloader.Resource, 0, len(resources))
+	for _, resource := range resources {
+		if resource.ForEach == "" {
+			expanded = append(expanded, resource)
+			continue
+		}
+		items, err := evaluateForEach(ctx, re.log, execCtx, resource.ForEach)
+		if err != nil {
+			execCtx.RecordResourceError(resource.Name, err.Error())
+			return nil, NewExecutorError(PhaseResources, resource.Name, "failed to evaluate for_each", err)
+		}
+		re.log.Debugf(ctx, "Resource[%s] for_each: %d items", resource.Name, len(items))
+		if err := addForEachItems(execCtx, resource.Name, len(items)); err != nil {
+			execCtx.RecordResourceError(resource.Name, err.Error())
+			return nil, NewExecutorError(PhaseResources, resource.Name, "for_each exceeds limits", err)
+		}
+		names := make([]string, len(items))
+		for i, value := range items {
+			item := resource
+			item.Name = configloader.ForEachItemName(resource.Name, i)
+			item.ForEach = ""
+			names[i] = item.Name
+			execCtx.setForEachItem(item.Name, ForEachItem{Value: value, Index: i})
+			expanded = append(expanded, item)
+		}
+		execCtx.SetForEachResource(resource.Name, names)
+	}
+	return expanded, nil
+}
+
+// forEachContext returns ctx carrying the item of resource when it is a for_each item
+func forEachContext(ctx context.Context, execCtx *ExecutionContext, resource configloader.Resource) context.Context {
+	if item, ok := execCtx.forEachItem(resource.Name); ok {
+		return withForEachItem(ctx, item)
+	}
+	return ctx
+}
+
+// hasForEachResources reports whether any resource has a for_each expression
+func hasForEachResources(resources []configloader.Resource) bool {
+	for _, resource := range resources {
+		if resource.ForEach != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -2,9 +2,18 @@ package executor
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dedup"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/notification"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sharding"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sli"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
 )
@@ -44,6 +53,269 @@ func WithMetrics(h HandlerFunc, recorder *metrics.Recorder, log logger.Logger) H
 	}
 }
 
+// WithNotifications wraps a HandlerFunc to report execution outcomes per HyperFleet
+// resource to notifier, which notifies once a resource keeps failing.
+// If notifier is nil, the handler is returned unwrapped.
+func WithNotifications(h HandlerFunc, notifier *notification.Notifier) HandlerFunc {
+	if notifier == nil {
+		return h
+	}
+	return func(ctx context.Context, evt *event.Event) (*ExecutionResult, error) {
+		ctx, parsed := withParsedEvent(ctx, evt)
+		result, err := h(ctx, evt)
+
+		if parsed.err != nil {
+			return result, err
+		}
+		eventData := parsed.data
+		switch {
+		case err != nil:
+			notifier.RecordFailure(ctx, notification.Failure{
+				ResourceKind: eventData.Kind,
+				ResourceID:   eventData.ID,
+				Error:        err.Error(),
+			})
+		case result != nil && result.Status == StatusFailed:
+			failure := notification.Failure{ResourceKind: eventData.Kind, ResourceID: eventData.ID}
+			if len(result.Errors) > 0 {
+				failure.Phase = string(result.Errors[0].Phase)
+				failure.Error = result.Errors.String()
+			}
+			notifier.RecordFailure(ctx, failure)
+		default:
+			notifier.RecordSuccess(eventData.Kind, eventData.ID)
+		}
+		return result, err
+	}
+}
+
+// WithSharding wraps a HandlerFunc to skip events for resources owned by another replica.
+// Ownership is decided by the owner's ID when the event has owner references, so related
+// resources are processed by the same replica. Wrap it outside WithMetrics so events left
+// to other replicas are not counted as processed; they are counted on recorder as filtered
+// skips. If sharder is nil, the handler is returned unwrapped.
+func WithSharding(h HandlerFunc, sharder *sharding.Sharder, recorder *metrics.Recorder, log logger.Logger) HandlerFunc {
+	if sharder == nil {
+		return h
+	}
+	return func(ctx context.Context, evt *event.Event) (*ExecutionResult, error) {
+		ctx, parsed := withParsedEvent(ctx, evt)
+		if parsed.err != nil {
+			// Let the executor report the malformed event
+			return h(ctx, evt)
+		}
+		key := resourceKey(parsed.data)
+		if !sharder.Owns(key) {
+			log.Debugf(ctx, "Skipping event %s: resource %s belongs to shard %d of %d, this replica is shard %d",
+				evt.ID(), key, sharding.ShardOf(key, sharder.Count()), sharder.Count(), sharder.Index())
+			recorder.RecordSkip("", metrics.SkipReasonFiltered)
+			return &ExecutionResult{
+				Status:           StatusSuccess,
+				ResourcesSkipped: true,
+				SkipReason:       "resource is owned by another shard",
+			}, nil
+		}
+		return h(ctx, evt)
+	}
+}
+
+// WithEventFilter wraps a HandlerFunc to skip events for which the CEL expression filter is
+// not true. The expression sees the event's type, source, id, subject and datacontenttype,
+// its extension attributes as extensions, and its data as data. Evaluation errors, such as
+// a missing field, count as not true. name describes the filter in logs and skip reasons,
+// e.g. "event filter". Skipped events are counted on recorder as filtered. If filter is
+// empty, the handler is returned unwrapped.
+func WithEventFilter(
+	h HandlerFunc,
+	name, filter string,
+	recorder *metrics.Recorder,
+	log logger.Logger,
+) HandlerFunc {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return h
+	}
+	return func(ctx context.Context, evt *event.Event) (*ExecutionResult, error) {
+		ctx, parsed := withParsedEvent(ctx, evt)
+		if parsed.err != nil {
+			// Let the executor report the malformed event
+			return h(ctx, evt)
+		}
+		evalCtx := criteria.NewEvaluationContext()
+		evalCtx.Set("type", evt.Type())
+		evalCtx.Set("source", evt.Source())
+		evalCtx.Set("id", evt.ID())
+		evalCtx.Set("subject", evt.Subject())
+		evalCtx.Set("datacontenttype", evt.DataContentType())
+		evalCtx.Set("extensions", eventExtensions(evt))
+		evalCtx.Set("data", parsed.raw)
+		evaluator, err := criteria.NewEvaluator(ctx, evalCtx, log)
+		if err != nil {
+			return nil, err
+		}
+		result, err := evaluator.EvaluateCEL(filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate the %s: %w", name, err)
+		}
+		if !result.Matched {
+			if result.HasError() {
+				log.Debugf(ctx, "Skipping event %s: %s not evaluable: %v", evt.ID(), name, result.Error)
+			} else {
+				log.Debugf(ctx, "Skipping event %s: %s is not true", evt.ID(), name)
+			}
+			recorder.RecordSkip("", metrics.SkipReasonFiltered)
+			return &ExecutionResult{
+				Status:           StatusSuccess,
+				ResourcesSkipped: true,
+				SkipReason:       "event does not match the " + name,
+			}, nil
+		}
+		return h(ctx, evt)
+	}
+}
+
+// WithEventRouting wraps a broker handler to ack the events whose type or subject routing
+// does not match without handling them. Only the CloudEvent attributes are read, so wrap
+// it around the Acknowledger: unrouted events are neither decoded nor queued on the worker
+// pool. Skipped events are counted on recorder as filtered. If routing is not set, the
+// handler is returned unwrapped.
+func WithEventRouting(
+	h func(ctx context.Context, evt *event.Event) error,
+	routing configloader.EventRoutingConfig,
+	recorder *metrics.Recorder,
+	log logger.Logger,
+) func(ctx context.Context, evt *event.Event) error {
+	if !routing.IsSet() {
+		return h
+	}
+	return func(ctx context.Context, evt *event.Event) error {
+		if !routing.Matches(evt.Type(), evt.Subject()) {
+			log.Debugf(ctx, "Skipping event %s: type %q and subject %q are not routed to this adapter",
+				evt.ID(), evt.Type(), evt.Subject())
+			recorder.RecordSkip("", metrics.SkipReasonFiltered)
+			return nil
+		}
+		return h(ctx, evt)
+	}
+}
+
+// eventExtensions returns the extension attributes of an event. Strings, booleans and
+// integers keep their type; other values, such as URIs and timestamps, are formatted as
+// in the CloudEvents string encoding.
+func eventExtensions(evt *event.Event) map[string]interface{} {
+	extensions := make(map[string]interface{}, len(evt.Extensions()))
+	for name, value := range evt.Extensions() {
+		switch value.(type) {
+		case string, bool, int32:
+			extensions[name] = value
+		default:
+			if s, err := types.Format(value); err == nil {
+				extensions[name] = s
+			}
+		}
+	}
+	return extensions
+}
+
+// eventCompletedReason is the skip reason of an event whose generation already completed
+const eventCompletedReason = "event already completed"
+
+// WithDeduplication wraps a HandlerFunc to skip events whose workflow already completed,
+// such as broker redeliveries. An event is marked in store after it executes successfully,
+// keyed by its ID and the generation in its data. Store errors are logged and the event
+// is executed, so an unavailable store never drops events. If store is nil, the handler
+// is returned unwrapped.
+func WithDeduplication(h HandlerFunc, store dedup.Store, log logger.Logger) HandlerFunc {
+	if store == nil {
+		return h
+	}
+	return func(ctx context.Context, evt *event.Event) (*ExecutionResult, error) {
+		ctx, parsed := withParsedEvent(ctx, evt)
+		if parsed.err != nil || evt.ID() == "" {
+			// Let the executor report the malformed event
+			return h(ctx, evt)
+		}
+		eventData := parsed.data
+		key := dedup.Key(evt.ID(), eventData.Generation)
+		seen, err := store.Seen(ctx, key)
+		if err != nil {
+			errCtx := logger.WithErrorField(ctx, err)
+			log.Warnf(errCtx, "Failed to check event %s for duplicates, executing it", evt.ID())
+		} else if seen {
+			log.Infof(ctx, "Skipping event %s: generation %d already completed", evt.ID(), eventData.Generation)
+			return &ExecutionResult{
+				Status:           StatusSuccess,
+				ResourcesSkipped: true,
+				SkipReason:       eventCompletedReason,
+			}, nil
+		}
+
+		result, err := h(ctx, evt)
+		if err == nil && result != nil && result.Status == StatusSuccess {
+			if markErr := store.Mark(ctx, key); markErr != nil {
+				errCtx := logger.WithErrorField(ctx, markErr)
+				log.Warnf(errCtx, "Failed to record event %s as completed", evt.ID())
+			}
+		}
+		return result, err
+	}
+}
+
+// WithSLI wraps a HandlerFunc to record in tracker how long each event takes from being
+// received to its status being reported, by event type and generation freshness. The
+// freshness is decided before the event executes, against the last generation reported for
+// its resource. Wrap it inside WithDeduplication so skipped redeliveries are not counted.
+// If tracker is nil, the handler is returned unwrapped.
+func WithSLI(h HandlerFunc, tracker *sli.Tracker) HandlerFunc {
+	if tracker == nil {
+		return h
+	}
+	return func(ctx context.Context, evt *event.Event) (*ExecutionResult, error) {
+		start := time.Now()
+		ctx, parsed := withParsedEvent(ctx, evt)
+		if parsed.err != nil {
+			return h(ctx, evt)
+		}
+		eventData := parsed.data
+		key := resourceKey(eventData)
+		freshness := tracker.Freshness(key, eventData.Generation)
+
+		result, err := h(ctx, evt)
+
+		tracker.Observe(sli.Observation{
+			EventType:  evt.Type(),
+			Freshness:  freshness,
+			Resource:   key,
+			Generation: eventData.Generation,
+			Duration:   time.Since(start),
+			Reported:   err == nil && statusReported(result),
+		})
+		return result, err
+	}
+}
+
+// statusReported reports whether a post-action called the HyperFleet API successfully
+func statusReported(result *ExecutionResult) bool {
+	if result == nil {
+		return false
+	}
+	for _, r := range result.PostActionResults {
+		if r.APICallMade && r.Status == StatusSuccess {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceKey returns the key that groups related events: the owner's ID when the event
+// has owner references, else the resource ID
+func resourceKey(eventData *EventData) string {
+	if eventData.OwnerReferences != nil && eventData.OwnerReferences.ID != "" {
+		return eventData.OwnerReferences.ID
+	}
+	return eventData.ID
+}
+
 // AlwaysAck wraps a HandlerFunc into a broker compatible handler that always returns nil,
 // preventing infinite retry loops for non-recoverable errors.
 // Errors are logged at warn level before being discarded.
@@ -59,7 +331,7 @@ func AlwaysAck(h HandlerFunc, log logger.Logger) func(ctx context.Context, evt *
 			log.Warn(errCtx, "event handler error (acked)")
 		} else if result != nil && result.Status == StatusFailed {
 			phases := make([]string, 0, len(result.Errors))
-			for phase := range result.Errors {
+			for _, phase := range result.Errors.Phases() {
 				phases = append(phases, string(phase))
 			}
 			errCtx = logger.WithLogField(errCtx, "failed_phases", phases)
@@ -84,7 +356,7 @@ func recordMetrics(recorder *metrics.Recorder, result *ExecutionResult, duration
 	switch {
 	case result.Status == StatusFailed:
 		recorder.RecordEventProcessed("failed")
-		for phase := range result.Errors {
+		for _, phase := range result.Errors.Phases() {
 			recorder.RecordError(string(phase))
 		}
 	case result.ResourcesSkipped:
@@ -92,4 +364,40 @@ func recordMetrics(recorder *metrics.Recorder, result *ExecutionResult, duration
 	default:
 		recorder.RecordEventProcessed("success")
 	}
+
+	for _, warning := range result.Warnings {
+		recorder.RecordWarning(string(warning.Phase))
+		recorder.RecordStepWarning(string(warning.Phase), warning.Step)
+	}
+	if result.SkipReason == eventCompletedReason {
+		recorder.RecordSkip("", metrics.SkipReasonStaleEvent)
+	}
+	recordStepMetrics(recorder, result)
+}
+
+// recordStepMetrics records the status and duration of every step that ran, and the
+// reason of every step that was skipped
+func recordStepMetrics(recorder *metrics.Recorder, result *ExecutionResult) {
+	for _, r := range result.PreconditionResults {
+		recorder.RecordStep(metrics.StepTypePrecondition, r.Name, r.stepStatus(), r.Duration)
+		recordSkip(recorder, r.Name, r.skipReason())
+	}
+	for _, r := range result.ResourceResults {
+		recorder.RecordStep(r.stepType(), r.Name, r.stepStatus(), r.Duration)
+		recordSkip(recorder, r.Name, r.skipReason())
+	}
+	for _, r := range result.WaitResults {
+		recorder.RecordStep(metrics.StepTypeWait, r.Name, string(r.Status), r.Duration)
+	}
+	for _, r := range result.PostActionResults {
+		recorder.RecordStep(metrics.StepTypePostAction, r.Name, r.stepStatus(), r.Duration)
+		recordSkip(recorder, r.Name, r.skipReason())
+	}
+}
+
+// recordSkip records a skipped step, unless reason is empty
+func recordSkip(recorder *metrics.Recorder, step, reason string) {
+	if reason != "" {
+		recorder.RecordSkip(step, reason)
+	}
 }
@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/helm"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// renderHelmChart renders the chart of a helm resource with its values rendered from
+// params. With the maestro transport the manifests are wrapped into one ManifestWork,
+// so a single document is returned.
+func renderHelmChart(resource configloader.Resource, params map[string]interface{}) ([][]byte, error) {
+	h := resource.Helm
+	if h.LoadedChart == nil {
+		return nil, fmt.Errorf("chart %s of resource %s is not loaded", h.Chart, resource.Name)
+	}
+
+	releaseName, err := utils.RenderTemplate(h.ReleaseName, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm.release_name: %w", err)
+	}
+	namespace, err := utils.RenderTemplate(h.Namespace, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm.namespace: %w", err)
+	}
+	values, err := renderHelmValues(h.Values, params)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := helm.Render(h.LoadedChart, helm.Release{Name: releaseName, Namespace: namespace}, values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render chart %s: %w", h.LoadedChart.Metadata.Name, err)
+	}
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("chart %s rendered no manifests", h.LoadedChart.Metadata.Name)
+	}
+	if !resource.IsMaestroTransport() {
+		return docs, nil
+	}
+
+	workName, err := utils.RenderTemplate(h.GetManifestWorkName(), params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm.manifest_work_name: %w", err)
+	}
+	work, err := helmManifestWork(workName, docs)
+	if err != nil {
+		return nil, err
+	}
+	return [][]byte{work}, nil
+}
+
+// renderHelmValues renders the values of a helm resource like a manifest, so strings can
+// use the execution params and embed structured params with toYaml
+func renderHelmValues(values map[string]interface{}, params map[string]interface{}) (map[string]interface{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	valuesStr, err := manifest.ToYAMLString(values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert helm.values to string: %w", err)
+	}
+	rendered, err := manifest.RenderStringManifest(valuesStr, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render helm.values: %w", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(rendered, &out); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered helm.values: %w", err)
+	}
+	return out, nil
+}
+
+// helmManifestWork wraps the manifests of a chart into a ManifestWork named name
+func helmManifestWork(name string, docs [][]byte) ([]byte, error) {
+	manifests := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(doc, &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse rendered chart manifest: %w", err)
+		}
+		manifests = append(manifests, obj)
+	}
+	return json.Marshal(map[string]interface{}{
+		"apiVersion": constants.ManifestWorkGroup + "/" + constants.ManifestWorkVersion,
+		"kind":       constants.ManifestWorkKind,
+		"metadata":   map[string]interface{}{"name": name},
+		"spec": map[string]interface{}{
+			"workload": map[string]interface{}{"manifests": manifests},
+		},
+	})
+}
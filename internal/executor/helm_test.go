@@ -0,0 +1,110 @@
+package executor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/helm"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func loadTestChart(t *testing.T) *helm.Chart {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"Chart.yaml":  "apiVersion: v2\nname: agent\nversion: 0.1.0\n",
+		"values.yaml": "region: unknown\n",
+		"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+data:
+  region: {{ .Values.region | quote }}
+`,
+		"templates/serviceaccount.yaml": `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ .Release.Name }}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	chart, err := helm.LoadDir(dir)
+	require.NoError(t, err)
+	return chart
+}
+
+func TestResourceExecutor_ExecuteAll_HelmChart(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resource := configloader.Resource{
+		Name: "agent",
+		Helm: &configloader.HelmConfig{
+			Chart:       "charts/agent",
+			ReleaseName: "agent-{{ .clusterId }}",
+			Namespace:   "fleet",
+			Values:      map[string]interface{}{"region": "{{ .region }}"},
+			LoadedChart: loadTestChart(t),
+		},
+	}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["clusterId"] = "c1"
+	execCtx.Params["region"] = "us-east-1"
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSuccess, results[0].Status)
+	assert.Equal(t, "ServiceAccount", results[0].Kind, "the first manifest in install order is the resource's own")
+	require.Len(t, mock.Resources, 2)
+	cm := mock.Resources["fleet/agent-c1-config"]
+	require.NotNil(t, cm, "manifests should be put in the release namespace")
+	region, _, _ := unstructured.NestedString(cm.Object, "data", "region")
+	assert.Equal(t, "us-east-1", region)
+}
+
+func TestResourceExecutor_RenderHelmChartManifestWork(t *testing.T) {
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: k8sclient.NewMockK8sClient(),
+		Logger:          logger.NewTestLogger(),
+	})
+	resource := configloader.Resource{
+		Name: "agentWork",
+		Transport: &configloader.TransportConfig{
+			Client:  "maestro",
+			Maestro: &configloader.MaestroTransportConfig{TargetCluster: "mgmt-1"},
+		},
+		Helm: &configloader.HelmConfig{
+			Chart:            "charts/agent",
+			ReleaseName:      "agent",
+			Namespace:        "fleet",
+			ManifestWorkName: "agent-{{ .clusterId }}",
+			LoadedChart:      loadTestChart(t),
+		},
+	}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["clusterId"] = "c1"
+
+	rendered, extra, err := re.renderDocuments(context.Background(), resource, execCtx)
+	require.NoError(t, err)
+	assert.Empty(t, extra)
+	work, err := manifest.ParseManifestWork(rendered)
+	require.NoError(t, err)
+	assert.Equal(t, "agent-c1", work.Name)
+	require.Len(t, work.Spec.Workload.Manifests, 2)
+	assert.Contains(t, string(work.Spec.Workload.Manifests[0].Raw), `"kind":"ServiceAccount"`)
+	assert.Contains(t, string(work.Spec.Workload.Manifests[1].Raw), `"region":"unknown"`)
+}
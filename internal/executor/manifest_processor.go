@@ -0,0 +1,89 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ManifestProcessor post-processes the rendered manifests of a resource before they are
+// applied, for org-specific changes such as injected sidecars or cost labels that would
+// otherwise be duplicated across task configs. Processors are registered with
+// ExecutorBuilder.WithManifestProcessor and run in registration order.
+type ManifestProcessor interface {
+	// Name identifies the processor in logs and errors
+	Name() string
+	// ProcessManifest may modify obj in place. Returning an error fails the resource
+	// step; return VetoManifest to refuse to apply obj.
+	ProcessManifest(ctx context.Context, obj *unstructured.Unstructured, mctx ManifestContext) error
+}
+
+// ManifestContext describes the manifest a ManifestProcessor is given
+type ManifestContext struct {
+	// Params are the execution params; processors must not modify them
+	Params map[string]interface{}
+	// Resource is the name of the resource in the task config
+	Resource string
+	// Transport is the transport client the manifest is applied with
+	Transport string
+	// Document is the index of the manifest among the documents rendered for the resource:
+	// 0 for the manifest, or ManifestWork with maestro, and 1+ for extra documents
+	Document int
+}
+
+// ManifestVetoError is the error of a manifest a ManifestProcessor refused to apply
+type ManifestVetoError struct {
+	Processor string
+	Reason    string
+}
+
+func (e *ManifestVetoError) Error() string {
+	return fmt.Sprintf("manifest vetoed by %s: %s", e.Processor, e.Reason)
+}
+
+// VetoManifest returns the error a ManifestProcessor returns to refuse to apply a manifest
+func VetoManifest(reason string) error {
+	return &ManifestVetoError{Reason: reason}
+}
+
+// processManifests runs the manifest processors on the rendered documents of resource
+func (re *ResourceExecutor) processManifests(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+	docs [][]byte,
+) ([][]byte, error) {
+	params := stepParams(ctx, execCtx)
+	processed := make([][]byte, len(docs))
+	for i, doc := range docs {
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal(doc, &obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to parse rendered manifest: %w", err)
+		}
+		mctx := ManifestContext{
+			Params:    params,
+			Resource:  resource.Name,
+			Transport: resource.GetTransportClient(),
+			Document:  i,
+		}
+		for _, processor := range re.processors {
+			if err := processor.ProcessManifest(ctx, obj, mctx); err != nil {
+				var veto *ManifestVetoError
+				if errors.As(err, &veto) {
+					return nil, &ManifestVetoError{Processor: processor.Name(), Reason: veto.Reason}
+				}
+				return nil, fmt.Errorf("manifest processor %s failed: %w", processor.Name(), err)
+			}
+			re.log.Debugf(ctx, "Resource[%s] manifest %d processed by %s", resource.Name, i, processor.Name())
+		}
+		var err error
+		if processed[i], err = json.Marshal(obj.Object); err != nil {
+			return nil, fmt.Errorf("failed to marshal processed manifest: %w", err)
+		}
+	}
+	return processed, nil
+}
@@ -0,0 +1,100 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// labelProcessor adds a cost-center label, read from the params, to every manifest
+type labelProcessor struct {
+	contexts []ManifestContext
+}
+
+func (p *labelProcessor) Name() string { return "cost-labels" }
+
+func (p *labelProcessor) ProcessManifest(
+	_ context.Context,
+	obj *unstructured.Unstructured,
+	mctx ManifestContext,
+) error {
+	p.contexts = append(p.contexts, mctx)
+	labels := obj.GetLabels()
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels["cost-center"], _ = mctx.Params["costCenter"].(string)
+	obj.SetLabels(labels)
+	return nil
+}
+
+// rejectingProcessor returns err for every manifest
+type rejectingProcessor struct {
+	err error
+}
+
+func (p *rejectingProcessor) Name() string { return "policy" }
+
+func (p *rejectingProcessor) ProcessManifest(context.Context, *unstructured.Unstructured, ManifestContext) error {
+	return p.err
+}
+
+func TestResourceExecutor_ManifestProcessors(t *testing.T) {
+	newExecCtx := func() *ExecutionContext {
+		execCtx := NewExecutionContext(context.Background(), nil, nil)
+		execCtx.SetParam("costCenter", "cc-42")
+		return execCtx
+	}
+	resource := newResourceWithLifecycle("", "")
+
+	mock := k8sclient.NewMockK8sClient()
+	labels := &labelProcessor{}
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient:    mock,
+		Logger:             logger.NewTestLogger(),
+		ManifestProcessors: []ManifestProcessor{labels},
+	})
+	_, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, newExecCtx())
+	require.NoError(t, err)
+	assert.Equal(t, "cc-42", mock.Resources["default/test-cm"].GetLabels()["cost-center"],
+		"the processed manifest should be applied")
+	require.Len(t, labels.contexts, 1)
+	assert.Equal(t, "test-resource", labels.contexts[0].Resource)
+	assert.Equal(t, "kubernetes", labels.contexts[0].Transport)
+
+	for name, tt := range map[string]struct {
+		err     error
+		message string
+		veto    bool
+	}{
+		"veto": {err: VetoManifest("privileged containers are not allowed"), veto: true,
+			message: "manifest vetoed by policy: privileged containers are not allowed"},
+		"error": {err: errors.New("policy service unavailable"),
+			message: "manifest processor policy failed: policy service unavailable"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mock := k8sclient.NewMockK8sClient()
+			re := newResourceExecutor(&ExecutorConfig{
+				TransportClient:    mock,
+				Logger:             logger.NewTestLogger(),
+				ManifestProcessors: []ManifestProcessor{&labelProcessor{}, &rejectingProcessor{err: tt.err}},
+			})
+			results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, newExecCtx())
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.message)
+			var veto *ManifestVetoError
+			assert.Equal(t, tt.veto, errors.As(err, &veto))
+			require.Len(t, results, 1)
+			assert.Equal(t, StatusFailed, results[0].Status)
+			assert.Empty(t, mock.Resources, "a rejected manifest should not be applied")
+		})
+	}
+}
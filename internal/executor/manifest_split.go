@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// manifestWorkGVK is the GVK of the ManifestWorks applied by the maestro transport
+var manifestWorkGVK = schema.GroupVersionKind{
+	Group:   constants.ManifestWorkGroup,
+	Version: constants.ManifestWorkVersion,
+	Kind:    constants.ManifestWorkKind,
+}
+
+// orderManifestWork sorts the manifests of a rendered ManifestWork by the ordering tiers
+func orderManifestWork(rendered []byte, ordering *configloader.ManifestOrderingConfig) ([]byte, error) {
+	work, err := manifest.ParseManifestWork(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rendered ManifestWork: %w", err)
+	}
+	manifest.OrderManifestWork(work, ordering.KindTiers())
+	return json.Marshal(work)
+}
+
+// splitApplyResult is the outcome of applying the ManifestWorks of a split resource
+type splitApplyResult struct {
+	*transportclient.ApplyResult
+	// Deferred is true when a tier was not applied because the previous one is not applied yet
+	Deferred bool
+}
+
+// applySplitWork applies the ManifestWorks of a split resource in tier order. A tier is
+// only applied once the ManifestWork of the previous tier was left unchanged by this
+// execution and the work agent reported it as applied; otherwise the remaining tiers
+// are deferred to a later execution. The operation reported is the first one that
+// changed a ManifestWork.
+func (re *ResourceExecutor) applySplitWork(
+	ctx context.Context,
+	resource configloader.Resource,
+	rendered []byte,
+	opts *transportclient.ApplyOptions,
+	target transportclient.TransportContext,
+) (*splitApplyResult, error) {
+	work, err := manifest.ParseManifestWork(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rendered ManifestWork: %w", err)
+	}
+	works := manifest.SplitManifestWork(work, resource.Transport.Maestro.Ordering.KindTiers())
+
+	result := &splitApplyResult{ApplyResult: &transportclient.ApplyResult{Operation: manifest.OperationSkip}}
+	reasons := make([]string, 0, len(works))
+	for i, w := range works {
+		if i > 0 {
+			previous := works[i-1]
+			ready, reason, readyErr := re.splitWorkReady(ctx, previous, target)
+			if readyErr != nil {
+				return nil, readyErr
+			}
+			if !ready {
+				result.Deferred = true
+				reasons = append(reasons, fmt.Sprintf("waiting for ManifestWork %s (%s) before %s",
+					previous.Name, reason, w.Name))
+				re.log.Infof(ctx, "Resource[%s] deferred ManifestWork %s: %s is %s",
+					resource.Name, w.Name, previous.Name, reason)
+				break
+			}
+		}
+
+		data, marshalErr := json.Marshal(w)
+		if marshalErr != nil {
+			return nil, fmt.Errorf("failed to marshal ManifestWork %s: %w", w.Name, marshalErr)
+		}
+		applied, applyErr := re.client.ApplyResource(ctx, data, opts, target)
+		if applyErr != nil {
+			return nil, fmt.Errorf("ManifestWork %s: %w", w.Name, applyErr)
+		}
+		if result.Operation == manifest.OperationSkip {
+			result.Operation = applied.Operation
+		}
+		reasons = append(reasons, fmt.Sprintf("%s %s: %s", w.Name, applied.Operation, applied.Reason))
+		if applied.Operation != manifest.OperationSkip && i < len(works)-1 {
+			// The work agent has not seen this version yet: the next tier waits for it
+			result.Deferred = true
+			reasons = append(reasons, fmt.Sprintf("waiting for ManifestWork %s before %s", w.Name, works[i+1].Name))
+			break
+		}
+	}
+	result.Reason = strings.Join(reasons, "; ")
+	return result, nil
+}
+
+// splitWorkReady reports whether the ManifestWork of a tier is applied on the target
+// cluster, so the next tier can be applied. When it is not, reason says why.
+func (re *ResourceExecutor) splitWorkReady(
+	ctx context.Context,
+	work *workv1.ManifestWork,
+	target transportclient.TransportContext,
+) (bool, string, error) {
+	obj, err := re.client.GetResource(ctx, manifestWorkGVK, work.Namespace, work.Name, target)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to get ManifestWork %s: %w", work.Name, err)
+	}
+	var current workv1.ManifestWork
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &current); err != nil {
+		return false, "", fmt.Errorf("failed to read ManifestWork %s: %w", work.Name, err)
+	}
+	if !manifest.IsManifestWorkApplied(&current) {
+		return false, "not applied yet", nil
+	}
+	return true, "", nil
+}
+
+// deleteSplitWorks deletes the ManifestWorks a split resource creates besides the one
+// named workName, the last tiers first. Works that do not exist are ignored. An empty
+// workName is read from the rendered manifest, for when the resource was not discovered.
+func (re *ResourceExecutor) deleteSplitWorks(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+	namespace, workName string,
+	target transportclient.TransportContext,
+) error {
+	if workName == "" {
+		rendered, err := re.renderToBytes(ctx, resource, execCtx)
+		if err != nil {
+			return fmt.Errorf("failed to render ManifestWork: %w", err)
+		}
+		work, err := manifest.ParseManifestWork(rendered)
+		if err != nil {
+			return fmt.Errorf("failed to parse rendered ManifestWork: %w", err)
+		}
+		namespace, workName = work.Namespace, work.Name
+	}
+
+	names := manifest.SplitWorkNames(workName, resource.Transport.Maestro.Ordering.KindTiers())
+	for i := len(names) - 1; i >= 0; i-- {
+		err := re.client.DeleteResource(ctx, manifestWorkGVK, namespace, names[i], nil, target)
+		execCtx.invalidateDiscovery(manifestWorkGVK, namespace, target)
+		if err != nil {
+			return fmt.Errorf("failed to delete ManifestWork %s: %w", names[i], err)
+		}
+	}
+	return nil
+}
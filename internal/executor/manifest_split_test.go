@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// skipExistingMockClient wraps MockK8sClient and reports a skip when the applied object
+// already exists, like the generation comparison of the Maestro client.
+type skipExistingMockClient struct {
+	*k8sclient.MockK8sClient
+}
+
+func (m *skipExistingMockClient) ApplyResource(
+	ctx context.Context,
+	manifestBytes []byte,
+	opts *transportclient.ApplyOptions,
+	target transportclient.TransportContext,
+) (*transportclient.ApplyResult, error) {
+	var obj unstructured.Unstructured
+	if err := json.Unmarshal(manifestBytes, &obj.Object); err != nil {
+		return nil, err
+	}
+	if _, ok := m.Resources[obj.GetNamespace()+"/"+obj.GetName()]; ok {
+		return &transportclient.ApplyResult{Operation: manifest.OperationSkip, Reason: "generation 1 unchanged"}, nil
+	}
+	return m.MockK8sClient.ApplyResource(ctx, manifestBytes, opts, target)
+}
+
+// markWorkApplied sets the Applied condition the work agent reports on a ManifestWork
+func markWorkApplied(t *testing.T, obj *unstructured.Unstructured) {
+	t.Helper()
+	require.NoError(t, unstructured.SetNestedSlice(obj.Object, []interface{}{
+		map[string]interface{}{"type": "Applied", "status": "True", "reason": "AppliedManifestWorkComplete"},
+	}, "status", "conditions"))
+}
+
+func newSplitResource(split bool) configloader.Resource {
+	return configloader.Resource{
+		Name: "clusterWork",
+		Transport: &configloader.TransportConfig{
+			Client: configloader.TransportClientMaestro,
+			Maestro: &configloader.MaestroTransportConfig{
+				TargetCluster: "cluster-1",
+				Ordering:      &configloader.ManifestOrderingConfig{Split: split},
+			},
+		},
+		Manifest: map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "cluster-1-work", "namespace": "cluster-1"},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []interface{}{
+						map[string]interface{}{
+							"apiVersion": "apps/v1", "kind": "Deployment",
+							"metadata": map[string]interface{}{"name": "app", "namespace": "cluster-1"},
+						},
+						map[string]interface{}{
+							"apiVersion": "v1", "kind": "Namespace",
+							"metadata": map[string]interface{}{"name": "cluster-1"},
+						},
+					},
+				},
+			},
+		},
+		Discovery: &configloader.DiscoveryConfig{Namespace: "cluster-1", ByName: "cluster-1-work"},
+	}
+}
+
+func TestResourceExecutor_OrdersManifestWork(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	_, err := re.ExecuteAll(context.Background(), []configloader.Resource{newSplitResource(false)}, execCtx)
+	require.NoError(t, err)
+
+	stored := mock.Resources["cluster-1/cluster-1-work"]
+	require.NotNil(t, stored)
+	manifests, _, _ := unstructured.NestedSlice(stored.Object, "spec", "workload", "manifests")
+	require.Len(t, manifests, 2)
+	assert.Equal(t, "Namespace", manifests[0].(map[string]interface{})["kind"])
+	assert.Equal(t, "Deployment", manifests[1].(map[string]interface{})["kind"])
+}
+
+func TestResourceExecutor_SplitManifestWork(t *testing.T) {
+	mock := &skipExistingMockClient{MockK8sClient: k8sclient.NewMockK8sClient()}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+	resource := newSplitResource(true)
+
+	execute := func() ResourceResult {
+		execCtx := NewExecutionContext(context.Background(), nil, nil)
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, StatusSuccess, results[0].Status)
+		return results[0]
+	}
+
+	// First execution: only the namespaces tier is created
+	result := execute()
+	assert.Equal(t, manifest.OperationCreate, result.Operation)
+	assert.Contains(t, result.OperationReason, "waiting for ManifestWork cluster-1-work-namespaces")
+	assert.Contains(t, mock.Resources, "cluster-1/cluster-1-work-namespaces")
+	assert.NotContains(t, mock.Resources, "cluster-1/cluster-1-work")
+
+	// The namespaces tier is unchanged but not applied yet: the workloads tier still waits
+	result = execute()
+	assert.Equal(t, manifest.OperationSkip, result.Operation)
+	assert.Contains(t, result.OperationReason, "not applied yet")
+	assert.NotContains(t, mock.Resources, "cluster-1/cluster-1-work")
+
+	// Once the work agent applied the namespaces tier, the workloads tier follows
+	markWorkApplied(t, mock.Resources["cluster-1/cluster-1-work-namespaces"])
+	result = execute()
+	assert.Equal(t, manifest.OperationCreate, result.Operation)
+	assert.NotContains(t, result.OperationReason, "waiting")
+	require.Contains(t, mock.Resources, "cluster-1/cluster-1-work")
+
+	workload := mock.Resources["cluster-1/cluster-1-work"]
+	assert.Equal(t, "cluster-1-work-namespaces", workload.GetAnnotations()["hyperfleet.io/depends-on"])
+	manifests, _, _ := unstructured.NestedSlice(workload.Object, "spec", "workload", "manifests")
+	require.Len(t, manifests, 1)
+	assert.Equal(t, "Deployment", manifests[0].(map[string]interface{})["kind"])
+}
+
+func TestResourceExecutor_SplitManifestWork_Delete(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	for _, name := range []string{"cluster-1-work", "cluster-1-work-namespaces", "unrelated"} {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("work.open-cluster-management.io/v1")
+		obj.SetKind("ManifestWork")
+		obj.SetNamespace("cluster-1")
+		obj.SetName(name)
+		mock.Resources["cluster-1/"+name] = obj
+	}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resource := newSplitResource(true)
+	resource.Lifecycle = &configloader.ResourceLifecycle{
+		Delete: &configloader.LifecycleDelete{When: &configloader.LifecycleWhen{Expression: "true"}},
+	}
+
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, manifest.OperationDelete, results[0].Operation)
+	assert.NotContains(t, mock.Resources, "cluster-1/cluster-1-work")
+	assert.NotContains(t, mock.Resources, "cluster-1/cluster-1-work-namespaces")
+	assert.Contains(t, mock.Resources, "cluster-1/unrelated")
+}
@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// namespaceManagedBy is the managed-by label value of the namespaces the adapter creates
+const namespaceManagedBy = "hyperfleet-adapter"
+
+// namespaceGVK is the GroupVersionKind of core/v1 Namespace
+var namespaceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+// ensuresNamespace returns true if the missing namespaces of resource are created before
+// it is applied, per ensure_namespace or clients.kubernetes.manage_namespaces
+func ensuresNamespace(resource *configloader.Resource, execCtx *ExecutionContext) bool {
+	manageNamespaces := execCtx.Config != nil && execCtx.Config.Clients.Kubernetes.ManageNamespaces
+	return resource.EnsuresNamespace(manageNamespaces)
+}
+
+// ensureNamespaces creates the namespaces of the rendered documents that do not exist,
+// labeled as managed by the adapter. Namespaces that exist are left untouched, and so are
+// namespaces declared as documents of the resource itself.
+func (re *ResourceExecutor) ensureNamespaces(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+	docs ...[]byte,
+) error {
+	var namespaces []string
+	declared := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, doc := range docs {
+		var obj unstructured.Unstructured
+		if err := json.Unmarshal(doc, &obj.Object); err != nil {
+			return fmt.Errorf("failed to parse rendered manifest: %w", err)
+		}
+		if obj.GroupVersionKind() == namespaceGVK {
+			declared[obj.GetName()] = true
+			continue
+		}
+		if ns := obj.GetNamespace(); ns != "" && !seen[ns] {
+			seen[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	for _, ns := range namespaces {
+		if declared[ns] {
+			continue
+		}
+		_, err := re.client.GetResource(ctx, namespaceGVK, "", ns, nil)
+		if err == nil {
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get namespace %s: %w", ns, err)
+		}
+
+		nsManifest, err := json.Marshal(namespaceManifest(ns, execCtx))
+		if err != nil {
+			return fmt.Errorf("failed to build namespace %s: %w", ns, err)
+		}
+		if _, err := re.client.ApplyResource(ctx, nsManifest, nil, nil); err != nil {
+			return fmt.Errorf("failed to create namespace %s: %w", ns, err)
+		}
+		execCtx.invalidateDiscovery(namespaceGVK, "", nil)
+		re.log.Infof(ctx, "Resource[%s] created namespace %s", resource.Name, ns)
+	}
+	return nil
+}
+
+// namespaceManifest returns a Namespace labeled as managed by the adapter
+func namespaceManifest(name string, execCtx *ExecutionContext) map[string]interface{} {
+	labels := map[string]interface{}{
+		constants.LabelManagedBy: namespaceManagedBy,
+	}
+	if execCtx.Config != nil && execCtx.Config.Adapter.Name != "" {
+		labels[constants.LabelAdapter] = execCtx.Config.Adapter.Name
+	}
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]interface{}{
+			"name":   name,
+			"labels": labels,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func TestResourceExecutor_EnsureNamespace(t *testing.T) {
+	resource := configloader.Resource{
+		Name: "agentConfig",
+		Manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: agent-config
+  namespace: "{{ .namespace }}"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: agent-creds
+  namespace: existing
+`,
+		Discovery: &configloader.DiscoveryConfig{Namespace: "{{ .namespace }}", ByName: "agent-config"},
+	}
+
+	run := func(t *testing.T, resource configloader.Resource, config *configloader.Config) *k8sclient.MockK8sClient {
+		t.Helper()
+		mock := k8sclient.NewMockK8sClient()
+		mock.Resources["/existing"] = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata":   map[string]interface{}{"name": "existing"},
+		}}
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+		execCtx := NewExecutionContext(context.Background(), nil, config)
+		execCtx.Params["namespace"] = "cluster-c1"
+
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, StatusSuccess, results[0].Status)
+		return mock
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		mock := run(t, resource, nil)
+		assert.NotContains(t, mock.Resources, "/cluster-c1", "namespaces are not created by default")
+	})
+
+	t.Run("ensure_namespace", func(t *testing.T) {
+		r := resource
+		r.EnsureNamespace = true
+		mock := run(t, r, &configloader.Config{Adapter: configloader.AdapterInfo{Name: "agent-adapter"}})
+
+		ns := mock.Resources["/cluster-c1"]
+		require.NotNil(t, ns, "the missing namespace should be created")
+		assert.Equal(t, "Namespace", ns.GetKind())
+		assert.Equal(t, map[string]string{
+			constants.LabelManagedBy: "hyperfleet-adapter",
+			constants.LabelAdapter:   "agent-adapter",
+		}, ns.GetLabels())
+		assert.Empty(t, mock.Resources["/existing"].GetLabels(), "existing namespaces are left untouched")
+		assert.Contains(t, mock.Resources, "cluster-c1/agent-config")
+	})
+
+	t.Run("manage_namespaces", func(t *testing.T) {
+		config := &configloader.Config{}
+		config.Clients.Kubernetes.ManageNamespaces = true
+		mock := run(t, resource, config)
+		assert.Contains(t, mock.Resources, "/cluster-c1")
+	})
+}
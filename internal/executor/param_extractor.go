@@ -2,7 +2,6 @@ package executor
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -23,19 +22,29 @@ func extractConfigParams(
 	execCtx *ExecutionContext,
 	configMap map[string]interface{},
 	apiClient hyperfleetapi.Client,
+	secrets SecretProvider,
 	log logger.Logger,
 ) error {
 	for _, param := range config.Params {
-		value, err := extractParam(ctx, param, execCtx, configMap, apiClient, log)
+		value, err := extractParam(ctx, param, execCtx, configMap, apiClient, secrets, log)
 		if err != nil {
 			if param.Required {
 				return NewExecutorError(PhaseParamExtraction, param.Name,
 					fmt.Sprintf("failed to extract required parameter '%s' from source '%s'",
 						param.Name, param.Source.Describe()), err)
 			}
+			// A configured default is the expected fallback; without one the param is left
+			// unset, which templates and CEL expressions may not expect
 			if param.Default != nil {
-				execCtx.Params[param.Name] = param.Default
+				if setErr := execCtx.SetVariable(param.Name, param.Default); setErr != nil {
+					return NewExecutorError(PhaseParamExtraction, param.Name, "failed to set parameter", setErr)
+				}
+				continue
 			}
+			warning := fmt.Sprintf("optional parameter not resolved from source '%s', leaving it unset: %v",
+				param.Source.Describe(), err)
+			log.Warnf(ctx, "Parameter '%s': %s", param.Name, warning)
+			execCtx.AddWarning(PhaseParamExtraction, param.Name, warning)
 			continue
 		}
 
@@ -55,8 +64,14 @@ func extractConfigParams(
 					return NewExecutorError(PhaseParamExtraction, param.Name,
 						fmt.Sprintf("failed to convert parameter '%s' to type '%s'", param.Name, param.Type), convErr)
 				}
+				warning := fmt.Sprintf("optional parameter not converted to type '%s'%s: %v",
+					param.Type, fallbackSuffix(param), convErr)
+				log.Warnf(ctx, "Parameter '%s': %s", param.Name, warning)
+				execCtx.AddWarning(PhaseParamExtraction, param.Name, warning)
 				if param.Default != nil {
-					execCtx.Params[param.Name] = param.Default
+					if err := execCtx.SetVariable(param.Name, param.Default); err != nil {
+						return NewExecutorError(PhaseParamExtraction, param.Name, "failed to set parameter", err)
+					}
 				}
 				continue
 			}
@@ -64,13 +79,23 @@ func extractConfigParams(
 		}
 
 		if value != nil {
-			execCtx.Params[param.Name] = value
+			if err := execCtx.SetVariable(param.Name, value); err != nil {
+				return NewExecutorError(PhaseParamExtraction, param.Name, "failed to set parameter", err)
+			}
 		}
 	}
 
 	return nil
 }
 
+// fallbackSuffix describes what an optional parameter falls back to when it cannot be resolved
+func fallbackSuffix(param configloader.Parameter) string {
+	if param.Default != nil {
+		return fmt.Sprintf(", using default %v", param.Default)
+	}
+	return ", leaving it unset"
+}
+
 // extractParam resolves a single parameter based on its source kind
 func extractParam(
 	ctx context.Context,
@@ -78,6 +103,7 @@ func extractParam(
 	execCtx *ExecutionContext,
 	configMap map[string]interface{},
 	apiClient hyperfleetapi.Client,
+	secrets SecretProvider,
 	log logger.Logger,
 ) (interface{}, error) {
 	switch {
@@ -87,6 +113,8 @@ func extractParam(
 		return extractFromCELExpression(ctx, param, execCtx, log)
 	case param.Source.IsFile():
 		return extractFromFile(param)
+	case param.Source.IsVault():
+		return extractFromSecretProvider(ctx, param, secrets)
 	case param.Source.IsString():
 		return extractFromStringSource(param, execCtx.EventData, configMap, execCtx.Params)
 	default:
@@ -130,7 +158,8 @@ func extractFromStringSource(
 	}
 }
 
-// extractFromAPICall makes an HTTP call, stores the parsed JSON response map as the param value
+// extractFromAPICall makes an HTTP call, stores the parsed JSON response map, or the result
+// of its transform, as the param value
 func extractFromAPICall(
 	ctx context.Context,
 	param configloader.Parameter,
@@ -146,10 +175,17 @@ func extractFromAPICall(
 	if validationErr := ValidateAPIResponse(resp, err, ac.Method, renderedURL); validationErr != nil {
 		return nil, validationErr
 	}
-	var responseData map[string]interface{}
-	if jsonErr := json.Unmarshal(resp.Body, &responseData); jsonErr != nil {
+	responseData, jsonErr := ParseAPIResponse(ac, resp, execCtx.Config.StrictJSONNumbers())
+	if jsonErr != nil {
 		return nil, fmt.Errorf("param %q: failed to parse API response as JSON: %w", param.Name, jsonErr)
 	}
+	if ac.Transform != "" {
+		transformed, err := transformStepResult(ctx, log, param.Name, ac.Transform, responseData)
+		if err != nil {
+			return nil, fmt.Errorf("param %q: %w", param.Name, err)
+		}
+		return transformed, nil
+	}
 	return responseData, nil
 }
 
@@ -176,6 +212,26 @@ func extractFromCELExpression(
 	return result.Value, nil
 }
 
+// extractFromSecretProvider resolves a vault.<path>#<key> source. The value is never logged.
+func extractFromSecretProvider(
+	ctx context.Context,
+	param configloader.Parameter,
+	secrets SecretProvider,
+) (interface{}, error) {
+	if secrets == nil {
+		return nil, fmt.Errorf("param %q: no secret provider configured (clients.vault)", param.Name)
+	}
+	path, key, err := param.Source.VaultRef()
+	if err != nil {
+		return nil, fmt.Errorf("param %q: %w", param.Name, err)
+	}
+	value, err := secrets.GetSecret(ctx, path, key)
+	if err != nil {
+		return nil, fmt.Errorf("param %q: %w", param.Name, err)
+	}
+	return value, nil
+}
+
 // maxFileSourceSize is a defensive cap for file-based parameter sources (1 MB).
 const maxFileSourceSize = 1 << 20
 
@@ -243,13 +299,13 @@ func extractFromEnv(envVar string) (interface{}, error) {
 
 // addAdapterParams adds adapter info, config, env, and event to execCtx.Params
 func addAdapterParams(config *configloader.Config, execCtx *ExecutionContext, configMap map[string]interface{}) {
-	execCtx.Params["adapter"] = map[string]interface{}{
+	execCtx.SetParam("adapter", map[string]interface{}{
 		"name":    config.Adapter.Name,
 		"version": config.Adapter.Version,
-	}
-	execCtx.Params["config"] = configMap
-	execCtx.Params["env"] = buildEnvMap()
-	execCtx.Params["event"] = execCtx.EventData
+	})
+	execCtx.SetParam("config", configMap)
+	execCtx.SetParam("env", buildEnvMap())
+	execCtx.SetParam("event", execCtx.EventData)
 }
 
 // convertParamType converts a value to the specified type.
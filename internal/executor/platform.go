@@ -0,0 +1,88 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// resolvePlatform evaluates the platform expressions of the config against the CEL
+// context of the execution and stores the spoke platform in the platform param:
+//
+//	{"os": "linux", "architecture": "arm64", "values": {<map name>: <selected value>}}
+//
+// It does nothing when the config has no platform section.
+func resolvePlatform(ctx context.Context, execCtx *ExecutionContext, log logger.Logger) error {
+	platform := execCtx.Config.Platform
+	if platform == nil {
+		return nil
+	}
+
+	evalCtx := criteria.NewEvaluationContext()
+	evalCtx.SetVariablesFromMap(execCtx.GetCELVariables())
+	evaluator, err := criteria.NewEvaluator(ctx, evalCtx, log)
+	if err != nil {
+		return fmt.Errorf("failed to create CEL evaluator: %w", err)
+	}
+
+	os, err := evaluatePlatformField(evaluator, configloader.FieldOS, platform.OS, utils.DefaultPlatformOS)
+	if err != nil {
+		return err
+	}
+	arch, err := evaluatePlatformField(evaluator, configloader.FieldArchitecture, platform.Architecture,
+		utils.DefaultPlatformArchitecture)
+	if err != nil {
+		return err
+	}
+	os, arch = utils.NormalizeOS(os), utils.NormalizeArchitecture(arch)
+
+	values := make(map[string]interface{}, len(platform.Maps))
+	for name, m := range platform.Maps {
+		value, ok := utils.SelectPlatformValue(m, os, arch)
+		if !ok {
+			return fmt.Errorf("platform map %q has no value for %s/%s and no %q value",
+				name, os, arch, utils.PlatformDefaultKey)
+		}
+		values[name] = value
+	}
+
+	execCtx.SetParam(configloader.FieldPlatform, map[string]interface{}{
+		configloader.FieldOS:           os,
+		configloader.FieldArchitecture: arch,
+		"values":                       values,
+	})
+	log.Debugf(ctx, "Platform resolved: %s/%s", os, arch)
+	return nil
+}
+
+// evaluatePlatformField evaluates a platform expression to a string. An empty expression,
+// or one evaluating to null or "", gives fallback.
+func evaluatePlatformField(evaluator *criteria.Evaluator, field, expression, fallback string) (string, error) {
+	if strings.TrimSpace(expression) == "" {
+		return fallback, nil
+	}
+	result, err := evaluator.EvaluateCEL(expression)
+	if err == nil && result.HasError() {
+		err = result.Error
+	}
+	if err != nil {
+		return "", fmt.Errorf("platform %s expression %q failed to evaluate: %w", field, expression, err)
+	}
+	if result.Value == nil {
+		return fallback, nil
+	}
+	value, ok := result.Value.(string)
+	if !ok {
+		return "", fmt.Errorf("platform %s expression %q must return a string, got %s",
+			field, expression, result.ValueType)
+	}
+	if value == "" {
+		return fallback, nil
+	}
+	return value, nil
+}
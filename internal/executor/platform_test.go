@@ -0,0 +1,97 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func newPlatformExecutionContext(platform *configloader.PlatformConfig) *ExecutionContext {
+	execCtx := NewExecutionContext(context.Background(), nil, &configloader.Config{Platform: platform})
+	execCtx.SetParam("clusterArch", "aarch64")
+	return execCtx
+}
+
+func TestResolvePlatform(t *testing.T) {
+	execCtx := newPlatformExecutionContext(&configloader.PlatformConfig{
+		Architecture: "clusterArch",
+		Maps: map[string]map[string]interface{}{
+			"agentImage":   {"linux/arm64": "agent:1.4-arm64", "default": "agent:1.4"},
+			"nodeSelector": {"default": map[string]interface{}{"kubernetes.io/os": "linux"}},
+		},
+	})
+
+	require.NoError(t, resolvePlatform(context.Background(), execCtx, logger.NewTestLogger()))
+
+	platform, ok := execCtx.GetParam(configloader.FieldPlatform)
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{
+		"os":           "linux",
+		"architecture": "arm64",
+		"values": map[string]interface{}{
+			"agentImage":   "agent:1.4-arm64",
+			"nodeSelector": map[string]interface{}{"kubernetes.io/os": "linux"},
+		},
+	}, platform)
+}
+
+func TestResolvePlatform_Defaults(t *testing.T) {
+	execCtx := newPlatformExecutionContext(&configloader.PlatformConfig{
+		Architecture: `resources.?agent.?status.?arch.orValue("")`,
+	})
+
+	require.NoError(t, resolvePlatform(context.Background(), execCtx, logger.NewTestLogger()))
+
+	platform, _ := execCtx.GetParam(configloader.FieldPlatform)
+	assert.Equal(t, "linux", platform.(map[string]interface{})["os"])
+	assert.Equal(t, "amd64", platform.(map[string]interface{})["architecture"])
+}
+
+func TestResolvePlatform_Errors(t *testing.T) {
+	tests := []struct {
+		platform *configloader.PlatformConfig
+		name     string
+		wantErr  string
+	}{
+		{
+			name: "no value for the platform",
+			platform: &configloader.PlatformConfig{
+				Architecture: "clusterArch",
+				Maps:         map[string]map[string]interface{}{"agentImage": {"amd64": "agent:1.4"}},
+			},
+			wantErr: `platform map "agentImage" has no value for linux/arm64`,
+		},
+		{
+			name:     "expression is not a string",
+			platform: &configloader.PlatformConfig{OS: "1 + 1"},
+			wantErr:  "must return a string",
+		},
+		{
+			name:     "expression fails",
+			platform: &configloader.PlatformConfig{OS: "missing.os"},
+			wantErr:  `platform os expression "missing.os" failed to evaluate`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execCtx := newPlatformExecutionContext(tt.platform)
+			err := resolvePlatform(context.Background(), execCtx, logger.NewTestLogger())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			_, ok := execCtx.GetParam(configloader.FieldPlatform)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestResolvePlatform_NotConfigured(t *testing.T) {
+	execCtx := newPlatformExecutionContext(nil)
+	require.NoError(t, resolvePlatform(context.Background(), execCtx, logger.NewTestLogger()))
+	_, ok := execCtx.GetParam(configloader.FieldPlatform)
+	assert.False(t, ok)
+}
@@ -3,13 +3,16 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"text/template/parse"
+	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 )
 
@@ -29,7 +32,9 @@ func newPostActionExecutor(config *ExecutorConfig) *PostActionExecutor {
 }
 
 // ExecuteAll executes all post-processing actions
-// First builds payloads from post.payloads, then executes post.postActions
+// First builds payloads from post.payloads, then executes the post_actions-phase actions of
+// post.postActions, then its reporting-phase actions. The reporting actions always run,
+// even when building the payloads or an earlier action failed.
 func (pae *PostActionExecutor) ExecuteAll(
 	ctx context.Context,
 	postConfig *configloader.PostConfig,
@@ -41,45 +46,81 @@ func (pae *PostActionExecutor) ExecuteAll(
 
 	// Step 1: Build post payloads (like clusterStatusPayload)
 	var skippedPayloads map[string]bool
+	var err error
 	if len(postConfig.Payloads) > 0 {
 		pae.log.Infof(ctx, "Building %d post payloads", len(postConfig.Payloads))
-		var err error
-		skippedPayloads, err = pae.buildPostPayloads(ctx, postConfig.Payloads, execCtx)
+		if panicErr := recoverPhase(func() {
+			skippedPayloads, err = pae.buildPostPayloads(ctx, postConfig.Payloads, execCtx)
+		}); panicErr != nil {
+			err = panicErr
+		}
 		if err != nil {
 			errCtx := logger.WithErrorField(ctx, err)
 			pae.log.Errorf(errCtx, "Failed to build post payloads")
-			execCtx.Adapter.ExecutionError = &ExecutionError{
-				Phase:   string(PhasePostActions),
-				Step:    "build_payloads",
-				Message: err.Error(),
-			}
-			return []PostActionResult{}, NewExecutorError(
-				PhasePostActions, "build_payloads", "failed to build post payloads", err)
-		}
-		for _, payload := range postConfig.Payloads {
-			if !skippedPayloads[payload.Name] {
-				pae.log.Debugf(ctx, "payload[%s] built successfully", payload.Name)
+			execCtx.SetExecutionError(PhasePostActions, "build_payloads", err.Error())
+			err = NewExecutorError(PhasePostActions, "build_payloads", "failed to build post payloads", err)
+		} else {
+			for _, payload := range postConfig.Payloads {
+				if !skippedPayloads[payload.Name] {
+					pae.log.Debugf(ctx, "payload[%s] built successfully", payload.Name)
+				}
 			}
 		}
 	}
 
-	// Step 2: Execute post actions (sequential - stop on first failure)
+	// Step 2: Execute post_actions-phase actions (sequential - stop on the first failure, unless on_error is continue)
 	results := make([]PostActionResult, 0, len(postConfig.PostActions))
-	for _, action := range postConfig.PostActions {
-		result, err := pae.executePostAction(ctx, action, execCtx, skippedPayloads)
+	if err == nil {
+		results, err = pae.executePhase(ctx, postConfig.PostActions, configloader.PostActionPhasePostActions,
+			execCtx, skippedPayloads, results)
+	}
+
+	// Step 3: Execute reporting-phase actions (sequential - always run, each failure is kept)
+	results, reportErr := pae.executePhase(ctx, postConfig.PostActions, configloader.PostActionPhaseReporting,
+		execCtx, skippedPayloads, results)
+	if reportErr != nil {
+		return results, errors.Join(err, reportErr)
+	}
+	return results, err
+}
+
+// executePhase executes the actions of phase in order and appends their results to
+// results. A post_actions-phase failure stops the remaining actions; a reporting-phase
+// failure does not, and the failures are returned joined.
+func (pae *PostActionExecutor) executePhase(
+	ctx context.Context,
+	actions []configloader.PostAction,
+	phase string,
+	execCtx *ExecutionContext,
+	skippedPayloads map[string]bool,
+	results []PostActionResult,
+) ([]PostActionResult, error) {
+	var errs []error
+	for _, action := range actions {
+		if action.EffectivePhase() != phase {
+			continue
+		}
+		stepCtx, span := startStepSpan(ctx, metrics.StepTypePostAction, action.Name)
+		start := time.Now()
+		result, err := pae.runPostAction(stepCtx, action, execCtx, skippedPayloads)
+		result.Duration = time.Since(start)
 		results = append(results, result)
+		endStepSpan(span, result.stepStatus(), result.SkipReason, err)
 
 		if err != nil {
+			if continueOnError(ctx, pae.log, execCtx, PhasePostActions, action.Name, action.OnError, err) {
+				continue
+			}
 			errCtx := logger.WithErrorField(ctx, err)
 			pae.log.Errorf(errCtx, "PostAction[%s] processed: FAILED", action.Name)
 
 			// Set ExecutionError for failed post action
-			execCtx.Adapter.ExecutionError = &ExecutionError{
-				Phase:   string(PhasePostActions),
-				Step:    action.Name,
-				Message: err.Error(),
-			}
+			execCtx.SetExecutionError(PhasePostActions, action.Name, err.Error())
 
+			if phase == configloader.PostActionPhaseReporting {
+				errs = append(errs, err)
+				continue
+			}
 			// Stop execution - don't run remaining post actions
 			return results, err
 		}
@@ -89,8 +130,29 @@ func (pae *PostActionExecutor) ExecuteAll(
 			pae.log.Infof(ctx, "PostAction[%s] processed: SUCCESS - status=%s", action.Name, result.Status)
 		}
 	}
+	return results, errors.Join(errs...)
+}
 
-	return results, nil
+// runPostAction executes one post-action, recording a panic as its failure so that the
+// reporting phase still runs
+func (pae *PostActionExecutor) runPostAction(
+	ctx context.Context,
+	action configloader.PostAction,
+	execCtx *ExecutionContext,
+	skippedPayloads map[string]bool,
+) (PostActionResult, error) {
+	var result PostActionResult
+	var err error
+	if panicErr := recoverPhase(func() {
+		result, err = withRetry(ctx, pae.log, action.Retry, PhasePostActions, action.Name,
+			func() (PostActionResult, error) {
+				return pae.executePostAction(ctx, action, execCtx, skippedPayloads)
+			})
+	}); panicErr != nil {
+		result = PostActionResult{Name: action.Name, Status: StatusFailed, Error: panicErr}
+		err = panicErr
+	}
+	return result, err
 }
 
 // buildPostPayloads builds all post payloads and stores them in execCtx.Params.
@@ -140,7 +202,7 @@ func (pae *PostActionExecutor) buildPostPayloads(
 		}
 
 		// Build the payload
-		builtPayload, err := pae.buildPayload(ctx, buildDef, evaluator, execCtx.Params)
+		builtPayload, err := pae.buildPayload(ctx, buildDef, evaluator, execCtx.ParamsSnapshot())
 		if err != nil {
 			return nil, fmt.Errorf("failed to build payload '%s': %w", payload.Name, err)
 		}
@@ -152,7 +214,9 @@ func (pae *PostActionExecutor) buildPostPayloads(
 		}
 
 		// Store as JSON string in params for use in post action templates
-		execCtx.Params[payload.Name] = string(jsonBytes)
+		if err := execCtx.SetVariable(payload.Name, string(jsonBytes)); err != nil {
+			return nil, fmt.Errorf("failed to store payload '%s': %w", payload.Name, err)
+		}
 	}
 
 	return skippedPayloads, nil
@@ -3,8 +3,10 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
@@ -416,6 +418,129 @@ func TestPostActionExecutor_ExecuteAll(t *testing.T) {
 	}
 }
 
+// panicOnPutClient is a MockClient that panics on PUT requests
+type panicOnPutClient struct {
+	*hyperfleetapi.MockClient
+}
+
+func (c *panicOnPutClient) Put(
+	_ context.Context, _ string, _ []byte, _ ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	panic("boom")
+}
+
+func TestPostActionExecutor_ExecuteAll_Phases(t *testing.T) {
+	logAction := func(name, phase string) configloader.PostAction {
+		return configloader.PostAction{
+			ActionBase: configloader.ActionBase{Name: name, Log: &configloader.LogAction{Message: name}},
+			Phase:      phase,
+		}
+	}
+	callAction := func(name, method, phase string) configloader.PostAction {
+		return configloader.PostAction{
+			ActionBase: configloader.ActionBase{Name: name, APICall: &configloader.APICall{
+				Method: method,
+				URL:    "http://api.example.com/clusters/" + name,
+			}},
+			Phase: phase,
+		}
+	}
+	reporting := configloader.PostActionPhaseReporting
+
+	tests := []struct {
+		postConfig     *configloader.PostConfig
+		name           string
+		expectedError  string
+		expectedOrder  []string
+		expectedFailed int
+	}{
+		{
+			name: "reporting actions run after post actions",
+			postConfig: &configloader.PostConfig{PostActions: []configloader.PostAction{
+				logAction("report", reporting),
+				logAction("first", ""),
+				logAction("second", configloader.PostActionPhasePostActions),
+			}},
+			expectedOrder: []string{"first", "second", "report"},
+		},
+		{
+			name: "post action failure stops post actions but not reporting",
+			postConfig: &configloader.PostConfig{PostActions: []configloader.PostAction{
+				callAction("failing", http.MethodGet, ""),
+				logAction("skipped", ""),
+				logAction("report", reporting),
+			}},
+			expectedOrder:  []string{"failing", "report"},
+			expectedFailed: 1,
+			expectedError:  "connection refused",
+		},
+		{
+			name: "reporting failure does not stop other reporting actions",
+			postConfig: &configloader.PostConfig{PostActions: []configloader.PostAction{
+				callAction("report1", http.MethodGet, reporting),
+				callAction("report2", http.MethodGet, reporting),
+				logAction("report3", reporting),
+			}},
+			expectedOrder:  []string{"report1", "report2", "report3"},
+			expectedFailed: 2,
+			expectedError:  "connection refused",
+		},
+		{
+			name: "payload build failure still runs reporting actions",
+			postConfig: &configloader.PostConfig{
+				Payloads: []configloader.Payload{{Name: "broken"}},
+				PostActions: []configloader.PostAction{
+					logAction("first", ""),
+					logAction("report", reporting),
+				},
+			},
+			expectedOrder: []string{"report"},
+			expectedError: "failed to build post payloads",
+		},
+		{
+			name: "panicking post action still runs reporting actions",
+			postConfig: &configloader.PostConfig{PostActions: []configloader.PostAction{
+				callAction("panicking", http.MethodPut, ""),
+				logAction("report", reporting),
+			}},
+			expectedOrder:  []string{"panicking", "report"},
+			expectedFailed: 1,
+			expectedError:  "panic: boom",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := hyperfleetapi.NewMockClient()
+			mockClient.GetError = errors.New("connection refused")
+			pae := newPostActionExecutor(&ExecutorConfig{
+				APIClient: &panicOnPutClient{MockClient: mockClient},
+				Logger:    logger.NewTestLogger(),
+			})
+			execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+
+			results, err := pae.ExecuteAll(context.Background(), tt.postConfig, execCtx)
+
+			if tt.expectedError == "" {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			}
+			order := make([]string, 0, len(results))
+			failed := 0
+			for _, r := range results {
+				order = append(order, r.Name)
+				if r.Status == StatusFailed {
+					failed++
+				}
+			}
+			assert.Equal(t, tt.expectedOrder, order)
+			assert.Equal(t, tt.expectedFailed, failed)
+		})
+	}
+}
+
 func TestExecuteAPICall(t *testing.T) {
 	tests := []struct {
 		mockError    error
@@ -580,7 +705,7 @@ func TestExecuteAPICall(t *testing.T) {
 			apiCall: &configloader.APICall{
 				Method:  "GET",
 				URL:     "http://api.example.com/slow",
-				Timeout: "30s",
+				Timeout: configloader.Duration(30 * time.Second),
 			},
 			params: map[string]interface{}{},
 			mockResponse: &hyperfleetapi.Response{
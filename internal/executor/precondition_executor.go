@@ -2,14 +2,16 @@ package executor
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 )
 
 // PreconditionExecutor evaluates preconditions
@@ -37,10 +39,21 @@ func (pe *PreconditionExecutor) ExecuteAll(
 	results := make([]PreconditionResult, 0, len(preconditions))
 
 	for _, precond := range preconditions {
-		result, err := pe.executePrecondition(ctx, precond, execCtx)
+		stepCtx, span := startStepSpan(ctx, metrics.StepTypePrecondition, precond.Name)
+		start := time.Now()
+		result, err := pe.executePrecondition(stepCtx, precond, execCtx)
+		result.Duration = time.Since(start)
 		results = append(results, result)
+		notMetReason := ""
+		if err == nil && !result.Matched {
+			notMetReason = formatConditionDetails(result)
+		}
+		endStepSpan(span, result.stepStatus(), notMetReason, err)
 
 		if err != nil {
+			if continueOnError(ctx, pe.log, execCtx, PhasePreconditions, precond.Name, precond.OnError, err) {
+				continue
+			}
 			// Execution error (API call failed, parse error, etc.)
 			errCtx := logger.WithErrorField(ctx, err)
 			pe.log.Errorf(errCtx, "Precondition[%s] evaluated: FAILED", precond.Name)
@@ -90,44 +103,57 @@ func (pe *PreconditionExecutor) executePrecondition(
 		ExecuteLogAction(ctx, precond.Log, execCtx, pe.log)
 	}
 
-	// Step 2: Make API call if configured
-	if precond.APICall != nil {
-		apiResult, err := pe.executeAPICall(ctx, precond.APICall, execCtx)
+	// Step 2: Make API call if configured, once per item with for_each
+	if precond.ForEach != "" {
+		if err := pe.executeForEach(ctx, precond, execCtx, &result); err != nil {
+			return result, err
+		}
+	} else if precond.APICall != nil {
+		resp, err := pe.executeAPICall(ctx, precond.APICall, execCtx)
 		if err != nil {
 			result.Status = StatusFailed
 			result.Error = err
 
 			// Set ExecutionError for API call failure
-			execCtx.Adapter.ExecutionError = &ExecutionError{
-				Phase:   string(PhasePreconditions),
-				Step:    precond.Name,
-				Message: err.Error(),
-			}
+			execCtx.SetExecutionError(PhasePreconditions, precond.Name, err.Error())
 
 			return result, NewExecutorError(PhasePreconditions, precond.Name, "API call failed", err)
 		}
 		result.APICallMade = true
-		result.APIResponse = apiResult
+		// Nil when the response was streamed into response_fields
+		result.APIResponse = resp.Body
 
 		// Parse response as JSON
-		var responseData map[string]interface{}
-		if err := json.Unmarshal(apiResult, &responseData); err != nil {
+		responseData, err := ParseAPIResponse(precond.APICall, resp, execCtx.Config.StrictJSONNumbers())
+		if err != nil {
 			result.Status = StatusFailed
 			result.Error = fmt.Errorf("failed to parse API response as JSON: %w", err)
 
 			// Set ExecutionError for parse failure
-			execCtx.Adapter.ExecutionError = &ExecutionError{
-				Phase:   string(PhasePreconditions),
-				Step:    precond.Name,
-				Message: err.Error(),
-			}
+			execCtx.SetExecutionError(PhasePreconditions, precond.Name, err.Error())
 
 			return result, NewExecutorError(PhasePreconditions, precond.Name, "failed to parse API response", err)
 		}
 
 		// Store full response under precondition name for condition digging
 		// e.g., conditions can access "check-cluster.status.conditions"
-		execCtx.Params[precond.Name] = responseData
+		// A transform replaces it with its result; captures still read the full response.
+		if precond.APICall.Transform == "" {
+			if err := execCtx.SetVariable(precond.Name, responseData); err != nil {
+				return pe.failVariable(precond.Name, execCtx, &result, err)
+			}
+		} else {
+			transformed, err := transformStepResult(ctx, pe.log, precond.Name, precond.APICall.Transform, responseData)
+			if err != nil {
+				result.Status = StatusFailed
+				result.Error = err
+				execCtx.SetExecutionError(PhasePreconditions, precond.Name, err.Error())
+				return result, NewExecutorError(PhasePreconditions, precond.Name, "failed to transform API response", err)
+			}
+			if err := execCtx.SetVariable(precond.Name, transformed); err != nil {
+				return pe.failVariable(precond.Name, execCtx, &result, err)
+			}
+		}
 
 		// Capture fields from response
 		if len(precond.Capture) > 0 {
@@ -146,6 +172,8 @@ func (pe *PreconditionExecutor) executePrecondition(
 			captureEvaluator, evalErr := criteria.NewEvaluator(ctx, captureCtx, pe.log)
 			if evalErr != nil {
 				pe.log.Warnf(ctx, "Failed to create capture evaluator: %v", evalErr)
+				execCtx.AddWarning(PhasePreconditions, precond.Name,
+					fmt.Sprintf("failed to create capture evaluator, no fields captured: %v", evalErr))
 			} else {
 				for _, capture := range precond.Capture {
 					extractResult, err := captureEvaluator.ExtractValue(capture.Field, capture.Expression)
@@ -165,11 +193,25 @@ func (pe *PreconditionExecutor) executePrecondition(
 							value = capture.Default
 						} else {
 							pe.log.Warnf(ctx, "Failed to capture '%s': %v", capture.Name, extractResult.Error)
+							execCtx.AddWarning(PhasePreconditions, precond.Name,
+								fmt.Sprintf("failed to capture '%s': %v", capture.Name, extractResult.Error))
 						}
 					}
 
+					if capture.Transform != "" && value != nil {
+						transformed, err := transformCapturedValue(capture.Transform, value, execCtx.ParamsSnapshot())
+						if err != nil {
+							pe.log.Warnf(ctx, "Failed to transform capture '%s': %v", capture.Name, err)
+							execCtx.AddWarning(PhasePreconditions, precond.Name,
+								fmt.Sprintf("failed to transform capture '%s': %v", capture.Name, err))
+						}
+						value = transformed
+					}
+
 					result.CapturedFields[capture.Name] = value
-					execCtx.Params[capture.Name] = value
+					if err := execCtx.SetVariable(capture.Name, value); err != nil {
+						return pe.failVariable(precond.Name, execCtx, &result, err)
+					}
 					pe.log.Debugf(ctx, "Captured %s = %v (from %s)", capture.Name, value, extractResult.Source)
 				}
 			}
@@ -245,12 +287,12 @@ func (pe *PreconditionExecutor) executePrecondition(
 	return result, nil
 }
 
-// executeAPICall executes an API call and returns the response body for field capture
+// executeAPICall executes an API call and returns the successful response for field capture
 func (pe *PreconditionExecutor) executeAPICall(
 	ctx context.Context,
 	apiCall *configloader.APICall,
 	execCtx *ExecutionContext,
-) ([]byte, error) {
+) (*hyperfleetapi.Response, error) {
 	resp, url, err := ExecuteAPICall(ctx, apiCall, execCtx, pe.apiClient, pe.log)
 
 	// Validate response - returns APIError with full metadata if validation fails
@@ -258,7 +300,119 @@ func (pe *PreconditionExecutor) executeAPICall(
 		return nil, validationErr
 	}
 
-	return resp.Body, nil
+	return resp, nil
+}
+
+// executeForEach makes the api_call of a for_each precondition once per item and stores
+// the list of the (transformed) responses under the precondition name. The first failed
+// call fails the precondition.
+func (pe *PreconditionExecutor) executeForEach(
+	ctx context.Context,
+	precond configloader.Precondition,
+	execCtx *ExecutionContext,
+	result *PreconditionResult,
+) error {
+	fail := func(reason string, err error) error {
+		result.Status = StatusFailed
+		result.Error = err
+		execCtx.SetExecutionError(PhasePreconditions, precond.Name, err.Error())
+		return NewExecutorError(PhasePreconditions, precond.Name, reason, err)
+	}
+
+	items, err := evaluateForEach(ctx, pe.log, execCtx, precond.ForEach)
+	if err != nil {
+		return fail("failed to evaluate for_each", err)
+	}
+	pe.log.Debugf(ctx, "Precondition[%s] for_each: %d items", precond.Name, len(items))
+	if err := addForEachItems(execCtx, precond.Name, len(items)); err != nil {
+		return fail("for_each exceeds limits", err)
+	}
+
+	responses := make([]interface{}, 0, len(items))
+	for i, value := range items {
+		itemCtx := withForEachItem(ctx, ForEachItem{Value: value, Index: i})
+		resp, err := pe.executeAPICall(itemCtx, precond.APICall, execCtx)
+		if err != nil {
+			return fail("API call failed", fmt.Errorf("item %d: %w", i, err))
+		}
+		responseData, err := ParseAPIResponse(precond.APICall, resp, execCtx.Config.StrictJSONNumbers())
+		if err != nil {
+			return fail("failed to parse API response", fmt.Errorf("item %d: %w", i, err))
+		}
+		var response interface{} = responseData
+		if precond.APICall.Transform != "" {
+			response, err = transformStepResult(itemCtx, pe.log, precond.Name, precond.APICall.Transform, responseData)
+			if err != nil {
+				return fail("failed to transform API response", fmt.Errorf("item %d: %w", i, err))
+			}
+		}
+		responses = append(responses, response)
+	}
+	result.APICallMade = len(items) > 0
+	if err := execCtx.SetVariable(precond.Name, responses); err != nil {
+		return fail("failed to store API responses", err)
+	}
+	return nil
+}
+
+// failVariable fails the precondition when a variable cannot be set
+func (pe *PreconditionExecutor) failVariable(
+	name string,
+	execCtx *ExecutionContext,
+	result *PreconditionResult,
+	err error,
+) (PreconditionResult, error) {
+	result.Status = StatusFailed
+	result.Error = err
+	execCtx.SetExecutionError(PhasePreconditions, name, err.Error())
+	return *result, NewExecutorError(PhasePreconditions, name, "failed to set variable", err)
+}
+
+// transformCapturedValue applies a capture transform to value. String values are trimmed
+// before toInt and toBool, so " TRUE " is true. A template is rendered with the params and
+// the value as .value. On error the value is nil, as a capture that failed.
+func transformCapturedValue(transform string, value interface{}, params map[string]interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok &&
+		(transform == configloader.CaptureTransformToInt || transform == configloader.CaptureTransformToBool) {
+		value = strings.TrimSpace(s)
+	}
+	switch transform {
+	case configloader.CaptureTransformLower, configloader.CaptureTransformTrim:
+		s, err := utils.ConvertToString(value)
+		if err != nil {
+			return nil, err
+		}
+		if transform == configloader.CaptureTransformLower {
+			return strings.ToLower(s), nil
+		}
+		return strings.TrimSpace(s), nil
+	case configloader.CaptureTransformToInt:
+		i, err := utils.ConvertToInt64(value)
+		if err != nil {
+			return nil, err
+		}
+		return i, nil
+	case configloader.CaptureTransformToBool:
+		b, err := utils.ConvertToBool(value)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	text, ok := strings.CutPrefix(transform, configloader.CaptureTransformTemplatePrefix)
+	if !ok {
+		return nil, fmt.Errorf("unknown transform %q", transform)
+	}
+	data := make(map[string]interface{}, len(params)+1)
+	for name, param := range params {
+		data[name] = param
+	}
+	data["value"] = value
+	rendered, err := utils.RenderTemplate(text, data)
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
 }
 
 // formatConditionDetails formats condition evaluation details for error messages
@@ -0,0 +1,141 @@
+package executor
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+)
+
+// Progress logging of long-running steps; variables so tests can shorten them
+var (
+	// stepProgressThreshold is how long a step runs before its progress is first logged
+	stepProgressThreshold = 10 * time.Second
+	// stepProgressInterval is the interval of the progress logs that follow
+	stepProgressInterval = 30 * time.Second
+)
+
+type progressReporterKey struct{}
+
+type stepProgressKey struct{}
+
+// progressReporter logs and records the progress of the steps of an execution
+type progressReporter struct {
+	log      logger.Logger
+	recorder *metrics.Recorder
+}
+
+// withProgressReporter makes the steps started with ctx report their progress to log and
+// recorder. recorder may be nil.
+func withProgressReporter(ctx context.Context, log logger.Logger, recorder *metrics.Recorder) context.Context {
+	return context.WithValue(ctx, progressReporterKey{}, &progressReporter{log: log, recorder: recorder})
+}
+
+// stepProgress tracks one running step: it counts for longest_running_step_seconds, and
+// once the step runs longer than stepProgressThreshold its elapsed time, operation and
+// target are logged every stepProgressInterval until it ends or its context is done.
+type stepProgress struct {
+	ctx       context.Context
+	log       logger.Logger
+	done      func()
+	stop      chan struct{}
+	finished  chan struct{}
+	start     time.Time
+	stepType  string
+	name      string
+	operation string
+	target    string
+	mu        sync.Mutex
+	stopOnce  sync.Once
+}
+
+// startStepProgress starts tracking a step if ctx has a progress reporter, and returns
+// the context to run the step with. The returned progress is nil otherwise.
+func startStepProgress(ctx context.Context, stepType, name string) (context.Context, *stepProgress) {
+	reporter, ok := ctx.Value(progressReporterKey{}).(*progressReporter)
+	if !ok {
+		return ctx, nil
+	}
+	p := &stepProgress{
+		ctx:      ctx,
+		log:      reporter.log,
+		done:     reporter.recorder.StepStarted(),
+		stop:     make(chan struct{}),
+		finished: make(chan struct{}),
+		start:    time.Now(),
+		stepType: stepType,
+		name:     name,
+	}
+	go p.run()
+	return context.WithValue(ctx, stepProgressKey{}, p), p
+}
+
+// setStepOperation records what the step running with ctx is doing, for its progress logs
+func setStepOperation(ctx context.Context, operation, target string) {
+	p, ok := ctx.Value(stepProgressKey{}).(*stepProgress)
+	if !ok {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.operation, p.target = operation, target
+}
+
+// end stops tracking the step; no progress is logged once it returns. It is nil-safe and
+// may be called more than once.
+func (p *stepProgress) end() {
+	if p == nil {
+		return
+	}
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		<-p.finished
+		p.done()
+	})
+}
+
+// run logs the progress of the step until it ends or its context is done
+func (p *stepProgress) run() {
+	defer close(p.finished)
+	timer := time.NewTimer(stepProgressThreshold)
+	defer timer.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-p.ctx.Done():
+			return
+		case <-timer.C:
+			p.logProgress()
+			timer.Reset(stepProgressInterval)
+		}
+	}
+}
+
+// logProgress logs that the step is still running
+func (p *stepProgress) logProgress() {
+	p.mu.Lock()
+	operation, target := p.operation, p.target
+	p.mu.Unlock()
+
+	elapsed := time.Since(p.start)
+	ctx := logger.WithLogFields(p.ctx, logger.LogFields{
+		"step_type":       p.stepType,
+		"step":            p.name,
+		"elapsed_seconds": int64(elapsed.Seconds()),
+		"operation":       operation,
+		"target":          target,
+	})
+	switch {
+	case operation == "":
+		p.log.Infof(ctx, "Step %s[%s] still running after %s", p.stepType, p.name, elapsed.Round(time.Second))
+	case target == "":
+		p.log.Infof(ctx, "Step %s[%s] still running after %s: %s",
+			p.stepType, p.name, elapsed.Round(time.Second), operation)
+	default:
+		p.log.Infof(ctx, "Step %s[%s] still running after %s: %s %s",
+			p.stepType, p.name, elapsed.Round(time.Second), operation, target)
+	}
+}
@@ -0,0 +1,85 @@
+package executor
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/maestroclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+)
+
+// shortenStepProgress makes progress logs start after threshold and repeat every interval
+func shortenStepProgress(t *testing.T, threshold, interval time.Duration) {
+	t.Helper()
+	prevThreshold, prevInterval := stepProgressThreshold, stepProgressInterval
+	stepProgressThreshold, stepProgressInterval = threshold, interval
+	t.Cleanup(func() { stepProgressThreshold, stepProgressInterval = prevThreshold, prevInterval })
+}
+
+func TestStepProgress(t *testing.T) {
+	shortenStepProgress(t, 20*time.Millisecond, 10*time.Millisecond)
+	log, capture := logger.NewCaptureLogger()
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("test-adapter", "v0.1.0", "test", registry)
+	ctx := withProgressReporter(context.Background(), log, recorder)
+
+	longest := func() float64 {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, f := range families {
+			if f.GetName() == "hyperfleet_adapter_longest_running_step_seconds" {
+				return f.GetMetric()[0].GetGauge().GetValue()
+			}
+		}
+		return 0
+	}
+
+	// A fast step logs nothing
+	_, fast := startStepProgress(ctx, metrics.StepTypeResource, "configmap")
+	fast.end()
+	fast.end()
+	assert.NotContains(t, capture.Messages(), "still running")
+
+	stepCtx, slow := startStepProgress(ctx, metrics.StepTypeResource, "clusterWork")
+	setStepOperation(stepCtx, "apply", "ManifestWork cluster-1-work on consumer cluster-1")
+	time.Sleep(50 * time.Millisecond)
+	assert.Greater(t, longest(), 0.0, "a running step should be reported as the longest-running")
+	slow.end()
+	assert.Zero(t, longest(), "an ended step should no longer be reported")
+
+	messages := capture.Messages()
+	assert.Contains(t, messages,
+		"Step resource[clusterWork] still running after 0s: apply ManifestWork cluster-1-work on consumer cluster-1")
+	assert.Contains(t, messages, "step=clusterWork")
+	assert.Contains(t, messages, "operation=apply")
+	assert.Greater(t, strings.Count(messages, "still running"), 1, "progress should be logged periodically")
+
+	// Progress stops with the context of the step
+	capture.Reset()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	_, canceled := startStepProgress(cancelCtx, metrics.StepTypeWait, "clusterReady")
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	canceled.end()
+	assert.NotContains(t, capture.Messages(), "still running")
+
+	// Without a reporter nothing is tracked
+	_, untracked := startStepProgress(context.Background(), metrics.StepTypeWait, "clusterReady")
+	assert.Nil(t, untracked)
+	untracked.end()
+}
+
+func TestStepTarget(t *testing.T) {
+	assert.Equal(t, "Deployment ns-1/web", stepTarget("Deployment", "ns-1", "web", nil))
+	assert.Equal(t, "Namespace ns-1", stepTarget("Namespace", "", "ns-1", nil))
+	assert.Equal(t, "ManifestWork work-1 on consumer cluster-1",
+		stepTarget("ManifestWork", "", "work-1", &maestroclient.TransportContext{ConsumerName: "cluster-1"}))
+	assert.Equal(t, "", stepTarget("", "", "", nil))
+}
@@ -0,0 +1,177 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// pruneReason is the OperationReason of the resources deleted by a prune step
+const pruneReason = "not applied in this execution (prune)"
+
+// appliedKey identifies a resource applied by the resources phase
+type appliedKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+// appliedResources returns the resources kept by the resources phase: everything it
+// created, updated or left unchanged, but not what it deleted.
+func appliedResources(results []ResourceResult) map[appliedKey]bool {
+	applied := make(map[appliedKey]bool, len(results))
+	for _, r := range results {
+		if r.Operation == manifest.OperationDelete || r.ResourceName == "" {
+			continue
+		}
+		applied[appliedKey{kind: r.Kind, namespace: r.Namespace, name: r.ResourceName}] = true
+	}
+	return applied
+}
+
+// failedResource returns the name of the first failed resource in results, or "" when
+// every resource succeeded or was skipped
+func failedResource(results []ResourceResult) string {
+	for _, r := range results {
+		if r.Status == StatusFailed {
+			return r.Name
+		}
+	}
+	return ""
+}
+
+// PruneAll runs the prune steps after the resources phase succeeded. Each step deletes
+// the resources matching its kind and label selector that are not in resourceResults.
+// Every step runs even if an earlier one fails; the failures are joined. The returned
+// results hold one entry per deleted resource, and one per failed step lookup.
+func (re *ResourceExecutor) PruneAll(
+	ctx context.Context,
+	steps []configloader.PruneStep,
+	execCtx *ExecutionContext,
+	resourceResults []ResourceResult,
+) ([]ResourceResult, error) {
+	applied := appliedResources(resourceResults)
+
+	var results []ResourceResult
+	var errs []error
+	for _, step := range steps {
+		stepCtx, span := startStepSpan(ctx, metrics.StepTypePrune, step.Name)
+		stepResults, err := re.pruneStep(stepCtx, step, execCtx, applied)
+		results = append(results, stepResults...)
+		status := metrics.StepStatusSuccess
+		if err != nil {
+			status = metrics.StepStatusFailed
+		}
+		span.SetAttributes(attribute.Int("step.pruned", len(stepResults)))
+		endStepSpan(span, status, "", err)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// pruneStep lists the candidates of one prune step and deletes those not in applied
+func (re *ResourceExecutor) pruneStep(
+	ctx context.Context,
+	step configloader.PruneStep,
+	execCtx *ExecutionContext,
+	applied map[appliedKey]bool,
+) ([]ResourceResult, error) {
+	gvk := schema.FromAPIVersionAndKind(step.APIVersion, step.Kind)
+	failed := func(reason string, err error) ([]ResourceResult, error) {
+		execCtx.RecordResourceError(step.Name, err.Error())
+		result := ResourceResult{
+			Name:      step.Name,
+			Kind:      gvk.Kind,
+			Status:    StatusFailed,
+			Operation: manifest.OperationDelete,
+			Error:     err,
+		}
+		return []ResourceResult{result}, NewExecutorError(PhaseResources, step.Name, reason, err)
+	}
+
+	params := execCtx.ParamsSnapshot()
+	namespace, err := utils.RenderTemplate(step.Namespace, params)
+	if err != nil {
+		return failed("failed to render prune namespace", err)
+	}
+	labelSelector, err := renderLabelSelector(&configloader.SelectorConfig{LabelSelector: step.LabelSelector}, params)
+	if err != nil {
+		return failed("failed to render prune label selector", err)
+	}
+
+	discovery := &manifest.DiscoveryConfig{Namespace: namespace, LabelSelector: labelSelector}
+	ctx = transportclient.WithTransportName(ctx, configloader.TransportClientKubernetes)
+	list, err := re.client.DiscoverResources(ctx, gvk, discovery, nil)
+	if err != nil {
+		return failed("failed to list resources to prune", err)
+	}
+
+	propagationPolicy := "Background"
+	if step.PropagationPolicy != "" {
+		propagationPolicy = step.PropagationPolicy
+	}
+	deleteOpts := &transportclient.DeleteOptions{PropagationPolicy: propagationPolicy}
+
+	var results []ResourceResult
+	var errs []error
+	for i := range list.Items {
+		obj := &list.Items[i]
+		key := appliedKey{kind: gvk.Kind, namespace: obj.GetNamespace(), name: obj.GetName()}
+		if applied[key] || obj.GetDeletionTimestamp() != nil {
+			continue
+		}
+
+		result := ResourceResult{
+			Name:            step.Name,
+			Kind:            gvk.Kind,
+			Namespace:       key.namespace,
+			ResourceName:    key.name,
+			Status:          StatusSuccess,
+			Operation:       manifest.OperationDelete,
+			OperationReason: pruneReason,
+			DiscoveredState: obj,
+		}
+
+		startTime := time.Now()
+		re.metrics.IncDeletionInProgress(gvk.Kind)
+		err := re.client.DeleteResource(ctx, gvk, key.namespace, key.name, deleteOpts, nil)
+		re.metrics.DecDeletionInProgress(gvk.Kind)
+		result.Duration = time.Since(startTime)
+		re.metrics.ObserveDeletionDuration(gvk.Kind, result.Duration)
+		execCtx.invalidateDiscovery(gvk, key.namespace, nil)
+
+		if err != nil {
+			result.Status = StatusFailed
+			result.Error = err
+			execCtx.RecordResourceError(step.Name, err.Error())
+			re.metrics.RecordDeletion(gvk.Kind, metrics.DeletionStatusError)
+			errCtx := logger.WithErrorField(logger.WithK8sResult(ctx, "FAILED"), err)
+			re.log.Errorf(errCtx, "Prune[%s] delete %s %s/%s: FAILED", step.Name, gvk.Kind, key.namespace, key.name)
+			errs = append(errs, NewExecutorError(PhaseResources, step.Name,
+				fmt.Sprintf("failed to prune %s %s/%s", gvk.Kind, key.namespace, key.name), err))
+		} else {
+			re.metrics.RecordDeletion(gvk.Kind, metrics.DeletionStatusSuccess)
+			re.log.Infof(logger.WithK8sResult(ctx, "SUCCESS"),
+				"Prune[%s] deleted %s %s/%s: propagationPolicy=%s",
+				step.Name, gvk.Kind, key.namespace, key.name, propagationPolicy)
+		}
+		results = append(results, result)
+	}
+
+	if len(results) == 0 {
+		re.log.Debugf(ctx, "Prune[%s]: no %s matching %q to prune", step.Name, gvk.Kind, labelSelector)
+	}
+	return results, errors.Join(errs...)
+}
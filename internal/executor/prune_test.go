@@ -0,0 +1,224 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func newPruneConfigMap(namespace, name string) unstructured.Unstructured {
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+			"labels":    map[string]interface{}{"hyperfleet.io/cluster-id": "cluster-1"},
+		},
+	}}
+}
+
+func newPruneStep() configloader.PruneStep {
+	return configloader.PruneStep{
+		Name:          "staleConfigMaps",
+		APIVersion:    "v1",
+		Kind:          "ConfigMap",
+		Namespace:     "{{ .namespace }}",
+		LabelSelector: map[string]string{"hyperfleet.io/cluster-id": "{{ .clusterId }}"},
+	}
+}
+
+func newPruneExecutionContext() *ExecutionContext {
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.SetParam("namespace", "default")
+	execCtx.SetParam("clusterId", "cluster-1")
+	return execCtx
+}
+
+func TestResourceExecutor_PruneAll(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	kept := newPruneConfigMap("default", "current")
+	stale := newPruneConfigMap("default", "stale")
+	mock.Resources["default/current"] = kept.DeepCopy()
+	mock.Resources["default/stale"] = stale.DeepCopy()
+	mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{kept, stale}}
+
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+	execCtx := newPruneExecutionContext()
+
+	applied := []ResourceResult{{
+		Name: "config", Kind: "ConfigMap", Namespace: "default", ResourceName: "current",
+		Status: StatusSuccess, Operation: manifest.OperationSkip,
+	}}
+	results, err := re.PruneAll(context.Background(), []configloader.PruneStep{newPruneStep()}, execCtx, applied)
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "staleConfigMaps", results[0].Name)
+	assert.Equal(t, "stale", results[0].ResourceName)
+	assert.Equal(t, manifest.OperationDelete, results[0].Operation)
+	assert.Equal(t, pruneReason, results[0].OperationReason)
+	assert.Contains(t, mock.Resources, "default/current", "applied resource is kept")
+	assert.NotContains(t, mock.Resources, "default/stale", "stale resource is pruned")
+}
+
+func TestResourceExecutor_PruneAll_SkipsTerminating(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	terminating := newPruneConfigMap("default", "terminating")
+	now := metav1.Now()
+	terminating.SetDeletionTimestamp(&now)
+	mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{terminating}}
+
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+	execCtx := newPruneExecutionContext()
+
+	results, err := re.PruneAll(context.Background(), []configloader.PruneStep{newPruneStep()}, execCtx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestResourceExecutor_PruneAll_Errors(t *testing.T) {
+	t.Run("list failure fails the step", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.DiscoverError = errors.New("forbidden")
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+		execCtx := newPruneExecutionContext()
+
+		results, err := re.PruneAll(context.Background(), []configloader.PruneStep{newPruneStep()}, execCtx, nil)
+		require.Error(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, StatusFailed, results[0].Status)
+		assert.Contains(t, execCtx.Adapter.ResourceErrors, "staleConfigMaps")
+	})
+
+	t.Run("delete failure keeps pruning", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+			newPruneConfigMap("default", "a"), newPruneConfigMap("default", "b"),
+		}}
+		mock.DeleteResourceError = errors.New("conflict")
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+		execCtx := newPruneExecutionContext()
+
+		results, err := re.PruneAll(context.Background(), []configloader.PruneStep{newPruneStep()}, execCtx, nil)
+		require.Error(t, err)
+		require.Len(t, results, 2, "every candidate is attempted")
+		assert.Equal(t, StatusFailed, results[0].Status)
+		assert.Equal(t, StatusFailed, results[1].Status)
+	})
+}
+
+func TestExecute_PruneRunsOnlyAfterResourcesSucceed(t *testing.T) {
+	newConfig := func() *configloader.Config {
+		return &configloader.Config{
+			Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+			Params: []configloader.Parameter{
+				{Name: "clusterId", Source: configloader.StringSource("event.id")},
+				{Name: "namespace", Source: configloader.StringSource("event.namespace")},
+			},
+			Resources: []configloader.Resource{{
+				Name: "config",
+				Manifest: map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata": map[string]interface{}{
+						"name":      "current",
+						"namespace": "{{ .namespace }}",
+					},
+				},
+				Discovery: &configloader.DiscoveryConfig{Namespace: "{{ .namespace }}", ByName: "current"},
+			}},
+			Prune: []configloader.PruneStep{newPruneStep()},
+		}
+	}
+	eventData := map[string]interface{}{"id": "cluster-1", "namespace": "default"}
+
+	t.Run("prunes after a successful apply", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		stale := newPruneConfigMap("default", "stale")
+		mock.Resources["default/stale"] = stale.DeepCopy()
+		mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+			newPruneConfigMap("default", "current"), stale,
+		}}
+		exec, err := NewBuilder().
+			WithConfig(newConfig()).
+			WithAPIClient(newMockAPIClient()).
+			WithTransportClient(mock).
+			WithLogger(logger.NewTestLogger()).
+			Build()
+		require.NoError(t, err)
+
+		result := exec.Execute(context.Background(), eventData)
+		require.Equal(t, StatusSuccess, result.Status, "errors: %v", result.Errors)
+		require.Len(t, result.ResourceResults, 2)
+		assert.Equal(t, "stale", result.ResourceResults[1].ResourceName)
+		assert.Contains(t, mock.Resources, "default/current")
+		assert.NotContains(t, mock.Resources, "default/stale")
+	})
+
+	t.Run("does not prune after a failed apply", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.ApplyResourceError = errors.New("apply failed")
+		stale := newPruneConfigMap("default", "stale")
+		mock.Resources["default/stale"] = stale.DeepCopy()
+		mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{stale}}
+		exec, err := NewBuilder().
+			WithConfig(newConfig()).
+			WithAPIClient(newMockAPIClient()).
+			WithTransportClient(mock).
+			WithLogger(logger.NewTestLogger()).
+			Build()
+		require.NoError(t, err)
+
+		result := exec.Execute(context.Background(), eventData)
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Contains(t, mock.Resources, "default/stale")
+	})
+
+	t.Run("does not prune after a failed apply continued by on_error", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.ApplyResourceError = errors.New("apply failed")
+		stale := newPruneConfigMap("default", "stale")
+		mock.Resources["default/stale"] = stale.DeepCopy()
+		mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{stale}}
+		config := newConfig()
+		config.Resources[0].OnError = configloader.OnErrorContinue
+		exec, err := NewBuilder().
+			WithConfig(config).
+			WithAPIClient(newMockAPIClient()).
+			WithTransportClient(mock).
+			WithLogger(logger.NewTestLogger()).
+			Build()
+		require.NoError(t, err)
+
+		result := exec.Execute(context.Background(), eventData)
+		require.Len(t, result.ResourceResults, 1, "no prune results")
+		assert.Equal(t, StatusFailed, result.ResourceResults[0].Status)
+		assert.Contains(t, mock.Resources, "default/stale")
+	})
+}
+
+func TestNewExecutor_RejectsPruneWithMaestro(t *testing.T) {
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		Clients: configloader.ClientsConfig{Maestro: &configloader.MaestroClientConfig{}},
+		Prune:   []configloader.PruneStep{newPruneStep()},
+	}
+	_, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(newMockAPIClient()).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	assert.ErrorContains(t, err, "prune steps require the kubernetes transport")
+}
@@ -5,47 +5,64 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/gitopsclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/maestroclient"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 )
 
+// ErrForeignResource is returned when discovery with require_all finds an object with the
+// expected name that lacks the expected labels, so it was probably not created by the adapter
+var ErrForeignResource = errors.New("resource exists but is not managed by this adapter")
+
 // ResourceExecutor creates and updates Kubernetes resources
 type ResourceExecutor struct {
-	client  transportclient.TransportClient
-	log     logger.Logger
-	metrics *metrics.Recorder
+	client     transportclient.TransportClient
+	apiClient  hyperfleetapi.Client
+	log        logger.Logger
+	metrics    *metrics.Recorder
+	processors []ManifestProcessor
 }
 
 // newResourceExecutor creates a new resource executor
 // NOTE: Caller (NewExecutor) is responsible for config validation
 func newResourceExecutor(config *ExecutorConfig) *ResourceExecutor {
 	return &ResourceExecutor{
-		client:  config.TransportClient,
-		log:     config.Logger,
-		metrics: config.MetricsRecorder,
+		client:     config.TransportClient,
+		apiClient:  config.APIClient,
+		log:        config.Logger,
+		metrics:    config.MetricsRecorder,
+		processors: config.ManifestProcessors,
 	}
 }
 
-// ExecuteAll creates/updates all resources in sequence
+// ExecuteAll creates/updates all resources in sequence, or as a dependency graph when
+// any resource is marked parallel or declares depends_on.
 // Returns results for each resource and updates the execution context
 func (re *ResourceExecutor) ExecuteAll(
 	ctx context.Context,
 	resources []configloader.Resource,
 	execCtx *ExecutionContext,
 ) ([]ResourceResult, error) {
-	if execCtx.Resources == nil {
-		execCtx.Resources = make(map[string]interface{})
+	resources, err := re.expandForEach(ctx, resources, execCtx)
+	if err != nil {
+		return nil, err
 	}
 
 	// Pre-discover all resources before evaluating any lifecycle.create.when or lifecycle.delete.when expression.
@@ -62,19 +79,26 @@ func (re *ResourceExecutor) ExecuteAll(
 		}
 	}
 
+	if hasParallelResources(resources) {
+		return re.executeGraph(ctx, resources, execCtx)
+	}
+
 	results := make([]ResourceResult, 0, len(resources))
 	var deleteErrs []error
 
 	for _, resource := range resources {
-		result, err := re.executeResource(ctx, resource, execCtx)
+		result, err := re.executeResourceWithRetry(ctx, resource, execCtx)
 		results = append(results, result)
 
 		if err != nil {
+			if continueOnError(ctx, re.log, execCtx, PhaseResources, resource.Name, resource.OnError, err) {
+				continue
+			}
 			// Delete operations: continue processing remaining resources so that
 			// all deletions are attempted even when one fails (JIRA HYPERFLEET-849:
-			// "continue with the rest of resources deletion").
+			// "continue with the rest of resources deletion"), unless on_error is abort.
 			// Apply operations: fail fast (existing behavior).
-			if result.Operation == manifest.OperationDelete {
+			if result.Operation == manifest.OperationDelete && !abortsOnError(execCtx, resource.OnError) {
 				deleteErrs = append(deleteErrs, err)
 				continue
 			}
@@ -85,6 +109,139 @@ func (re *ResourceExecutor) ExecuteAll(
 	return results, errors.Join(deleteErrs...)
 }
 
+// executeGraph runs each resource once all of its dependencies have finished, so
+// independent resources are applied concurrently. Failure handling matches the
+// sequential path: a failed delete, or a resource with on_error continue, does not
+// block other resources, while a failed apply stops new resources from starting and
+// waits for the running ones.
+// Results are returned in config order and only include resources that ran.
+func (re *ResourceExecutor) executeGraph(
+	ctx context.Context,
+	resources []configloader.Resource,
+	execCtx *ExecutionContext,
+) ([]ResourceResult, error) {
+	type outcome struct {
+		err    error
+		result ResourceResult
+		index  int
+	}
+
+	pending := make([]int, len(resources))
+	dependents := make([][]int, len(resources))
+	for i := range resources {
+		deps := configloader.Dependencies(resources, i)
+		pending[i] = len(deps)
+		for _, d := range deps {
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	done := make(chan outcome)
+	ran := make([]bool, len(resources))
+	results := make([]ResourceResult, len(resources))
+	running := 0
+	start := func(i int) {
+		ran[i] = true
+		running++
+		re.log.Debugf(ctx, "Resource[%s] dependencies satisfied, starting", resources[i].Name)
+		go func() {
+			// recoverPhase in Execute only covers its own goroutine, so a panic in a
+			// parallel resource is recovered here and fails that resource
+			var result ResourceResult
+			var err error
+			if panicErr := recoverPhase(func() {
+				result, err = re.executeResourceWithRetry(ctx, resources[i], execCtx)
+			}); panicErr != nil {
+				result = ResourceResult{Name: resources[i].Name, Status: StatusFailed, Error: panicErr}
+				execCtx.RecordResourceError(resources[i].Name, panicErr.Error())
+				err = NewExecutorError(PhaseResources, resources[i].Name, "resource execution panicked", panicErr)
+			}
+			done <- outcome{index: i, result: result, err: err}
+		}()
+	}
+	for i := range resources {
+		if pending[i] == 0 {
+			start(i)
+		}
+	}
+
+	var deleteErrs []error
+	var applyErr error
+	for running > 0 {
+		o := <-done
+		running--
+		results[o.index] = o.result
+		resource := resources[o.index]
+		switch {
+		case o.err == nil:
+		case continueOnError(ctx, re.log, execCtx, PhaseResources, resource.Name, resource.OnError, o.err):
+		case o.result.Operation == manifest.OperationDelete && !abortsOnError(execCtx, resource.OnError):
+			deleteErrs = append(deleteErrs, o.err)
+		default:
+			if applyErr == nil {
+				applyErr = o.err
+			}
+			continue
+		}
+		if applyErr != nil {
+			continue
+		}
+		for _, d := range dependents[o.index] {
+			pending[d]--
+			if pending[d] == 0 {
+				start(d)
+			}
+		}
+	}
+
+	executed := make([]ResourceResult, 0, len(resources))
+	for i, result := range results {
+		if ran[i] {
+			executed = append(executed, result)
+		}
+	}
+	return executed, errors.Join(append(deleteErrs, applyErr)...)
+}
+
+// executeResourceWithRetry runs executeResource under the resource's retry policy.
+// Errors recorded in the execution context by failed attempts are cleared when a
+// later attempt succeeds.
+func (re *ResourceExecutor) executeResourceWithRetry(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+) (ResourceResult, error) {
+	ctx = forEachContext(ctx, execCtx, resource)
+	ctx, span := startStepSpan(ctx, metrics.StepTypeResource, resource.Name)
+	attempts := 0
+	start := time.Now()
+	result, err := withRetry(ctx, re.log, resource.Retry, PhaseResources, resource.Name,
+		func() (ResourceResult, error) {
+			attempts++
+			result, err := re.executeResource(ctx, resource, execCtx)
+			if err == nil && resource.Transform != "" {
+				err = re.transformResource(ctx, resource, execCtx, &result)
+			}
+			return result, err
+		})
+	result.Duration = time.Since(start)
+	span.SetAttributes(
+		attribute.String("step.operation", string(result.Operation)),
+		attribute.Int("step.attempts", attempts),
+	)
+	if len(result.Changes) > 0 {
+		span.SetAttributes(attribute.StringSlice("step.changed_fields", changedPaths(result.Changes)))
+	}
+	if len(result.Drift) > 0 {
+		span.SetAttributes(attribute.StringSlice("step.drifted_fields", changedPaths(result.Drift)))
+	}
+	endStepSpan(span, result.stepStatus(), "", err)
+	if err == nil && attempts > 1 {
+		execCtx.ClearStepError(PhaseResources, resource.Name)
+	}
+	return result, err
+}
+
 // executeResource creates or updates a single resource via the transport client.
 // For k8s transport: renders manifest template → marshals to JSON → calls ApplyResource(bytes)
 // For maestro transport: renders manifestWork template → marshals to JSON → calls ApplyResource(bytes)
@@ -98,6 +255,7 @@ func (re *ResourceExecutor) executeResource(
 		Status: StatusSuccess,
 	}
 
+	ctx = transportclient.WithTransportName(ctx, resource.GetTransportClient())
 	transportClient := re.client
 	if transportClient == nil {
 		result.Status = StatusFailed
@@ -105,19 +263,14 @@ func (re *ResourceExecutor) executeResource(
 		return result, NewExecutorError(PhaseResources, resource.Name, "transport client not configured", result.Error)
 	}
 
-	// Step 1: Build transport context (nil for k8s, *maestroclient.TransportContext for maestro).
+	// Step 1: Build transport context (nil for k8s, *maestroclient.TransportContext for maestro,
+	// *gitopsclient.TransportContext for gitops).
 	// Done first so it is available for both the lifecycle delete path and the apply path.
-	var transportTarget transportclient.TransportContext
-	if resource.IsMaestroTransport() && resource.Transport.Maestro != nil {
-		targetCluster, tplErr := utils.RenderTemplate(resource.Transport.Maestro.TargetCluster, execCtx.Params)
-		if tplErr != nil {
-			result.Status = StatusFailed
-			result.Error = tplErr
-			return result, NewExecutorError(PhaseResources, resource.Name, "failed to render targetCluster template", tplErr)
-		}
-		transportTarget = &maestroclient.TransportContext{
-			ConsumerName: targetCluster,
-		}
+	transportTarget, tplErr := transportTargetFor(resource, stepParams(ctx, execCtx))
+	if tplErr != nil {
+		result.Status = StatusFailed
+		result.Error = tplErr
+		return result, NewExecutorError(PhaseResources, resource.Name, "failed to render transport template", tplErr)
 	}
 
 	// Step 1.5: Check lifecycle.create — if the resource doesn't exist yet AND the when-expression
@@ -125,7 +278,8 @@ func (re *ResourceExecutor) executeResource(
 	// pre-discovery), ignore the when condition and apply normally (update flow).
 	if resource.Lifecycle != nil && resource.Lifecycle.Create != nil {
 		// Check if resource already exists in context (from pre-discovery)
-		resourceFound := execCtx.Resources[resource.Name] != nil
+		existing, _ := execCtx.GetResource(resource.Name)
+		resourceFound := existing != nil
 
 		if !resourceFound {
 			// Resource doesn't exist yet — evaluate the when condition. Validator guarantees
@@ -153,10 +307,12 @@ func (re *ResourceExecutor) executeResource(
 				// Surface the skip in adapter metadata so downstream post-action when-gates
 				// (e.g. "adapter.?resourcesSkipped.orValue(false)") can observe it. First
 				// reason wins, mirroring recordResourceError's ExecutionError convention.
-				execCtx.Adapter.ResourcesSkipped = true
-				if execCtx.Adapter.SkipReason == "" {
-					execCtx.Adapter.SkipReason = fmt.Sprintf("%s: %s", resource.Name, result.OperationReason)
-				}
+				execCtx.MarkResourcesSkipped(fmt.Sprintf("%s: %s", resource.Name, result.OperationReason), false)
+				execCtx.RecordResourceSkip(ResourceSkip{
+					Resource: resource.Name,
+					Reason:   metrics.SkipReasonWhenFalse,
+					Message:  result.OperationReason,
+				})
 
 				re.log.Infof(ctx, "Resource[%s] skipped: create.when condition is false", resource.Name)
 				return result, nil
@@ -189,13 +345,35 @@ func (re *ResourceExecutor) executeResource(
 
 	// Step 3: Render the manifest/manifestWork to bytes
 	re.log.Debugf(ctx, "Rendering manifest template for resource %s", resource.Name)
-	renderedBytes, err := re.renderToBytes(resource, execCtx)
+	renderedBytes, extraDocs, err := re.renderDocuments(ctx, resource, execCtx)
 	if err != nil {
 		result.Status = StatusFailed
 		result.Error = err
 		return result, NewExecutorError(PhaseResources, resource.Name, "failed to render manifest", err)
 	}
 
+	// Step 3.5: Add the adapter-managed finalizer so the object outlives its teardown
+	if finalizer := managedFinalizer(resource); finalizer != "" {
+		renderedBytes, err = addFinalizer(renderedBytes, finalizer)
+		if err != nil {
+			result.Status = StatusFailed
+			result.Error = err
+			return result, NewExecutorError(PhaseResources, resource.Name, "failed to add finalizer", err)
+		}
+	}
+
+	// Step 3.6: Run the manifest processors, which may modify or veto the documents
+	if len(re.processors) > 0 {
+		docs, procErr := re.processManifests(ctx, resource, execCtx, append([][]byte{renderedBytes}, extraDocs...))
+		if procErr != nil {
+			result.Status = StatusFailed
+			result.Error = procErr
+			re.recordResourceError(execCtx, resource, procErr)
+			return result, NewExecutorError(PhaseResources, resource.Name, "failed to process manifest", procErr)
+		}
+		renderedBytes, extraDocs = docs[0], docs[1:]
+	}
+
 	// Step 4: Extract resource identity from rendered manifest for result reporting
 	var obj unstructured.Unstructured
 	if unmarshalErr := json.Unmarshal(renderedBytes, &obj.Object); unmarshalErr == nil {
@@ -204,31 +382,118 @@ func (re *ResourceExecutor) executeResource(
 		result.ResourceName = obj.GetName()
 	}
 
+	// Step 4.5: Reject namespace/scope mismatches before any API call is attempted
+	if scopeErr := validateRenderedScope(resource, renderedBytes, &obj); scopeErr != nil {
+		result.Status = StatusFailed
+		result.Error = scopeErr
+		re.recordResourceError(execCtx, resource, scopeErr)
+		return result, NewExecutorError(PhaseResources, resource.Name, "invalid manifest namespace scope", scopeErr)
+	}
+	for i, doc := range extraDocs {
+		var extraObj unstructured.Unstructured
+		scopeErr := json.Unmarshal(doc, &extraObj.Object)
+		if scopeErr == nil {
+			scopeErr = manifest.ValidateNamespaceScope(&extraObj)
+		}
+		if scopeErr != nil {
+			scopeErr = fmt.Errorf("manifest document %d: %w", i+1, scopeErr)
+			result.Status = StatusFailed
+			result.Error = scopeErr
+			re.recordResourceError(execCtx, resource, scopeErr)
+			return result, NewExecutorError(PhaseResources, resource.Name, "invalid manifest namespace scope", scopeErr)
+		}
+	}
+
+	// Step 4.6: With require_all discovery, refuse to write over a same-named object that
+	// lacks the discovery labels. Other lookup errors are left to post-apply discovery.
+	if resource.Discovery != nil && resource.Discovery.RequireAll {
+		if _, ownErr := re.discoverResource(ctx, resource, execCtx, transportTarget); errors.Is(ownErr, ErrForeignResource) {
+			result.Status = StatusFailed
+			result.Error = ownErr
+			re.recordResourceError(execCtx, resource, ownErr)
+			return result, NewExecutorError(PhaseResources, resource.Name, "refusing to apply resource", ownErr)
+		}
+	}
+
+	// Step 4.7: Create the missing namespaces of the manifests when the resource ensures them
+	if ensuresNamespace(&resource, execCtx) {
+		docs := append([][]byte{renderedBytes}, extraDocs...)
+		if nsErr := re.ensureNamespaces(ctx, resource, execCtx, docs...); nsErr != nil {
+			result.Status = StatusFailed
+			result.Error = nsErr
+			re.recordResourceError(execCtx, resource, nsErr)
+			return result, NewExecutorError(PhaseResources, resource.Name, "failed to ensure namespace", nsErr)
+		}
+	}
+
 	// Step 5: Prepare apply options
 	var applyOpts *transportclient.ApplyOptions
-	if resource.RecreateOnChange {
-		applyOpts = &transportclient.ApplyOptions{RecreateOnChange: true}
+	driftPolicy := resource.GetDriftPolicy()
+	if resource.RecreateOnChange || resource.UsesServerSideApply() || driftPolicy != configloader.DriftPolicyIgnore {
+		applyOpts = &transportclient.ApplyOptions{
+			RecreateOnChange: resource.RecreateOnChange,
+			ServerSideApply:  resource.UsesServerSideApply(),
+			FieldManager:     resource.FieldManager,
+			DetectDrift:      driftPolicy != configloader.DriftPolicyIgnore,
+			ReconcileDrift:   driftPolicy == configloader.DriftPolicyReconcile,
+		}
 	}
 
-	// Step 6: Call transport client ApplyResource with rendered bytes
-	applyResult, err := transportClient.ApplyResource(ctx, renderedBytes, applyOpts, transportTarget)
+	// Step 6: Call transport client ApplyResource with rendered bytes. Cached lists of this
+	// kind are stale afterwards, even if the apply failed part way.
+	// A split ManifestWork is applied as one work per tier, which may defer the last tiers.
+	setStepOperation(ctx, "apply", stepTarget(obj.GetKind(), obj.GetNamespace(), obj.GetName(), transportTarget))
+	var applyResult *transportclient.ApplyResult
+	deferred := false
+	if resource.SplitsManifestWork() {
+		var splitResult *splitApplyResult
+		splitResult, err = re.applySplitWork(ctx, resource, renderedBytes, applyOpts, transportTarget)
+		if err == nil {
+			applyResult, deferred = splitResult.ApplyResult, splitResult.Deferred
+		}
+	} else {
+		applyResult, err = transportClient.ApplyResource(ctx, renderedBytes, applyOpts, transportTarget)
+	}
+	execCtx.invalidateDiscovery(obj.GroupVersionKind(), obj.GetNamespace(), transportTarget)
 	if err != nil {
 		result.Status = StatusFailed
 		result.Error = err
-		execCtx.Adapter.ExecutionError = &ExecutionError{
-			Phase:   string(PhaseResources),
-			Step:    resource.Name,
-			Message: err.Error(),
-		}
+		execCtx.SetExecutionError(PhaseResources, resource.Name, err.Error())
 		errCtx := logger.WithK8sResult(ctx, "FAILED")
 		errCtx = logger.WithErrorField(errCtx, err)
 		re.log.Errorf(errCtx, "Resource[%s] processed: FAILED", resource.Name)
 		return result, NewExecutorError(PhaseResources, resource.Name, "failed to apply resource", err)
 	}
 
+	// Step 6.5: Apply the other documents of a multi-document manifest, in order
+	if err = re.applyExtraDocuments(ctx, resource, execCtx, extraDocs, applyOpts); err != nil {
+		result.Status = StatusFailed
+		result.Error = err
+		execCtx.SetExecutionError(PhaseResources, resource.Name, err.Error())
+		errCtx := logger.WithK8sResult(ctx, "FAILED")
+		errCtx = logger.WithErrorField(errCtx, err)
+		re.log.Errorf(errCtx, "Resource[%s] processed: FAILED", resource.Name)
+		return result, NewExecutorError(PhaseResources, resource.Name, "failed to apply manifest document", err)
+	}
+
 	// Step 7: Extract result
 	result.Operation = applyResult.Operation
 	result.OperationReason = applyResult.Reason
+	result.Changes = applyResult.Changes
+	result.Drift = applyResult.Drift
+	if result.Operation == manifest.OperationSkip {
+		execCtx.RecordResourceSkip(ResourceSkip{
+			Resource:   resource.Name,
+			Reason:     metrics.SkipReasonGenerationUnchanged,
+			Message:    result.OperationReason,
+			Generation: manifest.GetGenerationFromUnstructured(&obj),
+		})
+	}
+	if len(result.Drift) > 0 {
+		execCtx.RecordResourceDrift(resource.Name, changedPaths(result.Drift))
+		re.log.Warnf(ctx, "Resource[%s] drifted from its manifest (drift_policy=%s): %s",
+			resource.Name, driftPolicy, strings.Join(changedPaths(result.Drift), ", "))
+	}
 
 	successCtx := logger.WithK8sResult(ctx, "SUCCESS")
 	re.log.Infof(successCtx, "Resource[%s] processed: operation=%s reason=%s",
@@ -237,14 +502,14 @@ func (re *ResourceExecutor) executeResource(
 	// Step 7: Post-apply discovery — find the applied resource and store in execCtx for CEL evaluation
 	if resource.Discovery != nil {
 		discovered, discoverErr := re.discoverResource(ctx, resource, execCtx, transportTarget)
+		if deferred && apierrors.IsNotFound(discoverErr) {
+			// The tier carrying the resource's ManifestWork has not been applied yet
+			discovered, discoverErr = nil, nil
+		}
 		if discoverErr != nil {
 			result.Status = StatusFailed
 			result.Error = discoverErr
-			execCtx.Adapter.ExecutionError = &ExecutionError{
-				Phase:   string(PhaseResources),
-				Step:    resource.Name,
-				Message: discoverErr.Error(),
-			}
+			execCtx.SetExecutionError(PhaseResources, resource.Name, discoverErr.Error())
 			errCtx := logger.WithK8sResult(ctx, "FAILED")
 			errCtx = logger.WithErrorField(errCtx, discoverErr)
 			re.log.Errorf(errCtx, "Resource[%s] discovery after apply failed: %v", resource.Name, discoverErr)
@@ -254,7 +519,7 @@ func (re *ResourceExecutor) executeResource(
 		if discovered != nil {
 			// Always store the discovered top-level resource by resource name.
 			// Nested discoveries are added as independent entries keyed by nested name.
-			execCtx.Resources[resource.Name] = discovered
+			execCtx.SetResource(resource.Name, discovered)
 			re.log.Debugf(ctx, "Resource[%s] discovered and stored in context", resource.Name)
 
 			// Step 8: Nested discoveries — find sub-resources within the discovered parent (e.g., ManifestWork)
@@ -265,30 +530,27 @@ func (re *ResourceExecutor) executeResource(
 						re.log.Warnf(ctx,
 							"Nested discovery %q has the same name as parent resource; skipping to avoid overwriting parent",
 							nestedName)
+						execCtx.AddWarning(PhaseResources, resource.Name, fmt.Sprintf(
+							"nested discovery %q has the same name as the parent resource and was skipped", nestedName))
 						continue
 					}
 					if nestedObj == nil {
 						continue
 					}
-					if _, exists := execCtx.Resources[nestedName]; exists {
+					if !execCtx.SetResourceIfAbsent(nestedName, nestedObj) {
 						collisionErr := fmt.Errorf(
 							"nested discovery key collision: %q already exists in context",
 							nestedName,
 						)
 						result.Status = StatusFailed
 						result.Error = collisionErr
-						execCtx.Adapter.ExecutionError = &ExecutionError{
-							Phase:   string(PhaseResources),
-							Step:    resource.Name,
-							Message: collisionErr.Error(),
-						}
+						execCtx.SetExecutionError(PhaseResources, resource.Name, collisionErr.Error())
 						return result, NewExecutorError(
 							PhaseResources, resource.Name,
 							"duplicate resource context key",
 							collisionErr,
 						)
 					}
-					execCtx.Resources[nestedName] = nestedObj
 				}
 				re.log.Debugf(ctx, "Resource[%s] discovered with %d nested resources added to context",
 					resource.Name, len(nestedResults))
@@ -303,20 +565,221 @@ func (re *ResourceExecutor) executeResource(
 // The manifest holds either a K8s resource or a ManifestWork depending on transport type.
 // All manifests are rendered as Go templates: map manifests are serialized to YAML first,
 // then rendered and parsed like string manifests.
+// Only the first document of a multi-document kubernetes manifest is returned; see
+// renderDocuments.
 func (re *ResourceExecutor) renderToBytes(
+	ctx context.Context,
 	resource configloader.Resource,
 	execCtx *ExecutionContext,
 ) ([]byte, error) {
-	if resource.Manifest == nil {
-		return nil, fmt.Errorf("no manifest specified for resource %s", resource.Name)
+	rendered, _, err := re.renderDocuments(ctx, resource, execCtx)
+	return rendered, err
+}
+
+// renderDocuments renders the resource's manifest like renderToBytes. Of a manifest with
+// several YAML documents, or of the manifests of a Helm chart, the first is the
+// resource's own object. The others are:
+//   - maestro transport: appended to the workload manifests of the ManifestWork, so extra
+//     is always empty
+//   - kubernetes transport: returned in extra, to be applied with the resource
+func (re *ResourceExecutor) renderDocuments(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+) (rendered []byte, extra [][]byte, err error) {
+	params := stepParams(ctx, execCtx)
+	var docs [][]byte
+	switch {
+	case resource.Helm != nil:
+		docs, err = renderHelmChart(resource, params)
+	case resource.Manifest == nil:
+		return nil, nil, fmt.Errorf("no manifest specified for resource %s", resource.Name)
+	default:
+		var manifestStr string
+		manifestStr, err = manifest.ToYAMLString(resource.Manifest)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert manifest to string: %w", err)
+		}
+		docs, err = manifest.RenderStringManifests(manifestStr, params)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if execCtx.ConfigFingerprint != "" && !resource.IsMaestroTransport() {
+		for i := range docs {
+			if docs[i], err = annotateConfigFingerprint(docs[i], execCtx.ConfigFingerprint); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	rendered, extra = docs[0], docs[1:]
+	if !resource.IsMaestroTransport() {
+		return rendered, extra, nil
+	}
+
+	if len(extra) > 0 {
+		rendered, err = appendWorkloadManifests(rendered, extra)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if resource.Transport.Maestro != nil && resource.Transport.Maestro.Placement != nil {
+		rendered, err = applyPlacement(rendered, resource.Transport.Maestro.Placement, params)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if resource.Transport.Maestro != nil && len(resource.Transport.Maestro.FeedbackRules) > 0 {
+		rendered, err = applyFeedbackRules(rendered, resource.Transport.Maestro.FeedbackRules, params)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if execCtx.ConfigFingerprint != "" {
+		rendered, err = annotateConfigFingerprint(rendered, execCtx.ConfigFingerprint)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if ordering := resource.ManifestOrdering(); ordering != nil {
+		rendered, err = orderManifestWork(rendered, ordering)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return rendered, nil, nil
+}
+
+// applyExtraDocuments applies the documents after the first of a multi-document kubernetes
+// manifest with the resource's apply options. They are not discovered, deleted or pruned
+// with the resource.
+func (re *ResourceExecutor) applyExtraDocuments(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+	docs [][]byte,
+	applyOpts *transportclient.ApplyOptions,
+) error {
+	for i, doc := range docs {
+		var obj unstructured.Unstructured
+		if err := json.Unmarshal(doc, &obj.Object); err != nil {
+			return fmt.Errorf("manifest document %d: %w", i+1, err)
+		}
+		applyResult, err := re.client.ApplyResource(ctx, doc, applyOpts, nil)
+		execCtx.invalidateDiscovery(obj.GroupVersionKind(), obj.GetNamespace(), nil)
+		if err != nil {
+			return fmt.Errorf("manifest document %d (%s %s): %w", i+1, obj.GetKind(), obj.GetName(), err)
+		}
+		re.log.Infof(ctx, "Resource[%s] document %d (%s %s): operation=%s reason=%s",
+			resource.Name, i+1, obj.GetKind(), obj.GetName(), applyResult.Operation, applyResult.Reason)
+	}
+	return nil
+}
+
+// appendWorkloadManifests appends docs to spec.workload.manifests of the rendered ManifestWork
+func appendWorkloadManifests(rendered []byte, docs [][]byte) ([]byte, error) {
+	var work map[string]interface{}
+	if err := json.Unmarshal(rendered, &work); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered ManifestWork: %w", err)
+	}
+	if kind, isString := work["kind"].(string); !isString || kind != constants.ManifestWorkKind {
+		return nil, fmt.Errorf("the first document of a multi-document maestro manifest must be a %s, got %q",
+			constants.ManifestWorkKind, kind)
 	}
 
-	manifestStr, err := manifest.ToYAMLString(resource.Manifest)
+	manifests, _, err := unstructured.NestedSlice(work, "spec", "workload", "manifests")
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert manifest to string: %w", err)
+		return nil, fmt.Errorf("invalid spec.workload.manifests in rendered ManifestWork: %w", err)
+	}
+	for _, doc := range docs {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(doc, &obj); err != nil {
+			return nil, fmt.Errorf("failed to parse rendered manifest document: %w", err)
+		}
+		manifests = append(manifests, obj)
+	}
+	if err := unstructured.SetNestedSlice(work, manifests, "spec", "workload", "manifests"); err != nil {
+		return nil, fmt.Errorf("failed to set spec.workload.manifests: %w", err)
+	}
+	return json.Marshal(work)
+}
+
+// annotateConfigFingerprint sets the hyperfleet.io/config-fingerprint annotation on a
+// rendered object, recording the config revision that last applied it
+func annotateConfigFingerprint(rendered []byte, fingerprint string) ([]byte, error) {
+	var obj unstructured.Unstructured
+	if err := json.Unmarshal(rendered, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered manifest: %w", err)
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string, 1)
+	}
+	annotations[constants.AnnotationConfigFingerprint] = fingerprint
+	obj.SetAnnotations(annotations)
+	return json.Marshal(obj.Object)
+}
+
+// applyPlacement renders the placement labels and annotations and sets them on the
+// rendered ManifestWork, overriding any keys the manifest already defines.
+func applyPlacement(
+	rendered []byte,
+	placement *configloader.MaestroPlacementConfig,
+	params map[string]interface{},
+) ([]byte, error) {
+	var obj unstructured.Unstructured
+	if err := json.Unmarshal(rendered, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse rendered ManifestWork: %w", err)
+	}
+
+	labels := obj.GetLabels()
+	if labels == nil && len(placement.Labels) > 0 {
+		labels = make(map[string]string, len(placement.Labels))
+	}
+	for key, tpl := range placement.Labels {
+		value, err := utils.RenderTemplate(tpl, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render placement label %q: %w", key, err)
+		}
+		if errs := k8svalidation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("placement label %q rendered to invalid value %q: %s",
+				key, value, strings.Join(errs, "; "))
+		}
+		labels[key] = value
+	}
+
+	annotations := obj.GetAnnotations()
+	if annotations == nil && len(placement.Annotations) > 0 {
+		annotations = make(map[string]string, len(placement.Annotations))
+	}
+	for key, tpl := range placement.Annotations {
+		value, err := utils.RenderTemplate(tpl, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render placement annotation %q: %w", key, err)
+		}
+		annotations[key] = value
 	}
 
-	return manifest.RenderStringManifest(manifestStr, execCtx.Params)
+	obj.SetLabels(labels)
+	obj.SetAnnotations(annotations)
+	return json.Marshal(obj.Object)
+}
+
+// validateRenderedScope checks that namespaces in the rendered manifest match the scope
+// of their kinds. For maestro transport the workload manifests inside the ManifestWork
+// are checked; for k8s transport the manifest itself is checked.
+func validateRenderedScope(resource configloader.Resource, rendered []byte, obj *unstructured.Unstructured) error {
+	if resource.IsMaestroTransport() {
+		work, err := manifest.ParseManifestWork(rendered)
+		if err != nil {
+			return fmt.Errorf("failed to parse rendered ManifestWork: %w", err)
+		}
+		return manifest.ValidateManifestWorkNamespaceScope(work)
+	}
+	if obj.Object == nil {
+		return nil
+	}
+	return manifest.ValidateNamespaceScope(obj)
 }
 
 // discoverResource discovers the applied resource using the discovery config.
@@ -334,62 +797,182 @@ func (re *ResourceExecutor) discoverResource(
 		return nil, nil
 	}
 
+	params := stepParams(ctx, execCtx)
+
 	// Render discovery namespace template
-	namespace, err := utils.RenderTemplate(discovery.Namespace, execCtx.Params)
+	namespace, err := utils.RenderTemplate(discovery.Namespace, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render namespace template: %w", err)
 	}
 
-	// Discover by name
-	if discovery.ByName != "" {
-		name, err := utils.RenderTemplate(discovery.ByName, execCtx.Params)
-		if err != nil {
-			return nil, fmt.Errorf("failed to render byName template: %w", err)
-		}
+	methods := discovery.Methods()
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("discovery config must specify byName or bySelectors")
+	}
 
-		// For maestro: use ManifestWork GVK
-		// For k8s: parse the rendered manifest to get GVK
-		gvk := re.resolveGVK(resource)
+	// For maestro: use ManifestWork GVK
+	// For k8s: parse the rendered manifest to get GVK
+	gvk := re.resolveGVK(resource)
 
-		return re.client.GetResource(ctx, gvk, namespace, name, transportTarget)
+	if discovery.RequireAll {
+		return re.discoverByNameAndSelectors(ctx, discovery, gvk, namespace, params, transportTarget)
 	}
 
-	// Discover by label selector
-	if discovery.BySelectors != nil && len(discovery.BySelectors.LabelSelector) > 0 {
-		renderedLabels := make(map[string]string)
-		for k, v := range discovery.BySelectors.LabelSelector {
-			renderedK, err := utils.RenderTemplate(k, execCtx.Params)
-			if err != nil {
-				return nil, fmt.Errorf("failed to render label key template: %w", err)
-			}
-			renderedV, err := utils.RenderTemplate(v, execCtx.Params)
-			if err != nil {
-				return nil, fmt.Errorf("failed to render label value template: %w", err)
+	// Try each method in order; fall through to the next one only when nothing is found,
+	// so resources named by an older convention remain visible via selectors (or vice versa)
+	var notFoundErr error
+	for i, method := range methods {
+		var obj *unstructured.Unstructured
+		switch method {
+		case configloader.DiscoveryByName:
+			obj, err = re.discoverByName(ctx, discovery, gvk, namespace, params, transportTarget)
+		default:
+			obj, err = re.discoverBySelectors(ctx, execCtx, discovery, gvk, namespace, params, transportTarget)
+		}
+		if err == nil {
+			if i > 0 {
+				re.log.Infof(ctx, "Resource[%s] discovered via fallback %s", resource.Name, method)
 			}
-			renderedLabels[renderedK] = renderedV
+			return obj, nil
 		}
-
-		labelSelector := manifest.BuildLabelSelector(renderedLabels)
-		discoveryConfig := &manifest.DiscoveryConfig{
-			Namespace:     namespace,
-			LabelSelector: labelSelector,
+		if !apierrors.IsNotFound(err) {
+			return nil, err
 		}
+		notFoundErr = err
+	}
+	return nil, notFoundErr
+}
+
+// discoverByName looks up a single resource by its rendered by_name
+func (re *ResourceExecutor) discoverByName(
+	ctx context.Context,
+	discovery *configloader.DiscoveryConfig,
+	gvk schema.GroupVersionKind,
+	namespace string,
+	params map[string]interface{},
+	transportTarget transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	name, err := utils.RenderTemplate(discovery.ByName, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render byName template: %w", err)
+	}
+	return re.client.GetResource(ctx, gvk, namespace, name, transportTarget)
+}
 
-		gvk := re.resolveGVK(resource)
+// discoverBySelectors lists resources matching the rendered label selector and
+// resolves multiple matches according to the discovery match policy. List results are
+// cached in execCtx, so resources sharing a kind, namespace and selector cost one LIST.
+func (re *ResourceExecutor) discoverBySelectors(
+	ctx context.Context,
+	execCtx *ExecutionContext,
+	discovery *configloader.DiscoveryConfig,
+	gvk schema.GroupVersionKind,
+	namespace string,
+	params map[string]interface{},
+	transportTarget transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	labelSelector, err := renderLabelSelector(discovery.BySelectors, params)
+	if err != nil {
+		return nil, err
+	}
+	discoveryConfig := &manifest.DiscoveryConfig{
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+	}
 
-		list, err := re.client.DiscoverResources(ctx, gvk, discoveryConfig, transportTarget)
+	cacheKey := newDiscoveryCacheKey(gvk, namespace, labelSelector, transportTarget)
+	list, cached := execCtx.cachedDiscovery(cacheKey)
+	if cached {
+		re.log.Debugf(ctx, "Discovery of %s %q in namespace %q served from cache", gvk.Kind, labelSelector, namespace)
+	} else {
+		list, err = re.client.DiscoverResources(ctx, gvk, discoveryConfig, transportTarget)
 		if err != nil {
 			return nil, err
 		}
+		execCtx.cacheDiscovery(cacheKey, list)
+	}
 
-		if len(list.Items) == 0 {
-			return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, "")
+	if len(list.Items) == 0 {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, "")
+	}
+	if len(list.Items) > 1 && discovery.UsesFailOnMulti() {
+		return nil, fmt.Errorf("label selector %q matched %d resources (match_policy=%s)",
+			labelSelector, len(list.Items), configloader.MatchPolicyFailOnMulti)
+	}
+
+	return manifest.GetLatestGenerationFromList(list), nil
+}
+
+// renderLabelSelector renders label selector key/value templates into a selector string
+func renderLabelSelector(selector *configloader.SelectorConfig, params map[string]interface{}) (string, error) {
+	renderedLabels, err := renderSelectorLabels(selector, params)
+	if err != nil {
+		return "", err
+	}
+	return manifest.BuildLabelSelector(renderedLabels), nil
+}
+
+// renderSelectorLabels renders label selector key/value templates
+func renderSelectorLabels(
+	selector *configloader.SelectorConfig,
+	params map[string]interface{},
+) (map[string]string, error) {
+	renderedLabels := make(map[string]string)
+	for k, v := range selector.LabelSelector {
+		renderedK, err := utils.RenderTemplate(k, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render label key template: %w", err)
+		}
+		renderedV, err := utils.RenderTemplate(v, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render label value template: %w", err)
 		}
+		renderedLabels[renderedK] = renderedV
+	}
+	return renderedLabels, nil
+}
 
-		return manifest.GetLatestGenerationFromList(list), nil
+// discoverByNameAndSelectors looks up the resource named by_name and requires it to carry
+// the by_selectors labels. A same-named object without them is most likely not ours, so
+// it fails discovery with ErrForeignResource rather than being applied over or deleted.
+func (re *ResourceExecutor) discoverByNameAndSelectors(
+	ctx context.Context,
+	discovery *configloader.DiscoveryConfig,
+	gvk schema.GroupVersionKind,
+	namespace string,
+	params map[string]interface{},
+	transportTarget transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	obj, err := re.discoverByName(ctx, discovery, gvk, namespace, params, transportTarget)
+	if err != nil {
+		return nil, err
+	}
+	want, err := renderSelectorLabels(discovery.BySelectors, params)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, fmt.Errorf("discovery config must specify byName or bySelectors")
+	labels := obj.GetLabels()
+	var mismatches []string
+	for k, v := range want {
+		got, ok := labels[k]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s is missing", k))
+		case got != v:
+			mismatches = append(mismatches, fmt.Sprintf("%s=%q, want %q", k, got, v))
+		}
+	}
+	if len(mismatches) == 0 {
+		return obj, nil
+	}
+	sort.Strings(mismatches)
+	ref := obj.GetName()
+	if obj.GetNamespace() != "" {
+		ref = obj.GetNamespace() + "/" + ref
+	}
+	return nil, fmt.Errorf("%w: %s %s does not match the discovery labels (%s)",
+		ErrForeignResource, obj.GetKind(), ref, strings.Join(mismatches, ", "))
 }
 
 // discoverNestedResources discovers sub-resources within a parent resource (e.g., manifests inside a ManifestWork).
@@ -407,19 +990,29 @@ func (re *ResourceExecutor) discoverNestedResources(
 			continue
 		}
 
-		// Build discovery config with rendered templates
-		discoveryConfig, err := re.buildNestedDiscoveryConfig(nd.Discovery, execCtx.Params)
+		// Build discovery configs with rendered templates, in lookup order
+		discoveryConfigs, err := re.buildNestedDiscoveryConfigs(nd.Discovery, stepParams(ctx, execCtx))
 		if err != nil {
 			re.log.Warnf(ctx, "Resource[%s] nested discovery[%s] failed to build config: %v",
 				resource.Name, nd.Name, err)
+			execCtx.AddWarning(PhaseResources, resource.Name,
+				fmt.Sprintf("nested discovery %q failed to build config: %v", nd.Name, err))
 			continue
 		}
 
-		// Search within the parent resource
-		list, err := manifest.DiscoverNestedManifest(parent, discoveryConfig)
+		// Search within the parent resource, falling back to the next method on no match
+		list := &unstructured.UnstructuredList{}
+		for _, discoveryConfig := range discoveryConfigs {
+			list, err = manifest.DiscoverNestedManifest(parent, discoveryConfig)
+			if err != nil || len(list.Items) > 0 {
+				break
+			}
+		}
 		if err != nil {
 			re.log.Warnf(ctx, "Resource[%s] nested discovery[%s] failed: %v",
 				resource.Name, nd.Name, err)
+			execCtx.AddWarning(PhaseResources, resource.Name,
+				fmt.Sprintf("nested discovery %q failed: %v", nd.Name, err))
 			continue
 		}
 
@@ -428,6 +1021,14 @@ func (re *ResourceExecutor) discoverNestedResources(
 				resource.Name, nd.Name)
 			continue
 		}
+		if len(list.Items) > 1 && nd.Discovery.UsesFailOnMulti() {
+			re.log.Warnf(ctx, "Resource[%s] nested discovery[%s] matched %d manifests (match_policy=%s)",
+				resource.Name, nd.Name, len(list.Items), configloader.MatchPolicyFailOnMulti)
+			execCtx.AddWarning(PhaseResources, resource.Name,
+				fmt.Sprintf("nested discovery %q matched %d manifests (match_policy=%s)",
+					nd.Name, len(list.Items), configloader.MatchPolicyFailOnMulti))
+			continue
+		}
 
 		// Use the latest generation match
 		best := manifest.GetLatestGenerationFromList(list)
@@ -442,47 +1043,51 @@ func (re *ResourceExecutor) discoverNestedResources(
 	return nestedResults
 }
 
-// buildNestedDiscoveryConfig renders templates in a discovery config and returns a manifest.DiscoveryConfig.
-func (re *ResourceExecutor) buildNestedDiscoveryConfig(
+// buildNestedDiscoveryConfigs renders templates in a discovery config and returns one
+// manifest.DiscoveryConfig per configured method, in lookup order.
+func (re *ResourceExecutor) buildNestedDiscoveryConfigs(
 	discovery *configloader.DiscoveryConfig,
 	params map[string]interface{},
-) (*manifest.DiscoveryConfig, error) {
+) ([]*manifest.DiscoveryConfig, error) {
 	namespace, err := utils.RenderTemplate(discovery.Namespace, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to render namespace template: %w", err)
 	}
 
-	if discovery.ByName != "" {
+	methods := discovery.Methods()
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("discovery must specify byName or bySelectors")
+	}
+
+	if discovery.RequireAll {
 		name, err := utils.RenderTemplate(discovery.ByName, params)
 		if err != nil {
 			return nil, fmt.Errorf("failed to render byName template: %w", err)
 		}
-		return &manifest.DiscoveryConfig{
-			Namespace: namespace,
-			ByName:    name,
-		}, nil
+		labelSelector, err := renderLabelSelector(discovery.BySelectors, params)
+		if err != nil {
+			return nil, err
+		}
+		return []*manifest.DiscoveryConfig{{Namespace: namespace, ByName: name, LabelSelector: labelSelector}}, nil
 	}
 
-	if discovery.BySelectors != nil && len(discovery.BySelectors.LabelSelector) > 0 {
-		renderedLabels := make(map[string]string)
-		for k, v := range discovery.BySelectors.LabelSelector {
-			renderedK, err := utils.RenderTemplate(k, params)
+	configs := make([]*manifest.DiscoveryConfig, 0, len(methods))
+	for _, method := range methods {
+		if method == configloader.DiscoveryByName {
+			name, err := utils.RenderTemplate(discovery.ByName, params)
 			if err != nil {
-				return nil, fmt.Errorf("failed to render label key template: %w", err)
+				return nil, fmt.Errorf("failed to render byName template: %w", err)
 			}
-			renderedV, err := utils.RenderTemplate(v, params)
-			if err != nil {
-				return nil, fmt.Errorf("failed to render label value template: %w", err)
-			}
-			renderedLabels[renderedK] = renderedV
+			configs = append(configs, &manifest.DiscoveryConfig{Namespace: namespace, ByName: name})
+			continue
 		}
-		return &manifest.DiscoveryConfig{
-			Namespace:     namespace,
-			LabelSelector: manifest.BuildLabelSelector(renderedLabels),
-		}, nil
+		labelSelector, err := renderLabelSelector(discovery.BySelectors, params)
+		if err != nil {
+			return nil, err
+		}
+		configs = append(configs, &manifest.DiscoveryConfig{Namespace: namespace, LabelSelector: labelSelector})
 	}
-
-	return nil, fmt.Errorf("discovery must specify byName or bySelectors")
+	return configs, nil
 }
 
 // resolveGVK extracts the GVK from the resource's manifest.
@@ -524,6 +1129,64 @@ func hasLifecycleConfig(resources []configloader.Resource) bool {
 	return false
 }
 
+// hasParallelResources reports whether any resource opts out of sequential execution
+func hasParallelResources(resources []configloader.Resource) bool {
+	for _, r := range resources {
+		if r.Parallel || len(r.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// transportTargetFor renders the per-request routing context of a resource's transport:
+// the target cluster of maestro, the path and commit message of gitops, nil for kubernetes
+func transportTargetFor(
+	resource configloader.Resource,
+	params map[string]interface{},
+) (transportclient.TransportContext, error) {
+	switch {
+	case resource.IsMaestroTransport() && resource.Transport.Maestro != nil:
+		targetCluster, err := utils.RenderTemplate(resource.Transport.Maestro.TargetCluster, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render targetCluster template: %w", err)
+		}
+		return &maestroclient.TransportContext{ConsumerName: targetCluster}, nil
+	case resource.Transport != nil && resource.Transport.Client == configloader.TransportClientGitOps &&
+		resource.Transport.GitOps != nil:
+		path, err := utils.RenderTemplate(resource.Transport.GitOps.Path, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render gitops path template: %w", err)
+		}
+		message, err := utils.RenderTemplate(resource.Transport.GitOps.CommitMessage, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render gitops commit_message template: %w", err)
+		}
+		return &gitopsclient.TransportContext{Path: path, CommitMessage: message}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// stepTarget describes the object a resource step operates on for its progress logs: its
+// kind and namespace/name, followed by the Maestro consumer or GitOps path it goes to.
+func stepTarget(kind, namespace, name string, transportTarget transportclient.TransportContext) string {
+	target := kind
+	switch {
+	case namespace != "" && name != "":
+		target += " " + namespace + "/" + name
+	case name != "":
+		target += " " + name
+	}
+	switch t := transportTarget.(type) {
+	case *maestroclient.TransportContext:
+		target += " on consumer " + t.ConsumerName
+	case *gitopsclient.TransportContext:
+		target += " at " + t.Path
+	}
+	return strings.TrimSpace(target)
+}
+
 // preDiscoverAll discovers all resources and populates execCtx.Resources before the main
 // resource loop begins. This makes every resource's current cluster state available to
 // lifecycle.delete.when CEL expressions regardless of list order.
@@ -549,18 +1212,16 @@ func (re *ResourceExecutor) preDiscoverAll(
 			continue
 		}
 
-		var transportTarget transportclient.TransportContext
-		if resource.IsMaestroTransport() && resource.Transport.Maestro != nil {
-			targetCluster, err := utils.RenderTemplate(resource.Transport.Maestro.TargetCluster, execCtx.Params)
-			if err != nil {
-				re.log.Warnf(ctx, "Resource[%s] pre-discovery: failed to render targetCluster: %v",
-					resource.Name, err)
-				return NewExecutorError(PhaseResources, resource.Name, "failed to render targetCluster", err)
-			}
-			transportTarget = &maestroclient.TransportContext{ConsumerName: targetCluster}
+		itemCtx := forEachContext(ctx, execCtx, resource)
+		transportTarget, err := transportTargetFor(resource, stepParams(itemCtx, execCtx))
+		if err != nil {
+			re.log.Warnf(ctx, "Resource[%s] pre-discovery: failed to render transport template: %v",
+				resource.Name, err)
+			return NewExecutorError(PhaseResources, resource.Name, "failed to render transport template", err)
 		}
 
-		discovered, err := re.discoverResource(ctx, resource, execCtx, transportTarget)
+		resourceCtx := transportclient.WithTransportName(itemCtx, resource.GetTransportClient())
+		discovered, err := re.discoverResource(resourceCtx, resource, execCtx, transportTarget)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				// Resource does not exist yet — leave absent from context.
@@ -572,7 +1233,7 @@ func (re *ResourceExecutor) preDiscoverAll(
 			return NewExecutorError(PhaseResources, resource.Name, "pre-discovery failed", err)
 		}
 		if discovered != nil {
-			execCtx.Resources[resource.Name] = discovered
+			execCtx.SetResource(resource.Name, discovered)
 			re.log.Debugf(ctx, "Resource[%s] pre-discovered and stored in context", resource.Name)
 		}
 	}
@@ -593,7 +1254,7 @@ func (re *ResourceExecutor) evaluateLifecycleWhen(
 	kind, expression string,
 ) (bool, error) {
 	evalCtx := criteria.NewEvaluationContext()
-	evalCtx.SetVariablesFromMap(execCtx.GetCELVariables())
+	evalCtx.SetVariablesFromMap(stepCELVariables(ctx, execCtx))
 
 	evaluator, err := criteria.NewEvaluator(ctx, evalCtx, re.log)
 	if err != nil {
@@ -650,6 +1311,8 @@ func (re *ResourceExecutor) executeResourceDelete(
 		Operation: manifest.OperationDelete,
 	}
 
+	setStepOperation(ctx, "delete", stepTarget(resourceType, "", "", transportTarget))
+
 	// Step 1: Discover the existing resource
 	discovered, discoverErr := re.discoverResource(ctx, resource, execCtx, transportTarget)
 
@@ -664,11 +1327,22 @@ func (re *ResourceExecutor) executeResourceDelete(
 			PhaseResources, resource.Name, "failed to discover resource for deletion", discoverErr)
 	}
 
-	// Step 2: If not found — resource is already deleted (or never existed)
+	// Step 2: If not found — resource is already deleted (or never existed). The earlier
+	// tiers of a split ManifestWork may still exist when the last one was never applied.
 	if discovered == nil || isNotFound {
+		if resource.SplitsManifestWork() {
+			if splitErr := re.deleteSplitWorks(ctx, resource, execCtx, "", "", transportTarget); splitErr != nil {
+				result.Status = StatusFailed
+				result.Error = splitErr
+				re.recordResourceError(execCtx, resource, splitErr)
+				re.metrics.RecordDeletion(resourceType, metrics.DeletionStatusError)
+				re.metrics.ObserveDeletionDuration(resourceType, time.Since(startTime))
+				return result, NewExecutorError(PhaseResources, resource.Name, "failed to delete resource", splitErr)
+			}
+		}
 		// Store nil — the key is removed from the CEL resources map, so
 		// !resources.?X.hasValue() evaluates to true in this reconciliation.
-		execCtx.Resources[resource.Name] = nil
+		execCtx.SetResource(resource.Name, nil)
 		result.OperationReason = "resource already deleted or never existed"
 		re.log.Infof(ctx, "Resource[%s] delete: already deleted or never existed", resource.Name)
 		re.metrics.RecordDeletion(resourceType, metrics.DeletionStatusSuccess)
@@ -697,10 +1371,13 @@ func (re *ResourceExecutor) executeResourceDelete(
 	}
 	deleteOpts := &transportclient.DeleteOptions{PropagationPolicy: propagationPolicy}
 
-	// Step 5: Delete via transport client
-	if err := re.client.DeleteResource(
-		ctx, gvk, result.Namespace, result.ResourceName, deleteOpts, transportTarget,
-	); err != nil {
+	// Step 5: Delete via transport client, then the earlier tiers of a split ManifestWork
+	err := re.client.DeleteResource(ctx, gvk, result.Namespace, result.ResourceName, deleteOpts, transportTarget)
+	execCtx.invalidateDiscovery(gvk, result.Namespace, transportTarget)
+	if err == nil && resource.SplitsManifestWork() {
+		err = re.deleteSplitWorks(ctx, resource, execCtx, result.Namespace, result.ResourceName, transportTarget)
+	}
+	if err != nil {
 		result.Status = StatusFailed
 		result.Error = err
 		re.recordResourceError(execCtx, resource, err)
@@ -712,6 +1389,28 @@ func (re *ResourceExecutor) executeResourceDelete(
 		return result, NewExecutorError(PhaseResources, resource.Name, "failed to delete resource", err)
 	}
 
+	// Step 5.5: The managed finalizer holds the object while the teardown steps run, and
+	// is removed once they succeeded. A failure keeps it so the next event retries.
+	if finalizer := managedFinalizer(resource); finalizer != "" && hasFinalizer(discovered, finalizer) {
+		err = re.runTeardown(ctx, resource, execCtx)
+		if err == nil {
+			err = re.removeFinalizer(ctx, gvk, result.Namespace, result.ResourceName, finalizer)
+			execCtx.invalidateDiscovery(gvk, result.Namespace, transportTarget)
+		}
+		if err != nil {
+			result.Status = StatusFailed
+			result.Error = err
+			re.recordResourceError(execCtx, resource, err)
+			errCtx := logger.WithK8sResult(ctx, "FAILED")
+			errCtx = logger.WithErrorField(errCtx, err)
+			re.log.Errorf(errCtx, "Resource[%s] delete: teardown FAILED, keeping finalizer %s", resource.Name, finalizer)
+			re.metrics.RecordDeletion(resourceType, metrics.DeletionStatusError)
+			re.metrics.ObserveDeletionDuration(resourceType, time.Since(startTime))
+			return result, NewExecutorError(PhaseResources, resource.Name, "failed to tear down resource", err)
+		}
+		re.log.Infof(ctx, "Resource[%s] delete: teardown done, removed finalizer %s", resource.Name, finalizer)
+	}
+
 	// Step 6: Re-discover the resource after deletion to determine its actual state.
 	// - If NotFound: resource is truly gone (no finalizers, or K8s Background delete was instant).
 	//   Store nil so dependent resources can cascade in the same reconciliation.
@@ -723,14 +1422,14 @@ func (re *ResourceExecutor) executeResourceDelete(
 	case postDiscoverErr != nil && !postIsNotFound:
 		// Non-fatal: log the error but don't fail the delete — the delete itself succeeded.
 		re.log.Debugf(ctx, "Resource[%s] post-delete discovery error (non-fatal): %v", resource.Name, postDiscoverErr)
-		execCtx.Resources[resource.Name] = discovered
+		execCtx.SetResource(resource.Name, discovered)
 	case postDeleteDiscovered == nil || postIsNotFound:
 		// Resource is confirmed gone: dependent resources can proceed in this reconciliation.
-		execCtx.Resources[resource.Name] = nil
+		execCtx.SetResource(resource.Name, nil)
 		re.log.Debugf(ctx, "Resource[%s] confirmed deleted (post-delete discovery: not found)", resource.Name)
 	default:
 		// Resource still present (finalizers or async deletion): dependents wait for next reconciliation.
-		execCtx.Resources[resource.Name] = postDeleteDiscovered
+		execCtx.SetResource(resource.Name, postDeleteDiscovered)
 		re.log.Debugf(ctx, "Resource[%s] still present after delete (finalizers or async): dependents wait", resource.Name)
 	}
 
@@ -746,22 +1445,36 @@ func (re *ResourceExecutor) executeResourceDelete(
 	return result, nil
 }
 
+// transformResource evaluates the transform of resource against its discovered object and
+// stores the result as resources.<name> for CEL. A resource that was deleted or not
+// discovered has nothing to transform.
+func (re *ResourceExecutor) transformResource(
+	ctx context.Context,
+	resource configloader.Resource,
+	execCtx *ExecutionContext,
+	result *ResourceResult,
+) error {
+	value, _ := execCtx.GetResource(resource.Name)
+	obj, ok := value.(*unstructured.Unstructured)
+	if !ok || obj == nil {
+		return nil
+	}
+	transformed, err := transformStepResult(ctx, re.log, resource.Name, resource.Transform, obj.Object)
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err
+		re.recordResourceError(execCtx, resource, err)
+		return NewExecutorError(PhaseResources, resource.Name, "failed to transform resource", err)
+	}
+	execCtx.SetTransformedResource(resource.Name, transformed)
+	return nil
+}
+
 // recordResourceError sets execCtx.Adapter.ExecutionError (first error wins) and populates
 // execCtx.Adapter.ResourceErrors with a per-resource entry. Called by executeResourceDelete
 // on both discovery failure and delete failure paths.
 func (re *ResourceExecutor) recordResourceError(execCtx *ExecutionContext, resource configloader.Resource, err error) {
-	execErr := ExecutionError{
-		Phase:   string(PhaseResources),
-		Step:    resource.Name,
-		Message: err.Error(),
-	}
-	if execCtx.Adapter.ExecutionError == nil {
-		execCtx.Adapter.ExecutionError = &execErr
-	}
-	if execCtx.Adapter.ResourceErrors == nil {
-		execCtx.Adapter.ResourceErrors = make(map[string]ExecutionError)
-	}
-	execCtx.Adapter.ResourceErrors[resource.Name] = execErr
+	execCtx.RecordResourceError(resource.Name, err.Error())
 }
 
 // GetResourceAsMap converts an unstructured resource to a map for CEL evaluation
@@ -2,19 +2,28 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/gitopsclient"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/maestroclient"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
 )
 
 // testDeletedTime is a non-null deleted_time value used in lifecycle delete tests to trigger when-expressions.
@@ -313,7 +322,7 @@ metadata:
 			execCtx := NewExecutionContext(context.Background(), nil, nil)
 			execCtx.Params = tt.params
 
-			data, err := re.renderToBytes(resource, execCtx)
+			data, err := re.renderToBytes(context.Background(), resource, execCtx)
 			if tt.wantErr {
 				require.Error(t, err)
 				return
@@ -353,7 +362,7 @@ data:
 	execCtx := NewExecutionContext(context.Background(), nil, nil)
 	execCtx.Params = params
 
-	data, err := re.renderToBytes(resource, execCtx)
+	data, err := re.renderToBytes(context.Background(), resource, execCtx)
 	require.NoError(t, err)
 	assert.Contains(t, string(data), "sub1")
 	assert.Contains(t, string(data), "sub2")
@@ -380,7 +389,7 @@ data:
 		execCtx := NewExecutionContext(context.Background(), nil, nil)
 		execCtx.Params = map[string]interface{}{}
 
-		data, err := re.renderToBytes(resource, execCtx)
+		data, err := re.renderToBytes(context.Background(), resource, execCtx)
 		require.NoError(t, err)
 		assert.Contains(t, string(data), `"name":"static-config"`)
 		assert.Contains(t, string(data), `"key":"value"`)
@@ -394,7 +403,7 @@ data:
 		execCtx := NewExecutionContext(context.Background(), nil, nil)
 		execCtx.Params = map[string]interface{}{}
 
-		_, err := re.renderToBytes(resource, execCtx)
+		_, err := re.renderToBytes(context.Background(), resource, execCtx)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "empty manifest")
 	})
@@ -410,7 +419,7 @@ data:
 			"content": "not: valid: yaml: [broken",
 		}
 
-		_, err := re.renderToBytes(resource, execCtx)
+		_, err := re.renderToBytes(context.Background(), resource, execCtx)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to parse rendered manifest as YAML")
 	})
@@ -427,7 +436,7 @@ metadata:
 		execCtx := NewExecutionContext(context.Background(), nil, nil)
 		execCtx.Params = map[string]interface{}{} // missingVar not provided
 
-		_, err := re.renderToBytes(resource, execCtx)
+		_, err := re.renderToBytes(context.Background(), resource, execCtx)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "missingVar")
 	})
@@ -439,7 +448,7 @@ metadata:
 		}
 		execCtx := NewExecutionContext(context.Background(), nil, nil)
 
-		_, err := re.renderToBytes(resource, execCtx)
+		_, err := re.renderToBytes(context.Background(), resource, execCtx)
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "no manifest specified")
 	})
@@ -461,7 +470,7 @@ metadata:
 			"name": "rendered-name",
 		}
 
-		data, err := re.renderToBytes(resource, execCtx)
+		data, err := re.renderToBytes(context.Background(), resource, execCtx)
 		require.NoError(t, err)
 		assert.Contains(t, string(data), `"name":"rendered-name"`)
 		assert.Contains(t, string(data), `"namespace":"default"`)
@@ -770,6 +779,53 @@ func TestResourceExecutor_LifecycleCreate_WhenFalse_ResourceNotFound_Skipped(t *
 	// post-action `when` gates can observe it.
 	assert.True(t, execCtx.Adapter.ResourcesSkipped, "adapter.resourcesSkipped must be set on skip")
 	assert.NotEmpty(t, execCtx.Adapter.SkipReason)
+	assert.Equal(t, []ResourceSkip{{
+		Resource: "test-resource",
+		Reason:   metrics.SkipReasonWhenFalse,
+		Message:  "lifecycle.create.when condition evaluated to false",
+	}}, execCtx.Adapter.SkipReasons)
+}
+
+func TestResourceExecutor_GenerationUnchanged_RecordsSkipReason(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	mock.ApplyResourceResult = &transportclient.ApplyResult{
+		Operation: manifest.OperationSkip,
+		Reason:    "generation 3 unchanged",
+	}
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: mock,
+		Logger:          logger.NewTestLogger(),
+	})
+
+	resource := newResourceWithLifecycle("", "")
+	resource.Discovery = nil
+	metadata := resource.Manifest.(map[string]interface{})["metadata"].(map[string]interface{})
+	metadata["annotations"] = map[string]interface{}{
+		constants.AnnotationGeneration: "3",
+	}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSuccess, results[0].Status)
+	assert.False(t, execCtx.Adapter.ResourcesSkipped,
+		"a resource already at its generation should not gate post actions on resourcesSkipped")
+	assert.Equal(t, []ResourceSkip{{
+		Resource:   "test-resource",
+		Reason:     metrics.SkipReasonGenerationUnchanged,
+		Message:    "generation 3 unchanged",
+		Generation: 3,
+	}}, execCtx.Adapter.SkipReasons)
+
+	skipReasons := execCtx.GetCELVariables()["adapter"].(map[string]interface{})["skipReasons"]
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"resource":   "test-resource",
+		"reason":     "generation_unchanged",
+		"message":    "generation 3 unchanged",
+		"generation": int64(3),
+	}}, skipReasons)
 }
 
 func TestResourceExecutor_LifecycleCreate_WhenCELError_ExecutionFails(t *testing.T) {
@@ -886,6 +942,7 @@ func TestResourceExecutor_LifecycleCreate_Absent_NormalApply(t *testing.T) {
 // ApplyResource was called.
 type trackingApplyMockClient struct {
 	*k8sclient.MockK8sClient
+	ApplyOpts   *transportclient.ApplyOptions
 	ApplyCalled bool
 }
 
@@ -896,6 +953,7 @@ func (m *trackingApplyMockClient) ApplyResource(
 	target transportclient.TransportContext,
 ) (*transportclient.ApplyResult, error) {
 	m.ApplyCalled = true
+	m.ApplyOpts = opts
 	return m.MockK8sClient.ApplyResource(ctx, data, opts, target)
 }
 
@@ -1853,8 +1911,8 @@ func TestResourceExecutor_GetCELVariables_DeletedResourceAbsent(t *testing.T) {
 	assert.True(t, exists, "non-nil resource should be in CEL resources map")
 }
 
-// selectorTrackingMockClient overrides DiscoverResources to return the pre-delete list on the
-// first call and an empty list on subsequent calls, simulating instant K8s deletion via
+// selectorTrackingMockClient overrides DiscoverResources to return the pre-delete list until
+// DeleteResource is called and an empty list afterwards, simulating instant K8s deletion via
 // label-selector discovery.
 type selectorTrackingMockClient struct {
 	*k8sclient.MockK8sClient
@@ -1870,9 +1928,10 @@ func (m *selectorTrackingMockClient) DiscoverResources(
 	target transportclient.TransportContext,
 ) (*unstructured.UnstructuredList, error) {
 	m.discoverCalls++
-	// Calls 1 (preDiscoverAll) and 2 (executeResourceDelete pre-delete): resource exists.
-	// Call 3+ (post-delete rediscovery): resource gone (instant K8s delete, no finalizers).
-	if m.discoverCalls <= 2 {
+	// Before the delete (preDiscoverAll; the pre-delete lookup is served from the discovery
+	// cache): resource exists. After it (post-delete rediscovery): resource gone (instant
+	// K8s delete, no finalizers).
+	if !m.DeleteCalled {
 		return m.MockK8sClient.DiscoverResources(ctx, gvk, discovery, target)
 	}
 	return &unstructured.UnstructuredList{}, nil
@@ -1955,3 +2014,940 @@ func TestResourceExecutor_LifecycleDelete_BySelectors(t *testing.T) {
 	assert.True(t, exists, "nil sentinel should be in execCtx.Resources")
 	assert.Nil(t, storedVal, "nil stored when post-delete discovery finds no resources")
 }
+
+func TestDiscoverResource_CachesSelectorLists(t *testing.T) {
+	inner := k8sclient.NewMockK8sClient()
+	inner.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "my-cm", "namespace": "default"},
+		},
+	}}}
+	mock := &selectorTrackingMockClient{MockK8sClient: inner}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resource := func(name string) configloader.Resource {
+		return configloader.Resource{
+			Name: name,
+			Manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": name},
+			},
+			Discovery: &configloader.DiscoveryConfig{
+				Namespace:   "default",
+				BySelectors: &configloader.SelectorConfig{LabelSelector: map[string]string{"app": "demo"}},
+			},
+		}
+	}
+	ctx := context.Background()
+	execCtx := NewExecutionContext(ctx, nil, nil)
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	// Resources sharing kind, namespace and selector cost one LIST
+	for _, name := range []string{"a", "b"} {
+		obj, err := re.discoverResource(ctx, resource(name), execCtx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "my-cm", obj.GetName())
+	}
+	assert.Equal(t, 1, mock.discoverCalls)
+
+	// Writes to another namespace, kind or target keep the cached list
+	execCtx.invalidateDiscovery(gvk, "other", nil)
+	execCtx.invalidateDiscovery(schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, "default", nil)
+	execCtx.invalidateDiscovery(gvk, "default", &maestroclient.TransportContext{ConsumerName: "cluster1"})
+	_, err := re.discoverResource(ctx, resource("a"), execCtx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, mock.discoverCalls)
+
+	// A write to the same kind and namespace lists again
+	execCtx.invalidateDiscovery(gvk, "default", nil)
+	_, err = re.discoverResource(ctx, resource("a"), execCtx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, mock.discoverCalls)
+
+	// Each execution starts with an empty cache
+	_, err = re.discoverResource(ctx, resource("a"), NewExecutionContext(ctx, nil, nil), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, mock.discoverCalls)
+}
+
+func TestDiscoverResource_FallbackOrder(t *testing.T) {
+	newObj := func(name string, generation int64) unstructured.Unstructured {
+		return unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":        name,
+				"namespace":   "default",
+				"annotations": map[string]interface{}{"hyperfleet.io/generation": fmt.Sprint(generation)},
+			},
+		}}
+	}
+	resource := func(discovery *configloader.DiscoveryConfig) configloader.Resource {
+		return configloader.Resource{
+			Name: "cm",
+			Manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "new-name"},
+			},
+			Discovery: discovery,
+		}
+	}
+	selectors := &configloader.SelectorConfig{LabelSelector: map[string]string{"app": "demo"}}
+
+	t.Run("falls back to selectors when name is not found", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{newObj("old-name", 1)}}
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		obj, err := re.discoverResource(context.Background(), resource(&configloader.DiscoveryConfig{
+			Namespace: "default", ByName: "new-name", BySelectors: selectors,
+		}), NewExecutionContext(context.Background(), nil, nil), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "old-name", obj.GetName())
+	})
+
+	t.Run("order puts selectors first", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		named := newObj("new-name", 1)
+		mock.Resources["default/new-name"] = &named
+		mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{newObj("old-name", 1)}}
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		obj, err := re.discoverResource(context.Background(), resource(&configloader.DiscoveryConfig{
+			Namespace: "default", ByName: "new-name", BySelectors: selectors,
+			Order: []string{configloader.DiscoveryBySelectors, configloader.DiscoveryByName},
+		}), NewExecutionContext(context.Background(), nil, nil), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "old-name", obj.GetName())
+	})
+
+	t.Run("not found by any method", func(t *testing.T) {
+		re := newResourceExecutor(&ExecutorConfig{
+			TransportClient: k8sclient.NewMockK8sClient(), Logger: logger.NewTestLogger(),
+		})
+		_, err := re.discoverResource(context.Background(), resource(&configloader.DiscoveryConfig{
+			Namespace: "default", ByName: "new-name", BySelectors: selectors,
+		}), NewExecutionContext(context.Background(), nil, nil), nil)
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("match policies", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.DiscoverResult = &unstructured.UnstructuredList{Items: []unstructured.Unstructured{
+			newObj("a", 1), newObj("b", 2),
+		}}
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		obj, err := re.discoverResource(context.Background(), resource(&configloader.DiscoveryConfig{
+			Namespace: "default", BySelectors: selectors,
+		}), NewExecutionContext(context.Background(), nil, nil), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "b", obj.GetName())
+
+		_, err = re.discoverResource(context.Background(), resource(&configloader.DiscoveryConfig{
+			Namespace: "default", BySelectors: selectors, MatchPolicy: configloader.MatchPolicyFailOnMulti,
+		}), NewExecutionContext(context.Background(), nil, nil), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "matched 2 resources")
+	})
+}
+
+func TestDiscoverResource_RequireAll(t *testing.T) {
+	newObj := func(labels map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "cluster-config",
+				"namespace": "default",
+				"labels":    labels,
+			},
+		}}
+	}
+	resource := configloader.Resource{
+		Name: "cm",
+		Manifest: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":      "cluster-config",
+				"namespace": "default",
+				"labels":    map[string]interface{}{"app": "demo", "cluster": "c1"},
+			},
+		},
+		Discovery: &configloader.DiscoveryConfig{
+			Namespace:  "default",
+			ByName:     "cluster-config",
+			RequireAll: true,
+			BySelectors: &configloader.SelectorConfig{
+				LabelSelector: map[string]string{"app": "demo", "cluster": "{{ .clusterId }}"},
+			},
+		},
+	}
+	newExecCtx := func() *ExecutionContext {
+		execCtx := NewExecutionContext(context.Background(), nil, nil)
+		execCtx.Params["clusterId"] = "c1"
+		return execCtx
+	}
+
+	t.Run("object with the labels is discovered", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.Resources["default/cluster-config"] = newObj(map[string]interface{}{"app": "demo", "cluster": "c1"})
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		obj, err := re.discoverResource(context.Background(), resource, newExecCtx(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, "cluster-config", obj.GetName())
+	})
+
+	t.Run("missing object is not found", func(t *testing.T) {
+		re := newResourceExecutor(&ExecutorConfig{
+			TransportClient: k8sclient.NewMockK8sClient(), Logger: logger.NewTestLogger(),
+		})
+		_, err := re.discoverResource(context.Background(), resource, newExecCtx(), nil)
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("foreign object is a conflict", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		mock.Resources["default/cluster-config"] = newObj(map[string]interface{}{"cluster": "c2"})
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		_, err := re.discoverResource(context.Background(), resource, newExecCtx(), nil)
+		require.ErrorIs(t, err, ErrForeignResource)
+		assert.Contains(t, err.Error(), `ConfigMap default/cluster-config does not match the discovery labels `+
+			`(app is missing, cluster="c2", want "c1")`)
+	})
+
+	t.Run("foreign object is not applied over", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		foreign := newObj(nil)
+		mock.Resources["default/cluster-config"] = foreign
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, newExecCtx())
+		require.ErrorIs(t, err, ErrForeignResource)
+		assert.Equal(t, StatusFailed, results[0].Status)
+		assert.Same(t, foreign, mock.Resources["default/cluster-config"], "the foreign object is untouched")
+	})
+
+	t.Run("new object is created", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, newExecCtx())
+		require.NoError(t, err)
+		assert.Equal(t, StatusSuccess, results[0].Status)
+		assert.Contains(t, mock.Resources, "default/cluster-config")
+	})
+}
+
+func TestResourceExecutor_RenderMaestroPlacement(t *testing.T) {
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: k8sclient.NewMockK8sClient(),
+		Logger:          logger.NewTestLogger(),
+	})
+
+	resource := configloader.Resource{
+		Name: "clusterWork",
+		Transport: &configloader.TransportConfig{
+			Client: "maestro",
+			Maestro: &configloader.MaestroTransportConfig{
+				TargetCluster: "{{ .consumer }}",
+				Placement: &configloader.MaestroPlacementConfig{
+					Labels: map[string]string{
+						"placement.example.com/region": "{{ .region }}",
+						"tier":                         "gold",
+					},
+					Annotations: map[string]string{"placement.example.com/hint": "spread-{{ .region }}"},
+				},
+			},
+		},
+		Manifest: map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata": map[string]interface{}{
+				"name":   "work-{{ .clusterId }}",
+				"labels": map[string]interface{}{"tier": "silver", "app": "agent"},
+			},
+		},
+	}
+
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["clusterId"] = "c1"
+	execCtx.Params["consumer"] = "mgmt-1"
+	execCtx.Params["region"] = "us-east-1"
+
+	data, err := re.renderToBytes(context.Background(), resource, execCtx)
+	require.NoError(t, err)
+
+	var obj unstructured.Unstructured
+	require.NoError(t, json.Unmarshal(data, &obj.Object))
+	assert.Equal(t, map[string]string{
+		"app":                          "agent",
+		"tier":                         "gold",
+		"placement.example.com/region": "us-east-1",
+	}, obj.GetLabels())
+	assert.Equal(t, "spread-us-east-1", obj.GetAnnotations()["placement.example.com/hint"])
+
+	execCtx.Params["region"] = "not a valid label value!"
+	_, err = re.renderToBytes(context.Background(), resource, execCtx)
+	assert.ErrorContains(t, err, "rendered to invalid value")
+}
+
+func TestResourceExecutor_RoutesByTransport(t *testing.T) {
+	kube, maestro := k8sclient.NewMockK8sClient(), k8sclient.NewMockK8sClient()
+	router := transportclient.NewRouter(map[string]transportclient.TransportClient{
+		configloader.TransportClientKubernetes: kube,
+		configloader.TransportClientMaestro:    maestro,
+	}, configloader.TransportClientMaestro)
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: router, Logger: logger.NewTestLogger()})
+
+	configMap := configloader.Resource{
+		Name: "app",
+		Manifest: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "app", "namespace": "default"},
+		},
+		Discovery: &configloader.DiscoveryConfig{Namespace: "default", ByName: "app"},
+	}
+	work := newSplitResource(false)
+	work.Transport.Maestro.Ordering = nil
+
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{configMap, work}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Contains(t, kube.Resources, "default/app")
+	assert.NotContains(t, kube.Resources, "cluster-1/cluster-1-work")
+	assert.Contains(t, maestro.Resources, "cluster-1/cluster-1-work")
+	assert.NotContains(t, maestro.Resources, "default/app")
+}
+
+func TestTransportTargetFor(t *testing.T) {
+	params := map[string]interface{}{"clusterId": "cluster-1", "generation": 2}
+
+	target, err := transportTargetFor(configloader.Resource{Name: "app"}, params)
+	require.NoError(t, err)
+	assert.Nil(t, target, "the kubernetes transport has no target")
+
+	target, err = transportTargetFor(configloader.Resource{
+		Name: "app",
+		Transport: &configloader.TransportConfig{
+			Client: configloader.TransportClientGitOps,
+			GitOps: &configloader.GitOpsTransportConfig{
+				Path:          "clusters/{{ .clusterId }}",
+				CommitMessage: "Reconcile {{ .clusterId }} at generation {{ .generation }}",
+			},
+		},
+	}, params)
+	require.NoError(t, err)
+	assert.Equal(t, &gitopsclient.TransportContext{
+		Path:          "clusters/cluster-1",
+		CommitMessage: "Reconcile cluster-1 at generation 2",
+	}, target)
+	assert.Equal(t, "gitops:clusters/cluster-1", transportTargetName(target))
+}
+
+func TestResourceExecutor_AnnotatesConfigFingerprint(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resource := configloader.Resource{
+		Name: "app",
+		Manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: default
+  annotations:
+    hyperfleet.io/generation: "1"
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app-credentials
+  namespace: default
+`,
+	}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.ConfigFingerprint = "adcace461a31"
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+	require.NoError(t, err)
+	assert.Equal(t, StatusSuccess, results[0].Status)
+	for _, key := range []string{"default/app", "default/app-credentials"} {
+		require.Contains(t, mock.Resources, key)
+		annotations := mock.Resources[key].GetAnnotations()
+		assert.Equal(t, "adcace461a31", annotations[constants.AnnotationConfigFingerprint], key)
+	}
+	assert.Equal(t, "1", mock.Resources["default/app"].GetAnnotations()[constants.AnnotationGeneration])
+
+	work := newSplitResource(false)
+	work.Transport.Maestro.Ordering = nil
+	data, err := re.renderToBytes(context.Background(), work, execCtx)
+	require.NoError(t, err)
+	parsed, err := manifest.ParseManifestWork(data)
+	require.NoError(t, err)
+	assert.Equal(t, "adcace461a31", parsed.Annotations[constants.AnnotationConfigFingerprint],
+		"the ManifestWork is annotated")
+	assert.NotContains(t, string(parsed.Spec.Workload.Manifests[0].Raw), constants.AnnotationConfigFingerprint,
+		"the workload manifests are left as rendered")
+}
+
+func TestResourceExecutor_RenderFeedbackRules(t *testing.T) {
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: k8sclient.NewMockK8sClient(),
+		Logger:          logger.NewTestLogger(),
+	})
+
+	resource := configloader.Resource{
+		Name: "clusterWork",
+		Transport: &configloader.TransportConfig{
+			Client: "maestro",
+			Maestro: &configloader.MaestroTransportConfig{
+				TargetCluster: "mgmt-1",
+				FeedbackRules: []configloader.ManifestFeedbackRule{
+					{Kind: "Deployment", Namespace: "{{ .clusterId }}", WellKnownStatus: true},
+					{Kind: "Namespace", JSONPaths: []configloader.FeedbackJSONPath{
+						{Name: "phase", Path: ".status.phase"},
+					}},
+				},
+			},
+		},
+		Manifest: map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "work-{{ .clusterId }}"},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []interface{}{
+						map[string]interface{}{
+							"apiVersion": "v1", "kind": "Namespace",
+							"metadata": map[string]interface{}{"name": "{{ .clusterId }}"},
+						},
+						map[string]interface{}{
+							"apiVersion": "apps/v1", "kind": "Deployment",
+							"metadata": map[string]interface{}{"name": "agent", "namespace": "{{ .clusterId }}"},
+						},
+					},
+				},
+				"manifestConfigs": []interface{}{
+					map[string]interface{}{
+						"resourceIdentifier": map[string]interface{}{
+							"group": "apps", "resource": "deployments", "name": "agent", "namespace": "c1",
+						},
+						"updateStrategy": map[string]interface{}{"type": "ServerSideApply"},
+					},
+				},
+			},
+		},
+	}
+
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["clusterId"] = "c1"
+
+	data, err := re.renderToBytes(context.Background(), resource, execCtx)
+	require.NoError(t, err)
+
+	work, err := manifest.ParseManifestWork(data)
+	require.NoError(t, err)
+	require.Len(t, work.Spec.ManifestConfigs, 2)
+
+	deployment := work.Spec.ManifestConfigs[0]
+	assert.Equal(t, workv1.ResourceIdentifier{
+		Group: "apps", Resource: "deployments", Name: "agent", Namespace: "c1",
+	}, deployment.ResourceIdentifier)
+	assert.Nil(t, deployment.UpdateStrategy, "the rule replaces the manifest's config for the same resource")
+	require.Len(t, deployment.FeedbackRules, 1)
+	assert.Equal(t, workv1.WellKnownStatusType, deployment.FeedbackRules[0].Type)
+
+	namespace := work.Spec.ManifestConfigs[1]
+	assert.Equal(t, workv1.ResourceIdentifier{Resource: "namespaces", Name: "c1"}, namespace.ResourceIdentifier)
+	require.Len(t, namespace.FeedbackRules, 1)
+	assert.Equal(t, workv1.JSONPathsType, namespace.FeedbackRules[0].Type)
+	assert.Equal(t, []workv1.JsonPath{{Name: "phase", Path: ".status.phase"}}, namespace.FeedbackRules[0].JsonPaths)
+
+	resource.Transport.Maestro.FeedbackRules[0].Name = "missing"
+	_, err = re.renderToBytes(context.Background(), resource, execCtx)
+	assert.ErrorContains(t, err, "selects no workload manifest")
+}
+
+func TestResourceExecutor_ExecuteAll_MultiDocumentManifest(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: mock,
+		Logger:          logger.NewTestLogger(),
+	})
+
+	resource := configloader.Resource{
+		Name: "app",
+		Manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "app-{{ .clusterId }}"
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: "app-{{ .clusterId }}-credentials"
+  namespace: default
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: "app-{{ .clusterId }}-sa"
+  namespace: default
+`,
+	}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["clusterId"] = "c1"
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSuccess, results[0].Status)
+	assert.Equal(t, "ConfigMap", results[0].Kind, "the first document is the resource's own object")
+	require.Len(t, mock.Resources, 3, "every document should be applied")
+	assert.Equal(t, "Secret", mock.Resources["default/app-c1-credentials"].GetKind())
+	assert.Equal(t, "ServiceAccount", mock.Resources["default/app-c1-sa"].GetKind())
+
+	t.Run("scope violation in a later document is rejected before apply", func(t *testing.T) {
+		mock := k8sclient.NewMockK8sClient()
+		re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+		resource := configloader.Resource{
+			Name: "app",
+			Manifest: `apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+---
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: app
+  namespace: default
+`,
+		}
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource},
+			NewExecutionContext(context.Background(), nil, nil))
+		require.Error(t, err)
+		assert.ErrorContains(t, results[0].Error, "manifest document 1")
+		assert.Empty(t, mock.Resources)
+	})
+}
+
+func TestResourceExecutor_RenderMultiDocumentManifestWork(t *testing.T) {
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: k8sclient.NewMockK8sClient(),
+		Logger:          logger.NewTestLogger(),
+	})
+	resource := configloader.Resource{
+		Name: "clusterWork",
+		Transport: &configloader.TransportConfig{
+			Client:  "maestro",
+			Maestro: &configloader.MaestroTransportConfig{TargetCluster: "mgmt-1"},
+		},
+		Manifest: `apiVersion: work.open-cluster-management.io/v1
+kind: ManifestWork
+metadata:
+  name: "work-{{ .clusterId }}"
+spec:
+  workload:
+    manifests:
+      - apiVersion: v1
+        kind: Namespace
+        metadata:
+          name: "{{ .clusterId }}"
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+  namespace: "{{ .clusterId }}"
+`,
+	}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["clusterId"] = "c1"
+
+	rendered, extra, err := re.renderDocuments(context.Background(), resource, execCtx)
+	require.NoError(t, err)
+	assert.Empty(t, extra)
+	work, err := manifest.ParseManifestWork(rendered)
+	require.NoError(t, err)
+	require.Len(t, work.Spec.Workload.Manifests, 2)
+	assert.Contains(t, string(work.Spec.Workload.Manifests[1].Raw), `"kind":"ConfigMap"`)
+
+	resource.Manifest = "apiVersion: v1\nkind: ConfigMap\n---\napiVersion: v1\nkind: Secret\n"
+	_, _, err = re.renderDocuments(context.Background(), resource, execCtx)
+	assert.ErrorContains(t, err, "must be a ManifestWork")
+}
+
+// concurrentApplyMock records the order in which manifests are applied. Apply of a
+// name listed in barrier blocks until every name in barrier has started, which only
+// succeeds if those resources are applied concurrently.
+type concurrentApplyMock struct {
+	*k8sclient.MockK8sClient
+	barrier map[string]bool
+	started chan struct{}
+	failOn  string
+	panicOn string
+	order   []string
+	mu      sync.Mutex
+}
+
+func (m *concurrentApplyMock) ApplyResource(
+	_ context.Context,
+	data []byte,
+	_ *transportclient.ApplyOptions,
+	_ transportclient.TransportContext,
+) (*transportclient.ApplyResult, error) {
+	var obj unstructured.Unstructured
+	if err := json.Unmarshal(data, &obj.Object); err != nil {
+		return nil, err
+	}
+	name := obj.GetName()
+	if name == m.panicOn {
+		panic("apply panicked")
+	}
+
+	if m.barrier[name] {
+		m.started <- struct{}{}
+		deadline := time.After(time.Second)
+		for waiting := true; waiting; {
+			select {
+			case <-deadline:
+				return nil, fmt.Errorf("%s: barrier timed out, resources did not run concurrently", name)
+			case <-time.After(time.Millisecond):
+				waiting = len(m.started) < len(m.barrier)
+			}
+		}
+	}
+
+	m.mu.Lock()
+	m.order = append(m.order, name)
+	m.mu.Unlock()
+	if name == m.failOn {
+		return nil, errors.New("apply failed")
+	}
+	return &transportclient.ApplyResult{Operation: manifest.OperationCreate, Reason: "mock"}, nil
+}
+
+func configMapResource(name string, parallel bool, dependsOn ...string) configloader.Resource {
+	return configloader.Resource{
+		Name:      name,
+		Parallel:  parallel,
+		DependsOn: dependsOn,
+		Manifest: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		},
+	}
+}
+
+func TestResourceExecutor_ExecuteAll_ParallelGraph(t *testing.T) {
+	mock := &concurrentApplyMock{
+		MockK8sClient: k8sclient.NewMockK8sClient(),
+		barrier:       map[string]bool{"a": true, "b": true},
+		started:       make(chan struct{}, 2),
+	}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resources := []configloader.Resource{
+		configMapResource("a", true),
+		configMapResource("b", true),
+		configMapResource("c", false, "a", "b"),
+		configMapResource("d", false), // sequential: waits for a, b and c
+	}
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+
+	results, err := re.ExecuteAll(context.Background(), resources, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	for i, r := range results {
+		assert.Equal(t, resources[i].Name, r.Name, "results are in config order")
+		assert.Equal(t, StatusSuccess, r.Status)
+	}
+	assert.ElementsMatch(t, []string{"a", "b"}, mock.order[:2])
+	assert.Equal(t, []string{"c", "d"}, mock.order[2:])
+}
+
+func TestResourceExecutor_ExecuteAll_ParallelGraphStopsOnApplyFailure(t *testing.T) {
+	mock := &concurrentApplyMock{
+		MockK8sClient: k8sclient.NewMockK8sClient(),
+		failOn:        "a",
+	}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resources := []configloader.Resource{
+		configMapResource("a", true),
+		configMapResource("b", true),
+		configMapResource("c", false, "a"),
+	}
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+
+	results, err := re.ExecuteAll(context.Background(), resources, execCtx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "apply failed")
+	require.Len(t, results, 2, "c depends on the failed resource and must not run")
+	assert.Equal(t, StatusFailed, results[0].Status)
+	assert.Equal(t, StatusSuccess, results[1].Status)
+	assert.NotContains(t, mock.order, "c")
+}
+
+func TestResourceExecutor_ExecuteAll_ParallelGraphRecoversPanic(t *testing.T) {
+	mock := &concurrentApplyMock{
+		MockK8sClient: k8sclient.NewMockK8sClient(),
+		panicOn:       "a",
+	}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resources := []configloader.Resource{
+		configMapResource("a", true),
+		configMapResource("b", true),
+	}
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+
+	results, err := re.ExecuteAll(context.Background(), resources, execCtx)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errPhasePanic)
+	require.Len(t, results, 2)
+	assert.Equal(t, StatusFailed, results[0].Status)
+	assert.Equal(t, StatusSuccess, results[1].Status)
+	require.Contains(t, execCtx.Adapter.ResourceErrors, "a")
+	assert.Contains(t, execCtx.Adapter.ResourceErrors["a"].Message, "apply panicked")
+}
+
+func TestResourceExecutor_ExecuteAll_RejectsNamespaceScopeMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest map[string]interface{}
+		wantErr  string
+	}{
+		{
+			name: "namespaced kind without namespace",
+			manifest: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "cm"},
+			},
+			wantErr: `ConfigMap "cm" is namespaced but does not set metadata.namespace`,
+		},
+		{
+			name: "cluster-scoped kind with namespace",
+			manifest: map[string]interface{}{
+				"apiVersion": "rbac.authorization.k8s.io/v1",
+				"kind":       "ClusterRole",
+				"metadata":   map[string]interface{}{"name": "role", "namespace": "{{ .namespace }}"},
+			},
+			wantErr: `ClusterRole "role" is cluster-scoped but sets namespace "default"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := k8sclient.NewMockK8sClient()
+			re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+			resource := configloader.Resource{Name: "res", Manifest: tt.manifest}
+			execCtx := NewExecutionContext(context.Background(), nil, nil)
+			execCtx.Params = map[string]interface{}{"namespace": "default"}
+
+			results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			require.Len(t, results, 1)
+			assert.Equal(t, StatusFailed, results[0].Status)
+			assert.Empty(t, mock.Resources, "no apply should be attempted")
+		})
+	}
+}
+
+func TestResourceExecutor_ServerSideApplyOptions(t *testing.T) {
+	mock := &trackingApplyMockClient{MockK8sClient: k8sclient.NewMockK8sClient()}
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: mock,
+		Logger:          logger.NewTestLogger(),
+	})
+
+	resource := newResourceWithLifecycleCreate("true")
+	resource.Lifecycle = nil
+	resource.ApplyStrategy = configloader.ApplyStrategyServerSideApply
+	resource.FieldManager = "landing-zone"
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.True(t, mock.ApplyCalled)
+	require.NotNil(t, mock.ApplyOpts)
+	assert.True(t, mock.ApplyOpts.ServerSideApply)
+	assert.Equal(t, "landing-zone", mock.ApplyOpts.FieldManager)
+	assert.False(t, mock.ApplyOpts.RecreateOnChange)
+}
+
+func TestResourceExecutor_DriftPolicy(t *testing.T) {
+	drift := []manifest.FieldChange{{Path: "data.key", Old: "edited", New: "value"}}
+	mock := k8sclient.NewMockK8sClient()
+	mock.ApplyResourceResult = &transportclient.ApplyResult{
+		Operation: manifest.OperationSkip,
+		Reason:    "generation 1 unchanged, drift detected in 1 field(s)",
+		Drift:     drift,
+	}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resource := configloader.Resource{
+		Name: "agentConfig",
+		Manifest: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "agent-config", "namespace": "default"},
+		},
+		DriftPolicy: configloader.DriftPolicyReport,
+	}
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, drift, results[0].Drift)
+	assert.Equal(t, map[string][]string{"agentConfig": {"data.key"}}, execCtx.Adapter.ResourceDrift)
+
+	adapter, ok := execCtx.GetCELVariables()["adapter"].(map[string]interface{})
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"agentConfig": []interface{}{"data.key"}}, adapter["resourceDrift"])
+}
+
+func TestResourceExecutor_Transform(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: mock,
+		Logger:          logger.NewTestLogger(),
+	})
+
+	resource := newResourceWithLifecycle("", "")
+	resource.Lifecycle = nil
+	resource.Manifest.(map[string]interface{})["data"] = map[string]interface{}{"endpoint": "https://api.example.com"}
+	resource.Transform = `{"name": metadata.name, "endpoint": data.endpoint}`
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSuccess, results[0].Status)
+	resources := execCtx.GetCELVariables()["resources"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"name": "test-cm", "endpoint": "https://api.example.com"},
+		resources["test-resource"])
+	stored, ok := execCtx.GetResource("test-resource")
+	require.True(t, ok)
+	assert.IsType(t, &unstructured.Unstructured{}, stored, "the executor should keep the discovered object")
+
+	resource.Transform = "spec.missing"
+	execCtx = NewExecutionContext(context.Background(), nil, nil)
+	results, err = re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to transform resource")
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusFailed, results[0].Status)
+	require.NotNil(t, execCtx.Adapter.ExecutionError)
+	assert.Equal(t, "test-resource", execCtx.Adapter.ExecutionError.Step)
+}
+
+func TestResourceExecutor_ForEach(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	re := newResourceExecutor(&ExecutorConfig{
+		TransportClient: mock,
+		Logger:          logger.NewTestLogger(),
+	})
+
+	resource := newResourceWithLifecycle("", "")
+	resource.Lifecycle = nil
+	resource.Name = "regionConfig"
+	resource.ForEach = "regions"
+	resource.Manifest.(map[string]interface{})["metadata"] = map[string]interface{}{
+		"name":      "cm-{{ .item }}",
+		"namespace": "default",
+	}
+	resource.Manifest.(map[string]interface{})["data"] = map[string]interface{}{"index": "{{ .index }}"}
+	resource.Discovery = &configloader.DiscoveryConfig{Namespace: "default", ByName: "cm-{{ .item }}"}
+	dependent := newResourceWithLifecycle("", "")
+	dependent.Lifecycle = nil
+	dependent.Parallel = true
+	dependent.DependsOn = []string{"regionConfig"}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["regions"] = []interface{}{"us-east", "eu-west"}
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource, dependent}, execCtx)
+
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, "regionConfig[0]", results[0].Name)
+	assert.Equal(t, "regionConfig[1]", results[1].Name)
+	assert.Contains(t, mock.Resources, "default/cm-us-east")
+	assert.Contains(t, mock.Resources, "default/cm-eu-west")
+	assert.Equal(t, "1", mock.Resources["default/cm-eu-west"].Object["data"].(map[string]interface{})["index"])
+
+	resources := execCtx.GetCELVariables()["resources"].(map[string]interface{})
+	items, ok := resources["regionConfig"].([]interface{})
+	require.True(t, ok, "resources.regionConfig should be the list of the item objects")
+	require.Len(t, items, 2)
+	assert.Equal(t, "cm-us-east", items[0].(map[string]interface{})["metadata"].(map[string]interface{})["name"])
+
+	t.Run("empty list applies nothing", func(t *testing.T) {
+		execCtx := NewExecutionContext(context.Background(), nil, nil)
+		execCtx.Params["regions"] = []interface{}{}
+		results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+		require.NoError(t, err)
+		assert.Empty(t, results)
+		resources := execCtx.GetCELVariables()["resources"].(map[string]interface{})
+		assert.Equal(t, []interface{}{}, resources["regionConfig"])
+	})
+
+	t.Run("items beyond limits.max_steps fail the resource", func(t *testing.T) {
+		config := &configloader.Config{
+			Limits:    configloader.LimitsConfig{MaxSteps: 2},
+			Resources: []configloader.Resource{resource},
+		}
+		execCtx := NewExecutionContext(context.Background(), nil, config)
+		execCtx.Params["regions"] = []interface{}{"us-east", "eu-west", "ap-south"}
+		before := len(mock.Resources)
+		_, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "limits.max_steps")
+		assert.Len(t, mock.Resources, before, "nothing should be applied")
+	})
+
+	t.Run("items beyond limits.max_for_each_items fail the resource", func(t *testing.T) {
+		config := &configloader.Config{
+			Limits:    configloader.LimitsConfig{MaxSteps: -1, MaxForEachItems: 2},
+			Resources: []configloader.Resource{resource},
+		}
+		execCtx := NewExecutionContext(context.Background(), nil, config)
+		execCtx.Params["regions"] = []interface{}{"us-east", "eu-west", "ap-south"}
+		before := len(mock.Resources)
+		_, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(),
+			`for_each of step "regionConfig" has 3 items, limit (limits.max_for_each_items) is 2`)
+		assert.Len(t, mock.Resources, before, "nothing should be applied")
+	})
+
+	t.Run("not a list", func(t *testing.T) {
+		execCtx := NewExecutionContext(context.Background(), nil, nil)
+		execCtx.Params["regions"] = "us-east"
+		_, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to evaluate for_each")
+		require.NotNil(t, execCtx.Adapter.ExecutionError)
+		assert.Equal(t, "regionConfig", execCtx.Adapter.ExecutionError.Step)
+	})
+}
@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// ResultEventType is the CloudEvent type used for published execution result summaries
+const ResultEventType = "com.redhat.hyperfleet.adapter.execution.result"
+
+// ResultPublisher publishes CloudEvents to a broker topic.
+// broker.Publisher from hyperfleet-broker satisfies this interface.
+type ResultPublisher interface {
+	Publish(ctx context.Context, topic string, evt *event.Event) error
+}
+
+// ExecutionSummary is the data payload of a published execution result event.
+// It carries outcomes only; params, captured fields and API responses are omitted
+// so that no task-specific or sensitive data leaves the adapter.
+type ExecutionSummary struct {
+	Resource          *ExecutionSummaryRef    `json:"resource,omitempty"`
+	Adapter           string                  `json:"adapter"`
+	EventID           string                  `json:"event_id"`
+	EventType         string                  `json:"event_type"`
+	Status            string                  `json:"status"`
+	Phase             string                  `json:"phase"`
+	SkipReason        string                  `json:"skip_reason,omitempty"`
+	Resources         []ExecutionSummaryEntry `json:"resources,omitempty"`
+	PostActions       []ExecutionSummaryEntry `json:"post_actions,omitempty"`
+	Warnings          []ExecutionWarning      `json:"warnings,omitempty"`
+	ConfigHash        string                  `json:"config_hash,omitempty"`
+	ConfigFingerprint string                  `json:"config_fingerprint,omitempty"`
+	Errors            ExecutionErrors         `json:"errors,omitempty"`
+	Generation        int64                   `json:"generation,omitempty"`
+	ResourcesSkipped  bool                    `json:"resources_skipped"`
+}
+
+// ExecutionSummaryRef identifies the HyperFleet resource the event was about
+type ExecutionSummaryRef struct {
+	ID   string `json:"id,omitempty"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// ExecutionSummaryEntry is the outcome of a single resource or post-action
+type ExecutionSummaryEntry struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Operation string `json:"operation,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// buildResultEvent builds the CloudEvent published for an execution result
+func buildResultEvent(
+	adapterName string,
+	evt *event.Event,
+	eventData *EventData,
+	result *ExecutionResult,
+) (*event.Event, error) {
+	out := event.New()
+	out.SetID(uuid.NewString())
+	out.SetType(ResultEventType)
+	out.SetSource(fmt.Sprintf("hyperfleet-adapter/%s", adapterName))
+	out.SetSubject(evt.ID())
+	out.SetTime(time.Now())
+	if err := out.SetData(event.ApplicationJSON, buildExecutionSummary(adapterName, evt, eventData, result)); err != nil {
+		return nil, fmt.Errorf("failed to encode execution summary: %w", err)
+	}
+	return &out, nil
+}
+
+// buildExecutionSummary summarizes the outcome of executing evt
+func buildExecutionSummary(
+	adapterName string,
+	evt *event.Event,
+	eventData *EventData,
+	result *ExecutionResult,
+) ExecutionSummary {
+	summary := ExecutionSummary{
+		Adapter:           adapterName,
+		EventID:           evt.ID(),
+		EventType:         evt.Type(),
+		Status:            string(result.Status),
+		Phase:             string(result.CurrentPhase),
+		SkipReason:        result.SkipReason,
+		ResourcesSkipped:  result.ResourcesSkipped,
+		Errors:            result.Errors,
+		Warnings:          result.Warnings,
+		ConfigHash:        result.ConfigHash,
+		ConfigFingerprint: result.ConfigFingerprint,
+	}
+
+	if eventData != nil && eventData.ID != "" {
+		summary.Resource = &ExecutionSummaryRef{ID: eventData.ID, Kind: eventData.Kind}
+		summary.Generation = eventData.Generation
+	}
+
+	for _, r := range result.ResourceResults {
+		entry := ExecutionSummaryEntry{Name: r.Name, Status: string(r.Status), Operation: string(r.Operation)}
+		if r.Error != nil {
+			entry.Error = r.Error.Error()
+		}
+		summary.Resources = append(summary.Resources, entry)
+	}
+
+	for _, pa := range result.PostActionResults {
+		entry := ExecutionSummaryEntry{Name: pa.Name, Status: string(pa.Status)}
+		if pa.Error != nil {
+			entry.Error = pa.Error.Error()
+		}
+		summary.PostActions = append(summary.PostActions, entry)
+	}
+	return summary
+}
+
+// publishResult publishes the execution summary when a result publisher is configured.
+// Publishing is best-effort: failures are logged and never change the execution outcome.
+func (e *Executor) publishResult(ctx context.Context, evt *event.Event, result *ExecutionResult) {
+	if e.config.ResultPublisher == nil || e.config.ResultTopic == "" || result == nil {
+		return
+	}
+
+	_, parsed := withParsedEvent(ctx, evt)
+	resultEvt, err := buildResultEvent(e.Config().Adapter.Name, evt, parsed.data, result)
+	if err == nil {
+		err = e.config.ResultPublisher.Publish(ctx, e.config.ResultTopic, resultEvt)
+	}
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		e.log.Warnf(errCtx, "Failed to publish execution result to topic %s", e.config.ResultTopic)
+		return
+	}
+	e.log.Debugf(ctx, "Published execution result to topic %s", e.config.ResultTopic)
+}
@@ -0,0 +1,138 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// recordingPublisher records published events for assertions
+type recordingPublisher struct {
+	err    error
+	topics []string
+	events []*event.Event
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, topic string, evt *event.Event) error {
+	p.topics = append(p.topics, topic)
+	p.events = append(p.events, evt)
+	return p.err
+}
+
+func newPublisherTestEvent(t *testing.T) *event.Event {
+	t.Helper()
+	evt := event.New()
+	evt.SetID("evt-1")
+	evt.SetType("com.redhat.hyperfleet.cluster.provision")
+	evt.SetSource("test")
+	require.NoError(t, evt.SetData(event.ApplicationJSON, map[string]interface{}{
+		"id":         "cluster-1",
+		"kind":       "Cluster",
+		"generation": 3,
+	}))
+	return &evt
+}
+
+func TestBuildResultEvent(t *testing.T) {
+	result := &ExecutionResult{
+		Status:       StatusFailed,
+		CurrentPhase: PhasePostActions,
+		Errors:       ExecutionErrors{newPhaseError(PhaseResources, errors.New("apply failed"))},
+		ResourceResults: []ResourceResult{
+			{Name: "ns", Status: StatusSuccess, Operation: manifest.OperationCreate},
+			{Name: "cm", Status: StatusFailed, Error: errors.New("apply failed")},
+		},
+		PostActionResults: []PostActionResult{{Name: "report", Status: StatusSuccess}},
+		Warnings: []ExecutionWarning{
+			{Phase: PhasePreconditions, Step: "check", Message: "failed to capture 'phase'"},
+		},
+		ConfigHash:        "0123456789ab",
+		ConfigFingerprint: "ba9876543210",
+		Params:            map[string]interface{}{"secret": "should-not-leak"},
+	}
+
+	evt := newPublisherTestEvent(t)
+	eventData, _, err := ParseEventData(evt)
+	require.NoError(t, err)
+	out, err := buildResultEvent("test-adapter", evt, eventData, result)
+	require.NoError(t, err)
+	assert.Equal(t, ResultEventType, out.Type())
+	assert.Equal(t, "hyperfleet-adapter/test-adapter", out.Source())
+	assert.Equal(t, "evt-1", out.Subject())
+	require.NoError(t, out.Validate())
+
+	var summary ExecutionSummary
+	require.NoError(t, out.DataAs(&summary))
+	assert.Equal(t, "test-adapter", summary.Adapter)
+	assert.Equal(t, "failed", summary.Status)
+	assert.Equal(t, string(PhasePostActions), summary.Phase)
+	assert.Equal(t, &ExecutionSummaryRef{ID: "cluster-1", Kind: "Cluster"}, summary.Resource)
+	assert.Equal(t, int64(3), summary.Generation)
+	require.Len(t, summary.Errors, 1)
+	assert.Equal(t, PhaseResources, summary.Errors[0].Phase)
+	assert.Equal(t, "apply failed", summary.Errors[0].Message)
+	require.Len(t, summary.Resources, 2)
+	assert.Equal(t, "create", summary.Resources[0].Operation)
+	assert.Equal(t, "apply failed", summary.Resources[1].Error)
+	require.Len(t, summary.PostActions, 1)
+	assert.Equal(t, result.Warnings, summary.Warnings)
+	assert.Equal(t, "0123456789ab", summary.ConfigHash)
+	assert.Equal(t, "ba9876543210", summary.ConfigFingerprint)
+	assert.NotContains(t, string(out.Data()), "should-not-leak")
+}
+
+func TestCreateHandler_PublishesResult(t *testing.T) {
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+	}
+
+	newExecutor := func(pub ResultPublisher) *Executor {
+		exec, err := NewBuilder().
+			WithConfig(config).
+			WithAPIClient(newMockAPIClient()).
+			WithTransportClient(k8sclient.NewMockK8sClient()).
+			WithLogger(logger.NewTestLogger()).
+			WithResultPublisher(pub, "adapter-results").
+			Build()
+		require.NoError(t, err)
+		return exec
+	}
+
+	t.Run("publishes summary to topic", func(t *testing.T) {
+		pub := &recordingPublisher{}
+		result, err := newExecutor(pub).CreateHandler()(context.Background(), newPublisherTestEvent(t))
+		require.NoError(t, err)
+		assert.Equal(t, StatusSuccess, result.Status)
+		assert.Equal(t, []string{"adapter-results"}, pub.topics)
+		require.Len(t, pub.events, 1)
+		assert.Equal(t, ResultEventType, pub.events[0].Type())
+	})
+
+	t.Run("publish failure does not affect result", func(t *testing.T) {
+		pub := &recordingPublisher{err: errors.New("broker down")}
+		result, err := newExecutor(pub).CreateHandler()(context.Background(), newPublisherTestEvent(t))
+		require.NoError(t, err)
+		assert.Equal(t, StatusSuccess, result.Status)
+		assert.Len(t, pub.events, 1)
+	})
+
+	t.Run("publisher without topic is rejected", func(t *testing.T) {
+		_, err := NewBuilder().
+			WithConfig(config).
+			WithAPIClient(newMockAPIClient()).
+			WithTransportClient(k8sclient.NewMockK8sClient()).
+			WithLogger(logger.NewTestLogger()).
+			WithResultPublisher(&recordingPublisher{}, "").
+			Build()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,80 @@
+package executor
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// Defaults for unset RetryPolicy delays
+const (
+	defaultRetryBaseDelay = 1 * time.Second
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// withRetry runs attempt until it succeeds or policy gives up, and returns the last
+// result. A nil policy runs attempt once. Only errors matched by policy.RetryOn are
+// retried; the wait between attempts stops early when ctx is done.
+func withRetry[T any](
+	ctx context.Context,
+	log logger.Logger,
+	policy *configloader.RetryPolicy,
+	phase ExecutionPhase,
+	step string,
+	attempt func() (T, error),
+) (T, error) {
+	result, err := attempt()
+	if policy == nil {
+		return result, err
+	}
+	for n := 1; err != nil && n < policy.Attempts && shouldRetry(policy, phase, err); n++ {
+		delay := retryDelay(policy, n)
+		errCtx := logger.WithErrorField(ctx, err)
+		log.Warnf(errCtx, "%s[%s] attempt %d/%d failed, retrying in %s", phase, step, n, policy.Attempts, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return result, err
+		case <-timer.C:
+		}
+		result, err = attempt()
+	}
+	return result, err
+}
+
+// shouldRetry reports whether err matches one of the policy's retry_on entries
+func shouldRetry(policy *configloader.RetryPolicy, phase ExecutionPhase, err error) bool {
+	retryOn := policy.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = []string{configloader.RetryOnRetryable}
+	}
+	category, retryable := classifyError(phase, err)
+	if retryable && slices.Contains(retryOn, configloader.RetryOnRetryable) {
+		return true
+	}
+	return slices.Contains(retryOn, string(category))
+}
+
+// retryDelay returns the delay before retry n (1-based), capped at max_delay
+func retryDelay(policy *configloader.RetryPolicy, n int) time.Duration {
+	base := policy.BaseDelay.OrDefault(defaultRetryBaseDelay)
+	maxDelay := policy.MaxDelay.OrDefault(defaultRetryMaxDelay)
+
+	delay := base
+	switch hyperfleetapi.BackoffStrategy(policy.Backoff) {
+	case hyperfleetapi.BackoffConstant:
+	case hyperfleetapi.BackoffLinear:
+		delay = base * time.Duration(n)
+	default:
+		for i := 1; i < n && delay < maxDelay; i++ {
+			delay *= 2
+		}
+	}
+	return min(delay, maxDelay)
+}
@@ -0,0 +1,139 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	apierrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetry(t *testing.T) {
+	unavailable := apierrors.NewAPIError(http.MethodPost, "/x", http.StatusServiceUnavailable, "503", nil, 1, 0, nil)
+	badRequest := apierrors.NewAPIError(http.MethodPost, "/x", http.StatusBadRequest, "400", nil, 1, 0, nil)
+	policy := func(attempts int, retryOn ...string) *configloader.RetryPolicy {
+		return &configloader.RetryPolicy{
+			Attempts: attempts, BaseDelay: configloader.Duration(time.Millisecond), RetryOn: retryOn,
+		}
+	}
+
+	tests := []struct {
+		policy    *configloader.RetryPolicy
+		err       error
+		name      string
+		succeedAt int
+		wantCalls int
+	}{
+		{name: "nil policy runs once", err: unavailable, wantCalls: 1},
+		{name: "retryable error until success", policy: policy(3), err: unavailable, succeedAt: 2, wantCalls: 2},
+		{name: "gives up after attempts", policy: policy(3), err: unavailable, wantCalls: 3},
+		{name: "non-retryable error by default", policy: policy(3), err: badRequest, wantCalls: 1},
+		{name: "category match retries non-retryable error", policy: policy(2, configloader.RetryOnAPI),
+			err: badRequest, wantCalls: 2},
+		{name: "unmatched category", policy: policy(3, configloader.RetryOnTransport), err: unavailable, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			_, err := withRetry(context.Background(), logger.NewTestLogger(), tt.policy, PhasePostActions, "step",
+				func() (int, error) {
+					calls++
+					if calls == tt.succeedAt {
+						return calls, nil
+					}
+					return calls, tt.err
+				})
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.succeedAt > 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestWithRetry_StopsWhenContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	calls := 0
+	policy := &configloader.RetryPolicy{
+		Attempts: 5, BaseDelay: configloader.Duration(time.Hour), RetryOn: []string{configloader.RetryOnTimeout},
+	}
+	_, err := withRetry(ctx, logger.NewTestLogger(), policy, PhaseResources, "step", func() (int, error) {
+		calls++
+		return 0, context.DeadlineExceeded
+	})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryDelay(t *testing.T) {
+	exponential := &configloader.RetryPolicy{
+		BaseDelay: configloader.Duration(time.Second), MaxDelay: configloader.Duration(5 * time.Second),
+	}
+	assert.Equal(t, time.Second, retryDelay(exponential, 1))
+	assert.Equal(t, 2*time.Second, retryDelay(exponential, 2))
+	assert.Equal(t, 4*time.Second, retryDelay(exponential, 3))
+	assert.Equal(t, 5*time.Second, retryDelay(exponential, 4))
+
+	linear := &configloader.RetryPolicy{Backoff: "linear", BaseDelay: configloader.Duration(2 * time.Second)}
+	assert.Equal(t, 6*time.Second, retryDelay(linear, 3))
+
+	constant := &configloader.RetryPolicy{Backoff: "constant"}
+	assert.Equal(t, defaultRetryBaseDelay, retryDelay(constant, 4))
+}
+
+// flakyApplyMock fails the first failures applies with err
+type flakyApplyMock struct {
+	*k8sclient.MockK8sClient
+	err      error
+	failures int
+	calls    int
+}
+
+func (m *flakyApplyMock) ApplyResource(
+	_ context.Context,
+	_ []byte,
+	_ *transportclient.ApplyOptions,
+	_ transportclient.TransportContext,
+) (*transportclient.ApplyResult, error) {
+	m.calls++
+	if m.calls <= m.failures {
+		return nil, m.err
+	}
+	return &transportclient.ApplyResult{Operation: manifest.OperationCreate, Reason: "mock"}, nil
+}
+
+func TestResourceExecutor_RetryClearsRecordedError(t *testing.T) {
+	mock := &flakyApplyMock{
+		MockK8sClient: k8sclient.NewMockK8sClient(),
+		err: &apierrors.K8sOperationError{
+			Operation: "create", Kind: "ConfigMap", Resource: "cm", Err: errors.New("conflict"),
+		},
+		failures: 1,
+	}
+	re := newResourceExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	resource := configMapResource("cm", false)
+	resource.Retry = &configloader.RetryPolicy{
+		Attempts: 2, BaseDelay: configloader.Duration(time.Millisecond), RetryOn: []string{configloader.RetryOnTransport},
+	}
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+
+	results, err := re.ExecuteAll(context.Background(), []configloader.Resource{resource}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSuccess, results[0].Status)
+	assert.Equal(t, 2, mock.calls)
+	assert.Nil(t, execCtx.Adapter.ExecutionError, "error from the failed attempt is cleared")
+}
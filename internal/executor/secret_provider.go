@@ -0,0 +1,164 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+)
+
+// SecretProvider resolves the values of vault.<path>#<key> param sources
+type SecretProvider interface {
+	// GetSecret returns the value stored under key in the secret at path
+	GetSecret(ctx context.Context, path, key string) (interface{}, error)
+}
+
+const (
+	defaultVaultTimeout   = 10 * time.Second
+	defaultVaultKVVersion = 2
+	// maxVaultResponseSize is a defensive cap on Vault response bodies (1 MB)
+	maxVaultResponseSize = 1 << 20
+)
+
+// VaultSecretProvider reads secrets from the KV secrets engine of HashiCorp Vault.
+// The first segment of a secret path is the engine mount, e.g. secret/clusters/creds.
+type VaultSecretProvider struct {
+	httpClient *http.Client
+	address    string
+	tokenPath  string
+	namespace  string
+	kvVersion  int
+}
+
+// NewVaultSecretProvider creates a Vault secret provider from clients.vault
+func NewVaultSecretProvider(config *configloader.VaultClientConfig) (*VaultSecretProvider, error) {
+	if config == nil {
+		return nil, fmt.Errorf("vault config is required")
+	}
+	timeout := config.Timeout.OrDefault(defaultVaultTimeout)
+	defaultTransport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("http.DefaultTransport is not *http.Transport")
+	}
+	transport := defaultTransport.Clone()
+	if config.CAFile != "" {
+		caPEM, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vault CA file %s: %w", config.CAFile, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("vault CA file %s contains no certificates", config.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	kvVersion := config.KVVersion
+	if kvVersion == 0 {
+		kvVersion = defaultVaultKVVersion
+	}
+	return &VaultSecretProvider{
+		httpClient: &http.Client{Timeout: timeout, Transport: transport},
+		address:    strings.TrimRight(config.Address, "/"),
+		tokenPath:  config.TokenPath,
+		namespace:  config.Namespace,
+		kvVersion:  kvVersion,
+	}, nil
+}
+
+// GetSecret implements SecretProvider. The token file is read on every call so a
+// token renewed by a Vault Agent is picked up without a restart.
+func (p *VaultSecretProvider) GetSecret(ctx context.Context, path, key string) (interface{}, error) {
+	mount, secretPath, ok := strings.Cut(strings.Trim(path, "/"), "/")
+	if !ok || secretPath == "" {
+		return nil, fmt.Errorf("vault path %q must be <mount>/<secret path>", path)
+	}
+	endpoint := fmt.Sprintf("%s/v1/%s/%s", p.address, url.PathEscape(mount), escapeVaultPath(secretPath))
+	if p.kvVersion == 2 {
+		endpoint = fmt.Sprintf("%s/v1/%s/data/%s", p.address, url.PathEscape(mount), escapeVaultPath(secretPath))
+	}
+
+	raw, err := os.ReadFile(p.tokenPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading vault token file %s: %w", p.tokenPath, err)
+	}
+	token := strings.TrimSpace(string(raw))
+	if token == "" {
+		return nil, fmt.Errorf("vault token file %s is empty", p.tokenPath)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault request for %s failed: %w", path, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close after reading the body
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxVaultResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vault response for %s: %w", path, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("vault secret %s not found", path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault returned %d for %s%s", resp.StatusCode, path, vaultErrors(body))
+	}
+
+	var envelope struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to parse vault response for %s: %w", path, err)
+	}
+	data := envelope.Data
+	if p.kvVersion == 2 {
+		// KV v2 nests the secret under data.data, next to data.metadata
+		nested, isMap := envelope.Data["data"].(map[string]interface{})
+		if !isMap {
+			return nil, fmt.Errorf("vault secret %s has no KV v2 data", path)
+		}
+		data = nested
+	}
+	value, ok := data[key]
+	if !ok {
+		return nil, fmt.Errorf("vault secret %s has no key %q", path, key)
+	}
+	return value, nil
+}
+
+// escapeVaultPath escapes each segment of a secret path
+func escapeVaultPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// vaultErrors formats the errors field of a Vault error response, if any
+func vaultErrors(body []byte) string {
+	var resp struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Errors) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(resp.Errors, "; ")
+}
@@ -0,0 +1,168 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// staticSecretProvider serves secrets from a map keyed by "path#key"
+type staticSecretProvider map[string]interface{}
+
+func (p staticSecretProvider) GetSecret(_ context.Context, path, key string) (interface{}, error) {
+	value, ok := p[path+"#"+key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return value, nil
+}
+
+func writeVaultToken(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("s.test-token\n"), 0o600))
+	return path
+}
+
+func TestVaultSecretProvider_GetSecret(t *testing.T) {
+	var gotPath, gotToken, gotNamespace string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotToken = r.Header.Get("X-Vault-Token")
+		gotNamespace = r.Header.Get("X-Vault-Namespace")
+		switch r.URL.Path {
+		case "/v1/secret/data/clusters/creds":
+			_, _ = w.Write([]byte(`{"data": {"data": {"password": "hunter2", "port": 5432}, "metadata": {"version": 3}}}`))
+		case "/v1/kv/clusters/creds":
+			_, _ = w.Write([]byte(`{"data": {"password": "v1-secret"}}`))
+		case "/v1/secret/data/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": ["permission denied"]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": []}`))
+		}
+	}))
+	defer server.Close()
+
+	tokenPath := writeVaultToken(t)
+	newProvider := func(kvVersion int) *VaultSecretProvider {
+		provider, err := NewVaultSecretProvider(&configloader.VaultClientConfig{
+			Address:   server.URL + "/",
+			TokenPath: tokenPath,
+			Namespace: "team-a",
+			KVVersion: kvVersion,
+		})
+		require.NoError(t, err)
+		return provider
+	}
+
+	t.Run("kv v2", func(t *testing.T) {
+		value, err := newProvider(0).GetSecret(context.Background(), "secret/clusters/creds", "password")
+		require.NoError(t, err)
+		assert.Equal(t, "hunter2", value)
+		assert.Equal(t, "/v1/secret/data/clusters/creds", gotPath)
+		assert.Equal(t, "s.test-token", gotToken)
+		assert.Equal(t, "team-a", gotNamespace)
+	})
+
+	t.Run("non-string value", func(t *testing.T) {
+		value, err := newProvider(2).GetSecret(context.Background(), "secret/clusters/creds", "port")
+		require.NoError(t, err)
+		assert.Equal(t, float64(5432), value)
+	})
+
+	t.Run("kv v1", func(t *testing.T) {
+		value, err := newProvider(1).GetSecret(context.Background(), "kv/clusters/creds", "password")
+		require.NoError(t, err)
+		assert.Equal(t, "v1-secret", value)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := newProvider(2).GetSecret(context.Background(), "secret/clusters/creds", "username")
+		assert.ErrorContains(t, err, `has no key "username"`)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := newProvider(2).GetSecret(context.Background(), "secret/missing", "password")
+		assert.ErrorContains(t, err, "vault secret secret/missing not found")
+	})
+
+	t.Run("vault errors are reported", func(t *testing.T) {
+		_, err := newProvider(2).GetSecret(context.Background(), "secret/forbidden", "password")
+		assert.ErrorContains(t, err, "vault returned 403 for secret/forbidden: permission denied")
+	})
+
+	t.Run("path without mount", func(t *testing.T) {
+		_, err := newProvider(2).GetSecret(context.Background(), "creds", "password")
+		assert.ErrorContains(t, err, "must be <mount>/<secret path>")
+	})
+}
+
+func TestVaultSecretProvider_TokenFile(t *testing.T) {
+	provider, err := NewVaultSecretProvider(&configloader.VaultClientConfig{
+		Address:   "http://127.0.0.1:1",
+		TokenPath: filepath.Join(t.TempDir(), "missing"),
+	})
+	require.NoError(t, err)
+	_, err = provider.GetSecret(context.Background(), "secret/creds", "password")
+	assert.ErrorContains(t, err, "reading vault token file")
+}
+
+func TestExtractConfigParams_VaultSource(t *testing.T) {
+	config := &configloader.Config{
+		Params: []configloader.Parameter{
+			{Name: "dbPassword", Source: configloader.StringSource("vault.secret/clusters/creds#password"), Required: true},
+			{Name: "dbPort", Source: configloader.StringSource("vault.secret/clusters/creds#port"), Type: "int"},
+		},
+	}
+	secrets := staticSecretProvider{
+		"secret/clusters/creds#password": "hunter2",
+		"secret/clusters/creds#port":     "5432",
+	}
+
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, config)
+	err := extractConfigParams(context.Background(), config, execCtx, nil, nil, secrets, logger.NewTestLogger())
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", execCtx.Params["dbPassword"])
+	assert.Equal(t, int64(5432), execCtx.Params["dbPort"])
+
+	t.Run("lookup failure fails a required param", func(t *testing.T) {
+		execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, config)
+		err := extractConfigParams(context.Background(), config, execCtx, nil, nil,
+			staticSecretProvider{}, logger.NewTestLogger())
+		assert.ErrorContains(t, err, "failed to extract required parameter 'dbPassword'")
+	})
+}
+
+func TestNewExecutor_RequiresSecretProviderForVaultSources(t *testing.T) {
+	newBuilder := func() *ExecutorBuilder {
+		return NewBuilder().
+			WithConfig(&configloader.Config{
+				Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+				Params: []configloader.Parameter{
+					{Name: "dbPassword", Source: configloader.StringSource("vault.secret/clusters/creds#password")},
+				},
+			}).
+			WithAPIClient(newMockAPIClient()).
+			WithTransportClient(k8sclient.NewMockK8sClient()).
+			WithLogger(logger.NewTestLogger())
+	}
+
+	_, err := newBuilder().Build()
+	assert.ErrorContains(t, err, "vault sources require clients.vault")
+
+	_, err = newBuilder().WithSecretProvider(staticSecretProvider{}).Build()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stepTracerName is the instrumentation scope of the step spans
+const stepTracerName = "executor"
+
+// stepSpan is the span of one step, along with the tracking of its progress
+type stepSpan struct {
+	trace.Span
+	progress *stepProgress
+}
+
+// startStepSpan starts a child span of the event span for one step, named after the
+// step type and name, adds its span_id to the logger context and starts tracking its
+// progress.
+func startStepSpan(ctx context.Context, stepType, name string) (context.Context, *stepSpan) {
+	ctx, span := otel.Tracer(stepTracerName).Start(ctx, stepType+" "+name,
+		trace.WithAttributes(
+			attribute.String("step.name", name),
+			attribute.String("step.type", stepType),
+		))
+	ctx, progress := startStepProgress(logger.WithOTelTraceContext(ctx), stepType, name)
+	return ctx, &stepSpan{Span: span, progress: progress}
+}
+
+// endStepSpan records the outcome of a step on its span and ends it. status is one of
+// the metrics.StepStatus* values; reason explains a skipped or unmet step.
+func endStepSpan(span *stepSpan, status, reason string, err error) {
+	span.progress.end()
+	span.SetAttributes(
+		attribute.String("step.status", status),
+		attribute.Bool("step.skipped", status == metrics.StepStatusSkipped),
+	)
+	if reason != "" {
+		span.SetAttributes(attribute.String("step.skip_reason", reason))
+	}
+	if err != nil {
+		span.SetAttributes(attribute.String("step.error_reason", err.Error()))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// endTracedExecution records the outcome of an execution on the event span
+func endTracedExecution(span trace.Span, result *ExecutionResult) {
+	span.SetAttributes(
+		attribute.String("execution.status", string(result.Status)),
+		attribute.Bool("execution.resources_skipped", result.ResourcesSkipped),
+		attribute.String("execution.config_hash", result.ConfigHash),
+		attribute.String("execution.config_fingerprint", result.ConfigFingerprint),
+	)
+	if result.SkipReason != "" {
+		span.SetAttributes(attribute.String("execution.skip_reason", result.SkipReason))
+	}
+	if result.Status == StatusFailed {
+		span.SetStatus(codes.Error, result.Errors.String())
+	}
+}
+
+// stepStatus returns the step status of a precondition: not_met when it ran but its
+// conditions did not match
+func (r PreconditionResult) stepStatus() string {
+	if r.Status == StatusSuccess && !r.Matched {
+		return metrics.StepStatusNotMet
+	}
+	return string(r.Status)
+}
+
+// stepType returns the step type of a resource result: prune for the resources deleted
+// by a prune step
+func (r ResourceResult) stepType() string {
+	if r.OperationReason == pruneReason {
+		return metrics.StepTypePrune
+	}
+	return metrics.StepTypeResource
+}
+
+// stepStatus returns the step status of a resource: skipped when it was left unchanged
+func (r ResourceResult) stepStatus() string {
+	if r.Status == StatusSuccess && r.Operation == manifest.OperationSkip {
+		return metrics.StepStatusSkipped
+	}
+	return string(r.Status)
+}
+
+// stepStatus returns the step status of a post action: skipped when its when condition
+// did not match
+func (r PostActionResult) stepStatus() string {
+	if r.Skipped {
+		return metrics.StepStatusSkipped
+	}
+	return string(r.Status)
+}
+
+// skipReason returns the skip reason of a precondition: filtered when its conditions did
+// not match, else empty
+func (r PreconditionResult) skipReason() string {
+	if r.stepStatus() == metrics.StepStatusNotMet {
+		return metrics.SkipReasonFiltered
+	}
+	return ""
+}
+
+// skipReason returns the skip reason of a resource: when_false when lifecycle.create.when
+// was false and generation_unchanged when it was left unchanged, else empty
+func (r ResourceResult) skipReason() string {
+	switch {
+	case r.Status == StatusSkipped:
+		return metrics.SkipReasonWhenFalse
+	case r.stepStatus() == metrics.StepStatusSkipped:
+		return metrics.SkipReasonGenerationUnchanged
+	default:
+		return ""
+	}
+}
+
+// skipReason returns the skip reason of a post action: when_false when it, or a payload
+// it references, was skipped by a when condition, else empty
+func (r PostActionResult) skipReason() string {
+	if r.Skipped {
+		return metrics.SkipReasonWhenFalse
+	}
+	return ""
+}
+
+// changedPaths returns the field paths of changes. Spans carry the paths only: values can
+// be large and stay in the debug log and the execution result.
+func changedPaths(changes []manifest.FieldChange) []string {
+	paths := make([]string, len(changes))
+	for i, c := range changes {
+		paths[i] = c.Path
+	}
+	return paths
+}
@@ -0,0 +1,94 @@
+package executor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// recordSpans installs a global tracer provider recording the ended spans for the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestExecute_StepSpans(t *testing.T) {
+	recorder := recordSpans(t)
+
+	config := &configloader.Config{
+		Adapter: configloader.AdapterInfo{Name: "test-adapter", Version: "1.0.0"},
+		Preconditions: []configloader.Precondition{
+			{ActionBase: configloader.ActionBase{Name: "precond1"}, Expression: "true"},
+			{ActionBase: configloader.ActionBase{Name: "precond2"}, Expression: "false"},
+		},
+	}
+	exec, err := NewBuilder().
+		WithConfig(config).
+		WithAPIClient(newMockAPIClient()).
+		WithTransportClient(k8sclient.NewMockK8sClient()).
+		WithLogger(logger.NewTestLogger()).
+		Build()
+	require.NoError(t, err)
+
+	result := exec.Execute(context.Background(), map[string]interface{}{})
+	require.Equal(t, StatusSuccess, result.Status)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	execute := spans["Execute"]
+	require.NotNil(t, execute, "the event should have a span")
+	assert.True(t, spanAttributes(execute)["execution.resources_skipped"].AsBool())
+
+	met := spans["precondition precond1"]
+	require.NotNil(t, met, "each step should have a span")
+	assert.Equal(t, execute.SpanContext().SpanID(), met.Parent().SpanID(), "step spans are children of the event span")
+	attrs := spanAttributes(met)
+	assert.Equal(t, "precond1", attrs["step.name"].AsString())
+	assert.Equal(t, "precondition", attrs["step.type"].AsString())
+	assert.Equal(t, "success", attrs["step.status"].AsString())
+	assert.False(t, attrs["step.skipped"].AsBool())
+
+	notMet := spans["precondition precond2"]
+	require.NotNil(t, notMet)
+	attrs = spanAttributes(notMet)
+	assert.Equal(t, "not_met", attrs["step.status"].AsString())
+	assert.NotEmpty(t, attrs["step.skip_reason"].AsString())
+	assert.Equal(t, codes.Unset, notMet.Status().Code, "an unmet precondition is not an error")
+}
+
+func TestEndStepSpan_Error(t *testing.T) {
+	recorder := recordSpans(t)
+
+	_, span := startStepSpan(context.Background(), "resource", "namespace")
+	endStepSpan(span, string(StatusFailed), "", assert.AnError)
+
+	require.Len(t, recorder.Ended(), 1)
+	ended := recorder.Ended()[0]
+	assert.Equal(t, codes.Error, ended.Status().Code)
+	assert.Equal(t, assert.AnError.Error(), spanAttributes(ended)["step.error_reason"].AsString())
+}
@@ -2,7 +2,10 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
@@ -69,25 +72,44 @@ type ExecutorConfig struct {
 	Logger logger.Logger
 	// MetricsRecorder is the optional Prometheus metrics recorder
 	MetricsRecorder *metrics.Recorder
+	// ResultPublisher optionally publishes an ExecutionSummary CloudEvent after each event
+	ResultPublisher ResultPublisher
+	// SecretProvider optionally resolves vault.* param sources (required when they are used)
+	SecretProvider SecretProvider
+	// ResultTopic is the broker topic for result events (required when ResultPublisher is set)
+	ResultTopic string
+	// TaskName labels the config this executor reports in config_info: empty for the main
+	// task config, the task config path for a subscription with its own task config
+	TaskName string
+	// ManifestProcessors post-process the rendered manifests before they are applied, in order
+	ManifestProcessors []ManifestProcessor
 }
 
 // Executor processes CloudEvents according to the adapter configuration
 type Executor struct {
-	config             *ExecutorConfig
+	config *ExecutorConfig
+	// current is the config used for new executions; see SwapConfig
+	current            atomic.Pointer[configVersion]
 	precondExecutor    *PreconditionExecutor
 	resourceExecutor   *ResourceExecutor
+	waitExecutor       *WaitExecutor
 	postActionExecutor *PostActionExecutor
 	log                logger.Logger
 }
 
+// configVersion is an immutable config snapshot, its task config hash and its fingerprint
+type configVersion struct {
+	config      *configloader.Config
+	hash        string
+	fingerprint string
+}
+
 // ExecutionResult contains the result of processing an event
 type ExecutionResult struct {
 	// ExecutionContext contains the full execution context (for testing and debugging)
 	ExecutionContext *ExecutionContext
 	// Params contains the extracted parameters
 	Params map[string]interface{}
-	// Errors contains errors keyed by the phase where they occurred
-	Errors map[ExecutionPhase]error
 	// SkipReason is why resources were skipped (e.g., "precondition not met")
 	SkipReason string
 	// Status is the overall execution status (runtime perspective)
@@ -98,8 +120,18 @@ type ExecutionResult struct {
 	PreconditionResults []PreconditionResult
 	// ResourceResults contains results of resource operations
 	ResourceResults []ResourceResult
+	// WaitResults contains results of the wait steps
+	WaitResults []WaitResult
 	// PostActionResults contains results of post-action executions
 	PostActionResults []PostActionResult
+	// Warnings contains the soft anomalies recorded during execution, in the order they occurred
+	Warnings []ExecutionWarning
+	// ConfigHash is the task config hash of the config the execution ran with
+	ConfigHash string
+	// ConfigFingerprint is the fingerprint of the whole config the execution ran with
+	ConfigFingerprint string
+	// Errors contains every failure in the order it occurred
+	Errors ExecutionErrors
 	// ResourcesSkipped indicates if resources were skipped (business outcome)
 	ResourcesSkipped bool
 }
@@ -120,6 +152,8 @@ type PreconditionResult struct {
 	APIResponse []byte
 	// ConditionResults contains individual condition evaluation results
 	ConditionResults []criteria.EvaluationResult
+	// Duration is how long the precondition took, including its API call
+	Duration time.Duration
 	// Matched indicates if conditions were satisfied
 	Matched bool
 	// APICallMade indicates if an API call was made
@@ -149,6 +183,27 @@ type ResourceResult struct {
 	Status ExecutionStatus
 	// Operation is the operation performed (create, update, recreate, skip, delete)
 	Operation manifest.Operation
+	// Changes are the fields an update or recreate changed, with Secret data redacted
+	Changes []manifest.FieldChange
+	// Drift are the fields of the live object that diverged from the manifest although its
+	// generation was unchanged. Only detected with a drift_policy other than ignore.
+	Drift []manifest.FieldChange
+	// Duration is how long the operation took, including retries
+	Duration time.Duration
+}
+
+// WaitResult contains the result of a single wait step
+type WaitResult struct {
+	// Error is the error if Status is StatusFailed
+	Error error
+	// Name is the wait step name
+	Name string
+	// Status is the result status
+	Status ExecutionStatus
+	// Polls is the number of times the target was read
+	Polls int
+	// Duration is how long the step waited
+	Duration time.Duration
 }
 
 // PostActionResult contains the result of a single post-action execution
@@ -165,13 +220,21 @@ type PostActionResult struct {
 	APIResponse []byte
 	// HTTPStatus is the HTTP status code of the API response
 	HTTPStatus int
+	// Duration is how long the action took, including retries
+	Duration time.Duration
 	// Skipped indicates if the action was skipped due to when condition
 	Skipped bool
 	// APICallMade indicates if an API call was made
 	APICallMade bool
 }
 
-// ExecutionContext holds runtime context during execution
+// ExecutionContext holds runtime context during execution.
+//
+// Params, Resources, Evaluations and Adapter are shared by every step of an
+// execution. Phase executors must go through the methods below (SetParam,
+// SetResource, RecordResourceError, ...) rather than writing the fields directly,
+// so steps can run concurrently; the methods serialize access with mu.
+// Direct field access is only safe once the execution has finished.
 type ExecutionContext struct {
 	// Ctx is the Go context
 	Ctx context.Context
@@ -187,10 +250,33 @@ type ExecutionContext struct {
 	// Nested discoveries are also added as top-level entries keyed by nested discovery name.
 	// Values are expected to be *unstructured.Unstructured.
 	Resources map[string]interface{}
+	// TransformedResources holds the results of the resource transforms, keyed by resource
+	// name. They replace the objects in the CEL resources map only.
+	TransformedResources map[string]interface{}
+	// ForEachResources holds the item names of each for_each resource, in list order.
+	// resources.<name> is the list of the item objects in CEL.
+	ForEachResources map[string][]string
+	// forEachItems holds the list item of each for_each resource item, keyed by item name
+	forEachItems map[string]ForEachItem
+	// variables holds the names set with SetVariable, counted against limits.max_variables
+	variables map[string]bool
+	// Warnings holds anomalies that did not fail the execution (capture misses,
+	// unresolved optional params without a default, discovery failures, ...)
+	Warnings []ExecutionWarning
+	// ConfigFingerprint is the fingerprint of Config, annotated on the applied resources
+	// when set
+	ConfigFingerprint string
+	// discoveryCache holds selector-based LIST results for reuse within this execution.
+	// Entries are dropped when a resource of the same kind and namespace is applied or deleted.
+	discoveryCache map[discoveryCacheKey]*unstructured.UnstructuredList
 	// Evaluations tracks all condition evaluations for debugging/auditing
 	Evaluations []EvaluationRecord
 	// Adapter holds adapter execution metadata
 	Adapter AdapterMetadata
+	// expandedSteps counts the steps added at runtime by for_each items, counted with the
+	// config's steps against limits.max_steps
+	expandedSteps int
+	mu            sync.RWMutex
 }
 
 // EvaluationRecord tracks a single condition evaluation during execution
@@ -233,6 +319,10 @@ type AdapterMetadata struct {
 	// who need granular per-resource failure details can access them via
 	// adapter.?resourceErrors.?myResource.?message without replacing the top-level signal.
 	ResourceErrors map[string]ExecutionError `json:"resourceErrors,omitempty"`
+	// ResourceDrift holds the paths of the fields that drifted on each resource with a
+	// drift_policy of report or reconcile, keyed by resource name. Available to payloads
+	// as adapter.?resourceDrift.?myResource.
+	ResourceDrift map[string][]string `json:"resourceDrift,omitempty"`
 	// ExecutionStatus is the overall execution status (runtime perspective: "success", "failed")
 	ExecutionStatus string
 	// ErrorReason is the error reason if failed (process execution errors only)
@@ -241,10 +331,27 @@ type AdapterMetadata struct {
 	ErrorMessage string
 	// SkipReason is why resources were skipped (e.g., "precondition not met")
 	SkipReason string `json:"skipReason,omitempty"`
+	// SkipReasons lists the resources left unchanged, in execution order. Unlike
+	// ResourcesSkipped it includes the resources already at the event's generation, so
+	// payloads can report "up to date for generation N" apart from "applied now".
+	SkipReasons []ResourceSkip `json:"skipReasons,omitempty"`
 	// ResourcesSkipped indicates if resources were skipped (business outcome)
 	ResourcesSkipped bool `json:"resourcesSkipped,omitempty"`
 }
 
+// ResourceSkip describes a resource an execution left unchanged
+type ResourceSkip struct {
+	// Resource is the resource name from config
+	Resource string `json:"resource"`
+	// Reason is generation_unchanged when the resource already had the manifest's
+	// generation, or when_false when its lifecycle.create.when was false
+	Reason string `json:"reason"`
+	// Message explains the skip, e.g. "generation 3 unchanged"
+	Message string `json:"message"`
+	// Generation is the generation of the manifest; 0 when it was not rendered
+	Generation int64 `json:"generation"`
+}
+
 // ExecutionError represents a structured execution error
 type ExecutionError struct {
 	// Phase is the execution phase where the error occurred
@@ -255,6 +362,17 @@ type ExecutionError struct {
 	Message string `json:"message"`
 }
 
+// ExecutionWarning is an anomaly that did not fail a step, such as a captured field missing
+// from a response without a default. A fallback to a configured default is not a warning.
+type ExecutionWarning struct {
+	// Phase is the execution phase where the warning was recorded
+	Phase ExecutionPhase `json:"phase"`
+	// Step is the precondition/resource/param name, if any
+	Step string `json:"step,omitempty"`
+	// Message describes the anomaly
+	Message string `json:"message"`
+}
+
 // NewExecutionContext creates a new execution context
 func NewExecutionContext(
 	ctx context.Context,
@@ -283,6 +401,8 @@ func (ec *ExecutionContext) AddEvaluation(
 	matched bool,
 	fieldResults map[string]criteria.EvaluationResult,
 ) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
 	ec.Evaluations = append(ec.Evaluations, EvaluationRecord{
 		Phase:          phase,
 		Name:           name,
@@ -311,6 +431,8 @@ func (ec *ExecutionContext) AddConditionsEvaluation(
 
 // GetEvaluationsByPhase returns all evaluations for a specific phase
 func (ec *ExecutionContext) GetEvaluationsByPhase(phase ExecutionPhase) []EvaluationRecord {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
 	var results []EvaluationRecord
 	for _, eval := range ec.Evaluations {
 		if eval.Phase == phase {
@@ -322,6 +444,8 @@ func (ec *ExecutionContext) GetEvaluationsByPhase(phase ExecutionPhase) []Evalua
 
 // GetFailedEvaluations returns all evaluations that did not match
 func (ec *ExecutionContext) GetFailedEvaluations() []EvaluationRecord {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
 	var results []EvaluationRecord
 	for _, eval := range ec.Evaluations {
 		if !eval.Matched {
@@ -336,6 +460,8 @@ func (ec *ExecutionContext) GetFailedEvaluations() []EvaluationRecord {
 // recordResourceError set a richer error (with Phase and Step) first, and
 // SetError must not overwrite it with a coarser-grained fallback.
 func (ec *ExecutionContext) SetError(reason, message string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
 	ec.Adapter.ExecutionStatus = string(StatusFailed)
 	ec.Adapter.ErrorReason = reason
 	ec.Adapter.ErrorMessage = message
@@ -349,6 +475,8 @@ func (ec *ExecutionContext) SetError(reason, message string) {
 
 // SetSkipped sets the status to indicate execution was skipped (not an error)
 func (ec *ExecutionContext) SetSkipped(reason, message string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
 	// Execution was successful, but resources were skipped due to business logic
 	ec.Adapter.ExecutionStatus = string(StatusSuccess)
 	ec.Adapter.ResourcesSkipped = true
@@ -358,9 +486,259 @@ func (ec *ExecutionContext) SetSkipped(reason, message string) {
 	}
 }
 
+// SetParam stores a parameter or captured value under name
+func (ec *ExecutionContext) SetParam(name string, value interface{}) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.Params[name] = value
+}
+
+// SetVariable stores a variable declared by the task config (param, precondition response,
+// capture, payload or ManifestWork wait status) under name. Setting a new name fails once the event has set
+// limits.max_variables variables.
+func (ec *ExecutionContext) SetVariable(name string, value interface{}) error {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if !ec.variables[name] {
+		if limit := ec.limits().MaxVariables; configloader.ExceedsLimit(len(ec.variables)+1, limit) {
+			return fmt.Errorf("variable %q exceeds the limit of %d variables (limits.max_variables)", name, limit)
+		}
+		if ec.variables == nil {
+			ec.variables = make(map[string]bool)
+		}
+		ec.variables[name] = true
+	}
+	ec.Params[name] = value
+	return nil
+}
+
+// AddExpandedSteps counts n steps added at runtime for step, such as the items of a
+// for_each step beyond the step itself. It fails once the event runs more steps than
+// limits.max_steps.
+func (ec *ExecutionContext) AddExpandedSteps(step string, n int) error {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	limit := ec.limits().MaxSteps
+	steps := configloader.StepCount(ec.Config) + ec.expandedSteps + n
+	if configloader.ExceedsLimit(steps, limit) {
+		return fmt.Errorf("step %q expands the event to %d steps, limit (limits.max_steps) is %d", step, steps, limit)
+	}
+	ec.expandedSteps += n
+	return nil
+}
+
+// limits returns the task config limits with defaults. Without a config nothing is limited.
+func (ec *ExecutionContext) limits() configloader.LimitsConfig {
+	if ec.Config == nil {
+		return configloader.LimitsConfig{}
+	}
+	return ec.Config.Limits.WithDefaults()
+}
+
+// GetParam returns the parameter stored under name
+func (ec *ExecutionContext) GetParam(name string) (interface{}, bool) {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	value, ok := ec.Params[name]
+	return value, ok
+}
+
+// ParamsSnapshot returns a shallow copy of Params, safe to hand to template
+// rendering while other steps keep adding params.
+func (ec *ExecutionContext) ParamsSnapshot() map[string]interface{} {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	snapshot := make(map[string]interface{}, len(ec.Params))
+	for k, v := range ec.Params {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
+// SetResource stores a discovered resource under name. A nil value records the
+// resource as deleted (absent from the CEL resources map). The result of an earlier
+// transform of the resource is dropped.
+func (ec *ExecutionContext) SetResource(name string, value interface{}) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.Resources == nil {
+		ec.Resources = make(map[string]interface{})
+	}
+	ec.Resources[name] = value
+	delete(ec.TransformedResources, name)
+}
+
+// SetTransformedResource stores the result of the transform of resource name, shown as
+// resources.<name> to CEL instead of the discovered object
+func (ec *ExecutionContext) SetTransformedResource(name string, value interface{}) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.TransformedResources == nil {
+		ec.TransformedResources = make(map[string]interface{})
+	}
+	ec.TransformedResources[name] = value
+}
+
+// SetForEachResource records the item names of the for_each resource name
+func (ec *ExecutionContext) SetForEachResource(name string, itemNames []string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.ForEachResources == nil {
+		ec.ForEachResources = make(map[string][]string)
+	}
+	ec.ForEachResources[name] = itemNames
+}
+
+// setForEachItem records the list item of the for_each resource item itemName
+func (ec *ExecutionContext) setForEachItem(itemName string, item ForEachItem) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.forEachItems == nil {
+		ec.forEachItems = make(map[string]ForEachItem)
+	}
+	ec.forEachItems[itemName] = item
+}
+
+// forEachItem returns the list item of the for_each resource item itemName
+func (ec *ExecutionContext) forEachItem(itemName string) (ForEachItem, bool) {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	item, ok := ec.forEachItems[itemName]
+	return item, ok
+}
+
+// SetResourceIfAbsent stores value under name unless the key already exists.
+// Returns false when the key was already present.
+func (ec *ExecutionContext) SetResourceIfAbsent(name string, value interface{}) bool {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.Resources == nil {
+		ec.Resources = make(map[string]interface{})
+	}
+	if _, exists := ec.Resources[name]; exists {
+		return false
+	}
+	ec.Resources[name] = value
+	return true
+}
+
+// GetResource returns the resource stored under name
+func (ec *ExecutionContext) GetResource(name string) (interface{}, bool) {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	value, ok := ec.Resources[name]
+	return value, ok
+}
+
+// AddWarning records a warning for step in phase
+func (ec *ExecutionContext) AddWarning(phase ExecutionPhase, step, message string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.Warnings = append(ec.Warnings, ExecutionWarning{Phase: phase, Step: step, Message: message})
+}
+
+// WarningsSnapshot returns a copy of the recorded warnings
+func (ec *ExecutionContext) WarningsSnapshot() []ExecutionWarning {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
+	if len(ec.Warnings) == 0 {
+		return nil
+	}
+	return append([]ExecutionWarning(nil), ec.Warnings...)
+}
+
+// SetExecutionError replaces adapter.executionError with a step-level error
+func (ec *ExecutionContext) SetExecutionError(phase ExecutionPhase, step, message string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.Adapter.ExecutionError = &ExecutionError{Phase: string(phase), Step: step, Message: message}
+}
+
+// ClearExecutionError resets adapter.executionError (used when a failure turns
+// out to be a graceful stop)
+func (ec *ExecutionContext) ClearExecutionError() {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.Adapter.ExecutionError = nil
+}
+
+// ClearStepError removes the errors recorded for step in phase, after a retry of
+// the step succeeded. Errors recorded by other steps are kept.
+func (ec *ExecutionContext) ClearStepError(phase ExecutionPhase, step string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if e := ec.Adapter.ExecutionError; e != nil && e.Phase == string(phase) && e.Step == step {
+		ec.Adapter.ExecutionError = nil
+	}
+	if phase == PhaseResources {
+		delete(ec.Adapter.ResourceErrors, step)
+	}
+}
+
+// RecordContinuedFailure records the error of a step that failed with on_error continue
+// as a warning, and removes it from adapter.executionError. Per-resource errors are kept
+// in adapter.resourceErrors.
+func (ec *ExecutionContext) RecordContinuedFailure(phase ExecutionPhase, step string, err error) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if e := ec.Adapter.ExecutionError; e != nil && e.Phase == string(phase) && e.Step == step {
+		ec.Adapter.ExecutionError = nil
+	}
+	ec.Warnings = append(ec.Warnings, ExecutionWarning{
+		Phase:   phase,
+		Step:    step,
+		Message: fmt.Sprintf("failed, continued (on_error=continue): %v", err),
+	})
+}
+
+// RecordResourceError records a per-resource error in adapter.resourceErrors and
+// sets adapter.executionError if no earlier error was recorded (first error wins).
+func (ec *ExecutionContext) RecordResourceError(step, message string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	execErr := ExecutionError{Phase: string(PhaseResources), Step: step, Message: message}
+	if ec.Adapter.ExecutionError == nil {
+		ec.Adapter.ExecutionError = &execErr
+	}
+	if ec.Adapter.ResourceErrors == nil {
+		ec.Adapter.ResourceErrors = make(map[string]ExecutionError)
+	}
+	ec.Adapter.ResourceErrors[step] = execErr
+}
+
+// RecordResourceDrift records the drifted field paths of a resource in adapter.resourceDrift
+func (ec *ExecutionContext) RecordResourceDrift(step string, paths []string) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	if ec.Adapter.ResourceDrift == nil {
+		ec.Adapter.ResourceDrift = make(map[string][]string)
+	}
+	ec.Adapter.ResourceDrift[step] = paths
+}
+
+// MarkResourcesSkipped sets adapter.resourcesSkipped without changing the execution
+// status. The skip reason is only set if none was recorded yet, unless overwrite is true.
+func (ec *ExecutionContext) MarkResourcesSkipped(reason string, overwrite bool) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.Adapter.ResourcesSkipped = true
+	if overwrite || ec.Adapter.SkipReason == "" {
+		ec.Adapter.SkipReason = reason
+	}
+}
+
+// RecordResourceSkip appends a resource left unchanged to adapter.skipReasons
+func (ec *ExecutionContext) RecordResourceSkip(skip ResourceSkip) {
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	ec.Adapter.SkipReasons = append(ec.Adapter.SkipReasons, skip)
+}
+
 // GetCELVariables returns all variables for CEL evaluation.
 // This includes Params, adapter metadata, and resources.
 func (ec *ExecutionContext) GetCELVariables() map[string]interface{} {
+	ec.mu.RLock()
+	defer ec.mu.RUnlock()
 	result := make(map[string]interface{})
 
 	// Copy all params
@@ -397,6 +775,22 @@ func (ec *ExecutionContext) GetCELVariables() map[string]interface{} {
 			resources[name] = nested
 		}
 	}
+	for name, value := range ec.TransformedResources {
+		if _, ok := resources[name]; ok {
+			resources[name] = value
+		}
+	}
+	// A for_each resource is the list of its item objects; items that were not
+	// discovered or were deleted are left out
+	for name, itemNames := range ec.ForEachResources {
+		items := make([]interface{}, 0, len(itemNames))
+		for _, itemName := range itemNames {
+			if item, ok := resources[itemName]; ok {
+				items = append(items, item)
+			}
+		}
+		resources[name] = items
+	}
 	result["resources"] = resources
 	result["event"] = ec.EventData
 	result["env"] = buildEnvMap()
@@ -433,6 +827,32 @@ func NewExecutorError(phase ExecutionPhase, step, message string, err error) *Ex
 	}
 }
 
+// EventRawKey is the event field holding the payload of a CloudEvent whose data is
+// text rather than JSON, e.g. {{ .event.eventRaw }}
+const EventRawKey = "eventRaw"
+
+// errUnsupportedContentType is wrapped by InvalidCloudEventError for data that is neither JSON nor text
+var errUnsupportedContentType = errors.New("unsupported datacontenttype, expected JSON or text/*")
+
+// InvalidCloudEventError reports CloudEvent data that cannot be read for its datacontenttype
+type InvalidCloudEventError struct {
+	Err error
+	// ContentType is the event's datacontenttype ("" when unset, read as JSON)
+	ContentType string
+}
+
+func (e *InvalidCloudEventError) Error() string {
+	contentType := e.ContentType
+	if contentType == "" {
+		contentType = "application/json (default)"
+	}
+	return fmt.Sprintf("invalid CloudEvent data with datacontenttype %s: %v", contentType, e.Err)
+}
+
+func (e *InvalidCloudEventError) Unwrap() error {
+	return e.Err
+}
+
 // PreconditionsOutcome represents the high-level result of precondition evaluation
 type PreconditionsOutcome struct {
 	// Error contains execution errors (API failures, parse errors, etc.)
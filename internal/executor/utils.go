@@ -1,14 +1,16 @@
 package executor
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
 	"strings"
-	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
@@ -46,7 +48,7 @@ func ExecuteLogAction(
 	}
 
 	// Render the message template
-	message, err := utils.RenderTemplate(logAction.Message, execCtx.Params)
+	message, err := utils.RenderTemplate(logAction.Message, stepParams(ctx, execCtx))
 	if err != nil {
 		errCtx := logger.WithErrorField(ctx, err)
 		log.Errorf(errCtx, "failed to render log message")
@@ -89,8 +91,10 @@ func ExecuteAPICall(
 		return nil, "", fmt.Errorf("apiCall is nil")
 	}
 
+	params := stepParams(ctx, execCtx)
+
 	// First render the URL template to resolve variables like {{ .hyperfleetApiBaseUrl }}
-	renderedURL, err := utils.RenderTemplate(apiCall.URL, execCtx.Params)
+	renderedURL, err := utils.RenderTemplate(apiCall.URL, params)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to render URL template: %w", err)
 	}
@@ -106,7 +110,7 @@ func ExecuteAPICall(
 	// Add headers
 	headers := make(map[string]string)
 	for _, h := range apiCall.Headers {
-		headerValue, headerErr := utils.RenderTemplate(h.Value, execCtx.Params)
+		headerValue, headerErr := utils.RenderTemplate(h.Value, params)
 		if headerErr != nil {
 			return nil, url, fmt.Errorf("failed to render header '%s' template: %w", h.Name, headerErr)
 		}
@@ -117,13 +121,8 @@ func ExecuteAPICall(
 	}
 
 	// Set timeout if specified
-	if apiCall.Timeout != "" {
-		timeout, timeoutErr := time.ParseDuration(apiCall.Timeout)
-		if timeoutErr == nil {
-			opts = append(opts, hyperfleetapi.WithRequestTimeout(timeout))
-		} else {
-			log.Warnf(ctx, "failed to parse timeout '%s': %v, using default timeout", apiCall.Timeout, timeoutErr)
-		}
+	if apiCall.Timeout > 0 {
+		opts = append(opts, hyperfleetapi.WithRequestTimeout(apiCall.Timeout.Std()))
 	}
 
 	// Set retry configuration
@@ -135,20 +134,27 @@ func ExecuteAPICall(
 		opts = append(opts, hyperfleetapi.WithRequestRetryBackoff(backoff))
 	}
 
+	// Stream the response and keep only the configured fields
+	if len(apiCall.ResponseFields) > 0 && !apiCall.KeepRawResponse {
+		fields := apiCall.ResponseFields
+		strictNumbers := execCtx.Config.StrictJSONNumbers()
+		opts = append(opts, hyperfleetapi.WithResponseDecoder(func(r io.Reader) (interface{}, error) {
+			return utils.ProjectJSON(r, fields, strictNumbers)
+		}))
+	}
+
 	// Execute request based on method
 	var resp *hyperfleetapi.Response
 	switch strings.ToUpper(apiCall.Method) {
 	case http.MethodGet:
 		resp, err = apiClient.Get(ctx, url, opts...)
 	case http.MethodPost:
-		body := []byte(apiCall.Body)
-		if apiCall.Body != "" {
-			body, err = utils.RenderTemplateBytes(apiCall.Body, execCtx.Params)
-			if err != nil {
-				return nil, url, fmt.Errorf("failed to render body template: %w", err)
-			}
+		body, contentType, bodyErr := renderAPICallBody(apiCall, params)
+		if bodyErr != nil {
+			return nil, url, bodyErr
 		}
-		log.Debugf(ctx, "API call payload: %s %s payload=%s", apiCall.Method, url, string(body))
+		opts = withBodyContentType(opts, contentType)
+		logAPICallPayload(ctx, log, apiCall, url, body)
 		resp, err = apiClient.Post(ctx, url, body, opts...)
 		// Log error message on failure for debugging purposes
 		if err != nil || (resp != nil && !resp.IsSuccess()) {
@@ -162,14 +168,12 @@ func ExecuteAPICall(
 			log.Error(errCtx, "POST Request failed")
 		}
 	case http.MethodPut:
-		body := []byte(apiCall.Body)
-		if apiCall.Body != "" {
-			body, err = utils.RenderTemplateBytes(apiCall.Body, execCtx.Params)
-			if err != nil {
-				return nil, "", fmt.Errorf("failed to render body template: %w", err)
-			}
+		body, contentType, bodyErr := renderAPICallBody(apiCall, params)
+		if bodyErr != nil {
+			return nil, "", bodyErr
 		}
-		log.Debugf(ctx, "API call payload: %s %s payload=%s", apiCall.Method, url, string(body))
+		opts = withBodyContentType(opts, contentType)
+		logAPICallPayload(ctx, log, apiCall, url, body)
 		resp, err = apiClient.Put(ctx, url, body, opts...)
 		// Log error message on failure for debugging purposes
 		if err != nil || (resp != nil && !resp.IsSuccess()) {
@@ -183,14 +187,22 @@ func ExecuteAPICall(
 			log.Error(errCtx, "PUT Request failed")
 		}
 	case http.MethodPatch:
-		body := []byte(apiCall.Body)
-		if apiCall.Body != "" {
-			body, err = utils.RenderTemplateBytes(apiCall.Body, execCtx.Params)
+		var body []byte
+		if len(apiCall.Patch) > 0 {
+			body, err = buildJSONPatch(ctx, apiCall.Patch, execCtx, params, log)
+			if err != nil {
+				return nil, url, fmt.Errorf("failed to build JSON patch: %w", err)
+			}
+			opts = withBodyContentType(opts, hyperfleetapi.ContentTypeJSONPatch)
+		} else {
+			var contentType string
+			body, contentType, err = renderAPICallBody(apiCall, params)
 			if err != nil {
-				return nil, "", fmt.Errorf("failed to render body template: %w", err)
+				return nil, "", err
 			}
+			opts = withBodyContentType(opts, contentType)
 		}
-		log.Debugf(ctx, "API call payload: %s %s payload=%s", apiCall.Method, url, string(body))
+		logAPICallPayload(ctx, log, apiCall, url, body)
 		resp, err = apiClient.Patch(ctx, url, body, opts...)
 	case http.MethodDelete:
 		resp, err = apiClient.Delete(ctx, url, opts...)
@@ -240,6 +252,79 @@ func ExecuteAPICall(
 	return resp, url, nil
 }
 
+// withBodyContentType sets the Content-Type of an encoded body. The header is prepended
+// so a configured Content-Type header still wins; an empty contentType keeps the client
+// default.
+func withBodyContentType(opts []hyperfleetapi.RequestOption, contentType string) []hyperfleetapi.RequestOption {
+	if contentType == "" {
+		return opts
+	}
+	return append([]hyperfleetapi.RequestOption{hyperfleetapi.WithHeader("Content-Type", contentType)}, opts...)
+}
+
+// logAPICallPayload logs the body of an api_call at debug level. Multipart bodies are
+// summarized, since their files can be large or binary.
+func logAPICallPayload(ctx context.Context, log logger.Logger, apiCall *configloader.APICall, url string, body []byte) {
+	if apiCall.BodyEncoding == configloader.BodyEncodingMultipart {
+		log.Debugf(ctx, "API call payload: %s %s multipart payload of %d bytes", apiCall.Method, url, len(body))
+		return
+	}
+	log.Debugf(ctx, "API call payload: %s %s payload=%s", apiCall.Method, url, string(body))
+}
+
+// jsonPatchOperation is an RFC 6902 JSON Patch operation as sent on the wire
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// buildJSONPatch renders the paths and evaluates the value expressions of an api_call patch
+// into a JSON Patch document. A value expression that fails to evaluate fails the call
+// rather than sending a null value.
+func buildJSONPatch(
+	ctx context.Context,
+	ops []configloader.JSONPatchOperation,
+	execCtx *ExecutionContext,
+	params map[string]interface{},
+	log logger.Logger,
+) ([]byte, error) {
+	evalCtx := criteria.NewEvaluationContext()
+	evalCtx.SetVariablesFromMap(stepCELVariables(ctx, execCtx))
+	evaluator, err := criteria.NewEvaluator(ctx, evalCtx, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL evaluator: %w", err)
+	}
+
+	patch := make([]jsonPatchOperation, 0, len(ops))
+	for i, op := range ops {
+		entry := jsonPatchOperation{Op: op.Op}
+		if entry.Path, err = utils.RenderTemplate(op.Path, params); err != nil {
+			return nil, fmt.Errorf("patch[%d]: failed to render path template: %w", i, err)
+		}
+		if op.From != "" {
+			if entry.From, err = utils.RenderTemplate(op.From, params); err != nil {
+				return nil, fmt.Errorf("patch[%d]: failed to render from template: %w", i, err)
+			}
+		}
+		if strings.TrimSpace(op.ValueExpression) != "" {
+			result, evalErr := evaluator.EvaluateCEL(strings.TrimSpace(op.ValueExpression))
+			if evalErr != nil {
+				return nil, fmt.Errorf("patch[%d]: CEL evaluation failed: %w", i, evalErr)
+			}
+			if result.HasError() {
+				return nil, fmt.Errorf("patch[%d]: CEL expression error: %w", i, result.Error)
+			}
+			if entry.Value, err = json.Marshal(result.Value); err != nil {
+				return nil, fmt.Errorf("patch[%d]: failed to marshal value: %w", i, err)
+			}
+		}
+		patch = append(patch, entry)
+	}
+	return json.Marshal(patch)
+}
+
 // buildHyperfleetAPICallURL builds a full HyperFleet API URL when a relative path is provided.
 // It uses hyperfleet API client settings from execution context config.
 // Since the hyperfleetapi.Client always prepends its baseURL to the path,
@@ -314,6 +399,53 @@ func buildHyperfleetAPICallURL(apiCallURL string, execCtx *ExecutionContext) str
 	return path.Join("/api/hyperfleet", version, cleanPath)
 }
 
+// ParseAPIResponse returns the JSON object of a successful API response. When the api_call
+// has response_fields, only those fields are returned: the client has already decoded them
+// from the stream, or they are projected from the raw body when it was kept. strictNumbers
+// decodes integers as int64 rather than float64 (json_numbers: strict).
+func ParseAPIResponse(
+	apiCall *configloader.APICall,
+	resp *hyperfleetapi.Response,
+	strictNumbers bool,
+) (map[string]interface{}, error) {
+	if resp.Decoded != nil {
+		data, ok := resp.Decoded.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected decoded response type %T", resp.Decoded)
+		}
+		return data, nil
+	}
+	if len(apiCall.ResponseFields) > 0 {
+		return utils.ProjectJSON(bytes.NewReader(resp.Body), apiCall.ResponseFields, strictNumbers)
+	}
+	return utils.UnmarshalJSONObject(resp.Body, strictNumbers)
+}
+
+// transformStepResult evaluates the transform expression of step name with the fields of
+// data as variables, and the whole of data under name, and returns its result as plain Go values
+func transformStepResult(
+	ctx context.Context,
+	log logger.Logger,
+	name, expression string,
+	data map[string]interface{},
+) (interface{}, error) {
+	evalCtx := criteria.NewEvaluationContext()
+	evalCtx.SetVariablesFromMap(data)
+	evalCtx.Set(name, data)
+	evaluator, err := criteria.NewEvaluator(ctx, evalCtx, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL evaluator: %w", err)
+	}
+	result, err := evaluator.EvaluateCEL(strings.TrimSpace(expression))
+	if err != nil {
+		return nil, fmt.Errorf("transform failed: %w", err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("transform failed: %w", result.Error)
+	}
+	return criteria.NativeValue(result.Value), nil
+}
+
 // ValidateAPIResponse checks if an API response is valid and successful
 // Returns an APIError with full context if response is nil or unsuccessful
 // method and url are used to construct APIError with proper context
@@ -390,13 +522,34 @@ func adapterMetadataToMap(adapter *AdapterMetadata) map[string]interface{} {
 		resourceErrors[name] = executionErrorToMap(&execErrCopy)
 	}
 
+	resourceDrift := make(map[string]interface{}, len(adapter.ResourceDrift))
+	for name, paths := range adapter.ResourceDrift {
+		drifted := make([]interface{}, len(paths))
+		for i, path := range paths {
+			drifted[i] = path
+		}
+		resourceDrift[name] = drifted
+	}
+
+	skipReasons := make([]interface{}, len(adapter.SkipReasons))
+	for i, skip := range adapter.SkipReasons {
+		skipReasons[i] = map[string]interface{}{
+			"resource":   skip.Resource,
+			"reason":     skip.Reason,
+			"message":    skip.Message,
+			"generation": skip.Generation,
+		}
+	}
+
 	return map[string]interface{}{
 		"executionStatus":  adapter.ExecutionStatus,
 		"resourcesSkipped": adapter.ResourcesSkipped,
 		"skipReason":       adapter.SkipReason,
+		"skipReasons":      skipReasons,
 		"errorReason":      adapter.ErrorReason,
 		"errorMessage":     adapter.ErrorMessage,
 		"executionError":   executionErrorToMap(adapter.ExecutionError),
 		"resourceErrors":   resourceErrors,
+		"resourceDrift":    resourceDrift,
 	}
 }
@@ -1,9 +1,15 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -1075,3 +1081,293 @@ func TestBuildHyperfleetAPICallURL(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteAPICall_JSONPatch(t *testing.T) {
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+	execCtx.SetParam("conditionIndex", 2)
+	execCtx.SetParam("reason", "Applied")
+	apiCall := &configloader.APICall{
+		Method: "PATCH",
+		URL:    "http://api.example.com/clusters/c1/statuses",
+		Patch: []configloader.JSONPatchOperation{
+			{Op: "replace", Path: "/conditions/{{ .conditionIndex }}/status", ValueExpression: "'True'"},
+			{Op: "add", Path: "/conditions/{{ .conditionIndex }}/reason", ValueExpression: "reason"},
+			{Op: "remove", Path: "/conditions/0/message"},
+			{Op: "copy", From: "/conditions/0/reason", Path: "/lastReason"},
+		},
+	}
+
+	t.Run("sends a JSON patch", func(t *testing.T) {
+		client := hyperfleetapi.NewMockClient()
+		_, _, err := ExecuteAPICall(context.Background(), apiCall, execCtx, client, logger.NewTestLogger())
+		require.NoError(t, err)
+
+		req := client.GetLastRequest()
+		require.NotNil(t, req)
+		assert.Equal(t, hyperfleetapi.ContentTypeJSONPatch, req.Headers["Content-Type"])
+		assert.JSONEq(t, `[
+			{"op":"replace","path":"/conditions/2/status","value":"True"},
+			{"op":"add","path":"/conditions/2/reason","value":"Applied"},
+			{"op":"remove","path":"/conditions/0/message"},
+			{"op":"copy","from":"/conditions/0/reason","path":"/lastReason"}
+		]`, string(req.Body))
+	})
+
+	t.Run("failing value expression fails the call", func(t *testing.T) {
+		client := hyperfleetapi.NewMockClient()
+		failing := *apiCall
+		failing.Patch = []configloader.JSONPatchOperation{
+			{Op: "replace", Path: "/conditions/0/status", ValueExpression: "missing.field"},
+		}
+		_, _, err := ExecuteAPICall(context.Background(), &failing, execCtx, client, logger.NewTestLogger())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "patch[0]")
+		assert.Empty(t, client.Requests, "no request is sent")
+	})
+}
+
+func TestExecuteAPICall_BodyEncoding(t *testing.T) {
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+	execCtx.SetParam("clusterId", "c1")
+	execCtx.SetParam("kubeconfig", "apiVersion: v1\nkind: Config\n")
+	body := `{"cluster": "{{ .clusterId }}", "tags": ["a", "b"], "spec": {"replicas": 2}, "count": 3}`
+
+	t.Run("form", func(t *testing.T) {
+		client := hyperfleetapi.NewMockClient()
+		apiCall := &configloader.APICall{Method: "POST", URL: "http://api.example.com/submit", Body: body,
+			BodyEncoding: configloader.BodyEncodingForm}
+		_, _, err := ExecuteAPICall(context.Background(), apiCall, execCtx, client, logger.NewTestLogger())
+		require.NoError(t, err)
+
+		req := client.GetLastRequest()
+		require.NotNil(t, req)
+		assert.Equal(t, hyperfleetapi.ContentTypeForm, req.Headers["Content-Type"])
+		assert.Equal(t, "cluster=c1&count=3&spec=%7B%22replicas%22%3A2%7D&tags=a&tags=b", string(req.Body))
+	})
+
+	t.Run("multipart", func(t *testing.T) {
+		reportPath := filepath.Join(t.TempDir(), "report.txt")
+		require.NoError(t, os.WriteFile(reportPath, []byte("all good"), 0o600))
+		client := hyperfleetapi.NewMockClient()
+		apiCall := &configloader.APICall{
+			Method: "PUT", URL: "http://api.example.com/submit", Body: `{"cluster": "{{ .clusterId }}"}`,
+			BodyEncoding: configloader.BodyEncodingMultipart,
+			Files: []configloader.FilePart{
+				{Name: "kubeconfig", Filename: "{{ .clusterId }}.kubeconfig", Content: "{{ .kubeconfig }}"},
+				{Name: "report", Path: reportPath, ContentType: "text/plain"},
+			},
+		}
+		_, _, err := ExecuteAPICall(context.Background(), apiCall, execCtx, client, logger.NewTestLogger())
+		require.NoError(t, err)
+
+		req := client.GetLastRequest()
+		require.NotNil(t, req)
+		mediaType, params, err := mime.ParseMediaType(req.Headers["Content-Type"])
+		require.NoError(t, err)
+		assert.Equal(t, "multipart/form-data", mediaType)
+		form, err := multipart.NewReader(bytes.NewReader(req.Body), params["boundary"]).ReadForm(1 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"c1"}, form.Value["cluster"])
+
+		kubeconfig := form.File["kubeconfig"][0]
+		assert.Equal(t, "c1.kubeconfig", kubeconfig.Filename)
+		assert.Equal(t, "application/octet-stream", kubeconfig.Header.Get("Content-Type"))
+		report := form.File["report"][0]
+		assert.Equal(t, "report.txt", report.Filename)
+		f, err := report.Open()
+		require.NoError(t, err)
+		defer f.Close() //nolint:errcheck // test
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, "all good", string(content))
+	})
+
+	t.Run("body that is not an object fails before sending", func(t *testing.T) {
+		client := hyperfleetapi.NewMockClient()
+		apiCall := &configloader.APICall{Method: "POST", URL: "http://api.example.com/submit", Body: `["c1"]`,
+			BodyEncoding: configloader.BodyEncodingForm}
+		_, _, err := ExecuteAPICall(context.Background(), apiCall, execCtx, client, logger.NewTestLogger())
+		assert.ErrorContains(t, err, "form body: body must render to a JSON object")
+		assert.Empty(t, client.Requests)
+	})
+}
+
+func TestExecuteAPICall_ResponseFields(t *testing.T) {
+	execCtx := NewExecutionContext(context.Background(), map[string]interface{}{}, nil)
+	body := []byte(`{"kind":"ClusterList","items":[{"id":"c1","spec":{"big":"x"}},{"id":"c2"}]}`)
+	apiCall := &configloader.APICall{
+		Method:         "GET",
+		URL:            "http://api.example.com/clusters",
+		ResponseFields: []string{"items.id"},
+	}
+	want := map[string]interface{}{"items": []interface{}{
+		map[string]interface{}{"id": "c1"},
+		map[string]interface{}{"id": "c2"},
+	}}
+
+	t.Run("response is streamed into the fields", func(t *testing.T) {
+		client := hyperfleetapi.NewMockClient()
+		_, _, err := ExecuteAPICall(context.Background(), apiCall, execCtx, client, logger.NewTestLogger())
+		require.NoError(t, err)
+		req := client.GetLastRequest()
+		require.NotNil(t, req)
+		require.NotNil(t, req.ResponseDecoder)
+
+		decoded, err := req.ResponseDecoder(bytes.NewReader(body))
+		require.NoError(t, err)
+		data, err := ParseAPIResponse(apiCall, &hyperfleetapi.Response{Decoded: decoded}, false)
+		require.NoError(t, err)
+		assert.Equal(t, want, data)
+	})
+
+	t.Run("raw response is kept when requested", func(t *testing.T) {
+		keep := *apiCall
+		keep.KeepRawResponse = true
+		client := hyperfleetapi.NewMockClient()
+		_, _, err := ExecuteAPICall(context.Background(), &keep, execCtx, client, logger.NewTestLogger())
+		require.NoError(t, err)
+		assert.Nil(t, client.GetLastRequest().ResponseDecoder)
+
+		data, err := ParseAPIResponse(&keep, &hyperfleetapi.Response{Body: body}, false)
+		require.NoError(t, err)
+		assert.Equal(t, want, data)
+	})
+
+	t.Run("whole response without fields", func(t *testing.T) {
+		data, err := ParseAPIResponse(&configloader.APICall{}, &hyperfleetapi.Response{Body: body}, false)
+		require.NoError(t, err)
+		assert.Equal(t, "ClusterList", data["kind"])
+	})
+}
+
+func TestTransformStepResult(t *testing.T) {
+	data := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"name": "a", "ready": true},
+			map[string]interface{}{"name": "b", "ready": false},
+		},
+	}
+
+	tests := []struct {
+		want       interface{}
+		name       string
+		expression string
+		wantErr    bool
+	}{
+		{
+			name:       "list of fields",
+			expression: "items.map(i, i.name)",
+			want:       []interface{}{"a", "b"},
+		},
+		{
+			name:       "map literal",
+			expression: `{"count": items.size(), "ready": items.filter(i, i.ready).map(i, i.name)}`,
+			want:       map[string]interface{}{"count": int64(2), "ready": []interface{}{"a"}},
+		},
+		{
+			name:       "whole response under the step name",
+			expression: `nodePools.?total.orValue(0)`,
+			want:       int64(0),
+		},
+		{
+			name:       "evaluation error",
+			expression: "items[5].name",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transformStepResult(context.Background(), logger.NewTestLogger(), "nodePools", tt.expression, data)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "transform failed")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPreconditionExecutor_Transform(t *testing.T) {
+	apiClient := hyperfleetapi.NewMockClient()
+	apiClient.GetResponse = &hyperfleetapi.Response{StatusCode: 200, Body: []byte(`{
+		"kind": "NodePoolList",
+		"items": [{"name": "np-1", "replicas": 2}, {"name": "np-2", "replicas": 3}]
+	}`)}
+	apiClient.DoResponse = apiClient.GetResponse
+	pe := newPreconditionExecutor(&ExecutorConfig{APIClient: apiClient, Logger: logger.NewTestLogger()})
+
+	preconditions := []configloader.Precondition{{
+		ActionBase: configloader.ActionBase{
+			Name: "nodePools",
+			APICall: &configloader.APICall{
+				Method:    "GET",
+				URL:       "http://api.example.com/nodepools",
+				Transform: "items.map(i, i.name)",
+			},
+		},
+		Capture: []configloader.CaptureField{
+			{Name: "listKind", FieldExpressionDef: configloader.FieldExpressionDef{Field: "kind"}},
+		},
+		Expression: "nodePools.size() == 2",
+	}}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+
+	outcome := pe.ExecuteAll(context.Background(), preconditions, execCtx)
+
+	require.NoError(t, outcome.Error)
+	assert.True(t, outcome.AllMatched)
+	assert.Equal(t, []interface{}{"np-1", "np-2"}, execCtx.Params["nodePools"])
+	assert.Equal(t, "NodePoolList", execCtx.Params["listKind"], "captures should read the full response")
+
+	preconditions[0].APICall.Transform = "items[5].name"
+	execCtx = NewExecutionContext(context.Background(), nil, nil)
+	outcome = pe.ExecuteAll(context.Background(), preconditions, execCtx)
+	require.Error(t, outcome.Error)
+	assert.Contains(t, outcome.Error.Error(), "failed to transform API response")
+	require.Len(t, outcome.Results, 1)
+	assert.Equal(t, StatusFailed, outcome.Results[0].Status)
+	require.NotNil(t, execCtx.Adapter.ExecutionError)
+	assert.Equal(t, "nodePools", execCtx.Adapter.ExecutionError.Step)
+}
+
+func TestPreconditionExecutor_ForEach(t *testing.T) {
+	apiClient := hyperfleetapi.NewMockClient()
+	apiClient.GetResponse = &hyperfleetapi.Response{StatusCode: 200, Body: []byte(`{"name": "np", "ready": true}`)}
+	pe := newPreconditionExecutor(&ExecutorConfig{APIClient: apiClient, Logger: logger.NewTestLogger()})
+
+	preconditions := []configloader.Precondition{{
+		ActionBase: configloader.ActionBase{
+			Name: "nodePools",
+			APICall: &configloader.APICall{
+				Method:    "GET",
+				URL:       "http://api.example.com/nodepools/{{ .item }}?position={{ .index }}",
+				Transform: "ready",
+			},
+		},
+		ForEach:    "nodePoolIds",
+		Expression: "nodePools.all(ready, ready)",
+	}}
+	execCtx := NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["nodePoolIds"] = []interface{}{"np-1", "np-2"}
+
+	outcome := pe.ExecuteAll(context.Background(), preconditions, execCtx)
+
+	require.NoError(t, outcome.Error)
+	assert.True(t, outcome.AllMatched)
+	assert.Equal(t, []interface{}{true, true}, execCtx.Params["nodePools"])
+	require.Len(t, apiClient.Requests, 2)
+	assert.Equal(t, "http://api.example.com/nodepools/np-1?position=0", apiClient.Requests[0].URL)
+	assert.Equal(t, "http://api.example.com/nodepools/np-2?position=1", apiClient.Requests[1].URL)
+
+	apiClient.GetError = errors.New("connection refused")
+	execCtx = NewExecutionContext(context.Background(), nil, nil)
+	execCtx.Params["nodePoolIds"] = []interface{}{"np-1"}
+	outcome = pe.ExecuteAll(context.Background(), preconditions, execCtx)
+	require.Error(t, outcome.Error)
+	assert.Contains(t, outcome.Error.Error(), "item 0")
+	require.NotNil(t, execCtx.Adapter.ExecutionError)
+	assert.Equal(t, "nodePools", execCtx.Adapter.ExecutionError.Step)
+}
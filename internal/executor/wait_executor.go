@@ -0,0 +1,247 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/criteria"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+const (
+	defaultWaitTimeout  = 5 * time.Minute
+	defaultWaitInterval = 5 * time.Second
+)
+
+// WaitExecutor runs the wait steps, polling resources or API endpoints until their
+// condition is true
+type WaitExecutor struct {
+	apiClient hyperfleetapi.Client
+	client    transportclient.TransportClient
+	log       logger.Logger
+}
+
+// newWaitExecutor creates a new wait executor
+// NOTE: Caller (NewExecutor) is responsible for config validation
+func newWaitExecutor(config *ExecutorConfig) *WaitExecutor {
+	return &WaitExecutor{
+		apiClient: config.APIClient,
+		client:    config.TransportClient,
+		log:       config.Logger,
+	}
+}
+
+// ExecuteAll runs the wait steps in sequence and stops at the first one that fails
+func (we *WaitExecutor) ExecuteAll(
+	ctx context.Context,
+	steps []configloader.WaitStep,
+	execCtx *ExecutionContext,
+) ([]WaitResult, error) {
+	results := make([]WaitResult, 0, len(steps))
+	for _, step := range steps {
+		stepCtx, span := startStepSpan(ctx, metrics.StepTypeWait, step.Name)
+		result, err := we.executeWait(stepCtx, step, execCtx)
+		results = append(results, result)
+		span.SetAttributes(attribute.Int("step.polls", result.Polls))
+		endStepSpan(span, string(result.Status), "", err)
+		if err != nil {
+			if continueOnError(ctx, we.log, execCtx, PhaseResources, step.Name, step.OnError, err) {
+				continue
+			}
+			execCtx.SetExecutionError(PhaseResources, step.Name, err.Error())
+			return results, err
+		}
+	}
+	return results, nil
+}
+
+// executeWait polls the target of one wait step until its condition is true. Poll
+// errors, such as a resource that does not exist yet, are retried until the timeout;
+// an invalid condition fails the step at once.
+func (we *WaitExecutor) executeWait(
+	ctx context.Context,
+	step configloader.WaitStep,
+	execCtx *ExecutionContext,
+) (WaitResult, error) {
+	timeout := step.Timeout.OrDefault(defaultWaitTimeout)
+	interval := step.Interval.OrDefault(defaultWaitInterval)
+	result := WaitResult{Name: step.Name, Status: StatusSuccess}
+
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	setStepOperation(ctx, "poll", step.Resource)
+	var lastErr error
+	var unmet []string
+	for {
+		result.Polls++
+		obj, pollErr := we.poll(waitCtx, step, execCtx)
+		var work *workv1.ManifestWork
+		if pollErr == nil && step.ManifestWork != nil {
+			work, pollErr = toManifestWork(obj)
+		}
+		if pollErr != nil {
+			lastErr = pollErr
+			we.log.Debugf(ctx, "Wait[%s] poll %d failed: %v", step.Name, result.Polls, pollErr)
+		} else if unmet = unmetWorkConditions(step, work); len(unmet) > 0 {
+			lastErr = nil
+			we.log.Debugf(ctx, "Wait[%s] poll %d: %s", step.Name, result.Polls, strings.Join(unmet, ", "))
+		} else {
+			matched := true
+			if step.Condition != "" {
+				var evalErr error
+				matched, evalErr = we.evaluate(ctx, step, obj)
+				if evalErr != nil {
+					result.Status = StatusFailed
+					result.Error = evalErr
+					return result, NewExecutorError(PhaseResources, step.Name, "failed to evaluate wait condition", evalErr)
+				}
+			}
+			if matched {
+				if step.Resource != "" {
+					execCtx.SetResource(step.Resource, &unstructured.Unstructured{Object: obj})
+				}
+				if work != nil {
+					if err := execCtx.SetVariable(step.Name, manifest.WorkStatusValues(work)); err != nil {
+						result.Status = StatusFailed
+						result.Error = err
+						return result, NewExecutorError(PhaseResources, step.Name, "failed to set variable", err)
+					}
+				}
+				we.log.Infof(ctx, "Wait[%s] condition met after %d polls (%s)",
+					step.Name, result.Polls, time.Since(start).Round(time.Millisecond))
+				return result, nil
+			}
+			lastErr = nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-waitCtx.Done():
+			timer.Stop()
+			err := fmt.Errorf("condition %q not met within %s", strings.TrimSpace(step.Condition), timeout)
+			if len(unmet) > 0 {
+				err = fmt.Errorf("ManifestWork conditions not met within %s: %s", timeout, strings.Join(unmet, ", "))
+			}
+			if ctx.Err() != nil {
+				err = fmt.Errorf("wait interrupted: %w", ctx.Err())
+			} else if lastErr != nil {
+				err = fmt.Errorf("%w: last poll failed: %w", err, lastErr)
+			}
+			result.Status = StatusFailed
+			result.Error = err
+			errCtx := logger.WithErrorField(ctx, err)
+			we.log.Errorf(errCtx, "Wait[%s]: FAILED after %d polls", step.Name, result.Polls)
+			return result, NewExecutorError(PhaseResources, step.Name, "wait condition not met", err)
+		case <-timer.C:
+		}
+	}
+}
+
+// poll reads the current state of the wait target
+func (we *WaitExecutor) poll(
+	ctx context.Context,
+	step configloader.WaitStep,
+	execCtx *ExecutionContext,
+) (map[string]interface{}, error) {
+	if step.APICall != nil {
+		resp, url, err := ExecuteAPICall(ctx, step.APICall, execCtx, we.apiClient, we.log)
+		if validationErr := ValidateAPIResponse(resp, err, step.APICall.Method, url); validationErr != nil {
+			return nil, validationErr
+		}
+		data, err := ParseAPIResponse(step.APICall, resp, execCtx.Config.StrictJSONNumbers())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse API response as JSON: %w", err)
+		}
+		return data, nil
+	}
+
+	resource, ok := findResource(execCtx.Config.Resources, step.Resource)
+	if !ok {
+		return nil, fmt.Errorf("resource %q is not configured", step.Resource)
+	}
+	value, _ := execCtx.GetResource(step.Resource)
+	current, ok := value.(*unstructured.Unstructured)
+	if !ok || current == nil {
+		return nil, fmt.Errorf("resource %q was not discovered in this execution", step.Resource)
+	}
+
+	target, err := transportTargetFor(resource, execCtx.ParamsSnapshot())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = transportclient.WithTransportName(ctx, resource.GetTransportClient())
+	obj, err := we.client.GetResource(ctx, current.GroupVersionKind(), current.GetNamespace(), current.GetName(), target)
+	if err != nil {
+		return nil, err
+	}
+	return obj.Object, nil
+}
+
+// evaluate reports whether the wait condition holds for the polled object. The
+// object's fields are the CEL variables, and the whole object is also available
+// under the step name. Evaluation errors such as a missing field count as not met.
+func (we *WaitExecutor) evaluate(
+	ctx context.Context,
+	step configloader.WaitStep,
+	obj map[string]interface{},
+) (bool, error) {
+	evalCtx := criteria.NewEvaluationContext()
+	evalCtx.SetVariablesFromMap(obj)
+	evalCtx.Set(step.Name, obj)
+
+	evaluator, err := criteria.NewEvaluator(ctx, evalCtx, we.log)
+	if err != nil {
+		return false, err
+	}
+	celResult, err := evaluator.EvaluateCEL(strings.TrimSpace(step.Condition))
+	if err != nil {
+		return false, err
+	}
+	if celResult.HasError() {
+		we.log.Debugf(ctx, "Wait[%s] condition not evaluable yet: %v", step.Name, celResult.Error)
+	}
+	return celResult.Matched, nil
+}
+
+// toManifestWork converts a polled ManifestWork object to its typed form
+func toManifestWork(obj map[string]interface{}) (*workv1.ManifestWork, error) {
+	work := &workv1.ManifestWork{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj, work); err != nil {
+		return nil, fmt.Errorf("polled object is not a ManifestWork: %w", err)
+	}
+	return work, nil
+}
+
+// unmetWorkConditions describes the conditions of a ManifestWork wait that are not True yet,
+// nil for other waits
+func unmetWorkConditions(step configloader.WaitStep, work *workv1.ManifestWork) []string {
+	if step.ManifestWork == nil || work == nil {
+		return nil
+	}
+	return manifest.UnmetWorkConditions(work, step.ManifestWork.GetConditions())
+}
+
+// findResource returns the resource with the given name
+func findResource(resources []configloader.Resource, name string) (configloader.Resource, bool) {
+	for _, r := range resources {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return configloader.Resource{}, false
+}
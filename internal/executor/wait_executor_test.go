@@ -0,0 +1,297 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// readyAfterMockClient wraps MockK8sClient and reports the resource as ready from the
+// given poll on, like a controller updating its status.
+type readyAfterMockClient struct {
+	*k8sclient.MockK8sClient
+	readyAfter int
+	gets       int
+}
+
+func (m *readyAfterMockClient) GetResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	target transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	m.gets++
+	obj, err := m.MockK8sClient.GetResource(ctx, gvk, namespace, name, target)
+	if err != nil || m.gets < m.readyAfter {
+		return obj, err
+	}
+	ready := obj.DeepCopy()
+	if err := unstructured.SetNestedField(ready.Object, "Ready", "status", "phase"); err != nil {
+		return nil, err
+	}
+	return ready, nil
+}
+
+func newWaitConfigMap() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cluster-config", "namespace": "default"},
+		"status":     map[string]interface{}{"phase": "Pending"},
+	}}
+}
+
+// newWaitExecutionContext returns an execution context where the resources phase
+// discovered the wait target
+func newWaitExecutionContext() *ExecutionContext {
+	execCtx := NewExecutionContext(context.Background(), nil, &configloader.Config{
+		Resources: []configloader.Resource{{Name: "clusterConfigMap"}},
+	})
+	execCtx.SetResource("clusterConfigMap", newWaitConfigMap())
+	return execCtx
+}
+
+func newResourceWaitStep() configloader.WaitStep {
+	return configloader.WaitStep{
+		Name:      "configMapReady",
+		Resource:  "clusterConfigMap",
+		Condition: `status.phase == "Ready"`,
+		Timeout:   configloader.Duration(time.Second),
+		Interval:  configloader.Duration(10 * time.Millisecond),
+	}
+}
+
+func TestWaitExecutor_ResourceConditionMet(t *testing.T) {
+	mock := &readyAfterMockClient{MockK8sClient: k8sclient.NewMockK8sClient(), readyAfter: 3}
+	mock.Resources["default/cluster-config"] = newWaitConfigMap()
+	we := newWaitExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+	execCtx := newWaitExecutionContext()
+
+	results, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{newResourceWaitStep()}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSuccess, results[0].Status)
+	assert.Equal(t, 3, results[0].Polls)
+
+	// The resource seen by post actions is the one that met the condition
+	value, _ := execCtx.GetResource("clusterConfigMap")
+	phase, _, _ := unstructured.NestedString(value.(*unstructured.Unstructured).Object, "status", "phase")
+	assert.Equal(t, "Ready", phase)
+}
+
+func TestWaitExecutor_Timeout(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	mock.Resources["default/cluster-config"] = newWaitConfigMap()
+	we := newWaitExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+	execCtx := newWaitExecutionContext()
+
+	step := newResourceWaitStep()
+	step.Timeout = configloader.Duration(50 * time.Millisecond)
+	results, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{step}, execCtx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `condition "status.phase == \"Ready\"" not met within 50ms`)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusFailed, results[0].Status)
+	assert.Greater(t, results[0].Polls, 1)
+	require.NotNil(t, execCtx.Adapter.ExecutionError)
+	assert.Equal(t, "configMapReady", execCtx.Adapter.ExecutionError.Step)
+}
+
+func TestWaitExecutor_TimeoutReportsLastPollError(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	mock.GetResourceError = errors.New("connection refused")
+	we := newWaitExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	step := newResourceWaitStep()
+	step.Timeout = configloader.Duration(50 * time.Millisecond)
+	_, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{step}, newWaitExecutionContext())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "last poll failed: connection refused")
+}
+
+func TestWaitExecutor_APICall(t *testing.T) {
+	apiClient := hyperfleetapi.NewMockClient()
+	apiClient.GetResponse = &hyperfleetapi.Response{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Body:       []byte(`{"id": "cluster-1", "status": {"phase": "Ready"}}`),
+	}
+	we := newWaitExecutor(&ExecutorConfig{
+		APIClient:       apiClient,
+		TransportClient: k8sclient.NewMockK8sClient(),
+		Logger:          logger.NewTestLogger(),
+	})
+	execCtx := NewExecutionContext(context.Background(), nil, &configloader.Config{})
+	execCtx.SetParam("clusterId", "cluster-1")
+
+	step := configloader.WaitStep{
+		Name:      "clusterReady",
+		APICall:   &configloader.APICall{Method: "GET", URL: "/clusters/{{ .clusterId }}"},
+		Condition: `clusterReady.status.phase == "Ready"`,
+		Timeout:   configloader.Duration(time.Second),
+		Interval:  configloader.Duration(10 * time.Millisecond),
+	}
+	results, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{step}, execCtx)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 1, results[0].Polls)
+	require.Len(t, apiClient.Requests, 1)
+	assert.Contains(t, apiClient.Requests[0].URL, "/clusters/cluster-1")
+}
+
+func TestWaitExecutor_InvalidConditionFailsAtOnce(t *testing.T) {
+	mock := k8sclient.NewMockK8sClient()
+	mock.Resources["default/cluster-config"] = newWaitConfigMap()
+	we := newWaitExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+	step := newResourceWaitStep()
+	step.Condition = "status.phase =="
+	step.Timeout = configloader.Duration(time.Minute)
+	results, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{step}, newWaitExecutionContext())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to evaluate wait condition")
+	require.Len(t, results, 1)
+	assert.Equal(t, 1, results[0].Polls)
+}
+
+// availableAfterMockClient wraps MockK8sClient and reports the ManifestWork as applied, and
+// as available with the namespace phase fed back from the given poll on
+type availableAfterMockClient struct {
+	*k8sclient.MockK8sClient
+	availableAfter int
+	gets           int
+}
+
+func (m *availableAfterMockClient) GetResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	target transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	m.gets++
+	obj, err := m.MockK8sClient.GetResource(ctx, gvk, namespace, name, target)
+	if err != nil {
+		return nil, err
+	}
+	work := obj.DeepCopy()
+	available := "False"
+	if m.gets >= m.availableAfter {
+		available = "True"
+	}
+	err = unstructured.SetNestedField(work.Object, map[string]interface{}{
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Applied", "status": "True", "reason": "AppliedManifestWorkComplete"},
+			map[string]interface{}{"type": "Available", "status": available, "reason": "ResourcesAvailable"},
+		},
+		"resourceStatus": map[string]interface{}{
+			"manifests": []interface{}{
+				map[string]interface{}{
+					"resourceMeta": map[string]interface{}{"kind": "Namespace", "name": "cluster-1"},
+					"statusFeedback": map[string]interface{}{"values": []interface{}{
+						map[string]interface{}{
+							"name":       "phase",
+							"fieldValue": map[string]interface{}{"type": "String", "string": "Active"},
+						},
+					}},
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Available", "status": available, "reason": "ResourceAvailable"},
+					},
+				},
+			},
+		},
+	}, "status")
+	return work, err
+}
+
+func newWaitManifestWork() *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "work.open-cluster-management.io/v1",
+		"kind":       "ManifestWork",
+		"metadata":   map[string]interface{}{"name": "cluster-1-work", "namespace": "cluster-1"},
+	}}
+}
+
+func newManifestWorkWaitContext() *ExecutionContext {
+	execCtx := NewExecutionContext(context.Background(), nil, &configloader.Config{
+		Resources: []configloader.Resource{{
+			Name: "clusterWork",
+			Transport: &configloader.TransportConfig{
+				Client:  configloader.TransportClientMaestro,
+				Maestro: &configloader.MaestroTransportConfig{TargetCluster: "cluster-1"},
+			},
+		}},
+	})
+	execCtx.SetResource("clusterWork", newWaitManifestWork())
+	return execCtx
+}
+
+func TestWaitExecutor_ManifestWork(t *testing.T) {
+	mock := &availableAfterMockClient{MockK8sClient: k8sclient.NewMockK8sClient(), availableAfter: 2}
+	mock.Resources["cluster-1/cluster-1-work"] = newWaitManifestWork()
+	we := newWaitExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+	execCtx := newManifestWorkWaitContext()
+
+	step := configloader.WaitStep{
+		Name:         "workStatus",
+		Resource:     "clusterWork",
+		ManifestWork: &configloader.ManifestWorkWait{},
+		Timeout:      configloader.Duration(time.Second),
+		Interval:     configloader.Duration(10 * time.Millisecond),
+	}
+	results, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{step}, execCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, results[0].Polls)
+
+	status, ok := execCtx.GetParam("workStatus")
+	require.True(t, ok, "the reported status should be stored in the step's param")
+	assert.Equal(t, map[string]interface{}{
+		"conditions": map[string]interface{}{"Applied": "True", "Available": "True"},
+		"manifests": []interface{}{
+			map[string]interface{}{
+				"kind":       "Namespace",
+				"name":       "cluster-1",
+				"namespace":  "",
+				"conditions": map[string]interface{}{"Available": "True"},
+				"values":     map[string]interface{}{"phase": "Active"},
+			},
+		},
+	}, status)
+
+	t.Run("timeout reports the unmet conditions", func(t *testing.T) {
+		mock := &availableAfterMockClient{MockK8sClient: k8sclient.NewMockK8sClient(), availableAfter: 1000}
+		mock.Resources["cluster-1/cluster-1-work"] = newWaitManifestWork()
+		we := newWaitExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		step := step
+		step.Timeout = configloader.Duration(50 * time.Millisecond)
+		_, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{step}, newManifestWorkWaitContext())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "ManifestWork conditions not met within 50ms: Available=False (ResourcesAvailable)")
+	})
+
+	t.Run("condition must hold too", func(t *testing.T) {
+		mock := &availableAfterMockClient{MockK8sClient: k8sclient.NewMockK8sClient(), availableAfter: 1}
+		mock.Resources["cluster-1/cluster-1-work"] = newWaitManifestWork()
+		we := newWaitExecutor(&ExecutorConfig{TransportClient: mock, Logger: logger.NewTestLogger()})
+
+		step := step
+		step.ManifestWork = &configloader.ManifestWorkWait{Conditions: []string{"Applied"}}
+		step.Condition = `status.resourceStatus.manifests.size() == 2`
+		step.Timeout = configloader.Duration(50 * time.Millisecond)
+		_, err := we.ExecuteAll(context.Background(), []configloader.WaitStep{step}, newManifestWorkWaitContext())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "condition \"status.resourceStatus.manifests.size() == 2\" not met")
+	})
+}
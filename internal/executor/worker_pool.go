@@ -0,0 +1,173 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sharding"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// workerQueueSize is how many events each worker buffers before Handle blocks
+const workerQueueSize = 64
+
+// ErrWorkerPoolClosed is returned by WorkerPool.Handle once the pool is closed
+var ErrWorkerPoolClosed = errors.New("worker pool is closed")
+
+// WorkerPool runs a broker handler on a fixed number of workers. Events are assigned to
+// a worker by resource key (the owner's ID when the event has owner references, else the
+// resource ID), so events for the same cluster and its child resources execute one at a
+// time, in the order they were queued, while events for different clusters execute
+// concurrently. Events handed over from concurrent goroutines, such as a broker delivering
+// several messages at once, are queued in the order their calls reach the pool, which is
+// not necessarily the order the broker received them in.
+//
+// Handle returns as soon as the event is queued, so the broker acks it before execution.
+// Events still queued when the process dies are lost; Close drains the queues on a
+// graceful shutdown. HandleAndWait returns once the event has been handled instead.
+type WorkerPool struct {
+	handler func(ctx context.Context, evt *event.Event) error
+	log     logger.Logger
+	// closing is closed when Close starts, to release the enqueues blocked on a full queue
+	closing   chan struct{}
+	queues    []chan poolJob
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+	// mu is read-held by enqueues, so that Close does not close a queue being sent to
+	mu     sync.RWMutex
+	closed bool
+}
+
+type poolJob struct {
+	ctx context.Context
+	evt *event.Event
+	// done is closed once the job has been handled; nil when nobody waits for it
+	done chan struct{}
+}
+
+// NewWorkerPool starts workers goroutines running h. A workers value below 1 starts one.
+func NewWorkerPool(h func(ctx context.Context, evt *event.Event) error, workers int, log logger.Logger) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	p := &WorkerPool{
+		handler: h,
+		log:     log,
+		queues:  make([]chan poolJob, workers),
+		closing: make(chan struct{}),
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan poolJob, workerQueueSize)
+		p.wg.Add(1)
+		go p.work(p.queues[i])
+	}
+	return p
+}
+
+// Handle queues evt on the worker that owns its resource key. It blocks while that
+// worker's queue is full, and fails if ctx is done first or the pool is closed, in which
+// case the broker redelivers the event.
+func (p *WorkerPool) Handle(ctx context.Context, evt *event.Event) error {
+	return p.enqueue(ctx, evt, nil)
+}
+
+// HandleAndWait queues evt like Handle and waits until it has been handled, so that the
+// broker acks it after execution. It fails if ctx is done before evt is queued, in which
+// case the broker redelivers the event. Once queued, it waits for the execution even if
+// ctx is done, so that an event that is executing is never redelivered and run twice.
+func (p *WorkerPool) HandleAndWait(ctx context.Context, evt *event.Event) error {
+	done := make(chan struct{})
+	if err := p.enqueue(ctx, evt, done); err != nil {
+		return err
+	}
+	<-done
+	return nil
+}
+
+// enqueue queues evt on the worker that owns its resource key. done, when not nil, is
+// closed once the event has been handled.
+func (p *WorkerPool) enqueue(ctx context.Context, evt *event.Event, done chan struct{}) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	select {
+	case <-p.closing:
+		return ErrWorkerPoolClosed
+	default:
+	}
+
+	ctx, parsed := withParsedEvent(ctx, evt)
+	queue := p.queues[sharding.ShardOf(eventResourceKey(parsed), len(p.queues))]
+	// The broker may cancel the message context once Handle returns; keep its values
+	// (trace context, log fields) for the execution but not its cancellation.
+	job := poolJob{ctx: context.WithoutCancel(ctx), evt: evt, done: done}
+	select {
+	case queue <- job:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.closing:
+		return ErrWorkerPoolClosed
+	}
+}
+
+// Close stops accepting events and waits until the queued events have been handled or
+// ctx is done. Events blocked on a full queue are rejected with ErrWorkerPoolClosed, so
+// the broker redelivers them.
+func (p *WorkerPool) Close(ctx context.Context) error {
+	p.closeOnce.Do(func() { close(p.closing) })
+
+	done := make(chan struct{})
+	go func() {
+		// The enqueues holding the read lock return now that closing is closed
+		p.mu.Lock()
+		if !p.closed {
+			p.closed = true
+			for _, queue := range p.queues {
+				close(queue)
+			}
+		}
+		p.mu.Unlock()
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *WorkerPool) work(queue <-chan poolJob) {
+	defer p.wg.Done()
+	for job := range queue {
+		p.handle(job)
+	}
+}
+
+// handle runs one job, recovering a panic so that the worker keeps serving its queue
+func (p *WorkerPool) handle(job poolJob) {
+	if job.done != nil {
+		defer close(job.done)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			p.log.Errorf(job.ctx, "panic handling event %s in worker pool (recovered): %v", job.evt.ID(), r)
+		}
+	}()
+	if err := p.handler(job.ctx, job.evt); err != nil {
+		errCtx := logger.WithErrorField(job.ctx, err)
+		p.log.Warnf(errCtx, "Event %s failed in worker pool", job.evt.ID())
+	}
+}
+
+// eventResourceKey returns the resource key of the parsed event, or the event ID when its
+// data cannot be parsed
+func eventResourceKey(parsed *parsedEvent) string {
+	if parsed.err != nil {
+		return parsed.evt.ID()
+	}
+	return resourceKey(parsed.data)
+}
@@ -0,0 +1,242 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/sharding"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func newPoolEvent(t *testing.T, id string, data map[string]interface{}) *event.Event {
+	t.Helper()
+	evt := event.New()
+	evt.SetID(id)
+	evt.SetType("com.hyperfleet.test")
+	evt.SetSource("test")
+	payload, err := json.Marshal(data)
+	require.NoError(t, err)
+	require.NoError(t, evt.SetData(event.ApplicationJSON, payload))
+	return &evt
+}
+
+func TestWorkerPool_OrdersEventsPerCluster(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string][]string{}
+	handler := func(ctx context.Context, evt *event.Event) error {
+		_, parsed := withParsedEvent(ctx, evt)
+		key := eventResourceKey(parsed)
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		seen[key] = append(seen[key], evt.ID())
+		mu.Unlock()
+		return nil
+	}
+	pool := NewWorkerPool(handler, 4, logger.NewTestLogger())
+
+	want := map[string][]string{}
+	for i := 0; i < 10; i++ {
+		for _, cluster := range []string{"cluster-a", "cluster-b", "cluster-c"} {
+			// Node pool events are ordered with the events of their owning cluster
+			data := map[string]interface{}{"id": cluster}
+			if i%2 == 1 {
+				data = map[string]interface{}{
+					"id":               fmt.Sprintf("%s-np", cluster),
+					"owner_references": map[string]interface{}{"id": cluster},
+				}
+			}
+			id := fmt.Sprintf("%s-%d", cluster, i)
+			want[cluster] = append(want[cluster], id)
+			require.NoError(t, pool.Handle(context.Background(), newPoolEvent(t, id, data)))
+		}
+	}
+
+	require.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, want, seen)
+}
+
+func TestWorkerPool_SerializesClusterEventsHandedOverConcurrently(t *testing.T) {
+	var mu sync.Mutex
+	running := map[string]int{}
+	overlaps := 0
+	handled := 0
+	handler := func(ctx context.Context, evt *event.Event) error {
+		_, parsed := withParsedEvent(ctx, evt)
+		key := eventResourceKey(parsed)
+		mu.Lock()
+		running[key]++
+		if running[key] > 1 {
+			overlaps++
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running[key]--
+		handled++
+		mu.Unlock()
+		return nil
+	}
+	pool := NewWorkerPool(handler, 4, logger.NewTestLogger())
+
+	// Like a broker delivering several messages at once, each event is handed over from
+	// its own goroutine
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, cluster := range []string{"cluster-a", "cluster-b"} {
+			data := map[string]interface{}{"id": cluster}
+			if i%2 == 1 {
+				data = map[string]interface{}{
+					"id":               fmt.Sprintf("%s-np", cluster),
+					"owner_references": map[string]interface{}{"id": cluster},
+				}
+			}
+			evt := newPoolEvent(t, fmt.Sprintf("%s-%d", cluster, i), data)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, pool.HandleAndWait(context.Background(), evt))
+			}()
+		}
+	}
+	wg.Wait()
+
+	require.NoError(t, pool.Close(context.Background()))
+	assert.Equal(t, 20, handled)
+	assert.Zero(t, overlaps, "events for the same cluster executed at the same time")
+}
+
+func TestWorkerPool_RunsClustersConcurrently(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan string, 2)
+	handler := func(_ context.Context, evt *event.Event) error {
+		started <- evt.ID()
+		<-release
+		return nil
+	}
+	pool := NewWorkerPool(handler, 2, logger.NewTestLogger())
+
+	// Pick two clusters that land on different workers
+	first := "cluster-0"
+	second := ""
+	for i := 1; second == ""; i++ {
+		candidate := fmt.Sprintf("cluster-%d", i)
+		if sharding.ShardOf(candidate, 2) != sharding.ShardOf(first, 2) {
+			second = candidate
+		}
+	}
+
+	require.NoError(t, pool.Handle(context.Background(), newPoolEvent(t, "e1", map[string]interface{}{"id": first})))
+	require.NoError(t, pool.Handle(context.Background(), newPoolEvent(t, "e2", map[string]interface{}{"id": second})))
+
+	for range 2 {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("events for different clusters did not run concurrently")
+		}
+	}
+	close(release)
+	require.NoError(t, pool.Close(context.Background()))
+}
+
+func TestWorkerPool_Close(t *testing.T) {
+	t.Run("drains queued events", func(t *testing.T) {
+		var mu sync.Mutex
+		handled := 0
+		handler := func(_ context.Context, _ *event.Event) error {
+			time.Sleep(time.Millisecond)
+			mu.Lock()
+			handled++
+			mu.Unlock()
+			return nil
+		}
+		pool := NewWorkerPool(handler, 2, logger.NewTestLogger())
+		for i := 0; i < 20; i++ {
+			evt := newPoolEvent(t, fmt.Sprintf("e%d", i), map[string]interface{}{"id": "cluster-1"})
+			require.NoError(t, pool.Handle(context.Background(), evt))
+		}
+		require.NoError(t, pool.Close(context.Background()))
+		assert.Equal(t, 20, handled)
+	})
+
+	t.Run("rejects events after close", func(t *testing.T) {
+		pool := NewWorkerPool(func(context.Context, *event.Event) error { return nil }, 2, logger.NewTestLogger())
+		require.NoError(t, pool.Close(context.Background()))
+		err := pool.Handle(context.Background(), newPoolEvent(t, "e1", map[string]interface{}{"id": "cluster-1"}))
+		assert.ErrorIs(t, err, ErrWorkerPoolClosed)
+	})
+
+	t.Run("times out waiting for running events", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		pool := NewWorkerPool(func(context.Context, *event.Event) error {
+			<-release
+			return nil
+		}, 1, logger.NewTestLogger())
+		require.NoError(t, pool.Handle(context.Background(), newPoolEvent(t, "e1", map[string]interface{}{"id": "c"})))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, pool.Close(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("does not block on a full queue", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		pool := NewWorkerPool(func(context.Context, *event.Event) error {
+			<-release
+			return nil
+		}, 1, logger.NewTestLogger())
+		// One event runs and workerQueueSize wait, so the next Handle blocks
+		for i := 0; i <= workerQueueSize; i++ {
+			evt := newPoolEvent(t, fmt.Sprintf("e%d", i), map[string]interface{}{"id": "c"})
+			require.NoError(t, pool.Handle(context.Background(), evt))
+		}
+		blocked := make(chan error, 1)
+		go func() {
+			blocked <- pool.Handle(context.Background(), newPoolEvent(t, "blocked", map[string]interface{}{"id": "c"}))
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		assert.ErrorIs(t, pool.Close(ctx), context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second, "Close should return at its deadline")
+
+		select {
+		case err := <-blocked:
+			assert.ErrorIs(t, err, ErrWorkerPoolClosed)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Handle stayed blocked on the full queue after Close")
+		}
+	})
+}
+
+func TestWorkerPool_RecoversPanics(t *testing.T) {
+	done := make(chan struct{})
+	handler := func(_ context.Context, evt *event.Event) error {
+		if evt.ID() == "boom" {
+			panic("boom")
+		}
+		close(done)
+		return nil
+	}
+	pool := NewWorkerPool(handler, 1, logger.NewTestLogger())
+	require.NoError(t, pool.Handle(context.Background(), newPoolEvent(t, "boom", map[string]interface{}{"id": "c"})))
+	require.NoError(t, pool.Handle(context.Background(), newPoolEvent(t, "ok", map[string]interface{}{"id": "c"})))
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker stopped after a panic")
+	}
+	require.NoError(t, pool.Close(context.Background()))
+}
@@ -0,0 +1,721 @@
+// Package gitopsclient is a transport client that commits rendered manifests to a Git
+// repository instead of applying them, for a GitOps controller such as Argo CD or Flux to
+// sync to the cluster. Each resource is a YAML file under the directory of its transport
+// path; applies and deletes are commits, pushed to a branch or proposed as a pull request.
+package gitopsclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// Default configuration values
+const (
+	DefaultBranch       = "main"
+	DefaultAuthorName   = "hyperfleet-adapter"
+	DefaultAuthorEmail  = "hyperfleet-adapter@hyperfleet.io"
+	DefaultAPIURL       = "https://api.github.com"
+	DefaultBranchPrefix = "hyperfleet/"
+	DefaultHTTPTimeout  = 10 * time.Second
+)
+
+// maxPushAttempts bounds the retries of a commit whose push was rejected because the
+// branch moved on, e.g. after another adapter replica pushed
+const maxPushAttempts = 3
+
+// errPushRejected is returned when the remote rejects a push that is not a fast-forward
+var errPushRejected = errors.New("push rejected: the branch moved on")
+
+// Config holds configuration for creating a GitOps client
+type Config struct {
+	// PullRequest opens a pull request for the commits instead of pushing them to Branch
+	PullRequest *PullRequestConfig
+	// Repository is the URL of the Git repository
+	Repository string
+	// Branch is the branch the manifests are committed to, or the base of the pull requests
+	Branch string
+	// TokenPath is the path to a file holding the token authenticating HTTPS pushes and
+	// pull requests. Empty uses the credentials of the environment.
+	TokenPath string
+	// AuthorName and AuthorEmail sign the commits
+	AuthorName  string
+	AuthorEmail string
+	// WorkDir is the directory the repository is cloned into. Empty uses a temporary directory.
+	WorkDir string
+}
+
+// PullRequestConfig configures the pull requests of the client
+type PullRequestConfig struct {
+	// Repository is the GitHub repository of the pull requests, as owner/name
+	Repository string
+	// APIURL is the GitHub API URL
+	APIURL string
+	// BranchPrefix prefixes the branch of each manifest directory
+	BranchPrefix string
+}
+
+// TransportContext carries per-request routing information for the GitOps transport backend.
+// Pass this as the TransportContext (any) in ApplyResource or method parameters.
+type TransportContext struct {
+	// Path is the directory of the repository the manifests are written to.
+	// Required for all GitOps operations.
+	Path string
+	// CommitMessage is the message of the commits. Empty describes the operation.
+	CommitMessage string
+}
+
+// Client commits manifests to a Git repository. Operations are serialized, since they
+// share one working tree.
+type Client struct {
+	log        logger.Logger
+	httpClient *http.Client
+	// openPullRequests are the branches known to have an open pull request
+	openPullRequests map[string]bool
+	config           Config
+	dir              string
+	mu               sync.Mutex
+}
+
+// Ensure Client implements transportclient.TransportClient
+var _ transportclient.TransportClient = (*Client)(nil)
+
+// NewClient creates a GitOps client, preparing a local repository tracking the configured
+// one. Nothing is fetched until the first operation.
+func NewClient(ctx context.Context, config Config, log logger.Logger) (*Client, error) {
+	if config.Repository == "" {
+		return nil, errors.New("gitops repository is required")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("the gitops transport needs the git command: %w", err)
+	}
+	if config.Branch == "" {
+		config.Branch = DefaultBranch
+	}
+	if config.AuthorName == "" {
+		config.AuthorName = DefaultAuthorName
+	}
+	if config.AuthorEmail == "" {
+		config.AuthorEmail = DefaultAuthorEmail
+	}
+	if pr := config.PullRequest; pr != nil {
+		if pr.Repository == "" {
+			return nil, errors.New("gitops pull_request.repository is required")
+		}
+		if pr.APIURL == "" {
+			pr.APIURL = DefaultAPIURL
+		}
+		if pr.BranchPrefix == "" {
+			pr.BranchPrefix = DefaultBranchPrefix
+		}
+	}
+
+	dir := config.WorkDir
+	if dir == "" {
+		tmp, err := os.MkdirTemp("", "hyperfleet-gitops-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the gitops work directory: %w", err)
+		}
+		dir = tmp
+	} else if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create the gitops work directory: %w", err)
+	}
+
+	c := &Client{
+		config:           config,
+		log:              log,
+		dir:              dir,
+		httpClient:       &http.Client{Timeout: DefaultHTTPTimeout},
+		openPullRequests: make(map[string]bool),
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if _, err := c.git(ctx, "init", "--quiet"); err != nil {
+			return nil, err
+		}
+		if _, err := c.git(ctx, "remote", "add", "origin", config.Repository); err != nil {
+			return nil, err
+		}
+	} else if _, err := c.git(ctx, "remote", "set-url", "origin", config.Repository); err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "GitOps client ready: repository=%s branch=%s pull_requests=%t",
+		config.Repository, config.Branch, config.PullRequest != nil)
+	return c, nil
+}
+
+// Ping checks that the repository is reachable with the configured credentials
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.git(ctx, "ls-remote", "--heads", "origin"); err != nil {
+		return fmt.Errorf("gitops repository is not reachable: %w", err)
+	}
+	return nil
+}
+
+// ApplyResource writes a manifest to the repository, implementing
+// transportclient.TransportClient. The file is compared with the manifest by generation,
+// like the other transports compare the live resource: an unchanged generation is skipped.
+// RecreateOnChange and ServerSideApply do not apply to files and are ignored.
+func (c *Client) ApplyResource(
+	ctx context.Context,
+	manifestBytes []byte,
+	opts *transportclient.ApplyOptions,
+	target transportclient.TransportContext,
+) (*transportclient.ApplyResult, error) {
+	if len(manifestBytes) == 0 {
+		return nil, fmt.Errorf("manifest bytes cannot be empty")
+	}
+	tc, err := c.resolveTransportContext(target)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := parseManifest(manifestBytes)
+	if err != nil {
+		return nil, err
+	}
+	if err = manifest.ValidateGenerationFromUnstructured(obj); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	if opts == nil {
+		opts = &transportclient.ApplyOptions{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result *transportclient.ApplyResult
+	err = c.commitWithRetry(ctx, tc, func() (string, error) {
+		written, file, writeErr := c.writeManifest(tc, obj, opts)
+		if writeErr != nil {
+			return "", writeErr
+		}
+		result = written
+		if file == "" {
+			return "", nil
+		}
+		if _, addErr := c.git(ctx, "add", "--", file); addErr != nil {
+			return "", addErr
+		}
+		return commitMessage(tc, string(result.Operation), obj.GetKind(), obj.GetNamespace(), obj.GetName()), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.log.Debugf(ctx, "GitOps ApplyResource %s/%s: operation=%s reason=%s",
+		obj.GetKind(), obj.GetName(), result.Operation, result.Reason)
+	return result, nil
+}
+
+// writeManifest compares the manifest with its file and writes it when it changed. It
+// returns the path of the written file, empty when the manifest was skipped.
+func (c *Client) writeManifest(
+	tc *TransportContext,
+	obj *unstructured.Unstructured,
+	opts *transportclient.ApplyOptions,
+) (*transportclient.ApplyResult, string, error) {
+	file, err := resourceFile(tc.Path, obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	if err != nil {
+		return nil, "", err
+	}
+	existing, err := c.readFile(file)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, "", err
+	}
+	if apierrors.IsNotFound(err) {
+		existing = nil
+	}
+
+	var existingGen int64
+	if existing != nil {
+		existingGen = manifest.GetGenerationFromUnstructured(existing)
+	}
+	decision := manifest.CompareGenerations(
+		manifest.GetGenerationFromUnstructured(obj), existingGen, existing != nil)
+	result := &transportclient.ApplyResult{Operation: decision.Operation, Reason: decision.Reason}
+
+	// The file may have been edited in the repository since it was written
+	if decision.Operation == manifest.OperationSkip && existing != nil && opts.DetectDrift {
+		if drift := manifest.Diff(existing, obj); len(drift) > 0 {
+			result.Drift = drift
+			result.Reason = fmt.Sprintf("%s, drift detected in %d field(s)", decision.Reason, len(drift))
+			if opts.ReconcileDrift {
+				result.Operation = manifest.OperationUpdate
+			}
+		}
+	}
+	if result.Operation == manifest.OperationSkip {
+		return result, "", nil
+	}
+	if existing != nil {
+		result.Changes = manifest.Diff(existing, obj)
+	}
+
+	data, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal %s/%s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	abs := filepath.Join(c.dir, file)
+	if err := os.MkdirAll(filepath.Dir(abs), 0o750); err != nil {
+		return nil, "", fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	if err := os.WriteFile(abs, data, 0o600); err != nil {
+		return nil, "", fmt.Errorf("failed to write %s: %w", file, err)
+	}
+	return result, file, nil
+}
+
+// GetResource reads a resource from the repository, implementing
+// transportclient.TransportClient. Returns a NotFound error when its file does not exist.
+func (c *Client) GetResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	target transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	tc, err := c.resolveTransportContext(target)
+	if err != nil {
+		return nil, err
+	}
+	file, err := resourceFile(tc.Path, gvk.Kind, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err = c.checkoutForRead(ctx, tc.Path); err != nil {
+		return nil, err
+	}
+	obj, err := c.readFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if obj.GroupVersionKind() != gvk {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: gvk.Group, Resource: gvk.Kind}, name)
+	}
+	return obj, nil
+}
+
+// DiscoverResources finds resources in the repository, implementing
+// transportclient.TransportClient. Every manifest under the transport path is a candidate,
+// filtered by GVK and the discovery criteria.
+func (c *Client) DiscoverResources(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	discovery manifest.Discovery,
+	target transportclient.TransportContext,
+) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk)
+	if discovery == nil {
+		return list, nil
+	}
+	if discovery.IsSingleResource() {
+		obj, err := c.GetResource(ctx, gvk, discovery.GetNamespace(), discovery.GetName(), target)
+		if err != nil {
+			return list, err
+		}
+		if manifest.MatchesDiscoveryCriteria(obj, discovery) {
+			list.Items = []unstructured.Unstructured{*obj}
+		}
+		return list, nil
+	}
+
+	tc, err := c.resolveTransportContext(target)
+	if err != nil {
+		return nil, err
+	}
+	root, err := cleanPath(tc.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.checkoutForRead(ctx, tc.Path); err != nil {
+		return nil, err
+	}
+	walkErr := filepath.WalkDir(filepath.Join(c.dir, root), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != ".yaml" {
+			return nil
+		}
+		data, err := os.ReadFile(path) // #nosec G304 -- the path is under the work directory
+		if err != nil {
+			return err
+		}
+		obj, err := parseManifest(data)
+		if err != nil {
+			c.log.Warnf(ctx, "GitOps discovery: skipping %s: %v", path, err)
+			return nil
+		}
+		if obj.GroupVersionKind() == gvk && manifest.MatchesDiscoveryCriteria(obj, discovery) {
+			list.Items = append(list.Items, *obj)
+		}
+		return nil
+	})
+	if walkErr != nil {
+		return nil, fmt.Errorf("failed to discover %s in %s: %w", gvk.Kind, root, walkErr)
+	}
+	return list, nil
+}
+
+// DeleteResource removes a resource's file from the repository, implementing
+// transportclient.TransportClient. The propagationPolicy in opts is ignored: the GitOps
+// controller prunes the resource. Returns nil if the file does not exist.
+func (c *Client) DeleteResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	_ *transportclient.DeleteOptions,
+	target transportclient.TransportContext,
+) error {
+	tc, err := c.resolveTransportContext(target)
+	if err != nil {
+		return err
+	}
+	file, err := resourceFile(tc.Path, gvk.Kind, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.commitWithRetry(ctx, tc, func() (string, error) {
+		if _, err := os.Stat(filepath.Join(c.dir, file)); os.IsNotExist(err) {
+			return "", nil
+		}
+		if _, err := c.git(ctx, "rm", "--quiet", "--", file); err != nil {
+			return "", err
+		}
+		return commitMessage(tc, "delete", gvk.Kind, namespace, name), nil
+	})
+}
+
+// commitWithRetry checks out the branch of the transport path, lets change edit the
+// working tree and commits the result. change returns the commit message, empty when
+// there is nothing to commit. A rejected push is retried on top of the new branch head.
+func (c *Client) commitWithRetry(ctx context.Context, tc *TransportContext, change func() (string, error)) error {
+	branch := c.writeBranch(tc.Path)
+	for attempt := 1; ; attempt++ {
+		if err := c.checkout(ctx, branch); err != nil {
+			return err
+		}
+		message, err := change()
+		if err != nil || message == "" {
+			return err
+		}
+		if _, err = c.git(ctx, "commit", "--quiet", "--message", message); err != nil {
+			return err
+		}
+		err = c.push(ctx, branch)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errPushRejected) || attempt == maxPushAttempts {
+			return err
+		}
+		c.log.Debugf(ctx, "GitOps push to %s rejected, retrying (attempt %d/%d)", branch, attempt, maxPushAttempts)
+	}
+	if c.config.PullRequest != nil {
+		return c.ensurePullRequest(ctx, branch, tc.Path)
+	}
+	return nil
+}
+
+// writeBranch returns the branch the changes of a transport path are committed to: the
+// configured branch, or the pull request branch of the path
+func (c *Client) writeBranch(path string) string {
+	if c.config.PullRequest == nil {
+		return c.config.Branch
+	}
+	return c.config.PullRequest.BranchPrefix + branchName(path)
+}
+
+// checkoutForRead checks out the branch a transport path is read from: its pull request
+// branch while one is open, so that reads see the changes awaiting review
+func (c *Client) checkoutForRead(ctx context.Context, path string) error {
+	branch := c.writeBranch(path)
+	if branch != c.config.Branch {
+		exists, err := c.remoteBranchExists(ctx, branch)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			branch = c.config.Branch
+		}
+	}
+	return c.checkout(ctx, branch)
+}
+
+// checkout resets the working tree to the remote head of branch. A branch missing from
+// the remote starts from the configured branch, or empty when the repository is.
+func (c *Client) checkout(ctx context.Context, branch string) error {
+	exists, err := c.remoteBranchExists(ctx, branch)
+	if err != nil {
+		return err
+	}
+	from := branch
+	if !exists {
+		// A pull request branch that was merged and deleted starts over
+		delete(c.openPullRequests, branch)
+		from = ""
+		if branch != c.config.Branch {
+			baseExists, baseErr := c.remoteBranchExists(ctx, c.config.Branch)
+			if baseErr != nil {
+				return baseErr
+			}
+			if baseExists {
+				from = c.config.Branch
+			}
+		}
+	}
+
+	if from == "" {
+		if _, err = c.git(ctx, "symbolic-ref", "HEAD", "refs/heads/"+branch); err != nil {
+			return err
+		}
+		if _, err = c.git(ctx, "update-ref", "-d", "refs/heads/"+branch); err != nil {
+			return err
+		}
+		if _, err = c.git(ctx, "read-tree", "--empty"); err != nil {
+			return err
+		}
+		_, err = c.git(ctx, "clean", "-fdxq")
+		return err
+	}
+
+	remoteRef := "refs/remotes/origin/" + from
+	if _, err = c.git(ctx, "fetch", "--quiet", "origin", "+refs/heads/"+from+":"+remoteRef); err != nil {
+		return err
+	}
+	if _, err = c.git(ctx, "checkout", "--quiet", "--force", "-B", branch, remoteRef); err != nil {
+		return err
+	}
+	_, err = c.git(ctx, "clean", "-fdxq")
+	return err
+}
+
+// remoteBranchExists reports whether the remote has branch
+func (c *Client) remoteBranchExists(ctx context.Context, branch string) (bool, error) {
+	out, err := c.git(ctx, "ls-remote", "--heads", "origin", "refs/heads/"+branch)
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// push pushes the checked out branch, returning errPushRejected when the remote branch
+// moved on since it was fetched
+func (c *Client) push(ctx context.Context, branch string) error {
+	_, err := c.git(ctx, "push", "--quiet", "origin", "HEAD:refs/heads/"+branch)
+	if err != nil && (strings.Contains(err.Error(), "[rejected]") ||
+		strings.Contains(err.Error(), "non-fast-forward") || strings.Contains(err.Error(), "fetch first")) {
+		return fmt.Errorf("%w: %w", errPushRejected, err)
+	}
+	return err
+}
+
+// ensurePullRequest opens a pull request of branch against the configured branch, unless
+// one is already open
+func (c *Client) ensurePullRequest(ctx context.Context, branch, path string) error {
+	if c.openPullRequests[branch] {
+		return nil
+	}
+	pr := c.config.PullRequest
+	body, err := json.Marshal(map[string]string{
+		"title": "HyperFleet: update " + path,
+		"head":  branch,
+		"base":  c.config.Branch,
+		"body":  "Manifests written by the HyperFleet adapter to " + path + ".",
+	})
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(pr.APIURL, "/") + "/repos/" + pr.Repository + "/pulls"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	token, err := c.token()
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to open a pull request for %s: %w", branch, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close, the body is not read
+	// 422 is returned when a pull request of the branch is already open
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusUnprocessableEntity {
+		return fmt.Errorf("failed to open a pull request for %s: %s returned %d", branch, url, resp.StatusCode)
+	}
+	if resp.StatusCode == http.StatusCreated {
+		c.log.Infof(ctx, "GitOps opened a pull request of %s against %s", branch, c.config.Branch)
+	}
+	c.openPullRequests[branch] = true
+	return nil
+}
+
+// readFile parses the manifest of a file of the working tree, relative to its root.
+// Returns a NotFound error when the file does not exist.
+func (c *Client) readFile(file string) (*unstructured.Unstructured, error) {
+	data, err := os.ReadFile(filepath.Join(c.dir, file)) // #nosec G304 -- the path is under the work directory
+	if os.IsNotExist(err) {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "file"}, file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", file, err)
+	}
+	return parseManifest(data)
+}
+
+// git runs a git command in the work directory. The token, if any, is passed as an HTTP
+// header through the environment rather than the command line or the remote URL, so that
+// it never shows in process listings or error messages.
+func (c *Client) git(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 -- arguments are not shell-interpreted
+	cmd.Dir = c.dir
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_AUTHOR_NAME="+c.config.AuthorName,
+		"GIT_AUTHOR_EMAIL="+c.config.AuthorEmail,
+		"GIT_COMMITTER_NAME="+c.config.AuthorName,
+		"GIT_COMMITTER_EMAIL="+c.config.AuthorEmail,
+	)
+	token, err := c.token()
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+credentials,
+		)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// token reads the token file, re-read on every use so that a rotated token is picked up
+func (c *Client) token() (string, error) {
+	if c.config.TokenPath == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(c.config.TokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read gitops token: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// resolveTransportContext extracts the gitops TransportContext from the generic transport
+// context. Unlike maestro, every operation needs it: the path locates the files.
+func (c *Client) resolveTransportContext(target transportclient.TransportContext) (*TransportContext, error) {
+	tc, ok := target.(*TransportContext)
+	if !ok || tc == nil || tc.Path == "" {
+		return nil, errors.New("gitops transport context with a path is required")
+	}
+	return tc, nil
+}
+
+// parseManifest parses YAML or JSON bytes into an unstructured object
+func parseManifest(data []byte) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, &obj.Object); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	if obj.Object == nil || obj.GetKind() == "" || obj.GetName() == "" {
+		return nil, errors.New("failed to parse manifest: kind and metadata.name are required")
+	}
+	return obj, nil
+}
+
+// cleanPath validates a transport path, which must stay inside the repository
+func cleanPath(path string) (string, error) {
+	cleaned := filepath.Clean(strings.TrimPrefix(path, "/"))
+	if !filepath.IsLocal(cleaned) {
+		return "", fmt.Errorf("gitops path %q must be a directory inside the repository", path)
+	}
+	return cleaned, nil
+}
+
+// resourceFile returns the file of a resource, relative to the repository root:
+// <path>/<namespace>/<kind>-<name>.yaml, without the namespace directory for
+// cluster-scoped resources
+func resourceFile(path, kind, namespace, name string) (string, error) {
+	root, err := cleanPath(path)
+	if err != nil {
+		return "", err
+	}
+	base := strings.ToLower(kind) + "-" + name + ".yaml"
+	if strings.ContainsAny(base, `/\`) || strings.ContainsAny(namespace, `/\`) || namespace == ".." {
+		return "", fmt.Errorf("invalid resource %s %s/%s", kind, namespace, name)
+	}
+	if namespace == "" {
+		return filepath.Join(root, base), nil
+	}
+	return filepath.Join(root, namespace, base), nil
+}
+
+// unsafeBranchChars are the characters replaced in the branch name of a path
+var unsafeBranchChars = regexp.MustCompile(`[^A-Za-z0-9._/-]+`)
+
+// branchName turns a transport path into a branch name
+func branchName(path string) string {
+	name := unsafeBranchChars.ReplaceAllString(strings.Trim(path, "/"), "-")
+	return strings.ReplaceAll(name, "..", "-")
+}
+
+// commitMessage returns the configured commit message, or one describing the operation,
+// e.g. "Update ConfigMap default/app"
+func commitMessage(tc *TransportContext, operation, kind, namespace, name string) string {
+	if tc.CommitMessage != "" {
+		return tc.CommitMessage
+	}
+	ref := name
+	if namespace != "" {
+		ref = namespace + "/" + name
+	}
+	verb := operation
+	if verb != "" {
+		verb = strings.ToUpper(verb[:1]) + verb[1:]
+	}
+	return fmt.Sprintf("%s %s %s", verb, kind, ref)
+}
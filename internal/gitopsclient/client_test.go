@@ -0,0 +1,213 @@
+package gitopsclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+var configMapGVK = schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+// newRemote creates an empty bare repository to push to
+func newRemote(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	remote := filepath.Join(t.TempDir(), "fleet.git")
+	out, err := exec.Command("git", "init", "--quiet", "--bare", remote).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return remote
+}
+
+// remoteFile returns the content of a file on a branch of the remote
+func remoteFile(t *testing.T, remote, branch, file string) string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", remote, "show", branch+":"+file).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return string(out)
+}
+
+// remoteLog returns the commit subjects of a branch of the remote, newest first
+func remoteLog(t *testing.T, remote, branch string) []string {
+	t.Helper()
+	out, err := exec.Command("git", "--git-dir", remote, "log", "--format=%s", branch).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return strings.Split(strings.TrimSpace(string(out)), "\n")
+}
+
+func newTestClient(t *testing.T, config Config) *Client {
+	t.Helper()
+	config.WorkDir = filepath.Join(t.TempDir(), "work")
+	client, err := NewClient(context.Background(), config, logger.NewTestLogger())
+	require.NoError(t, err)
+	return client
+}
+
+func configMap(name, generation, value string) []byte {
+	return []byte(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: ` + name + `
+  namespace: cluster-1
+  labels:
+    app: demo
+  annotations:
+    hyperfleet.io/generation: "` + generation + `"
+data:
+  key: ` + value + `
+`)
+}
+
+func TestClient_ApplyResource(t *testing.T) {
+	remote := newRemote(t)
+	client := newTestClient(t, Config{Repository: remote})
+	ctx := context.Background()
+	target := &TransportContext{Path: "clusters/cluster-1"}
+
+	result, err := client.ApplyResource(ctx, configMap("app", "1", "one"), nil, target)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationCreate, result.Operation)
+	file := remoteFile(t, remote, "main", "clusters/cluster-1/cluster-1/configmap-app.yaml")
+	assert.Contains(t, file, "key: one")
+
+	result, err = client.ApplyResource(ctx, configMap("app", "1", "one"), nil, target)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationSkip, result.Operation, "an unchanged generation should not commit")
+
+	target.CommitMessage = "Roll out generation 2 to cluster-1"
+	result, err = client.ApplyResource(ctx, configMap("app", "2", "two"), nil, target)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationUpdate, result.Operation)
+	assert.Contains(t, manifest.FormatChanges(result.Changes), `data.key: "one" -> "two"`)
+
+	assert.Equal(t, []string{"Roll out generation 2 to cluster-1", "Create ConfigMap cluster-1/app"},
+		remoteLog(t, remote, "main"))
+}
+
+func TestClient_GetDiscoverDelete(t *testing.T) {
+	remote := newRemote(t)
+	client := newTestClient(t, Config{Repository: remote})
+	ctx := context.Background()
+	target := &TransportContext{Path: "clusters/cluster-1"}
+
+	_, err := client.GetResource(ctx, configMapGVK, "cluster-1", "app", target)
+	assert.True(t, apierrors.IsNotFound(err), "an empty repository should have no resources: %v", err)
+
+	for _, name := range []string{"app", "other"} {
+		_, err = client.ApplyResource(ctx, configMap(name, "1", name), nil, target)
+		require.NoError(t, err)
+	}
+
+	obj, err := client.GetResource(ctx, configMapGVK, "cluster-1", "app", target)
+	require.NoError(t, err)
+	assert.Equal(t, "app", obj.GetName())
+
+	list, err := client.DiscoverResources(ctx, configMapGVK,
+		&manifest.DiscoveryConfig{Namespace: "cluster-1", LabelSelector: "app=demo"}, target)
+	require.NoError(t, err)
+	assert.Len(t, list.Items, 2)
+
+	// Another client, such as another replica, reads what was pushed
+	other := newTestClient(t, Config{Repository: remote})
+	require.NoError(t, other.DeleteResource(ctx, configMapGVK, "cluster-1", "app", nil, target))
+	require.NoError(t, other.DeleteResource(ctx, configMapGVK, "cluster-1", "app", nil, target),
+		"deleting a missing resource should succeed")
+
+	_, err = client.GetResource(ctx, configMapGVK, "cluster-1", "app", target)
+	assert.True(t, apierrors.IsNotFound(err), "the first client should see the deletion: %v", err)
+	assert.Equal(t, "Delete ConfigMap cluster-1/app", remoteLog(t, remote, "main")[0])
+}
+
+func TestClient_FollowsOtherWriters(t *testing.T) {
+	remote := newRemote(t)
+	first := newTestClient(t, Config{Repository: remote})
+	second := newTestClient(t, Config{Repository: remote})
+	ctx := context.Background()
+	target := &TransportContext{Path: "clusters/cluster-1"}
+
+	_, err := first.ApplyResource(ctx, configMap("a", "1", "a"), nil, target)
+	require.NoError(t, err)
+	_, err = second.ApplyResource(ctx, configMap("b", "1", "b"), nil, target)
+	require.NoError(t, err)
+	_, err = first.ApplyResource(ctx, configMap("c", "1", "c"), nil, target)
+	require.NoError(t, err, "a client behind the remote should commit on top of the new head")
+
+	assert.Len(t, remoteLog(t, remote, "main"), 3)
+}
+
+func TestClient_PullRequest(t *testing.T) {
+	remote := newRemote(t)
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("s3cret\n"), 0o600))
+
+	var requests []map[string]string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/org/fleet/pulls", r.URL.Path)
+		assert.Equal(t, "Bearer s3cret", r.Header.Get("Authorization"))
+		var body map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(api.Close)
+
+	// Seed the base branch
+	seed := newTestClient(t, Config{Repository: remote})
+	_, err := seed.ApplyResource(context.Background(),
+		configMap("base", "1", "base"), nil, &TransportContext{Path: "clusters/cluster-1"})
+	require.NoError(t, err)
+
+	client := newTestClient(t, Config{
+		Repository: remote,
+		TokenPath:  tokenPath,
+		PullRequest: &PullRequestConfig{
+			Repository: "org/fleet",
+			APIURL:     api.URL,
+		},
+	})
+	ctx := context.Background()
+	target := &TransportContext{Path: "clusters/cluster-1"}
+	for _, name := range []string{"app", "other"} {
+		_, err = client.ApplyResource(ctx, configMap(name, "1", name), nil, target)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, requests, 1, "one pull request should collect the commits of a path")
+	assert.Equal(t, "hyperfleet/clusters/cluster-1", requests[0]["head"])
+	assert.Equal(t, "main", requests[0]["base"])
+	assert.Len(t, remoteLog(t, remote, "main"), 1, "the base branch should be left to the pull request")
+	assert.Len(t, remoteLog(t, remote, "hyperfleet/clusters/cluster-1"), 3)
+
+	// Reads see the changes awaiting review
+	obj, err := client.GetResource(ctx, configMapGVK, "cluster-1", "app", target)
+	require.NoError(t, err)
+	assert.Equal(t, "app", obj.GetName())
+}
+
+func TestClient_RequiresPath(t *testing.T) {
+	client := newTestClient(t, Config{Repository: newRemote(t)})
+	ctx := context.Background()
+
+	_, err := client.ApplyResource(ctx, configMap("app", "1", "one"), nil, nil)
+	assert.ErrorContains(t, err, "path is required")
+
+	_, err = client.ApplyResource(ctx, configMap("app", "1", "one"), &transportclient.ApplyOptions{},
+		&TransportContext{Path: "../outside"})
+	assert.ErrorContains(t, err, "must be a directory inside the repository")
+}
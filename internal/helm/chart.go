@@ -0,0 +1,245 @@
+// Package helm renders Helm charts into Kubernetes manifests without a Helm installation.
+//
+// It implements the part of Helm that produces manifests, as `helm template` does: chart
+// loading from a directory or a .tgz archive, values merging, subcharts, and the Go
+// templates with the built-in objects and the common Sprig functions. Hooks are skipped
+// and lookup never finds anything, since rendering has no access to the cluster.
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxChartSize caps the uncompressed size of a chart, so a malformed archive cannot
+// exhaust memory
+const maxChartSize = 32 << 20
+
+// Metadata is the content of Chart.yaml used for rendering
+type Metadata struct {
+	Name         string       `yaml:"name"`
+	Version      string       `yaml:"version"`
+	AppVersion   string       `yaml:"appVersion"`
+	APIVersion   string       `yaml:"apiVersion"`
+	Type         string       `yaml:"type"`
+	Description  string       `yaml:"description"`
+	Dependencies []Dependency `yaml:"dependencies"`
+}
+
+// Dependency is a Chart.yaml dependency. Only the subcharts vendored in charts/ are
+// rendered; Condition disables one from the parent's values.
+type Dependency struct {
+	Name      string `yaml:"name"`
+	Alias     string `yaml:"alias"`
+	Condition string `yaml:"condition"`
+}
+
+// File is a file of a chart, with its path relative to the chart root
+type File struct {
+	Name string
+	Data []byte
+}
+
+// Chart is a chart loaded into memory
+type Chart struct {
+	Values    map[string]interface{}
+	Metadata  Metadata
+	Templates []File
+	// CRDs are the files in crds/, applied before the templates without rendering
+	CRDs []File
+	// Files are the other files, readable with .Files.Get
+	Files        []File
+	Dependencies []*Chart
+}
+
+// Load loads a chart from a directory or a .tgz archive
+func Load(chartPath string) (*Chart, error) {
+	info, err := os.Stat(chartPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart %q: %w", chartPath, err)
+	}
+	if info.IsDir() {
+		return LoadDir(chartPath)
+	}
+	data, err := os.ReadFile(filepath.Clean(chartPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart %q: %w", chartPath, err)
+	}
+	return LoadArchive(data)
+}
+
+// LoadDir loads a chart from a directory
+func LoadDir(dir string) (*Chart, error) {
+	files := map[string][]byte{}
+	var total int64
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if total += info.Size(); total > maxChartSize {
+			return fmt.Errorf("chart exceeds %d bytes", maxChartSize)
+		}
+		data, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart directory %q: %w", dir, err)
+	}
+	return loadFiles(files)
+}
+
+// LoadArchive loads a chart from a gzipped tar archive, as made by `helm package`. The
+// files are expected under a single top-level directory named after the chart.
+func LoadArchive(data []byte) (*Chart, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read chart archive: %w", err)
+	}
+	defer gz.Close() //nolint:errcheck // read-only
+
+	files := map[string][]byte{}
+	tr := tar.NewReader(io.LimitReader(gz, maxChartSize+1))
+	var total int
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chart archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		parts := strings.SplitN(name, "/", 2)
+		if len(parts) != 2 || strings.HasPrefix(name, "../") {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from chart archive: %w", name, err)
+		}
+		if total += len(content); total > maxChartSize {
+			return nil, fmt.Errorf("chart archive exceeds %d bytes", maxChartSize)
+		}
+		files[parts[1]] = content
+	}
+	return loadFiles(files)
+}
+
+// loadFiles builds a chart from its files, keyed by slash-separated path relative to
+// the chart root
+func loadFiles(files map[string][]byte) (*Chart, error) {
+	chartYAML, ok := files["Chart.yaml"]
+	if !ok {
+		return nil, fmt.Errorf("no Chart.yaml found")
+	}
+	chart := &Chart{}
+	if err := yaml.Unmarshal(chartYAML, &chart.Metadata); err != nil {
+		return nil, fmt.Errorf("invalid Chart.yaml: %w", err)
+	}
+	if chart.Metadata.Name == "" {
+		return nil, fmt.Errorf("invalid Chart.yaml: name is required")
+	}
+	if chart.Metadata.Type == "library" {
+		return nil, fmt.Errorf("chart %s is a library chart and cannot be rendered on its own", chart.Metadata.Name)
+	}
+
+	if values, ok := files["values.yaml"]; ok {
+		if err := yaml.Unmarshal(values, &chart.Values); err != nil {
+			return nil, fmt.Errorf("chart %s: invalid values.yaml: %w", chart.Metadata.Name, err)
+		}
+	}
+	if chart.Values == nil {
+		chart.Values = map[string]interface{}{}
+	}
+
+	subcharts := map[string]map[string][]byte{}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data := files[name]
+		switch {
+		case name == "Chart.yaml" || name == "values.yaml":
+		case strings.HasPrefix(name, "templates/"):
+			chart.Templates = append(chart.Templates, File{Name: name, Data: data})
+		case strings.HasPrefix(name, "crds/"):
+			chart.CRDs = append(chart.CRDs, File{Name: name, Data: data})
+		case strings.HasPrefix(name, "charts/"):
+			rest := strings.TrimPrefix(name, "charts/")
+			if dir, file, ok := strings.Cut(rest, "/"); ok {
+				if subcharts[dir] == nil {
+					subcharts[dir] = map[string][]byte{}
+				}
+				subcharts[dir][file] = data
+				continue
+			}
+			if strings.HasSuffix(rest, ".tgz") {
+				sub, err := LoadArchive(data)
+				if err != nil {
+					return nil, fmt.Errorf("chart %s: subchart %s: %w", chart.Metadata.Name, rest, err)
+				}
+				chart.Dependencies = append(chart.Dependencies, sub)
+			}
+		default:
+			chart.Files = append(chart.Files, File{Name: name, Data: data})
+		}
+	}
+
+	dirs := make([]string, 0, len(subcharts))
+	for dir := range subcharts {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		sub, err := loadFiles(subcharts[dir])
+		if err != nil {
+			return nil, fmt.Errorf("chart %s: subchart %s: %w", chart.Metadata.Name, dir, err)
+		}
+		chart.Dependencies = append(chart.Dependencies, sub)
+	}
+
+	return chart, nil
+}
+
+// dependency returns the Chart.yaml entry of a subchart, if there is one
+func (c *Chart) dependency(sub *Chart) (Dependency, bool) {
+	for _, d := range c.Metadata.Dependencies {
+		if d.Name == sub.Metadata.Name {
+			return d, true
+		}
+	}
+	return Dependency{}, false
+}
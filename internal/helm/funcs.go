@@ -0,0 +1,149 @@
+package helm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// maxIncludeDepth stops templates that include themselves, as Helm does
+const maxIncludeDepth = 1000
+
+// funcMap returns the functions available to chart templates, as Helm's engine defines
+// them: the adapter's template functions, overridden by all of Sprig but env and
+// expandenv, and the Helm functions toYaml, fromYaml, toJson, fromJson and their array
+// and must variants, include, tpl, required and lookup.
+func (r *renderer) funcMap() template.FuncMap {
+	funcs := template.FuncMap{}
+	for name, fn := range utils.TemplateFuncs {
+		funcs[name] = fn
+	}
+	for name, fn := range sprig.TxtFuncMap() {
+		funcs[name] = fn
+	}
+	// Charts must not read the adapter's environment
+	delete(funcs, "env")
+	delete(funcs, "expandenv")
+	for name, fn := range helmFuncs {
+		funcs[name] = fn
+	}
+	funcs["include"] = r.include
+	funcs["tpl"] = r.tpl
+	funcs["required"] = func(msg string, v interface{}) (interface{}, error) {
+		if v == nil || v == "" {
+			return nil, errors.New(msg)
+		}
+		return v, nil
+	}
+	// lookup never finds anything: rendering has no access to the cluster, as with
+	// `helm template`
+	funcs["lookup"] = func(_, _, _, _ string) (map[string]interface{}, error) {
+		return map[string]interface{}{}, nil
+	}
+	return funcs
+}
+
+// include renders the named template, so its output can be piped
+func (r *renderer) include(name string, data interface{}) (string, error) {
+	if r.includeDepth >= maxIncludeDepth {
+		return "", fmt.Errorf("include %q: exceeded the maximum depth of %d", name, maxIncludeDepth)
+	}
+	r.includeDepth++
+	defer func() { r.includeDepth-- }()
+
+	var buf strings.Builder
+	if err := r.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// tpl renders text as a template, with access to the chart's named templates
+func (r *renderer) tpl(text string, data interface{}) (string, error) {
+	t, err := r.tmpl.Clone()
+	if err != nil {
+		return "", fmt.Errorf("tpl: %w", err)
+	}
+	t, err = t.New("tpl").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("tpl: %w", err)
+	}
+	var buf strings.Builder
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("tpl: %w", err)
+	}
+	return strings.ReplaceAll(buf.String(), "<no value>", ""), nil
+}
+
+// helmFuncs are the serialization functions Helm adds to Sprig. As in Helm, the
+// non-must variants do not fail the template: toYaml and toJson return an empty string
+// and fromYaml and fromJson return the error under the "Error" key.
+var helmFuncs = template.FuncMap{
+	"toYaml": func(v interface{}) string {
+		out, err := toYaml(v)
+		if err != nil {
+			return ""
+		}
+		return out
+	},
+	"mustToYaml": toYaml,
+	"fromYaml": func(s string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	},
+	"fromYamlArray": func(s string) []interface{} {
+		var a []interface{}
+		if err := yaml.Unmarshal([]byte(s), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+		return a
+	},
+	"toJson": func(v interface{}) string {
+		out, err := toJson(v)
+		if err != nil {
+			return ""
+		}
+		return out
+	},
+	"mustToJson": toJson,
+	"fromJson": func(s string) map[string]interface{} {
+		m := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	},
+	"fromJsonArray": func(s string) []interface{} {
+		var a []interface{}
+		if err := json.Unmarshal([]byte(s), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+		return a
+	},
+}
+
+func toYaml(v interface{}) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("toYaml: %w", err)
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+func toJson(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("toJson: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,317 @@
+package helm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+)
+
+// hookAnnotation marks the templates Helm runs as hooks; they are not rendered
+const hookAnnotation = "helm.sh/hook"
+
+// kubeVersion is reported by .Capabilities.KubeVersion
+const kubeVersion = "v1.31.0"
+
+// installOrder is the order in which Helm installs kinds; other kinds come after
+var installOrder = []string{
+	"Namespace", "NetworkPolicy", "ResourceQuota", "LimitRange", "PodSecurityPolicy",
+	"PodDisruptionBudget", "ServiceAccount", "Secret", "SecretList", "ConfigMap",
+	"StorageClass", "PersistentVolume", "PersistentVolumeClaim", "CustomResourceDefinition",
+	"ClusterRole", "ClusterRoleList", "ClusterRoleBinding", "ClusterRoleBindingList", "Role",
+	"RoleList", "RoleBinding", "RoleBindingList", "Service", "DaemonSet", "Pod",
+	"ReplicationController", "ReplicaSet", "Deployment", "HorizontalPodAutoscaler",
+	"StatefulSet", "Job", "CronJob", "IngressClass", "Ingress", "APIService",
+}
+
+// Release is the release a chart is rendered for
+type Release struct {
+	Name      string
+	Namespace string
+}
+
+// Render renders the chart for a release, with values merged over the chart's
+// values.yaml, and returns the manifests as JSON documents in Helm's install order.
+// Objects without a namespace are put in the release namespace unless their kind is
+// known to be cluster-scoped.
+func Render(chart *Chart, release Release, values map[string]interface{}) ([][]byte, error) {
+	if chart == nil {
+		return nil, fmt.Errorf("no chart to render")
+	}
+	r := &renderer{release: release}
+	r.tmpl = template.New(chart.Metadata.Name).Option("missingkey=zero").Funcs(r.funcMap())
+
+	if err := r.add(chart, chart.Metadata.Name, coalesceValues(values, chart.Values)); err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]interface{}
+	for _, crd := range r.crds {
+		docs, err := splitDocuments(crd.Data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", crd.Name, err)
+		}
+		objects = append(objects, docs...)
+	}
+	for _, page := range r.pages {
+		var buf strings.Builder
+		if err := r.tmpl.ExecuteTemplate(&buf, page.name, page.data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", page.name, err)
+		}
+		docs, err := splitDocuments([]byte(strings.ReplaceAll(buf.String(), "<no value>", "")))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", page.name, err)
+		}
+		objects = append(objects, docs...)
+	}
+
+	sort.SliceStable(objects, func(i, j int) bool {
+		return kindRank(objects[i]) < kindRank(objects[j])
+	})
+
+	out := make([][]byte, 0, len(objects))
+	for _, obj := range objects {
+		u := unstructured.Unstructured{Object: obj}
+		if _, hook := u.GetAnnotations()[hookAnnotation]; hook {
+			continue
+		}
+		if u.GetNamespace() == "" && manifest.ScopeOf(u.GroupVersionKind().GroupKind()) != manifest.ScopeCluster {
+			u.SetNamespace(release.Namespace)
+		}
+		data, err := json.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+		out = append(out, data)
+	}
+	return out, nil
+}
+
+// renderer holds the templates of a chart and its subcharts, which share one template
+// set so named templates can be included across charts
+type renderer struct {
+	tmpl         *template.Template
+	release      Release
+	pages        []page
+	crds         []File
+	includeDepth int
+}
+
+// page is a template that produces manifests, with the data it is rendered with
+type page struct {
+	data map[string]interface{}
+	name string
+}
+
+// add parses the templates of chart, named under prefix, and of its enabled subcharts
+func (r *renderer) add(chart *Chart, prefix string, values map[string]interface{}) error {
+	files := chartFiles{}
+	for _, f := range chart.Files {
+		files[f.Name] = f.Data
+	}
+	chartObject := map[string]interface{}{
+		"Name":        chart.Metadata.Name,
+		"Version":     chart.Metadata.Version,
+		"AppVersion":  chart.Metadata.AppVersion,
+		"APIVersion":  chart.Metadata.APIVersion,
+		"Type":        chart.Metadata.Type,
+		"Description": chart.Metadata.Description,
+	}
+
+	for _, f := range chart.Templates {
+		name := path.Join(prefix, f.Name)
+		if _, err := r.tmpl.New(name).Parse(string(f.Data)); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+		base := path.Base(f.Name)
+		if strings.HasPrefix(base, "_") || strings.EqualFold(base, "NOTES.txt") {
+			continue
+		}
+		r.pages = append(r.pages, page{
+			name: name,
+			data: map[string]interface{}{
+				"Values":       values,
+				"Chart":        chartObject,
+				"Files":        files,
+				"Capabilities": capabilities,
+				"Release": map[string]interface{}{
+					"Name":      r.release.Name,
+					"Namespace": r.release.Namespace,
+					"Service":   "Helm",
+					"IsInstall": true,
+					"IsUpgrade": false,
+					"Revision":  1,
+				},
+				"Template": map[string]interface{}{
+					"Name":     name,
+					"BasePath": path.Join(prefix, "templates"),
+				},
+			},
+		})
+	}
+	r.crds = append(r.crds, chart.CRDs...)
+
+	for _, sub := range chart.Dependencies {
+		key := sub.Metadata.Name
+		dep, _ := chart.dependency(sub)
+		if dep.Alias != "" {
+			key = dep.Alias
+		}
+		if !conditionEnabled(dep.Condition, values) {
+			continue
+		}
+		subValues := valuesMap(values[key])
+		subValues = coalesceValues(subValues, sub.Values)
+		if global, ok := values["global"].(map[string]interface{}); ok {
+			subGlobal := valuesMap(subValues["global"])
+			subValues["global"] = coalesceValues(global, subGlobal)
+		}
+		values[key] = subValues
+		if err := r.add(sub, path.Join(prefix, "charts", key), subValues); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conditionEnabled evaluates a dependency condition: the first of its comma-separated
+// value paths that holds a boolean decides, and a subchart without one is enabled
+func conditionEnabled(condition string, values map[string]interface{}) bool {
+	for _, p := range strings.Split(condition, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		var current interface{} = values
+		for _, key := range strings.Split(p, ".") {
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				current = nil
+				break
+			}
+			current = m[key]
+		}
+		if enabled, ok := current.(bool); ok {
+			return enabled
+		}
+	}
+	return true
+}
+
+// coalesceValues returns values merged over defaults: nested maps are merged, other
+// values replace the defaults, and a null value removes the default
+func coalesceValues(values, defaults map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(values)+len(defaults))
+	for k, v := range defaults {
+		if m, ok := v.(map[string]interface{}); ok {
+			v = coalesceValues(nil, m)
+		}
+		out[k] = v
+	}
+	for k, v := range values {
+		if v == nil {
+			delete(out, k)
+			continue
+		}
+		m, isMap := v.(map[string]interface{})
+		if !isMap {
+			out[k] = v
+			continue
+		}
+		defaultMap := valuesMap(out[k])
+		out[k] = coalesceValues(m, defaultMap)
+	}
+	return out
+}
+
+// valuesMap returns v as a values map, or nil when it is not one
+func valuesMap(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	return nil
+}
+
+// splitDocuments parses the YAML documents of a rendered template, skipping empty ones
+func splitDocuments(data []byte) ([]map[string]interface{}, error) {
+	var docs []map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for i := 1; ; i++ {
+		var doc map[string]interface{}
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML in document %d: %w", i, err)
+		}
+		if len(doc) > 0 {
+			docs = append(docs, doc)
+		}
+	}
+}
+
+func kindRank(obj map[string]interface{}) int {
+	kind, isString := obj["kind"].(string)
+	if !isString {
+		return len(installOrder)
+	}
+	for i, k := range installOrder {
+		if k == kind {
+			return i
+		}
+	}
+	return len(installOrder)
+}
+
+// chartFiles is the .Files object: the chart files outside templates/, crds/ and charts/
+type chartFiles map[string][]byte
+
+// Get returns the content of a file, or "" if there is none
+func (f chartFiles) Get(name string) string {
+	return string(f[name])
+}
+
+// GetBytes returns the content of a file as bytes
+func (f chartFiles) GetBytes(name string) []byte {
+	return f[name]
+}
+
+// apiVersions is .Capabilities.APIVersions
+type apiVersions []string
+
+// Has reports whether an API version, or a version/Kind pair, is available. Rendering
+// has no access to the cluster, so only the built-in API versions are known.
+func (a apiVersions) Has(version string) bool {
+	for _, v := range a {
+		if v == version || strings.HasPrefix(version, v+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+var capabilities = map[string]interface{}{
+	"KubeVersion": map[string]interface{}{
+		"Version":    kubeVersion,
+		"GitVersion": kubeVersion,
+		"Major":      "1",
+		"Minor":      "31",
+	},
+	"APIVersions": apiVersions{
+		"v1", "apps/v1", "batch/v1", "policy/v1", "autoscaling/v1", "autoscaling/v2",
+		"networking.k8s.io/v1", "rbac.authorization.k8s.io/v1", "storage.k8s.io/v1",
+		"apiextensions.k8s.io/v1", "admissionregistration.k8s.io/v1", "coordination.k8s.io/v1",
+		"scheduling.k8s.io/v1",
+	},
+}
@@ -0,0 +1,285 @@
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var testChartFiles = map[string]string{
+	"Chart.yaml": `
+apiVersion: v2
+name: agent
+version: 1.2.0
+appVersion: "4.5"
+dependencies:
+  - name: metrics
+    condition: metrics.enabled
+`,
+	"values.yaml": `
+replicas: 1
+image:
+  repository: quay.io/hyperfleet/agent
+  tag: ""
+labels: {}
+global:
+  region: us-east-1
+`,
+	"templates/_helpers.tpl": `
+{{- define "agent.fullname" -}}
+{{ .Release.Name }}-{{ .Chart.Name }}
+{{- end -}}
+`,
+	"templates/deployment.yaml": `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ include "agent.fullname" . }}
+  labels:
+    app.kubernetes.io/version: {{ .Chart.AppVersion | quote }}
+    {{- range $k, $v := .Values.labels }}
+    {{ $k }}: {{ $v | quote }}
+    {{- end }}
+spec:
+  replicas: {{ .Values.replicas }}
+  template:
+    spec:
+      containers:
+        - name: agent
+          image: "{{ .Values.image.repository }}:{{ .Values.image.tag | default .Chart.AppVersion }}"
+          args: [{{ tpl .Values.greeting . | quote }}]
+`,
+	"templates/serviceaccount.yaml": `
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: {{ include "agent.fullname" . }}
+`,
+	"templates/clusterrole.yaml": `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: {{ include "agent.fullname" . }}
+---
+# empty documents are dropped
+`,
+	"templates/hook.yaml": `
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    helm.sh/hook: pre-install
+`,
+	"templates/NOTES.txt": `Installed {{ .Release.Name }}`,
+	"charts/metrics/Chart.yaml": `
+apiVersion: v2
+name: metrics
+version: 0.1.0
+`,
+	"charts/metrics/values.yaml": `
+port: 9090
+`,
+	"charts/metrics/templates/service.yaml": `
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ include "agent.fullname" . }}-metrics
+  namespace: monitoring
+  annotations:
+    region: {{ .Values.global.region }}
+spec:
+  ports:
+    - port: {{ .Values.port }}
+`,
+}
+
+func writeChart(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o750))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	return dir
+}
+
+func decode(t *testing.T, docs [][]byte) []map[string]interface{} {
+	t.Helper()
+	out := make([]map[string]interface{}, len(docs))
+	for i, doc := range docs {
+		require.NoError(t, json.Unmarshal(doc, &out[i]))
+	}
+	return out
+}
+
+func field(obj map[string]interface{}, fields ...string) interface{} {
+	v, _, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	return v
+}
+
+func TestRender(t *testing.T) {
+	chart, err := Load(writeChart(t, testChartFiles))
+	require.NoError(t, err)
+
+	docs, err := Render(chart, Release{Name: "cls-1", Namespace: "agents"}, map[string]interface{}{
+		"replicas": 3,
+		"labels":   map[string]interface{}{"team": "fleet"},
+		"greeting": "hello {{ .Release.Namespace }}",
+		"metrics":  map[string]interface{}{"port": 8443},
+	})
+	require.NoError(t, err)
+	objects := decode(t, docs)
+
+	var kinds []string
+	for _, obj := range objects {
+		kinds = append(kinds, obj["kind"].(string))
+	}
+	assert.Equal(t, []string{"ServiceAccount", "ClusterRole", "Service", "Deployment"}, kinds,
+		"manifests come in install order and hooks are skipped")
+
+	sa := objects[0]["metadata"].(map[string]interface{})
+	assert.Equal(t, "cls-1-agent", sa["name"])
+	assert.Equal(t, "agents", sa["namespace"], "namespaced objects default to the release namespace")
+	assert.NotContains(t, objects[1]["metadata"], "namespace", "cluster-scoped objects get no namespace")
+
+	svc := objects[2]
+	assert.Equal(t, "monitoring", field(svc, "metadata", "namespace"))
+	assert.Equal(t, "us-east-1", field(svc, "metadata", "annotations", "region"), "global values reach subcharts")
+	ports := field(svc, "spec", "ports").([]interface{})
+	assert.Equal(t, float64(8443), ports[0].(map[string]interface{})["port"])
+
+	deployment := objects[3]
+	assert.Equal(t, "fleet", field(deployment, "metadata", "labels", "team"))
+	assert.Equal(t, float64(3), field(deployment, "spec", "replicas"))
+	containers := field(deployment, "spec", "template", "spec", "containers").([]interface{})
+	container := containers[0].(map[string]interface{})
+	assert.Equal(t, "quay.io/hyperfleet/agent:4.5", container["image"])
+	assert.Equal(t, []interface{}{"hello agents"}, container["args"])
+}
+
+func TestRender_SubchartCondition(t *testing.T) {
+	chart, err := Load(writeChart(t, testChartFiles))
+	require.NoError(t, err)
+
+	docs, err := Render(chart, Release{Name: "cls-1", Namespace: "agents"}, map[string]interface{}{
+		"greeting": "hi",
+		"metrics":  map[string]interface{}{"enabled": false},
+	})
+	require.NoError(t, err)
+	for _, obj := range decode(t, docs) {
+		assert.NotEqual(t, "Service", obj["kind"])
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	files := map[string]string{
+		"Chart.yaml": "name: strict\nversion: 0.1.0\n",
+		"templates/cm.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ required "name is required" .Values.name }}
+`,
+	}
+	chart, err := Load(writeChart(t, files))
+	require.NoError(t, err)
+
+	_, err = Render(chart, Release{Name: "r", Namespace: "ns"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "strict/templates/cm.yaml")
+	assert.Contains(t, err.Error(), "name is required")
+
+	_, err = LoadDir(t.TempDir())
+	assert.ErrorContains(t, err, "no Chart.yaml found")
+}
+
+func TestLoadArchive(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range map[string]string{
+		"agent/Chart.yaml":  "name: agent\nversion: 0.1.0\n",
+		"agent/values.yaml": "data: {key: value}\n",
+		"agent/templates/cm.yaml": `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data: {{ toYaml .Values.data | nindent 2 }}
+`,
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)),
+			Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	archive := filepath.Join(t.TempDir(), "agent-0.1.0.tgz")
+	require.NoError(t, os.WriteFile(archive, buf.Bytes(), 0o600))
+	chart, err := Load(archive)
+	require.NoError(t, err)
+	assert.Equal(t, "agent", chart.Metadata.Name)
+
+	docs, err := Render(chart, Release{Name: "cfg", Namespace: "ns"}, nil)
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.JSONEq(t,
+		`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cfg","namespace":"ns"},"data":{"key":"value"}}`,
+		string(docs[0]))
+}
+
+func TestCoalesceValues(t *testing.T) {
+	defaults := map[string]interface{}{
+		"image":     map[string]interface{}{"repository": "a", "tag": "1"},
+		"resources": map[string]interface{}{"cpu": "1"},
+	}
+	out := coalesceValues(map[string]interface{}{
+		"image":     map[string]interface{}{"tag": "2"},
+		"resources": nil,
+	}, defaults)
+
+	assert.Equal(t, map[string]interface{}{
+		"image": map[string]interface{}{"repository": "a", "tag": "2"},
+	}, out)
+	assert.Equal(t, "1", defaults["image"].(map[string]interface{})["tag"], "defaults must not be modified")
+}
+
+func TestRender_RepoChart(t *testing.T) {
+	chart, err := Load(filepath.Join("..", "..", "charts"))
+	require.NoError(t, err)
+
+	// The minimal values of the chart's `helm template` check in the Makefile
+	docs, err := Render(chart, Release{Name: "test-release", Namespace: "hyperfleet"}, map[string]interface{}{
+		"image": map[string]interface{}{
+			"registry":   "quay.io",
+			"repository": "openshift-hyperfleet/hyperfleet-adapter",
+			"tag":        "test",
+		},
+		"adapterConfig":     map[string]interface{}{"yaml": "apiVersion: hyperfleet.redhat.com/v1alpha1"},
+		"adapterTaskConfig": map[string]interface{}{"yaml": "apiVersion: hyperfleet.redhat.com/v1alpha1"},
+		"broker": map[string]interface{}{
+			"type":         "googlepubsub",
+			"googlepubsub": map[string]interface{}{"subscriptionId": "test-sub", "topic": "test-topic"},
+		},
+	})
+	require.NoError(t, err, "every Sprig function the chart's helpers use is defined")
+
+	kinds := map[string]bool{}
+	for _, obj := range decode(t, docs) {
+		kinds[obj["kind"].(string)] = true
+	}
+	assert.True(t, kinds["Deployment"])
+	assert.True(t, kinds["ConfigMap"])
+}
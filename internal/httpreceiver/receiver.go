@@ -0,0 +1,222 @@
+// Package httpreceiver receives CloudEvents over HTTP. Its Receiver serves an endpoint per
+// subscribed topic, and its Subscriber implements the hyperfleet-broker Subscriber interface,
+// so webhook-style integrations and local tests can feed events to the adapter without a
+// broker.
+package httpreceiver
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// Defaults of the receiver config
+const (
+	DefaultPort = "8082"
+	DefaultPath = "/events"
+)
+
+// maxBodyBytes bounds the size of a received event
+const maxBodyBytes = 4 << 20
+
+// Config configures the HTTP CloudEvents receiver
+type Config struct {
+	// TLS serves the endpoint over HTTPS
+	TLS *TLSConfig `yaml:"tls,omitempty" mapstructure:"tls"`
+	// Port is the listen port. Empty uses DefaultPort.
+	Port string `yaml:"port,omitempty" mapstructure:"port"`
+	// Path is the URL path prefix of the endpoint: the events of a topic are posted to
+	// <path>/<topic>. Empty uses DefaultPath.
+	Path string `yaml:"path,omitempty" mapstructure:"path"`
+	// TokenPath is the absolute path to a file holding the bearer token senders must present.
+	// Required unless Insecure is set.
+	TokenPath string `yaml:"token_path,omitempty" mapstructure:"token_path"`
+	// Insecure accepts unauthenticated requests when TokenPath is empty, e.g. for local tests
+	Insecure bool `yaml:"insecure,omitempty" mapstructure:"insecure"`
+}
+
+// TLSConfig configures HTTPS on the receiver
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" mapstructure:"cert_file"`
+	KeyFile  string `yaml:"key_file" mapstructure:"key_file"`
+}
+
+// path returns the URL path prefix of the config, without a trailing slash
+func (c Config) path() string {
+	if c.Path == "" {
+		return DefaultPath
+	}
+	return strings.TrimSuffix(c.Path, "/")
+}
+
+// Receiver is the HTTP server the subscribers receive events from. A POST to <path>/<topic>
+// carrying a binary or structured CloudEvent is handled by the subscriber of the topic; the
+// response is sent once the handler returns. It is safe for concurrent use.
+type Receiver struct {
+	log         logger.Logger
+	server      *http.Server
+	subscribers map[string]*Subscriber
+	token       string
+	config      Config
+	mu          sync.RWMutex
+}
+
+// NewReceiver creates a Receiver, reading the bearer token of config. It fails when
+// config has no token and is not insecure, since anyone reaching the port could then
+// inject events.
+func NewReceiver(config Config, log logger.Logger) (*Receiver, error) {
+	if config.Port == "" {
+		config.Port = DefaultPort
+	}
+	if config.TokenPath == "" && !config.Insecure {
+		return nil, fmt.Errorf("HTTP receiver requires token_path; set insecure: true to accept unauthenticated events")
+	}
+	r := &Receiver{log: log, config: config, subscribers: make(map[string]*Subscriber)}
+	if config.TokenPath != "" {
+		raw, err := os.ReadFile(config.TokenPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading HTTP receiver token: %w", err)
+		}
+		r.token = strings.TrimSpace(string(raw))
+		if r.token == "" {
+			return nil, fmt.Errorf("HTTP receiver token file %s is empty", config.TokenPath)
+		}
+	}
+	r.server = &http.Server{
+		Addr:              ":" + config.Port,
+		Handler:           r,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return r, nil
+}
+
+// Start listens on the port of the config and serves requests in the background
+func (r *Receiver) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", r.server.Addr)
+	if err != nil {
+		return fmt.Errorf("HTTP receiver failed to listen on %s: %w", r.server.Addr, err)
+	}
+	r.log.Infof(ctx, "Receiving CloudEvents over HTTP on port %s at %s/<topic>", r.config.Port, r.config.path())
+	if r.token == "" {
+		r.log.Warnf(ctx, "HTTP receiver accepts unauthenticated events on port %s (insecure: true)", r.config.Port)
+	}
+
+	go func() {
+		var err error
+		if r.config.TLS != nil {
+			err = r.server.ServeTLS(listener, r.config.TLS.CertFile, r.config.TLS.KeyFile)
+		} else {
+			err = r.server.Serve(listener)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCtx := logger.WithErrorField(ctx, err)
+			r.log.Errorf(errCtx, "HTTP receiver error")
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops the server, waiting for the requests in progress
+func (r *Receiver) Shutdown(ctx context.Context) error {
+	r.log.Info(ctx, "Shutting down HTTP receiver...")
+	return r.server.Shutdown(ctx)
+}
+
+// ServeHTTP implements http.Handler
+func (r *Receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	topic, ok := strings.CutPrefix(req.URL.Path, r.config.path()+"/")
+	if !ok || topic == "" || strings.Contains(topic, "/") {
+		http.NotFound(w, req)
+		return
+	}
+	if !r.authorized(req) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	switch req.Method {
+	case http.MethodOptions:
+		// CloudEvents webhook abuse protection handshake
+		if origin := req.Header.Get("WebHook-Request-Origin"); origin != "" {
+			w.Header().Set("WebHook-Allowed-Origin", origin)
+		}
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusOK)
+		return
+	case http.MethodPost:
+	default:
+		w.Header().Set("Allow", "POST, OPTIONS")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.mu.RLock()
+	sub := r.subscribers[topic]
+	r.mu.RUnlock()
+	if sub == nil {
+		http.Error(w, fmt.Sprintf("no subscription to topic %s", topic), http.StatusNotFound)
+		return
+	}
+
+	req.Body = http.MaxBytesReader(w, req.Body, maxBodyBytes)
+	evt, err := binding.ToEvent(req.Context(), cehttp.NewMessageFromHttpRequest(req))
+	if err == nil {
+		err = evt.Validate()
+	}
+	if err != nil {
+		sub.recordError(topic, "conversion")
+		http.Error(w, fmt.Sprintf("invalid CloudEvent: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := sub.handle(topic, evt); err != nil {
+		if errors.Is(err, errSubscriberClosed) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// authorized returns true if req presents the bearer token, or the receiver is insecure
+func (r *Receiver) authorized(req *http.Request) bool {
+	if r.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) == 1
+}
+
+// register makes sub the subscriber of topic
+func (r *Receiver) register(topic string, sub *Subscriber) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, taken := r.subscribers[topic]; taken {
+		return fmt.Errorf("topic %s already has a subscriber", topic)
+	}
+	r.subscribers[topic] = sub
+	return nil
+}
+
+// unregister removes sub as the subscriber of topic
+func (r *Receiver) unregister(topic string, sub *Subscriber) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.subscribers[topic] == sub {
+		delete(r.subscribers, topic)
+	}
+}
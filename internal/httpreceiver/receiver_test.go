@@ -0,0 +1,137 @@
+package httpreceiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+func newTestReceiver(t *testing.T, config Config) *Receiver {
+	t.Helper()
+	r, err := NewReceiver(config, logger.NewTestLogger())
+	require.NoError(t, err)
+	return r
+}
+
+func binaryRequest(path, id string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"id":"cluster-1"}`))
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", id)
+	req.Header.Set("Ce-Source", "/hyperfleet")
+	req.Header.Set("Ce-Type", "io.hyperfleet.cluster.updated")
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func serve(r *Receiver, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReceiver_Events(t *testing.T) {
+	r := newTestReceiver(t, Config{Insecure: true})
+	var handled []*event.Event
+	handler := func(_ context.Context, evt *event.Event) error {
+		handled = append(handled, evt)
+		if evt.ID() == "evt-fail" {
+			return errors.New("execution failed")
+		}
+		return nil
+	}
+	sub := r.NewSubscriber("my-adapter", nil)
+	require.NoError(t, sub.Subscribe(context.Background(), "clusters", handler))
+	assert.Equal(t, BrokerType, sub.BrokerType())
+
+	rec := serve(r, binaryRequest("/events/clusters", "evt-1"))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	require.Len(t, handled, 1)
+	assert.Equal(t, "io.hyperfleet.cluster.updated", handled[0].Type())
+	assert.JSONEq(t, `{"id":"cluster-1"}`, string(handled[0].Data()))
+
+	structured := httptest.NewRequest(http.MethodPost, "/events/clusters", strings.NewReader(
+		`{"specversion":"1.0","id":"evt-2","source":"/hyperfleet","type":"io.hyperfleet.cluster.created",`+
+			`"datacontenttype":"application/json","data":{"id":"cluster-2"}}`))
+	structured.Header.Set("Content-Type", "application/cloudevents+json")
+	rec = serve(r, structured)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	require.Len(t, handled, 2)
+	assert.Equal(t, "evt-2", handled[1].ID())
+
+	rec = serve(r, binaryRequest("/events/clusters", "evt-fail"))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code, "a handler error should be returned to the sender")
+	assert.Contains(t, rec.Body.String(), "execution failed")
+
+	notAnEvent := httptest.NewRequest(http.MethodPost, "/events/clusters", strings.NewReader("hello"))
+	assert.Equal(t, http.StatusBadRequest, serve(r, notAnEvent).Code)
+	assert.Equal(t, http.StatusNotFound, serve(r, binaryRequest("/events/nodepools", "evt-3")).Code)
+	assert.Equal(t, http.StatusNotFound, serve(r, binaryRequest("/other/clusters", "evt-3")).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed,
+		serve(r, httptest.NewRequest(http.MethodGet, "/events/clusters", nil)).Code)
+
+	handshake := httptest.NewRequest(http.MethodOptions, "/events/clusters", nil)
+	handshake.Header.Set("WebHook-Request-Origin", "eventemitter.example.com")
+	rec = serve(r, handshake)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "eventemitter.example.com", rec.Header().Get("WebHook-Allowed-Origin"))
+	assert.Len(t, handled, 3)
+}
+
+func TestReceiver_Subscribers(t *testing.T) {
+	r := newTestReceiver(t, Config{Path: "/hooks/", Insecure: true})
+	noop := func(context.Context, *event.Event) error { return nil }
+
+	sub := r.NewSubscriber("my-adapter", nil)
+	require.NoError(t, sub.Subscribe(context.Background(), "clusters", noop))
+	assert.Error(t, sub.Subscribe(context.Background(), "nodepools", noop), "a subscriber receives one topic")
+	other := r.NewSubscriber("other-adapter", nil)
+	assert.ErrorContains(t, other.Subscribe(context.Background(), "clusters", noop), "already has a subscriber")
+	assert.Equal(t, http.StatusAccepted, serve(r, binaryRequest("/hooks/clusters", "evt-1")).Code)
+
+	require.NoError(t, sub.Close())
+	_, open := <-sub.Errors()
+	assert.False(t, open, "Close should close the error channel")
+	assert.Equal(t, http.StatusNotFound, serve(r, binaryRequest("/hooks/clusters", "evt-2")).Code)
+
+	// A restarted subscription takes the topic over
+	restarted := r.NewSubscriber("my-adapter", nil)
+	require.NoError(t, restarted.Subscribe(context.Background(), "clusters", noop))
+	assert.Equal(t, http.StatusAccepted, serve(r, binaryRequest("/hooks/clusters", "evt-3")).Code)
+}
+
+func TestReceiver_Token(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("s3cret\n"), 0o600))
+	r := newTestReceiver(t, Config{TokenPath: tokenPath})
+	sub := r.NewSubscriber("my-adapter", nil)
+	require.NoError(t, sub.Subscribe(context.Background(), "clusters", func(context.Context, *event.Event) error {
+		return nil
+	}))
+
+	req := binaryRequest("/events/clusters", "evt-1")
+	assert.Equal(t, http.StatusUnauthorized, serve(r, req).Code)
+	req = binaryRequest("/events/clusters", "evt-1")
+	req.Header.Set("Authorization", "Bearer wrong")
+	assert.Equal(t, http.StatusUnauthorized, serve(r, req).Code)
+	req = binaryRequest("/events/clusters", "evt-1")
+	req.Header.Set("Authorization", "Bearer s3cret")
+	assert.Equal(t, http.StatusAccepted, serve(r, req).Code)
+
+	require.NoError(t, os.WriteFile(tokenPath, nil, 0o600))
+	_, err := NewReceiver(Config{TokenPath: tokenPath}, logger.NewTestLogger())
+	assert.ErrorContains(t, err, "is empty")
+
+	_, err = NewReceiver(Config{}, logger.NewTestLogger())
+	assert.ErrorContains(t, err, "requires token_path", "a receiver without a token must be explicitly insecure")
+}
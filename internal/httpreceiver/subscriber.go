@@ -0,0 +1,144 @@
+package httpreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+)
+
+// BrokerType is the broker type of the HTTP subscriber
+const BrokerType = "http"
+
+// errSubscriberClosed is returned for events received while the subscriber closes
+var errSubscriberClosed = errors.New("subscriber is closed")
+
+// Subscriber receives the events posted to the receiver endpoint of the topic it subscribes
+// to. An event is handled in the request that posted it: the sender gets 202 Accepted when
+// the handler returns nil and 500 with the error otherwise, and is expected to retry.
+type Subscriber struct {
+	ctx            context.Context
+	receiver       *Receiver
+	metrics        *broker.MetricsRecorder
+	errors         chan *broker.SubscriberError
+	cancel         context.CancelFunc
+	handler        broker.HandlerFunc
+	subscriptionID string
+	topic          string
+	wg             sync.WaitGroup
+	mu             sync.RWMutex
+	closed         bool
+}
+
+// NewSubscriber creates a Subscriber of the receiver. metrics may be nil.
+func (r *Receiver) NewSubscriber(subscriptionID string, metrics *broker.MetricsRecorder) *Subscriber {
+	return &Subscriber{
+		receiver:       r,
+		metrics:        metrics,
+		errors:         make(chan *broker.SubscriberError, broker.ErrorChannelBufferSize),
+		subscriptionID: subscriptionID,
+	}
+}
+
+// Subscribe implements broker.Subscriber. The events posted to <path>/<topic> are handled
+// with ctx until the subscriber is closed or ctx is done. A subscriber receives a single
+// topic, and a topic has a single subscriber.
+func (s *Subscriber) Subscribe(ctx context.Context, topic string, handler broker.HandlerFunc) error {
+	if handler == nil {
+		return fmt.Errorf("handler must be provided")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errSubscriberClosed
+	}
+	if s.topic != "" {
+		return fmt.Errorf("subscriber already receives topic %s", s.topic)
+	}
+	if err := s.receiver.register(topic, s); err != nil {
+		return err
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.topic, s.handler = topic, handler
+	s.receiver.log.Infof(ctx, "Receiving topic %s at %s/%s (subscription %s)",
+		topic, s.receiver.config.path(), topic, s.subscriptionID)
+	return nil
+}
+
+// handle runs the handler on evt
+func (s *Subscriber) handle(topic string, evt *event.Event) error {
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return errSubscriberClosed
+	}
+	s.wg.Add(1)
+	ctx, handler := s.ctx, s.handler
+	s.mu.RUnlock()
+	defer s.wg.Done()
+
+	s.recordConsumed(topic)
+	start := time.Now()
+	err := handler(ctx, evt)
+	s.recordDuration(topic, time.Since(start))
+	if err != nil {
+		s.recordError(topic, "handler")
+		s.receiver.log.Errorf(ctx, "Handler failed to process event %s received over HTTP: %v", evt.ID(), err)
+	}
+	return err
+}
+
+// Errors implements broker.Subscriber. The sender of a failed event sees the error in the
+// response, so no error is reported here.
+func (s *Subscriber) Errors() <-chan *broker.SubscriberError {
+	return s.errors
+}
+
+// BrokerType implements broker.Subscriber
+func (s *Subscriber) BrokerType() string {
+	return BrokerType
+}
+
+// Close implements broker.Subscriber. Events posted afterwards get 503 Service Unavailable;
+// it waits for the handlers in progress and closes the error channel.
+func (s *Subscriber) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.topic != "" {
+		s.receiver.unregister(s.topic, s)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	close(s.errors)
+	return nil
+}
+
+func (s *Subscriber) recordConsumed(topic string) {
+	if s.metrics != nil {
+		s.metrics.RecordConsumed(topic)
+	}
+}
+
+func (s *Subscriber) recordError(topic, errorType string) {
+	if s.metrics != nil {
+		s.metrics.RecordError(topic, errorType)
+	}
+}
+
+func (s *Subscriber) recordDuration(topic string, d time.Duration) {
+	if s.metrics != nil {
+		s.metrics.RecordDuration(topic, d)
+	}
+}
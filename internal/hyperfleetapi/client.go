@@ -2,6 +2,7 @@ package hyperfleetapi
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"crypto/rand"
 	"errors"
@@ -16,6 +17,7 @@ import (
 
 	apierrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/version"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -34,10 +36,11 @@ const (
 
 // httpClient implements the Client interface
 type httpClient struct {
-	client      *http.Client
-	config      *ClientConfig
-	log         logger.Logger
-	tokenSource *fileTokenSource
+	client        *http.Client
+	config        *ClientConfig
+	log           logger.Logger
+	tokenSource   tokenSource
+	wrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 // ClientOption is a functional option for configuring the client
@@ -50,6 +53,14 @@ func WithHTTPClient(client *http.Client) ClientOption {
 	}
 }
 
+// WithTransportWrapper wraps the transport of the HTTP client, e.g. to observe requests.
+// The wrapper receives http.DefaultTransport when the client has no transport.
+func WithTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) ClientOption {
+	return func(c *httpClient) {
+		c.wrapTransport = wrap
+	}
+}
+
 // WithConfig sets the client configuration
 func WithConfig(config *ClientConfig) ClientOption {
 	return func(c *httpClient) {
@@ -72,7 +83,7 @@ func WithDefaultHeader(key, value string) ClientOption {
 // WithTimeout sets the client timeout
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *httpClient) {
-		c.config.Timeout = timeout
+		c.config.Timeout = utils.Duration(timeout)
 	}
 }
 
@@ -93,14 +104,14 @@ func WithRetryBackoff(backoff BackoffStrategy) ClientOption {
 // WithBaseDelay sets the base delay for retry backoff
 func WithBaseDelay(delay time.Duration) ClientOption {
 	return func(c *httpClient) {
-		c.config.BaseDelay = delay
+		c.config.BaseDelay = utils.Duration(delay)
 	}
 }
 
 // WithMaxDelay sets the maximum delay for retry backoff
 func WithMaxDelay(delay time.Duration) ClientOption {
 	return func(c *httpClient) {
-		c.config.MaxDelay = delay
+		c.config.MaxDelay = utils.Duration(delay)
 	}
 }
 
@@ -118,6 +129,29 @@ func WithAuth(auth *AuthConfig) ClientOption {
 	}
 }
 
+// WithTokenFile configures bearer token authentication from a file. The token is
+// cached for cacheTTL; zero re-reads the file on every request.
+func WithTokenFile(path string, cacheTTL time.Duration) ClientOption {
+	return func(c *httpClient) {
+		c.config.Auth = &AuthConfig{TokenPath: path, TokenCacheTTL: utils.Duration(cacheTTL)}
+	}
+}
+
+// WithOAuth2ClientCredentials configures bearer token authentication with tokens
+// obtained from an OAuth2 token endpoint and refreshed before they expire.
+func WithOAuth2ClientCredentials(oauth2 *OAuth2Config) ClientOption {
+	return func(c *httpClient) {
+		c.config.Auth = &AuthConfig{OAuth2: oauth2}
+	}
+}
+
+// WithCompression configures gzip compression of request and response bodies.
+func WithCompression(compression CompressionConfig) ClientOption {
+	return func(c *httpClient) {
+		c.config.Compression = compression
+	}
+}
+
 // NewClient creates a new HyperFleet API client.
 //
 // Base URL resolution order:
@@ -154,13 +188,35 @@ func NewClient(log logger.Logger, opts ...ClientOption) (Client, error) {
 	// Create HTTP client if not provided
 	if c.client == nil {
 		c.client = &http.Client{
-			Timeout: c.config.Timeout,
+			Timeout: c.config.Timeout.Std(),
+		}
+	}
+	if c.wrapTransport != nil {
+		wrapped := *c.client
+		transport := wrapped.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
 		}
+		wrapped.Transport = c.wrapTransport(transport)
+		c.client = &wrapped
 	}
 
 	// Initialize token source for bearer token auth if configured
-	if c.config.Auth != nil && c.config.Auth.TokenPath != "" {
-		c.tokenSource = newFileTokenSource(c.config.Auth.TokenPath, c.config.Auth.TokenCacheTTL)
+	if auth := c.config.Auth; auth != nil {
+		switch {
+		case auth.OAuth2 != nil:
+			if auth.OAuth2.TokenURL == "" || auth.OAuth2.ClientID == "" || auth.OAuth2.ClientSecretPath == "" {
+				return nil, fmt.Errorf("OAuth2 auth requires token URL, client ID and client secret path")
+			}
+			c.tokenSource = newOAuth2TokenSource(auth.OAuth2, c.client)
+		case auth.OIDC != nil:
+			if auth.OIDC.Issuer == "" || auth.OIDC.ClientID == "" {
+				return nil, fmt.Errorf("OIDC auth requires issuer and client ID")
+			}
+			c.tokenSource = newOIDCTokenSource(auth.OIDC, c.client)
+		case auth.TokenPath != "":
+			c.tokenSource = newFileTokenSource(auth.TokenPath, auth.TokenCacheTTL.Std())
+		}
 	}
 
 	return c, nil
@@ -207,6 +263,7 @@ func (c *httpClient) Do(ctx context.Context, req *Request) (*Response, error) {
 
 	var lastErr error
 	var lastResp *Response
+	reauthenticated := false
 	startTime := time.Now()
 
 	for attempt := 1; attempt <= retryAttempts; attempt++ {
@@ -217,6 +274,13 @@ func (c *httpClient) Do(ctx context.Context, req *Request) (*Response, error) {
 		}
 
 		resp, err := c.doRequest(ctx, req)
+		if err == nil && resp.StatusCode == http.StatusUnauthorized && c.tokenSource != nil && !reauthenticated {
+			// The token may have been revoked or rotated early: retry once at once with a new one
+			reauthenticated = true
+			c.tokenSource.invalidate()
+			c.log.Infof(ctx, "HyperFleet API rejected the bearer token, retrying with a new token")
+			resp, err = c.doRequest(ctx, req)
+		}
 		if err != nil {
 			lastErr = err
 			c.log.Warnf(ctx, "HyperFleet API request failed (attempt %d/%d): %v", attempt, retryAttempts, err)
@@ -304,7 +368,7 @@ func (c *httpClient) doRequest(ctx context.Context, req *Request) (*Response, er
 	ctx = logger.WithOTelTraceContext(ctx)
 
 	// Determine timeout
-	timeout := c.config.Timeout
+	timeout := c.config.Timeout.Std()
 	if req.Timeout > 0 {
 		timeout = req.Timeout
 	}
@@ -313,10 +377,21 @@ func (c *httpClient) doRequest(ctx context.Context, req *Request) (*Response, er
 	reqCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Create HTTP request
+	// Create HTTP request, compressing large bodies unless the caller encoded them already
 	var body io.Reader
+	gzipBody := false
 	if len(req.Body) > 0 {
-		body = bytes.NewReader(req.Body)
+		reqBody := req.Body
+		if c.shouldCompressRequest(req) {
+			compressed, gzErr := gzipBytes(req.Body)
+			if gzErr != nil {
+				return nil, fmt.Errorf("failed to compress request body: %w", gzErr)
+			}
+			c.log.Debugf(ctx, "Compressed request body from %d to %d bytes", len(req.Body), len(compressed))
+			reqBody = compressed
+			gzipBody = true
+		}
+		body = bytes.NewReader(reqBody)
 	}
 
 	httpReq, err := http.NewRequestWithContext(reqCtx, req.Method, resolvedURL, body)
@@ -336,7 +411,7 @@ func (c *httpClient) doRequest(ctx context.Context, req *Request) (*Response, er
 
 	// Inject bearer token auth header
 	if c.tokenSource != nil {
-		tok, authErr := c.tokenSource.get()
+		tok, authErr := c.tokenSource.token(reqCtx)
 		if authErr != nil {
 			return nil, fmt.Errorf("getting auth token: %w", authErr)
 		}
@@ -348,6 +423,20 @@ func (c *httpClient) doRequest(ctx context.Context, req *Request) (*Response, er
 		httpReq.Header.Set("Content-Type", "application/json")
 	}
 
+	if gzipBody {
+		httpReq.Header.Set("Content-Encoding", "gzip")
+	}
+
+	// Negotiate response compression (respect explicit caller override). Setting the header
+	// turns off the transport's implicit gzip handling, so gzip responses are decoded below.
+	if httpReq.Header.Get("Accept-Encoding") == "" {
+		if c.config.Compression.Disabled {
+			httpReq.Header.Set("Accept-Encoding", "identity")
+		} else {
+			httpReq.Header.Set("Accept-Encoding", "gzip")
+		}
+	}
+
 	// Set User-Agent header (respect explicit caller override)
 	if httpReq.Header.Get("User-Agent") == "" {
 		httpReq.Header.Set("User-Agent", version.UserAgent())
@@ -369,17 +458,23 @@ func (c *httpClient) doRequest(ctx context.Context, req *Request) (*Response, er
 		}
 	}()
 
-	// Read response body
-	respBody, err := io.ReadAll(httpResp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
 	response := &Response{
 		StatusCode: httpResp.StatusCode,
 		Status:     httpResp.Status,
 		Headers:    httpResp.Header,
-		Body:       respBody,
+	}
+
+	if req.ResponseDecoder != nil && response.IsSuccess() {
+		// Decode while reading so the body is never held in memory whole
+		response.Decoded, err = decodeResponseBody(httpResp, req.ResponseDecoder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response body: %w", err)
+		}
+	} else {
+		response.Body, err = readResponseBody(httpResp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body: %w", err)
+		}
 	}
 
 	c.log.Debugf(ctx, "HyperFleet API response: %d %s", response.StatusCode, response.Status)
@@ -387,10 +482,89 @@ func (c *httpClient) doRequest(ctx context.Context, req *Request) (*Response, er
 	return response, nil
 }
 
+// shouldCompressRequest reports whether the request body should be sent gzip-encoded
+func (c *httpClient) shouldCompressRequest(req *Request) bool {
+	minBytes := c.config.Compression.MinBytes
+	if c.config.Compression.Disabled || minBytes <= 0 || len(req.Body) < minBytes {
+		return false
+	}
+	return !hasHeader(req.Headers, "Content-Encoding") && !hasHeader(c.config.DefaultHeaders, "Content-Encoding")
+}
+
+// hasHeader reports whether headers contains name, ignoring case
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipBytes returns data gzip-compressed
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readResponseBody reads the response body, decompressing it when the server sent it
+// gzip-encoded. The Content-Encoding and Content-Length headers are dropped for decoded
+// bodies, as net/http does for transparently decompressed responses.
+func readResponseBody(httpResp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil || len(body) == 0 || !strings.EqualFold(httpResp.Header.Get("Content-Encoding"), "gzip") {
+		return body, err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip response: %w", err)
+	}
+	decoded, err := io.ReadAll(zr)
+	if err == nil {
+		err = zr.Close()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip response: %w", err)
+	}
+	httpResp.Header.Del("Content-Encoding")
+	httpResp.Header.Del("Content-Length")
+	return decoded, nil
+}
+
+// decodeResponseBody passes the response body to decode, decompressing it as it is read
+// when the server sent it gzip-encoded
+func decodeResponseBody(httpResp *http.Response, decode func(io.Reader) (interface{}, error)) (interface{}, error) {
+	if !strings.EqualFold(httpResp.Header.Get("Content-Encoding"), "gzip") {
+		return decode(httpResp.Body)
+	}
+	zr, err := gzip.NewReader(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid gzip response: %w", err)
+	}
+	httpResp.Header.Del("Content-Encoding")
+	httpResp.Header.Del("Content-Length")
+	result, err := decode(zr)
+	if err != nil {
+		return nil, err
+	}
+	if err = zr.Close(); err != nil {
+		return nil, fmt.Errorf("invalid gzip response: %w", err)
+	}
+	return result, nil
+}
+
 // calculateBackoff calculates the delay before the next retry attempt
 func (c *httpClient) calculateBackoff(attempt int, strategy BackoffStrategy) time.Duration {
-	baseDelay := c.config.BaseDelay
-	maxDelay := c.config.MaxDelay
+	baseDelay := c.config.BaseDelay.Std()
+	maxDelay := c.config.MaxDelay.Std()
 
 	var delay time.Duration
 
@@ -1,6 +1,8 @@
 package hyperfleetapi
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,6 +11,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -16,6 +19,7 @@ import (
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -124,11 +128,11 @@ func TestNewClientWithOptions(t *testing.T) {
 			opts: []ClientOption{
 				WithConfig(&ClientConfig{
 					BaseURL:       "http://localhost",
-					Timeout:       5 * time.Second,
+					Timeout:       utils.Duration(5 * time.Second),
 					RetryAttempts: 2,
 					RetryBackoff:  BackoffConstant,
-					BaseDelay:     100 * time.Millisecond,
-					MaxDelay:      10 * time.Second,
+					BaseDelay:     utils.Duration(100 * time.Millisecond),
+					MaxDelay:      utils.Duration(10 * time.Second),
 				}),
 			},
 		},
@@ -264,7 +268,7 @@ func TestClientRetry(t *testing.T) {
 	config := DefaultClientConfig()
 	config.BaseURL = server.URL
 	config.RetryAttempts = 3
-	config.BaseDelay = 10 * time.Millisecond // Short delay for tests
+	config.BaseDelay = utils.Duration(10 * time.Millisecond) // Short delay for tests
 
 	client, err := NewClient(testLog(), WithConfig(config))
 	require.NoError(t, err, "failed to create client")
@@ -299,7 +303,7 @@ func TestClientRetryExhausted(t *testing.T) {
 	config := DefaultClientConfig()
 	config.BaseURL = server.URL
 	config.RetryAttempts = 3
-	config.BaseDelay = 10 * time.Millisecond
+	config.BaseDelay = utils.Duration(10 * time.Millisecond)
 
 	client, err := NewClient(testLog(), WithConfig(config))
 	require.NoError(t, err, "failed to create client")
@@ -359,7 +363,7 @@ func TestClientTimeout(t *testing.T) {
 
 	config := DefaultClientConfig()
 	config.BaseURL = server.URL
-	config.Timeout = 100 * time.Millisecond
+	config.Timeout = utils.Duration(100 * time.Millisecond)
 	config.RetryAttempts = 1
 
 	client, err := NewClient(testLog(), WithConfig(config))
@@ -429,8 +433,8 @@ func TestResponseHelpers(t *testing.T) {
 
 func TestBackoffCalculation(t *testing.T) {
 	config := DefaultClientConfig()
-	config.BaseDelay = 100 * time.Millisecond
-	config.MaxDelay = 10 * time.Second
+	config.BaseDelay = utils.Duration(100 * time.Millisecond)
+	config.MaxDelay = utils.Duration(10 * time.Second)
 
 	c := &httpClient{
 		config: config,
@@ -655,7 +659,7 @@ func TestAPIErrorInRetryExhausted(t *testing.T) {
 	config := DefaultClientConfig()
 	config.BaseURL = server.URL
 	config.RetryAttempts = 2
-	config.BaseDelay = 10 * time.Millisecond
+	config.BaseDelay = utils.Duration(10 * time.Millisecond)
 
 	client, err := NewClient(testLog(), WithConfig(config))
 	require.NoError(t, err, "failed to create client")
@@ -732,3 +736,207 @@ func TestClientNoAuthHeader_WhenNoAuth(t *testing.T) {
 		t.Errorf("expected no Authorization header, got %q", receivedAuth)
 	}
 }
+
+func TestClientGzipResponse(t *testing.T) {
+	payload := []byte(`{"items":[{"id":"cluster-1"},{"id":"cluster-2"}]}`)
+
+	tests := []struct {
+		name               string
+		wantAcceptEncoding string
+		compression        CompressionConfig
+	}{
+		{name: "gzip requested by default", wantAcceptEncoding: "gzip"},
+		{name: "disabled", compression: CompressionConfig{Disabled: true}, wantAcceptEncoding: "identity"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var acceptEncoding string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				acceptEncoding = r.Header.Get("Accept-Encoding")
+				if acceptEncoding != "gzip" {
+					_, _ = w.Write(payload)
+					return
+				}
+				w.Header().Set("Content-Encoding", "gzip")
+				zw := gzip.NewWriter(w)
+				_, _ = zw.Write(payload)
+				_ = zw.Close()
+			}))
+			defer server.Close()
+
+			client, err := NewClient(testLog(), WithBaseURL(server.URL), WithCompression(tt.compression))
+			require.NoError(t, err)
+
+			resp, err := client.Get(context.Background(), "/clusters")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAcceptEncoding, acceptEncoding)
+			assert.Equal(t, payload, resp.Body)
+			assert.Empty(t, http.Header(resp.Headers).Get("Content-Encoding"))
+		})
+	}
+}
+
+func TestClientGzipResponse_Invalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		_, _ = w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(testLog(), WithBaseURL(server.URL), WithRetryAttempts(1))
+	require.NoError(t, err)
+
+	_, err = client.Get(context.Background(), "/clusters")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid gzip response")
+}
+
+func TestClientResponseDecoder(t *testing.T) {
+	payload := []byte(`{"items":[{"id":"cluster-1"},{"id":"cluster-2"}]}`)
+	decode := func(r io.Reader) (interface{}, error) {
+		var data map[string]interface{}
+		err := json.NewDecoder(r).Decode(&data)
+		return data, err
+	}
+
+	for _, gzipped := range []bool{false, true} {
+		t.Run(fmt.Sprintf("gzip=%v", gzipped), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if !gzipped {
+					_, _ = w.Write(payload)
+					return
+				}
+				w.Header().Set("Content-Encoding", "gzip")
+				zw := gzip.NewWriter(w)
+				_, _ = zw.Write(payload)
+				_ = zw.Close()
+			}))
+			defer server.Close()
+
+			client, err := NewClient(testLog(), WithBaseURL(server.URL))
+			require.NoError(t, err)
+
+			resp, err := client.Get(context.Background(), "/clusters", WithResponseDecoder(decode))
+			require.NoError(t, err)
+			assert.Nil(t, resp.Body, "a decoded body is not buffered")
+			items := resp.Decoded.(map[string]interface{})["items"].([]interface{})
+			assert.Len(t, items, 2)
+		})
+	}
+
+	t.Run("error responses are buffered", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"reason":"not found"}`))
+		}))
+		defer server.Close()
+
+		client, err := NewClient(testLog(), WithBaseURL(server.URL))
+		require.NoError(t, err)
+
+		resp, err := client.Get(context.Background(), "/clusters", WithResponseDecoder(decode))
+		require.NoError(t, err)
+		assert.Nil(t, resp.Decoded)
+		assert.Equal(t, `{"reason":"not found"}`, string(resp.Body))
+	})
+
+	t.Run("decode errors are returned", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+
+		client, err := NewClient(testLog(), WithBaseURL(server.URL), WithRetryAttempts(1))
+		require.NoError(t, err)
+
+		_, err = client.Get(context.Background(), "/clusters", WithResponseDecoder(decode))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to decode response body")
+	})
+}
+
+func TestClientGzipRequest(t *testing.T) {
+	small := []byte(`{"a":1}`)
+	large := []byte(`{"conditions":"` + strings.Repeat("x", 256) + `"}`)
+
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		body        []byte
+		compression CompressionConfig
+		wantGzip    bool
+	}{
+		{name: "not configured", body: large},
+		{name: "below threshold", compression: CompressionConfig{MinBytes: 100}, body: small},
+		{name: "above threshold", compression: CompressionConfig{MinBytes: 100}, body: large, wantGzip: true},
+		{name: "disabled", compression: CompressionConfig{Disabled: true, MinBytes: 100}, body: large},
+		{
+			name:        "caller encoded body",
+			compression: CompressionConfig{MinBytes: 100},
+			body:        large,
+			headers:     map[string]string{"content-encoding": "br"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var contentEncoding string
+			var received []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				contentEncoding = r.Header.Get("Content-Encoding")
+				received, _ = io.ReadAll(r.Body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(testLog(), WithBaseURL(server.URL), WithCompression(tt.compression))
+			require.NoError(t, err)
+
+			var opts []RequestOption
+			for k, v := range tt.headers {
+				opts = append(opts, WithHeader(k, v))
+			}
+			_, err = client.Patch(context.Background(), "/clusters/1/statuses", tt.body, opts...)
+			require.NoError(t, err)
+
+			if !tt.wantGzip {
+				assert.NotEqual(t, "gzip", contentEncoding)
+				assert.Equal(t, tt.body, received)
+				return
+			}
+			assert.Equal(t, "gzip", contentEncoding)
+			zr, err := gzip.NewReader(bytes.NewReader(received))
+			require.NoError(t, err)
+			decoded, err := io.ReadAll(zr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.body, decoded)
+		})
+	}
+}
+
+func TestClientTransportWrapper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var observed []string
+	wrap := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			observed = append(observed, req.Method+" "+req.URL.Path)
+			return next.RoundTrip(req)
+		})
+	}
+
+	client, err := NewClient(testLog(), WithBaseURL(server.URL), WithTransportWrapper(wrap))
+	require.NoError(t, err)
+
+	_, err = client.Get(context.Background(), "/clusters")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET /clusters"}, observed)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
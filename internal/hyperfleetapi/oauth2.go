@@ -0,0 +1,169 @@
+package hyperfleetapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oauth2ExpiryDelta refreshes tokens this long before they expire, so a token
+	// does not expire between being injected and reaching the API
+	oauth2ExpiryDelta = 30 * time.Second
+	// maxTokenResponseSize is a defensive cap on token endpoint responses (1 MB)
+	maxTokenResponseSize = 1 << 20
+)
+
+// oauth2TokenSource obtains tokens with the OAuth2 client credentials grant
+// (RFC 6749 section 4.4) and caches them until shortly before they expire.
+// Concurrent callers share a single token request. It is safe for concurrent use.
+type oauth2TokenSource struct {
+	expiresAt  time.Time // zero value means the token does not expire
+	client     *http.Client
+	config     *OAuth2Config
+	cached     string
+	mu         sync.Mutex
+	expirySkew time.Duration
+}
+
+func newOAuth2TokenSource(config *OAuth2Config, client *http.Client) *oauth2TokenSource {
+	return &oauth2TokenSource{config: config, client: client, expirySkew: oauth2ExpiryDelta}
+}
+
+// token implements tokenSource
+func (s *oauth2TokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached != "" && (s.expiresAt.IsZero() || time.Now().Before(s.expiresAt)) {
+		return s.cached, nil
+	}
+
+	tok, expiresIn, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.cached = tok
+	s.expiresAt = time.Time{}
+	if expiresIn > 0 {
+		s.expiresAt = time.Now().Add(expiresIn - min(s.expirySkew, expiresIn/2))
+	}
+	return tok, nil
+}
+
+// invalidate implements tokenSource; the next call fetches a new token
+func (s *oauth2TokenSource) invalidate() {
+	s.mu.Lock()
+	s.cached = ""
+	s.mu.Unlock()
+}
+
+// fetch requests a new token from the token endpoint
+func (s *oauth2TokenSource) fetch(ctx context.Context) (string, time.Duration, error) {
+	raw, err := os.ReadFile(s.config.ClientSecretPath)
+	if err != nil {
+		return "", 0, fmt.Errorf("reading client secret file %s: %w", s.config.ClientSecretPath, err)
+	}
+	secret := strings.TrimSpace(string(raw))
+	if secret == "" {
+		return "", 0, fmt.Errorf("client secret file %s is empty", s.config.ClientSecretPath)
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(s.config.Scopes, " "))
+	}
+	// Client credentials are form-encoded before basic auth (RFC 6749 section 2.3.1)
+	resp, err := requestToken(ctx, s.client, s.config.TokenURL, form, func(req *http.Request) {
+		req.SetBasicAuth(url.QueryEscape(s.config.ClientID), url.QueryEscape(secret))
+	})
+	if err != nil {
+		return "", 0, err
+	}
+	return resp.AccessToken, resp.expiresIn(), nil
+}
+
+// tokenResponse is the response of an OAuth2 token endpoint (RFC 6749 section 5.1)
+type tokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// expiresIn returns the lifetime of the access token, zero when the endpoint did not say
+func (r *tokenResponse) expiresIn() time.Duration {
+	return time.Duration(r.ExpiresIn) * time.Second
+}
+
+// tokenError is an error response of an OAuth2 token endpoint (RFC 6749 section 5.2)
+type tokenError struct {
+	Code        string
+	Description string
+	StatusCode  int
+}
+
+func (e *tokenError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("token endpoint returned %d", e.StatusCode)
+	}
+	return fmt.Sprintf("token endpoint returned %d: %s %s", e.StatusCode, e.Code, e.Description)
+}
+
+// requestToken posts form to a token endpoint and returns its bearer token. authenticate,
+// when not nil, adds the client authentication to the request. A rejected request returns
+// a *tokenError.
+func requestToken(
+	ctx context.Context,
+	client *http.Client,
+	tokenURL string,
+	form url.Values,
+	authenticate func(*http.Request),
+) (*tokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if authenticate != nil {
+		authenticate(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request to %s failed: %w", tokenURL, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close after reading the body
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errResp struct {
+			Error            string `json:"error"`
+			ErrorDescription string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &errResp) //nolint:errcheck // a body that is not an error response leaves Code empty
+		return nil, &tokenError{StatusCode: resp.StatusCode, Code: errResp.Error, Description: errResp.ErrorDescription}
+	}
+	var tokenResp tokenResponse
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return nil, fmt.Errorf("failed to parse token response: %w", err)
+	}
+	if tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("token response has no access_token")
+	}
+	if tokenResp.TokenType != "" && !strings.EqualFold(tokenResp.TokenType, "bearer") {
+		return nil, fmt.Errorf("unsupported token type %q", tokenResp.TokenType)
+	}
+	return &tokenResp, nil
+}
@@ -0,0 +1,149 @@
+package hyperfleetapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenServer returns a token endpoint issuing token-1, token-2, ... valid for expiresIn seconds
+func newTokenServer(t *testing.T, expiresIn int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var issued atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if r.Method != http.MethodPost || !ok || id != "adapter" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"error":"invalid_client","error_description":"bad credentials"}`)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d,"scope":%q}`,
+			n, expiresIn, r.PostForm.Get("scope"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func newOAuth2Config(t *testing.T, tokenURL, secret string) *OAuth2Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "client-secret")
+	require.NoError(t, os.WriteFile(path, []byte(secret+"\n"), 0600))
+	return &OAuth2Config{TokenURL: tokenURL, ClientID: "adapter", ClientSecretPath: path, Scopes: []string{"api"}}
+}
+
+func TestOAuth2TokenSource_CachesUntilExpiry(t *testing.T) {
+	server, issued := newTokenServer(t, 3600)
+	ts := newOAuth2TokenSource(newOAuth2Config(t, server.URL, "s3cret"), server.Client())
+
+	tok1, err := ts.token(context.Background())
+	require.NoError(t, err)
+	tok2, err := ts.token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok1)
+	assert.Equal(t, tok1, tok2, "token should be cached")
+	assert.Equal(t, int32(1), issued.Load())
+
+	// Expire the cache manually
+	ts.expiresAt = time.Now().Add(-time.Second)
+	tok3, err := ts.token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", tok3)
+
+	ts.invalidate()
+	tok4, err := ts.token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-3", tok4)
+}
+
+func TestOAuth2TokenSource_RefreshesBeforeExpiry(t *testing.T) {
+	server, _ := newTokenServer(t, 60)
+	ts := newOAuth2TokenSource(newOAuth2Config(t, server.URL, "s3cret"), server.Client())
+
+	_, err := ts.token(context.Background())
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(30*time.Second), ts.expiresAt, 5*time.Second,
+		"token should be refreshed before it expires")
+}
+
+func TestOAuth2TokenSource_Errors(t *testing.T) {
+	server, _ := newTokenServer(t, 3600)
+
+	ts := newOAuth2TokenSource(newOAuth2Config(t, server.URL, "wrong"), server.Client())
+	_, err := ts.token(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid_client bad credentials")
+
+	config := newOAuth2Config(t, server.URL, "s3cret")
+	config.ClientSecretPath = "/nonexistent/client-secret"
+	ts = newOAuth2TokenSource(config, server.Client())
+	_, err = ts.token(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "reading client secret file")
+}
+
+func TestClientOAuth2ClientCredentials(t *testing.T) {
+	tokenServer, issued := newTokenServer(t, 3600)
+
+	// The API rejects token-1 as if it was revoked, then accepts newer tokens
+	var receivedAuth []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		receivedAuth = append(receivedAuth, auth)
+		if auth == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(testLog(),
+		WithBaseURL(server.URL),
+		WithOAuth2ClientCredentials(newOAuth2Config(t, tokenServer.URL, "s3cret")),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.Get(context.Background(), "/test")
+	require.NoError(t, err)
+	assert.True(t, resp.IsSuccess())
+	assert.Equal(t, []string{"Bearer token-1", "Bearer token-2"}, receivedAuth)
+
+	_, err = client.Get(context.Background(), "/test")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token-2", receivedAuth[2], "refreshed token should be cached")
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestClientTokenFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("file-token"), 0600))
+
+	var receivedAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(testLog(), WithBaseURL(server.URL), WithTokenFile(path, time.Minute))
+	require.NoError(t, err)
+
+	_, err = client.Get(context.Background(), "/test")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer file-token", receivedAuth)
+}
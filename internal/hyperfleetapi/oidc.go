@@ -0,0 +1,448 @@
+package hyperfleetapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// oidcCacheDirName is the directory of the user config directory tokens are cached in
+	oidcCacheDirName = "hyperfleet-adapter"
+	// oidcRequestTimeout bounds each request to the issuer during a login
+	oidcRequestTimeout = 30 * time.Second
+	// oidcLoginTimeout bounds how long a login waits for the user
+	oidcLoginTimeout = 5 * time.Minute
+	// deviceCodeGrantType is the grant type of the device flow token requests (RFC 8628 section 3.4)
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+	// defaultDevicePollInterval is used when the device authorization response has no interval
+	defaultDevicePollInterval = 5
+)
+
+// oidcToken is a cached OIDC login
+type oidcToken struct {
+	Expiry       time.Time `json:"expiry,omitempty"` // zero value means the token does not expire
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+}
+
+// oidcProvider holds the endpoints of an OIDC issuer
+type oidcProvider struct {
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+// oidcTokenSource serves the access token of a cached OIDC login, refreshing it with the
+// refresh token once it expires. It never logs in: without a usable login it fails and
+// asks to run the login command. It is safe for concurrent use.
+type oidcTokenSource struct {
+	client      *http.Client
+	config      *OIDCConfig
+	cached      *oidcToken
+	mu          sync.Mutex
+	expirySkew  time.Duration
+	pollUnit    time.Duration // unit of the device flow poll interval, shortened in tests
+	invalidated bool
+}
+
+func newOIDCTokenSource(config *OIDCConfig, client *http.Client) *oidcTokenSource {
+	return &oidcTokenSource{config: config, client: client, expirySkew: oauth2ExpiryDelta, pollUnit: time.Second}
+}
+
+// EnsureOIDCLogin makes sure a login of config is cached, logging in interactively with
+// the configured flow when there is no valid or refreshable one. Login instructions are
+// written to prompt.
+func EnsureOIDCLogin(ctx context.Context, config *OIDCConfig, prompt io.Writer) error {
+	s := newOIDCTokenSource(config, &http.Client{Timeout: oidcRequestTimeout})
+	if _, err := s.token(ctx); err == nil {
+		return nil
+	}
+	return s.login(ctx, prompt)
+}
+
+// LoginOIDC logs in interactively with the configured flow and caches the token, replacing
+// any cached login. Login instructions are written to prompt. It returns the cache file.
+func LoginOIDC(ctx context.Context, config *OIDCConfig, prompt io.Writer) (string, error) {
+	s := newOIDCTokenSource(config, &http.Client{Timeout: oidcRequestTimeout})
+	if err := s.login(ctx, prompt); err != nil {
+		return "", err
+	}
+	return OIDCCachePath(config)
+}
+
+// OIDCCachePath returns the file the login of config is cached in. Logins are keyed by
+// issuer and client ID, so several configs can share a cache directory.
+func OIDCCachePath(config *OIDCConfig) (string, error) {
+	dir := config.CacheDir
+	if dir == "" {
+		userDir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("no OIDC cache directory: %w", err)
+		}
+		dir = filepath.Join(userDir, oidcCacheDirName)
+	}
+	sum := sha256.Sum256([]byte(config.Issuer + "\n" + config.ClientID))
+	return filepath.Join(dir, "oidc-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
+
+// token implements tokenSource
+func (s *oidcTokenSource) token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cached == nil {
+		cached, err := s.load()
+		if err != nil {
+			return "", err
+		}
+		s.cached = cached
+	}
+	if s.cached != nil && !s.invalidated &&
+		(s.cached.Expiry.IsZero() || time.Now().Add(s.expirySkew).Before(s.cached.Expiry)) {
+		return s.cached.AccessToken, nil
+	}
+	if s.cached == nil || s.cached.RefreshToken == "" {
+		return "", fmt.Errorf("no OIDC login for %s: run `adapter login`", s.config.Issuer)
+	}
+
+	refreshed, err := s.refresh(ctx, s.cached.RefreshToken)
+	if err != nil {
+		return "", fmt.Errorf("refreshing the OIDC login for %s failed, run `adapter login`: %w", s.config.Issuer, err)
+	}
+	if err := s.save(refreshed); err != nil {
+		return "", err
+	}
+	return refreshed.AccessToken, nil
+}
+
+// invalidate implements tokenSource; the next call refreshes the token
+func (s *oidcTokenSource) invalidate() {
+	s.mu.Lock()
+	s.invalidated = true
+	s.mu.Unlock()
+}
+
+// load reads the cached login, nil when there is none
+func (s *oidcTokenSource) load() (*oidcToken, error) {
+	path, err := OIDCCachePath(s.config)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path) //nolint:gosec // path is the configured token cache
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading OIDC token cache %s: %w", path, err)
+	}
+	var tok oidcToken
+	if err := json.Unmarshal(raw, &tok); err != nil || tok.AccessToken == "" {
+		// A corrupt cache is a missing login
+		return nil, nil
+	}
+	return &tok, nil
+}
+
+// save caches tok, readable by the user only, and makes it the current token
+func (s *oidcTokenSource) save(tok *oidcToken) error {
+	path, err := OIDCCachePath(s.config)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating OIDC token cache directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o600); err != nil {
+		return fmt.Errorf("writing OIDC token cache: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("writing OIDC token cache: %w", err)
+	}
+	s.cached = tok
+	s.invalidated = false
+	return nil
+}
+
+// newOIDCToken converts a token response, keeping refreshToken when the response has none
+func newOIDCToken(resp *tokenResponse, refreshToken string) *oidcToken {
+	tok := &oidcToken{AccessToken: resp.AccessToken, RefreshToken: resp.RefreshToken}
+	if tok.RefreshToken == "" {
+		tok.RefreshToken = refreshToken
+	}
+	if resp.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(resp.expiresIn())
+	}
+	return tok
+}
+
+// refresh obtains a new token with the refresh token grant (RFC 6749 section 6)
+func (s *oidcTokenSource) refresh(ctx context.Context, refreshToken string) (*oidcToken, error) {
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := requestToken(ctx, s.client, provider.TokenEndpoint, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {s.config.ClientID},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newOIDCToken(resp, refreshToken), nil
+}
+
+// discover reads the endpoints of the issuer (OpenID Connect Discovery section 4)
+func (s *oidcTokenSource) discover(ctx context.Context) (*oidcProvider, error) {
+	discoveryURL := strings.TrimSuffix(s.config.Issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OIDC discovery request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery of %s failed: %w", s.config.Issuer, err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close after reading the body
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery of %s returned %d", s.config.Issuer, resp.StatusCode)
+	}
+	var provider oidcProvider
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseSize)).Decode(&provider); err != nil {
+		return nil, fmt.Errorf("failed to parse the OIDC discovery document of %s: %w", s.config.Issuer, err)
+	}
+	if provider.TokenEndpoint == "" {
+		return nil, fmt.Errorf("OIDC discovery document of %s has no token_endpoint", s.config.Issuer)
+	}
+	return &provider, nil
+}
+
+// scope returns the requested scopes; offline_access asks for a refresh token
+func (s *oidcTokenSource) scope() string {
+	return strings.Join(append([]string{"openid", "offline_access"}, s.config.Scopes...), " ")
+}
+
+// login runs the interactive login flow and caches its token
+func (s *oidcTokenSource) login(ctx context.Context, prompt io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, oidcLoginTimeout)
+	defer cancel()
+
+	provider, err := s.discover(ctx)
+	if err != nil {
+		return err
+	}
+	var tok *oidcToken
+	switch s.config.Flow {
+	case "", OIDCFlowDevice:
+		tok, err = s.deviceLogin(ctx, provider, prompt)
+	case OIDCFlowPKCE:
+		tok, err = s.pkceLogin(ctx, provider, prompt)
+	default:
+		return fmt.Errorf("unsupported OIDC flow %q (supported: device, pkce)", s.config.Flow)
+	}
+	if err != nil {
+		return fmt.Errorf("OIDC login to %s failed: %w", s.config.Issuer, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(tok)
+}
+
+// deviceLogin runs the device authorization grant (RFC 8628)
+func (s *oidcTokenSource) deviceLogin(
+	ctx context.Context,
+	provider *oidcProvider,
+	prompt io.Writer,
+) (*oidcToken, error) {
+	if provider.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("the issuer does not support the device flow, use flow: pkce")
+	}
+	form := url.Values{"client_id": {s.config.ClientID}, "scope": {s.scope()}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.DeviceAuthorizationEndpoint,
+		strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create device authorization request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	defer resp.Body.Close() //nolint:errcheck // best-effort close after reading the body
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("device authorization endpoint returned %d", resp.StatusCode)
+	}
+	var device struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		Interval                int64  `json:"interval"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxTokenResponseSize)).Decode(&device); err != nil {
+		return nil, fmt.Errorf("failed to parse device authorization response: %w", err)
+	}
+	if device.DeviceCode == "" || device.VerificationURI == "" {
+		return nil, fmt.Errorf("device authorization response has no device_code or verification_uri")
+	}
+
+	if device.VerificationURIComplete != "" {
+		_, _ = fmt.Fprintf(prompt, "To log in, open %s and confirm the code %s\n", //nolint:errcheck // best-effort prompt
+			device.VerificationURIComplete, device.UserCode)
+	} else {
+		_, _ = fmt.Fprintf(prompt, "To log in, open %s and enter the code %s\n", //nolint:errcheck // best-effort prompt
+			device.VerificationURI, device.UserCode)
+	}
+
+	interval := device.Interval
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+	poll := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {device.DeviceCode},
+		"client_id":   {s.config.ClientID},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for the login: %w", ctx.Err())
+		case <-time.After(time.Duration(interval) * s.pollUnit):
+		}
+		resp, err := requestToken(ctx, s.client, provider.TokenEndpoint, poll, nil)
+		var tokErr *tokenError
+		switch {
+		case err == nil:
+			return newOIDCToken(resp, ""), nil
+		case errors.As(err, &tokErr) && tokErr.Code == "authorization_pending":
+		case errors.As(err, &tokErr) && tokErr.Code == "slow_down":
+			interval += 5
+		default:
+			return nil, err
+		}
+	}
+}
+
+// pkceLogin runs the authorization code grant with PKCE (RFC 7636), receiving the code on
+// a loopback redirect (RFC 8252 section 7.3)
+func (s *oidcTokenSource) pkceLogin(ctx context.Context, provider *oidcProvider, prompt io.Writer) (*oidcToken, error) {
+	if provider.AuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("OIDC discovery document has no authorization_endpoint")
+	}
+	authURL, err := url.Parse(provider.AuthorizationEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization_endpoint: %w", err)
+	}
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomURLString(16)
+	if err != nil {
+		return nil, err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the login callback: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr())
+
+	codes := make(chan string, 1)
+	failures := make(chan error, 1)
+	server := &http.Server{
+		ReadHeaderTimeout: oidcRequestTimeout,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/callback" {
+				http.NotFound(w, r)
+				return
+			}
+			query := r.URL.Query()
+			switch {
+			case query.Get("state") != state:
+				http.Error(w, "Login failed: unexpected state.", http.StatusBadRequest)
+				return
+			case query.Get("error") != "":
+				http.Error(w, "Login failed: "+query.Get("error"), http.StatusBadRequest)
+				select {
+				case failures <- fmt.Errorf("authorization failed: %s %s",
+					query.Get("error"), query.Get("error_description")):
+				default:
+				}
+				return
+			}
+			_, _ = fmt.Fprintln(w, "Logged in, you can close this window.") //nolint:errcheck // best-effort response
+			select {
+			case codes <- query.Get("code"):
+			default:
+			}
+		}),
+	}
+	go func() {
+		_ = server.Serve(listener) //nolint:errcheck // returns ErrServerClosed once the login is done
+	}()
+	defer server.Close() //nolint:errcheck // the callback server is no longer needed
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", s.config.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("scope", s.scope())
+	query.Set("state", state)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	_, _ = fmt.Fprintf(prompt, //nolint:errcheck // best-effort prompt
+		"To log in, open this URL in a browser on this machine:\n  %s\n", authURL)
+
+	var code string
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("timed out waiting for the login: %w", ctx.Err())
+	case failure := <-failures:
+		return nil, failure
+	case code = <-codes:
+	}
+	resp, err := requestToken(ctx, s.client, provider.TokenEndpoint, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"client_id":     {s.config.ClientID},
+		"code_verifier": {verifier},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return newOIDCToken(resp, ""), nil
+}
+
+// randomURLString returns n random bytes, base64url-encoded
+func randomURLString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,177 @@
+package hyperfleetapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIssuer is an OIDC issuer supporting the device, PKCE and refresh grants
+type fakeIssuer struct {
+	*httptest.Server
+	challenge atomic.Value
+	polls     atomic.Int32
+	issued    atomic.Int32
+}
+
+func newFakeIssuer(t *testing.T, expiresIn int) *fakeIssuer {
+	t.Helper()
+	issuer := &fakeIssuer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"authorization_endpoint":        issuer.URL + "/authorize",
+			"token_endpoint":                issuer.URL + "/token",
+			"device_authorization_endpoint": issuer.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"device_code":"dev-1","user_code":"ABCD-EFGH","verification_uri":"%s/activate","interval":1}`,
+			issuer.URL)
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		issuer.challenge.Store(query.Get("code_challenge"))
+		redirect := query.Get("redirect_uri") + "?code=code-1&state=" + url.QueryEscape(query.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("client_id") != "cli" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.PostForm.Get("grant_type") {
+		case deviceCodeGrantType:
+			if issuer.polls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"error":"authorization_pending"}`)
+				return
+			}
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+			if r.PostForm.Get("code") != "code-1" ||
+				base64.RawURLEncoding.EncodeToString(sum[:]) != issuer.challenge.Load() {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"error":"invalid_grant"}`)
+				return
+			}
+		case "refresh_token":
+			if r.PostForm.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = fmt.Fprint(w, `{"error":"invalid_grant"}`)
+				return
+			}
+		}
+		n := issuer.issued.Add(1)
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","refresh_token":"refresh-1","expires_in":%d}`,
+			n, expiresIn)
+	})
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+// browser is a login prompt that opens the first URL written to it, as a user would
+type browser struct {
+	once sync.Once
+}
+
+var promptURL = regexp.MustCompile(`https?://\S+`)
+
+func (b *browser) Write(p []byte) (int, error) {
+	if u := promptURL.FindString(string(p)); u != "" {
+		b.once.Do(func() {
+			go func() {
+				resp, err := http.Get(u) //nolint:noctx // test browser
+				if err == nil {
+					_ = resp.Body.Close()
+				}
+			}()
+		})
+	}
+	return len(p), nil
+}
+
+func TestOIDCDeviceLogin(t *testing.T) {
+	issuer := newFakeIssuer(t, 3600)
+	config := &OIDCConfig{Issuer: issuer.URL, ClientID: "cli", CacheDir: t.TempDir()}
+
+	ts := newOIDCTokenSource(config, issuer.Client())
+	_, err := ts.token(context.Background())
+	require.ErrorContains(t, err, "run `adapter login`", "requests should never log in interactively")
+
+	ts.pollUnit = time.Millisecond
+	require.NoError(t, ts.login(context.Background(), io.Discard))
+	assert.Equal(t, int32(2), issuer.polls.Load(), "the token should be polled until the user approves")
+
+	path, err := OIDCCachePath(config)
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// Another process reads the cached login
+	tok, err := newOIDCTokenSource(config, issuer.Client()).token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok)
+	require.NoError(t, EnsureOIDCLogin(context.Background(), config, io.Discard))
+	assert.Equal(t, int32(1), issuer.issued.Load(), "a cached login should not log in again")
+}
+
+func TestOIDCPKCELogin(t *testing.T) {
+	issuer := newFakeIssuer(t, 3600)
+	config := &OIDCConfig{Issuer: issuer.URL, ClientID: "cli", Flow: OIDCFlowPKCE, CacheDir: t.TempDir()}
+
+	path, err := LoginOIDC(context.Background(), config, &browser{})
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	tok, err := newOIDCTokenSource(config, issuer.Client()).token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok)
+}
+
+func TestOIDCTokenSource_Refresh(t *testing.T) {
+	issuer := newFakeIssuer(t, 3600)
+	config := &OIDCConfig{Issuer: issuer.URL, ClientID: "cli", CacheDir: t.TempDir()}
+	ts := newOIDCTokenSource(config, issuer.Client())
+	require.NoError(t, ts.save(&oidcToken{
+		AccessToken:  "expired",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(-time.Minute),
+	}))
+
+	other := newOIDCTokenSource(config, issuer.Client())
+	tok, err := other.token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", tok, "an expired token should be refreshed")
+
+	other.invalidate()
+	tok, err = other.token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", tok, "a rejected token should be refreshed")
+
+	cached, err := newOIDCTokenSource(config, issuer.Client()).load()
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", cached.AccessToken, "the refreshed token should be cached")
+
+	require.NoError(t, ts.save(&oidcToken{AccessToken: "expired", RefreshToken: "revoked"}))
+	ts.invalidate()
+	_, err = ts.token(context.Background())
+	assert.ErrorContains(t, err, "invalid_grant")
+}
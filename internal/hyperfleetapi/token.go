@@ -1,6 +1,7 @@
 package hyperfleetapi
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -8,6 +9,13 @@ import (
 	"time"
 )
 
+// tokenSource provides the bearer token injected into every request
+type tokenSource interface {
+	token(ctx context.Context) (string, error)
+	// invalidate drops a cached token after the API rejected it
+	invalidate()
+}
+
 // fileTokenSource reads a bearer token from disk on every call, or caches it
 // for cacheTTL when cacheTTL > 0. A zero cacheTTL disables caching and causes
 // the file to be re-read on every request. It is safe for concurrent use.
@@ -23,6 +31,18 @@ func newFileTokenSource(path string, cacheTTL time.Duration) *fileTokenSource {
 	return &fileTokenSource{path: path, cacheTTL: cacheTTL}
 }
 
+// token implements tokenSource
+func (s *fileTokenSource) token(_ context.Context) (string, error) {
+	return s.get()
+}
+
+// invalidate implements tokenSource; the next call re-reads the file
+func (s *fileTokenSource) invalidate() {
+	s.mu.Lock()
+	s.expiresAt = time.Time{}
+	s.mu.Unlock()
+}
+
 // get returns the current token. When cacheTTL > 0 the result is served from
 // memory until the TTL elapses; when cacheTTL == 0 the file is read every call.
 func (s *fileTokenSource) get() (string, error) {
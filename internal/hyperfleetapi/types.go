@@ -2,7 +2,10 @@ package hyperfleetapi
 
 import (
 	"context"
+	"io"
 	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 )
 
 // -----------------------------------------------------------------------------
@@ -21,6 +24,14 @@ const (
 	BackoffConstant BackoffStrategy = "constant"
 )
 
+// Request body media types
+const (
+	// ContentTypeJSONPatch is the media type of RFC 6902 JSON Patch request bodies
+	ContentTypeJSONPatch = "application/json-patch+json"
+	// ContentTypeForm is the media type of form-encoded request bodies
+	ContentTypeForm = "application/x-www-form-urlencoded"
+)
+
 // Default configuration values
 const (
 	DefaultTimeout       = 10 * time.Second
@@ -34,21 +45,93 @@ const (
 // Client Configuration
 // -----------------------------------------------------------------------------
 
-// AuthConfig holds optional JWT bearer token authentication configuration.
-// When set, a bearer token is read from TokenPath and injected as an
-// Authorization header on every outbound request.
+// AuthConfig holds optional bearer token authentication configuration.
+// When set, a bearer token is read from TokenPath, obtained with the OAuth2
+// client credentials grant or taken from a cached OIDC login, and injected as an
+// Authorization header on every outbound request. Only one of TokenPath, OAuth2
+// and OIDC may be set.
 type AuthConfig struct {
+	// OAuth2 obtains tokens from an OAuth2 token endpoint instead of a file.
+	OAuth2 *OAuth2Config `yaml:"oauth2,omitempty" mapstructure:"oauth2"`
+	// OIDC uses the token of an interactive OIDC login, for local CLI use.
+	OIDC *OIDCConfig `yaml:"oidc,omitempty" mapstructure:"oidc"`
 	// TokenPath is the absolute path to a file containing the bearer token.
 	TokenPath string `yaml:"token_path,omitempty" mapstructure:"token_path"`
 	// TokenCacheTTL controls how long the token is cached in memory.
 	// Zero means the file is re-read on every request.
-	TokenCacheTTL time.Duration `yaml:"token_cache_ttl,omitempty" mapstructure:"token_cache_ttl"`
+	TokenCacheTTL utils.Duration `yaml:"token_cache_ttl,omitempty" mapstructure:"token_cache_ttl"`
+}
+
+// OAuth2Config configures the OAuth2 client credentials grant. Tokens are cached
+// until shortly before they expire and then fetched again.
+type OAuth2Config struct {
+	// TokenURL is the token endpoint of the authorization server.
+	TokenURL string `yaml:"token_url" mapstructure:"token_url"`
+	// ClientID is the OAuth2 client ID.
+	ClientID string `yaml:"client_id" mapstructure:"client_id"`
+	// ClientSecretPath is the absolute path to a file containing the client secret.
+	// It is re-read on every token request, so a rotated secret is picked up.
+	ClientSecretPath string `yaml:"client_secret_path" mapstructure:"client_secret_path"`
+	// Scopes are the scopes requested with the token. Empty requests the default scopes.
+	Scopes []string `yaml:"scopes,omitempty" mapstructure:"scopes"`
+}
+
+// OIDC login flows
+const (
+	// OIDCFlowDevice is the device authorization grant (RFC 8628): the user enters a code
+	// shown in the terminal on any browser
+	OIDCFlowDevice = "device"
+	// OIDCFlowPKCE is the authorization code grant with PKCE (RFC 7636), redirecting a
+	// browser on this machine to a local callback
+	OIDCFlowPKCE = "pkce"
+)
+
+// OIDCConfig configures an interactive OIDC login for running the adapter from a developer
+// machine. The login obtains a short-lived access token and a refresh token, cached in
+// CacheDir; requests use the cached token and refresh it when it expires.
+type OIDCConfig struct {
+	// Issuer is the OIDC issuer URL; its endpoints are read from
+	// /.well-known/openid-configuration.
+	Issuer string `yaml:"issuer" mapstructure:"issuer"`
+	// ClientID is the ID of a public OIDC client.
+	ClientID string `yaml:"client_id" mapstructure:"client_id"`
+	// Flow is the login flow: device (default) or pkce.
+	Flow string `yaml:"flow,omitempty" mapstructure:"flow"`
+	// CacheDir is the directory tokens are cached in. Empty uses
+	// ~/.config/hyperfleet-adapter (the user config directory).
+	CacheDir string `yaml:"cache_dir,omitempty" mapstructure:"cache_dir"`
+	// Scopes are the scopes requested in addition to openid and offline_access.
+	Scopes []string `yaml:"scopes,omitempty" mapstructure:"scopes"`
+}
+
+// CompressionConfig controls gzip compression of request and response bodies.
+// Responses are requested gzip-encoded and decompressed by default; request bodies
+// are sent uncompressed unless MinBytes is set.
+type CompressionConfig struct {
+	// Disabled turns compression off: responses are requested with Accept-Encoding: identity
+	// and request bodies are never compressed.
+	Disabled bool `yaml:"disabled,omitempty" mapstructure:"disabled"`
+	// MinBytes gzips request bodies of at least this many bytes and sets
+	// Content-Encoding: gzip. Zero or less sends request bodies uncompressed.
+	MinBytes int `yaml:"min_bytes,omitempty" mapstructure:"min_bytes"`
+}
+
+// ClientProfile overrides the endpoint, headers and auth of a ClientConfig for one environment.
+// Set fields replace the base values; DefaultHeaders are merged into the base headers.
+type ClientProfile struct {
+	DefaultHeaders map[string]string `yaml:"default_headers,omitempty" mapstructure:"default_headers"`
+	Auth           *AuthConfig       `yaml:"auth,omitempty" mapstructure:"auth"`
+	BaseURL        string            `yaml:"base_url,omitempty" mapstructure:"base_url"`
+	Version        string            `yaml:"version,omitempty" mapstructure:"version"`
 }
 
 // ClientConfig holds the configuration for the HTTP client
 type ClientConfig struct {
 	// DefaultHeaders are headers added to all requests
 	DefaultHeaders map[string]string `yaml:"default_headers,omitempty" mapstructure:"default_headers"`
+	// Profiles are named per-environment overrides (e.g. dev, stage, prod) of the endpoint,
+	// headers and auth. The selected profile is applied when the config is loaded.
+	Profiles map[string]ClientProfile `yaml:"profiles,omitempty" mapstructure:"profiles"`
 	// Auth configures optional JWT bearer token authentication.
 	// When nil, requests are sent without an Authorization header.
 	Auth *AuthConfig `yaml:"auth,omitempty" mapstructure:"auth"`
@@ -57,14 +140,18 @@ type ClientConfig struct {
 	BaseURL string `yaml:"base_url,omitempty" mapstructure:"base_url"`
 	// Version is the HyperFleet API version (e.g., "v1")
 	Version string `yaml:"version,omitempty" mapstructure:"version"`
+	// Profile is the name of the selected entry in Profiles (empty for none)
+	Profile string `yaml:"profile,omitempty" mapstructure:"profile"`
 	// RetryBackoff is the backoff strategy for retries
 	RetryBackoff BackoffStrategy `yaml:"retry_backoff,omitempty" mapstructure:"retry_backoff"`
+	// Compression controls gzip compression of request and response bodies.
+	Compression CompressionConfig `yaml:"compression,omitempty" mapstructure:"compression"`
 	// Timeout is the HTTP client timeout for requests
-	Timeout time.Duration `yaml:"timeout,omitempty" mapstructure:"timeout"`
+	Timeout utils.Duration `yaml:"timeout,omitempty" mapstructure:"timeout"`
 	// BaseDelay is the initial delay for retry backoff
-	BaseDelay time.Duration `yaml:"base_delay,omitempty" mapstructure:"base_delay"`
+	BaseDelay utils.Duration `yaml:"base_delay,omitempty" mapstructure:"base_delay"`
 	// MaxDelay is the maximum delay for retry backoff
-	MaxDelay time.Duration `yaml:"max_delay,omitempty" mapstructure:"max_delay"`
+	MaxDelay utils.Duration `yaml:"max_delay,omitempty" mapstructure:"max_delay"`
 	// RetryAttempts is the number of retry attempts for failed requests
 	RetryAttempts int `yaml:"retry_attempts,omitempty" mapstructure:"retry_attempts"`
 }
@@ -73,11 +160,11 @@ type ClientConfig struct {
 func DefaultClientConfig() *ClientConfig {
 	return &ClientConfig{
 		Version:        "v1",
-		Timeout:        DefaultTimeout,
+		Timeout:        utils.Duration(DefaultTimeout),
 		RetryAttempts:  DefaultRetryAttempts,
 		RetryBackoff:   DefaultRetryBackoff,
-		BaseDelay:      DefaultBaseDelay,
-		MaxDelay:       DefaultMaxDelay,
+		BaseDelay:      utils.Duration(DefaultBaseDelay),
+		MaxDelay:       utils.Duration(DefaultMaxDelay),
 		DefaultHeaders: make(map[string]string),
 	}
 }
@@ -98,6 +185,9 @@ type Request struct {
 	Method string
 	// URL is the full URL for the request
 	URL string
+	// ResponseDecoder, when set, decodes a successful response body as it is read instead
+	// of buffering it in Response.Body; the result is returned in Response.Decoded
+	ResponseDecoder func(io.Reader) (interface{}, error)
 	// Body is the request body (for POST, PUT, PATCH)
 	Body []byte
 	// Timeout overrides the client timeout for this request
@@ -147,6 +237,14 @@ func WithJSONBody(body []byte) RequestOption {
 	}
 }
 
+// WithResponseDecoder streams a successful response body through decode instead of
+// buffering it. Error responses are still buffered in Response.Body.
+func WithResponseDecoder(decode func(io.Reader) (interface{}, error)) RequestOption {
+	return func(r *Request) {
+		r.ResponseDecoder = decode
+	}
+}
+
 // WithRequestTimeout sets a custom timeout for this specific request
 func WithRequestTimeout(timeout time.Duration) RequestOption {
 	return func(r *Request) {
@@ -178,7 +276,10 @@ type Response struct {
 	Headers map[string][]string
 	// Status is the HTTP status string (e.g., "200 OK")
 	Status string
-	// Body is the response body
+	// Decoded is the result of the request's ResponseDecoder
+	Decoded interface{}
+	// Body is the response body; it is nil when the request had a ResponseDecoder and
+	// the response was successful
 	Body []byte
 	// Duration is how long the request took
 	Duration time.Duration
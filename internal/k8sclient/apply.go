@@ -56,6 +56,7 @@ func (c *Client) ApplyResource(
 // If the resource doesn't exist, it creates it.
 // If it exists and the generation differs, it updates (or recreates if RecreateOnChange=true).
 // If it exists and the generation matches, it skips the update (idempotent).
+// With ServerSideApply=true, creates and updates are sent as a server-side apply patch.
 //
 // The manifest must have the hyperfleet.io/generation annotation set.
 func (c *Client) ApplyManifest(
@@ -98,9 +99,43 @@ func (c *Client) ApplyManifest(
 	gvk := newManifest.GroupVersionKind()
 	name := newManifest.GetName()
 
+	// An unchanged generation does not mean the live object still matches the manifest:
+	// something else may have edited it
+	if decision.Operation == manifest.OperationSkip && existing != nil && opts.DetectDrift {
+		if drift := manifest.Diff(existing, newManifest); len(drift) > 0 {
+			result.Drift = drift
+			c.log.Debugf(ctx, "ApplyManifest %s/%s: %d field(s) drifted: %s",
+				gvk.Kind, name, len(drift), manifest.FormatChanges(drift))
+			result.Reason = fmt.Sprintf("%s, drift detected in %d field(s)", decision.Reason, len(drift))
+			if opts.ReconcileDrift {
+				result.Operation = manifest.OperationUpdate
+				if opts.RecreateOnChange {
+					result.Operation = manifest.OperationRecreate
+				}
+			}
+		}
+	}
+
 	c.log.Debugf(ctx, "ApplyManifest %s/%s: operation=%s reason=%s",
 		gvk.Kind, name, result.Operation, result.Reason)
 
+	if result.Operation == manifest.OperationUpdate || result.Operation == manifest.OperationRecreate {
+		result.Changes = manifest.Diff(existing, newManifest)
+		c.log.Debugf(ctx, "ApplyManifest %s/%s: %d field(s) changed: %s",
+			gvk.Kind, name, len(result.Changes), manifest.FormatChanges(result.Changes))
+	}
+
+	// Server-side apply sends creates and updates as one apply patch: no resourceVersion is
+	// needed and fields owned by other managers are left alone
+	if opts.ServerSideApply &&
+		(result.Operation == manifest.OperationCreate || result.Operation == manifest.OperationUpdate) {
+		if err := c.serverSideApply(ctx, newManifest, opts.FieldManager); err != nil {
+			return nil, fmt.Errorf("failed to %s resource %s/%s: %w",
+				result.Operation, gvk.Kind, name, err)
+		}
+		return result, nil
+	}
+
 	// Execute the operation
 	var applyErr error
 	switch result.Operation {
@@ -89,6 +89,60 @@ func TestApplyManifest_SkipSameGeneration(t *testing.T) {
 	assert.Equal(t, manifest.OperationSkip, result.Operation)
 }
 
+func TestApplyManifest_UpdateChanges(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient()
+
+	_, err := c.CreateResource(ctx, newConfigMap("existing-cm", "default", 1))
+	require.NoError(t, err)
+	existing, err := c.GetResource(ctx, CommonResourceKinds.ConfigMap, "default", "existing-cm", nil)
+	require.NoError(t, err)
+
+	newCm := newConfigMap("existing-cm", "default", 2)
+	newCm.Object["data"] = map[string]any{"key": "changed"}
+	result, err := c.ApplyManifest(ctx, newCm, existing, nil)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationUpdate, result.Operation)
+	assert.Equal(t, []manifest.FieldChange{
+		{Path: "data.key", Old: "value", New: "changed"},
+		{Path: `metadata.annotations["hyperfleet.io/generation"]`, Old: "1", New: "2"},
+	}, result.Changes)
+}
+
+func TestApplyManifest_Drift(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient()
+
+	drifted := newConfigMap("drifted-cm", "default", 1)
+	drifted.Object["data"] = map[string]any{"key": "edited"}
+	_, err := c.CreateResource(ctx, drifted)
+	require.NoError(t, err)
+	existing, err := c.GetResource(ctx, CommonResourceKinds.ConfigMap, "default", "drifted-cm", nil)
+	require.NoError(t, err)
+	expectedDrift := []manifest.FieldChange{{Path: "data.key", Old: "edited", New: "value"}}
+
+	result, err := c.ApplyManifest(ctx, newConfigMap("drifted-cm", "default", 1), existing, nil)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationSkip, result.Operation)
+	assert.Empty(t, result.Drift, "drift is not detected by default")
+
+	result, err = c.ApplyManifest(ctx, newConfigMap("drifted-cm", "default", 1), existing,
+		&ApplyOptions{DetectDrift: true})
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationSkip, result.Operation)
+	assert.Equal(t, expectedDrift, result.Drift)
+	assert.Contains(t, result.Reason, "drift detected in 1 field(s)")
+
+	result, err = c.ApplyManifest(ctx, newConfigMap("drifted-cm", "default", 1), existing,
+		&ApplyOptions{DetectDrift: true, ReconcileDrift: true})
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationUpdate, result.Operation)
+	assert.Equal(t, expectedDrift, result.Drift)
+	reconciled, err := c.GetResource(ctx, CommonResourceKinds.ConfigMap, "default", "drifted-cm", nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"key": "value"}, reconciled.Object["data"])
+}
+
 func TestApplyManifest_NilManifest(t *testing.T) {
 	ctx := context.Background()
 	c := newTestClient()
@@ -98,3 +152,30 @@ func TestApplyManifest_NilManifest(t *testing.T) {
 	assert.Nil(t, result)
 	assert.Contains(t, err.Error(), "new manifest cannot be nil")
 }
+
+func TestApplyManifest_ServerSideApply(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient()
+	opts := &ApplyOptions{ServerSideApply: true, FieldManager: "test-manager"}
+
+	cm := newConfigMap("ssa-cm", "default", 1)
+	result, err := c.ApplyManifest(ctx, cm, nil, opts)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationCreate, result.Operation)
+
+	existing, err := c.GetResource(ctx, CommonResourceKinds.ConfigMap, "default", "ssa-cm", nil)
+	require.NoError(t, err)
+
+	// A newer generation is applied without carrying over the resourceVersion
+	updated := newConfigMap("ssa-cm", "default", 2)
+	updated.Object["data"] = map[string]any{"key": "new-value"}
+	result, err = c.ApplyManifest(ctx, updated, existing, opts)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.OperationUpdate, result.Operation)
+	assert.Empty(t, updated.GetResourceVersion())
+
+	got, err := c.GetResource(ctx, CommonResourceKinds.ConfigMap, "default", "ssa-cm", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "new-value", got.Object["data"].(map[string]any)["key"])
+	assert.Equal(t, "2", got.GetAnnotations()["hyperfleet.io/generation"])
+}
@@ -3,15 +3,18 @@ package k8sclient
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
 	apperrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/telemetry"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,11 +23,15 @@ import (
 // Client is the Kubernetes client for managing resources using controller-runtime
 type Client struct {
 	client client.Client
-	log    logger.Logger
+	// discovery serves the API server /version endpoint used by Ping
+	discovery rest.Interface
+	log       logger.Logger
 }
 
 // ClientConfig holds configuration for creating a Kubernetes client
 type ClientConfig struct {
+	// WrapTransport optionally wraps the HTTP transport, e.g. to observe requests
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 	// KubeConfigPath is the path to kubeconfig file
 	// If empty, falls back to in-cluster config
 	KubeConfigPath string
@@ -86,32 +93,48 @@ func NewClient(ctx context.Context, config ClientConfig, log logger.Logger) (*Cl
 	} else {
 		restConfig.Burst = config.Burst
 	}
+	if config.WrapTransport != nil {
+		restConfig.Wrap(config.WrapTransport)
+	}
+	// Trace API calls as children of the calling step; wrapped last so WrapTransport
+	// observes requests within their client span
+	restConfig.Wrap(telemetry.WrapTransport)
 
 	// Create controller-runtime client
 	// This provides automatic caching, better performance, and cleaner API
+	return newClient(restConfig, log)
+}
+
+// newClient creates the controller-runtime client and the discovery client used by Ping
+func newClient(restConfig *rest.Config, log logger.Logger) (*Client, error) {
 	k8sClient, err := client.New(restConfig, client.Options{})
 	if err != nil {
 		return nil, apperrors.KubernetesError("failed to create kubernetes client: %v", err)
 	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, apperrors.KubernetesError("failed to create kubernetes discovery client: %v", err)
+	}
 
 	return &Client{
-		client: k8sClient,
-		log:    log,
+		client:    k8sClient,
+		discovery: discoveryClient.RESTClient(),
+		log:       log,
 	}, nil
 }
 
+// Ping checks that the API server is reachable by reading its /version endpoint
+func (c *Client) Ping(ctx context.Context) error {
+	if err := c.discovery.Get().AbsPath("/version").Do(ctx).Error(); err != nil {
+		return apperrors.KubernetesError("kubernetes API server is not reachable: %v", err)
+	}
+	return nil
+}
+
 // NewClientFromConfig creates a client from an existing rest.Config
 // This is useful for testing with envtest
 func NewClientFromConfig(ctx context.Context, restConfig *rest.Config, log logger.Logger) (*Client, error) {
-	k8sClient, err := client.New(restConfig, client.Options{})
-	if err != nil {
-		return nil, apperrors.KubernetesError("failed to create kubernetes client: %v", err)
-	}
-
-	return &Client{
-		client: k8sClient,
-		log:    log,
-	}, nil
+	return newClient(restConfig, log)
 }
 
 // CreateResource creates a Kubernetes resource from an unstructured object
@@ -265,6 +288,38 @@ func (c *Client) UpdateResource(
 	return obj, nil
 }
 
+// DefaultFieldManager is the server-side apply field manager used when none is configured
+const DefaultFieldManager = "hyperfleet-adapter"
+
+// serverSideApply applies obj with server-side apply, forcing ownership of the fields it
+// sets. Fields owned by other managers that obj does not set are preserved.
+func (c *Client) serverSideApply(ctx context.Context, obj *unstructured.Unstructured, fieldManager string) error {
+	if fieldManager == "" {
+		fieldManager = DefaultFieldManager
+	}
+	gvk := obj.GroupVersionKind()
+
+	// Apply requests must not carry server-populated metadata
+	applyObj := obj.DeepCopy()
+	applyObj.SetResourceVersion("")
+	applyObj.SetUID("")
+	applyObj.SetManagedFields(nil)
+
+	err := c.client.Apply(ctx, client.ApplyConfigurationFromUnstructured(applyObj),
+		client.FieldOwner(fieldManager), client.ForceOwnership)
+	if err != nil {
+		return &apperrors.K8sOperationError{
+			Operation: "apply",
+			Resource:  obj.GetName(),
+			Kind:      gvk.Kind,
+			Namespace: obj.GetNamespace(),
+			Message:   err.Error(),
+			Err:       err,
+		}
+	}
+	return nil
+}
+
 // deleteResource deletes a Kubernetes resource (internal — used by recreateResource).
 func (c *Client) deleteResource(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) error {
 	obj := &unstructured.Unstructured{}
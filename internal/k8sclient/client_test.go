@@ -1,9 +1,16 @@
 package k8sclient
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
 )
 
 func TestDiscoveryConfig(t *testing.T) {
@@ -94,3 +101,27 @@ func TestBuildLabelSelector(t *testing.T) {
 		})
 	}
 }
+
+func TestClientPing(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" || !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"major": "1", "minor": "31", "gitVersion": "v1.31.0"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromConfig(context.Background(), &rest.Config{Host: server.URL}, logger.NewTestLogger())
+	require.NoError(t, err)
+
+	require.NoError(t, client.Ping(context.Background()))
+
+	healthy.Store(false)
+	err = client.Ping(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "kubernetes API server is not reachable")
+}
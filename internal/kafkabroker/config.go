@@ -0,0 +1,112 @@
+package kafkabroker
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// Start offsets of a consumer group without committed offsets
+const (
+	StartOffsetEarliest = "earliest"
+	StartOffsetLatest   = "latest"
+)
+
+// SASL mechanisms
+const (
+	SASLMechanismPlain       = "plain"
+	SASLMechanismSCRAMSHA256 = "scram-sha-256"
+	SASLMechanismSCRAMSHA512 = "scram-sha-512"
+)
+
+// dialTimeout bounds each connection attempt to a broker
+const dialTimeout = 10 * time.Second
+
+// Config configures the Kafka subscriber
+type Config struct {
+	// TLS encrypts the connections to the brokers
+	TLS *utils.TLSConfig `yaml:"tls,omitempty" mapstructure:"tls"`
+	// SASL authenticates the connections to the brokers
+	SASL *SASLConfig `yaml:"sasl,omitempty" mapstructure:"sasl"`
+	// ConsumerGroup is the consumer group of the subscriptions. Empty uses the subscription ID,
+	// so subscriptions with the same ID share the messages of a topic.
+	ConsumerGroup string `yaml:"consumer_group,omitempty" mapstructure:"consumer_group"`
+	// StartOffset is where a consumer group without committed offsets starts reading:
+	// earliest or latest. Empty uses latest.
+	StartOffset string `yaml:"start_offset,omitempty" mapstructure:"start_offset"`
+	// ClientID identifies the adapter to the brokers. Empty uses hyperfleet-adapter.
+	ClientID string `yaml:"client_id,omitempty" mapstructure:"client_id"`
+	// Brokers are the host:port addresses of the bootstrap brokers
+	Brokers []string `yaml:"brokers" mapstructure:"brokers"`
+}
+
+// SASLConfig configures SASL authentication
+type SASLConfig struct {
+	// Mechanism is plain, scram-sha-256 or scram-sha-512
+	Mechanism string `yaml:"mechanism" mapstructure:"mechanism"`
+	Username  string `yaml:"username" mapstructure:"username"`
+	// PasswordPath is the absolute path to a file holding the password. It is read when the
+	// subscriber is created.
+	PasswordPath string `yaml:"password_path" mapstructure:"password_path"`
+}
+
+// startOffset returns the kafka-go start offset of the config
+func (c Config) startOffset() int64 {
+	if c.StartOffset == StartOffsetEarliest {
+		return kafka.FirstOffset
+	}
+	return kafka.LastOffset
+}
+
+// newDialer creates the dialer of the config, reading its TLS and SASL files
+func newDialer(config Config) (*kafka.Dialer, error) {
+	clientID := config.ClientID
+	if clientID == "" {
+		clientID = "hyperfleet-adapter"
+	}
+	dialer := &kafka.Dialer{ClientID: clientID, Timeout: dialTimeout, DualStack: true}
+
+	if config.TLS != nil && config.TLS.Enabled {
+		tlsConfig, err := config.TLS.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("kafka TLS: %w", err)
+		}
+		dialer.TLS = tlsConfig
+	}
+	if config.SASL != nil {
+		mechanism, err := newSASLMechanism(config.SASL)
+		if err != nil {
+			return nil, err
+		}
+		dialer.SASLMechanism = mechanism
+	}
+	return dialer, nil
+}
+
+func newSASLMechanism(config *SASLConfig) (sasl.Mechanism, error) {
+	raw, err := os.ReadFile(config.PasswordPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading Kafka SASL password file: %w", err)
+	}
+	password := strings.TrimSpace(string(raw))
+
+	switch config.Mechanism {
+	case SASLMechanismPlain:
+		return plain.Mechanism{Username: config.Username, Password: password}, nil
+	case SASLMechanismSCRAMSHA256:
+		return scram.Mechanism(scram.SHA256, config.Username, password)
+	case SASLMechanismSCRAMSHA512:
+		return scram.Mechanism(scram.SHA512, config.Username, password)
+	default:
+		return nil, fmt.Errorf("unsupported Kafka SASL mechanism %q (supported: plain, scram-sha-256, scram-sha-512)",
+			config.Mechanism)
+	}
+}
@@ -0,0 +1,24 @@
+package kafkabroker
+
+import (
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/brokerutil"
+)
+
+// headerPrefix prefixes the attribute headers of the CloudEvents Kafka protocol binding
+const headerPrefix = "ce_"
+
+// messageToEvent converts a Kafka message to a CloudEvent following the CloudEvents Kafka
+// protocol binding: a binary-mode message carries the attributes in ce_ headers and the
+// data in its value.
+func messageToEvent(msg kafka.Message) (*event.Event, error) {
+	headers := make(map[string]string, len(msg.Headers))
+	for _, h := range msg.Headers {
+		headers[strings.ToLower(h.Key)] = string(h.Value)
+	}
+	return brokerutil.DecodeEvent(headers, headerPrefix, msg.Value)
+}
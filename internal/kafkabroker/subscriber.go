@@ -0,0 +1,263 @@
+// Package kafkabroker consumes CloudEvents from Apache Kafka topics. Its Subscriber
+// implements the hyperfleet-broker Subscriber interface, so installations without the
+// hyperfleet-broker infrastructure can feed events to the adapter from Kafka.
+package kafkabroker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/brokerutil"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+	"github.com/segmentio/kafka-go"
+)
+
+// BrokerType is the broker type of the Kafka subscriber
+const BrokerType = "kafka"
+
+// Handler retry backoff: a message whose handler fails is retried in place, so the
+// messages after it on its partition wait
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+)
+
+// reader is the part of kafka.Reader the subscriber uses
+type reader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// Subscriber consumes the topics it subscribes to in a consumer group. A message is
+// committed once its handler returns nil; a handler error retries the message with
+// backoff, so delivery is at-least-once and in order per partition. Messages that are not
+// CloudEvents are logged and committed. It is safe for concurrent use.
+type Subscriber struct {
+	log       logger.Logger
+	metrics   *broker.MetricsRecorder
+	dialer    *kafka.Dialer
+	newReader func(kafka.ReaderConfig) reader
+	errors    chan *broker.SubscriberError
+	config    Config
+	groupID   string
+	cancels   []context.CancelFunc
+	readers   []reader
+	wg        sync.WaitGroup
+	mu        sync.RWMutex
+	closed    bool
+}
+
+// NewSubscriber creates a Subscriber consuming in the consumer group of config, or in a
+// group named after subscriptionID. metrics may be nil.
+func NewSubscriber(
+	config Config,
+	subscriptionID string,
+	log logger.Logger,
+	metrics *broker.MetricsRecorder,
+) (*Subscriber, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("kafka brokers are required")
+	}
+	groupID := config.ConsumerGroup
+	if groupID == "" {
+		groupID = subscriptionID
+	}
+	if groupID == "" {
+		return nil, fmt.Errorf("kafka consumer group or subscription ID is required")
+	}
+	dialer, err := newDialer(config)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscriber{
+		log:     log,
+		metrics: metrics,
+		dialer:  dialer,
+		newReader: func(rc kafka.ReaderConfig) reader {
+			return kafka.NewReader(rc)
+		},
+		errors:  make(chan *broker.SubscriberError, broker.ErrorChannelBufferSize),
+		config:  config,
+		groupID: groupID,
+	}, nil
+}
+
+// Subscribe implements broker.Subscriber. It checks that a broker can be reached, then
+// consumes topic in the background until the subscriber is closed or ctx is done.
+func (s *Subscriber) Subscribe(ctx context.Context, topic string, handler broker.HandlerFunc) error {
+	if handler == nil {
+		return fmt.Errorf("handler must be provided")
+	}
+	if err := s.ping(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("subscriber is closed")
+	}
+	r := s.newReader(kafka.ReaderConfig{
+		Brokers:     s.config.Brokers,
+		GroupID:     s.groupID,
+		Topic:       topic,
+		Dialer:      s.dialer,
+		StartOffset: s.config.startOffset(),
+		// Commit synchronously: a message is committed once CommitMessages returns, never
+		// batched in the background where a crash could lose or repeat the commit
+		CommitInterval: 0,
+		// Consume the partitions added to the topic without waiting for a rebalance
+		WatchPartitionChanges: true,
+		ErrorLogger: kafka.LoggerFunc(func(msg string, args ...interface{}) {
+			s.log.Warnf(ctx, "Kafka consumer of topic %s: "+msg, append([]interface{}{topic}, args...)...)
+		}),
+	})
+	consumeCtx, cancel := context.WithCancel(ctx)
+	s.readers = append(s.readers, r)
+	s.cancels = append(s.cancels, cancel)
+
+	s.log.Infof(ctx, "Consuming Kafka topic %s in consumer group %s", topic, s.groupID)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.consume(consumeCtx, r, topic, handler)
+	}()
+	return nil
+}
+
+// ping dials the brokers until one accepts a connection, authenticated when SASL is set
+func (s *Subscriber) ping(ctx context.Context) error {
+	var errs []error
+	for _, address := range s.config.Brokers {
+		conn, err := s.dialer.DialContext(ctx, "tcp", address)
+		if err == nil {
+			_ = conn.Close() //nolint:errcheck // the connection only checked that the broker is reachable
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("no Kafka broker is reachable: %w", errors.Join(errs...))
+}
+
+// consume handles the messages of r until ctx is done or r fails
+func (s *Subscriber) consume(ctx context.Context, r reader, topic string, handler broker.HandlerFunc) {
+	for {
+		msg, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// The reader retries connection errors itself: an error here means it stopped
+			brokerutil.SendError(s.log, "Kafka", s.errors, &broker.SubscriberError{
+				Op:             "receive",
+				Topic:          topic,
+				SubscriptionID: s.groupID,
+				Err:            err,
+				Timestamp:      time.Now(),
+				Fatal:          true,
+			})
+			return
+		}
+		if !s.handle(ctx, msg, topic, handler) {
+			return
+		}
+		if err := r.CommitMessages(ctx, msg); err != nil && ctx.Err() == nil {
+			// The message is delivered again after the next rebalance
+			brokerutil.SendError(s.log, "Kafka", s.errors, &broker.SubscriberError{
+				Op:             "commit",
+				Topic:          topic,
+				SubscriptionID: s.groupID,
+				Err:            err,
+				Timestamp:      time.Now(),
+			})
+		}
+	}
+}
+
+// handle runs handler on msg until it succeeds. It returns false when ctx is done first.
+func (s *Subscriber) handle(ctx context.Context, msg kafka.Message, topic string, handler broker.HandlerFunc) bool {
+	s.recordConsumed(topic)
+	evt, err := messageToEvent(msg)
+	if err != nil {
+		s.recordError(topic, "conversion")
+		s.log.Errorf(ctx, "Skipping Kafka message %s/%d@%d that is not a CloudEvent: %v",
+			msg.Topic, msg.Partition, msg.Offset, err)
+		return true
+	}
+
+	delay := retryBaseDelay
+	for {
+		start := time.Now()
+		err := handler(ctx, evt)
+		s.recordDuration(topic, time.Since(start))
+		if err == nil {
+			return true
+		}
+		s.recordError(topic, "handler")
+		s.log.Errorf(ctx, "Handler failed to process event %s, retrying in %s: %v", evt.ID(), delay, err)
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, retryMaxDelay)
+	}
+}
+
+// Errors implements broker.Subscriber
+func (s *Subscriber) Errors() <-chan *broker.SubscriberError {
+	return s.errors
+}
+
+// BrokerType implements broker.Subscriber
+func (s *Subscriber) BrokerType() string {
+	return BrokerType
+}
+
+// Close implements broker.Subscriber. It stops consuming, waits for the handlers in
+// progress and closes the error channel.
+func (s *Subscriber) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	var errs []error
+	for _, r := range s.readers {
+		if err := r.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	close(s.errors)
+	return errors.Join(errs...)
+}
+
+func (s *Subscriber) recordConsumed(topic string) {
+	if s.metrics != nil {
+		s.metrics.RecordConsumed(topic)
+	}
+}
+
+func (s *Subscriber) recordError(topic, errorType string) {
+	if s.metrics != nil {
+		s.metrics.RecordError(topic, errorType)
+	}
+}
+
+func (s *Subscriber) recordDuration(topic string, d time.Duration) {
+	if s.metrics != nil {
+		s.metrics.RecordDuration(topic, d)
+	}
+}
@@ -0,0 +1,191 @@
+package kafkabroker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// fakeReader serves messages, then fails with err
+type fakeReader struct {
+	err       error
+	messages  []kafka.Message
+	committed []int64
+	mu        sync.Mutex
+}
+
+func (r *fakeReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.messages) == 0 {
+		if r.err != nil {
+			return kafka.Message{}, r.err
+		}
+		r.mu.Unlock()
+		<-ctx.Done()
+		r.mu.Lock()
+		return kafka.Message{}, ctx.Err()
+	}
+	msg := r.messages[0]
+	r.messages = r.messages[1:]
+	return msg, nil
+}
+
+func (r *fakeReader) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgs {
+		r.committed = append(r.committed, msg.Offset)
+	}
+	return nil
+}
+
+func (r *fakeReader) Close() error { return nil }
+
+func binaryMessage(offset int64, id string) kafka.Message {
+	return kafka.Message{
+		Topic:  "clusters",
+		Offset: offset,
+		Value:  []byte(`{"id":"cluster-1"}`),
+		Headers: []kafka.Header{
+			{Key: "ce_specversion", Value: []byte("1.0")},
+			{Key: "ce_id", Value: []byte(id)},
+			{Key: "ce_source", Value: []byte("/hyperfleet")},
+			{Key: "ce_type", Value: []byte("io.hyperfleet.cluster.updated")},
+			{Key: "ce_time", Value: []byte("2026-01-02T03:04:05Z")},
+			{Key: "ce_region", Value: []byte("us-east-1")},
+			{Key: "content-type", Value: []byte("application/json")},
+		},
+	}
+}
+
+func TestMessageToEvent(t *testing.T) {
+	evt, err := messageToEvent(binaryMessage(0, "evt-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "evt-1", evt.ID())
+	assert.Equal(t, "application/json", evt.DataContentType())
+	assert.Equal(t, "us-east-1", evt.Extensions()["region"])
+
+	_, err = messageToEvent(kafka.Message{Headers: []kafka.Header{{Key: "CE_SPECVERSION", Value: []byte("1.0")}}})
+	assert.ErrorContains(t, err, "invalid binary CloudEvent", "header names should be case-insensitive")
+}
+
+func newTestSubscriber() *Subscriber {
+	return &Subscriber{
+		log:     logger.NewTestLogger(),
+		errors:  make(chan *broker.SubscriberError, broker.ErrorChannelBufferSize),
+		groupID: "adapter",
+	}
+}
+
+func TestSubscriber_Consume(t *testing.T) {
+	s := newTestSubscriber()
+	r := &fakeReader{
+		messages: []kafka.Message{
+			binaryMessage(1, "evt-1"), {Offset: 2, Value: []byte("garbage")}, binaryMessage(3, "evt-3"),
+		},
+		err: errors.New("reader closed"),
+	}
+
+	var handled []string
+	failures := 1
+	handler := func(_ context.Context, evt *event.Event) error {
+		handled = append(handled, evt.ID())
+		if evt.ID() == "evt-3" && failures > 0 {
+			failures--
+			return errors.New("transient")
+		}
+		return nil
+	}
+	s.consume(context.Background(), r, "clusters", handler)
+
+	assert.Equal(t, []string{"evt-1", "evt-3", "evt-3"}, handled, "a failed event should be retried")
+	assert.Equal(t, []int64{1, 2, 3}, r.committed, "a message that is not a CloudEvent should be committed")
+
+	subErr := <-s.Errors()
+	assert.Equal(t, "receive", subErr.Op)
+	assert.True(t, subErr.Fatal, "a stopped reader should make the subscriber be recreated")
+}
+
+func TestSubscriber_Close(t *testing.T) {
+	s := newTestSubscriber()
+	s.config = Config{Brokers: []string{"127.0.0.1:1"}}
+	r := &fakeReader{}
+	s.newReader = func(kafka.ReaderConfig) reader { return r }
+	noop := func(context.Context, *event.Event) error { return nil }
+
+	// Subscribe fails fast when no broker can be reached
+	s.dialer = &kafka.Dialer{Timeout: time.Second}
+	err := s.Subscribe(context.Background(), "clusters", noop)
+	require.ErrorContains(t, err, "no Kafka broker is reachable")
+
+	// A listener accepting connections stands in for a broker
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+	s.config.Brokers = []string{listener.Addr().String()}
+	var readerConfig kafka.ReaderConfig
+	s.newReader = func(rc kafka.ReaderConfig) reader {
+		readerConfig = rc
+		return r
+	}
+	require.NoError(t, s.Subscribe(context.Background(), "clusters", noop))
+	assert.Equal(t, "clusters", readerConfig.Topic)
+	assert.Zero(t, readerConfig.CommitInterval, "messages should be committed synchronously")
+	assert.True(t, readerConfig.WatchPartitionChanges)
+
+	require.NoError(t, s.Close())
+	_, open := <-s.Errors()
+	assert.False(t, open, "Close should close the error channel")
+	assert.Error(t, s.Subscribe(context.Background(), "clusters", noop))
+}
+
+func TestNewDialer(t *testing.T) {
+	dir := t.TempDir()
+	passwordPath := filepath.Join(dir, "password")
+	require.NoError(t, os.WriteFile(passwordPath, []byte("s3cret\n"), 0o600))
+
+	dialer, err := newDialer(Config{
+		SASL: &SASLConfig{Mechanism: SASLMechanismPlain, Username: "adapter", PasswordPath: passwordPath},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, plain.Mechanism{Username: "adapter", Password: "s3cret"}, dialer.SASLMechanism)
+	assert.Nil(t, dialer.TLS)
+
+	dialer, err = newDialer(Config{
+		TLS:  &utils.TLSConfig{Enabled: true},
+		SASL: &SASLConfig{Mechanism: SASLMechanismSCRAMSHA512, Username: "adapter", PasswordPath: passwordPath},
+	})
+	require.NoError(t, err)
+	assert.NotNil(t, dialer.TLS)
+	assert.Equal(t, "SCRAM-SHA-512", dialer.SASLMechanism.Name())
+
+	_, err = newDialer(Config{SASL: &SASLConfig{Mechanism: "gssapi", PasswordPath: passwordPath}})
+	assert.ErrorContains(t, err, "unsupported Kafka SASL mechanism")
+	_, err = newDialer(Config{TLS: &utils.TLSConfig{Enabled: true, CAFile: passwordPath}})
+	assert.ErrorContains(t, err, "kafka TLS: CA file")
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
 	apperrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/telemetry"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/version"
 	"github.com/openshift-online/maestro/pkg/api/openapi"
 	"github.com/openshift-online/maestro/pkg/client/cloudevents/grpcsource"
@@ -84,6 +85,11 @@ type Config struct {
 	// If not set, falls back to CAFile for backwards compatibility
 	HTTPCAFile string
 
+	// WrapTransport optionally wraps the HTTP API transport, e.g. to observe requests
+	WrapTransport func(http.RoundTripper) http.RoundTripper
+	// GRPCDialOptions are appended to the gRPC dial options, e.g. client interceptors
+	GRPCDialOptions []grpc.DialOption
+
 	// Insecure disables TLS verification and allows plaintext connections
 	// Use this for local testing without TLS or with self-signed certificates
 	// WARNING: NOT recommended for production
@@ -169,6 +175,12 @@ func NewMaestroClient(ctx context.Context, config *Config, log logger.Logger) (*
 	if transportErr != nil {
 		return nil, apperrors.ConfigurationError("failed to create HTTP transport: %v", transportErr)
 	}
+	var apiTransport http.RoundTripper = httpTransport
+	if config.WrapTransport != nil {
+		apiTransport = config.WrapTransport(httpTransport)
+	}
+	// Trace API calls as children of the calling step
+	apiTransport = telemetry.WrapTransport(apiTransport)
 
 	// Create Maestro HTTP API client (OpenAPI)
 	maestroAPIClient := openapi.NewAPIClient(&openapi.Configuration{
@@ -183,7 +195,7 @@ func NewMaestroClient(ctx context.Context, config *Config, log logger.Logger) (*
 		},
 		OperationServers: map[string]openapi.ServerConfigurations{},
 		HTTPClient: &http.Client{
-			Transport: httpTransport,
+			Transport: apiTransport,
 			Timeout:   httpTimeout,
 		},
 	})
@@ -206,6 +218,8 @@ func NewMaestroClient(ctx context.Context, config *Config, log logger.Logger) (*
 		ServerHealthinessTimeout: &serverHealthinessTimeout,
 	}
 	grpcOptions.Dialer.URL = config.GRPCServerAddr
+	grpcOptions.Dialer.ExtraDialOpts = append(grpcOptions.Dialer.ExtraDialOpts, telemetry.GRPCDialOption())
+	grpcOptions.Dialer.ExtraDialOpts = append(grpcOptions.Dialer.ExtraDialOpts, config.GRPCDialOptions...)
 
 	// Configure TLS if certificates are provided
 	if tlsErr := configureTLS(config, grpcOptions); tlsErr != nil {
@@ -433,6 +447,18 @@ func (c *Client) SourceID() string {
 	return c.config.SourceID
 }
 
+// Ping checks that the Maestro HTTP API is reachable by listing at most one consumer
+func (c *Client) Ping(ctx context.Context) error {
+	_, resp, err := c.maestroAPIClient.DefaultAPI.ApiMaestroV1ConsumersGet(ctx).Size(1).Execute()
+	if resp != nil && resp.Body != nil {
+		_ = resp.Body.Close() //nolint:errcheck // the body was already read by the OpenAPI client
+	}
+	if err != nil {
+		return apperrors.MaestroError("maestro API is not reachable: %v", err)
+	}
+	return nil
+}
+
 // TransportContext carries per-request routing information for the Maestro transport backend.
 // Pass this as the TransportContext (any) in ApplyResource or method parameters.
 type TransportContext struct {
@@ -589,9 +615,11 @@ func (c *Client) GetResource(
 	return nil, apierrors.NewNotFound(gr, name)
 }
 
-// DiscoverResources discovers resources by searching all ManifestWorks for the target consumer.
-// If the GVK is ManifestWork, it matches against the ManifestWork objects themselves.
-// Otherwise, it searches within the workloads of each ManifestWork.
+// DiscoverResources discovers resources in the ManifestWorks of the target consumer.
+// If the GVK is ManifestWork, it matches against the ManifestWork objects themselves:
+// by-name discovery gets the ManifestWork directly and label selector discovery is
+// passed to the Maestro list call. Otherwise, it returns the manifests of that GVK
+// embedded in the workload of each ManifestWork that match the discovery criteria.
 func (c *Client) DiscoverResources(
 	ctx context.Context,
 	gvk schema.GroupVersionKind,
@@ -603,49 +631,85 @@ func (c *Client) DiscoverResources(
 	if transportCtx != nil {
 		consumerName = transportCtx.ConsumerName
 	}
-	if consumerName == "" {
+	if consumerName == "" || discovery == nil {
 		return &unstructured.UnstructuredList{}, nil
 	}
 
 	ctx = logger.WithMaestroConsumer(ctx, consumerName)
 
-	// List all ManifestWorks for this consumer
+	if gvk.Kind == constants.ManifestWorkKind &&
+		gvk.Group == constants.ManifestWorkGroup {
+		return c.discoverManifestWorks(ctx, consumerName, discovery)
+	}
+
+	// List all ManifestWorks for this consumer; the discovery labels apply to the
+	// embedded manifests, not to the ManifestWorks that carry them
 	workList, err := c.ListManifestWorks(ctx, consumerName, "")
 	if err != nil {
 		return nil, err
 	}
 
 	allItems := &unstructured.UnstructuredList{}
-
-	// If discovering ManifestWork objects themselves,
-	// match against the top-level objects
-	if gvk.Kind == constants.ManifestWorkKind &&
-		gvk.Group == constants.ManifestWorkGroup {
-		for i := range workList.Items {
-			workUnstructured, err := workToUnstructured(&workList.Items[i])
-			if err != nil {
-				continue
+	for i := range workList.Items {
+		workUnstructured, err := workToUnstructured(&workList.Items[i])
+		if err != nil {
+			continue
+		}
+		list, err := c.DiscoverManifestInWork(workUnstructured, discovery)
+		if err != nil {
+			continue
+		}
+		for j := range list.Items {
+			if list.Items[j].GroupVersionKind() == gvk {
+				allItems.Items = append(allItems.Items, list.Items[j])
 			}
-			if manifest.MatchesDiscoveryCriteria(workUnstructured, discovery) {
-				allItems.Items = append(allItems.Items, *workUnstructured)
+		}
+	}
+
+	return allItems, nil
+}
+
+// discoverManifestWorks finds the ManifestWorks of a consumer that match the discovery
+// criteria. A ManifestWork that does not exist yields an empty list, not an error.
+func (c *Client) discoverManifestWorks(
+	ctx context.Context,
+	consumerName string,
+	discovery manifest.Discovery,
+) (*unstructured.UnstructuredList, error) {
+	allItems := &unstructured.UnstructuredList{}
+
+	if discovery.IsSingleResource() {
+		work, err := c.GetManifestWork(ctx, consumerName, discovery.GetName())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return allItems, nil
 			}
+			return nil, err
+		}
+		workUnstructured, err := workToUnstructured(work)
+		if err != nil {
+			return nil, apperrors.MaestroError("failed to convert ManifestWork %s/%s to unstructured: %v",
+				consumerName, work.Name, err)
 		}
+		allItems.Items = append(allItems.Items, *workUnstructured)
 		return allItems, nil
 	}
 
-	// Otherwise, search within each ManifestWork's workload
+	workList, err := c.ListManifestWorks(ctx, consumerName, discovery.GetLabelSelector())
+	if err != nil {
+		return nil, err
+	}
+
 	for i := range workList.Items {
 		workUnstructured, err := workToUnstructured(&workList.Items[i])
 		if err != nil {
 			continue
 		}
-		list, err := c.DiscoverManifestInWork(workUnstructured, discovery)
-		if err != nil {
-			continue
+		// Re-check the criteria: the Maestro list API may not apply the label selector
+		if manifest.MatchesDiscoveryCriteria(workUnstructured, discovery) {
+			allItems.Items = append(allItems.Items, *workUnstructured)
 		}
-		allItems.Items = append(allItems.Items, list.Items...)
 	}
-
 	return allItems, nil
 }
 
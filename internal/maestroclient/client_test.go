@@ -1,14 +1,21 @@
 package maestroclient
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workfake "open-cluster-management.io/api/client/work/clientset/versioned/fake"
 	workv1 "open-cluster-management.io/api/work/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -187,3 +194,155 @@ func TestResolveTransportContext_WrongType(t *testing.T) {
 	result := c.resolveTransportContext("not-a-transport-context")
 	assert.Nil(t, result)
 }
+
+// --- DiscoverResources tests ---
+
+// newFakeClient returns a Client backed by an in-memory ManifestWork clientset.
+func newFakeClient(works ...*workv1.ManifestWork) *Client {
+	objs := make([]runtime.Object, 0, len(works))
+	for _, w := range works {
+		objs = append(objs, w)
+	}
+	return &Client{
+		//nolint:staticcheck // NewClientset is not generated for the work API clientset
+		workClient: workfake.NewSimpleClientset(objs...).WorkV1(),
+		log:        logger.NewTestLogger(),
+	}
+}
+
+// configMapJSON returns a labeled ConfigMap manifest as JSON.
+func configMapJSON(t *testing.T, name, app string) []byte {
+	t.Helper()
+	return mustJSON(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+			"labels":    map[string]interface{}{"app": app},
+		},
+	})
+}
+
+func newConsumerWork(
+	name, consumer string,
+	labels map[string]string,
+	manifests ...workv1.Manifest,
+) *workv1.ManifestWork {
+	work := newTestManifestWork(name, manifests)
+	work.Namespace = consumer
+	work.Labels = labels
+	return work
+}
+
+var manifestWorkGVK = schema.GroupVersionKind{
+	Group:   constants.ManifestWorkGroup,
+	Version: constants.ManifestWorkVersion,
+	Kind:    constants.ManifestWorkKind,
+}
+
+func TestDiscoverResources_ManifestWork(t *testing.T) {
+	c := newFakeClient(
+		newConsumerWork("work-a", "cluster-1", map[string]string{"app": "a"}),
+		newConsumerWork("work-b", "cluster-1", map[string]string{"app": "b"}),
+		newConsumerWork("work-a", "cluster-2", map[string]string{"app": "a"}),
+	)
+	target := &TransportContext{ConsumerName: "cluster-1"}
+
+	t.Run("by name", func(t *testing.T) {
+		list, err := c.DiscoverResources(context.Background(), manifestWorkGVK,
+			&manifest.DiscoveryConfig{ByName: "work-b"}, target)
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "work-b", list.Items[0].GetName())
+		assert.Equal(t, constants.ManifestWorkKind, list.Items[0].GetKind())
+	})
+
+	t.Run("by name not found", func(t *testing.T) {
+		list, err := c.DiscoverResources(context.Background(), manifestWorkGVK,
+			&manifest.DiscoveryConfig{ByName: "missing"}, target)
+		require.NoError(t, err)
+		assert.Empty(t, list.Items)
+	})
+
+	t.Run("by label selector", func(t *testing.T) {
+		list, err := c.DiscoverResources(context.Background(), manifestWorkGVK,
+			&manifest.DiscoveryConfig{LabelSelector: "app=a"}, target)
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "work-a", list.Items[0].GetName())
+		assert.Equal(t, "cluster-1", list.Items[0].GetNamespace())
+	})
+
+	t.Run("no consumer", func(t *testing.T) {
+		list, err := c.DiscoverResources(context.Background(), manifestWorkGVK,
+			&manifest.DiscoveryConfig{ByName: "work-a"}, nil)
+		require.NoError(t, err)
+		assert.Empty(t, list.Items)
+	})
+}
+
+func TestDiscoverResources_EmbeddedManifests(t *testing.T) {
+	c := newFakeClient(
+		newConsumerWork("work-a", "cluster-1", nil,
+			workv1.Manifest{RawExtension: runtime.RawExtension{Raw: configMapJSON(t, "cm-a", "web")}},
+			workv1.Manifest{RawExtension: runtime.RawExtension{Raw: bareNamespaceJSON(t, "ns-a")}},
+		),
+		newConsumerWork("work-b", "cluster-1", nil,
+			workv1.Manifest{RawExtension: runtime.RawExtension{Raw: configMapJSON(t, "cm-b", "web")}},
+		),
+	)
+	target := &TransportContext{ConsumerName: "cluster-1"}
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	list, err := c.DiscoverResources(context.Background(), cmGVK,
+		&manifest.DiscoveryConfig{Namespace: "default", LabelSelector: "app=web"}, target)
+	require.NoError(t, err)
+	names := []string{}
+	for _, item := range list.Items {
+		names = append(names, item.GetName())
+	}
+	assert.ElementsMatch(t, []string{"cm-a", "cm-b"}, names)
+
+	// Manifests of other kinds are not returned even when the criteria match
+	list, err = c.DiscoverResources(context.Background(), schema.GroupVersionKind{Version: "v1", Kind: "Namespace"},
+		&manifest.DiscoveryConfig{ByName: "cm-a"}, target)
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+}
+
+func TestGetResource_EmbeddedManifest(t *testing.T) {
+	c := newFakeClient(newConsumerWork("work-a", "cluster-1", nil,
+		workv1.Manifest{RawExtension: runtime.RawExtension{Raw: configMapJSON(t, "cm-a", "web")}},
+	))
+	target := &TransportContext{ConsumerName: "cluster-1"}
+	cmGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+
+	obj, err := c.GetResource(context.Background(), cmGVK, "default", "cm-a", target)
+	require.NoError(t, err)
+	assert.Equal(t, "cm-a", obj.GetName())
+
+	_, err = c.GetResource(context.Background(), cmGVK, "default", "missing", target)
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+// --- DeleteResource tests ---
+
+func TestDeleteResource(t *testing.T) {
+	work := newConsumerWork("work-a", "cluster-1", nil)
+	c := newFakeClient(work)
+	target := &TransportContext{ConsumerName: "cluster-1"}
+	opts := &transportclient.DeleteOptions{PropagationPolicy: "Background"}
+
+	require.NoError(t, c.DeleteResource(context.Background(), manifestWorkGVK, "", "work-a", opts, target))
+
+	_, err := c.GetManifestWork(context.Background(), "cluster-1", "work-a")
+	assert.True(t, apierrors.IsNotFound(err), "ManifestWork should be deleted")
+
+	// Deleting again is a no-op
+	require.NoError(t, c.DeleteResource(context.Background(), manifestWorkGVK, "", "work-a", opts, target))
+
+	// The consumer is required to address the ManifestWork
+	err = c.DeleteResource(context.Background(), manifestWorkGVK, "", "work-a", opts, nil)
+	assert.ErrorContains(t, err, "ConsumerName is required")
+}
@@ -0,0 +1,150 @@
+package maestroclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/maestrofake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// newFakeMaestroClient starts a fake Maestro server and connects a client to it
+func newFakeMaestroClient(t *testing.T) (*Client, *maestrofake.Server) {
+	t.Helper()
+	srv := maestrofake.New(maestrofake.Options{HeartbeatInterval: 100 * time.Millisecond})
+	require.NoError(t, srv.Start())
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	client, err := NewMaestroClient(ctx, &Config{
+		MaestroServerAddr: srv.HTTPURL(),
+		GRPCServerAddr:    srv.GRPCAddr(),
+		SourceID:          "fake-test-source",
+		Insecure:          true,
+	}, logger.NewTestLogger())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client, srv
+}
+
+// fakeWork builds a ManifestWork carrying a namespace at generation
+func fakeWork(t *testing.T, name string, generation int64, labels map[string]string) *workv1.ManifestWork {
+	t.Helper()
+	gen := fmt.Sprint(generation)
+	ns := mustJSON(t, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata": map[string]interface{}{
+			"name":        name + "-ns",
+			"annotations": map[string]interface{}{constants.AnnotationGeneration: gen},
+		},
+	})
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Labels:      labels,
+			Annotations: map[string]string{constants.AnnotationGeneration: gen},
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: ns}}},
+			},
+		},
+	}
+}
+
+func TestClient_FakeMaestroServer(t *testing.T) {
+	client, srv := newFakeMaestroClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	const consumer = "cluster-1"
+
+	require.NoError(t, client.Ping(ctx))
+
+	t.Run("apply creates then updates", func(t *testing.T) {
+		result, err := client.ApplyManifestWork(ctx, consumer, fakeWork(t, "work-a", 1, map[string]string{"app": "a"}))
+		require.NoError(t, err)
+		assert.Equal(t, manifest.OperationCreate, result.Operation)
+
+		result, err = client.ApplyManifestWork(ctx, consumer, fakeWork(t, "work-a", 1, map[string]string{"app": "a"}))
+		require.NoError(t, err)
+		assert.Equal(t, manifest.OperationSkip, result.Operation)
+
+		result, err = client.ApplyManifestWork(ctx, consumer, fakeWork(t, "work-a", 2, map[string]string{"app": "a"}))
+		require.NoError(t, err)
+		assert.Equal(t, manifest.OperationUpdate, result.Operation)
+
+		stored, ok := srv.Work(consumer, "work-a")
+		require.True(t, ok)
+		assert.Equal(t, "2", stored.Annotations[constants.AnnotationGeneration])
+	})
+
+	t.Run("get returns the work with its status", func(t *testing.T) {
+		work, err := client.GetManifestWork(ctx, consumer, "work-a")
+		require.NoError(t, err)
+		assert.Equal(t, "2", work.Annotations[constants.AnnotationGeneration])
+		assert.True(t, hasCondition(work, workv1.WorkApplied))
+
+		_, err = client.GetManifestWork(ctx, consumer, "missing")
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("list filters by label selector", func(t *testing.T) {
+		_, err := client.CreateManifestWork(ctx, consumer, fakeWork(t, "work-b", 1, map[string]string{"app": "b"}))
+		require.NoError(t, err)
+
+		list, err := client.ListManifestWorks(ctx, consumer, "app=b")
+		require.NoError(t, err)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, "work-b", list.Items[0].Name)
+
+		list, err = client.ListManifestWorks(ctx, consumer, "app in (a,b)")
+		require.NoError(t, err)
+		assert.Len(t, list.Items, 2)
+
+		list, err = client.ListManifestWorks(ctx, "cluster-2", "")
+		require.NoError(t, err)
+		assert.Empty(t, list.Items)
+	})
+
+	t.Run("delete removes the work", func(t *testing.T) {
+		require.NoError(t, client.DeleteManifestWork(ctx, consumer, "work-b"))
+		_, ok := srv.Work(consumer, "work-b")
+		assert.False(t, ok)
+		require.NoError(t, client.DeleteManifestWork(ctx, consumer, "work-b"), "deleting twice is not an error")
+	})
+
+	t.Run("publish failures are reported", func(t *testing.T) {
+		srv.SetPublishError(errors.New("maestro is down"))
+		_, err := client.CreateManifestWork(ctx, consumer, fakeWork(t, "work-c", 1, nil))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "maestro is down")
+		_, ok := srv.Work(consumer, "work-c")
+		assert.False(t, ok)
+
+		srv.SetPublishError(nil)
+		_, err = client.CreateManifestWork(ctx, consumer, fakeWork(t, "work-c", 1, nil))
+		require.NoError(t, err)
+	})
+}
+
+func hasCondition(work *workv1.ManifestWork, conditionType string) bool {
+	for _, c := range work.Status.Conditions {
+		if c.Type == conditionType && c.Status == metav1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
 	apperrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/telemetry"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -170,7 +171,8 @@ func (c *Client) PatchManifestWork(
 	return patched, nil
 }
 
-// DeleteManifestWork deletes a ManifestWork from a target cluster
+// DeleteManifestWork deletes a ManifestWork from a target cluster.
+// A ManifestWork that does not exist is treated as already deleted.
 func (c *Client) DeleteManifestWork(
 	ctx context.Context,
 	consumerName string,
@@ -179,7 +181,9 @@ func (c *Client) DeleteManifestWork(
 	ctx = logger.WithMaestroConsumer(ctx, consumerName)
 	ctx = logger.WithLogField(ctx, "manifestwork", workName)
 
-	err := c.workClient.ManifestWorks(consumerName).Delete(ctx, workName, metav1.DeleteOptions{})
+	err := c.retryOnTransientGRPC(ctx, func() error {
+		return c.workClient.ManifestWorks(consumerName).Delete(ctx, workName, metav1.DeleteOptions{})
+	})
 	if err != nil {
 		// Ignore not found errors (already deleted)
 		if apierrors.IsNotFound(err) {
@@ -273,6 +277,11 @@ func (c *Client) ApplyManifestWork(
 		"reason":    decision.Reason,
 	}).Debug(ctx, "Apply operation determined")
 
+	// Link the work to the trace of this execution for the systems acting on it
+	if decision.Operation != manifest.OperationSkip {
+		manifestWork.Annotations = telemetry.InjectTraceContextIntoAnnotations(ctx, manifestWork.Annotations)
+	}
+
 	// Execute operation based on comparison result
 	switch decision.Operation {
 	case manifest.OperationCreate:
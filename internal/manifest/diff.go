@@ -0,0 +1,197 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RedactedValue replaces the values of Secret data in a diff
+const RedactedValue = "<redacted>"
+
+// FieldChange is a field that an update changes
+type FieldChange struct {
+	// Old is the existing value, nil when the update adds the field
+	Old interface{} `json:"old,omitempty"`
+	// New is the desired value
+	New interface{} `json:"new"`
+	// Path is the field path, e.g. spec.replicas or metadata.labels["app.kubernetes.io/name"]
+	Path string `json:"path"`
+}
+
+// String formats the change as "path: old -> new", with values in JSON
+func (c FieldChange) String() string {
+	if c.Old == nil {
+		return fmt.Sprintf("%s: (added) -> %s", c.Path, formatDiffValue(c.New))
+	}
+	return fmt.Sprintf("%s: %s -> %s", c.Path, formatDiffValue(c.Old), formatDiffValue(c.New))
+}
+
+// FormatChanges formats changes on one line, separated by "; "
+func FormatChanges(changes []FieldChange) string {
+	parts := make([]string, len(changes))
+	for i, c := range changes {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// diffIgnoredPaths are fields owned by the server, which never count as changes, and the
+// config fingerprint annotation, which changes with every config revision
+var diffIgnoredPaths = map[string]bool{
+	"status":                     true,
+	"metadata.resourceVersion":   true,
+	"metadata.uid":               true,
+	"metadata.generation":        true,
+	"metadata.creationTimestamp": true,
+	"metadata.managedFields":     true,
+	`metadata.annotations["` + constants.AnnotationConfigFingerprint + `"]`: true,
+}
+
+// secretDataFields are the fields of a Secret whose values are redacted
+var secretDataFields = map[string]bool{
+	"data":       true,
+	"stringData": true,
+}
+
+// plainPathSegment matches the map keys written without brackets in a field path
+var plainPathSegment = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Diff returns the fields of desired whose values differ from existing, sorted by path.
+//
+// Only the fields desired sets are compared: fields set only on existing, such as status,
+// server defaults and metadata like resourceVersion, are not reported, since they cannot be
+// told apart from fields the manifest stopped setting. Lists of the same length are compared
+// item by item; otherwise the whole list is one change. The values of Secret data and
+// stringData are replaced with RedactedValue.
+func Diff(existing, desired *unstructured.Unstructured) []FieldChange {
+	if existing == nil || desired == nil {
+		return nil
+	}
+	var changes []FieldChange
+	diffMaps(nil, existing.Object, desired.Object, &changes)
+
+	if desired.GroupVersionKind().Group == "" && desired.GetKind() == "Secret" {
+		for i := range changes {
+			if secretDataFields[topLevelField(changes[i].Path)] {
+				changes[i].Old = redact(changes[i].Old)
+				changes[i].New = redact(changes[i].New)
+			}
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+func diffMaps(path []string, existing, desired map[string]interface{}, changes *[]FieldChange) {
+	for key, want := range desired {
+		keyPath := append(append([]string(nil), path...), pathSegment(key, len(path) == 0))
+		if diffIgnoredPaths[strings.Join(keyPath, "")] {
+			continue
+		}
+		have, exists := existing[key]
+		if !exists {
+			*changes = append(*changes, FieldChange{Path: strings.Join(keyPath, ""), New: want})
+			continue
+		}
+		diffValues(keyPath, have, want, changes)
+	}
+}
+
+func diffValues(path []string, have, want interface{}, changes *[]FieldChange) {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		if h, ok := have.(map[string]interface{}); ok {
+			diffMaps(path, h, w, changes)
+			return
+		}
+	case []interface{}:
+		if h, ok := have.([]interface{}); ok && len(h) == len(w) {
+			for i := range w {
+				diffValues(append(append([]string(nil), path...), fmt.Sprintf("[%d]", i)), h[i], w[i], changes)
+			}
+			return
+		}
+	default:
+		if equalScalars(have, want) {
+			return
+		}
+	}
+	if reflect.DeepEqual(have, want) {
+		return
+	}
+	*changes = append(*changes, FieldChange{Path: strings.Join(path, ""), Old: have, New: want})
+}
+
+// pathSegment returns the path segment of a map key: ".key", or ["key"] for keys with
+// characters other than letters, digits, '-' and '_'
+func pathSegment(key string, first bool) string {
+	if !plainPathSegment.MatchString(key) {
+		return fmt.Sprintf("[%q]", key)
+	}
+	if first {
+		return key
+	}
+	return "." + key
+}
+
+// equalScalars compares scalar values, treating numbers of different Go types as equal when
+// their values are: manifests decode numbers as float64, the API server as int64
+func equalScalars(a, b interface{}) bool {
+	af, aNum := toFloat(a)
+	bf, bNum := toFloat(b)
+	if aNum && bNum {
+		return af == bf
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// topLevelField returns the first segment of a field path
+func topLevelField(path string) string {
+	if i := strings.IndexAny(path, ".["); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+func redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return RedactedValue
+}
+
+func formatDiffValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
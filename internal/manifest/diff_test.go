@@ -0,0 +1,102 @@
+package manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDiff(t *testing.T) {
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":            "agent",
+			"resourceVersion": "42",
+			"annotations": map[string]interface{}{
+				"hyperfleet.io/generation":         "1",
+				"hyperfleet.io/config-fingerprint": "aaaaaaaaaaaa",
+			},
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(1),
+			"paused":   false,
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "agent", "image": "agent:v1", "imagePullPolicy": "Always"},
+				},
+			}},
+		},
+		"status": map[string]interface{}{"readyReplicas": int64(1)},
+	}}
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name": "agent",
+			"annotations": map[string]interface{}{
+				"hyperfleet.io/generation":         "2",
+				"hyperfleet.io/config-fingerprint": "bbbbbbbbbbbb",
+			},
+			"labels": map[string]interface{}{"app": "agent"},
+		},
+		"spec": map[string]interface{}{
+			"replicas": float64(1),
+			"paused":   false,
+			"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{
+					map[string]interface{}{"name": "agent", "image": "agent:v2"},
+				},
+			}},
+		},
+	}}
+
+	changes := Diff(existing, desired)
+	assert.Equal(t, []FieldChange{
+		{Path: `metadata.annotations["hyperfleet.io/generation"]`, Old: "1", New: "2"},
+		{Path: "metadata.labels", New: map[string]interface{}{"app": "agent"}},
+		{Path: "spec.template.spec.containers[0].image", Old: "agent:v1", New: "agent:v2"},
+	}, changes, "numbers compare by value and fields only set on the existing object are ignored")
+
+	assert.Equal(t,
+		`spec.template.spec.containers[0].image: "agent:v1" -> "agent:v2"; metadata.labels: (added) -> {"app":"agent"}`,
+		FormatChanges([]FieldChange{changes[2], changes[1]}))
+	assert.Empty(t, Diff(existing, existing))
+	assert.Nil(t, Diff(nil, desired))
+}
+
+func TestDiff_ListLengthChange(t *testing.T) {
+	existing := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"args": []interface{}{"--a"}},
+	}}
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"args": []interface{}{"--a", "--b"}},
+	}}
+	assert.Equal(t, []FieldChange{
+		{Path: "spec.args", Old: []interface{}{"--a"}, New: []interface{}{"--a", "--b"}},
+	}, Diff(existing, desired))
+}
+
+func TestDiff_RedactsSecretData(t *testing.T) {
+	secret := func(data, stringData map[string]interface{}, label string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata": map[string]interface{}{
+				"name":   "creds",
+				"labels": map[string]interface{}{"tier": label},
+			},
+			"data":       data,
+			"stringData": stringData,
+		}}
+	}
+	existing := secret(map[string]interface{}{"tls.crt": "b2xk"}, map[string]interface{}{}, "a")
+	desired := secret(map[string]interface{}{"tls.crt": "bmV3"}, map[string]interface{}{"token": "s3cr3t"}, "b")
+
+	assert.Equal(t, []FieldChange{
+		{Path: `data["tls.crt"]`, Old: RedactedValue, New: RedactedValue},
+		{Path: "metadata.labels.tier", Old: "a", New: "b"},
+		{Path: "stringData.token", New: RedactedValue},
+	}, Diff(existing, desired))
+}
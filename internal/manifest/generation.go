@@ -505,9 +505,9 @@ func MatchesDiscoveryCriteria(obj *unstructured.Unstructured, discovery Discover
 		return false
 	}
 
-	// Check name if single resource discovery
+	// Check name if single resource discovery, and the labels when a selector is set too
 	if discovery.IsSingleResource() {
-		return obj.GetName() == discovery.GetName()
+		return obj.GetName() == discovery.GetName() && MatchesLabels(obj, discovery.GetLabelSelector())
 	}
 
 	// Check label selector
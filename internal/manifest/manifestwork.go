@@ -5,7 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	"sigs.k8s.io/yaml"
 )
@@ -47,3 +51,211 @@ func LoadManifestWork(path string) (*workv1.ManifestWork, error) {
 
 	return work, nil
 }
+
+// RemainderTier is the name of the tier holding the manifests whose kind is in no tier
+const RemainderTier = "workloads"
+
+// KindTier groups the manifest kinds that are applied together
+type KindTier struct {
+	Name  string
+	Kinds []string
+}
+
+// DefaultKindTiers orders namespaces first, then CRDs, then RBAC, so the manifests that
+// depend on them (the RemainderTier) are applied last.
+var DefaultKindTiers = []KindTier{
+	{Name: "namespaces", Kinds: []string{"Namespace"}},
+	{Name: "crds", Kinds: []string{"CustomResourceDefinition"}},
+	{Name: "rbac", Kinds: []string{
+		"ServiceAccount", "ClusterRole", "Role", "ClusterRoleBinding", "RoleBinding",
+	}},
+}
+
+// tierRanks maps each manifest kind to the index of its tier. Kinds in no tier rank
+// len(tiers), the RemainderTier.
+func tierRanks(tiers []KindTier) map[string]int {
+	ranks := make(map[string]int)
+	for i, tier := range tiers {
+		for _, kind := range tier.Kinds {
+			if _, ok := ranks[kind]; !ok {
+				ranks[kind] = i
+			}
+		}
+	}
+	return ranks
+}
+
+// manifestKind returns the kind of a ManifestWork manifest, or "" if it cannot be read
+func manifestKind(m workv1.Manifest) string {
+	var meta struct {
+		Kind string `json:"kind"`
+	}
+	if err := json.Unmarshal(m.Raw, &meta); err != nil {
+		return ""
+	}
+	return meta.Kind
+}
+
+// groupManifests splits manifests by tier, keeping their relative order within a tier.
+// The result has len(tiers)+1 groups, the last one being the RemainderTier.
+func groupManifests(manifests []workv1.Manifest, tiers []KindTier) [][]workv1.Manifest {
+	ranks := tierRanks(tiers)
+	groups := make([][]workv1.Manifest, len(tiers)+1)
+	for _, m := range manifests {
+		rank, ok := ranks[manifestKind(m)]
+		if !ok {
+			rank = len(tiers)
+		}
+		groups[rank] = append(groups[rank], m)
+	}
+	return groups
+}
+
+// OrderManifestWork sorts the manifests of work by tier, in place. The sort is stable:
+// manifests of the same tier keep the order they were declared in.
+func OrderManifestWork(work *workv1.ManifestWork, tiers []KindTier) {
+	groups := groupManifests(work.Spec.Workload.Manifests, tiers)
+	ordered := make([]workv1.Manifest, 0, len(work.Spec.Workload.Manifests))
+	for _, group := range groups {
+		ordered = append(ordered, group...)
+	}
+	work.Spec.Workload.Manifests = ordered
+}
+
+// SplitWorkName is the name of the ManifestWork that carries one tier of a split work
+func SplitWorkName(workName, tier string) string {
+	return workName + "-" + tier
+}
+
+// SplitManifestWork splits work into one ManifestWork per non-empty tier, in tier order.
+// The last work keeps the name of work, so discovery and status feedback of the
+// resource keep pointing at it; the others are named SplitWorkName(work.Name, tier).
+// Every work copies the metadata and spec of work except its manifests, and each
+// work but the first is annotated with the name of the work it depends on.
+func SplitManifestWork(work *workv1.ManifestWork, tiers []KindTier) []*workv1.ManifestWork {
+	groups := groupManifests(work.Spec.Workload.Manifests, tiers)
+
+	var works []*workv1.ManifestWork
+	for i, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		tierName := RemainderTier
+		if i < len(tiers) {
+			tierName = tiers[i].Name
+		}
+		split := work.DeepCopy()
+		split.Name = SplitWorkName(work.Name, tierName)
+		split.Spec.Workload.Manifests = group
+		works = append(works, split)
+	}
+	if len(works) == 0 {
+		return []*workv1.ManifestWork{work.DeepCopy()}
+	}
+
+	works[len(works)-1].Name = work.Name
+	for i := 1; i < len(works); i++ {
+		if works[i].Annotations == nil {
+			works[i].Annotations = make(map[string]string)
+		}
+		works[i].Annotations[constants.AnnotationDependsOn] = works[i-1].Name
+	}
+	return works
+}
+
+// SplitWorkNames returns the names of every ManifestWork SplitManifestWork can create
+// for a work named workName, except workName itself.
+func SplitWorkNames(workName string, tiers []KindTier) []string {
+	names := make([]string, 0, len(tiers)+1)
+	for _, tier := range tiers {
+		names = append(names, SplitWorkName(workName, tier.Name))
+	}
+	return append(names, SplitWorkName(workName, RemainderTier))
+}
+
+// IsManifestWorkApplied reports whether the work agent reported the work as applied
+func IsManifestWorkApplied(work *workv1.ManifestWork) bool {
+	return meta.IsStatusConditionTrue(work.Status.Conditions, workv1.WorkApplied)
+}
+
+// DefaultWorkConditions are the condition types a ManifestWork is waited for by default
+var DefaultWorkConditions = []string{workv1.WorkApplied, workv1.WorkAvailable}
+
+// UnmetWorkConditions describes the condition types of conditionTypes that are not True on
+// work, e.g. "Available=False (ResourcesNotAvailable: 1 of 2 resources are not available)"
+// or "Applied is not reported". It returns nil when every condition is True.
+func UnmetWorkConditions(work *workv1.ManifestWork, conditionTypes []string) []string {
+	var unmet []string
+	for _, condType := range conditionTypes {
+		cond := meta.FindStatusCondition(work.Status.Conditions, condType)
+		switch {
+		case cond == nil:
+			unmet = append(unmet, condType+" is not reported")
+		case cond.Status != metav1.ConditionTrue:
+			detail := cond.Reason
+			if cond.Message != "" {
+				detail = strings.TrimPrefix(cond.Reason+": "+cond.Message, ": ")
+			}
+			description := fmt.Sprintf("%s=%s", condType, cond.Status)
+			if detail != "" {
+				description += " (" + detail + ")"
+			}
+			unmet = append(unmet, description)
+		}
+	}
+	return unmet
+}
+
+// WorkStatusValues returns the status the work agent reported on work as plain values:
+// "conditions" maps the work's condition types to their status, and "manifests" lists,
+// for each manifest, its kind, name and namespace, its condition statuses by type and
+// its status feedback values by name.
+func WorkStatusValues(work *workv1.ManifestWork) map[string]interface{} {
+	manifests := make([]interface{}, 0, len(work.Status.ResourceStatus.Manifests))
+	for _, m := range work.Status.ResourceStatus.Manifests {
+		values := make(map[string]interface{}, len(m.StatusFeedbacks.Values))
+		for _, v := range m.StatusFeedbacks.Values {
+			values[v.Name] = feedbackFieldValue(v.Value)
+		}
+		manifests = append(manifests, map[string]interface{}{
+			"kind":       m.ResourceMeta.Kind,
+			"name":       m.ResourceMeta.Name,
+			"namespace":  m.ResourceMeta.Namespace,
+			"conditions": conditionStatuses(m.Conditions),
+			"values":     values,
+		})
+	}
+	return map[string]interface{}{
+		"conditions": conditionStatuses(work.Status.Conditions),
+		"manifests":  manifests,
+	}
+}
+
+func conditionStatuses(conditions []metav1.Condition) map[string]interface{} {
+	statuses := make(map[string]interface{}, len(conditions))
+	for _, c := range conditions {
+		statuses[c.Type] = string(c.Status)
+	}
+	return statuses
+}
+
+// feedbackFieldValue returns the value a status feedback field carries; JSON raw values
+// are decoded, and kept as a string when they are not valid JSON
+func feedbackFieldValue(v workv1.FieldValue) interface{} {
+	switch {
+	case v.Integer != nil:
+		return *v.Integer
+	case v.String != nil:
+		return *v.String
+	case v.Boolean != nil:
+		return *v.Boolean
+	case v.JsonRaw != nil:
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(*v.JsonRaw), &decoded); err != nil {
+			return *v.JsonRaw
+		}
+		return decoded
+	default:
+		return nil
+	}
+}
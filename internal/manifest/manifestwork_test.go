@@ -1,9 +1,16 @@
 package manifest
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
 )
 
 func TestParseManifestWork_JSON(t *testing.T) {
@@ -156,3 +163,140 @@ func TestLoadManifestWork_FileNotFound(t *testing.T) {
 		t.Error("expected error for missing file, got nil")
 	}
 }
+
+func newOrderingWork(kinds ...string) *workv1.ManifestWork {
+	work := &workv1.ManifestWork{}
+	work.Name = "cluster-1-work"
+	work.Annotations = map[string]string{"hyperfleet.io/generation": "1"}
+	for i, kind := range kinds {
+		raw := fmt.Sprintf(`{"apiVersion":"v1","kind":%q,"metadata":{"name":"m%d"}}`, kind, i)
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests,
+			workv1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(raw)}})
+	}
+	return work
+}
+
+func workKinds(work *workv1.ManifestWork) []string {
+	kinds := make([]string, 0, len(work.Spec.Workload.Manifests))
+	for _, m := range work.Spec.Workload.Manifests {
+		kinds = append(kinds, manifestKind(m))
+	}
+	return kinds
+}
+
+func TestOrderManifestWork(t *testing.T) {
+	work := newOrderingWork(
+		"Deployment", "RoleBinding", "Namespace", "ConfigMap", "CustomResourceDefinition", "ServiceAccount")
+	OrderManifestWork(work, DefaultKindTiers)
+
+	want := []string{"Namespace", "CustomResourceDefinition", "RoleBinding", "ServiceAccount", "Deployment", "ConfigMap"}
+	if got := workKinds(work); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected order %v, got %v", want, got)
+	}
+
+	custom := newOrderingWork("Deployment", "ConfigMap", "Namespace")
+	OrderManifestWork(custom, []KindTier{{Name: "config", Kinds: []string{"ConfigMap"}}})
+	want = []string{"ConfigMap", "Deployment", "Namespace"}
+	if got := workKinds(custom); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected custom order %v, got %v", want, got)
+	}
+}
+
+func TestSplitManifestWork(t *testing.T) {
+	work := newOrderingWork("Deployment", "Namespace", "ServiceAccount", "ConfigMap")
+	works := SplitManifestWork(work, DefaultKindTiers)
+
+	if len(works) != 3 {
+		t.Fatalf("expected 3 works (empty crds tier skipped), got %d", len(works))
+	}
+	wantNames := []string{"cluster-1-work-namespaces", "cluster-1-work-rbac", "cluster-1-work"}
+	wantKinds := [][]string{{"Namespace"}, {"ServiceAccount"}, {"Deployment", "ConfigMap"}}
+	for i, w := range works {
+		if w.Name != wantNames[i] {
+			t.Errorf("work %d: expected name %q, got %q", i, wantNames[i], w.Name)
+		}
+		if got := workKinds(w); !reflect.DeepEqual(got, wantKinds[i]) {
+			t.Errorf("work %d: expected kinds %v, got %v", i, wantKinds[i], got)
+		}
+		if w.Annotations["hyperfleet.io/generation"] != "1" {
+			t.Errorf("work %d: generation annotation not copied", i)
+		}
+	}
+	if _, ok := works[0].Annotations[constants.AnnotationDependsOn]; ok {
+		t.Error("first work should not depend on another work")
+	}
+	if got := works[2].Annotations[constants.AnnotationDependsOn]; got != "cluster-1-work-rbac" {
+		t.Errorf("expected last work to depend on cluster-1-work-rbac, got %q", got)
+	}
+	if len(work.Spec.Workload.Manifests) != 4 {
+		t.Error("the original work should not be modified")
+	}
+}
+
+func TestSplitManifestWork_LastTierKeepsName(t *testing.T) {
+	works := SplitManifestWork(newOrderingWork("ServiceAccount", "Namespace"), DefaultKindTiers)
+	if len(works) != 2 {
+		t.Fatalf("expected 2 works, got %d", len(works))
+	}
+	if works[0].Name != "cluster-1-work-namespaces" || works[1].Name != "cluster-1-work" {
+		t.Errorf("unexpected names %q, %q", works[0].Name, works[1].Name)
+	}
+
+	names := SplitWorkNames("cluster-1-work", DefaultKindTiers)
+	want := []string{"cluster-1-work-namespaces", "cluster-1-work-crds", "cluster-1-work-rbac", "cluster-1-work-workloads"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("expected split names %v, got %v", want, names)
+	}
+}
+
+func TestUnmetWorkConditions(t *testing.T) {
+	work := &workv1.ManifestWork{Status: workv1.ManifestWorkStatus{Conditions: []metav1.Condition{
+		{Type: workv1.WorkApplied, Status: metav1.ConditionTrue},
+		{Type: workv1.WorkAvailable, Status: metav1.ConditionFalse, Reason: "ResourcesNotAvailable",
+			Message: "1 of 2 resources are not available"},
+	}}}
+
+	got := UnmetWorkConditions(work, []string{workv1.WorkApplied, workv1.WorkAvailable, workv1.WorkDegraded})
+	want := []string{
+		"Available=False (ResourcesNotAvailable: 1 of 2 resources are not available)",
+		"Degraded is not reported",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmetWorkConditions() = %v, want %v", got, want)
+	}
+	if got := UnmetWorkConditions(work, []string{workv1.WorkApplied}); got != nil {
+		t.Errorf("UnmetWorkConditions() = %v, want nil", got)
+	}
+}
+
+func TestWorkStatusValues(t *testing.T) {
+	replicas := int64(3)
+	raw := `{"ready":true}`
+	work := &workv1.ManifestWork{Status: workv1.ManifestWorkStatus{
+		Conditions: []metav1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionTrue}},
+		ResourceStatus: workv1.ManifestResourceStatus{Manifests: []workv1.ManifestCondition{{
+			ResourceMeta: workv1.ManifestResourceMeta{Kind: "Deployment", Name: "agent", Namespace: "c1"},
+			StatusFeedbacks: workv1.StatusFeedbackResult{Values: []workv1.FeedbackValue{
+				{Name: "replicas", Value: workv1.FieldValue{Type: workv1.Integer, Integer: &replicas}},
+				{Name: "state", Value: workv1.FieldValue{Type: workv1.JsonRaw, JsonRaw: &raw}},
+			}},
+		}}},
+	}}
+
+	want := map[string]interface{}{
+		"conditions": map[string]interface{}{"Applied": "True"},
+		"manifests": []interface{}{map[string]interface{}{
+			"kind":       "Deployment",
+			"name":       "agent",
+			"namespace":  "c1",
+			"conditions": map[string]interface{}{},
+			"values": map[string]interface{}{
+				"replicas": int64(3),
+				"state":    map[string]interface{}{"ready": true},
+			},
+		}},
+	}
+	if got := WorkStatusValues(work); !reflect.DeepEqual(got, want) {
+		t.Errorf("WorkStatusValues() = %v, want %v", got, want)
+	}
+}
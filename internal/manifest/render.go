@@ -3,40 +3,128 @@ package manifest
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
 	"gopkg.in/yaml.v3"
 )
 
+// embedActionRegex matches a string value that is a single template action using
+// toYaml or toJson, e.g. "{{ .nodepoolSpec | toYaml }}" or "{{ toJson .labels }}".
+var embedActionRegex = regexp.MustCompile(`^\{\{-?\s*([^{}]*\b(toYaml|toJson)\b[^{}]*?)\s*-?\}\}$`)
+
+// embedPlaceholderRegex matches the placeholders substituted for embed actions
+var embedPlaceholderRegex = regexp.MustCompile(`__hyperfleet_embed_(\d+)__`)
+
+// embedAction is a toYaml/toJson action extracted from a map manifest
+type embedAction struct {
+	pipeline string
+	function string
+}
+
 // ToYAMLString converts a manifest value to a YAML string.
 // String values are returned as-is. Map values are marshaled to YAML.
+//
+// In map manifests, a string value that is a single toYaml or toJson action is
+// embedded as structured data rather than as a quoted string: toYaml output is
+// indented to the value's nesting level and toJson output is written as YAML flow.
 func ToYAMLString(manifest interface{}) (string, error) {
-	switch m := manifest.(type) {
+	var m map[string]interface{}
+	switch v := manifest.(type) {
 	case string:
-		return m, nil
+		return v, nil
 	case map[string]interface{}:
-		data, err := yaml.Marshal(m)
-		if err != nil {
-			return "", fmt.Errorf("failed to marshal manifest to YAML: %w", err)
-		}
+		m = v
+	case map[interface{}]interface{}:
+		m = utils.ConvertToStringKeyMap(v)
+	default:
+		return "", fmt.Errorf("unsupported manifest type: %T", manifest)
+	}
+
+	var actions []embedAction
+	data, err := yaml.Marshal(extractEmbedActions(m, &actions))
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifest to YAML: %w", err)
+	}
+	if len(actions) == 0 {
 		return string(data), nil
+	}
+	return restoreEmbedActions(string(data), actions), nil
+}
+
+// extractEmbedActions returns a copy of v with embed actions replaced by placeholders
+func extractEmbedActions(v interface{}, actions *[]embedAction) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = extractEmbedActions(item, actions)
+		}
+		return out
 	case map[interface{}]interface{}:
-		converted := utils.ConvertToStringKeyMap(m)
-		data, err := yaml.Marshal(converted)
-		if err != nil {
-			return "", fmt.Errorf("failed to marshal manifest to YAML: %w", err)
+		return extractEmbedActions(utils.ConvertToStringKeyMap(val), actions)
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = extractEmbedActions(item, actions)
 		}
-		return string(data), nil
+		return out
+	case string:
+		match := embedActionRegex.FindStringSubmatch(strings.TrimSpace(val))
+		if match == nil {
+			return val
+		}
+		*actions = append(*actions, embedAction{pipeline: match[1], function: match[2]})
+		return fmt.Sprintf("__hyperfleet_embed_%d__", len(*actions)-1)
 	default:
-		return "", fmt.Errorf("unsupported manifest type: %T", manifest)
+		return v
+	}
+}
+
+// restoreEmbedActions replaces placeholders with their actions. toYaml actions are
+// piped through nindent so the block nests under the key or list item holding it.
+func restoreEmbedActions(yamlStr string, actions []embedAction) string {
+	lines := strings.Split(yamlStr, "\n")
+	for i, line := range lines {
+		lines[i] = embedPlaceholderRegex.ReplaceAllStringFunc(line, func(placeholder string) string {
+			match := embedPlaceholderRegex.FindStringSubmatch(placeholder)
+			idx, _ := strconv.Atoi(match[1]) //nolint:errcheck // regex guarantees digits
+			action := actions[idx]
+			if action.function == "toJson" {
+				return "{{ " + action.pipeline + " }}"
+			}
+			trimmed := strings.TrimLeft(line, " ")
+			keyCol := len(line) - len(trimmed)
+			for strings.HasPrefix(trimmed, "- ") {
+				keyCol += 2
+				trimmed = trimmed[2:]
+			}
+			return fmt.Sprintf("{{ %s | nindent %d }}", action.pipeline, keyCol+2)
+		})
 	}
+	return strings.Join(lines, "\n")
 }
 
 // RenderStringManifest renders a raw string manifest by executing Go templates,
 // then parsing the result as YAML and marshaling to JSON bytes.
+// Only the first YAML document is returned; see RenderStringManifests.
 func RenderStringManifest(manifestStr string, params map[string]interface{}) ([]byte, error) {
+	docs, err := RenderStringManifests(manifestStr, params)
+	if err != nil {
+		return nil, err
+	}
+	return docs[0], nil
+}
+
+// RenderStringManifests renders a raw string manifest by executing Go templates, then
+// parses each YAML document of the result and marshals it to JSON bytes, in order.
+// Documents that render empty, such as one whose content is a false {{ if }}, are dropped.
+func RenderStringManifests(manifestStr string, params map[string]interface{}) ([][]byte, error) {
 	if strings.TrimSpace(manifestStr) == "" {
 		return nil, fmt.Errorf("empty manifest: string manifest cannot be empty")
 	}
@@ -50,18 +138,31 @@ func RenderStringManifest(manifestStr string, params map[string]interface{}) ([]
 		return nil, fmt.Errorf("empty manifest: template rendered to an empty document")
 	}
 
-	var manifestData map[string]interface{}
-	if unmarshalErr := yaml.Unmarshal([]byte(rendered), &manifestData); unmarshalErr != nil {
-		return nil, fmt.Errorf("failed to parse rendered manifest as YAML: %w", unmarshalErr)
+	var docs [][]byte
+	decoder := yaml.NewDecoder(strings.NewReader(rendered))
+	for i := 0; ; i++ {
+		var manifestData map[string]interface{}
+		if decodeErr := decoder.Decode(&manifestData); errors.Is(decodeErr, io.EOF) {
+			break
+		} else if decodeErr != nil {
+			if i == 0 {
+				return nil, fmt.Errorf("failed to parse rendered manifest as YAML: %w", decodeErr)
+			}
+			return nil, fmt.Errorf("failed to parse document %d of rendered manifest as YAML: %w", i, decodeErr)
+		}
+		if len(manifestData) == 0 {
+			continue
+		}
+
+		data, err := json.Marshal(manifestData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal rendered manifest: %w", err)
+		}
+		docs = append(docs, data)
 	}
-	if len(manifestData) == 0 {
+	if len(docs) == 0 {
 		return nil, fmt.Errorf("empty manifest: rendered YAML did not contain an object")
 	}
 
-	data, err := json.Marshal(manifestData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal rendered manifest: %w", err)
-	}
-
-	return data, nil
+	return docs, nil
 }
@@ -1,6 +1,7 @@
 package manifest
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -101,3 +102,94 @@ func TestRenderStringManifest(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderStringManifests(t *testing.T) {
+	manifest := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: "{{ .name }}"
+---
+{{ if .withSecret }}
+apiVersion: v1
+kind: Secret
+metadata:
+  name: "{{ .name }}"
+{{ end }}
+---
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: "{{ .name }}"
+`
+
+	docs, err := RenderStringManifests(manifest, map[string]interface{}{"name": "app", "withSecret": true})
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Contains(t, string(docs[0]), `"kind":"ConfigMap"`)
+	assert.Contains(t, string(docs[1]), `"kind":"Secret"`)
+	assert.Contains(t, string(docs[2]), `"kind":"ServiceAccount"`)
+
+	docs, err = RenderStringManifests(manifest, map[string]interface{}{"name": "app", "withSecret": false})
+	require.NoError(t, err)
+	require.Len(t, docs, 2, "a document rendering empty is dropped")
+	assert.Contains(t, string(docs[1]), `"kind":"ServiceAccount"`)
+
+	first, err := RenderStringManifest(manifest, map[string]interface{}{"name": "app", "withSecret": true})
+	require.NoError(t, err)
+	assert.Equal(t, docs[0], first)
+
+	_, err = RenderStringManifests("---\n---\n", nil)
+	assert.ErrorContains(t, err, "did not contain an object")
+
+	_, err = RenderStringManifests("kind: ConfigMap\n---\n- not: an object\n", nil)
+	assert.ErrorContains(t, err, "document 1")
+}
+
+func TestToYAMLString_EmbedsStructuredValues(t *testing.T) {
+	manifest := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":   "np",
+			"labels": "{{ toJson .labels }}",
+		},
+		"spec": map[string]interface{}{
+			"nodepool": "{{ .nodepoolSpec | toYaml }}",
+			"pools": []interface{}{
+				"{{ toYaml .nodepoolSpec }}",
+				map[string]interface{}{"spec": "{{- toYaml .nodepoolSpec -}}"},
+			},
+			"note": "pool {{ toJson .labels }}",
+		},
+	}
+
+	yamlStr, err := ToYAMLString(manifest)
+	require.NoError(t, err)
+	assert.Contains(t, yamlStr, "nodepool: {{ .nodepoolSpec | toYaml | nindent 6 }}")
+
+	params := map[string]interface{}{
+		"labels": map[string]interface{}{"team": "hcp"},
+		"nodepoolSpec": map[string]interface{}{
+			"replicas": 2,
+			"platform": map[string]interface{}{"type": "aws"},
+		},
+	}
+	data, err := RenderStringManifest(yamlStr, params)
+	require.NoError(t, err)
+
+	var rendered map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &rendered))
+	metadata := rendered["metadata"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"team": "hcp"}, metadata["labels"])
+
+	spec := rendered["spec"].(map[string]interface{})
+	wantPool := map[string]interface{}{
+		"replicas": float64(2),
+		"platform": map[string]interface{}{"type": "aws"},
+	}
+	assert.Equal(t, wantPool, spec["nodepool"])
+	pools := spec["pools"].([]interface{})
+	assert.Equal(t, wantPool, pools[0])
+	assert.Equal(t, map[string]interface{}{"spec": wantPool}, pools[1])
+	assert.IsType(t, "", spec["note"], "actions mixed with text stay strings")
+}
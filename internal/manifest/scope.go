@@ -0,0 +1,146 @@
+package manifest
+
+import (
+	"fmt"
+
+	apperrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// clusterScopedKinds lists the built-in kinds that are not namespaced.
+// The keys use the API group (empty for the core group) so that a kind is
+// recognized regardless of the version used in the manifest.
+var clusterScopedKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "Namespace"}:                                                    true,
+	{Group: "", Kind: "Node"}:                                                         true,
+	{Group: "", Kind: "PersistentVolume"}:                                             true,
+	{Group: "", Kind: "ComponentStatus"}:                                              true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:                 true,
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                             true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   true,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      true,
+	{Group: "storage.k8s.io", Kind: "CSINode"}:                                        true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               true,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               true,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      true,
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                true,
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}:                 true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}:                       true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"}:       true,
+	{Group: "cluster.open-cluster-management.io", Kind: "ManagedCluster"}:             true,
+	{Group: "cluster.open-cluster-management.io", Kind: "ManagedClusterSet"}:          true,
+	{Group: "config.openshift.io", Kind: "ClusterVersion"}:                            true,
+	{Group: "config.openshift.io", Kind: "ClusterOperator"}:                           true,
+}
+
+// namespacedKinds lists the built-in kinds that always live in a namespace.
+// Kinds in neither table (typically custom resources) are not checked.
+var namespacedKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "ConfigMap"}:                                   true,
+	{Group: "", Kind: "Secret"}:                                      true,
+	{Group: "", Kind: "Service"}:                                     true,
+	{Group: "", Kind: "ServiceAccount"}:                              true,
+	{Group: "", Kind: "Pod"}:                                         true,
+	{Group: "", Kind: "PersistentVolumeClaim"}:                       true,
+	{Group: "", Kind: "Endpoints"}:                                   true,
+	{Group: "", Kind: "Event"}:                                       true,
+	{Group: "", Kind: "LimitRange"}:                                  true,
+	{Group: "", Kind: "ResourceQuota"}:                               true,
+	{Group: "apps", Kind: "Deployment"}:                              true,
+	{Group: "apps", Kind: "StatefulSet"}:                             true,
+	{Group: "apps", Kind: "DaemonSet"}:                               true,
+	{Group: "apps", Kind: "ReplicaSet"}:                              true,
+	{Group: "batch", Kind: "Job"}:                                    true,
+	{Group: "batch", Kind: "CronJob"}:                                true,
+	{Group: "rbac.authorization.k8s.io", Kind: "Role"}:               true,
+	{Group: "rbac.authorization.k8s.io", Kind: "RoleBinding"}:        true,
+	{Group: "networking.k8s.io", Kind: "Ingress"}:                    true,
+	{Group: "networking.k8s.io", Kind: "NetworkPolicy"}:              true,
+	{Group: "policy", Kind: "PodDisruptionBudget"}:                   true,
+	{Group: "autoscaling", Kind: "HorizontalPodAutoscaler"}:          true,
+	{Group: "coordination.k8s.io", Kind: "Lease"}:                    true,
+	{Group: "work.open-cluster-management.io", Kind: "ManifestWork"}: true,
+}
+
+// Scope describes whether a kind is namespaced.
+type Scope string
+
+const (
+	// ScopeUnknown indicates the kind is not in the built-in table
+	ScopeUnknown Scope = "unknown"
+	// ScopeCluster indicates the kind is cluster-scoped
+	ScopeCluster Scope = "cluster"
+	// ScopeNamespaced indicates the kind is namespaced
+	ScopeNamespaced Scope = "namespaced"
+)
+
+// ScopeOf returns the scope of a kind according to the built-in table.
+func ScopeOf(gk schema.GroupKind) Scope {
+	switch {
+	case clusterScopedKinds[gk]:
+		return ScopeCluster
+	case namespacedKinds[gk]:
+		return ScopeNamespaced
+	default:
+		return ScopeUnknown
+	}
+}
+
+// ValidateNamespaceScope checks that a manifest sets metadata.namespace if and only if
+// its kind is namespaced. Kinds with an unknown scope are accepted as-is so that
+// custom resources keep working; the API server remains the final authority for them.
+func ValidateNamespaceScope(obj *unstructured.Unstructured) error {
+	if reason := NamespaceScopeViolation(obj); reason != "" {
+		return apperrors.Validation("%s", reason).AsError()
+	}
+	return nil
+}
+
+// ValidateManifestWorkNamespaceScope runs the namespace scope check on every manifest in
+// the ManifestWork's workload. The ManifestWork itself is not checked: its namespace is
+// the target consumer and is set by the Maestro transport.
+func ValidateManifestWorkNamespaceScope(work *workv1.ManifestWork) error {
+	if work == nil {
+		return apperrors.Validation("work cannot be nil").AsError()
+	}
+
+	for i, m := range work.Spec.Workload.Manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(m.Raw); err != nil {
+			return apperrors.Validation("ManifestWork %q manifest[%d]: failed to unmarshal: %v", work.Name, i, err).AsError()
+		}
+		if reason := NamespaceScopeViolation(obj); reason != "" {
+			return apperrors.Validation("ManifestWork %q manifest[%d]: %s", work.Name, i, reason).AsError()
+		}
+	}
+
+	return nil
+}
+
+// NamespaceScopeViolation returns a description of the mismatch between the manifest's
+// namespace and the scope of its kind, or "" if there is none.
+func NamespaceScopeViolation(obj *unstructured.Unstructured) string {
+	gvk := obj.GroupVersionKind()
+	namespace := obj.GetNamespace()
+
+	switch ScopeOf(gvk.GroupKind()) {
+	case ScopeCluster:
+		if namespace != "" {
+			return fmt.Sprintf("%s %q is cluster-scoped but sets namespace %q", gvk.Kind, obj.GetName(), namespace)
+		}
+	case ScopeNamespaced:
+		if namespace == "" {
+			return fmt.Sprintf("%s %q is namespaced but does not set metadata.namespace", gvk.Kind, obj.GetName())
+		}
+	case ScopeUnknown:
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func newObj(apiVersion, kind, namespace string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName("test")
+	if namespace != "" {
+		obj.SetNamespace(namespace)
+	}
+	return obj
+}
+
+func TestScopeOf(t *testing.T) {
+	assert.Equal(t, ScopeCluster, ScopeOf(schema.GroupKind{Kind: "Namespace"}))
+	assert.Equal(t, ScopeCluster, ScopeOf(schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}))
+	assert.Equal(t, ScopeNamespaced, ScopeOf(schema.GroupKind{Kind: "ConfigMap"}))
+	assert.Equal(t, ScopeNamespaced, ScopeOf(schema.GroupKind{Group: "apps", Kind: "Deployment"}))
+	assert.Equal(t, ScopeUnknown, ScopeOf(schema.GroupKind{Group: "example.com", Kind: "Widget"}))
+	// Same kind name in a different group is not assumed to share the scope
+	assert.Equal(t, ScopeUnknown, ScopeOf(schema.GroupKind{Group: "example.com", Kind: "Namespace"}))
+}
+
+func TestValidateNamespaceScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     *unstructured.Unstructured
+		wantErr string
+	}{
+		{name: "cluster-scoped without namespace", obj: newObj("v1", "Namespace", "")},
+		{
+			name:    "cluster-scoped with namespace",
+			obj:     newObj("rbac.authorization.k8s.io/v1", "ClusterRole", "default"),
+			wantErr: `ClusterRole "test" is cluster-scoped but sets namespace "default"`,
+		},
+		{name: "namespaced with namespace", obj: newObj("v1", "ConfigMap", "default")},
+		{
+			name:    "namespaced without namespace",
+			obj:     newObj("apps/v1", "Deployment", ""),
+			wantErr: `Deployment "test" is namespaced but does not set metadata.namespace`,
+		},
+		{name: "unknown kind without namespace", obj: newObj("example.com/v1", "Widget", "")},
+		{name: "unknown kind with namespace", obj: newObj("example.com/v1", "Widget", "default")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateNamespaceScope(tt.obj)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestValidateManifestWorkNamespaceScope(t *testing.T) {
+	toManifest := func(t *testing.T, obj *unstructured.Unstructured) workv1.Manifest {
+		t.Helper()
+		raw, err := json.Marshal(obj.Object)
+		require.NoError(t, err)
+		return workv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}}
+	}
+
+	t.Run("valid workload", func(t *testing.T) {
+		work := &workv1.ManifestWork{}
+		work.Name = "work"
+		work.Spec.Workload.Manifests = []workv1.Manifest{
+			toManifest(t, newObj("v1", "Namespace", "")),
+			toManifest(t, newObj("v1", "ConfigMap", "ns")),
+		}
+		assert.NoError(t, ValidateManifestWorkNamespaceScope(work))
+	})
+
+	t.Run("invalid workload manifest", func(t *testing.T) {
+		work := &workv1.ManifestWork{}
+		work.Name = "work"
+		work.Spec.Workload.Manifests = []workv1.Manifest{
+			toManifest(t, newObj("v1", "Namespace", "")),
+			toManifest(t, newObj("v1", "Namespace", "ns")),
+		}
+		err := ValidateManifestWorkNamespaceScope(work)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `ManifestWork "work" manifest[1]: Namespace "test" is cluster-scoped`)
+	})
+
+	t.Run("nil work", func(t *testing.T) {
+		assert.Error(t, ValidateManifestWorkNamespaceScope(nil))
+	})
+}
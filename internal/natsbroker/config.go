@@ -0,0 +1,91 @@
+package natsbroker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// DefaultAckWait is how long JetStream waits for the ack of a delivered message before
+// redelivering it, when Config.AckWait is unset
+const DefaultAckWait = 30 * time.Second
+
+// connectTimeout bounds each connection attempt to a server
+const connectTimeout = 10 * time.Second
+
+// Config configures the NATS JetStream subscriber
+type Config struct {
+	// TLS configures TLS connections to the servers. It is used when enabled or when a file
+	// is set, and for tls:// server URLs, which are verified with the system roots otherwise.
+	TLS *utils.TLSConfig `yaml:"tls,omitempty" mapstructure:"tls"`
+	// CredentialsPath is the absolute path to a NATS credentials (.creds) file
+	CredentialsPath string `yaml:"credentials_path,omitempty" mapstructure:"credentials_path"`
+	// Stream is the JetStream stream holding the subscribed subjects
+	Stream string `yaml:"stream" mapstructure:"stream"`
+	// Durable is the name of the durable consumer. Empty uses the subscription ID, so
+	// replicas with the same subscription ID share the messages of a subject.
+	Durable string `yaml:"durable,omitempty" mapstructure:"durable"`
+	// Servers are the URLs of the NATS servers, such as nats://nats:4222
+	Servers []string `yaml:"servers" mapstructure:"servers"`
+	// AckWait is how long a delivered message may stay unacked before JetStream redelivers
+	// it. Empty uses DefaultAckWait. Messages being executed are kept in progress.
+	AckWait utils.Duration `yaml:"ack_wait,omitempty" mapstructure:"ack_wait"`
+	// MaxDeliver caps the deliveries of a message. Zero is unlimited.
+	MaxDeliver int `yaml:"max_deliver,omitempty" mapstructure:"max_deliver"`
+}
+
+// ackWait returns the ack wait of the config
+func (c Config) ackWait() time.Duration {
+	if c.AckWait > 0 {
+		return c.AckWait.Std()
+	}
+	return DefaultAckWait
+}
+
+// consumerConfig returns the config of the durable consumer of subject
+func (c Config) consumerConfig(durable, subject string) jetstream.ConsumerConfig {
+	maxDeliver := c.MaxDeliver
+	if maxDeliver <= 0 {
+		maxDeliver = -1
+	}
+	return jetstream.ConsumerConfig{
+		Durable:       durable,
+		FilterSubject: subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       c.ackWait(),
+		MaxDeliver:    maxDeliver,
+	}
+}
+
+// serverURL returns the comma-separated server URLs nats.Connect takes
+func (c Config) serverURL() string {
+	return strings.Join(c.Servers, ",")
+}
+
+// connectOptions returns the connection options of the config, reading its TLS files.
+// Reconnects are unlimited: a subscriber is recreated when its consumer stops, not when a
+// server goes away.
+func (c Config) connectOptions() ([]nats.Option, error) {
+	opts := []nats.Option{
+		nats.Name("hyperfleet-adapter"),
+		nats.Timeout(connectTimeout),
+		nats.MaxReconnects(-1),
+	}
+	if c.CredentialsPath != "" {
+		opts = append(opts, nats.UserCredentials(c.CredentialsPath))
+	}
+	if tlsFiles := c.TLS; tlsFiles != nil &&
+		(tlsFiles.Enabled || tlsFiles.CAFile != "" || tlsFiles.CertFile != "" || tlsFiles.KeyFile != "") {
+		tlsConfig, err := tlsFiles.ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("NATS TLS: %w", err)
+		}
+		opts = append(opts, nats.Secure(tlsConfig))
+	}
+	return opts, nil
+}
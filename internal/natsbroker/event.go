@@ -0,0 +1,26 @@
+package natsbroker
+
+import (
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/brokerutil"
+)
+
+// headerPrefix prefixes the attribute headers of the CloudEvents NATS protocol binding
+const headerPrefix = "ce-"
+
+// messageToEvent converts the headers and data of a NATS message to a CloudEvent following
+// the CloudEvents NATS protocol binding: a binary-mode message carries the attributes in
+// ce- headers and the data in its payload.
+func messageToEvent(header nats.Header, data []byte) (*event.Event, error) {
+	headers := make(map[string]string, len(header))
+	for key, values := range header {
+		if len(values) > 0 {
+			headers[strings.ToLower(key)] = values[0]
+		}
+	}
+	return brokerutil.DecodeEvent(headers, headerPrefix, data)
+}
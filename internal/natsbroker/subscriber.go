@@ -0,0 +1,297 @@
+// Package natsbroker consumes CloudEvents from NATS JetStream. Its Subscriber implements
+// the hyperfleet-broker Subscriber interface, so installations without the hyperfleet-broker
+// infrastructure can feed events to the adapter from a JetStream stream.
+package natsbroker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/brokerutil"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+)
+
+// BrokerType is the broker type of the NATS JetStream subscriber
+const BrokerType = "nats"
+
+// Redelivery backoff of a message whose handler fails, by its number of deliveries
+const (
+	redeliveryBaseDelay = time.Second
+	redeliveryMaxDelay  = 30 * time.Second
+)
+
+// Subscriber consumes the subject it subscribes to with a durable pull consumer of the
+// configured stream. A message is acked once its handler returns nil and negatively acked
+// with backoff when it fails, so JetStream redelivers it, up to MaxDeliver deliveries.
+// Messages that are not CloudEvents are logged and terminated. It is safe for concurrent use.
+type Subscriber struct {
+	log     logger.Logger
+	metrics *broker.MetricsRecorder
+	conn    *nats.Conn
+	errors  chan *broker.SubscriberError
+	durable string
+	topic   string
+	cancel  context.CancelFunc
+	iter    jetstream.MessagesContext
+	config  Config
+	opts    []nats.Option
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	closed  bool
+}
+
+// NewSubscriber creates a Subscriber consuming with the durable consumer of config, or with
+// one named after subscriptionID. metrics may be nil. It reads the TLS files of config
+// and connects when it subscribes.
+func NewSubscriber(
+	config Config,
+	subscriptionID string,
+	log logger.Logger,
+	metrics *broker.MetricsRecorder,
+) (*Subscriber, error) {
+	if len(config.Servers) == 0 {
+		return nil, fmt.Errorf("nats servers are required")
+	}
+	if config.Stream == "" {
+		return nil, fmt.Errorf("nats stream is required")
+	}
+	durable := config.Durable
+	if durable == "" {
+		durable = subscriptionID
+	}
+	if durable == "" {
+		return nil, fmt.Errorf("nats durable consumer or subscription ID is required")
+	}
+	opts, err := config.connectOptions()
+	if err != nil {
+		return nil, err
+	}
+	return &Subscriber{
+		log:     log,
+		metrics: metrics,
+		errors:  make(chan *broker.SubscriberError, broker.ErrorChannelBufferSize),
+		config:  config,
+		opts:    opts,
+		durable: durable,
+	}, nil
+}
+
+// Subscribe implements broker.Subscriber. It connects to the servers, creates or updates the
+// durable consumer of topic, then consumes it in the background until the subscriber is
+// closed or ctx is done. A subscriber consumes a single topic.
+func (s *Subscriber) Subscribe(ctx context.Context, topic string, handler broker.HandlerFunc) error {
+	if handler == nil {
+		return fmt.Errorf("handler must be provided")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return fmt.Errorf("subscriber is closed")
+	}
+	if s.topic != "" {
+		return fmt.Errorf("subscriber already consumes topic %s", s.topic)
+	}
+
+	conn, err := nats.Connect(s.config.serverURL(), s.opts...)
+	if err != nil {
+		return fmt.Errorf("connecting to NATS: %w", err)
+	}
+	iter, err := s.consumer(ctx, conn, topic)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	consumeCtx, cancel := context.WithCancel(ctx)
+	s.conn, s.iter, s.cancel, s.topic = conn, iter, cancel, topic
+
+	s.log.Infof(ctx, "Consuming NATS subject %s of stream %s with durable consumer %s",
+		topic, s.config.Stream, s.durable)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.consume(consumeCtx, iter, topic, handler)
+	}()
+	return nil
+}
+
+// consumer creates or updates the durable consumer of topic and starts pulling its messages
+func (s *Subscriber) consumer(ctx context.Context, conn *nats.Conn, topic string) (jetstream.MessagesContext, error) {
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, fmt.Errorf("creating JetStream context: %w", err)
+	}
+	consumer, err := js.CreateOrUpdateConsumer(ctx, s.config.Stream, s.config.consumerConfig(s.durable, topic))
+	if err != nil {
+		return nil, fmt.Errorf("creating consumer %s of stream %s: %w", s.durable, s.config.Stream, err)
+	}
+	iter, err := consumer.Messages()
+	if err != nil {
+		return nil, fmt.Errorf("consuming stream %s: %w", s.config.Stream, err)
+	}
+	return iter, nil
+}
+
+// consume handles the messages of iter until ctx is done or iter fails
+func (s *Subscriber) consume(
+	ctx context.Context,
+	iter jetstream.MessagesContext,
+	topic string,
+	handler broker.HandlerFunc,
+) {
+	for {
+		msg, err := iter.Next(jetstream.NextContext(ctx))
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// Disconnections are retried by the connection: an error here means the
+			// consumer was deleted or the connection closed
+			brokerutil.SendError(s.log, "NATS", s.errors, &broker.SubscriberError{
+				Op:             "receive",
+				Topic:          topic,
+				SubscriptionID: s.durable,
+				Err:            err,
+				Timestamp:      time.Now(),
+				Fatal:          true,
+			})
+			return
+		}
+		s.handle(ctx, msg, topic, handler)
+	}
+}
+
+// handle runs handler on msg, then acks it or, when the handler fails, negatively acks it
+// with a backoff by its deliveries so JetStream redelivers it
+func (s *Subscriber) handle(ctx context.Context, msg jetstream.Msg, topic string, handler broker.HandlerFunc) {
+	s.recordConsumed(topic)
+	evt, err := messageToEvent(msg.Headers(), msg.Data())
+	if err != nil {
+		s.recordError(topic, "conversion")
+		s.log.Errorf(ctx, "Terminating NATS message of subject %s that is not a CloudEvent: %v", msg.Subject(), err)
+		s.settle(topic, "term", msg.TermWithReason("not a CloudEvent"))
+		return
+	}
+
+	stop := s.keepInProgress(msg)
+	start := time.Now()
+	err = handler(ctx, evt)
+	stop()
+	s.recordDuration(topic, time.Since(start))
+	if err == nil {
+		s.settle(topic, "ack", msg.Ack())
+		return
+	}
+
+	s.recordError(topic, "handler")
+	var delivered uint64 = 1
+	if meta, metaErr := msg.Metadata(); metaErr == nil {
+		delivered = meta.NumDelivered
+	}
+	if s.config.MaxDeliver > 0 && delivered >= uint64(s.config.MaxDeliver) {
+		s.log.Errorf(ctx, "Handler failed to process event %s on its last delivery (%d), it is not redelivered: %v",
+			evt.ID(), delivered, err)
+	} else {
+		s.log.Errorf(ctx, "Handler failed to process event %s (delivery %d), redelivering: %v", evt.ID(), delivered, err)
+	}
+	s.settle(topic, "nak", msg.NakWithDelay(redeliveryDelay(delivered)))
+}
+
+// keepInProgress resets the ack wait of msg until the returned function is called, so an
+// execution longer than AckWait does not get the message redelivered
+func (s *Subscriber) keepInProgress(msg jetstream.Msg) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(s.config.ackWait() / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				_ = msg.InProgress() //nolint:errcheck // a missed refresh at worst redelivers the message
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+// settle reports the failure of an ack, nak or term of a message. JetStream redelivers the
+// message once its ack wait expires.
+func (s *Subscriber) settle(topic, op string, err error) {
+	if err == nil {
+		return
+	}
+	brokerutil.SendError(s.log, "NATS", s.errors, &broker.SubscriberError{
+		Op:             op,
+		Topic:          topic,
+		SubscriptionID: s.durable,
+		Err:            err,
+		Timestamp:      time.Now(),
+	})
+}
+
+// redeliveryDelay returns the delay before the redelivery of a message delivered delivered times
+func redeliveryDelay(delivered uint64) time.Duration {
+	delay := redeliveryBaseDelay
+	for i := uint64(1); i < delivered && delay < redeliveryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, redeliveryMaxDelay)
+}
+
+// Errors implements broker.Subscriber
+func (s *Subscriber) Errors() <-chan *broker.SubscriberError {
+	return s.errors
+}
+
+// BrokerType implements broker.Subscriber
+func (s *Subscriber) BrokerType() string {
+	return BrokerType
+}
+
+// Close implements broker.Subscriber. It stops consuming, waits for the handler in
+// progress, closes the connection and the error channel.
+func (s *Subscriber) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	if s.cancel != nil {
+		s.cancel()
+		s.iter.Stop()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	close(s.errors)
+	return nil
+}
+
+func (s *Subscriber) recordConsumed(topic string) {
+	if s.metrics != nil {
+		s.metrics.RecordConsumed(topic)
+	}
+}
+
+func (s *Subscriber) recordError(topic, errorType string) {
+	if s.metrics != nil {
+		s.metrics.RecordError(topic, errorType)
+	}
+}
+
+func (s *Subscriber) recordDuration(topic string, d time.Duration) {
+	if s.metrics != nil {
+		s.metrics.RecordDuration(topic, d)
+	}
+}
@@ -0,0 +1,203 @@
+package natsbroker
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// fakeMsg records how it was settled
+type fakeMsg struct {
+	jetstream.Msg
+	header    nats.Header
+	settled   string
+	data      []byte
+	delivered uint64
+	nakDelay  time.Duration
+}
+
+func (m *fakeMsg) Headers() nats.Header { return m.header }
+func (m *fakeMsg) Data() []byte         { return m.data }
+func (m *fakeMsg) Subject() string      { return "hyperfleet.clusters" }
+func (m *fakeMsg) InProgress() error    { return nil }
+
+func (m *fakeMsg) Metadata() (*jetstream.MsgMetadata, error) {
+	return &jetstream.MsgMetadata{NumDelivered: m.delivered}, nil
+}
+
+func (m *fakeMsg) Ack() error {
+	m.settled = "ack"
+	return nil
+}
+
+func (m *fakeMsg) NakWithDelay(delay time.Duration) error {
+	m.settled, m.nakDelay = "nak", delay
+	return nil
+}
+
+func (m *fakeMsg) TermWithReason(string) error {
+	m.settled = "term"
+	return nil
+}
+
+// fakeIter serves messages, then fails with err
+type fakeIter struct {
+	jetstream.MessagesContext
+	err      error
+	messages []*fakeMsg
+	mu       sync.Mutex
+}
+
+func (it *fakeIter) Next(...jetstream.NextOpt) (jetstream.Msg, error) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if len(it.messages) == 0 {
+		return nil, it.err
+	}
+	msg := it.messages[0]
+	it.messages = it.messages[1:]
+	return msg, nil
+}
+
+func binaryMessage(id string, delivered uint64) *fakeMsg {
+	return &fakeMsg{
+		data:      []byte(`{"id":"cluster-1"}`),
+		delivered: delivered,
+		header: nats.Header{
+			"Ce-Specversion": []string{"1.0"},
+			"Ce-Id":          []string{id},
+			"Ce-Source":      []string{"/hyperfleet"},
+			"Ce-Type":        []string{"io.hyperfleet.cluster.updated"},
+			"Ce-Time":        []string{"2026-01-02T03:04:05Z"},
+			"Ce-Region":      []string{"us-east-1"},
+			"Content-Type":   []string{"application/json"},
+		},
+	}
+}
+
+func TestMessageToEvent(t *testing.T) {
+	msg := binaryMessage("evt-1", 1)
+	evt, err := messageToEvent(msg.header, msg.data)
+	require.NoError(t, err)
+	assert.Equal(t, "evt-1", evt.ID())
+	assert.Equal(t, "application/json", evt.DataContentType())
+	assert.Equal(t, "us-east-1", evt.Extensions()["region"])
+
+	_, err = messageToEvent(nats.Header{"Ce-Specversion": []string{"1.0"}}, nil)
+	assert.ErrorContains(t, err, "invalid binary CloudEvent", "a binary message without ce-id should be rejected")
+}
+
+func TestConfig_ConnectOptions(t *testing.T) {
+	opts, err := Config{}.connectOptions()
+	require.NoError(t, err)
+	plain := len(opts)
+
+	opts, err = Config{TLS: &utils.TLSConfig{Enabled: true}}.connectOptions()
+	require.NoError(t, err)
+	assert.Len(t, opts, plain+1)
+
+	_, err = Config{TLS: &utils.TLSConfig{CAFile: filepath.Join(t.TempDir(), "missing")}}.connectOptions()
+	assert.ErrorContains(t, err, "NATS TLS: failed to read CA file")
+}
+
+func newTestSubscriber(config Config) *Subscriber {
+	return &Subscriber{
+		log:     logger.NewTestLogger(),
+		errors:  make(chan *broker.SubscriberError, broker.ErrorChannelBufferSize),
+		config:  config,
+		durable: "adapter",
+	}
+}
+
+func TestSubscriber_Consume(t *testing.T) {
+	s := newTestSubscriber(Config{MaxDeliver: 3})
+	succeeded := binaryMessage("evt-1", 1)
+	garbage := &fakeMsg{data: []byte("garbage"), delivered: 1}
+	failed := binaryMessage("evt-3", 2)
+	exhausted := binaryMessage("evt-4", 3)
+	it := &fakeIter{
+		messages: []*fakeMsg{succeeded, garbage, failed, exhausted},
+		err:      errors.New("consumer deleted"),
+	}
+
+	var handled []string
+	handler := func(_ context.Context, evt *event.Event) error {
+		handled = append(handled, evt.ID())
+		if evt.ID() == "evt-1" {
+			return nil
+		}
+		return errors.New("transient")
+	}
+	s.consume(context.Background(), it, "hyperfleet.clusters", handler)
+
+	assert.Equal(t, []string{"evt-1", "evt-3", "evt-4"}, handled)
+	assert.Equal(t, "ack", succeeded.settled)
+	assert.Equal(t, "term", garbage.settled, "a message that is not a CloudEvent should be terminated")
+	assert.Equal(t, "nak", failed.settled, "a failed event should be redelivered")
+	assert.Equal(t, 2*time.Second, failed.nakDelay, "the redelivery delay should grow with the deliveries")
+	assert.Equal(t, "nak", exhausted.settled)
+
+	subErr := <-s.Errors()
+	assert.Equal(t, "receive", subErr.Op)
+	assert.True(t, subErr.Fatal, "a stopped consumer should make the subscriber be recreated")
+}
+
+func TestRedeliveryDelay(t *testing.T) {
+	assert.Equal(t, time.Second, redeliveryDelay(0))
+	assert.Equal(t, time.Second, redeliveryDelay(1))
+	assert.Equal(t, 4*time.Second, redeliveryDelay(3))
+	assert.Equal(t, redeliveryMaxDelay, redeliveryDelay(10))
+	assert.Equal(t, redeliveryMaxDelay, redeliveryDelay(1000))
+}
+
+func TestNewSubscriber(t *testing.T) {
+	log := logger.NewTestLogger()
+	config := Config{Servers: []string{"nats://127.0.0.1:4222"}, Stream: "HYPERFLEET"}
+
+	s, err := NewSubscriber(config, "my-adapter", log, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "my-adapter", s.durable)
+	assert.Equal(t, BrokerType, s.BrokerType())
+
+	consumer := s.config.consumerConfig(s.durable, "hyperfleet.clusters")
+	assert.Equal(t, "hyperfleet.clusters", consumer.FilterSubject)
+	assert.Equal(t, jetstream.AckExplicitPolicy, consumer.AckPolicy)
+	assert.Equal(t, DefaultAckWait, consumer.AckWait)
+	assert.Equal(t, -1, consumer.MaxDeliver, "an unset max_deliver should be unlimited")
+
+	config.Durable, config.AckWait, config.MaxDeliver = "shared", utils.Duration(time.Minute), 5
+	s, err = NewSubscriber(config, "my-adapter", log, nil)
+	require.NoError(t, err)
+	consumer = s.config.consumerConfig(s.durable, "hyperfleet.clusters")
+	assert.Equal(t, "shared", consumer.Durable)
+	assert.Equal(t, time.Minute, consumer.AckWait)
+	assert.Equal(t, 5, consumer.MaxDeliver)
+
+	_, err = NewSubscriber(Config{Stream: "HYPERFLEET"}, "my-adapter", log, nil)
+	assert.ErrorContains(t, err, "servers are required")
+	_, err = NewSubscriber(Config{Servers: config.Servers}, "my-adapter", log, nil)
+	assert.ErrorContains(t, err, "stream is required")
+
+	// Subscribe fails fast when no server can be reached
+	s, err = NewSubscriber(Config{Servers: []string{"nats://127.0.0.1:1"}, Stream: "HYPERFLEET"}, "my-adapter", log, nil)
+	require.NoError(t, err)
+	err = s.Subscribe(context.Background(), "hyperfleet.clusters",
+		func(context.Context, *event.Event) error { return nil })
+	assert.ErrorContains(t, err, "connecting to NATS")
+	require.NoError(t, s.Close())
+	_, open := <-s.Errors()
+	assert.False(t, open, "Close should close the error channel")
+}
@@ -0,0 +1,280 @@
+// Package notification sends webhook notifications (Slack-compatible or generic JSON)
+// when executions keep failing for the same resource or the adapter becomes degraded.
+//
+// Notifications are sent asynchronously so a slow webhook never delays event processing.
+// They are deduplicated and rate-limited per key: a resource that keeps failing, or a
+// degraded reason that keeps recurring, notifies at most once per MinInterval.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// Notification kinds
+const (
+	KindExecutionFailed = "execution_failed"
+	KindDegraded        = "degraded"
+)
+
+// Defaults for unset NotificationsConfig fields
+const (
+	DefaultFailureThreshold = 3
+	DefaultMinInterval      = 15 * time.Minute
+	DefaultTimeout          = 10 * time.Second
+)
+
+const (
+	defaultFailedMessage = "[{{ .adapter }}] {{ .resource_kind }} {{ .resource_id }} failed " +
+		"{{ .failures }} consecutive times in phase {{ .phase }}: {{ .error }}"
+	defaultDegradedMessage = "[{{ .adapter }}] adapter is degraded: {{ .reason }}"
+)
+
+// Notification is the payload posted to generic webhook targets. Slack targets
+// receive only the rendered message as {"text": message}.
+type Notification struct {
+	Time         time.Time `json:"time"`
+	Kind         string    `json:"kind"`
+	Adapter      string    `json:"adapter"`
+	ResourceKind string    `json:"resource_kind,omitempty"`
+	ResourceID   string    `json:"resource_id,omitempty"`
+	Phase        string    `json:"phase,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Message      string    `json:"message"`
+	Failures     int       `json:"failures,omitempty"`
+}
+
+// templateData exposes the notification fields to message templates
+func (n Notification) templateData() map[string]interface{} {
+	return map[string]interface{}{
+		"time":          n.Time.Format(time.RFC3339),
+		"kind":          n.Kind,
+		"adapter":       n.Adapter,
+		"resource_kind": n.ResourceKind,
+		"resource_id":   n.ResourceID,
+		"phase":         n.Phase,
+		"error":         n.Error,
+		"reason":        n.Reason,
+		"failures":      n.Failures,
+	}
+}
+
+// Failure describes a failed execution reported to RecordFailure
+type Failure struct {
+	ResourceKind string
+	ResourceID   string
+	Phase        string
+	Error        string
+}
+
+// Option configures a Notifier
+type Option func(*Notifier)
+
+// WithHTTPClient sets the client used to post notifications
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+// Notifier tracks consecutive failures per resource and posts notifications to the
+// configured targets. A nil *Notifier is valid and does nothing.
+type Notifier struct {
+	client      *http.Client
+	log         logger.Logger
+	now         func() time.Time
+	failures    map[string]int
+	lastSent    map[string]time.Time
+	adapter     string
+	targets     []configloader.NotificationTarget
+	threshold   int
+	minInterval time.Duration
+	timeout     time.Duration
+	wg          sync.WaitGroup
+	mu          sync.Mutex
+	degraded    bool
+}
+
+// New creates a Notifier for cfg. It returns nil when no targets are configured.
+func New(cfg configloader.NotificationsConfig, adapterName string, log logger.Logger, opts ...Option) *Notifier {
+	if len(cfg.Targets) == 0 {
+		return nil
+	}
+	n := &Notifier{
+		client:      &http.Client{},
+		log:         log,
+		now:         time.Now,
+		failures:    make(map[string]int),
+		lastSent:    make(map[string]time.Time),
+		adapter:     adapterName,
+		targets:     cfg.Targets,
+		threshold:   cfg.FailureThreshold,
+		minInterval: cfg.MinInterval.OrDefault(DefaultMinInterval),
+		timeout:     cfg.Timeout.OrDefault(DefaultTimeout),
+	}
+	if n.threshold <= 0 {
+		n.threshold = DefaultFailureThreshold
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// RecordFailure counts a failed execution for the resource and notifies once the
+// consecutive failure count reaches the threshold. Failures without a resource ID
+// are not tracked since they cannot be attributed to a resource.
+func (n *Notifier) RecordFailure(ctx context.Context, f Failure) {
+	if n == nil || f.ResourceID == "" {
+		return
+	}
+	key := f.ResourceKind + "/" + f.ResourceID
+
+	n.mu.Lock()
+	n.failures[key]++
+	count := n.failures[key]
+	send := count >= n.threshold && n.allowLocked(KindExecutionFailed+":"+key)
+	n.mu.Unlock()
+
+	if send {
+		n.send(ctx, Notification{
+			Kind:         KindExecutionFailed,
+			ResourceKind: f.ResourceKind,
+			ResourceID:   f.ResourceID,
+			Phase:        f.Phase,
+			Error:        f.Error,
+			Failures:     count,
+		})
+	}
+}
+
+// RecordSuccess resets the consecutive failure count of the resource
+func (n *Notifier) RecordSuccess(resourceKind, resourceID string) {
+	if n == nil || resourceID == "" {
+		return
+	}
+	n.mu.Lock()
+	delete(n.failures, resourceKind+"/"+resourceID)
+	n.mu.Unlock()
+}
+
+// SetDegraded records whether the adapter is degraded and notifies when it enters
+// the degraded state
+func (n *Notifier) SetDegraded(ctx context.Context, degraded bool, reason string) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	entered := degraded && !n.degraded
+	n.degraded = degraded
+	send := entered && n.allowLocked(KindDegraded+":"+reason)
+	n.mu.Unlock()
+
+	if send {
+		n.send(ctx, Notification{Kind: KindDegraded, Reason: reason})
+	}
+}
+
+// Close waits for notifications that are still being sent
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+// allowLocked reports whether a notification for key may be sent now and records
+// the send. Entries older than minInterval are pruned. Callers must hold n.mu.
+func (n *Notifier) allowLocked(key string) bool {
+	now := n.now()
+	for k, sentAt := range n.lastSent {
+		if now.Sub(sentAt) >= n.minInterval {
+			delete(n.lastSent, k)
+		}
+	}
+	if _, recent := n.lastSent[key]; recent {
+		return false
+	}
+	n.lastSent[key] = now
+	return true
+}
+
+// send posts the notification to every target in the background
+func (n *Notifier) send(ctx context.Context, notif Notification) {
+	notif.Time = n.now().UTC()
+	notif.Adapter = n.adapter
+	// The event context is canceled once the handler returns; keep its values only
+	ctx = context.WithoutCancel(ctx)
+
+	for _, target := range n.targets {
+		n.wg.Add(1)
+		go func(target configloader.NotificationTarget) {
+			defer n.wg.Done()
+			if err := n.post(ctx, target, notif); err != nil {
+				errCtx := logger.WithErrorField(ctx, err)
+				n.log.Warnf(errCtx, "Failed to send %s notification to %s", notif.Kind, target.Name)
+				return
+			}
+			n.log.Infof(ctx, "Sent %s notification to %s", notif.Kind, target.Name)
+		}(target)
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, target configloader.NotificationTarget, notif Notification) error {
+	message := target.Message
+	if message == "" {
+		message = defaultFailedMessage
+		if notif.Kind == KindDegraded {
+			message = defaultDegradedMessage
+		}
+	}
+	rendered, err := utils.RenderTemplate(message, notif.templateData())
+	if err != nil {
+		return fmt.Errorf("failed to render message: %w", err)
+	}
+	notif.Message = rendered
+
+	var payload interface{} = notif
+	if target.Type == configloader.NotificationTargetSlack {
+		payload = map[string]string{"text": rendered}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range target.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			n.log.Debugf(ctx, "Failed to close webhook response body: %v", closeErr)
+		}
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
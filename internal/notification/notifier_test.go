@@ -0,0 +1,147 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookRecorder is a test webhook that records the JSON bodies it receives
+type webhookRecorder struct {
+	server *httptest.Server
+	bodies []map[string]interface{}
+	mu     sync.Mutex
+	status int
+}
+
+func newWebhookRecorder(t *testing.T) *webhookRecorder {
+	w := &webhookRecorder{status: http.StatusOK}
+	w.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		w.mu.Lock()
+		w.bodies = append(w.bodies, body)
+		w.mu.Unlock()
+		rw.WriteHeader(w.status)
+	}))
+	t.Cleanup(w.server.Close)
+	return w
+}
+
+func (w *webhookRecorder) received() []map[string]interface{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]map[string]interface{}(nil), w.bodies...)
+}
+
+func newTestNotifier(targets []configloader.NotificationTarget, now *time.Time) *Notifier {
+	n := New(configloader.NotificationsConfig{
+		Targets:          targets,
+		FailureThreshold: 2,
+		MinInterval:      configloader.Duration(time.Minute),
+	}, "test-adapter", logger.NewTestLogger())
+	n.now = func() time.Time { return *now }
+	return n
+}
+
+func TestNew_NoTargets(t *testing.T) {
+	n := New(configloader.NotificationsConfig{}, "test-adapter", logger.NewTestLogger())
+	assert.Nil(t, n)
+
+	// A nil notifier is a no-op
+	n.RecordFailure(context.Background(), Failure{ResourceID: "c1"})
+	n.RecordSuccess("Cluster", "c1")
+	n.SetDegraded(context.Background(), true, "down")
+	n.Close()
+}
+
+func TestNotifier_FailureThresholdAndDedup(t *testing.T) {
+	slack := newWebhookRecorder(t)
+	webhook := newWebhookRecorder(t)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	headers := map[string]string{"X-Token": "secret"}
+	n := newTestNotifier([]configloader.NotificationTarget{
+		{Name: "slack", Type: configloader.NotificationTargetSlack, URL: slack.server.URL, Headers: headers},
+		{Name: "hook", Type: configloader.NotificationTargetWebhook, URL: webhook.server.URL, Headers: headers,
+			Message: "{{ .resource_id }} x{{ .failures }}"},
+	}, &now)
+	ctx := context.Background()
+	failure := Failure{ResourceKind: "Cluster", ResourceID: "c1", Phase: "resources", Error: "apply failed"}
+
+	n.RecordFailure(ctx, failure)
+	n.Close()
+	assert.Empty(t, slack.received(), "below threshold")
+
+	n.RecordFailure(ctx, failure)
+	n.RecordFailure(ctx, failure) // deduplicated within min_interval
+	n.Close()
+	require.Len(t, slack.received(), 1)
+	assert.Equal(t,
+		"[test-adapter] Cluster c1 failed 2 consecutive times in phase resources: apply failed",
+		slack.received()[0]["text"])
+	require.Len(t, webhook.received(), 1)
+	assert.Equal(t, KindExecutionFailed, webhook.received()[0]["kind"])
+	assert.Equal(t, "c1 x2", webhook.received()[0]["message"])
+	assert.Equal(t, float64(2), webhook.received()[0]["failures"])
+
+	// Once min_interval has passed the ongoing failure is reported again
+	now = now.Add(time.Minute)
+	n.RecordFailure(ctx, failure)
+	n.Close()
+	assert.Len(t, slack.received(), 2)
+
+	// A success resets the consecutive count
+	now = now.Add(time.Minute)
+	n.RecordSuccess("Cluster", "c1")
+	n.RecordFailure(ctx, failure)
+	n.Close()
+	assert.Len(t, slack.received(), 2)
+}
+
+func TestNotifier_Degraded(t *testing.T) {
+	webhook := newWebhookRecorder(t)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := newTestNotifier([]configloader.NotificationTarget{
+		{Name: "hook", Type: configloader.NotificationTargetWebhook, URL: webhook.server.URL,
+			Headers: map[string]string{"X-Token": "secret"}},
+	}, &now)
+	ctx := context.Background()
+
+	n.SetDegraded(ctx, false, "broker down")
+	n.SetDegraded(ctx, true, "broker down")
+	n.SetDegraded(ctx, true, "broker down") // still degraded, no new notification
+	n.Close()
+	require.Len(t, webhook.received(), 1)
+	assert.Equal(t, KindDegraded, webhook.received()[0]["kind"])
+	assert.Equal(t, "[test-adapter] adapter is degraded: broker down", webhook.received()[0]["message"])
+
+	// Flapping within min_interval is rate-limited
+	n.SetDegraded(ctx, false, "broker down")
+	n.SetDegraded(ctx, true, "broker down")
+	n.Close()
+	assert.Len(t, webhook.received(), 1)
+}
+
+func TestNotifier_WebhookErrorIsNotFatal(t *testing.T) {
+	webhook := newWebhookRecorder(t)
+	webhook.status = http.StatusInternalServerError
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	n := newTestNotifier([]configloader.NotificationTarget{
+		{Name: "hook", Type: configloader.NotificationTargetWebhook, URL: webhook.server.URL,
+			Headers: map[string]string{"X-Token": "secret"}},
+	}, &now)
+
+	n.SetDegraded(context.Background(), true, "broker down")
+	n.Close()
+	assert.Len(t, webhook.received(), 1)
+}
@@ -0,0 +1,142 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WrapAPIClient returns a client that records the responses of c into the session of
+// the calling execution. Calls outside a recorded execution are passed through.
+func WrapAPIClient(c hyperfleetapi.Client) hyperfleetapi.Client {
+	return &apiClient{Client: c}
+}
+
+type apiClient struct {
+	hyperfleetapi.Client
+}
+
+func (c *apiClient) record(
+	ctx context.Context,
+	method, url string,
+	resp *hyperfleetapi.Response,
+	err error,
+) (*hyperfleetapi.Response, error) {
+	if s := sessionFrom(ctx); s != nil {
+		s.recordResponse(method, url, resp, err)
+	}
+	return resp, err
+}
+
+func (c *apiClient) Do(ctx context.Context, req *hyperfleetapi.Request) (*hyperfleetapi.Response, error) {
+	resp, err := c.Client.Do(ctx, req)
+	return c.record(ctx, req.Method, req.URL, resp, err)
+}
+
+func (c *apiClient) Get(
+	ctx context.Context,
+	url string,
+	opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	resp, err := c.Client.Get(ctx, url, opts...)
+	return c.record(ctx, http.MethodGet, url, resp, err)
+}
+
+func (c *apiClient) Post(
+	ctx context.Context,
+	url string,
+	body []byte,
+	opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	resp, err := c.Client.Post(ctx, url, body, opts...)
+	return c.record(ctx, http.MethodPost, url, resp, err)
+}
+
+func (c *apiClient) Put(
+	ctx context.Context,
+	url string,
+	body []byte,
+	opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	resp, err := c.Client.Put(ctx, url, body, opts...)
+	return c.record(ctx, http.MethodPut, url, resp, err)
+}
+
+func (c *apiClient) Patch(
+	ctx context.Context,
+	url string,
+	body []byte,
+	opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	resp, err := c.Client.Patch(ctx, url, body, opts...)
+	return c.record(ctx, http.MethodPatch, url, resp, err)
+}
+
+func (c *apiClient) Delete(
+	ctx context.Context,
+	url string,
+	opts ...hyperfleetapi.RequestOption,
+) (*hyperfleetapi.Response, error) {
+	resp, err := c.Client.Delete(ctx, url, opts...)
+	return c.record(ctx, http.MethodDelete, url, resp, err)
+}
+
+// WrapTransportClient returns a transport client that records the objects read through
+// tc (get and discover) into the session of the calling execution. Writes are not
+// recorded: on replay they are rendered again from the event and the recorded responses.
+func WrapTransportClient(tc transportclient.TransportClient) transportclient.TransportClient {
+	return &transportClient{TransportClient: tc}
+}
+
+type transportClient struct {
+	transportclient.TransportClient
+}
+
+func (c *transportClient) GetResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	target transportclient.TransportContext,
+) (*unstructured.Unstructured, error) {
+	obj, err := c.TransportClient.GetResource(ctx, gvk, namespace, name, target)
+	if s := sessionFrom(ctx); s != nil && err == nil {
+		s.recordObject(obj)
+	}
+	return obj, err
+}
+
+func (c *transportClient) DiscoverResources(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	discovery manifest.Discovery,
+	target transportclient.TransportContext,
+) (*unstructured.UnstructuredList, error) {
+	list, err := c.TransportClient.DiscoverResources(ctx, gvk, discovery, target)
+	if s := sessionFrom(ctx); s != nil && err == nil && list != nil {
+		for i := range list.Items {
+			s.recordObject(&list.Items[i])
+		}
+	}
+	return list, err
+}
+
+// UpdateResource passes updates through to clients that support them, such as the
+// kubernetes client removing adapter-managed finalizers
+func (c *transportClient) UpdateResource(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	updater, ok := c.TransportClient.(interface {
+		UpdateResource(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("transport client cannot update resources")
+	}
+	return updater.UpdateResource(ctx, obj)
+}
@@ -0,0 +1,196 @@
+// Package replay records failed executions of a serving adapter and loads them back for
+// `adapter replay`, which runs them again through the dry-run clients. A recording holds
+// the incoming event, every HyperFleet API response and the objects read from the
+// transport, in the file formats of the dry-run flags. Secret data and the configured
+// sensitive fields are redacted before a recording is written, but recordings still hold
+// the API response bodies and objects of an execution and must be kept private.
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// Files of a recording directory
+const (
+	// EventFile is the CloudEvent that was executed
+	EventFile = "event.json"
+	// APIResponsesFile holds the API responses in the --dry-run-api-responses format
+	APIResponsesFile = "api-responses.json"
+	// DiscoveryFile holds the objects read in the --dry-run-discovery format
+	DiscoveryFile = "discovery.json"
+	// ResultFile holds the ResultRecord of the recorded execution
+	ResultFile = "result.json"
+)
+
+// DefaultMaxRecordings is the number of recordings kept when max_recordings is not set
+const DefaultMaxRecordings = 100
+
+// ResultRecord describes how a recorded execution failed
+type ResultRecord struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	EventID    string    `json:"event_id"`
+	Errors     []string  `json:"errors,omitempty"`
+}
+
+// Recorder writes the recordings of failed executions to a directory
+type Recorder struct {
+	log           logger.Logger
+	redactor      *redactor
+	dir           string
+	maxRecordings int
+}
+
+// NewRecorder creates the recorder configured by cfg. It returns nil when recording is
+// disabled.
+func NewRecorder(cfg configloader.ExecutionRecordingConfig, log logger.Logger) (*Recorder, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create execution recording directory: %w", err)
+	}
+	maxRecordings := cfg.MaxRecordings
+	if maxRecordings <= 0 {
+		maxRecordings = DefaultMaxRecordings
+	}
+	return &Recorder{
+		log:           log,
+		redactor:      newRedactor(cfg.RedactFields),
+		dir:           cfg.Dir,
+		maxRecordings: maxRecordings,
+	}, nil
+}
+
+// WithRecorder wraps a HandlerFunc to record the API responses and transport reads of
+// each execution, and to save them with the event when the execution fails. The clients
+// given to the executor must be wrapped with WrapAPIClient and WrapTransportClient.
+// Saving is best-effort and never changes the returned result. If r is nil, the handler
+// is returned unwrapped.
+func WithRecorder(h executor.HandlerFunc, r *Recorder) executor.HandlerFunc {
+	if r == nil {
+		return h
+	}
+	return func(ctx context.Context, evt *event.Event) (*executor.ExecutionResult, error) {
+		recordCtx, s := withSession(ctx)
+		result, err := h(recordCtx, evt)
+		if err != nil || result == nil || result.Status == executor.StatusFailed {
+			if dir, saveErr := r.save(ctx, evt, result, err, s); saveErr != nil {
+				errCtx := logger.WithErrorField(ctx, saveErr)
+				r.log.Errorf(errCtx, "Failed to record failed execution of event %s", evt.ID())
+			} else {
+				r.log.Infof(ctx, "Recorded failed execution of event %s to %s", evt.ID(), dir)
+			}
+		}
+		return result, err
+	}
+}
+
+// save writes the recording of a failed execution, redacted, and removes the oldest
+// recordings beyond the limit. The recording is written to a temporary directory and renamed, so
+// that a partial recording is never replayed.
+func (r *Recorder) save(
+	ctx context.Context,
+	evt *event.Event,
+	result *executor.ExecutionResult,
+	handlerErr error,
+	s *session,
+) (string, error) {
+	record := ResultRecord{RecordedAt: time.Now().UTC(), EventID: evt.ID()}
+	if handlerErr != nil {
+		record.Errors = append(record.Errors, handlerErr.Error())
+	}
+	if result != nil {
+		for _, execErr := range result.Errors {
+			record.Errors = append(record.Errors, execErr.Error())
+		}
+	}
+	responses, discovery := s.snapshot()
+
+	files := map[string]interface{}{
+		EventFile:        r.redactor.event(evt),
+		APIResponsesFile: r.redactor.responses(responses),
+		DiscoveryFile:    r.redactor.discovery(discovery),
+		ResultFile:       record,
+	}
+
+	tmp, err := os.MkdirTemp(r.dir, ".recording-")
+	if err != nil {
+		return "", err
+	}
+
+	name := record.RecordedAt.Format("20060102T150405.000000000Z") + "-" + recordingName(evt.ID())
+	dir := filepath.Join(r.dir, name)
+	if err = writeFiles(tmp, files); err == nil {
+		err = os.Rename(tmp, dir)
+	}
+	if err != nil {
+		return "", errors.Join(err, os.RemoveAll(tmp))
+	}
+	if err = r.prune(); err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		r.log.Warnf(errCtx, "Failed to remove old execution recordings from %s", r.dir)
+	}
+	return dir, nil
+}
+
+// writeFiles writes each of files to dir as indented JSON
+func writeFiles(dir string, files map[string]interface{}) error {
+	for name, content := range files {
+		data, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// recordingName turns an event ID into a safe directory name part
+func recordingName(eventID string) string {
+	name := unsafeNameChars.ReplaceAllString(eventID, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// prune removes the oldest recordings beyond maxRecordings. Recording names start with
+// their time, so the oldest sort first.
+func (r *Recorder) prune() error {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	for len(names) > r.maxRecordings {
+		if err := os.RemoveAll(filepath.Join(r.dir, names[0])); err != nil {
+			return err
+		}
+		names = names[1:]
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dryrun"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/executor"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func newTestEvent(t *testing.T, id string) *event.Event {
+	t.Helper()
+	evt := event.New()
+	evt.SetID(id)
+	evt.SetType("com.redhat.hyperfleet.cluster.updated")
+	evt.SetSource("test")
+	require.NoError(t, evt.SetData(event.ApplicationJSON, map[string]interface{}{"id": "cluster-1"}))
+	return &evt
+}
+
+func newTestRecorder(t *testing.T, maxRecordings int) (*Recorder, string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "recordings")
+	r, err := NewRecorder(configloader.ExecutionRecordingConfig{
+		Enabled:       true,
+		Dir:           dir,
+		MaxRecordings: maxRecordings,
+	}, logger.NewTestLogger())
+	require.NoError(t, err)
+	return r, dir
+}
+
+func recordings(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestNewRecorder_Disabled(t *testing.T) {
+	r, err := NewRecorder(configloader.ExecutionRecordingConfig{}, logger.NewTestLogger())
+	require.NoError(t, err)
+	assert.Nil(t, r)
+
+	called := false
+	h := func(ctx context.Context, evt *event.Event) (*executor.ExecutionResult, error) {
+		called = true
+		return &executor.ExecutionResult{Status: executor.StatusFailed}, nil
+	}
+	_, _ = WithRecorder(h, nil)(context.Background(), newTestEvent(t, "evt-1"))
+	assert.True(t, called)
+}
+
+func TestWithRecorder_RecordsFailedExecution(t *testing.T) {
+	r, dir := newTestRecorder(t, 0)
+
+	api := hyperfleetapi.NewMockClient()
+	api.GetResponse = &hyperfleetapi.Response{StatusCode: 200, Body: []byte(`{"phase":"Ready"}`)}
+	api.PostResponse = &hyperfleetapi.Response{StatusCode: 503, Body: []byte("unavailable")}
+	apiClient := WrapAPIClient(api)
+
+	k8s := k8sclient.NewMockK8sClient()
+	k8s.GetResourceResult = &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Namespace",
+		"metadata":   map[string]interface{}{"name": "cluster-1"},
+		"status":     map[string]interface{}{"phase": "Terminating"},
+	}}
+	tc := WrapTransportClient(k8s)
+	nsGVK := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+	h := func(ctx context.Context, evt *event.Event) (*executor.ExecutionResult, error) {
+		_, _ = apiClient.Get(ctx, "/clusters/cluster-1")
+		_, _ = apiClient.Get(ctx, "/clusters/cluster-1")
+		_, _ = apiClient.Post(ctx, "/clusters/cluster-1/statuses", []byte(`{}`))
+		_, _ = tc.GetResource(ctx, nsGVK, "", "cluster-1", nil)
+		_, _ = tc.DiscoverResources(ctx, nsGVK, &manifest.DiscoveryConfig{ByName: "cluster-1"}, nil)
+		return &executor.ExecutionResult{
+			Status: executor.StatusFailed,
+			Errors: executor.ExecutionErrors{
+				{Phase: executor.PhasePostActions, Step: "reportStatus", Message: "API returned 503"},
+			},
+		}, nil
+	}
+
+	result, err := WithRecorder(h, r)(context.Background(), newTestEvent(t, "evt/1"))
+	require.NoError(t, err)
+	assert.Equal(t, executor.StatusFailed, result.Status)
+
+	names := recordings(t, dir)
+	require.Len(t, names, 1)
+	assert.Contains(t, names[0], "-evt_1")
+
+	rec, err := Load(filepath.Join(dir, names[0]))
+	require.NoError(t, err)
+	assert.Equal(t, "evt/1", rec.Event.ID())
+	assert.Equal(t, "evt/1", rec.Result.EventID)
+	require.Len(t, rec.Result.Errors, 1)
+	assert.Contains(t, rec.Result.Errors[0], "API returned 503")
+	require.Contains(t, rec.Discovery, "cluster-1")
+	assert.Equal(t, map[string]interface{}{"phase": "Terminating"}, rec.Discovery["cluster-1"]["status"])
+
+	// The recorded responses are served back in order by the dry-run client
+	replayAPI, err := dryrun.NewDryrunAPIClient(rec.Responses)
+	require.NoError(t, err)
+	ctx := context.Background()
+	resp, err := replayAPI.Get(ctx, "/clusters/cluster-1")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"phase":"Ready"}`, string(resp.Body))
+	resp, err = replayAPI.Post(ctx, "/clusters/cluster-1/statuses", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+	assert.Equal(t, `"unavailable"`, string(resp.Body))
+
+	// Other URLs are not matched by the recorded exact-URL patterns
+	resp, err = replayAPI.Get(ctx, "/clusters/cluster-12")
+	require.NoError(t, err)
+	assert.Equal(t, "{}", string(resp.Body))
+}
+
+func TestWithRecorder_RecordsRequestErrors(t *testing.T) {
+	r, dir := newTestRecorder(t, 0)
+
+	api := hyperfleetapi.NewMockClient()
+	api.GetError = errors.New("connection refused")
+	apiClient := WrapAPIClient(api)
+
+	h := func(ctx context.Context, evt *event.Event) (*executor.ExecutionResult, error) {
+		_, err := apiClient.Get(ctx, "/clusters/cluster-1")
+		return &executor.ExecutionResult{Status: executor.StatusFailed}, err
+	}
+	_, err := WithRecorder(h, r)(context.Background(), newTestEvent(t, "evt-1"))
+	require.Error(t, err)
+
+	names := recordings(t, dir)
+	require.Len(t, names, 1)
+	rec, err := Load(filepath.Join(dir, names[0]))
+	require.NoError(t, err)
+	require.Len(t, rec.Responses.Responses, 1)
+	assert.Equal(t, "connection refused", rec.Responses.Responses[0].Responses[0].Error)
+	assert.Equal(t, []string{"connection refused"}, rec.Result.Errors)
+}
+
+func TestWithRecorder_RedactsSensitiveValues(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "recordings")
+	r, err := NewRecorder(configloader.ExecutionRecordingConfig{
+		Enabled:      true,
+		Dir:          dir,
+		RedactFields: []string{"password", "Kubeconfig"},
+	}, logger.NewTestLogger())
+	require.NoError(t, err)
+
+	api := hyperfleetapi.NewMockClient()
+	api.GetResponse = &hyperfleetapi.Response{
+		StatusCode: 200,
+		Body:       []byte(`{"id":"cluster-1","spec":{"kubeconfig":"apiVersion: v1","owner":"team-a"}}`),
+	}
+	apiClient := WrapAPIClient(api)
+
+	k8s := k8sclient.NewMockK8sClient()
+	k8s.GetResourceResult = &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "creds"},
+		"data":       map[string]interface{}{"token": "c2VjcmV0"},
+		"stringData": map[string]interface{}{"password": "hunter2"},
+	}}
+	tc := WrapTransportClient(k8s)
+
+	evt := event.New()
+	evt.SetID("evt-1")
+	evt.SetType("com.redhat.hyperfleet.cluster.updated")
+	evt.SetSource("test")
+	require.NoError(t, evt.SetData(event.ApplicationJSON, map[string]interface{}{
+		"id":          "cluster-1",
+		"credentials": map[string]interface{}{"PASSWORD": "hunter2", "user": "admin"},
+	}))
+
+	h := func(ctx context.Context, evt *event.Event) (*executor.ExecutionResult, error) {
+		_, _ = apiClient.Get(ctx, "/clusters/cluster-1")
+		_, _ = tc.GetResource(ctx, schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, "ns", "creds", nil)
+		return &executor.ExecutionResult{Status: executor.StatusFailed}, nil
+	}
+	_, err = WithRecorder(h, r)(context.Background(), &evt)
+	require.NoError(t, err)
+
+	names := recordings(t, dir)
+	require.Len(t, names, 1)
+	recordingDir := filepath.Join(dir, names[0])
+	for _, file := range []string{EventFile, APIResponsesFile, DiscoveryFile} {
+		data, readErr := os.ReadFile(filepath.Join(recordingDir, file))
+		require.NoError(t, readErr)
+		assert.NotContains(t, string(data), "hunter2", file)
+		assert.NotContains(t, string(data), "c2VjcmV0", file)
+		assert.NotContains(t, string(data), "apiVersion: v1", file)
+	}
+
+	rec, err := Load(recordingDir)
+	require.NoError(t, err)
+	var data map[string]interface{}
+	require.NoError(t, rec.Event.DataAs(&data))
+	assert.Equal(t, map[string]interface{}{"PASSWORD": redactedValue, "user": "admin"}, data["credentials"])
+	assert.Equal(t, map[string]interface{}{"token": redactedValue}, rec.Discovery["creds"]["data"],
+		"Secret data keeps its keys")
+	body := rec.Responses.Responses[0].Responses[0].Body.(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"kubeconfig": redactedValue, "owner": "team-a"}, body["spec"])
+	require.NoError(t, evt.DataAs(&data), "the executed event is not modified")
+	assert.Equal(t, "hunter2", data["credentials"].(map[string]interface{})["PASSWORD"])
+}
+
+func TestWithRecorder_SkipsSuccessfulExecutions(t *testing.T) {
+	r, dir := newTestRecorder(t, 0)
+	apiClient := WrapAPIClient(hyperfleetapi.NewMockClient())
+
+	h := func(ctx context.Context, evt *event.Event) (*executor.ExecutionResult, error) {
+		_, _ = apiClient.Get(ctx, "/clusters/cluster-1")
+		return &executor.ExecutionResult{Status: executor.StatusSuccess}, nil
+	}
+	_, err := WithRecorder(h, r)(context.Background(), newTestEvent(t, "evt-1"))
+	require.NoError(t, err)
+	assert.Empty(t, recordings(t, dir))
+}
+
+func TestWithRecorder_KeepsMaxRecordings(t *testing.T) {
+	r, dir := newTestRecorder(t, 2)
+	h := func(ctx context.Context, evt *event.Event) (*executor.ExecutionResult, error) {
+		return &executor.ExecutionResult{Status: executor.StatusFailed}, nil
+	}
+
+	for _, id := range []string{"evt-1", "evt-2", "evt-3"} {
+		_, _ = WithRecorder(h, r)(context.Background(), newTestEvent(t, id))
+	}
+
+	names := recordings(t, dir)
+	require.Len(t, names, 2)
+	assert.Contains(t, names[0], "evt-2")
+	assert.Contains(t, names[1], "evt-3")
+}
+
+func TestWrapAPIClient_PassesThroughOutsideRecording(t *testing.T) {
+	api := hyperfleetapi.NewMockClient()
+	resp, err := WrapAPIClient(api).Get(context.Background(), "/clusters")
+	require.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Len(t, api.Requests, 1)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+
+	dir := t.TempDir()
+	_, err = Load(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), EventFile)
+}
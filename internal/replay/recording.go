@@ -0,0 +1,53 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dryrun"
+)
+
+// Recording is a recorded failed execution, loaded for replay
+type Recording struct {
+	Event     *event.Event
+	Responses *dryrun.DryrunResponsesFile
+	Discovery dryrun.DiscoveryOverrides
+	Result    ResultRecord
+}
+
+// Load reads the recording in dir
+func Load(dir string) (*Recording, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("recording %q is not a directory", dir)
+	}
+
+	evt, err := dryrun.LoadCloudEvent(filepath.Join(dir, EventFile))
+	if err != nil {
+		return nil, err
+	}
+	responses, err := dryrun.LoadDryrunResponses(filepath.Join(dir, APIResponsesFile))
+	if err != nil {
+		return nil, err
+	}
+	discovery, err := dryrun.LoadDiscoveryOverrides(filepath.Join(dir, DiscoveryFile))
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Recording{Event: evt, Responses: responses, Discovery: discovery}
+	data, err := os.ReadFile(filepath.Clean(filepath.Join(dir, ResultFile)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording result: %w", err)
+	}
+	if err := json.Unmarshal(data, &rec.Result); err != nil {
+		return nil, fmt.Errorf("failed to parse recording result: %w", err)
+	}
+	return rec, nil
+}
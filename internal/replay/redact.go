@@ -0,0 +1,118 @@
+package replay
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dryrun"
+)
+
+// redactedValue replaces the sensitive values of a recording
+const redactedValue = "**REDACTED**"
+
+// secretDataFields are the fields of a Secret whose values are always redacted
+var secretDataFields = map[string]bool{
+	"data":       true,
+	"stringData": true,
+}
+
+// redactor replaces sensitive values before a recording is written: the data and
+// stringData values of every Secret, wherever it appears, and the values of the
+// configured field names, matched case-insensitively at any depth
+type redactor struct {
+	fields map[string]bool
+}
+
+func newRedactor(fields []string) *redactor {
+	r := &redactor{fields: make(map[string]bool, len(fields))}
+	for _, field := range fields {
+		r.fields[strings.ToLower(field)] = true
+	}
+	return r
+}
+
+// event returns a copy of evt with its JSON data redacted. Data that is not JSON is
+// kept as is.
+func (r *redactor) event(evt *event.Event) *event.Event {
+	redacted := evt.Clone()
+	var data interface{}
+	if len(evt.Data()) == 0 || json.Unmarshal(evt.Data(), &data) != nil {
+		return &redacted
+	}
+	if err := redacted.SetData(evt.DataContentType(), r.value(data)); err != nil {
+		// A value decoded from JSON always encodes again; keep the event without data
+		// rather than write it unredacted
+		redacted.DataEncoded = nil
+	}
+	return &redacted
+}
+
+// responses returns a copy of file with the response bodies redacted
+func (r *redactor) responses(file *dryrun.DryrunResponsesFile) *dryrun.DryrunResponsesFile {
+	redacted := &dryrun.DryrunResponsesFile{Responses: make([]dryrun.DryrunEndpoint, len(file.Responses))}
+	for i, endpoint := range file.Responses {
+		responses := make([]dryrun.DryrunResponse, len(endpoint.Responses))
+		for j, resp := range endpoint.Responses {
+			resp.Body = r.value(resp.Body)
+			responses[j] = resp
+		}
+		redacted.Responses[i] = dryrun.DryrunEndpoint{Match: endpoint.Match, Responses: responses}
+	}
+	return redacted
+}
+
+// discovery returns a copy of objects with their sensitive values redacted
+func (r *redactor) discovery(objects dryrun.DiscoveryOverrides) dryrun.DiscoveryOverrides {
+	redacted := make(dryrun.DiscoveryOverrides, len(objects))
+	for name, obj := range objects {
+		if m, ok := r.value(obj).(map[string]interface{}); ok {
+			redacted[name] = m
+		}
+	}
+	return redacted
+}
+
+// value returns a copy of v with its sensitive values redacted
+func (r *redactor) value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		secret := v["apiVersion"] == "v1" && v["kind"] == "Secret"
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			switch {
+			case r.fields[strings.ToLower(key)] && value != nil:
+				out[key] = redactedValue
+			case secret && secretDataFields[key]:
+				out[key] = redactKeys(value)
+			default:
+				out[key] = r.value(value)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = r.value(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// redactKeys replaces every value of a Secret data map, keeping its keys
+func redactKeys(v interface{}) interface{} {
+	data, ok := v.(map[string]interface{})
+	if !ok {
+		if v == nil {
+			return nil
+		}
+		return redactedValue
+	}
+	out := make(map[string]interface{}, len(data))
+	for key := range data {
+		out[key] = redactedValue
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sync"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/dryrun"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/hyperfleetapi"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// session collects what one execution read from the HyperFleet API and the transport
+type session struct {
+	discovery dryrun.DiscoveryOverrides
+	// endpoints maps "METHOD URL" to its index in responses
+	endpoints map[string]int
+	responses []dryrun.DryrunEndpoint
+	mu        sync.Mutex
+}
+
+type sessionKey struct{}
+
+// withSession returns a context whose client calls are recorded into a new session
+func withSession(ctx context.Context) (context.Context, *session) {
+	s := &session{
+		discovery: dryrun.DiscoveryOverrides{},
+		endpoints: map[string]int{},
+	}
+	return context.WithValue(ctx, sessionKey{}, s), s
+}
+
+func sessionFrom(ctx context.Context) *session {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+		return s
+	}
+	return nil
+}
+
+// recordResponse appends the outcome of an API call to the responses of its method and
+// URL, so that the dry-run client returns them in the same order on replay
+func (s *session) recordResponse(method, url string, resp *hyperfleetapi.Response, err error) {
+	var recorded dryrun.DryrunResponse
+	switch {
+	case resp != nil:
+		recorded = dryrun.DryrunResponse{StatusCode: resp.StatusCode, Body: responseBody(resp)}
+	case err != nil:
+		recorded = dryrun.DryrunResponse{Error: err.Error()}
+	default:
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := method + " " + url
+	i, ok := s.endpoints[key]
+	if !ok {
+		i = len(s.responses)
+		s.endpoints[key] = i
+		s.responses = append(s.responses, dryrun.DryrunEndpoint{
+			Match: dryrun.DryrunMatch{Method: method, URLPattern: "^" + regexp.QuoteMeta(url) + "$"},
+		})
+	}
+	s.responses[i].Responses = append(s.responses[i].Responses, recorded)
+}
+
+// responseBody returns the body of resp as JSON data. A body decoded while streaming
+// (response_fields) is recorded as projected; a body that is not JSON is kept as a string.
+func responseBody(resp *hyperfleetapi.Response) interface{} {
+	if resp.Decoded != nil {
+		return resp.Decoded
+	}
+	if len(resp.Body) == 0 {
+		return nil
+	}
+	var body interface{}
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		return string(resp.Body)
+	}
+	return body
+}
+
+// recordObject keeps the last state of obj seen by the execution
+func (s *session) recordObject(obj *unstructured.Unstructured) {
+	if obj == nil || obj.GetName() == "" || obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.discovery[obj.GetName()] = obj.DeepCopy().Object
+}
+
+// snapshot returns the recorded responses and objects
+func (s *session) snapshot() (*dryrun.DryrunResponsesFile, dryrun.DiscoveryOverrides) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	responses := &dryrun.DryrunResponsesFile{Responses: append([]dryrun.DryrunEndpoint{}, s.responses...)}
+	discovery := make(dryrun.DiscoveryOverrides, len(s.discovery))
+	for name, obj := range s.discovery {
+		discovery[name] = obj
+	}
+	return responses, discovery
+}
@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCron_Next(t *testing.T) {
+	// Wednesday
+	base := time.Date(2026, time.January, 14, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		want time.Time
+		expr string
+	}{
+		{time.Date(2026, time.January, 14, 10, 18, 0, 0, time.UTC), "* * * * *"},
+		{time.Date(2026, time.January, 14, 10, 30, 0, 0, time.UTC), "*/15 * * * *"},
+		{time.Date(2026, time.January, 15, 2, 0, 0, 0, time.UTC), "0 2 * * *"},
+		{time.Date(2026, time.January, 14, 13, 30, 0, 0, time.UTC), "30 9-17/4 * * *"},
+		{time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC), "0 0 1,15 * *"},
+		{time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC), "0 0 * * mon-fri"},
+		{time.Date(2026, time.January, 18, 0, 0, 0, 0, time.UTC), "0 0 * * 7"},
+		{time.Date(2026, time.February, 1, 0, 0, 0, 0, time.UTC), "0 0 * feb *"},
+		// Both day fields restricted: either matches
+		{time.Date(2026, time.January, 17, 0, 0, 0, 0, time.UTC), "0 0 20 * sat"},
+		{time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC), "0 0 29 2 *"},
+		{time.Date(2026, time.January, 14, 11, 0, 0, 0, time.UTC), "@hourly"},
+		{time.Date(2026, time.January, 18, 0, 0, 0, 0, time.UTC), "@weekly"},
+		{time.Date(2027, time.January, 1, 0, 0, 0, 0, time.UTC), "@yearly"},
+		{base.Add(90 * time.Minute), "@every 90m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cron.Next(base))
+		})
+	}
+}
+
+func TestCron_NextNeverMatches(t *testing.T) {
+	cron, err := ParseCron("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, cron.Next(time.Now()).IsZero())
+}
+
+func TestCron_NextTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+	cron, err := ParseCron("0 2 * * *")
+	require.NoError(t, err)
+
+	next := cron.Next(time.Date(2026, time.January, 14, 12, 0, 0, 0, time.UTC).In(loc))
+	assert.Equal(t, time.Date(2026, time.January, 15, 2, 0, 0, 0, loc), next)
+	assert.Equal(t, time.Date(2026, time.January, 14, 20, 30, 0, 0, time.UTC), next.UTC())
+}
+
+func TestCron_NextSkipsMissingDSTHour(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	cron, err := ParseCron("30 2 * * *")
+	require.NoError(t, err)
+
+	// 02:30 does not exist on 2026-03-08
+	next := cron.Next(time.Date(2026, time.March, 7, 12, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, time.March, 9, 2, 30, 0, 0, loc), next)
+}
+
+func TestParseCron_Errors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@every 500ms",
+		"@every soon",
+		"@fortnightly",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseCron(expr)
+			assert.Error(t, err)
+		})
+	}
+}
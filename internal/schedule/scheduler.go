@@ -0,0 +1,227 @@
+// Package schedule injects synthetic CloudEvents into the executor on cron schedules, so
+// periodic reconciliation flows such as a nightly status refresh need no external
+// scheduler publishing to the broker.
+//
+// Every replica runs the schedules. With sharding, a scheduled event is processed by the
+// replica owning the resource it references, like a broker event.
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/google/uuid"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// ScheduledEventType is the CloudEvent type of scheduled events that do not set one
+const ScheduledEventType = "com.redhat.hyperfleet.adapter.scheduled"
+
+// Config is a schedule injecting a synthetic CloudEvent into the executor
+type Config struct {
+	// Event is the template of the injected event
+	Event EventTemplate `yaml:"event,omitempty" mapstructure:"event"`
+	// Name identifies the schedule in logs and in the default event source
+	Name string `yaml:"name" mapstructure:"name" validate:"required"`
+	// Cron is a five-field cron expression or a descriptor such as @daily or @every 1h
+	Cron string `yaml:"cron" mapstructure:"cron" validate:"required"`
+	// Timezone is the IANA time zone the cron expression is evaluated in (default UTC)
+	Timezone string `yaml:"timezone,omitempty" mapstructure:"timezone"`
+}
+
+// EventTemplate is the template of a scheduled CloudEvent
+type EventTemplate struct {
+	// Data is the event data. String values are Go templates rendered with .schedule (the
+	// schedule name) and .time (the scheduled time in RFC 3339).
+	Data map[string]interface{} `yaml:"data,omitempty" mapstructure:"data"`
+	// Type is the CloudEvent type (default ScheduledEventType)
+	Type string `yaml:"type,omitempty" mapstructure:"type"`
+	// Source is the CloudEvent source (default hyperfleet-adapter/schedules/<name>)
+	Source string `yaml:"source,omitempty" mapstructure:"source"`
+}
+
+// Location returns the time zone of the schedule
+func (c Config) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// Handler handles an injected event, like the broker subscription handler
+type Handler func(ctx context.Context, evt *event.Event) error
+
+// Scheduler fires the configured schedules. A nil Scheduler does nothing.
+type Scheduler struct {
+	handler Handler
+	log     logger.Logger
+	entries []*entry
+}
+
+type entry struct {
+	cron   *Cron
+	loc    *time.Location
+	next   time.Time
+	config Config
+}
+
+// New creates a scheduler for the schedules, or returns nil when there are none
+func New(schedules []Config, handler Handler, log logger.Logger) (*Scheduler, error) {
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+	s := &Scheduler{handler: handler, log: log}
+	for _, config := range schedules {
+		cron, err := ParseCron(config.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: %w", config.Name, err)
+		}
+		loc, err := config.Location()
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: invalid timezone: %w", config.Name, err)
+		}
+		s.entries = append(s.entries, &entry{config: config, cron: cron, loc: loc})
+	}
+	return s, nil
+}
+
+// Run fires the schedules until ctx is canceled. Activations missed while the adapter
+// was down or busy are not caught up. Events are handled one at a time.
+func (s *Scheduler) Run(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	now := time.Now()
+	for _, e := range s.entries {
+		e.next = e.cron.Next(now.In(e.loc))
+		s.log.Infof(ctx, "Schedule %s (%s) next fires at %s", e.config.Name, e.config.Cron, formatNext(e.next))
+	}
+
+	for {
+		var next time.Time
+		for _, e := range s.entries {
+			if !e.next.IsZero() && (next.IsZero() || e.next.Before(next)) {
+				next = e.next
+			}
+		}
+		if next.IsZero() {
+			s.log.Warn(ctx, "No schedule will fire again, stopping the scheduler")
+			return
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now = time.Now()
+		for _, e := range s.entries {
+			if e.next.IsZero() || e.next.After(now) {
+				continue
+			}
+			s.fire(ctx, e)
+			e.next = e.cron.Next(time.Now().In(e.loc))
+		}
+	}
+}
+
+// fire injects the event of one schedule activation
+func (s *Scheduler) fire(ctx context.Context, e *entry) {
+	evt, err := NewEvent(e.config, e.next)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		s.log.Errorf(errCtx, "Schedule %s: failed to build the scheduled event", e.config.Name)
+		return
+	}
+	evtCtx := logger.WithLogFields(ctx, logger.LogFields{
+		"schedule":   e.config.Name,
+		"event_id":   evt.ID(),
+		"event_type": evt.Type(),
+	})
+	s.log.Infof(evtCtx, "Schedule %s fired, injecting a synthetic event", e.config.Name)
+	if err := s.handler(evtCtx, evt); err != nil {
+		errCtx := logger.WithErrorField(evtCtx, err)
+		s.log.Warnf(errCtx, "Schedule %s: scheduled event was not handled", e.config.Name)
+	}
+}
+
+// NewEvent builds the CloudEvent of a schedule activated at the given time
+func NewEvent(config Config, at time.Time) (*event.Event, error) {
+	vars := map[string]interface{}{
+		"schedule": config.Name,
+		"time":     at.UTC().Format(time.RFC3339),
+	}
+	data, err := renderData(config.Event.Data, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render event data: %w", err)
+	}
+
+	evt := event.New()
+	evt.SetID(uuid.NewString())
+	evt.SetTime(at)
+	evt.SetType(config.Event.Type)
+	if evt.Type() == "" {
+		evt.SetType(ScheduledEventType)
+	}
+	evt.SetSource(config.Event.Source)
+	if evt.Source() == "" {
+		evt.SetSource("hyperfleet-adapter/schedules/" + config.Name)
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	if err := evt.SetData(event.ApplicationJSON, data); err != nil {
+		return nil, fmt.Errorf("failed to set event data: %w", err)
+	}
+	return &evt, nil
+}
+
+// renderData renders the string values of the event data template
+func renderData(value interface{}, vars map[string]interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return nil, nil
+		}
+		rendered := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			r, err := renderData(item, vars)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			rendered[key] = r
+		}
+		return rendered, nil
+	case []interface{}:
+		rendered := make([]interface{}, len(v))
+		for i, item := range v {
+			r, err := renderData(item, vars)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			rendered[i] = r
+		}
+		return rendered, nil
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		return utils.RenderTemplate(v, vars)
+	default:
+		return v, nil
+	}
+}
+
+func formatNext(next time.Time) string {
+	if next.IsZero() {
+		return "never"
+	}
+	return next.Format(time.RFC3339)
+}
@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent(t *testing.T) {
+	at := time.Date(2026, time.January, 14, 2, 0, 0, 0, time.UTC)
+	config := Config{
+		Name: "nightly-refresh",
+		Cron: "0 2 * * *",
+		Event: EventTemplate{
+			Data: map[string]interface{}{
+				"id":         "cluster-1",
+				"kind":       "Cluster",
+				"reason":     "{{ .schedule }} at {{ .time }}",
+				"labels":     []interface{}{"static", "{{ .schedule }}"},
+				"generation": 3,
+			},
+		},
+	}
+
+	evt, err := NewEvent(config, at)
+	require.NoError(t, err)
+	assert.NotEmpty(t, evt.ID())
+	assert.Equal(t, ScheduledEventType, evt.Type())
+	assert.Equal(t, "hyperfleet-adapter/schedules/nightly-refresh", evt.Source())
+	assert.Equal(t, at, evt.Time())
+
+	var data map[string]interface{}
+	require.NoError(t, evt.DataAs(&data))
+	assert.Equal(t, "cluster-1", data["id"])
+	assert.Equal(t, "nightly-refresh at 2026-01-14T02:00:00Z", data["reason"])
+	assert.Equal(t, []interface{}{"static", "nightly-refresh"}, data["labels"])
+	assert.EqualValues(t, 3, data["generation"])
+
+	config.Event.Type = "com.example.refresh"
+	config.Event.Source = "custom"
+	evt, err = NewEvent(config, at)
+	require.NoError(t, err)
+	assert.Equal(t, "com.example.refresh", evt.Type())
+	assert.Equal(t, "custom", evt.Source())
+
+	config.Event.Data = map[string]interface{}{"id": "{{ .missing }}"}
+	_, err = NewEvent(config, at)
+	assert.Error(t, err)
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(nil, nil, logger.NewTestLogger())
+	require.NoError(t, err)
+	assert.Nil(t, s)
+	s.Run(context.Background()) // nil scheduler returns immediately
+
+	_, err = New([]Config{{Name: "bad", Cron: "* * *"}}, nil, logger.NewTestLogger())
+	assert.ErrorContains(t, err, `schedule "bad"`)
+
+	_, err = New([]Config{{Name: "bad", Cron: "@daily", Timezone: "Mars/Olympus"}}, nil, logger.NewTestLogger())
+	assert.ErrorContains(t, err, "invalid timezone")
+}
+
+func TestScheduler_Run(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []*event.Event
+	)
+	handler := func(_ context.Context, evt *event.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, evt)
+		return errors.New("handler errors are logged, not fatal")
+	}
+	s, err := New([]Config{{
+		Name:  "tick",
+		Cron:  "@every 1s",
+		Event: EventTemplate{Data: map[string]interface{}{"id": "{{ .schedule }}"}},
+	}}, handler, logger.NewTestLogger())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) >= 2
+	}, 5*time.Second, 50*time.Millisecond)
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "tick", data["id"])
+	assert.NotEqual(t, events[0].ID(), events[1].ID())
+}
@@ -0,0 +1,256 @@
+// Package sharding splits event ownership between the replicas of a StatefulSet.
+//
+// Every replica receives every event through its own subscription and processes only the
+// events of the resources it owns: a resource belongs to shard hash(key) % count, where the
+// key is the owning resource's ID (so a cluster and its node pools share a shard) or the
+// resource's own ID. The shard index is the pod ordinal and the shard count is the
+// StatefulSet's replica count, re-read periodically so scaling rebalances ownership.
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Environment variables read when sharding is enabled
+const (
+	// EnvShardIndex overrides the shard index derived from the pod ordinal
+	EnvShardIndex = "HYPERFLEET_SHARDING_INDEX"
+	// EnvPodName is the pod name, set from the downward API (falls back to the hostname)
+	EnvPodName = "POD_NAME"
+	// EnvPodNamespace is the pod namespace, set from the downward API (falls back to the
+	// service account namespace)
+	EnvPodNamespace = "POD_NAMESPACE"
+)
+
+// DefaultRefreshInterval is how often the replica count is re-read when not configured
+const DefaultRefreshInterval = 30 * time.Second
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// ReplicaCounter returns the current replica count of the named StatefulSet
+type ReplicaCounter func(ctx context.Context, namespace, name string) (int, error)
+
+// ResourceGetter reads a single Kubernetes object; *k8sclient.Client satisfies it
+type ResourceGetter interface {
+	GetResource(
+		ctx context.Context,
+		gvk schema.GroupVersionKind,
+		namespace, name string,
+		target transportclient.TransportContext,
+	) (*unstructured.Unstructured, error)
+}
+
+var statefulSetGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}
+
+// StatefulSetReplicas returns a ReplicaCounter that reads spec.replicas of the StatefulSet
+// with client. The adapter's service account needs get access to statefulsets.
+func StatefulSetReplicas(client ResourceGetter) ReplicaCounter {
+	return func(ctx context.Context, namespace, name string) (int, error) {
+		obj, err := client.GetResource(ctx, statefulSetGVK, namespace, name, nil)
+		if err != nil {
+			return 0, err
+		}
+		replicas, found, err := unstructured.NestedInt64(obj.Object, "spec", "replicas")
+		if err != nil {
+			return 0, fmt.Errorf("invalid spec.replicas: %w", err)
+		}
+		if !found {
+			// Kubernetes defaults an unset replica count to 1
+			return 1, nil
+		}
+		return int(replicas), nil
+	}
+}
+
+// Sharder decides which events this replica owns. A nil *Sharder owns every event.
+type Sharder struct {
+	counter     ReplicaCounter
+	log         logger.Logger
+	namespace   string
+	statefulSet string
+	count       atomic.Int64
+	index       int
+	refresh     time.Duration
+}
+
+// New creates a Sharder for cfg. It returns nil when sharding is disabled. counter is
+// used to read the shard count when cfg.Count is not set and is called once here, so
+// a StatefulSet that cannot be read fails startup.
+func New(
+	ctx context.Context,
+	cfg configloader.ShardingConfig,
+	counter ReplicaCounter,
+	log logger.Logger,
+) (*Sharder, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	podName := os.Getenv(EnvPodName)
+	if podName == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine pod name: %w", err)
+		}
+		podName = hostname
+	}
+	statefulSet, index, ordinalErr := Ordinal(podName)
+	if env := os.Getenv(EnvShardIndex); env != "" {
+		parsed, err := strconv.Atoi(env)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a non-negative integer", EnvShardIndex, env)
+		}
+		index = parsed
+	} else if ordinalErr != nil {
+		return nil, fmt.Errorf("failed to derive shard index (set %s to override): %w", EnvShardIndex, ordinalErr)
+	}
+	if cfg.StatefulSet != "" {
+		statefulSet = cfg.StatefulSet
+	}
+
+	s := &Sharder{
+		log:         log,
+		statefulSet: statefulSet,
+		index:       index,
+		refresh:     cfg.RefreshInterval.OrDefault(DefaultRefreshInterval),
+	}
+
+	if cfg.Count > 0 {
+		s.count.Store(int64(cfg.Count))
+	} else {
+		if counter == nil {
+			return nil, fmt.Errorf("sharding.count is required when the replica count cannot be read")
+		}
+		if statefulSet == "" {
+			return nil, fmt.Errorf("sharding.statefulset is required when the pod name has no ordinal")
+		}
+		s.counter = counter
+		s.namespace = PodNamespace()
+		count, err := counter(ctx, s.namespace, statefulSet)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read replicas of StatefulSet %s/%s: %w", s.namespace, statefulSet, err)
+		}
+		s.count.Store(int64(count))
+	}
+
+	if count := s.Count(); index >= count {
+		log.Warnf(ctx, "Shard index %d is outside the shard count %d: this replica owns no events", index, count)
+	}
+	return s, nil
+}
+
+// Index returns the shard index of this replica
+func (s *Sharder) Index() int {
+	if s == nil {
+		return 0
+	}
+	return s.index
+}
+
+// Count returns the current number of shards
+func (s *Sharder) Count() int {
+	if s == nil {
+		return 1
+	}
+	return int(s.count.Load())
+}
+
+// Owns reports whether this replica processes events for the resource identified by key.
+// Events without a key are owned by every replica.
+func (s *Sharder) Owns(key string) bool {
+	if s == nil || key == "" {
+		return true
+	}
+	return ShardOf(key, s.Count()) == s.index
+}
+
+// SubscriptionID returns the broker subscription of this replica. Each shard needs its
+// own subscription so that every replica receives every event.
+func (s *Sharder) SubscriptionID(base string) string {
+	if s == nil {
+		return base
+	}
+	return fmt.Sprintf("%s-shard-%d", base, s.index)
+}
+
+// Run re-reads the replica count until ctx is canceled, so ownership follows scaling.
+// It returns immediately when the shard count is fixed by configuration.
+func (s *Sharder) Run(ctx context.Context) {
+	if s == nil || s.counter == nil {
+		return
+	}
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refreshCount(ctx)
+		}
+	}
+}
+
+// refreshCount reads the replica count and logs when ownership changes. Read errors keep
+// the current count.
+func (s *Sharder) refreshCount(ctx context.Context) {
+	count, err := s.counter(ctx, s.namespace, s.statefulSet)
+	if err != nil {
+		errCtx := logger.WithErrorField(ctx, err)
+		s.log.Warnf(errCtx, "Failed to read replicas of StatefulSet %s/%s, keeping %d shards",
+			s.namespace, s.statefulSet, s.Count())
+		return
+	}
+	if previous := s.count.Swap(int64(count)); previous != int64(count) {
+		s.log.Infof(ctx, "StatefulSet %s scaled from %d to %d replicas, rebalancing shard %d",
+			s.statefulSet, previous, count, s.index)
+	}
+}
+
+// ShardOf returns the shard that owns key out of count shards
+func ShardOf(key string, count int) int {
+	if count <= 1 {
+		return 0
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(h.Sum32() % uint32(count)) //nolint:gosec // count is a positive replica count
+}
+
+// Ordinal splits a StatefulSet pod name such as "adapter-2" into the StatefulSet name
+// and the pod ordinal
+func Ordinal(podName string) (string, int, error) {
+	i := strings.LastIndex(podName, "-")
+	if i <= 0 {
+		return "", 0, fmt.Errorf("pod name %q has no StatefulSet ordinal", podName)
+	}
+	ordinal, err := strconv.Atoi(podName[i+1:])
+	if err != nil || ordinal < 0 {
+		return "", 0, fmt.Errorf("pod name %q has no StatefulSet ordinal", podName)
+	}
+	return podName[:i], ordinal, nil
+}
+
+// PodNamespace returns the namespace of the running pod, or "" outside a pod
+func PodNamespace() string {
+	if ns := os.Getenv(EnvPodNamespace); ns != "" {
+		return ns
+	}
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	return ""
+}
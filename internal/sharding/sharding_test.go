@@ -0,0 +1,153 @@
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestOrdinal(t *testing.T) {
+	name, ordinal, err := Ordinal("hyperfleet-adapter-12")
+	require.NoError(t, err)
+	assert.Equal(t, "hyperfleet-adapter", name)
+	assert.Equal(t, 12, ordinal)
+
+	for _, podName := range []string{"adapter", "adapter-7f9c8d", "-1", "adapter-"} {
+		_, _, err := Ordinal(podName)
+		assert.Error(t, err, podName)
+	}
+}
+
+func TestShardOf(t *testing.T) {
+	assert.Equal(t, 0, ShardOf("cluster-1", 0))
+	assert.Equal(t, 0, ShardOf("cluster-1", 1))
+
+	counts := make([]int, 3)
+	for i := 0; i < 300; i++ {
+		shard := ShardOf(fmt.Sprintf("cluster-%d", i), 3)
+		require.GreaterOrEqual(t, shard, 0)
+		require.Less(t, shard, 3)
+		counts[shard]++
+	}
+	for shard, n := range counts {
+		assert.Greater(t, n, 50, "shard %d is underused", shard)
+	}
+	assert.Equal(t, ShardOf("cluster-1", 3), ShardOf("cluster-1", 3), "hashing is stable")
+}
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	log := logger.NewTestLogger()
+
+	t.Run("disabled", func(t *testing.T) {
+		s, err := New(ctx, configloader.ShardingConfig{}, nil, log)
+		require.NoError(t, err)
+		assert.Nil(t, s)
+		assert.True(t, s.Owns("cluster-1"))
+		assert.Equal(t, "sub", s.SubscriptionID("sub"))
+		s.Run(ctx)
+	})
+
+	t.Run("index from pod ordinal, count from StatefulSet", func(t *testing.T) {
+		t.Setenv(EnvPodName, "adapter-1")
+		t.Setenv(EnvPodNamespace, "hyperfleet")
+		var gotNamespace, gotName string
+		counter := func(_ context.Context, namespace, name string) (int, error) {
+			gotNamespace, gotName = namespace, name
+			return 3, nil
+		}
+
+		s, err := New(ctx, configloader.ShardingConfig{Enabled: true}, counter, log)
+		require.NoError(t, err)
+		assert.Equal(t, 1, s.Index())
+		assert.Equal(t, 3, s.Count())
+		assert.Equal(t, "hyperfleet", gotNamespace)
+		assert.Equal(t, "adapter", gotName)
+		assert.Equal(t, "sub-shard-1", s.SubscriptionID("sub"))
+		assert.True(t, s.Owns(""), "events without a resource ID are handled everywhere")
+	})
+
+	t.Run("env and config overrides", func(t *testing.T) {
+		t.Setenv(EnvPodName, "not-a-statefulset-pod")
+		t.Setenv(EnvShardIndex, "2")
+
+		s, err := New(ctx, configloader.ShardingConfig{Enabled: true, Count: 4}, nil, log)
+		require.NoError(t, err)
+		assert.Equal(t, 2, s.Index())
+		assert.Equal(t, 4, s.Count())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Setenv(EnvPodName, "adapter-0")
+		t.Setenv(EnvShardIndex, "-1")
+		_, err := New(ctx, configloader.ShardingConfig{Enabled: true, Count: 2}, nil, log)
+		assert.ErrorContains(t, err, EnvShardIndex)
+
+		t.Setenv(EnvShardIndex, "")
+		_, err = New(ctx, configloader.ShardingConfig{Enabled: true}, nil, log)
+		assert.ErrorContains(t, err, "sharding.count is required")
+
+		failing := func(context.Context, string, string) (int, error) { return 0, errors.New("forbidden") }
+		_, err = New(ctx, configloader.ShardingConfig{Enabled: true}, failing, log)
+		assert.ErrorContains(t, err, "forbidden")
+
+		t.Setenv(EnvPodName, "adapter")
+		_, err = New(ctx, configloader.ShardingConfig{Enabled: true, Count: 2}, nil, log)
+		assert.ErrorContains(t, err, "failed to derive shard index")
+	})
+}
+
+func TestSharder_RefreshCount(t *testing.T) {
+	t.Setenv(EnvPodName, "adapter-0")
+	replicas, fail := 2, false
+	counter := func(context.Context, string, string) (int, error) {
+		if fail {
+			return 0, errors.New("unavailable")
+		}
+		return replicas, nil
+	}
+	s, err := New(context.Background(), configloader.ShardingConfig{Enabled: true}, counter, logger.NewTestLogger())
+	require.NoError(t, err)
+	assert.Equal(t, 2, s.Count())
+
+	replicas = 5
+	s.refreshCount(context.Background())
+	assert.Equal(t, 5, s.Count(), "scaling rebalances ownership")
+
+	fail = true
+	s.refreshCount(context.Background())
+	assert.Equal(t, 5, s.Count(), "read errors keep the current count")
+}
+
+func TestStatefulSetReplicas(t *testing.T) {
+	client := k8sclient.NewMockK8sClient()
+	client.Resources["hyperfleet/adapter"] = &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "StatefulSet",
+		"metadata":   map[string]interface{}{"name": "adapter", "namespace": "hyperfleet"},
+		"spec":       map[string]interface{}{"replicas": int64(4)},
+	}}
+	client.Resources["hyperfleet/defaulted"] = &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "defaulted", "namespace": "hyperfleet"},
+	}}
+	counter := StatefulSetReplicas(client)
+
+	count, err := counter(context.Background(), "hyperfleet", "adapter")
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	count, err = counter(context.Background(), "hyperfleet", "defaulted")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	_, err = counter(context.Background(), "hyperfleet", "missing")
+	assert.Error(t, err)
+}
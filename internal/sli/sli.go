@@ -0,0 +1,521 @@
+// Package sli tracks the service level indicators of processed generations: how long it
+// takes from receiving an event to reporting the status of its resource, by event type and
+// generation freshness. Durations are exposed as Prometheus histograms, and the events of
+// a rolling window are summarized as JSON at /sli to back an SLO such as "cluster state
+// reflected within 5 minutes".
+//
+// The window and the last reported generation of each resource are kept in memory and,
+// when a state path is configured, persisted to a file so that they survive restarts.
+package sli
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Generation freshness of an event, compared to the last generation reported for its resource
+const (
+	// FreshnessNew is a generation newer than the last reported one, or the first one seen
+	FreshnessNew = "new"
+	// FreshnessResync is the generation that was last reported, such as a redelivery or resync
+	FreshnessResync = "resync"
+	// FreshnessStale is a generation older than the last reported one
+	FreshnessStale = "stale"
+)
+
+// Results of an observed event
+const (
+	ResultReported    = "reported"
+	ResultNotReported = "not_reported"
+)
+
+// Defaults for unset SLIConfig fields
+const (
+	DefaultTarget       = 5 * time.Minute
+	DefaultWindow       = time.Hour
+	DefaultMaxResources = 10000
+)
+
+const (
+	// flushInterval is how often the state is persisted when a state path is configured
+	flushInterval = 30 * time.Second
+	// maxWindowEvents bounds the events kept in the window; the oldest are dropped first
+	maxWindowEvents = 50000
+)
+
+// durationBuckets are the histogram buckets in seconds; the target is added to them so the
+// SLI can also be computed in PromQL
+var durationBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 900, 1800, 3600}
+
+// Observation is an event whose execution completed
+type Observation struct {
+	// EventType is the CloudEvent type
+	EventType string
+	// Freshness is one of the Freshness constants, as returned by Tracker.Freshness
+	Freshness string
+	// Resource identifies the resource of the event
+	Resource string
+	// Generation is the generation carried by the event
+	Generation int64
+	// Duration is the time from receiving the event until its status was reported
+	Duration time.Duration
+	// Reported indicates the status of the resource was reported to the HyperFleet API
+	Reported bool
+}
+
+// windowEvent is an observation kept in the window and the persisted state
+type windowEvent struct {
+	At        time.Time `json:"at"`
+	EventType string    `json:"event_type"`
+	Freshness string    `json:"freshness"`
+	Seconds   float64   `json:"seconds"`
+	Reported  bool      `json:"reported"`
+}
+
+// reportedGeneration is the last generation reported for a resource
+type reportedGeneration struct {
+	Resource   string `json:"resource"`
+	Generation int64  `json:"generation"`
+}
+
+// state is the content of the state file
+type state struct {
+	Events      []windowEvent        `json:"events"`
+	Generations []reportedGeneration `json:"generations"`
+}
+
+// Tracker records observations and summarizes them. A nil *Tracker is valid and does
+// nothing.
+type Tracker struct {
+	log             logger.Logger
+	now             func() time.Time
+	duration        *prometheus.HistogramVec
+	reports         *prometheus.CounterVec
+	generations     map[string]*list.Element
+	generationOrder *list.List
+	stop            chan struct{}
+	done            chan struct{}
+	statePath       string
+	events          []windowEvent
+	target          time.Duration
+	window          time.Duration
+	maxResources    int
+	mu              sync.Mutex
+}
+
+// New creates the tracker configured by cfg and registers its metrics with reg
+// (prometheus.DefaultRegisterer when nil). The persisted state is loaded when a state path
+// is configured. It returns nil when the SLIs are disabled.
+func New(
+	cfg configloader.SLIConfig,
+	component, version, adapterName string,
+	reg prometheus.Registerer,
+	log logger.Logger,
+) (*Tracker, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+	t := &Tracker{
+		log:             log,
+		now:             time.Now,
+		generations:     make(map[string]*list.Element),
+		generationOrder: list.New(),
+		statePath:       cfg.StatePath,
+		target:          cfg.Target.OrDefault(DefaultTarget),
+		window:          cfg.Window.OrDefault(DefaultWindow),
+		maxResources:    cfg.MaxResources,
+	}
+	if t.maxResources <= 0 {
+		t.maxResources = DefaultMaxResources
+	}
+
+	constLabels := prometheus.Labels{
+		"component":    component,
+		"version":      version,
+		"adapter_name": adapterName,
+	}
+	t.duration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:        "hyperfleet_adapter_status_report_duration_seconds",
+			Help:        "Duration from receiving an event to reporting the resource status, in seconds",
+			Buckets:     bucketsWithTarget(t.target),
+			ConstLabels: constLabels,
+		},
+		[]string{"event_type", "freshness"},
+	)
+	t.reports = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name:        "hyperfleet_adapter_status_reports_total",
+			Help:        "Total number of processed events, by whether the resource status was reported",
+			ConstLabels: constLabels,
+		},
+		[]string{"event_type", "freshness", "result"},
+	)
+	reg.MustRegister(t.duration)
+	reg.MustRegister(t.reports)
+
+	if t.statePath != "" {
+		if err := t.load(); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// bucketsWithTarget returns durationBuckets with the target added
+func bucketsWithTarget(target time.Duration) []float64 {
+	seconds := target.Seconds()
+	buckets := make([]float64, 0, len(durationBuckets)+1)
+	for _, b := range durationBuckets {
+		if b == seconds {
+			return durationBuckets
+		}
+		buckets = append(buckets, b)
+	}
+	buckets = append(buckets, seconds)
+	sort.Float64s(buckets)
+	return buckets
+}
+
+// Freshness classifies generation against the last generation reported for resource
+func (t *Tracker) Freshness(resource string, generation int64) string {
+	if t == nil {
+		return FreshnessNew
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	elem, ok := t.generations[resource]
+	if !ok {
+		return FreshnessNew
+	}
+	last := generationOf(elem).Generation
+	switch {
+	case generation > last:
+		return FreshnessNew
+	case generation == last:
+		return FreshnessResync
+	default:
+		return FreshnessStale
+	}
+}
+
+// Observe records a completed event
+func (t *Tracker) Observe(o Observation) {
+	if t == nil {
+		return
+	}
+	result := ResultNotReported
+	if o.Reported {
+		result = ResultReported
+		t.duration.WithLabelValues(o.EventType, o.Freshness).Observe(o.Duration.Seconds())
+	}
+	t.reports.WithLabelValues(o.EventType, o.Freshness, result).Inc()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if o.Reported && o.Resource != "" {
+		t.setGeneration(o.Resource, o.Generation)
+	}
+	t.events = append(t.events, windowEvent{
+		At:        t.now(),
+		EventType: o.EventType,
+		Freshness: o.Freshness,
+		Seconds:   o.Duration.Seconds(),
+		Reported:  o.Reported,
+	})
+	t.trim()
+}
+
+// setGeneration records generation as reported for resource, unless a newer one was. The
+// least recently reported resource is evicted when the tracker is full.
+func (t *Tracker) setGeneration(resource string, generation int64) {
+	if elem, ok := t.generations[resource]; ok {
+		entry := generationOf(elem)
+		if generation > entry.Generation {
+			entry.Generation = generation
+		}
+		t.generationOrder.MoveToFront(elem)
+		return
+	}
+	t.generations[resource] = t.generationOrder.PushFront(&reportedGeneration{
+		Resource:   resource,
+		Generation: generation,
+	})
+	for t.generationOrder.Len() > t.maxResources {
+		oldest := t.generationOrder.Back()
+		t.generationOrder.Remove(oldest)
+		delete(t.generations, generationOf(oldest).Resource)
+	}
+}
+
+// generationOf returns the entry held by an element of the generation order list
+func generationOf(elem *list.Element) *reportedGeneration {
+	entry, _ := elem.Value.(*reportedGeneration) //nolint:errcheck // the list only holds *reportedGeneration
+	return entry
+}
+
+// trim drops the events that left the window, and the oldest beyond maxWindowEvents
+func (t *Tracker) trim() {
+	cutoff := t.now().Add(-t.window)
+	i := sort.Search(len(t.events), func(i int) bool {
+		return t.events[i].At.After(cutoff)
+	})
+	if excess := len(t.events) - i - maxWindowEvents; excess > 0 {
+		i += excess
+	}
+	if i > 0 {
+		t.events = append(t.events[:0:0], t.events[i:]...)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Summary
+// -----------------------------------------------------------------------------
+
+// Summary is the JSON document served at /sli
+type Summary struct {
+	Since  time.Time `json:"since"`
+	Target string    `json:"target"`
+	Window string    `json:"window"`
+	// Groups summarize the events by event type and freshness
+	Groups []GroupSummary `json:"groups"`
+	// Total summarizes all the events of the window
+	Total GroupSummary `json:"total"`
+}
+
+// GroupSummary summarizes the events of a window. SLI is the fraction of events whose
+// status was reported within the target, 1 when there were no events. The percentiles are
+// those of the reported events.
+type GroupSummary struct {
+	EventType    string  `json:"event_type,omitempty"`
+	Freshness    string  `json:"freshness,omitempty"`
+	Events       int     `json:"events"`
+	Reported     int     `json:"reported"`
+	WithinTarget int     `json:"within_target"`
+	SLI          float64 `json:"sli"`
+	P50Seconds   float64 `json:"p50_seconds"`
+	P90Seconds   float64 `json:"p90_seconds"`
+	P99Seconds   float64 `json:"p99_seconds"`
+	MaxSeconds   float64 `json:"max_seconds"`
+}
+
+// Summary summarizes the events of the window
+func (t *Tracker) Summary() Summary {
+	if t == nil {
+		return Summary{}
+	}
+	t.mu.Lock()
+	t.trim()
+	events := append([]windowEvent(nil), t.events...)
+	now := t.now()
+	t.mu.Unlock()
+
+	type groupKey struct{ eventType, freshness string }
+	grouped := make(map[groupKey][]windowEvent)
+	for _, e := range events {
+		key := groupKey{e.EventType, e.Freshness}
+		grouped[key] = append(grouped[key], e)
+	}
+
+	s := Summary{
+		Since:  now.Add(-t.window).UTC(),
+		Target: t.target.String(),
+		Window: t.window.String(),
+		Groups: make([]GroupSummary, 0, len(grouped)),
+		Total:  t.summarize(events),
+	}
+	for key, groupEvents := range grouped {
+		g := t.summarize(groupEvents)
+		g.EventType, g.Freshness = key.eventType, key.freshness
+		s.Groups = append(s.Groups, g)
+	}
+	sort.Slice(s.Groups, func(i, j int) bool {
+		if s.Groups[i].EventType != s.Groups[j].EventType {
+			return s.Groups[i].EventType < s.Groups[j].EventType
+		}
+		return s.Groups[i].Freshness < s.Groups[j].Freshness
+	})
+	return s
+}
+
+// summarize computes the counts and percentiles of events
+func (t *Tracker) summarize(events []windowEvent) GroupSummary {
+	g := GroupSummary{Events: len(events), SLI: 1}
+	durations := make([]float64, 0, len(events))
+	for _, e := range events {
+		if !e.Reported {
+			continue
+		}
+		g.Reported++
+		if e.Seconds <= t.target.Seconds() {
+			g.WithinTarget++
+		}
+		durations = append(durations, e.Seconds)
+	}
+	if g.Events > 0 {
+		g.SLI = float64(g.WithinTarget) / float64(g.Events)
+	}
+	if len(durations) > 0 {
+		sort.Float64s(durations)
+		g.P50Seconds = percentile(durations, 0.50)
+		g.P90Seconds = percentile(durations, 0.90)
+		g.P99Seconds = percentile(durations, 0.99)
+		g.MaxSeconds = durations[len(durations)-1]
+	}
+	return g
+}
+
+// percentile returns the nearest-rank percentile p of sorted
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// Handler serves the Summary as JSON
+func (t *Tracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(t.Summary()) //nolint:errcheck // best-effort response
+	})
+}
+
+// -----------------------------------------------------------------------------
+// Persistence
+// -----------------------------------------------------------------------------
+
+// Start persists the state every flushInterval until Close is called. It does nothing when
+// no state path is configured.
+func (t *Tracker) Start(ctx context.Context) {
+	if t == nil || t.statePath == "" {
+		return
+	}
+	t.stop = make(chan struct{})
+	t.done = make(chan struct{})
+	go func() {
+		defer close(t.done)
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := t.Flush(); err != nil {
+					errCtx := logger.WithErrorField(ctx, err)
+					t.log.Warnf(errCtx, "Failed to persist the SLI state to %s", t.statePath)
+				}
+			}
+		}
+	}()
+}
+
+// Close stops the periodic persistence and persists the state a last time
+func (t *Tracker) Close() {
+	if t == nil || t.statePath == "" {
+		return
+	}
+	if t.stop != nil {
+		close(t.stop)
+		<-t.done
+		t.stop = nil
+	}
+	if err := t.Flush(); err != nil {
+		errCtx := logger.WithErrorField(context.Background(), err)
+		t.log.Warnf(errCtx, "Failed to persist the SLI state to %s", t.statePath)
+	}
+}
+
+// Flush writes the state to the state path. The file is written to a temporary file and
+// renamed, so a crash never leaves a partial state.
+func (t *Tracker) Flush() error {
+	if t == nil || t.statePath == "" {
+		return nil
+	}
+	t.mu.Lock()
+	t.trim()
+	st := state{
+		Events:      append([]windowEvent(nil), t.events...),
+		Generations: make([]reportedGeneration, 0, t.generationOrder.Len()),
+	}
+	// Oldest first, so that loading restores the eviction order
+	for elem := t.generationOrder.Back(); elem != nil; elem = elem.Prev() {
+		st.Generations = append(st.Generations, *generationOf(elem))
+	}
+	t.mu.Unlock()
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return fmt.Errorf("failed to encode SLI state: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(t.statePath), ".sli-state-")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), t.statePath)
+	}
+	if err != nil {
+		return errors.Join(err, os.Remove(tmp.Name()))
+	}
+	return nil
+}
+
+// load restores the persisted state. A missing file is an empty state.
+func (t *Tracker) load() error {
+	data, err := os.ReadFile(t.statePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read SLI state: %w", err)
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return fmt.Errorf("failed to parse SLI state %s: %w", t.statePath, err)
+	}
+	sort.SliceStable(st.Events, func(i, j int) bool {
+		return st.Events[i].At.Before(st.Events[j].At)
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = st.Events
+	t.trim()
+	for _, g := range st.Generations {
+		t.setGeneration(g.Resource, g.Generation)
+	}
+	return nil
+}
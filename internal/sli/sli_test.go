@@ -0,0 +1,185 @@
+package sli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTracker(t *testing.T, cfg configloader.SLIConfig) *Tracker {
+	t.Helper()
+	cfg.Enabled = true
+	tracker, err := New(cfg, "test", "v0", "test", prometheus.NewRegistry(), logger.NewTestLogger())
+	require.NoError(t, err)
+	return tracker
+}
+
+func TestNew_Disabled(t *testing.T) {
+	tracker, err := New(configloader.SLIConfig{}, "test", "v0", "test", prometheus.NewRegistry(), logger.NewTestLogger())
+	require.NoError(t, err)
+	assert.Nil(t, tracker)
+
+	// A nil tracker is a no-op
+	assert.Equal(t, FreshnessNew, tracker.Freshness("cluster-1", 1))
+	tracker.Observe(Observation{EventType: "test", Reported: true})
+	assert.Equal(t, Summary{}, tracker.Summary())
+	require.NoError(t, tracker.Flush())
+	tracker.Close()
+}
+
+func TestTracker_Freshness(t *testing.T) {
+	tracker := newTestTracker(t, configloader.SLIConfig{MaxResources: 2})
+
+	assert.Equal(t, FreshnessNew, tracker.Freshness("cluster-1", 3))
+	tracker.Observe(Observation{Resource: "cluster-1", Generation: 3, Reported: true})
+	assert.Equal(t, FreshnessNew, tracker.Freshness("cluster-1", 4))
+	assert.Equal(t, FreshnessResync, tracker.Freshness("cluster-1", 3))
+	assert.Equal(t, FreshnessStale, tracker.Freshness("cluster-1", 2))
+
+	// Events whose status was not reported, and stale reports, keep the last generation
+	tracker.Observe(Observation{Resource: "cluster-1", Generation: 5})
+	tracker.Observe(Observation{Resource: "cluster-1", Generation: 1, Reported: true})
+	assert.Equal(t, FreshnessResync, tracker.Freshness("cluster-1", 3))
+
+	// The least recently reported resource is evicted
+	tracker.Observe(Observation{Resource: "cluster-2", Generation: 1, Reported: true})
+	tracker.Observe(Observation{Resource: "cluster-3", Generation: 1, Reported: true})
+	assert.Equal(t, FreshnessNew, tracker.Freshness("cluster-1", 3))
+	assert.Equal(t, FreshnessResync, tracker.Freshness("cluster-3", 1))
+}
+
+func TestTracker_Summary(t *testing.T) {
+	tracker := newTestTracker(t, configloader.SLIConfig{
+		Target: configloader.Duration(time.Minute),
+		Window: configloader.Duration(time.Hour),
+	})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	for _, seconds := range []int{10, 20, 30, 90} {
+		tracker.Observe(Observation{
+			EventType: "cluster.updated",
+			Freshness: FreshnessNew,
+			Duration:  time.Duration(seconds) * time.Second,
+			Reported:  true,
+		})
+	}
+	tracker.Observe(Observation{EventType: "cluster.updated", Freshness: FreshnessNew, Duration: time.Second})
+	tracker.Observe(Observation{
+		EventType: "nodepool.updated", Freshness: FreshnessResync, Duration: 5 * time.Second, Reported: true,
+	})
+
+	summary := tracker.Summary()
+	assert.Equal(t, "1m0s", summary.Target)
+	assert.Equal(t, "1h0m0s", summary.Window)
+	assert.Equal(t, now.Add(-time.Hour), summary.Since)
+	assert.Equal(t, 6, summary.Total.Events)
+	assert.Equal(t, 5, summary.Total.Reported)
+	assert.Equal(t, 4, summary.Total.WithinTarget)
+
+	require.Len(t, summary.Groups, 2)
+	cluster := summary.Groups[0]
+	assert.Equal(t, "cluster.updated", cluster.EventType)
+	assert.Equal(t, FreshnessNew, cluster.Freshness)
+	assert.Equal(t, 5, cluster.Events)
+	assert.Equal(t, 4, cluster.Reported)
+	assert.Equal(t, 3, cluster.WithinTarget)
+	assert.InDelta(t, 0.6, cluster.SLI, 0.001)
+	assert.Equal(t, 20.0, cluster.P50Seconds)
+	assert.Equal(t, 90.0, cluster.P99Seconds)
+	assert.Equal(t, 90.0, cluster.MaxSeconds)
+	assert.Equal(t, "nodepool.updated", summary.Groups[1].EventType)
+	assert.Equal(t, 1.0, summary.Groups[1].SLI)
+
+	// Events leave the window
+	now = now.Add(2 * time.Hour)
+	summary = tracker.Summary()
+	assert.Equal(t, 0, summary.Total.Events)
+	assert.Equal(t, 1.0, summary.Total.SLI, "the SLI is met when there were no events")
+	assert.Empty(t, summary.Groups)
+}
+
+func TestTracker_Metrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	config := configloader.SLIConfig{Enabled: true, Target: configloader.Duration(45 * time.Second)}
+	tracker, err := New(config, "test", "v0", "test", reg, logger.NewTestLogger())
+	require.NoError(t, err)
+
+	tracker.Observe(Observation{EventType: "cluster.updated", Freshness: FreshnessNew, Duration: 40 * time.Second,
+		Reported: true})
+	tracker.Observe(Observation{EventType: "cluster.updated", Freshness: FreshnessNew})
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(tracker.reports.WithLabelValues(
+		"cluster.updated", FreshnessNew, ResultReported)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(tracker.reports.WithLabelValues(
+		"cluster.updated", FreshnessNew, ResultNotReported)))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	var buckets []float64
+	for _, family := range families {
+		if family.GetName() != "hyperfleet_adapter_status_report_duration_seconds" {
+			continue
+		}
+		for _, b := range family.GetMetric()[0].GetHistogram().GetBucket() {
+			buckets = append(buckets, b.GetUpperBound())
+		}
+	}
+	assert.Contains(t, buckets, 45.0, "the target must be a bucket boundary")
+	assert.Equal(t, durationBuckets, bucketsWithTarget(5*time.Minute))
+}
+
+func TestTracker_PersistsState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sli-state.json")
+	tracker := newTestTracker(t, configloader.SLIConfig{StatePath: path})
+	tracker.Start(t.Context())
+	tracker.Observe(Observation{
+		EventType: "cluster.updated", Freshness: FreshnessNew, Resource: "cluster-1", Generation: 7,
+		Duration: 3 * time.Second, Reported: true,
+	})
+	tracker.Close()
+
+	restarted := newTestTracker(t, configloader.SLIConfig{StatePath: path})
+	assert.Equal(t, FreshnessResync, restarted.Freshness("cluster-1", 7))
+	summary := restarted.Summary()
+	assert.Equal(t, 1, summary.Total.Events)
+	assert.Equal(t, 3.0, summary.Total.MaxSeconds)
+}
+
+func TestNew_InvalidState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sli-state.json")
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err := New(configloader.SLIConfig{Enabled: true, StatePath: path}, "test", "v0", "test",
+		prometheus.NewRegistry(), logger.NewTestLogger())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to parse SLI state")
+}
+
+func TestTracker_Handler(t *testing.T) {
+	tracker := newTestTracker(t, configloader.SLIConfig{})
+	tracker.Observe(Observation{EventType: "cluster.updated", Freshness: FreshnessNew, Duration: time.Second,
+		Reported: true})
+
+	rec := httptest.NewRecorder()
+	tracker.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/sli", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var summary Summary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &summary))
+	assert.Equal(t, 1, summary.Total.Reported)
+
+	rec = httptest.NewRecorder()
+	tracker.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/sli", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
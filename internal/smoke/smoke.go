@@ -0,0 +1,263 @@
+// Package smoke checks that the adapter can manage objects in a new environment without
+// crafting events. It creates, reads, updates and deletes a canary object through a
+// transport client, and reports the latency of each operation and whether it was denied
+// by RBAC.
+package smoke
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/maestroclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/transportclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// Operations of a smoke test, in the order they run
+const (
+	OperationCreate = "create"
+	OperationRead   = "read"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+// Results of an operation
+const (
+	ResultOK        = "ok"
+	ResultForbidden = "forbidden"
+	ResultFailed    = "failed"
+	ResultSkipped   = "skipped"
+)
+
+// LabelSmokeTest marks the canary objects, so leftovers of an interrupted test can be found
+const LabelSmokeTest = "hyperfleet.io/smoke-test"
+
+// manifestWorkGVK is the kind of the ManifestWork wrapping the canary with maestro
+var manifestWorkGVK = schema.GroupVersionKind{
+	Group:   constants.ManifestWorkGroup,
+	Version: constants.ManifestWorkVersion,
+	Kind:    constants.ManifestWorkKind,
+}
+
+// Options configures a smoke test
+type Options struct {
+	// GVK is the kind of the canary object
+	GVK schema.GroupVersionKind
+	// Transport is the transport client the client was created for, kubernetes or maestro
+	Transport string
+	// Namespace of the canary object, empty for a cluster-scoped kind
+	Namespace string
+	// Name of the canary object, and of its ManifestWork with the maestro transport
+	Name string
+	// Consumer is the Maestro consumer (target cluster), required with the maestro transport
+	Consumer string
+}
+
+// Report is the outcome of a smoke test
+type Report struct {
+	Transport  string      `json:"transport"`
+	APIVersion string      `json:"api_version"`
+	Kind       string      `json:"kind"`
+	Namespace  string      `json:"namespace,omitempty"`
+	Name       string      `json:"name"`
+	Consumer   string      `json:"consumer,omitempty"`
+	Operations []Operation `json:"operations"`
+	// Passed is true when every operation succeeded
+	Passed bool `json:"passed"`
+}
+
+// Operation is the outcome of one operation on the canary object
+type Operation struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	// Detail is the apply operation performed, or the error of a failed operation
+	Detail     string `json:"detail,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// ParseGVK parses a kind given as <apiVersion>/<Kind>, e.g. v1/ConfigMap or apps/v1/Deployment
+func ParseGVK(value string) (schema.GroupVersionKind, error) {
+	i := strings.LastIndex(value, "/")
+	if i <= 0 || i == len(value)-1 {
+		return schema.GroupVersionKind{}, fmt.Errorf(
+			"invalid kind %q: expected <apiVersion>/<Kind>, e.g. v1/ConfigMap", value)
+	}
+	gvk := schema.FromAPIVersionAndKind(value[:i], value[i+1:])
+	if gvk.Version == "" {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid kind %q: missing version", value)
+	}
+	return gvk, nil
+}
+
+// Run creates, reads, updates and deletes the canary object through client. The object
+// only sets metadata, so the kind must accept an object without spec (ConfigMap, Secret,
+// ServiceAccount, ...). With the maestro transport the object is wrapped in a ManifestWork,
+// which is what is read and deleted. After a failed create the other operations are
+// skipped; after a failed read or update the object is still deleted.
+func Run(ctx context.Context, client transportclient.TransportClient, opts Options) (*Report, error) {
+	if opts.Name == "" {
+		return nil, errors.New("a canary object name is required")
+	}
+	var target transportclient.TransportContext
+	if opts.Transport == configloader.TransportClientMaestro {
+		if opts.Consumer == "" {
+			return nil, errors.New("a Maestro consumer is required with the maestro transport")
+		}
+		target = &maestroclient.TransportContext{ConsumerName: opts.Consumer}
+	}
+
+	report := &Report{
+		Transport:  opts.Transport,
+		APIVersion: opts.GVK.GroupVersion().String(),
+		Kind:       opts.GVK.Kind,
+		Namespace:  opts.Namespace,
+		Name:       opts.Name,
+		Consumer:   opts.Consumer,
+		Passed:     true,
+	}
+	run := func(name string, op func() (string, error)) bool {
+		start := time.Now()
+		detail, err := op()
+		result := Operation{Name: name, Result: ResultOK, DurationMs: time.Since(start).Milliseconds(), Detail: detail}
+		if err != nil {
+			result.Result = resultOf(err)
+			result.Detail = err.Error()
+			report.Passed = false
+		}
+		report.Operations = append(report.Operations, result)
+		return err == nil
+	}
+	skip := func(names ...string) {
+		for _, name := range names {
+			report.Operations = append(report.Operations, Operation{Name: name, Result: ResultSkipped})
+		}
+		report.Passed = false
+	}
+
+	// The objects read and deleted: the ManifestWork with maestro, the canary otherwise
+	readGVK, readNamespace := opts.GVK, opts.Namespace
+	if target != nil {
+		readGVK = manifestWorkGVK
+		readNamespace = opts.Consumer
+	}
+	apply := func(generation int64) (string, error) {
+		data, err := canaryManifest(opts, generation, target != nil)
+		if err != nil {
+			return "", err
+		}
+		result, err := client.ApplyResource(ctx, data, nil, target)
+		if err != nil {
+			return "", err
+		}
+		return string(result.Operation), nil
+	}
+
+	if !run(OperationCreate, func() (string, error) { return apply(1) }) {
+		skip(OperationRead, OperationUpdate, OperationDelete)
+		return report, nil
+	}
+	run(OperationRead, func() (string, error) {
+		_, err := client.GetResource(ctx, readGVK, readNamespace, opts.Name, target)
+		return "", err
+	})
+	run(OperationUpdate, func() (string, error) { return apply(2) })
+	run(OperationDelete, func() (string, error) {
+		return "", client.DeleteResource(ctx, readGVK, readNamespace, opts.Name, nil, target)
+	})
+	return report, nil
+}
+
+// canaryManifest renders the canary object at generation, wrapped in a ManifestWork when
+// manifestWork is set
+func canaryManifest(opts Options, generation int64, manifestWork bool) ([]byte, error) {
+	annotations := map[string]interface{}{
+		constants.AnnotationGeneration: strconv.FormatInt(generation, 10),
+	}
+	metadata := map[string]interface{}{
+		"name":        opts.Name,
+		"labels":      map[string]interface{}{LabelSmokeTest: "true"},
+		"annotations": annotations,
+	}
+	if opts.Namespace != "" {
+		metadata["namespace"] = opts.Namespace
+	}
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{"metadata": metadata}}
+	obj.SetGroupVersionKind(opts.GVK)
+	if !manifestWork {
+		return json.Marshal(obj.Object)
+	}
+
+	work := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":        opts.Name,
+			"labels":      map[string]interface{}{LabelSmokeTest: "true"},
+			"annotations": map[string]interface{}{constants.AnnotationGeneration: strconv.FormatInt(generation, 10)},
+		},
+		"spec": map[string]interface{}{
+			"workload": map[string]interface{}{"manifests": []interface{}{obj.Object}},
+		},
+	}}
+	work.SetGroupVersionKind(manifestWorkGVK)
+	return json.Marshal(work.Object)
+}
+
+// resultOf classifies the error of an operation: denied by Kubernetes RBAC or Maestro
+// authorization, or failed for another reason
+func resultOf(err error) string {
+	if k8serrors.IsForbidden(err) || k8serrors.IsUnauthorized(err) {
+		return ResultForbidden
+	}
+	if s, ok := status.FromError(err); ok && (s.Code() == codes.PermissionDenied || s.Code() == codes.Unauthenticated) {
+		return ResultForbidden
+	}
+	return ResultFailed
+}
+
+// WriteText writes the report as a table of the operations
+func (r *Report) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	out := &utils.ErrWriter{W: tw}
+
+	target := r.Name
+	if r.Namespace != "" {
+		target = r.Namespace + "/" + r.Name
+	}
+	out.Printf("Smoke test: %s %s/%s %s", r.Transport, r.APIVersion, r.Kind, target)
+	if r.Consumer != "" {
+		out.Printf(" consumer=%s", r.Consumer)
+	}
+	out.Printf("\n\n")
+
+	for _, op := range r.Operations {
+		duration := "-"
+		if op.Result != ResultSkipped {
+			duration = fmt.Sprintf("%dms", op.DurationMs)
+		}
+		out.Printf("  %s\t%s\t%s\t%s\n", op.Name, op.Result, duration, op.Detail)
+	}
+
+	if r.Passed {
+		out.Printf("\nSmoke test passed\n")
+	} else {
+		out.Printf("\nSmoke test failed\n")
+	}
+	if out.Err != nil {
+		return out.Err
+	}
+	return tw.Flush()
+}
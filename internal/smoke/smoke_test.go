@@ -0,0 +1,121 @@
+package smoke
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/k8sclient"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
+)
+
+func TestParseGVK(t *testing.T) {
+	gvk, err := ParseGVK("v1/ConfigMap")
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, gvk)
+
+	gvk, err = ParseGVK("apps/v1/Deployment")
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, gvk)
+
+	for _, value := range []string{"ConfigMap", "v1/", "/ConfigMap", ""} {
+		_, err := ParseGVK(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestRun(t *testing.T) {
+	opts := Options{
+		GVK:       schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		Transport: configloader.TransportClientKubernetes,
+		Namespace: "smoke",
+		Name:      "hyperfleet-smoke",
+	}
+
+	t.Run("all operations succeed", func(t *testing.T) {
+		client := k8sclient.NewMockK8sClient()
+
+		report, err := Run(context.Background(), client, opts)
+
+		require.NoError(t, err)
+		assert.True(t, report.Passed)
+		require.Len(t, report.Operations, 4)
+		for i, name := range []string{OperationCreate, OperationRead, OperationUpdate, OperationDelete} {
+			assert.Equal(t, name, report.Operations[i].Name)
+			assert.Equal(t, ResultOK, report.Operations[i].Result)
+		}
+		assert.Empty(t, client.Resources, "the canary should be deleted")
+
+		var out bytes.Buffer
+		require.NoError(t, report.WriteText(&out))
+		assert.Contains(t, out.String(), "kubernetes v1/ConfigMap smoke/hyperfleet-smoke")
+		assert.Contains(t, out.String(), "Smoke test passed")
+	})
+
+	t.Run("forbidden create skips the other operations", func(t *testing.T) {
+		client := k8sclient.NewMockK8sClient()
+		client.ApplyResourceError = k8serrors.NewForbidden(
+			schema.GroupResource{Resource: "configmaps"}, opts.Name, errors.New("RBAC denied"))
+
+		report, err := Run(context.Background(), client, opts)
+
+		require.NoError(t, err)
+		assert.False(t, report.Passed)
+		require.Len(t, report.Operations, 4)
+		assert.Equal(t, ResultForbidden, report.Operations[0].Result)
+		assert.Contains(t, report.Operations[0].Detail, "RBAC denied")
+		for _, op := range report.Operations[1:] {
+			assert.Equal(t, ResultSkipped, op.Result)
+		}
+	})
+
+	t.Run("failed read still deletes", func(t *testing.T) {
+		client := k8sclient.NewMockK8sClient()
+		client.GetResourceError = errors.New("connection reset")
+
+		report, err := Run(context.Background(), client, opts)
+
+		require.NoError(t, err)
+		assert.False(t, report.Passed)
+		assert.Equal(t, ResultFailed, report.Operations[1].Result)
+		assert.Equal(t, ResultOK, report.Operations[3].Result)
+		assert.Empty(t, client.Resources)
+	})
+
+	t.Run("maestro requires a consumer", func(t *testing.T) {
+		maestroOpts := opts
+		maestroOpts.Transport = configloader.TransportClientMaestro
+		_, err := Run(context.Background(), k8sclient.NewMockK8sClient(), maestroOpts)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "consumer")
+	})
+}
+
+func TestCanaryManifest(t *testing.T) {
+	opts := Options{GVK: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, Namespace: "smoke", Name: "canary"}
+
+	data, err := canaryManifest(opts, 2, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"kind":"ConfigMap"`)
+	assert.Contains(t, string(data), `"`+constants.AnnotationGeneration+`":"2"`)
+
+	data, err = canaryManifest(opts, 1, true)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"kind":"ManifestWork"`)
+	assert.Contains(t, string(data), `"manifests":[{`)
+}
+
+func TestResultOf(t *testing.T) {
+	assert.Equal(t, ResultForbidden, resultOf(k8serrors.NewUnauthorized("token expired")))
+	assert.Equal(t, ResultForbidden, resultOf(status.Error(codes.PermissionDenied, "denied")))
+	assert.Equal(t, ResultFailed, resultOf(errors.New("timeout")))
+}
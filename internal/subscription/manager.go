@@ -0,0 +1,341 @@
+// Package subscription supervises the broker subscription.
+//
+// The broker reports background failures on Subscriber.Errors(). A Manager drains
+// that channel for the lifetime of the adapter, logs transient errors, recreates
+// the subscriber with exponential backoff when the broker reports it has stopped,
+// and only gives up when an error cannot be fixed by resubscribing or the restart
+// budget is exhausted.
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Default restart settings
+const (
+	DefaultBaseDelay    = 1 * time.Second
+	DefaultMaxDelay     = 30 * time.Second
+	DefaultMaxRestarts  = 10
+	DefaultStablePeriod = 5 * time.Minute
+)
+
+// ErrorClass describes how the Manager reacts to a subscriber error
+type ErrorClass string
+
+const (
+	// ErrorClassTransient errors are logged; the subscriber keeps running
+	ErrorClassTransient ErrorClass = "transient"
+	// ErrorClassRecoverable errors mean the subscriber stopped and must be recreated
+	ErrorClassRecoverable ErrorClass = "recoverable"
+	// ErrorClassFatal errors cannot be fixed by resubscribing and shut the adapter down
+	ErrorClassFatal ErrorClass = "fatal"
+)
+
+// errSubscriberClosed is reported when the error channel closes while the
+// Manager still expects the subscriber to be running
+var errSubscriberClosed = errors.New("subscriber error channel closed unexpectedly")
+
+// Factory creates a new, unsubscribed broker subscriber
+type Factory func() (broker.Subscriber, error)
+
+// Classify returns how err should be handled.
+//
+// Errors the broker marks as Fatal have stopped the subscriber and are recoverable
+// by recreating it, unless the underlying cause is a permission, authentication or
+// missing-resource failure that a new subscriber would hit again.
+func Classify(err error) ErrorClass {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return ErrorClassTransient
+	}
+	if st, ok := status.FromError(err); ok && isFatalCode(st.Code()) {
+		return ErrorClassFatal
+	}
+	var subErr *broker.SubscriberError
+	if errors.As(err, &subErr) && !subErr.Fatal {
+		return ErrorClassTransient
+	}
+	return ErrorClassRecoverable
+}
+
+// isFatalCode reports whether a gRPC status code means a new subscriber would fail the same way
+func isFatalCode(code codes.Code) bool {
+	switch code {
+	case codes.PermissionDenied, codes.Unauthenticated, codes.NotFound, codes.InvalidArgument:
+		return true
+	default:
+		return false
+	}
+}
+
+// Option configures a Manager
+type Option func(*Manager)
+
+// WithMetrics records every restart attempt on the given recorder
+func WithMetrics(recorder *metrics.Recorder) Option {
+	return func(m *Manager) {
+		m.metrics = recorder
+	}
+}
+
+// WithReadiness sets the callback used to report whether a subscription is active
+func WithReadiness(setReady func(bool)) Option {
+	return func(m *Manager) {
+		m.setReady = setReady
+	}
+}
+
+// WithBackoff sets the base and maximum delay between restart attempts
+func WithBackoff(base, max time.Duration) Option {
+	return func(m *Manager) {
+		m.baseDelay = base
+		m.maxDelay = max
+	}
+}
+
+// WithMaxRestarts sets how many consecutive restart attempts are made before
+// giving up. Zero means unlimited.
+func WithMaxRestarts(n int) Option {
+	return func(m *Manager) {
+		m.maxRestarts = n
+	}
+}
+
+// WithStablePeriod sets how long a subscription must stay up before the
+// consecutive restart counter is reset
+func WithStablePeriod(d time.Duration) Option {
+	return func(m *Manager) {
+		m.stablePeriod = d
+	}
+}
+
+// Manager owns the broker subscriber and restarts it on recoverable errors.
+// Errors() of the current subscriber is only ever read by the Run goroutine.
+// sub and closed are guarded by mu.
+type Manager struct {
+	factory      Factory
+	handler      broker.HandlerFunc
+	log          logger.Logger
+	metrics      *metrics.Recorder
+	setReady     func(bool)
+	sub          broker.Subscriber
+	topic        string
+	baseDelay    time.Duration
+	maxDelay     time.Duration
+	maxRestarts  int
+	stablePeriod time.Duration
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewManager creates a Manager that subscribes handler to topic using
+// subscribers created by factory
+func NewManager(factory Factory, topic string, handler broker.HandlerFunc, log logger.Logger, opts ...Option) *Manager {
+	m := &Manager{
+		factory:      factory,
+		topic:        topic,
+		handler:      handler,
+		log:          log,
+		setReady:     func(bool) {},
+		baseDelay:    DefaultBaseDelay,
+		maxDelay:     DefaultMaxDelay,
+		maxRestarts:  DefaultMaxRestarts,
+		stablePeriod: DefaultStablePeriod,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start creates the initial subscriber and subscribes to the topic. Errors are
+// returned as-is so startup fails fast on misconfiguration.
+func (m *Manager) Start(ctx context.Context) error {
+	sub, err := m.subscribe(ctx)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	m.sub = sub
+	m.mu.Unlock()
+	m.setReady(true)
+	return nil
+}
+
+// Run supervises the subscription until ctx is canceled or an unrecoverable error
+// occurs. It returns nil on cancellation and the escalated error otherwise.
+func (m *Manager) Run(ctx context.Context) error {
+	failures := 0
+	for {
+		startedAt := time.Now()
+		err := m.watch(ctx, m.current())
+		if err == nil {
+			return nil
+		}
+		if Classify(err) == ErrorClassFatal {
+			m.setReady(false)
+			return fmt.Errorf("unrecoverable subscription error: %w", err)
+		}
+		if time.Since(startedAt) >= m.stablePeriod {
+			failures = 0
+		}
+
+		m.setReady(false)
+		errCtx := logger.WithErrorField(ctx, err)
+		m.log.Warnf(errCtx, "Subscriber stopped, restarting subscription to topic %s", m.topic)
+
+		for {
+			failures++
+			if m.maxRestarts > 0 && failures > m.maxRestarts {
+				return fmt.Errorf("subscription restart limit (%d) reached: %w", m.maxRestarts, err)
+			}
+			if !m.sleep(ctx, m.backoff(failures)) {
+				return nil
+			}
+			err = m.restart(ctx)
+			if err == nil {
+				m.metrics.RecordSubscriptionRestart(metrics.RestartResultSuccess)
+				m.log.Infof(ctx, "Subscription to topic %s restarted (attempt %d)", m.topic, failures)
+				m.setReady(true)
+				break
+			}
+			if ctx.Err() != nil || m.isClosed() {
+				return nil
+			}
+			m.metrics.RecordSubscriptionRestart(metrics.RestartResultFailed)
+			if Classify(err) == ErrorClassFatal {
+				return fmt.Errorf("unrecoverable subscription error: %w", err)
+			}
+			errCtx := logger.WithErrorField(ctx, err)
+			m.log.Warnf(errCtx, "Subscription restart attempt %d failed", failures)
+		}
+	}
+}
+
+// Close closes the current subscriber. Run stops restarting once Close is called.
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	if m.sub == nil {
+		return nil
+	}
+	return m.sub.Close()
+}
+
+// watch drains sub.Errors() until ctx is done or an error requires a restart
+func (m *Manager) watch(ctx context.Context, sub broker.Subscriber) error {
+	if sub == nil {
+		return errSubscriberClosed
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case subErr, ok := <-sub.Errors():
+			if !ok {
+				if ctx.Err() != nil || m.isClosed() {
+					return nil
+				}
+				return errSubscriberClosed
+			}
+			if subErr == nil {
+				continue
+			}
+			errCtx := logger.WithErrorField(ctx, subErr)
+			if Classify(subErr) == ErrorClassTransient {
+				m.log.Warnf(errCtx, "Subscription error (op=%s)", subErr.Op)
+				continue
+			}
+			m.log.Errorf(errCtx, "Subscription error (op=%s, fatal=%t)", subErr.Op, subErr.Fatal)
+			return subErr
+		}
+	}
+}
+
+// restart closes the current subscriber and replaces it with a new subscription
+func (m *Manager) restart(ctx context.Context) error {
+	if old := m.current(); old != nil {
+		if err := old.Close(); err != nil {
+			errCtx := logger.WithErrorField(ctx, err)
+			m.log.Warn(errCtx, "Failed to close stopped subscriber")
+		}
+	}
+
+	sub, err := m.subscribe(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		if closeErr := sub.Close(); closeErr != nil {
+			m.log.Warnf(ctx, "Failed to close subscriber: %v", closeErr)
+		}
+		return context.Canceled
+	}
+	m.sub = sub
+	return nil
+}
+
+// subscribe creates a subscriber and subscribes the handler to the topic
+func (m *Manager) subscribe(ctx context.Context) (broker.Subscriber, error) {
+	sub, err := m.factory()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscriber: %w", err)
+	}
+	if err := sub.Subscribe(ctx, m.topic, m.handler); err != nil {
+		if closeErr := sub.Close(); closeErr != nil {
+			m.log.Warnf(ctx, "Failed to close subscriber: %v", closeErr)
+		}
+		return nil, fmt.Errorf("failed to subscribe to topic: %w", err)
+	}
+	return sub, nil
+}
+
+func (m *Manager) current() broker.Subscriber {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sub
+}
+
+func (m *Manager) isClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// backoff returns baseDelay * 2^(attempt-1), capped at maxDelay
+func (m *Manager) backoff(attempt int) time.Duration {
+	delay := m.baseDelay
+	for i := 1; i < attempt && delay < m.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > m.maxDelay {
+		delay = m.maxDelay
+	}
+	return delay
+}
+
+// sleep waits for d and reports false if ctx is done first
+func (m *Manager) sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
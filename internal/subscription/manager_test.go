@@ -0,0 +1,235 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/metrics"
+	"github.com/openshift-hyperfleet/hyperfleet-broker/broker"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeSubscriber struct {
+	errCh     chan *broker.SubscriberError
+	subErr    error
+	closeOnce sync.Once
+	closed    atomic.Bool
+}
+
+func newFakeSubscriber() *fakeSubscriber {
+	return &fakeSubscriber{errCh: make(chan *broker.SubscriberError, 10)}
+}
+
+func (f *fakeSubscriber) Subscribe(_ context.Context, _ string, _ broker.HandlerFunc) error {
+	return f.subErr
+}
+
+func (f *fakeSubscriber) Errors() <-chan *broker.SubscriberError { return f.errCh }
+
+func (f *fakeSubscriber) Close() error {
+	f.closeOnce.Do(func() {
+		f.closed.Store(true)
+		close(f.errCh)
+	})
+	return nil
+}
+
+// stop closes the error channel without Close, as a subscriber that died would
+func (f *fakeSubscriber) stop() {
+	f.closeOnce.Do(func() { close(f.errCh) })
+}
+
+func (f *fakeSubscriber) BrokerType() string { return "fake" }
+
+// fakeFactory hands out the given subscribers in order
+type fakeFactory struct {
+	subs []*fakeSubscriber
+	made []*fakeSubscriber
+	mu   sync.Mutex
+}
+
+func (f *fakeFactory) create() (broker.Subscriber, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.subs) == 0 {
+		return nil, errors.New("no more subscribers")
+	}
+	sub := f.subs[0]
+	f.subs = f.subs[1:]
+	f.made = append(f.made, sub)
+	return sub, nil
+}
+
+func (f *fakeFactory) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.made)
+}
+
+// restartCount returns hyperfleet_adapter_subscription_restarts_total for result
+func restartCount(t *testing.T, registry *prometheus.Registry, result string) float64 {
+	t.Helper()
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		if f.GetName() != "hyperfleet_adapter_subscription_restarts_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "result" && l.GetValue() == result {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func noopHandler(_ context.Context, _ *event.Event) error { return nil }
+
+func newTestManager(factory *fakeFactory, opts ...Option) *Manager {
+	opts = append([]Option{WithBackoff(time.Millisecond, 5*time.Millisecond)}, opts...)
+	return NewManager(factory.create, "topic", noopHandler, logger.NewTestLogger(), opts...)
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{
+			"non-fatal subscriber error",
+			&broker.SubscriberError{Op: "receive", Err: errors.New("nack")},
+			ErrorClassTransient,
+		},
+		{
+			"fatal subscriber error",
+			&broker.SubscriberError{Op: "router", Err: errors.New("connection lost"), Fatal: true},
+			ErrorClassRecoverable,
+		},
+		{
+			"permission denied",
+			&broker.SubscriberError{Op: "router", Err: status.Error(codes.PermissionDenied, "denied"), Fatal: true},
+			ErrorClassFatal,
+		},
+		{
+			"wrapped not found",
+			fmt.Errorf("subscribe: %w", status.Error(codes.NotFound, "no subscription")),
+			ErrorClassFatal,
+		},
+		{"canceled", context.Canceled, ErrorClassTransient},
+		{"channel closed", errSubscriberClosed, ErrorClassRecoverable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Classify(tt.err))
+		})
+	}
+}
+
+func TestManager_RestartsOnFatalSubscriberError(t *testing.T) {
+	first, second := newFakeSubscriber(), newFakeSubscriber()
+	factory := &fakeFactory{subs: []*fakeSubscriber{first, second}}
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("adapter", "v0", "test", registry)
+
+	var ready atomic.Bool
+	mgr := newTestManager(factory, WithMetrics(recorder), WithReadiness(ready.Store))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, mgr.Start(ctx))
+	assert.True(t, ready.Load())
+
+	done := make(chan error, 1)
+	go func() { done <- mgr.Run(ctx) }()
+
+	// A transient error does not restart the subscription
+	first.errCh <- &broker.SubscriberError{Op: "receive", Err: errors.New("nack")}
+	first.errCh <- &broker.SubscriberError{Op: "router", Err: errors.New("connection lost"), Fatal: true}
+
+	require.Eventually(t, func() bool { return factory.count() == 2 && ready.Load() }, time.Second, time.Millisecond)
+	assert.True(t, first.closed.Load(), "stopped subscriber should be closed")
+
+	cancel()
+	require.NoError(t, <-done)
+	require.NoError(t, mgr.Close())
+	assert.True(t, second.closed.Load())
+
+	assert.Equal(t, float64(1), restartCount(t, registry, metrics.RestartResultSuccess))
+}
+
+func TestManager_EscalatesUnrecoverableError(t *testing.T) {
+	sub := newFakeSubscriber()
+	mgr := newTestManager(&fakeFactory{subs: []*fakeSubscriber{sub}})
+	require.NoError(t, mgr.Start(context.Background()))
+
+	sub.errCh <- &broker.SubscriberError{
+		Op: "router", Err: status.Error(codes.Unauthenticated, "bad credentials"), Fatal: true,
+	}
+
+	err := mgr.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unrecoverable subscription error")
+}
+
+func TestManager_GivesUpAfterMaxRestarts(t *testing.T) {
+	first := newFakeSubscriber()
+	failing := newFakeSubscriber()
+	failing.subErr = errors.New("broker unavailable")
+	another := newFakeSubscriber()
+	another.subErr = errors.New("broker unavailable")
+	factory := &fakeFactory{subs: []*fakeSubscriber{first, failing, another}}
+
+	registry := prometheus.NewRegistry()
+	recorder := metrics.NewRecorder("adapter", "v0", "test", registry)
+	mgr := newTestManager(factory, WithMaxRestarts(2), WithMetrics(recorder))
+	require.NoError(t, mgr.Start(context.Background()))
+
+	// The error channel closing without Close() being called is treated as a stopped subscriber
+	first.stop()
+
+	err := mgr.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "restart limit (2) reached")
+	assert.Equal(t, float64(2), restartCount(t, registry, metrics.RestartResultFailed))
+}
+
+func TestManager_CloseStopsRun(t *testing.T) {
+	sub := newFakeSubscriber()
+	mgr := newTestManager(&fakeFactory{subs: []*fakeSubscriber{sub}})
+	require.NoError(t, mgr.Start(context.Background()))
+
+	done := make(chan error, 1)
+	go func() { done <- mgr.Run(context.Background()) }()
+
+	require.NoError(t, mgr.Close())
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after Close")
+	}
+}
+
+func TestManager_Backoff(t *testing.T) {
+	mgr := NewManager(nil, "topic", noopHandler, logger.NewTestLogger(), WithBackoff(time.Second, 5*time.Second))
+	assert.Equal(t, time.Second, mgr.backoff(1))
+	assert.Equal(t, 2*time.Second, mgr.backoff(2))
+	assert.Equal(t, 4*time.Second, mgr.backoff(3))
+	assert.Equal(t, 5*time.Second, mgr.backoff(4))
+	assert.Equal(t, 5*time.Second, mgr.backoff(50))
+}
@@ -0,0 +1,31 @@
+package subscription
+
+import "sync"
+
+// Readiness combines the readiness of several subscriptions: the adapter is ready while
+// every one of them is active
+type Readiness struct {
+	setReady func(bool)
+	ready    []bool
+	mu       sync.Mutex
+}
+
+// NewReadiness returns a Readiness of n subscriptions, all inactive, reporting the combined
+// readiness to setReady whenever one of them changes
+func NewReadiness(n int, setReady func(bool)) *Readiness {
+	return &Readiness{setReady: setReady, ready: make([]bool, n)}
+}
+
+// Member returns the readiness callback of subscription i, for WithReadiness
+func (r *Readiness) Member(i int) func(bool) {
+	return func(ready bool) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.ready[i] = ready
+		all := true
+		for _, member := range r.ready {
+			all = all && member
+		}
+		r.setReady(all)
+	}
+}
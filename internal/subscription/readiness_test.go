@@ -0,0 +1,21 @@
+package subscription
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness(t *testing.T) {
+	var reported []bool
+	readiness := NewReadiness(2, func(ready bool) { reported = append(reported, ready) })
+	first, second := readiness.Member(0), readiness.Member(1)
+
+	first(true)
+	second(true)
+	second(false)
+	second(true)
+
+	assert.Equal(t, []bool{false, true, false, true}, reported,
+		"the adapter should only be ready while every subscription is")
+}
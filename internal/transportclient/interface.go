@@ -13,6 +13,7 @@ import (
 // to be applied via different backends:
 //   - Direct Kubernetes API (k8sclient)
 //   - Maestro/OCM ManifestWork (maestroclient)
+//   - Git repository for GitOps controllers (gitopsclient)
 //
 // All implementations must support generation-aware apply operations:
 //   - Create if resource doesn't exist
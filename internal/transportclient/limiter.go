@@ -0,0 +1,203 @@
+package transportclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type queueKeyContextKey struct{}
+
+// WithQueueKey returns a context whose writes are queued under key by a limited client.
+// The executor sets it to the cluster of the event, so that a busy cluster cannot starve
+// the others of write slots.
+func WithQueueKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, queueKeyContextKey{}, key)
+}
+
+// QueueKeyFrom returns the queue key of ctx, empty when none was set
+func QueueKeyFrom(ctx context.Context) string {
+	if key, ok := ctx.Value(queueKeyContextKey{}).(string); ok {
+		return key
+	}
+	return ""
+}
+
+// FairLimiter caps the number of concurrent holders of a slot. Callers waiting for a slot
+// are queued by key, and freed slots go to the keys in turn, first come first served
+// within a key.
+type FairLimiter struct {
+	queues map[string][]*limiterWaiter
+	// order lists the keys with waiters, in the order their turn comes
+	order []string
+	limit int
+	inUse int
+	mu    sync.Mutex
+}
+
+type limiterWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// NewFairLimiter returns a limiter with limit slots. A limit below 1 allows one holder.
+func NewFairLimiter(limit int) *FairLimiter {
+	return &FairLimiter{
+		queues: make(map[string][]*limiterWaiter),
+		limit:  max(limit, 1),
+	}
+}
+
+// Acquire waits for a slot, queued under key. It fails if ctx is done first, in which case
+// no slot is held.
+func (l *FairLimiter) Acquire(ctx context.Context, key string) error {
+	l.mu.Lock()
+	if l.inUse < l.limit && len(l.order) == 0 {
+		l.inUse++
+		l.mu.Unlock()
+		return nil
+	}
+	w := &limiterWaiter{ready: make(chan struct{})}
+	if len(l.queues[key]) == 0 {
+		l.order = append(l.order, key)
+	}
+	l.queues[key] = append(l.queues[key], w)
+	l.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	if w.granted {
+		// The slot was handed over as ctx was done: pass it on
+		l.mu.Unlock()
+		l.Release()
+		return ctx.Err()
+	}
+	l.removeWaiter(key, w)
+	l.mu.Unlock()
+	return ctx.Err()
+}
+
+// Release frees a slot taken by Acquire, handing it to the next waiter if any
+func (l *FairLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.order) == 0 {
+		l.inUse--
+		return
+	}
+	key := l.order[0]
+	l.order = l.order[1:]
+	queue := l.queues[key]
+	w := queue[0]
+	if len(queue) > 1 {
+		l.queues[key] = queue[1:]
+		l.order = append(l.order, key)
+	} else {
+		delete(l.queues, key)
+	}
+	w.granted = true
+	close(w.ready)
+}
+
+// Waiting returns the number of callers waiting for a slot
+func (l *FairLimiter) Waiting() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	waiting := 0
+	for _, queue := range l.queues {
+		waiting += len(queue)
+	}
+	return waiting
+}
+
+// removeWaiter drops w from the queue of key. The caller holds l.mu.
+func (l *FairLimiter) removeWaiter(key string, w *limiterWaiter) {
+	queue := l.queues[key]
+	for i, queued := range queue {
+		if queued == w {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(queue) > 0 {
+		l.queues[key] = queue
+		return
+	}
+	delete(l.queues, key)
+	for i, k := range l.order {
+		if k == key {
+			l.order = append(l.order[:i], l.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// NewLimitedClient returns a transport client that runs at most maxConcurrentWrites writes
+// (applies, deletes and updates) through tc at a time, whatever the number of events
+// executing. Waiting writes are queued per WithQueueKey key. Reads are not limited.
+// A maxConcurrentWrites below 1 returns tc unchanged.
+func NewLimitedClient(tc TransportClient, maxConcurrentWrites int) TransportClient {
+	if maxConcurrentWrites < 1 {
+		return tc
+	}
+	return &limitedClient{TransportClient: tc, limiter: NewFairLimiter(maxConcurrentWrites)}
+}
+
+type limitedClient struct {
+	TransportClient
+	limiter *FairLimiter
+}
+
+func (c *limitedClient) ApplyResource(
+	ctx context.Context,
+	manifest []byte,
+	opts *ApplyOptions,
+	target TransportContext,
+) (*ApplyResult, error) {
+	if err := c.limiter.Acquire(ctx, QueueKeyFrom(ctx)); err != nil {
+		return nil, fmt.Errorf("waiting for a write slot: %w", err)
+	}
+	defer c.limiter.Release()
+	return c.TransportClient.ApplyResource(ctx, manifest, opts, target)
+}
+
+func (c *limitedClient) DeleteResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	opts *DeleteOptions,
+	target TransportContext,
+) error {
+	if err := c.limiter.Acquire(ctx, QueueKeyFrom(ctx)); err != nil {
+		return fmt.Errorf("waiting for a write slot: %w", err)
+	}
+	defer c.limiter.Release()
+	return c.TransportClient.DeleteResource(ctx, gvk, namespace, name, opts, target)
+}
+
+// UpdateResource passes updates through to clients that support them, such as the
+// kubernetes client removing adapter-managed finalizers
+func (c *limitedClient) UpdateResource(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	updater, ok := c.TransportClient.(interface {
+		UpdateResource(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("transport client cannot update resources")
+	}
+	if err := c.limiter.Acquire(ctx, QueueKeyFrom(ctx)); err != nil {
+		return nil, fmt.Errorf("waiting for a write slot: %w", err)
+	}
+	defer c.limiter.Release()
+	return updater.UpdateResource(ctx, obj)
+}
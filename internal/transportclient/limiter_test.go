@@ -0,0 +1,76 @@
+package transportclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitForWaiters waits until n callers are queued on l
+func waitForWaiters(t *testing.T, l *FairLimiter, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return l.Waiting() == n }, time.Second, time.Millisecond)
+}
+
+func TestFairLimiter_RoundRobinsKeys(t *testing.T) {
+	ctx := context.Background()
+	l := NewFairLimiter(1)
+	require.NoError(t, l.Acquire(ctx, "busy"))
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	acquire := func(key string) {
+		defer wg.Done()
+		require.NoError(t, l.Acquire(ctx, key))
+		mu.Lock()
+		order = append(order, key)
+		mu.Unlock()
+		l.Release()
+	}
+	// Three writes of a busy cluster queue before one of a quiet cluster
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go acquire("busy")
+		waitForWaiters(t, l, i)
+	}
+	wg.Add(1)
+	go acquire("quiet")
+	waitForWaiters(t, l, 4)
+
+	l.Release()
+	wg.Wait()
+	assert.Equal(t, []string{"busy", "quiet", "busy", "busy"}, order,
+		"the quiet cluster should not wait behind every write of the busy one")
+	assert.Zero(t, l.Waiting())
+	require.NoError(t, l.Acquire(ctx, "busy"), "all slots should be free again")
+}
+
+func TestFairLimiter_Limit(t *testing.T) {
+	ctx := context.Background()
+	l := NewFairLimiter(2)
+	require.NoError(t, l.Acquire(ctx, "a"))
+	require.NoError(t, l.Acquire(ctx, "b"))
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Acquire(timeoutCtx, "c"), context.DeadlineExceeded, "a third holder should wait")
+	assert.Zero(t, l.Waiting(), "a canceled waiter should leave the queue")
+
+	l.Release()
+	require.NoError(t, l.Acquire(ctx, "c"))
+}
+
+func TestNewLimitedClient(t *testing.T) {
+	var tc TransportClient
+	assert.Nil(t, NewLimitedClient(tc, 0), "no limit should return the client unchanged")
+	assert.IsType(t, &limitedClient{}, NewLimitedClient(tc, 2))
+
+	ctx := WithQueueKey(context.Background(), "cluster-1")
+	assert.Equal(t, "cluster-1", QueueKeyFrom(ctx))
+	assert.Empty(t, QueueKeyFrom(context.Background()))
+}
@@ -0,0 +1,67 @@
+package transportclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/traffic"
+)
+
+// Factory creates the transport client of a backend from the adapter config
+type Factory func(ctx context.Context, config *configloader.Config, opts FactoryOptions) (TransportClient, error)
+
+// FactoryOptions are the dependencies passed to every transport factory
+type FactoryOptions struct {
+	Logger logger.Logger
+	// Recorder captures the traffic of the client, nil when traffic recording is disabled
+	Recorder *traffic.Recorder
+}
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a transport backend available under name, which resources select with
+// transport.client. It is meant to be called from init functions, and panics if name is
+// empty or already registered.
+func Register(name string, factory Factory) {
+	if name == "" || factory == nil {
+		panic("transportclient: Register needs a name and a factory")
+	}
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if _, exists := factories[name]; exists {
+		panic(fmt.Sprintf("transportclient: transport %q is already registered", name))
+	}
+	factories[name] = factory
+	configloader.RegisterTransportClient(name)
+}
+
+// Registered returns the names of the registered transports, sorted
+func Registered() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the transport client registered under name
+func New(ctx context.Context, name string, config *configloader.Config, opts FactoryOptions) (TransportClient, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown transport %q (registered: %s)", name, strings.Join(Registered(), ", "))
+	}
+	return factory(ctx, config, opts)
+}
@@ -0,0 +1,122 @@
+package transportclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/manifest"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+type transportNameContextKey struct{}
+
+// WithTransportName returns a context whose calls a router passes to the transport named
+// name. The executor sets it to the transport.client of the resource being processed.
+func WithTransportName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, transportNameContextKey{}, name)
+}
+
+// TransportNameFrom returns the transport name of ctx, empty when none was set
+func TransportNameFrom(ctx context.Context) string {
+	if name, ok := ctx.Value(transportNameContextKey{}).(string); ok {
+		return name
+	}
+	return ""
+}
+
+// NewRouter returns a transport client that passes each call to the client of the
+// transport named by WithTransportName, or to the client of defaultName when the context
+// names none. Calls for a transport without a client fail.
+func NewRouter(clients map[string]TransportClient, defaultName string) TransportClient {
+	return &router{clients: clients, defaultName: defaultName}
+}
+
+type router struct {
+	clients     map[string]TransportClient
+	defaultName string
+}
+
+func (r *router) clientFor(ctx context.Context) (TransportClient, error) {
+	name := TransportNameFrom(ctx)
+	if name == "" {
+		name = r.defaultName
+	}
+	client, ok := r.clients[name]
+	if !ok {
+		return nil, fmt.Errorf("no %q transport client is configured", name)
+	}
+	return client, nil
+}
+
+func (r *router) ApplyResource(
+	ctx context.Context,
+	manifest []byte,
+	opts *ApplyOptions,
+	target TransportContext,
+) (*ApplyResult, error) {
+	client, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.ApplyResource(ctx, manifest, opts, target)
+}
+
+func (r *router) GetResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	target TransportContext,
+) (*unstructured.Unstructured, error) {
+	client, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetResource(ctx, gvk, namespace, name, target)
+}
+
+func (r *router) DiscoverResources(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	discovery manifest.Discovery,
+	target TransportContext,
+) (*unstructured.UnstructuredList, error) {
+	client, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.DiscoverResources(ctx, gvk, discovery, target)
+}
+
+func (r *router) DeleteResource(
+	ctx context.Context,
+	gvk schema.GroupVersionKind,
+	namespace, name string,
+	opts *DeleteOptions,
+	target TransportContext,
+) error {
+	client, err := r.clientFor(ctx)
+	if err != nil {
+		return err
+	}
+	return client.DeleteResource(ctx, gvk, namespace, name, opts, target)
+}
+
+// UpdateResource passes updates through to clients that support them, such as the
+// kubernetes client removing adapter-managed finalizers
+func (r *router) UpdateResource(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+) (*unstructured.Unstructured, error) {
+	client, err := r.clientFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	updater, ok := client.(interface {
+		UpdateResource(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("transport client cannot update resources")
+	}
+	return updater.UpdateResource(ctx, obj)
+}
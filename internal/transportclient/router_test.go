@@ -0,0 +1,81 @@
+package transportclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/internal/configloader"
+)
+
+// namedClient returns objects named after the client, to tell which client served a call
+type namedClient struct {
+	TransportClient
+	name string
+}
+
+func (c *namedClient) GetResource(
+	_ context.Context,
+	_ schema.GroupVersionKind,
+	_, _ string,
+	_ TransportContext,
+) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetName(c.name)
+	return obj, nil
+}
+
+func TestRouter(t *testing.T) {
+	router := NewRouter(map[string]TransportClient{
+		"kubernetes": &namedClient{name: "kubernetes"},
+		"maestro":    &namedClient{name: "maestro"},
+	}, "maestro")
+	get := func(ctx context.Context) (string, error) {
+		obj, err := router.GetResource(ctx, schema.GroupVersionKind{Kind: "ConfigMap"}, "ns", "cm", nil)
+		if err != nil {
+			return "", err
+		}
+		return obj.GetName(), nil
+	}
+
+	served, err := get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "maestro", served, "calls without a transport name go to the default")
+
+	served, err = get(WithTransportName(context.Background(), "kubernetes"))
+	require.NoError(t, err)
+	assert.Equal(t, "kubernetes", served)
+
+	_, err = get(WithTransportName(context.Background(), "argocd"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no "argocd" transport client`)
+
+	updater, ok := router.(interface {
+		UpdateResource(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	})
+	require.True(t, ok, "the router should pass updates through")
+	_, err = updater.UpdateResource(context.Background(), &unstructured.Unstructured{})
+	assert.ErrorContains(t, err, "cannot update resources")
+}
+
+func TestRegister(t *testing.T) {
+	factory := func(context.Context, *configloader.Config, FactoryOptions) (TransportClient, error) {
+		return &namedClient{name: "gitops"}, nil
+	}
+	Register("test-gitops", factory)
+	assert.Contains(t, Registered(), "test-gitops")
+	assert.True(t, configloader.IsSupportedTransportClient("test-gitops"),
+		"registered transports should pass config validation")
+	assert.Panics(t, func() { Register("test-gitops", factory) }, "names are registered once")
+
+	client, err := New(context.Background(), "test-gitops", &configloader.Config{}, FactoryOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "gitops", client.(*namedClient).name)
+
+	_, err = New(context.Background(), "test-unknown", &configloader.Config{}, FactoryOptions{})
+	assert.ErrorContains(t, err, `unknown transport "test-unknown"`)
+}
@@ -8,9 +8,20 @@ import (
 
 // ApplyOptions configures the behavior of resource apply operations.
 type ApplyOptions struct {
+	// FieldManager is the server-side apply field manager. Empty uses the client default.
+	FieldManager string
 	// RecreateOnChange forces delete+create instead of update when resource exists
 	// and generation has changed. Useful for resources that don't support in-place updates.
 	RecreateOnChange bool
+	// ServerSideApply writes the resource with server-side apply instead of a full update,
+	// taking ownership of the fields in the manifest. Ignored for Maestro transport.
+	ServerSideApply bool
+	// DetectDrift compares a resource whose generation is unchanged with the manifest and
+	// reports the fields that diverge in ApplyResult.Drift. Ignored for Maestro transport.
+	DetectDrift bool
+	// ReconcileDrift re-applies a resource on which drift was detected instead of skipping it.
+	// Requires DetectDrift.
+	ReconcileDrift bool
 }
 
 // DeleteOptions configures the behavior of resource delete operations.
@@ -28,12 +39,21 @@ type ApplyResult struct {
 
 	// Reason explains why the operation was chosen
 	Reason string
+
+	// Changes are the fields an update or recreate changes, compared with the existing
+	// resource. Empty for other operations and for transports that do not compute them.
+	Changes []manifest.FieldChange
+
+	// Drift are the fields of a resource whose generation is unchanged that diverge from
+	// the manifest. Only set when ApplyOptions.DetectDrift is.
+	Drift []manifest.FieldChange
 }
 
 // TransportContext carries per-request routing information for the transport backend.
 // Each transport client defines its own concrete context type and type-asserts:
 //   - k8sclient: ignores it (nil)
 //   - maestroclient: expects *maestroclient.TransportContext with ConsumerName
+//   - gitopsclient: expects *gitopsclient.TransportContext with Path
 //
 // This is typed as `any` to allow each backend to define its own context shape.
 type TransportContext = any
@@ -0,0 +1,455 @@
+// Package admin provides the admin HTTP server hosting debug and operational endpoints:
+// the effective config, runtime log level changes, feature toggles, recorded traffic and
+// pprof profiles. It listens on its own port, separate from the unauthenticated health
+// port. Every request must authenticate as a configured principal, with a bearer token or
+// an mTLS client certificate, and every request is logged with the principal that made it.
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// DefaultPort is the port of the admin server when none is configured
+const DefaultPort = "8081"
+
+// Role is the set of admin endpoints a principal may use
+type Role string
+
+const (
+	// RoleViewer may read the config, log level, toggles and recorded traffic
+	RoleViewer Role = "viewer"
+	// RoleOperator may also change them and collect pprof profiles
+	RoleOperator Role = "operator"
+)
+
+// allows reports whether the role grants access to what requires role required
+func (r Role) allows(required Role) bool {
+	return r == RoleOperator || r == required
+}
+
+// Config configures the admin server
+type Config struct {
+	// TLS serves the admin endpoints over HTTPS, and enables mTLS principals when a client
+	// CA is set
+	TLS *TLSConfig `yaml:"tls,omitempty" mapstructure:"tls"`
+	// Port is the listen port (default DefaultPort)
+	Port string `yaml:"port,omitempty" mapstructure:"port"`
+	// Principals are the identities allowed to use the admin endpoints
+	Principals []Principal `yaml:"principals,omitempty" mapstructure:"principals" validate:"dive"`
+	Enabled    bool        `yaml:"enabled,omitempty" mapstructure:"enabled"`
+}
+
+// TLSConfig configures HTTPS on the admin server
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file" mapstructure:"cert_file"`
+	KeyFile  string `yaml:"key_file" mapstructure:"key_file"`
+	// ClientCAFile verifies client certificates; principals with a common_name need it
+	ClientCAFile string `yaml:"client_ca_file,omitempty" mapstructure:"client_ca_file"`
+}
+
+// Principal is an identity allowed to use the admin endpoints. It authenticates with the
+// bearer token stored in TokenPath, or with a client certificate whose subject common name
+// is CommonName.
+type Principal struct {
+	Name       string `yaml:"name" mapstructure:"name" validate:"required"`
+	Role       Role   `yaml:"role" mapstructure:"role" validate:"required,oneof=viewer operator"`
+	TokenPath  string `yaml:"token_path,omitempty" mapstructure:"token_path"`
+	CommonName string `yaml:"common_name,omitempty" mapstructure:"common_name"`
+}
+
+// Toggle is a named boolean switch that can be flipped at runtime
+type Toggle struct {
+	Get func() bool
+	Set func(bool)
+}
+
+// Server is the admin HTTP server. All methods are no-ops on a nil Server.
+type Server struct {
+	log     logger.Logger
+	server  *http.Server
+	toggles map[string]Toggle
+	// traffic serves /debug/traffic; nil when traffic recording is not available
+	traffic    http.Handler
+	configYAML []byte
+	config     Config
+	mu         sync.RWMutex
+}
+
+// NewServer creates the admin server, or returns nil when it is disabled
+func NewServer(cfg Config, log logger.Logger) (*Server, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if len(cfg.Principals) == 0 {
+		return nil, fmt.Errorf("admin server requires at least one principal")
+	}
+	if cfg.Port == "" {
+		cfg.Port = DefaultPort
+	}
+	s := &Server{log: log, config: cfg, toggles: make(map[string]Toggle)}
+
+	mux := http.NewServeMux()
+	mux.Handle("/configz", s.authorize(RoleViewer, http.HandlerFunc(s.configzHandler)))
+	mux.Handle("/loglevel", s.authorize(RoleViewer, http.HandlerFunc(s.logLevelHandler)))
+	mux.Handle("/logformat", s.authorize(RoleViewer, http.HandlerFunc(s.logFormatHandler)))
+	mux.Handle("/features", s.authorize(RoleViewer, http.HandlerFunc(s.featuresHandler)))
+	mux.Handle("/debug/traffic", s.authorize(RoleViewer, http.HandlerFunc(s.trafficHandler)))
+	// Profiles expose memory contents and cost CPU while they are collected
+	mux.Handle("/debug/pprof/", s.authorize(RoleOperator, http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", s.authorize(RoleOperator, http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", s.authorize(RoleOperator, http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", s.authorize(RoleOperator, http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", s.authorize(RoleOperator, http.HandlerFunc(pprof.Trace)))
+
+	s.server = &http.Server{
+		Addr:              ":" + cfg.Port,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	if cfg.TLS != nil {
+		tlsConfig, err := serverTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		s.server.TLSConfig = tlsConfig
+	}
+	return s, nil
+}
+
+// serverTLSConfig builds the TLS config of the server. Client certificates are verified
+// when they are presented, so token principals can still connect without one.
+func serverTLSConfig(cfg *TLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.ClientCAFile == "" {
+		return tlsConfig, nil
+	}
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read admin client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("admin client CA file %s contains no certificates", cfg.ClientCAFile)
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	return tlsConfig, nil
+}
+
+// Start starts the admin server in a goroutine
+func (s *Server) Start(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	if s.config.TLS == nil {
+		s.log.Warnf(ctx, "Starting admin server on port %s without TLS; bearer tokens are sent in clear text",
+			s.config.Port)
+	} else {
+		s.log.Infof(ctx, "Starting admin server on port %s with TLS", s.config.Port)
+	}
+
+	go func() {
+		var err error
+		if s.config.TLS != nil {
+			err = s.server.ListenAndServeTLS(s.config.TLS.CertFile, s.config.TLS.KeyFile)
+		} else {
+			err = s.server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			errCtx := logger.WithErrorField(ctx, err)
+			s.log.Errorf(errCtx, "Admin server error")
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown gracefully shuts down the admin server
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.log.Info(ctx, "Shutting down admin server...")
+	return s.server.Shutdown(ctx)
+}
+
+// SetConfig stores the pre-marshaled, redacted YAML config served at /configz
+func (s *Server) SetConfig(data []byte) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configYAML = data
+}
+
+// SetTrafficHandler sets the handler serving the recorded outgoing traffic at /debug/traffic.
+// The endpoint returns 404 until it is set.
+func (s *Server) SetTrafficHandler(h http.Handler) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.traffic = h
+}
+
+// RegisterToggle exposes a feature toggle at /features
+func (s *Server) RegisterToggle(name string, toggle Toggle) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toggles[name] = toggle
+}
+
+// -----------------------------------------------------------------------------
+// Authentication and authorization
+// -----------------------------------------------------------------------------
+
+// authorize authenticates the request and checks the principal's role. Reads (GET and
+// HEAD) require readRole; every other method requires RoleOperator. Every request is logged.
+func (s *Server) authorize(readRole Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		required := RoleOperator
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			required = readRole
+		}
+
+		ctx := logger.WithLogFields(r.Context(), logger.LogFields{
+			"admin_method": r.Method,
+			"admin_path":   r.URL.Path,
+			"remote_addr":  r.RemoteAddr,
+		})
+		principal := s.authenticate(r)
+		if principal == nil {
+			s.log.Warn(ctx, "Rejected unauthenticated admin request")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="hyperfleet-adapter-admin"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		ctx = logger.WithLogFields(ctx, logger.LogFields{
+			"admin_principal": principal.Name,
+			"admin_role":      string(principal.Role),
+		})
+		if !principal.Role.allows(required) {
+			s.log.Warnf(ctx, "Rejected admin request: %s requires the %s role", r.URL.Path, required)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		s.log.Infof(ctx, "Admin request %s %s by %s: %d", r.Method, r.URL.RequestURI(), principal.Name, rec.status)
+	})
+}
+
+// authenticate returns the principal of a verified client certificate or bearer token
+func (s *Server) authenticate(r *http.Request) *Principal {
+	if r.TLS != nil && len(r.TLS.VerifiedChains) > 0 {
+		commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		for i := range s.config.Principals {
+			p := &s.config.Principals[i]
+			if p.CommonName != "" && p.CommonName == commonName {
+				return p
+			}
+		}
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return nil
+	}
+	for i := range s.config.Principals {
+		p := &s.config.Principals[i]
+		if p.TokenPath == "" {
+			continue
+		}
+		// Read on every request so rotated tokens apply without a restart
+		expected, err := os.ReadFile(p.TokenPath)
+		if err != nil {
+			errCtx := logger.WithErrorField(r.Context(), err)
+			s.log.Warnf(errCtx, "Failed to read the admin token of principal %s", p.Name)
+			continue
+		}
+		want := strings.TrimSpace(string(expected))
+		if want != "" && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1 {
+			return p
+		}
+	}
+	return nil
+}
+
+// statusRecorder captures the response status for the request log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streaming handlers such as pprof trace flush through the recorder
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Handlers
+// -----------------------------------------------------------------------------
+
+// LogLevelResponse is the JSON document served by /loglevel
+type LogLevelResponse struct {
+	Level string `json:"level"`
+}
+
+// LogFormatResponse is the JSON document served by /logformat
+type LogFormatResponse struct {
+	Format string `json:"format"`
+}
+
+// configzHandler serves the redacted adapter configuration as YAML
+func (s *Server) configzHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		methodNotAllowed(w, "GET")
+		return
+	}
+	s.mu.RLock()
+	data := s.configYAML
+	s.mu.RUnlock()
+	if data == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(data) //nolint:errcheck // best-effort response
+}
+
+// logLevelHandler serves the log level on GET and changes it on PUT or POST ?level=<level>
+func (s *Server) logLevelHandler(w http.ResponseWriter, r *http.Request) {
+	controller, ok := s.log.(logger.LevelController)
+	if !ok {
+		http.Error(w, "the log level cannot be changed at runtime", http.StatusNotImplemented)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		previous := controller.Level()
+		if err := controller.SetLevel(r.URL.Query().Get("level")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.log.Warnf(r.Context(), "Admin changed the log level from %s to %s", previous, controller.Level())
+	default:
+		methodNotAllowed(w, "GET, PUT, POST")
+		return
+	}
+	writeJSON(w, LogLevelResponse{Level: controller.Level()})
+}
+
+// logFormatHandler serves the log format on GET and changes it on PUT or POST ?format=json|text
+func (s *Server) logFormatHandler(w http.ResponseWriter, r *http.Request) {
+	controller, ok := s.log.(logger.FormatController)
+	if !ok {
+		http.Error(w, "the log format cannot be changed at runtime", http.StatusNotImplemented)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		previous := controller.Format()
+		if err := controller.SetFormat(r.URL.Query().Get("format")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		s.log.Warnf(r.Context(), "Admin changed the log format from %s to %s", previous, controller.Format())
+	default:
+		methodNotAllowed(w, "GET, PUT, POST")
+		return
+	}
+	writeJSON(w, LogFormatResponse{Format: controller.Format()})
+}
+
+// featuresHandler serves the feature toggles on GET and flips one on PUT or POST
+// ?name=<toggle>&enabled=true|false
+func (s *Server) featuresHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPut, http.MethodPost:
+		query := r.URL.Query()
+		name := query.Get("name")
+		s.mu.RLock()
+		toggle, ok := s.toggles[name]
+		s.mu.RUnlock()
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown feature %q", name), http.StatusNotFound)
+			return
+		}
+		enabled, err := strconv.ParseBool(query.Get("enabled"))
+		if err != nil {
+			http.Error(w, "enabled must be true or false", http.StatusBadRequest)
+			return
+		}
+		previous := toggle.Get()
+		toggle.Set(enabled)
+		s.log.Warnf(r.Context(), "Admin changed feature %s from %t to %t", name, previous, enabled)
+	default:
+		methodNotAllowed(w, "GET, PUT, POST")
+		return
+	}
+	writeJSON(w, s.features())
+}
+
+// features returns the current state of the feature toggles
+func (s *Server) features() map[string]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	features := make(map[string]bool, len(s.toggles))
+	for name, toggle := range s.toggles {
+		features[name] = toggle.Get()
+	}
+	return features
+}
+
+// trafficHandler delegates to the handler set with SetTrafficHandler. Returns 404 if it
+// was never set.
+func (s *Server) trafficHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	h := s.traffic
+	s.mu.RUnlock()
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	h.ServeHTTP(w, r)
+}
+
+func methodNotAllowed(w http.ResponseWriter, allow string) {
+	w.Header().Set("Allow", allow)
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v) //nolint:errcheck // best-effort response
+}
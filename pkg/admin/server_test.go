@@ -0,0 +1,163 @@
+package admin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeToken(t *testing.T, token string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte(token+"\n"), 0600))
+	return path
+}
+
+// newTestServer returns an admin server with a viewer token, an operator token and an
+// operator client certificate, logging into the returned buffer at info level
+func newTestServer(t *testing.T) (*Server, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	log, err := logger.NewLogger(logger.Config{Level: "info", Format: logger.FormatText, Writer: &buf})
+	require.NoError(t, err)
+	s, err := NewServer(Config{
+		Enabled: true,
+		Principals: []Principal{
+			{Name: "oncall", Role: RoleViewer, TokenPath: writeToken(t, "viewer-token")},
+			{Name: "automation", Role: RoleOperator, TokenPath: writeToken(t, "operator-token")},
+			{Name: "sre-bot", Role: RoleOperator, CommonName: "sre-bot"},
+		},
+	}, log)
+	require.NoError(t, err)
+	return s, &buf
+}
+
+func serve(s *Server, method, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestNewServer(t *testing.T) {
+	s, err := NewServer(Config{}, logger.NewTestLogger())
+	require.NoError(t, err)
+	assert.Nil(t, s, "disabled admin server should be nil")
+	s.SetConfig([]byte("ignored"))
+	s.RegisterToggle("ignored", Toggle{})
+
+	_, err = NewServer(Config{Enabled: true}, logger.NewTestLogger())
+	assert.Error(t, err, "an admin server without principals would reject every request")
+}
+
+func TestServer_Authentication(t *testing.T) {
+	s, buf := newTestServer(t)
+
+	assert.Equal(t, http.StatusUnauthorized, serve(s, http.MethodGet, "/loglevel", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(s, http.MethodGet, "/loglevel", "wrong").Code)
+	assert.Equal(t, http.StatusOK, serve(s, http.MethodGet, "/loglevel", "viewer-token").Code)
+
+	req := httptest.NewRequest(http.MethodPost, "/loglevel?level=info", nil)
+	req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{
+		{Subject: pkix.Name{CommonName: "sre-bot"}},
+	}}}
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "verified client certificate should authenticate")
+
+	assert.Contains(t, buf.String(), "Rejected unauthenticated admin request")
+	assert.Contains(t, buf.String(), "admin_principal=sre-bot")
+}
+
+func TestServer_Roles(t *testing.T) {
+	s, buf := newTestServer(t)
+
+	assert.Equal(t, http.StatusForbidden, serve(s, http.MethodPost, "/loglevel?level=debug", "viewer-token").Code)
+	assert.Equal(t, http.StatusForbidden, serve(s, http.MethodGet, "/debug/pprof/", "viewer-token").Code)
+	assert.Equal(t, http.StatusOK, serve(s, http.MethodGet, "/debug/pprof/", "operator-token").Code)
+	assert.Contains(t, buf.String(), "requires the operator role")
+}
+
+func TestServer_LogLevel(t *testing.T) {
+	s, buf := newTestServer(t)
+
+	w := serve(s, http.MethodPut, "/loglevel?level=debug", "operator-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp LogLevelResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "debug", resp.Level)
+	assert.Equal(t, "debug", s.log.(logger.LevelController).Level())
+	assert.Contains(t, buf.String(), "Admin changed the log level from info to debug")
+
+	assert.Equal(t, http.StatusBadRequest, serve(s, http.MethodPut, "/loglevel?level=loud", "operator-token").Code)
+}
+
+func TestServer_LogFormat(t *testing.T) {
+	s, buf := newTestServer(t)
+
+	assert.Equal(t, http.StatusForbidden, serve(s, http.MethodPut, "/logformat?format=json", "viewer-token").Code)
+	w := serve(s, http.MethodPut, "/logformat?format=json", "operator-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp LogFormatResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, logger.FormatJSON, resp.Format)
+	assert.Contains(t, buf.String(), `"msg":"Admin changed the log format from text to json"`)
+
+	assert.Equal(t, http.StatusBadRequest, serve(s, http.MethodPut, "/logformat?format=xml", "operator-token").Code)
+}
+
+func TestServer_Features(t *testing.T) {
+	s, buf := newTestServer(t)
+	enabled := false
+	s.RegisterToggle("traffic_recording", Toggle{
+		Get: func() bool { return enabled },
+		Set: func(v bool) { enabled = v },
+	})
+
+	w := serve(s, http.MethodPost, "/features?name=traffic_recording&enabled=true", "operator-token")
+	require.Equal(t, http.StatusOK, w.Code)
+	var features map[string]bool
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &features))
+	assert.Equal(t, map[string]bool{"traffic_recording": true}, features)
+	assert.True(t, enabled)
+	assert.Contains(t, buf.String(), "Admin changed feature traffic_recording from false to true")
+
+	assert.Equal(t, http.StatusNotFound,
+		serve(s, http.MethodPost, "/features?name=unknown&enabled=true", "operator-token").Code)
+	assert.Equal(t, http.StatusBadRequest,
+		serve(s, http.MethodPost, "/features?name=traffic_recording&enabled=maybe", "operator-token").Code)
+}
+
+func TestServer_ConfigzAndTraffic(t *testing.T) {
+	s, _ := newTestServer(t)
+
+	assert.Equal(t, http.StatusNotFound, serve(s, http.MethodGet, "/configz", "viewer-token").Code)
+	assert.Equal(t, http.StatusNotFound, serve(s, http.MethodGet, "/debug/traffic", "viewer-token").Code)
+
+	s.SetConfig([]byte("adapter:\n  name: test\n"))
+	s.SetTrafficHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"enabled":false}`))
+	}))
+
+	w := serve(s, http.MethodGet, "/configz", "viewer-token")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "name: test")
+	assert.Equal(t, http.StatusOK, serve(s, http.MethodGet, "/debug/traffic", "viewer-token").Code)
+	assert.Equal(t, http.StatusForbidden,
+		serve(s, http.MethodPost, "/debug/traffic?enabled=true", "viewer-token").Code)
+}
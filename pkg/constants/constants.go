@@ -50,6 +50,27 @@ const (
 	// Format: "hyperfleet.io/created-by"
 	// Example value: "hyperfleet-adapter"
 	AnnotationCreatedBy = "hyperfleet.io/created-by"
+
+	// AnnotationDependsOn names the ManifestWork that must be applied before this one,
+	// set on the ManifestWorks of a split resource.
+	// Format: "hyperfleet.io/depends-on"
+	AnnotationDependsOn = "hyperfleet.io/depends-on"
+
+	// AnnotationConfigFingerprint records the fingerprint of the config that last created or
+	// updated the resource, so operators can check which config revision it reflects.
+	// Format: "hyperfleet.io/config-fingerprint"
+	// Example value: "3f2a9c81d0e4"
+	AnnotationConfigFingerprint = "hyperfleet.io/config-fingerprint"
+
+	// AnnotationTraceParent carries the W3C traceparent of the execution that last created
+	// or updated the ManifestWork, so downstream systems can join the event's trace.
+	// Format: "hyperfleet.io/traceparent"
+	// Example value: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	AnnotationTraceParent = "hyperfleet.io/traceparent"
+
+	// AnnotationTraceState carries the W3C tracestate that accompanies AnnotationTraceParent.
+	// Format: "hyperfleet.io/tracestate"
+	AnnotationTraceState = "hyperfleet.io/tracestate"
 )
 
 // OCM ManifestWork GVK constants
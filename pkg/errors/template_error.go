@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// -----------------------------------------------------------------------------
+// Template Error Types
+// -----------------------------------------------------------------------------
+
+// TemplateErrorType represents the type of template error
+type TemplateErrorType string
+
+const (
+	TemplateErrorTypeParse   TemplateErrorType = "parse"
+	TemplateErrorTypeExecute TemplateErrorType = "execute"
+)
+
+// TemplateError represents an error while rendering a Go template
+type TemplateError struct {
+	// Err is the underlying text/template error
+	Err error
+	// Template is the template that failed to render
+	Template string
+	// Step is the precondition, resource or post-action being rendered, when known
+	Step string
+	// Variable is the variable path referenced by the failing action, e.g. ".cluster.name"
+	Variable string
+	// Type is the error type (parse, execute)
+	Type TemplateErrorType
+	// Available are the variable roots the template could reference, sorted
+	Available []string
+}
+
+// Error implements the error interface
+func (e *TemplateError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "failed to %s template", e.Type)
+	if e.Step != "" {
+		fmt.Fprintf(&b, " in step %q", e.Step)
+	}
+	if e.Variable != "" {
+		fmt.Fprintf(&b, " at variable %s", e.Variable)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	if len(e.Available) > 0 {
+		fmt.Fprintf(&b, " (available variables: %s)", strings.Join(e.Available, ", "))
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying error for errors.Is/As support
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// NewTemplateParseError creates a new template parse error
+func NewTemplateParseError(template string, err error) *TemplateError {
+	return &TemplateError{
+		Type:     TemplateErrorTypeParse,
+		Template: template,
+		Err:      err,
+	}
+}
+
+// templateActionPattern matches the action text/template reports an execution error at
+var templateActionPattern = regexp.MustCompile(`executing ".*?" at <([^>]*)>`)
+
+// templateVariablePattern matches a field chain such as .cluster.name or $item.id
+var templateVariablePattern = regexp.MustCompile(`(?:\$[A-Za-z0-9_]*)?(?:\.[A-Za-z_][A-Za-z0-9_]*)+`)
+
+// NewTemplateExecError creates a new template execution error. The variable path is
+// taken from the action text/template reports, and the available variable roots are
+// the keys of data.
+func NewTemplateExecError(template string, data map[string]interface{}, err error) *TemplateError {
+	tmplErr := &TemplateError{
+		Type:      TemplateErrorTypeExecute,
+		Template:  template,
+		Err:       err,
+		Available: make([]string, 0, len(data)),
+	}
+	if match := templateActionPattern.FindStringSubmatch(err.Error()); match != nil {
+		tmplErr.Variable = templateVariablePattern.FindString(match[1])
+	}
+	for key := range data {
+		tmplErr.Available = append(tmplErr.Available, key)
+	}
+	sort.Strings(tmplErr.Available)
+	return tmplErr
+}
+
+// IsTemplateError checks if an error is a TemplateError and returns it
+func IsTemplateError(err error) (*TemplateError, bool) {
+	var tmplErr *TemplateError
+	if errors.As(err, &tmplErr) {
+		return tmplErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,141 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+)
+
+// Client health check defaults
+const (
+	// DefaultClientCheckInterval is how often each client is probed
+	DefaultClientCheckInterval = 30 * time.Second
+	// DefaultClientCheckTimeout bounds a single probe
+	DefaultClientCheckTimeout = 10 * time.Second
+)
+
+// ClientCheck probes one client dependency (HyperFleet API, Kubernetes, Maestro, broker).
+// Probe returns nil when the dependency is reachable.
+type ClientCheck struct {
+	Probe func(ctx context.Context) error
+	Name  string
+	// Readiness is the readiness check the results are reported to, e.g. CheckNameTransport,
+	// when the checker reports to a Server. Empty reports to none.
+	Readiness string
+}
+
+// Pinger is implemented by clients that can probe their own dependency
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ClientCheckRecorder records the outcome of client probes, e.g. as Prometheus gauges
+type ClientCheckRecorder interface {
+	RecordClientCheck(client string, healthy bool, at time.Time)
+}
+
+// ClientChecker periodically probes the configured clients in the background,
+// independent of event flow, so alerting can tell "no events" apart from
+// "cannot reach dependencies". Results are recorded, and reported to the readiness checks
+// of a Server with ReportTo; those affect /readyz only when aggregated.
+type ClientChecker struct {
+	log      logger.Logger
+	recorder ClientCheckRecorder
+	server   *Server
+	// healthy holds the last result per client, to log state changes only
+	healthy  map[string]bool
+	checks   []ClientCheck
+	interval time.Duration
+	timeout  time.Duration
+	mu       sync.Mutex
+}
+
+// NewClientChecker creates a ClientChecker. A zero interval or timeout uses the defaults.
+func NewClientChecker(
+	log logger.Logger,
+	recorder ClientCheckRecorder,
+	interval, timeout time.Duration,
+	checks ...ClientCheck,
+) *ClientChecker {
+	if interval <= 0 {
+		interval = DefaultClientCheckInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultClientCheckTimeout
+	}
+	return &ClientChecker{
+		log:      log,
+		recorder: recorder,
+		healthy:  make(map[string]bool, len(checks)),
+		checks:   checks,
+		interval: interval,
+		timeout:  timeout,
+	}
+}
+
+// ReportTo reports the results of the checks with a Readiness to the readiness checks of
+// server. Call it before Run.
+func (c *ClientChecker) ReportTo(server *Server) {
+	c.server = server
+}
+
+// Run probes every client immediately and then on each interval until ctx is canceled
+func (c *ClientChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.CheckAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckAll probes every client once, concurrently, and records the results
+func (c *ClientChecker) CheckAll(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func(check ClientCheck) {
+			defer wg.Done()
+			c.check(ctx, check)
+		}(check)
+	}
+	wg.Wait()
+}
+
+// check runs a single probe bounded by the probe timeout
+func (c *ClientChecker) check(ctx context.Context, check ClientCheck) {
+	probeCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	err := check.Probe(probeCtx)
+	if ctx.Err() != nil {
+		// Shutting down: a canceled probe says nothing about the dependency
+		return
+	}
+	healthy := err == nil
+	c.recorder.RecordClientCheck(check.Name, healthy, time.Now())
+	if c.server != nil && check.Readiness != "" {
+		c.server.SetClientResult(check.Readiness, check.Name, err)
+	}
+
+	c.mu.Lock()
+	previous, seen := c.healthy[check.Name]
+	c.healthy[check.Name] = healthy
+	c.mu.Unlock()
+
+	switch {
+	case !healthy && (!seen || previous):
+		errCtx := logger.WithErrorField(ctx, err)
+		c.log.Warnf(errCtx, "Client health check %s failed", check.Name)
+	case healthy && seen && !previous:
+		c.log.Infof(ctx, "Client health check %s recovered", check.Name)
+	default:
+		c.log.Debugf(ctx, "Client health check %s: healthy=%t", check.Name, healthy)
+	}
+}
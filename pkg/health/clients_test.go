@@ -0,0 +1,123 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type clientCheckResult struct {
+	at      time.Time
+	client  string
+	healthy bool
+}
+
+// recordingClientCheckRecorder collects the recorded client checks
+type recordingClientCheckRecorder struct {
+	results []clientCheckResult
+	mu      sync.Mutex
+}
+
+func (r *recordingClientCheckRecorder) RecordClientCheck(client string, healthy bool, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, clientCheckResult{client: client, healthy: healthy, at: at})
+}
+
+func (r *recordingClientCheckRecorder) byClient() map[string]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := make(map[string]bool, len(r.results))
+	for _, result := range r.results {
+		last[result.client] = result.healthy
+	}
+	return last
+}
+
+func TestClientChecker_CheckAll(t *testing.T) {
+	recorder := &recordingClientCheckRecorder{}
+	checker := NewClientChecker(&mockLogger{}, recorder, 0, 0,
+		ClientCheck{Name: "hyperfleet_api", Probe: func(ctx context.Context) error { return nil }},
+		ClientCheck{Name: "kubernetes", Probe: func(ctx context.Context) error { return errors.New("connection refused") }},
+	)
+
+	checker.CheckAll(context.Background())
+
+	assert.Equal(t, map[string]bool{"hyperfleet_api": true, "kubernetes": false}, recorder.byClient())
+}
+
+func TestClientChecker_ProbeTimeout(t *testing.T) {
+	recorder := &recordingClientCheckRecorder{}
+	checker := NewClientChecker(&mockLogger{}, recorder, time.Minute, 20*time.Millisecond,
+		ClientCheck{Name: "maestro", Probe: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}},
+	)
+
+	checker.CheckAll(context.Background())
+
+	assert.Equal(t, map[string]bool{"maestro": false}, recorder.byClient())
+}
+
+func TestClientChecker_RunUntilCanceled(t *testing.T) {
+	recorder := &recordingClientCheckRecorder{}
+	probes := make(chan struct{}, 10)
+	checker := NewClientChecker(&mockLogger{}, recorder, 10*time.Millisecond, time.Second,
+		ClientCheck{Name: "broker", Probe: func(ctx context.Context) error {
+			probes <- struct{}{}
+			return nil
+		}},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		checker.Run(ctx)
+		close(done)
+	}()
+
+	// The first probe runs at once, the next ones on each interval
+	for i := 0; i < 2; i++ {
+		select {
+		case <-probes:
+		case <-time.After(time.Second):
+			t.Fatal("probe did not run")
+		}
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+	require.NotEmpty(t, recorder.results)
+	assert.True(t, recorder.byClient()["broker"])
+}
+
+func TestClientChecker_ReportTo(t *testing.T) {
+	server := NewServer(&mockLogger{}, "8080", "test-adapter")
+	kubernetesErr := errors.New("connection refused")
+	checker := NewClientChecker(&mockLogger{}, &recordingClientCheckRecorder{}, 0, 0,
+		ClientCheck{Name: "hyperfleet_api", Readiness: CheckNameAPI, Probe: func(ctx context.Context) error { return nil }},
+		ClientCheck{Name: "kubernetes", Readiness: CheckNameTransport,
+			Probe: func(ctx context.Context) error { return kubernetesErr }},
+		ClientCheck{Name: "maestro", Readiness: CheckNameTransport, Probe: func(ctx context.Context) error { return nil }},
+		ClientCheck{Name: "broker", Probe: func(ctx context.Context) error { return errors.New("not active") }},
+	)
+	checker.ReportTo(server)
+
+	checker.CheckAll(context.Background())
+	assert.Equal(t, CheckOK, server.Check(CheckNameAPI))
+	assert.Equal(t, CheckError, server.Check(CheckNameTransport), "one failing transport fails the check")
+	assert.Equal(t, CheckError, server.Check(CheckNameBroker), "a check without readiness is not reported")
+
+	kubernetesErr = nil
+	checker.CheckAll(context.Background())
+	assert.Equal(t, CheckOK, server.Check(CheckNameTransport))
+}
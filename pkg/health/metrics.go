@@ -3,6 +3,7 @@ package health
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
@@ -16,7 +17,10 @@ type MetricsServer struct {
 	log       logger.Logger
 	buildInfo *prometheus.GaugeVec
 	upGauge   prometheus.Gauge
-	port      string
+	// sli serves /sli; nil when the SLIs are disabled
+	sli  http.Handler
+	port string
+	mu   sync.RWMutex
 }
 
 // MetricsConfig holds configuration for metrics registration.
@@ -59,20 +63,43 @@ func NewMetricsServer(log logger.Logger, port string, cfg MetricsConfig) *Metric
 	// Set up to 1 (adapter is running)
 	upGauge.Set(1)
 
-	mux := http.NewServeMux()
-	mux.Handle("/metrics", promhttp.Handler())
-
-	return &MetricsServer{
+	s := &MetricsServer{
 		log:       log,
 		port:      port,
 		upGauge:   upGauge,
 		buildInfo: buildInfo,
-		server: &http.Server{
-			Addr:              ":" + port,
-			Handler:           mux,
-			ReadHeaderTimeout: 5 * time.Second,
-		},
 	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/sli", s.sliHandler)
+
+	s.server = &http.Server{
+		Addr:              ":" + port,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return s
+}
+
+// SetSLIHandler sets the handler serving the SLI summary at /sli. The endpoint returns 404
+// until it is set.
+func (s *MetricsServer) SetSLIHandler(h http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sli = h
+}
+
+// sliHandler delegates to the handler set with SetSLIHandler
+func (s *MetricsServer) sliHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	h := s.sli
+	s.mu.RUnlock()
+	if h == nil {
+		http.NotFound(w, r)
+		return
+	}
+	h.ServeHTTP(w, r)
 }
 
 // Start starts the metrics server in a goroutine.
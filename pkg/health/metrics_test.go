@@ -159,3 +159,19 @@ func TestAdapterMetricsExposedOnMetricsEndpoint(t *testing.T) {
 	assert.Contains(t, metricsOutput, `version="v0.1.0-test"`,
 		"version label should be in output")
 }
+
+func TestMetricsServer_SLIHandler(t *testing.T) {
+	s := &MetricsServer{}
+
+	w := httptest.NewRecorder()
+	s.sliHandler(w, httptest.NewRequest(http.MethodGet, "/sli", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code, "/sli must return 404 until a handler is set")
+
+	s.SetSLIHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"total":{}}`))
+	}))
+	w = httptest.NewRecorder()
+	s.sliHandler(w, httptest.NewRequest(http.MethodGet, "/sli", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"total":{}}`, w.Body.String())
+}
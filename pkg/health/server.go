@@ -3,12 +3,16 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/version"
 )
 
 // CheckStatus represents the status of a single health check.
@@ -21,6 +25,18 @@ const (
 	CheckError CheckStatus = "error"
 )
 
+// Readiness checks, each served at /readyz/<name>
+const (
+	// CheckNameConfig is ok once the config is loaded
+	CheckNameConfig = "config"
+	// CheckNameBroker is ok while every broker subscription is active
+	CheckNameBroker = "broker"
+	// CheckNameTransport is ok while every transport client answers its probe
+	CheckNameTransport = "transport"
+	// CheckNameAPI is ok while the HyperFleet API answers its probe
+	CheckNameAPI = "api"
+)
+
 // HealthResponse represents the JSON response for /healthz endpoint.
 type HealthResponse struct {
 	Status  string `json:"status"`
@@ -34,15 +50,54 @@ type ReadyResponse struct {
 	Message string                 `json:"message,omitempty"`
 }
 
+// CheckResponse represents the JSON response for a /readyz/<name> endpoint: the state of a
+// single check.
+type CheckResponse struct {
+	// LastUpdate is when the check last changed or was probed, nil before the first result
+	LastUpdate *time.Time `json:"lastUpdate,omitempty"`
+	// Clients is the status of each client probed for a dependency check, e.g. each transport
+	Clients map[string]CheckStatus `json:"clients,omitempty"`
+	Name    string                 `json:"name"`
+	Status  CheckStatus            `json:"status"`
+	Message string                 `json:"message,omitempty"`
+	// Aggregated is true when the check is part of /readyz
+	Aggregated bool `json:"aggregated"`
+}
+
+// VersionResponse represents the JSON response for the /version endpoint: the build and
+// the config revision the replica runs.
+type VersionResponse struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildDate string `json:"buildDate"`
+	// ConfigHash is the hash of the task config used for new events
+	ConfigHash string `json:"configHash,omitempty"`
+	// ConfigFingerprint is the hash of the whole merged config used for new events
+	ConfigFingerprint string `json:"configFingerprint,omitempty"`
+}
+
+// checkState is the state of a readiness check
+type checkState struct {
+	updated time.Time
+	// clients holds the last probe error of each client of a dependency check, nil when ok
+	clients map[string]error
+	status  CheckStatus
+	message string
+}
+
 // Server provides HTTP health check endpoints.
 type Server struct {
-	log        logger.Logger
-	server     *http.Server
-	checks     map[string]CheckStatus
-	port       string
-	component  string
-	configYAML []byte // set only when debug_config is true
-	mu         sync.RWMutex
+	log    logger.Logger
+	server *http.Server
+	checks map[string]*checkState
+	// excluded are the checks served at /readyz/<name> but left out of /readyz
+	excluded          map[string]bool
+	port              string
+	component         string
+	configHash        string
+	configFingerprint string
+	configYAML        []byte // set only when debug_config is true
+	mu                sync.RWMutex
 	// shuttingDown is an atomic flag that indicates the server is shutting down.
 	// When true, /readyz immediately returns 503 regardless of other checks.
 	// This follows the HyperFleet Graceful Shutdown Standard.
@@ -55,16 +110,24 @@ func NewServer(log logger.Logger, port string, component string) *Server {
 		log:       log,
 		port:      port,
 		component: component,
-		checks: map[string]CheckStatus{
-			"config": CheckError,
-			"broker": CheckError,
+		checks: map[string]*checkState{
+			CheckNameConfig:    {status: CheckError, message: "config is not loaded"},
+			CheckNameBroker:    {status: CheckError, message: "broker subscription is not active"},
+			CheckNameTransport: {status: CheckError, message: "not probed yet"},
+			CheckNameAPI:       {status: CheckError, message: "not probed yet"},
+		},
+		excluded: map[string]bool{
+			CheckNameTransport: true,
+			CheckNameAPI:       true,
 		},
 	}
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", s.healthzHandler)
 	mux.HandleFunc("/readyz", s.readyzHandler)
+	mux.HandleFunc("/readyz/", s.readyzCheckHandler)
 	mux.HandleFunc("/config", s.configHandler)
+	mux.HandleFunc("/version", s.versionHandler)
 
 	s.server = &http.Server{
 		Addr:              ":" + port,
@@ -97,23 +160,73 @@ func (s *Server) Shutdown(ctx context.Context) error {
 
 // SetCheck sets the status of a specific health check.
 func (s *Server) SetCheck(name string, status CheckStatus) {
+	s.SetCheckResult(name, status, "")
+}
+
+// SetCheckResult sets the status of a specific health check, with a message describing it
+// in the /readyz/<name> detail.
+func (s *Server) SetCheckResult(name string, status CheckStatus, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks[name] = &checkState{status: status, message: message, updated: time.Now()}
+}
+
+// SetClientResult records the probe result of one client of a dependency check, such as
+// the kubernetes client of the transport check. The check is ok while all of its clients
+// are, and its message lists the errors of the others.
+func (s *Server) SetClientResult(name, client string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.checks[name]
+	if !ok || state.clients == nil {
+		state = &checkState{clients: map[string]error{}}
+		s.checks[name] = state
+	}
+	state.clients[client] = err
+	state.updated = time.Now()
+
+	var failed []string
+	for _, c := range slices.Sorted(maps.Keys(state.clients)) {
+		if clientErr := state.clients[c]; clientErr != nil {
+			failed = append(failed, c+": "+clientErr.Error())
+		}
+	}
+	state.status, state.message = CheckOK, ""
+	if len(failed) > 0 {
+		state.status, state.message = CheckError, strings.Join(failed, "; ")
+	}
+}
+
+// SetAggregated sets whether a check is part of /readyz. A check left out is still served
+// at /readyz/<name>. The transport and api checks are left out by default.
+func (s *Server) SetAggregated(name string, aggregated bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.checks[name] = status
+	s.excluded[name] = !aggregated
+}
+
+// Check returns the status of a specific health check. Unknown checks report CheckError.
+func (s *Server) Check(name string) CheckStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if state, ok := s.checks[name]; ok {
+		return state.status
+	}
+	return CheckError
 }
 
 // SetBrokerReady sets the broker check status.
 func (s *Server) SetBrokerReady(ready bool) {
 	if ready {
-		s.SetCheck("broker", CheckOK)
+		s.SetCheck(CheckNameBroker, CheckOK)
 	} else {
-		s.SetCheck("broker", CheckError)
+		s.SetCheckResult(CheckNameBroker, CheckError, "broker subscription is not active")
 	}
 }
 
 // SetConfigLoaded marks the config check as ok.
 func (s *Server) SetConfigLoaded() {
-	s.SetCheck("config", CheckOK)
+	s.SetCheck(CheckNameConfig, CheckOK)
 }
 
 // SetConfig stores pre-marshaled YAML config to serve at /config.
@@ -124,6 +237,15 @@ func (s *Server) SetConfig(data []byte) {
 	s.configYAML = data
 }
 
+// SetConfigVersion sets the task config hash and config fingerprint served at /version.
+// Call it again when a config reload changes them.
+func (s *Server) SetConfigVersion(hash, fingerprint string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.configHash = hash
+	s.configFingerprint = fingerprint
+}
+
 // SetShuttingDown marks the server as shutting down.
 // When set to true, /readyz will immediately return 503 Service Unavailable
 // regardless of other check statuses. This follows the HyperFleet Graceful
@@ -137,7 +259,7 @@ func (s *Server) IsShuttingDown() bool {
 	return s.shuttingDown.Load()
 }
 
-// IsReady returns true if all checks are passing and server is not shutting down.
+// IsReady returns true if all aggregated checks are passing and server is not shutting down.
 func (s *Server) IsReady() bool {
 	// Check shutdown flag first (atomic, no lock needed)
 	if s.shuttingDown.Load() {
@@ -147,8 +269,8 @@ func (s *Server) IsReady() bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	for _, status := range s.checks {
-		if status != CheckOK {
+	for name, state := range s.checks {
+		if !s.excluded[name] && state.status != CheckOK {
 			return false
 		}
 	}
@@ -164,8 +286,8 @@ func (s *Server) healthzHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // readyzHandler handles readiness probe requests.
-// Returns 200 OK with detailed checks if all checks pass,
-// 503 Service Unavailable if shutting down or any check fails.
+// Returns 200 OK with detailed checks if all aggregated checks pass,
+// 503 Service Unavailable if shutting down or any aggregated check fails.
 func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
@@ -184,9 +306,12 @@ func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
 	s.mu.RLock()
 	checks := make(map[string]CheckStatus, len(s.checks))
 	allOK := true
-	for name, status := range s.checks {
-		checks[name] = status
-		if status != CheckOK {
+	for name, state := range s.checks {
+		if s.excluded[name] {
+			continue
+		}
+		checks[name] = state.status
+		if state.status != CheckOK {
 			allOK = false
 		}
 	}
@@ -211,6 +336,73 @@ func (s *Server) readyzHandler(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// readyzCheckHandler serves a single check at /readyz/<name>, whether or not it is part of
+// /readyz. Returns 200 OK when the check passes, 503 Service Unavailable when it fails or
+// the server is shutting down, and 404 for unknown checks.
+func (s *Server) readyzCheckHandler(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/readyz/")
+	w.Header().Set("Content-Type", "application/json")
+
+	s.mu.RLock()
+	state, ok := s.checks[name]
+	var resp CheckResponse
+	if ok {
+		resp = CheckResponse{
+			Name:       name,
+			Status:     state.status,
+			Message:    state.message,
+			Aggregated: !s.excluded[name],
+		}
+		if !state.updated.IsZero() {
+			updated := state.updated
+			resp.LastUpdate = &updated
+		}
+		if len(state.clients) > 0 {
+			resp.Clients = make(map[string]CheckStatus, len(state.clients))
+			for client, err := range state.clients {
+				resp.Clients[client] = CheckOK
+				if err != nil {
+					resp.Clients[client] = CheckError
+				}
+			}
+		}
+	}
+	s.mu.RUnlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		//nolint:errcheck // best-effort response
+		_ = json.NewEncoder(w).Encode(ReadyResponse{Status: "error", Message: "unknown check " + name})
+		return
+	}
+	if s.shuttingDown.Load() {
+		resp.Status, resp.Message = CheckError, "server is shutting down"
+	}
+	if resp.Status == CheckOK {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(resp) //nolint:errcheck // best-effort response
+}
+
+// versionHandler serves the build version and the active config revision as JSON
+func (s *Server) versionHandler(w http.ResponseWriter, r *http.Request) {
+	info := version.Info()
+	s.mu.RLock()
+	resp := VersionResponse{
+		Version:           info.Version,
+		Commit:            info.Commit,
+		BuildDate:         info.BuildDate,
+		ConfigHash:        s.configHash,
+		ConfigFingerprint: s.configFingerprint,
+	}
+	s.mu.RUnlock()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp) //nolint:errcheck // best-effort response
+}
+
 // configHandler serves the current adapter configuration as YAML.
 // Returns 404 if debug_config is not enabled (SetConfig was never called).
 func (s *Server) configHandler(w http.ResponseWriter, r *http.Request) {
@@ -3,12 +3,14 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/logger"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -50,6 +52,26 @@ func TestHealthzHandler(t *testing.T) {
 	assert.Empty(t, response.Message)
 }
 
+func TestVersionHandler(t *testing.T) {
+	server := NewServer(&mockLogger{}, "8080", "test-adapter")
+	server.SetConfigVersion("c6e546f029bc", "adcace461a31")
+
+	req := httptest.NewRequest(http.MethodGet, "/version", nil)
+	w := httptest.NewRecorder()
+
+	server.versionHandler(w, req)
+
+	resp := w.Result()
+	defer func() { _ = resp.Body.Close() }()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var response VersionResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, version.Version, response.Version)
+	assert.Equal(t, "c6e546f029bc", response.ConfigHash)
+	assert.Equal(t, "adcace461a31", response.ConfigFingerprint)
+}
+
 func TestReadyzHandler_NotReady(t *testing.T) {
 	server := NewServer(&mockLogger{}, "8080", "test-adapter")
 	// By default, checks are in error state
@@ -439,3 +461,75 @@ func TestServerLifecycle_ShutdownTimeout(t *testing.T) {
 	err = server.Shutdown(shutdownCtx)
 	require.NoError(t, err)
 }
+
+func TestReadyzCheckHandler(t *testing.T) {
+	server := NewServer(&mockLogger{}, "8080", "test-adapter")
+	server.SetConfigLoaded()
+	server.SetBrokerReady(true)
+	server.SetClientResult(CheckNameTransport, "kubernetes", nil)
+	server.SetClientResult(CheckNameTransport, "maestro", errors.New("connection refused"))
+
+	get := func(path string) (int, CheckResponse) {
+		w := httptest.NewRecorder()
+		server.server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		var response CheckResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return w.Code, response
+	}
+
+	code, broker := get("/readyz/broker")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, CheckOK, broker.Status)
+	assert.True(t, broker.Aggregated)
+	assert.NotNil(t, broker.LastUpdate)
+
+	code, transport := get("/readyz/transport")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "maestro: connection refused", transport.Message)
+	assert.Equal(t, map[string]CheckStatus{"kubernetes": CheckOK, "maestro": CheckError}, transport.Clients)
+	assert.False(t, transport.Aggregated)
+
+	code, api := get("/readyz/api")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not probed yet", api.Message)
+	assert.Nil(t, api.LastUpdate)
+
+	code, _ = get("/readyz/unknown")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	server.SetShuttingDown(true)
+	code, broker = get("/readyz/broker")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "server is shutting down", broker.Message)
+}
+
+func TestReadyzHandler_Aggregation(t *testing.T) {
+	server := NewServer(&mockLogger{}, "8080", "test-adapter")
+	server.SetConfigLoaded()
+	server.SetBrokerReady(true)
+	server.SetClientResult(CheckNameTransport, "kubernetes", errors.New("connection refused"))
+	assert.True(t, server.IsReady(), "the transport check is not aggregated by default")
+
+	server.SetAggregated(CheckNameTransport, true)
+	assert.False(t, server.IsReady())
+
+	server.SetAggregated(CheckNameBroker, false)
+	server.SetBrokerReady(false)
+	server.SetClientResult(CheckNameTransport, "kubernetes", nil)
+	assert.True(t, server.IsReady(), "a broker left out of the aggregate does not affect readiness")
+
+	w := httptest.NewRecorder()
+	server.readyzHandler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var response ReadyResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+	assert.Equal(t, map[string]CheckStatus{CheckNameConfig: CheckOK, CheckNameTransport: CheckOK}, response.Checks)
+}
+
+func TestServerCheck(t *testing.T) {
+	server := NewServer(&mockLogger{}, "8080", "test-adapter")
+
+	assert.Equal(t, CheckError, server.Check("broker"))
+	server.SetBrokerReady(true)
+	assert.Equal(t, CheckOK, server.Check("broker"))
+	assert.Equal(t, CheckError, server.Check("unknown"), "unknown checks are not ok")
+}
@@ -12,7 +12,8 @@ type contextKey string
 
 // Context keys for storing values in context.Context
 const (
-	LogFieldsKey contextKey = "log_fields"
+	LogFieldsKey     contextKey = "log_fields"
+	DebugOverrideKey contextKey = "debug_override"
 )
 
 // Log field name constants - use these directly in WithFields maps
@@ -80,6 +81,22 @@ func WithLogFields(ctx context.Context, newFields LogFields) context.Context {
 	return context.WithValue(ctx, LogFieldsKey, fields)
 }
 
+// WithDebugOverride returns a context whose log entries are written down to debug level,
+// whatever the level of the logger. Used to debug the events of a single resource
+// without raising the verbosity of all the others.
+func WithDebugOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, DebugOverrideKey, true)
+}
+
+// IsDebugOverride reports whether ctx was marked with WithDebugOverride
+func IsDebugOverride(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	override, ok := ctx.Value(DebugOverrideKey).(bool)
+	return ok && override
+}
+
 // WithDynamicResourceID adds a resource ID as a dynamic log field
 // The field name is derived from the resource type (e.g., "Cluster" -> "cluster_id", "NodePool" -> "nodepool_id")
 func WithDynamicResourceID(ctx context.Context, resourceType string, resourceID string) context.Context {
@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"sync/atomic"
 )
 
 const (
@@ -44,11 +45,35 @@ type Logger interface {
 	Without(key string) Logger
 }
 
-var _ Logger = &logger{}
+// LevelController is implemented by loggers whose minimum level can be changed at runtime.
+// Loggers derived with With, WithFields and Without share the level of their parent.
+type LevelController interface {
+	// Level returns the current minimum level: "debug", "info", "warn" or "error"
+	Level() string
+	// SetLevel changes the minimum level; it rejects unknown levels
+	SetLevel(level string) error
+}
+
+// FormatController is implemented by loggers whose output format can be changed at
+// runtime. Loggers derived with With, WithFields and Without share the format of their parent.
+type FormatController interface {
+	// Format returns the current output format: FormatJSON or FormatText
+	Format() string
+	// SetFormat changes the output format; it rejects unknown formats
+	SetFormat(format string) error
+}
+
+var (
+	_ Logger           = &logger{}
+	_ LevelController  = &logger{}
+	_ FormatController = &logger{}
+)
 
 // logger is the concrete implementation using log/slog
 type logger struct {
 	slog      *slog.Logger
+	level     *slog.LevelVar
+	useText   *atomic.Bool
 	fields    map[string]interface{}
 	component string
 	version   string
@@ -67,6 +92,8 @@ type Config struct {
 	// Writer is an optional custom io.Writer for log output.
 	// If set, Output is ignored. Useful for testing (e.g., bytes.Buffer).
 	Writer io.Writer
+	// Sampling limits identical warning and error records. Nil logs every record.
+	Sampling *SamplingConfig
 	// Component is the component name (e.g., "adapter", "sentinel")
 	Component string
 	// Version is the component version
@@ -121,8 +148,9 @@ func NewLogger(cfg Config) (Logger, error) {
 		}
 	}
 
-	// Parse log level
-	level := parseLevel(cfg.Level)
+	// Parse log level; the LevelVar lets SetLevel change it at runtime
+	level := new(slog.LevelVar)
+	level.Set(parseLevel(cfg.Level))
 
 	// Create handler options
 	opts := &slog.HandlerOptions{
@@ -131,15 +159,18 @@ func NewLogger(cfg Config) (Logger, error) {
 		AddSource: false,
 	}
 
-	// Create handler based on format
-	var handler slog.Handler
-	switch strings.ToLower(cfg.Format) {
-	case FormatJSON:
-		handler = slog.NewJSONHandler(writer, opts)
-	case FormatText:
-		handler = slog.NewTextHandler(writer, opts)
-	default:
-		return nil, fmt.Errorf("invalid log format %q: must be %q or %q", cfg.Format, FormatJSON, FormatText)
+	// Create both handlers so SetFormat can switch between them at runtime
+	useText := new(atomic.Bool)
+	if err := setFormat(useText, cfg.Format); err != nil {
+		return nil, err
+	}
+	var handler slog.Handler = &runtimeHandler{
+		useText: useText,
+		json:    slog.NewJSONHandler(writer, opts),
+		text:    slog.NewTextHandler(writer, opts),
+	}
+	if cfg.Sampling != nil {
+		handler = newSamplingHandler(handler, *cfg.Sampling)
 	}
 
 	// Get hostname
@@ -160,6 +191,8 @@ func NewLogger(cfg Config) (Logger, error) {
 
 	return &logger{
 		slog:      slogLogger,
+		level:     level,
+		useText:   useText,
 		fields:    make(map[string]interface{}),
 		component: cfg.Component,
 		version:   cfg.Version,
@@ -167,20 +200,117 @@ func NewLogger(cfg Config) (Logger, error) {
 	}, nil
 }
 
+// Log level names
+const (
+	levelDebug   = "debug"
+	levelInfo    = "info"
+	levelWarn    = "warn"
+	levelWarning = "warning"
+	levelError   = "error"
+)
+
 // parseLevel converts string level to slog.Level
 func parseLevel(level string) slog.Level {
 	switch strings.ToLower(level) {
-	case "debug":
+	case levelDebug:
 		return slog.LevelDebug
-	case "warn", "warning":
+	case levelWarn, levelWarning:
 		return slog.LevelWarn
-	case "error":
+	case levelError:
 		return slog.LevelError
 	default:
 		return slog.LevelInfo
 	}
 }
 
+// Level returns the current minimum level
+func (l *logger) Level() string {
+	switch level := l.level.Level(); {
+	case level <= slog.LevelDebug:
+		return levelDebug
+	case level <= slog.LevelInfo:
+		return levelInfo
+	case level <= slog.LevelWarn:
+		return levelWarn
+	default:
+		return levelError
+	}
+}
+
+// SetLevel changes the minimum level of the logger and of all loggers derived from it
+func (l *logger) SetLevel(level string) error {
+	switch strings.ToLower(level) {
+	case levelDebug, levelInfo, levelWarn, levelWarning, levelError:
+		l.level.Set(parseLevel(level))
+		return nil
+	default:
+		return fmt.Errorf("invalid log level %q: must be debug, info, warn or error", level)
+	}
+}
+
+// Format returns the current output format
+func (l *logger) Format() string {
+	if l.useText.Load() {
+		return FormatText
+	}
+	return FormatJSON
+}
+
+// SetFormat changes the output format of the logger and of all loggers derived from it
+func (l *logger) SetFormat(format string) error {
+	return setFormat(l.useText, format)
+}
+
+func setFormat(useText *atomic.Bool, format string) error {
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		useText.Store(false)
+	case FormatText:
+		useText.Store(true)
+	default:
+		return fmt.Errorf("invalid log format %q: must be %q or %q", format, FormatJSON, FormatText)
+	}
+	return nil
+}
+
+// runtimeHandler writes records with the JSON or the text handler, whichever format is
+// current, and enables debug records for contexts marked with WithDebugOverride.
+type runtimeHandler struct {
+	useText *atomic.Bool
+	json    slog.Handler
+	text    slog.Handler
+}
+
+func (h *runtimeHandler) current() slog.Handler {
+	if h.useText.Load() {
+		return h.text
+	}
+	return h.json
+}
+
+// Enabled reports whether the level is enabled, or whether ctx overrides it
+func (h *runtimeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level >= slog.LevelDebug && IsDebugOverride(ctx) {
+		return true
+	}
+	return h.current().Enabled(ctx, level)
+}
+
+// Handle writes the record in the current format
+func (h *runtimeHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.current().Handle(ctx, record)
+}
+
+// WithAttrs returns a handler adding attrs in either format
+func (h *runtimeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &runtimeHandler{useText: h.useText, json: h.json.WithAttrs(attrs), text: h.text.WithAttrs(attrs)}
+}
+
+// WithGroup returns a handler nesting attributes under name in either format
+func (h *runtimeHandler) WithGroup(name string) slog.Handler {
+	return &runtimeHandler{useText: h.useText, json: h.json.WithGroup(name), text: h.text.WithGroup(name)}
+}
+
 // buildArgs builds the slog args from fields and context
 func (l *logger) buildArgs(ctx context.Context) []any {
 	args := make([]any, 0, len(l.fields)*2+10)
@@ -266,6 +396,8 @@ func (l *logger) With(key string, value interface{}) Logger {
 	newFields[key] = value
 	return &logger{
 		slog:      l.slog,
+		level:     l.level,
+		useText:   l.useText,
 		fields:    newFields,
 		component: l.component,
 		version:   l.version,
@@ -281,6 +413,8 @@ func (l *logger) WithFields(fields map[string]interface{}) Logger {
 	}
 	return &logger{
 		slog:      l.slog,
+		level:     l.level,
+		useText:   l.useText,
 		fields:    newFields,
 		component: l.component,
 		version:   l.version,
@@ -295,6 +429,8 @@ func (l *logger) Without(key string) Logger {
 	delete(newFields, key)
 	return &logger{
 		slog:      l.slog,
+		level:     l.level,
+		useText:   l.useText,
 		fields:    newFields,
 		component: l.component,
 		version:   l.version,
@@ -1,14 +1,16 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"strings"
 	"testing"
 )
 
 func TestNewLogger(t *testing.T) {
 	tests := []struct {
-		name   string
 		config Config
+		name   string
 	}{
 		{
 			name:   "create_logger_with_default_config",
@@ -293,6 +295,106 @@ func TestLoggerChaining(t *testing.T) {
 	})
 }
 
+func TestLoggerSetLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := NewLogger(Config{Level: "info", Format: FormatText, Writer: &buf, Component: "test"})
+	if err != nil {
+		t.Fatalf("NewLogger returned error: %v", err)
+	}
+	ctx := context.Background()
+	derived := log.With("key", "value")
+	controller, ok := log.(LevelController)
+	if !ok {
+		t.Fatal("logger does not implement LevelController")
+	}
+
+	derived.Debug(ctx, "hidden debug")
+	if err := controller.SetLevel(levelDebug); err != nil {
+		t.Fatalf("SetLevel returned error: %v", err)
+	}
+	if got := controller.Level(); got != levelDebug {
+		t.Errorf("Level() = %q, want debug", got)
+	}
+	derived.Debug(ctx, "visible debug")
+
+	if strings.Contains(buf.String(), "hidden debug") {
+		t.Error("debug message logged before the level was lowered")
+	}
+	if !strings.Contains(buf.String(), "visible debug") {
+		t.Error("derived logger did not follow the new level")
+	}
+
+	if err := controller.SetLevel("verbose"); err == nil {
+		t.Error("SetLevel accepted an unknown level")
+	}
+	if got := controller.Level(); got != levelDebug {
+		t.Errorf("Level() = %q after a rejected change, want debug", got)
+	}
+}
+
+func TestLoggerSetFormat(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := NewLogger(Config{Level: "info", Format: FormatText, Writer: &buf, Component: "test"})
+	if err != nil {
+		t.Fatalf("NewLogger returned error: %v", err)
+	}
+	ctx := context.Background()
+	derived := log.With("key", "value")
+	controller, ok := log.(FormatController)
+	if !ok {
+		t.Fatal("logger does not implement FormatController")
+	}
+
+	derived.Info(ctx, "text message")
+	if err := controller.SetFormat(FormatJSON); err != nil {
+		t.Fatalf("SetFormat returned error: %v", err)
+	}
+	if got := controller.Format(); got != FormatJSON {
+		t.Errorf("Format() = %q, want json", got)
+	}
+	derived.Info(ctx, "json message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %s", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[0], "msg=\"text message\"") || !strings.Contains(lines[0], "key=value") {
+		t.Errorf("first line is not text: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"msg":"json message"`) || !strings.Contains(lines[1], `"component":"test"`) {
+		t.Errorf("derived logger did not follow the new format: %s", lines[1])
+	}
+
+	if err := controller.SetFormat("xml"); err == nil {
+		t.Error("SetFormat accepted an unknown format")
+	}
+	if got := controller.Format(); got != FormatJSON {
+		t.Errorf("Format() = %q after a rejected change, want json", got)
+	}
+}
+
+func TestLoggerDebugOverride(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := NewLogger(Config{Level: "info", Format: FormatText, Writer: &buf, Component: "test"})
+	if err != nil {
+		t.Fatalf("NewLogger returned error: %v", err)
+	}
+	ctx := context.Background()
+
+	log.Debug(ctx, "hidden debug")
+	log.Debug(WithDebugOverride(ctx), "overridden debug")
+
+	if strings.Contains(buf.String(), "hidden debug") {
+		t.Error("debug message logged without the override")
+	}
+	if !strings.Contains(buf.String(), "overridden debug") {
+		t.Error("debug message not logged with the override")
+	}
+	if IsDebugOverride(ctx) || !IsDebugOverride(WithDebugOverride(ctx)) {
+		t.Error("IsDebugOverride does not reflect WithDebugOverride")
+	}
+}
+
 func TestFieldConstants(t *testing.T) {
 	tests := []struct {
 		name     string
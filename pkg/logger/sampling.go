@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// Sampling defaults, used for unset SamplingConfig fields
+const (
+	DefaultSamplingFirst      = 10
+	DefaultSamplingThereafter = 100
+	DefaultSamplingWindow     = time.Minute
+)
+
+// SamplingConfig limits identical warning and error records, such as the same failure of
+// the same step for the same cluster logged for every event while a downstream is broken.
+// Within each window the first First identical records are logged, then one in every
+// Thereafter; the number of records dropped is logged when the window ends.
+type SamplingConfig struct {
+	// First is the number of identical records logged per window. 0 uses DefaultSamplingFirst.
+	First int `yaml:"first,omitempty" mapstructure:"first"`
+	// Thereafter logs one in every Thereafter identical records after the first ones.
+	// 0 uses DefaultSamplingThereafter.
+	Thereafter int `yaml:"thereafter,omitempty" mapstructure:"thereafter"`
+	// Window is the period identical records are counted over. 0 uses DefaultSamplingWindow.
+	Window utils.Duration `yaml:"window,omitempty" mapstructure:"window"`
+}
+
+// WithDefaults returns a copy with unset fields replaced by their defaults
+func (c SamplingConfig) WithDefaults() SamplingConfig {
+	if c.First == 0 {
+		c.First = DefaultSamplingFirst
+	}
+	if c.Thereafter == 0 {
+		c.Thereafter = DefaultSamplingThereafter
+	}
+	if c.Window == 0 {
+		c.Window = utils.Duration(DefaultSamplingWindow)
+	}
+	return c
+}
+
+// sampler counts identical records per window; it is shared by the handlers derived
+// from one sampling handler
+type sampler struct {
+	counts map[string]*sampleCount
+	config SamplingConfig
+	mu     sync.Mutex
+}
+
+// sampleCount counts the records of one key within the current window
+type sampleCount struct {
+	next       slog.Handler
+	message    string
+	attrs      []slog.Attr
+	level      slog.Level
+	seen       int
+	suppressed int
+}
+
+// samplingHandler drops identical warning and error records beyond the sampling rate.
+// Records are identical when they have the same level, message, error and resource IDs.
+type samplingHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+func newSamplingHandler(next slog.Handler, config SamplingConfig) *samplingHandler {
+	return &samplingHandler{
+		next:    next,
+		sampler: &sampler{config: config.WithDefaults(), counts: make(map[string]*sampleCount)},
+	}
+}
+
+// Enabled reports whether next handles the level
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle writes the record unless it is sampled out
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelWarn && !h.sampler.allow(h.next, record) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+// WithAttrs returns a handler adding attrs, sharing the sampling counts
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup returns a handler nesting attributes under name, sharing the sampling counts
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+// allow counts record and reports whether it is within the sampling rate. The first
+// record of a key starts its window; the count is dropped, and the suppressed records
+// reported, when the window ends.
+func (s *sampler) allow(next slog.Handler, record slog.Record) bool {
+	key, attrs := sampleKey(record)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count, ok := s.counts[key]
+	if !ok {
+		count = &sampleCount{next: next, message: record.Message, attrs: attrs, level: record.Level}
+		s.counts[key] = count
+		time.AfterFunc(s.config.Window.Std(), func() { s.expire(key, count) })
+	}
+	count.seen++
+	if count.seen <= s.config.First || (count.seen-s.config.First)%s.config.Thereafter == 0 {
+		return true
+	}
+	count.suppressed++
+	return false
+}
+
+// expire ends the window of key and logs how many of its records were suppressed
+func (s *sampler) expire(key string, count *sampleCount) {
+	s.mu.Lock()
+	if s.counts[key] == count {
+		delete(s.counts, key)
+	}
+	suppressed := count.suppressed
+	s.mu.Unlock()
+	if suppressed == 0 {
+		return
+	}
+
+	summary := slog.NewRecord(time.Now(), slog.LevelWarn, "Suppressed repeated log messages", 0)
+	summary.AddAttrs(
+		slog.String("suppressed_message", count.message),
+		slog.String("suppressed_level", count.level.String()),
+		slog.Int("suppressed_count", suppressed),
+		slog.String("sampling_window", s.config.Window.String()),
+	)
+	summary.AddAttrs(count.attrs...)
+	_ = count.next.Handle(context.Background(), summary) //nolint:errcheck // nowhere to report a log write error
+}
+
+// sampleKey returns the key of the records identical to record and the attributes it
+// was built from: its level, message, error and resource IDs. Trace, span and event IDs
+// differ between the deliveries of a repeated failure and are left out.
+func sampleKey(record slog.Record) (string, []slog.Attr) {
+	var key strings.Builder
+	key.WriteString(record.Level.String())
+	key.WriteByte(0)
+	key.WriteString(record.Message)
+
+	var attrs []slog.Attr
+	record.Attrs(func(attr slog.Attr) bool {
+		switch {
+		case attr.Key == TraceIDKey, attr.Key == SpanIDKey, attr.Key == EventIDKey:
+		case attr.Key == ErrorKey, strings.HasSuffix(attr.Key, "_id"):
+			attrs = append(attrs, attr)
+		}
+		return true
+	})
+	// Record attributes come from a map, so their order varies between records
+	slices.SortFunc(attrs, func(a, b slog.Attr) int { return strings.Compare(a.Key, b.Key) })
+	for _, attr := range attrs {
+		key.WriteByte(0)
+		key.WriteString(attr.Key)
+		key.WriteByte('=')
+		key.WriteString(attr.Value.String())
+	}
+	return key.String(), attrs
+}
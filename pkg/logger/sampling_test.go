@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/utils"
+)
+
+// syncBuffer is a bytes.Buffer safe for the writes of the sampling window timers
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestSamplingConfigWithDefaults(t *testing.T) {
+	cfg := SamplingConfig{}.WithDefaults()
+	if cfg.First != DefaultSamplingFirst || cfg.Thereafter != DefaultSamplingThereafter ||
+		cfg.Window.Std() != DefaultSamplingWindow {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+
+	cfg = SamplingConfig{First: 1, Thereafter: 2, Window: utils.Duration(time.Second)}.WithDefaults()
+	if cfg.First != 1 || cfg.Thereafter != 2 || cfg.Window.Std() != time.Second {
+		t.Errorf("expected set fields to be kept, got %+v", cfg)
+	}
+}
+
+func TestLoggerSampling(t *testing.T) {
+	var buf syncBuffer
+	log, err := NewLogger(Config{
+		Level:     "debug",
+		Format:    FormatJSON,
+		Component: "test",
+		Version:   "v1.0.0",
+		Writer:    &buf,
+		Sampling:  &SamplingConfig{First: 2, Thereafter: 3, Window: utils.Duration(100 * time.Millisecond)},
+	})
+	if err != nil {
+		t.Fatalf("NewLogger failed: %v", err)
+	}
+
+	downstreamErr := errors.New("connection refused")
+	for i := 0; i < 10; i++ {
+		// Each delivery has its own event and trace IDs; the same cluster failure is still identical
+		ctx := WithDynamicResourceID(context.Background(), "Cluster", "cluster-1")
+		ctx = WithEventID(ctx, "event-"+string(rune('a'+i)))
+		log.Errorf(WithErrorField(ctx, downstreamErr), "Failed to apply resource")
+	}
+	otherCtx := WithDynamicResourceID(context.Background(), "Cluster", "cluster-2")
+	log.Errorf(WithErrorField(otherCtx, downstreamErr), "Failed to apply resource")
+	for i := 0; i < 5; i++ {
+		log.Info(context.Background(), "Event processed")
+	}
+
+	output := buf.String()
+	// Records 1, 2, 5 and 8 of cluster-1 are within the rate
+	if got := strings.Count(output, `"cluster_id":"cluster-1"`); got != 4 {
+		t.Errorf("expected 4 cluster-1 errors to be logged, got %d:\n%s", got, output)
+	}
+	if got := strings.Count(output, `"cluster_id":"cluster-2"`); got != 1 {
+		t.Errorf("expected the cluster-2 error to be logged, got %d", got)
+	}
+	if got := strings.Count(output, "Event processed"); got != 5 {
+		t.Errorf("expected info records not to be sampled, got %d", got)
+	}
+	if strings.Contains(output, "Suppressed repeated log messages") {
+		t.Error("expected no suppression summary before the window ends")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(buf.String(), "Suppressed repeated log messages") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	output = buf.String()
+	for _, want := range []string{
+		`"suppressed_message":"Failed to apply resource"`,
+		`"suppressed_level":"ERROR"`,
+		`"suppressed_count":6`,
+		`"sampling_window":"100ms"`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected suppression summary to contain %s, got:\n%s", want, output)
+		}
+	}
+	if got := strings.Count(output, "Suppressed repeated log messages"); got != 1 {
+		t.Errorf("expected one summary, for cluster-1 only, got %d", got)
+	}
+
+	// A new window logs the first records again
+	ctx := WithDynamicResourceID(context.Background(), "Cluster", "cluster-1")
+	log.Errorf(WithErrorField(ctx, downstreamErr), "Failed to apply resource")
+	if got := strings.Count(buf.String(), `"cluster_id":"cluster-1"`); got != 6 {
+		t.Errorf("expected the first record of a new window to be logged, got %d cluster-1 records", got)
+	}
+}
@@ -0,0 +1,160 @@
+package maestrofake
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// labelsPrefix is the JSONB path Maestro searches resource bundle labels with
+const labelsPrefix = `payload->'metadata'->'labels'`
+
+// filter matches the resource bundles selected by a search
+type filter func(b *bundle) bool
+
+func (f filter) matches(b *bundle) bool {
+	return f == nil || f(b)
+}
+
+// parseSearch parses the subset of the Maestro search language the Maestro client sends:
+// source and consumer_name equality, the label queries generated from label selectors,
+// and "and", "or" and parentheses combining them. Other searches are rejected so a test
+// notices the fake does not support them.
+func parseSearch(search string) (filter, error) {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return nil, nil
+	}
+	if parts := splitTopLevel(search, " or "); len(parts) > 1 {
+		return combine(parts, false)
+	}
+	if parts := splitTopLevel(search, " and "); len(parts) > 1 {
+		return combine(parts, true)
+	}
+	if strings.HasPrefix(search, "(") && strings.HasSuffix(search, ")") {
+		return parseSearch(search[1 : len(search)-1])
+	}
+	return parseClause(search)
+}
+
+// combine parses parts and joins them with "and" when all is set, else with "or"
+func combine(parts []string, all bool) (filter, error) {
+	filters := make([]filter, 0, len(parts))
+	for _, part := range parts {
+		f, err := parseSearch(part)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, f)
+	}
+	return func(b *bundle) bool {
+		for _, f := range filters {
+			if f.matches(b) != all {
+				return !all
+			}
+		}
+		return all
+	}, nil
+}
+
+// splitTopLevel splits s on sep outside quotes and parentheses
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth, start := 0, 0
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'':
+			quoted = !quoted
+		case quoted:
+		case s[i] == '(':
+			depth++
+		case s[i] == ')':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// parseClause parses a single comparison
+func parseClause(clause string) (filter, error) {
+	if value, ok := cutQuoted(clause, "source="); ok {
+		return func(b *bundle) bool { return b.source == value }, nil
+	}
+	if value, ok := cutQuoted(clause, "consumer_name="); ok {
+		return func(b *bundle) bool { return b.work.Namespace == value }, nil
+	}
+	if rest, ok := strings.CutPrefix(clause, labelsPrefix+"@>"); ok {
+		raw, ok := unquote(rest)
+		if !ok {
+			return nil, fmt.Errorf("unsupported search %q", clause)
+		}
+		want := map[string]string{}
+		if err := json.Unmarshal([]byte(raw), &want); err != nil {
+			return nil, fmt.Errorf("invalid label search %q: %w", clause, err)
+		}
+		return func(b *bundle) bool {
+			for k, v := range want {
+				if got, ok := b.work.Labels[k]; !ok || got != v {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+	if rest, ok := strings.CutPrefix(clause, labelsPrefix+"->>"); ok {
+		return parseLabelComparison(clause, rest)
+	}
+	return nil, fmt.Errorf("unsupported search %q", clause)
+}
+
+// parseLabelComparison parses "'key'<>'value'", "'key'<>null" and "'key'in('a','b')"
+func parseLabelComparison(clause, rest string) (filter, error) {
+	end := strings.Index(rest[1:], "'")
+	if !strings.HasPrefix(rest, "'") || end < 0 {
+		return nil, fmt.Errorf("unsupported search %q", clause)
+	}
+	key, op := rest[1:end+1], rest[end+2:]
+
+	if op == "<>null" {
+		return func(b *bundle) bool { _, ok := b.work.Labels[key]; return ok }, nil
+	}
+	if value, ok := cutQuoted(op, "<>"); ok {
+		return func(b *bundle) bool { return b.work.Labels[key] != value }, nil
+	}
+	if list, ok := strings.CutPrefix(op, "in("); ok && strings.HasSuffix(list, ")") {
+		values := map[string]bool{}
+		for _, item := range strings.Split(strings.TrimSuffix(list, ")"), ",") {
+			value, ok := unquote(strings.TrimSpace(item))
+			if !ok {
+				return nil, fmt.Errorf("unsupported search %q", clause)
+			}
+			values[value] = true
+		}
+		return func(b *bundle) bool {
+			value, ok := b.work.Labels[key]
+			return ok && values[value]
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported search %q", clause)
+}
+
+// cutQuoted returns the quoted value after prefix
+func cutQuoted(s, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(s), prefix)
+	if !ok {
+		return "", false
+	}
+	return unquote(strings.TrimSpace(rest))
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '\'' || s[len(s)-1] != '\'' {
+		return "", false
+	}
+	return s[1 : len(s)-1], true
+}
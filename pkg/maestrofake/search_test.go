@@ -0,0 +1,57 @@
+package maestrofake
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestParseSearch(t *testing.T) {
+	b := &bundle{
+		source: "adapter",
+		work: &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{
+			Name:      "work",
+			Namespace: "cluster-1",
+			Labels:    map[string]string{"app": "a", "tier": "web"},
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		search string
+		want   bool
+	}{
+		{name: "empty search matches everything", search: "", want: true},
+		{name: "source", search: "source='adapter'", want: true},
+		{name: "other source", search: "source='other'", want: false},
+		{name: "source and consumer", search: "source='adapter' and consumer_name='cluster-1'", want: true},
+		{name: "other consumer", search: "source='adapter' and consumer_name='cluster-2'", want: false},
+		{
+			name:   "consumer alternatives",
+			search: "source='adapter' and (consumer_name='cluster-2' or consumer_name='cluster-1')",
+			want:   true,
+		},
+		{name: "label equality", search: `payload->'metadata'->'labels'@>'{"app":"a","tier":"web"}'`, want: true},
+		{name: "label mismatch", search: `payload->'metadata'->'labels'@>'{"app":"b"}'`, want: false},
+		{name: "label not equal", search: "payload->'metadata'->'labels'->>'app'<>'b'", want: true},
+		{name: "label exists", search: "payload->'metadata'->'labels'->>'tier'<>null", want: true},
+		{name: "label missing", search: "payload->'metadata'->'labels'->>'zone'<>null", want: false},
+		{name: "label in", search: "payload->'metadata'->'labels'->>'app'in('a','b')", want: true},
+		{name: "label not in", search: "payload->'metadata'->'labels'->>'app'in('b','c')", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := parseSearch(tt.search)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.matches(b))
+		})
+	}
+
+	t.Run("unsupported search", func(t *testing.T) {
+		_, err := parseSearch("name like 'work%'")
+		require.Error(t, err)
+	})
+}
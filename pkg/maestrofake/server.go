@@ -0,0 +1,585 @@
+// Package maestrofake is an in-memory fake of the Maestro server for tests and local
+// development. It serves the subset of Maestro used by the adapter's Maestro client:
+//
+//   - the CloudEvents gRPC service, receiving ManifestWork create, update and delete
+//     requests and streaming status updates and heartbeats to subscribed sources
+//   - the HTTP API listing consumers and getting and searching resource bundles
+//
+// The fake also plays the agent: every work it receives is reported Applied and
+// Available at once, unless a test sets other conditions with SetConditions.
+package maestrofake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/google/uuid"
+	"github.com/openshift-online/maestro/pkg/api/openapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/common"
+	agentcodec "open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/agent/codec"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/payload"
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+	grpcprotocol "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// DefaultHeartbeatInterval is how often subscribers receive a heartbeat when not configured
+const DefaultHeartbeatInterval = time.Second
+
+const (
+	resourceBundlesPath = "/api/maestro/v1/resource-bundles"
+	consumersPath       = "/api/maestro/v1/consumers"
+)
+
+// Options configures a Server
+type Options struct {
+	// HTTPAddr is the listen address of the HTTP API (default "127.0.0.1:0")
+	HTTPAddr string
+	// GRPCAddr is the listen address of the gRPC service (default "127.0.0.1:0")
+	GRPCAddr string
+	// HeartbeatInterval is how often subscribers receive a heartbeat (default 1s)
+	HeartbeatInterval time.Duration
+}
+
+// Server is a fake Maestro server holding resource bundles in memory
+type Server struct {
+	pbv1.UnimplementedCloudEventServiceServer
+	codec        *agentcodec.ManifestBundleCodec
+	bundles      map[string]*bundle
+	consumers    map[string]time.Time
+	subscribers  map[string]*subscriber
+	publishErr   error
+	httpServer   *http.Server
+	grpcServer   *grpc.Server
+	httpListener net.Listener
+	grpcListener net.Listener
+
+	opts Options
+	mu   sync.Mutex
+}
+
+// bundle is a stored ManifestWork with the Maestro bookkeeping around it
+type bundle struct {
+	work      *workv1.ManifestWork
+	createdAt time.Time
+	updatedAt time.Time
+	source    string
+	version   int32
+}
+
+// subscriber is a source streaming status updates
+type subscriber struct {
+	events chan *pbv1.CloudEvent
+	source string
+}
+
+// New creates a Server. Call Start to listen.
+func New(opts Options) *Server {
+	if opts.HTTPAddr == "" {
+		opts.HTTPAddr = "127.0.0.1:0"
+	}
+	if opts.GRPCAddr == "" {
+		opts.GRPCAddr = "127.0.0.1:0"
+	}
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	return &Server{
+		opts:        opts,
+		codec:       agentcodec.NewManifestBundleCodec(),
+		bundles:     make(map[string]*bundle),
+		consumers:   make(map[string]time.Time),
+		subscribers: make(map[string]*subscriber),
+	}
+}
+
+// Start listens on the HTTP and gRPC addresses and serves in the background
+func (s *Server) Start() error {
+	httpListener, err := net.Listen("tcp", s.opts.HTTPAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.opts.HTTPAddr, err)
+	}
+	grpcListener, err := net.Listen("tcp", s.opts.GRPCAddr)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to listen on %s: %w", s.opts.GRPCAddr, err), httpListener.Close())
+	}
+	s.httpListener = httpListener
+	s.grpcListener = grpcListener
+
+	s.grpcServer = grpc.NewServer()
+	pbv1.RegisterCloudEventServiceServer(s.grpcServer, s)
+	s.httpServer = &http.Server{Handler: s.handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	go func() { _ = s.grpcServer.Serve(grpcListener) }() //nolint:errcheck // returns once Close stops the server
+	go func() { _ = s.httpServer.Serve(httpListener) }() //nolint:errcheck // returns once Close stops the server
+	return nil
+}
+
+// Close stops both servers and ends the subscriber streams
+func (s *Server) Close() {
+	if s.grpcServer != nil {
+		s.grpcServer.Stop()
+	}
+	if s.httpServer != nil {
+		_ = s.httpServer.Close() //nolint:errcheck // best-effort shutdown
+	}
+}
+
+// HTTPURL returns the base URL of the HTTP API, for the client's MaestroServerAddr
+func (s *Server) HTTPURL() string {
+	return "http://" + s.httpListener.Addr().String()
+}
+
+// GRPCAddr returns the address of the gRPC service, for the client's GRPCServerAddr
+func (s *Server) GRPCAddr() string {
+	return s.grpcListener.Addr().String()
+}
+
+// AddConsumer registers a consumer. Consumers are also registered by their first work.
+func (s *Server) AddConsumer(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.consumers[name]; !ok {
+		s.consumers[name] = time.Now()
+	}
+}
+
+// Works returns copies of the works of consumer, sorted by name
+func (s *Server) Works(consumer string) []*workv1.ManifestWork {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	works := make([]*workv1.ManifestWork, 0)
+	for _, b := range s.bundles {
+		if b.work.Namespace == consumer {
+			works = append(works, b.work.DeepCopy())
+		}
+	}
+	sort.Slice(works, func(i, j int) bool { return works[i].Name < works[j].Name })
+	return works
+}
+
+// Work returns a copy of the work name of consumer
+func (s *Server) Work(consumer, name string) (*workv1.ManifestWork, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.find(consumer, name)
+	if b == nil {
+		return nil, false
+	}
+	return b.work.DeepCopy(), true
+}
+
+// SetConditions replaces the conditions of a work, as an agent would report them, and
+// streams the new status to the subscribed sources
+func (s *Server) SetConditions(consumer, name string, conditions ...metav1.Condition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.find(consumer, name)
+	if b == nil {
+		return fmt.Errorf("work %s/%s not found", consumer, name)
+	}
+	b.work.Status.Conditions = nil
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&b.work.Status.Conditions, condition)
+	}
+	b.updatedAt = time.Now()
+	return s.sendStatus(b.work)
+}
+
+// SetPublishError makes every publish fail with err until it is reset with nil, to test
+// how the client handles an unavailable Maestro
+func (s *Server) SetPublishError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publishErr = err
+}
+
+// find returns the bundle of the work name of consumer. The caller holds s.mu.
+func (s *Server) find(consumer, name string) *bundle {
+	for _, b := range s.bundles {
+		if b.work.Namespace == consumer && b.work.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// Publish receives a ManifestWork spec request from a source
+func (s *Server) Publish(ctx context.Context, req *pbv1.PublishRequest) (*emptypb.Empty, error) {
+	evt, err := binding.ToEvent(ctx, grpcprotocol.NewMessage(req.Event))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cloudevent: %v", err)
+	}
+	eventType, err := types.ParseCloudEventsType(evt.Type())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cloudevent type %q: %v", evt.Type(), err)
+	}
+	if eventType.CloudEventsDataType != payload.ManifestBundleEventDataType ||
+		eventType.SubResource != types.SubResourceSpec {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported cloudevent type %q", evt.Type())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.publishErr != nil {
+		return nil, status.Error(codes.Unavailable, s.publishErr.Error())
+	}
+	work, err := s.codec.Decode(evt)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid manifest bundle: %v", err)
+	}
+	id := string(work.UID)
+	now := time.Now()
+
+	switch eventType.Action {
+	case types.CreateRequestAction, types.UpdateRequestAction:
+		b, ok := s.bundles[id]
+		if !ok {
+			b = &bundle{source: evt.Source(), createdAt: now}
+			s.bundles[id] = b
+		}
+		b.version++
+		b.updatedAt = now
+		work.Generation = int64(b.version)
+		applied(work)
+		b.work = work
+		if _, ok := s.consumers[work.Namespace]; !ok {
+			s.consumers[work.Namespace] = now
+		}
+		if err := s.sendStatus(work); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	case types.DeleteRequestAction:
+		b, ok := s.bundles[id]
+		if !ok {
+			return &emptypb.Empty{}, nil
+		}
+		delete(s.bundles, id)
+		deleted := b.work.DeepCopy()
+		meta.SetStatusCondition(&deleted.Status.Conditions, metav1.Condition{
+			Type: common.ResourceDeleted, Status: metav1.ConditionTrue, Reason: "ManifestsDeleted",
+		})
+		if err := s.sendStatus(deleted); err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported action %q", eventType.Action)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Subscribe streams status updates of the works of the requesting source and heartbeats
+func (s *Server) Subscribe(req *pbv1.SubscriptionRequest, stream pbv1.CloudEventService_SubscribeServer) error {
+	if req.Source == "" {
+		return status.Error(codes.InvalidArgument, "only source subscriptions are supported")
+	}
+	id := uuid.NewString()
+	sub := &subscriber{source: req.Source, events: make(chan *pbv1.CloudEvent, 100)}
+	s.mu.Lock()
+	s.subscribers[id] = sub
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, id)
+		s.mu.Unlock()
+	}()
+
+	heartbeat := time.NewTicker(s.opts.HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if err := stream.Send(&pbv1.CloudEvent{
+				SpecVersion: "1.0",
+				Id:          uuid.NewString(),
+				Type:        types.HeartbeatCloudEventsType,
+			}); err != nil {
+				return err
+			}
+		case evt := <-sub.events:
+			if err := stream.Send(evt); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sendStatus streams the status of work to the subscribers of its source. The caller
+// holds s.mu. Events are dropped for subscribers too slow to keep up.
+func (s *Server) sendStatus(work *workv1.ManifestWork) error {
+	eventType := types.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         types.SubResourceStatus,
+		Action:              types.UpdateRequestAction,
+	}
+	evt, err := s.codec.Encode(work.Namespace, eventType, work)
+	if err != nil {
+		return fmt.Errorf("failed to encode status of work %s/%s: %w", work.Namespace, work.Name, err)
+	}
+	pbEvt := &pbv1.CloudEvent{}
+	if err := grpcprotocol.WritePBMessage(context.Background(), binding.ToMessage(evt), pbEvt); err != nil {
+		return fmt.Errorf("failed to convert status of work %s/%s: %w", work.Namespace, work.Name, err)
+	}
+	source := work.Labels[common.CloudEventsOriginalSourceLabelKey]
+	for _, sub := range s.subscribers {
+		if sub.source != source {
+			continue
+		}
+		select {
+		case sub.events <- pbEvt:
+		default:
+		}
+	}
+	return nil
+}
+
+// applied sets the conditions an agent reports once it applied every manifest of work
+func applied(work *workv1.ManifestWork) {
+	conditions := []metav1.Condition{
+		{Type: workv1.WorkApplied, Status: metav1.ConditionTrue, Reason: "AppliedManifestWorkComplete",
+			ObservedGeneration: work.Generation},
+		{Type: workv1.WorkAvailable, Status: metav1.ConditionTrue, Reason: "ResourcesAvailable",
+			ObservedGeneration: work.Generation},
+	}
+	work.Status.Conditions = nil
+	for _, condition := range conditions {
+		meta.SetStatusCondition(&work.Status.Conditions, condition)
+	}
+	work.Status.ResourceStatus.Manifests = nil
+	for i, m := range work.Spec.Workload.Manifests {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(m.Raw); err != nil {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		work.Status.ResourceStatus.Manifests = append(work.Status.ResourceStatus.Manifests, workv1.ManifestCondition{
+			ResourceMeta: workv1.ManifestResourceMeta{
+				Ordinal:   int32(i), //nolint:gosec // a work has far fewer manifests than MaxInt32
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Kind:      gvk.Kind,
+				Name:      obj.GetName(),
+				Namespace: obj.GetNamespace(),
+			},
+			Conditions: conditions,
+		})
+	}
+}
+
+// handler serves the HTTP API
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+consumersPath, s.listConsumers)
+	mux.HandleFunc("GET "+resourceBundlesPath, s.listResourceBundles)
+	mux.HandleFunc("GET "+resourceBundlesPath+"/{id}", s.getResourceBundle)
+	return mux
+}
+
+func (s *Server) listConsumers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	names := make([]string, 0, len(s.consumers))
+	for name := range s.consumers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]openapi.Consumer, 0, len(names))
+	for _, name := range names {
+		createdAt := s.consumers[name]
+		items = append(items, openapi.Consumer{
+			Id:        openapi.PtrString(name),
+			Kind:      openapi.PtrString("Consumer"),
+			Href:      openapi.PtrString(consumersPath + "/" + name),
+			Name:      openapi.PtrString(name),
+			CreatedAt: &createdAt,
+			UpdatedAt: &createdAt,
+		})
+	}
+	s.mu.Unlock()
+
+	page, size, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	total := len(items)
+	items = paginate(items, page, size)
+	writeJSON(w, http.StatusOK, openapi.ConsumerList{
+		Kind: "ConsumerList", Items: items,
+		Page: int32(page), Size: int32(len(items)), Total: int32(total), //nolint:gosec // small test data
+	})
+}
+
+func (s *Server) listResourceBundles(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSearch(r.URL.Query().Get("search"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	page, size, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	ids := make([]string, 0, len(s.bundles))
+	for id, b := range s.bundles {
+		if filter.matches(b) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	items := make([]openapi.ResourceBundle, 0, len(ids))
+	for _, id := range ids {
+		rb, err := toResourceBundle(id, s.bundles[id])
+		if err != nil {
+			s.mu.Unlock()
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		items = append(items, *rb)
+	}
+	s.mu.Unlock()
+
+	total := len(items)
+	items = paginate(items, page, size)
+	writeJSON(w, http.StatusOK, openapi.ResourceBundleList{
+		Kind: "ResourceBundleList", Items: items,
+		Page: int32(page), Size: int32(len(items)), Total: int32(total), //nolint:gosec // small test data
+	})
+}
+
+func (s *Server) getResourceBundle(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	b, ok := s.bundles[id]
+	var rb *openapi.ResourceBundle
+	var err error
+	if ok {
+		rb, err = toResourceBundle(id, b)
+	}
+	s.mu.Unlock()
+	switch {
+	case !ok:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("resource bundle with id '%s' not found", id))
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+	default:
+		writeJSON(w, http.StatusOK, rb)
+	}
+}
+
+// toResourceBundle presents a stored work the way Maestro does
+func toResourceBundle(id string, b *bundle) (*openapi.ResourceBundle, error) {
+	metadata, err := toMap(b.work.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+	status, err := toMap(payload.ManifestBundleStatus{
+		Conditions:     b.work.Status.Conditions,
+		ResourceStatus: b.work.Status.ResourceStatus.Manifests,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rb := &openapi.ResourceBundle{
+		Id:           openapi.PtrString(id),
+		Kind:         openapi.PtrString("ResourceBundle"),
+		Href:         openapi.PtrString(resourceBundlesPath + "/" + id),
+		Name:         openapi.PtrString(b.work.Name),
+		ConsumerName: openapi.PtrString(b.work.Namespace),
+		Version:      openapi.PtrInt32(b.version),
+		CreatedAt:    openapi.PtrTime(b.createdAt),
+		UpdatedAt:    openapi.PtrTime(b.updatedAt),
+		Metadata:     metadata,
+		Status:       status,
+	}
+	for _, m := range b.work.Spec.Workload.Manifests {
+		manifest := map[string]interface{}{}
+		if err = json.Unmarshal(m.Raw, &manifest); err != nil {
+			return nil, fmt.Errorf("invalid manifest in work %s: %w", b.work.Name, err)
+		}
+		rb.Manifests = append(rb.Manifests, manifest)
+	}
+	if b.work.Spec.DeleteOption != nil {
+		if rb.DeleteOption, err = toMap(b.work.Spec.DeleteOption); err != nil {
+			return nil, err
+		}
+	}
+	for _, config := range b.work.Spec.ManifestConfigs {
+		configMap, err := toMap(config)
+		if err != nil {
+			return nil, err
+		}
+		rb.ManifestConfigs = append(rb.ManifestConfigs, configMap)
+	}
+	return rb, nil
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// pagination reads the 1-based page and the page size, defaulting to the first page of 100
+func pagination(r *http.Request) (page, size int, err error) {
+	page, size = 1, 100
+	if v := r.URL.Query().Get("page"); v != "" {
+		if _, err := fmt.Sscan(v, &page); err != nil || page < 1 {
+			return 0, 0, fmt.Errorf("invalid page %q", v)
+		}
+	}
+	if v := r.URL.Query().Get("size"); v != "" {
+		if _, err := fmt.Sscan(v, &size); err != nil || size < 0 {
+			return 0, 0, fmt.Errorf("invalid size %q", v)
+		}
+	}
+	return page, size, nil
+}
+
+func paginate[T any](items []T, page, size int) []T {
+	start := (page - 1) * size
+	if start >= len(items) {
+		return []T{}
+	}
+	return items[start:min(start+size, len(items))]
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v) //nolint:errcheck // best-effort response
+}
+
+func writeError(w http.ResponseWriter, code int, reason string) {
+	writeJSON(w, code, openapi.Error{
+		Kind:   openapi.PtrString("Error"),
+		Code:   openapi.PtrString(fmt.Sprintf("maestro-%d", code)),
+		Reason: openapi.PtrString(reason),
+	})
+}
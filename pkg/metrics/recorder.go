@@ -4,6 +4,7 @@ package metrics
 
 import (
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -15,6 +16,53 @@ const (
 	DeletionStatusError   = "error"
 )
 
+// Subscription restart result constants
+const (
+	RestartResultSuccess = "success"
+	RestartResultFailed  = "failed"
+)
+
+// Config reload result constants
+const (
+	ReloadResultSuccess = "success"
+	ReloadResultFailed  = "failed"
+)
+
+// ConfigTaskMain is the config_info task label of the main task config
+const ConfigTaskMain = "main"
+
+// Step type constants, one per kind of configured task step
+const (
+	StepTypePrecondition = "precondition"
+	StepTypeResource     = "resource"
+	StepTypePrune        = "prune"
+	StepTypeWait         = "wait"
+	StepTypePostAction   = "post_action"
+)
+
+// Step status constants. StepStatusNotMet is a precondition whose conditions did not match.
+const (
+	StepStatusSuccess = "success"
+	StepStatusFailed  = "failed"
+	StepStatusSkipped = "skipped"
+	StepStatusNotMet  = "not_met"
+)
+
+// Skip reason constants, the normalized reasons a step or event was skipped:
+//   - SkipReasonWhenFalse: a when condition (lifecycle.create.when, post-action when)
+//     evaluated to false
+//   - SkipReasonGenerationUnchanged: the resource already has the event's generation
+//   - SkipReasonStaleEvent: the event's generation was already completed
+//   - SkipReasonFiltered: the event was filtered out by a precondition, sharding, the
+//     event filter or a subscription filter
+const (
+	SkipReasonWhenFalse           = "when_false"
+	SkipReasonGenerationUnchanged = "generation_unchanged"
+	SkipReasonStaleEvent          = "stale_event"
+	SkipReasonFiltered            = "filtered"
+	SkipReasonOther               = "other"
+)
+
 // Resource type constants
 const (
 	ResourceTypeUnknown = "Unknown"
@@ -67,16 +115,59 @@ func normalizeDeletionStatus(status string) string {
 	}
 }
 
+// normalizeSkipReason validates skip reason labels. Values other than the SkipReason
+// constants are replaced with SkipReasonOther to prevent bad time series.
+func normalizeSkipReason(reason string) string {
+	switch reason {
+	case SkipReasonWhenFalse, SkipReasonGenerationUnchanged, SkipReasonStaleEvent, SkipReasonFiltered:
+		return reason
+	default:
+		return SkipReasonOther
+	}
+}
+
 // Recorder registers and records adapter-level Prometheus metrics.
 // All methods are nil-safe: calling methods on a nil *Recorder is a no-op,
 // which allows dry-run mode to skip metrics without nil checks at every call site.
 type Recorder struct {
-	eventsProcessed    *prometheus.CounterVec
-	processingDuration prometheus.Observer
-	errorsTotal        *prometheus.CounterVec
-	deletionTotal      *prometheus.CounterVec
-	deletionDuration   *prometheus.HistogramVec
-	deletionInProgress *prometheus.GaugeVec
+	eventsProcessed      *prometheus.CounterVec
+	processingDuration   prometheus.Observer
+	errorsTotal          *prometheus.CounterVec
+	deletionTotal        *prometheus.CounterVec
+	deletionDuration     *prometheus.HistogramVec
+	deletionInProgress   *prometheus.GaugeVec
+	subscriptionRestarts *prometheus.CounterVec
+	configReloads        *prometheus.CounterVec
+	warningsTotal        *prometheus.CounterVec
+	configInfo           *prometheus.GaugeVec
+	clientUp             *prometheus.GaugeVec
+	clientLastSuccess    *prometheus.GaugeVec
+	stepsTotal           *prometheus.CounterVec
+	stepDuration         *prometheus.HistogramVec
+	stepWarnings         *prometheus.CounterVec
+	skipsTotal           *prometheus.CounterVec
+	redeliveries         prometheus.Counter
+	ackMode              *prometheus.GaugeVec
+	runningSteps         *runningSteps
+}
+
+// runningSteps holds the start times of the steps in progress
+type runningSteps struct {
+	started map[*time.Time]struct{}
+	mu      sync.Mutex
+}
+
+// longest returns how long the oldest step in progress has been running, or 0
+func (s *runningSteps) longest() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var longest time.Duration
+	for start := range s.started {
+		if d := time.Since(*start); d > longest {
+			longest = d
+		}
+	}
+	return longest.Seconds()
 }
 
 // NewRecorder creates a new Recorder and registers metrics with the given registerer.
@@ -166,20 +257,217 @@ func NewRecorder(component, version, adapterName string, reg prometheus.Register
 		[]string{"resource_type"},
 	)
 
+	subscriptionRestarts := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hyperfleet_adapter_subscription_restarts_total",
+			Help: "Total number of broker subscription restart attempts after a fatal subscriber error",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"result"},
+	)
+
+	configReloads := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hyperfleet_adapter_config_reloads_total",
+			Help: "Total number of task config hot reloads after a change was detected",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"result"},
+	)
+
+	warningsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hyperfleet_adapter_execution_warnings_total",
+			Help: "Total number of execution warnings (capture misses, unresolved optional params, discovery anomalies)",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"phase"},
+	)
+
+	configInfo := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hyperfleet_adapter_config_info",
+			Help: "Config used for new executions of each task config, identified by its task config hash " +
+				"and whole config fingerprint (always 1)",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"task", "config_hash", "config_fingerprint"},
+	)
+
+	clientUp := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hyperfleet_adapter_client_up",
+			Help: "Whether the last background health check of a client dependency succeeded (1) or failed (0)",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"client"},
+	)
+
+	clientLastSuccess := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hyperfleet_adapter_client_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful background health check of a client dependency",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"client"},
+	)
+
+	stepsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hyperfleet_adapter_steps_total",
+			Help: "Total number of task steps executed, by step type, step name and status",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"step_type", "step", "status"},
+	)
+
+	stepDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "hyperfleet_adapter_step_duration_seconds",
+			Help:    "Duration of task steps in seconds, including retries",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2, 5, 10, 30, 60, 120},
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"step_type", "step"},
+	)
+
+	stepWarnings := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hyperfleet_adapter_step_warnings_total",
+			Help: "Total number of soft errors that did not fail the execution, by phase and step name",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"phase", "step"},
+	)
+
+	skipsTotal := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "hyperfleet_adapter_skips_total",
+			Help: "Total number of steps and events skipped with nothing to do, by step name and skip reason",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"step", "reason"},
+	)
+
+	redeliveries := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "hyperfleet_adapter_event_redeliveries_total",
+			Help: "Total number of broker deliveries of an event ID already delivered within the last hour",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+	)
+
+	ackMode := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "hyperfleet_adapter_ack_mode",
+			Help: "When broker messages are acknowledged: after_execute or before_execute (always 1)",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		[]string{"ack_mode"},
+	)
+
+	running := &runningSteps{started: make(map[*time.Time]struct{})}
+	longestRunningStep := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "hyperfleet_adapter_longest_running_step_seconds",
+			Help: "How long the longest-running task step in progress has been running, 0 when none is",
+			ConstLabels: prometheus.Labels{
+				"component":    component,
+				"version":      version,
+				"adapter_name": adapterName,
+			},
+		},
+		running.longest,
+	)
+
 	reg.MustRegister(eventsProcessed)
 	reg.MustRegister(processingDuration)
 	reg.MustRegister(errorsTotal)
 	reg.MustRegister(deletionTotal)
 	reg.MustRegister(deletionDuration)
 	reg.MustRegister(deletionInProgress)
+	reg.MustRegister(subscriptionRestarts)
+	reg.MustRegister(configReloads)
+	reg.MustRegister(warningsTotal)
+	reg.MustRegister(configInfo)
+	reg.MustRegister(clientUp)
+	reg.MustRegister(clientLastSuccess)
+	reg.MustRegister(stepsTotal)
+	reg.MustRegister(stepDuration)
+	reg.MustRegister(stepWarnings)
+	reg.MustRegister(skipsTotal)
+	reg.MustRegister(redeliveries)
+	reg.MustRegister(ackMode)
+	reg.MustRegister(longestRunningStep)
 
 	return &Recorder{
-		eventsProcessed:    eventsProcessed,
-		processingDuration: processingDuration,
-		errorsTotal:        errorsTotal,
-		deletionTotal:      deletionTotal,
-		deletionDuration:   deletionDuration,
-		deletionInProgress: deletionInProgress,
+		eventsProcessed:      eventsProcessed,
+		processingDuration:   processingDuration,
+		errorsTotal:          errorsTotal,
+		deletionTotal:        deletionTotal,
+		deletionDuration:     deletionDuration,
+		deletionInProgress:   deletionInProgress,
+		subscriptionRestarts: subscriptionRestarts,
+		configReloads:        configReloads,
+		warningsTotal:        warningsTotal,
+		configInfo:           configInfo,
+		clientUp:             clientUp,
+		clientLastSuccess:    clientLastSuccess,
+		stepsTotal:           stepsTotal,
+		stepDuration:         stepDuration,
+		stepWarnings:         stepWarnings,
+		skipsTotal:           skipsTotal,
+		redeliveries:         redeliveries,
+		ackMode:              ackMode,
+		runningSteps:         running,
 	}
 }
 
@@ -255,3 +543,129 @@ func (r *Recorder) DecDeletionInProgress(resourceType string) {
 	resourceType = normalizeResourceType(resourceType)
 	r.deletionInProgress.WithLabelValues(resourceType).Dec()
 }
+
+// RecordSubscriptionRestart increments the subscription_restarts_total counter.
+// Valid result values: RestartResultSuccess ("success"), RestartResultFailed ("failed").
+func (r *Recorder) RecordSubscriptionRestart(result string) {
+	if r == nil {
+		return
+	}
+	if result != RestartResultSuccess {
+		result = RestartResultFailed
+	}
+	r.subscriptionRestarts.WithLabelValues(result).Inc()
+}
+
+// RecordConfigReload increments the config_reloads_total counter.
+// Valid result values: ReloadResultSuccess ("success"), ReloadResultFailed ("failed").
+func (r *Recorder) RecordConfigReload(result string) {
+	if r == nil {
+		return
+	}
+	if result != ReloadResultSuccess {
+		result = ReloadResultFailed
+	}
+	r.configReloads.WithLabelValues(result).Inc()
+}
+
+// RecordWarning increments the execution_warnings_total counter for the given phase.
+// Phases correspond to execution phases: "param_extraction", "preconditions",
+// "resources", "post_actions".
+func (r *Recorder) RecordWarning(phase string) {
+	if r == nil {
+		return
+	}
+	r.warningsTotal.WithLabelValues(phase).Inc()
+}
+
+// SetActiveConfig sets config_info of task to the given task config hash and config
+// fingerprint, replacing the previously active ones of task. task is ConfigTaskMain for
+// the main task config and the path of a subscription's own task config otherwise, so
+// the executors sharing the recorder do not replace each other's config.
+func (r *Recorder) SetActiveConfig(task, hash, fingerprint string) {
+	if r == nil {
+		return
+	}
+	r.configInfo.DeletePartialMatch(prometheus.Labels{"task": task})
+	r.configInfo.WithLabelValues(task, hash, fingerprint).Set(1)
+}
+
+// RecordClientCheck records the result of a background client health check. client
+// names the dependency ("hyperfleet_api", "kubernetes", "maestro", "broker"). The
+// last-success timestamp is only updated when the check succeeded.
+func (r *Recorder) RecordClientCheck(client string, healthy bool, at time.Time) {
+	if r == nil {
+		return
+	}
+	if !healthy {
+		r.clientUp.WithLabelValues(client).Set(0)
+		return
+	}
+	r.clientUp.WithLabelValues(client).Set(1)
+	r.clientLastSuccess.WithLabelValues(client).Set(float64(at.Unix()))
+}
+
+// RecordStep increments steps_total for a task step and, unless the step was skipped,
+// observes its duration. stepType is one of the StepType constants and status one of the
+// StepStatus constants.
+func (r *Recorder) RecordStep(stepType, step, status string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.stepsTotal.WithLabelValues(stepType, step, status).Inc()
+	if status != StepStatusSkipped {
+		r.stepDuration.WithLabelValues(stepType, step).Observe(d.Seconds())
+	}
+}
+
+// RecordStepWarning increments step_warnings_total for a soft error recorded by a step.
+// step is empty for warnings not tied to a step.
+func (r *Recorder) RecordStepWarning(phase, step string) {
+	if r == nil {
+		return
+	}
+	r.stepWarnings.WithLabelValues(phase, step).Inc()
+}
+
+// RecordSkip increments skips_total for a skipped step, or an event skipped before any
+// step ran when step is empty. reason is one of the SkipReason constants.
+func (r *Recorder) RecordSkip(step, reason string) {
+	if r == nil {
+		return
+	}
+	r.skipsTotal.WithLabelValues(step, normalizeSkipReason(reason)).Inc()
+}
+
+// RecordRedelivery increments event_redeliveries_total for an event delivered again
+func (r *Recorder) RecordRedelivery() {
+	if r == nil {
+		return
+	}
+	r.redeliveries.Inc()
+}
+
+// SetAckMode sets ack_mode to the ack mode in use
+func (r *Recorder) SetAckMode(mode string) {
+	if r == nil {
+		return
+	}
+	r.ackMode.Reset()
+	r.ackMode.WithLabelValues(mode).Set(1)
+}
+
+// StepStarted counts a step as in progress for longest_running_step_seconds until the
+// returned func is called. The func is safe to call more than once.
+func (r *Recorder) StepStarted() (done func()) {
+	if r == nil {
+		return func() {}
+	}
+	start := time.Now()
+	r.runningSteps.mu.Lock()
+	r.runningSteps.started[&start] = struct{}{}
+	r.runningSteps.mu.Unlock()
+	return func() {
+		r.runningSteps.mu.Lock()
+		delete(r.runningSteps.started, &start)
+		r.runningSteps.mu.Unlock()
+	}
+}
@@ -335,6 +335,35 @@ func TestNilRecorderNoPanic(t *testing.T) {
 	assert.NotPanics(t, func() {
 		recorder.DecDeletionInProgress("Namespace")
 	}, "DecDeletionInProgress on nil recorder")
+
+	assert.NotPanics(t, func() {
+		recorder.RecordWarning("preconditions")
+	}, "RecordWarning on nil recorder")
+
+	assert.NotPanics(t, func() {
+		recorder.RecordStep(StepTypeResource, "namespace", StepStatusSuccess, time.Second)
+	}, "RecordStep on nil recorder")
+
+	assert.NotPanics(t, func() {
+		recorder.RecordStepWarning("preconditions", "clusterStatus")
+	}, "RecordStepWarning on nil recorder")
+
+	assert.NotPanics(t, func() {
+		recorder.RecordSkip("namespace", SkipReasonWhenFalse)
+	}, "RecordSkip on nil recorder")
+
+	assert.NotPanics(t, func() {
+		recorder.SetActiveConfig(ConfigTaskMain, "abc123", "def456")
+	}, "SetActiveConfig on nil recorder")
+
+	assert.NotPanics(t, func() {
+		recorder.RecordRedelivery()
+		recorder.SetAckMode("after_execute")
+	}, "RecordRedelivery and SetAckMode on nil recorder")
+
+	assert.NotPanics(t, func() {
+		recorder.StepStarted()()
+	}, "StepStarted on nil recorder")
 }
 
 func TestExtractAdapterName(t *testing.T) {
@@ -444,3 +473,322 @@ func TestRecordDeletion_Normalization(t *testing.T) {
 	// valid values unchanged
 	assert.Equal(t, float64(1), counts["ServiceAccount/success"], "Valid values should be preserved")
 }
+
+func TestRecordSubscriptionRestart(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.RecordSubscriptionRestart(RestartResultSuccess)
+	recorder.RecordSubscriptionRestart(RestartResultFailed)
+	recorder.RecordSubscriptionRestart("bogus")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	counts := make(map[string]float64)
+	for _, f := range families {
+		if f.GetName() != "hyperfleet_adapter_subscription_restarts_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "result" {
+					counts[l.GetValue()] = m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, float64(1), counts[RestartResultSuccess], "success restart count")
+	assert.Equal(t, float64(2), counts[RestartResultFailed], "invalid results count as failed")
+}
+
+func TestRecordConfigReload(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.RecordConfigReload(ReloadResultSuccess)
+	recorder.RecordConfigReload(ReloadResultFailed)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	counts := make(map[string]float64)
+	for _, f := range families {
+		if f.GetName() != "hyperfleet_adapter_config_reloads_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "result" {
+					counts[l.GetValue()] = m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	assert.Equal(t, float64(1), counts[ReloadResultSuccess])
+	assert.Equal(t, float64(1), counts[ReloadResultFailed])
+}
+
+func TestRecordWarning(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.RecordWarning("preconditions")
+	recorder.RecordWarning("preconditions")
+	recorder.RecordWarning("resources")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var warningsFamily *dto.MetricFamily
+	for _, f := range families {
+		if f.GetName() == "hyperfleet_adapter_execution_warnings_total" {
+			warningsFamily = f
+			break
+		}
+	}
+	require.NotNil(t, warningsFamily, "execution_warnings_total metric family should exist")
+
+	counts := make(map[string]float64)
+	for _, m := range warningsFamily.GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == "phase" {
+				counts[l.GetValue()] = m.GetCounter().GetValue()
+			}
+		}
+	}
+
+	assert.Equal(t, float64(2), counts["preconditions"], "preconditions warning count")
+	assert.Equal(t, float64(1), counts["resources"], "resources warning count")
+}
+
+func TestRecordStep(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.RecordStep(StepTypePrecondition, "clusterStatus", StepStatusSuccess, 200*time.Millisecond)
+	recorder.RecordStep(StepTypePrecondition, "clusterStatus", StepStatusNotMet, 100*time.Millisecond)
+	recorder.RecordStep(StepTypeResource, "namespace", StepStatusSkipped, 0)
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var stepsFamily, durationFamily *dto.MetricFamily
+	for _, f := range families {
+		switch f.GetName() {
+		case "hyperfleet_adapter_steps_total":
+			stepsFamily = f
+		case "hyperfleet_adapter_step_duration_seconds":
+			durationFamily = f
+		}
+	}
+	require.NotNil(t, stepsFamily, "steps_total metric family should exist")
+	require.NotNil(t, durationFamily, "step_duration_seconds metric family should exist")
+
+	counts := make(map[string]float64)
+	for _, m := range stepsFamily.GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		counts[labels["step_type"]+"/"+labels["step"]+"/"+labels["status"]] = m.GetCounter().GetValue()
+	}
+	assert.Equal(t, float64(1), counts["precondition/clusterStatus/success"])
+	assert.Equal(t, float64(1), counts["precondition/clusterStatus/not_met"])
+	assert.Equal(t, float64(1), counts["resource/namespace/skipped"])
+
+	require.Len(t, durationFamily.GetMetric(), 1, "skipped steps should not observe a duration")
+	histogram := durationFamily.GetMetric()[0].GetHistogram()
+	assert.Equal(t, uint64(2), histogram.GetSampleCount())
+	assert.InDelta(t, 0.3, histogram.GetSampleSum(), 0.001)
+}
+
+func TestRecordStepWarning(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.RecordStepWarning("preconditions", "clusterStatus")
+	recorder.RecordStepWarning("preconditions", "clusterStatus")
+	recorder.RecordStepWarning("resources", "")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var warningsFamily *dto.MetricFamily
+	for _, f := range families {
+		if f.GetName() == "hyperfleet_adapter_step_warnings_total" {
+			warningsFamily = f
+			break
+		}
+	}
+	require.NotNil(t, warningsFamily, "step_warnings_total metric family should exist")
+
+	counts := make(map[string]float64)
+	for _, m := range warningsFamily.GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		counts[labels["phase"]+"/"+labels["step"]] = m.GetCounter().GetValue()
+	}
+	assert.Equal(t, float64(2), counts["preconditions/clusterStatus"])
+	assert.Equal(t, float64(1), counts["resources/"])
+}
+
+func TestRecordSkip(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.RecordSkip("namespace", SkipReasonGenerationUnchanged)
+	recorder.RecordSkip("namespace", SkipReasonGenerationUnchanged)
+	recorder.RecordSkip("reportStatus", SkipReasonWhenFalse)
+	recorder.RecordSkip("", SkipReasonStaleEvent)
+	recorder.RecordSkip("clusterStatus", "conditions not met")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var skipsFamily *dto.MetricFamily
+	for _, f := range families {
+		if f.GetName() == "hyperfleet_adapter_skips_total" {
+			skipsFamily = f
+			break
+		}
+	}
+	require.NotNil(t, skipsFamily, "skips_total metric family should exist")
+
+	counts := make(map[string]float64)
+	for _, m := range skipsFamily.GetMetric() {
+		labels := make(map[string]string)
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		counts[labels["step"]+"/"+labels["reason"]] = m.GetCounter().GetValue()
+	}
+	assert.Equal(t, map[string]float64{
+		"namespace/generation_unchanged": 2,
+		"reportStatus/when_false":        1,
+		"/stale_event":                   1,
+		"clusterStatus/other":            1,
+	}, counts, "unknown reasons should be normalized to other")
+}
+
+func TestSetActiveConfig(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.SetActiveConfig(ConfigTaskMain, "aaaaaaaaaaaa", "111111111111")
+	recorder.SetActiveConfig(ConfigTaskMain, "bbbbbbbbbbbb", "222222222222")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var infoFamily *dto.MetricFamily
+	for _, f := range families {
+		if f.GetName() == "hyperfleet_adapter_config_info" {
+			infoFamily = f
+			break
+		}
+	}
+	require.NotNil(t, infoFamily, "config_info metric family should exist")
+	require.Len(t, infoFamily.GetMetric(), 1, "only the active config is reported")
+
+	metric := infoFamily.GetMetric()[0]
+	assert.Equal(t, float64(1), metric.GetGauge().GetValue())
+	labels := make(map[string]string)
+	for _, l := range metric.GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	assert.Equal(t, ConfigTaskMain, labels["task"])
+	assert.Equal(t, "bbbbbbbbbbbb", labels["config_hash"])
+	assert.Equal(t, "222222222222", labels["config_fingerprint"])
+}
+
+func TestRecordClientCheck(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	success := time.Unix(1700000000, 0)
+	recorder.RecordClientCheck("kubernetes", true, success)
+	recorder.RecordClientCheck("kubernetes", false, success.Add(time.Minute))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	gauges := make(map[string]float64)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "client" && l.GetValue() == "kubernetes" {
+					gauges[f.GetName()] = m.GetGauge().GetValue()
+				}
+			}
+		}
+	}
+	assert.Equal(t, float64(0), gauges["hyperfleet_adapter_client_up"], "last check failed")
+	assert.Equal(t, float64(success.Unix()), gauges["hyperfleet_adapter_client_last_success_timestamp_seconds"],
+		"a failed check keeps the last success time")
+}
+
+func TestRecordRedeliveryAndAckMode(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	recorder.RecordRedelivery()
+	recorder.RecordRedelivery()
+	recorder.SetAckMode("after_execute")
+	recorder.SetAckMode("before_execute")
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	var redeliveries float64
+	var modes []string
+	for _, f := range families {
+		switch f.GetName() {
+		case "hyperfleet_adapter_event_redeliveries_total":
+			redeliveries = f.GetMetric()[0].GetCounter().GetValue()
+		case "hyperfleet_adapter_ack_mode":
+			for _, m := range f.GetMetric() {
+				for _, l := range m.GetLabel() {
+					if l.GetName() == "ack_mode" {
+						modes = append(modes, l.GetValue())
+					}
+				}
+			}
+		}
+	}
+	assert.Equal(t, float64(2), redeliveries)
+	assert.Equal(t, []string{"before_execute"}, modes, "only the mode in use is reported")
+}
+
+func TestStepStarted(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	recorder := NewRecorder("test-adapter", "v0.1.0", "test", registry)
+
+	longest := func() float64 {
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		for _, f := range families {
+			if f.GetName() == "hyperfleet_adapter_longest_running_step_seconds" {
+				return f.GetMetric()[0].GetGauge().GetValue()
+			}
+		}
+		t.Fatal("longest_running_step_seconds is not registered")
+		return 0
+	}
+
+	assert.Zero(t, longest(), "no step is running")
+	first := recorder.StepStarted()
+	time.Sleep(20 * time.Millisecond)
+	second := recorder.StepStarted()
+	assert.GreaterOrEqual(t, longest(), 0.02, "the oldest running step is reported")
+
+	first()
+	first()
+	assert.Less(t, longest(), 0.02, "a finished step is no longer reported")
+	second()
+	assert.Zero(t, longest())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 )
@@ -55,3 +56,27 @@ func ExtractTraceContextFromCloudEvent(ctx context.Context, evt *event.Event) co
 	// Use the global propagator to extract trace context into the context
 	return otel.GetTextMapPropagator().Extract(ctx, carrier)
 }
+
+// InjectTraceContextIntoAnnotations stores the W3C trace context of ctx in the
+// hyperfleet.io/traceparent and hyperfleet.io/tracestate annotations, so systems that
+// act on the annotated object can continue the trace. Returns annotations, allocated if
+// nil and a trace context is present; it is unchanged when ctx carries no trace context.
+func InjectTraceContextIntoAnnotations(ctx context.Context, annotations map[string]string) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	traceparent := carrier.Get("traceparent")
+	if traceparent == "" {
+		return annotations
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[constants.AnnotationTraceParent] = traceparent
+	if tracestate := carrier.Get("tracestate"); tracestate != "" {
+		annotations[constants.AnnotationTraceState] = tracestate
+	} else {
+		delete(annotations, constants.AnnotationTraceState)
+	}
+	return annotations
+}
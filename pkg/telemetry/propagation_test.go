@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/constants"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
@@ -239,3 +240,34 @@ func TestExtractTraceContextFromCloudEvent(t *testing.T) {
 		}
 	})
 }
+
+func TestInjectTraceContextIntoAnnotations(t *testing.T) {
+	const traceparent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	t.Run("no_trace_context_leaves_annotations_unchanged", func(t *testing.T) {
+		result := InjectTraceContextIntoAnnotations(context.Background(), nil)
+		if result != nil {
+			t.Errorf("Expected nil annotations, got %v", result)
+		}
+	})
+
+	t.Run("event_trace_context_is_injected", func(t *testing.T) {
+		evt := event.New()
+		evt.SetID("test-id")
+		evt.SetExtension("traceparent", traceparent)
+		evt.SetExtension("tracestate", "vendor=value")
+		ctx := ExtractTraceContextFromCloudEvent(context.Background(), &evt)
+
+		result := InjectTraceContextIntoAnnotations(ctx, map[string]string{"keep": "me"})
+
+		if result[constants.AnnotationTraceParent] != traceparent {
+			t.Errorf("Expected traceparent %s, got %q", traceparent, result[constants.AnnotationTraceParent])
+		}
+		if result[constants.AnnotationTraceState] != "vendor=value" {
+			t.Errorf("Expected tracestate vendor=value, got %q", result[constants.AnnotationTraceState])
+		}
+		if result["keep"] != "me" {
+			t.Error("Expected existing annotations to be kept")
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"google.golang.org/grpc"
+)
+
+// WrapTransport returns a RoundTripper that creates a client span for every request sent
+// through rt and injects the W3C trace context into its headers, so the call is a child
+// of the span in the request context. A nil rt wraps http.DefaultTransport. Its
+// signature matches rest.Config.WrapTransport.
+//
+// Span names use the method only ("HTTP GET") to keep their cardinality low; the URL
+// is in the span attributes.
+func WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(rt,
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return "HTTP " + r.Method
+		}))
+}
+
+// GRPCDialOption returns the dial option that creates a client span for every gRPC call
+// and propagates the trace context in the call metadata.
+func GRPCDialOption() grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler())
+}
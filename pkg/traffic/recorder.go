@@ -0,0 +1,371 @@
+// Package traffic records the metadata of outgoing HTTP and gRPC calls into a bounded
+// in-memory ring, for debugging a live adapter. Recording can be switched on and off at
+// runtime; while it is off the hooks only cost an atomic load. Bodies are never recorded,
+// and credentials in headers and query strings are redacted.
+package traffic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultCapacity is the number of exchanges kept when no capacity is configured
+const DefaultCapacity = 500
+
+// Protocols of recorded exchanges
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+const redactedValue = "**REDACTED**"
+
+// sensitiveHeaderParts marks headers whose values are redacted when their lowercase
+// name contains one of these parts
+var sensitiveHeaderParts = []string{"authorization", "cookie", "token", "secret", "password", "api-key", "apikey"}
+
+// Exchange is the metadata of one outgoing call
+type Exchange struct {
+	Time time.Time `json:"time"`
+	// Headers are the request headers (HTTP) or outgoing metadata (gRPC), redacted
+	Headers map[string]string `json:"headers,omitempty"`
+	// Protocol is ProtocolHTTP or ProtocolGRPC
+	Protocol string `json:"protocol"`
+	// Method is the HTTP method or the full gRPC method name
+	Method string `json:"method"`
+	// Target is the request URL with query values redacted, or the gRPC target
+	Target string `json:"target"`
+	// Status is the HTTP status code or the gRPC status code
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// DurationMs is the time until the response headers (HTTP) or the call result (gRPC)
+	DurationMs    int64 `json:"duration_ms"`
+	RequestBytes  int64 `json:"request_bytes,omitempty"`
+	ResponseBytes int64 `json:"response_bytes,omitempty"`
+}
+
+// Recorder keeps the last exchanges in a ring buffer. It is safe for concurrent use, and
+// all methods are no-ops on a nil Recorder.
+type Recorder struct {
+	ring       []Exchange
+	next       int
+	mu         sync.Mutex
+	enabled    atomic.Bool
+	full       bool
+	spanEvents bool
+}
+
+// NewRecorder creates a disabled recorder keeping the last capacity exchanges. A zero
+// capacity uses DefaultCapacity. When spanEvents is true, each exchange is also added as
+// an event to the active OTel span of the call.
+func NewRecorder(capacity int, spanEvents bool) *Recorder {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Recorder{ring: make([]Exchange, capacity), spanEvents: spanEvents}
+}
+
+// SetEnabled turns recording on or off. Recorded exchanges are kept either way.
+func (r *Recorder) SetEnabled(enabled bool) {
+	if r == nil {
+		return
+	}
+	r.enabled.Store(enabled)
+}
+
+// Toggle flips recording and returns the new state
+func (r *Recorder) Toggle() bool {
+	if r == nil {
+		return false
+	}
+	for {
+		current := r.enabled.Load()
+		if r.enabled.CompareAndSwap(current, !current) {
+			return !current
+		}
+	}
+}
+
+// Enabled reports whether exchanges are being recorded
+func (r *Recorder) Enabled() bool {
+	return r != nil && r.enabled.Load()
+}
+
+// Record adds an exchange to the ring when recording is enabled
+func (r *Recorder) Record(ctx context.Context, e Exchange) {
+	if !r.Enabled() {
+		return
+	}
+	r.mu.Lock()
+	r.ring[r.next] = e
+	r.next = (r.next + 1) % len(r.ring)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.mu.Unlock()
+
+	if r.spanEvents {
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.AddEvent("traffic.exchange", trace.WithAttributes(
+				attribute.String("traffic.protocol", e.Protocol),
+				attribute.String("traffic.method", e.Method),
+				attribute.String("traffic.target", e.Target),
+				attribute.String("traffic.status", e.Status),
+				attribute.String("traffic.error", e.Error),
+				attribute.Int64("traffic.duration_ms", e.DurationMs),
+			))
+		}
+	}
+}
+
+// Exchanges returns the recorded exchanges, oldest first
+func (r *Recorder) Exchanges() []Exchange {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]Exchange(nil), r.ring[:r.next]...)
+	}
+	return append(append([]Exchange(nil), r.ring[r.next:]...), r.ring[:r.next]...)
+}
+
+// Reset drops the recorded exchanges
+func (r *Recorder) Reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.ring)
+	r.next = 0
+	r.full = false
+}
+
+// -----------------------------------------------------------------------------
+// HTTP
+// -----------------------------------------------------------------------------
+
+// WrapTransport returns a RoundTripper recording the requests sent through rt. A nil rt
+// wraps http.DefaultTransport. Its signature matches rest.Config.WrapTransport.
+func (r *Recorder) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if r == nil {
+		return rt
+	}
+	return &roundTripper{next: rt, recorder: r}
+}
+
+type roundTripper struct {
+	next     http.RoundTripper
+	recorder *Recorder
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.recorder.Enabled() {
+		return t.next.RoundTrip(req)
+	}
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	e := Exchange{
+		Time:         start,
+		Protocol:     ProtocolHTTP,
+		Method:       req.Method,
+		Target:       RedactURL(req.URL),
+		Headers:      redactHeaders(req.Header),
+		DurationMs:   time.Since(start).Milliseconds(),
+		RequestBytes: max(req.ContentLength, 0),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	} else {
+		e.Status = strconv.Itoa(resp.StatusCode)
+		e.ResponseBytes = max(resp.ContentLength, 0)
+	}
+	t.recorder.Record(req.Context(), e)
+	return resp, err
+}
+
+// RedactURL returns u without user info and with query values replaced by a placeholder
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+	redacted.User = nil
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+		keys := make([]string, 0, len(query))
+		for key := range query {
+			keys = append(keys, url.QueryEscape(key)+"="+redactedValue)
+		}
+		sort.Strings(keys)
+		redacted.RawQuery = strings.Join(keys, "&")
+	}
+	return redacted.String()
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string, len(header))
+	for name, values := range header {
+		redacted[name] = redactHeader(name, strings.Join(values, ", "))
+	}
+	return redacted
+}
+
+func redactHeader(name, value string) string {
+	lower := strings.ToLower(name)
+	for _, part := range sensitiveHeaderParts {
+		if strings.Contains(lower, part) {
+			return redactedValue
+		}
+	}
+	return value
+}
+
+// -----------------------------------------------------------------------------
+// gRPC
+// -----------------------------------------------------------------------------
+
+// UnaryClientInterceptor records unary gRPC calls
+func (r *Recorder) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if !r.Enabled() {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.Record(ctx, grpcExchange(ctx, method, cc, start, err))
+		return err
+	}
+}
+
+// StreamClientInterceptor records the opening of gRPC streams. Messages sent on a stream
+// are not recorded individually.
+func (r *Recorder) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		if !r.Enabled() {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		r.Record(ctx, grpcExchange(ctx, method, cc, start, err))
+		return stream, err
+	}
+}
+
+func grpcExchange(ctx context.Context, method string, cc *grpc.ClientConn, start time.Time, err error) Exchange {
+	e := Exchange{
+		Time:       start,
+		Protocol:   ProtocolGRPC,
+		Method:     method,
+		DurationMs: time.Since(start).Milliseconds(),
+		Status:     status.Code(err).String(),
+	}
+	if cc != nil {
+		e.Target = cc.Target()
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md) > 0 {
+		e.Headers = make(map[string]string, len(md))
+		for name, values := range md {
+			e.Headers[name] = redactHeader(name, strings.Join(values, ", "))
+		}
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// -----------------------------------------------------------------------------
+// Admin endpoint
+// -----------------------------------------------------------------------------
+
+// Snapshot is the JSON document served by Handler
+type Snapshot struct {
+	Exchanges []Exchange `json:"exchanges"`
+	Capacity  int        `json:"capacity"`
+	Enabled   bool       `json:"enabled"`
+}
+
+// Handler serves the recorder state. GET returns a Snapshot; POST with ?enabled=true|false
+// turns recording on or off, and with ?reset=true drops the recorded exchanges.
+func (r *Recorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			query := req.URL.Query()
+			if raw := query.Get("enabled"); raw != "" {
+				enabled, err := strconv.ParseBool(raw)
+				if err != nil {
+					http.Error(w, "enabled must be true or false", http.StatusBadRequest)
+					return
+				}
+				r.SetEnabled(enabled)
+			}
+			if raw := query.Get("reset"); raw != "" {
+				reset, err := strconv.ParseBool(raw)
+				if err != nil {
+					http.Error(w, "reset must be true or false", http.StatusBadRequest)
+					return
+				}
+				if reset {
+					r.Reset()
+				}
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeSnapshot(w, r)
+	})
+}
+
+func writeSnapshot(w http.ResponseWriter, r *Recorder) {
+	w.Header().Set("Content-Type", "application/json")
+	snapshot := Snapshot{Enabled: r.Enabled(), Exchanges: r.Exchanges()}
+	if r != nil {
+		snapshot.Capacity = len(r.ring)
+	}
+	if snapshot.Exchanges == nil {
+		snapshot.Exchanges = []Exchange{}
+	}
+	_ = json.NewEncoder(w).Encode(snapshot) //nolint:errcheck // best-effort response
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// UnmarshalJSONObject decodes a JSON object into a map. With strictNumbers, numbers keep
+// their JSON form: integers become int64 and other numbers float64, as NormalizeJSONNumbers
+// does. Otherwise every number is a float64, as with json.Unmarshal.
+func UnmarshalJSONObject(data []byte, strictNumbers bool) (map[string]interface{}, error) {
+	var out map[string]interface{}
+	if !strictNumbers {
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the object")
+	}
+	NormalizeJSONNumbers(out)
+	return out, nil
+}
+
+// UnmarshalJSONValue decodes any JSON value, such as the argument of the CEL fromJson()
+// function. Numbers are decoded as by UnmarshalJSONObject.
+func UnmarshalJSONValue(data []byte, strictNumbers bool) (interface{}, error) {
+	var out interface{}
+	if !strictNumbers {
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid JSON: unexpected data after the value")
+	}
+	return NormalizeJSONNumbers(out), nil
+}
+
+// NormalizeJSONNumbers replaces the json.Number values of decoded JSON, in place for maps
+// and lists, and returns the result. An integer that fits int64 becomes an int64; any other
+// number becomes a float64, so numbers are typed as CEL int and double values.
+func NormalizeJSONNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case json.Number:
+		return jsonNumberValue(val)
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = NormalizeJSONNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = NormalizeJSONNumbers(item)
+		}
+	}
+	return v
+}
+
+// jsonNumberValue returns n as an int64 when it is an integer in range, else as a float64
+func jsonNumberValue(n json.Number) interface{} {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return n.String()
+	}
+	return f
+}
+
+// FormatNumber formats a number in plain decimal notation: 1000000 rather than the 1e+06
+// that templates print for a float64. Values other than numbers are formatted with %v.
+func FormatNumber(v interface{}) string {
+	switch val := v.(type) {
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		return FormatNumber(float64(val))
+	case json.Number:
+		return FormatNumber(jsonNumberValue(val))
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
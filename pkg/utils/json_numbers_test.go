@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalJSONObject(t *testing.T) {
+	doc := []byte(`{"nodeCount": 3, "ratio": 0.5, "big": 9007199254740993, "spec": {"sizes": [1, 2.5]}}`)
+
+	loose, err := UnmarshalJSONObject(doc, false)
+	require.NoError(t, err)
+	assert.Equal(t, float64(3), loose["nodeCount"])
+	assert.Equal(t, float64(9007199254740992), loose["big"], "a float64 loses the last digit")
+
+	strict, err := UnmarshalJSONObject(doc, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), strict["nodeCount"])
+	assert.Equal(t, 0.5, strict["ratio"])
+	assert.Equal(t, int64(9007199254740993), strict["big"])
+	assert.Equal(t, map[string]interface{}{"sizes": []interface{}{int64(1), 2.5}}, strict["spec"])
+
+	for _, invalid := range []string{`[1]`, `{"a": 1} {"b": 2}`, `{"a": }`} {
+		_, err := UnmarshalJSONObject([]byte(invalid), true)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestUnmarshalJSONValue(t *testing.T) {
+	loose, err := UnmarshalJSONValue([]byte(`[3, {"big": 9007199254740993}]`), false)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{float64(3), map[string]interface{}{"big": float64(9007199254740992)}}, loose)
+
+	strict, err := UnmarshalJSONValue([]byte(`[3, {"big": 9007199254740993}]`), true)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(3), map[string]interface{}{"big": int64(9007199254740993)}}, strict)
+
+	scalar, err := UnmarshalJSONValue([]byte(`42`), true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), scalar)
+
+	_, err = UnmarshalJSONValue([]byte(`1 2`), true)
+	assert.Error(t, err)
+}
+
+func TestProjectJSON_StrictNumbers(t *testing.T) {
+	got, err := ProjectJSON(strings.NewReader(`{"total": 2, "items": [{"size": 1.5}], "skip": 7}`),
+		[]string{"total", "items.size"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"total": int64(2),
+		"items": []interface{}{map[string]interface{}{"size": 1.5}},
+	}, got)
+}
+
+func TestFormatNumber(t *testing.T) {
+	assert.Equal(t, "1000000", FormatNumber(float64(1000000)))
+	assert.Equal(t, "0.000001", FormatNumber(1e-6))
+	assert.Equal(t, "42", FormatNumber(int64(42)))
+	assert.Equal(t, "3", FormatNumber(json.Number("3")))
+	assert.Equal(t, "abc", FormatNumber("abc"))
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// projectionNode is one segment of the dot-separated paths kept by ProjectJSON
+type projectionNode struct {
+	children map[string]*projectionNode
+	leaf     bool
+}
+
+// ProjectJSON decodes the JSON object read from r, keeping only the values at the given
+// dot-separated paths. Values outside the paths are skipped token by token, so memory use
+// is bounded by the size of the kept values rather than the whole document.
+//
+// A path descending into an array is applied to each element, and elements that have none
+// of the paths are dropped. Numbers are decoded as float64, as with json.Unmarshal, or as
+// with UnmarshalJSONObject when strictNumbers is set.
+//
+// Example:
+//
+//	// {"kind":"List","items":[{"id":"a","spec":{...}},{"id":"b","spec":{...}}]}
+//	data, err := ProjectJSON(body, []string{"kind", "items.id"}, false)
+//	// data = {"kind":"List","items":[{"id":"a"},{"id":"b"}]}
+func ProjectJSON(r io.Reader, paths []string, strictNumbers bool) (map[string]interface{}, error) {
+	root, err := buildProjection(paths)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(r)
+	if strictNumbers {
+		dec.UseNumber()
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", tok)
+	}
+	out, err := projectObject(dec, root)
+	if err != nil {
+		return nil, err
+	}
+	if strictNumbers {
+		NormalizeJSONNumbers(out)
+	}
+	return out, nil
+}
+
+func buildProjection(paths []string) (*projectionNode, error) {
+	root := &projectionNode{children: map[string]*projectionNode{}}
+	for _, path := range paths {
+		if path == "" {
+			return nil, fmt.Errorf("empty projection path")
+		}
+		node := root
+		for _, segment := range strings.Split(path, ".") {
+			if segment == "" {
+				return nil, fmt.Errorf("invalid projection path %q", path)
+			}
+			if node.leaf {
+				// A shorter path already keeps the whole value
+				break
+			}
+			child, ok := node.children[segment]
+			if !ok {
+				child = &projectionNode{children: map[string]*projectionNode{}}
+				node.children[segment] = child
+			}
+			node = child
+		}
+		node.leaf = true
+		node.children = nil
+	}
+	return root, nil
+}
+
+// projectObject reads the members of an object whose opening brace was consumed
+func projectObject(dec *json.Decoder, node *projectionNode) (map[string]interface{}, error) {
+	out := map[string]interface{}{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid JSON: expected an object key, got %v", tok)
+		}
+
+		child := node.children[key]
+		switch {
+		case child == nil:
+			if err := skipJSONValue(dec); err != nil {
+				return nil, err
+			}
+		case child.leaf:
+			var value interface{}
+			if err := dec.Decode(&value); err != nil {
+				return nil, fmt.Errorf("invalid JSON at %q: %w", key, err)
+			}
+			out[key] = value
+		default:
+			value, found, err := projectValue(dec, child)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				out[key] = value
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return out, nil
+}
+
+// projectValue reads a value that paths continue into. found is false when the value
+// holds none of the paths.
+func projectValue(dec *json.Decoder, node *projectionNode) (interface{}, bool, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid JSON: %w", err)
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj, err := projectObject(dec, node)
+		if err != nil {
+			return nil, false, err
+		}
+		return obj, len(obj) > 0, nil
+	case json.Delim('['):
+		items := []interface{}{}
+		for dec.More() {
+			item, found, err := projectValue(dec, node)
+			if err != nil {
+				return nil, false, err
+			}
+			if found {
+				items = append(items, item)
+			}
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, false, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return items, true, nil
+	default:
+		// A scalar has no fields to descend into
+		return nil, false, nil
+	}
+}
+
+// skipJSONValue reads past the next value without decoding it
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("invalid JSON: %w", err)
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
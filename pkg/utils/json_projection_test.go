@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectJSON(t *testing.T) {
+	doc := `{
+		"kind": "ClusterList",
+		"total": 2,
+		"items": [
+			{"id": "c1", "status": {"phase": "Ready", "conditions": [{"type": "Available"}]}, "spec": {"big": [1, 2, 3]}},
+			{"id": "c2", "spec": {"big": {"nested": true}}},
+			"not an object"
+		],
+		"metadata": {"labels": {"a": "b"}, "annotations": {"x": "y"}}
+	}`
+
+	tests := []struct {
+		expected map[string]interface{}
+		name     string
+		paths    []string
+	}{
+		{
+			name:     "top-level fields",
+			paths:    []string{"kind", "total"},
+			expected: map[string]interface{}{"kind": "ClusterList", "total": float64(2)},
+		},
+		{
+			name:  "fields of array elements",
+			paths: []string{"items.id", "items.status.phase"},
+			expected: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"id": "c1", "status": map[string]interface{}{"phase": "Ready"}},
+				map[string]interface{}{"id": "c2"},
+			}},
+		},
+		{
+			name:  "a prefix path keeps the whole value",
+			paths: []string{"metadata.labels.a", "metadata"},
+			expected: map[string]interface{}{"metadata": map[string]interface{}{
+				"labels":      map[string]interface{}{"a": "b"},
+				"annotations": map[string]interface{}{"x": "y"},
+			}},
+		},
+		{
+			name:     "missing paths are omitted",
+			paths:    []string{"nope", "kind.deeper", "metadata.labels.missing"},
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ProjectJSON(strings.NewReader(doc), tt.paths, false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestProjectJSON_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		paths []string
+	}{
+		{name: "not an object", input: `[1, 2]`, paths: []string{"a"}},
+		{name: "truncated document", input: `{"a": {"b": [1, 2`, paths: []string{"c"}},
+		{name: "invalid kept value", input: `{"a": tru}`, paths: []string{"a"}},
+		{name: "empty path", input: `{}`, paths: []string{""}},
+		{name: "empty segment", input: `{}`, paths: []string{"a..b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ProjectJSON(strings.NewReader(tt.input), tt.paths, false)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Platform defaults and the fallback key of platform maps
+const (
+	DefaultPlatformOS           = "linux"
+	DefaultPlatformArchitecture = "amd64"
+	PlatformDefaultKey          = "default"
+)
+
+// architectureAliases maps the names reported by uname and some cloud APIs to the
+// GOARCH names used by container image platforms and the kubernetes.io/arch label
+var architectureAliases = map[string]string{
+	"x86_64":  "amd64",
+	"x86-64":  "amd64",
+	"x64":     "amd64",
+	"aarch64": "arm64",
+	"armv8":   "arm64",
+	"ppc64el": "ppc64le",
+}
+
+// NormalizeOS returns os lower-cased and trimmed
+func NormalizeOS(os string) string {
+	return strings.ToLower(strings.TrimSpace(os))
+}
+
+// NormalizeArchitecture returns arch lower-cased, trimmed and mapped to its GOARCH name,
+// e.g. "x86_64" to "amd64" and "aarch64" to "arm64"
+func NormalizeArchitecture(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if alias, ok := architectureAliases[arch]; ok {
+		return alias
+	}
+	return arch
+}
+
+// SelectPlatformValue returns the value of values for a platform. Keys are tried from the
+// most to the least specific: "os/arch", "arch", "os", then "default".
+func SelectPlatformValue(values map[string]interface{}, os, arch string) (interface{}, bool) {
+	os, arch = NormalizeOS(os), NormalizeArchitecture(arch)
+	for _, key := range []string{os + "/" + arch, arch, os, PlatformDefaultKey} {
+		if value, ok := values[key]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// PlatformOSArch returns the os and architecture of a resolved platform map. Missing or
+// non-string entries are returned empty.
+func PlatformOSArch(platform map[string]interface{}) (os, arch string) {
+	if v, ok := platform["os"].(string); ok {
+		os = v
+	}
+	if v, ok := platform["architecture"].(string); ok {
+		arch = v
+	}
+	return os, arch
+}
+
+// platformSelect is the platformSelect template function. platform is the resolved
+// platform param, and pairs alternate keys and values:
+//
+//	{{ platformSelect .platform "arm64" "quay.io/agent:1.4-arm64" "default" "quay.io/agent:1.4" }}
+func platformSelect(platform map[string]interface{}, pairs ...interface{}) (interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("platformSelect: expected key/value pairs, got %d arguments", len(pairs))
+	}
+	values := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("platformSelect: key %v is not a string", pairs[i])
+		}
+		values[key] = pairs[i+1]
+	}
+	os, arch := PlatformOSArch(platform)
+	value, ok := SelectPlatformValue(values, os, arch)
+	if !ok {
+		return nil, fmt.Errorf("platformSelect: no value for %s/%s and no %q value", os, arch, PlatformDefaultKey)
+	}
+	return value, nil
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeArchitecture(t *testing.T) {
+	assert.Equal(t, "amd64", NormalizeArchitecture(" x86_64 "))
+	assert.Equal(t, "arm64", NormalizeArchitecture("AArch64"))
+	assert.Equal(t, "s390x", NormalizeArchitecture("s390x"))
+	assert.Equal(t, "windows", NormalizeOS(" Windows"))
+}
+
+func TestSelectPlatformValue(t *testing.T) {
+	values := map[string]interface{}{
+		"linux/arm64": "os-arch",
+		"ppc64le":     "arch",
+		"windows":     "os",
+		"default":     "default",
+	}
+	tests := []struct {
+		want     interface{}
+		os, arch string
+	}{
+		{"os-arch", "linux", "aarch64"},
+		{"arch", "linux", "ppc64le"},
+		{"os", "windows", "amd64"},
+		{"default", "linux", "amd64"},
+	}
+	for _, tt := range tests {
+		got, ok := SelectPlatformValue(values, tt.os, tt.arch)
+		assert.True(t, ok)
+		assert.Equal(t, tt.want, got, "%s/%s", tt.os, tt.arch)
+	}
+
+	_, ok := SelectPlatformValue(map[string]interface{}{"arm64": "x"}, "linux", "amd64")
+	assert.False(t, ok)
+}
+
+func TestRenderTemplate_PlatformSelect(t *testing.T) {
+	data := map[string]interface{}{
+		"platform": map[string]interface{}{"os": "linux", "architecture": "arm64"},
+	}
+
+	result, err := RenderTemplate(`{{ platformSelect .platform "arm64" "agent:1.4-arm64" "default" "agent:1.4" }}`, data)
+	require.NoError(t, err)
+	assert.Equal(t, "agent:1.4-arm64", result)
+
+	_, err = RenderTemplate(`{{ platformSelect .platform "amd64" "agent:1.4" }}`, data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no value for linux/arm64")
+
+	_, err = RenderTemplate(`{{ platformSelect .platform "amd64" }}`, data)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "expected key/value pairs")
+}
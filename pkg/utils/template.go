@@ -3,14 +3,21 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
+	apperrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
 )
 
 // TemplateFuncs provides helper functions for Go templates.
@@ -116,6 +123,135 @@ var TemplateFuncs = template.FuncMap{
 	"string": func(v interface{}) string {
 		return fmt.Sprintf("%v", v)
 	},
+	// number formats a number without an exponent: {{ .maxPods | number }} is 1000000, not 1e+06
+	"number": FormatNumber,
+
+	// Structured data functions
+	"toYaml": func(v interface{}) (string, error) {
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("toYaml: %w", err)
+		}
+		return strings.TrimSuffix(string(data), "\n"), nil
+	},
+	"toJson": func(v interface{}) (string, error) {
+		if m, ok := v.(map[interface{}]interface{}); ok {
+			v = ConvertToStringKeyMap(m)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("toJson: %w", err)
+		}
+		return string(data), nil
+	},
+	"fromYaml": func(s string) (interface{}, error) {
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("fromYaml: %w", err)
+		}
+		return v, nil
+	},
+	"fromJson": func(s string) (interface{}, error) {
+		var v interface{}
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("fromJson: %w", err)
+		}
+		return v, nil
+	},
+	// indent prefixes every line of s with n spaces
+	"indent": Indent,
+	// nindent is indent preceded by a newline, for embedding a block after "key:"
+	"nindent": func(n int, s string) string {
+		return "\n" + Indent(n, s)
+	},
+
+	// Sprig-compatible functions, with the same names and argument order as in Helm charts
+	"b64enc": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	"b64dec": func(s string) (string, error) {
+		data, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return "", fmt.Errorf("b64dec: %w", err)
+		}
+		return string(data), nil
+	},
+	"sha256sum": func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	},
+	// ternary returns trueVal if cond is true, else falseVal: {{ ternary "on" "off" .enabled }}
+	"ternary": func(trueVal, falseVal interface{}, cond bool) interface{} {
+		if cond {
+			return trueVal
+		}
+		return falseVal
+	},
+	"dict":  dict,
+	"merge": merge,
+	// regexReplaceAll replaces the matches of regex in s; repl may use $1 for submatches
+	"regexReplaceAll": func(regex, s, repl string) (string, error) {
+		re, err := regexp.Compile(regex)
+		if err != nil {
+			return "", fmt.Errorf("regexReplaceAll: %w", err)
+		}
+		return re.ReplaceAllString(s, repl), nil
+	},
+
+	// platformSelect picks the value for the resolved platform from key/value pairs
+	"platformSelect": platformSelect,
+}
+
+// dict builds a map from alternating keys and values. Keys are formatted as strings and a
+// missing last value is "", as in Sprig.
+func dict(pairs ...interface{}) map[string]interface{} {
+	m := make(map[string]interface{}, (len(pairs)+1)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key := fmt.Sprintf("%v", pairs[i])
+		if i+1 < len(pairs) {
+			m[key] = pairs[i+1]
+		} else {
+			m[key] = ""
+		}
+	}
+	return m
+}
+
+// merge deep-merges maps into a copy of dst. As in Sprig, keys already in dst take
+// precedence over those of the following maps, and nested maps are merged. Unlike Sprig,
+// dst itself is not modified, so params are never changed by a template.
+func merge(dst map[string]interface{}, srcs ...map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(dst))
+	for k, v := range dst {
+		out[k] = v
+	}
+	for _, src := range srcs {
+		for k, v := range src {
+			existing, ok := out[k]
+			if !ok {
+				out[k] = v
+				continue
+			}
+			existingMap, existingIsMap := existing.(map[string]interface{})
+			srcMap, srcIsMap := v.(map[string]interface{})
+			if existingIsMap && srcIsMap {
+				out[k] = merge(existingMap, srcMap)
+			}
+		}
+	}
+	return out
+}
+
+// Indent prefixes every non-empty line of s with n spaces.
+func Indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 // RenderTemplate renders a Go template string with the given data.
@@ -125,7 +261,8 @@ var TemplateFuncs = template.FuncMap{
 //   - templateStr: The template string to render
 //   - data: The data to use for template rendering
 //
-// Returns the rendered string or an error if rendering fails.
+// Returns the rendered string or a *errors.TemplateError naming the failing variable and
+// the available variables if rendering fails.
 //
 // Example:
 //
@@ -139,12 +276,12 @@ func RenderTemplate(templateStr string, data map[string]interface{}) (string, er
 
 	tmpl, err := template.New("template").Funcs(TemplateFuncs).Option("missingkey=error").Parse(templateStr)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse template: %w", err)
+		return "", apperrors.NewTemplateParseError(templateStr, err)
 	}
 
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("failed to execute template: %w", err)
+		return "", apperrors.NewTemplateExecError(templateStr, data, err)
 	}
 
 	return buf.String(), nil
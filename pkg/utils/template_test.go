@@ -3,6 +3,7 @@ package utils
 import (
 	"testing"
 
+	apperrors "github.com/openshift-hyperfleet/hyperfleet-adapter/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -35,6 +36,12 @@ func TestRenderTemplate(t *testing.T) {
 			},
 			expected: "test-123",
 		},
+		{
+			name:     "number without an exponent",
+			template: "{{ .maxPods | number }} {{ .ratio | number }} {{ .replicas | number }}",
+			data:     map[string]interface{}{"maxPods": float64(1000000), "ratio": 0.25, "replicas": int64(3)},
+			expected: "1000000 0.25 3",
+		},
 		{
 			name:        "missing variable returns error",
 			template:    "{{ .missing }}",
@@ -58,6 +65,47 @@ func TestRenderTemplate(t *testing.T) {
 	}
 }
 
+func TestRenderTemplate_TemplateErrors(t *testing.T) {
+	data := map[string]interface{}{
+		"clusterId": "abc123",
+		"cluster":   map[string]interface{}{"id": "abc123"},
+	}
+
+	t.Run("missing key names the variable and the available roots", func(t *testing.T) {
+		_, err := RenderTemplate("name: {{ .cluster.name }}", data)
+		require.Error(t, err)
+		tmplErr, ok := apperrors.IsTemplateError(err)
+		require.True(t, ok)
+		assert.Equal(t, apperrors.TemplateErrorTypeExecute, tmplErr.Type)
+		assert.Equal(t, ".cluster.name", tmplErr.Variable)
+		assert.Equal(t, []string{"cluster", "clusterId"}, tmplErr.Available)
+		assert.Contains(t, err.Error(), "failed to execute template at variable .cluster.name: ")
+		assert.Contains(t, err.Error(), `map has no entry for key "name"`)
+		assert.Contains(t, err.Error(), "(available variables: cluster, clusterId)")
+
+		tmplErr.Step = "configmap0"
+		assert.Contains(t, err.Error(), `failed to execute template in step "configmap0" at variable .cluster.name`)
+	})
+
+	t.Run("function error names its argument", func(t *testing.T) {
+		_, err := RenderTemplate(`{{ fromYaml .values }}`, map[string]interface{}{"values": "a: [b"})
+		require.Error(t, err)
+		tmplErr, ok := apperrors.IsTemplateError(err)
+		require.True(t, ok)
+		assert.Equal(t, ".values", tmplErr.Variable)
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		_, err := RenderTemplate(`{{ undefinedFunc .clusterId }}`, data)
+		require.Error(t, err)
+		tmplErr, ok := apperrors.IsTemplateError(err)
+		require.True(t, ok)
+		assert.Equal(t, apperrors.TemplateErrorTypeParse, tmplErr.Type)
+		assert.Empty(t, tmplErr.Available)
+		assert.Contains(t, err.Error(), "failed to parse template: ")
+	})
+}
+
 func TestRenderTemplateBytes(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -106,3 +154,122 @@ func TestRenderTemplateBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderTemplate_StructuredFuncs(t *testing.T) {
+	data := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"replicas": 3,
+			"labels":   map[string]interface{}{"tier": "worker"},
+		},
+		"raw": "replicas: 5\nname: np-1\n",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "toYaml",
+			template: "{{ toYaml .spec }}",
+			expected: "labels:\n    tier: worker\nreplicas: 3",
+		},
+		{
+			name:     "toYaml with nindent",
+			template: "spec:{{ .spec | toYaml | nindent 2 }}",
+			expected: "spec:\n  labels:\n      tier: worker\n  replicas: 3",
+		},
+		{
+			name:     "toJson",
+			template: "{{ toJson .spec }}",
+			expected: `{"labels":{"tier":"worker"},"replicas":3}`,
+		},
+		{
+			name:     "fromYaml field access",
+			template: "{{ (fromYaml .raw).name }}-{{ (fromYaml .raw).replicas }}",
+			expected: "np-1-5",
+		},
+		{
+			name:     "indent skips empty lines",
+			template: `{{ indent 2 "a\n\nb" }}`,
+			expected: "  a\n\n  b",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderTemplate(tt.template, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	_, err := RenderTemplate(`{{ fromYaml "a: [" }}`, data)
+	assert.ErrorContains(t, err, "fromYaml")
+}
+
+func TestRenderTemplate_SprigFuncs(t *testing.T) {
+	data := map[string]interface{}{
+		"annotation": `{"owner":"team-a","zones":["a","b"]}`,
+		"defaults":   map[string]interface{}{"tier": "standard", "labels": map[string]interface{}{"env": "dev"}},
+		"overrides":  map[string]interface{}{"tier": "premium", "labels": map[string]interface{}{"team": "a"}},
+		"enabled":    true,
+		"version":    "v4.15.2",
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "fromJson",
+			template: "{{ (fromJson .annotation).owner }} {{ index (fromJson .annotation).zones 1 }}",
+			expected: "team-a b",
+		},
+		{name: "b64enc", template: `{{ b64enc "hyperfleet" }}`, expected: "aHlwZXJmbGVldA=="},
+		{name: "b64dec", template: `{{ b64dec "aHlwZXJmbGVldA==" }}`, expected: "hyperfleet"},
+		{
+			name:     "sha256sum",
+			template: `{{ sha256sum "hyperfleet" }}`,
+			expected: "35df1d18ba286978499c3ea0b777424fc5e866b6037bf20886c568d4d6f5bc30",
+		},
+		{name: "ternary true", template: `{{ ternary "on" "off" .enabled }}`, expected: "on"},
+		{name: "ternary false", template: `{{ ternary "on" "off" (eq .version "v5") }}`, expected: "off"},
+		{
+			name:     "dict",
+			template: `{{ toJson (dict "name" "np-1" "replicas" 3) }}`,
+			expected: `{"name":"np-1","replicas":3}`,
+		},
+		{
+			name:     "merge gives precedence to the first map",
+			template: "{{ toJson (merge .overrides .defaults) }}",
+			expected: `{"labels":{"env":"dev","team":"a"},"tier":"premium"}`,
+		},
+		{
+			name:     "regexReplaceAll",
+			template: `{{ regexReplaceAll "^v([0-9]+)\\..*" .version "${1}" }}`,
+			expected: "4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := RenderTemplate(tt.template, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	// merge does not modify its arguments
+	_, err := RenderTemplate("{{ merge .overrides .defaults }}", data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"team": "a"}, data["overrides"].(map[string]interface{})["labels"])
+
+	_, err = RenderTemplate(`{{ b64dec "not base64!" }}`, data)
+	assert.ErrorContains(t, err, "b64dec")
+	_, err = RenderTemplate(`{{ regexReplaceAll "(" "a" "b" }}`, data)
+	assert.ErrorContains(t, err, "regexReplaceAll")
+	_, err = RenderTemplate(`{{ fromJson "{" }}`, data)
+	assert.ErrorContains(t, err, "fromJson")
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// TLSConfig configures TLS client connections. The system roots verify the server when
+// CAFile is empty.
+type TLSConfig struct {
+	// CAFile is the CA bundle verifying the server
+	CAFile string `yaml:"ca_file,omitempty" mapstructure:"ca_file"`
+	// CertFile and KeyFile are the client certificate of mutual TLS
+	CertFile string `yaml:"cert_file,omitempty" mapstructure:"cert_file"`
+	KeyFile  string `yaml:"key_file,omitempty" mapstructure:"key_file"`
+	// Enabled turns TLS on
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+}
+
+// ClientConfig reads the CA bundle and client certificate of c into a TLS client config
+func (c *TLSConfig) ClientConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CAFile != "" {
+		caPEM, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("CA file %s contains no certificates", c.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+)
+
+// ErrWriter records the first write error so a sequence of writes can be checked once;
+// the writes after a failed one are skipped
+type ErrWriter struct {
+	W   io.Writer
+	Err error
+}
+
+// Printf writes to W unless an earlier write failed
+func (ew *ErrWriter) Printf(format string, args ...interface{}) {
+	if ew.Err != nil {
+		return
+	}
+	_, ew.Err = fmt.Fprintf(ew.W, format, args...)
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter fails every write after the first n
+type failingWriter struct {
+	n int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.n == 0 {
+		return 0, errors.New("disk full")
+	}
+	w.n--
+	return len(p), nil
+}
+
+func TestErrWriter(t *testing.T) {
+	var buf strings.Builder
+	out := &ErrWriter{W: &buf}
+	out.Printf("%s=%d\n", "a", 1)
+	out.Printf("%s=%d\n", "b", 2)
+	assert.NoError(t, out.Err)
+	assert.Equal(t, "a=1\nb=2\n", buf.String())
+
+	fw := &failingWriter{n: 1}
+	out = &ErrWriter{W: fw}
+	out.Printf("first\n")
+	out.Printf("second\n")
+	fw.n = 1
+	out.Printf("third\n")
+	assert.EqualError(t, out.Err, "disk full")
+	assert.Equal(t, 1, fw.n, "writes after a failure should be skipped")
+}
@@ -60,7 +60,7 @@ func TestLoadSplitConfig(t *testing.T) {
 	assert.Equal(t, "0.1.0", config.Adapter.Version)
 
 	// Clients config comes from adapter config
-	assert.Equal(t, 2*time.Second, config.Clients.HyperfleetAPI.Timeout)
+	assert.Equal(t, configloader.Duration(2*time.Second), config.Clients.HyperfleetAPI.Timeout)
 	assert.Equal(t, 3, config.Clients.HyperfleetAPI.RetryAttempts)
 	assert.Equal(t, hyperfleetapi.BackoffExponential, config.Clients.HyperfleetAPI.RetryBackoff)
 
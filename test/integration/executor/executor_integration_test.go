@@ -79,7 +79,7 @@ func createTestConfig(apiBaseURL string) *configloader.Config {
 		},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout:       10 * time.Second,
+				Timeout:       configloader.Duration(10 * time.Second),
 				RetryAttempts: 1,
 				RetryBackoff:  hyperfleetapi.BackoffConstant,
 			},
@@ -97,7 +97,7 @@ func createTestConfig(apiBaseURL string) *configloader.Config {
 					APICall: &configloader.APICall{
 						Method:  "GET",
 						URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}",
-						Timeout: "5s",
+						Timeout: configloader.Duration(5 * time.Second),
 					},
 				},
 				Capture: []configloader.CaptureField{
@@ -158,7 +158,7 @@ func createTestConfig(apiBaseURL string) *configloader.Config {
 							Method:  "PUT",
 							URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}/statuses",
 							Body:    "{{ .clusterStatusPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 				},
@@ -588,7 +588,7 @@ func TestExecutor_CELExpressionEvaluation(t *testing.T) {
 				APICall: &configloader.APICall{
 					Method:  "GET",
 					URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}",
-					Timeout: "5s",
+					Timeout: configloader.Duration(5 * time.Second),
 				},
 			},
 			Capture: []configloader.CaptureField{
@@ -978,7 +978,7 @@ func TestExecutor_MissingEventFields(t *testing.T) {
 	assert.Equal(t, executor.PhaseParamExtraction, result.CurrentPhase, "Should fail in param extraction")
 	require.NotEmpty(t, result.Errors)
 	// Expect failure in param extraction phase
-	errPhase := result.Errors[executor.PhaseParamExtraction]
+	errPhase := result.Errors.Phase(executor.PhaseParamExtraction)
 	require.Error(t, errPhase)
 	assert.Contains(t, errPhase.Error(), "clusterID", "Error should mention missing clusterID")
 	t.Logf("Missing field error: %v", errPhase)
@@ -1015,7 +1015,7 @@ func TestExecutor_LogAction(t *testing.T) {
 		},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout: 10 * time.Second, RetryAttempts: 1,
+				Timeout: configloader.Duration(10 * time.Second), RetryAttempts: 1,
 			},
 		},
 		Params: []configloader.Parameter{
@@ -1263,7 +1263,7 @@ func TestExecutor_ExecutionError_CELAccess(t *testing.T) {
 		},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout: 10 * time.Second, RetryAttempts: 1, RetryBackoff: hyperfleetapi.BackoffConstant,
+				Timeout: configloader.Duration(10 * time.Second), RetryAttempts: 1, RetryBackoff: hyperfleetapi.BackoffConstant,
 			},
 		},
 		Params: []configloader.Parameter{
@@ -1278,7 +1278,7 @@ func TestExecutor_ExecutionError_CELAccess(t *testing.T) {
 					APICall: &configloader.APICall{
 						Method:  "GET",
 						URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}",
-						Timeout: "5s",
+						Timeout: configloader.Duration(5 * time.Second),
 					},
 				},
 				Capture: []configloader.CaptureField{
@@ -1338,7 +1338,7 @@ func TestExecutor_ExecutionError_CELAccess(t *testing.T) {
 							Method:  "PUT",
 							URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}/error-report",
 							Body:    "{{ .errorReportPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 				},
@@ -1438,7 +1438,7 @@ func TestExecutor_PayloadBuildFailure(t *testing.T) {
 		},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout: 10 * time.Second, RetryAttempts: 1,
+				Timeout: configloader.Duration(10 * time.Second), RetryAttempts: 1,
 			},
 		},
 		Params: []configloader.Parameter{
@@ -1475,7 +1475,7 @@ func TestExecutor_PayloadBuildFailure(t *testing.T) {
 							Method:  "PUT",
 							URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}/statuses",
 							Body:    "{{ .badPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 				},
@@ -1564,7 +1564,7 @@ func TestExecutor_PayloadWhenCondition(t *testing.T) {
 		},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout:       10 * time.Second,
+				Timeout:       configloader.Duration(10 * time.Second),
 				RetryAttempts: 1,
 				RetryBackoff:  hyperfleetapi.BackoffConstant,
 			},
@@ -1582,7 +1582,7 @@ func TestExecutor_PayloadWhenCondition(t *testing.T) {
 					APICall: &configloader.APICall{
 						Method:  "GET",
 						URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}",
-						Timeout: "5s",
+						Timeout: configloader.Duration(5 * time.Second),
 					},
 				},
 				Capture: []configloader.CaptureField{
@@ -1620,7 +1620,7 @@ func TestExecutor_PayloadWhenCondition(t *testing.T) {
 							Method:  "PUT",
 							URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}/statuses",
 							Body:    "{{ .alwaysBuiltPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 				},
@@ -1632,7 +1632,7 @@ func TestExecutor_PayloadWhenCondition(t *testing.T) {
 							URL: "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}" +
 								"/clusters/{{ .clusterID }}/skipped-statuses",
 							Body:    "{{ .skippedPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 				},
@@ -1717,7 +1717,7 @@ func TestExecutor_CELNamespaces_EnvAndEvent(t *testing.T) {
 		Adapter: configloader.AdapterInfo{Name: "cel-ns-test", Version: "1.0.0"},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout: 10 * time.Second, RetryAttempts: 1, RetryBackoff: hyperfleetapi.BackoffConstant,
+				Timeout: configloader.Duration(10 * time.Second), RetryAttempts: 1, RetryBackoff: hyperfleetapi.BackoffConstant,
 			},
 		},
 		Params: []configloader.Parameter{
@@ -1786,7 +1786,7 @@ func TestExecutor_CELNamespaces_EnvAndEvent(t *testing.T) {
 							Method:  "PUT",
 							URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}/statuses",
 							Body:    "{{ .envGatedPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 					// Context: post_action when — event.* gates execution
@@ -1869,7 +1869,7 @@ func TestExecutor_CELNamespaces_ResourcesAdapterCaptures(t *testing.T) {
 		Adapter: configloader.AdapterInfo{Name: "cel-ns-rac-test", Version: "1.0.0"},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout: 10 * time.Second, RetryAttempts: 1, RetryBackoff: hyperfleetapi.BackoffConstant,
+				Timeout: configloader.Duration(10 * time.Second), RetryAttempts: 1, RetryBackoff: hyperfleetapi.BackoffConstant,
 			},
 		},
 		Params: []configloader.Parameter{
@@ -1884,7 +1884,7 @@ func TestExecutor_CELNamespaces_ResourcesAdapterCaptures(t *testing.T) {
 					APICall: &configloader.APICall{
 						Method:  "GET",
 						URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}",
-						Timeout: "5s",
+						Timeout: configloader.Duration(5 * time.Second),
 					},
 				},
 				// Capture clusterName for use in post-phase CEL expressions
@@ -1954,7 +1954,7 @@ func TestExecutor_CELNamespaces_ResourcesAdapterCaptures(t *testing.T) {
 							Method:  "PUT",
 							URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}/statuses",
 							Body:    "{{ .racPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 					// Context: post_action when — resources.* gates execution
@@ -1969,7 +1969,7 @@ func TestExecutor_CELNamespaces_ResourcesAdapterCaptures(t *testing.T) {
 							Method:  "PUT",
 							URL:     "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/{{ .clusterID }}/statuses",
 							Body:    `{"adapterGated":true}`,
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 					// Context: post_action when — adapter.* gates execution
@@ -150,7 +150,7 @@ func createK8sTestConfig(testNamespace string) *configloader.Config {
 		},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout:       10 * time.Second,
+				Timeout:       configloader.Duration(10 * time.Second),
 				RetryAttempts: 1,
 				RetryBackoff:  hyperfleetapi.BackoffConstant,
 			},
@@ -186,7 +186,7 @@ func createK8sTestConfig(testNamespace string) *configloader.Config {
 						Method: "GET",
 						URL: "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/" +
 							"{{ .clusterID }}",
-						Timeout: "5s",
+						Timeout: configloader.Duration(5 * time.Second),
 					},
 				},
 				Capture: []configloader.CaptureField{
@@ -299,7 +299,7 @@ func createK8sTestConfig(testNamespace string) *configloader.Config {
 							URL: "{{ .hyperfleetApiBaseUrl }}/api/{{ .hyperfleetApiVersion }}/clusters/" +
 								"{{ .clusterID }}/statuses",
 							Body:    "{{ .clusterStatusPayload }}",
-							Timeout: "5s",
+							Timeout: configloader.Duration(5 * time.Second),
 						},
 					},
 				},
@@ -834,6 +834,43 @@ func TestExecutor_K8s_ResourceCreationFailure(t *testing.T) {
 	}
 }
 
+// TestExecutor_K8s_ManageNamespaces tests that the namespace missing in
+// TestExecutor_K8s_ResourceCreationFailure is created when the adapter manages namespaces
+func TestExecutor_K8s_ManageNamespaces(t *testing.T) {
+	k8sEnv := SetupK8sTestEnv(t)
+	defer k8sEnv.Cleanup(t)
+
+	testNamespace := fmt.Sprintf("executor-managed-ns-%d", time.Now().Unix())
+	defer k8sEnv.CleanupTestNamespace(t, testNamespace)
+
+	mockAPI := newK8sTestAPIServer(t)
+	defer mockAPI.Close()
+
+	t.Setenv("HYPERFLEET_API_BASE_URL", mockAPI.URL())
+	t.Setenv("HYPERFLEET_API_VERSION", "v1")
+
+	config := createK8sTestConfig(testNamespace)
+	config.Clients.Kubernetes.ManageNamespaces = true
+	apiClient, err := hyperfleetapi.NewClient(testLog())
+	require.NoError(t, err)
+	exec, err := executor.NewBuilder().
+		WithConfig(config).
+		WithAPIClient(apiClient).
+		WithTransportClient(k8sEnv.Client).
+		WithLogger(k8sEnv.Log).
+		Build()
+	require.NoError(t, err)
+
+	result := exec.Execute(context.Background(), createK8sTestEvent("managed-ns-test"))
+	require.Equal(t, executor.StatusSuccess, result.Status, "errors: %v", result.Errors)
+
+	ns, err := k8sEnv.Client.GetResource(context.Background(),
+		schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}, "", testNamespace, nil)
+	require.NoError(t, err, "the namespace should be created")
+	assert.Equal(t, "hyperfleet-adapter", ns.GetLabels()["hyperfleet.io/managed-by"])
+	assert.Equal(t, "k8s-test-adapter", ns.GetLabels()["hyperfleet.io/adapter"])
+}
+
 // TestExecutor_K8s_MultipleMatchingResources tests behavior when multiple resources match label selector
 // Expected behavior: returns the first matching resource (order is not guaranteed by K8s API)
 // TestExecutor_K8s_MultipleMatchingResources tests resource creation with multiple labeled resources.
@@ -888,7 +925,7 @@ func TestExecutor_K8s_MultipleMatchingResources(t *testing.T) {
 		Adapter: configloader.AdapterInfo{Name: "multi-match-test", Version: "1.0.0"},
 		Clients: configloader.ClientsConfig{
 			HyperfleetAPI: configloader.HyperfleetAPIConfig{
-				Timeout: 10 * time.Second, RetryAttempts: 1,
+				Timeout: configloader.Duration(10 * time.Second), RetryAttempts: 1,
 			},
 		},
 		Params: []configloader.Parameter{
@@ -30,26 +30,8 @@ func buildMaestroClientConfigFromLoaded(
 		Insecure:          maestroConfig.Insecure,
 	}
 
-	if maestroConfig.Timeout != "" {
-		d, err := time.ParseDuration(maestroConfig.Timeout)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"invalid maestro timeout %q: %w", maestroConfig.Timeout, err,
-			)
-		}
-		config.HTTPTimeout = d
-	}
-
-	if maestroConfig.ServerHealthinessTimeout != "" {
-		d, err := time.ParseDuration(maestroConfig.ServerHealthinessTimeout)
-		if err != nil {
-			return nil, fmt.Errorf(
-				"invalid maestro serverHealthinessTimeout %q: %w",
-				maestroConfig.ServerHealthinessTimeout, err,
-			)
-		}
-		config.ServerHealthinessTimeout = d
-	}
+	config.HTTPTimeout = maestroConfig.Timeout.Std()
+	config.ServerHealthinessTimeout = maestroConfig.ServerHealthinessTimeout.Std()
 
 	if maestroConfig.Auth.TLSConfig != nil {
 		config.CAFile = maestroConfig.Auth.TLSConfig.CAFile
@@ -156,8 +138,8 @@ func TestTLSConfigLoadAndConnect_MutualTLS(t *testing.T) {
 	assert.Equal(t, env.TLSMaestroServerAddr, maestroCfg.HTTPServerAddress)
 	assert.Equal(t, "config-tls-mtls", maestroCfg.SourceID)
 	assert.False(t, maestroCfg.Insecure)
-	assert.Equal(t, "15s", maestroCfg.Timeout)
-	assert.Equal(t, "25s", maestroCfg.ServerHealthinessTimeout)
+	assert.Equal(t, configloader.Duration(15*time.Second), maestroCfg.Timeout)
+	assert.Equal(t, configloader.Duration(25*time.Second), maestroCfg.ServerHealthinessTimeout)
 	require.NotNil(t, maestroCfg.Auth.TLSConfig)
 	assert.Equal(t, env.TLSCerts.CAFilePath(), maestroCfg.Auth.TLSConfig.CAFile)
 	assert.Equal(t, env.TLSCerts.ClientCertFilePath(), maestroCfg.Auth.TLSConfig.CertFile)